@@ -0,0 +1,175 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// ArgumentsBuilder composes the arguments of a transaction or script, e.g.
+//
+//   arguments, err := cadence.NewArgumentsBuilder().
+//       Add(42).
+//       AddAddress("0x1").
+//       AddUFix64("1.5").
+//       Add([]string{"a", "b"}).
+//       Build()
+//
+// Go values are converted to Cadence values using Marshal.
+//
+// If adding an argument fails, all further additions are ignored,
+// and the error is returned by Build and Encode.
+//
+type ArgumentsBuilder struct {
+	arguments []Value
+	err       error
+}
+
+// NewArgumentsBuilder returns a new builder without any arguments
+//
+func NewArgumentsBuilder() *ArgumentsBuilder {
+	return &ArgumentsBuilder{}
+}
+
+func (b *ArgumentsBuilder) add(argument Value, err error) *ArgumentsBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if err != nil {
+		b.err = fmt.Errorf("invalid argument at index %d: %w", len(b.arguments), err)
+		return b
+	}
+
+	b.arguments = append(b.arguments, argument)
+	return b
+}
+
+// Add adds the given argument.
+// Cadence values are added as-is, all other Go values are converted using Marshal
+//
+func (b *ArgumentsBuilder) Add(argument interface{}) *ArgumentsBuilder {
+	return b.add(Marshal(argument))
+}
+
+// AddOptional adds the given argument as an optional.
+// A nil argument results in `nil`, all other arguments are converted using Marshal,
+// and wrapped in an optional
+//
+func (b *ArgumentsBuilder) AddOptional(argument interface{}) *ArgumentsBuilder {
+	if argument == nil {
+		return b.add(NewOptional(nil), nil)
+	}
+
+	value, err := Marshal(argument)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(NewOptional(value), nil)
+}
+
+// AddAddress adds an address argument, given in hexadecimal, e.g. `0x1`
+//
+func (b *ArgumentsBuilder) AddAddress(address string) *ArgumentsBuilder {
+	result, err := common.HexToAddress(address)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(NewAddress(result), nil)
+}
+
+// AddUFix64 adds a UFix64 argument, given in decimal, e.g. `1.5`
+//
+func (b *ArgumentsBuilder) AddUFix64(value string) *ArgumentsBuilder {
+	result, err := NewUFix64(value)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(result, nil)
+}
+
+// AddFix64 adds a Fix64 argument, given in decimal, e.g. `-1.5`
+//
+func (b *ArgumentsBuilder) AddFix64(value string) *ArgumentsBuilder {
+	result, err := NewFix64(value)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(result, nil)
+}
+
+// AddUFix128 adds a UFix128 argument, given in decimal, e.g. `1.5`
+//
+func (b *ArgumentsBuilder) AddUFix128(value string) *ArgumentsBuilder {
+	result, err := NewUFix128(value)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(result, nil)
+}
+
+// AddFix128 adds a Fix128 argument, given in decimal, e.g. `-1.5`
+//
+func (b *ArgumentsBuilder) AddFix128(value string) *ArgumentsBuilder {
+	result, err := NewFix128(value)
+	if err != nil {
+		return b.add(nil, err)
+	}
+
+	return b.add(result, nil)
+}
+
+// Build returns the added arguments,
+// or the error which occurred when adding an argument
+//
+func (b *ArgumentsBuilder) Build() ([]Value, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.arguments, nil
+}
+
+// Encode encodes the added arguments using the given encoding function,
+// e.g. the JSON-Cadence encoding function json.Encode
+//
+func (b *ArgumentsBuilder) Encode(encode func(Value) ([]byte, error)) ([][]byte, error) {
+	arguments, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([][]byte, len(arguments))
+
+	for i, argument := range arguments {
+		encoded[i], err = encode(argument)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument at index %d: %w", i, err)
+		}
+	}
+
+	return encoded, nil
+}
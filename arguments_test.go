@@ -0,0 +1,143 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentsBuilder(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("values", func(t *testing.T) {
+
+		t.Parallel()
+
+		x := int8(1)
+
+		arguments, err := NewArgumentsBuilder().
+			Add(42).
+			Add("foo").
+			Add(&x).
+			Add((*string)(nil)).
+			AddOptional(true).
+			AddOptional(nil).
+			Add([]uint8{1, 2}).
+			Add(map[string]int{"b": 2, "a": 1}).
+			Add(NewUInt64(3)).
+			AddAddress("0x1").
+			AddUFix64("1.5").
+			AddFix64("-1.5").
+			Build()
+		require.NoError(t, err)
+
+		ufix64, err := NewUFix64("1.5")
+		require.NoError(t, err)
+
+		fix64, err := NewFix64("-1.5")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]Value{
+				NewInt(42),
+				String("foo"),
+				NewOptional(NewInt8(1)),
+				NewOptional(nil),
+				NewOptional(NewBool(true)),
+				NewOptional(nil),
+				NewArray([]Value{NewUInt8(1), NewUInt8(2)}),
+				NewDictionary([]KeyValuePair{
+					{Key: String("a"), Value: NewInt(1)},
+					{Key: String("b"), Value: NewInt(2)},
+				}),
+				NewUInt64(3),
+				BytesToAddress([]byte{0x1}),
+				ufix64,
+				fix64,
+			},
+			arguments,
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		arguments, err := NewArgumentsBuilder().
+			Add(1).
+			AddUFix64("-1.5").
+			Add(2).
+			Build()
+		require.Error(t, err)
+		assert.Nil(t, arguments)
+		assert.Contains(t, err.Error(), "invalid argument at index 1")
+	})
+
+	t.Run("unsupported Go value", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := NewArgumentsBuilder().
+			Add(func() {}).
+			Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid argument at index 0")
+	})
+
+	t.Run("encode", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := NewArgumentsBuilder().
+			Add(1).
+			Add("foo").
+			Encode(func(value Value) ([]byte, error) {
+				return []byte(value.String()), nil
+			})
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[][]byte{
+				[]byte("1"),
+				[]byte(`"foo"`),
+			},
+			encoded,
+		)
+	})
+
+	t.Run("encode, error", func(t *testing.T) {
+
+		t.Parallel()
+
+		encodeErr := errors.New("test")
+
+		_, err := NewArgumentsBuilder().
+			Add(1).
+			Encode(func(value Value) ([]byte, error) {
+				return nil, encodeErr
+			})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, encodeErr)
+	})
+}
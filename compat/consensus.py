@@ -0,0 +1,155 @@
+from __future__ import annotations
+
+import logging
+import shutil
+import subprocess
+import sys
+import tempfile
+from pathlib import Path
+from typing import Optional
+
+import click as click
+import coloredlogs
+
+REPO_PATH = Path("..").resolve()
+CORPUS_PATH = Path("consensus/corpus").resolve()
+HARNESS_PACKAGE = Path("runtime/cmd/consensus")
+RUNTIME_INTERFACE_PATH = Path("runtime/interface.go")
+
+# The build tags of the harness for versions of the runtime which lack parts of the runtime interface.
+# A tag is used if the declaration is missing in the runtime interface of the version
+LEGACY_BUILD_TAGS = {
+    "consensus_without_clock": "Clock() Clock",
+}
+
+
+class Git:
+
+    @staticmethod
+    def add_worktree(ref: str, path: Path):
+        completed_process = subprocess.run(
+            ["git", "worktree", "add", "--detach", path, ref],
+            cwd=REPO_PATH,
+        )
+        if completed_process.returncode != 0:
+            raise Exception(f'failed to check out ref {ref}')
+
+    @staticmethod
+    def remove_worktree(path: Path):
+        subprocess.run(
+            ["git", "worktree", "remove", "--force", path],
+            cwd=REPO_PATH,
+        )
+
+
+def harness_build_tags(repo_path: Path) -> list[str]:
+    runtime_interface = (repo_path / RUNTIME_INTERFACE_PATH).read_text()
+    return [
+        tag
+        for tag, declaration in LEGACY_BUILD_TAGS.items()
+        if declaration not in runtime_interface
+    ]
+
+
+def build_harness(repo_path: Path, output_path: Path):
+    tags = harness_build_tags(repo_path)
+    logger.info(f"Building harness in {repo_path} (tags: {', '.join(tags) or 'none'})")
+    completed_process = subprocess.run(
+        ["go", "build", "-tags", ",".join(tags), "-o", output_path, f"./{HARNESS_PACKAGE}"],
+        cwd=repo_path,
+    )
+    if completed_process.returncode != 0:
+        raise Exception(f'failed to build harness in {repo_path}')
+
+
+def run_harness(harness_path: Path, corpus_path: Path, report_path: Path):
+    logger.info(f"Running corpus {corpus_path} with {harness_path.name}")
+    completed_process = subprocess.run(
+        [harness_path, "-output", report_path, "run", corpus_path],
+    )
+    if completed_process.returncode != 0:
+        raise Exception(f'failed to run corpus with {harness_path.name}')
+
+
+@click.command()
+@click.option(
+    "--compare-ref",
+    "other_ref",
+    required=True,
+    help="Git ref of the old version to compare with (e.g. commit, branch, or tag)"
+)
+@click.option(
+    "--corpus",
+    default=str(CORPUS_PATH),
+    type=click.Path(exists=True, file_okay=False),
+    help="Path of the corpus of programs and state fixtures"
+)
+@click.option(
+    "--report-dir",
+    default=None,
+    type=click.Path(file_okay=False),
+    help="Keep the reports of both versions in the given directory"
+)
+def main(
+        other_ref: str,
+        corpus: str,
+        report_dir: Optional[str],
+):
+    corpus_path = Path(corpus).resolve()
+
+    with tempfile.TemporaryDirectory() as temp_dir:
+        temp_path = Path(temp_dir)
+
+        report_path = temp_path
+        if report_dir is not None:
+            report_path = Path(report_dir).resolve()
+            report_path.mkdir(parents=True, exist_ok=True)
+
+        # Build the harness for the current checkout
+
+        current_harness_path = temp_path / "consensus-current"
+        build_harness(REPO_PATH, current_harness_path)
+
+        # Build the harness for the other ref.
+        # The harness of the current checkout is used for both versions,
+        # so the old version is run with the same harness, even if it has none or an older one.
+        # The harness package is self-contained, it only depends on the runtime API
+        # which is available in all supported versions, see LEGACY_BUILD_TAGS
+
+        other_harness_path = temp_path / "consensus-other"
+        worktree_path = temp_path / "worktree"
+
+        Git.add_worktree(other_ref, worktree_path)
+        try:
+            other_harness_package_path = worktree_path / HARNESS_PACKAGE
+            shutil.rmtree(other_harness_package_path, ignore_errors=True)
+            shutil.copytree(REPO_PATH / HARNESS_PACKAGE, other_harness_package_path)
+
+            build_harness(worktree_path, other_harness_path)
+        finally:
+            Git.remove_worktree(worktree_path)
+
+        # Run the corpus with both versions, and compare the reports
+
+        old_report_path = report_path / "old.json"
+        new_report_path = report_path / "new.json"
+
+        run_harness(other_harness_path, corpus_path, old_report_path)
+        run_harness(current_harness_path, corpus_path, new_report_path)
+
+        completed_process = subprocess.run(
+            [current_harness_path, "compare", old_report_path, new_report_path],
+        )
+
+    sys.exit(completed_process.returncode)
+
+
+if __name__ == "__main__":
+    logger = logging.getLogger(__name__)
+
+    coloredlogs.install(
+        level="INFO",
+        fmt="%(asctime)s,%(msecs)03d %(levelname)s %(message)s"
+    )
+
+    main()
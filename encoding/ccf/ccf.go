@@ -0,0 +1,479 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ccf implements the Cadence Compact Format (CCF),
+// a compact, schema-aware binary encoding of Cadence values based on CBOR.
+//
+// In contrast to JSON-Cadence, a CCF message separates the types from the values:
+// composite and interface types are defined once per message, and values are encoded
+// according to their static type, e.g. a composite value is encoded as the list of its field values,
+// without the field names and field types.
+//
+// Only values in positions where the static type is not sufficient to determine the type of the value,
+// e.g. values of type `AnyStruct`, are encoded together with their type.
+//
+// A CCF message has the following structure:
+//
+//   ccf-message = [
+//       type-definition-headers: [* [kind: uint, type-id: tstr]],
+//       type-definition-members: [* [fields, initializers, raw-type: inline-type / null]],
+//       type: inline-type,
+//       value: value,
+//   ]
+//
+// The headers of all type definitions precede their members,
+// so that members can refer to any type definition, including recursively.
+//
+package ccf
+
+import (
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// CBOREncMode
+//
+// See https://github.com/fxamacker/cbor:
+// "For best performance, reuse EncMode and DecMode after creating them."
+//
+var CBOREncMode = func() cbor.EncMode {
+	options := cbor.CanonicalEncOptions()
+	options.BigIntConvert = cbor.BigIntConvertShortest
+	encMode, err := options.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return encMode
+}()
+
+// CBORDecMode
+//
+// See https://github.com/fxamacker/cbor:
+// "For best performance, reuse EncMode and DecMode after creating them."
+//
+// The decoding mode enforces the default decoding limits, see DecodeWithLimits.
+//
+var CBORDecMode = func() cbor.DecMode {
+	decMode, err := newCBORDecMode(common.DefaultDecodingLimits)
+	if err != nil {
+		panic(err)
+	}
+	return decMode
+}()
+
+// newCBORDecMode returns a CBOR decoding mode which enforces the given decoding limits
+// on the nesting depth and the declared lengths of arrays and maps
+//
+func newCBORDecMode(limits common.DecodingLimits) (cbor.DecMode, error) {
+	err := limits.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.DecOptions{
+		IntDec:           cbor.IntDecConvertNone,
+		MaxArrayElements: limits.MaxArrayElements,
+		MaxMapPairs:      limits.MaxArrayElements,
+		MaxNestedLevels:  limits.MaxNestedLevels,
+	}.DecMode()
+}
+
+// CBORTagBase is the base tag number of the CCF tags
+//
+const CBORTagBase = 128
+
+// !!! *WARNING* !!!
+//
+// Only add new fields to the end of the list.
+//
+// DO NOT REPLACE EXISTING FIELDS, OR INSERT NEW FIELDS,
+// as this will change the encoding of existing messages
+//
+const (
+	// CBORTagTypeAndValue is the tag of a value which is encoded
+	// together with its type: [type: inline-type, value: value]
+	CBORTagTypeAndValue = CBORTagBase + iota
+	// CBORTagLinkValue is the tag of a link value: [target-path, borrow-type: tstr]
+	CBORTagLinkValue
+
+	// CBORTagTypeRef is the tag of a reference to a type definition: uint
+	CBORTagTypeRef
+	// CBORTagSimpleType is the tag of a simple type: uint
+	CBORTagSimpleType
+	// CBORTagOptionalType is the tag of an optional type: inline-type
+	CBORTagOptionalType
+	// CBORTagVariableSizedArrayType is the tag of a variable-sized array type: inline-type
+	CBORTagVariableSizedArrayType
+	// CBORTagConstantSizedArrayType is the tag of a constant-sized array type: [size: uint, inline-type]
+	CBORTagConstantSizedArrayType
+	// CBORTagDictionaryType is the tag of a dictionary type: [key: inline-type, element: inline-type]
+	CBORTagDictionaryType
+	// CBORTagReferenceType is the tag of a reference type: [authorized: bool, inline-type]
+	CBORTagReferenceType
+	// CBORTagRestrictedType is the tag of a restricted type: [type-id: tstr, inline-type, [* inline-type]]
+	CBORTagRestrictedType
+	// CBORTagCapabilityType is the tag of a capability type: inline-type / null
+	CBORTagCapabilityType
+	// CBORTagFunctionType is the tag of a function type: [type-id: tstr, [* parameter], return: inline-type]
+	CBORTagFunctionType
+)
+
+// !!! *WARNING* !!!
+//
+// Only add new kinds to the end of the list.
+//
+// DO NOT REPLACE EXISTING KINDS, OR INSERT NEW KINDS,
+// as this will change the encoding of existing messages
+//
+const (
+	definitionKindStruct uint64 = iota
+	definitionKindResource
+	definitionKindEvent
+	definitionKindContract
+	definitionKindEnum
+	definitionKindStructInterface
+	definitionKindResourceInterface
+	definitionKindContractInterface
+)
+
+// simpleTypes are the types which are encoded as CBORTagSimpleType,
+// with their index in this list as the content.
+//
+// !!! *WARNING* !!!
+//
+// Only add new types to the end of the list.
+//
+// DO NOT REPLACE EXISTING TYPES, OR INSERT NEW TYPES,
+// as this will change the encoding of existing messages
+//
+var simpleTypes = []cadence.Type{
+	cadence.AnyType{},
+	cadence.AnyStructType{},
+	cadence.AnyResourceType{},
+	cadence.MetaType{},
+	cadence.VoidType{},
+	cadence.NeverType{},
+	cadence.BoolType{},
+	cadence.StringType{},
+	cadence.CharacterType{},
+	cadence.BytesType{},
+	cadence.AddressType{},
+	cadence.NumberType{},
+	cadence.SignedNumberType{},
+	cadence.IntegerType{},
+	cadence.SignedIntegerType{},
+	cadence.FixedPointType{},
+	cadence.SignedFixedPointType{},
+	cadence.IntType{},
+	cadence.Int8Type{},
+	cadence.Int16Type{},
+	cadence.Int32Type{},
+	cadence.Int64Type{},
+	cadence.Int128Type{},
+	cadence.Int256Type{},
+	cadence.UIntType{},
+	cadence.UInt8Type{},
+	cadence.UInt16Type{},
+	cadence.UInt32Type{},
+	cadence.UInt64Type{},
+	cadence.UInt128Type{},
+	cadence.UInt256Type{},
+	cadence.Word8Type{},
+	cadence.Word16Type{},
+	cadence.Word32Type{},
+	cadence.Word64Type{},
+	cadence.Fix64Type{},
+	cadence.UFix64Type{},
+	cadence.BlockType{},
+	cadence.PathType{},
+	cadence.CapabilityPathType{},
+	cadence.StoragePathType{},
+	cadence.PublicPathType{},
+	cadence.PrivatePathType{},
+	cadence.AuthAccountType{},
+	cadence.PublicAccountType{},
+	cadence.AuthAccountKeysType{},
+	cadence.PublicAccountKeysType{},
+	cadence.AuthAccountContractsType{},
+	cadence.PublicAccountContractsType{},
+	cadence.DeployedContractType{},
+	cadence.AccountKeyType{},
+	cadence.Fix128Type{},
+	cadence.UFix128Type{},
+	cadence.TimestampType{},
+	cadence.DurationType{},
+}
+
+var simpleTypeIDs = func() map[cadence.Type]uint64 {
+	ids := make(map[cadence.Type]uint64, len(simpleTypes))
+	for i, simpleType := range simpleTypes {
+		ids[simpleType] = uint64(i)
+	}
+	return ids
+}()
+
+// needsRuntimeType returns true if values of the given static type
+// must be encoded together with their type, i.e. if the static type
+// is not sufficient to determine the type of the value
+//
+func needsRuntimeType(staticType cadence.Type) bool {
+	switch staticType.(type) {
+	case cadence.VoidType,
+		cadence.BoolType,
+		cadence.StringType,
+		cadence.BytesType,
+		cadence.AddressType,
+		cadence.IntType,
+		cadence.Int8Type,
+		cadence.Int16Type,
+		cadence.Int32Type,
+		cadence.Int64Type,
+		cadence.Int128Type,
+		cadence.Int256Type,
+		cadence.UIntType,
+		cadence.UInt8Type,
+		cadence.UInt16Type,
+		cadence.UInt32Type,
+		cadence.UInt64Type,
+		cadence.UInt128Type,
+		cadence.UInt256Type,
+		cadence.Word8Type,
+		cadence.Word16Type,
+		cadence.Word32Type,
+		cadence.Word64Type,
+		cadence.Fix64Type,
+		cadence.UFix64Type,
+		cadence.Fix128Type,
+		cadence.UFix128Type,
+		cadence.TimestampType,
+		cadence.DurationType,
+		cadence.PathType,
+		cadence.CapabilityPathType,
+		cadence.StoragePathType,
+		cadence.PublicPathType,
+		cadence.PrivatePathType,
+		cadence.MetaType,
+		cadence.CapabilityType,
+		cadence.OptionalType,
+		cadence.VariableSizedArrayType,
+		cadence.ConstantSizedArrayType,
+		cadence.DictionaryType,
+		*cadence.StructType,
+		*cadence.ResourceType,
+		*cadence.EventType,
+		*cadence.ContractType,
+		*cadence.EnumType:
+
+		return false
+
+	default:
+		return true
+	}
+}
+
+// definitionKind returns the kind of the type definition for the given type,
+// if the type is a composite or interface type
+//
+func definitionKind(t cadence.Type) (uint64, bool) {
+	switch t.(type) {
+	case *cadence.StructType:
+		return definitionKindStruct, true
+	case *cadence.ResourceType:
+		return definitionKindResource, true
+	case *cadence.EventType:
+		return definitionKindEvent, true
+	case *cadence.ContractType:
+		return definitionKindContract, true
+	case *cadence.EnumType:
+		return definitionKindEnum, true
+	case *cadence.StructInterfaceType:
+		return definitionKindStructInterface, true
+	case *cadence.ResourceInterfaceType:
+		return definitionKindResourceInterface, true
+	case *cadence.ContractInterfaceType:
+		return definitionKindContractInterface, true
+	default:
+		return 0, false
+	}
+}
+
+// definitionMembers returns the fields, initializers, and the raw type
+// of the given composite or interface type
+//
+func definitionMembers(t cadence.Type) (fields []cadence.Field, initializers [][]cadence.Parameter, rawType cadence.Type) {
+	switch t := t.(type) {
+	case *cadence.StructType:
+		return t.Fields, t.Initializers, nil
+	case *cadence.ResourceType:
+		return t.Fields, t.Initializers, nil
+	case *cadence.EventType:
+		return t.Fields, [][]cadence.Parameter{t.Initializer}, nil
+	case *cadence.ContractType:
+		return t.Fields, t.Initializers, nil
+	case *cadence.EnumType:
+		return t.Fields, t.Initializers, t.RawType
+	case *cadence.StructInterfaceType:
+		return t.Fields, t.Initializers, nil
+	case *cadence.ResourceInterfaceType:
+		return t.Fields, t.Initializers, nil
+	case *cadence.ContractInterfaceType:
+		return t.Fields, t.Initializers, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// valueFields returns the fields of the composite type
+// which are stored in values, i.e. all fields except functions
+//
+func valueFields(fields []cadence.Field) []cadence.Field {
+	result := make([]cadence.Field, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := field.Type.(cadence.FunctionType); ok {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// typesEqual returns true if the given types are structurally equal.
+// Composite and interface types are compared by kind and type ID
+//
+func typesEqual(a, b cadence.Type) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+
+	case cadence.OptionalType:
+		b, ok := b.(cadence.OptionalType)
+		return ok && typesEqual(a.Type, b.Type)
+
+	case cadence.VariableSizedArrayType:
+		b, ok := b.(cadence.VariableSizedArrayType)
+		return ok && typesEqual(a.ElementType, b.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		b, ok := b.(cadence.ConstantSizedArrayType)
+		return ok &&
+			a.Size == b.Size &&
+			typesEqual(a.ElementType, b.ElementType)
+
+	case cadence.DictionaryType:
+		b, ok := b.(cadence.DictionaryType)
+		return ok &&
+			typesEqual(a.KeyType, b.KeyType) &&
+			typesEqual(a.ElementType, b.ElementType)
+
+	case cadence.ReferenceType:
+		b, ok := b.(cadence.ReferenceType)
+		return ok &&
+			a.Authorized == b.Authorized &&
+			typesEqual(a.Type, b.Type)
+
+	case cadence.RestrictedType:
+		b, ok := b.(cadence.RestrictedType)
+		if !ok ||
+			a.ID() != b.ID() ||
+			!typesEqual(a.Type, b.Type) ||
+			len(a.Restrictions) != len(b.Restrictions) {
+
+			return false
+		}
+		for i, restriction := range a.Restrictions {
+			if !typesEqual(restriction, b.Restrictions[i]) {
+				return false
+			}
+		}
+		return true
+
+	case cadence.CapabilityType:
+		b, ok := b.(cadence.CapabilityType)
+		return ok && typesEqual(a.BorrowType, b.BorrowType)
+
+	case cadence.FunctionType:
+		b, ok := b.(cadence.FunctionType)
+		return ok &&
+			a.ID() == b.ID() &&
+			parametersEqual(a.Parameters, b.Parameters) &&
+			typesEqual(a.ReturnType, b.ReturnType)
+	}
+
+	if kind, ok := definitionKind(a); ok {
+		otherKind, ok := definitionKind(b)
+		return ok &&
+			kind == otherKind &&
+			a.ID() == b.ID()
+	}
+
+	return a == b
+}
+
+func parametersEqual(a, b []cadence.Parameter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, parameter := range a {
+		otherParameter := b[i]
+		if parameter.Label != otherParameter.Label ||
+			parameter.Identifier != otherParameter.Identifier ||
+			!typesEqual(parameter.Type, otherParameter.Type) {
+
+			return false
+		}
+	}
+	return true
+}
+
+// definitionsEqual returns true if the given composite or interface types
+// have the same kind, type ID, and structurally equal members
+//
+func definitionsEqual(a, b cadence.Type) bool {
+	if !typesEqual(a, b) {
+		return false
+	}
+
+	fields, initializers, rawType := definitionMembers(a)
+	otherFields, otherInitializers, otherRawType := definitionMembers(b)
+
+	if len(fields) != len(otherFields) ||
+		len(initializers) != len(otherInitializers) ||
+		!typesEqual(rawType, otherRawType) {
+
+		return false
+	}
+
+	for i, field := range fields {
+		otherField := otherFields[i]
+		if field.Identifier != otherField.Identifier ||
+			!typesEqual(field.Type, otherField.Type) {
+
+			return false
+		}
+	}
+
+	for i, parameters := range initializers {
+		if !parametersEqual(parameters, otherInitializers[i]) {
+			return false
+		}
+	}
+
+	return true
+}
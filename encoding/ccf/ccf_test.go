@@ -0,0 +1,1121 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type encodeTest struct {
+	name string
+	val  cadence.Value
+}
+
+func TestEncodeVoid(t *testing.T) {
+
+	t.Parallel()
+
+	testEncodeAndDecode(t, cadence.NewVoid())
+}
+
+func TestEncodeOptional(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Nil",
+			cadence.NewOptional(nil),
+		},
+		{
+			"Non-nil",
+			cadence.NewOptional(cadence.NewInt(42)),
+		},
+		{
+			"Nested nil",
+			cadence.NewOptional(cadence.NewOptional(nil)),
+		},
+		{
+			"Nested non-nil",
+			cadence.NewOptional(cadence.NewOptional(cadence.NewInt(42))),
+		},
+	}...)
+}
+
+func TestEncodeBool(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"True", cadence.NewBool(true)},
+		{"False", cadence.NewBool(false)},
+	}...)
+}
+
+func TestEncodeString(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"Empty", cadence.String("")},
+		{"Non-empty", cadence.String("foo")},
+	}...)
+}
+
+func TestEncodeBytes(t *testing.T) {
+
+	t.Parallel()
+
+	// Bytes are not supported by JSON-Cadence,
+	// so only check the CCF round-trip
+
+	for _, value := range []cadence.Bytes{
+		cadence.NewBytes([]byte{}),
+		cadence.NewBytes([]byte{1, 2, 3}),
+	} {
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		decoded, err := ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, value, decoded)
+	}
+}
+
+func TestEncodeAddress(t *testing.T) {
+
+	t.Parallel()
+
+	testEncodeAndDecode(t, cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}))
+}
+
+func TestEncodeIntegers(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"Int negative", cadence.NewInt(-42)},
+		{"Int zero", cadence.NewInt(0)},
+		{"Int positive", cadence.NewInt(42)},
+		{
+			"Int smaller than min Int256",
+			cadence.NewIntFromBig(new(big.Int).Sub(sema.Int256TypeMinIntBig, big.NewInt(10))),
+		},
+		{
+			"Int larger than max UInt256",
+			cadence.NewIntFromBig(new(big.Int).Add(sema.UInt256TypeMaxIntBig, big.NewInt(10))),
+		},
+		{"Int8 min", cadence.NewInt8(math.MinInt8)},
+		{"Int8 max", cadence.NewInt8(math.MaxInt8)},
+		{"Int16 min", cadence.NewInt16(math.MinInt16)},
+		{"Int16 max", cadence.NewInt16(math.MaxInt16)},
+		{"Int32 min", cadence.NewInt32(math.MinInt32)},
+		{"Int32 max", cadence.NewInt32(math.MaxInt32)},
+		{"Int64 min", cadence.NewInt64(math.MinInt64)},
+		{"Int64 max", cadence.NewInt64(math.MaxInt64)},
+		{"Int128 min", cadence.Int128{Value: sema.Int128TypeMinIntBig}},
+		{"Int128 max", cadence.Int128{Value: sema.Int128TypeMaxIntBig}},
+		{"Int256 min", cadence.Int256{Value: sema.Int256TypeMinIntBig}},
+		{"Int256 max", cadence.Int256{Value: sema.Int256TypeMaxIntBig}},
+		{"UInt zero", cadence.NewUInt(0)},
+		{"UInt large", cadence.UInt{Value: sema.UInt256TypeMaxIntBig}},
+		{"UInt8 max", cadence.NewUInt8(math.MaxUint8)},
+		{"UInt16 max", cadence.NewUInt16(math.MaxUint16)},
+		{"UInt32 max", cadence.NewUInt32(math.MaxUint32)},
+		{"UInt64 max", cadence.NewUInt64(math.MaxUint64)},
+		{"UInt128 zero", cadence.NewUInt128(0)},
+		{"UInt128 max", cadence.UInt128{Value: sema.UInt128TypeMaxIntBig}},
+		{"UInt256 zero", cadence.NewUInt256(0)},
+		{"UInt256 max", cadence.UInt256{Value: sema.UInt256TypeMaxIntBig}},
+		{"Word8 max", cadence.NewWord8(math.MaxUint8)},
+		{"Word16 max", cadence.NewWord16(math.MaxUint16)},
+		{"Word32 max", cadence.NewWord32(math.MaxUint32)},
+		{"Word64 max", cadence.NewWord64(math.MaxUint64)},
+	}...)
+}
+
+func TestEncodeFixedPoints(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"Fix64 min", cadence.Fix64(math.MinInt64)},
+		{"Fix64 negative", cadence.Fix64(-123456789)},
+		{"Fix64 max", cadence.Fix64(math.MaxInt64)},
+		{"UFix64 zero", cadence.UFix64(0)},
+		{"UFix64 max", cadence.UFix64(math.MaxUint64)},
+		{"Fix128 min", cadence.Fix128{Value: sema.Fix128TypeMinBig}},
+		{"Fix128 negative", cadence.Fix128{Value: big.NewInt(-123456789)}},
+		{"Fix128 max", cadence.Fix128{Value: sema.Fix128TypeMaxBig}},
+		{"UFix128 zero", cadence.UFix128{Value: big.NewInt(0)}},
+		{"UFix128 max", cadence.UFix128{Value: sema.UFix128TypeMaxBig}},
+	}...)
+}
+
+func TestEncodeTimestampAndDuration(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"Timestamp epoch", cadence.Timestamp(0)},
+		{"Timestamp min", cadence.Timestamp(math.MinInt64)},
+		{"Timestamp max", cadence.Timestamp(math.MaxInt64)},
+		{"Duration negative", cadence.Duration(-1500000000)},
+		{"Duration max", cadence.Duration(math.MaxInt64)},
+	}...)
+}
+
+func TestEncodeArray(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Empty",
+			cadence.NewArray([]cadence.Value{}),
+		},
+		{
+			"Integers",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+				cadence.NewInt(3),
+			}),
+		},
+		{
+			"Mixed",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("foo"),
+				cadence.NewOptional(nil),
+				cadence.NewArray([]cadence.Value{
+					cadence.NewBool(true),
+				}),
+			}),
+		},
+		{
+			"Variable-sized",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewUInt8(1),
+				cadence.NewUInt8(2),
+			}).WithType(cadence.VariableSizedArrayType{
+				ElementType: cadence.UInt8Type{},
+			}),
+		},
+		{
+			"Constant-sized",
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.String("b"),
+			}).WithType(cadence.ConstantSizedArrayType{
+				ElementType: cadence.StringType{},
+				Size:        2,
+			}),
+		},
+		{
+			"Abstract element type",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt8(2),
+			}).WithType(cadence.VariableSizedArrayType{
+				ElementType: cadence.NumberType{},
+			}),
+		},
+	}...)
+}
+
+func TestEncodeDictionary(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Empty",
+			cadence.NewDictionary([]cadence.KeyValuePair{}),
+		},
+		{
+			"Untyped",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("a"),
+					Value: cadence.NewInt(1),
+				},
+				{
+					Key:   cadence.NewInt(2),
+					Value: cadence.NewOptional(nil),
+				},
+			}),
+		},
+		{
+			"Typed",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("a"),
+					Value: cadence.NewInt(1),
+				},
+				{
+					Key:   cadence.String("b"),
+					Value: cadence.NewInt(2),
+				},
+			}).WithType(cadence.DictionaryType{
+				KeyType:     cadence.StringType{},
+				ElementType: cadence.IntType{},
+			}),
+		},
+		{
+			"Nested",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key: cadence.String("a"),
+					Value: cadence.NewDictionary([]cadence.KeyValuePair{
+						{
+							Key:   cadence.String("b"),
+							Value: cadence.NewInt(1),
+						},
+					}).WithType(cadence.DictionaryType{
+						KeyType:     cadence.StringType{},
+						ElementType: cadence.IntType{},
+					}),
+				},
+			}).WithType(cadence.DictionaryType{
+				KeyType: cadence.StringType{},
+				ElementType: cadence.DictionaryType{
+					KeyType:     cadence.StringType{},
+					ElementType: cadence.IntType{},
+				},
+			}),
+		},
+	}...)
+}
+
+func exportFromScript(t *testing.T, code string) cadence.Value {
+	checker, err := checker.ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	var uuid uint64 = 0
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithUUIDHandler(
+			func() (uint64, error) {
+				uuid++
+				return uuid, nil
+			},
+		),
+		interpreter.WithStorage(
+			interpreter.NewInMemoryStorage(),
+		),
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	result, err := inter.Invoke("main")
+	require.NoError(t, err)
+
+	exported, err := runtime.ExportValue(result, inter)
+	require.NoError(t, err)
+
+	return exported
+}
+
+func TestEncodeComposites(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("Struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual := exportFromScript(t, `
+			struct Foo {
+				let a: Int
+				let b: String?
+				let c: AnyStruct
+
+				fun foo(): String {
+					return "foo"
+				}
+
+				init() {
+					self.a = 1
+					self.b = "2"
+					self.c = [3]
+				}
+			}
+
+			fun main(): Foo {
+				return Foo()
+			}
+		`)
+
+		testEncodeAndDecode(t, actual)
+	})
+
+	t.Run("Resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual := exportFromScript(t, `
+			resource Bar {
+				let x: Int
+
+				init(x: Int) {
+					self.x = x
+				}
+			}
+
+			resource Foo {
+				let bar: @Bar
+				let bars: @[Bar]
+
+				init(bar: @Bar) {
+					self.bar <- bar
+					self.bars <- [<- create Bar(x: 2), <- create Bar(x: 3)]
+				}
+
+				destroy() {
+					destroy self.bar
+					destroy self.bars
+				}
+			}
+
+			fun main(): @Foo {
+				return <- create Foo(bar: <- create Bar(x: 1))
+			}
+		`)
+
+		testEncodeAndDecode(t, actual)
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual := exportFromScript(t, `
+			enum Direction: UInt8 {
+				case up
+				case down
+			}
+
+			fun main(): [Direction] {
+				return [Direction.up, Direction.down]
+			}
+		`)
+
+		testEncodeAndDecode(t, actual)
+	})
+
+	t.Run("Contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		ty := &cadence.ContractType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "C",
+			Fields: []cadence.Field{
+				{
+					Identifier: "a",
+					Type:       cadence.IntType{},
+				},
+			},
+		}
+
+		testEncodeAndDecode(
+			t,
+			cadence.NewContract([]cadence.Value{
+				cadence.NewInt(1),
+			}).WithType(ty),
+		)
+	})
+
+	t.Run("Event", func(t *testing.T) {
+
+		t.Parallel()
+
+		ty := &cadence.EventType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "E",
+			Fields: []cadence.Field{
+				{
+					Identifier: "a",
+					Type:       cadence.IntType{},
+				},
+				{
+					Identifier: "b",
+					Type:       cadence.StringType{},
+				},
+			},
+			Initializer: []cadence.Parameter{
+				{
+					Label:      "a",
+					Identifier: "a",
+					Type:       cadence.IntType{},
+				},
+				{
+					Label:      "b",
+					Identifier: "b",
+					Type:       cadence.StringType{},
+				},
+			},
+		}
+
+		testEncodeAndDecode(
+			t,
+			cadence.NewEvent([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("foo"),
+			}).WithType(ty),
+		)
+	})
+
+	t.Run("Recursive type", func(t *testing.T) {
+
+		t.Parallel()
+
+		ty := &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Node",
+			Fields: []cadence.Field{
+				{
+					Identifier: "next",
+				},
+			},
+		}
+
+		ty.Fields[0].Type = cadence.OptionalType{
+			Type: ty,
+		}
+
+		testEncodeAndDecode(
+			t,
+			cadence.NewStruct([]cadence.Value{
+				cadence.NewOptional(
+					cadence.NewStruct([]cadence.Value{
+						cadence.NewOptional(nil),
+					}).WithType(ty),
+				),
+			}).WithType(ty),
+		)
+	})
+}
+
+func TestEncodeLink(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Root",
+			cadence.NewLink(
+				cadence.Path{Domain: "storage", Identifier: "foo"},
+				"Bar",
+			),
+		},
+		{
+			"Nested",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewLink(
+					cadence.Path{Domain: "private", Identifier: "foo"},
+					"&Int",
+				),
+			}),
+		},
+	}...)
+}
+
+func TestEncodePath(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{"Storage", cadence.Path{Domain: "storage", Identifier: "foo"}},
+		{"Private", cadence.Path{Domain: "private", Identifier: "foo"}},
+		{"Public", cadence.Path{Domain: "public", Identifier: "foo"}},
+	}...)
+}
+
+func TestEncodeCapability(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"With borrow type",
+			cadence.Capability{
+				Path:       cadence.Path{Domain: "storage", Identifier: "foo"},
+				Address:    cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+				BorrowType: cadence.IntType{},
+			},
+		},
+		{
+			"Without borrow type",
+			cadence.Capability{
+				Path:    cadence.Path{Domain: "public", Identifier: "foo"},
+				Address: cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+			},
+		},
+	}...)
+}
+
+func TestEncodeType(t *testing.T) {
+
+	t.Parallel()
+
+	structType := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "S",
+		Fields: []cadence.Field{
+			{
+				Identifier: "foo",
+				Type:       cadence.IntType{},
+			},
+		},
+		Initializers: [][]cadence.Parameter{
+			{
+				{
+					Label:      "foo",
+					Identifier: "foo",
+					Type:       cadence.IntType{},
+				},
+			},
+		},
+	}
+
+	resourceInterfaceType := &cadence.ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "RI",
+		Fields: []cadence.Field{
+			{
+				Identifier: "bar",
+				Type:       cadence.StringType{},
+			},
+		},
+	}
+
+	types := []cadence.Type{
+		cadence.IntType{},
+		cadence.AnyStructType{},
+		cadence.AuthAccountType{},
+		cadence.OptionalType{Type: cadence.IntType{}},
+		cadence.VariableSizedArrayType{ElementType: cadence.IntType{}},
+		cadence.ConstantSizedArrayType{ElementType: cadence.IntType{}, Size: 3},
+		cadence.DictionaryType{KeyType: cadence.IntType{}, ElementType: cadence.StringType{}},
+		cadence.ReferenceType{Authorized: true, Type: cadence.IntType{}},
+		cadence.CapabilityType{BorrowType: cadence.ReferenceType{Type: cadence.IntType{}}},
+		cadence.CapabilityType{},
+		structType,
+		resourceInterfaceType,
+		&cadence.StructInterfaceType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "SI",
+		},
+		&cadence.ContractInterfaceType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "CI",
+		},
+		cadence.RestrictedType{
+			Type:         cadence.AnyResourceType{},
+			Restrictions: []cadence.Type{resourceInterfaceType},
+		}.WithID("AnyResource{S.test.RI}"),
+		cadence.FunctionType{
+			Parameters: []cadence.Parameter{
+				{Label: "_", Identifier: "a", Type: cadence.IntType{}},
+			},
+			ReturnType: structType,
+		}.WithID("((Int):S.test.S)"),
+	}
+
+	for _, ty := range types {
+		ty := ty
+
+		t.Run(ty.ID(), func(t *testing.T) {
+
+			t.Parallel()
+
+			testEncodeAndDecode(t, cadence.NewTypeValue(ty))
+		})
+	}
+}
+
+func TestEncodeAbstractValues(t *testing.T) {
+
+	t.Parallel()
+
+	structType := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "S",
+		Fields: []cadence.Field{
+			{
+				Identifier: "foo",
+				Type:       cadence.AnyStructType{},
+			},
+		},
+	}
+
+	testEncodeAndDecode(
+		t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewStruct([]cadence.Value{
+				cadence.NewStruct([]cadence.Value{
+					cadence.NewInt(1),
+				}).WithType(structType),
+			}).WithType(structType),
+			cadence.NewTypeValue(cadence.IntType{}),
+			cadence.Path{Domain: "public", Identifier: "foo"},
+			cadence.NewDictionary([]cadence.KeyValuePair{}).WithType(cadence.DictionaryType{
+				KeyType:     cadence.PathType{},
+				ElementType: cadence.AnyStructType{},
+			}),
+		}),
+	)
+}
+
+func TestEncodeTypeDefinitionDeduplication(t *testing.T) {
+
+	t.Parallel()
+
+	newStructType := func() *cadence.StructType {
+		return &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "S",
+			Fields: []cadence.Field{
+				{
+					Identifier: "foo",
+					Type:       cadence.IntType{},
+				},
+			},
+		}
+	}
+
+	// Exported values have different, but equal type instances
+
+	value := cadence.NewArray([]cadence.Value{
+		cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}).WithType(newStructType()),
+		cadence.NewStruct([]cadence.Value{cadence.NewInt(2)}).WithType(newStructType()),
+	})
+
+	single := cadence.NewArray([]cadence.Value{
+		cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}).WithType(newStructType()),
+	})
+
+	encoded := testEncodeAndDecode(t, value)
+	encodedSingle := testEncodeAndDecode(t, single)
+
+	// The second value only adds its type, a reference to the type definition,
+	// and the field value, but not another type definition
+
+	assert.Less(t, len(encoded)-len(encodedSingle), 10)
+
+	// Types with the same ID, but different definitions,
+	// must not be merged
+
+	otherStructType := newStructType()
+	otherStructType.Fields[0].Type = cadence.StringType{}
+
+	testEncodeAndDecode(
+		t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}).WithType(newStructType()),
+			cadence.NewStruct([]cadence.Value{cadence.String("2")}).WithType(otherStructType),
+		}),
+	)
+}
+
+func TestEncodeSizeComparedToJSON(t *testing.T) {
+
+	t.Parallel()
+
+	actual := exportFromScript(t, `
+		struct Foo {
+			let id: UInt64
+			let name: String
+			let balance: UFix64
+
+			init(id: UInt64) {
+				self.id = id
+				self.name = "foo"
+				self.balance = 1.5
+			}
+		}
+
+		fun main(): [Foo] {
+			let foos: [Foo] = []
+			var i: UInt64 = 0
+			while i < 100 {
+				foos.append(Foo(id: i))
+				i = i + 1
+			}
+			return foos
+		}
+	`)
+
+	ccfEncoded := testEncodeAndDecode(t, actual)
+
+	jsonEncoded, err := json.Encode(actual)
+	require.NoError(t, err)
+
+	assert.Less(t, len(ccfEncoded)*5, len(jsonEncoded))
+}
+
+func TestEncodeStream(t *testing.T) {
+
+	t.Parallel()
+
+	values := []cadence.Value{
+		cadence.NewInt(1),
+		cadence.String("foo"),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewBool(true),
+		}),
+	}
+
+	var buf bytes.Buffer
+
+	encoder := ccf.NewEncoder(&buf)
+	for _, value := range values {
+		err := encoder.Encode(value)
+		require.NoError(t, err)
+	}
+
+	decoder := ccf.NewDecoder(&buf)
+	for _, value := range values {
+		decoded, err := decoder.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, value.String(), decoded.String())
+	}
+}
+
+func TestEncodeInvalidValue(t *testing.T) {
+
+	t.Parallel()
+
+	ty := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "S",
+		Fields: []cadence.Field{
+			{
+				Identifier: "foo",
+				Type:       cadence.IntType{},
+			},
+		},
+	}
+
+	// The field value does not conform to the field type
+
+	_, err := ccf.Encode(
+		cadence.NewStruct([]cadence.Value{
+			cadence.String("foo"),
+		}).WithType(ty),
+	)
+	require.Error(t, err)
+
+	// The constant-sized array has the wrong number of elements
+
+	_, err = ccf.Encode(
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+		}).WithType(cadence.ConstantSizedArrayType{
+			ElementType: cadence.IntType{},
+			Size:        2,
+		}),
+	)
+	require.Error(t, err)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	valid, err := ccf.Encode(
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.String("foo"),
+		}),
+	)
+	require.NoError(t, err)
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ccf.Decode(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ccf.Decode(append(append([]byte{}, valid...), 0))
+		require.Error(t, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		for i := 0; i < len(valid); i++ {
+			_, err := ccf.Decode(valid[:i])
+			require.Error(t, err)
+		}
+	})
+
+	for name, data := range map[string][]byte{
+		// [[], [], <int>, null]: value of type Int must be an integer
+		"type mismatch": {0x84, 0x80, 0x80, 0xd8, 0x83, 0x11, 0xf6},
+		// [[], [], <ref 0>, []]: reference to undefined type definition
+		"undefined type reference": {0x84, 0x80, 0x80, 0xd8, 0x82, 0x00, 0x80},
+		// [[], [], <simple 255>, null]: unknown simple type
+		"unknown simple type": {0x84, 0x80, 0x80, 0xd8, 0x83, 0x18, 0xff, 0xf6},
+		// [[], [], <Int8>, 128]: out of range
+		"out of range": {0x84, 0x80, 0x80, 0xd8, 0x83, 0x12, 0x18, 0x80},
+		// [[], [], <Path>, [4, "foo"]]: invalid domain
+		"invalid path domain": {0x84, 0x80, 0x80, 0xd8, 0x83, 0x26, 0x82, 0x04, 0x63, 'f', 'o', 'o'},
+		// [[], [], <AnyStruct>, <value>[<AnyStruct>, null]]: abstract runtime type
+		"abstract runtime type": {0x84, 0x80, 0x80, 0xd8, 0x83, 0x01, 0xd8, 0x80, 0x82, 0xd8, 0x83, 0x01, 0xf6},
+		// [[[99, "S.test.S"]], [[[], [], null]], null, null]: unknown definition kind
+		"unknown definition kind": {
+			0x84,
+			0x81, 0x82, 0x18, 0x63, 0x68, 'S', '.', 't', 'e', 's', 't', '.', 'S',
+			0x81, 0x83, 0x80, 0x80, 0xf6,
+			0xf6, 0xf6,
+		},
+	} {
+		data := data
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ccf.Decode(data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func testAllEncodeAndDecode(t *testing.T, tests ...encodeTest) {
+
+	test := func(testCase encodeTest) {
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			testEncodeAndDecode(t, testCase.val)
+		})
+	}
+
+	for _, testCase := range tests {
+		test(testCase)
+	}
+}
+
+// testEncodeAndDecode encodes the given value in CCF and decodes it again,
+// and checks that the decoded value is equivalent to the original value.
+//
+// As the JSON-Cadence format is the reference external encoding,
+// the original and the decoded value are compared through their JSON encodings.
+// The test also ensures that values decoded from JSON-Cadence can be encoded in CCF.
+//
+func testEncodeAndDecode(t *testing.T, value cadence.Value) []byte {
+
+	encoded, err := ccf.Encode(value)
+	require.NoError(t, err)
+
+	decoded, err := ccf.Decode(encoded)
+	require.NoError(t, err)
+
+	expectedJSON, err := json.Encode(value)
+	require.NoError(t, err)
+
+	actualJSON, err := json.Encode(decoded)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+
+	// Encoding the decoded value must result in the same encoding
+
+	reencoded, err := ccf.Encode(decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, encoded, reencoded)
+
+	// Values decoded from JSON-Cadence must round-trip through CCF
+
+	fromJSON, err := json.Decode(expectedJSON)
+	require.NoError(t, err)
+
+	encodedFromJSON, err := ccf.Encode(fromJSON)
+	require.NoError(t, err)
+
+	decodedFromJSON, err := ccf.Decode(encodedFromJSON)
+	require.NoError(t, err)
+
+	actualJSON, err = json.Encode(decodedFromJSON)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+
+	return encoded
+}
+
+func TestDecodeWithLimits(t *testing.T) {
+
+	t.Parallel()
+
+	limits := common.DecodingLimits{
+		MaxNestedLevels:  16,
+		MaxArrayElements: 16,
+		MaxValueCount:    32,
+	}
+
+	newArray := func(count int) cadence.Array {
+		values := make([]cadence.Value, count)
+		for i := range values {
+			values[i] = cadence.NewInt(i)
+		}
+		return cadence.NewArray(values).
+			WithType(cadence.VariableSizedArrayType{
+				ElementType: cadence.IntType{},
+			})
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		t.Parallel()
+
+		value := newArray(16)
+
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		decoded, err := ccf.DecodeWithLimits(encoded, limits)
+		require.NoError(t, err)
+		assert.Equal(t, value, decoded)
+	})
+
+	t.Run("nesting depth", func(t *testing.T) {
+		t.Parallel()
+
+		var value cadence.Value = cadence.NewInt(1)
+		for i := 0; i < 16; i++ {
+			value = cadence.NewArray([]cadence.Value{value})
+		}
+
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		_, err = ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		_, err = ccf.DecodeWithLimits(encoded, limits)
+		var nestedLevelErr *cbor.MaxNestedLevelError
+		require.ErrorAs(t, err, &nestedLevelErr)
+	})
+
+	t.Run("array length", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newArray(17))
+		require.NoError(t, err)
+
+		_, err = ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		_, err = ccf.DecodeWithLimits(encoded, limits)
+		var arrayElementsErr *cbor.MaxArrayElementsError
+		require.ErrorAs(t, err, &arrayElementsErr)
+	})
+
+	t.Run("value count", func(t *testing.T) {
+		t.Parallel()
+
+		value := cadence.NewArray([]cadence.Value{
+			newArray(16),
+			newArray(16),
+		})
+
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		_, err = ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		_, err = ccf.DecodeWithLimits(encoded, limits)
+		require.ErrorAs(t, err, &common.MaxValueCountExceededError{})
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := ccf.NewEncoder(&buf)
+
+		// Each message is limited separately
+
+		for i := 0; i < 3; i++ {
+			err := encoder.Encode(newArray(16))
+			require.NoError(t, err)
+		}
+
+		decoder, err := ccf.NewDecoderWithLimits(&buf, limits)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := decoder.Decode()
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newArray(1))
+		require.NoError(t, err)
+
+		for _, invalidLimits := range []common.DecodingLimits{
+			{},
+			{
+				MaxNestedLevels:  1,
+				MaxArrayElements: 16,
+				MaxValueCount:    32,
+			},
+		} {
+			_, err = ccf.DecodeWithLimits(encoded, invalidLimits)
+			require.Error(t, err)
+		}
+	})
+}
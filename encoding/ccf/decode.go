@@ -0,0 +1,1177 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// A Decoder decodes CCF-encoded representations of Cadence values.
+//
+type Decoder struct {
+	dec *cbor.StreamDecoder
+	// definitions are the composite and interface types
+	// which are defined in the message, in order
+	definitions []cadence.Type
+	limits      common.DecodingLimits
+	// valueCounter counts the values decoded from the current message
+	valueCounter *common.DecodedValueCounter
+}
+
+var ErrInvalidCCF = errors.New("invalid CCF structure")
+
+func invalidCCFError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCCF, fmt.Sprintf(format, args...))
+}
+
+// Decode returns a Cadence value decoded from its CCF-encoded representation.
+//
+// This function returns an error if the bytes are malformed
+// or do not conform to the CCF specification.
+//
+func Decode(b []byte) (cadence.Value, error) {
+	dec := &Decoder{
+		dec:    CBORDecMode.NewByteStreamDecoder(b),
+		limits: common.DefaultDecodingLimits,
+	}
+
+	return dec.decodeMessage(b)
+}
+
+// DecodeWithLimits returns a Cadence value decoded from its CCF-encoded representation,
+// like Decode, but enforces the given decoding limits instead of the default limits,
+// see common.DecodingLimits.
+//
+// This function returns an error if the limits are invalid,
+// or if the bytes exceed the limits.
+//
+func DecodeWithLimits(b []byte, limits common.DecodingLimits) (cadence.Value, error) {
+	decMode, err := newCBORDecMode(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := &Decoder{
+		dec:    decMode.NewByteStreamDecoder(b),
+		limits: limits,
+	}
+
+	return dec.decodeMessage(b)
+}
+
+func (d *Decoder) decodeMessage(b []byte) (cadence.Value, error) {
+	v, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.dec.NumBytesDecoded() != len(b) {
+		return nil, invalidCCFError("trailing data")
+	}
+
+	return v, nil
+}
+
+// NewDecoder initializes a Decoder that will decode CCF-encoded bytes from the
+// given io.Reader.
+//
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		dec:    CBORDecMode.NewStreamDecoder(r),
+		limits: common.DefaultDecodingLimits,
+	}
+}
+
+// NewDecoderWithLimits initializes a Decoder that will decode CCF-encoded bytes from the
+// given io.Reader, and enforces the given decoding limits for each message.
+//
+// This function returns an error if the limits are invalid.
+//
+func NewDecoderWithLimits(r io.Reader, limits common.DecodingLimits) (*Decoder, error) {
+	decMode, err := newCBORDecMode(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder{
+		dec:    decMode.NewStreamDecoder(r),
+		limits: limits,
+	}, nil
+}
+
+// Decode reads CCF-encoded bytes from the io.Reader and decodes them to a
+// Cadence value.
+//
+// This function returns an error if the bytes are malformed
+// or do not conform to the CCF specification.
+//
+func (d *Decoder) Decode() (value cadence.Value, err error) {
+
+	// Each message is self-contained
+
+	d.definitions = nil
+	d.valueCounter = common.NewDecodedValueCounter(d.limits)
+
+	err = d.decodeArrayHead(4)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.decodeDefinitionHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.decodeDefinitionMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	staticType, err := d.decodeType()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.decodeValue(staticType)
+}
+
+// decodeArrayHead decodes the head of an array with the given expected length
+//
+func (d *Decoder) decodeArrayHead(expectedLength uint64) error {
+	length, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return err
+	}
+
+	if length != expectedLength {
+		return invalidCCFError(
+			"invalid array length: expected %d, got %d",
+			expectedLength,
+			length,
+		)
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeDefinitionHeaders() error {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return err
+	}
+
+	d.definitions = make([]cadence.Type, count)
+
+	for i := uint64(0); i < count; i++ {
+		err = d.decodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		kind, err := d.dec.DecodeUint64()
+		if err != nil {
+			return err
+		}
+
+		typeID, err := d.dec.DecodeString()
+		if err != nil {
+			return err
+		}
+
+		location, qualifiedIdentifier, err := common.DecodeTypeID(typeID)
+		if err != nil ||
+			location == nil && sema.NativeCompositeTypes[typeID] == nil {
+
+			return invalidCCFError("invalid type ID: `%s`", typeID)
+		}
+
+		var definition cadence.Type
+
+		switch kind {
+		case definitionKindStruct:
+			definition = &cadence.StructType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindResource:
+			definition = &cadence.ResourceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindEvent:
+			definition = &cadence.EventType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindContract:
+			definition = &cadence.ContractType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindEnum:
+			definition = &cadence.EnumType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindStructInterface:
+			definition = &cadence.StructInterfaceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindResourceInterface:
+			definition = &cadence.ResourceInterfaceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		case definitionKindContractInterface:
+			definition = &cadence.ContractInterfaceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}
+		default:
+			return invalidCCFError("invalid type definition kind: %d", kind)
+		}
+
+		d.definitions[i] = definition
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeDefinitionMembers() error {
+	err := d.decodeArrayHead(uint64(len(d.definitions)))
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range d.definitions {
+		err = d.decodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+
+		fields, err := d.decodeFields()
+		if err != nil {
+			return err
+		}
+
+		initializers, err := d.decodeInitializers()
+		if err != nil {
+			return err
+		}
+
+		rawType, err := d.decodeType()
+		if err != nil {
+			return err
+		}
+
+		switch definition := definition.(type) {
+		case *cadence.StructType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		case *cadence.ResourceType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		case *cadence.EventType:
+			definition.Fields = fields
+			switch len(initializers) {
+			case 0:
+				break
+			case 1:
+				definition.Initializer = initializers[0]
+			default:
+				return invalidCCFError("invalid event initializer count: %d", len(initializers))
+			}
+		case *cadence.ContractType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		case *cadence.EnumType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+			definition.RawType = rawType
+		case *cadence.StructInterfaceType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		case *cadence.ResourceInterfaceType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		case *cadence.ContractInterfaceType:
+			definition.Fields = fields
+			definition.Initializers = initializers
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeFields() ([]cadence.Field, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]cadence.Field, count)
+
+	for i := range fields {
+		err = d.decodeArrayHead(2)
+		if err != nil {
+			return nil, err
+		}
+
+		identifier, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		fieldType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+
+		fields[i] = cadence.Field{
+			Identifier: identifier,
+			Type:       fieldType,
+		}
+	}
+
+	return fields, nil
+}
+
+func (d *Decoder) decodeInitializers() ([][]cadence.Parameter, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	initializers := make([][]cadence.Parameter, count)
+
+	for i := range initializers {
+		initializers[i], err = d.decodeParameters()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return initializers, nil
+}
+
+func (d *Decoder) decodeParameters() ([]cadence.Parameter, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make([]cadence.Parameter, count)
+
+	for i := range parameters {
+		err = d.decodeArrayHead(3)
+		if err != nil {
+			return nil, err
+		}
+
+		label, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		identifier, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		parameterType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+
+		parameters[i] = cadence.Parameter{
+			Label:      label,
+			Identifier: identifier,
+			Type:       parameterType,
+		}
+	}
+
+	return parameters, nil
+}
+
+// decodeType decodes an inline type, or null
+//
+func (d *Decoder) decodeType() (cadence.Type, error) {
+	nextType, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	if nextType == cbor.NilType {
+		err = d.dec.DecodeNil()
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	tag, err := d.dec.DecodeTagNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case CBORTagTypeRef:
+		index, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+		if index >= uint64(len(d.definitions)) {
+			return nil, invalidCCFError("invalid type definition reference: %d", index)
+		}
+		return d.definitions[index], nil
+
+	case CBORTagSimpleType:
+		id, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+		if id >= uint64(len(simpleTypes)) {
+			return nil, invalidCCFError("invalid simple type: %d", id)
+		}
+		return simpleTypes[id], nil
+
+	case CBORTagOptionalType:
+		innerType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.OptionalType{
+			Type: innerType,
+		}, nil
+
+	case CBORTagVariableSizedArrayType:
+		elementType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.VariableSizedArrayType{
+			ElementType: elementType,
+		}, nil
+
+	case CBORTagConstantSizedArrayType:
+		err = d.decodeArrayHead(2)
+		if err != nil {
+			return nil, err
+		}
+		size, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+		elementType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.ConstantSizedArrayType{
+			Size:        uint(size),
+			ElementType: elementType,
+		}, nil
+
+	case CBORTagDictionaryType:
+		err = d.decodeArrayHead(2)
+		if err != nil {
+			return nil, err
+		}
+		keyType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		elementType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.DictionaryType{
+			KeyType:     keyType,
+			ElementType: elementType,
+		}, nil
+
+	case CBORTagReferenceType:
+		err = d.decodeArrayHead(2)
+		if err != nil {
+			return nil, err
+		}
+		authorized, err := d.dec.DecodeBool()
+		if err != nil {
+			return nil, err
+		}
+		referencedType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.ReferenceType{
+			Authorized: authorized,
+			Type:       referencedType,
+		}, nil
+
+	case CBORTagRestrictedType:
+		err = d.decodeArrayHead(3)
+		if err != nil {
+			return nil, err
+		}
+		typeID, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		restrictedType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		count, err := d.dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+		restrictions := make([]cadence.Type, count)
+		for i := range restrictions {
+			restrictions[i], err = d.decodeType()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return cadence.RestrictedType{
+			Type:         restrictedType,
+			Restrictions: restrictions,
+		}.WithID(typeID), nil
+
+	case CBORTagCapabilityType:
+		borrowType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.CapabilityType{
+			BorrowType: borrowType,
+		}, nil
+
+	case CBORTagFunctionType:
+		err = d.decodeArrayHead(3)
+		if err != nil {
+			return nil, err
+		}
+		typeID, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		parameters, err := d.decodeParameters()
+		if err != nil {
+			return nil, err
+		}
+		returnType, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.FunctionType{
+			Parameters: parameters,
+			ReturnType: returnType,
+		}.WithID(typeID), nil
+
+	default:
+		return nil, invalidCCFError("invalid type tag: %d", tag)
+	}
+}
+
+// decodeValue decodes a value of the given static type
+//
+func (d *Decoder) decodeValue(staticType cadence.Type) (cadence.Value, error) {
+
+	if needsRuntimeType(staticType) {
+		tag, err := d.dec.DecodeTagNumber()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case CBORTagLinkValue:
+			err = d.valueCounter.Count()
+			if err != nil {
+				return nil, err
+			}
+
+			return d.decodeLink()
+
+		case CBORTagTypeAndValue:
+			err = d.decodeArrayHead(2)
+			if err != nil {
+				return nil, err
+			}
+
+			valueType, err := d.decodeType()
+			if err != nil {
+				return nil, err
+			}
+
+			if needsRuntimeType(valueType) {
+				return nil, invalidCCFError("invalid value type: %v", valueType)
+			}
+
+			return d.decodeValue(valueType)
+
+		default:
+			return nil, invalidCCFError("invalid value tag: %d", tag)
+		}
+	}
+
+	// Values with a runtime type are counted once, when decoding the value of the runtime type
+
+	err := d.valueCounter.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	switch staticType := staticType.(type) {
+	case cadence.VoidType:
+		err := d.dec.DecodeNil()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewVoid(), nil
+
+	case cadence.BoolType:
+		b, err := d.dec.DecodeBool()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewBool(b), nil
+
+	case cadence.StringType:
+		s, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewString(s)
+
+	case cadence.BytesType:
+		b, err := d.dec.DecodeBytes()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewBytes(b), nil
+
+	case cadence.AddressType:
+		b, err := d.dec.DecodeBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != cadence.AddressLength {
+			return nil, invalidCCFError("invalid address length: %d", len(b))
+		}
+		return cadence.BytesToAddress(b), nil
+
+	case cadence.IntType:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewIntFromBig(i), nil
+
+	case cadence.Int8Type:
+		i, err := d.decodeInt64(math.MinInt8, math.MaxInt8)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt8(int8(i)), nil
+
+	case cadence.Int16Type:
+		i, err := d.decodeInt64(math.MinInt16, math.MaxInt16)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt16(int16(i)), nil
+
+	case cadence.Int32Type:
+		i, err := d.decodeInt64(math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt32(int32(i)), nil
+
+	case cadence.Int64Type:
+		i, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt64(i), nil
+
+	case cadence.Int128Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt128FromBig(i)
+
+	case cadence.Int256Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt256FromBig(i)
+
+	case cadence.UIntType:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUIntFromBig(i)
+
+	case cadence.UInt8Type:
+		i, err := d.decodeUint64(math.MaxUint8)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt8(uint8(i)), nil
+
+	case cadence.UInt16Type:
+		i, err := d.decodeUint64(math.MaxUint16)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt16(uint16(i)), nil
+
+	case cadence.UInt32Type:
+		i, err := d.decodeUint64(math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt32(uint32(i)), nil
+
+	case cadence.UInt64Type:
+		i, err := d.decodeUint64(math.MaxUint64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt64(i), nil
+
+	case cadence.UInt128Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt128FromBig(i)
+
+	case cadence.UInt256Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt256FromBig(i)
+
+	case cadence.Word8Type:
+		i, err := d.decodeUint64(math.MaxUint8)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewWord8(uint8(i)), nil
+
+	case cadence.Word16Type:
+		i, err := d.decodeUint64(math.MaxUint16)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewWord16(uint16(i)), nil
+
+	case cadence.Word32Type:
+		i, err := d.decodeUint64(math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewWord32(uint32(i)), nil
+
+	case cadence.Word64Type:
+		i, err := d.decodeUint64(math.MaxUint64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewWord64(i), nil
+
+	case cadence.Fix64Type:
+		i, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.Fix64(i), nil
+
+	case cadence.UFix64Type:
+		i, err := d.decodeUint64(math.MaxUint64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.UFix64(i), nil
+
+	case cadence.Fix128Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewFix128FromBig(i)
+
+	case cadence.UFix128Type:
+		i, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUFix128FromBig(i)
+
+	case cadence.TimestampType:
+		i, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.Timestamp(i), nil
+
+	case cadence.DurationType:
+		i, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.Duration(i), nil
+
+	case cadence.PathType,
+		cadence.CapabilityPathType,
+		cadence.StoragePathType,
+		cadence.PublicPathType,
+		cadence.PrivatePathType:
+
+		return d.decodePath()
+
+	case cadence.MetaType:
+		t, err := d.decodeType()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewTypeValue(t), nil
+
+	case cadence.CapabilityType:
+		return d.decodeCapability()
+
+	case cadence.OptionalType:
+		return d.decodeOptional(staticType)
+
+	case cadence.VariableSizedArrayType:
+		values, err := d.decodeValues(staticType.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewArray(values).WithType(staticType), nil
+
+	case cadence.ConstantSizedArrayType:
+		values, err := d.decodeValues(staticType.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		if uint(len(values)) != staticType.Size {
+			return nil, invalidCCFError(
+				"invalid constant-sized array length: expected %d, got %d",
+				staticType.Size,
+				len(values),
+			)
+		}
+		return cadence.NewArray(values).WithType(staticType), nil
+
+	case cadence.DictionaryType:
+		return d.decodeDictionary(staticType)
+
+	case *cadence.StructType:
+		fields, err := d.decodeCompositeFields(staticType.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewStruct(fields).WithType(staticType), nil
+
+	case *cadence.ResourceType:
+		fields, err := d.decodeCompositeFields(staticType.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewResource(fields).WithType(staticType), nil
+
+	case *cadence.EventType:
+		fields, err := d.decodeCompositeFields(staticType.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewEvent(fields).WithType(staticType), nil
+
+	case *cadence.ContractType:
+		fields, err := d.decodeCompositeFields(staticType.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewContract(fields).WithType(staticType), nil
+
+	case *cadence.EnumType:
+		fields, err := d.decodeCompositeFields(staticType.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewEnum(fields).WithType(staticType), nil
+
+	default:
+		return nil, invalidCCFError("unsupported static type: %v", staticType)
+	}
+}
+
+// decodeBigInt decodes an integer, which is either encoded as a CBOR integer,
+// or, if it does not fit into 64 bits, as a CBOR bignum
+//
+func (d *Decoder) decodeBigInt() (*big.Int, error) {
+	nextType, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch nextType {
+	case cbor.UintType:
+		i, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(i), nil
+
+	case cbor.IntType:
+		i, err := d.dec.DecodeInt64()
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(i), nil
+
+	default:
+		return d.dec.DecodeBigInt()
+	}
+}
+
+func (d *Decoder) decodeInt64(min, max int64) (int64, error) {
+	i, err := d.dec.DecodeInt64()
+	if err != nil {
+		return 0, err
+	}
+
+	if i < min || i > max {
+		return 0, invalidCCFError("integer out of range: %d", i)
+	}
+
+	return i, nil
+}
+
+func (d *Decoder) decodeUint64(max uint64) (uint64, error) {
+	i, err := d.dec.DecodeUint64()
+	if err != nil {
+		return 0, err
+	}
+
+	if i > max {
+		return 0, invalidCCFError("integer out of range: %d", i)
+	}
+
+	return i, nil
+}
+
+func (d *Decoder) decodeOptional(staticType cadence.OptionalType) (cadence.Value, error) {
+	nextType, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	if nextType == cbor.NilType {
+		err = d.dec.DecodeNil()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(nil), nil
+	}
+
+	// Nested optionals are wrapped,
+	// so non-nil values can be distinguished from nil
+
+	if _, ok := staticType.Type.(cadence.OptionalType); ok {
+		err = d.decodeArrayHead(1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := d.decodeValue(staticType.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewOptional(value), nil
+}
+
+func (d *Decoder) decodeValues(elementType cadence.Type) ([]cadence.Value, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]cadence.Value, count)
+
+	for i := range values {
+		values[i], err = d.decodeValue(elementType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (d *Decoder) decodeDictionary(staticType cadence.DictionaryType) (cadence.Value, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if count%2 != 0 {
+		return nil, invalidCCFError("invalid dictionary length: %d", count)
+	}
+
+	pairs := make([]cadence.KeyValuePair, count/2)
+
+	for i := range pairs {
+		key, err := d.decodeValue(staticType.KeyType)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decodeValue(staticType.ElementType)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[i] = cadence.KeyValuePair{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	return cadence.NewDictionary(pairs).WithType(staticType), nil
+}
+
+func (d *Decoder) decodeCompositeFields(fieldTypes []cadence.Field) ([]cadence.Value, error) {
+	fields := valueFields(fieldTypes)
+
+	err := d.decodeArrayHead(uint64(len(fields)))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]cadence.Value, len(fields))
+
+	for i, field := range fields {
+		values[i], err = d.decodeValue(field.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (d *Decoder) decodePath() (cadence.Path, error) {
+	err := d.decodeArrayHead(2)
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	domain, err := d.dec.DecodeUint64()
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	pathDomain := common.PathDomain(domain)
+	if domain > math.MaxUint8 ||
+		common.PathDomainFromIdentifier(pathDomain.Identifier()) != pathDomain {
+
+		return cadence.Path{}, invalidCCFError("invalid path domain: %d", domain)
+	}
+
+	identifier, err := d.dec.DecodeString()
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	return cadence.Path{
+		Domain:     pathDomain.Identifier(),
+		Identifier: identifier,
+	}, nil
+}
+
+func (d *Decoder) decodeCapability() (cadence.Value, error) {
+	err := d.decodeArrayHead(3)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := d.decodePath()
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := d.dec.DecodeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(address) != cadence.AddressLength {
+		return nil, invalidCCFError("invalid address length: %d", len(address))
+	}
+
+	borrowType, err := d.decodeType()
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.Capability{
+		Path:       path,
+		Address:    cadence.BytesToAddress(address),
+		BorrowType: borrowType,
+	}, nil
+}
+
+func (d *Decoder) decodeLink() (cadence.Value, error) {
+	err := d.decodeArrayHead(2)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath, err := d.decodePath()
+	if err != nil {
+		return nil, err
+	}
+
+	borrowType, err := d.dec.DecodeString()
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewLink(targetPath, borrowType), nil
+}
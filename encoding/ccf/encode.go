@@ -0,0 +1,884 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// An Encoder converts Cadence values into CCF-encoded bytes.
+//
+type Encoder struct {
+	w io.Writer
+	// definitions are the composite and interface types
+	// which are defined in the message, in order
+	definitions []cadence.Type
+	// definitionIndices maps type IDs to the indices of the type definitions
+	// which have the type ID. Types with the same type ID might be defined multiple times,
+	// if their members differ
+	definitionIndices map[string][]int
+}
+
+// Encode returns the CCF-encoded representation of the given value.
+//
+// This function returns an error if the Cadence value cannot be represented in CCF.
+//
+func Encode(value cadence.Value) ([]byte, error) {
+	var w bytes.Buffer
+	enc := NewEncoder(&w)
+
+	err := enc.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// MustEncode returns the CCF-encoded representation of the given value, or panics
+// if the value cannot be represented in CCF.
+//
+func MustEncode(value cadence.Value) []byte {
+	b, err := Encode(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewEncoder initializes an Encoder that will write CCF-encoded bytes to the
+// given io.Writer.
+//
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the CCF-encoded representation of the given value to this
+// encoder's io.Writer.
+//
+// This function returns an error if the given value's type is not supported
+// by this encoder.
+//
+func (e *Encoder) Encode(value cadence.Value) (err error) {
+
+	// Each message is self-contained
+
+	e.definitions = nil
+	e.definitionIndices = map[string][]int{}
+
+	// Encode the type and the value first,
+	// as they determine which types must be defined
+
+	var typeAndValue bytes.Buffer
+	enc := CBOREncMode.NewStreamEncoder(&typeAndValue)
+
+	staticType := runtimeType(value)
+
+	err = e.encodeType(enc, staticType)
+	if err != nil {
+		return err
+	}
+
+	err = e.encodeValue(enc, value, staticType)
+	if err != nil {
+		return err
+	}
+
+	err = enc.Flush()
+	if err != nil {
+		return err
+	}
+
+	// Encode the members of the type definitions.
+	// Encoding the members might define further types
+
+	var members bytes.Buffer
+	enc = CBOREncMode.NewStreamEncoder(&members)
+
+	for i := 0; i < len(e.definitions); i++ {
+		err = e.encodeDefinitionMembers(enc, e.definitions[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = enc.Flush()
+	if err != nil {
+		return err
+	}
+
+	// Encode the message
+
+	enc = CBOREncMode.NewStreamEncoder(e.w)
+
+	err = enc.EncodeArrayHead(4)
+	if err != nil {
+		return err
+	}
+
+	// Encode the headers of the type definitions
+
+	err = enc.EncodeArrayHead(uint64(len(e.definitions)))
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range e.definitions {
+		err = e.encodeDefinitionHeader(enc, definition)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = enc.EncodeArrayHead(uint64(len(e.definitions)))
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeRawBytes(members.Bytes())
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeRawBytes(typeAndValue.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// runtimeType returns the type of the given value.
+//
+// Arrays and dictionaries without a type are assumed to contain values of any type.
+// Links have no type, so nil is returned.
+//
+func runtimeType(value cadence.Value) cadence.Type {
+	switch value := value.(type) {
+	case cadence.Optional:
+		if value.Value == nil {
+			return cadence.OptionalType{
+				Type: cadence.NeverType{},
+			}
+		}
+		return cadence.OptionalType{
+			Type: runtimeType(value.Value),
+		}
+
+	case cadence.Array:
+		if value.ArrayType == nil {
+			return cadence.VariableSizedArrayType{
+				ElementType: cadence.AnyType{},
+			}
+		}
+		return value.ArrayType
+
+	case cadence.Dictionary:
+		if value.DictionaryType == nil {
+			return cadence.DictionaryType{
+				KeyType:     cadence.AnyType{},
+				ElementType: cadence.AnyType{},
+			}
+		}
+		return value.DictionaryType
+
+	case cadence.Link:
+		return nil
+
+	case cadence.Struct:
+		if value.StructType == nil {
+			return nil
+		}
+		return value.StructType
+
+	case cadence.Resource:
+		if value.ResourceType == nil {
+			return nil
+		}
+		return value.ResourceType
+
+	case cadence.Event:
+		if value.EventType == nil {
+			return nil
+		}
+		return value.EventType
+
+	case cadence.Contract:
+		if value.ContractType == nil {
+			return nil
+		}
+		return value.ContractType
+
+	case cadence.Enum:
+		if value.EnumType == nil {
+			return nil
+		}
+		return value.EnumType
+
+	default:
+		return value.Type()
+	}
+}
+
+// compositeFields returns the field values of the given composite value
+//
+func compositeFields(value cadence.Value) ([]cadence.Value, bool) {
+	switch value := value.(type) {
+	case cadence.Struct:
+		return value.Fields, true
+	case cadence.Resource:
+		return value.Fields, true
+	case cadence.Event:
+		return value.Fields, true
+	case cadence.Contract:
+		return value.Fields, true
+	case cadence.Enum:
+		return value.Fields, true
+	default:
+		return nil, false
+	}
+}
+
+// definitionIndex returns the index of the type definition for the given composite or interface type.
+// If the type is not defined yet, it is added to the definitions
+//
+func (e *Encoder) definitionIndex(t cadence.Type) int {
+	id := t.ID()
+
+	for _, index := range e.definitionIndices[id] {
+		if definitionsEqual(e.definitions[index], t) {
+			return index
+		}
+	}
+
+	index := len(e.definitions)
+	e.definitions = append(e.definitions, t)
+	e.definitionIndices[id] = append(e.definitionIndices[id], index)
+
+	return index
+}
+
+// encodeDefinitionHeader encodes the header of a type definition as
+// [kind: uint, type-id: tstr]
+//
+func (e *Encoder) encodeDefinitionHeader(enc *cbor.StreamEncoder, t cadence.Type) error {
+	kind, ok := definitionKind(t)
+	if !ok {
+		return fmt.Errorf("unsupported type definition: %T", t)
+	}
+
+	err := enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeUint64(kind)
+	if err != nil {
+		return err
+	}
+
+	return enc.EncodeString(t.ID())
+}
+
+// encodeDefinitionMembers encodes the members of a type definition as
+// [
+//     fields: [* [identifier: tstr, type: inline-type]],
+//     initializers: [* [* parameter]],
+//     raw-type: inline-type / null,
+// ]
+//
+func (e *Encoder) encodeDefinitionMembers(enc *cbor.StreamEncoder, t cadence.Type) error {
+	fields, initializers, rawType := definitionMembers(t)
+
+	err := enc.EncodeArrayHead(3)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeArrayHead(uint64(len(fields)))
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		err = enc.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeString(field.Identifier)
+		if err != nil {
+			return err
+		}
+
+		err = e.encodeType(enc, field.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = enc.EncodeArrayHead(uint64(len(initializers)))
+	if err != nil {
+		return err
+	}
+
+	for _, parameters := range initializers {
+		err = e.encodeParameters(enc, parameters)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.encodeType(enc, rawType)
+}
+
+// encodeParameters encodes parameters as
+// [* [label: tstr, identifier: tstr, type: inline-type]]
+//
+func (e *Encoder) encodeParameters(enc *cbor.StreamEncoder, parameters []cadence.Parameter) error {
+	err := enc.EncodeArrayHead(uint64(len(parameters)))
+	if err != nil {
+		return err
+	}
+
+	for _, parameter := range parameters {
+		err = enc.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeString(parameter.Label)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeString(parameter.Identifier)
+		if err != nil {
+			return err
+		}
+
+		err = e.encodeType(enc, parameter.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeType encodes the given type as an inline type.
+// Composite and interface types are encoded as references to their type definition
+//
+func (e *Encoder) encodeType(enc *cbor.StreamEncoder, t cadence.Type) error {
+	switch t := t.(type) {
+	case nil:
+		return enc.EncodeNil()
+
+	case cadence.OptionalType:
+		err := enc.EncodeTagHead(CBORTagOptionalType)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.Type)
+
+	case cadence.VariableSizedArrayType:
+		err := enc.EncodeTagHead(CBORTagVariableSizedArrayType)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		err := enc.EncodeTagHead(CBORTagConstantSizedArrayType)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeUint64(uint64(t.Size))
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.ElementType)
+
+	case cadence.DictionaryType:
+		err := enc.EncodeTagHead(CBORTagDictionaryType)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+		err = e.encodeType(enc, t.KeyType)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.ElementType)
+
+	case cadence.ReferenceType:
+		err := enc.EncodeTagHead(CBORTagReferenceType)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeBool(t.Authorized)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.Type)
+
+	case cadence.RestrictedType:
+		err := enc.EncodeTagHead(CBORTagRestrictedType)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeString(t.ID())
+		if err != nil {
+			return err
+		}
+		err = e.encodeType(enc, t.Type)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(uint64(len(t.Restrictions)))
+		if err != nil {
+			return err
+		}
+		for _, restriction := range t.Restrictions {
+			err = e.encodeType(enc, restriction)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case cadence.CapabilityType:
+		err := enc.EncodeTagHead(CBORTagCapabilityType)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.BorrowType)
+
+	case cadence.FunctionType:
+		err := enc.EncodeTagHead(CBORTagFunctionType)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+		err = enc.EncodeString(t.ID())
+		if err != nil {
+			return err
+		}
+		err = e.encodeParameters(enc, t.Parameters)
+		if err != nil {
+			return err
+		}
+		return e.encodeType(enc, t.ReturnType)
+	}
+
+	if _, ok := definitionKind(t); ok {
+		err := enc.EncodeTagHead(CBORTagTypeRef)
+		if err != nil {
+			return err
+		}
+		return enc.EncodeUint64(uint64(e.definitionIndex(t)))
+	}
+
+	id, ok := simpleTypeIDs[t]
+	if !ok {
+		return fmt.Errorf("unsupported type: %T, %v", t, t)
+	}
+
+	err := enc.EncodeTagHead(CBORTagSimpleType)
+	if err != nil {
+		return err
+	}
+	return enc.EncodeUint64(id)
+}
+
+func typeMismatchError(value cadence.Value, staticType cadence.Type) error {
+	return fmt.Errorf(
+		"value does not conform to static type: %T, %v",
+		value,
+		staticType,
+	)
+}
+
+// encodeValue encodes the given value according to the given static type.
+//
+// If the static type is not sufficient to determine the type of the value,
+// the value is encoded together with its type
+//
+func (e *Encoder) encodeValue(enc *cbor.StreamEncoder, value cadence.Value, staticType cadence.Type) error {
+
+	if needsRuntimeType(staticType) {
+
+		// Links have no type, so they are tagged separately
+
+		if link, ok := value.(cadence.Link); ok {
+			return e.encodeLink(enc, link)
+		}
+
+		valueType := runtimeType(value)
+		if needsRuntimeType(valueType) {
+			return fmt.Errorf("unsupported value: %T, %v", value, value)
+		}
+
+		err := enc.EncodeTagHead(CBORTagTypeAndValue)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		err = e.encodeType(enc, valueType)
+		if err != nil {
+			return err
+		}
+
+		return e.encodeValue(enc, value, valueType)
+	}
+
+	switch staticType := staticType.(type) {
+	case cadence.OptionalType:
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		if optional.Value == nil {
+			return enc.EncodeNil()
+		}
+
+		// Nested optionals are wrapped,
+		// so non-nil values can be distinguished from nil
+
+		if _, ok := staticType.Type.(cadence.OptionalType); ok {
+			err := enc.EncodeArrayHead(1)
+			if err != nil {
+				return err
+			}
+		}
+
+		return e.encodeValue(enc, optional.Value, staticType.Type)
+
+	case cadence.VariableSizedArrayType:
+		array, ok := value.(cadence.Array)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodeArray(enc, array.Values, staticType.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		array, ok := value.(cadence.Array)
+		if !ok || uint(len(array.Values)) != staticType.Size {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodeArray(enc, array.Values, staticType.ElementType)
+
+	case cadence.DictionaryType:
+		dictionary, ok := value.(cadence.Dictionary)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodeDictionary(enc, dictionary, staticType)
+
+	case cadence.CapabilityType:
+		capability, ok := value.(cadence.Capability)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodeCapability(enc, capability)
+
+	case cadence.MetaType:
+		typeValue, ok := value.(cadence.TypeValue)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodeType(enc, typeValue.StaticType)
+
+	case cadence.PathType,
+		cadence.CapabilityPathType,
+		cadence.StoragePathType,
+		cadence.PublicPathType,
+		cadence.PrivatePathType:
+
+		path, ok := value.(cadence.Path)
+		if !ok {
+			return typeMismatchError(value, staticType)
+		}
+
+		return e.encodePath(enc, path)
+	}
+
+	if _, ok := definitionKind(staticType); ok {
+		return e.encodeComposite(enc, value, staticType)
+	}
+
+	if runtimeType(value) != staticType {
+		return typeMismatchError(value, staticType)
+	}
+
+	switch value := value.(type) {
+	case cadence.Void:
+		return enc.EncodeNil()
+	case cadence.Bool:
+		return enc.EncodeBool(bool(value))
+	case cadence.String:
+		return enc.EncodeString(string(value))
+	case cadence.Bytes:
+		return enc.EncodeBytes(value)
+	case cadence.Address:
+		return enc.EncodeBytes(value.Bytes())
+	case cadence.Int:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.Int8:
+		return enc.EncodeInt8(int8(value))
+	case cadence.Int16:
+		return enc.EncodeInt16(int16(value))
+	case cadence.Int32:
+		return enc.EncodeInt32(int32(value))
+	case cadence.Int64:
+		return enc.EncodeInt64(int64(value))
+	case cadence.Int128:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.Int256:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.UInt:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.UInt8:
+		return enc.EncodeUint8(uint8(value))
+	case cadence.UInt16:
+		return enc.EncodeUint16(uint16(value))
+	case cadence.UInt32:
+		return enc.EncodeUint32(uint32(value))
+	case cadence.UInt64:
+		return enc.EncodeUint64(uint64(value))
+	case cadence.UInt128:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.UInt256:
+		return enc.EncodeBigInt(value.Big())
+	case cadence.Word8:
+		return enc.EncodeUint8(uint8(value))
+	case cadence.Word16:
+		return enc.EncodeUint16(uint16(value))
+	case cadence.Word32:
+		return enc.EncodeUint32(uint32(value))
+	case cadence.Word64:
+		return enc.EncodeUint64(uint64(value))
+	case cadence.Fix64:
+		return enc.EncodeInt64(int64(value))
+	case cadence.UFix64:
+		return enc.EncodeUint64(uint64(value))
+	case cadence.Fix128:
+		return enc.EncodeBigInt(value.Value)
+	case cadence.UFix128:
+		return enc.EncodeBigInt(value.Value)
+	case cadence.Timestamp:
+		return enc.EncodeInt64(int64(value))
+	case cadence.Duration:
+		return enc.EncodeInt64(int64(value))
+	default:
+		return fmt.Errorf("unsupported value: %T, %v", value, value)
+	}
+}
+
+// encodeArray encodes the elements of an array as
+// [* value]
+//
+func (e *Encoder) encodeArray(enc *cbor.StreamEncoder, values []cadence.Value, elementType cadence.Type) error {
+	err := enc.EncodeArrayHead(uint64(len(values)))
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		err = e.encodeValue(enc, value, elementType)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeDictionary encodes the key-value pairs of a dictionary, in order, as
+// [* (key: value, value: value)]
+//
+func (e *Encoder) encodeDictionary(
+	enc *cbor.StreamEncoder,
+	dictionary cadence.Dictionary,
+	dictionaryType cadence.DictionaryType,
+) error {
+	err := enc.EncodeArrayHead(uint64(len(dictionary.Pairs)) * 2)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range dictionary.Pairs {
+		err = e.encodeValue(enc, pair.Key, dictionaryType.KeyType)
+		if err != nil {
+			return err
+		}
+
+		err = e.encodeValue(enc, pair.Value, dictionaryType.ElementType)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeComposite encodes the field values of a composite value as
+// [* value]
+//
+// The field names and field types are defined by the static type
+//
+func (e *Encoder) encodeComposite(enc *cbor.StreamEncoder, value cadence.Value, staticType cadence.Type) error {
+	fieldValues, ok := compositeFields(value)
+	if !ok {
+		return typeMismatchError(value, staticType)
+	}
+
+	valueType := runtimeType(value)
+	if valueType == nil || !definitionsEqual(valueType, staticType) {
+		return typeMismatchError(value, staticType)
+	}
+
+	staticFields, _, _ := definitionMembers(staticType)
+	fields := valueFields(staticFields)
+
+	if len(fields) != len(fieldValues) {
+		return fmt.Errorf(
+			"%s field count (%d) does not match declared type (%d)",
+			staticType.ID(),
+			len(fieldValues),
+			len(fields),
+		)
+	}
+
+	err := enc.EncodeArrayHead(uint64(len(fieldValues)))
+	if err != nil {
+		return err
+	}
+
+	for i, fieldValue := range fieldValues {
+		err = e.encodeValue(enc, fieldValue, fields[i].Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodePath encodes a path value as
+// [domain: uint, identifier: tstr]
+//
+func (e *Encoder) encodePath(enc *cbor.StreamEncoder, path cadence.Path) error {
+	domain := common.PathDomainFromIdentifier(path.Domain)
+	if domain == common.PathDomainUnknown {
+		return fmt.Errorf("invalid path domain: %s", path.Domain)
+	}
+
+	err := enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeUint64(uint64(domain))
+	if err != nil {
+		return err
+	}
+
+	return enc.EncodeString(path.Identifier)
+}
+
+// encodeCapability encodes a capability value as
+// [path, address: bstr, borrow-type: inline-type / null]
+//
+func (e *Encoder) encodeCapability(enc *cbor.StreamEncoder, capability cadence.Capability) error {
+	err := enc.EncodeArrayHead(3)
+	if err != nil {
+		return err
+	}
+
+	err = e.encodePath(enc, capability.Path)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeBytes(capability.Address.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return e.encodeType(enc, capability.BorrowType)
+}
+
+// encodeLink encodes a link value as
+// #6.CBORTagLinkValue([target-path, borrow-type: tstr])
+//
+func (e *Encoder) encodeLink(enc *cbor.StreamEncoder, link cadence.Link) error {
+	err := enc.EncodeTagHead(CBORTagLinkValue)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = e.encodePath(enc, link.TargetPath)
+	if err != nil {
+		return err
+	}
+
+	return enc.EncodeString(link.BorrowType)
+}
@@ -34,6 +34,10 @@ import (
 )
 
 // A Decoder decodes JSON-encoded representations of Cadence values.
+//
+// The decoder reads the JSON token stream incrementally:
+// arrays, dictionaries, optionals, and composites are decoded element by element,
+// so the JSON document is never buffered as a whole.
 type Decoder struct {
 	dec *json.Decoder
 }
@@ -54,25 +58,45 @@ func Decode(b []byte) (cadence.Value, error) {
 	return v, nil
 }
 
+// DecodeEvent decodes a JSON-encoded event payload, e.g. of a Flow event,
+// and converts the event into a Go value using the given event decoders.
+//
+// This function returns an error if the payload is not a valid JSON-encoded event,
+// or if the event cannot be converted, see cadence.EventDecoders.Decode.
+func DecodeEvent(payload []byte, decoders *cadence.EventDecoders) (interface{}, error) {
+	value, err := Decode(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoders.DecodeValue(value)
+}
+
 // NewDecoder initializes a Decoder that will decode JSON-encoded bytes from the
 // given io.Reader.
 func NewDecoder(r io.Reader) *Decoder {
 	return &Decoder{json.NewDecoder(r)}
 }
 
+// jsonStructureError is an error which occurred when reading the JSON token stream,
+// i.e. the input is not valid JSON
+type jsonStructureError struct {
+	err error
+}
+
+func (e jsonStructureError) Error() string {
+	return e.err.Error()
+}
+
 // Decode reads JSON-encoded bytes from the io.Reader and decodes them to a
 // Cadence value.
 //
+// Multiple values can be decoded from the same io.Reader
+// by calling Decode repeatedly.
+//
 // This function returns an error if the bytes represent JSON that is malformed
 // or does not conform to the JSON Cadence specification.
 func (d *Decoder) Decode() (value cadence.Value, err error) {
-	jsonMap := make(map[string]interface{})
-
-	err = d.dec.Decode(&jsonMap)
-	if err != nil {
-		return nil, fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", err)
-	}
-
 	// capture panics that occur during decoding
 	defer func() {
 		if r := recover(); r != nil {
@@ -81,41 +105,154 @@ func (d *Decoder) Decode() (value cadence.Value, err error) {
 				panic(r)
 			}
 
+			if structureErr, ok := panicErr.(jsonStructureError); ok {
+				err = fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", structureErr.err)
+				return
+			}
+
 			err = fmt.Errorf("failed to decode value: %w", panicErr)
 		}
 	}()
 
-	value = decodeJSON(jsonMap)
+	value = d.decodeValue()
 	return value, nil
 }
 
 const (
-	typeKey         = "type"
-	kindKey         = "kind"
-	valueKey        = "value"
-	keyKey          = "key"
-	nameKey         = "name"
-	fieldsKey       = "fields"
-	initializersKey = "initializers"
-	idKey           = "id"
-	targetPathKey   = "targetPath"
-	borrowTypeKey   = "borrowType"
-	domainKey       = "domain"
-	identifierKey   = "identifier"
-	staticTypeKey   = "staticType"
-	addressKey      = "address"
-	pathKey         = "path"
-	authorizedKey   = "authorized"
-	sizeKey         = "size"
-	typeIDKey       = "typeID"
-	restrictionsKey = "restrictions"
-	labelKey        = "label"
-	parametersKey   = "parameters"
-	returnKey       = "return"
+	typeKey          = "type"
+	kindKey          = "kind"
+	valueKey         = "value"
+	keyKey           = "key"
+	nameKey          = "name"
+	fieldsKey        = "fields"
+	initializersKey  = "initializers"
+	idKey            = "id"
+	targetPathKey    = "targetPath"
+	borrowTypeKey    = "borrowType"
+	domainKey        = "domain"
+	identifierKey    = "identifier"
+	staticTypeKey    = "staticType"
+	addressKey       = "address"
+	pathKey          = "path"
+	authorizedKey    = "authorized"
+	sizeKey          = "size"
+	typeIDKey        = "typeID"
+	restrictionsKey  = "restrictions"
+	labelKey         = "label"
+	parametersKey    = "parameters"
+	returnKey        = "return"
+	entitlementsKey  = "entitlements"
+	functionTypeKey  = "functionType"
+	indexedFieldsKey = "indexedFields"
 )
 
 var ErrInvalidJSONCadence = errors.New("invalid JSON Cadence structure")
 
+// decodeValue decodes a value object from the token stream
+func (d *Decoder) decodeValue() cadence.Value {
+	d.expectDelim('{')
+	return d.decodeValueObject()
+}
+
+// decodeValueObject decodes the remainder of a value object,
+// after its opening delimiter has been read.
+//
+// The value is decoded directly from the token stream if the type precedes the value,
+// which is always the case for values encoded by the Encoder.
+// Otherwise, the value must be buffered until the type is known.
+func (d *Decoder) decodeValueObject() cadence.Value {
+	var typeStr string
+	var hasType bool
+
+	var value cadence.Value
+	var rawValue json.RawMessage
+	var hasValue bool
+
+	for d.dec.More() {
+		switch d.stringToken() {
+		case typeKey:
+			if hasType {
+				panic(ErrInvalidJSONCadence)
+			}
+			typeStr = d.stringToken()
+			hasType = true
+
+		case valueKey:
+			if hasValue {
+				panic(ErrInvalidJSONCadence)
+			}
+			hasValue = true
+
+			if hasType {
+				value = d.decodeValueOfType(typeStr)
+			} else {
+				rawValue = d.decodeRawMessage()
+			}
+
+		default:
+			// object should only contain two keys: "type", "value"
+			panic(ErrInvalidJSONCadence)
+		}
+	}
+
+	d.expectDelim('}')
+
+	if !hasType {
+		panic(ErrInvalidJSONCadence)
+	}
+
+	// void is a special case, does not have "value" field
+	if typeStr == voidTypeStr {
+		if hasValue {
+			// TODO: improve error message
+			panic(ErrInvalidJSONCadence)
+		}
+
+		return cadence.NewVoid()
+	}
+
+	if !hasValue {
+		panic(ErrInvalidJSONCadence)
+	}
+
+	if rawValue != nil {
+		return NewDecoder(bytes.NewReader(rawValue)).decodeValueOfType(typeStr)
+	}
+
+	return value
+}
+
+// decodeValueOfType decodes the value of a value object with the given type.
+//
+// Values which contain other values are decoded from the token stream,
+// all other values are decoded as a whole
+func (d *Decoder) decodeValueOfType(typeStr string) cadence.Value {
+	switch typeStr {
+	case optionalTypeStr:
+		return d.decodeOptional()
+	case arrayTypeStr:
+		return d.decodeArray()
+	case dictionaryTypeStr:
+		return d.decodeDictionary()
+	case resourceTypeStr:
+		return d.decodeResource()
+	case structTypeStr:
+		return d.decodeStruct()
+	case eventTypeStr:
+		return d.decodeEvent()
+	case contractTypeStr:
+		return d.decodeContract()
+	case enumTypeStr:
+		return d.decodeEnum()
+	}
+
+	return decodeJSONValue(typeStr, d.decodeJSON())
+}
+
+// decodeJSON decodes a value object which was already decoded from JSON as a whole.
+//
+// Only values which do not contain other values are supported,
+// e.g. the target path of a link
 func decodeJSON(v interface{}) cadence.Value {
 	obj := toObject(v)
 
@@ -131,17 +268,21 @@ func decodeJSON(v interface{}) cadence.Value {
 		panic(ErrInvalidJSONCadence)
 	}
 
-	valueJSON := obj.Get(valueKey)
+	return decodeJSONValue(typeStr, obj.Get(valueKey))
+}
 
+func decodeJSONValue(typeStr string, valueJSON interface{}) cadence.Value {
 	switch typeStr {
-	case optionalTypeStr:
-		return decodeOptional(valueJSON)
 	case boolTypeStr:
 		return decodeBool(valueJSON)
 	case stringTypeStr:
 		return decodeString(valueJSON)
 	case addressTypeStr:
 		return decodeAddress(valueJSON)
+	case timestampTypeStr:
+		return decodeTimestamp(valueJSON)
+	case durationTypeStr:
+		return decodeDuration(valueJSON)
 	case intTypeStr:
 		return decodeInt(valueJSON)
 	case int8TypeStr:
@@ -182,18 +323,10 @@ func decodeJSON(v interface{}) cadence.Value {
 		return decodeFix64(valueJSON)
 	case ufix64TypeStr:
 		return decodeUFix64(valueJSON)
-	case arrayTypeStr:
-		return decodeArray(valueJSON)
-	case dictionaryTypeStr:
-		return decodeDictionary(valueJSON)
-	case resourceTypeStr:
-		return decodeResource(valueJSON)
-	case structTypeStr:
-		return decodeStruct(valueJSON)
-	case eventTypeStr:
-		return decodeEvent(valueJSON)
-	case contractTypeStr:
-		return decodeContract(valueJSON)
+	case fix128TypeStr:
+		return decodeFix128(valueJSON)
+	case ufix128TypeStr:
+		return decodeUFix128(valueJSON)
 	case linkTypeStr:
 		return decodeLink(valueJSON)
 	case pathTypeStr:
@@ -202,8 +335,8 @@ func decodeJSON(v interface{}) cadence.Value {
 		return decodeTypeValue(valueJSON)
 	case capabilityTypeStr:
 		return decodeCapability(valueJSON)
-	case enumTypeStr:
-		return decodeEnum(valueJSON)
+	case functionTypeStr:
+		return decodeFunction(valueJSON)
 	}
 
 	panic(ErrInvalidJSONCadence)
@@ -219,12 +352,16 @@ func decodeVoid(m map[string]interface{}) cadence.Void {
 	return cadence.NewVoid()
 }
 
-func decodeOptional(valueJSON interface{}) cadence.Optional {
-	if valueJSON == nil {
+func (d *Decoder) decodeOptional() cadence.Optional {
+	switch d.token() {
+	case nil:
 		return cadence.NewOptional(nil)
+	case json.Delim('{'):
+		return cadence.NewOptional(d.decodeValueObject())
 	}
 
-	return cadence.NewOptional(decodeJSON(valueJSON))
+	// TODO: improve error message
+	panic(ErrInvalidJSONCadence)
 }
 
 func decodeBool(valueJSON interface{}) cadence.Bool {
@@ -322,6 +459,30 @@ func decodeInt64(valueJSON interface{}) cadence.Int64 {
 	return cadence.NewInt64(i)
 }
 
+func decodeTimestamp(valueJSON interface{}) cadence.Timestamp {
+	v := toString(valueJSON)
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	return cadence.Timestamp(i)
+}
+
+func decodeDuration(valueJSON interface{}) cadence.Duration {
+	v := toString(valueJSON)
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	return cadence.Duration(i)
+}
+
 func decodeInt128(valueJSON interface{}) cadence.Int128 {
 	bigInt := decodeBigInt(valueJSON)
 	value, err := cadence.NewInt128FromBig(bigInt)
@@ -486,39 +647,78 @@ func decodeUFix64(valueJSON interface{}) cadence.UFix64 {
 	return v
 }
 
-func decodeValues(valueJSON interface{}) []cadence.Value {
-	v := toSlice(valueJSON)
+func decodeFix128(valueJSON interface{}) cadence.Fix128 {
+	v, err := cadence.NewFix128(toString(valueJSON))
+	if err != nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+	return v
+}
+
+func decodeUFix128(valueJSON interface{}) cadence.UFix128 {
+	v, err := cadence.NewUFix128(toString(valueJSON))
+	if err != nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+	return v
+}
+
+func (d *Decoder) decodeValues() []cadence.Value {
+	values := make([]cadence.Value, 0)
 
-	values := make([]cadence.Value, len(v))
+	d.expectDelim('[')
 
-	for i, val := range v {
-		values[i] = decodeJSON(val)
+	for d.dec.More() {
+		values = append(values, d.decodeValue())
 	}
 
+	d.expectDelim(']')
+
 	return values
 }
 
-func decodeArray(valueJSON interface{}) cadence.Array {
-	return cadence.NewArray(decodeValues(valueJSON))
+func (d *Decoder) decodeArray() cadence.Array {
+	return cadence.NewArray(d.decodeValues())
 }
 
-func decodeDictionary(valueJSON interface{}) cadence.Dictionary {
-	v := toSlice(valueJSON)
+func (d *Decoder) decodeDictionary() cadence.Dictionary {
+	pairs := make([]cadence.KeyValuePair, 0)
 
-	pairs := make([]cadence.KeyValuePair, len(v))
+	d.expectDelim('[')
 
-	for i, val := range v {
-		pairs[i] = decodeKeyValuePair(val)
+	for d.dec.More() {
+		pairs = append(pairs, d.decodeKeyValuePair())
 	}
 
+	d.expectDelim(']')
+
 	return cadence.NewDictionary(pairs)
 }
 
-func decodeKeyValuePair(valueJSON interface{}) cadence.KeyValuePair {
-	obj := toObject(valueJSON)
+func (d *Decoder) decodeKeyValuePair() cadence.KeyValuePair {
+	var key, value cadence.Value
+
+	d.expectDelim('{')
 
-	key := obj.GetValue(keyKey)
-	value := obj.GetValue(valueKey)
+	for d.dec.More() {
+		switch d.stringToken() {
+		case keyKey:
+			key = d.decodeValue()
+		case valueKey:
+			value = d.decodeValue()
+		default:
+			d.decodeRawMessage()
+		}
+	}
+
+	d.expectDelim('}')
+
+	if key == nil || value == nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
 
 	return cadence.KeyValuePair{
 		Key:   key,
@@ -533,10 +733,48 @@ type composite struct {
 	fieldTypes          []cadence.Field
 }
 
-func decodeComposite(valueJSON interface{}) composite {
-	obj := toObject(valueJSON)
+func (d *Decoder) decodeComposite() composite {
+	var typeID string
+	var hasTypeID bool
+
+	var hasFields bool
+	fieldValues := make([]cadence.Value, 0)
+	fieldTypes := make([]cadence.Field, 0)
+
+	d.expectDelim('{')
+
+	for d.dec.More() {
+		switch d.stringToken() {
+		case idKey:
+			typeID = d.stringToken()
+			hasTypeID = true
+
+		case fieldsKey:
+			hasFields = true
+
+			d.expectDelim('[')
+
+			for d.dec.More() {
+				value, fieldType := d.decodeCompositeField()
+
+				fieldValues = append(fieldValues, value)
+				fieldTypes = append(fieldTypes, fieldType)
+			}
+
+			d.expectDelim(']')
+
+		default:
+			d.decodeRawMessage()
+		}
+	}
+
+	d.expectDelim('}')
+
+	if !hasTypeID || !hasFields {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
 
-	typeID := obj.GetString(idKey)
 	location, qualifiedIdentifier, err := common.DecodeTypeID(typeID)
 
 	if err != nil ||
@@ -547,18 +785,6 @@ func decodeComposite(valueJSON interface{}) composite {
 		panic(fmt.Errorf("%s. invalid type ID: `%s`", ErrInvalidJSONCadence, typeID))
 	}
 
-	fields := obj.GetSlice(fieldsKey)
-
-	fieldValues := make([]cadence.Value, len(fields))
-	fieldTypes := make([]cadence.Field, len(fields))
-
-	for i, field := range fields {
-		value, fieldType := decodeCompositeField(field)
-
-		fieldValues[i] = value
-		fieldTypes[i] = fieldType
-	}
-
 	return composite{
 		location:            location,
 		qualifiedIdentifier: qualifiedIdentifier,
@@ -567,11 +793,31 @@ func decodeComposite(valueJSON interface{}) composite {
 	}
 }
 
-func decodeCompositeField(valueJSON interface{}) (cadence.Value, cadence.Field) {
-	obj := toObject(valueJSON)
+func (d *Decoder) decodeCompositeField() (cadence.Value, cadence.Field) {
+	var name string
+	var hasName bool
+	var value cadence.Value
+
+	d.expectDelim('{')
+
+	for d.dec.More() {
+		switch d.stringToken() {
+		case nameKey:
+			name = d.stringToken()
+			hasName = true
+		case valueKey:
+			value = d.decodeValue()
+		default:
+			d.decodeRawMessage()
+		}
+	}
 
-	name := obj.GetString(nameKey)
-	value := obj.GetValue(valueKey)
+	d.expectDelim('}')
+
+	if !hasName || value == nil {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
 
 	field := cadence.Field{
 		Identifier: name,
@@ -581,8 +827,8 @@ func decodeCompositeField(valueJSON interface{}) (cadence.Value, cadence.Field)
 	return value, field
 }
 
-func decodeStruct(valueJSON interface{}) cadence.Struct {
-	comp := decodeComposite(valueJSON)
+func (d *Decoder) decodeStruct() cadence.Struct {
+	comp := d.decodeComposite()
 
 	return cadence.NewStruct(comp.fieldValues).WithType(&cadence.StructType{
 		Location:            comp.location,
@@ -591,8 +837,8 @@ func decodeStruct(valueJSON interface{}) cadence.Struct {
 	})
 }
 
-func decodeResource(valueJSON interface{}) cadence.Resource {
-	comp := decodeComposite(valueJSON)
+func (d *Decoder) decodeResource() cadence.Resource {
+	comp := d.decodeComposite()
 
 	return cadence.NewResource(comp.fieldValues).WithType(&cadence.ResourceType{
 		Location:            comp.location,
@@ -601,8 +847,8 @@ func decodeResource(valueJSON interface{}) cadence.Resource {
 	})
 }
 
-func decodeEvent(valueJSON interface{}) cadence.Event {
-	comp := decodeComposite(valueJSON)
+func (d *Decoder) decodeEvent() cadence.Event {
+	comp := d.decodeComposite()
 
 	return cadence.NewEvent(comp.fieldValues).WithType(&cadence.EventType{
 		Location:            comp.location,
@@ -611,8 +857,8 @@ func decodeEvent(valueJSON interface{}) cadence.Event {
 	})
 }
 
-func decodeContract(valueJSON interface{}) cadence.Contract {
-	comp := decodeComposite(valueJSON)
+func (d *Decoder) decodeContract() cadence.Contract {
+	comp := d.decodeComposite()
 
 	return cadence.NewContract(comp.fieldValues).WithType(&cadence.ContractType{
 		Location:            comp.location,
@@ -621,8 +867,8 @@ func decodeContract(valueJSON interface{}) cadence.Contract {
 	})
 }
 
-func decodeEnum(valueJSON interface{}) cadence.Enum {
-	comp := decodeComposite(valueJSON)
+func (d *Decoder) decodeEnum() cadence.Enum {
+	comp := d.decodeComposite()
 
 	return cadence.NewEnum(comp.fieldValues).WithType(&cadence.EnumType{
 		Location:            comp.location,
@@ -654,46 +900,46 @@ func decodePath(valueJSON interface{}) cadence.Path {
 	}
 }
 
-func decodeParamType(valueJSON interface{}) cadence.Parameter {
+func decodeParamType(valueJSON interface{}, results typeDecodingResults) cadence.Parameter {
 	obj := toObject(valueJSON)
 	return cadence.Parameter{
 		Label:      toString(obj.Get(labelKey)),
 		Identifier: toString(obj.Get(idKey)),
-		Type:       decodeType(obj.Get(typeKey)),
+		Type:       decodeType(obj.Get(typeKey), results),
 	}
 }
 
-func decodeParamTypes(params []interface{}) []cadence.Parameter {
+func decodeParamTypes(params []interface{}, results typeDecodingResults) []cadence.Parameter {
 	parameters := make([]cadence.Parameter, 0, len(params))
 
 	for _, param := range params {
-		parameters = append(parameters, decodeParamType(param))
+		parameters = append(parameters, decodeParamType(param, results))
 	}
 
 	return parameters
 }
 
-func decodeFieldTypes(fs []interface{}) []cadence.Field {
+func decodeFieldTypes(fs []interface{}, results typeDecodingResults) []cadence.Field {
 	fields := make([]cadence.Field, 0, len(fs))
 
 	for _, field := range fs {
-		fields = append(fields, decodeFieldType(field))
+		fields = append(fields, decodeFieldType(field, results))
 	}
 
 	return fields
 }
 
-func decodeFieldType(valueJSON interface{}) cadence.Field {
+func decodeFieldType(valueJSON interface{}, results typeDecodingResults) cadence.Field {
 	obj := toObject(valueJSON)
 	return cadence.Field{
 		Identifier: toString(obj.Get(idKey)),
-		Type:       decodeType(obj.Get(typeKey)),
+		Type:       decodeType(obj.Get(typeKey), results),
 	}
 }
 
-func decodeFunctionType(returnValue, parametersValue, id interface{}) cadence.Type {
-	parameters := decodeParamTypes(toSlice(parametersValue))
-	returnType := decodeType(returnValue)
+func decodeFunctionType(returnValue, parametersValue, id interface{}, results typeDecodingResults) cadence.Type {
+	parameters := decodeParamTypes(toSlice(parametersValue), results)
+	returnType := decodeType(returnValue, results)
 
 	return cadence.FunctionType{
 		Parameters: parameters,
@@ -701,91 +947,132 @@ func decodeFunctionType(returnValue, parametersValue, id interface{}) cadence.Ty
 	}.WithID(toString(id))
 }
 
-func decodeNominalType(obj jsonObject, kind, typeID string, fs, initializers []interface{}) cadence.Type {
-	fields := decodeFieldTypes(fs)
-	inits := make([][]cadence.Parameter, 0, len(initializers))
-
-	for _, params := range initializers {
-		inits = append(inits, decodeParamTypes(toSlice(params)))
-	}
+func decodeNominalType(
+	obj jsonObject,
+	kind, typeID string,
+	fs, initializers []interface{},
+	results typeDecodingResults,
+) cadence.Type {
 
 	location, id, err := common.DecodeTypeID(typeID)
-	if err != nil {
-		panic(ErrInvalidJSONCadence)
+	if err != nil ||
+		location == nil && sema.NativeCompositeTypes[typeID] == nil {
+
+		panic(fmt.Errorf("%s. invalid type ID: `%s`", ErrInvalidJSONCadence, typeID))
 	}
 
+	// Create the type and record it before decoding its members,
+	// so the members may refer to the type, e.g. in the case of recursive types
+
+	var result cadence.Type
+
 	switch kind {
 	case "Struct":
-		return &cadence.StructType{
+		result = &cadence.StructType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "Resource":
-		return &cadence.ResourceType{
+		result = &cadence.ResourceType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "Event":
-		return &cadence.EventType{
+		result = &cadence.EventType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializer:         inits[0],
 		}
 	case "Contract":
-		return &cadence.ContractType{
+		result = &cadence.ContractType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "StructInterface":
-		return &cadence.StructInterfaceType{
+		result = &cadence.StructInterfaceType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "ResourceInterface":
-		return &cadence.ResourceInterfaceType{
+		result = &cadence.ResourceInterfaceType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "ContractInterface":
-		return &cadence.ContractInterfaceType{
+		result = &cadence.ContractInterfaceType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			Fields:              fields,
-			Initializers:        inits,
 		}
 	case "Enum":
-		return &cadence.EnumType{
+		result = &cadence.EnumType{
 			Location:            location,
 			QualifiedIdentifier: id,
-			RawType:             decodeType(obj.Get(typeKey)),
-			Fields:              fields,
-			Initializers:        inits,
 		}
+	default:
+		panic(ErrInvalidJSONCadence)
 	}
 
-	panic(ErrInvalidJSONCadence)
+	results[typeID] = result
+
+	fields := decodeFieldTypes(fs, results)
+	inits := make([][]cadence.Parameter, 0, len(initializers))
+
+	for _, params := range initializers {
+		inits = append(inits, decodeParamTypes(toSlice(params), results))
+	}
+
+	switch result := result.(type) {
+	case *cadence.StructType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.ResourceType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.EventType:
+		result.Fields = fields
+		// events have exactly one initializer
+		if len(inits) != 1 {
+			panic(ErrInvalidJSONCadence)
+		}
+		result.Initializer = inits[0]
+		// indexed fields are optional
+		if indexedFieldsValue, ok := obj[indexedFieldsKey]; ok {
+			indexedFields := toSlice(indexedFieldsValue)
+			result.IndexedFields = make([]string, 0, len(indexedFields))
+			for _, indexedField := range indexedFields {
+				result.IndexedFields = append(result.IndexedFields, toString(indexedField))
+			}
+		}
+	case *cadence.ContractType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.StructInterfaceType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.ResourceInterfaceType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.ContractInterfaceType:
+		result.Fields = fields
+		result.Initializers = inits
+	case *cadence.EnumType:
+		result.RawType = decodeType(obj.Get(typeKey), results)
+		result.Fields = fields
+		result.Initializers = inits
+	}
+
+	return result
 }
 
 func decodeRestrictedType(
 	typeValue interface{},
 	restrictionsValue []interface{},
 	typeIDValue string,
+	results typeDecodingResults,
 ) cadence.Type {
-	typ := decodeType(typeValue)
+	typ := decodeType(typeValue, results)
 	restrictions := make([]cadence.Type, 0, len(restrictionsValue))
 	for _, restriction := range restrictionsValue {
-		restrictions = append(restrictions, decodeType(restriction))
+		restrictions = append(restrictions, decodeType(restriction, results))
 	}
 
 	return cadence.RestrictedType{
@@ -794,10 +1081,26 @@ func decodeRestrictedType(
 	}.WithID(typeIDValue)
 }
 
-func decodeType(valueJSON interface{}) cadence.Type {
+// typeDecodingResults records the composite and interface types
+// which were already decoded as part of the same type, by type ID.
+//
+// Only the first occurrence of such a type is encoded in full,
+// all further occurrences only refer to the type by its ID.
+type typeDecodingResults map[string]cadence.Type
+
+func decodeType(valueJSON interface{}, results typeDecodingResults) cadence.Type {
 	if valueJSON == "" {
 		return nil
 	}
+
+	if typeID, ok := valueJSON.(string); ok {
+		result, ok := results[typeID]
+		if !ok {
+			panic(fmt.Errorf("%s. unknown type ID: `%s`", ErrInvalidJSONCadence, typeID))
+		}
+		return result
+	}
+
 	obj := toObject(valueJSON)
 	kindValue := toString(obj.Get(kindKey))
 
@@ -806,40 +1109,49 @@ func decodeType(valueJSON interface{}) cadence.Type {
 		returnValue := obj.Get(returnKey)
 		parametersValue := obj.Get(parametersKey)
 		idValue := obj.Get(typeIDKey)
-		return decodeFunctionType(returnValue, parametersValue, idValue)
+		return decodeFunctionType(returnValue, parametersValue, idValue, results)
 	case "Restriction":
 		restrictionsValue := obj.Get(restrictionsKey)
 		typeIDValue := toString(obj.Get(typeIDKey))
 		typeValue := obj.Get(typeKey)
-		return decodeRestrictedType(typeValue, toSlice(restrictionsValue), typeIDValue)
+		return decodeRestrictedType(typeValue, toSlice(restrictionsValue), typeIDValue, results)
 	case "Optional":
 		return cadence.OptionalType{
-			Type: decodeType(obj.Get(typeKey)),
+			Type: decodeType(obj.Get(typeKey), results),
 		}
 	case "VariableSizedArray":
 		return cadence.VariableSizedArrayType{
-			ElementType: decodeType(obj.Get(typeKey)),
+			ElementType: decodeType(obj.Get(typeKey), results),
 		}
 	case "Capability":
 		return cadence.CapabilityType{
-			BorrowType: decodeType(obj.Get(typeKey)),
+			BorrowType: decodeType(obj.Get(typeKey), results),
 		}
 	case "Dictionary":
 		return cadence.DictionaryType{
-			KeyType:     decodeType(obj.Get(keyKey)),
-			ElementType: decodeType(obj.Get(valueKey)),
+			KeyType:     decodeType(obj.Get(keyKey), results),
+			ElementType: decodeType(obj.Get(valueKey), results),
 		}
 	case "ConstantSizedArray":
 		size := toUInt(obj.Get(sizeKey))
 		return cadence.ConstantSizedArrayType{
-			ElementType: decodeType(obj.Get(typeKey)),
+			ElementType: decodeType(obj.Get(typeKey), results),
 			Size:        size,
 		}
 	case "Reference":
 		auth := toBool(obj.Get(authorizedKey))
+		var entitlements []string
+		if entitlementsValue, ok := obj[entitlementsKey]; ok {
+			entitlementValues := toSlice(entitlementsValue)
+			entitlements = make([]string, 0, len(entitlementValues))
+			for _, entitlementValue := range entitlementValues {
+				entitlements = append(entitlements, toString(entitlementValue))
+			}
+		}
 		return cadence.ReferenceType{
-			Type:       decodeType(obj.Get(typeKey)),
-			Authorized: auth,
+			Type:         decodeType(obj.Get(typeKey), results),
+			Authorized:   auth,
+			Entitlements: entitlements,
 		}
 	case "Any":
 		return cadence.AnyType{}
@@ -915,6 +1227,10 @@ func decodeType(valueJSON interface{}) cadence.Type {
 		return cadence.Fix64Type{}
 	case "UFix64":
 		return cadence.UFix64Type{}
+	case "Fix128":
+		return cadence.Fix128Type{}
+	case "UFix128":
+		return cadence.UFix128Type{}
 	case "Path":
 		return cadence.PathType{}
 	case "CapabilityPath":
@@ -941,11 +1257,17 @@ func decodeType(valueJSON interface{}) cadence.Type {
 		return cadence.DeployedContractType{}
 	case "AccountKey":
 		return cadence.AccountKeyType{}
+	case "Block":
+		return cadence.BlockType{}
+	case "Timestamp":
+		return cadence.TimestampType{}
+	case "Duration":
+		return cadence.DurationType{}
 	default:
 		fieldsValue := obj.Get(fieldsKey)
 		typeIDValue := toString(obj.Get(typeIDKey))
 		initValue := obj.Get(initializersKey)
-		return decodeNominalType(obj, kindValue, typeIDValue, toSlice(fieldsValue), toSlice(initValue))
+		return decodeNominalType(obj, kindValue, typeIDValue, toSlice(fieldsValue), toSlice(initValue), results)
 	}
 }
 
@@ -953,7 +1275,7 @@ func decodeTypeValue(valueJSON interface{}) cadence.TypeValue {
 	obj := toObject(valueJSON)
 
 	return cadence.TypeValue{
-		StaticType: decodeType(obj.Get(staticTypeKey)),
+		StaticType: decodeType(obj.Get(staticTypeKey), typeDecodingResults{}),
 	}
 }
 
@@ -969,8 +1291,66 @@ func decodeCapability(valueJSON interface{}) cadence.Capability {
 	return cadence.Capability{
 		Path:       path,
 		Address:    decodeAddress(obj.Get(addressKey)),
-		BorrowType: decodeType(obj.Get(borrowTypeKey)),
+		BorrowType: decodeType(obj.Get(borrowTypeKey), typeDecodingResults{}),
+	}
+}
+
+func decodeFunction(valueJSON interface{}) cadence.Function {
+	obj := toObject(valueJSON)
+
+	functionType, ok := decodeType(obj.Get(functionTypeKey), typeDecodingResults{}).(cadence.FunctionType)
+	if !ok {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	return cadence.NewFunction(functionType)
+}
+
+// JSON token stream helpers
+
+func (d *Decoder) token() json.Token {
+	token, err := d.dec.Token()
+	if err != nil {
+		panic(jsonStructureError{err})
+	}
+
+	return token
+}
+
+func (d *Decoder) expectDelim(delim json.Delim) {
+	if d.token() != delim {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+}
+
+func (d *Decoder) stringToken() string {
+	return toString(d.token())
+}
+
+// decodeJSON decodes the next JSON value as a whole
+func (d *Decoder) decodeJSON() interface{} {
+	var v interface{}
+
+	err := d.dec.Decode(&v)
+	if err != nil {
+		panic(jsonStructureError{err})
+	}
+
+	return v
+}
+
+// decodeRawMessage buffers the next JSON value, without decoding it
+func (d *Decoder) decodeRawMessage() json.RawMessage {
+	var message json.RawMessage
+
+	err := d.dec.Decode(&message)
+	if err != nil {
+		panic(jsonStructureError{err})
 	}
+
+	return message
 }
 
 // JSON types
@@ -1002,11 +1382,6 @@ func (obj jsonObject) GetSlice(key string) []interface{} {
 	return toSlice(v)
 }
 
-func (obj jsonObject) GetValue(key string) cadence.Value {
-	v := obj.Get(key)
-	return decodeJSON(v)
-}
-
 // JSON conversion helpers
 
 func toBool(valueJSON interface{}) bool {
@@ -19,6 +19,7 @@
 package json
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -33,8 +34,13 @@ import (
 )
 
 // An Encoder converts Cadence values into JSON-encoded bytes.
+//
+// The encoder writes values incrementally:
+// arrays, dictionaries, optionals, and composites are encoded element by element,
+// so the JSON document is never constructed as a whole.
 type Encoder struct {
-	enc *json.Encoder
+	w   io.Writer
+	buf *bufio.Writer
 }
 
 // Encode returns the JSON-encoded representation of the given value.
@@ -62,19 +68,55 @@ func MustEncode(value cadence.Value) []byte {
 	return b
 }
 
+// EncodeArguments returns the JSON-encoded representations of the arguments
+// added to the given builder, e.g. the arguments of a transaction.
+func EncodeArguments(arguments *cadence.ArgumentsBuilder) ([][]byte, error) {
+	return arguments.Encode(Encode)
+}
+
+// EncodeType returns the JSON-encoded representation of the given type,
+// in the same format as the static type of a type value.
+//
+// This function returns an error if the given type is not supported by this encoder.
+func EncodeType(t cadence.Type) (result []byte, err error) {
+	// capture panics that occur during encoding
+	defer func() {
+		if r := recover(); r != nil {
+			// don't recover Go errors
+			goErr, ok := r.(goRuntime.Error)
+			if ok {
+				panic(goErr)
+			}
+
+			panicErr, isError := r.(error)
+			if !isError {
+				panic(r)
+			}
+
+			err = fmt.Errorf("failed to encode type: %w", panicErr)
+		}
+	}()
+
+	return json.Marshal(prepareType(t, typePreparationResults{}))
+}
+
 // NewEncoder initializes an Encoder that will write JSON-encoded bytes to the
 // given io.Writer.
 func NewEncoder(w io.Writer) *Encoder {
-	return &Encoder{enc: json.NewEncoder(w)}
+	return &Encoder{
+		w:   w,
+		buf: bufio.NewWriter(w),
+	}
 }
 
 // Encode writes the JSON-encoded representation of the given value to this
-// encoder's io.Writer.
+// encoder's io.Writer, followed by a newline character.
 //
 // This function returns an error if the given value's type is not supported
-// by this encoder.
+// by this encoder. As the value is written incrementally, the io.Writer
+// may have already received a part of the representation when an error occurs.
 func (e *Encoder) Encode(value cadence.Value) (err error) {
-	// capture panics that occur during struct preparation
+	// capture panics that occur during encoding
 	defer func() {
 		if r := recover(); r != nil {
 			// don't recover Go errors
@@ -88,13 +130,136 @@ func (e *Encoder) Encode(value cadence.Value) (err error) {
 				panic(r)
 			}
 
+			// discard the buffered part of the representation
+			e.buf.Reset(e.w)
+
 			err = fmt.Errorf("failed to encode value: %w", panicErr)
 		}
 	}()
 
-	preparedValue := Prepare(value)
+	e.encodeValue(value)
+	e.write([]byte{'\n'})
+
+	return e.buf.Flush()
+}
+
+// encodeValue writes the JSON representation of the given value.
+//
+// Values which contain other values are written incrementally,
+// all other values are prepared and marshalled as a whole
+func (e *Encoder) encodeValue(value cadence.Value) {
+	switch value := value.(type) {
+	case cadence.Optional:
+		if value.Value == nil {
+			e.marshal(prepareOptional(value))
+			return
+		}
+
+		e.writeValueObjectStart(optionalTypeStr)
+		e.encodeValue(value.Value)
+		e.writeString("}")
 
-	return e.enc.Encode(&preparedValue)
+	case cadence.Array:
+		e.writeValueObjectStart(arrayTypeStr)
+		e.writeString("[")
+
+		for i, element := range value.Values {
+			if i > 0 {
+				e.writeString(",")
+			}
+			e.encodeValue(element)
+		}
+
+		e.writeString("]}")
+
+	case cadence.Dictionary:
+		e.writeValueObjectStart(dictionaryTypeStr)
+		e.writeString("[")
+
+		for i, pair := range value.Pairs {
+			if i > 0 {
+				e.writeString(",")
+			}
+			e.writeString(`{"key":`)
+			e.encodeValue(pair.Key)
+			e.writeString(`,"value":`)
+			e.encodeValue(pair.Value)
+			e.writeString("}")
+		}
+
+		e.writeString("]}")
+
+	case cadence.Struct:
+		e.encodeComposite(structTypeStr, value.StructType.ID(), value.StructType.Fields, value.Fields)
+
+	case cadence.Resource:
+		e.encodeComposite(resourceTypeStr, value.ResourceType.ID(), value.ResourceType.Fields, value.Fields)
+
+	case cadence.Event:
+		e.encodeComposite(eventTypeStr, value.EventType.ID(), value.EventType.Fields, value.Fields)
+
+	case cadence.Contract:
+		e.encodeComposite(contractTypeStr, value.ContractType.ID(), value.ContractType.Fields, value.Fields)
+
+	case cadence.Enum:
+		e.encodeComposite(enumTypeStr, value.EnumType.ID(), value.EnumType.Fields, value.Fields)
+
+	default:
+		e.marshal(Prepare(value))
+	}
+}
+
+func (e *Encoder) encodeComposite(kind, id string, fieldTypes []cadence.Field, fields []cadence.Value) {
+	nonFunctionFieldTypes := compositeFieldTypes(kind, fieldTypes, fields)
+
+	e.writeValueObjectStart(kind)
+	e.writeString(`{"id":`)
+	e.marshal(id)
+	e.writeString(`,"fields":[`)
+
+	for i, value := range fields {
+		if i > 0 {
+			e.writeString(",")
+		}
+		e.writeString(`{"name":`)
+		e.marshal(nonFunctionFieldTypes[i].Identifier)
+		e.writeString(`,"value":`)
+		e.encodeValue(value)
+		e.writeString("}")
+	}
+
+	e.writeString("]}}")
+}
+
+// writeValueObjectStart writes the start of a value object with the given type,
+// up to its value
+func (e *Encoder) writeValueObjectStart(typeStr string) {
+	e.writeString(`{"type":`)
+	e.marshal(typeStr)
+	e.writeString(`,"value":`)
+}
+
+func (e *Encoder) marshal(v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	e.write(b)
+}
+
+func (e *Encoder) writeString(s string) {
+	_, err := e.buf.WriteString(s)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (e *Encoder) write(b []byte) {
+	_, err := e.buf.Write(b)
+	if err != nil {
+		panic(err)
+	}
 }
 
 // JSON struct definitions
@@ -141,11 +306,12 @@ type jsonFieldType struct {
 }
 
 type jsonNominalType struct {
-	Kind         string                `json:"kind"`
-	TypeID       string                `json:"typeID"`
-	Fields       []jsonFieldType       `json:"fields"`
-	Initializers [][]jsonParameterType `json:"initializers"`
-	Type         jsonValue             `json:"type"`
+	Kind          string                `json:"kind"`
+	TypeID        string                `json:"typeID"`
+	Fields        []jsonFieldType       `json:"fields"`
+	Initializers  [][]jsonParameterType `json:"initializers"`
+	Type          jsonValue             `json:"type"`
+	IndexedFields []string              `json:"indexedFields,omitempty"`
 }
 
 type jsonSimpleType struct {
@@ -170,9 +336,10 @@ type jsonDictionaryType struct {
 }
 
 type jsonReferenceType struct {
-	Kind       string    `json:"kind"`
-	Type       jsonValue `json:"type"`
-	Authorized bool      `json:"authorized"`
+	Kind         string    `json:"kind"`
+	Type         jsonValue `json:"type"`
+	Authorized   bool      `json:"authorized"`
+	Entitlements []string  `json:"entitlements,omitempty"`
 }
 
 type jsonRestrictedType struct {
@@ -205,6 +372,10 @@ type jsonCapabilityValue struct {
 	BorrowType jsonValue `json:"borrowType"`
 }
 
+type jsonFunctionValue struct {
+	FunctionType jsonValue `json:"functionType"`
+}
+
 const (
 	voidTypeStr       = "Void"
 	optionalTypeStr   = "Optional"
@@ -231,6 +402,10 @@ const (
 	word64TypeStr     = "Word64"
 	fix64TypeStr      = "Fix64"
 	ufix64TypeStr     = "UFix64"
+	fix128TypeStr     = "Fix128"
+	ufix128TypeStr    = "UFix128"
+	timestampTypeStr  = "Timestamp"
+	durationTypeStr   = "Duration"
 	arrayTypeStr      = "Array"
 	dictionaryTypeStr = "Dictionary"
 	structTypeStr     = "Struct"
@@ -242,6 +417,7 @@ const (
 	typeTypeStr       = "Type"
 	capabilityTypeStr = "Capability"
 	enumTypeStr       = "Enum"
+	functionTypeStr   = "Function"
 )
 
 // prepare traverses the object graph of the provided value and constructs
@@ -258,6 +434,10 @@ func Prepare(v cadence.Value) jsonValue {
 		return prepareString(x)
 	case cadence.Address:
 		return prepareAddress(x)
+	case cadence.Timestamp:
+		return prepareTimestamp(x)
+	case cadence.Duration:
+		return prepareDuration(x)
 	case cadence.Int:
 		return prepareInt(x)
 	case cadence.Int8:
@@ -298,6 +478,10 @@ func Prepare(v cadence.Value) jsonValue {
 		return prepareFix64(x)
 	case cadence.UFix64:
 		return prepareUFix64(x)
+	case cadence.Fix128:
+		return prepareFix128(x)
+	case cadence.UFix128:
+		return prepareUFix128(x)
 	case cadence.Array:
 		return prepareArray(x)
 	case cadence.Dictionary:
@@ -320,6 +504,8 @@ func Prepare(v cadence.Value) jsonValue {
 		return prepareCapability(x)
 	case cadence.Enum:
 		return prepareEnum(x)
+	case cadence.Function:
+		return prepareFunction(x)
 	default:
 		panic(fmt.Errorf("unsupported value: %T, %v", v, v))
 	}
@@ -363,6 +549,20 @@ func prepareAddress(v cadence.Address) jsonValue {
 	}
 }
 
+func prepareTimestamp(v cadence.Timestamp) jsonValue {
+	return jsonValueObject{
+		Type:  timestampTypeStr,
+		Value: encodeInt(int64(v)),
+	}
+}
+
+func prepareDuration(v cadence.Duration) jsonValue {
+	return jsonValueObject{
+		Type:  durationTypeStr,
+		Value: encodeInt(int64(v)),
+	}
+}
+
 func prepareInt(v cadence.Int) jsonValue {
 	return jsonValueObject{
 		Type:  intTypeStr,
@@ -503,6 +703,20 @@ func prepareUFix64(v cadence.UFix64) jsonValue {
 	}
 }
 
+func prepareFix128(v cadence.Fix128) jsonValue {
+	return jsonValueObject{
+		Type:  fix128TypeStr,
+		Value: v.String(),
+	}
+}
+
+func prepareUFix128(v cadence.UFix128) jsonValue {
+	return jsonValueObject{
+		Type:  ufix128TypeStr,
+		Value: v.String(),
+	}
+}
+
 func prepareArray(v cadence.Array) jsonValue {
 	values := make([]jsonValue, len(v.Values))
 
@@ -552,7 +766,9 @@ func prepareEnum(v cadence.Enum) jsonValue {
 	return prepareComposite(enumTypeStr, v.EnumType.ID(), v.EnumType.Fields, v.Fields)
 }
 
-func prepareComposite(kind, id string, fieldTypes []cadence.Field, fields []cadence.Value) jsonValue {
+// compositeFieldTypes returns the types of the given composite field values,
+// i.e. the field types without the function fields
+func compositeFieldTypes(kind string, fieldTypes []cadence.Field, fields []cadence.Value) []cadence.Field {
 	nonFunctionFieldTypes := make([]cadence.Field, 0)
 
 	for _, field := range fieldTypes {
@@ -570,6 +786,12 @@ func prepareComposite(kind, id string, fieldTypes []cadence.Field, fields []cade
 		))
 	}
 
+	return nonFunctionFieldTypes
+}
+
+func prepareComposite(kind, id string, fieldTypes []cadence.Field, fields []cadence.Value) jsonValue {
+	nonFunctionFieldTypes := compositeFieldTypes(kind, fieldTypes, fields)
+
 	compositeFields := make([]jsonCompositeField, len(fields))
 
 	for i, value := range fields {
@@ -610,46 +832,71 @@ func preparePath(x cadence.Path) jsonValue {
 	}
 }
 
-func prepareParameterType(parameterType cadence.Parameter) jsonParameterType {
+func prepareParameterType(parameterType cadence.Parameter, results typePreparationResults) jsonParameterType {
 	return jsonParameterType{
 		Label: parameterType.Label,
 		Id:    parameterType.Identifier,
-		Type:  prepareType(parameterType.Type),
+		Type:  prepareType(parameterType.Type, results),
 	}
 }
 
-func prepareFieldType(fieldType cadence.Field) jsonFieldType {
+func prepareFieldType(fieldType cadence.Field, results typePreparationResults) jsonFieldType {
 	return jsonFieldType{
 		Id:   fieldType.Identifier,
-		Type: prepareType(fieldType.Type),
+		Type: prepareType(fieldType.Type, results),
 	}
 }
 
-func prepareFields(fieldTypes []cadence.Field) []jsonFieldType {
+func prepareFields(fieldTypes []cadence.Field, results typePreparationResults) []jsonFieldType {
 	fields := make([]jsonFieldType, 0)
 	for _, field := range fieldTypes {
-		fields = append(fields, prepareFieldType(field))
+		fields = append(fields, prepareFieldType(field, results))
 	}
 	return fields
 }
 
-func prepareParameters(parameterTypes []cadence.Parameter) []jsonParameterType {
+func prepareParameters(parameterTypes []cadence.Parameter, results typePreparationResults) []jsonParameterType {
 	parameters := make([]jsonParameterType, 0)
 	for _, param := range parameterTypes {
-		parameters = append(parameters, prepareParameterType(param))
+		parameters = append(parameters, prepareParameterType(param, results))
 	}
 	return parameters
 }
 
-func prepareInitializers(initializerTypes [][]cadence.Parameter) [][]jsonParameterType {
+func prepareInitializers(initializerTypes [][]cadence.Parameter, results typePreparationResults) [][]jsonParameterType {
 	initializers := make([][]jsonParameterType, 0)
 	for _, params := range initializerTypes {
-		initializers = append(initializers, prepareParameters(params))
+		initializers = append(initializers, prepareParameters(params, results))
 	}
 	return initializers
 }
 
-func prepareType(typ cadence.Type) jsonValue {
+// typePreparationResults records the composite and interface types
+// which were already prepared as part of the same type.
+//
+// Only the first occurrence of such a type is prepared in full,
+// all further occurrences only refer to the type by its ID.
+// This also allows preparing recursive types.
+type typePreparationResults map[cadence.Type]struct{}
+
+func prepareType(typ cadence.Type, results typePreparationResults) jsonValue {
+
+	switch typ.(type) {
+	case *cadence.StructType,
+		*cadence.ResourceType,
+		*cadence.EventType,
+		*cadence.ContractType,
+		*cadence.StructInterfaceType,
+		*cadence.ResourceInterfaceType,
+		*cadence.ContractInterfaceType,
+		*cadence.EnumType:
+
+		if _, ok := results[typ]; ok {
+			return typ.ID()
+		}
+		results[typ] = struct{}{}
+	}
+
 	switch typ := typ.(type) {
 	case cadence.AnyType,
 		cadence.AnyStructType,
@@ -661,6 +908,7 @@ func prepareType(typ cadence.Type) jsonValue {
 		cadence.StringType,
 		cadence.CharacterType,
 		cadence.BytesType,
+		cadence.AddressType,
 		cadence.NumberType,
 		cadence.SignedNumberType,
 		cadence.IntegerType,
@@ -687,7 +935,11 @@ func prepareType(typ cadence.Type) jsonValue {
 		cadence.Word64Type,
 		cadence.Fix64Type,
 		cadence.UFix64Type,
+		cadence.Fix128Type,
+		cadence.UFix128Type,
 		cadence.BlockType,
+		cadence.TimestampType,
+		cadence.DurationType,
 		cadence.PathType,
 		cadence.CapabilityPathType,
 		cadence.StoragePathType,
@@ -707,117 +959,122 @@ func prepareType(typ cadence.Type) jsonValue {
 	case cadence.OptionalType:
 		return jsonUnaryType{
 			Kind: "Optional",
-			Type: prepareType(typ.Type),
+			Type: prepareType(typ.Type, results),
 		}
 	case cadence.VariableSizedArrayType:
 		return jsonUnaryType{
 			Kind: "VariableSizedArray",
-			Type: prepareType(typ.ElementType),
+			Type: prepareType(typ.ElementType, results),
 		}
 	case cadence.ConstantSizedArrayType:
 		return jsonConstantSizedArrayType{
 			Kind: "ConstantSizedArray",
-			Type: prepareType(typ.ElementType),
+			Type: prepareType(typ.ElementType, results),
 			Size: typ.Size,
 		}
 	case cadence.DictionaryType:
 		return jsonDictionaryType{
 			Kind:      "Dictionary",
-			KeyType:   prepareType(typ.KeyType),
-			ValueType: prepareType(typ.ElementType),
+			KeyType:   prepareType(typ.KeyType, results),
+			ValueType: prepareType(typ.ElementType, results),
 		}
 	case *cadence.StructType:
 		return jsonNominalType{
 			Kind:         "Struct",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ResourceType:
 		return jsonNominalType{
 			Kind:         "Resource",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.EventType:
 		return jsonNominalType{
-			Kind:         "Event",
-			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: [][]jsonParameterType{prepareParameters(typ.Initializer)},
+			Kind:          "Event",
+			Type:          "",
+			TypeID:        typ.ID(),
+			Fields:        prepareFields(typ.Fields, results),
+			Initializers:  [][]jsonParameterType{prepareParameters(typ.Initializer, results)},
+			IndexedFields: typ.IndexedFields,
 		}
 	case *cadence.ContractType:
 		return jsonNominalType{
 			Kind:         "Contract",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.StructInterfaceType:
 		return jsonNominalType{
 			Kind:         "StructInterface",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ResourceInterfaceType:
 		return jsonNominalType{
 			Kind:         "ResourceInterface",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ContractInterfaceType:
 		return jsonNominalType{
 			Kind:         "ContractInterface",
 			Type:         "",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
 		}
 	case cadence.FunctionType:
+		// NOTE: prepare the parameters before the return type,
+		// in the same order as they are encoded and decoded
+		parameters := prepareParameters(typ.Parameters, results)
 		return jsonFunctionType{
 			Kind:       "Function",
 			TypeID:     typ.ID(),
-			Return:     prepareType(typ.ReturnType),
-			Parameters: prepareParameters(typ.Parameters),
+			Return:     prepareType(typ.ReturnType, results),
+			Parameters: parameters,
 		}
 	case cadence.ReferenceType:
 		return jsonReferenceType{
-			Kind:       "Reference",
-			Authorized: typ.Authorized,
-			Type:       prepareType(typ.Type),
+			Kind:         "Reference",
+			Authorized:   typ.Authorized,
+			Entitlements: typ.Entitlements,
+			Type:         prepareType(typ.Type, results),
 		}
 	case cadence.RestrictedType:
 		restrictions := make([]jsonValue, 0)
 		for _, restriction := range typ.Restrictions {
-			restrictions = append(restrictions, prepareType(restriction))
+			restrictions = append(restrictions, prepareType(restriction, results))
 		}
 		return jsonRestrictedType{
 			Kind:         "Restriction",
 			TypeID:       typ.ID(),
-			Type:         prepareType(typ.Type),
+			Type:         prepareType(typ.Type, results),
 			Restrictions: restrictions,
 		}
 	case cadence.CapabilityType:
 		return jsonUnaryType{
 			Kind: "Capability",
-			Type: prepareType(typ.BorrowType),
+			Type: prepareType(typ.BorrowType, results),
 		}
 	case *cadence.EnumType:
 		return jsonNominalType{
 			Kind:         "Enum",
-			TypeID:       string(typ.Location.TypeID(typ.QualifiedIdentifier)),
-			Fields:       prepareFields(typ.Fields),
-			Initializers: prepareInitializers(typ.Initializers),
-			Type:         prepareType(typ.RawType),
+			TypeID:       typ.ID(),
+			Fields:       prepareFields(typ.Fields, results),
+			Initializers: prepareInitializers(typ.Initializers, results),
+			Type:         prepareType(typ.RawType, results),
 		}
 	case nil:
 		return ""
@@ -830,7 +1087,7 @@ func prepareTypeValue(typeValue cadence.TypeValue) jsonValue {
 	return jsonValueObject{
 		Type: typeTypeStr,
 		Value: jsonTypeValue{
-			StaticType: prepareType(typeValue.StaticType),
+			StaticType: prepareType(typeValue.StaticType, typePreparationResults{}),
 		},
 	}
 }
@@ -841,7 +1098,16 @@ func prepareCapability(capability cadence.Capability) jsonValue {
 		Value: jsonCapabilityValue{
 			Path:       preparePath(capability.Path),
 			Address:    encodeBytes(capability.Address.Bytes()),
-			BorrowType: prepareType(capability.BorrowType),
+			BorrowType: prepareType(capability.BorrowType, typePreparationResults{}),
+		},
+	}
+}
+
+func prepareFunction(function cadence.Function) jsonValue {
+	return jsonValueObject{
+		Type: functionTypeStr,
+		Value: jsonFunctionValue{
+			FunctionType: prepareType(function.FunctionType, typePreparationResults{}),
 		},
 	}
 }
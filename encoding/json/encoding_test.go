@@ -19,9 +19,15 @@
 package json_test
 
 import (
+	"bytes"
+	goJSON "encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"unicode/utf8"
 
@@ -541,6 +547,113 @@ func TestEncodeUFix64(t *testing.T) {
 	}...)
 }
 
+func TestEncodeFix128(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Zero",
+			cadence.Fix128{Value: big.NewInt(0)},
+			`{"type":"Fix128","value":"0.000000000000000000000000"}`,
+		},
+		{
+			"0.000000000000000000000001",
+			cadence.Fix128{Value: big.NewInt(1)},
+			`{"type":"Fix128","value":"0.000000000000000000000001"}`,
+		},
+		{
+			"-0.000000000000000000000001",
+			cadence.Fix128{Value: big.NewInt(-1)},
+			`{"type":"Fix128","value":"-0.000000000000000000000001"}`,
+		},
+		{
+			"-12345",
+			cadence.Fix128{Value: new(big.Int).Mul(big.NewInt(-12345), sema.Fix128FactorBig)},
+			`{"type":"Fix128","value":"-12345.000000000000000000000000"}`,
+		},
+		{
+			"Min",
+			cadence.Fix128{Value: sema.Fix128TypeMinBig},
+			`{"type":"Fix128","value":"-170141183460469.231731687303715884105728"}`,
+		},
+		{
+			"Max",
+			cadence.Fix128{Value: sema.Fix128TypeMaxBig},
+			`{"type":"Fix128","value":"170141183460469.231731687303715884105727"}`,
+		},
+	}...)
+}
+
+func TestEncodeUFix128(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Zero",
+			cadence.UFix128{Value: big.NewInt(0)},
+			`{"type":"UFix128","value":"0.000000000000000000000000"}`,
+		},
+		{
+			"1234",
+			cadence.UFix128{Value: new(big.Int).Mul(big.NewInt(1234), sema.Fix128FactorBig)},
+			`{"type":"UFix128","value":"1234.000000000000000000000000"}`,
+		},
+		{
+			"Max",
+			cadence.UFix128{Value: sema.UFix128TypeMaxBig},
+			`{"type":"UFix128","value":"340282366920938.463463374607431768211455"}`,
+		},
+	}...)
+}
+
+func TestEncodeTimestamp(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Epoch",
+			cadence.Timestamp(0),
+			`{"type":"Timestamp","value":"0"}`,
+		},
+		{
+			"Before epoch",
+			cadence.Timestamp(-1),
+			`{"type":"Timestamp","value":"-1"}`,
+		},
+		{
+			"Max",
+			cadence.Timestamp(math.MaxInt64),
+			`{"type":"Timestamp","value":"9223372036854775807"}`,
+		},
+	}...)
+}
+
+func TestEncodeDuration(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t, []encodeTest{
+		{
+			"Zero",
+			cadence.Duration(0),
+			`{"type":"Duration","value":"0"}`,
+		},
+		{
+			"Min",
+			cadence.Duration(math.MinInt64),
+			`{"type":"Duration","value":"-9223372036854775808"}`,
+		},
+		{
+			"1.5s",
+			cadence.Duration(1500000000),
+			`{"type":"Duration","value":"1500000000"}`,
+		},
+	}...)
+}
+
 func TestEncodeArray(t *testing.T) {
 
 	t.Parallel()
@@ -1041,7 +1154,11 @@ func TestEncodeSimpleTypes(t *testing.T) {
 		cadence.Word64Type{},
 		cadence.Fix64Type{},
 		cadence.UFix64Type{},
+		cadence.Fix128Type{},
+		cadence.UFix128Type{},
 		cadence.BlockType{},
+		cadence.TimestampType{},
+		cadence.DurationType{},
 		cadence.PathType{},
 		cadence.CapabilityPathType{},
 		cadence.StoragePathType{},
@@ -1055,9 +1172,12 @@ func TestEncodeSimpleTypes(t *testing.T) {
 		cadence.PublicAccountKeysType{},
 		cadence.PublicAccountType{},
 		cadence.DeployedContractType{},
+		cadence.AddressType{},
 	}
 
 	for _, test := range tests {
+		test := test
+
 		t.Run(fmt.Sprintf("with static %s", test.ID()), func(t *testing.T) {
 
 			t.Parallel()
@@ -1371,6 +1491,43 @@ func TestEncodeType(t *testing.T) {
 		)
 	})
 
+	t.Run("with static event, indexed fields", func(t *testing.T) {
+
+		testEncodeAndDecode(
+			t,
+			cadence.TypeValue{
+				StaticType: &cadence.EventType{
+					Location:            utils.TestLocation,
+					QualifiedIdentifier: "E",
+					Fields: []cadence.Field{
+						{Identifier: "foo", Type: cadence.IntType{}},
+						{Identifier: "bar", Type: cadence.StringType{}},
+					},
+					Initializer: []cadence.Parameter{
+						{Identifier: "foo", Type: cadence.IntType{}},
+						{Identifier: "bar", Type: cadence.StringType{}},
+					},
+					IndexedFields: []string{"bar"},
+				},
+			},
+			`{"type":"Type", "value": {"staticType":
+					{"kind": "Event", 
+					 "type" : "",
+					 "typeID" : "S.test.E", 
+					 "fields" : [
+						  {"id" : "foo", "type": {"kind" : "Int"} },
+						  {"id" : "bar", "type": {"kind" : "String"} }
+					    ],
+					 "initializers" : 
+						  [[{"label" : "", "id" : "foo", "type": {"kind" : "Int"}},
+						  {"label" : "", "id" : "bar", "type": {"kind" : "String"}}]],
+					 "indexedFields" : ["bar"]
+					}
+				}
+			}`,
+		)
+	})
+
 	t.Run("with static enum", func(t *testing.T) {
 
 		testEncodeAndDecode(
@@ -1422,6 +1579,23 @@ func TestEncodeType(t *testing.T) {
 
 	})
 
+	t.Run("with static entitled &int", func(t *testing.T) {
+
+		testEncodeAndDecode(
+			t,
+			cadence.TypeValue{
+				StaticType: cadence.ReferenceType{
+					Entitlements: []string{"S.test.E1", "S.test.E2"},
+					Type:         cadence.IntType{},
+				},
+			},
+			`{"type":"Type","value":{"staticType":{"kind":"Reference",
+			"type" : {"kind" : "Int"}, "authorized" : false,
+			"entitlements" : ["S.test.E1", "S.test.E2"]}}}`,
+		)
+
+	})
+
 	t.Run("with static function", func(t *testing.T) {
 
 		testEncodeAndDecode(
@@ -1500,18 +1674,72 @@ func TestEncodeType(t *testing.T) {
 	})
 }
 
+func TestEncodeStandaloneType(t *testing.T) {
+
+	t.Parallel()
+
+	actual, err := json.EncodeType(cadence.OptionalType{Type: cadence.IntType{}})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"kind":"Optional","type":{"kind":"Int"}}`, string(actual))
+
+	// a missing type is encoded like the static type of an empty type value
+
+	actual, err = json.EncodeType(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, `""`, string(actual))
+}
+
 func TestEncodeCapability(t *testing.T) {
 
 	t.Parallel()
 
+	t.Run("with borrow type", func(t *testing.T) {
+
+		t.Parallel()
+
+		testEncodeAndDecode(
+			t,
+			cadence.Capability{
+				Path:       cadence.Path{Domain: "storage", Identifier: "foo"},
+				Address:    cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+				BorrowType: cadence.IntType{},
+			},
+			`{"type":"Capability","value":{"path":{"type":"Path","value":{"domain":"storage","identifier":"foo"}},"borrowType":{"kind":"Int"},"address":"0x0000000102030405"}}`,
+		)
+	})
+
+	t.Run("without borrow type", func(t *testing.T) {
+
+		t.Parallel()
+
+		testEncodeAndDecode(
+			t,
+			cadence.Capability{
+				Path:    cadence.Path{Domain: "public", Identifier: "foo"},
+				Address: cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+			},
+			`{"type":"Capability","value":{"path":{"type":"Path","value":{"domain":"public","identifier":"foo"}},"borrowType":"","address":"0x0000000102030405"}}`,
+		)
+	})
+}
+
+func TestEncodeFunction(t *testing.T) {
+
+	t.Parallel()
+
 	testEncodeAndDecode(
 		t,
-		cadence.Capability{
-			Path:       cadence.Path{Domain: "storage", Identifier: "foo"},
-			Address:    cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
-			BorrowType: cadence.IntType{},
-		},
-		`{"type":"Capability","value":{"path":{"type":"Path","value":{"domain":"storage","identifier":"foo"}},"borrowType":{"kind":"Int"},"address":"0x0000000102030405"}}`,
+		cadence.NewFunction(
+			cadence.FunctionType{
+				Parameters: []cadence.Parameter{
+					{Label: "_", Identifier: "n", Type: cadence.IntType{}},
+				},
+				ReturnType: cadence.BoolType{},
+			}.WithID("((Int):Bool)"),
+		),
+		`{"type":"Function","value":{"functionType":{"kind":"Function","typeID":"((Int):Bool)","parameters":[{"label":"_","id":"n","type":{"kind":"Int"}}],"return":{"kind":"Bool"}}}}`,
 	)
 }
 
@@ -1903,3 +2131,391 @@ func TestNonUTF8StringEncoding(t *testing.T) {
 	assert.IsType(t, cadence.String(""), decodedValue)
 	assert.True(t, utf8.ValidString(decodedValue.String()))
 }
+
+func TestEncodeMatchesPreparedValue(t *testing.T) {
+
+	t.Parallel()
+
+	value := cadence.NewArray([]cadence.Value{
+		cadence.NewOptional(nil),
+		cadence.NewOptional(cadence.NewOptional(cadence.String("<&>"))),
+		cadence.NewDictionary([]cadence.KeyValuePair{
+			{
+				Key:   cadence.String("a"),
+				Value: cadence.NewInt(1),
+			},
+		}),
+		cadence.NewResource([]cadence.Value{
+			cadence.NewInt(42),
+		}).WithType(fooResourceType),
+		cadence.NewArray([]cadence.Value{}),
+		cadence.NewLink(
+			cadence.Path{Domain: "storage", Identifier: "foo"},
+			"Bar",
+		),
+	})
+
+	// The incremental encoding must be identical
+	// to marshalling the prepared value as a whole
+
+	prepared, err := goJSON.Marshal(json.Prepare(value))
+	require.NoError(t, err)
+
+	encoded, err := json.Encode(value)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(prepared)+"\n", string(encoded))
+}
+
+func TestEncodeInvalidValueDoesNotWritePartialValue(t *testing.T) {
+
+	t.Parallel()
+
+	var w bytes.Buffer
+	encoder := json.NewEncoder(&w)
+
+	err := encoder.Encode(
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewResource([]cadence.Value{}).WithType(fooResourceType),
+		}),
+	)
+	require.Error(t, err)
+
+	// The encoder can still be used after an error
+
+	err = encoder.Encode(cadence.NewInt(2))
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"type":"Int","value":"2"}`+"\n", w.String())
+}
+
+func TestDecodeValueBeforeType(t *testing.T) {
+
+	t.Parallel()
+
+	actual, err := json.Decode([]byte(`
+      {
+        "value": [
+          {"value": "1", "type": "Int"},
+          {"value": {"value": "foo", "type": "String"}, "type": "Optional"}
+        ],
+        "type": "Array"
+      }
+    `))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewOptional(cadence.String("foo")),
+		}),
+		actual,
+	)
+}
+
+func TestDecodeInvalidStructure(t *testing.T) {
+
+	t.Parallel()
+
+	for _, data := range []string{
+		``,
+		`[]`,
+		`{"type":"Int"}`,
+		`{"type":"Int","value":"1","foo":"bar"}`,
+		`{"type":"Int","type":"Int","value":"1"}`,
+		`{"type":"Void","value":null}`,
+		`{"type":"Array","value":[{"type":"Int","value":"1"}`,
+		`{"type":"Array","value":{}}`,
+		`{"type":"Dictionary","value":[{"key":{"type":"Int","value":"1"}}]}`,
+		`{"type":"Optional","value":[]}`,
+		`{"type":"Struct","value":{"fields":[]}}`,
+		`{"type":"Struct","value":{"id":"S.test.Foo","fields":[{"name":"bar"}]}}`,
+	} {
+		_, err := json.Decode([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestEncodeAndDecodeStream(t *testing.T) {
+
+	t.Parallel()
+
+	const count = 1000
+
+	newValue := func(i int) cadence.Value {
+		return cadence.NewArray([]cadence.Value{
+			cadence.NewInt(i),
+			cadence.NewResource([]cadence.Value{
+				cadence.NewInt(i),
+			}).WithType(fooResourceType),
+		})
+	}
+
+	r, w := io.Pipe()
+
+	go func() {
+		encoder := json.NewEncoder(w)
+
+		for i := 0; i < count; i++ {
+			err := encoder.Encode(newValue(i))
+			if err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+		}
+
+		_ = w.Close()
+	}()
+
+	decoder := json.NewDecoder(r)
+
+	for i := 0; i < count; i++ {
+		actual, err := decoder.Decode()
+		require.NoError(t, err)
+
+		assert.Equal(t, newValue(i), actual)
+	}
+
+	_, err := decoder.Decode()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+var updateGoldenFiles = flag.Bool("update", false, "update the golden files")
+
+func TestGoldenFiles(t *testing.T) {
+
+	t.Parallel()
+
+	fooStructType := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Foo",
+		Fields: []cadence.Field{
+			{
+				Identifier: "bar",
+				Type:       cadence.IntType{},
+			},
+		},
+		Initializers: [][]cadence.Parameter{
+			{
+				{
+					Label:      "bar",
+					Identifier: "bar",
+					Type:       cadence.IntType{},
+				},
+			},
+		},
+	}
+
+	nodeStructType := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Node",
+		Fields: []cadence.Field{
+			{
+				Identifier: "next",
+			},
+		},
+	}
+	nodeStructType.Fields[0].Type = cadence.OptionalType{
+		Type: nodeStructType,
+	}
+
+	receiverInterfaceType := &cadence.ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Receiver",
+		Fields:              []cadence.Field{},
+		Initializers:        [][]cadence.Parameter{},
+	}
+
+	directionEnumType := &cadence.EnumType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Direction",
+		RawType:             cadence.UInt8Type{},
+		Fields: []cadence.Field{
+			{
+				Identifier: sema.EnumRawValueFieldName,
+				Type:       cadence.UInt8Type{},
+			},
+		},
+		Initializers: [][]cadence.Parameter{},
+	}
+
+	transferEventType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Transfer",
+		Fields: []cadence.Field{
+			{
+				Identifier: "amount",
+				Type:       cadence.UFix64Type{},
+			},
+			{
+				Identifier: "to",
+				Type:       cadence.OptionalType{Type: cadence.AddressType{}},
+			},
+		},
+		Initializer: []cadence.Parameter{
+			{
+				Label:      "amount",
+				Identifier: "amount",
+				Type:       cadence.UFix64Type{},
+			},
+			{
+				Label:      "to",
+				Identifier: "to",
+				Type:       cadence.OptionalType{Type: cadence.AddressType{}},
+			},
+		},
+	}
+
+	tests := map[string]cadence.Value{
+		"storage_path": cadence.Path{
+			Domain:     "storage",
+			Identifier: "foo",
+		},
+		"public_path": cadence.Path{
+			Domain:     "public",
+			Identifier: "foo",
+		},
+		"link": cadence.NewLink(
+			cadence.Path{Domain: "private", Identifier: "foo"},
+			"&Int",
+		),
+		"capability": cadence.Capability{
+			Path:    cadence.Path{Domain: "public", Identifier: "foo"},
+			Address: cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+			BorrowType: cadence.ReferenceType{
+				Type: cadence.RestrictedType{
+					Type:         cadence.AnyResourceType{},
+					Restrictions: []cadence.Type{receiverInterfaceType},
+				}.WithID("AnyResource{S.test.Receiver}"),
+			},
+		},
+		"type_address": cadence.NewTypeValue(cadence.AddressType{}),
+		"type_reference": cadence.NewTypeValue(
+			cadence.ReferenceType{
+				Authorized: true,
+				Type:       fooStructType,
+			},
+		),
+		"type_restricted": cadence.NewTypeValue(
+			cadence.RestrictedType{
+				Type:         cadence.AnyResourceType{},
+				Restrictions: []cadence.Type{receiverInterfaceType},
+			}.WithID("AnyResource{S.test.Receiver}"),
+		),
+		"type_function": cadence.NewTypeValue(
+			cadence.FunctionType{
+				Parameters: []cadence.Parameter{
+					{
+						Label:      "_",
+						Identifier: "foo",
+						Type:       fooStructType,
+					},
+				},
+				ReturnType: fooStructType,
+			}.WithID("((S.test.Foo):S.test.Foo)"),
+		),
+		"type_recursive": cadence.NewTypeValue(nodeStructType),
+		"type_enum":      cadence.NewTypeValue(directionEnumType),
+		"type_event":     cadence.NewTypeValue(transferEventType),
+		"enum": cadence.NewEnum([]cadence.Value{
+			cadence.NewUInt8(1),
+		}).WithType(directionEnumType),
+		"event": cadence.NewEvent([]cadence.Value{
+			cadence.UFix64(150000000),
+			cadence.NewOptional(cadence.BytesToAddress([]byte{1})),
+		}).WithType(transferEventType),
+	}
+
+	for name, value := range tests {
+		name := name
+		value := value
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			path := filepath.Join("testdata", name+".json")
+
+			actual, err := json.Encode(value)
+			require.NoError(t, err)
+
+			if *updateGoldenFiles {
+				var indented bytes.Buffer
+				err = goJSON.Indent(&indented, actual, "", "  ")
+				require.NoError(t, err)
+
+				indented.WriteByte('\n')
+
+				err = os.WriteFile(path, indented.Bytes(), 0644)
+				require.NoError(t, err)
+			}
+
+			expected, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expected), string(actual))
+
+			// Decoding must be symmetrical to encoding
+
+			decoded, err := json.Decode(expected)
+			require.NoError(t, err)
+
+			reencoded, err := json.Encode(decoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(actual), string(reencoded))
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+
+	t.Parallel()
+
+	type deposit struct {
+		Amount cadence.UFix64 `cadence:"amount"`
+	}
+
+	decoders := cadence.NewEventDecoders()
+	err := decoders.Register("S.test.Deposit", deposit{})
+	require.NoError(t, err)
+
+	payload := []byte(`
+      {
+        "type": "Event",
+        "value": {
+          "id": "S.test.Deposit",
+          "fields": [
+            {"name": "amount", "value": {"type": "UFix64", "value": "1.50000000"}}
+          ]
+        }
+      }
+    `)
+
+	actual, err := json.DecodeEvent(payload, decoders)
+	require.NoError(t, err)
+
+	assert.Equal(t, deposit{Amount: 150000000}, actual)
+
+	_, err = json.DecodeEvent([]byte(`{"type":"Int","value":"1"}`), decoders)
+	require.Error(t, err)
+}
+
+func TestEncodeArguments(t *testing.T) {
+
+	t.Parallel()
+
+	encoded, err := json.EncodeArguments(
+		cadence.NewArgumentsBuilder().
+			Add(1).
+			AddOptional("foo").
+			AddAddress("0x1"),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, encoded, 3)
+	assert.JSONEq(t, `{"type":"Int","value":"1"}`, string(encoded[0]))
+	assert.JSONEq(t, `{"type":"Optional","value":{"type":"String","value":"foo"}}`, string(encoded[1]))
+	assert.JSONEq(t, `{"type":"Address","value":"0x0000000000000001"}`, string(encoded[2]))
+}
@@ -0,0 +1,633 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// SchemaDialect is the JSON Schema dialect of the generated schemas
+//
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ErrUnsupportedSchemaType is returned by GenerateSchema
+// if no schema can be generated for a type,
+// e.g. because values of the type cannot be encoded
+//
+var ErrUnsupportedSchemaType = errors.New("unsupported type")
+
+// Schema is a JSON Schema, which describes the JSON-Cadence encoding
+// of the values of a Cadence type.
+//
+// Schemas are generated with GenerateSchema,
+// and can be converted back to types with SchemaType.
+//
+type Schema struct {
+	Dialect     string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Definitions map[string]*Schema `json:"$defs,omitempty"`
+	// CadenceType is the ID of the type the schema was generated for.
+	// It is not a JSON Schema keyword, so it is ignored by validators
+	CadenceType          string             `json:"cadenceType,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	PrefixItems          []*Schema          `json:"prefixItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+const (
+	signedIntegerPattern    = `^-?[0-9]+$`
+	unsignedIntegerPattern  = `^[0-9]+$`
+	signedFix64Pattern      = `^-?[0-9]+\.[0-9]{8}$`
+	unsignedFix64Pattern    = `^[0-9]+\.[0-9]{8}$`
+	signedFixedPointPattern = `^-?[0-9]+\.[0-9]+$`
+	unsignedFixedPattern    = `^[0-9]+\.[0-9]+$`
+	addressPattern          = `^0x[0-9a-f]{16}$`
+)
+
+// GenerateSchema returns a JSON Schema for the JSON-Cadence encoding of values of the given type,
+// which can be used to validate e.g. event payloads and script arguments.
+//
+// Composite types are defined in the definitions (`$defs`) of the returned schema,
+// so recursive composite types are supported.
+//
+func GenerateSchema(ty cadence.Type) (*Schema, error) {
+	generator := schemaGenerator{
+		definitions: map[string]*Schema{},
+	}
+
+	schema, err := generator.generate(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: copy, the schema of the type may be a shared simple schema
+
+	result := *schema
+	result.Dialect = SchemaDialect
+
+	if len(generator.definitions) > 0 {
+		result.Definitions = generator.definitions
+	}
+
+	return &result, nil
+}
+
+type schemaGenerator struct {
+	definitions map[string]*Schema
+}
+
+func (g schemaGenerator) generate(ty cadence.Type) (*Schema, error) {
+	switch ty := ty.(type) {
+	case cadence.AnyType, cadence.AnyStructType, cadence.AnyResourceType:
+		return anyValueSchema(ty), nil
+
+	case cadence.VoidType:
+		return &Schema{
+			CadenceType: ty.ID(),
+			Type:        "object",
+			Properties: map[string]*Schema{
+				"type": {Const: voidTypeStr},
+			},
+			Required:             []string{"type"},
+			AdditionalProperties: schemaFalse(),
+		}, nil
+
+	case cadence.BoolType:
+		return valueObjectSchema(ty, boolTypeStr, &Schema{Type: "boolean"}), nil
+
+	case cadence.StringType:
+		return valueObjectSchema(ty, stringTypeStr, &Schema{Type: "string"}), nil
+
+	case cadence.AddressType:
+		return valueObjectSchema(ty, addressTypeStr, patternSchema(addressPattern)), nil
+
+	case cadence.IntType:
+		return valueObjectSchema(ty, intTypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int8Type:
+		return valueObjectSchema(ty, int8TypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int16Type:
+		return valueObjectSchema(ty, int16TypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int32Type:
+		return valueObjectSchema(ty, int32TypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int64Type:
+		return valueObjectSchema(ty, int64TypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int128Type:
+		return valueObjectSchema(ty, int128TypeStr, patternSchema(signedIntegerPattern)), nil
+	case cadence.Int256Type:
+		return valueObjectSchema(ty, int256TypeStr, patternSchema(signedIntegerPattern)), nil
+
+	case cadence.UIntType:
+		return valueObjectSchema(ty, uintTypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt8Type:
+		return valueObjectSchema(ty, uint8TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt16Type:
+		return valueObjectSchema(ty, uint16TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt32Type:
+		return valueObjectSchema(ty, uint32TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt64Type:
+		return valueObjectSchema(ty, uint64TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt128Type:
+		return valueObjectSchema(ty, uint128TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.UInt256Type:
+		return valueObjectSchema(ty, uint256TypeStr, patternSchema(unsignedIntegerPattern)), nil
+
+	case cadence.Word8Type:
+		return valueObjectSchema(ty, word8TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.Word16Type:
+		return valueObjectSchema(ty, word16TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.Word32Type:
+		return valueObjectSchema(ty, word32TypeStr, patternSchema(unsignedIntegerPattern)), nil
+	case cadence.Word64Type:
+		return valueObjectSchema(ty, word64TypeStr, patternSchema(unsignedIntegerPattern)), nil
+
+	case cadence.Fix64Type:
+		return valueObjectSchema(ty, fix64TypeStr, patternSchema(signedFix64Pattern)), nil
+	case cadence.UFix64Type:
+		return valueObjectSchema(ty, ufix64TypeStr, patternSchema(unsignedFix64Pattern)), nil
+	case cadence.Fix128Type:
+		return valueObjectSchema(ty, fix128TypeStr, patternSchema(signedFixedPointPattern)), nil
+	case cadence.UFix128Type:
+		return valueObjectSchema(ty, ufix128TypeStr, patternSchema(unsignedFixedPattern)), nil
+
+	case cadence.OptionalType:
+		innerSchema, err := g.generate(ty.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		return valueObjectSchema(
+			ty,
+			optionalTypeStr,
+			&Schema{
+				AnyOf: []*Schema{
+					{Type: "null"},
+					innerSchema,
+				},
+			},
+		), nil
+
+	case cadence.VariableSizedArrayType:
+		elementSchema, err := g.generate(ty.ElementType)
+		if err != nil {
+			return nil, err
+		}
+
+		return valueObjectSchema(
+			ty,
+			arrayTypeStr,
+			&Schema{
+				Type:  "array",
+				Items: elementSchema,
+			},
+		), nil
+
+	case cadence.ConstantSizedArrayType:
+		elementSchema, err := g.generate(ty.ElementType)
+		if err != nil {
+			return nil, err
+		}
+
+		size := int(ty.Size)
+
+		return valueObjectSchema(
+			ty,
+			arrayTypeStr,
+			&Schema{
+				Type:     "array",
+				Items:    elementSchema,
+				MinItems: &size,
+				MaxItems: &size,
+			},
+		), nil
+
+	case cadence.DictionaryType:
+		keySchema, err := g.generate(ty.KeyType)
+		if err != nil {
+			return nil, err
+		}
+
+		valueSchema, err := g.generate(ty.ElementType)
+		if err != nil {
+			return nil, err
+		}
+
+		return valueObjectSchema(
+			ty,
+			dictionaryTypeStr,
+			&Schema{
+				Type: "array",
+				Items: &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"key":   keySchema,
+						"value": valueSchema,
+					},
+					Required:             []string{"key", "value"},
+					AdditionalProperties: schemaFalse(),
+				},
+			},
+		), nil
+
+	case *cadence.StructType:
+		return g.composite(ty, structTypeStr, ty.Fields)
+	case *cadence.ResourceType:
+		return g.composite(ty, resourceTypeStr, ty.Fields)
+	case *cadence.EventType:
+		return g.composite(ty, eventTypeStr, ty.Fields)
+	case *cadence.ContractType:
+		return g.composite(ty, contractTypeStr, ty.Fields)
+	case *cadence.EnumType:
+		return g.composite(ty, enumTypeStr, ty.Fields)
+
+	case cadence.RestrictedType:
+		// The value may be any composite which conforms to the restrictions
+		return anyValueSchema(ty), nil
+
+	case cadence.PathType:
+		return pathSchema(ty, common.PathDomainStorage, common.PathDomainPrivate, common.PathDomainPublic), nil
+	case cadence.CapabilityPathType:
+		return pathSchema(ty, common.PathDomainPrivate, common.PathDomainPublic), nil
+	case cadence.StoragePathType:
+		return pathSchema(ty, common.PathDomainStorage), nil
+	case cadence.PublicPathType:
+		return pathSchema(ty, common.PathDomainPublic), nil
+	case cadence.PrivatePathType:
+		return pathSchema(ty, common.PathDomainPrivate), nil
+
+	case cadence.CapabilityType:
+		return valueObjectSchema(
+			ty,
+			capabilityTypeStr,
+			&Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"path":       pathSchema(cadence.PathType{}, common.PathDomainPrivate, common.PathDomainPublic),
+					"address":    patternSchema(addressPattern),
+					"borrowType": {},
+				},
+				Required:             []string{"path", "address", "borrowType"},
+				AdditionalProperties: schemaFalse(),
+			},
+		), nil
+
+	case cadence.MetaType:
+		return valueObjectSchema(
+			ty,
+			typeTypeStr,
+			&Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"staticType": {},
+				},
+				Required:             []string{"staticType"},
+				AdditionalProperties: schemaFalse(),
+			},
+		), nil
+	}
+
+	if ty == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedSchemaType)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedSchemaType, ty.ID())
+}
+
+// composite returns a reference to the definition of the given composite type,
+// and adds the definition if it does not exist yet
+//
+func (g schemaGenerator) composite(ty cadence.Type, kind string, fields []cadence.Field) (*Schema, error) {
+	typeID := ty.ID()
+
+	reference := &Schema{
+		CadenceType: typeID,
+		Ref:         "#/$defs/" + escapeJSONPointer(typeID),
+	}
+
+	if _, ok := g.definitions[typeID]; ok {
+		return reference, nil
+	}
+
+	// NOTE: add the definition before generating the schemas of the fields,
+	// as the type may be recursive
+
+	definition := &Schema{}
+	g.definitions[typeID] = definition
+
+	var fieldSchemas []*Schema
+
+	for _, field := range fields {
+
+		// Function fields are not encoded
+
+		if _, ok := field.Type.(cadence.FunctionType); ok {
+			continue
+		}
+
+		valueSchema, err := g.generate(field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldSchemas = append(
+			fieldSchemas,
+			&Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"name":  {Const: field.Identifier},
+					"value": valueSchema,
+				},
+				Required:             []string{"name", "value"},
+				AdditionalProperties: schemaFalse(),
+			},
+		)
+	}
+
+	fieldCount := len(fieldSchemas)
+
+	*definition = *valueObjectSchema(
+		ty,
+		kind,
+		&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id": {Const: typeID},
+				"fields": {
+					Type:        "array",
+					PrefixItems: fieldSchemas,
+					MinItems:    &fieldCount,
+					MaxItems:    &fieldCount,
+				},
+			},
+			Required:             []string{"id", "fields"},
+			AdditionalProperties: schemaFalse(),
+		},
+	)
+
+	return reference, nil
+}
+
+func valueObjectSchema(ty cadence.Type, typeStr string, valueSchema *Schema) *Schema {
+	return &Schema{
+		CadenceType: ty.ID(),
+		Type:        "object",
+		Properties: map[string]*Schema{
+			"type":  {Const: typeStr},
+			"value": valueSchema,
+		},
+		Required:             []string{"type", "value"},
+		AdditionalProperties: schemaFalse(),
+	}
+}
+
+func anyValueSchema(ty cadence.Type) *Schema {
+	return &Schema{
+		CadenceType: ty.ID(),
+		Type:        "object",
+		Properties: map[string]*Schema{
+			"type": {Type: "string"},
+		},
+		Required: []string{"type"},
+	}
+}
+
+func pathSchema(ty cadence.Type, domains ...common.PathDomain) *Schema {
+	domainIdentifiers := make([]interface{}, len(domains))
+	for i, domain := range domains {
+		domainIdentifiers[i] = domain.Identifier()
+	}
+
+	return valueObjectSchema(
+		ty,
+		pathTypeStr,
+		&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"domain":     {Enum: domainIdentifiers},
+				"identifier": {Type: "string"},
+			},
+			Required:             []string{"domain", "identifier"},
+			AdditionalProperties: schemaFalse(),
+		},
+	)
+}
+
+func patternSchema(pattern string) *Schema {
+	return &Schema{
+		Type:    "string",
+		Pattern: pattern,
+	}
+}
+
+func schemaFalse() *bool {
+	result := false
+	return &result
+}
+
+// escapeJSONPointer escapes the given reference token of a JSON pointer (RFC 6901)
+//
+func escapeJSONPointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// SchemaType returns the type the given schema was generated for by GenerateSchema.
+//
+// Composite types are reconstructed from the definitions of the schema,
+// including their fields. Function fields and initializers are not included in schemas,
+// so they are missing from the resulting composite types.
+//
+// Only schemas generated by GenerateSchema can be converted,
+// as the conversion relies on the `cadenceType` annotations of the schema.
+//
+func SchemaType(schema *Schema) (cadence.Type, error) {
+	converter := &schemaConverter{
+		definitions: schema.Definitions,
+		composites:  map[string]cadence.Type{},
+	}
+	return converter.convert(schema)
+}
+
+type schemaConverter struct {
+	definitions map[string]*Schema
+	composites  map[string]cadence.Type
+}
+
+func (c *schemaConverter) convert(schema *Schema) (cadence.Type, error) {
+	if schema == nil || schema.CadenceType == "" {
+		return nil, fmt.Errorf("cannot convert schema to type: missing Cadence type")
+	}
+
+	return cadence.ParseTypeID(schema.CadenceType, c.resolveNominalType)
+}
+
+func (c *schemaConverter) resolveNominalType(
+	location common.Location,
+	qualifiedIdentifier string,
+	restrictedType cadence.Type,
+) (
+	cadence.Type,
+	error,
+) {
+	// Restrictions are interfaces, which are not defined in schemas
+
+	if restrictedType != nil {
+		switch restrictedType.(type) {
+		case cadence.AnyResourceType, *cadence.ResourceType:
+			return &cadence.ResourceInterfaceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}, nil
+		default:
+			return &cadence.StructInterfaceType{
+				Location:            location,
+				QualifiedIdentifier: qualifiedIdentifier,
+			}, nil
+		}
+	}
+
+	typeID := qualifiedIdentifier
+	if location != nil {
+		typeID = string(location.TypeID(qualifiedIdentifier))
+	}
+
+	if composite, ok := c.composites[typeID]; ok {
+		return composite, nil
+	}
+
+	definition, ok := c.definitions[typeID]
+	if !ok {
+		return nil, fmt.Errorf("cannot convert schema to type: missing definition for %s", typeID)
+	}
+
+	kind, _ := definition.property("type").Const.(string)
+
+	var composite cadence.Type
+	var setFields func(fields []cadence.Field)
+
+	switch kind {
+	case structTypeStr:
+		structType := &cadence.StructType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}
+		composite = structType
+		setFields = func(fields []cadence.Field) { structType.Fields = fields }
+
+	case resourceTypeStr:
+		resourceType := &cadence.ResourceType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}
+		composite = resourceType
+		setFields = func(fields []cadence.Field) { resourceType.Fields = fields }
+
+	case eventTypeStr:
+		eventType := &cadence.EventType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}
+		composite = eventType
+		setFields = func(fields []cadence.Field) { eventType.Fields = fields }
+
+	case contractTypeStr:
+		contractType := &cadence.ContractType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}
+		composite = contractType
+		setFields = func(fields []cadence.Field) { contractType.Fields = fields }
+
+	case enumTypeStr:
+		enumType := &cadence.EnumType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}
+		composite = enumType
+		setFields = func(fields []cadence.Field) {
+			enumType.Fields = fields
+			for _, field := range fields {
+				if field.Identifier == "rawValue" {
+					enumType.RawType = field.Type
+				}
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("cannot convert schema to type: invalid composite kind for %s: %q", typeID, kind)
+	}
+
+	// NOTE: register the composite before converting the fields,
+	// as the type may be recursive
+
+	c.composites[typeID] = composite
+
+	fieldSchemas := definition.property("value").property("fields")
+	if fieldSchemas == nil {
+		return nil, fmt.Errorf("cannot convert schema to type: missing fields for %s", typeID)
+	}
+
+	fields := make([]cadence.Field, 0, len(fieldSchemas.PrefixItems))
+
+	for _, fieldSchema := range fieldSchemas.PrefixItems {
+		identifier, ok := fieldSchema.property("name").Const.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert schema to type: invalid field name in %s", typeID)
+		}
+
+		fieldType, err := c.convert(fieldSchema.property("value"))
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(
+			fields,
+			cadence.Field{
+				Identifier: identifier,
+				Type:       fieldType,
+			},
+		)
+	}
+
+	setFields(fields)
+
+	return composite, nil
+}
+
+// property returns the schema of the property with the given name, if any
+//
+func (s *Schema) property(name string) *Schema {
+	if s == nil {
+		return nil
+	}
+	return s.Properties[name]
+}
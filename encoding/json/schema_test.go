@@ -0,0 +1,215 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func testSchemaRoundTrip(t *testing.T, ty cadence.Type) *jsoncdc.Schema {
+	schema, err := jsoncdc.GenerateSchema(ty)
+	require.NoError(t, err)
+
+	assert.Equal(t, jsoncdc.SchemaDialect, schema.Dialect)
+
+	encoded, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	var decoded jsoncdc.Schema
+	err = json.Unmarshal(encoded, &decoded)
+	require.NoError(t, err)
+
+	convertedType, err := jsoncdc.SchemaType(&decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, ty.ID(), convertedType.ID())
+
+	return &decoded
+}
+
+func TestGenerateSchemaSimpleTypes(t *testing.T) {
+
+	t.Parallel()
+
+	for _, ty := range []cadence.Type{
+		cadence.AnyStructType{},
+		cadence.VoidType{},
+		cadence.BoolType{},
+		cadence.StringType{},
+		cadence.AddressType{},
+		cadence.IntType{},
+		cadence.UInt8Type{},
+		cadence.Word64Type{},
+		cadence.UFix64Type{},
+		cadence.PathType{},
+		cadence.PublicPathType{},
+		cadence.MetaType{},
+		cadence.OptionalType{Type: cadence.IntType{}},
+		cadence.VariableSizedArrayType{ElementType: cadence.StringType{}},
+		cadence.ConstantSizedArrayType{ElementType: cadence.BoolType{}, Size: 2},
+		cadence.DictionaryType{KeyType: cadence.StringType{}, ElementType: cadence.UInt64Type{}},
+		cadence.CapabilityType{BorrowType: cadence.ReferenceType{Type: cadence.AnyStructType{}}},
+	} {
+		ty := ty
+
+		t.Run(ty.ID(), func(t *testing.T) {
+
+			t.Parallel()
+
+			schema := testSchemaRoundTrip(t, ty)
+			assert.Empty(t, schema.Definitions)
+		})
+	}
+}
+
+func TestGenerateSchemaValueShape(t *testing.T) {
+
+	t.Parallel()
+
+	schema, err := jsoncdc.GenerateSchema(
+		cadence.ConstantSizedArrayType{
+			ElementType: cadence.UFix64Type{},
+			Size:        3,
+		},
+	)
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t,
+		`{
+          "$schema": "https://json-schema.org/draft/2020-12/schema",
+          "cadenceType": "[UFix64;3]",
+          "type": "object",
+          "properties": {
+            "type": {"const": "Array"},
+            "value": {
+              "type": "array",
+              "items": {
+                "cadenceType": "UFix64",
+                "type": "object",
+                "properties": {
+                  "type": {"const": "UFix64"},
+                  "value": {"type": "string", "pattern": "^[0-9]+\\.[0-9]{8}$"}
+                },
+                "required": ["type", "value"],
+                "additionalProperties": false
+              },
+              "minItems": 3,
+              "maxItems": 3
+            }
+          },
+          "required": ["type", "value"],
+          "additionalProperties": false
+        }`,
+		string(encoded),
+	)
+}
+
+func TestGenerateSchemaComposite(t *testing.T) {
+
+	t.Parallel()
+
+	eventType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Transfer",
+		Fields: []cadence.Field{
+			{Identifier: "from", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+		},
+	}
+
+	schema := testSchemaRoundTrip(t, eventType)
+
+	assert.Equal(t, "#/$defs/S.test.Transfer", schema.Ref)
+	require.Contains(t, schema.Definitions, "S.test.Transfer")
+
+	convertedType, err := jsoncdc.SchemaType(schema)
+	require.NoError(t, err)
+
+	assert.Equal(t, eventType, convertedType)
+}
+
+func TestGenerateSchemaRecursiveComposite(t *testing.T) {
+
+	t.Parallel()
+
+	nodeType := &cadence.StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Node",
+	}
+	nodeType.Fields = []cadence.Field{
+		{Identifier: "value", Type: cadence.IntType{}},
+		{Identifier: "next", Type: cadence.OptionalType{Type: nodeType}},
+	}
+
+	schema := testSchemaRoundTrip(t, nodeType)
+
+	require.Len(t, schema.Definitions, 1)
+
+	convertedType, err := jsoncdc.SchemaType(schema)
+	require.NoError(t, err)
+
+	require.IsType(t, &cadence.StructType{}, convertedType)
+	convertedNodeType := convertedType.(*cadence.StructType)
+
+	require.Len(t, convertedNodeType.Fields, 2)
+	assert.Equal(t,
+		cadence.OptionalType{Type: convertedNodeType},
+		convertedNodeType.Fields[1].Type,
+	)
+}
+
+func TestGenerateSchemaEnum(t *testing.T) {
+
+	t.Parallel()
+
+	enumType := &cadence.EnumType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Color",
+		RawType:             cadence.UInt8Type{},
+		Fields: []cadence.Field{
+			{Identifier: "rawValue", Type: cadence.UInt8Type{}},
+		},
+	}
+
+	schema := testSchemaRoundTrip(t, enumType)
+
+	convertedType, err := jsoncdc.SchemaType(schema)
+	require.NoError(t, err)
+
+	assert.Equal(t, enumType, convertedType)
+}
+
+func TestGenerateSchemaUnsupportedType(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := jsoncdc.GenerateSchema(cadence.ReferenceType{Type: cadence.IntType{}})
+	require.ErrorIs(t, err, jsoncdc.ErrUnsupportedSchemaType)
+}
@@ -0,0 +1,182 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnknownEventType is returned by EventDecoders.Decode
+// if no decoder is registered for the type of the event
+//
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// EventDecodeFunc converts an event into a Go value
+//
+type EventDecodeFunc func(event Event) (interface{}, error)
+
+// eventDecoder is a decoder for one version of an event type
+//
+type eventDecoder struct {
+	// requiredFields are the names of the fields the event must have
+	// for this decoder to be applicable. nil if the decoder is always applicable
+	requiredFields []string
+	decode         EventDecodeFunc
+}
+
+func (d eventDecoder) isApplicable(fieldNames map[string]struct{}) bool {
+	for _, name := range d.requiredFields {
+		if _, ok := fieldNames[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// EventDecoders converts events into Go values,
+// using the decoders registered for the event's type ID.
+//
+// Multiple decoders may be registered for the same event type ID,
+// e.g. for different versions of an event type which was extended with new fields.
+// Decoders are tried from the most recently to the least recently registered,
+// and the first decoder which is applicable to the event is used.
+// So versions should be registered from the oldest to the newest.
+//
+type EventDecoders struct {
+	decoders map[string][]eventDecoder
+}
+
+// NewEventDecoders returns a new, empty set of event decoders
+//
+func NewEventDecoders() *EventDecoders {
+	return &EventDecoders{
+		decoders: map[string][]eventDecoder{},
+	}
+}
+
+// Register registers the Go struct type of the given value
+// as a version of the event type with the given ID.
+//
+// Events are converted to the Go struct using Unmarshal.
+// The version is only applicable to events which have all fields of the Go struct,
+// except fields with the `optional` struct tag option.
+//
+func (d *EventDecoders) Register(typeID string, goStruct interface{}) error {
+	goType := reflect.TypeOf(goStruct)
+	if goType == nil || goType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot register event decoder for %s: %T is not a struct", typeID, goStruct)
+	}
+
+	fields := structFields(goType)
+
+	requiredFields := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.optional {
+			continue
+		}
+		requiredFields = append(requiredFields, field.name)
+	}
+
+	d.add(typeID, eventDecoder{
+		requiredFields: requiredFields,
+		decode: func(event Event) (interface{}, error) {
+			result := reflect.New(goType)
+
+			err := Unmarshal(event, result.Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			return result.Elem().Interface(), nil
+		},
+	})
+
+	return nil
+}
+
+// RegisterFunc registers the given function as a decoder
+// for the event type with the given ID.
+//
+// The decoder is applicable to all events of the type,
+// so it takes precedence over all previously registered decoders for the type.
+//
+func (d *EventDecoders) RegisterFunc(typeID string, decode EventDecodeFunc) {
+	d.add(typeID, eventDecoder{
+		decode: decode,
+	})
+}
+
+func (d *EventDecoders) add(typeID string, decoder eventDecoder) {
+	d.decoders[typeID] = append(d.decoders[typeID], decoder)
+}
+
+// Decode converts the given event into a Go value,
+// using the most recently registered decoder for the event's type
+// which is applicable to the event.
+//
+// If no decoder is registered for the event's type,
+// an error wrapping ErrUnknownEventType is returned.
+//
+func (d *EventDecoders) Decode(event Event) (interface{}, error) {
+	if event.EventType == nil {
+		return nil, fmt.Errorf("cannot decode event without type")
+	}
+
+	typeID := event.EventType.ID()
+
+	decoders := d.decoders[typeID]
+	if len(decoders) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, typeID)
+	}
+
+	fields := valueFields(event.EventType.Fields)
+	fieldNames := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		fieldNames[field.Identifier] = struct{}{}
+	}
+
+	for i := len(decoders) - 1; i >= 0; i-- {
+		decoder := decoders[i]
+		if !decoder.isApplicable(fieldNames) {
+			continue
+		}
+
+		result, err := decoder.decode(event)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode event %s: %w", typeID, err)
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("cannot decode event %s: no registered version has matching fields", typeID)
+}
+
+// DecodeValue is like Decode, but accepts any value,
+// e.g. a value decoded from an event payload
+//
+func (d *EventDecoders) DecodeValue(value Value) (interface{}, error) {
+	event, ok := value.(Event)
+	if !ok {
+		return nil, fmt.Errorf("cannot decode %T: not an event", value)
+	}
+
+	return d.Decode(event)
+}
@@ -0,0 +1,195 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type testWithdrawV1 struct {
+	Amount UFix64 `cadence:"amount"`
+}
+
+type testWithdrawV2 struct {
+	Amount UFix64   `cadence:"amount"`
+	From   *Address `cadence:"from"`
+	Memo   string   `cadence:"memo,optional"`
+}
+
+func newTestWithdrawEvent(fields []Field, values []Value) Event {
+	return NewEvent(values).WithType(&EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Withdraw",
+		Fields:              fields,
+	})
+}
+
+func TestEventDecoders(t *testing.T) {
+
+	t.Parallel()
+
+	const typeID = "S.test.Withdraw"
+
+	decoders := NewEventDecoders()
+
+	require.NoError(t, decoders.Register(typeID, testWithdrawV1{}))
+	require.NoError(t, decoders.Register(typeID, testWithdrawV2{}))
+
+	t.Run("old version", func(t *testing.T) {
+
+		t.Parallel()
+
+		event := newTestWithdrawEvent(
+			[]Field{
+				{Identifier: "amount", Type: UFix64Type{}},
+			},
+			[]Value{
+				UFix64(100),
+			},
+		)
+
+		actual, err := decoders.Decode(event)
+		require.NoError(t, err)
+
+		assert.Equal(t, testWithdrawV1{Amount: 100}, actual)
+	})
+
+	t.Run("new version", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := BytesToAddress([]byte{1})
+
+		event := newTestWithdrawEvent(
+			[]Field{
+				{Identifier: "amount", Type: UFix64Type{}},
+				{Identifier: "from", Type: OptionalType{Type: AddressType{}}},
+			},
+			[]Value{
+				UFix64(100),
+				NewOptional(address),
+			},
+		)
+
+		actual, err := decoders.Decode(event)
+		require.NoError(t, err)
+
+		assert.Equal(t, testWithdrawV2{Amount: 100, From: &address}, actual)
+	})
+
+	t.Run("new version with optional field", func(t *testing.T) {
+
+		t.Parallel()
+
+		event := newTestWithdrawEvent(
+			[]Field{
+				{Identifier: "amount", Type: UFix64Type{}},
+				{Identifier: "from", Type: OptionalType{Type: AddressType{}}},
+				{Identifier: "memo", Type: StringType{}},
+			},
+			[]Value{
+				UFix64(100),
+				NewOptional(nil),
+				String("rent"),
+			},
+		)
+
+		actual, err := decoders.DecodeValue(event)
+		require.NoError(t, err)
+
+		assert.Equal(t, testWithdrawV2{Amount: 100, Memo: "rent"}, actual)
+	})
+
+	t.Run("no matching version", func(t *testing.T) {
+
+		t.Parallel()
+
+		event := newTestWithdrawEvent(
+			[]Field{
+				{Identifier: "from", Type: OptionalType{Type: AddressType{}}},
+			},
+			[]Value{
+				NewOptional(nil),
+			},
+		)
+
+		_, err := decoders.Decode(event)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+
+		t.Parallel()
+
+		event := NewEvent(nil).WithType(&EventType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Unknown",
+		})
+
+		_, err := decoders.Decode(event)
+		require.ErrorIs(t, err, ErrUnknownEventType)
+	})
+
+	t.Run("not an event", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := decoders.DecodeValue(NewInt(1))
+		require.Error(t, err)
+	})
+}
+
+func TestEventDecodersRegisterFunc(t *testing.T) {
+
+	t.Parallel()
+
+	const typeID = "S.test.Withdraw"
+
+	decoders := NewEventDecoders()
+
+	require.NoError(t, decoders.Register(typeID, testWithdrawV1{}))
+
+	decoders.RegisterFunc(typeID, func(event Event) (interface{}, error) {
+		return len(event.Fields), nil
+	})
+
+	actual, err := decoders.Decode(
+		newTestWithdrawEvent(
+			[]Field{
+				{Identifier: "amount", Type: UFix64Type{}},
+			},
+			[]Value{
+				UFix64(100),
+			},
+		),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, actual)
+
+	// only structs can be registered
+
+	require.Error(t, decoders.Register(typeID, 1))
+	require.Error(t, decoders.Register(typeID, nil))
+}
@@ -54,8 +54,36 @@ const UFix64TypeMaxFractional = math.MaxUint64 % uint64(Fix64Factor)
 var UFix64TypeMinFractionalBig = new(big.Int).SetUint64(UFix64TypeMinFractional)
 var UFix64TypeMaxFractionalBig = new(big.Int).SetUint64(UFix64TypeMaxFractional)
 
+// Fix128 and UFix128
+//
+// The integer and fractional ranges exceed the range of Go's integer types,
+// so they are only available as big integers
+
+const Fix128Scale uint = 24
+
+var Fix128FactorBig = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(Fix128Scale)), nil)
+
+// Fix128
+
+var Fix128TypeMinBig = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+var Fix128TypeMaxBig = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+
+var Fix128TypeMinIntBig, Fix128TypeMinFractionalBig = new(big.Int).QuoRem(Fix128TypeMinBig, Fix128FactorBig, new(big.Int))
+var Fix128TypeMaxIntBig, Fix128TypeMaxFractionalBig = new(big.Int).QuoRem(Fix128TypeMaxBig, Fix128FactorBig, new(big.Int))
+
+// UFix128
+
+var UFix128TypeMinBig = new(big.Int)
+var UFix128TypeMaxBig = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+var UFix128TypeMinIntBig = new(big.Int)
+var UFix128TypeMaxIntBig, UFix128TypeMaxFractionalBig = new(big.Int).QuoRem(UFix128TypeMaxBig, Fix128FactorBig, new(big.Int))
+
+var UFix128TypeMinFractionalBig = new(big.Int)
+
 func init() {
 	Fix64TypeMinFractionalBig.Abs(Fix64TypeMinFractionalBig)
+	Fix128TypeMinFractionalBig.Abs(Fix128TypeMinFractionalBig)
 }
 
 func CheckRange(
@@ -56,10 +56,12 @@ func ConvertToFixedPointBigInt(
 	}
 
 	// value = integer + fractional
+	//
+	// NOTE: fractional might still be the given argument, so it must not be negated in place
 
 	if negative {
 		integer.Neg(integer)
-		fractional.Neg(fractional)
+		fractional = new(big.Int).Neg(fractional)
 	}
 
 	return integer.Add(integer, fractional)
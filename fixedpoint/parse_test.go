@@ -57,3 +57,60 @@ func TestParseFixedPoint(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFix128(t *testing.T) {
+
+	t.Parallel()
+
+	for input, expected := range map[string]string{
+		"0.000000000000000000000001": "1",
+		"-1.5":                       "-1500000000000000000000000",
+		"170141183460469.231731687303715884105727":  "170141183460469231731687303715884105727",
+		"-170141183460469.231731687303715884105728": "-170141183460469231731687303715884105728",
+	} {
+		t.Run(input, func(t *testing.T) {
+			value, err := ParseFix128(input)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value.String())
+		})
+	}
+
+	for _, input := range []string{
+		"0.0000000000000000000000001",
+		"170141183460469.231731687303715884105728",
+		"-170141183460469.231731687303715884105729",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseFix128(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseUFix128(t *testing.T) {
+
+	t.Parallel()
+
+	for input, expected := range map[string]string{
+		"0.000000000000000000000001": "1",
+		"1.5":                        "1500000000000000000000000",
+		"340282366920938.463463374607431768211455": "340282366920938463463374607431768211455",
+	} {
+		t.Run(input, func(t *testing.T) {
+			value, err := ParseUFix128(input)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value.String())
+		})
+	}
+
+	for _, input := range []string{
+		"-1.0",
+		"0.0000000000000000000000001",
+		"340282366920938.463463374607431768211456",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseUFix128(input)
+			assert.Error(t, err)
+		})
+	}
+}
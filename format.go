@@ -0,0 +1,198 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/format"
+)
+
+// FormatOptions configures how values are formatted by Format.
+//
+// The zero value results in the canonical format,
+// which is also used by the String functions of all values.
+//
+type FormatOptions struct {
+	// SortDictionaryKeys formats the entries of dictionaries
+	// sorted by their formatted keys, instead of in the order of their pairs
+	SortDictionaryKeys bool
+	// Indent, if not empty, formats arrays, dictionaries, and composites
+	// over multiple lines, indenting their elements with the given string
+	Indent string
+}
+
+// Format returns a human-readable representation of the given value, e.g.
+//
+//   S.test.Foo(bar: [1, 2], baz: {"a": 1.50000000})
+//
+func Format(value Value, options FormatOptions) string {
+	var builder strings.Builder
+	f := valueFormatter{
+		builder: &builder,
+		options: options,
+	}
+	f.format(value, 0)
+	return builder.String()
+}
+
+type valueFormatter struct {
+	builder *strings.Builder
+	options FormatOptions
+}
+
+// formattedElement is an element of an array, dictionary, or composite value
+//
+type formattedElement struct {
+	// prefix is written before the element, e.g. the key of a dictionary entry
+	prefix string
+	value  Value
+}
+
+func (f valueFormatter) format(value Value, depth int) {
+	switch value := value.(type) {
+	case nil:
+		f.builder.WriteString(format.Nil)
+
+	case Optional:
+		f.format(value.Value, depth)
+
+	case Array:
+		elements := make([]formattedElement, len(value.Values))
+		for i, element := range value.Values {
+			elements[i] = formattedElement{value: element}
+		}
+		f.formatElements('[', ']', elements, depth)
+
+	case Dictionary:
+		elements := make([]formattedElement, len(value.Pairs))
+		for i, pair := range value.Pairs {
+			elements[i] = formattedElement{
+				prefix: Format(pair.Key, FormatOptions{SortDictionaryKeys: f.options.SortDictionaryKeys}) + ": ",
+				value:  pair.Value,
+			}
+		}
+
+		if f.options.SortDictionaryKeys {
+			sort.SliceStable(elements, func(i, j int) bool {
+				return elements[i].prefix < elements[j].prefix
+			})
+		}
+
+		f.formatElements('{', '}', elements, depth)
+
+	case Struct:
+		f.formatComposite(value.StructType, value.Fields, depth)
+
+	case Resource:
+		f.formatComposite(value.ResourceType, value.Fields, depth)
+
+	case Event:
+		f.formatComposite(value.EventType, value.Fields, depth)
+
+	case Contract:
+		f.formatComposite(value.ContractType, value.Fields, depth)
+
+	case Enum:
+		f.formatComposite(value.EnumType, value.Fields, depth)
+
+	default:
+		f.builder.WriteString(value.String())
+	}
+}
+
+func (f valueFormatter) formatComposite(compositeType CompositeType, values []Value, depth int) {
+
+	// NOTE: the composite type might be a typed nil pointer
+
+	var fields []Field
+	if compositeTypeIsSet(compositeType) {
+		f.builder.WriteString(compositeType.ID())
+		fields = compositeType.CompositeFields()
+	}
+
+	elements := make([]formattedElement, len(values))
+	for i, value := range values {
+		var prefix string
+		if i < len(fields) {
+			prefix = fields[i].Identifier + ": "
+		}
+		elements[i] = formattedElement{
+			prefix: prefix,
+			value:  value,
+		}
+	}
+
+	f.formatElements('(', ')', elements, depth)
+}
+
+func compositeTypeIsSet(compositeType CompositeType) bool {
+	switch compositeType := compositeType.(type) {
+	case nil:
+		return false
+	case *StructType:
+		return compositeType != nil
+	case *ResourceType:
+		return compositeType != nil
+	case *EventType:
+		return compositeType != nil
+	case *ContractType:
+		return compositeType != nil
+	case *EnumType:
+		return compositeType != nil
+	default:
+		return true
+	}
+}
+
+func (f valueFormatter) formatElements(open, close rune, elements []formattedElement, depth int) {
+	f.builder.WriteRune(open)
+
+	multiline := f.options.Indent != "" && len(elements) > 0
+
+	for i, element := range elements {
+		if i > 0 {
+			f.builder.WriteRune(',')
+			if !multiline {
+				f.builder.WriteRune(' ')
+			}
+		}
+
+		if multiline {
+			f.newLine(depth + 1)
+		}
+
+		f.builder.WriteString(element.prefix)
+		f.format(element.value, depth+1)
+	}
+
+	if multiline {
+		f.newLine(depth)
+	}
+
+	f.builder.WriteRune(close)
+}
+
+func (f valueFormatter) newLine(depth int) {
+	f.builder.WriteRune('\n')
+	for i := 0; i < depth; i++ {
+		f.builder.WriteString(f.options.Indent)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestFormat(t *testing.T) {
+
+	t.Parallel()
+
+	ufix64, err := NewUFix64("1.5")
+	require.NoError(t, err)
+
+	fooType := &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Foo",
+		Fields: []Field{
+			{Identifier: "bar", Type: VariableSizedArrayType{ElementType: IntType{}}},
+			{Identifier: "baz", Type: DictionaryType{KeyType: StringType{}, ElementType: UFix64Type{}}},
+		},
+	}
+
+	foo := NewStruct([]Value{
+		NewArray([]Value{NewInt(1), NewInt(2)}),
+		NewDictionary([]KeyValuePair{
+			{Key: String("b"), Value: ufix64},
+			{Key: String("a"), Value: NewOptional(nil)},
+		}),
+	}).WithType(fooType)
+
+	t.Run("canonical", func(t *testing.T) {
+
+		t.Parallel()
+
+		const expected = `S.test.Foo(bar: [1, 2], baz: {"b": 1.50000000, "a": nil})`
+
+		assert.Equal(t, expected, Format(foo, FormatOptions{}))
+		assert.Equal(t, expected, foo.String())
+	})
+
+	t.Run("sorted dictionary keys", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			`S.test.Foo(bar: [1, 2], baz: {"a": nil, "b": 1.50000000})`,
+			Format(foo, FormatOptions{SortDictionaryKeys: true}),
+		)
+	})
+
+	t.Run("indented", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := NewArray([]Value{
+			foo,
+			NewArray(nil),
+			NewDictionary(nil),
+		})
+
+		assert.Equal(t,
+			`[
+  S.test.Foo(
+    bar: [
+      1,
+      2
+    ],
+    baz: {
+      "a": nil,
+      "b": 1.50000000
+    }
+  ),
+  [],
+  {}
+]`,
+			Format(value, FormatOptions{
+				SortDictionaryKeys: true,
+				Indent:             "  ",
+			}),
+		)
+	})
+
+	t.Run("missing types and values", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := NewArray([]Value{
+			nil,
+			NewStruct([]Value{NewInt(1)}),
+			NewEvent([]Value{NewInt(2)}).WithType(&EventType{
+				Location:            utils.TestLocation,
+				QualifiedIdentifier: "E",
+			}),
+		})
+
+		assert.Equal(t,
+			`[nil, (1), S.test.E(2)]`,
+			value.String(),
+		)
+	})
+}
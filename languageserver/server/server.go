@@ -189,6 +189,9 @@ type Server struct {
 	// initializationOptionsHandlers are the functions that are used to handle initialization options sent by the client
 	initializationOptionsHandlers []InitializationOptionsHandler
 	accessCheckMode               sema.AccessCheckMode
+	// diagnosticConfig is the configuration of the reported hints.
+	// The suppressions are parsed from each document
+	diagnosticConfig sema.DiagnosticConfig
 }
 
 type Option func(*Server) error
@@ -401,6 +404,58 @@ func (s *Server) configure(opts interface{}) {
 	} else {
 		s.accessCheckMode = sema.AccessCheckModeStrict
 	}
+
+	s.diagnosticConfig = sema.DiagnosticConfig{}
+
+	if disabled, ok := optsMap[disabledDiagnosticsOption].([]interface{}); ok {
+		s.diagnosticConfig.Disabled = map[string]bool{}
+		for _, code := range disabled {
+			if code, ok := code.(string); ok {
+				s.diagnosticConfig.Disabled[code] = true
+			}
+		}
+	}
+
+	if severities, ok := optsMap[diagnosticSeveritiesOption].(map[string]interface{}); ok {
+		s.diagnosticConfig.Severities = map[string]sema.DiagnosticSeverity{}
+		for code, severityName := range severities {
+			if severityName, ok := severityName.(string); ok {
+				severity := diagnosticSeverityFromName(severityName)
+				if severity != sema.DiagnosticSeverityUnknown {
+					s.diagnosticConfig.Severities[code] = severity
+				}
+			}
+		}
+	}
+}
+
+// disabledDiagnosticsOption is the option for the codes of the hints which are not reported,
+// e.g. `["UnnecessaryCastHint"]`
+//
+const disabledDiagnosticsOption = "disabledDiagnostics"
+
+// diagnosticSeveritiesOption is the option for the severities of hints,
+// e.g. `{"DeprecatedDeclarationHint": "warning"}`
+//
+const diagnosticSeveritiesOption = "diagnosticSeverities"
+
+func diagnosticSeverityFromName(name string) sema.DiagnosticSeverity {
+	switch name {
+	case "error":
+		return sema.DiagnosticSeverityError
+
+	case "warning":
+		return sema.DiagnosticSeverityWarning
+
+	case "information":
+		return sema.DiagnosticSeverityInformation
+
+	case "hint":
+		return sema.DiagnosticSeverityHint
+
+	default:
+		return sema.DiagnosticSeverityUnknown
+	}
 }
 
 // Registers the commands that the server is able to handle.
@@ -1691,6 +1746,9 @@ func (s *Server) getDiagnostics(
 		return
 	}
 
+	diagnosticConfig := s.diagnosticConfig
+	diagnosticConfig.Suppressions = parser2.ParseDiagnosticSuppressions(text)
+
 	var checker *sema.Checker
 	checker, diagnosticsErr = sema.NewChecker(
 		program,
@@ -1818,6 +1876,7 @@ func (s *Server) getDiagnostics(
 			},
 		),
 		sema.WithAccessCheckMode(s.accessCheckMode),
+		sema.WithDiagnosticConfig(diagnosticConfig),
 	)
 	if diagnosticsErr != nil {
 		return
@@ -1852,7 +1911,7 @@ func (s *Server) getDiagnostics(
 	}
 
 	for _, hint := range checker.Hints() {
-		diagnostic, codeActionsResolver := convertHint(hint, uri)
+		diagnostic, codeActionsResolver := convertHint(hint, uri, &diagnosticConfig)
 		if codeActionsResolver != nil {
 			codeActionsResolverID := uuid.New()
 			diagnostic.Data = codeActionsResolverID
@@ -2942,9 +3001,27 @@ func fieldDeclarationCodeActions(
 // convertHint converts a checker hint to a diagnostic
 // and an optional code action to resolve the hint.
 //
+// convertHintSeverity returns the protocol severity for the given severity of a hint
+//
+func convertHintSeverity(severity sema.DiagnosticSeverity) protocol.DiagnosticSeverity {
+	switch severity {
+	case sema.DiagnosticSeverityError:
+		return protocol.SeverityError
+
+	case sema.DiagnosticSeverityWarning:
+		return protocol.SeverityWarning
+
+	default:
+		// protocol.SeverityHint doesn't look prominent enough in VS Code,
+		// only the first character of the range is highlighted.
+		return protocol.SeverityInformation
+	}
+}
+
 func convertHint(
 	hint sema.Hint,
 	uri protocol.DocumentUri,
+	diagnosticConfig *sema.DiagnosticConfig,
 ) (
 	protocol.Diagnostic,
 	func() []*protocol.CodeAction,
@@ -2954,11 +3031,12 @@ func convertHint(
 
 	protocolRange := conversion.ASTToProtocolRange(startPosition, endPosition)
 
+	semaDiagnostic := sema.NewConfiguredHintDiagnostic(hint, nil, diagnosticConfig)
+
 	diagnostic := protocol.Diagnostic{
-		Message: hint.Hint(),
-		// protocol.SeverityHint doesn't look prominent enough in VS Code,
-		// only the first character of the range is highlighted.
-		Severity: protocol.SeverityInformation,
+		Message:  hint.Hint(),
+		Code:     semaDiagnostic.Code,
+		Severity: convertHintSeverity(semaDiagnostic.Severity),
 		Range:    protocolRange,
 	}
 
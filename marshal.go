@@ -0,0 +1,710 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// structTagKey is the key of the struct tag which maps
+// a Go struct field to a Cadence composite field, e.g.
+//
+//   type Deposit struct {
+//       Amount UFix64   `cadence:"amount"`
+//       To     *Address `cadence:"to"`
+//   }
+//
+// Fields without a tag are mapped to the Cadence field
+// with the name of the Go field, with the first letter lower-cased.
+// Fields with the tag `cadence:"-"` are ignored.
+//
+const structTagKey = "cadence"
+
+// CompositeTypeProvider is implemented by Go structs
+// which can be marshaled to Cadence composite values.
+//
+// The kind of the returned type determines the kind of the composite value,
+// e.g. an *EventType results in an Event.
+//
+// If the returned type declares fields, the Go struct must provide all of them,
+// and the values are marshaled in the declared order.
+// Otherwise, the fields are derived from the Go struct.
+//
+type CompositeTypeProvider interface {
+	CompositeType() CompositeType
+}
+
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+var compositeTypeProviderType = reflect.TypeOf((*CompositeTypeProvider)(nil)).Elem()
+var bigIntType = reflect.TypeOf(big.Int{})
+var bigIntPointerType = reflect.TypeOf(&big.Int{})
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Marshal converts the given Go value to a Cadence value.
+//
+// Booleans, strings, and integers are converted to the corresponding Cadence values,
+// e.g. an int8 is converted to an Int8, an int to an Int, and a *big.Int to an Int.
+// A time.Time is converted to a Timestamp, and a time.Duration to a Duration.
+// Pointers are converted to optionals, slices and arrays to arrays,
+// maps to dictionaries, and structs which implement CompositeTypeProvider to composites.
+// Cadence values are used as-is.
+//
+func Marshal(v interface{}) (Value, error) {
+	if v == nil {
+		return NewOptional(nil), nil
+	}
+
+	return marshal(reflect.ValueOf(v))
+}
+
+func marshal(v reflect.Value) (Value, error) {
+	t := v.Type()
+
+	switch t {
+	case bigIntPointerType:
+		if v.IsNil() {
+			return NewOptional(nil), nil
+		}
+		return NewIntFromBig(new(big.Int).Set(v.Interface().(*big.Int))), nil
+
+	case bigIntType:
+		i := v.Interface().(big.Int)
+		return NewIntFromBig(new(big.Int).Set(&i)), nil
+
+	case timeType:
+		return NewTimestamp(v.Interface().(time.Time)), nil
+
+	case durationType:
+		return NewDuration(time.Duration(v.Int())), nil
+	}
+
+	// NOTE: pointers to Cadence values also implement Value,
+	// but are converted to optionals, like all other pointers
+
+	if t.Kind() != reflect.Ptr && t.Implements(valueType) {
+		if t.Kind() == reflect.Interface && v.IsNil() {
+			return NewOptional(nil), nil
+		}
+		return v.Interface().(Value), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return NewBool(v.Bool()), nil
+
+	case reflect.String:
+		return NewString(v.String())
+
+	case reflect.Int:
+		return NewInt(int(v.Int())), nil
+	case reflect.Int8:
+		return NewInt8(int8(v.Int())), nil
+	case reflect.Int16:
+		return NewInt16(int16(v.Int())), nil
+	case reflect.Int32:
+		return NewInt32(int32(v.Int())), nil
+	case reflect.Int64:
+		return NewInt64(v.Int()), nil
+
+	case reflect.Uint:
+		return NewUInt(uint(v.Uint())), nil
+	case reflect.Uint8:
+		return NewUInt8(uint8(v.Uint())), nil
+	case reflect.Uint16:
+		return NewUInt16(uint16(v.Uint())), nil
+	case reflect.Uint32:
+		return NewUInt32(uint32(v.Uint())), nil
+	case reflect.Uint64:
+		return NewUInt64(v.Uint()), nil
+
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return NewOptional(nil), nil
+		}
+
+		value, err := marshal(v.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		if t.Kind() == reflect.Interface {
+			return value, nil
+		}
+
+		return NewOptional(value), nil
+
+	case reflect.Slice, reflect.Array:
+		values := make([]Value, v.Len())
+
+		for i := range values {
+			value, err := marshal(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+
+		return NewArray(values), nil
+
+	case reflect.Map:
+		pairs := make([]KeyValuePair, 0, v.Len())
+
+		iterator := v.MapRange()
+		for iterator.Next() {
+			key, err := marshal(iterator.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := marshal(iterator.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			pairs = append(pairs, KeyValuePair{
+				Key:   key,
+				Value: value,
+			})
+		}
+
+		// Go maps are unordered, so sort the pairs to get a deterministic result
+
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].Key.String() < pairs[j].Key.String()
+		})
+
+		return NewDictionary(pairs), nil
+
+	case reflect.Struct:
+		return marshalStruct(v)
+	}
+
+	return nil, fmt.Errorf("cannot marshal Go value of type %s", t)
+}
+
+func marshalStruct(v reflect.Value) (Value, error) {
+	var provider CompositeTypeProvider
+
+	switch {
+	case v.Type().Implements(compositeTypeProviderType):
+		provider = v.Interface().(CompositeTypeProvider)
+
+	case v.CanAddr() && v.Addr().Type().Implements(compositeTypeProviderType):
+		provider = v.Addr().Interface().(CompositeTypeProvider)
+
+	default:
+		return nil, fmt.Errorf(
+			"cannot marshal Go struct of type %s: type does not implement CompositeTypeProvider",
+			v.Type(),
+		)
+	}
+
+	compositeType := provider.CompositeType()
+	if compositeType == nil {
+		return nil, fmt.Errorf("cannot marshal Go struct of type %s: missing composite type", v.Type())
+	}
+
+	goFields := structFields(v.Type())
+
+	declaredFields := valueFields(compositeType.CompositeFields())
+
+	values := make([]Value, 0, len(goFields))
+
+	if len(declaredFields) == 0 {
+
+		// The composite type does not declare fields,
+		// so derive them from the Go struct
+
+		fields := make([]Field, 0, len(goFields))
+
+		for _, goField := range goFields {
+			value, err := marshal(v.Field(goField.index))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", goField.name, err)
+			}
+
+			values = append(values, value)
+			fields = append(fields, Field{
+				Identifier: goField.name,
+				Type:       value.Type(),
+			})
+		}
+
+		compositeType = compositeTypeWithFields(compositeType, fields)
+
+	} else {
+
+		goFieldIndices := make(map[string]int, len(goFields))
+		for _, goField := range goFields {
+			goFieldIndices[goField.name] = goField.index
+		}
+
+		for _, field := range declaredFields {
+			index, ok := goFieldIndices[field.Identifier]
+			if !ok {
+				return nil, fmt.Errorf(
+					"cannot marshal Go struct of type %s: missing field %s",
+					v.Type(),
+					field.Identifier,
+				)
+			}
+
+			value, err := marshal(v.Field(index))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Identifier, err)
+			}
+
+			values = append(values, value)
+		}
+	}
+
+	switch compositeType := compositeType.(type) {
+	case *StructType:
+		return NewStruct(values).WithType(compositeType), nil
+	case *ResourceType:
+		return NewResource(values).WithType(compositeType), nil
+	case *EventType:
+		return NewEvent(values).WithType(compositeType), nil
+	case *ContractType:
+		return NewContract(values).WithType(compositeType), nil
+	case *EnumType:
+		return NewEnum(values).WithType(compositeType), nil
+	}
+
+	return nil, fmt.Errorf(
+		"cannot marshal Go struct of type %s: unsupported composite type %T",
+		v.Type(),
+		compositeType,
+	)
+}
+
+// compositeTypeWithFields returns a copy of the given composite type
+// with the given fields
+//
+func compositeTypeWithFields(compositeType CompositeType, fields []Field) CompositeType {
+	switch compositeType := compositeType.(type) {
+	case *StructType:
+		result := *compositeType
+		result.Fields = fields
+		return &result
+	case *ResourceType:
+		result := *compositeType
+		result.Fields = fields
+		return &result
+	case *EventType:
+		result := *compositeType
+		result.Fields = fields
+		return &result
+	case *ContractType:
+		result := *compositeType
+		result.Fields = fields
+		return &result
+	case *EnumType:
+		result := *compositeType
+		result.Fields = fields
+		return &result
+	}
+
+	return compositeType
+}
+
+// Unmarshal converts the given Cadence value to a Go value,
+// and stores the result in the value pointed to by target.
+//
+// Unmarshal is the inverse of Marshal: booleans, strings, and integers are converted
+// to the corresponding Go values, as long as the Go type can represent the value.
+// Optionals are converted to pointers, arrays to slices or arrays, dictionaries to maps,
+// and composites to structs. The fields of a composite are mapped to the fields of the struct
+// using the struct tags, see structTagKey. Composite fields without a corresponding Go field
+// are ignored, and Go fields without a corresponding composite field are left unchanged.
+//
+// If the Cadence value is assignable to the Go value, e.g. a UFix64 to a UFix64,
+// or any value to an interface{}, it is stored as-is.
+//
+func Unmarshal(value Value, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot unmarshal into non-pointer or nil target of type %T", target)
+	}
+
+	return unmarshal(value, v.Elem())
+}
+
+func unmarshal(value Value, target reflect.Value) error {
+	targetType := target.Type()
+
+	if value != nil && reflect.TypeOf(value).AssignableTo(targetType) {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	if optional, ok := value.(Optional); ok {
+		if optional.Value == nil {
+			target.Set(reflect.Zero(targetType))
+			return nil
+		}
+
+		return unmarshal(optional.Value, target)
+	}
+
+	if targetType.Kind() == reflect.Ptr && targetType != bigIntPointerType {
+		result := reflect.New(targetType.Elem())
+
+		err := unmarshal(value, result.Elem())
+		if err != nil {
+			return err
+		}
+
+		target.Set(result)
+		return nil
+	}
+
+	switch value := value.(type) {
+	case Bool:
+		if targetType.Kind() == reflect.Bool {
+			target.SetBool(bool(value))
+			return nil
+		}
+
+	case String:
+		if targetType.Kind() == reflect.String {
+			target.SetString(string(value))
+			return nil
+		}
+
+	case Address:
+		// Addresses can be stored in byte arrays of the same length, e.g. flow.Address
+		valueType := reflect.TypeOf(value)
+		if targetType.Kind() == reflect.Array && valueType.ConvertibleTo(targetType) {
+			target.Set(reflect.ValueOf(value).Convert(targetType))
+			return nil
+		}
+
+	case Int, Int8, Int16, Int32, Int64, Int128, Int256,
+		UInt, UInt8, UInt16, UInt32, UInt64, UInt128, UInt256,
+		Word8, Word16, Word32, Word64:
+
+		return unmarshalInteger(value, integerValueToBig(value), target)
+
+	case Timestamp:
+		if targetType == timeType {
+			target.Set(reflect.ValueOf(value.Time()))
+			return nil
+		}
+
+	case Duration:
+		if targetType == durationType {
+			target.SetInt(int64(value))
+			return nil
+		}
+
+	case Array:
+		return unmarshalArray(value, target)
+
+	case Dictionary:
+		return unmarshalDictionary(value, target)
+
+	case Struct:
+		return unmarshalComposite(value, value.StructType, value.Fields, target)
+	case Resource:
+		return unmarshalComposite(value, value.ResourceType, value.Fields, target)
+	case Event:
+		return unmarshalComposite(value, value.EventType, value.Fields, target)
+	case Contract:
+		return unmarshalComposite(value, value.ContractType, value.Fields, target)
+	case Enum:
+		return unmarshalComposite(value, value.EnumType, value.Fields, target)
+	}
+
+	return unmarshalTypeError(value, targetType)
+}
+
+func unmarshalTypeError(value Value, targetType reflect.Type) error {
+	return fmt.Errorf("cannot unmarshal %T into Go value of type %s", value, targetType)
+}
+
+func integerValueToBig(value Value) *big.Int {
+	switch value := value.(type) {
+	case Int:
+		return value.Value
+	case Int8:
+		return big.NewInt(int64(value))
+	case Int16:
+		return big.NewInt(int64(value))
+	case Int32:
+		return big.NewInt(int64(value))
+	case Int64:
+		return big.NewInt(int64(value))
+	case Int128:
+		return value.Value
+	case Int256:
+		return value.Value
+	case UInt:
+		return value.Value
+	case UInt8:
+		return new(big.Int).SetUint64(uint64(value))
+	case UInt16:
+		return new(big.Int).SetUint64(uint64(value))
+	case UInt32:
+		return new(big.Int).SetUint64(uint64(value))
+	case UInt64:
+		return new(big.Int).SetUint64(uint64(value))
+	case UInt128:
+		return value.Value
+	case UInt256:
+		return value.Value
+	case Word8:
+		return new(big.Int).SetUint64(uint64(value))
+	case Word16:
+		return new(big.Int).SetUint64(uint64(value))
+	case Word32:
+		return new(big.Int).SetUint64(uint64(value))
+	case Word64:
+		return new(big.Int).SetUint64(uint64(value))
+	default:
+		panic(fmt.Errorf("not an integer value: %T", value))
+	}
+}
+
+func unmarshalInteger(value Value, i *big.Int, target reflect.Value) error {
+	targetType := target.Type()
+
+	switch targetType {
+	case bigIntPointerType:
+		target.Set(reflect.ValueOf(new(big.Int).Set(i)))
+		return nil
+
+	case bigIntType:
+		target.Set(reflect.ValueOf(*new(big.Int).Set(i)))
+		return nil
+	}
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !i.IsInt64() || target.OverflowInt(i.Int64()) {
+			return fmt.Errorf("cannot unmarshal %s into Go value of type %s: overflow", value, targetType)
+		}
+		target.SetInt(i.Int64())
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !i.IsUint64() || target.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("cannot unmarshal %s into Go value of type %s: overflow", value, targetType)
+		}
+		target.SetUint(i.Uint64())
+		return nil
+	}
+
+	return unmarshalTypeError(value, targetType)
+}
+
+func unmarshalArray(value Array, target reflect.Value) error {
+	targetType := target.Type()
+
+	switch targetType.Kind() {
+	case reflect.Slice:
+		result := reflect.MakeSlice(targetType, len(value.Values), len(value.Values))
+
+		for i, element := range value.Values {
+			err := unmarshal(element, result.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		target.Set(result)
+		return nil
+
+	case reflect.Array:
+		if targetType.Len() != len(value.Values) {
+			return fmt.Errorf(
+				"cannot unmarshal array of length %d into Go value of type %s",
+				len(value.Values),
+				targetType,
+			)
+		}
+
+		result := reflect.New(targetType).Elem()
+
+		for i, element := range value.Values {
+			err := unmarshal(element, result.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		target.Set(result)
+		return nil
+	}
+
+	return unmarshalTypeError(value, targetType)
+}
+
+func unmarshalDictionary(value Dictionary, target reflect.Value) error {
+	targetType := target.Type()
+
+	if targetType.Kind() != reflect.Map {
+		return unmarshalTypeError(value, targetType)
+	}
+
+	result := reflect.MakeMapWithSize(targetType, len(value.Pairs))
+
+	for _, pair := range value.Pairs {
+		key := reflect.New(targetType.Key()).Elem()
+		err := unmarshal(pair.Key, key)
+		if err != nil {
+			return err
+		}
+
+		element := reflect.New(targetType.Elem()).Elem()
+		err = unmarshal(pair.Value, element)
+		if err != nil {
+			return err
+		}
+
+		result.SetMapIndex(key, element)
+	}
+
+	target.Set(result)
+	return nil
+}
+
+func unmarshalComposite(value Value, compositeType CompositeType, fieldValues []Value, target reflect.Value) error {
+	targetType := target.Type()
+
+	if targetType.Kind() != reflect.Struct {
+		return unmarshalTypeError(value, targetType)
+	}
+
+	if compositeType == nil || reflect.ValueOf(compositeType).IsNil() {
+		return fmt.Errorf("cannot unmarshal %T without type into Go value of type %s", value, targetType)
+	}
+
+	fields := valueFields(compositeType.CompositeFields())
+	if len(fields) != len(fieldValues) {
+		return fmt.Errorf(
+			"cannot unmarshal %T: field count (%d) does not match declared type (%d)",
+			value,
+			len(fieldValues),
+			len(fields),
+		)
+	}
+
+	fieldValuesByName := make(map[string]Value, len(fields))
+	for i, field := range fields {
+		fieldValuesByName[field.Identifier] = fieldValues[i]
+	}
+
+	for _, goField := range structFields(targetType) {
+		fieldValue, ok := fieldValuesByName[goField.name]
+		if !ok {
+			continue
+		}
+
+		err := unmarshal(fieldValue, target.Field(goField.index))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", goField.name, err)
+		}
+	}
+
+	return nil
+}
+
+// valueFields returns the given fields without function fields
+//
+func valueFields(fields []Field) []Field {
+	result := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := field.Type.(FunctionType); ok {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+type structField struct {
+	name  string
+	index int
+	// optional is true if the field may be absent in the composite value,
+	// see structTagOptionOptional
+	optional bool
+}
+
+// structTagOptionOptional is the struct tag option
+// which declares that a field may be absent in a composite value, e.g.
+//
+//   Memo string `cadence:"memo,optional"`
+//
+// Unmarshal always leaves Go fields without a corresponding composite field unchanged,
+// but the option is relevant for choosing between versions of event types, see EventDecoders.
+//
+const structTagOptionOptional = "optional"
+
+func parseStructTag(tag string) (name string, options string) {
+	index := strings.IndexByte(tag, ',')
+	if index < 0 {
+		return tag, ""
+	}
+	return tag[:index], tag[index+1:]
+}
+
+// structFields returns the fields of the given Go struct type
+// which are mapped to Cadence composite fields
+//
+func structFields(t reflect.Type) []structField {
+	fields := make([]structField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// Only exported, non-embedded fields are mapped
+		if field.PkgPath != "" || field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get(structTagKey)
+		if tag == "-" {
+			continue
+		}
+
+		name, options := parseStructTag(tag)
+
+		if name == "" {
+			first, size := utf8.DecodeRuneInString(field.Name)
+			name = string(unicode.ToLower(first)) + field.Name[size:]
+		}
+
+		fields = append(fields, structField{
+			name:     name,
+			index:    i,
+			optional: options == structTagOptionOptional,
+		})
+	}
+
+	return fields
+}
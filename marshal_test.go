@@ -0,0 +1,365 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+var testDepositEventType = &EventType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "Deposit",
+	Fields: []Field{
+		{
+			Identifier: "amount",
+			Type:       UFix64Type{},
+		},
+		{
+			Identifier: "to",
+			Type:       OptionalType{Type: AddressType{}},
+		},
+		{
+			Identifier: "tags",
+			Type:       VariableSizedArrayType{ElementType: StringType{}},
+		},
+	},
+}
+
+type testDeposit struct {
+	Amount  UFix64   `cadence:"amount"`
+	To      *Address `cadence:"to"`
+	Labels  []string `cadence:"tags"`
+	Ignored int      `cadence:"-"`
+}
+
+func (testDeposit) CompositeType() CompositeType {
+	return testDepositEventType
+}
+
+type testPoint struct {
+	X      int8
+	Y      int8
+	hidden int
+}
+
+func (*testPoint) CompositeType() CompositeType {
+	return &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Point",
+	}
+}
+
+func TestMarshal(t *testing.T) {
+
+	t.Parallel()
+
+	address := BytesToAddress([]byte{1, 2})
+
+	tests := map[string]struct {
+		value    interface{}
+		expected Value
+	}{
+		"nil": {
+			value:    nil,
+			expected: NewOptional(nil),
+		},
+		"bool": {
+			value:    true,
+			expected: NewBool(true),
+		},
+		"string": {
+			value:    "foo",
+			expected: String("foo"),
+		},
+		"int": {
+			value:    42,
+			expected: NewInt(42),
+		},
+		"int8": {
+			value:    int8(-8),
+			expected: NewInt8(-8),
+		},
+		"int64": {
+			value:    int64(math.MinInt64),
+			expected: NewInt64(math.MinInt64),
+		},
+		"uint": {
+			value:    uint(42),
+			expected: NewUInt(42),
+		},
+		"uint64": {
+			value:    uint64(math.MaxUint64),
+			expected: NewUInt64(math.MaxUint64),
+		},
+		"big.Int": {
+			value:    big.NewInt(-42),
+			expected: NewInt(-42),
+		},
+		"time.Time": {
+			value:    time.Unix(1614834367, 500),
+			expected: Timestamp(1614834367000000500),
+		},
+		"time.Duration": {
+			value:    -1500 * time.Millisecond,
+			expected: Duration(-1500000000),
+		},
+		"Cadence value": {
+			value:    UFix64(100),
+			expected: UFix64(100),
+		},
+		"nil pointer": {
+			value:    (*string)(nil),
+			expected: NewOptional(nil),
+		},
+		"slice": {
+			value: []uint16{1, 2},
+			expected: NewArray([]Value{
+				NewUInt16(1),
+				NewUInt16(2),
+			}),
+		},
+		"array of interfaces": {
+			value: [2]interface{}{"a", nil},
+			expected: NewArray([]Value{
+				String("a"),
+				NewOptional(nil),
+			}),
+		},
+		"map": {
+			value: map[string]bool{"b": false, "a": true},
+			expected: NewDictionary([]KeyValuePair{
+				{Key: String("a"), Value: NewBool(true)},
+				{Key: String("b"), Value: NewBool(false)},
+			}),
+		},
+		"struct with declared fields": {
+			value: testDeposit{
+				Amount:  UFix64(150000000),
+				To:      &address,
+				Labels:  []string{"x"},
+				Ignored: 1,
+			},
+			expected: NewEvent([]Value{
+				UFix64(150000000),
+				NewOptional(address),
+				NewArray([]Value{String("x")}),
+			}).WithType(testDepositEventType),
+		},
+		"struct with derived fields": {
+			value: &testPoint{X: 1, Y: 2},
+			expected: NewOptional(
+				NewStruct([]Value{
+					NewInt8(1),
+					NewInt8(2),
+				}).WithType(&StructType{
+					Location:            utils.TestLocation,
+					QualifiedIdentifier: "Point",
+					Fields: []Field{
+						{Identifier: "x", Type: Int8Type{}},
+						{Identifier: "y", Type: Int8Type{}},
+					},
+				}),
+			),
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			actual, err := Marshal(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	for name, value := range map[string]interface{}{
+		"float":               1.5,
+		"struct without type": struct{ A int }{A: 1},
+		"channel":             make(chan int),
+		"nested":              []interface{}{1, 1.5},
+	} {
+		_, err := Marshal(value)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("event", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := BytesToAddress([]byte{1, 2})
+
+		event := NewEvent([]Value{
+			UFix64(150000000),
+			NewOptional(address),
+			NewArray([]Value{String("x"), String("y")}),
+		}).WithType(testDepositEventType)
+
+		actual := testDeposit{Ignored: 42}
+		err := Unmarshal(event, &actual)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			testDeposit{
+				Amount:  UFix64(150000000),
+				To:      &address,
+				Labels:  []string{"x", "y"},
+				Ignored: 42,
+			},
+			actual,
+		)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+
+		t.Parallel()
+
+		expected := &testPoint{X: 1, Y: -2}
+
+		value, err := Marshal(expected)
+		require.NoError(t, err)
+
+		var actual *testPoint
+		err = Unmarshal(value, &actual)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("integers", func(t *testing.T) {
+
+		t.Parallel()
+
+		var i8 int8
+		require.NoError(t, Unmarshal(NewInt(-128), &i8))
+		assert.Equal(t, int8(-128), i8)
+
+		var u uint
+		require.NoError(t, Unmarshal(NewWord64(math.MaxUint64), &u))
+		assert.Equal(t, uint(math.MaxUint64), u)
+
+		var b *big.Int
+		require.NoError(t, Unmarshal(UInt256{Value: big.NewInt(7)}, &b))
+		assert.Equal(t, big.NewInt(7), b)
+
+		// overflows
+
+		assert.Error(t, Unmarshal(NewInt(128), &i8))
+		assert.Error(t, Unmarshal(NewInt(-1), &u))
+	})
+
+	t.Run("time", func(t *testing.T) {
+
+		t.Parallel()
+
+		var timestamp time.Time
+		err := Unmarshal(Timestamp(1614834367000000500), &timestamp)
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(1614834367, 500).UTC(), timestamp)
+
+		var duration time.Duration
+		err = Unmarshal(Duration(-1500000000), &duration)
+		require.NoError(t, err)
+		assert.Equal(t, -1500*time.Millisecond, duration)
+
+		// timestamps and durations are not integers
+
+		var i int64
+		assert.Error(t, Unmarshal(Timestamp(1), &i))
+		assert.Error(t, Unmarshal(Duration(1), &i))
+	})
+
+	t.Run("collections", func(t *testing.T) {
+
+		t.Parallel()
+
+		var m map[string][]*int
+		err := Unmarshal(
+			NewDictionary([]KeyValuePair{
+				{
+					Key: String("a"),
+					Value: NewArray([]Value{
+						NewOptional(NewInt(1)),
+						NewOptional(nil),
+					}),
+				},
+			}),
+			&m,
+		)
+		require.NoError(t, err)
+
+		one := 1
+		assert.Equal(t, map[string][]*int{"a": {&one, nil}}, m)
+
+		var a [2]bool
+		require.NoError(t, Unmarshal(NewArray([]Value{NewBool(true), NewBool(false)}), &a))
+		assert.Equal(t, [2]bool{true, false}, a)
+
+		assert.Error(t, Unmarshal(NewArray([]Value{NewBool(true)}), &a))
+	})
+
+	t.Run("as-is", func(t *testing.T) {
+
+		t.Parallel()
+
+		var v interface{}
+		require.NoError(t, Unmarshal(NewInt(1), &v))
+		assert.Equal(t, NewInt(1), v)
+
+		var path Path
+		require.NoError(t, Unmarshal(Path{Domain: "public", Identifier: "foo"}, &path))
+		assert.Equal(t, Path{Domain: "public", Identifier: "foo"}, path)
+
+		var address [8]byte
+		require.NoError(t, Unmarshal(BytesToAddress([]byte{1}), &address))
+		assert.Equal(t, [8]byte{0, 0, 0, 0, 0, 0, 0, 1}, address)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		var s string
+		assert.Error(t, Unmarshal(NewInt(1), s))
+		assert.Error(t, Unmarshal(NewInt(1), &s))
+		assert.Error(t, Unmarshal(NewStruct(nil), &testPoint{}))
+	})
+}
@@ -0,0 +1,66 @@
+import {CadencePlayground} from "../src"
+import * as fs from "fs"
+
+async function createPlayground(): Promise<CadencePlayground> {
+  const binary = fs.readFileSync(require.resolve('../dist/cadence-playground.wasm'))
+  return await CadencePlayground.create(binary)
+}
+
+test("parse", async () => {
+  const playground = await createPlayground()
+  const res = playground.parse("pub fun main() {}")
+  expect(res.diagnostics).toEqual([])
+  expect(res.program.Declarations).toHaveLength(1)
+})
+
+test("check valid", async () => {
+  const playground = await createPlayground()
+  const res = playground.check("pub fun main(): UInt64 { return getCurrentBlock().height }")
+  expect(res).toEqual({
+    valid: true,
+    diagnostics: [],
+  })
+})
+
+test("check invalid", async () => {
+  const playground = await createPlayground()
+  const res = playground.check(`pub let x: Int = "1"`)
+  expect(res).toEqual({
+    valid: false,
+    diagnostics: [
+      {
+        message: "mismatched types",
+        secondaryMessage: "expected `Int`, got `String`",
+        severity: "error",
+        startPosition: {Offset: 17, Line: 1, Column: 17},
+        endPosition: {Offset: 19, Line: 1, Column: 19},
+      },
+    ],
+  })
+})
+
+test("check imports", async () => {
+  const playground = await createPlayground()
+  const res = playground.check(
+    `
+      import Hello from 0x1
+
+      pub fun main(): String {
+        return Hello.hello()
+      }
+    `,
+    {
+      "0x1": `
+        pub contract Hello {
+          pub fun hello(): String {
+            return "Hello"
+          }
+        }
+      `
+    }
+  )
+  expect(res).toEqual({
+    valid: true,
+    diagnostics: [],
+  })
+})
@@ -28,6 +28,7 @@ import (
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
 	"github.com/onflow/cadence/runtime/tests/utils"
@@ -766,6 +767,7 @@ var AccountKeyType = ExportedBuiltinType(sema.AccountKeyType).(*cadence.StructTy
 var PublicKeyType = ExportedBuiltinType(sema.PublicKeyType).(*cadence.StructType)
 var SignAlgoType = ExportedBuiltinType(sema.SignatureAlgorithmType).(*cadence.EnumType)
 var HashAlgoType = ExportedBuiltinType(sema.HashAlgorithmType).(*cadence.EnumType)
+var RoundingRuleType = ExportedBuiltinType(sema.RoundingRuleType).(*cadence.EnumType)
 
 func ExportedBuiltinType(internalType sema.Type) cadence.Type {
 	return ExportType(internalType, map[sema.TypeID]cadence.Type{})
@@ -1765,3 +1767,163 @@ func TestGetAuthAccount(t *testing.T) {
 		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
 	})
 }
+
+func TestRuntimeAuthAccountLinkAccount(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{42})
+
+	const linkTransaction = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              let capability = signer.linkAccount(/private/account)!
+              assert(signer.linkAccount(/private/account) == nil)
+          }
+      }
+    `
+
+	newRuntimeInterface := func(allowed bool, events *[]cadence.Event, loggedMessages *[]string) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			isAccountLinkingAllowed: func(_ Address) (bool, error) {
+				return allowed, nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				*events = append(*events, event)
+				return nil
+			},
+			log: func(message string) {
+				*loggedMessages = append(*loggedMessages, message)
+			},
+		}
+	}
+
+	t.Run("pragma not declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		rt := newTestInterpreterRuntime()
+
+		var events []cadence.Event
+		var loggedMessages []string
+		runtimeInterface := newRuntimeInterface(true, &events, &loggedMessages)
+
+		err := rt.ExecuteTransaction(
+			Script{
+				Source: []byte(linkTransaction),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  newTransactionLocationGenerator()(),
+			},
+		)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+		errs := checkerErr.Errors
+		require.Len(t, errs, 2)
+
+		for _, err := range errs {
+			assert.IsType(t, &sema.FeatureNotEnabledError{}, err)
+		}
+		assert.Empty(t, events)
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+
+		t.Parallel()
+
+		rt := newTestInterpreterRuntime()
+
+		var events []cadence.Event
+		var loggedMessages []string
+		runtimeInterface := newRuntimeInterface(false, &events, &loggedMessages)
+
+		err := rt.ExecuteTransaction(
+			Script{
+				Source: []byte("#allowAccountLinking\n" + linkTransaction),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  newTransactionLocationGenerator()(),
+			},
+		)
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.AccountLinkingNotAllowedError{})
+		assert.Empty(t, events)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+
+		t.Parallel()
+
+		rt := newTestInterpreterRuntime()
+
+		var events []cadence.Event
+		var loggedMessages []string
+		runtimeInterface := newRuntimeInterface(true, &events, &loggedMessages)
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err := rt.ExecuteTransaction(
+			Script{
+				Source: []byte("#allowAccountLinking\n" + linkTransaction),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, events, 1)
+		assert.EqualValues(t, stdlib.AccountLinkedEventType.ID(), events[0].Type().ID())
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewAddress(address),
+				cadence.Path{
+					Domain:     "private",
+					Identifier: "account",
+				},
+			},
+			events[0].Fields,
+		)
+
+		// Borrowing the account through the capability
+		// does not require the pragma
+
+		err = rt.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          let capability = signer.getCapability<&AuthAccount>(/private/account)
+                          assert(capability.check())
+                          log(capability.borrow()!.address)
+
+                          let anyCapability = signer.getCapability<&AnyStruct>(/private/account)
+                          assert(anyCapability.check())
+
+                          let invalidCapability = signer.getCapability<&PublicAccount>(/private/account)
+                          assert(!invalidCapability.check())
+                          assert(invalidCapability.borrow() == nil)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"0x000000000000002a"}, loggedMessages)
+	})
+}
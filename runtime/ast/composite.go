@@ -88,10 +88,13 @@ func (d *CompositeDeclaration) MarshalJSON() ([]byte, error) {
 
 type FieldDeclaration struct {
 	Access         Access
+	Entitlements   []*NominalType `json:",omitempty"`
 	VariableKind   VariableKind
 	Identifier     Identifier
 	TypeAnnotation *TypeAnnotation
-	DocString      string
+	// Value is the value of a constant field, e.g. `let x: Int = 1`, if any
+	Value     Expression `json:",omitempty"`
+	DocString string
 	Range
 }
 
@@ -99,9 +102,11 @@ func (d *FieldDeclaration) Accept(visitor Visitor) Repr {
 	return visitor.VisitFieldDeclaration(d)
 }
 
-func (d *FieldDeclaration) Walk(_ func(Element)) {
-	// NO-OP
+func (d *FieldDeclaration) Walk(walkChild func(Element)) {
 	// TODO: walk type
+	if d.Value != nil {
+		walkChild(d.Value)
+	}
 }
 
 func (*FieldDeclaration) isDeclaration() {}
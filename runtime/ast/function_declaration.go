@@ -26,6 +26,7 @@ import (
 
 type FunctionDeclaration struct {
 	Access               Access
+	Entitlements         []*NominalType `json:",omitempty"`
 	Identifier           Identifier
 	ParameterList        *ParameterList
 	ReturnTypeAnnotation *TypeAnnotation
@@ -28,6 +28,9 @@ import (
 
 type ImportDeclaration struct {
 	Identifiers []Identifier
+	// Aliases maps imported identifiers to the names they are declared as
+	// in the importing program, e.g. `import A as B from 0x1` maps `A` to `B`
+	Aliases     map[string]string `json:",omitempty"`
 	Location    common.Location
 	LocationPos Position
 	Range
@@ -56,6 +56,8 @@ type memberIndices struct {
 	_composites []*CompositeDeclaration
 	// Use `EnumCases()` instead
 	_enumCases []*EnumCaseDeclaration
+	// Use `Entitlements()` instead
+	_entitlements []*EntitlementDeclaration
 }
 
 func (i *memberIndices) FieldsByIdentifier(declarations []Declaration) map[string]*FieldDeclaration {
@@ -118,6 +120,11 @@ func (i *memberIndices) EnumCases(declarations []Declaration) []*EnumCaseDeclara
 	return i._enumCases
 }
 
+func (i *memberIndices) Entitlements(declarations []Declaration) []*EntitlementDeclaration {
+	i.once.Do(i.initializer(declarations))
+	return i._entitlements
+}
+
 func (i *memberIndices) initializer(declarations []Declaration) func() {
 	return func() {
 		i.init(declarations)
@@ -145,6 +152,8 @@ func (i *memberIndices) init(declarations []Declaration) {
 
 	i._enumCases = make([]*EnumCaseDeclaration, 0)
 
+	i._entitlements = make([]*EntitlementDeclaration, 0)
+
 	for _, declaration := range declarations {
 		switch declaration := declaration.(type) {
 		case *FieldDeclaration:
@@ -175,6 +184,9 @@ func (i *memberIndices) init(declarations []Declaration) {
 
 		case *EnumCaseDeclaration:
 			i._enumCases = append(i._enumCases, declaration)
+
+		case *EntitlementDeclaration:
+			i._entitlements = append(i._entitlements, declaration)
 		}
 	}
 }
@@ -0,0 +1,125 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"sync"
+)
+
+// NodeID identifies an element of a program, e.g. an expression or a declaration.
+//
+// IDs are assigned in depth-first order, starting at 1, see NodeIDs.
+// As they only depend on the structure of the program, the elements of a program
+// have the same IDs in all processes which parse the same code,
+// so tools can reference elements by ID instead of comparing positions.
+//
+// The zero ID is not assigned to any element.
+//
+type NodeID uint64
+
+// NodeIDs assigns IDs to the elements of a program, see NodeID
+//
+type NodeIDs struct {
+	ids map[Element]NodeID
+	// elements are the elements by ID. The element with ID 1 is at index 0
+	elements []Element
+}
+
+// NewNodeIDs assigns IDs to the given element and all its children,
+// in the order in which they are walked, see Walk.
+//
+// The conditions of function blocks are not walked,
+// so they are assigned IDs after the function block and before its statements
+//
+func NewNodeIDs(root Element) *NodeIDs {
+	nodeIDs := &NodeIDs{
+		ids: map[Element]NodeID{},
+	}
+
+	var assign func(element Element) bool
+	assign = func(element Element) bool {
+		if element == nil {
+			return false
+		}
+
+		nodeIDs.elements = append(nodeIDs.elements, element)
+		nodeIDs.ids[element] = NodeID(len(nodeIDs.elements))
+
+		if functionBlock, ok := element.(*FunctionBlock); ok {
+			for _, conditions := range []*Conditions{
+				functionBlock.PreConditions,
+				functionBlock.PostConditions,
+			} {
+				if conditions == nil {
+					continue
+				}
+
+				for _, condition := range *conditions {
+					Inspect(condition.Test, assign)
+					if condition.Message != nil {
+						Inspect(condition.Message, assign)
+					}
+				}
+			}
+		}
+
+		return true
+	}
+
+	Inspect(root, assign)
+
+	return nodeIDs
+}
+
+// ID returns the ID of the given element,
+// or false if the element has no ID, e.g. because it is not part of the program
+//
+func (n *NodeIDs) ID(element Element) (NodeID, bool) {
+	id, ok := n.ids[element]
+	return id, ok
+}
+
+// Element returns the element with the given ID, or nil if there is no such element
+//
+func (n *NodeIDs) Element(id NodeID) Element {
+	if id == 0 || uint64(id) > uint64(len(n.elements)) {
+		return nil
+	}
+	return n.elements[id-1]
+}
+
+// Count returns the number of elements which have an ID
+//
+func (n *NodeIDs) Count() int {
+	return len(n.elements)
+}
+
+// programNodeIDs lazily assigns the IDs of the elements of a program
+//
+type programNodeIDs struct {
+	once    sync.Once
+	nodeIDs *NodeIDs
+}
+
+func (i *programNodeIDs) get(program *Program) *NodeIDs {
+	i.once.Do(func() {
+		i.nodeIDs = NewNodeIDs(program)
+	})
+	return i.nodeIDs
+}
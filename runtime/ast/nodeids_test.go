@@ -0,0 +1,119 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeIDs(t *testing.T) {
+
+	t.Parallel()
+
+	// let x = 1 + y
+	//
+	// fun test() {
+	//     pre { x > 0 }
+	//     return
+	// }
+
+	one := &IntegerExpression{PositiveLiteral: "1"}
+	y := &IdentifierExpression{Identifier: Identifier{Identifier: "y"}}
+	sum := &BinaryExpression{
+		Operation: OperationPlus,
+		Left:      one,
+		Right:     y,
+	}
+	variableDeclaration := &VariableDeclaration{
+		Identifier: Identifier{Identifier: "x"},
+		Value:      sum,
+	}
+
+	x := &IdentifierExpression{Identifier: Identifier{Identifier: "x"}}
+	zero := &IntegerExpression{PositiveLiteral: "0"}
+	greater := &BinaryExpression{
+		Operation: OperationGreater,
+		Left:      x,
+		Right:     zero,
+	}
+	returnStatement := &ReturnStatement{}
+	block := &Block{
+		Statements: []Statement{returnStatement},
+	}
+	functionBlock := &FunctionBlock{
+		Block: block,
+		PreConditions: &Conditions{
+			{
+				Kind: ConditionKindPre,
+				Test: greater,
+			},
+		},
+	}
+	functionDeclaration := &FunctionDeclaration{
+		Identifier:    Identifier{Identifier: "test"},
+		FunctionBlock: functionBlock,
+	}
+
+	program := NewProgram([]Declaration{
+		variableDeclaration,
+		functionDeclaration,
+	})
+
+	nodeIDs := program.NodeIDs()
+
+	expected := []Element{
+		program,
+		variableDeclaration,
+		sum,
+		one,
+		y,
+		functionDeclaration,
+		functionBlock,
+		greater,
+		x,
+		zero,
+		block,
+		returnStatement,
+	}
+
+	require.Equal(t, len(expected), nodeIDs.Count())
+
+	for i, element := range expected {
+		expectedID := NodeID(i + 1)
+
+		id, ok := nodeIDs.ID(element)
+		require.True(t, ok)
+		assert.Equal(t, expectedID, id)
+
+		assert.Same(t, element, nodeIDs.Element(expectedID))
+	}
+
+	assert.Nil(t, nodeIDs.Element(0))
+	assert.Nil(t, nodeIDs.Element(NodeID(len(expected)+1)))
+
+	_, ok := nodeIDs.ID(&IntegerExpression{})
+	assert.False(t, ok)
+
+	// The IDs are only assigned once
+
+	assert.Same(t, nodeIDs, program.NodeIDs())
+}
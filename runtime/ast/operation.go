@@ -54,6 +54,8 @@ const (
 	OperationBitwiseAnd
 	OperationBitwiseLeftShift
 	OperationBitwiseRightShift
+	OperationRangeInclusive
+	OperationRangeExclusive
 )
 
 func OperationCount() int {
@@ -110,6 +112,10 @@ func (s Operation) Symbol() string {
 		return "<<"
 	case OperationBitwiseRightShift:
 		return ">>"
+	case OperationRangeInclusive:
+		return "..."
+	case OperationRangeExclusive:
+		return "..<"
 	}
 
 	panic(errors.NewUnreachableError())
@@ -33,11 +33,13 @@ func _() {
 	_ = x[OperationBitwiseAnd-22]
 	_ = x[OperationBitwiseLeftShift-23]
 	_ = x[OperationBitwiseRightShift-24]
+	_ = x[OperationRangeInclusive-25]
+	_ = x[OperationRangeExclusive-26]
 }
 
-const _Operation_name = "OperationUnknownOperationOrOperationAndOperationEqualOperationNotEqualOperationLessOperationGreaterOperationLessEqualOperationGreaterEqualOperationPlusOperationMinusOperationMulOperationDivOperationModOperationNegateOperationNilCoalesceOperationMoveOperationCastOperationFailableCastOperationForceCastOperationBitwiseOrOperationBitwiseXorOperationBitwiseAndOperationBitwiseLeftShiftOperationBitwiseRightShift"
+const _Operation_name = "OperationUnknownOperationOrOperationAndOperationEqualOperationNotEqualOperationLessOperationGreaterOperationLessEqualOperationGreaterEqualOperationPlusOperationMinusOperationMulOperationDivOperationModOperationNegateOperationNilCoalesceOperationMoveOperationCastOperationFailableCastOperationForceCastOperationBitwiseOrOperationBitwiseXorOperationBitwiseAndOperationBitwiseLeftShiftOperationBitwiseRightShiftOperationRangeInclusiveOperationRangeExclusive"
 
-var _Operation_index = [...]uint16{0, 16, 27, 39, 53, 70, 83, 99, 117, 138, 151, 165, 177, 189, 201, 216, 236, 249, 262, 283, 301, 319, 338, 357, 382, 408}
+var _Operation_index = [...]uint16{0, 16, 27, 39, 53, 70, 83, 99, 117, 138, 151, 165, 177, 189, 201, 216, 236, 249, 262, 283, 301, 319, 338, 357, 382, 408, 431, 454}
 
 func (i Operation) String() string {
 	if i >= Operation(len(_Operation_index)-1) {
@@ -22,6 +22,8 @@ type Parameter struct {
 	Label          string
 	Identifier     Identifier
 	TypeAnnotation *TypeAnnotation
+	// Indexed is true if the parameter of an event declaration is marked as indexed
+	Indexed bool `json:",omitempty"`
 	Range
 }
 
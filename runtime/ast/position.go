@@ -20,18 +20,29 @@ package ast
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
 // Position defines a row/column within a Cadence script.
+//
+// The offset is counted in bytes, the column is counted in Unicode code points.
+// Tools which need the column in another unit, e.g. in bytes or in UTF-16 code units
+// like the Language Server Protocol, should use ByteColumn or UTF16Column.
+//
 type Position struct {
-	// offset, starting at 0
+	// offset, starting at 0 (byte count)
 	Offset int
 	// line number, starting at 1
 	Line int
-	// column number, starting at 0 (byte count)
+	// column number, starting at 0 (code point count)
 	Column int
 }
 
+// Shifted returns the position shifted by the given length.
+// The length is added to both the offset and the column,
+// so the skipped code must neither contain multi-byte code points nor newlines
+//
 func (position Position) Shifted(length int) Position {
 	return Position{
 		Line:   position.Line,
@@ -60,6 +71,117 @@ func (position Position) Compare(other Position) int {
 	}
 }
 
+// lineOffsets returns the byte offset of the start of the line of the position,
+// and the byte offset of the position, both clamped to the given code
+//
+func (position Position) lineOffsets(code string) (lineStart int, offset int) {
+	offset = position.Offset
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(code) {
+		offset = len(code)
+	}
+
+	lineStart = strings.LastIndexByte(code[:offset], '\n') + 1
+
+	return lineStart, offset
+}
+
+// ByteColumn returns the column number of the position, counted in bytes.
+// The given code must be the code the position refers to
+//
+func (position Position) ByteColumn(code string) int {
+	lineStart, offset := position.lineOffsets(code)
+	return offset - lineStart
+}
+
+// UTF16Column returns the column number of the position, counted in UTF-16 code units,
+// as required by e.g. the Language Server Protocol.
+// The given code must be the code the position refers to
+//
+func (position Position) UTF16Column(code string) int {
+	lineStart, offset := position.lineOffsets(code)
+	return utf16Length(code[lineStart:offset])
+}
+
+// utf16Length returns the number of UTF-16 code units of the given string.
+// Invalid UTF-8 sequences are counted as one code unit per byte,
+// as they are decoded as the replacement character
+//
+func utf16Length(s string) int {
+	length := 0
+	for _, r := range s {
+		length += utf16RuneLength(r)
+	}
+	return length
+}
+
+// utf16RuneLength returns the number of UTF-16 code units of the given code point:
+// Code points outside of the basic multilingual plane are encoded as a surrogate pair
+//
+func utf16RuneLength(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// PositionAtOffset returns the position at the given byte offset in the given code
+//
+func PositionAtOffset(code string, offset int) Position {
+	position := Position{Offset: offset}
+	lineStart, offset := position.lineOffsets(code)
+
+	return Position{
+		Offset: offset,
+		Line:   strings.Count(code[:lineStart], "\n") + 1,
+		Column: utf8.RuneCountInString(code[lineStart:offset]),
+	}
+}
+
+// PositionAtUTF16Column returns the position at the given line and column in the given code,
+// where the line starts at 1, and the column is counted in UTF-16 code units, starting at 0.
+//
+// It is the inverse of UTF16Column, and can be used to convert
+// positions of the Language Server Protocol.
+// Lines and columns which are out of range are clamped to the code
+//
+func PositionAtUTF16Column(code string, line int, utf16Column int) Position {
+	lineStart := 0
+	for currentLine := 1; currentLine < line; currentLine++ {
+		index := strings.IndexByte(code[lineStart:], '\n')
+		if index < 0 {
+			break
+		}
+		lineStart += index + 1
+	}
+
+	lineEnd := len(code)
+	if index := strings.IndexByte(code[lineStart:], '\n'); index >= 0 {
+		lineEnd = lineStart + index
+	}
+
+	offset := lineStart
+	column := 0
+	length := 0
+
+	for offset < lineEnd {
+		r, size := utf8.DecodeRuneInString(code[offset:lineEnd])
+		if length >= utf16Column {
+			break
+		}
+		length += utf16RuneLength(r)
+		offset += size
+		column++
+	}
+
+	return Position{
+		Offset: offset,
+		Line:   strings.Count(code[:lineStart], "\n") + 1,
+		Column: column,
+	}
+}
+
 func EndPosition(startPosition Position, end int) Position {
 	length := end - startPosition.Offset
 	return startPosition.Shifted(length)
@@ -0,0 +1,139 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionColumns(t *testing.T) {
+
+	t.Parallel()
+
+	// "é" is 2 bytes and 1 UTF-16 code unit,
+	// "😀" is 4 bytes and 2 UTF-16 code units
+
+	const code = "let a = 1\nlet é = \"😀\" + b"
+
+	// position of `b`, the last character
+	position := Position{
+		Offset: len(code) - 1,
+		Line:   2,
+		Column: 14,
+	}
+
+	assert.Equal(t, 18, position.ByteColumn(code))
+	assert.Equal(t, 15, position.UTF16Column(code))
+
+	// position of `a` in the first line
+
+	position = Position{Offset: 4, Line: 1, Column: 4}
+
+	assert.Equal(t, 4, position.ByteColumn(code))
+	assert.Equal(t, 4, position.UTF16Column(code))
+
+	// positions out of range are clamped
+
+	position = Position{Offset: len(code) + 10, Line: 2, Column: 15}
+
+	assert.Equal(t, 19, position.ByteColumn(code))
+}
+
+func TestPositionAtOffset(t *testing.T) {
+
+	t.Parallel()
+
+	const code = "let a = 1\nlet é = \"😀\" + b"
+
+	assert.Equal(t,
+		Position{Offset: 0, Line: 1, Column: 0},
+		PositionAtOffset(code, 0),
+	)
+
+	assert.Equal(t,
+		Position{Offset: 10, Line: 2, Column: 0},
+		PositionAtOffset(code, 10),
+	)
+
+	assert.Equal(t,
+		Position{Offset: len(code) - 1, Line: 2, Column: 14},
+		PositionAtOffset(code, len(code)-1),
+	)
+}
+
+func TestPositionAtUTF16Column(t *testing.T) {
+
+	t.Parallel()
+
+	const code = "let a = 1\nlet é = \"😀\" + b"
+
+	assert.Equal(t,
+		Position{Offset: 4, Line: 1, Column: 4},
+		PositionAtUTF16Column(code, 1, 4),
+	)
+
+	// `b`
+
+	expected := PositionAtOffset(code, len(code)-1)
+	assert.Equal(t, expected, PositionAtUTF16Column(code, 2, 15))
+	assert.Equal(t, 15, expected.UTF16Column(code))
+
+	// the quote after the emoji
+
+	assert.Equal(t,
+		Position{Offset: 24, Line: 2, Column: 10},
+		PositionAtUTF16Column(code, 2, 11),
+	)
+
+	// column beyond the end of the line is clamped to the end of the line
+
+	assert.Equal(t,
+		Position{Offset: 9, Line: 1, Column: 9},
+		PositionAtUTF16Column(code, 1, 100),
+	)
+
+	// line beyond the end of the code is clamped to the last line
+
+	assert.Equal(t,
+		Position{Offset: 10, Line: 2, Column: 0},
+		PositionAtUTF16Column(code, 10, 0),
+	)
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	const code = "// ü\nlet x = \"𝔘𝔫𝔦\"\n"
+
+	// Converting the position at the start of each code point to a UTF-16 column and back
+	// must result in the same position
+
+	for offset := range code {
+		position := PositionAtOffset(code, offset)
+		utf16Column := position.UTF16Column(code)
+
+		assert.Equal(t,
+			position,
+			PositionAtUTF16Column(code, position.Line, utf16Column),
+		)
+	}
+}
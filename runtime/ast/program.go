@@ -28,6 +28,7 @@ type Program struct {
 	// all declarations, in the order they are defined
 	declarations []Declaration
 	indices      programIndices
+	nodeIDs      programNodeIDs
 }
 
 func NewProgram(declarations []Declaration) *Program {
@@ -65,6 +66,13 @@ func (d *Program) Walk(walkChild func(Element)) {
 	walkDeclarations(walkChild, d.declarations)
 }
 
+// NodeIDs returns the IDs of the elements of the program, see NodeID.
+// The IDs are assigned when they are first requested
+//
+func (p *Program) NodeIDs() *NodeIDs {
+	return p.nodeIDs.get(p)
+}
+
 func (p *Program) PragmaDeclarations() []*PragmaDeclaration {
 	return p.indices.pragmaDeclarations(p.declarations)
 }
@@ -77,6 +85,10 @@ func (p *Program) InterfaceDeclarations() []*InterfaceDeclaration {
 	return p.indices.interfaceDeclarations(p.declarations)
 }
 
+func (p *Program) EntitlementDeclarations() []*EntitlementDeclaration {
+	return p.indices.entitlementDeclarations(p.declarations)
+}
+
 func (p *Program) CompositeDeclarations() []*CompositeDeclaration {
 	return p.indices.compositeDeclarations(p.declarations)
 }
@@ -34,6 +34,8 @@ type programIndices struct {
 	_interfaceDeclarations []*InterfaceDeclaration
 	// Use `compositeDeclarations` instead
 	_compositeDeclarations []*CompositeDeclaration
+	// Use `entitlementDeclarations` instead
+	_entitlementDeclarations []*EntitlementDeclaration
 	// Use `functionDeclarations()` instead
 	_functionDeclarations []*FunctionDeclaration
 	// Use `transactionDeclarations()` instead
@@ -62,6 +64,11 @@ func (i *programIndices) compositeDeclarations(declarations []Declaration) []*Co
 	return i._compositeDeclarations
 }
 
+func (i *programIndices) entitlementDeclarations(declarations []Declaration) []*EntitlementDeclaration {
+	i.once.Do(i.initializer(declarations))
+	return i._entitlementDeclarations
+}
+
 func (i *programIndices) functionDeclarations(declarations []Declaration) []*FunctionDeclaration {
 	i.once.Do(i.initializer(declarations))
 	return i._functionDeclarations
@@ -91,6 +98,7 @@ func (i *programIndices) init(declarations []Declaration) {
 	i._importDeclarations = make([]*ImportDeclaration, 0)
 	i._compositeDeclarations = make([]*CompositeDeclaration, 0)
 	i._interfaceDeclarations = make([]*InterfaceDeclaration, 0)
+	i._entitlementDeclarations = make([]*EntitlementDeclaration, 0)
 	i._functionDeclarations = make([]*FunctionDeclaration, 0)
 	i._transactionDeclarations = make([]*TransactionDeclaration, 0)
 
@@ -109,6 +117,9 @@ func (i *programIndices) init(declarations []Declaration) {
 		case *InterfaceDeclaration:
 			i._interfaceDeclarations = append(i._interfaceDeclarations, declaration)
 
+		case *EntitlementDeclaration:
+			i._entitlementDeclarations = append(i._entitlementDeclarations, declaration)
+
 		case *FunctionDeclaration:
 			i._functionDeclarations = append(i._functionDeclarations, declaration)
 
@@ -0,0 +1,51 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+// DiagnosticSuppression is a suppression of diagnostics on a line of a program,
+// declared by a suppression comment, e.g. `// cadence-disable-next-line UnnecessaryCastHint`
+//
+type DiagnosticSuppression struct {
+	// Line is the line on which the diagnostics are suppressed
+	Line int
+	// Codes are the codes of the suppressed diagnostics, e.g. `UnnecessaryCastHint`.
+	// If empty, all diagnostics on the line are suppressed
+	Codes []string
+}
+
+// Suppresses returns true if the suppression applies to the diagnostic
+// with the given code on the given line
+//
+func (s DiagnosticSuppression) Suppresses(code string, line int) bool {
+	if s.Line != line {
+		return false
+	}
+
+	if len(s.Codes) == 0 {
+		return true
+	}
+
+	for _, suppressedCode := range s.Codes {
+		if suppressedCode == code {
+			return true
+		}
+	}
+
+	return false
+}
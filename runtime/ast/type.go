@@ -427,9 +427,10 @@ func (t *FunctionType) CheckEqual(other Type, checker TypeEqualityChecker) error
 // ReferenceType
 
 type ReferenceType struct {
-	Authorized bool
-	Type       Type     `json:"ReferencedType"`
-	StartPos   Position `json:"-"`
+	Authorized   bool
+	Entitlements []*NominalType `json:",omitempty"`
+	Type         Type           `json:"ReferencedType"`
+	StartPos     Position       `json:"-"`
 }
 
 var _ Type = &ReferenceType{}
@@ -439,7 +440,18 @@ func (*ReferenceType) isType() {}
 func (t *ReferenceType) String() string {
 	var builder strings.Builder
 	if t.Authorized {
-		builder.WriteString("auth ")
+		builder.WriteString("auth")
+		if len(t.Entitlements) > 0 {
+			builder.WriteRune('(')
+			for i, entitlement := range t.Entitlements {
+				if i > 0 {
+					builder.WriteString(", ")
+				}
+				builder.WriteString(entitlement.String())
+			}
+			builder.WriteRune(')')
+		}
+		builder.WriteRune(' ')
 	}
 	builder.WriteRune('&')
 	builder.WriteString(t.Type.String())
@@ -455,12 +467,29 @@ func (t *ReferenceType) EndPosition() Position {
 }
 
 const referenceTypeAuthKeywordSpaceDoc = prettier.Text("auth ")
+const referenceTypeAuthKeywordDoc = prettier.Text("auth")
+const referenceTypeEntitlementsStartDoc = prettier.Text("(")
+const referenceTypeEntitlementsEndDoc = prettier.Text(")")
+const referenceTypeEntitlementsSeparatorDoc = prettier.Text(", ")
 const referenceTypeSymbolDoc = prettier.Text("&")
 
 func (t *ReferenceType) Doc() prettier.Doc {
 	var doc prettier.Concat
 	if t.Authorized {
-		doc = append(doc, referenceTypeAuthKeywordSpaceDoc)
+		if len(t.Entitlements) == 0 {
+			doc = append(doc, referenceTypeAuthKeywordSpaceDoc)
+		} else {
+			doc = append(doc, referenceTypeAuthKeywordDoc)
+			doc = append(doc, referenceTypeEntitlementsStartDoc)
+			for i, entitlement := range t.Entitlements {
+				if i > 0 {
+					doc = append(doc, referenceTypeEntitlementsSeparatorDoc)
+				}
+				doc = append(doc, entitlement.Doc())
+			}
+			doc = append(doc, referenceTypeEntitlementsEndDoc)
+			doc = append(doc, prettier.Space)
+		}
 	}
 
 	return append(
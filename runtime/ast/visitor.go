@@ -93,6 +93,7 @@ type Visitor interface {
 	VisitFunctionBlock(*FunctionBlock) Repr
 	VisitCompositeDeclaration(*CompositeDeclaration) Repr
 	VisitInterfaceDeclaration(*InterfaceDeclaration) Repr
+	VisitEntitlementDeclaration(*EntitlementDeclaration) Repr
 	VisitFieldDeclaration(*FieldDeclaration) Repr
 	VisitEnumCaseDeclaration(*EnumCaseDeclaration) Repr
 	VisitPragmaDeclaration(*PragmaDeclaration) Repr
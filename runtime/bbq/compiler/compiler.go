@@ -0,0 +1,619 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/bbq"
+	"github.com/onflow/cadence/runtime/bbq/opcode"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Compiler lowers a checked program into bytecode.
+//
+// Only a subset of the language is supported so far:
+// functions declared at the top-level of the program,
+// which operate on integers, booleans, and strings.
+//
+type Compiler struct {
+	Program         *ast.Program
+	Elaboration     *sema.Elaboration
+	functionIndices map[string]uint16
+	constants       []sema.ConstantValue
+	currentFunction *function
+}
+
+// function is the state of the function which is currently compiled
+//
+type function struct {
+	name           string
+	code           []byte
+	parameterCount uint16
+	localCount     uint16
+	scopes         []map[string]uint16
+	loops          []*loop
+}
+
+// loop is the state of the loop which is currently compiled.
+// The jumps of break statements are patched once the end of the loop is known
+//
+type loop struct {
+	start  int
+	breaks []int
+}
+
+func NewCompiler(program *ast.Program, elaboration *sema.Elaboration) *Compiler {
+	return &Compiler{
+		Program:         program,
+		Elaboration:     elaboration,
+		functionIndices: map[string]uint16{},
+	}
+}
+
+// Compile compiles the given checked program.
+//
+// It returns an UnsupportedError if the program uses a feature
+// which is not supported by the compiler yet.
+//
+func Compile(program *ast.Program, elaboration *sema.Elaboration) (compiled *bbq.Program, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			unsupportedErr, ok := r.(*UnsupportedError)
+			if !ok {
+				panic(r)
+			}
+			err = unsupportedErr
+		}
+	}()
+
+	return NewCompiler(program, elaboration).Compile(), nil
+}
+
+// Compile compiles the program.
+//
+// It panics with an UnsupportedError if the program uses a feature
+// which is not supported by the compiler yet.
+//
+func (c *Compiler) Compile() *bbq.Program {
+
+	var functionDeclarations []*ast.FunctionDeclaration
+
+	for _, declaration := range c.Program.Declarations() {
+		switch declaration := declaration.(type) {
+		case *ast.FunctionDeclaration:
+			functionDeclarations = append(functionDeclarations, declaration)
+
+		case *ast.PragmaDeclaration:
+			continue
+
+		default:
+			c.unsupported(
+				fmt.Sprintf("%s declarations", declaration.DeclarationKind().Name()),
+				declaration,
+			)
+		}
+	}
+
+	// Declare all functions before compiling them,
+	// so functions can be invoked before they are declared
+
+	for index, declaration := range functionDeclarations {
+		if index > math.MaxUint16 {
+			c.unsupported("more than 65536 functions", declaration)
+		}
+		c.functionIndices[declaration.Identifier.Identifier] = uint16(index)
+	}
+
+	functions := make([]*bbq.Function, 0, len(functionDeclarations))
+
+	for _, declaration := range functionDeclarations {
+		functions = append(functions, c.compileFunction(declaration))
+	}
+
+	return &bbq.Program{
+		Functions: functions,
+		Constants: c.constants,
+	}
+}
+
+func (c *Compiler) compileFunction(declaration *ast.FunctionDeclaration) *bbq.Function {
+	functionType := c.Elaboration.FunctionDeclarationFunctionTypes[declaration]
+
+	functionBlock := declaration.FunctionBlock
+	if functionBlock == nil {
+		c.unsupported("functions without a body", declaration)
+	}
+
+	if (functionBlock.PreConditions != nil && len(*functionBlock.PreConditions) > 0) ||
+		(functionBlock.PostConditions != nil && len(*functionBlock.PostConditions) > 0) {
+
+		c.unsupported("function conditions", declaration)
+	}
+
+	returnType := functionType.ReturnTypeAnnotation.Type
+	if returnType != sema.VoidType && !isSupportedType(returnType) {
+		c.unsupported(
+			fmt.Sprintf("functions returning %s", returnType.QualifiedString()),
+			declaration,
+		)
+	}
+
+	c.currentFunction = &function{
+		name: declaration.Identifier.Identifier,
+	}
+	defer func() {
+		c.currentFunction = nil
+	}()
+
+	c.pushScope()
+
+	// The parameters are the first locals of the function
+
+	var parameterCount int
+
+	if declaration.ParameterList != nil {
+		parameters := declaration.ParameterList.Parameters
+		parameterCount = len(parameters)
+
+		for i, parameter := range parameters {
+			parameterType := functionType.Parameters[i].TypeAnnotation.Type
+			if !isSupportedType(parameterType) {
+				c.unsupported(
+					fmt.Sprintf("parameters of type %s", parameterType.QualifiedString()),
+					parameter,
+				)
+			}
+
+			c.declareLocal(parameter.Identifier.Identifier, parameter)
+		}
+	}
+
+	c.compileBlock(functionBlock.Block)
+
+	// Functions without a result may end without a return statement
+
+	c.emit(opcode.Return)
+
+	c.popScope()
+
+	if len(c.currentFunction.code) > math.MaxUint16 {
+		c.unsupported("functions with more than 65535 bytes of code", declaration)
+	}
+
+	return &bbq.Function{
+		Name:           c.currentFunction.name,
+		Code:           c.currentFunction.code,
+		ParameterCount: uint16(parameterCount),
+		LocalCount:     c.currentFunction.localCount,
+	}
+}
+
+func (c *Compiler) compileBlock(block *ast.Block) {
+	if block == nil {
+		return
+	}
+
+	c.pushScope()
+	defer c.popScope()
+
+	for _, statement := range block.Statements {
+		c.compileStatement(statement)
+	}
+}
+
+func (c *Compiler) compileStatement(statement ast.Statement) {
+	switch statement := statement.(type) {
+	case *ast.ReturnStatement:
+		if statement.Expression == nil {
+			c.emit(opcode.Return)
+		} else {
+			c.compileExpression(statement.Expression)
+			c.emit(opcode.ReturnValue)
+		}
+
+	case *ast.ExpressionStatement:
+		c.compileExpression(statement.Expression)
+		c.emit(opcode.Pop)
+
+	case *ast.VariableDeclaration:
+		c.compileVariableDeclaration(statement)
+
+	case *ast.AssignmentStatement:
+		c.compileAssignment(statement)
+
+	case *ast.IfStatement:
+		c.compileIfStatement(statement)
+
+	case *ast.WhileStatement:
+		c.compileWhileStatement(statement)
+
+	case *ast.BreakStatement:
+		currentLoop := c.currentLoop()
+		currentLoop.breaks = append(currentLoop.breaks, c.emitJump(opcode.Jump))
+
+	case *ast.ContinueStatement:
+		c.emit(opcode.Jump, uint16(c.currentLoop().start))
+
+	default:
+		c.unsupported(elementName(statement), statement)
+	}
+}
+
+func (c *Compiler) compileVariableDeclaration(declaration *ast.VariableDeclaration) {
+	if declaration.SecondValue != nil {
+		c.unsupported("variable declarations with a second value", declaration)
+	}
+
+	targetType := c.Elaboration.VariableDeclarationTargetTypes[declaration]
+	if !isSupportedType(targetType) {
+		c.unsupported(
+			fmt.Sprintf("variables of type %s", targetType.QualifiedString()),
+			declaration,
+		)
+	}
+
+	// Compile the value before declaring the local,
+	// as the value may refer to a shadowed variable with the same name
+
+	c.compileExpression(declaration.Value)
+
+	index := c.declareLocal(declaration.Identifier.Identifier, declaration)
+	c.emit(opcode.SetLocal, index)
+}
+
+func (c *Compiler) compileAssignment(assignment *ast.AssignmentStatement) {
+	target, ok := assignment.Target.(*ast.IdentifierExpression)
+	if !ok {
+		c.unsupported(
+			fmt.Sprintf("assignments to %s", elementName(assignment.Target)),
+			assignment,
+		)
+	}
+
+	index, ok := c.findLocal(target.Identifier.Identifier)
+	if !ok {
+		c.unsupported("assignments to global variables", assignment)
+	}
+
+	c.compileExpression(assignment.Value)
+	c.emit(opcode.SetLocal, index)
+}
+
+func (c *Compiler) compileIfStatement(statement *ast.IfStatement) {
+	test, ok := statement.Test.(ast.Expression)
+	if !ok {
+		c.unsupported("optional binding", statement)
+	}
+
+	c.compileExpression(test)
+	elseJump := c.emitJump(opcode.JumpIfFalse)
+
+	c.compileBlock(statement.Then)
+
+	if statement.Else == nil {
+		c.patchJump(elseJump)
+		return
+	}
+
+	endJump := c.emitJump(opcode.Jump)
+	c.patchJump(elseJump)
+
+	c.compileBlock(statement.Else)
+
+	c.patchJump(endJump)
+}
+
+func (c *Compiler) compileWhileStatement(statement *ast.WhileStatement) {
+	whileLoop := &loop{
+		start: len(c.currentFunction.code),
+	}
+
+	c.compileExpression(statement.Test)
+	endJump := c.emitJump(opcode.JumpIfFalse)
+
+	c.currentFunction.loops = append(c.currentFunction.loops, whileLoop)
+	c.compileBlock(statement.Block)
+	c.currentFunction.loops = c.currentFunction.loops[:len(c.currentFunction.loops)-1]
+
+	c.emit(opcode.Jump, uint16(whileLoop.start))
+
+	c.patchJump(endJump)
+	for _, breakJump := range whileLoop.breaks {
+		c.patchJump(breakJump)
+	}
+}
+
+func (c *Compiler) compileExpression(expression ast.Expression) {
+
+	// Constant expressions were already evaluated by the checker
+
+	if constant, ok := c.Elaboration.ConstantExpressionValues[expression]; ok {
+		c.emitConstant(constant, expression)
+		return
+	}
+
+	switch expression := expression.(type) {
+	case *ast.BoolExpression:
+		c.emitConstant(
+			sema.ConstantValue{
+				Type:  sema.BoolType,
+				Value: expression.Value,
+			},
+			expression,
+		)
+
+	case *ast.IntegerExpression:
+		integerType := c.Elaboration.IntegerExpressionType[expression]
+		if !isSupportedType(integerType) {
+			c.unsupported(
+				fmt.Sprintf("literals of type %s", integerType.QualifiedString()),
+				expression,
+			)
+		}
+
+		c.emitConstant(
+			sema.ConstantValue{
+				Type:  integerType,
+				Value: expression.Value,
+			},
+			expression,
+		)
+
+	case *ast.StringExpression:
+		c.emitConstant(
+			sema.ConstantValue{
+				Type:  sema.StringType,
+				Value: expression.Value,
+			},
+			expression,
+		)
+
+	case *ast.IdentifierExpression:
+		index, ok := c.findLocal(expression.Identifier.Identifier)
+		if !ok {
+			c.unsupported("references to global values", expression)
+		}
+		c.emit(opcode.GetLocal, index)
+
+	case *ast.InvocationExpression:
+		c.compileInvocation(expression)
+
+	case *ast.UnaryExpression:
+		c.compileUnaryExpression(expression)
+
+	case *ast.BinaryExpression:
+		c.compileBinaryExpression(expression)
+
+	default:
+		c.unsupported(elementName(expression), expression)
+	}
+}
+
+func (c *Compiler) compileInvocation(expression *ast.InvocationExpression) {
+	invokedExpression, ok := expression.InvokedExpression.(*ast.IdentifierExpression)
+	if !ok {
+		c.unsupported(
+			fmt.Sprintf("invocations of %s", elementName(expression.InvokedExpression)),
+			expression,
+		)
+	}
+
+	name := invokedExpression.Identifier.Identifier
+	index, ok := c.functionIndices[name]
+	if !ok {
+		c.unsupported(fmt.Sprintf("invocations of %s", name), expression)
+	}
+
+	if len(expression.TypeArguments) > 0 {
+		c.unsupported("type arguments", expression)
+	}
+
+	for _, argument := range expression.Arguments {
+		c.compileExpression(argument.Expression)
+	}
+
+	c.emit(opcode.Call, index)
+}
+
+func (c *Compiler) compileUnaryExpression(expression *ast.UnaryExpression) {
+	switch expression.Operation {
+	case ast.OperationMinus:
+		c.compileExpression(expression.Expression)
+		c.emit(opcode.Negate)
+
+	case ast.OperationNegate:
+		c.compileExpression(expression.Expression)
+		c.emit(opcode.Not)
+
+	default:
+		c.unsupported(
+			fmt.Sprintf("unary operation %s", expression.Operation.Symbol()),
+			expression,
+		)
+	}
+}
+
+var binaryOpcodes = map[ast.Operation]opcode.Opcode{
+	ast.OperationPlus:              opcode.Add,
+	ast.OperationMinus:             opcode.Subtract,
+	ast.OperationMul:               opcode.Multiply,
+	ast.OperationDiv:               opcode.Divide,
+	ast.OperationMod:               opcode.Mod,
+	ast.OperationBitwiseOr:         opcode.BitwiseOr,
+	ast.OperationBitwiseAnd:        opcode.BitwiseAnd,
+	ast.OperationBitwiseXor:        opcode.BitwiseXor,
+	ast.OperationBitwiseLeftShift:  opcode.BitwiseLeftShift,
+	ast.OperationBitwiseRightShift: opcode.BitwiseRightShift,
+	ast.OperationLess:              opcode.Less,
+	ast.OperationGreater:           opcode.Greater,
+	ast.OperationLessEqual:         opcode.LessOrEqual,
+	ast.OperationGreaterEqual:      opcode.GreaterOrEqual,
+	ast.OperationEqual:             opcode.Equal,
+	ast.OperationNotEqual:          opcode.NotEqual,
+}
+
+func (c *Compiler) compileBinaryExpression(expression *ast.BinaryExpression) {
+	switch expression.Operation {
+	case ast.OperationOr:
+		// Only evaluate the right-hand side if the left-hand side is false
+		c.compileExpression(expression.Left)
+		rightJump := c.emitJump(opcode.JumpIfFalse)
+		c.emit(opcode.True)
+		endJump := c.emitJump(opcode.Jump)
+		c.patchJump(rightJump)
+		c.compileExpression(expression.Right)
+		c.patchJump(endJump)
+
+	case ast.OperationAnd:
+		// Only evaluate the right-hand side if the left-hand side is true
+		c.compileExpression(expression.Left)
+		falseJump := c.emitJump(opcode.JumpIfFalse)
+		c.compileExpression(expression.Right)
+		endJump := c.emitJump(opcode.Jump)
+		c.patchJump(falseJump)
+		c.emit(opcode.False)
+		c.patchJump(endJump)
+
+	default:
+		op, ok := binaryOpcodes[expression.Operation]
+		if !ok {
+			c.unsupported(
+				fmt.Sprintf("binary operation %s", expression.Operation.Symbol()),
+				expression,
+			)
+		}
+
+		c.compileExpression(expression.Left)
+		c.compileExpression(expression.Right)
+		c.emit(op)
+	}
+}
+
+func (c *Compiler) emit(op opcode.Opcode, operands ...uint16) {
+	code := append(c.currentFunction.code, byte(op))
+	for _, operand := range operands {
+		code = append(code, byte(operand>>8), byte(operand))
+	}
+	c.currentFunction.code = code
+}
+
+// emitJump emits a jump with a yet unknown target,
+// and returns the offset of the target operand, which must be patched
+//
+func (c *Compiler) emitJump(op opcode.Opcode) int {
+	c.emit(op, 0)
+	return len(c.currentFunction.code) - 2
+}
+
+// patchJump sets the target of the jump with the given operand offset
+// to the current end of the code
+//
+func (c *Compiler) patchJump(operandOffset int) {
+	code := c.currentFunction.code
+	target := uint16(len(code))
+	code[operandOffset] = byte(target >> 8)
+	code[operandOffset+1] = byte(target)
+}
+
+func (c *Compiler) emitConstant(constant sema.ConstantValue, expression ast.Expression) {
+	if value, ok := constant.Value.(bool); ok {
+		if value {
+			c.emit(opcode.True)
+		} else {
+			c.emit(opcode.False)
+		}
+		return
+	}
+
+	index := len(c.constants)
+	if index > math.MaxUint16 {
+		c.unsupported("more than 65536 constants", expression)
+	}
+
+	c.constants = append(c.constants, constant)
+	c.emit(opcode.GetConstant, uint16(index))
+}
+
+func (c *Compiler) currentLoop() *loop {
+	loops := c.currentFunction.loops
+	return loops[len(loops)-1]
+}
+
+func (c *Compiler) pushScope() {
+	c.currentFunction.scopes = append(c.currentFunction.scopes, map[string]uint16{})
+}
+
+func (c *Compiler) popScope() {
+	scopes := c.currentFunction.scopes
+	c.currentFunction.scopes = scopes[:len(scopes)-1]
+}
+
+func (c *Compiler) declareLocal(name string, element ast.HasPosition) uint16 {
+	currentFunction := c.currentFunction
+	if currentFunction.localCount == math.MaxUint16 {
+		c.unsupported("more than 65535 locals", element)
+	}
+
+	index := currentFunction.localCount
+	currentFunction.localCount++
+
+	currentFunction.scopes[len(currentFunction.scopes)-1][name] = index
+
+	return index
+}
+
+func (c *Compiler) findLocal(name string) (uint16, bool) {
+	scopes := c.currentFunction.scopes
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if index, ok := scopes[i][name]; ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func (c *Compiler) unsupported(feature string, element ast.HasPosition) {
+	panic(&UnsupportedError{
+		Feature: feature,
+		Range:   ast.NewRangeFromPositioned(element),
+	})
+}
+
+// isSupportedType returns true if values of the given type can be compiled.
+// Only booleans, strings, and integers of a concrete integer type are supported
+//
+func isSupportedType(ty sema.Type) bool {
+	switch ty {
+	case sema.BoolType, sema.StringType:
+		return true
+
+	case sema.IntegerType, sema.SignedIntegerType:
+		return false
+	}
+
+	return sema.IsSubType(ty, sema.IntegerType)
+}
+
+func elementName(element ast.Element) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", element), "*ast.")
+}
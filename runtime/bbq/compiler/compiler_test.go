@@ -0,0 +1,131 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/bbq"
+	"github.com/onflow/cadence/runtime/bbq/opcode"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func TestCompileWhile(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun count(_ n: Int): Int {
+          var i = 0
+          while i < n {
+              i = i + 1
+          }
+          return i
+      }
+    `)
+	require.NoError(t, err)
+
+	program, err := Compile(checker.Program, checker.Elaboration)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		&bbq.Program{
+			Functions: []*bbq.Function{
+				{
+					Name: "count",
+					Code: []byte{
+						// var i = 0
+						byte(opcode.GetConstant), 0, 0,
+						byte(opcode.SetLocal), 0, 1,
+						// i < n
+						byte(opcode.GetLocal), 0, 1,
+						byte(opcode.GetLocal), 0, 0,
+						byte(opcode.Less),
+						byte(opcode.JumpIfFalse), 0, 29,
+						// i = i + 1
+						byte(opcode.GetLocal), 0, 1,
+						byte(opcode.GetConstant), 0, 1,
+						byte(opcode.Add),
+						byte(opcode.SetLocal), 0, 1,
+						byte(opcode.Jump), 0, 6,
+						// return i
+						byte(opcode.GetLocal), 0, 1,
+						byte(opcode.ReturnValue),
+						byte(opcode.Return),
+					},
+					ParameterCount: 1,
+					LocalCount:     2,
+				},
+			},
+			Constants: []sema.ConstantValue{
+				{
+					Type:  sema.IntType,
+					Value: big.NewInt(0),
+				},
+				{
+					Type:  sema.IntType,
+					Value: big.NewInt(1),
+				},
+			},
+		},
+		program,
+	)
+}
+
+func TestCompileUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	for name, code := range map[string]string{
+		"composite": `
+          struct S {}
+        `,
+		"for loop": `
+          fun test() {
+              for i in [1, 2] {}
+          }
+        `,
+		"optional type": `
+          fun test(_ x: Int?) {}
+        `,
+		"member access": `
+          fun test(): Int {
+              return "abc".length
+          }
+        `,
+	} {
+		code := code
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := checker.ParseAndCheck(t, code)
+			require.NoError(t, err)
+
+			_, err = Compile(checker.Program, checker.Elaboration)
+			require.ErrorAs(t, err, new(*UnsupportedError))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// UnsupportedError is returned by the compiler
+// when the program uses a feature which cannot be compiled yet.
+//
+// Programs which cannot be compiled can still be executed
+// by the interpreter.
+//
+type UnsupportedError struct {
+	Feature string
+	ast.Range
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("compiler does not support %s", e.Feature)
+}
@@ -0,0 +1,88 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opcode
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Opcode
+
+// Opcode is a single instruction of the bytecode executed by the VM.
+//
+// Operands follow the opcode directly in the code of a function.
+// Unless documented otherwise, an operand is a big-endian uint16.
+//
+type Opcode byte
+
+const (
+	Unknown Opcode = iota
+
+	// Return returns from the current function, without a result
+	Return
+	// ReturnValue returns from the current function, with the value on top of the stack as the result
+	ReturnValue
+	// Jump continues execution at the offset given by the operand
+	Jump
+	// JumpIfFalse pops a boolean from the stack, and if it is false,
+	// continues execution at the offset given by the operand
+	JumpIfFalse
+
+	// Arithmetic and bitwise operations
+
+	Add
+	Subtract
+	Multiply
+	Divide
+	Mod
+	Negate
+	BitwiseOr
+	BitwiseAnd
+	BitwiseXor
+	BitwiseLeftShift
+	BitwiseRightShift
+
+	// Comparison and logical operations
+
+	Less
+	Greater
+	LessOrEqual
+	GreaterOrEqual
+	Equal
+	NotEqual
+	Not
+
+	// Values
+
+	// True pushes the boolean `true`
+	True
+	// False pushes the boolean `false`
+	False
+	// GetConstant pushes the constant with the index given by the operand
+	GetConstant
+	// GetLocal pushes the local with the index given by the operand
+	GetLocal
+	// SetLocal pops a value from the stack and stores it in the local with the index given by the operand
+	SetLocal
+
+	// Invocations
+
+	// Call pops the arguments from the stack and invokes the function
+	// with the index given by the operand, then pushes the result
+	Call
+
+	// Pop removes the value on top of the stack
+	Pop
+)
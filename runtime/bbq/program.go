@@ -0,0 +1,48 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bbq contains the bytecode representation of Cadence programs,
+// which is produced by the compiler and executed by the VM.
+//
+package bbq
+
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Program is a compiled program.
+//
+// Functions and constants are referred to by their index,
+// e.g. by the operands of the `Call` and `GetConstant` instructions.
+//
+type Program struct {
+	Functions []*Function
+	Constants []sema.ConstantValue
+}
+
+// FunctionIndex returns the index of the function with the given name,
+// or -1 if the program has no such function
+//
+func (p *Program) FunctionIndex(name string) int {
+	for index, function := range p.Functions {
+		if function.Name == name {
+			return index
+		}
+	}
+	return -1
+}
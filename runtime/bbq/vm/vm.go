@@ -0,0 +1,366 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	goRuntime "runtime"
+
+	"github.com/onflow/cadence/runtime/bbq"
+	"github.com/onflow/cadence/runtime/bbq/opcode"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// VM is a stack-based virtual machine which executes compiled programs.
+//
+// Values are represented as interpreter values,
+// so the tree-walking interpreter can serve as the reference implementation.
+//
+type VM struct {
+	program     *bbq.Program
+	interpreter *interpreter.Interpreter
+	constants   []interpreter.Value
+	stack       []interpreter.Value
+	callFrames  []*callFrame
+}
+
+// callFrame is the state of a function invocation.
+// The locals of the function are stored on the stack, starting at the locals offset
+//
+type callFrame struct {
+	function     *bbq.Function
+	ip           int
+	localsOffset int
+}
+
+// NewVM returns a new VM for the given program.
+//
+// The interpreter is optional. If given, its handlers are notified
+// about loop iterations and function invocations, e.g. for metering.
+// The VM has no position information yet, so the reported lines are always 0.
+//
+func NewVM(program *bbq.Program, inter *interpreter.Interpreter) *VM {
+	constants := make([]interpreter.Value, len(program.Constants))
+	for i, constant := range program.Constants {
+		constants[i] = interpreter.NewConstantValue(constant)
+	}
+
+	return &VM{
+		program:     program,
+		interpreter: inter,
+		constants:   constants,
+	}
+}
+
+// Invoke invokes the function with the given name
+//
+func (vm *VM) Invoke(name string, arguments ...interpreter.Value) (value interpreter.Value, err error) {
+	index := vm.program.FunctionIndex(name)
+	if index < 0 {
+		return nil, interpreter.NotDeclaredError{
+			ExpectedKind: common.DeclarationKindFunction,
+			Name:         name,
+		}
+	}
+
+	function := vm.program.Functions[index]
+
+	if len(arguments) != int(function.ParameterCount) {
+		return nil, interpreter.ArgumentCountError{
+			ParameterCount: int(function.ParameterCount),
+			ArgumentCount:  len(arguments),
+		}
+	}
+
+	// Recover internal panics and return them as an error,
+	// e.g. overflows and division by zero
+
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case goRuntime.Error, interpreter.ExternalError:
+				// Don't recover Go's or external panics
+				panic(r)
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	vm.stack = append(vm.stack[:0], arguments...)
+	vm.callFrames = vm.callFrames[:0]
+
+	vm.pushCallFrame(function)
+
+	return vm.run(), nil
+}
+
+func (vm *VM) run() interpreter.Value {
+	frame := vm.callFrames[len(vm.callFrames)-1]
+
+	for {
+		code := frame.function.Code
+		op := opcode.Opcode(code[frame.ip])
+		frame.ip++
+
+		switch op {
+		case opcode.Return, opcode.ReturnValue:
+			var value interpreter.Value
+			if op == opcode.ReturnValue {
+				value = vm.pop()
+			} else {
+				value = interpreter.VoidValue{}
+			}
+
+			vm.popCallFrame()
+
+			if len(vm.callFrames) == 0 {
+				return value
+			}
+
+			vm.push(value)
+			frame = vm.callFrames[len(vm.callFrames)-1]
+
+		case opcode.Jump:
+			target := frame.readOperand()
+
+			// Jumping backwards is an iteration of a loop
+			if target < frame.ip {
+				vm.reportLoopIteration()
+			}
+
+			frame.ip = target
+
+		case opcode.JumpIfFalse:
+			target := frame.readOperand()
+			if !vm.pop().(interpreter.BoolValue) {
+				frame.ip = target
+			}
+
+		case opcode.Add:
+			right, left := vm.popNumbers()
+			vm.push(left.Plus(right))
+
+		case opcode.Subtract:
+			right, left := vm.popNumbers()
+			vm.push(left.Minus(right))
+
+		case opcode.Multiply:
+			right, left := vm.popNumbers()
+			vm.push(left.Mul(right))
+
+		case opcode.Divide:
+			right, left := vm.popNumbers()
+			vm.push(left.Div(right))
+
+		case opcode.Mod:
+			right, left := vm.popNumbers()
+			vm.push(left.Mod(right))
+
+		case opcode.Negate:
+			vm.push(vm.pop().(interpreter.NumberValue).Negate())
+
+		case opcode.BitwiseOr:
+			right, left := vm.popIntegers()
+			vm.push(left.BitwiseOr(right))
+
+		case opcode.BitwiseAnd:
+			right, left := vm.popIntegers()
+			vm.push(left.BitwiseAnd(right))
+
+		case opcode.BitwiseXor:
+			right, left := vm.popIntegers()
+			vm.push(left.BitwiseXor(right))
+
+		case opcode.BitwiseLeftShift:
+			right, left := vm.popIntegers()
+			vm.push(left.BitwiseLeftShift(right))
+
+		case opcode.BitwiseRightShift:
+			right, left := vm.popIntegers()
+			vm.push(left.BitwiseRightShift(right))
+
+		case opcode.Less:
+			right, left := vm.popNumbers()
+			vm.push(left.Less(right))
+
+		case opcode.Greater:
+			right, left := vm.popNumbers()
+			vm.push(left.Greater(right))
+
+		case opcode.LessOrEqual:
+			right, left := vm.popNumbers()
+			vm.push(left.LessEqual(right))
+
+		case opcode.GreaterOrEqual:
+			right, left := vm.popNumbers()
+			vm.push(left.GreaterEqual(right))
+
+		case opcode.Equal:
+			right := vm.pop()
+			left := vm.pop()
+			vm.push(vm.equal(left, right))
+
+		case opcode.NotEqual:
+			right := vm.pop()
+			left := vm.pop()
+			vm.push(!vm.equal(left, right))
+
+		case opcode.Not:
+			vm.push(vm.pop().(interpreter.BoolValue).Negate())
+
+		case opcode.True:
+			vm.push(interpreter.BoolValue(true))
+
+		case opcode.False:
+			vm.push(interpreter.BoolValue(false))
+
+		case opcode.GetConstant:
+			index := frame.readOperand()
+			vm.push(vm.constants[index])
+
+		case opcode.GetLocal:
+			index := frame.readOperand()
+			vm.push(vm.stack[frame.localsOffset+index])
+
+		case opcode.SetLocal:
+			index := frame.readOperand()
+			vm.stack[frame.localsOffset+index] = vm.pop()
+
+		case opcode.Call:
+			index := frame.readOperand()
+			vm.pushCallFrame(vm.program.Functions[index])
+			frame = vm.callFrames[len(vm.callFrames)-1]
+
+		case opcode.Pop:
+			vm.pop()
+
+		default:
+			panic(errors.NewUnreachableError())
+		}
+	}
+}
+
+// pushCallFrame starts the invocation of the given function.
+// The arguments must already be on the stack
+//
+func (vm *VM) pushCallFrame(function *bbq.Function) {
+	vm.reportFunctionInvocation()
+
+	parameterCount := int(function.ParameterCount)
+
+	vm.callFrames = append(
+		vm.callFrames,
+		&callFrame{
+			function:     function,
+			localsOffset: len(vm.stack) - parameterCount,
+		},
+	)
+
+	// Allocate the remaining locals
+
+	for i := parameterCount; i < int(function.LocalCount); i++ {
+		vm.push(nil)
+	}
+}
+
+// popCallFrame ends the current invocation and removes its locals from the stack
+//
+func (vm *VM) popCallFrame() {
+	lastIndex := len(vm.callFrames) - 1
+	frame := vm.callFrames[lastIndex]
+	vm.callFrames = vm.callFrames[:lastIndex]
+
+	vm.stack = vm.stack[:frame.localsOffset]
+
+	vm.reportInvokedFunctionReturn()
+}
+
+func (vm *VM) push(value interpreter.Value) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop() interpreter.Value {
+	lastIndex := len(vm.stack) - 1
+	value := vm.stack[lastIndex]
+	vm.stack[lastIndex] = nil
+	vm.stack = vm.stack[:lastIndex]
+	return value
+}
+
+// popNumbers pops the operands of a binary number operation,
+// and returns them in reverse order, i.e. the right operand first
+//
+func (vm *VM) popNumbers() (right, left interpreter.NumberValue) {
+	right = vm.pop().(interpreter.NumberValue)
+	left = vm.pop().(interpreter.NumberValue)
+	return
+}
+
+// popIntegers pops the operands of a binary integer operation,
+// and returns them in reverse order, i.e. the right operand first
+//
+func (vm *VM) popIntegers() (right, left interpreter.IntegerValue) {
+	right = vm.pop().(interpreter.IntegerValue)
+	left = vm.pop().(interpreter.IntegerValue)
+	return
+}
+
+func (vm *VM) equal(left, right interpreter.Value) interpreter.BoolValue {
+	return interpreter.BoolValue(
+		left.(interpreter.EquatableValue).Equal(
+			vm.interpreter,
+			interpreter.ReturnEmptyLocationRange,
+			right,
+		),
+	)
+}
+
+func (vm *VM) reportLoopIteration() {
+	if vm.interpreter == nil {
+		return
+	}
+	vm.interpreter.ReportLoopIteration(interpreter.LoopIteration{})
+}
+
+func (vm *VM) reportFunctionInvocation() {
+	if vm.interpreter == nil {
+		return
+	}
+	vm.interpreter.ReportFunctionInvocation(interpreter.FunctionInvocation{})
+}
+
+func (vm *VM) reportInvokedFunctionReturn() {
+	if vm.interpreter == nil {
+		return
+	}
+	vm.interpreter.ReportInvokedFunctionReturn(interpreter.FunctionInvocation{}, nil)
+}
+
+// readOperand reads the operand at the current instruction pointer
+//
+func (f *callFrame) readOperand() int {
+	code := f.function.Code
+	operand := int(code[f.ip])<<8 | int(code[f.ip+1])
+	f.ip += 2
+	return operand
+}
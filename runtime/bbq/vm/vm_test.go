@@ -0,0 +1,363 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/bbq"
+	"github.com/onflow/cadence/runtime/bbq/compiler"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func parseCheckAndCompile(t testing.TB, code string) (*sema.Checker, *bbq.Program) {
+	checker, err := checker.ParseAndCheckWithOptions(t, code, checker.ParseAndCheckOptions{})
+	require.NoError(t, err)
+
+	program, err := compiler.Compile(checker.Program, checker.Elaboration)
+	require.NoError(t, err)
+
+	return checker, program
+}
+
+func newInterpreter(t testing.TB, checker *sema.Checker, options ...interpreter.Option) *interpreter.Interpreter {
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		append(
+			[]interpreter.Option{
+				interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+			},
+			options...,
+		)...,
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	return inter
+}
+
+const recursiveFib = `
+  fun fib(_ n: Int): Int {
+      if n < 2 {
+          return n
+      }
+      return fib(n - 1) + fib(n - 2)
+  }
+`
+
+const imperativeFib = `
+  fun fib(_ n: Int): Int {
+      var fib1 = 1
+      var fib2 = 1
+      var fibonacci = fib1
+      var i = 2
+      while i < n {
+          fibonacci = fib1 + fib2
+          fib1 = fib2
+          fib2 = fibonacci
+          i = i + 1
+      }
+      return fibonacci
+  }
+`
+
+func TestVMFib(t *testing.T) {
+
+	t.Parallel()
+
+	for name, code := range map[string]string{
+		"recursive":  recursiveFib,
+		"imperative": imperativeFib,
+	} {
+		code := code
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, program := parseCheckAndCompile(t, code)
+
+			inter := newInterpreter(t, checker)
+			vm := NewVM(program, nil)
+
+			for n := 1; n <= 15; n++ {
+				argument := interpreter.NewIntValueFromInt64(int64(n))
+
+				expected, err := inter.Invoke("fib", argument)
+				require.NoError(t, err)
+
+				actual, err := vm.Invoke("fib", argument)
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, actual, fmt.Sprintf("fib(%d)", n))
+			}
+
+			value, err := vm.Invoke("fib", interpreter.NewIntValueFromInt64(15))
+			require.NoError(t, err)
+			assert.Equal(t, interpreter.NewIntValueFromInt64(610), value)
+		})
+	}
+}
+
+func TestVMLoopControl(t *testing.T) {
+
+	t.Parallel()
+
+	_, program := parseCheckAndCompile(t, `
+      fun test(): Int {
+          var i = 0
+          var sum = 0
+          while true {
+              i = i + 1
+              if i > 10 {
+                  break
+              }
+              if i % 2 == 0 {
+                  continue
+              }
+              sum = sum + i
+          }
+          return sum
+      }
+    `)
+
+	value, err := NewVM(program, nil).Invoke("test")
+	require.NoError(t, err)
+	assert.Equal(t, interpreter.NewIntValueFromInt64(25), value)
+}
+
+func TestVMShortCircuit(t *testing.T) {
+
+	t.Parallel()
+
+	_, program := parseCheckAndCompile(t, `
+      fun zero(): Int {
+          return 0
+      }
+
+      fun fail(): Bool {
+          return 1 / zero() == 0
+      }
+
+      fun and(): Bool {
+          return false && fail()
+      }
+
+      fun or(): Bool {
+          return true || fail()
+      }
+
+      fun both(_ a: Bool, _ b: Bool): Bool {
+          return a && b || !a && !b
+      }
+    `)
+
+	vm := NewVM(program, nil)
+
+	value, err := vm.Invoke("and")
+	require.NoError(t, err)
+	assert.Equal(t, interpreter.BoolValue(false), value)
+
+	value, err = vm.Invoke("or")
+	require.NoError(t, err)
+	assert.Equal(t, interpreter.BoolValue(true), value)
+
+	_, err = vm.Invoke("fail")
+	require.ErrorAs(t, err, &interpreter.DivisionByZeroError{})
+
+	for _, a := range []bool{true, false} {
+		for _, b := range []bool{true, false} {
+			value, err = vm.Invoke(
+				"both",
+				interpreter.BoolValue(a),
+				interpreter.BoolValue(b),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, interpreter.BoolValue(a == b), value)
+		}
+	}
+}
+
+func TestVMValues(t *testing.T) {
+
+	t.Parallel()
+
+	checker, program := parseCheckAndCompile(t, `
+      fun integers(): UInt8 {
+          let a: UInt8 = 200
+          let b = a / 3
+          return (b << 1) | 1 ^ (b & 7)
+      }
+
+      fun strings(_ s: String): Bool {
+          let t = "abc"
+          return s == t && s != "def"
+      }
+
+      fun constants(): Int {
+          return -(1 + 2 * 3)
+      }
+
+      fun void() {
+          let x = 1
+          if x > 0 {
+              return
+          }
+      }
+    `)
+
+	inter := newInterpreter(t, checker)
+	vm := NewVM(program, nil)
+
+	for _, invocation := range []struct {
+		name      string
+		arguments []interpreter.Value
+	}{
+		{name: "integers"},
+		{name: "strings", arguments: []interpreter.Value{interpreter.NewStringValue("abc")}},
+		{name: "strings", arguments: []interpreter.Value{interpreter.NewStringValue("def")}},
+		{name: "constants"},
+		{name: "void"},
+	} {
+		expected, err := inter.Invoke(invocation.name, invocation.arguments...)
+		require.NoError(t, err)
+
+		actual, err := vm.Invoke(invocation.name, invocation.arguments...)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual, invocation.name)
+	}
+}
+
+func TestVMOverflow(t *testing.T) {
+
+	t.Parallel()
+
+	_, program := parseCheckAndCompile(t, `
+      fun test(_ a: UInt8): UInt8 {
+          return a + 1
+      }
+    `)
+
+	_, err := NewVM(program, nil).Invoke("test", interpreter.UInt8Value(255))
+	require.ErrorAs(t, err, &interpreter.OverflowError{})
+}
+
+func TestVMInvokeErrors(t *testing.T) {
+
+	t.Parallel()
+
+	_, program := parseCheckAndCompile(t, `
+      fun test(_ a: Int): Int {
+          return a
+      }
+    `)
+
+	vm := NewVM(program, nil)
+
+	_, err := vm.Invoke("unknown")
+	require.ErrorAs(t, err, &interpreter.NotDeclaredError{})
+
+	_, err = vm.Invoke("test")
+	require.ErrorAs(t, err, &interpreter.ArgumentCountError{})
+}
+
+func TestVMHandlers(t *testing.T) {
+
+	t.Parallel()
+
+	checker, program := parseCheckAndCompile(t, `
+      fun count(_ n: Int): Int {
+          var i = 0
+          while i < n {
+              i = i + 1
+          }
+          return i
+      }
+
+      fun test(): Int {
+          return count(3) + count(4)
+      }
+    `)
+
+	var loopIterations, invocations, returns int
+
+	inter := newInterpreter(
+		t,
+		checker,
+		interpreter.WithOnLoopIterationHandler(func(_ *interpreter.Interpreter, _ interpreter.LoopIteration) {
+			loopIterations++
+		}),
+		interpreter.WithOnFunctionInvocationHandler(func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation) {
+			invocations++
+		}),
+		interpreter.WithOnInvokedFunctionReturnHandler(func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation, _ interpreter.Value) {
+			returns++
+		}),
+	)
+
+	value, err := NewVM(program, inter).Invoke("test")
+	require.NoError(t, err)
+	assert.Equal(t, interpreter.NewIntValueFromInt64(7), value)
+
+	assert.Equal(t, 7, loopIterations)
+	assert.Equal(t, 3, invocations)
+	assert.Equal(t, 3, returns)
+}
+
+func BenchmarkVMRecursionFib(b *testing.B) {
+
+	_, program := parseCheckAndCompile(b, recursiveFib)
+
+	vm := NewVM(program, nil)
+	argument := interpreter.NewIntValueFromInt64(14)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := vm.Invoke("fib", argument)
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkInterpreterRecursionFib(b *testing.B) {
+
+	checker, _ := parseCheckAndCompile(b, recursiveFib)
+
+	inter := newInterpreter(b, checker)
+	argument := interpreter.NewIntValueFromInt64(14)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := inter.Invoke("fib", argument)
+		require.NoError(b, err)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package benchmark runs Cadence programs repeatedly under metering,
+// and reports the computation used, the wall time, the allocations,
+// and the number of storage reads and writes.
+//
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	goRuntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+)
+
+// Result is the result of running a program a number of times.
+// All values are totals over all iterations.
+//
+type Result struct {
+	Iterations      int
+	ComputationUsed uint64
+	Duration        time.Duration
+	Allocations     uint64
+	AllocatedBytes  uint64
+	StorageReads    uint64
+	StorageWrites   uint64
+}
+
+func (r Result) perIteration(total uint64) float64 {
+	if r.Iterations == 0 {
+		return 0
+	}
+	return float64(total) / float64(r.Iterations)
+}
+
+// ComputationUsedPerIteration returns the average computation used by one iteration
+//
+func (r Result) ComputationUsedPerIteration() float64 {
+	return r.perIteration(r.ComputationUsed)
+}
+
+// DurationPerIteration returns the average wall time of one iteration
+//
+func (r Result) DurationPerIteration() time.Duration {
+	return time.Duration(r.perIteration(uint64(r.Duration)))
+}
+
+// AllocationsPerIteration returns the average number of allocations of one iteration
+//
+func (r Result) AllocationsPerIteration() float64 {
+	return r.perIteration(r.Allocations)
+}
+
+// StorageReadsPerIteration returns the average number of storage reads of one iteration
+//
+func (r Result) StorageReadsPerIteration() float64 {
+	return r.perIteration(r.StorageReads)
+}
+
+// StorageWritesPerIteration returns the average number of storage writes of one iteration
+//
+func (r Result) StorageWritesPerIteration() float64 {
+	return r.perIteration(r.StorageWrites)
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf(
+		"%d iterations, %.2f computation/op, %s/op, %.2f allocs/op, %.2f reads/op, %.2f writes/op",
+		r.Iterations,
+		r.ComputationUsedPerIteration(),
+		r.DurationPerIteration(),
+		r.AllocationsPerIteration(),
+		r.StorageReadsPerIteration(),
+		r.StorageWritesPerIteration(),
+	)
+}
+
+// Runner runs scripts against an in-memory environment.
+//
+// The environment can be prepared before running benchmarks,
+// e.g. by deploying the contracts that the benchmarked scripts import.
+//
+type Runner struct {
+	runtime     runtime.Runtime
+	environment *inmemory.Interface
+}
+
+// NewRunner returns a new runner for the given environment.
+// If the environment is nil, a new empty environment is used.
+//
+func NewRunner(environment *inmemory.Interface) *Runner {
+	if environment == nil {
+		environment = inmemory.NewInterface()
+	}
+
+	return &Runner{
+		runtime:     runtime.NewInterpreterRuntime(),
+		environment: environment,
+	}
+}
+
+// RunScript runs the given script with the given arguments the given number of times.
+//
+// The script is parsed and checked in every iteration, like it is when executed on-chain.
+//
+func (r *Runner) RunScript(code string, arguments []cadence.Value, iterations int) (Result, error) {
+	encodedArguments, err := encodeArguments(arguments)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Iterations: iterations,
+	}
+
+	for i := 0; i < iterations; i++ {
+		err := r.runScript(code, encodedArguments, &result)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// BenchmarkScript runs the given script with the given arguments b.N times,
+// and reports the computation used and the storage reads and writes
+// as custom metrics of the benchmark.
+//
+func (r *Runner) BenchmarkScript(b *testing.B, code string, arguments ...cadence.Value) {
+	encodedArguments, err := encodeArguments(arguments)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	result := Result{
+		Iterations: b.N,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := r.runScript(code, encodedArguments, &result)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+
+	b.ReportMetric(result.ComputationUsedPerIteration(), "computation/op")
+	b.ReportMetric(result.StorageReadsPerIteration(), "reads/op")
+	b.ReportMetric(result.StorageWritesPerIteration(), "writes/op")
+}
+
+func (r *Runner) runScript(code string, arguments [][]byte, result *Result) error {
+
+	// NOTE: metering is only enabled if a computation limit is set
+
+	previousLimit := r.environment.GetComputationLimit()
+	r.environment.SetComputationLimit(math.MaxUint64)
+	defer r.environment.SetComputationLimit(previousLimit)
+
+	runtimeInterface := &countingInterface{
+		Interface: r.environment,
+	}
+
+	var memStatsBefore, memStatsAfter goRuntime.MemStats
+	goRuntime.ReadMemStats(&memStatsBefore)
+
+	start := time.Now()
+
+	_, err := r.runtime.ExecuteScript(
+		runtime.Script{
+			Source:    []byte(code),
+			Arguments: arguments,
+		},
+		runtime.Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+
+	duration := time.Since(start)
+
+	goRuntime.ReadMemStats(&memStatsAfter)
+
+	if err != nil {
+		return err
+	}
+
+	result.ComputationUsed += r.environment.ComputationUsed()
+	result.Duration += duration
+	result.Allocations += memStatsAfter.Mallocs - memStatsBefore.Mallocs
+	result.AllocatedBytes += memStatsAfter.TotalAlloc - memStatsBefore.TotalAlloc
+	result.StorageReads += runtimeInterface.reads
+	result.StorageWrites += runtimeInterface.writes
+
+	return nil
+}
+
+func encodeArguments(arguments []cadence.Value) ([][]byte, error) {
+	encodedArguments := make([][]byte, len(arguments))
+	for i, argument := range arguments {
+		encodedArgument, err := json.Encode(argument)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode argument %d: %w", i, err)
+		}
+		encodedArguments[i] = encodedArgument
+	}
+	return encodedArguments, nil
+}
+
+// countingInterface counts the storage reads and writes
+// performed through the in-memory interface
+//
+type countingInterface struct {
+	*inmemory.Interface
+	reads  uint64
+	writes uint64
+}
+
+func (i *countingInterface) GetValue(owner, key []byte) (value []byte, err error) {
+	i.reads++
+	return i.Interface.GetValue(owner, key)
+}
+
+func (i *countingInterface) ValueExists(owner, key []byte) (exists bool, err error) {
+	i.reads++
+	return i.Interface.ValueExists(owner, key)
+}
+
+func (i *countingInterface) SetValue(owner, key, value []byte) (err error) {
+	i.writes++
+	return i.Interface.SetValue(owner, key, value)
+}
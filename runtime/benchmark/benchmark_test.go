@@ -0,0 +1,126 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+)
+
+const fibonacciScript = `
+  pub fun fib(_ n: Int): Int {
+      if n < 2 {
+          return n
+      }
+      return fib(n - 1) + fib(n - 2)
+  }
+
+  pub fun main(n: Int): Int {
+      return fib(n)
+  }
+`
+
+func TestRunScript(t *testing.T) {
+
+	t.Parallel()
+
+	runner := NewRunner(nil)
+
+	result, err := runner.RunScript(
+		fibonacciScript,
+		[]cadence.Value{cadence.NewInt(10)},
+		3,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Iterations)
+	assert.Greater(t, result.ComputationUsed, uint64(0))
+	assert.Equal(t, uint64(0), result.ComputationUsed%3)
+	assert.Greater(t, result.Duration.Nanoseconds(), int64(0))
+	assert.Greater(t, result.Allocations, uint64(0))
+	assert.Equal(t, uint64(0), result.StorageWrites)
+
+	// computation grows with the input
+
+	largerResult, err := runner.RunScript(
+		fibonacciScript,
+		[]cadence.Value{cadence.NewInt(12)},
+		3,
+	)
+	require.NoError(t, err)
+
+	assert.Greater(t, largerResult.ComputationUsed, result.ComputationUsed)
+}
+
+func TestRunScriptStorageReads(t *testing.T) {
+
+	t.Parallel()
+
+	environment := inmemory.NewInterface()
+
+	_, err := environment.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	runner := NewRunner(environment)
+
+	result, err := runner.RunScript(
+		`
+          pub fun main(): Bool {
+              return getAuthAccount(0x1).borrow<&Int>(from: /storage/value) == nil
+          }
+        `,
+		nil,
+		2,
+	)
+	require.NoError(t, err)
+
+	assert.Greater(t, result.StorageReads, uint64(0))
+
+	// the computation limit of the environment is not changed
+
+	assert.Equal(t, uint64(0), environment.GetComputationLimit())
+}
+
+func TestRunScriptError(t *testing.T) {
+
+	t.Parallel()
+
+	runner := NewRunner(nil)
+
+	_, err := runner.RunScript(
+		`
+          pub fun main() {
+              panic("broken")
+          }
+        `,
+		nil,
+		1,
+	)
+	require.Error(t, err)
+}
+
+func BenchmarkFibonacci(b *testing.B) {
+	NewRunner(nil).BenchmarkScript(b, fibonacciScript, cadence.NewInt(10))
+}
@@ -0,0 +1,216 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeCallSafe(t *testing.T) {
+
+	t.Parallel()
+
+	const oracleContract = `
+      pub contract Oracle {
+
+          pub event PriceRequested()
+
+          pub var requests: Int
+
+          init() {
+              self.requests = 0
+          }
+
+          pub fun price(available: Bool): Int {
+              emit PriceRequested()
+              if !available {
+                  panic("price unavailable")
+              }
+              return 42
+          }
+
+          pub fun countedPrice(available: Bool): Int {
+              self.requests = self.requests + 1
+              return self.price(available: available)
+          }
+      }
+    `
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	test := func(t *testing.T, transaction string) (
+		loggedMessages []string,
+		events []cadence.Event,
+		err error,
+	) {
+		runtime := NewInterpreterRuntime()
+
+		accountCodes := map[common.LocationID][]byte{}
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			resolveLocation: singleIdentifierLocationResolver(t),
+			getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+				location := common.AddressLocation{
+					Address: address,
+					Name:    name,
+				}
+				return accountCodes[location.ID()], nil
+			},
+			updateAccountContractCode: func(address Address, name string, code []byte) error {
+				location := common.AddressLocation{
+					Address: address,
+					Name:    name,
+				}
+				accountCodes[location.ID()] = code
+				return nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				events = append(events, event)
+				return nil
+			},
+			log: func(message string) {
+				loggedMessages = append(loggedMessages, message)
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err = runtime.ExecuteTransaction(
+			Script{
+				Source: utils.DeploymentTransaction("Oracle", []byte(oracleContract)),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		events = nil
+
+		err = runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(transaction),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+
+		return loggedMessages, events, err
+	}
+
+	t.Run("success", func(t *testing.T) {
+
+		t.Parallel()
+
+		loggedMessages, events, err := test(t, `
+          import Oracle from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(callSafe(fun (): Int {
+                      return Oracle.price(available: true)
+                  }))
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"42"}, loggedMessages)
+		require.Len(t, events, 1)
+	})
+
+	t.Run("failure without effects", func(t *testing.T) {
+
+		t.Parallel()
+
+		loggedMessages, events, err := test(t, `
+          import Oracle from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let price = callSafe(fun (): Int {
+                      return Oracle.price(available: false)
+                  })
+                  log(price ?? 0)
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"0"}, loggedMessages)
+		require.Empty(t, events)
+	})
+
+	t.Run("failure after effects", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, _, err := test(t, `
+          import Oracle from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let price = callSafe(fun (): Int {
+                      return Oracle.countedPrice(available: false)
+                  })
+                  log(price ?? 0)
+              }
+          }
+        `)
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+
+	t.Run("failure after storage write", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, _, err := test(t, `
+          import Oracle from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let price = callSafe(fun (): Int {
+                      signer.save(1, to: /storage/price)
+                      return Oracle.price(available: false)
+                  })
+                  log(price ?? 0)
+              }
+          }
+        `)
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+}
@@ -1,3 +1,6 @@
+//go:build !consensus_without_clock
+// +build !consensus_without_clock
+
 /*
  * Cadence - The resource-oriented smart contract programming language
  *
@@ -14,13 +17,19 @@
  * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
  * See the License for the specific language governing permissions and
  * limitations under the License.
- *
- * Based on https://github.com/wk8/go-ordered-map, Copyright Jean Rougé
- *
  */
 
-package orderedmap
+package main
+
+import (
+	"github.com/onflow/cadence/runtime"
+)
 
-//go:generate go run github.com/cheekybits/genny -pkg=orderedmap -in=orderedmap.go -out=orderedmap_string_string.go gen "KeyType=string ValueType=string"
-//go:generate go run github.com/cheekybits/genny -pkg=orderedmap -in=orderedmap.go -out=orderedmap_string_interface.go gen "KeyType=string ValueType=interface{}"
-//go:generate go run github.com/cheekybits/genny -pkg=orderedmap -in=orderedmap.go -out=orderedmap_string_struct.go gen "KeyType=string ValueType=struct{}"
+// Clock returns no clock, so the timestamp of the current block is used.
+//
+// Older versions of the runtime have no clock in the runtime interface,
+// so the harness is built with the tag `consensus_without_clock` for them, see compat/consensus.py
+//
+func (i *recordingInterface) Clock() runtime.Clock {
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Comparison is the result of comparing the reports of two versions of the runtime.
+//
+// Differences are changes in behavior which break consensus:
+// failures, results, events, logs, and the encoded state must be the same, byte-for-byte.
+//
+// Warnings are changes which do not affect consensus, e.g. changed error messages
+//
+type Comparison struct {
+	Differences []string
+	Warnings    []string
+}
+
+func (c *Comparison) difference(format string, args ...interface{}) {
+	c.Differences = append(c.Differences, fmt.Sprintf(format, args...))
+}
+
+func (c *Comparison) warning(format string, args ...interface{}) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Write writes the differences and warnings of the comparison
+//
+func (c *Comparison) Write(w io.Writer) error {
+	for _, warning := range c.Warnings {
+		_, err := fmt.Fprintf(w, "warning: %s\n", warning)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, difference := range c.Differences {
+		_, err := fmt.Fprintf(w, "difference: %s\n", difference)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(
+		w,
+		"%d difference(s), %d warning(s)\n",
+		len(c.Differences),
+		len(c.Warnings),
+	)
+	return err
+}
+
+// compareReports compares the report of the old version with the report of the new version
+//
+func compareReports(old, updated *Report) *Comparison {
+	comparison := &Comparison{}
+
+	newCases := map[string]CaseResult{}
+	for _, newCase := range updated.Cases {
+		newCases[newCase.Name] = newCase
+	}
+
+	for _, oldCase := range old.Cases {
+		newCase, ok := newCases[oldCase.Name]
+		if !ok {
+			comparison.difference("case %s: missing in new report", oldCase.Name)
+			continue
+		}
+		delete(newCases, oldCase.Name)
+
+		comparison.compareCases(oldCase, newCase)
+	}
+
+	// Report the remaining cases in the order of the new report
+
+	for _, newCase := range updated.Cases {
+		if _, ok := newCases[newCase.Name]; ok {
+			comparison.difference("case %s: missing in old report", newCase.Name)
+		}
+	}
+
+	return comparison
+}
+
+func (c *Comparison) compareCases(old, updated CaseResult) {
+	name := old.Name
+
+	if len(old.Steps) != len(updated.Steps) {
+		c.difference(
+			"case %s: number of steps differs: old: %d, new: %d",
+			name,
+			len(old.Steps),
+			len(updated.Steps),
+		)
+	} else {
+		for i, oldStep := range old.Steps {
+			c.compareSteps(fmt.Sprintf("case %s, step %d (%s)", name, i, oldStep.Path), oldStep, updated.Steps[i])
+		}
+	}
+
+	c.compareStates(fmt.Sprintf("case %s", name), old.State, updated.State)
+}
+
+func (c *Comparison) compareSteps(prefix string, old, updated StepResult) {
+	if old.Kind != updated.Kind || old.Path != updated.Path {
+		c.difference("%s: steps differ: old: %s %s, new: %s %s", prefix, old.Kind, old.Path, updated.Kind, updated.Path)
+		return
+	}
+
+	if old.Failed != updated.Failed {
+		c.difference(
+			"%s: failure differs: old: %s, new: %s",
+			prefix,
+			describeFailure(old),
+			describeFailure(updated),
+		)
+	} else if old.Error != updated.Error {
+		c.warning("%s: error message differs:\n  old: %s\n  new: %s", prefix, old.Error, updated.Error)
+	}
+
+	if !bytes.Equal(compactJSON(old.Value), compactJSON(updated.Value)) {
+		c.difference("%s: value differs:\n  old: %s\n  new: %s", prefix, old.Value, updated.Value)
+	}
+
+	if len(old.Events) != len(updated.Events) {
+		c.difference("%s: number of events differs: old: %d, new: %d", prefix, len(old.Events), len(updated.Events))
+	} else {
+		for i, oldEvent := range old.Events {
+			newEvent := updated.Events[i]
+			if !bytes.Equal(compactJSON(oldEvent), compactJSON(newEvent)) {
+				c.difference("%s: event %d differs:\n  old: %s\n  new: %s", prefix, i, oldEvent, newEvent)
+			}
+		}
+	}
+
+	if len(old.Logs) != len(updated.Logs) {
+		c.difference("%s: number of logs differs: old: %d, new: %d", prefix, len(old.Logs), len(updated.Logs))
+	} else {
+		for i, oldLog := range old.Logs {
+			newLog := updated.Logs[i]
+			if oldLog != newLog {
+				c.difference("%s: log %d differs:\n  old: %s\n  new: %s", prefix, i, oldLog, newLog)
+			}
+		}
+	}
+}
+
+func describeFailure(result StepResult) string {
+	if !result.Failed {
+		return "succeeded"
+	}
+	return fmt.Sprintf("failed: %s", result.Error)
+}
+
+func (c *Comparison) compareStates(prefix string, old, updated State) {
+
+	if old.UUIDs != updated.UUIDs {
+		c.difference("%s: number of generated UUIDs differs: old: %d, new: %d", prefix, old.UUIDs, updated.UUIDs)
+	}
+
+	type contractKey struct {
+		address string
+		name    string
+	}
+
+	newContracts := map[contractKey]string{}
+	for _, contract := range updated.Contracts {
+		newContracts[contractKey{contract.Address, contract.Name}] = contract.Code
+	}
+
+	for _, oldContract := range old.Contracts {
+		key := contractKey{oldContract.Address, oldContract.Name}
+		newCode, ok := newContracts[key]
+		if !ok {
+			c.difference("%s: contract %s.%s: missing in new state", prefix, key.address, key.name)
+			continue
+		}
+		delete(newContracts, key)
+
+		if newCode != oldContract.Code {
+			c.difference("%s: contract %s.%s: code differs", prefix, key.address, key.name)
+		}
+	}
+
+	for _, newContract := range updated.Contracts {
+		key := contractKey{newContract.Address, newContract.Name}
+		if _, ok := newContracts[key]; ok {
+			c.difference("%s: contract %s.%s: missing in old state", prefix, key.address, key.name)
+		}
+	}
+
+	type registerKey struct {
+		owner string
+		key   string
+	}
+
+	newRegisters := map[registerKey]string{}
+	for _, register := range updated.Registers {
+		newRegisters[registerKey{register.Owner, register.Key}] = register.Value
+	}
+
+	for _, oldRegister := range old.Registers {
+		key := registerKey{oldRegister.Owner, oldRegister.Key}
+		newValue, ok := newRegisters[key]
+		if !ok {
+			c.difference("%s: register %s/%s: missing in new state", prefix, key.owner, key.key)
+			continue
+		}
+		delete(newRegisters, key)
+
+		if newValue != oldRegister.Value {
+			c.difference(
+				"%s: register %s/%s: value differs:\n  old: %s\n  new: %s",
+				prefix,
+				key.owner,
+				key.key,
+				oldRegister.Value,
+				newValue,
+			)
+		}
+	}
+
+	for _, newRegister := range updated.Registers {
+		key := registerKey{newRegister.Owner, newRegister.Key}
+		if _, ok := newRegisters[key]; ok {
+			c.difference("%s: register %s/%s: missing in old state", prefix, key.owner, key.key)
+		}
+	}
+}
+
+// compactJSON returns the compact encoding of the given JSON,
+// so encodings which only differ in whitespace are considered equal
+//
+func compactJSON(data json.RawMessage) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	err := json.Compact(&buffer, data)
+	if err != nil {
+		return data
+	}
+	return buffer.Bytes()
+}
@@ -0,0 +1,247 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+const corpusDir = "../../../compat/consensus/corpus"
+
+func TestRunCorpus(t *testing.T) {
+
+	t.Parallel()
+
+	cases, err := loadCorpus(corpusDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, cases)
+
+	run := func() []byte {
+		report, err := runCorpus(cases)
+		require.NoError(t, err)
+		require.Len(t, report.Cases, len(cases))
+
+		data, err := json.Marshal(report)
+		require.NoError(t, err)
+		return data
+	}
+
+	// The report is deterministic
+
+	first := run()
+	second := run()
+	assert.Equal(t, string(first), string(second))
+
+	var report Report
+	err = json.Unmarshal(first, &report)
+	require.NoError(t, err)
+
+	comparison := compareReports(&report, &report)
+	assert.Empty(t, comparison.Differences)
+	assert.Empty(t, comparison.Warnings)
+}
+
+func TestRecordingInterfaceState(t *testing.T) {
+
+	t.Parallel()
+
+	cases, err := loadCorpus(corpusDir)
+	require.NoError(t, err)
+
+	for _, c := range cases {
+
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+
+			t.Parallel()
+
+			result, err := runCase(c)
+			require.NoError(t, err)
+
+			// Loading the state of a case and reporting it again results in the same state
+
+			runtimeInterface := newRecordingInterface()
+
+			addresses := make([]common.Address, 0, c.Accounts)
+			for i := 0; i < c.Accounts; i++ {
+				address, err := runtimeInterface.CreateAccount(common.Address{})
+				require.NoError(t, err)
+				addresses = append(addresses, address)
+			}
+
+			err = runtimeInterface.loadState(&result.State)
+			require.NoError(t, err)
+
+			state, err := runtimeInterface.state(addresses)
+			require.NoError(t, err)
+
+			assert.Equal(t, result.State, state)
+		})
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+
+	t.Parallel()
+
+	newReport := func() *Report {
+		return &Report{
+			Cases: []CaseResult{
+				{
+					Name: "test",
+					Steps: []StepResult{
+						{
+							Kind:   "transaction",
+							Path:   "test.cdc",
+							Failed: true,
+							Error:  "error: overflow",
+							Events: []json.RawMessage{
+								json.RawMessage(`{"type":"Event"}`),
+							},
+							Logs: []string{`"log"`},
+						},
+						{
+							Kind:   "script",
+							Path:   "test.cdc",
+							Value:  json.RawMessage(`{"type":"Int","value":"1"}`),
+							Events: []json.RawMessage{},
+							Logs:   []string{},
+						},
+					},
+					State: State{
+						Contracts: []Contract{
+							{
+								Address: "0x1",
+								Name:    "C",
+								Code:    "pub contract C {}",
+							},
+						},
+						Registers: []Register{
+							{
+								Owner: "0000000000000001",
+								Key:   "73746f72616765",
+								Value: "0000000000000001",
+							},
+						},
+						UUIDs: 1,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("equal", func(t *testing.T) {
+
+		t.Parallel()
+
+		comparison := compareReports(newReport(), newReport())
+		assert.Empty(t, comparison.Differences)
+		assert.Empty(t, comparison.Warnings)
+	})
+
+	t.Run("whitespace", func(t *testing.T) {
+
+		t.Parallel()
+
+		report := newReport()
+		report.Cases[0].Steps[1].Value = json.RawMessage(`{"type": "Int", "value": "1"}`)
+
+		comparison := compareReports(newReport(), report)
+		assert.Empty(t, comparison.Differences)
+	})
+
+	t.Run("error message", func(t *testing.T) {
+
+		t.Parallel()
+
+		report := newReport()
+		report.Cases[0].Steps[0].Error = "error: arithmetic overflow"
+
+		comparison := compareReports(newReport(), report)
+		assert.Empty(t, comparison.Differences)
+		assert.Len(t, comparison.Warnings, 1)
+	})
+
+	for name, modify := range map[string]func(report *Report){
+		"failure": func(report *Report) {
+			report.Cases[0].Steps[0].Failed = false
+		},
+		"value": func(report *Report) {
+			report.Cases[0].Steps[1].Value = json.RawMessage(`{"type":"Int","value":"2"}`)
+		},
+		"event": func(report *Report) {
+			report.Cases[0].Steps[0].Events[0] = json.RawMessage(`{"type":"Other"}`)
+		},
+		"events": func(report *Report) {
+			report.Cases[0].Steps[0].Events = nil
+		},
+		"log": func(report *Report) {
+			report.Cases[0].Steps[0].Logs[0] = `"other"`
+		},
+		"steps": func(report *Report) {
+			report.Cases[0].Steps = report.Cases[0].Steps[:1]
+		},
+		"contract code": func(report *Report) {
+			report.Cases[0].State.Contracts[0].Code = "pub contract C { pub let x: Int; init() { self.x = 1 } }"
+		},
+		"contract": func(report *Report) {
+			report.Cases[0].State.Contracts = nil
+		},
+		"register value": func(report *Report) {
+			report.Cases[0].State.Registers[0].Value = "0000000000000002"
+		},
+		"register": func(report *Report) {
+			report.Cases[0].State.Registers = append(
+				report.Cases[0].State.Registers,
+				Register{
+					Owner: "0000000000000001",
+					Key:   "7075626c6963",
+					Value: "0000000000000002",
+				},
+			)
+		},
+		"uuids": func(report *Report) {
+			report.Cases[0].State.UUIDs = 2
+		},
+		"case": func(report *Report) {
+			report.Cases[0].Name = "other"
+		},
+	} {
+
+		modify := modify
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			report := newReport()
+			modify(report)
+
+			assert.NotEmpty(t, compareReports(newReport(), report).Differences)
+			assert.NotEmpty(t, compareReports(report, newReport()).Differences)
+		})
+	}
+}
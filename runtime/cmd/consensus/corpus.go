@@ -0,0 +1,241 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// caseFileName is the name of the description file of a case in a corpus directory
+//
+const caseFileName = "case.json"
+
+// Case is a program of the corpus: a sequence of transactions, scripts, and contract deployments,
+// which are executed against a fresh state, optionally initialized from a state fixture.
+//
+// The programs of the steps and the fixture are given as paths relative to the case directory
+//
+type Case struct {
+	// Name is the name of the case directory
+	Name        string `json:"-"`
+	Description string `json:"description"`
+	// Accounts is the number of accounts created before the fixture is loaded,
+	// at the addresses 0x1, 0x2, etc.
+	Accounts int `json:"accounts"`
+	// Fixture is the path of the state fixture, if any
+	Fixture string `json:"fixture,omitempty"`
+	Steps   []Step `json:"steps"`
+
+	dir string
+}
+
+// Step is a transaction, a script, or a contract deployment of a case
+//
+type Step struct {
+	// Transaction is the path of the transaction program, if the step is a transaction
+	Transaction string `json:"transaction,omitempty"`
+	// Script is the path of the script program, if the step is a script
+	Script string `json:"script,omitempty"`
+	// Contract is the path of the contract program, if the step is a contract deployment.
+	// The contract is deployed to the account of the signer by a transaction,
+	// and its name is the file name without extension
+	Contract string `json:"contract,omitempty"`
+	// Update is true if the deployment updates an existing contract
+	Update bool `json:"update,omitempty"`
+	// Signers are the addresses of the signers of the transaction
+	Signers []string `json:"signers,omitempty"`
+	// Arguments are the JSON-CDC encoded arguments
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+}
+
+func (s Step) kind() string {
+	switch {
+	case s.Transaction != "":
+		return "transaction"
+	case s.Contract != "":
+		return "deployment"
+	default:
+		return "script"
+	}
+}
+
+func (s Step) path() string {
+	switch {
+	case s.Transaction != "":
+		return s.Transaction
+	case s.Contract != "":
+		return s.Contract
+	default:
+		return s.Script
+	}
+}
+
+// State is the state of the accounts after the execution of a case.
+// State fixtures have the same format, so the state of a case can be used as the fixture of another.
+//
+// Contracts and registers are sorted, so the encoding of the state is deterministic
+//
+type State struct {
+	Contracts []Contract `json:"contracts"`
+	Registers []Register `json:"registers"`
+	// UUIDs is the number of generated UUIDs
+	UUIDs uint64 `json:"uuids"`
+}
+
+// Contract is the code of a contract deployed to an account
+//
+type Contract struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+}
+
+// Register is a stored value. The owner, the key, and the value are hex-encoded
+//
+type Register struct {
+	Owner string `json:"owner"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (s *State) sort() {
+	sort.Slice(s.Contracts, func(i, j int) bool {
+		a := s.Contracts[i]
+		b := s.Contracts[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.Name < b.Name
+	})
+
+	sort.Slice(s.Registers, func(i, j int) bool {
+		a := s.Registers[i]
+		b := s.Registers[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Key < b.Key
+	})
+}
+
+// loadCorpus loads all cases of the corpus in the given directory,
+// i.e. all subdirectories which contain a case file, ordered by name
+//
+func loadCorpus(dir string) ([]*Case, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*Case
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		caseDir := filepath.Join(dir, entry.Name())
+
+		_, err := os.Stat(filepath.Join(caseDir, caseFileName))
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		c, err := loadCase(caseDir)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+func loadCase(dir string) (*Case, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, caseFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Case{
+		Name: filepath.Base(dir),
+		dir:  dir,
+	}
+
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load case %s: %w", c.Name, err)
+	}
+
+	for i, step := range c.Steps {
+		count := 0
+		for _, path := range []string{step.Transaction, step.Script, step.Contract} {
+			if path != "" {
+				count++
+			}
+		}
+
+		if count != 1 {
+			return nil, fmt.Errorf(
+				"invalid step %d of case %s: exactly one of transaction, script, and contract must be given",
+				i,
+				c.Name,
+			)
+		}
+
+		if step.Contract != "" && (len(step.Signers) != 1 || len(step.Arguments) > 0) {
+			return nil, fmt.Errorf(
+				"invalid step %d of case %s: a deployment must have exactly one signer and no arguments",
+				i,
+				c.Name,
+			)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Case) readFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(c.dir, path))
+}
+
+func (c *Case) loadFixture() (*State, error) {
+	if c.Fixture == "" {
+		return nil, nil
+	}
+
+	data, err := c.readFile(c.Fixture)
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixture of case %s: %w", c.Name, err)
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,502 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/onflow/atree"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// errNotSupported is returned by the functions of the runtime interface
+// which are not supported by the harness, e.g. cryptography and account keys.
+// Programs of the corpus which use them fail in all versions
+//
+var errNotSupported = errors.New("not supported by the consensus harness")
+
+type account struct {
+	contracts     map[string][]byte
+	contractNames []string
+}
+
+func (a *account) copy() *account {
+	contracts := make(map[string][]byte, len(a.contracts))
+	for name, code := range a.contracts { //nolint:maprangecheck
+		contracts[name] = code
+	}
+
+	return &account{
+		contracts:     contracts,
+		contractNames: append([]string(nil), a.contractNames...),
+	}
+}
+
+// recordingInterface is an in-memory runtime interface
+// which keeps all registers, so the state can be reported.
+//
+// The interface is part of the harness, and only uses the parts of the runtime API
+// which are available in all supported versions of the runtime,
+// so the harness can also be built against other versions of the runtime.
+// Parts of the runtime interface which are not available in all versions
+// are implemented in separate files with build tags, see compat/consensus.py
+//
+type recordingInterface struct {
+	registers       map[string][]byte
+	storageIndices  map[string]uint64
+	accounts        map[common.Address]*account
+	accountCount    uint64
+	signingAccounts []common.Address
+	events          []cadence.Event
+	logs            []string
+	// uuids is the number of generated UUIDs
+	uuids  uint64
+	random *rand.Rand
+}
+
+var _ runtime.Interface = &recordingInterface{}
+
+func newRecordingInterface() *recordingInterface {
+	return &recordingInterface{
+		registers:      map[string][]byte{},
+		storageIndices: map[string]uint64{},
+		accounts:       map[common.Address]*account{},
+		random:         rand.New(rand.NewSource(0)),
+	}
+}
+
+// SetSigningAccounts sets the accounts which sign the next transactions
+//
+func (i *recordingInterface) SetSigningAccounts(addresses []common.Address) {
+	i.signingAccounts = addresses
+}
+
+// Events returns the events emitted so far
+//
+func (i *recordingInterface) Events() []cadence.Event {
+	return i.events
+}
+
+// Logs returns the messages logged so far
+//
+func (i *recordingInterface) Logs() []string {
+	return i.logs
+}
+
+type recordingSnapshot struct {
+	registers       map[string][]byte
+	storageIndices  map[string]uint64
+	accounts        map[common.Address]*account
+	accountCount    uint64
+	signingAccounts []common.Address
+	events          []cadence.Event
+	logs            []string
+	uuids           uint64
+}
+
+// snapshot returns a copy of the current state.
+//
+// Registers are never modified in place, so only the maps are copied
+//
+func (i *recordingInterface) snapshot() recordingSnapshot {
+	return recordingSnapshot{
+		registers:       copyRegisters(i.registers),
+		storageIndices:  copyStorageIndices(i.storageIndices),
+		accounts:        copyAccounts(i.accounts),
+		accountCount:    i.accountCount,
+		signingAccounts: append([]common.Address(nil), i.signingAccounts...),
+		events:          append([]cadence.Event(nil), i.events...),
+		logs:            append([]string(nil), i.logs...),
+		uuids:           i.uuids,
+	}
+}
+
+// restore resets the state to the given snapshot
+//
+func (i *recordingInterface) restore(snapshot recordingSnapshot) {
+	i.registers = copyRegisters(snapshot.registers)
+	i.storageIndices = copyStorageIndices(snapshot.storageIndices)
+	i.accounts = copyAccounts(snapshot.accounts)
+	i.accountCount = snapshot.accountCount
+	i.signingAccounts = append([]common.Address(nil), snapshot.signingAccounts...)
+	i.events = append([]cadence.Event(nil), snapshot.events...)
+	i.logs = append([]string(nil), snapshot.logs...)
+	i.uuids = snapshot.uuids
+}
+
+func copyRegisters(registers map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(registers))
+	for key, value := range registers { //nolint:maprangecheck
+		result[key] = value
+	}
+	return result
+}
+
+func copyStorageIndices(storageIndices map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(storageIndices))
+	for owner, index := range storageIndices { //nolint:maprangecheck
+		result[owner] = index
+	}
+	return result
+}
+
+func copyAccounts(accounts map[common.Address]*account) map[common.Address]*account {
+	result := make(map[common.Address]*account, len(accounts))
+	for address, account := range accounts { //nolint:maprangecheck
+		result[address] = account.copy()
+	}
+	return result
+}
+
+func (i *recordingInterface) account(address common.Address) (*account, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s does not exist", address)
+	}
+	return account, nil
+}
+
+func (i *recordingInterface) ResolveLocation(
+	identifiers []runtime.Identifier,
+	location runtime.Location,
+) (
+	[]runtime.ResolvedLocation,
+	error,
+) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    location,
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	// if no identifiers were given, import all contracts of the account
+
+	if len(identifiers) == 0 {
+		names, err := i.GetAccountContractNames(addressLocation.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			identifiers = append(
+				identifiers,
+				runtime.Identifier{
+					Identifier: name,
+				},
+			)
+		}
+	}
+
+	resolvedLocations := make([]runtime.ResolvedLocation, len(identifiers))
+	for index, identifier := range identifiers {
+		resolvedLocations[index] = runtime.ResolvedLocation{
+			Location: common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			},
+			Identifiers: []runtime.Identifier{identifier},
+		}
+	}
+
+	return resolvedLocations, nil
+}
+
+func (i *recordingInterface) GetCode(location runtime.Location) ([]byte, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot get code of location %s", location)
+	}
+
+	return i.GetAccountContractCode(addressLocation.Address, addressLocation.Name)
+}
+
+// GetProgram returns no program, i.e. programs are always parsed and checked,
+// so the results do not depend on caching
+//
+func (i *recordingInterface) GetProgram(_ runtime.Location) (*interpreter.Program, error) {
+	return nil, nil
+}
+
+func (i *recordingInterface) SetProgram(_ runtime.Location, _ *interpreter.Program) error {
+	return nil
+}
+
+func registerKey(owner, key []byte) string {
+	return string(owner) + "|" + string(key)
+}
+
+func (i *recordingInterface) GetValue(owner, key []byte) ([]byte, error) {
+	return i.registers[registerKey(owner, key)], nil
+}
+
+func (i *recordingInterface) SetValue(owner, key, value []byte) error {
+	registerKey := registerKey(owner, key)
+	if len(value) == 0 {
+		delete(i.registers, registerKey)
+	} else {
+		// copy the value, as the caller may reuse the slice,
+		// and snapshots share registers
+		i.registers[registerKey] = append([]byte(nil), value...)
+	}
+	return nil
+}
+
+func (i *recordingInterface) ValueExists(owner, key []byte) (bool, error) {
+	return len(i.registers[registerKey(owner, key)]) > 0, nil
+}
+
+func (i *recordingInterface) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := i.storageIndices[string(owner)] + 1
+	i.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+// CreateAccount creates an account at the next address, i.e. at 0x1, 0x2, etc.
+//
+func (i *recordingInterface) CreateAccount(_ runtime.Address) (address runtime.Address, err error) {
+	i.accountCount++
+	binary.BigEndian.PutUint64(address[:], i.accountCount)
+
+	i.accounts[address] = &account{
+		contracts: map[string][]byte{},
+	}
+
+	return address, nil
+}
+
+func (i *recordingInterface) AddEncodedAccountKey(_ runtime.Address, _ []byte) error {
+	return errNotSupported
+}
+
+func (i *recordingInterface) RevokeEncodedAccountKey(_ runtime.Address, _ int) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) AddAccountKey(
+	_ runtime.Address,
+	_ *runtime.PublicKey,
+	_ runtime.HashAlgorithm,
+	_ int,
+) (*runtime.AccountKey, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) GetAccountKey(_ runtime.Address, _ int) (*runtime.AccountKey, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) RevokeAccountKey(_ runtime.Address, _ int) (*runtime.AccountKey, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) UpdateAccountContractCode(address runtime.Address, name string, code []byte) error {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := account.contracts[name]; !ok {
+		account.contractNames = append(account.contractNames, name)
+	}
+
+	account.contracts[name] = code
+
+	return nil
+}
+
+func (i *recordingInterface) GetAccountContractCode(address runtime.Address, name string) ([]byte, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, nil
+	}
+	return account.contracts[name], nil
+}
+
+func (i *recordingInterface) RemoveAccountContractCode(address runtime.Address, name string) error {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+
+	delete(account.contracts, name)
+
+	for index, contractName := range account.contractNames {
+		if contractName == name {
+			account.contractNames = append(account.contractNames[:index:index], account.contractNames[index+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (i *recordingInterface) GetAccountContractNames(address runtime.Address) ([]string, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, nil
+	}
+
+	names := make([]string, len(account.contractNames))
+	copy(names, account.contractNames)
+	return names, nil
+}
+
+func (i *recordingInterface) GetSigningAccounts() ([]runtime.Address, error) {
+	return i.signingAccounts, nil
+}
+
+func (i *recordingInterface) ProgramLog(message string) error {
+	i.logs = append(i.logs, message)
+	return nil
+}
+
+func (i *recordingInterface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *recordingInterface) GenerateUUID() (uint64, error) {
+	uuid := i.uuids
+	i.uuids++
+	return uuid, nil
+}
+
+// GetComputationLimit returns no limit, as the computation used by the programs
+// is not part of the results which must be the same in all versions
+//
+func (i *recordingInterface) GetComputationLimit() uint64 {
+	return 0
+}
+
+func (i *recordingInterface) SetComputationUsed(_ uint64) error {
+	return nil
+}
+
+func (i *recordingInterface) DecodeArgument(argument []byte, _ cadence.Type) (cadence.Value, error) {
+	return jsoncdc.Decode(argument)
+}
+
+// GetCurrentBlockHeight returns the height of the only block, the genesis block
+//
+func (i *recordingInterface) GetCurrentBlockHeight() (uint64, error) {
+	return 0, nil
+}
+
+func (i *recordingInterface) GetBlockAtHeight(height uint64) (runtime.Block, bool, error) {
+	if height > 0 {
+		return runtime.Block{}, false, nil
+	}
+	return runtime.Block{}, true, nil
+}
+
+func (i *recordingInterface) UnsafeRandom() (uint64, error) {
+	return i.random.Uint64(), nil
+}
+
+func (i *recordingInterface) VerifySignature(
+	_ []byte,
+	_ string,
+	_ []byte,
+	_ []byte,
+	_ runtime.SignatureAlgorithm,
+	_ runtime.HashAlgorithm,
+) (bool, error) {
+	return false, errNotSupported
+}
+
+func (i *recordingInterface) Hash(_ []byte, _ string, _ runtime.HashAlgorithm) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) GetAccountBalance(_ common.Address) (uint64, error) {
+	return 0, errNotSupported
+}
+
+func (i *recordingInterface) GetAccountAvailableBalance(_ common.Address) (uint64, error) {
+	return 0, errNotSupported
+}
+
+func (i *recordingInterface) GetStorageUsed(address runtime.Address) (value uint64, err error) {
+	prefix := string(address[:]) + "|"
+	for key, register := range i.registers { //nolint:maprangecheck
+		if strings.HasPrefix(key, prefix) {
+			value += uint64(len(key) - len(prefix) + len(register))
+		}
+	}
+	return value, nil
+}
+
+func (i *recordingInterface) GetStorageCapacity(_ runtime.Address) (uint64, error) {
+	return 0, errNotSupported
+}
+
+func (i *recordingInterface) ImplementationDebugLog(_ string) error {
+	return nil
+}
+
+func (i *recordingInterface) ValidatePublicKey(_ *runtime.PublicKey) (bool, error) {
+	return false, errNotSupported
+}
+
+func (i *recordingInterface) RecordTrace(_ string, _ common.Location, _ time.Duration, _ []opentracing.LogRecord) {
+	// NO-OP
+}
+
+func (i *recordingInterface) BLSVerifyPOP(_ *runtime.PublicKey, _ []byte) (bool, error) {
+	return false, errNotSupported
+}
+
+func (i *recordingInterface) AggregateBLSSignatures(_ [][]byte) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) AggregateBLSPublicKeys(_ []*runtime.PublicKey) (*runtime.PublicKey, error) {
+	return nil, errNotSupported
+}
+
+func (i *recordingInterface) ResourceOwnerChanged(
+	_ *interpreter.CompositeValue,
+	_ common.Address,
+	_ common.Address,
+) {
+	// NO-OP
+}
+
+// IsAccountLinkingAllowed is only called by versions of the runtime which support account linking.
+// Accounts may not be linked, so the programs of the corpus which link accounts fail in all versions
+//
+func (i *recordingInterface) IsAccountLinkingAllowed(_ runtime.Address) (bool, error) {
+	return false, nil
+}
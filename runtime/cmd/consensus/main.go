@@ -0,0 +1,137 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+// This command is the harness of the consensus compatibility suite, see compat/README.md:
+//
+// The run mode executes a corpus of programs and state fixtures
+// and writes a report of the results, events, logs, and encoded state.
+//
+// The compare mode compares the reports of two versions of the runtime,
+// and fails if any of them differ, i.e. if a change in behavior would break consensus.
+//
+// The harness is self-contained, it has its own in-memory implementation of the runtime interface,
+// and only depends on the runtime API which is available in all supported versions,
+// so the same source can be built against other versions of the runtime, see compat/consensus.py
+
+var outputFlag = flag.String("output", "", "write the report to the given path. Standard output by default")
+
+func main() {
+	flag.Usage = func() {
+		output := flag.CommandLine.Output()
+		_, _ = fmt.Fprintf(output, "usage: %s [-output path] run <corpus directory>\n", os.Args[0])
+		_, _ = fmt.Fprintf(output, "       %s compare <old report> <new report>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "run":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		run(flag.Arg(1))
+
+	case "compare":
+		if flag.NArg() != 3 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		compare(flag.Arg(1), flag.Arg(2))
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func run(corpusDir string) {
+	cases, err := loadCorpus(corpusDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := runCorpus(cases)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	if *outputFlag == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = ioutil.WriteFile(*outputFlag, data, 0644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func compare(oldPath, newPath string) {
+	oldReport, err := loadReport(oldPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	newReport, err := loadReport(newPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	comparison := compareReports(oldReport, newReport)
+
+	err = comparison.Write(os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(comparison.Differences) > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadReport(path string) (*Report, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	err = json.Unmarshal(data, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report %s: %w", path, err)
+	}
+
+	return &report, nil
+}
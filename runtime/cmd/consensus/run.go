@@ -0,0 +1,392 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// Report is the result of running a corpus
+//
+type Report struct {
+	Cases []CaseResult `json:"cases"`
+}
+
+// CaseResult is the result of running a case:
+// the results of all steps, and the state of the accounts afterwards
+//
+type CaseResult struct {
+	Name  string       `json:"name"`
+	Steps []StepResult `json:"steps"`
+	State State        `json:"state"`
+}
+
+// StepResult is the result of executing a transaction, a script, or a contract deployment.
+//
+// The value is the JSON-CDC encoded result of a script.
+// Events are JSON-CDC encoded, and include the events emitted by failed transactions
+//
+type StepResult struct {
+	Kind   string            `json:"kind"`
+	Path   string            `json:"path"`
+	Failed bool              `json:"failed"`
+	Error  string            `json:"error,omitempty"`
+	Value  json.RawMessage   `json:"value,omitempty"`
+	Events []json.RawMessage `json:"events"`
+	Logs   []string          `json:"logs"`
+}
+
+// loadState loads the given state fixture.
+//
+// The storage indices of the owners are advanced past the indices of the loaded slabs,
+// and the UUID generator is advanced past the UUIDs generated for the state,
+// so newly allocated slabs and newly created resources do not collide with loaded ones
+//
+func (i *recordingInterface) loadState(state *State) error {
+	for _, contract := range state.Contracts {
+		address, err := common.HexToAddress(contract.Address)
+		if err != nil {
+			return err
+		}
+
+		err = i.UpdateAccountContractCode(address, contract.Name, []byte(contract.Code))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, register := range state.Registers {
+		owner, err := hex.DecodeString(register.Owner)
+		if err != nil {
+			return err
+		}
+
+		key, err := hex.DecodeString(register.Key)
+		if err != nil {
+			return err
+		}
+
+		value, err := hex.DecodeString(register.Value)
+		if err != nil {
+			return err
+		}
+
+		err = i.SetValue(owner, key, value)
+		if err != nil {
+			return err
+		}
+
+		// Slab keys are '$' followed by the 8 byte storage index
+
+		if len(key) == 1+len(atree.StorageIndex{}) && key[0] == '$' {
+			index := binary.BigEndian.Uint64(key[1:])
+			if index > i.storageIndices[string(owner)] {
+				i.storageIndices[string(owner)] = index
+			}
+		}
+	}
+
+	if state.UUIDs > i.uuids {
+		i.uuids = state.UUIDs
+	}
+
+	return nil
+}
+
+// state returns the current state of the accounts
+//
+func (i *recordingInterface) state(addresses []common.Address) (State, error) {
+	state := State{
+		Contracts: []Contract{},
+		Registers: []Register{},
+		UUIDs:     i.uuids,
+	}
+
+	for _, address := range addresses {
+		names, err := i.GetAccountContractNames(address)
+		if err != nil {
+			return state, err
+		}
+
+		for _, name := range names {
+			code, err := i.GetAccountContractCode(address, name)
+			if err != nil {
+				return state, err
+			}
+
+			state.Contracts = append(
+				state.Contracts,
+				Contract{
+					Address: address.ShortHexWithPrefix(),
+					Name:    name,
+					Code:    string(code),
+				},
+			)
+		}
+	}
+
+	// NOTE: iteration over map is safe,
+	// as the state is sorted below
+
+	for registerKey, value := range i.registers { //nolint:maprangecheck
+		separator := strings.Index(registerKey, "|")
+		owner := registerKey[:separator]
+		key := registerKey[separator+1:]
+
+		state.Registers = append(
+			state.Registers,
+			Register{
+				Owner: hex.EncodeToString([]byte(owner)),
+				Key:   hex.EncodeToString([]byte(key)),
+				Value: hex.EncodeToString(value),
+			},
+		)
+	}
+
+	state.sort()
+
+	return state, nil
+}
+
+// runCorpus runs all given cases
+//
+func runCorpus(cases []*Case) (*Report, error) {
+	report := &Report{
+		Cases: []CaseResult{},
+	}
+
+	for _, c := range cases {
+		result, err := runCase(c)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Cases = append(report.Cases, *result)
+	}
+
+	return report, nil
+}
+
+// runCase executes all steps of the given case against a fresh state.
+//
+// Failures of transactions and scripts are part of the result.
+// An error is only returned if the case itself is invalid, e.g. a program or the fixture cannot be read
+//
+func runCase(c *Case) (*CaseResult, error) {
+	rt := runtime.NewInterpreterRuntime()
+	runtimeInterface := newRecordingInterface()
+
+	addresses := make([]common.Address, 0, c.Accounts)
+	for i := 0; i < c.Accounts; i++ {
+		address, err := runtimeInterface.CreateAccount(common.Address{})
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	fixture, err := c.loadFixture()
+	if err != nil {
+		return nil, err
+	}
+
+	if fixture != nil {
+		err = runtimeInterface.loadState(fixture)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture of case %s: %w", c.Name, err)
+		}
+	}
+
+	result := &CaseResult{
+		Name:  c.Name,
+		Steps: []StepResult{},
+	}
+
+	for index, step := range c.Steps {
+		stepResult, err := runStep(rt, runtimeInterface, c, index, step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run step %d of case %s: %w", index, c.Name, err)
+		}
+
+		result.Steps = append(result.Steps, *stepResult)
+	}
+
+	result.State, err = runtimeInterface.state(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func runStep(
+	rt runtime.Runtime,
+	runtimeInterface *recordingInterface,
+	c *Case,
+	index int,
+	step Step,
+) (
+	*StepResult,
+	error,
+) {
+	code, err := c.readFile(step.path())
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([][]byte, 0, len(step.Arguments))
+	for _, argument := range step.Arguments {
+		arguments = append(arguments, argument)
+	}
+
+	if step.Contract != "" {
+		code, arguments, err = deploymentTransaction(step, code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signers := make([]common.Address, 0, len(step.Signers))
+	for _, signer := range step.Signers {
+		address, err := common.HexToAddress(signer)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, address)
+	}
+
+	script := runtime.Script{
+		Source:    code,
+		Arguments: arguments,
+	}
+
+	eventCount := len(runtimeInterface.Events())
+	logCount := len(runtimeInterface.Logs())
+
+	result := &StepResult{
+		Kind: step.kind(),
+		Path: step.path(),
+	}
+
+	// The location of a step only depends on its index,
+	// so the type IDs of types declared in programs are deterministic
+
+	var location [8]byte
+	binary.BigEndian.PutUint64(location[:], uint64(index))
+
+	var executionErr error
+
+	if step.Script == "" {
+		runtimeInterface.SetSigningAccounts(signers)
+
+		executionErr = rt.ExecuteTransaction(
+			script,
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation(location[:]),
+			},
+		)
+	} else {
+		// Like on chain, the state changes of scripts are discarded,
+		// e.g. the storage domains created when storage is read.
+		// NOTE: the state is restored after the events and logs were recorded
+
+		defer runtimeInterface.restore(runtimeInterface.snapshot())
+
+		var value cadence.Value
+		value, executionErr = rt.ExecuteScript(
+			script,
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation(location[:]),
+			},
+		)
+
+		if executionErr == nil && value != nil {
+			result.Value, err = jsoncdc.Encode(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if executionErr != nil {
+		result.Failed = true
+		result.Error = executionErr.Error()
+	}
+
+	result.Events = []json.RawMessage{}
+	for _, event := range runtimeInterface.Events()[eventCount:] {
+		encoded, err := jsoncdc.Encode(event)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, encoded)
+	}
+
+	result.Logs = append([]string{}, runtimeInterface.Logs()[logCount:]...)
+
+	return result, nil
+}
+
+const deploymentTransactionCode = `
+  transaction(name: String, code: String, update: Bool) {
+      prepare(signer: AuthAccount) {
+          if update {
+              signer.contracts.update__experimental(name: name, code: code.decodeHex())
+          } else {
+              signer.contracts.add(name: name, code: code.decodeHex())
+          }
+      }
+  }
+`
+
+// deploymentTransaction returns the transaction and its arguments
+// which deploy the contract of the given deployment step
+//
+func deploymentTransaction(step Step, contractCode []byte) ([]byte, [][]byte, error) {
+	name := strings.TrimSuffix(filepath.Base(step.Contract), filepath.Ext(step.Contract))
+
+	arguments := make([][]byte, 0, 3)
+	for _, argument := range []cadence.Value{
+		cadence.String(name),
+		cadence.String(hex.EncodeToString(contractCode)),
+		cadence.NewBool(step.Update),
+	} {
+		encoded, err := jsoncdc.Encode(argument)
+		if err != nil {
+			return nil, nil, err
+		}
+		arguments = append(arguments, encoded)
+	}
+
+	return []byte(deploymentTransactionCode), arguments, nil
+}
@@ -21,14 +21,22 @@ package execute
 import (
 	"github.com/logrusorgru/aurora"
 
+	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
+const maxResultLineWidth = 80
+
 func colorizeResult(value interpreter.Value) string {
-	str := value.String()
+	str := runtime.FormatREPLValue(value, maxResultLineWidth)
 	return aurora.Colorize(str, aurora.YellowFg|aurora.BrightFg).String()
 }
 
+func colorizeType(ty sema.Type) string {
+	return aurora.Colorize(ty.QualifiedString(), aurora.CyanFg|aurora.BrightFg).String()
+}
+
 func formatValue(value interpreter.Value) string {
 	if _, isVoid := value.(*interpreter.VoidValue); isVoid || value == nil {
 		return ""
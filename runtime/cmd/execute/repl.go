@@ -65,16 +65,19 @@ func RunREPL() {
 		}()
 
 		if code == "" && strings.HasPrefix(line, ".") {
-			handleCommand(line)
+			handleCommand(repl, line)
 			code = ""
 			return
 		}
 
 		// Prefix the code with empty lines,
-		// so that error messages match current line number
+		// so that error messages match current line number.
+		// Continuation lines are appended to the already prefixed code
 
-		for i := 1; i < lineNumber; i++ {
-			code = "\n" + code
+		if code == "" {
+			for i := 1; i < lineNumber; i++ {
+				code += "\n"
+			}
 		}
 
 		code += line + "\n"
@@ -90,20 +93,34 @@ func RunREPL() {
 	}
 
 	suggest := func(d prompt.Document) []prompt.Suggest {
-		if len(d.GetWordBeforeCursor()) == 0 {
+		word := d.GetWordBeforeCursor()
+		if len(word) == 0 {
 			return nil
 		}
 
+		// If the word is a member access, e.g. `foo.ba`,
+		// suggest the members of the accessed expression
+
+		var suggestions []runtime.REPLSuggestion
+
+		dotIndex := strings.LastIndex(word, ".")
+		if dotIndex > 0 {
+			suggestions = repl.MemberSuggestions(word[:dotIndex])
+			word = word[dotIndex+1:]
+		} else {
+			suggestions = repl.Suggestions()
+		}
+
 		suggests := []prompt.Suggest{}
 
-		for _, suggestion := range repl.Suggestions() {
+		for _, suggestion := range suggestions {
 			suggests = append(suggests, prompt.Suggest{
 				Text:        suggestion.Name,
 				Description: suggestion.Description,
 			})
 		}
 
-		return prompt.FilterHasPrefix(suggests, d.GetWordBeforeCursor(), false)
+		return prompt.FilterHasPrefix(suggests, word, false)
 	}
 
 	changeLivePrefix := func() (string, bool) {
@@ -125,20 +142,36 @@ const replHelpMessage = `
 Enter declarations and statements to evaluate them.
 Commands are prefixed with a dot. Valid commands are:
 
-.exit     Exit the interpreter
-.help     Print this help message
+.exit          Exit the interpreter
+.help          Print this help message
+.type <expr>   Print the type of the expression, without evaluating it
 
 Press ^C to abort current expression, ^D to exit
 `
 
 const replAssistanceMessage = `Type '.help' for assistance.`
 
-func handleCommand(command string) {
+func handleCommand(repl *runtime.REPL, line string) {
+	command, argument := line, ""
+	if index := strings.IndexByte(line, ' '); index >= 0 {
+		command = line[:index]
+		argument = strings.TrimSpace(line[index+1:])
+	}
+
 	switch command {
 	case ".exit":
 		os.Exit(0)
 	case ".help":
 		fmt.Println(replHelpMessage)
+	case ".type":
+		if argument == "" {
+			fmt.Println(colorizeError("Missing expression. Usage: .type <expr>"))
+			return
+		}
+		ty := repl.ExpressionType(argument)
+		if ty != nil {
+			fmt.Println(colorizeType(ty))
+		}
 	default:
 		fmt.Println(colorizeError(fmt.Sprintf("Unknown command. %s", replAssistanceMessage)))
 	}
@@ -0,0 +1,326 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/declarations"
+)
+
+// This file implements the API of the playground build of the parser and checker.
+// The functions only depend on the parser, the checker, and the standard library declarations,
+// so the WebAssembly build does not include the interpreter and the language server.
+
+const (
+	SeverityError = "error"
+	SeverityHint  = "hint"
+)
+
+// Diagnostic is an error or hint reported for a program
+//
+type Diagnostic struct {
+	Message          string        `json:"message"`
+	SecondaryMessage string        `json:"secondaryMessage,omitempty"`
+	Severity         string        `json:"severity"`
+	Location         string        `json:"location,omitempty"`
+	StartPosition    *ast.Position `json:"startPosition,omitempty"`
+	EndPosition      *ast.Position `json:"endPosition,omitempty"`
+}
+
+type parseResult struct {
+	Program     *ast.Program `json:"program,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Error       string       `json:"error,omitempty"`
+}
+
+type checkResult struct {
+	Valid       bool         `json:"valid"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// enabledFeatures are the experimental features which are enabled in the playground,
+// so features which are still in progress can be tried out, see common.ExperimentalFeature
+//
+var enabledFeatures = common.NewExperimentalFeatures(common.AllExperimentalFeatures...)
+
+// parserConfig is the configuration of the parser,
+// which enables the experimental features
+//
+var parserConfig = func() parser2.Config {
+	config := parser2.DefaultConfig
+	config.EnabledFeatures = enabledFeatures
+	return config
+}()
+
+// mainLocation is the location of the program which is parsed or checked
+//
+const mainLocation = common.StringLocation("main")
+
+// recoverError recovers from a panic and reports it as an internal error,
+// so a crash of the parser or checker does not terminate the WebAssembly instance
+//
+func recoverError(report func(message string)) {
+	if r := recover(); r != nil {
+		report(fmt.Sprintf("%s\n%s", r, debug.Stack()))
+	}
+}
+
+func parse(code string) (result parseResult) {
+	// NOTE: Always initialize to an empty slice, i.e. DON'T use nil,
+	// so the diagnostics are encoded as an empty array
+	result.Diagnostics = []Diagnostic{}
+
+	defer recoverError(func(message string) {
+		result.Error = message
+	})
+
+	program, err := parser2.ParseProgramWithConfig(code, parserConfig)
+	result.Program = program
+	result.Diagnostics = append(result.Diagnostics, diagnostics(err, nil)...)
+
+	return result
+}
+
+// check parses and checks the given code.
+// The imports map addresses (e.g. `0x1`) to the code of the contracts deployed to them.
+// The Crypto contract can always be imported
+//
+func check(code string, imports map[string]string) (result checkResult) {
+	result.Diagnostics = []Diagnostic{}
+
+	defer recoverError(func(message string) {
+		result.Error = message
+	})
+
+	program, err := parser2.ParseProgramWithConfig(code, parserConfig)
+	if err != nil {
+		result.Diagnostics = append(result.Diagnostics, diagnostics(err, nil)...)
+		return result
+	}
+
+	checker, err := newChecker(program, mainLocation, imports, map[common.LocationID]*sema.Checker{})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	err = checker.Check()
+	result.Diagnostics = append(result.Diagnostics, diagnostics(err, nil)...)
+
+	for _, hint := range checker.Hints() {
+		result.Diagnostics = append(result.Diagnostics, newDiagnostic(hint.Hint(), SeverityHint, hint, nil))
+	}
+
+	result.Valid = err == nil
+
+	return result
+}
+
+func newChecker(
+	program *ast.Program,
+	location common.Location,
+	imports map[string]string,
+	checkers map[common.LocationID]*sema.Checker,
+) (*sema.Checker, error) {
+	return sema.NewChecker(
+		program,
+		location,
+		sema.WithPredeclaredValues(declarations.All()),
+		sema.WithLintingEnabled(true),
+		sema.WithEnabledFeatures(enabledFeatures),
+		sema.WithImportHandler(
+			func(_ *sema.Checker, importedLocation common.Location, _ ast.Range) (sema.Import, error) {
+				importedChecker, err := importedChecker(importedLocation, imports, checkers)
+				if err != nil {
+					return nil, err
+				}
+
+				return sema.ElaborationImport{
+					Elaboration: importedChecker.Elaboration,
+				}, nil
+			},
+		),
+	)
+}
+
+func importedChecker(
+	location common.Location,
+	imports map[string]string,
+	checkers map[common.LocationID]*sema.Checker,
+) (*sema.Checker, error) {
+
+	if location == declarations.CryptoChecker.Location {
+		return declarations.CryptoChecker, nil
+	}
+
+	if checker, ok := checkers[location.ID()]; ok {
+		if checker == nil {
+			return nil, fmt.Errorf("cannot import `%s`: cyclic import", location)
+		}
+		return checker, nil
+	}
+
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot import `%s`: only addresses are supported", location)
+	}
+
+	code, ok := imports[addressLocation.Address.ShortHexWithPrefix()]
+	if !ok {
+		return nil, fmt.Errorf("cannot import `%s`: no code for address", location)
+	}
+
+	program, err := parser2.ParseProgramWithConfig(code, parserConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark the location as being checked, to detect cyclic imports
+
+	checkers[location.ID()] = nil
+
+	checker, err := newChecker(program, location, imports, checkers)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	checkers[location.ID()] = checker
+
+	return checker, nil
+}
+
+// diagnostics returns the diagnostics for the given error
+// and its child errors, if any
+//
+func diagnostics(err error, location common.Location) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	parentError, ok := err.(errors.ParentError)
+	if !ok {
+		var positioned ast.HasPosition
+		positioned, _ = err.(ast.HasPosition)
+		return []Diagnostic{
+			newDiagnostic(err.Error(), SeverityError, positioned, location),
+		}
+	}
+
+	if checkerError, ok := err.(*sema.CheckerError); ok {
+		location = checkerError.Location
+	}
+
+	var result []Diagnostic
+
+	for _, childError := range parentError.ChildErrors() {
+		positioned, hasPosition := childError.(ast.HasPosition)
+		_, isParent := childError.(errors.ParentError)
+
+		// Errors which only group other errors, like the errors of an imported program,
+		// have no diagnostic of their own
+
+		if hasPosition || !isParent {
+			diagnostic := newDiagnostic(childError.Error(), SeverityError, positioned, location)
+
+			if secondaryError, ok := childError.(errors.SecondaryError); ok {
+				diagnostic.SecondaryMessage = secondaryError.SecondaryError()
+			}
+
+			result = append(result, diagnostic)
+		}
+
+		if isParent {
+			result = append(result, diagnostics(childError, location)...)
+		}
+	}
+
+	return result
+}
+
+func newDiagnostic(
+	message string,
+	severity string,
+	positioned ast.HasPosition,
+	location common.Location,
+) Diagnostic {
+	diagnostic := Diagnostic{
+		Message:  message,
+		Severity: severity,
+	}
+
+	if location != nil && location != mainLocation {
+		diagnostic.Location = string(location.ID())
+	}
+
+	if positioned != nil {
+		startPosition := positioned.StartPosition()
+		endPosition := positioned.EndPosition()
+		diagnostic.StartPosition = &startPosition
+		diagnostic.EndPosition = &endPosition
+	}
+
+	return diagnostic
+}
+
+// parseJSON parses the given code and returns the result encoded as JSON
+//
+func parseJSON(code string) string {
+	return mustMarshal(parse(code))
+}
+
+// checkJSON checks the given code and returns the result encoded as JSON.
+// The imports are a JSON object mapping addresses to code, and may be empty
+//
+func checkJSON(code string, importsJSON string) string {
+	imports := map[string]string{}
+
+	if importsJSON != "" {
+		err := json.Unmarshal([]byte(importsJSON), &imports)
+		if err != nil {
+			return mustMarshal(checkResult{
+				Diagnostics: []Diagnostic{},
+				Error:       fmt.Sprintf("invalid imports: %s", err),
+			})
+		}
+	}
+
+	return mustMarshal(check(code, imports))
+}
+
+func mustMarshal(value interface{}) string {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return string(serialized)
+}
@@ -0,0 +1,256 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func TestParse(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := parse(`pub fun test() {}`)
+		require.Empty(t, result.Error)
+		assert.Empty(t, result.Diagnostics)
+		require.NotNil(t, result.Program)
+		assert.Len(t, result.Program.FunctionDeclarations(), 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := parse(`pub fun test() {`)
+		require.Empty(t, result.Error)
+		require.Len(t, result.Diagnostics, 1)
+
+		diagnostic := result.Diagnostics[0]
+		assert.Equal(t, SeverityError, diagnostic.Severity)
+		assert.NotEmpty(t, diagnostic.Message)
+		assert.NotNil(t, diagnostic.StartPosition)
+	})
+}
+
+func TestCheck(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid, standard library", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := check(
+			`
+              import Crypto
+
+              pub fun main(): UInt64 {
+                  let account = getAccount(0x1)
+                  let algorithm = HashAlgorithm.SHA3_256
+                  log(account.address)
+                  return getCurrentBlock().height
+              }
+            `,
+			nil,
+		)
+		require.Empty(t, result.Error)
+		assert.Empty(t, result.Diagnostics)
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := check(`pub let x: Int = "1"`, nil)
+		require.Empty(t, result.Error)
+		assert.False(t, result.Valid)
+		assert.Equal(t,
+			[]Diagnostic{
+				{
+					Message:          "mismatched types",
+					SecondaryMessage: "expected `Int`, got `String`",
+					Severity:         SeverityError,
+					StartPosition:    &ast.Position{Offset: 17, Line: 1, Column: 17},
+					EndPosition:      &ast.Position{Offset: 19, Line: 1, Column: 19},
+				},
+			},
+			result.Diagnostics,
+		)
+	})
+
+	t.Run("imports", func(t *testing.T) {
+
+		t.Parallel()
+
+		imports := map[string]string{
+			"0x1": `
+              pub contract Hello {
+                  pub fun hello(): String {
+                      return "Hello"
+                  }
+              }
+            `,
+		}
+
+		result := check(
+			`
+              import Hello from 0x01
+
+              pub fun main(): String {
+                  return Hello.hello()
+              }
+            `,
+			imports,
+		)
+		require.Empty(t, result.Error)
+		assert.Empty(t, result.Diagnostics)
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("invalid import", func(t *testing.T) {
+
+		t.Parallel()
+
+		imports := map[string]string{
+			"0x1": `
+              pub contract Hello {
+                  pub let x: Int
+                  init() {
+                      self.x = true
+                  }
+              }
+            `,
+		}
+
+		result := check(`import Hello from 0x1`, imports)
+		require.Empty(t, result.Error)
+		assert.False(t, result.Valid)
+		require.Len(t, result.Diagnostics, 2)
+
+		assert.Equal(t, "checking of imported program `0000000000000001` failed", result.Diagnostics[0].Message)
+		assert.Empty(t, result.Diagnostics[0].Location)
+
+		assert.Equal(t, "mismatched types", result.Diagnostics[1].Message)
+		assert.Equal(t, "A.0000000000000001", result.Diagnostics[1].Location)
+	})
+
+	t.Run("missing import", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := check(`import Hello from 0x2`, nil)
+		require.Empty(t, result.Error)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Diagnostics)
+		assert.Equal(t,
+			"cannot import `0000000000000002`: no code for address",
+			result.Diagnostics[len(result.Diagnostics)-1].Message,
+		)
+	})
+
+	t.Run("hint", func(t *testing.T) {
+
+		t.Parallel()
+
+		result := check(
+			`
+              pub fun test() {
+                  let x = 1 as Int
+              }
+            `,
+			nil,
+		)
+		require.Empty(t, result.Error)
+		assert.True(t, result.Valid)
+		require.Len(t, result.Diagnostics, 1)
+		assert.Equal(t, SeverityHint, result.Diagnostics[0].Severity)
+	})
+}
+
+func TestCheckJSON(t *testing.T) {
+
+	t.Parallel()
+
+	var result map[string]interface{}
+
+	err := json.Unmarshal([]byte(checkJSON(`pub let x = 1`, "")), &result)
+	require.NoError(t, err)
+	assert.Equal(t,
+		map[string]interface{}{
+			"valid":       true,
+			"diagnostics": []interface{}{},
+		},
+		result,
+	)
+
+	err = json.Unmarshal([]byte(checkJSON(`pub let x = 1`, "[")), &result)
+	require.NoError(t, err)
+	assert.Contains(t, result["error"], "invalid imports")
+}
+
+// TestDependencies ensures the WebAssembly build
+// does not include the interpreter, storage encoding, cryptography, and the language server
+//
+func TestDependencies(t *testing.T) {
+
+	t.Parallel()
+
+	goPath, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not available")
+	}
+
+	cmd := exec.Command(goPath, "list", "-deps", ".")
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+
+	output, err := cmd.Output()
+	require.NoError(t, err)
+
+	forbidden := []string{
+		"github.com/onflow/cadence/runtime/interpreter",
+		"github.com/onflow/cadence/languageserver",
+		"github.com/onflow/atree",
+		"github.com/fxamacker/cbor",
+		"crypto/ecdsa",
+		"crypto/ed25519",
+	}
+
+	for _, dependency := range strings.Split(string(output), "\n") {
+		for _, prefix := range forbidden {
+			assert.False(t,
+				strings.HasPrefix(dependency, prefix),
+				"unexpected dependency: %s", dependency,
+			)
+		}
+	}
+}
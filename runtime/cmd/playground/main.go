@@ -0,0 +1,62 @@
+// +build !wasm
+
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// This command runs the playground API natively, which is useful for debugging:
+// It reads the code from the standard input and prints the result encoded as JSON.
+// The WebAssembly build exposes the same API to JavaScript, see main_wasm.go
+
+var importsFlag = flag.String("imports", "", "JSON object mapping addresses to the code of imported contracts")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-imports JSON] parse|check < code\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	code, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+
+	switch flag.Arg(0) {
+	case "parse":
+		fmt.Println(parseJSON(string(code)))
+	case "check":
+		fmt.Println(checkJSON(string(code), *importsFlag))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
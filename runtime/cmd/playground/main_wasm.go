@@ -0,0 +1,73 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"syscall/js"
+)
+
+const globalFunctionNamePrefix = "CADENCE_PLAYGROUND"
+
+func globalFunctionName(name string) string {
+	return fmt.Sprintf("__%s_%s__", globalFunctionNamePrefix, name)
+}
+
+func main() {
+
+	log.Println("Cadence Playground")
+
+	done := make(chan struct{}, 0)
+
+	// parse(code: string): string
+	//
+	// Returns the result encoded as JSON:
+	// { program?: Program, diagnostics: Diagnostic[], error?: string }
+
+	js.Global().Set(
+		globalFunctionName("parse"),
+		js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			code := args[0].String()
+			return parseJSON(code)
+		}),
+	)
+
+	// check(code: string, imports?: string): string
+	//
+	// The optional imports are a JSON object mapping addresses to code.
+	// Returns the result encoded as JSON:
+	// { valid: boolean, diagnostics: Diagnostic[], error?: string }
+
+	js.Global().Set(
+		globalFunctionName("check"),
+		js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			code := args[0].String()
+
+			var imports string
+			if len(args) > 1 && args[1].Type() == js.TypeString {
+				imports = args[1].String()
+			}
+
+			return checkJSON(code, imports)
+		}),
+	)
+
+	<-done
+}
@@ -0,0 +1,218 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAddress is returned by address schemes
+// if an address or its string representation is invalid
+//
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressScheme defines the addresses of a chain:
+// how many bytes are significant, how addresses are formatted and parsed,
+// and which addresses are valid, e.g. because they have a valid checksum.
+//
+// Addresses are always stored in an Address, i.e. in AddressLength bytes.
+// Addresses of schemes with a shorter length are padded with leading zeros.
+//
+type AddressScheme interface {
+	// Length returns the number of significant bytes of addresses,
+	// which is at most AddressLength
+	Length() int
+	// Format returns the string representation of the given address
+	Format(address Address) string
+	// Parse parses the given string representation of an address
+	Parse(s string) (Address, error)
+	// Validate returns an error if the given address is not valid
+	Validate(address Address) error
+}
+
+// HexAddressScheme is an AddressScheme for addresses
+// which are formatted as hexadecimal strings with a prefix, e.g. `0x`.
+//
+type HexAddressScheme struct {
+	// Prefix is the prefix of the string representation, e.g. `0x`
+	Prefix string
+	// AddressLength is the number of significant bytes of addresses.
+	// Zero means AddressLength
+	AddressLength int
+	// Checksum, if any, returns true if the given address has a valid checksum
+	Checksum func(address Address) bool
+}
+
+var _ AddressScheme = HexAddressScheme{}
+
+// FlowAddressScheme is the address scheme of Flow:
+// 8 byte addresses, formatted as hexadecimal strings with the prefix `0x`
+//
+var FlowAddressScheme AddressScheme = HexAddressScheme{
+	Prefix: "0x",
+}
+
+func (s HexAddressScheme) Length() int {
+	if s.AddressLength == 0 {
+		return AddressLength
+	}
+	return s.AddressLength
+}
+
+func (s HexAddressScheme) Format(address Address) string {
+	return s.Prefix + hex.EncodeToString(address[AddressLength-s.Length():])
+}
+
+func (s HexAddressScheme) Parse(str string) (Address, error) {
+	if !strings.HasPrefix(str, s.Prefix) {
+		return Address{}, fmt.Errorf(
+			"%w: %q: missing prefix %q",
+			ErrInvalidAddress,
+			str,
+			s.Prefix,
+		)
+	}
+
+	digits := str[len(s.Prefix):]
+	if len(digits) == 0 {
+		return Address{}, fmt.Errorf(
+			"%w: %q: missing digits",
+			ErrInvalidAddress,
+			str,
+		)
+	}
+
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return Address{}, fmt.Errorf(
+			"%w: %q: %s",
+			ErrInvalidAddress,
+			str,
+			err,
+		)
+	}
+
+	if len(b) > s.Length() {
+		return Address{}, fmt.Errorf(
+			"%w: %q: expected at most %d bytes, got %d",
+			ErrInvalidAddress,
+			str,
+			s.Length(),
+			len(b),
+		)
+	}
+
+	address := BytesToAddress(b)
+
+	err = s.Validate(address)
+	if err != nil {
+		return Address{}, err
+	}
+
+	return address, nil
+}
+
+func (s HexAddressScheme) Validate(address Address) error {
+	for _, b := range address[:AddressLength-s.Length()] {
+		if b != 0 {
+			return fmt.Errorf(
+				"%w: %s: expected at most %d significant bytes",
+				ErrInvalidAddress,
+				address.HexWithPrefix(),
+				s.Length(),
+			)
+		}
+	}
+
+	if s.Checksum != nil && !s.Checksum(address) {
+		return fmt.Errorf(
+			"%w: %s: invalid checksum",
+			ErrInvalidAddress,
+			s.Format(address),
+		)
+	}
+
+	return nil
+}
+
+// AddressGenerator generates the addresses of new accounts
+//
+type AddressGenerator interface {
+	// NextAddress returns the next address.
+	// It returns an error if no further addresses can be generated
+	NextAddress() (Address, error)
+}
+
+// SequentialAddressGenerator is an AddressGenerator which generates
+// all valid addresses of an address scheme in ascending order,
+// starting at 0x1
+//
+type SequentialAddressGenerator struct {
+	Scheme AddressScheme
+	// Index is the index of the last generated address,
+	// i.e. the generator is at its initial state if it is zero
+	Index uint64
+}
+
+var _ AddressGenerator = &SequentialAddressGenerator{}
+
+func NewSequentialAddressGenerator(scheme AddressScheme) *SequentialAddressGenerator {
+	return &SequentialAddressGenerator{
+		Scheme: scheme,
+	}
+}
+
+func (g *SequentialAddressGenerator) NextAddress() (Address, error) {
+	for {
+		if g.Index == g.maxIndex() {
+			return Address{}, fmt.Errorf(
+				"all addresses of length %d were generated",
+				g.Scheme.Length(),
+			)
+		}
+
+		g.Index++
+
+		var address Address
+		binary.BigEndian.PutUint64(address[:], g.Index)
+
+		// Skip addresses which are not valid,
+		// e.g. because the scheme has a checksum
+
+		if g.Scheme.Validate(address) == nil {
+			return address, nil
+		}
+	}
+}
+
+func (g *SequentialAddressGenerator) maxIndex() uint64 {
+	length := g.Scheme.Length()
+	if length >= AddressLength {
+		return ^uint64(0)
+	}
+	return 1<<(8*length) - 1
+}
@@ -0,0 +1,147 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowAddressScheme(t *testing.T) {
+
+	t.Parallel()
+
+	address := BytesToAddress([]byte{0x1, 0x2})
+
+	assert.Equal(t, AddressLength, FlowAddressScheme.Length())
+	assert.Equal(t, "0x0000000000000102", FlowAddressScheme.Format(address))
+
+	parsed, err := FlowAddressScheme.Parse("0x0000000000000102")
+	require.NoError(t, err)
+	assert.Equal(t, address, parsed)
+
+	parsed, err = FlowAddressScheme.Parse("0x102")
+	require.NoError(t, err)
+	assert.Equal(t, address, parsed)
+
+	for _, invalid := range []string{
+		"0102",
+		"0x",
+		"0xzz",
+		"0x010203040506070809",
+	} {
+		_, err := FlowAddressScheme.Parse(invalid)
+		require.ErrorIs(t, err, ErrInvalidAddress, invalid)
+	}
+}
+
+func TestHexAddressScheme(t *testing.T) {
+
+	t.Parallel()
+
+	// A scheme with 2 byte addresses, where the checksum is the parity of the address
+
+	scheme := HexAddressScheme{
+		Prefix:        "chain:",
+		AddressLength: 2,
+		Checksum: func(address Address) bool {
+			return address[AddressLength-1]%2 == 0
+		},
+	}
+
+	address := BytesToAddress([]byte{0x1, 0x2})
+
+	assert.Equal(t, 2, scheme.Length())
+	assert.Equal(t, "chain:0102", scheme.Format(address))
+
+	parsed, err := scheme.Parse("chain:0102")
+	require.NoError(t, err)
+	assert.Equal(t, address, parsed)
+
+	// Invalid checksum
+
+	_, err = scheme.Parse("chain:0101")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+
+	// Too long
+
+	_, err = scheme.Parse("chain:010200")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+
+	err = scheme.Validate(BytesToAddress([]byte{0x1, 0x0, 0x0}))
+	require.ErrorIs(t, err, ErrInvalidAddress)
+
+	// Wrong prefix
+
+	_, err = scheme.Parse("0x0102")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func TestSequentialAddressGenerator(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("Flow", func(t *testing.T) {
+
+		t.Parallel()
+
+		generator := NewSequentialAddressGenerator(FlowAddressScheme)
+
+		for i := byte(1); i <= 3; i++ {
+			address, err := generator.NextAddress()
+			require.NoError(t, err)
+			assert.Equal(t, BytesToAddress([]byte{i}), address)
+		}
+	})
+
+	t.Run("checksum, exhausted", func(t *testing.T) {
+
+		t.Parallel()
+
+		scheme := HexAddressScheme{
+			Prefix:        "0x",
+			AddressLength: 1,
+			Checksum: func(address Address) bool {
+				return address[AddressLength-1]%64 == 0
+			},
+		}
+
+		generator := NewSequentialAddressGenerator(scheme)
+
+		var addresses []Address
+		for {
+			address, err := generator.NextAddress()
+			if err != nil {
+				break
+			}
+			addresses = append(addresses, address)
+		}
+
+		assert.Equal(t,
+			[]Address{
+				BytesToAddress([]byte{64}),
+				BytesToAddress([]byte{128}),
+				BytesToAddress([]byte{192}),
+			},
+			addresses,
+		)
+	})
+}
@@ -56,6 +56,7 @@ const (
 	DeclarationKindPragma
 	DeclarationKindEnum
 	DeclarationKindEnumCase
+	DeclarationKindEntitlement
 )
 
 func DeclarationKindCount() int {
@@ -72,7 +73,8 @@ func (k DeclarationKind) IsTypeDeclaration() bool {
 		DeclarationKindResourceInterface,
 		DeclarationKindContractInterface,
 		DeclarationKindTypeParameter,
-		DeclarationKindEnum:
+		DeclarationKindEnum,
+		DeclarationKindEntitlement:
 
 		return true
 
@@ -135,6 +137,8 @@ func (k DeclarationKind) Name() string {
 		return "enum"
 	case DeclarationKindEnumCase:
 		return "enum case"
+	case DeclarationKindEntitlement:
+		return "entitlement"
 	case DeclarationKindUnknown:
 		return "unknown"
 	}
@@ -182,6 +186,8 @@ func (k DeclarationKind) Keywords() string {
 		return "enum"
 	case DeclarationKindEnumCase:
 		return "case"
+	case DeclarationKindEntitlement:
+		return "entitlement"
 	default:
 		return ""
 	}
@@ -0,0 +1,101 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+)
+
+// DecodingLimits bound the resources used to decode CBOR-encoded data,
+// e.g. stored values or transaction arguments,
+// so that crafted data cannot exhaust memory.
+//
+// The limits on the nesting depth and the declared lengths of arrays and maps
+// are checked by the CBOR decoder before any data item is decoded,
+// the limit on the number of values is checked by the decoders of values,
+// see e.g. interpreter.NewCBORDecMode.
+//
+type DecodingLimits struct {
+	// MaxNestedLevels is the maximum nesting depth of CBOR data items
+	MaxNestedLevels int
+	// MaxArrayElements is the maximum declared number of elements of a CBOR array,
+	// and the maximum declared number of pairs of a CBOR map
+	MaxArrayElements int
+	// MaxValueCount is the maximum number of values decoded from one data item
+	MaxValueCount uint64
+}
+
+// DefaultDecodingLimits are the decoding limits used if none are configured
+//
+var DefaultDecodingLimits = DecodingLimits{
+	MaxNestedLevels:  256,
+	MaxArrayElements: 1 << 20,
+	MaxValueCount:    1 << 20,
+}
+
+// Validate returns an error if a limit is not positive
+//
+func (l DecodingLimits) Validate() error {
+	if l.MaxNestedLevels <= 0 || l.MaxArrayElements <= 0 || l.MaxValueCount == 0 {
+		return fmt.Errorf("invalid decoding limits: all limits must be positive: %+v", l)
+	}
+	return nil
+}
+
+// MaxValueCountExceededError is reported when more values are decoded
+// than allowed by DecodingLimits.MaxValueCount
+//
+type MaxValueCountExceededError struct {
+	MaxValueCount uint64
+}
+
+func (e MaxValueCountExceededError) Error() string {
+	return fmt.Sprintf(
+		"exceeded maximum number of decoded values: %d",
+		e.MaxValueCount,
+	)
+}
+
+// DecodedValueCounter counts decoded values against a limit
+//
+type DecodedValueCounter struct {
+	count uint64
+	limit uint64
+}
+
+// NewDecodedValueCounter returns a counter for the given limits
+//
+func NewDecodedValueCounter(limits DecodingLimits) *DecodedValueCounter {
+	return &DecodedValueCounter{
+		limit: limits.MaxValueCount,
+	}
+}
+
+// Count records a decoded value.
+// A MaxValueCountExceededError is returned if the limit is exceeded.
+//
+func (c *DecodedValueCounter) Count() error {
+	c.count++
+	if c.count > c.limit {
+		return MaxValueCountExceededError{
+			MaxValueCount: c.limit,
+		}
+	}
+	return nil
+}
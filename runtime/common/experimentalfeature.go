@@ -0,0 +1,56 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// ExperimentalFeature is a language feature which is still in progress,
+// e.g. draft syntax or semantics.
+//
+// Experimental features are disabled by default, so merging them does not affect
+// the behaviour of programs, e.g. on mainnet. They can be enabled in the parser,
+// see parser2.Config.EnabledFeatures, and in the checker, see sema.WithEnabledFeatures,
+// e.g. in the playground and in tests.
+//
+type ExperimentalFeature string
+
+// AllExperimentalFeatures are all experimental features.
+//
+// A feature is added when its development starts,
+// and it is removed once the feature is complete and always enabled.
+//
+var AllExperimentalFeatures []ExperimentalFeature
+
+// ExperimentalFeatures is a set of experimental features.
+// The nil set contains no features
+//
+type ExperimentalFeatures map[ExperimentalFeature]struct{}
+
+func NewExperimentalFeatures(features ...ExperimentalFeature) ExperimentalFeatures {
+	result := make(ExperimentalFeatures, len(features))
+	for _, feature := range features {
+		result[feature] = struct{}{}
+	}
+	return result
+}
+
+// Enabled returns true if the given feature is in the set
+//
+func (f ExperimentalFeatures) Enabled(feature ExperimentalFeature) bool {
+	_, ok := f[feature]
+	return ok
+}
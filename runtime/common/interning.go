@@ -0,0 +1,85 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"strings"
+)
+
+// maxInternedStrings is the maximum number of strings retained by an Interner.
+// Strings interned when the table is full are returned as-is.
+//
+const maxInternedStrings = 1 << 16
+
+// Interner is a table of canonical strings.
+//
+// Interning a string returns a canonical instance of it,
+// so equal strings which are retained, e.g. in maps or in types and values,
+// share the same memory instead of being duplicated.
+//
+// An Interner is scoped to a session, e.g. a checker and its sub-checkers,
+// so the strings are released with the session.
+// An Interner is not safe for concurrent use.
+//
+// The checker interns the member names of composite and interface types.
+// Type IDs and qualified identifiers are not interned:
+// they are cached per type, and static types reuse the cached strings of the sema types.
+//
+type Interner struct {
+	strings map[string]string
+}
+
+func NewInterner() *Interner {
+	return &Interner{
+		strings: map[string]string{},
+	}
+}
+
+// Intern returns the canonical instance of the given string.
+//
+func (i *Interner) Intern(s string) string {
+	interned, ok := i.strings[s]
+	if ok {
+		return interned
+	}
+
+	if len(i.strings) >= maxInternedStrings {
+		return s
+	}
+
+	// The given string might be a substring of a larger string, e.g. a program's code.
+	// Copy it, so the interned string does not retain the larger string
+	interned = cloneString(s)
+	i.strings[interned] = interned
+
+	return interned
+}
+
+// Len returns the number of interned strings.
+//
+func (i *Interner) Len() int {
+	return len(i.strings)
+}
+
+func cloneString(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	builder.WriteString(s)
+	return builder.String()
+}
@@ -0,0 +1,88 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestInterner(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("equal strings share memory", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewInterner()
+
+		code := "let foo = foo"
+
+		first := interner.Intern(code[4:7])
+		second := interner.Intern(code[10:13])
+
+		require.Equal(t, "foo", first)
+		require.Equal(t, "foo", second)
+
+		assert.Equal(t, stringData(first), stringData(second))
+
+		assert.Equal(t, 1, interner.Len())
+	})
+
+	t.Run("interned string does not retain original", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewInterner()
+
+		code := "let foo = 1"
+
+		interned := interner.Intern(code[4:7])
+
+		require.Equal(t, "foo", interned)
+		assert.NotEqual(t, stringData(code[4:7]), stringData(interned))
+	})
+
+	t.Run("full table", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewInterner()
+
+		for i := 0; i < maxInternedStrings; i++ {
+			interner.Intern(strconv.Itoa(i))
+		}
+
+		require.Equal(t, maxInternedStrings, interner.Len())
+
+		s := interner.Intern("new")
+		assert.Equal(t, "new", s)
+		assert.Equal(t, maxInternedStrings, interner.Len())
+	})
+}
@@ -138,6 +138,57 @@ func DecodeTypeID(typeID string) (location Location, qualifiedIdentifier string,
 	return decoder(typeID)
 }
 
+// LocationKind describes a kind of location which is defined by an embedder,
+// e.g. locations of programs stored on IPFS, or retrieved from a REST API.
+//
+// Locations of a registered kind participate in type IDs through their prefix,
+// and can be encoded and decoded, e.g. when a value with a type
+// that is declared at such a location is stored.
+//
+type LocationKind struct {
+	// Prefix is the prefix of the IDs and type IDs of locations of the kind.
+	// It must not be used by any other kind of location
+	Prefix string
+	// DecodeTypeID decodes a type ID with the prefix
+	DecodeTypeID TypeIDDecoder
+	// Encode encodes the given location of the kind
+	Encode func(location Location) ([]byte, error)
+	// Decode decodes a location of the kind, which was encoded using Encode
+	Decode func(encoded []byte) (Location, error)
+}
+
+// CustomLocation is implemented by locations of kinds
+// which are registered using RegisterLocationKind
+//
+type CustomLocation interface {
+	Location
+	// LocationKindPrefix returns the prefix of the location's kind
+	LocationKindPrefix() string
+}
+
+var locationKinds = map[string]LocationKind{}
+
+// RegisterLocationKind registers a kind of location.
+// It panics if a kind or type ID decoder is already registered for the prefix
+//
+func RegisterLocationKind(kind LocationKind) {
+	prefix := kind.Prefix
+	if _, ok := locationKinds[prefix]; ok {
+		panic(fmt.Errorf("cannot register location kind for already registered prefix: %s", prefix))
+	}
+
+	RegisterTypeIDDecoder(prefix, kind.DecodeTypeID)
+
+	locationKinds[prefix] = kind
+}
+
+// LookupLocationKind returns the registered kind of location with the given prefix
+//
+func LookupLocationKind(prefix string) (LocationKind, bool) {
+	kind, ok := locationKinds[prefix]
+	return kind, ok
+}
+
 // HasImportLocation
 
 type HasImportLocation interface {
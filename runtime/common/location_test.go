@@ -19,6 +19,7 @@
 package common
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -153,3 +154,44 @@ func TestLocationsInSameAccount(t *testing.T) {
 		)
 	})
 }
+
+func TestRegisterLocationKind(t *testing.T) {
+
+	const prefix = "test-kind"
+
+	decodeTypeID := func(typeID string) (Location, string, error) {
+		pieces := strings.SplitN(typeID, ".", 3)
+		return IdentifierLocation(pieces[1]), pieces[2], nil
+	}
+
+	RegisterLocationKind(LocationKind{
+		Prefix:       prefix,
+		DecodeTypeID: decodeTypeID,
+	})
+
+	kind, ok := LookupLocationKind(prefix)
+	require.True(t, ok)
+	require.Equal(t, prefix, kind.Prefix)
+
+	location, qualifiedIdentifier, err := DecodeTypeID("test-kind.foo.Bar")
+	require.NoError(t, err)
+	require.Equal(t, IdentifierLocation("foo"), location)
+	require.Equal(t, "Bar", qualifiedIdentifier)
+
+	require.Panics(t, func() {
+		RegisterLocationKind(LocationKind{
+			Prefix:       prefix,
+			DecodeTypeID: decodeTypeID,
+		})
+	})
+
+	require.Panics(t, func() {
+		RegisterLocationKind(LocationKind{
+			Prefix:       AddressLocationPrefix,
+			DecodeTypeID: decodeTypeID,
+		})
+	})
+
+	_, ok = LookupLocationKind(AddressLocationPrefix)
+	require.False(t, ok)
+}
@@ -21,44 +21,31 @@
 
 package orderedmap
 
-import (
-	"container/list"
-
-	"github.com/cheekybits/genny/generic"
-)
-
-type KeyType generic.Type
-type ValueType generic.Type
-
-// KeyTypeValueTypeOrderedMap
+// OrderedMap is a map which maintains the insertion order of its entries.
 //
-type KeyTypeValueTypeOrderedMap struct {
-	pairs map[KeyType]*KeyTypeValueTypePair
-	list  *list.List
-}
-
-// NewKeyTypeValueTypeOrderedMap creates a new KeyTypeValueTypeOrderedMap.
-func NewKeyTypeValueTypeOrderedMap() *KeyTypeValueTypeOrderedMap {
-	return &KeyTypeValueTypeOrderedMap{
-		pairs: make(map[KeyType]*KeyTypeValueTypePair),
-		list:  list.New(),
-	}
+// The zero value is an empty map ready to use.
+//
+type OrderedMap[K comparable, V any] struct {
+	pairs  map[K]*Pair[K, V]
+	oldest *Pair[K, V]
+	newest *Pair[K, V]
 }
 
 // Clear removes all entries from this ordered map.
-func (om *KeyTypeValueTypeOrderedMap) Clear() {
-	om.list.Init()
+func (om *OrderedMap[K, V]) Clear() {
 	// NOTE: Range over map is safe, as it is only used to delete entries
 	for key := range om.pairs { //nolint:maprangecheck
 		delete(om.pairs, key)
 	}
+	om.oldest = nil
+	om.newest = nil
 }
 
 // Get returns the value associated with the given key.
 // Returns nil if not found.
 // The second return value indicates if the key is present in the map.
-func (om *KeyTypeValueTypeOrderedMap) Get(key KeyType) (result ValueType, present bool) {
-	var pair *KeyTypeValueTypePair
+func (om *OrderedMap[K, V]) Get(key K) (result V, present bool) {
+	var pair *Pair[K, V]
 	if pair, present = om.pairs[key]; present {
 		return pair.Value, present
 	}
@@ -67,25 +54,35 @@ func (om *KeyTypeValueTypeOrderedMap) Get(key KeyType) (result ValueType, presen
 
 // GetPair returns the key-value pair associated with the given key.
 // Returns nil if not found.
-func (om *KeyTypeValueTypeOrderedMap) GetPair(key KeyType) *KeyTypeValueTypePair {
+func (om *OrderedMap[K, V]) GetPair(key K) *Pair[K, V] {
 	return om.pairs[key]
 }
 
 // Set sets the key-value pair, and returns what `Get` would have returned
 // on that key prior to the call to `Set`.
-func (om *KeyTypeValueTypeOrderedMap) Set(key KeyType, value ValueType) (oldValue ValueType, present bool) {
-	var pair *KeyTypeValueTypePair
+func (om *OrderedMap[K, V]) Set(key K, value V) (oldValue V, present bool) {
+	var pair *Pair[K, V]
 	if pair, present = om.pairs[key]; present {
 		oldValue = pair.Value
 		pair.Value = value
 		return
 	}
 
-	pair = &KeyTypeValueTypePair{
+	if om.pairs == nil {
+		om.pairs = make(map[K]*Pair[K, V])
+	}
+
+	pair = &Pair[K, V]{
 		Key:   key,
 		Value: value,
+		prev:  om.newest,
+	}
+	if om.newest == nil {
+		om.oldest = pair
+	} else {
+		om.newest.next = pair
 	}
-	pair.element = om.list.PushBack(pair)
+	om.newest = pair
 	om.pairs[key] = pair
 
 	return
@@ -93,14 +90,26 @@ func (om *KeyTypeValueTypeOrderedMap) Set(key KeyType, value ValueType) (oldValu
 
 // Delete removes the key-value pair, and returns what `Get` would have returned
 // on that key prior to the call to `Delete`.
-func (om *KeyTypeValueTypeOrderedMap) Delete(key KeyType) (oldValue ValueType, present bool) {
-	var pair *KeyTypeValueTypePair
+func (om *OrderedMap[K, V]) Delete(key K) (oldValue V, present bool) {
+	var pair *Pair[K, V]
 	pair, present = om.pairs[key]
 	if !present {
 		return
 	}
 
-	om.list.Remove(pair.element)
+	if pair.prev == nil {
+		om.oldest = pair.next
+	} else {
+		pair.prev.next = pair.next
+	}
+	if pair.next == nil {
+		om.newest = pair.prev
+	} else {
+		pair.next.prev = pair.prev
+	}
+	pair.prev = nil
+	pair.next = nil
+
 	delete(om.pairs, key)
 	oldValue = pair.Value
 
@@ -108,23 +117,23 @@ func (om *KeyTypeValueTypeOrderedMap) Delete(key KeyType) (oldValue ValueType, p
 }
 
 // Len returns the length of the ordered map.
-func (om *KeyTypeValueTypeOrderedMap) Len() int {
+func (om *OrderedMap[K, V]) Len() int {
 	return len(om.pairs)
 }
 
 // Oldest returns a pointer to the oldest pair.
-func (om *KeyTypeValueTypeOrderedMap) Oldest() *KeyTypeValueTypePair {
-	return listElementToKeyTypeValueTypePair(om.list.Front())
+func (om *OrderedMap[K, V]) Oldest() *Pair[K, V] {
+	return om.oldest
 }
 
 // Newest returns a pointer to the newest pair.
-func (om *KeyTypeValueTypeOrderedMap) Newest() *KeyTypeValueTypePair {
-	return listElementToKeyTypeValueTypePair(om.list.Back())
+func (om *OrderedMap[K, V]) Newest() *Pair[K, V] {
+	return om.newest
 }
 
 // Foreach iterates over the entries of the map in the insertion order, and invokes
 // the provided function for each key-value pair.
-func (om *KeyTypeValueTypeOrderedMap) Foreach(f func(key KeyType, value ValueType)) {
+func (om *OrderedMap[K, V]) Foreach(f func(key K, value V)) {
 	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
 		f(pair.Key, pair.Value)
 	}
@@ -133,7 +142,7 @@ func (om *KeyTypeValueTypeOrderedMap) Foreach(f func(key KeyType, value ValueTyp
 // ForeachWithError iterates over the entries of the map in the insertion order,
 // and invokes the provided function for each key-value pair.
 // If the passed function returns an error, iteration breaks and the error is returned.
-func (om *KeyTypeValueTypeOrderedMap) ForeachWithError(f func(key KeyType, value ValueType) error) error {
+func (om *OrderedMap[K, V]) ForeachWithError(f func(key K, value V) error) error {
 	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
 		err := f(pair.Key, pair.Value)
 		if err != nil {
@@ -143,28 +152,22 @@ func (om *KeyTypeValueTypeOrderedMap) ForeachWithError(f func(key KeyType, value
 	return nil
 }
 
-// KeyTypeValueTypePair
+// Pair is an entry in an OrderedMap
 //
-type KeyTypeValueTypePair struct {
-	Key   KeyType
-	Value ValueType
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
 
-	element *list.Element
+	prev *Pair[K, V]
+	next *Pair[K, V]
 }
 
 // Next returns a pointer to the next pair.
-func (p *KeyTypeValueTypePair) Next() *KeyTypeValueTypePair {
-	return listElementToKeyTypeValueTypePair(p.element.Next())
+func (p *Pair[K, V]) Next() *Pair[K, V] {
+	return p.next
 }
 
 // Prev returns a pointer to the previous pair.
-func (p *KeyTypeValueTypePair) Prev() *KeyTypeValueTypePair {
-	return listElementToKeyTypeValueTypePair(p.element.Prev())
-}
-
-func listElementToKeyTypeValueTypePair(element *list.Element) *KeyTypeValueTypePair {
-	if element == nil {
-		return nil
-	}
-	return element.Value.(*KeyTypeValueTypePair)
+func (p *Pair[K, V]) Prev() *Pair[K, V] {
+	return p.prev
 }
@@ -19,11 +19,10 @@
  *
  */
 
-//go:generate go run github.com/cheekybits/genny -pkg=orderedmap -in=orderedmap.go -out=string_fruit_orderedmap_test.go gen "KeyType=string ValueType=*Fruit"
-
 package orderedmap
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,22 +35,30 @@ type Fruit struct {
 	price float32
 }
 
+type FruitOrderedMap = OrderedMap[string, *Fruit]
+
 // TestOrderedMapOperations tests the operations of the generic map.
 func TestOrderedMapOperations(t *testing.T) {
 
 	t.Parallel()
 
-	t.Run("test constructor", func(t *testing.T) {
-		om := NewKeyTypeValueTypeOrderedMap()
-		require.NotNil(t, om)
-		require.NotNil(t, om.list)
-		require.NotNil(t, om.pairs)
-		assert.Equal(t, 0, len(om.pairs))
-		assert.Equal(t, 0, om.list.Len())
+	t.Run("test zero value", func(t *testing.T) {
+		om := &FruitOrderedMap{}
+		assert.Equal(t, 0, om.Len())
+		assert.Nil(t, om.Oldest())
+		assert.Nil(t, om.Newest())
+
+		value, ok := om.Get("apple")
+		require.False(t, ok)
+		require.Nil(t, value)
+
+		deletedItem, ok := om.Delete("apple")
+		require.False(t, ok)
+		require.Nil(t, deletedItem)
 	})
 
 	t.Run("test map set", func(t *testing.T) {
-		om := NewKeyTypeValueTypeOrderedMap()
+		om := &FruitOrderedMap{}
 		require.NotNil(t, om)
 
 		insertedValues := []*Fruit{
@@ -69,13 +76,11 @@ func TestOrderedMapOperations(t *testing.T) {
 			assert.Nil(t, oldValue)
 		}
 
-		require.NotNil(t, om.list)
-		require.NotNil(t, om.pairs)
 		require.Equal(t, len(insertedValues), len(om.pairs))
-		require.Equal(t, len(insertedValues), om.list.Len())
+		require.Equal(t, len(insertedValues), listLen(om))
 
 		// Check map's internal values
-		element := om.list.Front()
+		pair := om.Oldest()
 		for _, value := range insertedValues {
 			name := value.name
 
@@ -85,18 +90,16 @@ func TestOrderedMapOperations(t *testing.T) {
 			assert.Equal(t, value, keyValuePair.Value)
 
 			// check the internal list
-			assert.Equal(t, keyValuePair, element.Value)
-			element = element.Next()
+			assert.Same(t, keyValuePair, pair)
+			pair = pair.Next()
 		}
 	})
 
 	t.Run("test map update", func(t *testing.T) {
 		om, insertedValues := createAndPopulateMap(t)
 
-		require.NotNil(t, om.list)
-		require.NotNil(t, om.pairs)
 		require.Equal(t, len(insertedValues), len(om.pairs))
-		require.Equal(t, len(insertedValues), om.list.Len())
+		require.Equal(t, len(insertedValues), listLen(om))
 
 		const updateItemIndex = 1
 		insertedOldValue := insertedValues[updateItemIndex]
@@ -109,7 +112,7 @@ func TestOrderedMapOperations(t *testing.T) {
 		assert.True(t, updated)
 		assert.Equal(t, insertedOldValue, oldValue)
 
-		element := om.list.Front()
+		pair := om.Oldest()
 		for i := 0; i < len(insertedValues); i++ {
 			var value *Fruit
 			if i == updateItemIndex {
@@ -126,8 +129,8 @@ func TestOrderedMapOperations(t *testing.T) {
 			assert.Equal(t, value, keyValuePair.Value)
 
 			// check the internal list
-			assert.Equal(t, keyValuePair, element.Value)
-			element = element.Next()
+			assert.Same(t, keyValuePair, pair)
+			pair = pair.Next()
 		}
 	})
 
@@ -158,7 +161,7 @@ func TestOrderedMapOperations(t *testing.T) {
 			name := insertedValue.name
 			pair := om.GetPair(name)
 			require.NotNil(t, pair)
-			require.IsType(t, &KeyTypeValueTypePair{}, pair)
+			require.IsType(t, &Pair[string, *Fruit]{}, pair)
 			assert.Equal(t, name, pair.Key)
 			assert.Equal(t, insertedValue, pair.Value)
 		}
@@ -187,10 +190,10 @@ func TestOrderedMapOperations(t *testing.T) {
 		assert.Equal(t, insertedValues[deleteItemIndex], deletedItem)
 
 		require.Equal(t, len(insertedValues)-1, len(om.pairs))
-		require.Equal(t, len(insertedValues)-1, om.list.Len())
+		require.Equal(t, len(insertedValues)-1, listLen(om))
 		require.Equal(t, len(insertedValues)-1, om.Len())
 
-		element := om.list.Front()
+		pair := om.Oldest()
 		for i := 0; i < len(insertedValues); i++ {
 			if i == deleteItemIndex {
 				continue
@@ -205,8 +208,8 @@ func TestOrderedMapOperations(t *testing.T) {
 			assert.Equal(t, value, keyValuePair.Value)
 
 			// check the internal list
-			assert.Equal(t, keyValuePair, element.Value)
-			element = element.Next()
+			assert.Same(t, keyValuePair, pair)
+			pair = pair.Next()
 		}
 	})
 
@@ -217,10 +220,10 @@ func TestOrderedMapOperations(t *testing.T) {
 		require.False(t, ok)
 		require.Nil(t, deletedItem)
 		require.Equal(t, len(insertedValues), len(om.pairs))
-		require.Equal(t, len(insertedValues), om.list.Len())
+		require.Equal(t, len(insertedValues), listLen(om))
 		require.Equal(t, len(insertedValues), om.Len())
 
-		element := om.list.Front()
+		pair := om.Oldest()
 		for _, insertedValue := range insertedValues {
 			name := insertedValue.name
 
@@ -230,8 +233,8 @@ func TestOrderedMapOperations(t *testing.T) {
 			assert.Equal(t, insertedValue, keyValuePair.Value)
 
 			// check the internal list
-			assert.Equal(t, keyValuePair, element.Value)
-			element = element.Next()
+			assert.Same(t, keyValuePair, pair)
+			pair = pair.Next()
 		}
 	})
 
@@ -240,7 +243,7 @@ func TestOrderedMapOperations(t *testing.T) {
 		value := om.Oldest()
 
 		require.NotNil(t, value)
-		require.IsType(t, &KeyTypeValueTypePair{}, value)
+		require.IsType(t, &Pair[string, *Fruit]{}, value)
 
 		expected := insertedValues[0]
 		assert.Equal(t, expected.name, value.Key)
@@ -248,7 +251,7 @@ func TestOrderedMapOperations(t *testing.T) {
 	})
 
 	t.Run("test map get oldest for empty map", func(t *testing.T) {
-		om := NewKeyTypeValueTypeOrderedMap()
+		om := &FruitOrderedMap{}
 		require.Nil(t, om.Oldest())
 	})
 
@@ -257,7 +260,7 @@ func TestOrderedMapOperations(t *testing.T) {
 		value := om.Newest()
 
 		require.NotNil(t, value)
-		require.IsType(t, &KeyTypeValueTypePair{}, value)
+		require.IsType(t, &Pair[string, *Fruit]{}, value)
 
 		expected := insertedValues[len(insertedValues)-1]
 		assert.Equal(t, expected.name, value.Key)
@@ -265,7 +268,7 @@ func TestOrderedMapOperations(t *testing.T) {
 	})
 
 	t.Run("test map get newest for empty map", func(t *testing.T) {
-		om := NewKeyTypeValueTypeOrderedMap()
+		om := &FruitOrderedMap{}
 		require.Nil(t, om.Newest())
 	})
 
@@ -273,22 +276,20 @@ func TestOrderedMapOperations(t *testing.T) {
 		om, insertedValues := createAndPopulateMap(t)
 
 		var loopResult []*Fruit
-		om.Foreach(func(key KeyType, value ValueType) {
-			switch value := value.(type) {
-			case *Fruit:
-				loopResult = append(loopResult, value)
-			}
+		om.Foreach(func(key string, value *Fruit) {
+			loopResult = append(loopResult, value)
 		})
 
 		assert.Equal(t, insertedValues, loopResult)
 	})
 }
 
-// TestGeneratedMapOperations tests the basic functionality of a generated map.
-// This is to make sure any update to the generator would not cause any regression issues.
-func TestGeneratedMapOperations(t *testing.T) {
-	fruits := NewStringFruitOrderedMap()
-	require.NotNil(t, fruits)
+// TestOrderedMapUsage tests the basic functionality of an instantiated map.
+func TestOrderedMapUsage(t *testing.T) {
+
+	t.Parallel()
+
+	fruits := &FruitOrderedMap{}
 
 	apple := &Fruit{name: "apple", color: "red", price: 1.5}
 	oldValue, updated := fruits.Set(apple.name, apple)
@@ -328,12 +329,35 @@ func TestGeneratedMapOperations(t *testing.T) {
 
 	assert.Equal(t, 2, fruits.Len())
 
+	// Deleting the remaining entries and inserting again
+	// maintains the insertion order
+
+	fruits.Delete("apple")
+	fruits.Delete("mango")
+
+	assert.Equal(t, 0, fruits.Len())
+	assert.Nil(t, fruits.Oldest())
+	assert.Nil(t, fruits.Newest())
+
+	fruits.Set(orange.name, orange)
+	fruits.Set(mango.name, mango)
+
+	assert.Equal(t, orange, fruits.Oldest().Value)
+	assert.Equal(t, mango, fruits.Newest().Value)
+	assert.Equal(t, orange, fruits.Newest().Prev().Value)
+	assert.Nil(t, fruits.Oldest().Prev())
+
+	fruits.Clear()
+
+	assert.Equal(t, 0, fruits.Len())
+	assert.Nil(t, fruits.Oldest())
+	assert.Nil(t, fruits.Newest())
 }
 
 // Utility functions
 
-func createAndPopulateMap(t *testing.T) (*KeyTypeValueTypeOrderedMap, []*Fruit) {
-	om := NewKeyTypeValueTypeOrderedMap()
+func createAndPopulateMap(t *testing.T) (*FruitOrderedMap, []*Fruit) {
+	om := &FruitOrderedMap{}
 	require.NotNil(t, om)
 
 	fruits := []*Fruit{
@@ -346,10 +370,83 @@ func createAndPopulateMap(t *testing.T) (*KeyTypeValueTypeOrderedMap, []*Fruit)
 		om.Set(fruit.name, fruit)
 	}
 
-	require.NotNil(t, om.list)
-	require.NotNil(t, om.pairs)
 	require.Equal(t, 3, len(om.pairs))
-	require.Equal(t, 3, om.list.Len())
+	require.Equal(t, 3, listLen(om))
 
 	return om, fruits
 }
+
+func listLen(om *FruitOrderedMap) int {
+	var count int
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		count++
+	}
+	return count
+}
+
+func BenchmarkOrderedMap(b *testing.B) {
+
+	const size = 100
+
+	keys := make([]string, size)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	newMap := func() *OrderedMap[string, int] {
+		om := &OrderedMap[string, int]{}
+		for i, key := range keys {
+			om.Set(key, i)
+		}
+		return om
+	}
+
+	b.Run("Set", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			newMap()
+		}
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		om := newMap()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			for _, key := range keys {
+				_, _ = om.Get(key)
+			}
+		}
+	})
+
+	b.Run("Delete", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			om := newMap()
+			b.StartTimer()
+
+			for _, key := range keys {
+				om.Delete(key)
+			}
+		}
+	})
+
+	b.Run("Foreach", func(b *testing.B) {
+		om := newMap()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			var sum int
+			om.Foreach(func(_ string, value int) {
+				sum += value
+			})
+		}
+	})
+}
@@ -0,0 +1,53 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// StorageKey is the key of a value in account storage.
+//
+// The domain is the storage domain of the value, e.g. the identifier of a path domain,
+// and the identifier is the key of the value in the domain's storage map.
+//
+// The domain is a string, so it is compatible with the storage domains
+// used as keys of the account's registers, e.g. "storage" or "contract".
+//
+type StorageKey struct {
+	Domain     string
+	Identifier string
+}
+
+// NewPathStorageKey returns the key of the value stored at the path
+// with the given domain and identifier
+//
+func NewPathStorageKey(domain PathDomain, identifier string) StorageKey {
+	return StorageKey{
+		Domain:     domain.Identifier(),
+		Identifier: identifier,
+	}
+}
+
+// PathDomain returns the path domain of the key's domain,
+// or PathDomainUnknown if the domain is not a path domain
+//
+func (k StorageKey) PathDomain() PathDomain {
+	return PathDomainFromIdentifier(k.Domain)
+}
+
+func (k StorageKey) String() string {
+	return "/" + k.Domain + "/" + k.Identifier
+}
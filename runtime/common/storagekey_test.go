@@ -0,0 +1,48 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageKey(t *testing.T) {
+
+	t.Parallel()
+
+	key := NewPathStorageKey(PathDomainPublic, "foo")
+
+	assert.Equal(t,
+		StorageKey{
+			Domain:     "public",
+			Identifier: "foo",
+		},
+		key,
+	)
+	assert.Equal(t, PathDomainPublic, key.PathDomain())
+	assert.Equal(t, "/public/foo", key.String())
+
+	contractKey := StorageKey{
+		Domain:     "contract",
+		Identifier: "Test",
+	}
+	assert.Equal(t, PathDomainUnknown, contractKey.PathDomain())
+}
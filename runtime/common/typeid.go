@@ -0,0 +1,704 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypeIDVersion is the version of the type ID scheme.
+//
+// Type IDs are persisted, e.g. in stored values and in events,
+// so the version must be incremented whenever the format of type IDs changes.
+//
+// The type ID of a type is defined by the following grammar (EBNF):
+//
+//     Type           = ReferenceType { "?" } .
+//     ReferenceType  = [ Authorization ] "&" RestrictedType
+//                    | RestrictedType .
+//     Authorization  = "auth" [ "(" Identifier { "," [ " " ] Identifier } ")" ] [ " " ] .
+//     RestrictedType = PrimaryType [ "{" [ Identifier { "," Identifier } ] "}" ] .
+//     PrimaryType    = "[" Type "]"
+//                    | "[" Type ";" Size "]"
+//                    | "{" Type ":" Type "}"
+//                    | "(" [ "<" [ TypeList ] ">" ] "(" [ TypeList ] ")" ":" Type ")"
+//                    | "Capability" [ "<" Type ">" ]
+//                    | Identifier .
+//     TypeList       = Type { "," Type } .
+//     Size           = Digit { Digit } .
+//     Identifier     = IdentifierChar { IdentifierChar } .
+//     IdentifierChar = "_" | "." | Letter | Digit .
+//
+// Identifiers are the IDs of nominal types, e.g. `Int` or `A.0000000000000001.FungibleToken.Vault`,
+// and are decoded using the registered type ID decoders (see DecodeTypeID).
+//
+// NOTE: The reference prefix binds tighter than the optional suffix,
+// i.e. `&R?` is an optional reference, like in Cadence programs.
+//
+// The constructors below produce the canonical form of type IDs:
+// authorizations are separated from the reference by a space,
+// and the entitlements of a reference are sorted and separated by ", ".
+// The parser also accepts the non-canonical forms which are allowed by the grammar.
+//
+const TypeIDVersion = 1
+
+func NewOptionalTypeID(ty TypeID) TypeID {
+	return ty + "?"
+}
+
+func NewVariableSizedTypeID(elementType TypeID) TypeID {
+	return "[" + elementType + "]"
+}
+
+func NewConstantSizedTypeID(elementType TypeID, size int64) TypeID {
+	return TypeID(fmt.Sprintf("[%s;%d]", elementType, size))
+}
+
+func NewDictionaryTypeID(keyType, valueType TypeID) TypeID {
+	return "{" + keyType + ":" + valueType + "}"
+}
+
+// NewReferenceTypeID returns the type ID of a reference type.
+// The entitlements are only included if the reference is not fully authorized.
+//
+func NewReferenceTypeID(authorized bool, entitlements []TypeID, referencedType TypeID) TypeID {
+	var builder strings.Builder
+	if authorized {
+		builder.WriteString("auth ")
+	} else if len(entitlements) > 0 {
+		sortedEntitlements := make([]string, len(entitlements))
+		for i, entitlement := range entitlements {
+			sortedEntitlements[i] = string(entitlement)
+		}
+		sort.Strings(sortedEntitlements)
+
+		builder.WriteString("auth(")
+		builder.WriteString(strings.Join(sortedEntitlements, ", "))
+		builder.WriteString(") ")
+	}
+	builder.WriteRune('&')
+	builder.WriteString(string(referencedType))
+	return TypeID(builder.String())
+}
+
+func NewRestrictedTypeID(restrictedType TypeID, restrictions []TypeID) TypeID {
+	var builder strings.Builder
+	builder.WriteString(string(restrictedType))
+	builder.WriteRune('{')
+	for i, restriction := range restrictions {
+		if i > 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteString(string(restriction))
+	}
+	builder.WriteRune('}')
+	return TypeID(builder.String())
+}
+
+// NewCapabilityTypeID returns the type ID of a capability type.
+// The borrow type is empty for unparameterized capability types.
+//
+func NewCapabilityTypeID(borrowType TypeID) TypeID {
+	if borrowType == "" {
+		return "Capability"
+	}
+	return "Capability<" + borrowType + ">"
+}
+
+func NewFunctionTypeID(typeParameters []TypeID, parameters []TypeID, returnType TypeID) TypeID {
+	var builder strings.Builder
+	builder.WriteRune('(')
+	if len(typeParameters) > 0 {
+		builder.WriteRune('<')
+		writeTypeIDList(&builder, typeParameters)
+		builder.WriteRune('>')
+	}
+	builder.WriteRune('(')
+	writeTypeIDList(&builder, parameters)
+	builder.WriteString("):")
+	builder.WriteString(string(returnType))
+	builder.WriteRune(')')
+	return TypeID(builder.String())
+}
+
+func writeTypeIDList(builder *strings.Builder, typeIDs []TypeID) {
+	for i, typeID := range typeIDs {
+		if i > 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteString(string(typeID))
+	}
+}
+
+// ParsedType is the result of parsing a type ID.
+// The ID of a parsed type is the canonical form of the parsed type ID.
+//
+type ParsedType interface {
+	isParsedType()
+	ID() TypeID
+}
+
+// ParsedNominalType is a composite or interface type, or a built-in type, e.g. `Int`.
+// The location is nil for built-in types.
+//
+type ParsedNominalType struct {
+	Location            Location
+	QualifiedIdentifier string
+}
+
+func (*ParsedNominalType) isParsedType() {}
+
+func (t *ParsedNominalType) ID() TypeID {
+	return NewTypeIDFromQualifiedName(t.Location, t.QualifiedIdentifier)
+}
+
+type ParsedOptionalType struct {
+	Type ParsedType
+}
+
+func (*ParsedOptionalType) isParsedType() {}
+
+func (t *ParsedOptionalType) ID() TypeID {
+	return NewOptionalTypeID(t.Type.ID())
+}
+
+type ParsedVariableSizedType struct {
+	ElementType ParsedType
+}
+
+func (*ParsedVariableSizedType) isParsedType() {}
+
+func (t *ParsedVariableSizedType) ID() TypeID {
+	return NewVariableSizedTypeID(t.ElementType.ID())
+}
+
+type ParsedConstantSizedType struct {
+	ElementType ParsedType
+	Size        int64
+}
+
+func (*ParsedConstantSizedType) isParsedType() {}
+
+func (t *ParsedConstantSizedType) ID() TypeID {
+	return NewConstantSizedTypeID(t.ElementType.ID(), t.Size)
+}
+
+type ParsedDictionaryType struct {
+	KeyType   ParsedType
+	ValueType ParsedType
+}
+
+func (*ParsedDictionaryType) isParsedType() {}
+
+func (t *ParsedDictionaryType) ID() TypeID {
+	return NewDictionaryTypeID(t.KeyType.ID(), t.ValueType.ID())
+}
+
+type ParsedReferenceType struct {
+	Authorized   bool
+	Entitlements []*ParsedNominalType
+	Type         ParsedType
+}
+
+func (*ParsedReferenceType) isParsedType() {}
+
+func (t *ParsedReferenceType) ID() TypeID {
+	entitlements := make([]TypeID, len(t.Entitlements))
+	for i, entitlement := range t.Entitlements {
+		entitlements[i] = entitlement.ID()
+	}
+	return NewReferenceTypeID(t.Authorized, entitlements, t.Type.ID())
+}
+
+type ParsedRestrictedType struct {
+	Type         ParsedType
+	Restrictions []*ParsedNominalType
+}
+
+func (*ParsedRestrictedType) isParsedType() {}
+
+func (t *ParsedRestrictedType) ID() TypeID {
+	restrictions := make([]TypeID, len(t.Restrictions))
+	for i, restriction := range t.Restrictions {
+		restrictions[i] = restriction.ID()
+	}
+	return NewRestrictedTypeID(t.Type.ID(), restrictions)
+}
+
+// ParsedCapabilityType is a capability type.
+// The borrow type is nil for unparameterized capability types.
+//
+type ParsedCapabilityType struct {
+	BorrowType ParsedType
+}
+
+func (*ParsedCapabilityType) isParsedType() {}
+
+func (t *ParsedCapabilityType) ID() TypeID {
+	var borrowType TypeID
+	if t.BorrowType != nil {
+		borrowType = t.BorrowType.ID()
+	}
+	return NewCapabilityTypeID(borrowType)
+}
+
+type ParsedFunctionType struct {
+	TypeParameters []ParsedType
+	Parameters     []ParsedType
+	ReturnType     ParsedType
+}
+
+func (*ParsedFunctionType) isParsedType() {}
+
+func (t *ParsedFunctionType) ID() TypeID {
+	return NewFunctionTypeID(
+		parsedTypeIDs(t.TypeParameters),
+		parsedTypeIDs(t.Parameters),
+		t.ReturnType.ID(),
+	)
+}
+
+func parsedTypeIDs(types []ParsedType) []TypeID {
+	typeIDs := make([]TypeID, len(types))
+	for i, ty := range types {
+		typeIDs[i] = ty.ID()
+	}
+	return typeIDs
+}
+
+// ErrInvalidTypeID is returned by ParseTypeID
+// if the given string is not a valid type ID
+//
+var ErrInvalidTypeID = errors.New("invalid type ID")
+
+// ParseTypeID parses the given type ID, e.g. `A.0x1.FungibleToken.Vault`,
+// `[String]`, or `&AnyResource{A.0x1.NFT.Receiver}`.
+// See TypeIDVersion for the grammar of type IDs.
+//
+func ParseTypeID(typeID string) (ParsedType, error) {
+	p := &typeIDParser{
+		input: typeID,
+	}
+
+	ty, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.atEnd() {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return ty, nil
+}
+
+type typeIDParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeIDParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(
+		"%w: %q: %s at position %d",
+		ErrInvalidTypeID,
+		p.input,
+		fmt.Sprintf(format, args...),
+		p.pos,
+	)
+}
+
+func (p *typeIDParser) atEnd() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *typeIDParser) peek() byte {
+	if p.atEnd() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *typeIDParser) accept(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *typeIDParser) acceptPrefix(prefix string) bool {
+	if !strings.HasPrefix(p.input[p.pos:], prefix) {
+		return false
+	}
+	p.pos += len(prefix)
+	return true
+}
+
+func (p *typeIDParser) expect(c byte) error {
+	if p.atEnd() {
+		return p.errorf("expected %q, got end of input", c)
+	}
+	if !p.accept(c) {
+		return p.errorf("expected %q, got %q", c, p.input[p.pos])
+	}
+	return nil
+}
+
+func isTypeIDIdentifierChar(c byte) bool {
+	return c == '_' ||
+		c == '.' ||
+		('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
+
+func (p *typeIDParser) parseIdentifier() (string, error) {
+	start := p.pos
+	for !p.atEnd() && isTypeIDIdentifierChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.atEnd() {
+			return "", p.errorf("expected type, got end of input")
+		}
+		return "", p.errorf("expected type, got %q", p.input[p.pos])
+	}
+	return p.input[start:p.pos], nil
+}
+
+func (p *typeIDParser) parseType() (ParsedType, error) {
+	ty, err := p.parseReferenceType()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept('?') {
+		ty = &ParsedOptionalType{Type: ty}
+	}
+
+	return ty, nil
+}
+
+func (p *typeIDParser) parseReferenceType() (ParsedType, error) {
+	start := p.pos
+
+	authorized := false
+	var entitlements []*ParsedNominalType
+
+	if p.acceptPrefix("auth") {
+		if p.accept('(') {
+			var err error
+			entitlements, err = p.parseNominalTypeList(')', true)
+			if err != nil {
+				return nil, err
+			}
+			if len(entitlements) == 0 {
+				return nil, p.errorf("expected entitlements")
+			}
+		} else {
+			authorized = true
+		}
+
+		p.accept(' ')
+
+		// `auth` is not a keyword in type IDs:
+		// if it is not followed by a reference, it is an identifier
+
+		if p.peek() != '&' {
+			if p.pos != start+len("auth") {
+				return nil, p.errorf("expected %q", '&')
+			}
+			p.pos = start
+			authorized = false
+		}
+	}
+
+	if !p.accept('&') {
+		return p.parseRestrictedType()
+	}
+
+	ty, err := p.parseRestrictedType()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParsedReferenceType{
+		Authorized:   authorized,
+		Entitlements: entitlements,
+		Type:         ty,
+	}, nil
+}
+
+func (p *typeIDParser) parseRestrictedType() (ParsedType, error) {
+	ty, err := p.parsePrimaryType()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept('{') {
+		return ty, nil
+	}
+
+	restrictions, err := p.parseNominalTypeList('}', false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParsedRestrictedType{
+		Type:         ty,
+		Restrictions: restrictions,
+	}, nil
+}
+
+func (p *typeIDParser) parsePrimaryType() (ParsedType, error) {
+	switch p.peek() {
+	case '[':
+		return p.parseArrayType()
+	case '{':
+		return p.parseDictionaryType()
+	case '(':
+		return p.parseFunctionType()
+	}
+
+	start := p.pos
+
+	identifier, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	if identifier == "Capability" {
+		return p.parseCapabilityType()
+	}
+
+	p.pos = start
+	return p.parseNominalType()
+}
+
+func (p *typeIDParser) parseNominalType() (*ParsedNominalType, error) {
+	start := p.pos
+
+	typeID, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	location, qualifiedIdentifier, err := DecodeTypeID(typeID)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("%s", err)
+	}
+
+	return &ParsedNominalType{
+		Location:            location,
+		QualifiedIdentifier: qualifiedIdentifier,
+	}, nil
+}
+
+// parseNominalTypeList parses a comma-separated list of nominal types,
+// up to and including the given closing delimiter.
+// If spaces is true, each comma may be followed by a space
+//
+func (p *typeIDParser) parseNominalTypeList(end byte, spaces bool) ([]*ParsedNominalType, error) {
+	var types []*ParsedNominalType
+
+	if p.accept(end) {
+		return types, nil
+	}
+
+	for {
+		ty, err := p.parseNominalType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, ty)
+
+		if p.accept(end) {
+			return types, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		if spaces {
+			p.accept(' ')
+		}
+	}
+}
+
+// parseTypeList parses a comma-separated list of types,
+// up to and including the given closing delimiter
+//
+func (p *typeIDParser) parseTypeList(end byte) ([]ParsedType, error) {
+	var types []ParsedType
+
+	if p.accept(end) {
+		return types, nil
+	}
+
+	for {
+		ty, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, ty)
+
+		if p.accept(end) {
+			return types, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *typeIDParser) parseArrayType() (ParsedType, error) {
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+
+	elementType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept(']') {
+		return &ParsedVariableSizedType{
+			ElementType: elementType,
+		}, nil
+	}
+
+	if err := p.expect(';'); err != nil {
+		return nil, err
+	}
+
+	start := p.pos
+	for !p.atEnd() && '0' <= p.input[p.pos] && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+
+	size, err := strconv.ParseInt(p.input[start:p.pos], 10, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid array size")
+	}
+
+	if err := p.expect(']'); err != nil {
+		return nil, err
+	}
+
+	return &ParsedConstantSizedType{
+		ElementType: elementType,
+		Size:        size,
+	}, nil
+}
+
+func (p *typeIDParser) parseDictionaryType() (ParsedType, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	keyType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(':'); err != nil {
+		return nil, err
+	}
+
+	valueType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect('}'); err != nil {
+		return nil, err
+	}
+
+	return &ParsedDictionaryType{
+		KeyType:   keyType,
+		ValueType: valueType,
+	}, nil
+}
+
+func (p *typeIDParser) parseFunctionType() (ParsedType, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	var typeParameters []ParsedType
+	if p.accept('<') {
+		var err error
+		typeParameters, err = p.parseTypeList('>')
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	parameters, err := p.parseTypeList(')')
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(':'); err != nil {
+		return nil, err
+	}
+
+	returnType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+
+	return &ParsedFunctionType{
+		TypeParameters: typeParameters,
+		Parameters:     parameters,
+		ReturnType:     returnType,
+	}, nil
+}
+
+func (p *typeIDParser) parseCapabilityType() (ParsedType, error) {
+	if !p.accept('<') {
+		return &ParsedCapabilityType{}, nil
+	}
+
+	borrowType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect('>'); err != nil {
+		return nil, err
+	}
+
+	return &ParsedCapabilityType{
+		BorrowType: borrowType,
+	}, nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTypeIDs(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, TypeID("Int?"), NewOptionalTypeID("Int"))
+	assert.Equal(t, TypeID("[Int]"), NewVariableSizedTypeID("Int"))
+	assert.Equal(t, TypeID("[Int;2]"), NewConstantSizedTypeID("Int", 2))
+	assert.Equal(t, TypeID("{String:Int}"), NewDictionaryTypeID("String", "Int"))
+	assert.Equal(t, TypeID("&Int"), NewReferenceTypeID(false, nil, "Int"))
+	assert.Equal(t, TypeID("auth &Int"), NewReferenceTypeID(true, nil, "Int"))
+	assert.Equal(t,
+		TypeID("auth(S.test.A, S.test.B) &Int"),
+		NewReferenceTypeID(false, []TypeID{"S.test.B", "S.test.A"}, "Int"),
+	)
+	assert.Equal(t, TypeID("AnyStruct{S.test.I1,S.test.I2}"), NewRestrictedTypeID("AnyStruct", []TypeID{"S.test.I1", "S.test.I2"}))
+	assert.Equal(t, TypeID("AnyStruct{}"), NewRestrictedTypeID("AnyStruct", nil))
+	assert.Equal(t, TypeID("Capability"), NewCapabilityTypeID(""))
+	assert.Equal(t, TypeID("Capability<&Int>"), NewCapabilityTypeID("&Int"))
+	assert.Equal(t, TypeID("((Int,String):Bool)"), NewFunctionTypeID(nil, []TypeID{"Int", "String"}, "Bool"))
+	assert.Equal(t, TypeID("(<AnyStruct>():Void)"), NewFunctionTypeID([]TypeID{"AnyStruct"}, nil, "Void"))
+}
+
+func TestParseTypeID(t *testing.T) {
+
+	t.Parallel()
+
+	intType := &ParsedNominalType{QualifiedIdentifier: "Int"}
+
+	type testCase struct {
+		typeID   string
+		expected ParsedType
+	}
+
+	testCases := []testCase{
+		{"Int", intType},
+		{"authority", &ParsedNominalType{QualifiedIdentifier: "authority"}},
+		{
+			"S.test.Foo.Bar",
+			&ParsedNominalType{
+				Location:            StringLocation("test"),
+				QualifiedIdentifier: "Foo.Bar",
+			},
+		},
+		{"Int??", &ParsedOptionalType{Type: &ParsedOptionalType{Type: intType}}},
+		{"[Int]", &ParsedVariableSizedType{ElementType: intType}},
+		{"[Int;3]", &ParsedConstantSizedType{ElementType: intType, Size: 3}},
+		{"{Int:[Int]}", &ParsedDictionaryType{KeyType: intType, ValueType: &ParsedVariableSizedType{ElementType: intType}}},
+		{"&Int?", &ParsedOptionalType{Type: &ParsedReferenceType{Type: intType}}},
+		{"auth &Int", &ParsedReferenceType{Authorized: true, Type: intType}},
+		{
+			"auth(S.test.E1, S.test.E2) &Int",
+			&ParsedReferenceType{
+				Entitlements: []*ParsedNominalType{
+					{Location: StringLocation("test"), QualifiedIdentifier: "E1"},
+					{Location: StringLocation("test"), QualifiedIdentifier: "E2"},
+				},
+				Type: intType,
+			},
+		},
+		{
+			"&AnyResource{S.test.R}",
+			&ParsedReferenceType{
+				Type: &ParsedRestrictedType{
+					Type: &ParsedNominalType{QualifiedIdentifier: "AnyResource"},
+					Restrictions: []*ParsedNominalType{
+						{Location: StringLocation("test"), QualifiedIdentifier: "R"},
+					},
+				},
+			},
+		},
+		{"Capability", &ParsedCapabilityType{}},
+		{"Capability<&Int>", &ParsedCapabilityType{BorrowType: &ParsedReferenceType{Type: intType}}},
+		{
+			"(<Int>(Int,Int):Int)",
+			&ParsedFunctionType{
+				TypeParameters: []ParsedType{intType},
+				Parameters:     []ParsedType{intType, intType},
+				ReturnType:     intType,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.typeID, func(t *testing.T) {
+
+			t.Parallel()
+
+			ty, err := ParseTypeID(testCase.typeID)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, ty)
+			assert.Equal(t, TypeID(testCase.typeID), ty.ID())
+		})
+	}
+}
+
+func TestParseTypeID_Canonical(t *testing.T) {
+
+	t.Parallel()
+
+	for typeID, expected := range map[string]TypeID{
+		"auth&Int":                       "auth &Int",
+		"auth(S.test.B,S.test.A)&Int":    "auth(S.test.A, S.test.B) &Int",
+		"auth(S.test.B, S.test.A) &Int?": "auth(S.test.A, S.test.B) &Int?",
+	} {
+		ty, err := ParseTypeID(typeID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, ty.ID())
+	}
+}
+
+func TestParseTypeID_Invalid(t *testing.T) {
+
+	t.Parallel()
+
+	for _, typeID := range []string{
+		"",
+		"[Int",
+		"[Int;]",
+		"[Int;-1]",
+		"{String}",
+		"Int?!",
+		"&",
+		"auth Int",
+		"auth()&Int",
+		"auth(S.test.E,)&Int",
+		"auth([Int])&Int",
+		"Capability<Int",
+		"((Int):Bool",
+		"AnyResource{[Int]}",
+		"A.invalid.Foo",
+	} {
+		typeID := typeID
+
+		t.Run(typeID, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseTypeID(typeID)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidTypeID))
+		})
+	}
+}
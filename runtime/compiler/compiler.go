@@ -347,6 +347,11 @@ func (compiler *Compiler) VisitInterfaceDeclaration(_ *ast.InterfaceDeclaration)
 	panic(errors.NewUnreachableError())
 }
 
+func (compiler *Compiler) VisitEntitlementDeclaration(_ *ast.EntitlementDeclaration) ast.Repr {
+	// TODO
+	panic(errors.NewUnreachableError())
+}
+
 func (compiler *Compiler) VisitFieldDeclaration(_ *ast.FieldDeclaration) ast.Repr {
 	// TODO
 	panic(errors.NewUnreachableError())
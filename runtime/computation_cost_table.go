@@ -0,0 +1,175 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComputationKind is a kind of operation of an execution which uses computation
+//
+type ComputationKind string
+
+const (
+	// ComputationKindStatement is the execution of a statement
+	ComputationKindStatement ComputationKind = "statement"
+	// ComputationKindLoopIteration is an iteration of a loop,
+	// or an invocation of a function passed to a container function like `map`
+	ComputationKindLoopIteration ComputationKind = "loopIteration"
+	// ComputationKindFunctionInvocation is the invocation of a function
+	ComputationKindFunctionInvocation ComputationKind = "functionInvocation"
+)
+
+// ComputationKinds are all kinds of operations which use computation.
+// A computation cost table must have a cost for each of them
+//
+var ComputationKinds = []ComputationKind{
+	ComputationKindStatement,
+	ComputationKindLoopIteration,
+	ComputationKindFunctionInvocation,
+}
+
+// ComputationCostTable defines the computation used by the operations of an execution,
+// see Context.ComputationCostTable.
+//
+// The costs are data, so they can be adjusted without changing the runtime,
+// and a table can be loaded from JSON, see ParseComputationCostTable.
+// Each table has a version, so historical executions can be re-executed
+// with the table which was in effect at the time, e.g. the table of an epoch.
+//
+type ComputationCostTable struct {
+	// Version identifies the table
+	Version uint64 `json:"version"`
+	// Operations are the costs of the operations of an execution, by kind
+	Operations map[ComputationKind]uint64 `json:"operations"`
+	// HostFunctions are the costs of host functions, by name.
+	// A cost in the table overrides the function's HostFunction.ComputationCost
+	HostFunctions map[string]uint64 `json:"hostFunctions,omitempty"`
+}
+
+// DefaultComputationCostTableVersion is the version of the default computation cost table
+//
+const DefaultComputationCostTableVersion = 1
+
+// DefaultComputationCostTable returns the computation cost table which is used
+// if no table is provided in the context.
+// Each operation uses a computation of 1
+//
+func DefaultComputationCostTable() *ComputationCostTable {
+	operations := make(map[ComputationKind]uint64, len(ComputationKinds))
+	for _, kind := range ComputationKinds {
+		operations[kind] = 1
+	}
+
+	return &ComputationCostTable{
+		Version:    DefaultComputationCostTableVersion,
+		Operations: operations,
+	}
+}
+
+// ParseComputationCostTable decodes a computation cost table from JSON, e.g.
+//
+//     {
+//       "version": 2,
+//       "operations": {"statement": 1, "loopIteration": 2, "functionInvocation": 5},
+//       "hostFunctions": {"verifySignature": 100}
+//     }
+//
+// The table must have a cost for each kind of operation, see ComputationKinds.
+//
+func ParseComputationCostTable(data []byte) (*ComputationCostTable, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var table ComputationCostTable
+	err := decoder.Decode(&table)
+	if err != nil {
+		return nil, fmt.Errorf("invalid computation cost table: %w", err)
+	}
+
+	err = table.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+// Validate returns an error if the table does not have a cost for each kind of operation,
+// or if it has a cost for an unknown kind of operation
+//
+func (t *ComputationCostTable) Validate() error {
+	for _, kind := range ComputationKinds {
+		if _, ok := t.Operations[kind]; !ok {
+			return fmt.Errorf(
+				"invalid computation cost table version %d: missing cost of %s",
+				t.Version,
+				kind,
+			)
+		}
+	}
+
+	var unknownKinds []string
+	for kind := range t.Operations { //nolint:maprangecheck
+		if !isComputationKind(kind) {
+			unknownKinds = append(unknownKinds, string(kind))
+		}
+	}
+
+	if len(unknownKinds) > 0 {
+		sort.Strings(unknownKinds)
+
+		return fmt.Errorf(
+			"invalid computation cost table version %d: unknown operations %s",
+			t.Version,
+			strings.Join(unknownKinds, ", "),
+		)
+	}
+
+	return nil
+}
+
+func isComputationKind(kind ComputationKind) bool {
+	for _, knownKind := range ComputationKinds {
+		if kind == knownKind {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationCost returns the computation used by an operation of the given kind
+//
+func (t *ComputationCostTable) OperationCost(kind ComputationKind) uint64 {
+	return t.Operations[kind]
+}
+
+// HostFunctionCost returns the computation used by an invocation of the given host function,
+// in addition to the computation used by any function invocation
+//
+func (t *ComputationCostTable) HostFunctionCost(function HostFunction) uint64 {
+	if cost, ok := t.HostFunctions[function.Name]; ok {
+		return cost
+	}
+	return function.ComputationCost
+}
@@ -0,0 +1,218 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestParseComputationCostTable(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		table, err := ParseComputationCostTable([]byte(`
+          {
+            "version": 2,
+            "operations": {"statement": 1, "loopIteration": 2, "functionInvocation": 5},
+            "hostFunctions": {"double": 100}
+          }
+        `))
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&ComputationCostTable{
+				Version: 2,
+				Operations: map[ComputationKind]uint64{
+					ComputationKindStatement:          1,
+					ComputationKindLoopIteration:      2,
+					ComputationKindFunctionInvocation: 5,
+				},
+				HostFunctions: map[string]uint64{
+					"double": 100,
+				},
+			},
+			table,
+		)
+	})
+
+	t.Run("missing operation", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseComputationCostTable([]byte(`
+          {
+            "version": 2,
+            "operations": {"statement": 1, "loopIteration": 2}
+          }
+        `))
+		require.EqualError(t,
+			err,
+			"invalid computation cost table version 2: missing cost of functionInvocation",
+		)
+	})
+
+	t.Run("unknown operations", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseComputationCostTable([]byte(`
+          {
+            "version": 2,
+            "operations": {
+              "statement": 1,
+              "loopIteration": 2,
+              "functionInvocation": 5,
+              "storageWrite": 3,
+              "allocation": 4
+            }
+          }
+        `))
+		require.EqualError(t,
+			err,
+			"invalid computation cost table version 2: unknown operations allocation, storageWrite",
+		)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseComputationCostTable([]byte(`
+          {
+            "version": 2,
+            "operations": {"statement": 1, "loopIteration": 2, "functionInvocation": 5},
+            "epoch": 3
+          }
+        `))
+		require.Error(t, err)
+	})
+}
+
+func TestDefaultComputationCostTable(t *testing.T) {
+
+	t.Parallel()
+
+	table := DefaultComputationCostTable()
+
+	require.NoError(t, table.Validate())
+	assert.Equal(t, uint64(DefaultComputationCostTableVersion), table.Version)
+
+	for _, kind := range ComputationKinds {
+		assert.Equal(t, uint64(1), table.OperationCost(kind))
+	}
+
+	assert.Equal(t,
+		uint64(7),
+		table.HostFunctionCost(newTestDoubleHostFunction(7)),
+	)
+}
+
+func TestRuntimeComputationCostTable(t *testing.T) {
+
+	t.Parallel()
+
+	const computationLimit = 100
+
+	script := []byte(`
+      pub fun main(): Int {
+          return double(21)
+      }
+    `)
+
+	test := func(table *ComputationCostTable) (cadence.Value, error) {
+
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage:          newTestLedger(nil, nil),
+			computationLimit: computationLimit,
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:            runtimeInterface,
+				Location:             common.ScriptLocation{},
+				HostFunctions:        []HostFunction{newTestDoubleHostFunction(10)},
+				ComputationCostTable: table,
+			},
+		)
+	}
+
+	requireComputationLimitExceeded := func(t *testing.T, err error) {
+		require.Error(t, err)
+
+		var computationLimitErr ComputationLimitExceededError
+		require.ErrorAs(t, err, &computationLimitErr)
+
+		assert.Equal(t,
+			ComputationLimitExceededError{
+				Limit: computationLimit,
+			},
+			computationLimitErr,
+		)
+	}
+
+	t.Run("default", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := test(nil)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(42), value)
+	})
+
+	t.Run("operation cost", func(t *testing.T) {
+
+		t.Parallel()
+
+		table := DefaultComputationCostTable()
+		table.Version = 2
+		table.Operations[ComputationKindStatement] = computationLimit
+
+		_, err := test(table)
+		requireComputationLimitExceeded(t, err)
+	})
+
+	t.Run("host function cost", func(t *testing.T) {
+
+		t.Parallel()
+
+		table := DefaultComputationCostTable()
+		table.Version = 2
+		table.HostFunctions = map[string]uint64{
+			"double": computationLimit,
+		}
+
+		_, err := test(table)
+		requireComputationLimitExceeded(t, err)
+	})
+}
@@ -19,20 +19,92 @@
 package runtime
 
 import (
+	"sort"
+
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
 )
 
 type Context struct {
 	Interface         Interface
 	Location          Location
 	PredeclaredValues []ValueDeclaration
-	codes             map[common.LocationID]string
-	programs          map[common.LocationID]*ast.Program
+	// PredeclaredTypes are types which are declared in addition to the built-in types.
+	// Like predeclared values, they may be restricted to certain locations,
+	// see TypeDeclaration.Available
+	PredeclaredTypes []TypeDeclaration
+	// HostFunctions are functions implemented by the embedder,
+	// which are declared in all programs, see HostFunction
+	HostFunctions []HostFunction
+	// UseVM enables the compilation of scripts to bytecode, which is executed by the VM.
+	// Scripts which cannot be compiled yet are executed by the interpreter
+	UseVM bool
+	// ImportCache is an optional cache for the programs of imported locations,
+	// which may be shared across executions, see ImportCache
+	ImportCache *ImportCache
+	// MemoizationCache is an optional cache for the results of memoized contract functions,
+	// which may be shared across executions, see MemoizationCache
+	MemoizationCache *MemoizationCache
+	// TypeLoader is an optional loader for the types of decoded values,
+	// e.g. an interpreter.CachingTypeLoader, which may be shared across executions,
+	// see interpreter.TypeLoader and NewProgramTypeLoader
+	TypeLoader interpreter.TypeLoader
+	// ExportOptions configures the export of result values, e.g. of scripts
+	ExportOptions ExportOptions
+	// EventSchemaRegistry is an optional registry, which records the event types
+	// of contracts deployed by successful executions, see EventSchemaRegistry
+	EventSchemaRegistry *EventSchemaRegistry
+	// YieldHandler is an optional function which is called at the yield points of the execution,
+	// i.e. at loop back-edges and function invocations, see interpreter.OnYieldFunc.
+	// If it returns an error, the execution is aborted with the error
+	YieldHandler func(point interpreter.YieldPoint) error
+	// ComputationCostTable is an optional table of the computation used by operations,
+	// e.g. the table of the epoch of the executed block, see ComputationCostTable.
+	// If it is nil, the DefaultComputationCostTable is used
+	ComputationCostTable *ComputationCostTable
+	// ExecutionReport is an optional report, in which the operations performed by the execution
+	// are counted by kind, e.g. function invocations and storage reads, see ExecutionReport.
+	// Counting is only enabled if a report is provided
+	ExecutionReport *ExecutionReport
+	// MutationJournal is an optional journal, in which the paths of the stored values
+	// which are read and written by the execution are recorded,
+	// e.g. to detect conflicts between transactions executed in parallel,
+	// see interpreter.MutationJournal
+	MutationJournal *interpreter.MutationJournal
+	// SharedState is an optional, decoded view of the account state, which may be shared
+	// by concurrent read-only executions, e.g. of scripts. If it is provided,
+	// stored values are read from it and cannot be written, see SharedState
+	SharedState *SharedState
+	// OnInternalError is an optional function which is called when the execution fails
+	// due to an internal error or panics, see InternalErrorReport.
+	// It allows node operators to collect crash reports
+	OnInternalError func(report InternalErrorReport)
+	codes           map[common.LocationID]string
+	// codeHashes are the hashes of the codes, which are computed on demand,
+	// e.g. to key the results of memoized functions, see MemoizationCache
+	codeHashes map[common.LocationID][32]byte
+	programs   map[common.LocationID]*ast.Program
+	// importDependencies are the dependencies of the programs which were checked,
+	// or which were loaded from the import cache, if an import cache is used, see ImportCache.
+	// The dependencies of programs provided by the host environment are unknown
+	importDependencies map[common.LocationID]importDependencies
+	// callStack records the call stack of the execution,
+	// if internal errors are reported
+	callStack *interpreter.CallStack
+	// eventTypes are the event types of the contracts deployed by the execution,
+	// which are registered once the execution succeeded
+	eventTypes map[common.TypeID]*cadence.EventType
+	// resultRecorder records the result of the execution, if it was requested,
+	// see ExecutionResult
+	resultRecorder *resultRecordingInterface
 }
 
 func (c Context) SetCode(location common.Location, code string) {
-	c.codes[location.ID()] = code
+	locationID := location.ID()
+	c.codes[locationID] = code
+	delete(c.codeHashes, locationID)
 }
 
 func (c Context) SetProgram(location common.Location, program *ast.Program) {
@@ -50,7 +122,58 @@ func (c *Context) InitializeCodesAndPrograms() {
 		c.codes = map[common.LocationID]string{}
 	}
 
+	if c.codeHashes == nil {
+		c.codeHashes = map[common.LocationID][32]byte{}
+	}
+
 	if c.programs == nil {
 		c.programs = map[common.LocationID]*ast.Program{}
 	}
+
+	if c.importDependencies == nil {
+		c.importDependencies = map[common.LocationID]importDependencies{}
+	}
+
+	if c.eventTypes == nil {
+		c.eventTypes = map[common.TypeID]*cadence.EventType{}
+	}
+
+	if c.callStack == nil && c.OnInternalError != nil {
+		c.callStack = &interpreter.CallStack{}
+	}
+}
+
+// computationCostTable returns the computation cost table of the context,
+// or the default table, if the context has none
+//
+func (c Context) computationCostTable() *ComputationCostTable {
+	if c.ComputationCostTable != nil {
+		return c.ComputationCostTable
+	}
+	return DefaultComputationCostTable()
+}
+
+func (c Context) recordEventTypes(eventTypes []*cadence.EventType) {
+	for _, eventType := range eventTypes {
+		c.eventTypes[common.TypeID(eventType.ID())] = eventType
+	}
+}
+
+// registerEventTypes registers the recorded event types
+// in the event schema registry, if any
+//
+func (c Context) registerEventTypes() {
+	if c.EventSchemaRegistry == nil || len(c.eventTypes) == 0 {
+		return
+	}
+
+	typeIDs := make([]string, 0, len(c.eventTypes))
+	for typeID := range c.eventTypes { //nolint:maprangecheck
+		typeIDs = append(typeIDs, string(typeID))
+	}
+	sort.Strings(typeIDs)
+
+	for _, typeID := range typeIDs {
+		c.EventSchemaRegistry.Register(c.eventTypes[common.TypeID(typeID)])
+	}
 }
@@ -0,0 +1,236 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// FieldAPI describes a field of a composite or interface type
+//
+type FieldAPI struct {
+	Identifier   string
+	Access       ast.Access
+	VariableKind ast.VariableKind
+	Type         cadence.Type
+}
+
+// FunctionAPI describes a function of a composite or interface type
+//
+type FunctionAPI struct {
+	Identifier string
+	Access     ast.Access
+	Type       cadence.FunctionType
+}
+
+// TypeAPI describes the API of a composite or interface type,
+// i.e. its externally accessible fields, functions, and nested types.
+//
+// Members with private or contract access are not part of the API,
+// as they are not accessible outside of the type.
+//
+type TypeAPI struct {
+	Type        cadence.Type
+	Fields      []FieldAPI
+	Functions   []FunctionAPI
+	Events      []cadence.Type
+	NestedTypes []*TypeAPI
+}
+
+// ExportContractAPI returns the description of the API
+// of the given contract or contract interface type.
+//
+// The description is based on exported types, see ExportType,
+// and can be serialized to JSON.
+//
+func ExportContractAPI(contractType sema.CompositeKindedType) (*TypeAPI, error) {
+	if contractType.GetCompositeKind() != common.CompositeKindContract {
+		return nil, fmt.Errorf(
+			"cannot export API of %s `%s`: not a contract",
+			contractType.GetCompositeKind().Name(),
+			contractType.QualifiedString(),
+		)
+	}
+
+	return exportTypeAPI(contractType, map[sema.TypeID]cadence.Type{}), nil
+}
+
+func exportTypeAPI(t sema.CompositeKindedType, results map[sema.TypeID]cadence.Type) *TypeAPI {
+	var members *sema.StringMemberOrderedMap
+	var nestedTypes *sema.StringTypeOrderedMap
+
+	switch t := t.(type) {
+	case *sema.CompositeType:
+		members = t.Members
+		nestedTypes = t.GetNestedTypes()
+	case *sema.InterfaceType:
+		members = t.Members
+		nestedTypes = t.GetNestedTypes()
+	default:
+		panic(fmt.Errorf("cannot export API of type %s", t))
+	}
+
+	api := &TypeAPI{
+		Type: ExportType(t, results),
+	}
+
+	members.Foreach(func(_ string, member *sema.Member) {
+		if member.Predeclared || !isAPIAccess(member.Access) {
+			return
+		}
+
+		switch member.DeclarationKind {
+		case common.DeclarationKindField:
+			api.Fields = append(
+				api.Fields,
+				FieldAPI{
+					Identifier:   member.Identifier.Identifier,
+					Access:       member.Access,
+					VariableKind: member.VariableKind,
+					Type:         ExportType(member.TypeAnnotation.Type, results),
+				},
+			)
+
+		case common.DeclarationKindFunction:
+			api.Functions = append(
+				api.Functions,
+				FunctionAPI{
+					Identifier: member.Identifier.Identifier,
+					Access:     member.Access,
+					Type:       ExportType(member.TypeAnnotation.Type, results).(cadence.FunctionType),
+				},
+			)
+		}
+	})
+
+	if nestedTypes != nil {
+		nestedTypes.Foreach(func(_ string, nestedType sema.Type) {
+			compositeKindedType, ok := nestedType.(sema.CompositeKindedType)
+			if !ok {
+				return
+			}
+
+			if compositeKindedType.GetCompositeKind() == common.CompositeKindEvent {
+				api.Events = append(api.Events, ExportType(nestedType, results))
+				return
+			}
+
+			api.NestedTypes = append(api.NestedTypes, exportTypeAPI(compositeKindedType, results))
+		})
+	}
+
+	return api
+}
+
+func isAPIAccess(access ast.Access) bool {
+	switch access {
+	case ast.AccessPrivate, ast.AccessContract:
+		return false
+	}
+	return true
+}
+
+// JSON
+
+type jsonFieldAPI struct {
+	Identifier   string          `json:"identifier"`
+	Access       string          `json:"access"`
+	VariableKind string          `json:"variableKind"`
+	Type         json.RawMessage `json:"type"`
+}
+
+type jsonFunctionAPI struct {
+	Identifier string          `json:"identifier"`
+	Access     string          `json:"access"`
+	Type       json.RawMessage `json:"type"`
+}
+
+type jsonTypeAPI struct {
+	Type        json.RawMessage   `json:"type"`
+	Fields      []jsonFieldAPI    `json:"fields"`
+	Functions   []jsonFunctionAPI `json:"functions"`
+	Events      []json.RawMessage `json:"events"`
+	NestedTypes []*TypeAPI        `json:"nestedTypes"`
+}
+
+// MarshalJSON encodes the API description as JSON.
+// Types are encoded in the JSON-Cadence format of static types.
+//
+func (api *TypeAPI) MarshalJSON() ([]byte, error) {
+	ty, err := jsoncdc.EncodeType(api.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	result := jsonTypeAPI{
+		Type:        ty,
+		Fields:      make([]jsonFieldAPI, len(api.Fields)),
+		Functions:   make([]jsonFunctionAPI, len(api.Functions)),
+		Events:      make([]json.RawMessage, len(api.Events)),
+		NestedTypes: api.NestedTypes,
+	}
+
+	if result.NestedTypes == nil {
+		result.NestedTypes = []*TypeAPI{}
+	}
+
+	for i, field := range api.Fields {
+		fieldType, err := jsoncdc.EncodeType(field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Fields[i] = jsonFieldAPI{
+			Identifier:   field.Identifier,
+			Access:       field.Access.Keyword(),
+			VariableKind: field.VariableKind.Keyword(),
+			Type:         fieldType,
+		}
+	}
+
+	for i, function := range api.Functions {
+		functionType, err := jsoncdc.EncodeType(function.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Functions[i] = jsonFunctionAPI{
+			Identifier: function.Identifier,
+			Access:     function.Access.Keyword(),
+			Type:       functionType,
+		}
+	}
+
+	for i, event := range api.Events {
+		eventType, err := jsoncdc.EncodeType(event)
+		if err != nil {
+			return nil, err
+		}
+		result.Events[i] = eventType
+	}
+
+	return json.Marshal(result)
+}
@@ -0,0 +1,204 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func TestExportContractAPI(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      pub contract C {
+
+          pub event Deposited(amount: UFix64)
+
+          pub var total: UFix64
+          pub(set) var name: String
+          access(account) let owner: Address
+          access(contract) var secret: Int
+          priv let hidden: Int
+
+          pub resource Vault {
+              pub var balance: UFix64
+
+              init() {
+                  self.balance = 0.0
+              }
+
+              pub fun deposit(amount: UFix64) {
+                  self.balance = self.balance + amount
+              }
+
+              priv fun helper() {}
+          }
+
+          pub resource interface Receiver {
+              pub fun deposit(amount: UFix64)
+          }
+
+          init() {
+              self.total = 0.0
+              self.name = ""
+              self.owner = 0x1
+              self.secret = 0
+              self.hidden = 0
+          }
+
+          pub fun createVault(): @Vault {
+              return <-create Vault()
+          }
+
+          access(account) fun mint(amount: UFix64) {}
+
+          access(contract) fun internal() {}
+      }
+    `)
+	require.NoError(t, err)
+
+	contractType := checker.Elaboration.CompositeDeclarationTypes[checker.Program.CompositeDeclarations()[0]]
+
+	api, err := ExportContractAPI(contractType)
+	require.NoError(t, err)
+
+	assert.Equal(t, "S.test.C", string(api.Type.ID()))
+
+	assert.Equal(t,
+		[]FieldAPI{
+			{
+				Identifier:   "total",
+				Access:       ast.AccessPublic,
+				VariableKind: ast.VariableKindVariable,
+				Type:         cadence.UFix64Type{},
+			},
+			{
+				Identifier:   "name",
+				Access:       ast.AccessPublicSettable,
+				VariableKind: ast.VariableKindVariable,
+				Type:         cadence.StringType{},
+			},
+			{
+				Identifier:   "owner",
+				Access:       ast.AccessAccount,
+				VariableKind: ast.VariableKindConstant,
+				Type:         cadence.AddressType{},
+			},
+		},
+		api.Fields,
+	)
+
+	require.Len(t, api.Functions, 2)
+
+	assert.Equal(t, "createVault", api.Functions[0].Identifier)
+	assert.Equal(t, ast.AccessPublic, api.Functions[0].Access)
+	assert.Equal(t, "S.test.C.Vault", string(api.Functions[0].Type.ReturnType.ID()))
+
+	assert.Equal(t, "mint", api.Functions[1].Identifier)
+	assert.Equal(t, ast.AccessAccount, api.Functions[1].Access)
+	require.Len(t, api.Functions[1].Type.Parameters, 1)
+	assert.Equal(t, "amount", api.Functions[1].Type.Parameters[0].Identifier)
+
+	require.Len(t, api.Events, 1)
+	assert.Equal(t, "S.test.C.Deposited", string(api.Events[0].ID()))
+
+	require.Len(t, api.NestedTypes, 2)
+
+	// nested interfaces are declared before nested composites
+
+	receiverAPI := api.NestedTypes[0]
+	assert.Equal(t, "S.test.C.Receiver", string(receiverAPI.Type.ID()))
+	require.Len(t, receiverAPI.Functions, 1)
+
+	vaultAPI := api.NestedTypes[1]
+	assert.Equal(t, "S.test.C.Vault", string(vaultAPI.Type.ID()))
+	require.Len(t, vaultAPI.Fields, 1)
+	assert.Equal(t, "balance", vaultAPI.Fields[0].Identifier)
+	require.Len(t, vaultAPI.Functions, 1)
+	assert.Equal(t, "deposit", vaultAPI.Functions[0].Identifier)
+
+	// the API can be serialized
+
+	encoded, err := json.Marshal(api)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(encoded, &decoded)
+	require.NoError(t, err)
+
+	fields := decoded["fields"].([]interface{})
+	require.Len(t, fields, 3)
+	assert.Equal(t,
+		map[string]interface{}{
+			"identifier":   "name",
+			"access":       "pub(set)",
+			"variableKind": "var",
+			"type": map[string]interface{}{
+				"kind": "String",
+			},
+		},
+		fields[1],
+	)
+
+	assert.Len(t, decoded["nestedTypes"], 2)
+}
+
+func TestExportContractAPIContractInterface(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      pub contract interface CI {
+          pub fun foo(): Int
+      }
+    `)
+	require.NoError(t, err)
+
+	interfaceType := checker.Elaboration.InterfaceDeclarationTypes[checker.Program.InterfaceDeclarations()[0]]
+
+	api, err := ExportContractAPI(interfaceType)
+	require.NoError(t, err)
+
+	require.Len(t, api.Functions, 1)
+	assert.Equal(t, "foo", api.Functions[0].Identifier)
+}
+
+func TestExportContractAPINonContract(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      pub struct S {}
+    `)
+	require.NoError(t, err)
+
+	compositeType := checker.Elaboration.CompositeDeclarationTypes[checker.Program.CompositeDeclarations()[0]]
+
+	_, err = ExportContractAPI(compositeType)
+	require.Error(t, err)
+}
@@ -0,0 +1,271 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// ContractDeployment is a deployment of a contract or contract interface to an account,
+// see Runtime.SimulateContractDeployment
+//
+type ContractDeployment struct {
+	Address common.Address
+	// Name is the name of the deployed contract or contract interface,
+	// which must match the name of the declaration in the code
+	Name string
+	Code []byte
+	// Arguments are the encoded arguments for the contract initializer
+	Arguments [][]byte
+}
+
+// ContractDeploymentSimulation is the result of simulating a contract deployment,
+// see Runtime.SimulateContractDeployment
+//
+type ContractDeploymentSimulation struct {
+	// Types are the composite and interface types declared by the code,
+	// including the contract or contract interface itself, sorted by their type ID
+	Types []cadence.Type
+	// Events are the events emitted by the contract initializer
+	Events []cadence.Event
+}
+
+func (r *interpreterRuntime) SimulateContractDeployment(
+	deployment ContractDeployment,
+	context Context,
+) (
+	ContractDeploymentSimulation,
+	error,
+) {
+	location := common.AddressLocation{
+		Address: deployment.Address,
+		Name:    deployment.Name,
+	}
+
+	context = context.WithLocation(location)
+	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
+
+	context.SetCode(location, string(deployment.Code))
+
+	// Effects are discarded, like for estimations
+
+	simulationInterface := newEstimationInterface(context.Interface)
+	context.Interface = simulationInterface
+
+	var simulation ContractDeploymentSimulation
+
+	var existingCode []byte
+	var err error
+	wrapPanic(func() {
+		existingCode, err = context.Interface.GetAccountContractCode(deployment.Address, deployment.Name)
+	})
+	if err != nil {
+		return simulation, newError(err, context)
+	}
+
+	if len(existingCode) > 0 {
+		err = fmt.Errorf(
+			"cannot overwrite existing contract with name %q in account %s",
+			deployment.Name,
+			deployment.Address.ShortHexWithPrefix(),
+		)
+		return simulation, newError(err, context)
+	}
+
+	storage := r.newStorage(context)
+
+	var interpreterOptions []interpreter.Option
+	var checkerOptions []sema.Option
+
+	functions := r.standardLibraryFunctions(
+		context,
+		storage,
+		interpreterOptions,
+		checkerOptions,
+	)
+	values := stdlib.BuiltinValues()
+
+	// NOTE: *DO NOT* store the program, as the deployment is not committed
+
+	const storeProgram = false
+
+	program, err := r.parseAndCheckProgram(
+		deployment.Code,
+		context,
+		functions,
+		values,
+		checkerOptions,
+		storeProgram,
+		importResolutionResults{},
+	)
+	if err != nil {
+		return simulation, newError(err, context)
+	}
+
+	contractType, err := deployedContractType(program, deployment.Name)
+	if err != nil {
+		return simulation, newError(err, context)
+	}
+
+	simulation.Types = declaredTypes(program, location)
+
+	// Contract interfaces are not instantiated
+
+	if contractType == nil {
+		return simulation, nil
+	}
+
+	argumentTypes := make([]sema.Type, len(contractType.ConstructorParameters))
+	for i, parameter := range contractType.ConstructorParameters {
+		argumentTypes[i] = parameter.TypeAnnotation.Type
+	}
+
+	// Validate the arguments against the parameters of the initializer,
+	// and instantiate the contract.
+	//
+	// Like for the arguments passed to `AuthAccount.contracts.add`,
+	// the arguments are imported by an interpreter separate from the one of the contract,
+	// which also recovers failures of the initializer
+
+	_, _, err = r.interpret(
+		nil,
+		context,
+		storage,
+		functions,
+		values,
+		interpreterOptions,
+		checkerOptions,
+		func(inter *interpreter.Interpreter) (value interpreter.Value, err error) {
+
+			// Recover internal panics and return them as an error.
+			// For example, the argument validation might attempt to
+			// load contract code for non-existing types
+
+			defer inter.RecoverErrors(func(internalErr error) {
+				err = internalErr
+			})
+
+			arguments, err := validateArgumentParams(
+				inter,
+				context.Interface,
+				deployment.Arguments,
+				contractType.ConstructorParameters,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = r.instantiateContract(
+				program,
+				context,
+				deployment.Address,
+				contractType,
+				arguments,
+				argumentTypes,
+				storage,
+				functions,
+				values,
+				interpreterOptions,
+				checkerOptions,
+			)
+			return nil, err
+		},
+	)
+	if err != nil {
+		return simulation, newError(err, context)
+	}
+
+	simulation.Events = simulationInterface.events
+
+	return simulation, nil
+}
+
+// deployedContractType returns the type of the contract declared by the given program,
+// or nil if the program declares a contract interface.
+//
+// The program must declare exactly one contract or contract interface with the given name.
+//
+func deployedContractType(program *interpreter.Program, name string) (*sema.CompositeType, error) {
+
+	var contractType *sema.CompositeType
+	var declaredNames []string
+
+	program.Elaboration.GlobalTypes.Foreach(func(_ string, variable *sema.Variable) {
+		switch ty := variable.Type.(type) {
+		case *sema.CompositeType:
+			if ty.Kind == common.CompositeKindContract {
+				contractType = ty
+				declaredNames = append(declaredNames, ty.Identifier)
+			}
+
+		case *sema.InterfaceType:
+			if ty.CompositeKind == common.CompositeKindContract {
+				declaredNames = append(declaredNames, ty.Identifier)
+			}
+		}
+	})
+
+	if len(declaredNames) != 1 || declaredNames[0] != name {
+		return nil, &ContractDeclarationMismatchError{
+			Name:          name,
+			DeclaredNames: declaredNames,
+		}
+	}
+
+	return contractType, nil
+}
+
+// declaredTypes returns the exported composite and interface types
+// declared by the given program, sorted by their type ID
+//
+func declaredTypes(program *interpreter.Program, location common.Location) []cadence.Type {
+	var types []cadence.Type
+
+	results := map[sema.TypeID]cadence.Type{}
+
+	for _, compositeType := range program.Elaboration.CompositeTypes { //nolint:maprangecheck
+		if !common.LocationsMatch(compositeType.Location, location) {
+			continue
+		}
+
+		types = append(types, ExportType(compositeType, results))
+	}
+
+	for _, interfaceType := range program.Elaboration.InterfaceTypes { //nolint:maprangecheck
+		if !common.LocationsMatch(interfaceType.Location, location) {
+			continue
+		}
+
+		types = append(types, ExportType(interfaceType, results))
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].ID() < types[j].ID()
+	})
+
+	return types
+}
@@ -0,0 +1,222 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func TestRuntimeSimulateContractDeployment(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      pub contract Test {
+
+          pub event Initialized(value: Int)
+
+          pub struct S {}
+
+          pub resource interface RI {}
+
+          pub let value: Int
+
+          init(value: Int) {
+              self.value = value
+              self.account.save(value, to: /storage/value)
+              emit Initialized(value: value)
+          }
+      }
+    `)
+
+	newRuntimeInterface := func(writes *int, events *[]cadence.Event) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: newTestLedger(nil, func(_, _, _ []byte) {
+				*writes++
+			}),
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return nil, nil
+			},
+			updateAccountContractCode: func(_ Address, _ string, _ []byte) error {
+				require.FailNow(t, "unexpected contract update")
+				return nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				*events = append(*events, event)
+				return nil
+			},
+			decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+				return jsoncdc.Decode(b)
+			},
+		}
+	}
+
+	simulate := func(name string, code []byte, arguments ...cadence.Value) (ContractDeploymentSimulation, error) {
+		var writes int
+		var events []cadence.Event
+
+		encodedArguments := make([][]byte, len(arguments))
+		for i, argument := range arguments {
+			encodedArguments[i] = jsoncdc.MustEncode(argument)
+		}
+
+		simulation, err := newTestInterpreterRuntime().SimulateContractDeployment(
+			ContractDeployment{
+				Address:   address,
+				Name:      name,
+				Code:      code,
+				Arguments: encodedArguments,
+			},
+			Context{
+				Interface: newRuntimeInterface(&writes, &events),
+			},
+		)
+
+		// The effects of the simulated deployment are not committed
+
+		assert.Equal(t, 0, writes)
+		assert.Empty(t, events)
+
+		return simulation, err
+	}
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		simulation, err := simulate("Test", contract, cadence.NewInt(42))
+		require.NoError(t, err)
+
+		typeIDs := make([]string, len(simulation.Types))
+		for i, ty := range simulation.Types {
+			typeIDs[i] = ty.ID()
+		}
+
+		assert.Equal(t,
+			[]string{
+				"A.0000000000000001.Test",
+				"A.0000000000000001.Test.Initialized",
+				"A.0000000000000001.Test.RI",
+				"A.0000000000000001.Test.S",
+			},
+			typeIDs,
+		)
+
+		require.Len(t, simulation.Events, 1)
+		assert.Equal(t,
+			"A.0000000000000001.Test.Initialized",
+			simulation.Events[0].EventType.ID(),
+		)
+		assert.Equal(t,
+			[]cadence.Value{cadence.NewInt(42)},
+			simulation.Events[0].Fields,
+		)
+	})
+
+	t.Run("contract interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		simulation, err := simulate("TestInterface", []byte(`
+          pub contract interface TestInterface {}
+        `))
+		require.NoError(t, err)
+
+		require.Len(t, simulation.Types, 1)
+		assert.Equal(t, "A.0000000000000001.TestInterface", simulation.Types[0].ID())
+		assert.Empty(t, simulation.Events)
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := simulate("Test", contract)
+		require.Error(t, err)
+
+		assert.ErrorAs(t, err, &InvalidEntryPointParameterCountError{})
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := simulate("Test", contract, cadence.String("42"))
+		require.Error(t, err)
+
+		var argumentErr *InvalidEntryPointArgumentError
+		require.ErrorAs(t, err, &argumentErr)
+		assert.Equal(t, 0, argumentErr.Index)
+	})
+
+	t.Run("name mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := simulate("Other", contract, cadence.NewInt(42))
+		require.Error(t, err)
+
+		var mismatchErr *ContractDeclarationMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		assert.Equal(t, []string{"Test"}, mismatchErr.DeclaredNames)
+	})
+
+	t.Run("check error", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := simulate("Test", []byte(`
+          pub contract Test {
+              init() {
+                  let x: Int = "1"
+              }
+          }
+        `))
+		require.Error(t, err)
+
+		var checkingErr *ParsingCheckingError
+		assert.ErrorAs(t, err, &checkingErr)
+	})
+
+	t.Run("initializer failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := simulate("Test", []byte(`
+          pub contract Test {
+              init() {
+                  panic("failed")
+              }
+          }
+        `))
+		require.Error(t, err)
+
+		assert.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
 )
 
 func TestRuntimeContract(t *testing.T) {
@@ -219,7 +220,7 @@ func TestRuntimeContract(t *testing.T) {
 
 		getContractValueExists := func() bool {
 			return NewStorage(storage).
-				GetStorageMap(signerAddress, StorageDomainContract).
+				GetStorageMap(signerAddress, StorageDomainContract, true).
 				ValueExists("Test")
 		}
 
@@ -803,3 +804,97 @@ func TestRuntimeImportMultipleContracts(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestRuntimeContractConstantFields(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      pub contract C {
+
+          pub let a: Int = 1 + 2
+          pub let s: String = "hello"
+          pub let b: Int
+
+          init() {
+              self.b = self.a * 2
+          }
+      }
+    `)
+
+	var contractCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+			return contractCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			contractCode = code
+			return nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		emitEvent: func(_ cadence.Event) error {
+			return nil
+		},
+	}
+
+	runtime := newTestInterpreterRuntime()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: utils.DeploymentTransaction("C", contract),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	// The constant fields are encoded in the stored contract value
+
+	storage := NewStorage(runtimeInterface.storage)
+	contractValue := storage.
+		GetStorageMap(address, StorageDomainContract, true).
+		ReadValue("C")
+
+	require.IsType(t, &interpreter.CompositeValue{}, contractValue)
+	assert.Equal(t,
+		interpreter.NewIntValueFromInt64(3),
+		contractValue.(*interpreter.CompositeValue).GetField("a"),
+	)
+
+	// The constant fields are available when the contract value is loaded
+
+	value, err := runtime.ExecuteScript(
+		Script{
+			Source: []byte(`
+              import C from 0x1
+
+              pub fun main(): [AnyStruct] {
+                  return [C.a, C.s, C.b]
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(3),
+			cadence.String("hello"),
+			cadence.NewInt(6),
+		}),
+		value,
+	)
+}
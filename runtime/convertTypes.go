@@ -118,6 +118,10 @@ func ExportType(t sema.Type, results map[sema.TypeID]cadence.Type) cadence.Type
 			return cadence.Fix64Type{}
 		case sema.UFix64Type:
 			return cadence.UFix64Type{}
+		case sema.Fix128Type:
+			return cadence.Fix128Type{}
+		case sema.UFix128Type:
+			return cadence.UFix128Type{}
 		case sema.PathType:
 			return cadence.PathType{}
 		case sema.StoragePathType:
@@ -148,6 +152,10 @@ func ExportType(t sema.Type, results map[sema.TypeID]cadence.Type) cadence.Type
 			return cadence.AnyResourceType{}
 		case sema.BlockType:
 			return cadence.BlockType{}
+		case sema.TimestampType:
+			return cadence.TimestampType{}
+		case sema.DurationType:
+			return cadence.DurationType{}
 		case sema.StringType:
 			return cadence.StringType{}
 		case sema.AccountKeyType:
@@ -241,6 +249,7 @@ func exportCompositeType(t *sema.CompositeType, results map[sema.TypeID]cadence.
 			Location:            t.Location,
 			QualifiedIdentifier: t.QualifiedIdentifier(),
 			Fields:              fields,
+			IndexedFields:       exportIndexedEventFields(t),
 		}
 
 	case common.CompositeKindContract:
@@ -278,6 +287,19 @@ func exportCompositeType(t *sema.CompositeType, results map[sema.TypeID]cadence.
 	return
 }
 
+// exportIndexedEventFields returns the identifiers of the indexed fields of the given event type,
+// i.e. the parameters of the event which are marked as indexed.
+//
+func exportIndexedEventFields(t *sema.CompositeType) []string {
+	var indexedFields []string
+	for _, parameter := range t.ConstructorParameters {
+		if parameter.Indexed {
+			indexedFields = append(indexedFields, parameter.Identifier)
+		}
+	}
+	return indexedFields
+}
+
 func exportInterfaceType(t *sema.InterfaceType, results map[sema.TypeID]cadence.Type) (result cadence.InterfaceType) {
 
 	fieldMembers := make([]*sema.Member, 0, len(t.Fields))
@@ -375,9 +397,19 @@ func exportFunctionType(t *sema.FunctionType, results map[sema.TypeID]cadence.Ty
 func exportReferenceType(t *sema.ReferenceType, results map[sema.TypeID]cadence.Type) cadence.ReferenceType {
 	convertedType := ExportType(t.Type, results)
 
+	var entitlements []string
+	if len(t.Entitlements) > 0 {
+		entitlements = make([]string, len(t.Entitlements))
+
+		for i, entitlement := range t.Entitlements {
+			entitlements[i] = string(entitlement.ID())
+		}
+	}
+
 	return cadence.ReferenceType{
-		Authorized: t.Authorized,
-		Type:       convertedType,
+		Authorized:   t.Authorized,
+		Entitlements: entitlements,
+		Type:         convertedType,
 	}
 }
 
@@ -416,13 +448,87 @@ func importInterfaceType(t cadence.InterfaceType) interpreter.InterfaceStaticTyp
 	}
 }
 
+func importEntitlementType(typeID string) interpreter.EntitlementStaticType {
+	location, qualifiedIdentifier, err := common.DecodeTypeID(typeID)
+	if err != nil {
+		panic(fmt.Sprintf("cannot import entitlement type %s: %s", typeID, err))
+	}
+
+	return interpreter.EntitlementStaticType{
+		Location:            location,
+		QualifiedIdentifier: qualifiedIdentifier,
+	}
+}
+
 func importCompositeType(t cadence.CompositeType) interpreter.CompositeStaticType {
-	return interpreter.CompositeStaticType{
-		Location:            t.CompositeTypeLocation(),
-		QualifiedIdentifier: t.CompositeTypeQualifiedIdentifier(),
+	return interpreter.NewCompositeStaticType(
+		t.CompositeTypeLocation(),
+		t.CompositeTypeQualifiedIdentifier(),
+	)
+}
+
+// importFunctionType converts a function type to a static function type.
+//
+// Static function types are backed by a sema function type,
+// so the parameter types and the return type are converted to sema types.
+// This is only possible for types which do not refer to user-defined types,
+// as the sema types of composites, interfaces, and entitlements
+// can only be obtained from the checked program which declares them.
+//
+func importFunctionType(t cadence.FunctionType) interpreter.FunctionStaticType {
+	parameters := make([]*sema.Parameter, len(t.Parameters))
+	for i, parameter := range t.Parameters {
+		parameters[i] = &sema.Parameter{
+			Label:          parameter.Label,
+			Identifier:     parameter.Identifier,
+			TypeAnnotation: sema.NewTypeAnnotation(importFunctionTypeMemberType(t, parameter.Type)),
+		}
+	}
+
+	returnType := sema.Type(sema.VoidType)
+	if t.ReturnType != nil {
+		returnType = importFunctionTypeMemberType(t, t.ReturnType)
+	}
+
+	return interpreter.FunctionStaticType{
+		Type: &sema.FunctionType{
+			Parameters:           parameters,
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(returnType),
+		},
+	}
+}
+
+func importFunctionTypeMemberType(functionType cadence.FunctionType, t cadence.Type) sema.Type {
+	userDefinedTypeError := func() error {
+		return fmt.Errorf(
+			"cannot import function type %s: user-defined type %s",
+			functionType.ID(),
+			t.ID(),
+		)
 	}
+
+	semaType, err := interpreter.ConvertStaticToSemaType(
+		ImportType(t),
+		func(_ common.Location, _ string) (*sema.InterfaceType, error) {
+			return nil, userDefinedTypeError()
+		},
+		func(_ common.Location, _ string, _ common.TypeID) (*sema.CompositeType, error) {
+			return nil, userDefinedTypeError()
+		},
+		func(_ common.Location, _ string) (*sema.EntitlementType, error) {
+			return nil, userDefinedTypeError()
+		},
+	)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return semaType
 }
 
+// ImportType converts a Go representation of a type to its corresponding static type.
+// It is the inverse of ExportType.
+//
 func ImportType(t cadence.Type) interpreter.StaticType {
 	switch t := t.(type) {
 	case cadence.AnyType:
@@ -501,6 +607,10 @@ func ImportType(t cadence.Type) interpreter.StaticType {
 		return interpreter.PrimitiveStaticTypeFix64
 	case cadence.UFix64Type:
 		return interpreter.PrimitiveStaticTypeUFix64
+	case cadence.Fix128Type:
+		return interpreter.PrimitiveStaticTypeFix128
+	case cadence.UFix128Type:
+		return interpreter.PrimitiveStaticTypeUFix128
 	case cadence.VariableSizedArrayType:
 		return interpreter.VariableSizedStaticType{
 			Type: ImportType(t.ElementType),
@@ -526,16 +636,24 @@ func ImportType(t cadence.Type) interpreter.StaticType {
 		*cadence.ContractInterfaceType:
 		return importInterfaceType(t.(cadence.InterfaceType))
 	case cadence.ReferenceType:
-		return interpreter.ReferenceStaticType{
-			Authorized: t.Authorized,
-			Type:       ImportType(t.Type),
+		var entitlements []interpreter.EntitlementStaticType
+		if len(t.Entitlements) > 0 {
+			entitlements = make([]interpreter.EntitlementStaticType, 0, len(t.Entitlements))
+			for _, entitlement := range t.Entitlements {
+				entitlements = append(entitlements, importEntitlementType(entitlement))
+			}
+		}
+		return &interpreter.ReferenceStaticType{
+			Authorized:   t.Authorized,
+			Entitlements: entitlements,
+			Type:         ImportType(t.Type),
 		}
 	case cadence.RestrictedType:
 		restrictions := make([]interpreter.InterfaceStaticType, 0, len(t.Restrictions))
 		for _, restriction := range t.Restrictions {
 			intf, ok := restriction.(cadence.InterfaceType)
 			if !ok {
-				panic(fmt.Sprintf("cannot export type of type %T", t))
+				panic(fmt.Sprintf("cannot import restriction of type %T", restriction))
 			}
 			restrictions = append(restrictions, importInterfaceType(intf))
 		}
@@ -545,6 +663,12 @@ func ImportType(t cadence.Type) interpreter.StaticType {
 		}
 	case cadence.BlockType:
 		return interpreter.PrimitiveStaticTypeBlock
+	case cadence.TimestampType:
+		return interpreter.PrimitiveStaticTypeTimestamp
+	case cadence.DurationType:
+		return interpreter.PrimitiveStaticTypeDuration
+	case cadence.PathType:
+		return interpreter.PrimitiveStaticTypePath
 	case cadence.CapabilityPathType:
 		return interpreter.PrimitiveStaticTypeCapabilityPath
 	case cadence.StoragePathType:
@@ -554,9 +678,15 @@ func ImportType(t cadence.Type) interpreter.StaticType {
 	case cadence.PrivatePathType:
 		return interpreter.PrimitiveStaticTypePrivatePath
 	case cadence.CapabilityType:
+		var borrowType interpreter.StaticType
+		if t.BorrowType != nil {
+			borrowType = ImportType(t.BorrowType)
+		}
 		return interpreter.CapabilityStaticType{
-			BorrowType: ImportType(t.BorrowType),
+			BorrowType: borrowType,
 		}
+	case cadence.FunctionType:
+		return importFunctionType(t)
 	case cadence.AccountKeyType:
 		return interpreter.PrimitiveStaticTypeAccountKey
 	case cadence.AuthAccountContractsType:
@@ -574,6 +704,6 @@ func ImportType(t cadence.Type) interpreter.StaticType {
 	case cadence.DeployedContractType:
 		return interpreter.PrimitiveStaticTypeDeployedContract
 	default:
-		panic(fmt.Sprintf("cannot export type of type %T", t))
+		panic(fmt.Sprintf("cannot import type of type %T", t))
 	}
 }
@@ -19,13 +19,16 @@
 package runtime
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/tests/utils"
 )
@@ -38,7 +41,7 @@ func TestExportRecursiveType(t *testing.T) {
 		Location:   utils.TestLocation,
 		Identifier: "Foo",
 		Kind:       common.CompositeKindResource,
-		Members:    sema.NewStringMemberOrderedMap(),
+		Members:    &sema.StringMemberOrderedMap{},
 		Fields:     []string{"foo"},
 	}
 
@@ -70,3 +73,269 @@ func TestExportRecursiveType(t *testing.T) {
 		ExportType(ty, map[sema.TypeID]cadence.Type{}),
 	)
 }
+
+func TestImportExportedType(t *testing.T) {
+
+	t.Parallel()
+
+	newCompositeType := func(identifier string, kind common.CompositeKind) *sema.CompositeType {
+		ty := &sema.CompositeType{
+			Location:   utils.TestLocation,
+			Identifier: identifier,
+			Kind:       kind,
+			Members:    &sema.StringMemberOrderedMap{},
+		}
+		if kind == common.CompositeKindEnum {
+			ty.EnumRawType = sema.UInt8Type
+		}
+		return ty
+	}
+
+	newInterfaceType := func(identifier string, kind common.CompositeKind) *sema.InterfaceType {
+		return &sema.InterfaceType{
+			Location:      utils.TestLocation,
+			Identifier:    identifier,
+			CompositeKind: kind,
+			Members:       &sema.StringMemberOrderedMap{},
+		}
+	}
+
+	newEntitlementType := func(identifier string) *sema.EntitlementType {
+		return &sema.EntitlementType{
+			Location:   utils.TestLocation,
+			Identifier: identifier,
+		}
+	}
+
+	structType := newCompositeType("S", common.CompositeKindStructure)
+	resourceType := newCompositeType("R", common.CompositeKindResource)
+
+	resourceInterfaceType1 := newInterfaceType("RI1", common.CompositeKindResource)
+	resourceInterfaceType2 := newInterfaceType("RI2", common.CompositeKindResource)
+
+	entitlementType1 := newEntitlementType("E1")
+	entitlementType2 := newEntitlementType("E2")
+
+	type testCase struct {
+		name string
+		ty   sema.Type
+	}
+
+	testCases := []testCase{
+		{
+			name: "optional",
+			ty:   &sema.OptionalType{Type: sema.IntType},
+		},
+		{
+			name: "variable-sized array",
+			ty:   &sema.VariableSizedType{Type: sema.StringType},
+		},
+		{
+			name: "constant-sized array",
+			ty:   &sema.ConstantSizedType{Type: sema.UInt8Type, Size: 3},
+		},
+		{
+			name: "dictionary",
+			ty: &sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: &sema.OptionalType{Type: structType},
+			},
+		},
+		{
+			name: "struct",
+			ty:   structType,
+		},
+		{
+			name: "resource",
+			ty:   resourceType,
+		},
+		{
+			name: "event",
+			ty:   newCompositeType("E", common.CompositeKindEvent),
+		},
+		{
+			name: "contract",
+			ty:   newCompositeType("C", common.CompositeKindContract),
+		},
+		{
+			name: "enum",
+			ty:   newCompositeType("N", common.CompositeKindEnum),
+		},
+		{
+			name: "struct interface",
+			ty:   newInterfaceType("SI", common.CompositeKindStructure),
+		},
+		{
+			name: "resource interface",
+			ty:   resourceInterfaceType1,
+		},
+		{
+			name: "contract interface",
+			ty:   newInterfaceType("CI", common.CompositeKindContract),
+		},
+		{
+			name: "reference",
+			ty: &sema.ReferenceType{
+				Type: structType,
+			},
+		},
+		{
+			name: "authorized reference",
+			ty: &sema.ReferenceType{
+				Authorized: true,
+				Type:       sema.AnyStructType,
+			},
+		},
+		{
+			name: "entitled reference",
+			ty: &sema.ReferenceType{
+				Entitlements: []*sema.EntitlementType{
+					entitlementType1,
+					entitlementType2,
+				},
+				Type: resourceType,
+			},
+		},
+		{
+			name: "restricted, one restriction",
+			ty: &sema.RestrictedType{
+				Type: resourceType,
+				Restrictions: []*sema.InterfaceType{
+					resourceInterfaceType1,
+				},
+			},
+		},
+		{
+			name: "restricted, multiple restrictions",
+			ty: &sema.RestrictedType{
+				Type: sema.AnyResourceType,
+				Restrictions: []*sema.InterfaceType{
+					resourceInterfaceType1,
+					resourceInterfaceType2,
+				},
+			},
+		},
+		{
+			name: "capability, without borrow type",
+			ty:   &sema.CapabilityType{},
+		},
+		{
+			name: "capability, with borrow type",
+			ty: &sema.CapabilityType{
+				BorrowType: &sema.ReferenceType{
+					Type: structType,
+				},
+			},
+		},
+		{
+			name: "function",
+			ty: &sema.FunctionType{
+				Parameters: []*sema.Parameter{
+					{
+						Label:          "from",
+						Identifier:     "a",
+						TypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+					},
+					{
+						Label:          sema.ArgumentLabelNotRequired,
+						Identifier:     "b",
+						TypeAnnotation: sema.NewTypeAnnotation(&sema.OptionalType{Type: sema.StringType}),
+					},
+				},
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.BoolType}),
+			},
+		},
+	}
+
+	for ty := interpreter.PrimitiveStaticTypeUnknown + 1; ty <= interpreter.PrimitiveStaticTypeAccountKey; ty++ {
+		// Skip reserved, undefined primitive static types
+		if strings.HasPrefix(ty.String(), "PrimitiveStaticType(") {
+			continue
+		}
+		testCases = append(testCases, testCase{
+			name: ty.String(),
+			ty:   ty.SemaType(),
+		})
+	}
+
+	test := func(testCase testCase) {
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			exportedType := ExportType(testCase.ty, map[sema.TypeID]cadence.Type{})
+			require.NotNil(t, exportedType)
+
+			assert.Equal(t, string(testCase.ty.ID()), exportedType.ID())
+
+			expectedType := interpreter.ConvertSemaToStaticType(testCase.ty)
+			importedType := ImportType(exportedType)
+
+			assert.True(t,
+				expectedType.Equal(importedType),
+				"expected %s, got %s",
+				expectedType,
+				importedType,
+			)
+		})
+	}
+
+	for _, testCase := range testCases {
+		test(testCase)
+	}
+}
+
+func TestImportFunctionType(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("labels", func(t *testing.T) {
+
+		t.Parallel()
+
+		ty := cadence.FunctionType{
+			Parameters: []cadence.Parameter{
+				{
+					Label:      "to",
+					Identifier: "recipient",
+					Type:       cadence.AddressType{},
+				},
+			},
+			ReturnType: cadence.VoidType{},
+		}
+
+		importedType := ImportType(ty)
+		require.IsType(t, interpreter.FunctionStaticType{}, importedType)
+
+		functionType := importedType.(interpreter.FunctionStaticType).Type
+
+		assert.Equal(t,
+			ty.WithID(string(functionType.ID())),
+			ExportType(functionType, map[sema.TypeID]cadence.Type{}),
+		)
+	})
+
+	t.Run("user-defined type", func(t *testing.T) {
+
+		t.Parallel()
+
+		ty := cadence.FunctionType{
+			Parameters: []cadence.Parameter{
+				{
+					Label:      sema.ArgumentLabelNotRequired,
+					Identifier: "s",
+					Type: &cadence.StructType{
+						Location:            utils.TestLocation,
+						QualifiedIdentifier: "S",
+					},
+				},
+			},
+			ReturnType: cadence.VoidType{},
+		}
+
+		assert.Panics(t, func() {
+			_ = ImportType(ty)
+		})
+	})
+}
@@ -20,6 +20,7 @@ package runtime
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/common"
@@ -28,9 +29,62 @@ import (
 	"github.com/onflow/cadence/runtime/stdlib"
 )
 
+// FunctionExportMode determines how function values are exported,
+// e.g. when they are contained in the result of a script.
+//
+type FunctionExportMode uint8
+
+const (
+	// FunctionExportModeDescriptor exports function values as opaque descriptors,
+	// which only contain the type of the function (see cadence.Function)
+	FunctionExportModeDescriptor FunctionExportMode = iota
+	// FunctionExportModeStrip exports function values as nil,
+	// and reports a StrippedFunctionWarning for each function value
+	FunctionExportModeStrip
+)
+
+// ExportOptions configures the export of values.
+// The zero value is the default configuration.
+//
+type ExportOptions struct {
+	FunctionExportMode FunctionExportMode
+	// ReportWarning, if set, is called for each warning produced by the export
+	ReportWarning func(warning ExportWarning)
+}
+
+func (o ExportOptions) reportWarning(warning ExportWarning) {
+	if o.ReportWarning == nil {
+		return
+	}
+	o.ReportWarning(warning)
+}
+
+// ExportWarning is a warning produced by the export of a value
+//
+type ExportWarning interface {
+	isExportWarning()
+	fmt.Stringer
+}
+
+// StrippedFunctionWarning is reported when a function value was exported as nil,
+// because the function export mode is FunctionExportModeStrip
+//
+type StrippedFunctionWarning struct {
+	FunctionType cadence.FunctionType
+}
+
+func (StrippedFunctionWarning) isExportWarning() {}
+
+func (w StrippedFunctionWarning) String() string {
+	return fmt.Sprintf(
+		"function value of type `%s` was not exported",
+		w.FunctionType.ID(),
+	)
+}
+
 // exportValue converts a runtime value to its native Go representation.
-func exportValue(value exportableValue) (cadence.Value, error) {
-	return exportValueWithInterpreter(value.Value, value.Interpreter(), seenReferences{})
+func exportValue(value exportableValue, options ExportOptions) (cadence.Value, error) {
+	return exportValueWithOptions(value.Value, value.Interpreter(), seenReferences{}, options)
 }
 
 // ExportValue converts a runtime value to its native Go representation.
@@ -38,6 +92,19 @@ func ExportValue(value interpreter.Value, inter *interpreter.Interpreter) (caden
 	return exportValueWithInterpreter(value, inter, seenReferences{})
 }
 
+// ExportValueWithOptions converts a runtime value to its native Go representation,
+// using the given export options.
+func ExportValueWithOptions(
+	value interpreter.Value,
+	inter *interpreter.Interpreter,
+	options ExportOptions,
+) (
+	cadence.Value,
+	error,
+) {
+	return exportValueWithOptions(value, inter, seenReferences{}, options)
+}
+
 // NOTE: Do not generalize to map[interpreter.Value],
 // as not all values are Go hashable, i.e. this might lead to run-time panics
 type seenReferences map[*interpreter.EphemeralReferenceValue]struct{}
@@ -56,6 +123,18 @@ func exportValueWithInterpreter(
 	cadence.Value,
 	error,
 ) {
+	return exportValueWithOptions(value, inter, seenReferences, ExportOptions{})
+}
+
+func exportValueWithOptions(
+	value interpreter.Value,
+	inter *interpreter.Interpreter,
+	seenReferences seenReferences,
+	options ExportOptions,
+) (
+	cadence.Value,
+	error,
+) {
 
 	switch v := value.(type) {
 	case interpreter.VoidValue:
@@ -63,13 +142,13 @@ func exportValueWithInterpreter(
 	case interpreter.NilValue:
 		return cadence.NewOptional(nil), nil
 	case *interpreter.SomeValue:
-		return exportSomeValue(v, inter, seenReferences)
+		return exportSomeValue(v, inter, seenReferences, options)
 	case interpreter.BoolValue:
 		return cadence.NewBool(bool(v)), nil
 	case *interpreter.StringValue:
 		return cadence.NewString(v.Str)
 	case *interpreter.ArrayValue:
-		return exportArrayValue(v, inter, seenReferences)
+		return exportArrayValue(v, inter, seenReferences, options)
 	case interpreter.IntValue:
 		return cadence.NewIntFromBig(v.ToBigInt()), nil
 	case interpreter.Int8Value:
@@ -110,14 +189,22 @@ func exportValueWithInterpreter(
 		return cadence.Fix64(v), nil
 	case interpreter.UFix64Value:
 		return cadence.UFix64(v), nil
+	case interpreter.Fix128Value:
+		return cadence.NewFix128FromBig(new(big.Int).Set(v.BigInt))
+	case interpreter.UFix128Value:
+		return cadence.NewUFix128FromBig(new(big.Int).Set(v.BigInt))
 	case *interpreter.CompositeValue:
-		return exportCompositeValue(v, inter, seenReferences)
+		return exportCompositeValue(v, inter, seenReferences, options)
 	case *interpreter.SimpleCompositeValue:
-		return exportSimpleCompositeValue(v, inter, seenReferences)
+		return exportSimpleCompositeValue(v, inter, seenReferences, options)
 	case *interpreter.DictionaryValue:
-		return exportDictionaryValue(v, inter, seenReferences)
+		return exportDictionaryValue(v, inter, seenReferences, options)
 	case interpreter.AddressValue:
 		return cadence.NewAddress(v), nil
+	case interpreter.TimestampValue:
+		return cadence.Timestamp(v), nil
+	case interpreter.DurationValue:
+		return cadence.Duration(v), nil
 	case interpreter.LinkValue:
 		return exportLinkValue(v, inter), nil
 	case interpreter.PathValue:
@@ -126,6 +213,8 @@ func exportValueWithInterpreter(
 		return exportTypeValue(v, inter), nil
 	case *interpreter.CapabilityValue:
 		return exportCapabilityValue(v, inter), nil
+	case interpreter.FunctionValue:
+		return exportFunctionValue(v, inter, options)
 	case *interpreter.EphemeralReferenceValue:
 		// Break recursion through ephemeral references
 		if _, ok := seenReferences[v]; ok {
@@ -133,13 +222,13 @@ func exportValueWithInterpreter(
 		}
 		defer delete(seenReferences, v)
 		seenReferences[v] = struct{}{}
-		return exportValueWithInterpreter(v.Value, inter, seenReferences)
+		return exportValueWithOptions(v.Value, inter, seenReferences, options)
 	case *interpreter.StorageReferenceValue:
 		referencedValue := v.ReferencedValue(inter)
 		if referencedValue == nil {
 			return nil, nil
 		}
-		return exportValueWithInterpreter(*referencedValue, inter, seenReferences)
+		return exportValueWithOptions(*referencedValue, inter, seenReferences, options)
 	}
 
 	return nil, fmt.Errorf("cannot export value of type %T", value)
@@ -150,6 +239,7 @@ func exportSomeValue(
 	v *interpreter.SomeValue,
 	inter *interpreter.Interpreter,
 	seenReferences seenReferences,
+	options ExportOptions,
 ) (
 	cadence.Optional,
 	error,
@@ -158,7 +248,7 @@ func exportSomeValue(
 		return cadence.NewOptional(nil), nil
 	}
 
-	value, err := exportValueWithInterpreter(v.Value, inter, seenReferences)
+	value, err := exportValueWithOptions(v.Value, inter, seenReferences, options)
 	if err != nil {
 		return cadence.Optional{}, err
 	}
@@ -170,6 +260,7 @@ func exportArrayValue(
 	v *interpreter.ArrayValue,
 	inter *interpreter.Interpreter,
 	seenReferences seenReferences,
+	options ExportOptions,
 ) (
 	cadence.Array,
 	error,
@@ -179,7 +270,7 @@ func exportArrayValue(
 	var err error
 	v.Iterate(func(value interpreter.Value) (resume bool) {
 		var exportedValue cadence.Value
-		exportedValue, err = exportValueWithInterpreter(value, inter, seenReferences)
+		exportedValue, err = exportValueWithOptions(value, inter, seenReferences, options)
 		if err != nil {
 			return false
 		}
@@ -200,6 +291,7 @@ func exportCompositeValue(
 	v *interpreter.CompositeValue,
 	inter *interpreter.Interpreter,
 	seenReferences seenReferences,
+	options ExportOptions,
 ) (
 	cadence.Value,
 	error,
@@ -228,7 +320,7 @@ func exportCompositeValue(
 			}
 		}
 
-		exportedFieldValue, err := exportValueWithInterpreter(fieldValue, inter, seenReferences)
+		exportedFieldValue, err := exportValueWithOptions(fieldValue, inter, seenReferences, options)
 		if err != nil {
 			return nil, err
 		}
@@ -271,6 +363,7 @@ func exportSimpleCompositeValue(
 	v *interpreter.SimpleCompositeValue,
 	inter *interpreter.Interpreter,
 	seenReferences seenReferences,
+	options ExportOptions,
 ) (
 	cadence.Value,
 	error,
@@ -302,7 +395,7 @@ func exportSimpleCompositeValue(
 			}
 		}
 
-		exportedFieldValue, err := exportValueWithInterpreter(fieldValue, inter, seenReferences)
+		exportedFieldValue, err := exportValueWithOptions(fieldValue, inter, seenReferences, options)
 		if err != nil {
 			return nil, err
 		}
@@ -345,6 +438,7 @@ func exportDictionaryValue(
 	v *interpreter.DictionaryValue,
 	inter *interpreter.Interpreter,
 	seenReferences seenReferences,
+	options ExportOptions,
 ) (
 	cadence.Dictionary,
 	error,
@@ -355,13 +449,13 @@ func exportDictionaryValue(
 	v.Iterate(func(key, value interpreter.Value) (resume bool) {
 
 		var convertedKey cadence.Value
-		convertedKey, err = exportValueWithInterpreter(key, inter, seenReferences)
+		convertedKey, err = exportValueWithOptions(key, inter, seenReferences, options)
 		if err != nil {
 			return false
 		}
 
 		var convertedValue cadence.Value
-		convertedValue, err = exportValueWithInterpreter(value, inter, seenReferences)
+		convertedValue, err = exportValueWithOptions(value, inter, seenReferences, options)
 		if err != nil {
 			return false
 		}
@@ -420,6 +514,40 @@ func exportCapabilityValue(v *interpreter.CapabilityValue, inter *interpreter.In
 	}
 }
 
+func exportFunctionValue(
+	v interpreter.FunctionValue,
+	inter *interpreter.Interpreter,
+	options ExportOptions,
+) (
+	cadence.Value,
+	error,
+) {
+	// Bound functions without an underlying function have no type
+	if boundFunction, ok := v.(interpreter.BoundFunctionValue); ok && boundFunction.Function == nil {
+		return nil, fmt.Errorf("cannot export function value without type")
+	}
+
+	dynamicType := v.DynamicType(inter, interpreter.SeenReferences{}).(interpreter.FunctionDynamicType)
+	if dynamicType.FuncType == nil {
+		return nil, fmt.Errorf("cannot export function value without type")
+	}
+
+	functionType := exportFunctionType(dynamicType.FuncType, map[sema.TypeID]cadence.Type{}).(cadence.FunctionType)
+
+	switch options.FunctionExportMode {
+	case FunctionExportModeStrip:
+		options.reportWarning(StrippedFunctionWarning{
+			FunctionType: functionType,
+		})
+		return cadence.NewOptional(nil), nil
+
+	case FunctionExportModeDescriptor:
+		return cadence.NewFunction(functionType), nil
+	}
+
+	return nil, fmt.Errorf("invalid function export mode: %d", options.FunctionExportMode)
+}
+
 // exportEvent converts a runtime event to its native Go representation.
 func exportEvent(event exportableEvent, seenReferences seenReferences) (cadence.Event, error) {
 	fields := make([]cadence.Value, len(event.Fields))
@@ -436,6 +564,11 @@ func exportEvent(event exportableEvent, seenReferences seenReferences) (cadence.
 	return cadence.NewEvent(fields).WithType(eventType), nil
 }
 
+// ImportValue converts a Cadence value to a runtime value.
+func ImportValue(inter *interpreter.Interpreter, value cadence.Value, expectedType sema.Type) (interpreter.Value, error) {
+	return importValue(inter, value, expectedType)
+}
+
 // importValue converts a Cadence value to a runtime value.
 func importValue(inter *interpreter.Interpreter, value cadence.Value, expectedType sema.Type) (interpreter.Value, error) {
 	switch v := value.(type) {
@@ -451,6 +584,10 @@ func importValue(inter *interpreter.Interpreter, value cadence.Value, expectedTy
 		return interpreter.ByteSliceToByteArrayValue(inter, v), nil
 	case cadence.Address:
 		return interpreter.NewAddressValue(common.Address(v)), nil
+	case cadence.Timestamp:
+		return interpreter.NewTimestampValue(int64(v)), nil
+	case cadence.Duration:
+		return interpreter.NewDurationValue(int64(v)), nil
 	case cadence.Int:
 		return interpreter.NewIntValueFromBigInt(v.Value), nil
 	case cadence.Int8:
@@ -491,8 +628,12 @@ func importValue(inter *interpreter.Interpreter, value cadence.Value, expectedTy
 		return interpreter.Fix64Value(v), nil
 	case cadence.UFix64:
 		return interpreter.UFix64Value(v), nil
+	case cadence.Fix128:
+		return interpreter.NewFix128ValueFromBigInt(v.Value), nil
+	case cadence.UFix128:
+		return interpreter.NewUFix128ValueFromBigInt(v.Value), nil
 	case cadence.Path:
-		return importPathValue(v), nil
+		return importPathValue(v)
 	case cadence.Array:
 		return importArrayValue(inter, v, expectedType)
 	case cadence.Dictionary:
@@ -545,16 +686,26 @@ func importValue(inter *interpreter.Interpreter, value cadence.Value, expectedTy
 			v.Address,
 			v.BorrowType,
 		)
+	case cadence.Link:
+		return importLinkValue(inter, v)
 	}
 
 	return nil, fmt.Errorf("cannot import value of type %T", value)
 }
 
-func importPathValue(v cadence.Path) interpreter.PathValue {
+func importPathValue(v cadence.Path) (interpreter.PathValue, error) {
+	domain := common.PathDomainFromIdentifier(v.Domain)
+	if domain == common.PathDomainUnknown {
+		return interpreter.PathValue{}, fmt.Errorf(
+			"cannot import path: invalid domain '%s'",
+			v.Domain,
+		)
+	}
+
 	return interpreter.PathValue{
-		Domain:     common.PathDomainFromIdentifier(v.Domain),
+		Domain:     domain,
 		Identifier: v.Identifier,
-	}
+	}, nil
 }
 
 func importTypeValue(
@@ -580,7 +731,7 @@ func importTypeValue(
 }
 
 func importCapability(
-	_ *interpreter.Interpreter,
+	inter *interpreter.Interpreter,
 	path cadence.Path,
 	address cadence.Address,
 	borrowType cadence.Type,
@@ -589,21 +740,90 @@ func importCapability(
 	error,
 ) {
 
-	_, ok := borrowType.(cadence.ReferenceType)
+	pathValue, err := importPathValue(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Capabilities which were not created with a borrow type,
+	// e.g. `getCapability(/public/foo)`, have no borrow type
+
+	var borrowStaticType interpreter.StaticType
+	if borrowType != nil {
+		borrowStaticType, err = importReferenceType(inter, borrowType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot import capability: %w", err)
+		}
+	}
+
+	return &interpreter.CapabilityValue{
+		Path:       pathValue,
+		Address:    interpreter.NewAddressValueFromBytes(address.Bytes()),
+		BorrowType: borrowStaticType,
+	}, nil
 
+}
+
+func importLinkValue(
+	inter *interpreter.Interpreter,
+	v cadence.Link,
+) (
+	interpreter.LinkValue,
+	error,
+) {
+	targetPath, err := importPathValue(v.TargetPath)
+	if err != nil {
+		return interpreter.LinkValue{}, err
+	}
+
+	// The borrow type of a link is exported as a type ID,
+	// see exportLinkValue
+
+	borrowType, err := cadence.ParseTypeID(v.BorrowType, nil)
+	if err != nil {
+		return interpreter.LinkValue{}, fmt.Errorf("cannot import link: %w", err)
+	}
+
+	staticType, err := importReferenceType(inter, borrowType)
+	if err != nil {
+		return interpreter.LinkValue{}, fmt.Errorf("cannot import link: %w", err)
+	}
+
+	return interpreter.LinkValue{
+		TargetPath: targetPath,
+		Type:       staticType,
+	}, nil
+}
+
+// importReferenceType imports the given borrow type of a capability or link,
+// which must be a valid reference type
+//
+func importReferenceType(
+	inter *interpreter.Interpreter,
+	borrowType cadence.Type,
+) (
+	interpreter.StaticType,
+	error,
+) {
+	_, ok := borrowType.(cadence.ReferenceType)
 	if !ok {
 		return nil, fmt.Errorf(
-			"cannot import capability: expected reference, got '%s'",
+			"expected reference, got '%s'",
 			borrowType.ID(),
 		)
 	}
 
-	return &interpreter.CapabilityValue{
-		Path:       importPathValue(path),
-		Address:    interpreter.NewAddressValueFromBytes(address.Bytes()),
-		BorrowType: ImportType(borrowType),
-	}, nil
+	staticType := ImportType(borrowType)
+
+	// Creating a static type performs no validation,
+	// so ensure the type is valid by converting it to a sema type
+
+	_, err := inter.ConvertStaticToSemaType(staticType)
+	if err != nil {
+		return nil, err
+	}
 
+	return staticType, nil
 }
 
 func importOptionalValue(
@@ -787,6 +1007,16 @@ func importCompositeValue(
 		return nil, typeErr
 	}
 
+	// Type requirements are abstract,
+	// only values of the types implementing them can be imported
+
+	if compositeType.IsTypeRequirement() {
+		return nil, fmt.Errorf(
+			"cannot import value of type requirement %s",
+			qualifiedIdentifier,
+		)
+	}
+
 	for i := 0; i < len(fieldTypes) && i < len(fieldValues); i++ {
 		fieldType := fieldTypes[i]
 		fieldValue := fieldValues[i]
@@ -828,6 +1058,9 @@ func importCompositeValue(
 			// (e.g. it has host functions)
 			return importSignatureAlgorithm(inter, fields)
 
+		case sema.RoundingRuleType:
+			return importRoundingRule(inter, fields)
+
 		default:
 			return nil, fmt.Errorf(
 				"cannot import value of type %s",
@@ -1018,3 +1251,57 @@ func importSignatureAlgorithm(
 
 	return stdlib.NewSignatureAlgorithmCase(inter, uint8(rawValue)), nil
 }
+
+func importRoundingRule(
+	inter *interpreter.Interpreter,
+	fields []interpreter.CompositeField,
+) (
+	*interpreter.CompositeValue,
+	error,
+) {
+
+	var foundRawValue bool
+	var rawValue interpreter.UInt8Value
+
+	ty := sema.RoundingRuleType
+
+	for _, field := range fields {
+		switch field.Name {
+		case sema.EnumRawValueFieldName:
+			rawValue, foundRawValue = field.Value.(interpreter.UInt8Value)
+			if !foundRawValue {
+				return nil, fmt.Errorf(
+					"cannot import value of type '%s'. invalid value for field '%s': %v",
+					ty,
+					field.Name,
+					field.Value,
+				)
+			}
+
+		default:
+			return nil, fmt.Errorf(
+				"cannot import value of type '%s'. invalid field '%s'",
+				ty,
+				field.Name,
+			)
+		}
+	}
+
+	if !foundRawValue {
+		return nil, fmt.Errorf(
+			"cannot import value of type '%s'. missing field '%s'",
+			ty,
+			sema.EnumRawValueFieldName,
+		)
+	}
+
+	if int(rawValue) >= len(sema.RoundingRules) {
+		return nil, fmt.Errorf(
+			"cannot import value of type '%s'. invalid raw value: %d",
+			ty,
+			rawValue,
+		)
+	}
+
+	return stdlib.NewRoundingRuleCase(inter, uint8(rawValue)), nil
+}
@@ -718,6 +718,44 @@ func TestImportValue(t *testing.T) {
 				Identifier: "foo",
 			},
 		},
+		{
+			label: "Path (invalid domain)",
+			value: cadence.Path{
+				Domain:     "foo",
+				Identifier: "bar",
+			},
+			expected: nil,
+		},
+		{
+			label: "Link",
+			value: cadence.Link{
+				TargetPath: cadence.Path{
+					Domain:     "storage",
+					Identifier: "test",
+				},
+				BorrowType: "&Int",
+			},
+			expected: interpreter.LinkValue{
+				TargetPath: interpreter.PathValue{
+					Domain:     common.PathDomainStorage,
+					Identifier: "test",
+				},
+				Type: &interpreter.ReferenceStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+		},
+		{
+			label: "Link (invalid type ID)",
+			value: cadence.Link{
+				TargetPath: cadence.Path{
+					Domain:     "storage",
+					Identifier: "test",
+				},
+				BorrowType: "&[Int",
+			},
+			expected: nil,
+		},
 		{
 			label: "Link (invalid)",
 			value: cadence.Link{
@@ -740,6 +778,44 @@ func TestImportValue(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			label: "Capability",
+			value: cadence.Capability{
+				Path: cadence.Path{
+					Domain:     "public",
+					Identifier: "test",
+				},
+				Address:    cadence.Address{0x1},
+				BorrowType: cadence.ReferenceType{Type: cadence.IntType{}},
+			},
+			expected: &interpreter.CapabilityValue{
+				Path: interpreter.PathValue{
+					Domain:     common.PathDomainPublic,
+					Identifier: "test",
+				},
+				Address: interpreter.AddressValue{0x1},
+				BorrowType: &interpreter.ReferenceStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+		},
+		{
+			label: "Capability (no borrow type)",
+			value: cadence.Capability{
+				Path: cadence.Path{
+					Domain:     "public",
+					Identifier: "test",
+				},
+				Address: cadence.Address{0x1},
+			},
+			expected: &interpreter.CapabilityValue{
+				Path: interpreter.PathValue{
+					Domain:     common.PathDomainPublic,
+					Identifier: "test",
+				},
+				Address: interpreter.AddressValue{0x1},
+			},
+		},
 		{
 			label:    "Type<Int>()",
 			value:    cadence.NewTypeValue(cadence.IntType{}),
@@ -1060,7 +1136,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Authorized: false,
 				Type:       cadence.IntType{},
 			},
-			expected: interpreter.ReferenceStaticType{
+			expected: &interpreter.ReferenceStaticType{
 				Authorized: false,
 				Type:       interpreter.PrimitiveStaticTypeInt,
 			},
@@ -1080,10 +1156,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Location:            TestLocation,
 				QualifiedIdentifier: "S",
 			},
-			expected: interpreter.CompositeStaticType{
-				Location:            TestLocation,
-				QualifiedIdentifier: "S",
-			},
+			expected: interpreter.NewCompositeStaticType(TestLocation, "S"),
 		},
 		{
 			label: "Resource",
@@ -1091,10 +1164,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Location:            TestLocation,
 				QualifiedIdentifier: "S",
 			},
-			expected: interpreter.CompositeStaticType{
-				Location:            TestLocation,
-				QualifiedIdentifier: "S",
-			},
+			expected: interpreter.NewCompositeStaticType(TestLocation, "S"),
 		},
 		{
 			label: "Contract",
@@ -1102,10 +1172,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Location:            TestLocation,
 				QualifiedIdentifier: "S",
 			},
-			expected: interpreter.CompositeStaticType{
-				Location:            TestLocation,
-				QualifiedIdentifier: "S",
-			},
+			expected: interpreter.NewCompositeStaticType(TestLocation, "S"),
 		},
 		{
 			label: "Event",
@@ -1113,10 +1180,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Location:            TestLocation,
 				QualifiedIdentifier: "S",
 			},
-			expected: interpreter.CompositeStaticType{
-				Location:            TestLocation,
-				QualifiedIdentifier: "S",
-			},
+			expected: interpreter.NewCompositeStaticType(TestLocation, "S"),
 		},
 		{
 			label: "Enum",
@@ -1124,10 +1188,7 @@ func TestImportRuntimeType(t *testing.T) {
 				Location:            TestLocation,
 				QualifiedIdentifier: "S",
 			},
-			expected: interpreter.CompositeStaticType{
-				Location:            TestLocation,
-				QualifiedIdentifier: "S",
-			},
+			expected: interpreter.NewCompositeStaticType(TestLocation, "S"),
 		},
 		{
 			label: "StructInterface",
@@ -1176,10 +1237,7 @@ func TestImportRuntimeType(t *testing.T) {
 					}},
 			},
 			expected: &interpreter.RestrictedStaticType{
-				Type: interpreter.CompositeStaticType{
-					Location:            TestLocation,
-					QualifiedIdentifier: "S",
-				},
+				Type: interpreter.NewCompositeStaticType(TestLocation, "S"),
 				Restrictions: []interpreter.InterfaceStaticType{
 					{
 						Location:            TestLocation,
@@ -1545,6 +1603,88 @@ func exportValueFromScript(t *testing.T, script string) cadence.Value {
 	return value
 }
 
+func TestExportFunctionValue(t *testing.T) {
+
+	t.Parallel()
+
+	const script = `
+      pub fun main(): [AnyStruct] {
+          return [1, fun (x: Int): Bool { return x > 0 }]
+      }
+    `
+
+	functionType := cadence.FunctionType{
+		Parameters: []cadence.Parameter{
+			{Identifier: "x", Type: cadence.IntType{}},
+		},
+		ReturnType: cadence.BoolType{},
+	}.WithID("((Int):Bool)")
+
+	executeScript := func(t *testing.T, options ExportOptions) cadence.Value {
+		rt := newTestInterpreterRuntime()
+
+		value, err := rt.ExecuteScript(
+			Script{
+				Source: []byte(script),
+			},
+			Context{
+				Interface:     &testRuntimeInterface{},
+				Location:      TestLocation,
+				ExportOptions: options,
+			},
+		)
+		require.NoError(t, err)
+
+		return value
+	}
+
+	t.Run("descriptor", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual := executeScript(t, ExportOptions{})
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewFunction(functionType),
+			}),
+			actual,
+		)
+	})
+
+	t.Run("strip", func(t *testing.T) {
+
+		t.Parallel()
+
+		var warnings []ExportWarning
+
+		actual := executeScript(t, ExportOptions{
+			FunctionExportMode: FunctionExportModeStrip,
+			ReportWarning: func(warning ExportWarning) {
+				warnings = append(warnings, warning)
+			},
+		})
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewOptional(nil),
+			}),
+			actual,
+		)
+
+		assert.Equal(t,
+			[]ExportWarning{
+				StrippedFunctionWarning{
+					FunctionType: functionType,
+				},
+			},
+			warnings,
+		)
+	})
+}
+
 func TestExportReferenceValue(t *testing.T) {
 
 	t.Parallel()
@@ -1875,6 +2015,76 @@ func TestExportCapabilityValue(t *testing.T) {
 	})
 }
 
+func TestExportCapabilityValueFromScript(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("no borrow type", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): Capability {
+                return getAccount(0x1).getCapability(/public/test)
+            }
+        `
+
+		actual := exportValueFromScript(t, script)
+		expected := cadence.Capability{
+			Path: cadence.Path{
+				Domain:     "public",
+				Identifier: "test",
+			},
+			Address: cadence.Address{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+		}
+
+		assert.Equal(t, expected, actual)
+		assert.Equal(t,
+			"Capability(address: 0x0000000000000001, path: /public/test)",
+			actual.String(),
+		)
+
+		encoded, err := json.Encode(actual)
+		require.NoError(t, err)
+
+		decoded, err := json.Decode(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, decoded)
+	})
+
+	t.Run("borrow type", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): Capability<&Int> {
+                return getAccount(0x1).getCapability<&Int>(/public/test)
+            }
+        `
+
+		actual := exportValueFromScript(t, script)
+		expected := cadence.Capability{
+			Path: cadence.Path{
+				Domain:     "public",
+				Identifier: "test",
+			},
+			Address:    cadence.Address{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+			BorrowType: cadence.ReferenceType{Type: cadence.IntType{}},
+		}
+
+		assert.Equal(t, expected, actual)
+
+		encoded, err := json.Encode(actual)
+		require.NoError(t, err)
+
+		decoded, err := json.Decode(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, decoded)
+	})
+}
+
 func TestExportLinkValue(t *testing.T) {
 
 	t.Parallel()
@@ -3553,6 +3763,56 @@ func TestCapabilityValueImport(t *testing.T) {
 		require.True(t, ok)
 	})
 
+	t.Run("Capability without borrow type", func(t *testing.T) {
+
+		t.Parallel()
+
+		capabilityValue := cadence.Capability{
+			Address: cadence.Address{0x1},
+			Path: cadence.Path{
+				Domain:     common.PathDomainPublic.Identifier(),
+				Identifier: "foo",
+			},
+		}
+
+		script := `
+            pub fun main(s: Capability) {
+                log(s)
+            }
+        `
+
+		encodedArg, err := json.Encode(capabilityValue)
+		require.NoError(t, err)
+
+		rt := NewInterpreterRuntime()
+
+		var ok bool
+
+		runtimeInterface := &testRuntimeInterface{
+			decodeArgument: func(b []byte, t cadence.Type) (value cadence.Value, err error) {
+				return json.Decode(b)
+			},
+			log: func(s string) {
+				assert.Equal(t, s, "Capability(address: 0x0100000000000000, path: /public/foo)")
+				ok = true
+			},
+		}
+
+		_, err = rt.ExecuteScript(
+			Script{
+				Source:    []byte(script),
+				Arguments: [][]byte{encodedArg},
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  TestLocation,
+			},
+		)
+
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
 	t.Run("Capability<Int>", func(t *testing.T) {
 
 		t.Parallel()
@@ -4370,7 +4630,7 @@ func TestRuntimeImportExportComplex(t *testing.T) {
 		Location:   TestLocation,
 		Identifier: "Foo",
 		Kind:       common.CompositeKindStructure,
-		Members:    sema.NewStringMemberOrderedMap(),
+		Members:    &sema.StringMemberOrderedMap{},
 		Fields:     []string{"dictionary"},
 	}
 
@@ -27,6 +27,8 @@ import (
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -387,3 +389,90 @@ func TestRuntimeError(t *testing.T) {
 
 	})
 }
+
+func TestRuntimeErrorClassification(t *testing.T) {
+
+	t.Parallel()
+
+	execute := func(script string, hostFunctions ...HostFunction) error {
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{}
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(script),
+			},
+			Context{
+				Interface:     runtimeInterface,
+				Location:      common.ScriptLocation{0x1},
+				HostFunctions: hostFunctions,
+			},
+		)
+		return err
+	}
+
+	t.Run("parse error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := execute(`X`)
+		require.Error(t, err)
+
+		require.True(t, errors.IsUserError(err))
+		require.False(t, errors.IsInternalError(err))
+	})
+
+	t.Run("checking error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := execute(`pub fun main() { X }`)
+		require.Error(t, err)
+
+		require.True(t, errors.IsUserError(err))
+		require.False(t, errors.IsInternalError(err))
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := execute(`
+          pub fun main() {
+              let x: UInt8 = 255
+              let y = x + 1
+          }
+        `)
+		require.Error(t, err)
+
+		require.True(t, errors.IsUserError(err))
+		require.False(t, errors.IsInternalError(err))
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := execute(
+			`
+              pub fun main() {
+                  fail()
+              }
+            `,
+			HostFunction{
+				Name: "fail",
+				Type: &sema.FunctionType{
+					ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.VoidType),
+				},
+				Function: func(_ interpreter.Invocation) interpreter.Value {
+					panic("unexpected")
+				},
+			},
+		)
+		require.Error(t, err)
+
+		require.False(t, errors.IsUserError(err))
+		require.True(t, errors.IsInternalError(err))
+	})
+}
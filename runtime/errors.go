@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
@@ -38,6 +39,11 @@ type Error struct {
 }
 
 func newError(err error, context Context) Error {
+	// Errors which are already runtime errors were already reported
+	if _, ok := err.(Error); !ok {
+		context.reportInternalError(err)
+	}
+
 	return Error{
 		Err:      err,
 		Location: context.Location,
@@ -67,6 +73,8 @@ type ComputationLimitExceededError struct {
 	Limit uint64
 }
 
+func (ComputationLimitExceededError) IsUserError() {}
+
 func (e ComputationLimitExceededError) Error() string {
 	return fmt.Sprintf(
 		"computation limited exceeded: %d",
@@ -80,6 +88,8 @@ type CallStackLimitExceededError struct {
 	Limit uint64
 }
 
+func (CallStackLimitExceededError) IsUserError() {}
+
 func (e CallStackLimitExceededError) Error() string {
 	return fmt.Sprintf(
 		"call stack limit exceeded: %d",
@@ -87,12 +97,34 @@ func (e CallStackLimitExceededError) Error() string {
 	)
 }
 
+// StorageCapacityExceededError is reported when the storage used by an account
+// exceeds the account's storage capacity after storage was committed
+//
+type StorageCapacityExceededError struct {
+	Address         common.Address
+	StorageUsed     uint64
+	StorageCapacity uint64
+}
+
+func (StorageCapacityExceededError) IsUserError() {}
+
+func (e StorageCapacityExceededError) Error() string {
+	return fmt.Sprintf(
+		"storage capacity exceeded: account %s uses %d bytes of storage, but has a capacity of %d bytes",
+		e.Address,
+		e.StorageUsed,
+		e.StorageCapacity,
+	)
+}
+
 // InvalidTransactionCountError
 
 type InvalidTransactionCountError struct {
 	Count int
 }
 
+func (InvalidTransactionCountError) IsUserError() {}
+
 func (e InvalidTransactionCountError) Error() string {
 	if e.Count == 0 {
 		return "no transaction declared: expected 1, got 0"
@@ -111,6 +143,8 @@ type InvalidEntryPointParameterCountError struct {
 	Actual   int
 }
 
+func (InvalidEntryPointParameterCountError) IsUserError() {}
+
 func (e InvalidEntryPointParameterCountError) Error() string {
 	return fmt.Sprintf(
 		"entry point parameter count mismatch: expected %d, got %d",
@@ -126,6 +160,8 @@ type InvalidTransactionAuthorizerCountError struct {
 	Actual   int
 }
 
+func (InvalidTransactionAuthorizerCountError) IsUserError() {}
+
 func (e InvalidTransactionAuthorizerCountError) Error() string {
 	return fmt.Sprintf(
 		"authorizer count mismatch for transaction: expected %d, got %d",
@@ -134,23 +170,55 @@ func (e InvalidTransactionAuthorizerCountError) Error() string {
 	)
 }
 
-// InvalidEntryPointArgumentError
+// InvalidEntryPointArgumentError is an error that is reported for
+// transaction and script arguments which cannot be decoded or imported,
+// or which are not valid for the declared type of the parameter.
+//
+// The cause is available through Unwrap
 //
 type InvalidEntryPointArgumentError struct {
 	Index int
-	Err   error
+	// ParameterType is the declared type of the parameter
+	ParameterType sema.Type
+	// ArgumentType is the type of the provided argument.
+	// It is nil if the argument could not be decoded
+	ArgumentType cadence.Type
+	Err          error
 }
 
+func (InvalidEntryPointArgumentError) IsUserError() {}
+
 func (e *InvalidEntryPointArgumentError) Unwrap() error {
 	return e.Err
 }
 
 func (e *InvalidEntryPointArgumentError) Error() string {
-	return fmt.Sprintf(
-		"invalid argument at index %d: %s",
-		e.Index,
-		e.Err.Error(),
-	)
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("invalid argument at index %d", e.Index))
+
+	if e.ParameterType != nil {
+		builder.WriteString(
+			fmt.Sprintf(
+				" for parameter of type `%s`",
+				e.ParameterType.QualifiedString(),
+			),
+		)
+	}
+
+	if e.ArgumentType != nil {
+		builder.WriteString(
+			fmt.Sprintf(
+				", got argument of type `%s`",
+				e.ArgumentType.ID(),
+			),
+		)
+	}
+
+	builder.WriteString(": ")
+	builder.WriteString(e.Err.Error())
+
+	return builder.String()
 }
 
 // MalformedValueError
@@ -159,6 +227,8 @@ type MalformedValueError struct {
 	ExpectedType sema.Type
 }
 
+func (MalformedValueError) IsUserError() {}
+
 func (e *MalformedValueError) Error() string {
 	return fmt.Sprintf(
 		"value does not conform to expected type `%s`",
@@ -172,6 +242,8 @@ type InvalidValueTypeError struct {
 	ExpectedType sema.Type
 }
 
+func (InvalidValueTypeError) IsUserError() {}
+
 func (e *InvalidValueTypeError) Error() string {
 	return fmt.Sprintf(
 		"expected value of type `%s`",
@@ -190,6 +262,8 @@ type InvalidScriptReturnTypeError struct {
 	Type sema.Type
 }
 
+func (InvalidScriptReturnTypeError) IsUserError() {}
+
 func (e *InvalidScriptReturnTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid script return type: `%s`",
@@ -207,6 +281,8 @@ type ScriptParameterTypeNotStorableError struct {
 	Type sema.Type
 }
 
+func (ScriptParameterTypeNotStorableError) IsUserError() {}
+
 func (e *ScriptParameterTypeNotStorableError) Error() string {
 	return fmt.Sprintf(
 		"parameter type is non-storable type: `%s`",
@@ -224,6 +300,8 @@ type ScriptParameterTypeNotImportableError struct {
 	Type sema.Type
 }
 
+func (ScriptParameterTypeNotImportableError) IsUserError() {}
+
 func (e *ScriptParameterTypeNotImportableError) Error() string {
 	return fmt.Sprintf(
 		"parameter type is a non-importable type: `%s`",
@@ -235,12 +313,16 @@ func (e *ScriptParameterTypeNotImportableError) Error() string {
 // script arguments that belongs to non-importable types.
 //
 type ArgumentNotImportableError struct {
-	Type interpreter.DynamicType
+	Index int
+	Type  interpreter.DynamicType
 }
 
+func (ArgumentNotImportableError) IsUserError() {}
+
 func (e *ArgumentNotImportableError) Error() string {
 	return fmt.Sprintf(
-		"argument type is not importable: `%s`",
+		"invalid argument at index %d: argument type is not importable: `%s`",
+		e.Index,
 		e.Type,
 	)
 }
@@ -253,6 +335,8 @@ type ParsingCheckingError struct {
 	Location common.Location
 }
 
+func (ParsingCheckingError) IsUserError() {}
+
 func (e *ParsingCheckingError) ChildErrors() []error {
 	return []error{e.Err}
 }
@@ -276,6 +360,8 @@ type InvalidContractDeploymentError struct {
 	interpreter.LocationRange
 }
 
+func (InvalidContractDeploymentError) IsUserError() {}
+
 func (e *InvalidContractDeploymentError) Error() string {
 	return fmt.Sprintf("cannot deploy invalid contract: %s", e.Err.Error())
 }
@@ -293,6 +379,31 @@ func (e *InvalidContractDeploymentError) Unwrap() error {
 	return e.Err
 }
 
+// ContractDeclarationMismatchError is reported when deployed code does not declare
+// exactly one contract or contract interface with the name of the deployment
+//
+type ContractDeclarationMismatchError struct {
+	Name          string
+	DeclaredNames []string
+}
+
+func (ContractDeclarationMismatchError) IsUserError() {}
+
+func (e *ContractDeclarationMismatchError) Error() string {
+	if len(e.DeclaredNames) != 1 {
+		return fmt.Sprintf(
+			"the code must declare exactly one contract or contract interface, got %d",
+			len(e.DeclaredNames),
+		)
+	}
+
+	return fmt.Sprintf(
+		"the name must match the name of the declaration: got %q, expected %q",
+		e.Name,
+		e.DeclaredNames[0],
+	)
+}
+
 // ContractRemovalError
 //
 type ContractRemovalError struct {
@@ -300,6 +411,8 @@ type ContractRemovalError struct {
 	interpreter.LocationRange
 }
 
+func (ContractRemovalError) IsUserError() {}
+
 func (e *ContractRemovalError) Error() string {
 	return fmt.Sprintf("cannot remove contract `%s`", e.Name)
 }
@@ -310,6 +423,8 @@ type InvalidContractDeploymentOriginError struct {
 	interpreter.LocationRange
 }
 
+func (InvalidContractDeploymentOriginError) IsUserError() {}
+
 func (*InvalidContractDeploymentOriginError) Error() string {
 	return "cannot deploy invalid contract"
 }
@@ -324,6 +439,8 @@ type ContractUpdateError struct {
 	Location     common.Location
 }
 
+func (ContractUpdateError) IsUserError() {}
+
 func (e *ContractUpdateError) Error() string {
 	return fmt.Sprintf("cannot update contract `%s`", e.ContractName)
 }
@@ -19,10 +19,55 @@
 package errors
 
 import (
+	goErrors "errors"
 	"fmt"
 	"runtime/debug"
 )
 
+// UserError is an error which is caused by a user-provided program or input,
+// e.g. a failed pre-condition, an overflow, or an invalid argument.
+//
+// User errors are expected and are reported to the user.
+//
+// NOTE: Inside the interpreter, user errors are still propagated as panics,
+// which are recovered at the boundary of the interpreter, see Interpreter.RecoverErrors.
+// Converting the hot error paths to explicit error returns is not done yet, see the roadmap.
+//
+type UserError interface {
+	error
+	IsUserError()
+}
+
+// InternalError is an error which is caused by a bug in the runtime or the embedder,
+// i.e. an invariant of the implementation was violated.
+//
+// Internal errors should never occur, and must not be reported as a problem of the user's program.
+//
+type InternalError interface {
+	error
+	IsInternalError()
+}
+
+// IsInternalError returns true if the given error, or any error it wraps, is an internal error.
+//
+func IsInternalError(err error) bool {
+	var internalErr InternalError
+	return goErrors.As(err, &internalErr)
+}
+
+// IsUserError returns true if the given error, or any error it wraps, is a user error,
+// and the error is not an internal error.
+//
+// Internal errors take precedence, as a user error might be the result of an internal error.
+//
+func IsUserError(err error) bool {
+	if IsInternalError(err) {
+		return false
+	}
+	var userErr UserError
+	return goErrors.As(err, &userErr)
+}
+
 // UnreachableError
 
 // UnreachableError is an internal error in the runtime which should have never occurred
@@ -35,6 +80,10 @@ type UnreachableError struct {
 	Stack []byte
 }
 
+var _ InternalError = UnreachableError{}
+
+func (UnreachableError) IsInternalError() {}
+
 func (e UnreachableError) Error() string {
 	return fmt.Sprintf("unreachable\n%s", e.Stack)
 }
@@ -43,6 +92,31 @@ func NewUnreachableError() *UnreachableError {
 	return &UnreachableError{Stack: debug.Stack()}
 }
 
+// UnexpectedError
+
+// UnexpectedError is an internal error in the runtime,
+// e.g. an unexpected failure or a panic with a value that is not an error.
+//
+type UnexpectedError struct {
+	Message string
+	Stack   []byte
+}
+
+var _ InternalError = UnexpectedError{}
+
+func (UnexpectedError) IsInternalError() {}
+
+func (e UnexpectedError) Error() string {
+	return e.Message
+}
+
+func NewUnexpectedError(format string, args ...interface{}) UnexpectedError {
+	return UnexpectedError{
+		Message: fmt.Sprintf(format, args...),
+		Stack:   debug.Stack(),
+	}
+}
+
 // SecondaryError
 
 // SecondaryError is an interface for errors that provide a secondary error message
@@ -0,0 +1,384 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// ComputationEstimate is the estimated cost of executing a transaction,
+// see Runtime.EstimateComputation
+//
+type ComputationEstimate struct {
+	// ComputationUsed is the computation used by the transaction,
+	// metered in the same way as for executions
+	ComputationUsed uint64
+	// StorageDelta is the change of the storage used by the transaction in bytes,
+	// i.e. the difference of the sizes (key and value) of all written registers
+	StorageDelta int64
+	// EventCount is the number of events emitted by the transaction
+	EventCount int
+}
+
+func (r *interpreterRuntime) EstimateComputation(script Script, context Context) (ComputationEstimate, error) {
+	estimationInterface := newEstimationInterface(context.Interface)
+	context.Interface = estimationInterface
+
+	err := r.ExecuteTransaction(script, context)
+
+	return estimationInterface.estimate(), err
+}
+
+// estimationInterface is an interface used for estimating the cost of an execution.
+//
+// Register writes are buffered and not written to the wrapped interface,
+// emitted events are recorded and not emitted, and contract updates are discarded.
+// Storage indices are allocated from a separate range, so the storage index
+// of the accounts in the wrapped interface is not changed.
+//
+// Accounts are not created in the wrapped interface: the addresses of created accounts
+// are allocated from a separate range, so they only exist during the estimation.
+// Account key changes are buffered, and are only visible during the estimation.
+//
+// All other functions are performed by the wrapped interface, e.g. reads of the execution state.
+//
+type estimationInterface struct {
+	Interface
+	writes          map[estimationRegisterKey][]byte
+	storageIndices  map[string]uint64
+	events          []cadence.Event
+	computationUsed uint64
+	accountCount    uint64
+	accountKeys     map[Address]*estimationAccountKeys
+}
+
+// estimationAccountKeys are the changes to the keys of an account during an estimation
+//
+type estimationAccountKeys struct {
+	// count is the number of keys of the account in the wrapped interface
+	count int
+	// added are the keys added during the estimation, indexed from count.
+	// Encoded keys cannot be decoded, so they have no entry
+	added []*AccountKey
+	// revoked are the indices of the keys in the wrapped interface
+	// which were revoked during the estimation
+	revoked map[int]struct{}
+}
+
+type estimationRegisterKey struct {
+	owner string
+	key   string
+}
+
+// estimationStorageIndexStart is the start of the range
+// from which storage indices are allocated during estimations
+//
+const estimationStorageIndexStart = uint64(1) << 63
+
+// estimationAddressStart is the start of the range
+// from which the addresses of accounts created during estimations are allocated
+//
+const estimationAddressStart = uint64(1) << 63
+
+var _ Interface = &estimationInterface{}
+var _ Metrics = &estimationInterface{}
+
+func newEstimationInterface(wrapped Interface) *estimationInterface {
+	return &estimationInterface{
+		Interface:      wrapped,
+		writes:         map[estimationRegisterKey][]byte{},
+		storageIndices: map[string]uint64{},
+		accountKeys:    map[Address]*estimationAccountKeys{},
+	}
+}
+
+func (i *estimationInterface) GetValue(owner, key []byte) ([]byte, error) {
+	value, ok := i.writes[estimationRegisterKey{
+		owner: string(owner),
+		key:   string(key),
+	}]
+	if ok {
+		return value, nil
+	}
+
+	return i.Interface.GetValue(owner, key)
+}
+
+func (i *estimationInterface) SetValue(owner, key, value []byte) error {
+	i.writes[estimationRegisterKey{
+		owner: string(owner),
+		key:   string(key),
+	}] = value
+
+	return nil
+}
+
+func (i *estimationInterface) ValueExists(owner, key []byte) (bool, error) {
+	value, ok := i.writes[estimationRegisterKey{
+		owner: string(owner),
+		key:   string(key),
+	}]
+	if ok {
+		return len(value) > 0, nil
+	}
+
+	return i.Interface.ValueExists(owner, key)
+}
+
+func (i *estimationInterface) AllocateStorageIndex(owner []byte) (atree.StorageIndex, error) {
+	index, ok := i.storageIndices[string(owner)]
+	if !ok {
+		index = estimationStorageIndexStart
+	}
+	index++
+	i.storageIndices[string(owner)] = index
+
+	var result atree.StorageIndex
+	for j := len(result) - 1; j >= 0; j-- {
+		result[j] = byte(index)
+		index >>= 8
+	}
+
+	return result, nil
+}
+
+func (i *estimationInterface) UpdateAccountContractCode(_ Address, _ string, _ []byte) error {
+	return nil
+}
+
+func (i *estimationInterface) RemoveAccountContractCode(_ Address, _ string) error {
+	return nil
+}
+
+func (i *estimationInterface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *estimationInterface) CreateAccount(_ Address) (Address, error) {
+	i.accountCount++
+
+	var address Address
+	binary.BigEndian.PutUint64(address[:], estimationAddressStart+i.accountCount)
+
+	return address, nil
+}
+
+// changedAccountKeys returns the changes to the keys of the account with the given address.
+//
+// The number of keys of the account in the wrapped interface is determined when the keys
+// of the account are changed for the first time
+//
+func (i *estimationInterface) changedAccountKeys(address Address) (*estimationAccountKeys, error) {
+	keys, ok := i.accountKeys[address]
+	if ok {
+		return keys, nil
+	}
+
+	// The wrapped interface returns no key if there is no key with the given index
+
+	var count int
+	for {
+		key, err := i.Interface.GetAccountKey(address, count)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			break
+		}
+		count++
+	}
+
+	keys = &estimationAccountKeys{
+		count:   count,
+		revoked: map[int]struct{}{},
+	}
+	i.accountKeys[address] = keys
+
+	return keys, nil
+}
+
+func (i *estimationInterface) AddEncodedAccountKey(address Address, _ []byte) error {
+	keys, err := i.changedAccountKeys(address)
+	if err != nil {
+		return err
+	}
+
+	keys.added = append(keys.added, nil)
+
+	return nil
+}
+
+func (i *estimationInterface) AddAccountKey(
+	address Address,
+	publicKey *PublicKey,
+	hashAlgo HashAlgorithm,
+	weight int,
+) (*AccountKey, error) {
+	keys, err := i.changedAccountKeys(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &AccountKey{
+		KeyIndex:  keys.count + len(keys.added),
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+	keys.added = append(keys.added, key)
+
+	return key, nil
+}
+
+func (i *estimationInterface) GetAccountKey(address Address, index int) (*AccountKey, error) {
+	keys, ok := i.accountKeys[address]
+	if !ok {
+		return i.Interface.GetAccountKey(address, index)
+	}
+
+	return i.getChangedAccountKey(keys, address, index)
+}
+
+func (i *estimationInterface) getChangedAccountKey(
+	keys *estimationAccountKeys,
+	address Address,
+	index int,
+) (*AccountKey, error) {
+	if index < 0 {
+		return nil, nil
+	}
+
+	if index >= keys.count {
+		index -= keys.count
+		if index >= len(keys.added) || keys.added[index] == nil {
+			return nil, nil
+		}
+		key := *keys.added[index]
+		return &key, nil
+	}
+
+	key, err := i.Interface.GetAccountKey(address, index)
+	if err != nil || key == nil {
+		return key, err
+	}
+
+	if _, ok := keys.revoked[index]; ok {
+		revokedKey := *key
+		revokedKey.IsRevoked = true
+		key = &revokedKey
+	}
+
+	return key, nil
+}
+
+func (i *estimationInterface) RevokeAccountKey(address Address, index int) (*AccountKey, error) {
+	keys, err := i.changedAccountKeys(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := i.getChangedAccountKey(keys, address, index)
+	if err != nil || key == nil {
+		return key, err
+	}
+
+	if index >= keys.count {
+		keys.added[index-keys.count].IsRevoked = true
+	} else {
+		keys.revoked[index] = struct{}{}
+	}
+
+	key.IsRevoked = true
+
+	return key, nil
+}
+
+// GetComputationLimit returns the computation limit of the wrapped interface.
+// If there is no limit, the maximum limit is returned,
+// as computation is only metered if there is a limit
+//
+func (i *estimationInterface) GetComputationLimit() uint64 {
+	limit := i.Interface.GetComputationLimit()
+	if limit == 0 {
+		return math.MaxUint64
+	}
+	return limit
+}
+
+func (i *estimationInterface) SetComputationUsed(used uint64) error {
+	i.computationUsed = used
+	return nil
+}
+
+// The metrics are reported to the wrapped interface, if it collects metrics
+
+func (i *estimationInterface) ProgramParsed(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramParsed(location, duration)
+	}
+}
+
+func (i *estimationInterface) ProgramChecked(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramChecked(location, duration)
+	}
+}
+
+func (i *estimationInterface) ProgramInterpreted(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramInterpreted(location, duration)
+	}
+}
+
+func (i *estimationInterface) estimate() ComputationEstimate {
+	var storageDelta int64
+
+	// NOTE: ranging over maps is safe (deterministic),
+	// as the sizes are summed up
+
+	for key, value := range i.writes { //nolint:maprangecheck
+		storageDelta += estimationRegisterSize(key, value)
+
+		previousValue, err := i.Interface.GetValue([]byte(key.owner), []byte(key.key))
+		if err == nil {
+			storageDelta -= estimationRegisterSize(key, previousValue)
+		}
+	}
+
+	return ComputationEstimate{
+		ComputationUsed: i.computationUsed,
+		StorageDelta:    storageDelta,
+		EventCount:      len(i.events),
+	}
+}
+
+func estimationRegisterSize(key estimationRegisterKey, value []byte) int64 {
+	if len(value) == 0 {
+		return 0
+	}
+	return int64(len(key.key) + len(value))
+}
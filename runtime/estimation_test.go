@@ -0,0 +1,338 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeEstimateComputation(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	transaction := []byte(`
+      transaction(count: Int) {
+
+          prepare(signer: AuthAccount) {
+              var numbers: [Int] = []
+              var i = 0
+              while i < count {
+                  numbers.append(i)
+                  i = i + 1
+              }
+              signer.save(numbers, to: /storage/numbers)
+          }
+      }
+    `)
+
+	var writes int
+	var events []cadence.Event
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, func(_, _, _ []byte) {
+			writes++
+		}),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			events = append(events, event)
+			return nil
+		},
+		decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+			return jsoncdc.Decode(b)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	estimate := func(count int64) ComputationEstimate {
+		estimate, err := runtime.EstimateComputation(
+			Script{
+				Source: transaction,
+				Arguments: [][]byte{
+					jsoncdc.MustEncode(cadence.NewInt(int(count))),
+				},
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+		return estimate
+	}
+
+	smallEstimate := estimate(1)
+	largeEstimate := estimate(100)
+
+	assert.Greater(t, smallEstimate.ComputationUsed, uint64(0))
+	assert.Greater(t, largeEstimate.ComputationUsed, smallEstimate.ComputationUsed)
+
+	assert.Greater(t, smallEstimate.StorageDelta, int64(0))
+	assert.Greater(t, largeEstimate.StorageDelta, smallEstimate.StorageDelta)
+
+	assert.Equal(t, 0, largeEstimate.EventCount)
+
+	// The effects of the estimated transactions are not committed
+
+	assert.Equal(t, 0, writes)
+	assert.Empty(t, events)
+}
+
+func TestRuntimeEstimateComputationEvents(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      pub contract Test {
+          pub event Tested(value: Int)
+
+          pub fun test(value: Int) {
+              emit Tested(value: value)
+          }
+      }
+    `)
+
+	transaction := []byte(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              Test.test(value: 1)
+              Test.test(value: 2)
+          }
+      }
+    `)
+
+	var accountCode []byte
+	var events []cadence.Event
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(_ Location) (bytes []byte, err error) {
+			return accountCode, nil
+		},
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	// Deploying a contract is not committed when estimating
+
+	deploy := utils.DeploymentTransaction("Test", contract)
+
+	_, err := runtime.EstimateComputation(
+		Script{
+			Source: deploy,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+	assert.Nil(t, accountCode)
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: deploy,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, accountCode)
+
+	events = nil
+
+	estimate, err := runtime.EstimateComputation(
+		Script{
+			Source: transaction,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, estimate.EventCount)
+	assert.Empty(t, events)
+}
+
+func TestRuntimeEstimateComputationAccounts(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	transaction := []byte(`
+      transaction(publicKey: [UInt8]) {
+          prepare(signer: AuthAccount) {
+              let account = AuthAccount(payer: signer)
+              account.save(1, to: /storage/one)
+
+              let key = account.keys.add(
+                  publicKey: PublicKey(
+                      publicKey: publicKey,
+                      signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+                  ),
+                  hashAlgorithm: HashAlgorithm.SHA3_256,
+                  weight: 100.0
+              )
+              assert(key.keyIndex == 0)
+              assert(account.keys.get(keyIndex: 0) != nil)
+
+              let signerKey = signer.keys.add(
+                  publicKey: PublicKey(
+                      publicKey: publicKey,
+                      signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+                  ),
+                  hashAlgorithm: HashAlgorithm.SHA3_256,
+                  weight: 100.0
+              )
+              assert(signerKey.keyIndex == 1)
+
+              assert(signer.keys.revoke(keyIndex: 0)!.isRevoked)
+              assert(signer.keys.get(keyIndex: 0)!.isRevoked)
+              assert(signer.keys.revoke(keyIndex: 2) == nil)
+          }
+      }
+    `)
+
+	existingKey := &AccountKey{
+		KeyIndex: 0,
+		PublicKey: &PublicKey{
+			PublicKey: []byte{1, 2, 3},
+			SignAlgo:  SignatureAlgorithmECDSA_P256,
+		},
+		HashAlgo: HashAlgorithmSHA3_256,
+		Weight:   1000,
+	}
+
+	var writes int
+	var events []cadence.Event
+	var programsParsed int
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, func(_, _, _ []byte) {
+			writes++
+		}),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			events = append(events, event)
+			return nil
+		},
+		decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+			return jsoncdc.Decode(b)
+		},
+		validatePublicKey: func(_ *PublicKey) (bool, error) {
+			return true, nil
+		},
+		createAccount: func(_ Address) (Address, error) {
+			require.FailNow(t, "account must not be created")
+			return Address{}, nil
+		},
+		addAccountKey: func(_ Address, _ *PublicKey, _ HashAlgorithm, _ int) (*AccountKey, error) {
+			require.FailNow(t, "account key must not be added")
+			return nil, nil
+		},
+		removeAccountKey: func(_ Address, _ int) (*AccountKey, error) {
+			require.FailNow(t, "account key must not be revoked")
+			return nil, nil
+		},
+		getAccountKey: func(keyAddress Address, index int) (*AccountKey, error) {
+			if keyAddress != address || index != 0 {
+				return nil, nil
+			}
+			key := *existingKey
+			return &key, nil
+		},
+		programParsed: func(_ common.Location, _ time.Duration) {
+			programsParsed++
+		},
+	}
+
+	estimate, err := runtime.EstimateComputation(
+		Script{
+			Source: transaction,
+			Arguments: encodeArgs([]cadence.Value{
+				newBytesValue([]byte{4, 5, 6}),
+			}),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	// The account created by the estimated transaction and the key changes are not committed,
+	// but they are visible during the estimation
+
+	assert.Greater(t, estimate.ComputationUsed, uint64(0))
+	assert.Greater(t, estimate.StorageDelta, int64(0))
+	assert.Equal(t, 4, estimate.EventCount)
+
+	assert.Equal(t, 0, writes)
+	assert.Empty(t, events)
+	assert.False(t, existingKey.IsRevoked)
+
+	// Metrics are reported to the wrapped interface
+
+	assert.Greater(t, programsParsed, 0)
+}
@@ -0,0 +1,226 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// EventSchema is a version of an event type declared by a deployed contract
+//
+type EventSchema struct {
+	// TypeID is the ID of the event type, e.g. `A.0000000000000001.Token.Deposited`
+	TypeID common.TypeID
+	// Version is the version of the event type, starting at 1.
+	// A new version is recorded when the fields of the event type change
+	Version int
+	// EventType is the exported event type
+	EventType *cadence.EventType
+}
+
+// EventSchemaRegistry records the event types declared by deployed contracts,
+// when contracts are added or updated, so events can be interpreted
+// using the event type which was declared at the time they were emitted,
+// e.g. after a contract update changed the event type.
+//
+// The registry is owned by the embedder and may be shared across executions,
+// see Context.EventSchemaRegistry.
+// The event types of a contract are only recorded once the execution which deployed it succeeded.
+//
+type EventSchemaRegistry struct {
+	lock    sync.RWMutex
+	schemas map[common.TypeID][]EventSchema
+}
+
+func NewEventSchemaRegistry() *EventSchemaRegistry {
+	return &EventSchemaRegistry{
+		schemas: map[common.TypeID][]EventSchema{},
+	}
+}
+
+// Register records the given event type.
+//
+// A new version is only recorded if the fields or the indexed fields of the event type differ
+// from the ones of the latest version.
+// It returns the schema of the event type, and true if a new version was recorded.
+//
+func (r *EventSchemaRegistry) Register(eventType *cadence.EventType) (EventSchema, bool) {
+	typeID := common.TypeID(eventType.ID())
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	versions := r.schemas[typeID]
+
+	if len(versions) > 0 {
+		latest := versions[len(versions)-1]
+		if eventFieldsEqual(latest.EventType.Fields, eventType.Fields) &&
+			indexedEventFieldsEqual(latest.EventType.IndexedFields, eventType.IndexedFields) {
+
+			return latest, false
+		}
+	}
+
+	schema := EventSchema{
+		TypeID:    typeID,
+		Version:   len(versions) + 1,
+		EventType: eventType,
+	}
+
+	r.schemas[typeID] = append(versions, schema)
+
+	return schema, true
+}
+
+// Latest returns the latest version of the event type with the given ID, if any
+//
+func (r *EventSchemaRegistry) Latest(typeID common.TypeID) (EventSchema, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	versions := r.schemas[typeID]
+	if len(versions) == 0 {
+		return EventSchema{}, false
+	}
+
+	return versions[len(versions)-1], true
+}
+
+// Version returns the given version of the event type with the given ID, if any
+//
+func (r *EventSchemaRegistry) Version(typeID common.TypeID, version int) (EventSchema, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	versions := r.schemas[typeID]
+	if version < 1 || version > len(versions) {
+		return EventSchema{}, false
+	}
+
+	return versions[version-1], true
+}
+
+// Versions returns all versions of the event type with the given ID,
+// from the oldest to the latest
+//
+func (r *EventSchemaRegistry) Versions(typeID common.TypeID) []EventSchema {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	versions := r.schemas[typeID]
+
+	result := make([]EventSchema, len(versions))
+	copy(result, versions)
+	return result
+}
+
+// SchemaOf returns the latest version of the event's type
+// which has the same fields as the event, if any.
+//
+// For example, an event emitted before a contract update
+// is matched to the version of the event type at the time it was emitted.
+//
+func (r *EventSchemaRegistry) SchemaOf(event cadence.Event) (EventSchema, bool) {
+	if event.EventType == nil {
+		return EventSchema{}, false
+	}
+
+	typeID := common.TypeID(event.EventType.ID())
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	versions := r.schemas[typeID]
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		schema := versions[i]
+		if eventFieldsEqual(schema.EventType.Fields, event.EventType.Fields) {
+			return schema, true
+		}
+	}
+
+	return EventSchema{}, false
+}
+
+func eventFieldsEqual(a, b []cadence.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, field := range a {
+		otherField := b[i]
+		if field.Identifier != otherField.Identifier ||
+			field.Type.ID() != otherField.Type.ID() {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func indexedEventFieldsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, identifier := range a {
+		if identifier != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// declaredEventTypes returns the exported event types declared in the given program,
+// sorted by type ID
+//
+func declaredEventTypes(program *interpreter.Program, location common.Location) []*cadence.EventType {
+	var eventTypes []*cadence.EventType
+
+	results := map[sema.TypeID]cadence.Type{}
+
+	for _, compositeType := range program.Elaboration.CompositeTypes { //nolint:maprangecheck
+		if compositeType.Kind != common.CompositeKindEvent ||
+			!common.LocationsMatch(compositeType.Location, location) {
+
+			continue
+		}
+
+		eventType, ok := ExportType(compositeType, results).(*cadence.EventType)
+		if !ok {
+			continue
+		}
+
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	sort.Slice(eventTypes, func(i, j int) bool {
+		return eventTypes[i].ID() < eventTypes[j].ID()
+	})
+
+	return eventTypes
+}
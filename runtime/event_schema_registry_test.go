@@ -0,0 +1,186 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeEventSchemaRegistry(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	registry := NewEventSchemaRegistry()
+
+	var accountCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getCode: func(_ Location) ([]byte, error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	execute := func(transaction []byte) error {
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: transaction,
+			},
+			Context{
+				Interface:           runtimeInterface,
+				Location:            nextTransactionLocation(),
+				EventSchemaRegistry: registry,
+			},
+		)
+	}
+
+	const typeID = common.TypeID("A.0000000000000001.Test.Transferred")
+
+	contract := func(fields string, answer int) []byte {
+		return []byte(fmt.Sprintf(
+			`
+              pub contract Test {
+                  pub event Transferred(%s)
+
+                  pub fun answer(): Int {
+                      return %d
+                  }
+              }
+            `,
+			fields,
+			answer,
+		))
+	}
+
+	// Deploy
+
+	err := execute(utils.DeploymentTransaction("Test", contract("amount: Int", 1)))
+	require.NoError(t, err)
+
+	firstSchema, ok := registry.Latest(typeID)
+	require.True(t, ok)
+	assert.Equal(t, typeID, firstSchema.TypeID)
+	assert.Equal(t, 1, firstSchema.Version)
+	assert.Equal(t,
+		[]cadence.Field{
+			{
+				Identifier: "amount",
+				Type:       cadence.IntType{},
+			},
+		},
+		firstSchema.EventType.Fields,
+	)
+
+	// An update which does not change the event type does not record a new version
+
+	err = execute(utils.UpdateTransaction("Test", contract("amount: Int", 2)))
+	require.NoError(t, err)
+
+	assert.Len(t, registry.Versions(typeID), 1)
+
+	// An update which changes the event type records a new version
+
+	err = execute(utils.UpdateTransaction("Test", contract("amount: Int, memo: String", 3)))
+	require.NoError(t, err)
+
+	secondSchema, ok := registry.Latest(typeID)
+	require.True(t, ok)
+	assert.Equal(t, 2, secondSchema.Version)
+	assert.Len(t, secondSchema.EventType.Fields, 2)
+
+	schema, ok := registry.Version(typeID, 1)
+	require.True(t, ok)
+	assert.Equal(t, firstSchema, schema)
+
+	_, ok = registry.Version(typeID, 3)
+	assert.False(t, ok)
+
+	// Events emitted before the update are matched to the previous version
+
+	schema, ok = registry.SchemaOf(
+		cadence.NewEvent([]cadence.Value{cadence.NewInt(1)}).
+			WithType(&cadence.EventType{
+				Location:            common.AddressLocation{Address: address, Name: "Test"},
+				QualifiedIdentifier: "Test.Transferred",
+				Fields: []cadence.Field{
+					{
+						Identifier: "amount",
+						Type:       cadence.IntType{},
+					},
+				},
+			}),
+	)
+	require.True(t, ok)
+	assert.Equal(t, 1, schema.Version)
+
+	// The event types of a failed execution are not recorded
+
+	err = execute([]byte(fmt.Sprintf(
+		`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.contracts.update__experimental(name: "Test", code: "%x".decodeHex())
+                  panic("failed")
+              }
+          }
+        `,
+		contract("memo: String", 4),
+	)))
+	require.Error(t, err)
+
+	assert.Len(t, registry.Versions(typeID), 2)
+
+	// An update which only marks a field as indexed records a new version
+
+	err = execute(utils.UpdateTransaction("Test", contract("indexed amount: Int, memo: String", 4)))
+	require.NoError(t, err)
+
+	thirdSchema, ok := registry.Latest(typeID)
+	require.True(t, ok)
+	assert.Equal(t, 3, thirdSchema.Version)
+	assert.Equal(t, []string{"amount"}, thirdSchema.EventType.IndexedFields)
+}
@@ -0,0 +1,241 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ExecutionResult is the result of an execution,
+// see Runtime.ExecuteScriptWithResult and Runtime.ExecuteTransactionWithResult
+//
+type ExecutionResult struct {
+	// Value is the result of the script, or nil for transactions and failed executions
+	Value cadence.Value
+	// Events are the events emitted by the execution, in emission order
+	Events []cadence.Event
+	// Logs are the messages logged by the execution, in logging order
+	Logs []string
+	// ComputationUsed is the computation used by the execution.
+	// Computation is only metered if the interface has a computation limit,
+	// otherwise it is zero
+	ComputationUsed uint64
+	// StorageDelta summarizes the changes of the storage by the execution
+	StorageDelta StorageDelta
+	// Warnings are the warnings produced by the execution, e.g. checker hints
+	// for the executed program, and warnings produced by the export of the result
+	Warnings []ExecutionWarning
+}
+
+// StorageDelta summarizes the changes of the storage by an execution
+//
+type StorageDelta struct {
+	// RegistersWritten is the number of registers written by the execution
+	RegistersWritten int
+	// SizeDelta is the change of the storage used by the execution in bytes,
+	// i.e. the difference of the sizes (key and value) of all written registers
+	SizeDelta int64
+}
+
+// ExecutionWarning is a warning produced by an execution
+//
+type ExecutionWarning struct {
+	Message string
+	// Location is the location of the program the warning refers to, if any
+	Location common.Location
+	// Range is the range of the program the warning refers to, if the warning has a location
+	ast.Range
+}
+
+func (r *interpreterRuntime) ExecuteScriptWithResult(script Script, context Context) (ExecutionResult, error) {
+	recorder := newResultRecordingInterface(context.Interface, context.Location)
+	context.Interface = recorder
+	context.resultRecorder = recorder
+
+	reportWarning := context.ExportOptions.ReportWarning
+	context.ExportOptions.ReportWarning = func(warning ExportWarning) {
+		recorder.recordWarning(ExecutionWarning{
+			Message: warning.String(),
+		})
+
+		if reportWarning != nil {
+			reportWarning(warning)
+		}
+	}
+
+	value, err := r.ExecuteScript(script, context)
+
+	result := recorder.result()
+	result.Value = value
+
+	return result, err
+}
+
+func (r *interpreterRuntime) ExecuteTransactionWithResult(script Script, context Context) (ExecutionResult, error) {
+	recorder := newResultRecordingInterface(context.Interface, context.Location)
+	context.Interface = recorder
+	context.resultRecorder = recorder
+
+	err := r.ExecuteTransaction(script, context)
+
+	return recorder.result(), err
+}
+
+// resultRecordingInterface is an interface which records the effects of an execution
+// for its result, see ExecutionResult.
+//
+// All functions are performed by the wrapped interface,
+// i.e. the effects of the execution are not changed
+//
+type resultRecordingInterface struct {
+	Interface
+	location        common.Location
+	events          []cadence.Event
+	logs            []string
+	computationUsed uint64
+	// previousValues are the values of the written registers before their first write
+	previousValues map[estimationRegisterKey][]byte
+	// writtenKeys are the keys of the written registers, in order of their first write
+	writtenKeys []estimationRegisterKey
+	warnings    []ExecutionWarning
+}
+
+var _ Interface = &resultRecordingInterface{}
+var _ Metrics = &resultRecordingInterface{}
+
+func newResultRecordingInterface(wrapped Interface, location common.Location) *resultRecordingInterface {
+	return &resultRecordingInterface{
+		Interface:      wrapped,
+		location:       location,
+		previousValues: map[estimationRegisterKey][]byte{},
+	}
+}
+
+func (i *resultRecordingInterface) SetValue(owner, key, value []byte) error {
+	registerKey := estimationRegisterKey{
+		owner: string(owner),
+		key:   string(key),
+	}
+
+	if _, ok := i.previousValues[registerKey]; !ok {
+		previousValue, err := i.Interface.GetValue(owner, key)
+		if err != nil {
+			return err
+		}
+		i.previousValues[registerKey] = previousValue
+		i.writtenKeys = append(i.writtenKeys, registerKey)
+	}
+
+	return i.Interface.SetValue(owner, key, value)
+}
+
+func (i *resultRecordingInterface) EmitEvent(event cadence.Event) error {
+	err := i.Interface.EmitEvent(event)
+	if err != nil {
+		return err
+	}
+
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *resultRecordingInterface) ProgramLog(message string) error {
+	err := i.Interface.ProgramLog(message)
+	if err != nil {
+		return err
+	}
+
+	i.logs = append(i.logs, message)
+	return nil
+}
+
+func (i *resultRecordingInterface) SetComputationUsed(used uint64) error {
+	i.computationUsed = used
+	return i.Interface.SetComputationUsed(used)
+}
+
+// The metrics are reported to the wrapped interface, if it collects metrics
+
+func (i *resultRecordingInterface) ProgramParsed(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramParsed(location, duration)
+	}
+}
+
+func (i *resultRecordingInterface) ProgramChecked(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramChecked(location, duration)
+	}
+}
+
+func (i *resultRecordingInterface) ProgramInterpreted(location common.Location, duration time.Duration) {
+	if metrics, ok := i.Interface.(Metrics); ok {
+		metrics.ProgramInterpreted(location, duration)
+	}
+}
+
+func (i *resultRecordingInterface) recordWarning(warning ExecutionWarning) {
+	i.warnings = append(i.warnings, warning)
+}
+
+// recordHints records the hints of the checker as warnings,
+// if the checker checked the executed program, and not e.g. an imported program
+//
+func (i *resultRecordingInterface) recordHints(checker *sema.Checker) {
+	if !common.LocationsMatch(checker.Location, i.location) {
+		return
+	}
+
+	for _, hint := range checker.Hints() {
+		i.recordWarning(ExecutionWarning{
+			Message:  hint.Hint(),
+			Location: checker.Location,
+			Range:    ast.NewRangeFromPositioned(hint),
+		})
+	}
+}
+
+func (i *resultRecordingInterface) result() ExecutionResult {
+	storageDelta := StorageDelta{
+		RegistersWritten: len(i.writtenKeys),
+	}
+
+	for _, key := range i.writtenKeys {
+		value, err := i.Interface.GetValue([]byte(key.owner), []byte(key.key))
+		if err != nil {
+			continue
+		}
+
+		storageDelta.SizeDelta += estimationRegisterSize(key, value) -
+			estimationRegisterSize(key, i.previousValues[key])
+	}
+
+	return ExecutionResult{
+		Events:          i.events,
+		Logs:            i.logs,
+		ComputationUsed: i.computationUsed,
+		StorageDelta:    storageDelta,
+		Warnings:        i.warnings,
+	}
+}
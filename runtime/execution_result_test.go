@@ -0,0 +1,277 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/stdlib"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeExecutionResult(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	newRuntimeInterface := func(events *[]cadence.Event, logs *[]string) *testRuntimeInterface {
+		var contractCode []byte
+
+		return &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return contractCode, nil
+			},
+			updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+				contractCode = code
+				return nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				*events = append(*events, event)
+				return nil
+			},
+			log: func(message string) {
+				*logs = append(*logs, message)
+			},
+			computationLimit: 1000,
+		}
+	}
+
+	t.Run("transaction", func(t *testing.T) {
+
+		t.Parallel()
+
+		var events []cadence.Event
+		var logs []string
+
+		runtimeInterface := newRuntimeInterface(&events, &logs)
+
+		result, err := newTestInterpreterRuntime().ExecuteTransactionWithResult(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.save("Hello", to: /storage/greeting)
+                          log("saved")
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Nil(t, result.Value)
+		assert.Equal(t, []string{`"saved"`}, result.Logs)
+		assert.Empty(t, result.Events)
+		assert.Empty(t, result.Warnings)
+		assert.Greater(t, result.ComputationUsed, uint64(0))
+
+		assert.Greater(t, result.StorageDelta.RegistersWritten, 0)
+		assert.Greater(t, result.StorageDelta.SizeDelta, int64(0))
+
+		// The effects are still performed by the interface
+
+		assert.Equal(t, logs, result.Logs)
+
+		// Deploying a contract emits an event
+
+		result, err = newTestInterpreterRuntime().ExecuteTransactionWithResult(
+			Script{
+				Source: DeploymentTransaction("C", []byte(`pub contract C {}`)),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, result.Events, 1)
+		assert.Equal(t,
+			string(stdlib.AccountContractAddedEventType.ID()),
+			result.Events[0].EventType.ID(),
+		)
+		assert.Equal(t, events, result.Events)
+	})
+
+	t.Run("script", func(t *testing.T) {
+
+		t.Parallel()
+
+		var events []cadence.Event
+		var logs []string
+
+		runtimeInterface := newRuntimeInterface(&events, &logs)
+
+		result, err := newTestInterpreterRuntime().ExecuteScriptWithResult(
+			Script{
+				Source: []byte(`
+                  /// @deprecated
+                  pub fun one(): Int {
+                      return 1
+                  }
+
+                  pub fun main(): Int {
+                      let x = one()
+                      log(x)
+                      return x
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewInt(1), result.Value)
+		assert.Equal(t, []string{"1"}, result.Logs)
+		assert.Equal(t, StorageDelta{}, result.StorageDelta)
+
+		// The use of the deprecated function is reported as a warning
+
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, common.ScriptLocation{}, result.Warnings[0].Location)
+		assert.Equal(t, 8, result.Warnings[0].StartPos.Line)
+	})
+
+	t.Run("failed execution", func(t *testing.T) {
+
+		t.Parallel()
+
+		var events []cadence.Event
+		var logs []string
+
+		result, err := newTestInterpreterRuntime().ExecuteScriptWithResult(
+			Script{
+				Source: []byte(`
+                  pub fun main(): Int {
+                      log("before")
+                      panic("fail")
+                  }
+                `),
+			},
+			Context{
+				Interface: newRuntimeInterface(&events, &logs),
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.Error(t, err)
+
+		assert.Nil(t, result.Value)
+		assert.Equal(t, []string{`"before"`}, result.Logs)
+	})
+
+	t.Run("export warnings", func(t *testing.T) {
+
+		t.Parallel()
+
+		var events []cadence.Event
+		var logs []string
+
+		var reportedWarnings []ExportWarning
+
+		result, err := newTestInterpreterRuntime().ExecuteScriptWithResult(
+			Script{
+				Source: []byte(`
+                  pub fun main(): AnyStruct {
+                      return fun (): Int { return 1 }
+                  }
+                `),
+			},
+			Context{
+				Interface: newRuntimeInterface(&events, &logs),
+				Location:  common.ScriptLocation{},
+				ExportOptions: ExportOptions{
+					FunctionExportMode: FunctionExportModeStrip,
+					ReportWarning: func(warning ExportWarning) {
+						reportedWarnings = append(reportedWarnings, warning)
+					},
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewOptional(nil), result.Value)
+
+		// The warnings are reported to the export options, and included in the result
+
+		require.Len(t, reportedWarnings, 1)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, reportedWarnings[0].String(), result.Warnings[0].Message)
+		assert.Nil(t, result.Warnings[0].Location)
+	})
+
+	t.Run("hints of imports", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Hints of imported programs are not reported as warnings of the execution
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getCode: func(_ Location) ([]byte, error) {
+				return []byte(`
+                  /// @deprecated
+                  pub fun fortyTwo(): Int {
+                      return 42
+                  }
+
+                  pub fun answer(): Int {
+                      return fortyTwo()
+                  }
+                `), nil
+			},
+		}
+
+		result, err := newTestInterpreterRuntime().ExecuteScriptWithResult(
+			Script{
+				Source: []byte(`
+                  import "imported"
+
+                  pub fun main(): Int {
+                      return answer()
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewInt(42), result.Value)
+		assert.Empty(t, result.Warnings)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// OperationKind is a kind of operation which is counted in an execution report
+//
+type OperationKind string
+
+const (
+	// OperationKindFunctionInvocation is the invocation of a function
+	OperationKindFunctionInvocation OperationKind = "functionInvocation"
+	// OperationKindLoopIteration is an iteration of a loop,
+	// or an invocation of a function passed to a container function like `map`
+	OperationKindLoopIteration OperationKind = "loopIteration"
+	// OperationKindStorageRead is the read of a register
+	OperationKindStorageRead OperationKind = "storageRead"
+	// OperationKindStorageWrite is the write of a register
+	OperationKindStorageWrite OperationKind = "storageWrite"
+	// OperationKindEncode is the encoding of a stored value, i.e. the write of a non-empty slab
+	OperationKindEncode OperationKind = "encode"
+	// OperationKindDecode is the decoding of a stored value, i.e. the read of a non-empty slab
+	OperationKindDecode OperationKind = "decode"
+	// OperationKindBigIntOperation is an arithmetic, bitwise, or comparison operation
+	// on integers which are represented as big integers, e.g. Int and UInt256
+	OperationKindBigIntOperation OperationKind = "bigIntOperation"
+)
+
+// OperationKinds are all kinds of operations which are counted in an execution report
+//
+var OperationKinds = []OperationKind{
+	OperationKindFunctionInvocation,
+	OperationKindLoopIteration,
+	OperationKindStorageRead,
+	OperationKindStorageWrite,
+	OperationKindEncode,
+	OperationKindDecode,
+	OperationKindBigIntOperation,
+}
+
+// ExecutionReport counts the operations performed by an execution, by kind,
+// see Context.ExecutionReport.
+//
+// The counts can be used for performance analysis,
+// and to calibrate the costs of a computation cost table, see ComputationCostTable.
+//
+type ExecutionReport struct {
+	Operations map[OperationKind]uint64 `json:"operations"`
+}
+
+func NewExecutionReport() *ExecutionReport {
+	return &ExecutionReport{
+		Operations: map[OperationKind]uint64{},
+	}
+}
+
+func (r *ExecutionReport) AddOperation(kind OperationKind) {
+	r.Operations[kind]++
+}
+
+// OperationCount returns the number of operations of the given kind
+//
+func (r *ExecutionReport) OperationCount(kind OperationKind) uint64 {
+	return r.Operations[kind]
+}
+
+// Reset removes all counts, so the report can be used for another execution
+//
+func (r *ExecutionReport) Reset() {
+	r.Operations = map[OperationKind]uint64{}
+}
+
+// reportingLedger is a ledger which counts the register reads and writes in an execution report,
+// and the reads and writes of slabs as decodes and encodes of stored values
+//
+type reportingLedger struct {
+	atree.Ledger
+	report *ExecutionReport
+}
+
+func (l reportingLedger) GetValue(owner, key []byte) ([]byte, error) {
+	value, err := l.Ledger.GetValue(owner, key)
+
+	l.report.AddOperation(OperationKindStorageRead)
+	if len(value) > 0 && atree.LedgerKeyIsSlabKey(string(key)) {
+		l.report.AddOperation(OperationKindDecode)
+	}
+
+	return value, err
+}
+
+func (l reportingLedger) SetValue(owner, key, value []byte) error {
+	l.report.AddOperation(OperationKindStorageWrite)
+	if len(value) > 0 && atree.LedgerKeyIsSlabKey(string(key)) {
+		l.report.AddOperation(OperationKindEncode)
+	}
+
+	return l.Ledger.SetValue(owner, key, value)
+}
+
+// bigIntOperationReportingHandler returns the big integer operation handler
+// which counts the operations in the given execution report, if any
+//
+func bigIntOperationReportingHandler(report *ExecutionReport) interpreter.OnBigIntOperationFunc {
+	if report == nil {
+		return nil
+	}
+
+	return func(_ *interpreter.Interpreter, _ ast.Operation) {
+		report.AddOperation(OperationKindBigIntOperation)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestRuntimeExecutionReport(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("script", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		report := NewExecutionReport()
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(`
+                  pub fun double(_ x: Int): Int {
+                      return x * 2
+                  }
+
+                  pub fun main(): Int {
+                      var sum = 0
+                      var i: UInt8 = 0
+                      while i < 3 {
+                          sum = sum + double(Int(i))
+                          i = i + 1
+                      }
+                      return sum
+                  }
+                `),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        common.ScriptLocation{},
+				ExecutionReport: report,
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(6), value)
+
+		// The loop iterations, the invocations of `double` and `Int`,
+		// and the multiplications and additions of `Int` values
+		assert.Equal(t, uint64(3), report.OperationCount(OperationKindLoopIteration))
+		assert.Equal(t, uint64(6), report.OperationCount(OperationKindFunctionInvocation))
+		assert.Equal(t, uint64(6), report.OperationCount(OperationKindBigIntOperation))
+
+		assert.Zero(t, report.OperationCount(OperationKindStorageWrite))
+		assert.Zero(t, report.OperationCount(OperationKindEncode))
+	})
+
+	t.Run("storage", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		address := common.BytesToAddress([]byte{0x1})
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		report := NewExecutionReport()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.save([1, 2, 3], to: /storage/numbers)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        nextTransactionLocation(),
+				ExecutionReport: report,
+			},
+		)
+		require.NoError(t, err)
+
+		assert.NotZero(t, report.OperationCount(OperationKindStorageRead))
+		assert.NotZero(t, report.OperationCount(OperationKindStorageWrite))
+		assert.NotZero(t, report.OperationCount(OperationKindEncode))
+
+		report.Reset()
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(`
+                  pub fun main(): [Int] {
+                      return getAuthAccount(0x1).copy<[Int]>(from: /storage/numbers)!
+                  }
+                `),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        common.ScriptLocation{},
+				ExecutionReport: report,
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+				cadence.NewInt(3),
+			}),
+			value,
+		)
+
+		assert.NotZero(t, report.OperationCount(OperationKindStorageRead))
+		assert.NotZero(t, report.OperationCount(OperationKindDecode))
+		assert.Zero(t, report.OperationCount(OperationKindStorageWrite))
+		assert.Zero(t, report.OperationCount(OperationKindEncode))
+	})
+}
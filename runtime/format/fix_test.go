@@ -19,9 +19,12 @@
 package format
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 func TestUFix64(t *testing.T) {
@@ -30,3 +33,28 @@ func TestUFix64(t *testing.T) {
 
 	require.Equal(t, "99999999999.70000000", UFix64(9999999999970000000))
 }
+
+func TestFix128(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t,
+		"-0.500000000000000000000000",
+		Fix128(new(big.Int).Div(sema.Fix128FactorBig, big.NewInt(-2))),
+	)
+
+	require.Equal(t,
+		"-1.000000000000000000000001",
+		Fix128(new(big.Int).Neg(new(big.Int).Add(sema.Fix128FactorBig, big.NewInt(1)))),
+	)
+}
+
+func TestUFix128(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t,
+		"340282366920938.463463374607431768211455",
+		UFix128(sema.UFix128TypeMaxBig),
+	)
+}
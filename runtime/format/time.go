@@ -0,0 +1,39 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"time"
+)
+
+// Timestamp formats the given number of nanoseconds since the Unix epoch
+// as an RFC 3339 date and time in UTC, e.g. `2021-03-04T05:06:07.5Z`.
+// Trailing zeros of the fractional seconds are removed.
+//
+func Timestamp(unixNanoseconds int64) string {
+	return time.Unix(0, unixNanoseconds).UTC().Format(time.RFC3339Nano)
+}
+
+// Duration formats the given number of nanoseconds
+// as a sequence of decimal numbers with unit suffixes, e.g. `1h2m3.5s`.
+// Durations shorter than one second use smaller units, e.g. `1.5ms`.
+//
+func Duration(nanoseconds int64) string {
+	return time.Duration(nanoseconds).String()
+}
@@ -0,0 +1,45 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t, "1970-01-01T00:00:00Z", Timestamp(0))
+	require.Equal(t, "2021-03-04T05:06:07.5Z", Timestamp(1614834367500000000))
+	require.Equal(t, "1969-12-31T23:59:59.999999999Z", Timestamp(-1))
+	require.Equal(t, "2262-04-11T23:47:16.854775807Z", Timestamp(math.MaxInt64))
+}
+
+func TestDuration(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t, "0s", Duration(0))
+	require.Equal(t, "1h2m3.5s", Duration(3723500000000))
+	require.Equal(t, "-1.5ms", Duration(-1500000))
+}
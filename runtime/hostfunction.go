@@ -0,0 +1,79 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// HostFunction is a function implemented by the embedder,
+// which is declared in all programs executed with a context, see Context.HostFunctions.
+//
+// Host functions allow embedders to expose chain-specific functionality
+// without modifying the standard library.
+//
+type HostFunction struct {
+	Name      string
+	Type      *sema.FunctionType
+	DocString string
+	// ComputationCost is the computation used by each invocation of the function,
+	// in addition to the computation used by any function invocation
+	ComputationCost uint64
+	// Available, if set, determines if the function is available in a location
+	Available func(common.Location) bool
+	Function  interpreter.HostFunction
+}
+
+// valueDeclaration returns the declaration of the host function.
+//
+// If the given computation meter is not nil, each invocation of the function
+// reports the given computation cost to it, see ComputationCostTable.HostFunctionCost
+//
+func (f HostFunction) valueDeclaration(meterComputation func(uint64), computationCost uint64) ValueDeclaration {
+
+	function := f.Function
+
+	if meterComputation != nil && computationCost > 0 {
+		implementation := function
+
+		function = func(invocation interpreter.Invocation) interpreter.Value {
+			meterComputation(computationCost)
+			return implementation(invocation)
+		}
+	}
+
+	parameters := f.Type.Parameters
+	argumentLabels := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		argumentLabels[i] = parameter.EffectiveArgumentLabel()
+	}
+
+	return ValueDeclaration{
+		Name:           f.Name,
+		Type:           f.Type,
+		DocString:      f.DocString,
+		Kind:           common.DeclarationKindFunction,
+		IsConstant:     true,
+		ArgumentLabels: argumentLabels,
+		Available:      f.Available,
+		Value:          interpreter.NewHostFunctionValue(function, f.Type),
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func newTestDoubleHostFunction(computationCost uint64) HostFunction {
+	return HostFunction{
+		Name: "double",
+		Type: &sema.FunctionType{
+			Parameters: []*sema.Parameter{
+				{
+					Label:          sema.ArgumentLabelNotRequired,
+					Identifier:     "value",
+					TypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+				},
+			},
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+		},
+		ComputationCost: computationCost,
+		Function: func(invocation interpreter.Invocation) interpreter.Value {
+			value := invocation.Arguments[0].(interpreter.IntValue)
+			return value.Plus(value)
+		},
+	}
+}
+
+func TestRuntimeHostFunction(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+      pub fun main(): Int {
+          return double(21)
+      }
+    `)
+
+	t.Run("invocation", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:     runtimeInterface,
+				Location:      common.ScriptLocation{},
+				HostFunctions: []HostFunction{newTestDoubleHostFunction(0)},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewInt(42), value)
+	})
+
+	t.Run("undeclared", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checkerErr.Errors
+		require.Len(t, errs, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		hostFunction := newTestDoubleHostFunction(0)
+		hostFunction.Available = func(location common.Location) bool {
+			_, ok := location.(common.TransactionLocation)
+			return ok
+		}
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:     runtimeInterface,
+				Location:      common.ScriptLocation{},
+				HostFunctions: []HostFunction{hostFunction},
+			},
+		)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checkerErr.Errors
+		require.Len(t, errs, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("computation cost", func(t *testing.T) {
+
+		t.Parallel()
+
+		const computationLimit = 100
+
+		test := func(computationCost uint64) (cadence.Value, error) {
+
+			runtime := newTestInterpreterRuntime()
+
+			runtimeInterface := &testRuntimeInterface{
+				storage:          newTestLedger(nil, nil),
+				computationLimit: computationLimit,
+			}
+
+			return runtime.ExecuteScript(
+				Script{
+					Source: script,
+				},
+				Context{
+					Interface:     runtimeInterface,
+					Location:      common.ScriptLocation{},
+					HostFunctions: []HostFunction{newTestDoubleHostFunction(computationCost)},
+				},
+			)
+		}
+
+		value, err := test(10)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(42), value)
+
+		_, err = test(computationLimit)
+		require.Error(t, err)
+
+		var computationLimitErr ComputationLimitExceededError
+		require.ErrorAs(t, err, &computationLimitErr)
+
+		assert.Equal(t,
+			ComputationLimitExceededError{
+				Limit: computationLimit,
+			},
+			computationLimitErr,
+		)
+	})
+}
@@ -0,0 +1,166 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// ImportCache caches the parsed and checked programs of imported locations
+// across executions, e.g. across the transactions of a block,
+// so imports of the same contracts are not parsed and checked again.
+//
+// The cache is owned by the embedder: it should create a new cache
+// for each block, and pass it in the context of each execution, see Context.ImportCache.
+// The executions sharing a cache must use the same predeclared values.
+//
+// Programs are keyed by location and code, and a program is only reused
+// if neither its code nor the code of any of its imports, direct or transitive,
+// changed, e.g. through a contract update.
+//
+// Programs which import a program provided by the host environment (see Interface.GetProgram)
+// are not cached, as the imports of such a program are unknown.
+//
+type ImportCache struct {
+	lock     sync.RWMutex
+	programs map[importCacheKey]importCacheEntry
+}
+
+type importCacheKey struct {
+	locationID common.LocationID
+	codeHash   [32]byte
+}
+
+type importCacheEntry struct {
+	program      *interpreter.Program
+	dependencies importDependencies
+	// sortedDependencies are the dependencies, sorted by location ID,
+	// so they are validated in a deterministic order
+	sortedDependencies []importDependency
+}
+
+// importDependencies are the locations imported by a program, directly or transitively,
+// and the hashes of their codes at the time the program was checked
+//
+type importDependencies map[common.LocationID]importDependency
+
+type importDependency struct {
+	location common.Location
+	codeHash [32]byte
+}
+
+func (d importDependencies) sorted() []importDependency {
+	result := make([]importDependency, 0, len(d))
+	for _, dependency := range d { //nolint:maprangecheck
+		result = append(result, dependency)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].location.ID() < result[j].location.ID()
+	})
+
+	return result
+}
+
+func NewImportCache() *ImportCache {
+	return &ImportCache{
+		programs: map[importCacheKey]importCacheEntry{},
+	}
+}
+
+func newImportCacheKey(location common.Location, code []byte) importCacheKey {
+	return importCacheKey{
+		locationID: location.ID(),
+		codeHash:   sha3.Sum256(code),
+	}
+}
+
+// get returns the program for the given location and code, if any,
+// and the dependencies of the program.
+//
+// The program is only returned if the current code hashes of all its dependencies,
+// as returned by the given function, are the hashes the program was checked with
+//
+func (c *ImportCache) get(
+	location common.Location,
+	code []byte,
+	codeHash func(location common.Location) ([32]byte, error),
+) (
+	*interpreter.Program,
+	importDependencies,
+	error,
+) {
+	key := newImportCacheKey(location, code)
+
+	c.lock.RLock()
+	entry, ok := c.programs[key]
+	c.lock.RUnlock()
+
+	if !ok {
+		return nil, nil, nil
+	}
+
+	for _, dependency := range entry.sortedDependencies {
+		currentCodeHash, err := codeHash(dependency.location)
+		if err != nil {
+			return nil, nil, err
+		}
+		if currentCodeHash != dependency.codeHash {
+			return nil, nil, nil
+		}
+	}
+
+	return entry.program, entry.dependencies, nil
+}
+
+// set sets the program for the given location and code,
+// and the dependencies the program was checked with
+//
+func (c *ImportCache) set(
+	location common.Location,
+	code []byte,
+	program *interpreter.Program,
+	dependencies importDependencies,
+) {
+	key := newImportCacheKey(location, code)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.programs[key] = importCacheEntry{
+		program:            program,
+		dependencies:       dependencies,
+		sortedDependencies: dependencies.sorted(),
+	}
+}
+
+// Len returns the number of cached programs
+//
+func (c *ImportCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return len(c.programs)
+}
@@ -20,14 +20,18 @@ package runtime
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/runtime/tests/utils"
 )
 
 func TestRuntimeCyclicImport(t *testing.T) {
@@ -118,7 +122,7 @@ func TestRuntimeExport(t *testing.T) {
 
 	t.Parallel()
 
-	t.Run("interpreted function (invalid)", func(t *testing.T) {
+	t.Run("interpreted function", func(t *testing.T) {
 
 		t.Parallel()
 
@@ -132,7 +136,7 @@ func TestRuntimeExport(t *testing.T) {
             }
         `)
 
-		_, err := runtime.ExecuteScript(
+		value, err := runtime.ExecuteScript(
 			Script{
 				Source: script,
 			},
@@ -141,8 +145,512 @@ func TestRuntimeExport(t *testing.T) {
 				Location:  common.ScriptLocation{},
 			},
 		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewFunction(
+				cadence.FunctionType{
+					Parameters: []cadence.Parameter{},
+					ReturnType: cadence.VoidType{},
+				}.WithID("(():Void)"),
+			),
+			value,
+		)
+	})
+}
+
+func TestRuntimeImportCache(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("direct import", func(t *testing.T) {
 
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		importedLocation := common.IdentifierLocation("imported")
+
+		imported := []byte(`
+          pub fun answer(): Int {
+              return 42
+          }
+        `)
+
+		script := []byte(`
+          import answer from imported
+
+          pub fun main(): Int {
+              return answer()
+          }
+        `)
+
+		checkCounts := map[common.LocationID]int{}
+
+		importCache := NewImportCache()
+
+		executeScript := func() {
+
+			// NOTE: each execution uses a new runtime interface,
+			// i.e. programs are not reused through GetProgram
+
+			runtimeInterface := &testRuntimeInterface{
+				getCode: func(location Location) (bytes []byte, err error) {
+					switch location {
+					case importedLocation:
+						return imported, nil
+					default:
+						return nil, fmt.Errorf("unknown import location: %s", location)
+					}
+				},
+				programChecked: func(location common.Location, duration time.Duration) {
+					checkCounts[location.ID()]++
+				},
+			}
+
+			value, err := runtime.ExecuteScript(
+				Script{
+					Source: script,
+				},
+				Context{
+					Interface:   runtimeInterface,
+					Location:    common.ScriptLocation{},
+					ImportCache: importCache,
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, cadence.NewInt(42), value)
+		}
+
+		// The imported program is only checked once
+
+		executeScript()
+		executeScript()
+
+		require.Equal(t, 1, checkCounts[importedLocation.ID()])
+		require.Equal(t, 2, checkCounts[common.ScriptLocation{}.ID()])
+		require.Equal(t, 1, importCache.Len())
+
+		// The imported program is checked again when its code changed
+
+		imported = []byte(`
+          pub fun answer(): Int {
+              return 40 + 2
+          }
+        `)
+
+		executeScript()
+		executeScript()
+
+		require.Equal(t, 2, checkCounts[importedLocation.ID()])
+		require.Equal(t, 2, importCache.Len())
+	})
+
+	t.Run("transitive import", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := newTestInterpreterRuntime()
+
+		baseLocation := common.IdentifierLocation("base")
+		importedLocation := common.IdentifierLocation("imported")
+
+		base := []byte(`
+          pub fun number(): Int {
+              return 42
+          }
+        `)
+
+		imported := []byte(`
+          import number from base
+
+          pub fun answer(): Int {
+              return number()
+          }
+        `)
+
+		script := []byte(`
+          import answer from imported
+
+          pub fun main(): Int {
+              return answer()
+          }
+        `)
+
+		checkCounts := map[common.LocationID]int{}
+
+		importCache := NewImportCache()
+
+		executeScript := func() (cadence.Value, error) {
+			runtimeInterface := &testRuntimeInterface{
+				getCode: func(location Location) (bytes []byte, err error) {
+					switch location {
+					case baseLocation:
+						return base, nil
+					case importedLocation:
+						return imported, nil
+					default:
+						return nil, fmt.Errorf("unknown import location: %s", location)
+					}
+				},
+				programChecked: func(location common.Location, duration time.Duration) {
+					checkCounts[location.ID()]++
+				},
+			}
+
+			return runtime.ExecuteScript(
+				Script{
+					Source: script,
+				},
+				Context{
+					Interface:   runtimeInterface,
+					Location:    common.ScriptLocation{},
+					ImportCache: importCache,
+				},
+			)
+		}
+
+		for i := 0; i < 2; i++ {
+			value, err := executeScript()
+			require.NoError(t, err)
+			require.Equal(t, cadence.NewInt(42), value)
+		}
+
+		require.Equal(t, 1, checkCounts[baseLocation.ID()])
+		require.Equal(t, 1, checkCounts[importedLocation.ID()])
+		require.Equal(t, 2, importCache.Len())
+
+		// Update the import of the import, so the unchanged imported program is invalid.
+		// The imported program is checked again, even though its own code did not change
+
+		base = []byte(`
+          pub fun number(): String {
+              return "42"
+          }
+        `)
+
+		_, err := executeScript()
 		require.Error(t, err)
-		require.IsType(t, Error{}, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checker.ExpectCheckerErrors(t, checkerErr, 2)
+
+		var importedProgramErr *sema.ImportedProgramError
+		require.ErrorAs(t, errs[0], &importedProgramErr)
+		require.Equal(t, importedLocation, importedProgramErr.Location)
+
+		var importedCheckerErr *sema.CheckerError
+		require.ErrorAs(t, importedProgramErr.Err, &importedCheckerErr)
+
+		errs = checker.ExpectCheckerErrors(t, importedCheckerErr, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+
+		require.Equal(t, 2, checkCounts[baseLocation.ID()])
+		require.Equal(t, 2, checkCounts[importedLocation.ID()])
 	})
 }
+
+const testIPFSLocationPrefix = "ipfs"
+
+// testIPFSLocation is a location of a custom kind,
+// registered using common.RegisterLocationKind
+//
+type testIPFSLocation string
+
+var _ common.CustomLocation = testIPFSLocation("")
+
+func (l testIPFSLocation) ID() common.LocationID {
+	return common.NewLocationID(testIPFSLocationPrefix, string(l))
+}
+
+func (l testIPFSLocation) TypeID(qualifiedIdentifier string) common.TypeID {
+	return common.NewTypeID(testIPFSLocationPrefix, string(l), qualifiedIdentifier)
+}
+
+func (l testIPFSLocation) QualifiedIdentifier(typeID common.TypeID) string {
+	pieces := strings.SplitN(string(typeID), ".", 3)
+	if len(pieces) < 3 {
+		return ""
+	}
+	return pieces[2]
+}
+
+func (l testIPFSLocation) String() string {
+	return string(l)
+}
+
+func (l testIPFSLocation) LocationKindPrefix() string {
+	return testIPFSLocationPrefix
+}
+
+func init() {
+	common.RegisterLocationKind(common.LocationKind{
+		Prefix: testIPFSLocationPrefix,
+		DecodeTypeID: func(typeID string) (common.Location, string, error) {
+			pieces := strings.SplitN(typeID, ".", 3)
+			if len(pieces) < 3 {
+				return nil, "", fmt.Errorf("invalid IPFS location type ID: %s", typeID)
+			}
+			return testIPFSLocation(pieces[1]), pieces[2], nil
+		},
+		Encode: func(location common.Location) ([]byte, error) {
+			return []byte(location.(testIPFSLocation)), nil
+		},
+		Decode: func(encoded []byte) (common.Location, error) {
+			return testIPFSLocation(encoded), nil
+		},
+	})
+}
+
+func TestRuntimeImportCustomLocation(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	const cid = "QmFoo"
+
+	imported := []byte(`
+      pub struct Foo {}
+    `)
+
+	script := []byte(`
+      import Foo from "ipfs://QmFoo"
+
+      pub fun main(): Foo {
+          return Foo()
+      }
+    `)
+
+	runtimeInterface := &testRuntimeInterface{
+		resolveLocation: func(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+			stringLocation, ok := location.(common.StringLocation)
+			if !ok || !strings.HasPrefix(string(stringLocation), "ipfs://") {
+				return nil, fmt.Errorf("unknown import location: %s", location)
+			}
+
+			return []ResolvedLocation{
+				{
+					Location:    testIPFSLocation(strings.TrimPrefix(string(stringLocation), "ipfs://")),
+					Identifiers: identifiers,
+				},
+			}, nil
+		},
+		getCode: func(location Location) ([]byte, error) {
+			if location != testIPFSLocation(cid) {
+				return nil, fmt.Errorf("unknown import location: %s", location)
+			}
+			return imported, nil
+		},
+	}
+
+	result, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	fooType := result.Type().(*cadence.StructType)
+	require.Equal(t, testIPFSLocation(cid), fooType.Location)
+	require.Equal(t, "ipfs.QmFoo.Foo", fooType.ID())
+
+	location, qualifiedIdentifier, err := common.DecodeTypeID(fooType.ID())
+	require.NoError(t, err)
+	require.Equal(t, testIPFSLocation(cid), location)
+	require.Equal(t, "Foo", qualifiedIdentifier)
+}
+
+func TestRuntimeImportAlias(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	contractA := []byte(`
+      pub contract Token {
+          pub struct Vault {
+              pub let balance: Int
+
+              init() {
+                  self.balance = 1
+              }
+          }
+      }
+    `)
+
+	contractB := []byte(`
+      pub contract Token {
+          pub let name: String
+
+          init() {
+              self.name = "B"
+          }
+      }
+    `)
+
+	script := []byte(`
+      import Token as TokenA from 0x1
+      import Token as TokenB from 0x2
+
+      pub fun main(): [AnyStruct] {
+          return [TokenA.Vault(), TokenB.name]
+      }
+    `)
+
+	addressA := common.BytesToAddress([]byte{0x1})
+	addressB := common.BytesToAddress([]byte{0x2})
+
+	accountCodes := map[common.LocationID][]byte{}
+	var signer Address
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signer}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(address Address, name string) ([]byte, error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			return accountCodes[location.ID()], nil
+		},
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	for address, contract := range map[Address][]byte{
+		addressA: contractA,
+		addressB: contractB,
+	} {
+		signer = address
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: utils.DeploymentTransaction("Token", contract),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	result, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	values := result.(cadence.Array).Values
+	require.Len(t, values, 2)
+
+	vault := values[0].(cadence.Struct)
+	require.Equal(t, "A.0000000000000001.Token.Vault", vault.StructType.ID())
+	require.Equal(t, []cadence.Value{cadence.NewInt(1)}, vault.Fields)
+
+	require.Equal(t, cadence.String("B"), values[1])
+}
+
+func TestRuntimeImportMultipleContractsMissing(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contractCodes := map[string][]byte{
+		"A": []byte(`
+          pub contract A {}
+        `),
+	}
+
+	script := []byte(`
+      import A, B from 0x1
+
+      pub fun main() {}
+    `)
+
+	runtimeInterface := &testRuntimeInterface{
+		getAccountContractCode: func(_ Address, name string) ([]byte, error) {
+			return contractCodes[name], nil
+		},
+		resolveLocation: func(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+			addressLocation := location.(common.AddressLocation)
+
+			// Only resolve the identifiers of contracts which are deployed
+
+			var resolvedLocations []ResolvedLocation
+			for _, identifier := range identifiers {
+				if _, ok := contractCodes[identifier.Identifier]; !ok {
+					continue
+				}
+
+				resolvedLocations = append(
+					resolvedLocations,
+					ResolvedLocation{
+						Location: common.AddressLocation{
+							Address: addressLocation.Address,
+							Name:    identifier.Identifier,
+						},
+						Identifiers: []Identifier{identifier},
+					},
+				)
+			}
+			return resolvedLocations, nil
+		},
+	}
+
+	_, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.Error(t, err)
+
+	var checkerErr *sema.CheckerError
+	require.ErrorAs(t, err, &checkerErr)
+
+	errs := checker.ExpectCheckerErrors(t, checkerErr, 1)
+
+	var notExportedErr *sema.NotExportedError
+	require.ErrorAs(t, errs[0], &notExportedErr)
+
+	require.Equal(t, "B", notExportedErr.Name)
+	require.Equal(t,
+		common.AddressLocation{Address: address},
+		notExportedErr.ImportLocation,
+	)
+	require.Equal(t, []string{"A"}, notExportedErr.Available)
+}
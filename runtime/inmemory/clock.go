@@ -0,0 +1,57 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package inmemory
+
+import (
+	"time"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+// Clock is a clock which only advances when it is set or advanced explicitly,
+// so that time-dependent programs can be tested deterministically.
+//
+type Clock struct {
+	now time.Time
+}
+
+var _ runtime.Clock = &Clock{}
+
+// NewClock returns a new clock which is set to the given time
+//
+func NewClock(now time.Time) *Clock {
+	return &Clock{
+		now: now,
+	}
+}
+
+func (c *Clock) Now() (time.Time, error) {
+	return c.now, nil
+}
+
+// Set sets the clock to the given time
+//
+func (c *Clock) Set(now time.Time) {
+	c.now = now
+}
+
+// Advance advances the clock by the given duration
+//
+func (c *Clock) Advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
@@ -0,0 +1,161 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+// tagLength is the length to which non-empty domain separation tags are padded
+//
+const tagLength = 32
+
+// Hash hashes the given data using the given hash algorithm.
+//
+// A non-empty tag is used as a domain separation tag:
+// it is right-padded with zeros to 32 bytes and prefixed to the data.
+//
+func (i *Interface) Hash(data []byte, tag string, hashAlgorithm runtime.HashAlgorithm) ([]byte, error) {
+	if tag != "" {
+		if len(tag) > tagLength {
+			return nil, fmt.Errorf("tag must not be longer than %d bytes", tagLength)
+		}
+
+		prefixed := make([]byte, tagLength, tagLength+len(data))
+		copy(prefixed, tag)
+		data = append(prefixed, data...)
+	}
+
+	switch hashAlgorithm {
+	case runtime.HashAlgorithmSHA2_256:
+		hash := sha256.Sum256(data)
+		return hash[:], nil
+
+	case runtime.HashAlgorithmSHA2_384:
+		hash := sha512.Sum384(data)
+		return hash[:], nil
+
+	case runtime.HashAlgorithmSHA3_256:
+		hash := sha3.Sum256(data)
+		return hash[:], nil
+
+	case runtime.HashAlgorithmSHA3_384:
+		hash := sha3.Sum384(data)
+		return hash[:], nil
+
+	default:
+		return nil, fmt.Errorf("hash algorithm %s: %w", hashAlgorithm.Name(), ErrNotSupported)
+	}
+}
+
+// VerifySignature verifies the given signature.
+//
+// Only ECDSA_P256 signatures are supported.
+// Public keys are expected to be the concatenation of the X and Y coordinates,
+// and signatures are expected to be the concatenation of R and S.
+//
+func (i *Interface) VerifySignature(
+	signature []byte,
+	tag string,
+	signedData []byte,
+	publicKey []byte,
+	signatureAlgorithm runtime.SignatureAlgorithm,
+	hashAlgorithm runtime.HashAlgorithm,
+) (bool, error) {
+
+	key, err := decodeECDSAPublicKey(publicKey, signatureAlgorithm)
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := i.Hash(signedData, tag, hashAlgorithm)
+	if err != nil {
+		return false, err
+	}
+
+	if len(signature) == 0 || len(signature)%2 != 0 {
+		return false, nil
+	}
+
+	size := len(signature) / 2
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+
+	return ecdsa.Verify(key, hash, r, s), nil
+}
+
+// ValidatePublicKey validates the given public key.
+//
+// Only ECDSA_P256 public keys are supported.
+//
+func (i *Interface) ValidatePublicKey(publicKey *runtime.PublicKey) (bool, error) {
+	_, err := decodeECDSAPublicKey(publicKey.PublicKey, publicKey.SignAlgo)
+	if err == ErrNotSupported {
+		return false, err
+	}
+	return err == nil, nil
+}
+
+func decodeECDSAPublicKey(publicKey []byte, signatureAlgorithm runtime.SignatureAlgorithm) (*ecdsa.PublicKey, error) {
+	if signatureAlgorithm != runtime.SignatureAlgorithmECDSA_P256 {
+		return nil, ErrNotSupported
+	}
+
+	curve := elliptic.P256()
+
+	size := (curve.Params().BitSize + 7) / 8
+	if len(publicKey) != 2*size {
+		return nil, fmt.Errorf("invalid public key length: expected %d, got %d", 2*size, len(publicKey))
+	}
+
+	x := new(big.Int).SetBytes(publicKey[:size])
+	y := new(big.Int).SetBytes(publicKey[size:])
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("invalid public key: point is not on the curve")
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}, nil
+}
+
+func (i *Interface) BLSVerifyPOP(_ *runtime.PublicKey, _ []byte) (bool, error) {
+	return false, ErrNotSupported
+}
+
+func (i *Interface) AggregateBLSSignatures(_ [][]byte) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (i *Interface) AggregateBLSPublicKeys(_ []*runtime.PublicKey) (*runtime.PublicKey, error) {
+	return nil, ErrNotSupported
+}
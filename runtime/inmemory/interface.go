@@ -0,0 +1,584 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package inmemory provides an in-memory implementation of runtime.Interface.
+//
+// It is intended as a reference implementation for embedders,
+// and as an environment for tests and tools which need to execute
+// scripts and transactions without an actual blockchain.
+//
+package inmemory
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/onflow/atree"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// ErrNotSupported is returned by functions
+// which are not supported by the in-memory implementation
+//
+var ErrNotSupported = errors.New("not supported by the in-memory runtime interface")
+
+type account struct {
+	keys          []*runtime.AccountKey
+	encodedKeys   [][]byte
+	contracts     map[string][]byte
+	contractNames []string
+	balance       uint64
+	capacity      uint64
+}
+
+// Interface is an in-memory implementation of runtime.Interface.
+//
+// Accounts, storage, contracts, keys, events, logs, and blocks
+// are all kept in memory and can be inspected after an execution.
+//
+// Interface is not safe for concurrent use.
+//
+type Interface struct {
+	storedValues     map[string][]byte
+	storageIndices   map[string]uint64
+	programs         map[common.LocationID]*interpreter.Program
+	accounts         map[common.Address]*account
+	addressGenerator common.SequentialAddressGenerator
+	signingAccounts  []common.Address
+	events           []cadence.Event
+	logs             []string
+	uuid             uint64
+	blocks           []runtime.Block
+	clock            *Clock
+	computationLimit uint64
+	computationUsed  uint64
+	random           *rand.Rand
+	// accountLinkingAllowed is the set of accounts which may be linked
+	accountLinkingAllowed map[common.Address]struct{}
+}
+
+var _ runtime.Interface = &Interface{}
+
+// NewInterface returns a new in-memory runtime interface
+// with no accounts and a genesis block at height 0
+//
+func NewInterface() *Interface {
+	i := &Interface{
+		storedValues:   map[string][]byte{},
+		storageIndices: map[string]uint64{},
+		programs:       map[common.LocationID]*interpreter.Program{},
+		accounts:       map[common.Address]*account{},
+		random:         rand.New(rand.NewSource(0)),
+		clock:          NewClock(time.Unix(0, 0)),
+		addressGenerator: common.SequentialAddressGenerator{
+			Scheme: common.FlowAddressScheme,
+		},
+	}
+	i.CommitBlock()
+	return i
+}
+
+// SetSigningAccounts sets the accounts which sign the next transactions
+//
+func (i *Interface) SetSigningAccounts(addresses []common.Address) {
+	i.signingAccounts = addresses
+}
+
+// SetAddressScheme sets the address scheme of the addresses of created accounts.
+// The default is common.FlowAddressScheme.
+//
+// Accounts are created at the valid addresses of the scheme in ascending order.
+// The scheme should be set before any accounts are created.
+//
+func (i *Interface) SetAddressScheme(scheme common.AddressScheme) {
+	i.addressGenerator.Scheme = scheme
+}
+
+// AllowAccountLinking allows the account at the given address to be linked,
+// i.e. capabilities targeting the account may be created using `AuthAccount.linkAccount`
+//
+func (i *Interface) AllowAccountLinking(address common.Address) {
+	if i.accountLinkingAllowed == nil {
+		i.accountLinkingAllowed = map[common.Address]struct{}{}
+	}
+	i.accountLinkingAllowed[address] = struct{}{}
+}
+
+// Events returns the events emitted so far
+//
+func (i *Interface) Events() []cadence.Event {
+	return i.events
+}
+
+// Logs returns the messages logged so far
+//
+func (i *Interface) Logs() []string {
+	return i.logs
+}
+
+// SetComputationLimit sets the computation limit reported to the runtime.
+// A limit of 0 means the computation is unlimited.
+//
+func (i *Interface) SetComputationLimit(limit uint64) {
+	i.computationLimit = limit
+}
+
+// ComputationUsed returns the computation used by the last execution
+//
+func (i *Interface) ComputationUsed() uint64 {
+	return i.computationUsed
+}
+
+// CommitBlock appends a new block to the chain.
+//
+// The clock is advanced by one second,
+// and the timestamp of the new block is the time of the clock.
+//
+func (i *Interface) CommitBlock() runtime.Block {
+	var block runtime.Block
+
+	count := len(i.blocks)
+	if count > 0 {
+		previous := i.blocks[count-1]
+		i.clock.Advance(time.Second)
+		block = runtime.Block{
+			Height: previous.Height + 1,
+			View:   previous.View + 1,
+		}
+	}
+
+	block.Timestamp = i.clock.now.UnixNano()
+
+	binary.BigEndian.PutUint64(block.Hash[:], block.Height)
+
+	i.blocks = append(i.blocks, block)
+
+	return block
+}
+
+// SetTime sets the clock to the given time.
+//
+// The time is the timestamp of the current block returned by `getCurrentBlock`,
+// and the clock is used for the timestamps of the next committed blocks.
+//
+func (i *Interface) SetTime(now time.Time) {
+	i.clock.Set(now)
+}
+
+// AdvanceTime advances the clock by the given duration, see SetTime
+//
+func (i *Interface) AdvanceTime(duration time.Duration) {
+	i.clock.Advance(duration)
+}
+
+// SetAccountBalance sets the balance of the account with the given address
+//
+func (i *Interface) SetAccountBalance(address common.Address, balance uint64) error {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+	account.balance = balance
+	return nil
+}
+
+// SetStorageCapacity sets the storage capacity of the account with the given address
+//
+func (i *Interface) SetStorageCapacity(address common.Address, capacity uint64) error {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+	account.capacity = capacity
+	return nil
+}
+
+func (i *Interface) account(address common.Address) (*account, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s does not exist", address)
+	}
+	return account, nil
+}
+
+func (i *Interface) ResolveLocation(identifiers []runtime.Identifier, location runtime.Location) ([]runtime.ResolvedLocation, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    location,
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	// if no identifiers were given, import all contracts of the account
+
+	if len(identifiers) == 0 {
+		names, err := i.GetAccountContractNames(addressLocation.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			identifiers = append(
+				identifiers,
+				runtime.Identifier{
+					Identifier: name,
+				},
+			)
+		}
+	}
+
+	resolvedLocations := make([]runtime.ResolvedLocation, len(identifiers))
+	for index, identifier := range identifiers {
+		resolvedLocations[index] = runtime.ResolvedLocation{
+			Location: common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			},
+			Identifiers: []runtime.Identifier{identifier},
+		}
+	}
+
+	return resolvedLocations, nil
+}
+
+func (i *Interface) GetCode(location runtime.Location) ([]byte, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot get code of location %s", location)
+	}
+
+	return i.GetAccountContractCode(addressLocation.Address, addressLocation.Name)
+}
+
+func (i *Interface) GetProgram(location runtime.Location) (*interpreter.Program, error) {
+	return i.programs[location.ID()], nil
+}
+
+func (i *Interface) SetProgram(location runtime.Location, program *interpreter.Program) error {
+	// only contract programs are cached,
+	// the programs of scripts and transactions are not reused
+
+	if _, ok := location.(common.AddressLocation); ok {
+		i.programs[location.ID()] = program
+	}
+	return nil
+}
+
+func storageKey(owner, key []byte) string {
+	return strings.Join([]string{string(owner), string(key)}, "|")
+}
+
+func (i *Interface) GetValue(owner, key []byte) (value []byte, err error) {
+	return i.storedValues[storageKey(owner, key)], nil
+}
+
+func (i *Interface) SetValue(owner, key, value []byte) (err error) {
+	storageKey := storageKey(owner, key)
+	if len(value) == 0 {
+		delete(i.storedValues, storageKey)
+	} else {
+		// copy the value, as the caller may reuse the slice,
+		// and snapshots share stored values
+		i.storedValues[storageKey] = append([]byte(nil), value...)
+	}
+	return nil
+}
+
+func (i *Interface) ValueExists(owner, key []byte) (exists bool, err error) {
+	return len(i.storedValues[storageKey(owner, key)]) > 0, nil
+}
+
+func (i *Interface) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := i.storageIndices[string(owner)] + 1
+	i.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+func (i *Interface) CreateAccount(_ runtime.Address) (address runtime.Address, err error) {
+	address, err = i.addressGenerator.NextAddress()
+	if err != nil {
+		return address, err
+	}
+
+	i.accounts[address] = &account{
+		contracts: map[string][]byte{},
+	}
+
+	return address, nil
+}
+
+func (i *Interface) AddEncodedAccountKey(address runtime.Address, publicKey []byte) error {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+	account.encodedKeys = append(account.encodedKeys, publicKey)
+	return nil
+}
+
+func (i *Interface) RevokeEncodedAccountKey(address runtime.Address, index int) (publicKey []byte, err error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(account.encodedKeys) {
+		return nil, fmt.Errorf("invalid key index %d", index)
+	}
+
+	publicKey = account.encodedKeys[index]
+	account.encodedKeys = append(account.encodedKeys[:index:index], account.encodedKeys[index+1:]...)
+
+	return publicKey, nil
+}
+
+func (i *Interface) AddAccountKey(
+	address runtime.Address,
+	publicKey *runtime.PublicKey,
+	hashAlgo runtime.HashAlgorithm,
+	weight int,
+) (*runtime.AccountKey, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &runtime.AccountKey{
+		KeyIndex:  len(account.keys),
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+
+	account.keys = append(account.keys, key)
+
+	return key, nil
+}
+
+func (i *Interface) GetAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing key is not an error, the runtime returns nil for it
+
+	if index < 0 || index >= len(account.keys) {
+		return nil, nil
+	}
+
+	key := *account.keys[index]
+	return &key, nil
+}
+
+func (i *Interface) RevokeAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(account.keys) {
+		return nil, nil
+	}
+
+	account.keys[index].IsRevoked = true
+
+	key := *account.keys[index]
+	return &key, nil
+}
+
+func (i *Interface) UpdateAccountContractCode(address runtime.Address, name string, code []byte) (err error) {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := account.contracts[name]; !ok {
+		account.contractNames = append(account.contractNames, name)
+	}
+
+	account.contracts[name] = code
+
+	i.invalidateProgram(address, name)
+
+	return nil
+}
+
+func (i *Interface) GetAccountContractCode(address runtime.Address, name string) (code []byte, err error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, nil
+	}
+	return account.contracts[name], nil
+}
+
+func (i *Interface) RemoveAccountContractCode(address runtime.Address, name string) (err error) {
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+
+	delete(account.contracts, name)
+
+	for index, contractName := range account.contractNames {
+		if contractName == name {
+			account.contractNames = append(account.contractNames[:index:index], account.contractNames[index+1:]...)
+			break
+		}
+	}
+
+	i.invalidateProgram(address, name)
+
+	return nil
+}
+
+func (i *Interface) invalidateProgram(address runtime.Address, name string) {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+	delete(i.programs, location.ID())
+}
+
+func (i *Interface) GetSigningAccounts() ([]runtime.Address, error) {
+	return i.signingAccounts, nil
+}
+
+func (i *Interface) ProgramLog(message string) error {
+	i.logs = append(i.logs, message)
+	return nil
+}
+
+func (i *Interface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *Interface) GenerateUUID() (uint64, error) {
+	uuid := i.uuid
+	i.uuid++
+	return uuid, nil
+}
+
+func (i *Interface) GetComputationLimit() uint64 {
+	return i.computationLimit
+}
+
+func (i *Interface) SetComputationUsed(used uint64) error {
+	i.computationUsed = used
+	return nil
+}
+
+func (i *Interface) DecodeArgument(argument []byte, _ cadence.Type) (cadence.Value, error) {
+	return json.Decode(argument)
+}
+
+func (i *Interface) GetCurrentBlockHeight() (uint64, error) {
+	return i.blocks[len(i.blocks)-1].Height, nil
+}
+
+func (i *Interface) GetBlockAtHeight(height uint64) (block runtime.Block, exists bool, err error) {
+	if height >= uint64(len(i.blocks)) {
+		return runtime.Block{}, false, nil
+	}
+	return i.blocks[height], true, nil
+}
+
+func (i *Interface) Clock() runtime.Clock {
+	return i.clock
+}
+
+func (i *Interface) UnsafeRandom() (uint64, error) {
+	return i.random.Uint64(), nil
+}
+
+func (i *Interface) GetAccountBalance(address common.Address) (value uint64, err error) {
+	account, err := i.account(address)
+	if err != nil {
+		return 0, err
+	}
+	return account.balance, nil
+}
+
+func (i *Interface) GetAccountAvailableBalance(address common.Address) (value uint64, err error) {
+	return i.GetAccountBalance(address)
+}
+
+func (i *Interface) GetStorageUsed(address runtime.Address) (value uint64, err error) {
+	prefix := string(address[:]) + "|"
+	for key, storedValue := range i.storedValues {
+		if strings.HasPrefix(key, prefix) {
+			value += uint64(len(key) - len(prefix) + len(storedValue))
+		}
+	}
+	return value, nil
+}
+
+func (i *Interface) GetStorageCapacity(address runtime.Address) (value uint64, err error) {
+	account, err := i.account(address)
+	if err != nil {
+		return 0, err
+	}
+	return account.capacity, nil
+}
+
+func (i *Interface) ImplementationDebugLog(_ string) error {
+	return nil
+}
+
+func (i *Interface) GetAccountContractNames(address runtime.Address) ([]string, error) {
+	account, ok := i.accounts[address]
+	if !ok {
+		return nil, nil
+	}
+
+	names := make([]string, len(account.contractNames))
+	copy(names, account.contractNames)
+	return names, nil
+}
+
+func (i *Interface) RecordTrace(_ string, _ common.Location, _ time.Duration, _ []opentracing.LogRecord) {
+	// NO-OP
+}
+
+func (i *Interface) ResourceOwnerChanged(
+	_ *interpreter.CompositeValue,
+	_ common.Address,
+	_ common.Address,
+) {
+	// NO-OP
+}
+
+func (i *Interface) IsAccountLinkingAllowed(address runtime.Address) (bool, error) {
+	_, ok := i.accountLinkingAllowed[address]
+	return ok, nil
+}
@@ -0,0 +1,381 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func executeTransaction(
+	t *testing.T,
+	rt runtime.Runtime,
+	runtimeInterface *Interface,
+	code string,
+	signers ...common.Address,
+) {
+	runtimeInterface.SetSigningAccounts(signers)
+
+	err := rt.ExecuteTransaction(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(t, err)
+}
+
+func executeScript(
+	t *testing.T,
+	rt runtime.Runtime,
+	runtimeInterface *Interface,
+	code string,
+) cadence.Value {
+	result, err := rt.ExecuteScript(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	return result
+}
+
+func TestInterfaceStorageAndContracts(t *testing.T) {
+
+	t.Parallel()
+
+	rt := runtime.NewInterpreterRuntime()
+	runtimeInterface := NewInterface()
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	const contract = `
+      pub contract C {
+
+          pub event Created(id: UInt64)
+
+          pub resource R {}
+
+          pub fun createR(): @R {
+              let r <- create R()
+              emit Created(id: r.uuid)
+              return <-r
+          }
+      }
+    `
+
+	executeTransaction(t, rt, runtimeInterface,
+		fmt.Sprintf(
+			`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.contracts.add(name: "C", code: "%s".decodeHex())
+                  }
+              }
+            `,
+			hex.EncodeToString([]byte(contract)),
+		),
+		address,
+	)
+
+	names, err := runtimeInterface.GetAccountContractNames(address)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C"}, names)
+
+	executeTransaction(t, rt, runtimeInterface,
+		fmt.Sprintf(
+			`
+              import C from %s
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.save(<-C.createR(), to: /storage/r)
+                      log("saved")
+                  }
+              }
+            `,
+			address.ShortHexWithPrefix(),
+		),
+		address,
+	)
+
+	events := runtimeInterface.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "flow.AccountContractAdded", events[0].EventType.ID())
+	assert.Equal(t, "A.0000000000000001.C.Created", events[1].EventType.ID())
+
+	assert.Equal(t, []string{`"saved"`}, runtimeInterface.Logs())
+
+	used, err := runtimeInterface.GetStorageUsed(address)
+	require.NoError(t, err)
+	assert.Greater(t, used, uint64(0))
+
+	result := executeScript(t, rt, runtimeInterface,
+		fmt.Sprintf(
+			`
+              import C from %s
+
+              pub fun main(): Bool {
+                  return getAuthAccount(%[1]s).type(at: /storage/r) == Type<@C.R>()
+              }
+            `,
+			address.ShortHexWithPrefix(),
+		),
+	)
+	assert.Equal(t, cadence.NewBool(true), result)
+}
+
+func TestInterfaceBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	rt := runtime.NewInterpreterRuntime()
+	runtimeInterface := NewInterface()
+
+	const script = `
+      pub fun main(): [UInt64] {
+          let block = getCurrentBlock()
+          return [block.height, UInt64(block.timestamp)]
+      }
+    `
+
+	result := executeScript(t, rt, runtimeInterface, script)
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewUInt64(0),
+			cadence.NewUInt64(0),
+		}),
+		result,
+	)
+
+	runtimeInterface.CommitBlock()
+
+	result = executeScript(t, rt, runtimeInterface, script)
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewUInt64(1),
+			cadence.NewUInt64(1),
+		}),
+		result,
+	)
+
+	_, exists, err := runtimeInterface.GetBlockAtHeight(2)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestInterfaceClock(t *testing.T) {
+
+	t.Parallel()
+
+	rt := runtime.NewInterpreterRuntime()
+	runtimeInterface := NewInterface()
+
+	const script = `
+      pub fun main(): UInt64 {
+          return UInt64(getCurrentBlock().timestamp)
+      }
+    `
+
+	runtimeInterface.SetTime(time.Unix(1000, 0))
+
+	result := executeScript(t, rt, runtimeInterface, script)
+	assert.Equal(t, cadence.NewUInt64(1000), result)
+
+	// The timestamp of the current block follows the clock
+
+	runtimeInterface.AdvanceTime(time.Minute)
+
+	result = executeScript(t, rt, runtimeInterface, script)
+	assert.Equal(t, cadence.NewUInt64(1060), result)
+
+	// Committed blocks are one second after the current time
+
+	block := runtimeInterface.CommitBlock()
+	assert.Equal(t, time.Unix(1061, 0).UnixNano(), block.Timestamp)
+
+	result = executeScript(t, rt, runtimeInterface, script)
+	assert.Equal(t, cadence.NewUInt64(1061), result)
+
+	now, err := runtimeInterface.Clock().Now()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1061, 0), now)
+}
+
+func TestInterfaceAddressScheme(t *testing.T) {
+
+	t.Parallel()
+
+	runtimeInterface := NewInterface()
+
+	runtimeInterface.SetAddressScheme(
+		common.HexAddressScheme{
+			Prefix:        "0x",
+			AddressLength: 2,
+			Checksum: func(address common.Address) bool {
+				return address[common.AddressLength-1]%2 == 0
+			},
+		},
+	)
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToAddress([]byte{0x2}), address)
+
+	snapshot := runtimeInterface.Snapshot()
+
+	address, err = runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToAddress([]byte{0x4}), address)
+
+	// Restoring a snapshot also restores the address generator
+
+	runtimeInterface.Restore(snapshot)
+
+	address, err = runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToAddress([]byte{0x4}), address)
+}
+
+func TestInterfaceAccountKeys(t *testing.T) {
+
+	t.Parallel()
+
+	runtimeInterface := NewInterface()
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	publicKey := &runtime.PublicKey{
+		PublicKey: []byte{1, 2, 3},
+		SignAlgo:  runtime.SignatureAlgorithmECDSA_P256,
+	}
+
+	key, err := runtimeInterface.AddAccountKey(address, publicKey, runtime.HashAlgorithmSHA3_256, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 0, key.KeyIndex)
+
+	key, err = runtimeInterface.RevokeAccountKey(address, 0)
+	require.NoError(t, err)
+	assert.True(t, key.IsRevoked)
+
+	key, err = runtimeInterface.GetAccountKey(address, 0)
+	require.NoError(t, err)
+	assert.True(t, key.IsRevoked)
+
+	key, err = runtimeInterface.GetAccountKey(address, 1)
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	_, err = runtimeInterface.AddAccountKey(common.Address{0x42}, publicKey, runtime.HashAlgorithmSHA3_256, 1000)
+	require.Error(t, err)
+}
+
+func TestInterfaceVerifySignature(t *testing.T) {
+
+	t.Parallel()
+
+	runtimeInterface := NewInterface()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	publicKey := make([]byte, 64)
+	privateKey.X.FillBytes(publicKey[:32])
+	privateKey.Y.FillBytes(publicKey[32:])
+
+	valid, err := runtimeInterface.ValidatePublicKey(&runtime.PublicKey{
+		PublicKey: publicKey,
+		SignAlgo:  runtime.SignatureAlgorithmECDSA_P256,
+	})
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	data := []byte("hello")
+	const tag = "FLOW-V0.0-user"
+
+	hash, err := runtimeInterface.Hash(data, tag, runtime.HashAlgorithmSHA2_256)
+	require.NoError(t, err)
+
+	paddedTag := make([]byte, 32)
+	copy(paddedTag, tag)
+	expectedHash := sha256.Sum256(append(paddedTag, data...))
+	assert.Equal(t, expectedHash[:], hash)
+
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash)
+	require.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	valid, err = runtimeInterface.VerifySignature(
+		signature,
+		tag,
+		data,
+		publicKey,
+		runtime.SignatureAlgorithmECDSA_P256,
+		runtime.HashAlgorithmSHA2_256,
+	)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = runtimeInterface.VerifySignature(
+		signature,
+		tag,
+		[]byte("other"),
+		publicKey,
+		runtime.SignatureAlgorithmECDSA_P256,
+		runtime.HashAlgorithmSHA2_256,
+	)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = runtimeInterface.VerifySignature(
+		signature,
+		tag,
+		data,
+		publicKey,
+		runtime.SignatureAlgorithmBLS_BLS12_381,
+		runtime.HashAlgorithmSHA2_256,
+	)
+	require.ErrorIs(t, err, ErrNotSupported)
+}
@@ -0,0 +1,136 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// Snapshot is a copy of the state of an Interface,
+// which can be restored using Interface.Restore.
+//
+// The state of the pseudo-random number generator used for UnsafeRandom
+// is not part of the snapshot.
+//
+type Snapshot struct {
+	storedValues     map[string][]byte
+	storageIndices   map[string]uint64
+	programs         map[common.LocationID]*interpreter.Program
+	accounts         map[common.Address]*account
+	addressGenerator common.SequentialAddressGenerator
+	signingAccounts  []common.Address
+	events           []cadence.Event
+	logs             []string
+	uuid             uint64
+	blocks           []runtime.Block
+}
+
+// Snapshot returns a copy of the current state.
+//
+// Stored values are never modified in place, so only the maps are copied,
+// which makes taking a snapshot cheap compared to executing a transaction.
+//
+func (i *Interface) Snapshot() *Snapshot {
+	return &Snapshot{
+		storedValues:     copyStoredValues(i.storedValues),
+		storageIndices:   copyStorageIndices(i.storageIndices),
+		programs:         copyPrograms(i.programs),
+		accounts:         copyAccounts(i.accounts),
+		addressGenerator: i.addressGenerator,
+		signingAccounts:  append([]common.Address(nil), i.signingAccounts...),
+		events:           append([]cadence.Event(nil), i.events...),
+		logs:             append([]string(nil), i.logs...),
+		uuid:             i.uuid,
+		blocks:           append([]runtime.Block(nil), i.blocks...),
+	}
+}
+
+// Restore resets the state to the given snapshot.
+//
+// The snapshot is not consumed, it can be restored multiple times.
+//
+func (i *Interface) Restore(snapshot *Snapshot) {
+	i.storedValues = copyStoredValues(snapshot.storedValues)
+	i.storageIndices = copyStorageIndices(snapshot.storageIndices)
+	i.programs = copyPrograms(snapshot.programs)
+	i.accounts = copyAccounts(snapshot.accounts)
+	i.addressGenerator = snapshot.addressGenerator
+	i.signingAccounts = append([]common.Address(nil), snapshot.signingAccounts...)
+	i.events = append([]cadence.Event(nil), snapshot.events...)
+	i.logs = append([]string(nil), snapshot.logs...)
+	i.uuid = snapshot.uuid
+	i.blocks = append([]runtime.Block(nil), snapshot.blocks...)
+}
+
+func copyStoredValues(storedValues map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(storedValues))
+	for key, value := range storedValues {
+		result[key] = value
+	}
+	return result
+}
+
+func copyStorageIndices(storageIndices map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(storageIndices))
+	for owner, index := range storageIndices {
+		result[owner] = index
+	}
+	return result
+}
+
+func copyPrograms(programs map[common.LocationID]*interpreter.Program) map[common.LocationID]*interpreter.Program {
+	result := make(map[common.LocationID]*interpreter.Program, len(programs))
+	for locationID, program := range programs {
+		result[locationID] = program
+	}
+	return result
+}
+
+func copyAccounts(accounts map[common.Address]*account) map[common.Address]*account {
+	result := make(map[common.Address]*account, len(accounts))
+	for address, account := range accounts {
+		result[address] = account.copy()
+	}
+	return result
+}
+
+func (a *account) copy() *account {
+	keys := make([]*runtime.AccountKey, len(a.keys))
+	for index, key := range a.keys {
+		keyCopy := *key
+		keys[index] = &keyCopy
+	}
+
+	contracts := make(map[string][]byte, len(a.contracts))
+	for name, code := range a.contracts {
+		contracts[name] = code
+	}
+
+	return &account{
+		keys:          keys,
+		encodedKeys:   append([][]byte(nil), a.encodedKeys...),
+		contracts:     contracts,
+		contractNames: append([]string(nil), a.contractNames...),
+		balance:       a.balance,
+		capacity:      a.capacity,
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestInterfaceSnapshot(t *testing.T) {
+
+	t.Parallel()
+
+	rt := runtime.NewInterpreterRuntime()
+	runtimeInterface := NewInterface()
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	const setup = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(1, to: /storage/counter)
+          }
+      }
+    `
+
+	const increment = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              let counter = signer.load<Int>(from: /storage/counter)!
+              signer.save(counter + 1, to: /storage/counter)
+              log(counter + 1)
+          }
+      }
+    `
+
+	const script = `
+      pub fun main(): Int {
+          return getAuthAccount(0x1).copy<Int>(from: /storage/counter)!
+      }
+    `
+
+	executeTransaction(t, rt, runtimeInterface, setup, address)
+
+	snapshot := runtimeInterface.Snapshot()
+
+	executeTransaction(t, rt, runtimeInterface, increment, address)
+	executeTransaction(t, rt, runtimeInterface, increment, address)
+
+	_, err = runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	runtimeInterface.CommitBlock()
+
+	assert.Equal(t, cadence.NewInt(3), executeScript(t, rt, runtimeInterface, script))
+	assert.Equal(t, []string{"2", "3"}, runtimeInterface.Logs())
+
+	runtimeInterface.Restore(snapshot)
+
+	assert.Equal(t, cadence.NewInt(1), executeScript(t, rt, runtimeInterface, script))
+	assert.Empty(t, runtimeInterface.Logs())
+
+	height, err := runtimeInterface.GetCurrentBlockHeight()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), height)
+
+	// the account created after the snapshot is created again
+
+	newAddress, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, common.Address{0, 0, 0, 0, 0, 0, 0, 2}, newAddress)
+
+	// the snapshot can be restored again,
+	// and is unaffected by changes after the first restore
+
+	executeTransaction(t, rt, runtimeInterface, increment, address)
+
+	runtimeInterface.Restore(snapshot)
+
+	assert.Equal(t, cadence.NewInt(1), executeScript(t, rt, runtimeInterface, script))
+}
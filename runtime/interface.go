@@ -32,6 +32,8 @@ import (
 
 type Interface interface {
 	// ResolveLocation resolves an import location.
+	// Imported identifiers which are not part of any resolved location
+	// are reported as missing declarations.
 	ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error)
 	// GetCode returns the code at a given location
 	GetCode(location Location) ([]byte, error)
@@ -94,6 +96,10 @@ type Interface interface {
 	GetCurrentBlockHeight() (uint64, error)
 	// GetBlockAtHeight returns the block at the given height.
 	GetBlockAtHeight(height uint64) (block Block, exists bool, err error)
+	// Clock returns the clock which provides the current time,
+	// e.g. the timestamp of the block returned by `getCurrentBlock`.
+	// If the clock is nil, the timestamp of the current block is used.
+	Clock() Clock
 	// UnsafeRandom returns a random uint64, where the process of random number derivation is not cryptographically
 	// secure.
 	UnsafeRandom() (uint64, error)
@@ -133,6 +139,9 @@ type Interface interface {
 	AggregateBLSPublicKeys(keys []*PublicKey) (*PublicKey, error)
 	// ResourceOwnerChanged gets called when a resource's owner changed (if enabled)
 	ResourceOwnerChanged(resource *interpreter.CompositeValue, oldOwner common.Address, newOwner common.Address)
+	// IsAccountLinkingAllowed returns true if the account at the given address may be linked,
+	// i.e. if capabilities targeting the account may be created using `AuthAccount.linkAccount`
+	IsAccountLinkingAllowed(address Address) (bool, error)
 }
 
 type Metrics interface {
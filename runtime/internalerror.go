@@ -0,0 +1,119 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// InternalErrorReport describes an internal error which occurred during an execution,
+// see Context.OnInternalError.
+//
+// The report does not contain any values or code of the execution,
+// so it can be collected from node operators.
+//
+type InternalErrorReport struct {
+	// Err is the internal error.
+	// If the execution panicked with a value that is not an error,
+	// the value is wrapped in an errors.UnexpectedError
+	Err error
+	// Location is the location of the executed program
+	Location Location
+	// CallStack is the call stack of the execution at the time of the error,
+	// starting with the outermost frame
+	CallStack []interpreter.CallStackFrame
+	// Fingerprint identifies the programs loaded by the execution, without revealing them.
+	// It is the hex-encoded SHA-256 hash of the location IDs and code hashes of the programs
+	Fingerprint string
+}
+
+// reportInternalError reports the given error to the internal error handler, if any,
+// if the error is an internal error
+//
+func (c Context) reportInternalError(err error) {
+	if c.OnInternalError == nil || !errors.IsInternalError(err) {
+		return
+	}
+
+	var callStack []interpreter.CallStackFrame
+	if c.callStack != nil {
+		callStack = c.callStack.Frames()
+	}
+
+	c.OnInternalError(InternalErrorReport{
+		Err:         err,
+		Location:    c.Location,
+		CallStack:   callStack,
+		Fingerprint: c.fingerprint(),
+	})
+}
+
+// reportPanic reports a panic of the execution to the internal error handler, if any,
+// and continues panicking.
+//
+// It must be deferred by the entry points of the runtime
+//
+func (c Context) reportPanic() {
+	if c.OnInternalError == nil {
+		return
+	}
+
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err, ok := r.(error)
+	if !ok || !errors.IsInternalError(err) {
+		// Panics are never expected, independent of the panicked value
+		err = errors.NewUnexpectedError("%s", r)
+	}
+
+	c.reportInternalError(err)
+
+	panic(r)
+}
+
+// fingerprint returns the fingerprint of the programs loaded by the execution,
+// see InternalErrorReport.Fingerprint
+//
+func (c Context) fingerprint() string {
+	locationIDs := make([]string, 0, len(c.codes))
+	for locationID := range c.codes { //nolint:maprangecheck
+		locationIDs = append(locationIDs, string(locationID))
+	}
+	sort.Strings(locationIDs)
+
+	hasher := sha256.New()
+	for _, locationID := range locationIDs {
+		codeHash := sha256.Sum256([]byte(c.codes[common.LocationID(locationID)]))
+
+		hasher.Write([]byte(locationID))
+		hasher.Write([]byte{0})
+		hasher.Write(codeHash[:])
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
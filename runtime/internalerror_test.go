@@ -0,0 +1,196 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestRuntimeInternalErrorReporting(t *testing.T) {
+
+	t.Parallel()
+
+	newFailingHostFunction := func(fail func()) HostFunction {
+		return HostFunction{
+			Name: "fail",
+			Type: &sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.VoidType),
+			},
+			Function: func(_ interpreter.Invocation) interpreter.Value {
+				fail()
+				return interpreter.VoidValue{}
+			},
+		}
+	}
+
+	execute := func(
+		script string,
+		hostFunction HostFunction,
+		reports *[]InternalErrorReport,
+	) error {
+		runtime := newTestInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{}
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(script),
+			},
+			Context{
+				Interface:     runtimeInterface,
+				Location:      common.ScriptLocation{0x1},
+				HostFunctions: []HostFunction{hostFunction},
+				OnInternalError: func(report InternalErrorReport) {
+					*reports = append(*reports, report)
+				},
+			},
+		)
+		return err
+	}
+
+	const script = `
+      pub fun test() {
+          fail()
+      }
+
+      pub fun main() {
+          test()
+      }
+    `
+
+	t.Run("internal error", func(t *testing.T) {
+
+		t.Parallel()
+
+		var reports []InternalErrorReport
+
+		err := execute(
+			script,
+			newFailingHostFunction(func() {
+				panic("unexpected")
+			}),
+			&reports,
+		)
+		require.Error(t, err)
+		require.True(t, errors.IsInternalError(err))
+
+		require.Len(t, reports, 1)
+		report := reports[0]
+
+		var unexpectedErr errors.UnexpectedError
+		require.ErrorAs(t, report.Err, &unexpectedErr)
+		assert.Equal(t, "unexpected", unexpectedErr.Message)
+
+		assert.Equal(t, common.ScriptLocation{0x1}, report.Location)
+
+		assert.Equal(t,
+			[]interpreter.CallStackFrame{
+				{
+					Location: common.ScriptLocation{0x1},
+					Line:     7,
+				},
+				{
+					Location: common.ScriptLocation{0x1},
+					Line:     3,
+				},
+			},
+			report.CallStack,
+		)
+
+		assert.Len(t, report.Fingerprint, 64)
+	})
+
+	t.Run("user error", func(t *testing.T) {
+
+		t.Parallel()
+
+		var reports []InternalErrorReport
+
+		err := execute(
+			script,
+			newFailingHostFunction(func() {
+				panic(interpreter.OverflowError{})
+			}),
+			&reports,
+		)
+		require.Error(t, err)
+		require.True(t, errors.IsUserError(err))
+
+		require.Empty(t, reports)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+
+		t.Parallel()
+
+		var reports []InternalErrorReport
+
+		require.Panics(t, func() {
+			_ = execute(
+				script,
+				newFailingHostFunction(func() {
+					var values []int
+					_ = values[1]
+				}),
+				&reports,
+			)
+		})
+
+		require.Len(t, reports, 1)
+		report := reports[0]
+
+		require.True(t, errors.IsInternalError(report.Err))
+		assert.Len(t, report.CallStack, 2)
+	})
+
+	t.Run("fingerprint", func(t *testing.T) {
+
+		t.Parallel()
+
+		fingerprint := func(script string) string {
+			var reports []InternalErrorReport
+
+			_ = execute(
+				script,
+				newFailingHostFunction(func() {
+					panic("unexpected")
+				}),
+				&reports,
+			)
+
+			require.Len(t, reports, 1)
+			return reports[0].Fingerprint
+		}
+
+		fingerprint1 := fingerprint(script)
+		fingerprint2 := fingerprint(script)
+		fingerprint3 := fingerprint(`pub fun main() { fail() }`)
+
+		assert.Equal(t, fingerprint1, fingerprint2)
+		assert.NotEqual(t, fingerprint1, fingerprint3)
+	})
+}
@@ -89,9 +89,15 @@ func NewAuthAccountValue(
 		sema.AuthAccountStorageCapacityField: func(_ *Interpreter, _ func() LocationRange) Value {
 			return storageCapacityGet()
 		},
+		sema.AuthAccountStorageUsedByPathField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountStorageUsedByPathFunction(address)
+		},
 		sema.AuthAccountTypeField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountTypeFunction(address)
 		},
+		sema.AuthAccountStoragePathsField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountStoragePathsFunction(address)
+		},
 		sema.AuthAccountLoadField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountLoadFunction(address)
 		},
@@ -104,12 +110,24 @@ func NewAuthAccountValue(
 		sema.AuthAccountBorrowField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountBorrowFunction(address)
 		},
+		sema.AuthAccountSaveTransientField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountSaveTransientFunction(address)
+		},
+		sema.AuthAccountLoadTransientField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountLoadTransientFunction(address)
+		},
+		sema.AuthAccountCopyTransientField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountCopyTransientFunction(address)
+		},
 		sema.AuthAccountLinkField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountLinkFunction(address)
 		},
 		sema.AuthAccountUnlinkField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountUnlinkFunction(address)
 		},
+		sema.AuthAccountLinkAccountField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountLinkAccountFunction(address)
+		},
 		sema.AuthAccountGetLinkTargetField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.accountGetLinkTargetFunction(address)
 		},
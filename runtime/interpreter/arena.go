@@ -0,0 +1,241 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"math/big"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// valueArenaChunkSize is the number of big integers allocated at once by a value arena
+//
+const valueArenaChunkSize = 64
+
+// valueArena is a region allocator for the transient values of arithmetic expressions,
+// see WithValueArenaEnabled.
+//
+// The intermediate results of nested arithmetic expressions, e.g. the result of `a * b` in `a * b + c`,
+// are only used as operands of the enclosing operation, and are discarded afterwards.
+// Their big integers are allocated in chunks, and are released to the arena once they were consumed,
+// so they can be reused by later intermediate results, including their backing arrays.
+//
+// The result of the outermost arithmetic expression may escape, e.g. to a variable or to storage,
+// so it is never allocated in the arena.
+//
+// A value arena is not safe for concurrent use. It is shared by the interpreters of an execution
+//
+type valueArena struct {
+	chunk []big.Int
+	free  []*big.Int
+	// allocated is the number of big integers allocated from chunks
+	allocated int
+	// reused is the number of released big integers which were reused
+	reused int
+}
+
+func (a *valueArena) newBigInt() *big.Int {
+	if count := len(a.free); count > 0 {
+		result := a.free[count-1]
+		a.free = a.free[:count-1]
+		a.reused++
+		return result
+	}
+
+	if len(a.chunk) == 0 {
+		a.chunk = make([]big.Int, valueArenaChunkSize)
+	}
+
+	result := &a.chunk[0]
+	a.chunk = a.chunk[1:]
+	a.allocated++
+	return result
+}
+
+func (a *valueArena) release(bigInt *big.Int) {
+	a.free = append(a.free, bigInt)
+}
+
+// WithValueArenaEnabled returns an interpreter option which sets
+// the value arena option.
+//
+// When enabled, the intermediate results of nested arithmetic expressions of type `Int` and `UInt`
+// are allocated in a value arena, which reduces the number of allocations in arithmetic-heavy code.
+// The results of evaluation are the same with and without the arena.
+//
+func WithValueArenaEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetValueArenaEnabled(enabled)
+		return nil
+	}
+}
+
+// SetValueArenaEnabled sets the value arena option.
+//
+func (interpreter *Interpreter) SetValueArenaEnabled(enabled bool) {
+	if !enabled {
+		interpreter.valueArena = nil
+	} else if interpreter.valueArena == nil {
+		interpreter.valueArena = &valueArena{}
+	}
+}
+
+// withValueArena returns an interpreter option which sets the given value arena,
+// so the value arena is shared with sub-interpreters
+//
+func withValueArena(arena *valueArena) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.valueArena = arena
+		return nil
+	}
+}
+
+// isArenaOperation returns true if the results of the given operation
+// may be allocated in the value arena
+//
+func isArenaOperation(operation ast.Operation) bool {
+	switch operation {
+	case ast.OperationPlus,
+		ast.OperationMinus,
+		ast.OperationMul:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// evalArenaArithmetic evaluates the given arithmetic expression using the value arena.
+//
+// If the result is temporary, i.e. it is only used as an operand of an enclosing arena operation,
+// it is allocated in the arena, and the result is reported as allocated in the arena.
+// The caller must then release it after it was consumed.
+//
+// Only operations which cannot fail, and which always produce a new big integer, are evaluated in the arena.
+// All other operations are evaluated like without the arena
+//
+func (interpreter *Interpreter) evalArenaArithmetic(
+	expression *ast.BinaryExpression,
+	temporary bool,
+) (
+	result Value,
+	inArena bool,
+) {
+	arena := interpreter.valueArena
+
+	left, leftInArena := interpreter.evalArenaOperand(expression.Left)
+	right, rightInArena := interpreter.evalArenaOperand(expression.Right)
+
+	leftNumber := left.(NumberValue)
+	rightNumber := right.(NumberValue)
+
+	interpreter.reportBigIntOperation(expression.Operation, leftNumber)
+
+	newBigInt := func() *big.Int {
+		if temporary {
+			inArena = true
+			return arena.newBigInt()
+		}
+		return new(big.Int)
+	}
+
+	switch leftNumber := leftNumber.(type) {
+	case IntValue:
+		if rightNumber, ok := rightNumber.(IntValue); ok {
+			res := newBigInt()
+			switch expression.Operation {
+			case ast.OperationPlus:
+				res.Add(leftNumber.BigInt, rightNumber.BigInt)
+			case ast.OperationMinus:
+				res.Sub(leftNumber.BigInt, rightNumber.BigInt)
+			case ast.OperationMul:
+				res.Mul(leftNumber.BigInt, rightNumber.BigInt)
+			}
+			result = IntValue{res}
+		}
+
+	case UIntValue:
+		// Subtraction may underflow, so it is evaluated like without the arena
+		if rightNumber, ok := rightNumber.(UIntValue); ok &&
+			expression.Operation != ast.OperationMinus {
+
+			res := newBigInt()
+			switch expression.Operation {
+			case ast.OperationPlus:
+				res.Add(leftNumber.BigInt, rightNumber.BigInt)
+			case ast.OperationMul:
+				res.Mul(leftNumber.BigInt, rightNumber.BigInt)
+			}
+			result = UIntValue{res}
+		}
+	}
+
+	if result == nil {
+		switch expression.Operation {
+		case ast.OperationPlus:
+			result = leftNumber.Plus(rightNumber)
+		case ast.OperationMinus:
+			result = leftNumber.Minus(rightNumber)
+		case ast.OperationMul:
+			result = leftNumber.Mul(rightNumber)
+		}
+	}
+
+	// The operands were consumed, so they can be released.
+	// NOTE: only after the result was computed, as the result must not share memory with an operand
+
+	if leftInArena {
+		arena.release(bigIntOfArenaValue(left))
+	}
+	if rightInArena {
+		arena.release(bigIntOfArenaValue(right))
+	}
+
+	return result, inArena
+}
+
+// evalArenaOperand evaluates the given operand of an arena operation.
+// The result is allocated in the arena if the operand is itself an arena operation
+//
+func (interpreter *Interpreter) evalArenaOperand(expression ast.Expression) (Value, bool) {
+	if binaryExpression, ok := expression.(*ast.BinaryExpression); ok &&
+		isArenaOperation(binaryExpression.Operation) {
+
+		if value, ok := interpreter.constantExpressionValue(binaryExpression); ok {
+			return value, false
+		}
+
+		return interpreter.evalArenaArithmetic(binaryExpression, true)
+	}
+
+	return interpreter.evalExpression(expression), false
+}
+
+func bigIntOfArenaValue(value Value) *big.Int {
+	switch value := value.(type) {
+	case IntValue:
+		return value.BigInt
+	case UIntValue:
+		return value.BigInt
+	}
+
+	panic(errors.NewUnreachableError())
+}
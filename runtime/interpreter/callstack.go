@@ -0,0 +1,68 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// CallStackFrame is a frame of the call stack of an execution,
+// i.e. the call site of an invoked function.
+//
+// The location and line are only available if the function is invoked by the interpreter,
+// and not e.g. by the VM.
+//
+type CallStackFrame struct {
+	Location common.Location
+	Line     int
+}
+
+// CallStack records the call stack of an execution.
+//
+// A call stack may be shared by the interpreters of an execution, see WithCallStack.
+// When the execution is aborted, e.g. due to an error, the frames are not removed,
+// so the call stack describes the state of the execution at the time of the failure.
+//
+type CallStack struct {
+	frames []CallStackFrame
+}
+
+func (s *CallStack) push(frame CallStackFrame) {
+	s.frames = append(s.frames, frame)
+}
+
+func (s *CallStack) pop() {
+	count := len(s.frames)
+	if count == 0 {
+		return
+	}
+	s.frames = s.frames[:count-1]
+}
+
+// Frames returns a copy of the frames of the call stack,
+// starting with the outermost frame
+//
+func (s *CallStack) Frames() []CallStackFrame {
+	if len(s.frames) == 0 {
+		return nil
+	}
+	frames := make([]CallStackFrame, len(s.frames))
+	copy(frames, s.frames)
+	return frames
+}
@@ -0,0 +1,218 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common/orderedmap"
+)
+
+// copyOnWriteValue is a container value which may lazily share
+// the underlying atree container of another container value.
+//
+// Copies of non-resource containers which are transferred to a local destination
+// (e.g. a variable or an argument) are created in O(1) by sharing the underlying atree container.
+// The elements are only copied when either the copy
+// or the value it was copied from is mutated.
+//
+// All lazy copies of a container share one header of the atree container, see copyOnWriteShares.
+// When the copied container is mutated, the shared header is replaced by a copy of the container,
+// so the lazy copies never have to be tracked individually,
+// and lazy copies which are dropped can be garbage collected.
+//
+// Lazy copies are never stored in other containers:
+// Storing a value in a container transfers it with `remove` set,
+// which copies the elements of a lazy copy.
+//
+// Copy-on-write is only used for containers which cannot contain nested containers,
+// because mutations of nested containers cannot be detected by the outer container.
+//
+type copyOnWriteValue interface {
+	Value
+	// canCopyOnWrite returns true if the value can be copied lazily
+	canCopyOnWrite(interpreter *Interpreter) bool
+	// copyOnWrite returns a lazy copy of the value
+	copyOnWrite(interpreter *Interpreter) Value
+	// materializeCopyOnWrite copies the shared atree container,
+	// so the value can be mutated in-place
+	materializeCopyOnWrite(interpreter *Interpreter)
+}
+
+// copyOnWriteShare is the header of an atree container
+// which is shared by all lazy copies of the container
+//
+type copyOnWriteShare interface {
+	// materialize replaces the shared header with a copy of the container
+	materialize(interpreter *Interpreter)
+}
+
+// copyOnWriteShares records the shared headers of the atree containers which have lazy copies.
+//
+// There is at most one entry per container, no matter how many lazy copies were created,
+// and the entry is removed when the container is mutated.
+//
+// Containers which are dropped without being mutated cannot be detected,
+// so the number of entries is limited to maxCopyOnWriteShares:
+// When the limit is reached, the oldest entry is evicted by materializing it.
+// Eviction is deterministic, as it only depends on the order in which the entries were added
+//
+type copyOnWriteShares = orderedmap.OrderedMap[atree.StorageID, copyOnWriteShare]
+
+// maxCopyOnWriteShares is the maximum number of entries of copyOnWriteShares
+//
+const maxCopyOnWriteShares = 1024
+
+type copyOnWriteArrayShare struct {
+	header *atree.Array
+}
+
+func (s copyOnWriteArrayShare) materialize(interpreter *Interpreter) {
+	*s.header = *copyAtreeArray(interpreter, s.header)
+}
+
+type copyOnWriteDictionaryShare struct {
+	header *atree.OrderedMap
+}
+
+func (s copyOnWriteDictionaryShare) materialize(interpreter *Interpreter) {
+	*s.header = *copyAtreeMap(interpreter, s.header)
+}
+
+// transferToLocal transfers the given value to a local destination,
+// i.e. a destination which is not a container, like a variable or an argument.
+// Containers may be copied lazily, if the copy-on-write option is enabled, see copyOnWriteValue.
+//
+func (interpreter *Interpreter) transferToLocal(value Value, getLocationRange func() LocationRange) Value {
+	if copyOnWriteValue, ok := value.(copyOnWriteValue); ok &&
+		interpreter.copyOnWriteEnabled &&
+		copyOnWriteValue.canCopyOnWrite(interpreter) {
+
+		return copyOnWriteValue.copyOnWrite(interpreter)
+	}
+
+	return value.Transfer(
+		interpreter,
+		getLocationRange,
+		atree.Address{},
+		false,
+		nil,
+	)
+}
+
+// copyOnWriteArrayShare returns the header of the given atree array
+// which is shared by its lazy copies
+//
+func (interpreter *Interpreter) copyOnWriteArrayShare(array *atree.Array) copyOnWriteArrayShare {
+	storageID := array.StorageID()
+
+	if share, ok := interpreter.copyOnWriteShares.Get(storageID); ok {
+		return share.(copyOnWriteArrayShare)
+	}
+
+	header := *array
+	share := copyOnWriteArrayShare{
+		header: &header,
+	}
+	interpreter.addCopyOnWriteShare(storageID, share)
+	return share
+}
+
+// copyOnWriteDictionaryShare returns the header of the given atree map
+// which is shared by its lazy copies
+//
+func (interpreter *Interpreter) copyOnWriteDictionaryShare(dictionary *atree.OrderedMap) copyOnWriteDictionaryShare {
+	storageID := dictionary.StorageID()
+
+	if share, ok := interpreter.copyOnWriteShares.Get(storageID); ok {
+		return share.(copyOnWriteDictionaryShare)
+	}
+
+	header := *dictionary
+	share := copyOnWriteDictionaryShare{
+		header: &header,
+	}
+	interpreter.addCopyOnWriteShare(storageID, share)
+	return share
+}
+
+// materializeCopyOnWriteValues must be called before the atree container
+// with the given storage ID is mutated or removed.
+//
+// The container is copied at most once:
+// All lazy copies still sharing the container share the copy instead.
+//
+func (interpreter *Interpreter) materializeCopyOnWriteValues(storageID atree.StorageID) {
+	share, ok := interpreter.copyOnWriteShares.Delete(storageID)
+	if !ok {
+		return
+	}
+
+	share.materialize(interpreter)
+}
+
+// addCopyOnWriteShare records the shared header of the atree container with the given storage ID.
+// If the number of entries reaches maxCopyOnWriteShares, the oldest entry is materialized and removed
+//
+func (interpreter *Interpreter) addCopyOnWriteShare(storageID atree.StorageID, share copyOnWriteShare) {
+	shares := interpreter.copyOnWriteShares
+
+	if shares.Len() >= maxCopyOnWriteShares {
+		oldest := shares.Oldest()
+		shares.Delete(oldest.Key)
+		oldest.Value.materialize(interpreter)
+	}
+
+	shares.Set(storageID, share)
+}
+
+// isCopyOnWriteElementStaticType returns true if values of the given static type
+// can never be or contain containers, i.e. arrays, dictionaries, or composites.
+//
+func isCopyOnWriteElementStaticType(staticType StaticType) bool {
+	switch staticType := staticType.(type) {
+	case PrimitiveStaticType:
+		switch staticType {
+		case PrimitiveStaticTypeUnknown,
+			PrimitiveStaticTypeAny,
+			PrimitiveStaticTypeAnyStruct,
+			PrimitiveStaticTypeAnyResource,
+			PrimitiveStaticTypeAuthAccount,
+			PrimitiveStaticTypePublicAccount,
+			PrimitiveStaticTypeDeployedContract,
+			PrimitiveStaticTypeAuthAccountContracts,
+			PrimitiveStaticTypePublicAccountContracts,
+			PrimitiveStaticTypeAuthAccountKeys,
+			PrimitiveStaticTypePublicAccountKeys,
+			PrimitiveStaticTypeAccountKey:
+
+			return false
+		}
+
+		return true
+
+	case OptionalStaticType:
+		return isCopyOnWriteElementStaticType(staticType.Type)
+
+	case CapabilityStaticType:
+		return true
+	}
+
+	return false
+}
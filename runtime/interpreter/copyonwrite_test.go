@@ -0,0 +1,118 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestCopyOnWriteShares(t *testing.T) {
+
+	t.Parallel()
+
+	newInterpreter := func(t *testing.T) *Interpreter {
+		inter, err := NewInterpreter(
+			nil,
+			common.StringLocation("test"),
+			WithStorage(NewInMemoryStorage()),
+		)
+		require.NoError(t, err)
+		return inter
+	}
+
+	newArray := func(inter *Interpreter, values ...Value) *ArrayValue {
+		return NewArrayValue(
+			inter,
+			VariableSizedStaticType{
+				Type: PrimitiveStaticTypeInt,
+			},
+			common.Address{},
+			values...,
+		)
+	}
+
+	t.Run("one entry per container", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t)
+
+		array := newArray(inter, NewIntValueFromInt64(1))
+
+		// Copying the same container repeatedly, e.g. when passing it to a function in a loop,
+		// and copying the copies, does not add entries
+
+		var copies []Value
+		for i := 0; i < 10; i++ {
+			value := inter.transferToLocal(array, ReturnEmptyLocationRange)
+			copies = append(copies, value, inter.transferToLocal(value, ReturnEmptyLocationRange))
+		}
+
+		assert.Equal(t, 1, inter.copyOnWriteShares.Len())
+
+		// Mutating the container removes the entry
+
+		array.Append(inter, ReturnEmptyLocationRange, NewIntValueFromInt64(2))
+
+		assert.Equal(t, 0, inter.copyOnWriteShares.Len())
+		assert.Equal(t, 2, array.Count())
+
+		for _, value := range copies {
+			assert.Equal(t, 1, value.(*ArrayValue).Count())
+		}
+	})
+
+	t.Run("dropped containers are evicted", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t)
+
+		first := newArray(inter, NewIntValueFromInt64(1))
+		firstCopy := inter.transferToLocal(first, ReturnEmptyLocationRange).(*ArrayValue)
+
+		// Copying new containers which are dropped without being mutated,
+		// e.g. when passing array literals to a function in a loop,
+		// does not grow the shares without bound
+
+		for i := 0; i < 2*maxCopyOnWriteShares; i++ {
+			array := newArray(inter, NewIntValueFromInt64(int64(i)))
+			_ = inter.transferToLocal(array, ReturnEmptyLocationRange)
+		}
+
+		assert.Equal(t, maxCopyOnWriteShares, inter.copyOnWriteShares.Len())
+
+		// The evicted container was materialized for its copies
+
+		first.Append(inter, ReturnEmptyLocationRange, NewIntValueFromInt64(2))
+
+		assert.Equal(t, 2, first.Count())
+		assert.Equal(t, 1, firstCopy.Count())
+		assert.Equal(
+			t,
+			NewIntValueFromInt64(1),
+			firstCopy.Get(inter, ReturnEmptyLocationRange, 0),
+		)
+	})
+}
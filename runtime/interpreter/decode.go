@@ -21,6 +21,7 @@ package interpreter
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
@@ -30,22 +31,39 @@ import (
 )
 
 var CBORDecMode = func() cbor.DecMode {
-	decMode, err := cbor.DecOptions{
-		IntDec:           cbor.IntDecConvertNone,
-		MaxArrayElements: math.MaxInt64,
-		MaxMapPairs:      math.MaxInt64,
-		MaxNestedLevels:  math.MaxInt16,
-	}.DecMode()
+	decMode, err := NewCBORDecMode(common.DefaultDecodingLimits)
 	if err != nil {
 		panic(err)
 	}
 	return decMode
 }()
 
+// NewCBORDecMode returns a CBOR decoding mode which enforces the given decoding limits
+// on the nesting depth and the declared lengths of arrays and maps.
+//
+// An error is returned if the limits are invalid,
+// or outside the range supported by the CBOR decoder.
+//
+func NewCBORDecMode(limits common.DecodingLimits) (cbor.DecMode, error) {
+	err := limits.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.DecOptions{
+		IntDec:           cbor.IntDecConvertNone,
+		MaxArrayElements: limits.MaxArrayElements,
+		MaxMapPairs:      limits.MaxArrayElements,
+		MaxNestedLevels:  limits.MaxNestedLevels,
+	}.DecMode()
+}
+
 type UnsupportedTagDecodingError struct {
 	Tag uint64
 }
 
+func (UnsupportedTagDecodingError) IsInternalError() {}
+
 func (e UnsupportedTagDecodingError) Error() string {
 	return fmt.Sprintf(
 		"unsupported decoded tag: %d",
@@ -53,6 +71,55 @@ func (e UnsupportedTagDecodingError) Error() string {
 	)
 }
 
+// ValueDecodingError is reported when a stored value fails to decode.
+// It locates the value which failed to decode:
+// Owner and Key are the account and the storage key of the root value, if known,
+// and Path is the path of field names, indices, and keys from the root value
+// to the nested value which failed to decode, e.g. `.vaults["a"][2]`.
+// StorageID is the slab which failed to decode, if known.
+//
+type ValueDecodingError struct {
+	Owner     common.Address
+	Key       string
+	Path      string
+	StorageID atree.StorageID
+	Err       error
+}
+
+func (ValueDecodingError) IsInternalError() {}
+
+func (e ValueDecodingError) Unwrap() error {
+	return e.Err
+}
+
+func (e ValueDecodingError) Error() string {
+	var location strings.Builder
+
+	if e.Key != "" {
+		location.WriteString(e.Key)
+	} else {
+		location.WriteString("<root>")
+	}
+	location.WriteString(e.Path)
+
+	if e.Owner != (common.Address{}) {
+		fmt.Fprintf(&location, " in account %s", e.Owner.ShortHexWithPrefix())
+	}
+
+	if e.StorageID != atree.StorageIDUndefined {
+		fmt.Fprintf(&location, " (slab %s)", e.StorageID)
+	}
+
+	return fmt.Sprintf(
+		"failed to decode value at %s: %s",
+		location.String(),
+		e.Err,
+	)
+}
+
+// DecodeStorable decodes a storable with the default decoding limits,
+// see NewStorableDecoder
+//
 func DecodeStorable(
 	decoder *cbor.StreamDecoder,
 	slabStorageID atree.StorageID,
@@ -60,18 +127,45 @@ func DecodeStorable(
 	return Decoder{
 		decoder:       decoder,
 		slabStorageID: slabStorageID,
+		valueCounter:  common.NewDecodedValueCounter(common.DefaultDecodingLimits),
 	}.decodeStorable()
 }
 
+// NewStorableDecoder returns a storable decoder which enforces the given decoding limits
+// on the number of values decoded from each storable.
+//
+// The other limits are enforced by the CBOR decoder,
+// so the given CBOR decoder should use the CBOR decoding mode for the limits,
+// see NewCBORDecMode.
+//
+func NewStorableDecoder(limits common.DecodingLimits) atree.StorableDecoder {
+	return func(
+		decoder *cbor.StreamDecoder,
+		slabStorageID atree.StorageID,
+	) (atree.Storable, error) {
+		return Decoder{
+			decoder:       decoder,
+			slabStorageID: slabStorageID,
+			valueCounter:  common.NewDecodedValueCounter(limits),
+		}.decodeStorable()
+	}
+}
+
 type Decoder struct {
 	decoder       *cbor.StreamDecoder
 	slabStorageID atree.StorageID
+	valueCounter  *common.DecodedValueCounter
 }
 
 func (d Decoder) decodeStorable() (atree.Storable, error) {
 	var storable atree.Storable
 	var err error
 
+	err = d.valueCounter.Count()
+	if err != nil {
+		return nil, err
+	}
+
 	t, err := d.decoder.NextType()
 	if err != nil {
 		return nil, err
@@ -134,6 +228,12 @@ func (d Decoder) decodeStorable() (atree.Storable, error) {
 		case CBORTagAddressValue:
 			storable, err = d.decodeAddress()
 
+		case CBORTagTimestampValue:
+			storable, err = d.decodeTimestamp()
+
+		case CBORTagDurationValue:
+			storable, err = d.decodeDuration()
+
 		// Int*
 
 		case CBORTagIntValue:
@@ -199,11 +299,17 @@ func (d Decoder) decodeStorable() (atree.Storable, error) {
 		case CBORTagFix64Value:
 			storable, err = d.decodeFix64()
 
+		case CBORTagFix128Value:
+			storable, err = d.decodeFix128()
+
 		// UFix*
 
 		case CBORTagUFix64Value:
 			storable, err = d.decodeUFix64()
 
+		case CBORTagUFix128Value:
+			storable, err = d.decodeUFix128()
+
 		// Storage
 
 		case CBORTagPathValue:
@@ -215,6 +321,13 @@ func (d Decoder) decodeStorable() (atree.Storable, error) {
 		case CBORTagLinkValue:
 			storable, err = d.decodeLink()
 
+		case CBORTagAccountLinkValue:
+			err = d.decoder.Skip()
+			if err != nil {
+				return nil, err
+			}
+			storable = AccountLinkValue{}
+
 		case CBORTagTypeValue:
 			storable, err = d.decodeType()
 
@@ -281,11 +394,74 @@ func decodeLocation(dec *cbor.StreamDecoder) (common.Location, error) {
 	case CBORTagScriptLocation:
 		return decodeScriptLocation(dec)
 
+	case CBORTagCustomLocation:
+		return decodeCustomLocation(dec)
+
 	default:
 		return nil, fmt.Errorf("invalid location encoding tag: %d", number)
 	}
 }
 
+func decodeCustomLocation(dec *cbor.StreamDecoder) (common.Location, error) {
+
+	const expectedLength = encodedCustomLocationLength
+
+	size, err := dec.DecodeArrayHead()
+
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return nil, fmt.Errorf(
+				"invalid custom location encoding: expected [%d]interface{}, got %s",
+				expectedLength,
+				e.ActualType.String(),
+			)
+		}
+		return nil, err
+	}
+
+	if size != expectedLength {
+		return nil, fmt.Errorf("invalid custom location encoding: expected [%d]interface{}, got [%d]interface{}",
+			expectedLength,
+			size,
+		)
+	}
+
+	// Prefix
+
+	// Decode prefix at array index encodedCustomLocationPrefixFieldKey
+	prefix, err := dec.DecodeString()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return nil, fmt.Errorf(
+				"invalid custom location prefix encoding: %s",
+				e.ActualType.String(),
+			)
+		}
+		return nil, err
+	}
+
+	kind, ok := common.LookupLocationKind(prefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid custom location encoding: unknown kind: %s", prefix)
+	}
+
+	// Encoded location
+
+	// Decode location at array index encodedCustomLocationEncodedFieldKey
+	encoded, err := dec.DecodeBytes()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return nil, fmt.Errorf(
+				"invalid custom location encoding: %s",
+				e.ActualType.String(),
+			)
+		}
+		return nil, err
+	}
+
+	return kind.Decode(encoded)
+}
+
 func decodeStringLocation(dec *cbor.StreamDecoder) (common.Location, error) {
 	s, err := dec.DecodeString()
 	if err != nil {
@@ -499,6 +675,30 @@ func (d Decoder) decodeInt64() (Int64Value, error) {
 	return Int64Value(v), nil
 }
 
+func (d Decoder) decodeTimestamp() (TimestampValue, error) {
+	v, err := d.decoder.DecodeInt64()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return 0, fmt.Errorf("unknown Timestamp encoding: %s", e.ActualType.String())
+		}
+		return 0, err
+	}
+
+	return TimestampValue(v), nil
+}
+
+func (d Decoder) decodeDuration() (DurationValue, error) {
+	v, err := d.decoder.DecodeInt64()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return 0, fmt.Errorf("unknown Duration encoding: %s", e.ActualType.String())
+		}
+		return 0, err
+	}
+
+	return DurationValue(v), nil
+}
+
 func (d Decoder) decodeInt128() (Int128Value, error) {
 	bigInt, err := d.decoder.DecodeBigInt()
 	if err != nil {
@@ -737,6 +937,49 @@ func (d Decoder) decodeUFix64() (UFix64Value, error) {
 	return UFix64Value(value), nil
 }
 
+func (d Decoder) decodeFix128() (Fix128Value, error) {
+	bigInt, err := d.decoder.DecodeBigInt()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return Fix128Value{}, fmt.Errorf("invalid Fix128 encoding: %s", e.ActualType.String())
+		}
+		return Fix128Value{}, err
+	}
+
+	min := sema.Fix128TypeMinBig
+	if bigInt.Cmp(min) < 0 {
+		return Fix128Value{}, fmt.Errorf("invalid Fix128: got %s, expected min %s", bigInt, min)
+	}
+
+	max := sema.Fix128TypeMaxBig
+	if bigInt.Cmp(max) > 0 {
+		return Fix128Value{}, fmt.Errorf("invalid Fix128: got %s, expected max %s", bigInt, max)
+	}
+
+	return NewFix128ValueFromBigInt(bigInt), nil
+}
+
+func (d Decoder) decodeUFix128() (UFix128Value, error) {
+	bigInt, err := d.decoder.DecodeBigInt()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return UFix128Value{}, fmt.Errorf("invalid UFix128 encoding: %s", e.ActualType.String())
+		}
+		return UFix128Value{}, err
+	}
+
+	if bigInt.Sign() < 0 {
+		return UFix128Value{}, fmt.Errorf("invalid UFix128: got %s, expected positive", bigInt)
+	}
+
+	max := sema.UFix128TypeMaxBig
+	if bigInt.Cmp(max) > 0 {
+		return UFix128Value{}, fmt.Errorf("invalid UFix128: got %s, expected max %s", bigInt, max)
+	}
+
+	return NewUFix128ValueFromBigInt(bigInt), nil
+}
+
 func (d Decoder) decodeSome() (SomeStorable, error) {
 	storable, err := d.decodeStorable()
 	if err != nil {
@@ -1268,6 +1511,7 @@ func decodeConstantSizedStaticType(dec *cbor.StreamDecoder) (StaticType, error)
 
 func decodeReferenceStaticType(dec *cbor.StreamDecoder) (StaticType, error) {
 	const expectedLength = encodedReferenceStaticTypeLength
+	const expectedUnentitledLength = encodedUnentitledReferenceStaticTypeLength
 
 	arraySize, err := dec.DecodeArrayHead()
 
@@ -1282,7 +1526,7 @@ func decodeReferenceStaticType(dec *cbor.StreamDecoder) (StaticType, error) {
 		return nil, err
 	}
 
-	if arraySize != expectedLength {
+	if arraySize != expectedLength && arraySize != expectedUnentitledLength {
 		return nil, fmt.Errorf(
 			"invalid reference static type encoding: expected [%d]interface{}, got [%d]interface{}",
 			expectedLength,
@@ -1311,9 +1555,94 @@ func decodeReferenceStaticType(dec *cbor.StreamDecoder) (StaticType, error) {
 		)
 	}
 
-	return ReferenceStaticType{
-		Authorized: authorized,
-		Type:       staticType,
+	var entitlements []EntitlementStaticType
+
+	if arraySize == expectedLength {
+
+		// Decode entitlements at array index encodedReferenceStaticTypeEntitlementsFieldKey
+		entitlementsSize, err := dec.DecodeArrayHead()
+		if err != nil {
+			if e, ok := err.(*cbor.WrongTypeError); ok {
+				return nil, fmt.Errorf(
+					"invalid reference static type entitlements encoding: %s",
+					e.ActualType.String(),
+				)
+			}
+			return nil, err
+		}
+
+		entitlements = make([]EntitlementStaticType, entitlementsSize)
+		for i := 0; i < int(entitlementsSize); i++ {
+			entitlement, err := decodeEntitlementStaticType(dec)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid reference static type entitlement encoding: %w",
+					err,
+				)
+			}
+
+			entitlements[i] = entitlement
+		}
+	}
+
+	return &ReferenceStaticType{
+		Authorized:   authorized,
+		Entitlements: entitlements,
+		Type:         staticType,
+	}, nil
+}
+
+func decodeEntitlementStaticType(dec *cbor.StreamDecoder) (EntitlementStaticType, error) {
+	const expectedLength = encodedEntitlementStaticTypeLength
+
+	size, err := dec.DecodeArrayHead()
+
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return EntitlementStaticType{},
+				fmt.Errorf(
+					"invalid entitlement static type encoding: expected [%d]interface{}, got %s",
+					expectedLength,
+					e.ActualType.String(),
+				)
+		}
+		return EntitlementStaticType{}, err
+	}
+
+	if size != expectedLength {
+		return EntitlementStaticType{},
+			fmt.Errorf(
+				"invalid entitlement static type encoding: expected [%d]interface{}, got [%d]interface{}",
+				expectedLength,
+				size,
+			)
+	}
+
+	// Decode location at array index encodedEntitlementStaticTypeLocationFieldKey
+	location, err := decodeLocation(dec)
+	if err != nil {
+		return EntitlementStaticType{}, fmt.Errorf(
+			"invalid entitlement static type location encoding: %w",
+			err,
+		)
+	}
+
+	// Decode qualified identifier at array index encodedEntitlementStaticTypeQualifiedIdentifierFieldKey
+	qualifiedIdentifier, err := dec.DecodeString()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return EntitlementStaticType{},
+				fmt.Errorf(
+					"invalid entitlement static type qualified identifier encoding: %s",
+					e.ActualType.String(),
+				)
+		}
+		return EntitlementStaticType{}, err
+	}
+
+	return EntitlementStaticType{
+		Location:            location,
+		QualifiedIdentifier: qualifiedIdentifier,
 	}, nil
 }
 
@@ -0,0 +1,230 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/atree"
+)
+
+// DeepCopy returns a copy of the given value, owned by the given address.
+// Use the empty address to get a temporary copy, which is not stored in any account.
+//
+// The copy is independent of the original value:
+// all nested containers are copied, and all their contents are loaded from storage.
+// The original value is neither moved nor modified.
+//
+// Resources are copied like any other value, including their UUID.
+// The copy is therefore NOT a new resource and must not be used as one,
+// e.g. it must not be stored in addition to the original.
+// This makes DeepCopy suitable for snapshots and comparisons,
+// but not for program semantics, which should use Transfer.
+//
+// The copy is DeepEqual to the original.
+//
+func DeepCopy(interpreter *Interpreter, value Value, address atree.Address) Value {
+	switch value := value.(type) {
+	case *ArrayValue:
+		return deepCopyArray(interpreter, value, address)
+
+	case *DictionaryValue:
+		return deepCopyDictionary(interpreter, value, address)
+
+	case *CompositeValue:
+		return deepCopyComposite(interpreter, value, address)
+
+	case *SomeValue:
+		return NewSomeValueNonCopying(
+			DeepCopy(interpreter, value.Value, address),
+		)
+
+	default:
+		// All other values are either immutable, or not stored in containers
+		return value.Clone(interpreter)
+	}
+}
+
+func deepCopyArray(interpreter *Interpreter, v *ArrayValue, address atree.Address) *ArrayValue {
+	iterator, err := v.array.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	array, err := atree.NewArrayFromBatchData(
+		interpreter.Storage,
+		address,
+		v.array.Type(),
+		func() (atree.Value, error) {
+			value, err := iterator.Next()
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				return nil, nil
+			}
+
+			element := DeepCopy(interpreter, MustConvertStoredValue(value), address)
+
+			return element, nil
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return &ArrayValue{
+		Type:             v.Type,
+		semaType:         v.semaType,
+		isResourceKinded: v.isResourceKinded,
+		array:            array,
+		isDestroyed:      v.isDestroyed,
+	}
+}
+
+func deepCopyDictionary(interpreter *Interpreter, v *DictionaryValue, address atree.Address) *DictionaryValue {
+
+	valueComparator := newValueComparator(interpreter, ReturnEmptyLocationRange)
+	hashInputProvider := newHashInputProvider(interpreter, ReturnEmptyLocationRange)
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	// NOTE: the seed of the original is reused,
+	// so the entries can be inserted in iteration order
+
+	dictionary, err := atree.NewMapFromBatchData(
+		interpreter.Storage,
+		address,
+		atree.NewDefaultDigesterBuilder(),
+		v.dictionary.Type(),
+		valueComparator,
+		hashInputProvider,
+		v.dictionary.Seed(),
+		func() (atree.Value, atree.Value, error) {
+
+			atreeKey, atreeValue, err := iterator.Next()
+			if err != nil {
+				return nil, nil, err
+			}
+			if atreeKey == nil || atreeValue == nil {
+				return nil, nil, nil
+			}
+
+			key := DeepCopy(interpreter, MustConvertStoredValue(atreeKey), address)
+			value := DeepCopy(interpreter, MustConvertStoredValue(atreeValue), address)
+
+			return key, value, nil
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return &DictionaryValue{
+		Type:             v.Type,
+		semaType:         v.semaType,
+		isResourceKinded: v.isResourceKinded,
+		dictionary:       dictionary,
+		isDestroyed:      v.isDestroyed,
+	}
+}
+
+func deepCopyComposite(interpreter *Interpreter, v *CompositeValue, address atree.Address) *CompositeValue {
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	dictionary, err := atree.NewMapFromBatchData(
+		interpreter.Storage,
+		address,
+		atree.NewDefaultDigesterBuilder(),
+		v.dictionary.Type(),
+		stringAtreeComparator,
+		stringAtreeHashInput,
+		v.dictionary.Seed(),
+		func() (atree.Value, atree.Value, error) {
+
+			atreeKey, atreeValue, err := iterator.Next()
+			if err != nil {
+				return nil, nil, err
+			}
+			if atreeKey == nil || atreeValue == nil {
+				return nil, nil, nil
+			}
+
+			// Field names are immutable and not owned
+
+			value := DeepCopy(interpreter, MustConvertStoredValue(atreeValue), address)
+
+			return atreeKey, value, nil
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return &CompositeValue{
+		dictionary:          dictionary,
+		Location:            v.Location,
+		QualifiedIdentifier: v.QualifiedIdentifier,
+		Kind:                v.Kind,
+		InjectedFields:      v.InjectedFields,
+		ComputedFields:      v.ComputedFields,
+		NestedVariables:     v.NestedVariables,
+		Functions:           v.Functions,
+		memberSlots:         v.memberSlots,
+		functionSlots:       v.functionSlots,
+		constantFields:      v.constantFields,
+		Destructor:          v.Destructor,
+		Stringer:            v.Stringer,
+		isDestroyed:         v.isDestroyed,
+		typeID:              v.typeID,
+		staticType:          v.staticType,
+		dynamicType:         v.dynamicType,
+	}
+}
+
+// DeepEqual returns true if the given values are structurally equal.
+//
+// Containers are compared by their static types and their contents,
+// which are loaded from storage if needed.
+// The owners of values are not compared, i.e. a value stored in an account
+// is equal to its temporary copy.
+//
+// Resources are compared like any other composite value, including their UUID,
+// so two distinct resources are never equal, even if all other fields are equal.
+//
+// Values which cannot be compared, like functions, are never equal.
+// Two nil (absent) values are equal.
+//
+func DeepEqual(interpreter *Interpreter, value, other Value) bool {
+	if value == nil || other == nil {
+		return value == nil && other == nil
+	}
+
+	equatableValue, ok := value.(EquatableValue)
+	if !ok {
+		return false
+	}
+
+	return equatableValue.Equal(interpreter, ReturnEmptyLocationRange, other)
+}
@@ -0,0 +1,167 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCompleteDeferredTransfer(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("test")
+
+	resourceType := &sema.CompositeType{
+		Location:   location,
+		Identifier: "R",
+		Kind:       common.CompositeKindResource,
+		Members:    &sema.StringMemberOrderedMap{},
+	}
+
+	owner := common.Address{0x1}
+
+	storageKey := common.StorageKey{
+		Domain:     common.PathDomainStorage.Identifier(),
+		Identifier: "r",
+	}
+
+	// newMovedResource returns a resource with a nested container,
+	// which was saved to and then loaded from account storage,
+	// i.e. the transfer of which is deferred
+
+	newMovedResource := func(t *testing.T) (*Interpreter, *CompositeValue) {
+
+		elaboration := sema.NewElaboration()
+		elaboration.CompositeTypes[resourceType.ID()] = resourceType
+
+		inter, err := NewInterpreter(
+			&Program{
+				Elaboration: elaboration,
+			},
+			location,
+			WithStorage(NewInMemoryStorage()),
+		)
+		require.NoError(t, err)
+
+		resource := NewCompositeValue(
+			inter,
+			location,
+			"R",
+			common.CompositeKindResource,
+			[]CompositeField{
+				{
+					Name: "values",
+					Value: NewArrayValue(
+						inter,
+						VariableSizedStaticType{
+							Type: PrimitiveStaticTypeInt,
+						},
+						common.Address{},
+						NewIntValueFromInt64(1),
+					),
+				},
+			},
+			common.Address{},
+		)
+
+		inter.writeStored(owner, storageKey, resource)
+
+		loaded := inter.ReadStored(owner, storageKey).(*CompositeValue)
+		inter.detachStored(owner, storageKey)
+		loaded.deferTransfer(inter)
+
+		require.True(t, loaded.isTransferDeferred())
+		require.Equal(t, common.Address{}, loaded.GetOwner())
+
+		return inter, loaded
+	}
+
+	t.Run("ForEachField", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, resource := newMovedResource(t)
+
+		var fieldOwners []common.Address
+		resource.ForEachField(func(_ string, value Value) {
+			fieldOwners = append(fieldOwners, value.(*ArrayValue).GetOwner())
+		})
+
+		assert.False(t, resource.isTransferDeferred())
+		assert.Equal(t, []common.Address{{}}, fieldOwners)
+	})
+
+	t.Run("Walk", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, resource := newMovedResource(t)
+
+		var childOwners []common.Address
+		resource.Walk(func(child Value) {
+			childOwners = append(childOwners, child.(*ArrayValue).GetOwner())
+		})
+
+		assert.False(t, resource.isTransferDeferred())
+		assert.Equal(t, []common.Address{{}}, childOwners)
+	})
+
+	t.Run("GetField", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Exporting a composite value reads its fields using GetField
+
+		_, resource := newMovedResource(t)
+
+		field := resource.GetField("values").(*ArrayValue)
+
+		assert.False(t, resource.isTransferDeferred())
+		assert.Equal(t, common.Address{}, field.GetOwner())
+		assert.Equal(t, 1, field.Count())
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, resource := newMovedResource(t)
+
+		var arrayOwners []common.Address
+		resource.Accept(
+			inter,
+			EmptyVisitor{
+				ArrayValueVisitor: func(_ *Interpreter, value *ArrayValue) bool {
+					arrayOwners = append(arrayOwners, value.GetOwner())
+					return false
+				},
+			},
+		)
+
+		assert.False(t, resource.isTransferDeferred())
+		assert.Equal(t, []common.Address{{}}, arrayOwners)
+	})
+}
@@ -0,0 +1,191 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// hasDerivedToString returns true if the composite value is a user-defined structure or resource,
+// which have a derived `toString` function if they do not declare one themselves,
+// see sema.CompositeType
+//
+func (v *CompositeValue) hasDerivedToString() bool {
+	if v.Location == nil {
+		return false
+	}
+
+	switch v.Kind {
+	case common.CompositeKindStructure,
+		common.CompositeKindResource:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// derivedToStringFunction returns the derived `toString` function of the given composite value
+//
+func derivedToStringFunction(v *CompositeValue) *HostFunctionValue {
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+			stringer := derivedStringer{
+				interpreter:    invocation.Interpreter,
+				seenReferences: SeenReferences{},
+			}
+			stringer.writeComposite(v)
+			return NewStringValue(stringer.builder.String())
+		},
+		sema.ToStringFunctionType,
+	)
+}
+
+// derivedStringer writes the derived string representation of values.
+//
+// Structures and resources are written with their qualified identifier,
+// and their readable fields in declaration order, e.g. `S(a: 1, b: "x")`.
+// Resources are marked with a `@` prefix, e.g. `@R(a: 1)`.
+// Private fields, and fields which are only accessible in the contract or account, are omitted.
+//
+// The values of fields are written recursively, also through arrays, dictionaries, optionals,
+// and references. Cycles, which can only be formed through references, are written as `...`.
+// All other values are written using their string representation, see Value.RecursiveString
+//
+type derivedStringer struct {
+	interpreter    *Interpreter
+	builder        strings.Builder
+	seenReferences SeenReferences
+}
+
+func (s *derivedStringer) write(value Value) {
+	switch value := value.(type) {
+	case *CompositeValue:
+		if value.hasDerivedToString() {
+			s.writeComposite(value)
+			return
+		}
+
+	case *EphemeralReferenceValue:
+		if _, ok := s.seenReferences[value]; ok {
+			s.builder.WriteString("...")
+			return
+		}
+		s.seenReferences[value] = struct{}{}
+		defer delete(s.seenReferences, value)
+
+		s.write(value.Value)
+		return
+
+	case *SomeValue:
+		s.write(value.Value)
+		return
+
+	case *ArrayValue:
+		s.builder.WriteRune('[')
+		i := 0
+		value.Walk(func(element Value) {
+			if i > 0 {
+				s.builder.WriteString(", ")
+			}
+			s.write(element)
+			i++
+		})
+		s.builder.WriteRune(']')
+		return
+
+	case *DictionaryValue:
+		s.builder.WriteRune('{')
+		i := 0
+		value.Iterate(func(key, element Value) (resume bool) {
+			if i > 0 {
+				s.builder.WriteString(", ")
+			}
+			s.write(key)
+			s.builder.WriteString(": ")
+			s.write(element)
+			i++
+			return true
+		})
+		s.builder.WriteRune('}')
+		return
+	}
+
+	s.builder.WriteString(value.RecursiveString(s.seenReferences))
+}
+
+func (s *derivedStringer) writeComposite(v *CompositeValue) {
+
+	interpreter := v.getInterpreter(s.interpreter)
+
+	compositeType, err := interpreter.GetCompositeType(v.Location, v.QualifiedIdentifier, v.TypeID())
+	if err != nil {
+		panic(err)
+	}
+
+	if v.Kind == common.CompositeKindResource {
+		s.builder.WriteRune('@')
+	}
+	s.builder.WriteString(v.QualifiedIdentifier)
+	s.builder.WriteRune('(')
+
+	i := 0
+	for _, name := range compositeType.Fields {
+		member, ok := compositeType.Members.Get(name)
+		if !ok || !isDerivedStringFieldAccess(member.Access) {
+			continue
+		}
+
+		value := v.GetField(name)
+		if value == nil {
+			continue
+		}
+
+		if i > 0 {
+			s.builder.WriteString(", ")
+		}
+		s.builder.WriteString(name)
+		s.builder.WriteString(": ")
+		s.write(value)
+		i++
+	}
+
+	s.builder.WriteRune(')')
+}
+
+// isDerivedStringFieldAccess returns true if fields with the given access
+// are included in the derived string representation of a composite value
+//
+func isDerivedStringFieldAccess(access ast.Access) bool {
+	switch access {
+	case ast.AccessNotSpecified,
+		ast.AccessPublic,
+		ast.AccessPublicSettable:
+
+		return true
+
+	default:
+		return false
+	}
+}
@@ -32,6 +32,7 @@ type ReferenceDynamicType interface {
 	DynamicType
 	isReferenceType()
 	Authorized() bool
+	Entitlements() []*sema.EntitlementType
 	InnerType() DynamicType
 	BorrowedType() sema.Type
 }
@@ -119,6 +120,18 @@ func (t CompositeDynamicType) IsImportable() bool {
 	return t.StaticType.IsImportable(map[*sema.Member]bool{})
 }
 
+// InclusiveRangeDynamicType
+
+type InclusiveRangeDynamicType struct {
+	StaticType *sema.InclusiveRangeType
+}
+
+func (InclusiveRangeDynamicType) IsDynamicType() {}
+
+func (InclusiveRangeDynamicType) IsImportable() bool {
+	return false
+}
+
 // DictionaryDynamicType
 
 type DictionaryStaticTypeEntry struct {
@@ -170,6 +183,7 @@ func (t SomeDynamicType) IsImportable() bool {
 
 type StorageReferenceDynamicType struct {
 	authorized   bool
+	entitlements []*sema.EntitlementType
 	innerType    DynamicType
 	borrowedType sema.Type
 }
@@ -182,6 +196,10 @@ func (t StorageReferenceDynamicType) Authorized() bool {
 	return t.authorized
 }
 
+func (t StorageReferenceDynamicType) Entitlements() []*sema.EntitlementType {
+	return t.entitlements
+}
+
 func (t StorageReferenceDynamicType) InnerType() DynamicType {
 	return t.innerType
 }
@@ -198,6 +216,7 @@ func (StorageReferenceDynamicType) IsImportable() bool {
 
 type EphemeralReferenceDynamicType struct {
 	authorized   bool
+	entitlements []*sema.EntitlementType
 	innerType    DynamicType
 	borrowedType sema.Type
 }
@@ -210,6 +229,10 @@ func (t EphemeralReferenceDynamicType) Authorized() bool {
 	return t.authorized
 }
 
+func (t EphemeralReferenceDynamicType) Entitlements() []*sema.EntitlementType {
+	return t.entitlements
+}
+
 func (t EphemeralReferenceDynamicType) InnerType() DynamicType {
 	return t.innerType
 }
@@ -308,6 +331,26 @@ func (BlockDynamicType) IsImportable() bool {
 	return sema.BlockType.Importable
 }
 
+// TimestampDynamicType
+
+type TimestampDynamicType struct{}
+
+func (TimestampDynamicType) IsDynamicType() {}
+
+func (TimestampDynamicType) IsImportable() bool {
+	return sema.TimestampType.Importable
+}
+
+// DurationDynamicType
+
+type DurationDynamicType struct{}
+
+func (DurationDynamicType) IsDynamicType() {}
+
+func (DurationDynamicType) IsImportable() bool {
+	return sema.DurationType.Importable
+}
+
 // UnwrapOptionalDynamicType returns the type if it is not an optional type,
 // or the inner-most type if it is (optional types are repeatedly unwrapped)
 //
@@ -91,6 +91,9 @@ func getBytesCBORSize(b []byte) uint32 {
 // appending new fields with the next highest key.
 //
 // DO *NOT* REPLACE EXISTING FIELDS!
+//
+// When adding or changing an encoding, add entries to the conformance corpus
+// in testdata/storable_conformance.json, see TestStorableConformance.
 
 const CBORTagBase = 128
 
@@ -115,8 +118,8 @@ const (
 	CBORTagTypeValue
 	_ // DO *NOT* REPLACE. Previously used for array values
 	CBORTagStringValue
-	_
-	_
+	CBORTagTimestampValue
+	CBORTagDurationValue
 	_
 	_
 	_
@@ -168,7 +171,7 @@ const (
 	_ // future: Fix16
 	_ // future: Fix32
 	CBORTagFix64Value
-	_ // future: Fix128
+	CBORTagFix128Value
 	_ // future: Fix256
 	_
 
@@ -178,7 +181,7 @@ const (
 	_ // future: UFix16
 	_ // future: UFix32
 	CBORTagUFix64Value
-	_ // future: UFix128
+	CBORTagUFix128Value
 	_ // future: UFix256
 	_
 
@@ -188,7 +191,7 @@ const (
 	CBORTagIdentifierLocation
 	CBORTagTransactionLocation
 	CBORTagScriptLocation
-	_
+	CBORTagCustomLocation
 	_
 	_
 
@@ -198,7 +201,7 @@ const (
 	CBORTagCapabilityValue
 	_ // DO NOT REPLACE! used to be used for storage references
 	CBORTagLinkValue
-	_
+	CBORTagAccountLinkValue
 	_
 	_
 	_
@@ -612,6 +615,70 @@ func (v UFix64Value) Encode(e *atree.Encoder) error {
 	return e.CBOR.EncodeUint64(uint64(v))
 }
 
+// Encode encodes Fix128Value as
+// cbor.Tag{
+//		Number:  CBORTagFix128Value,
+//		Content: *big.Int(v.BigInt),
+// }
+func (v Fix128Value) Encode(e *atree.Encoder) error {
+	err := e.CBOR.EncodeRawBytes([]byte{
+		// tag number
+		0xd8, CBORTagFix128Value,
+	})
+	if err != nil {
+		return err
+	}
+	return e.CBOR.EncodeBigInt(v.BigInt)
+}
+
+// Encode encodes UFix128Value as
+// cbor.Tag{
+//		Number:  CBORTagUFix128Value,
+//		Content: *big.Int(v.BigInt),
+// }
+func (v UFix128Value) Encode(e *atree.Encoder) error {
+	err := e.CBOR.EncodeRawBytes([]byte{
+		// tag number
+		0xd8, CBORTagUFix128Value,
+	})
+	if err != nil {
+		return err
+	}
+	return e.CBOR.EncodeBigInt(v.BigInt)
+}
+
+// Encode encodes TimestampValue as
+// cbor.Tag{
+//		Number:  CBORTagTimestampValue,
+//		Content: int64(v),
+// }
+func (v TimestampValue) Encode(e *atree.Encoder) error {
+	err := e.CBOR.EncodeRawBytes([]byte{
+		// tag number
+		0xd8, CBORTagTimestampValue,
+	})
+	if err != nil {
+		return err
+	}
+	return e.CBOR.EncodeInt64(int64(v))
+}
+
+// Encode encodes DurationValue as
+// cbor.Tag{
+//		Number:  CBORTagDurationValue,
+//		Content: int64(v),
+// }
+func (v DurationValue) Encode(e *atree.Encoder) error {
+	err := e.CBOR.EncodeRawBytes([]byte{
+		// tag number
+		0xd8, CBORTagDurationValue,
+	})
+	if err != nil {
+		return err
+	}
+	return e.CBOR.EncodeInt64(int64(v))
+}
+
 // Encode encodes SomeStorable as
 // cbor.Tag{
 //		Number: CBORTagSomeValue,
@@ -749,6 +816,18 @@ const (
 	encodedAddressLocationLength = 2
 )
 
+// NOTE: NEVER change, only add/increment; ensure uint64
+const (
+	// encodedCustomLocationPrefixFieldKey  uint64 = 0
+	// encodedCustomLocationEncodedFieldKey uint64 = 1
+
+	// !!! *WARNING* !!!
+	//
+	// encodedCustomLocationLength MUST be updated when new element is added.
+	// It is used to verify encoded custom location length during decoding.
+	encodedCustomLocationLength = 2
+)
+
 func encodeLocation(e *cbor.StreamEncoder, l common.Location) error {
 	if l == nil {
 		return e.EncodeNil()
@@ -851,6 +930,47 @@ func encodeLocation(e *cbor.StreamEncoder, l common.Location) error {
 
 		return e.EncodeBytes(l)
 
+	case common.CustomLocation:
+		// common.CustomLocation is encoded as
+		// cbor.Tag{
+		//		Number: CBORTagCustomLocation,
+		//		Content: []interface{}{
+		//			encodedCustomLocationPrefixFieldKey:  string(l.LocationKindPrefix()),
+		//			encodedCustomLocationEncodedFieldKey: []byte(kind.Encode(l)),
+		//		},
+		// }
+
+		prefix := l.LocationKindPrefix()
+		kind, ok := common.LookupLocationKind(prefix)
+		if !ok {
+			return fmt.Errorf("unsupported location kind: %s", prefix)
+		}
+
+		encoded, err := kind.Encode(l)
+		if err != nil {
+			return err
+		}
+
+		// Encode tag number and array head
+		err = e.EncodeRawBytes([]byte{
+			// tag number
+			0xd8, CBORTagCustomLocation,
+			// array, 2 items follow
+			0x82,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Encode prefix at array index encodedCustomLocationPrefixFieldKey
+		err = e.EncodeString(prefix)
+		if err != nil {
+			return err
+		}
+
+		// Encode location at array index encodedCustomLocationEncodedFieldKey
+		return e.EncodeBytes(encoded)
+
 	default:
 		return fmt.Errorf("unsupported location: %T", l)
 	}
@@ -896,6 +1016,26 @@ func (v LinkValue) Encode(e *atree.Encoder) error {
 	return EncodeStaticType(e.CBOR, v.Type)
 }
 
+// cborAccountLinkValue represents the CBOR value:
+//
+// 	cbor.Tag{
+// 		Number: CBORTagAccountLinkValue,
+// 		Content: nil
+// 	}
+//
+var cborAccountLinkValue = []byte{
+	// tag
+	0xd8, CBORTagAccountLinkValue,
+	// null
+	0xf6,
+}
+
+// Encode writes an account link to the encoder
+//
+func (v AccountLinkValue) Encode(e *atree.Encoder) error {
+	return e.CBOR.EncodeRawBytes(cborAccountLinkValue)
+}
+
 // NOTE: NEVER change, only add/increment; ensure uint64
 const (
 	// encodedTypeValueTypeFieldKey uint64 = 0
@@ -1134,31 +1274,49 @@ func (t ConstantSizedStaticType) Encode(e *cbor.StreamEncoder) error {
 
 // NOTE: NEVER change, only add/increment; ensure uint64
 const (
-	// encodedReferenceStaticTypeAuthorizedFieldKey uint64 = 0
-	// encodedReferenceStaticTypeTypeFieldKey       uint64 = 1
+	// encodedReferenceStaticTypeAuthorizedFieldKey   uint64 = 0
+	// encodedReferenceStaticTypeTypeFieldKey         uint64 = 1
+	// encodedReferenceStaticTypeEntitlementsFieldKey uint64 = 2
 
 	// !!! *WARNING* !!!
 	//
 	// encodedReferenceStaticTypeLength MUST be updated when new element is added.
 	// It is used to verify encoded reference static type length during decoding.
-	encodedReferenceStaticTypeLength = 2
+	encodedReferenceStaticTypeLength = 3
+
+	// encodedUnentitledReferenceStaticTypeLength is the length of
+	// the encoding of reference static types without entitlements,
+	// which omits the entitlements field.
+	encodedUnentitledReferenceStaticTypeLength = 2
 )
 
 // Encode encodes ReferenceStaticType as
 // cbor.Tag{
 //		Number: CBORTagReferenceStaticType,
 //		Content: cborArray{
-//				encodedReferenceStaticTypeAuthorizedFieldKey: bool(v.Authorized),
-//				encodedReferenceStaticTypeTypeFieldKey:       StaticType(v.Type),
+//				encodedReferenceStaticTypeAuthorizedFieldKey:   bool(v.Authorized),
+//				encodedReferenceStaticTypeTypeFieldKey:         StaticType(v.Type),
+//				encodedReferenceStaticTypeEntitlementsFieldKey: []interface{}(v.Entitlements),
 //		},
 //	}
-func (t ReferenceStaticType) Encode(e *cbor.StreamEncoder) error {
+//
+// The entitlements field is omitted if the reference type has no entitlements.
+//
+func (t *ReferenceStaticType) Encode(e *cbor.StreamEncoder) error {
+	hasEntitlements := len(t.Entitlements) > 0
+
+	// array, 2 items follow
+	var arrayHead byte = 0x82
+	if hasEntitlements {
+		// array, 3 items follow
+		arrayHead = 0x83
+	}
+
 	// Encode tag number and array head
 	err := e.EncodeRawBytes([]byte{
 		// tag number
 		0xd8, CBORTagReferenceStaticType,
-		// array, 2 items follow
-		0x82,
+		arrayHead,
 	})
 	if err != nil {
 		return err
@@ -1169,7 +1327,67 @@ func (t ReferenceStaticType) Encode(e *cbor.StreamEncoder) error {
 		return err
 	}
 	// Encode type at array index encodedReferenceStaticTypeTypeFieldKey
-	return EncodeStaticType(e, t.Type)
+	err = EncodeStaticType(e, t.Type)
+	if err != nil {
+		return err
+	}
+	if !hasEntitlements {
+		return nil
+	}
+	// Encode entitlements (as array) at array index encodedReferenceStaticTypeEntitlementsFieldKey
+	err = e.EncodeArrayHead(uint64(len(t.Entitlements)))
+	if err != nil {
+		return err
+	}
+	for _, entitlement := range t.Entitlements {
+		// Encode entitlement as array entitlements element
+		err = entitlement.Encode(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NOTE: NEVER change, only add/increment; ensure uint64
+const (
+	// encodedEntitlementStaticTypeLocationFieldKey            uint64 = 0
+	// encodedEntitlementStaticTypeQualifiedIdentifierFieldKey uint64 = 1
+
+	// !!! *WARNING* !!!
+	//
+	// encodedEntitlementStaticTypeLength MUST be updated when new element is added.
+	// It is used to verify encoded entitlement static type length during decoding.
+	encodedEntitlementStaticTypeLength = 2
+)
+
+// Encode encodes EntitlementStaticType as
+// cborArray{
+//		encodedEntitlementStaticTypeLocationFieldKey:            Location(v.Location),
+//		encodedEntitlementStaticTypeQualifiedIdentifierFieldKey: string(v.QualifiedIdentifier),
+// }
+//
+// NOTE: entitlement static types are not static types on their own,
+// and only occur in reference static types, so they are not tagged.
+//
+func (t EntitlementStaticType) Encode(e *cbor.StreamEncoder) error {
+	// Encode array head
+	err := e.EncodeRawBytes([]byte{
+		// array, 2 items follow
+		0x82,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Encode location at array index encodedEntitlementStaticTypeLocationFieldKey
+	err = encodeLocation(e, t.Location)
+	if err != nil {
+		return err
+	}
+
+	// Encode qualified identifier at array index encodedEntitlementStaticTypeQualifiedIdentifierFieldKey
+	return e.EncodeString(t.QualifiedIdentifier)
 }
 
 // NOTE: NEVER change, only add/increment; ensure uint64
@@ -1285,6 +1503,12 @@ func (t FunctionStaticType) Encode(_ *cbor.StreamEncoder) error {
 	}
 }
 
+func (t InclusiveRangeStaticType) Encode(_ *cbor.StreamEncoder) error {
+	return NonStorableStaticTypeError{
+		Type: t,
+	}
+}
+
 // compositeTypeInfo
 //
 type compositeTypeInfo struct {
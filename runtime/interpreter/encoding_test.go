@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"fmt"
 	"math"
 	"math/big"
 	"strings"
@@ -2678,6 +2679,201 @@ func TestEncodeDecodeUFix64Value(t *testing.T) {
 	})
 }
 
+func TestEncodeDecodeFix128Value(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("zero", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewFix128ValueFromBigInt(big.NewInt(0)),
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 0
+					0x40,
+				},
+			},
+		)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewFix128ValueFromBigInt(big.NewInt(-42)),
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// negative bignum
+					0xc3,
+					// byte string, length 1
+					0x41,
+					0x29,
+				},
+			},
+		)
+	})
+
+	t.Run("min", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// negative bignum
+					0xc3,
+					// byte string, length 16
+					0x50,
+					0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				},
+			},
+		)
+	})
+
+	t.Run("<min", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// negative bignum
+					0xc3,
+					// byte string, length 16
+					0x50,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				},
+				invalid: true,
+			},
+		)
+	})
+
+	t.Run("max", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 16
+					0x50,
+					0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				},
+			},
+		)
+	})
+
+	t.Run(">max", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				encoded: []byte{
+					0xd8, CBORTagFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 16
+					0x50,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				},
+				invalid: true,
+			},
+		)
+	})
+}
+
+func TestEncodeDecodeUFix128Value(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("zero", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewUFix128ValueFromBigInt(big.NewInt(0)),
+				encoded: []byte{
+					0xd8, CBORTagUFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 0
+					0x40,
+				},
+			},
+		)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				encoded: []byte{
+					0xd8, CBORTagUFix128Value,
+					// negative bignum
+					0xc3,
+					// byte string, length 1
+					0x41,
+					0x29,
+				},
+				invalid: true,
+			},
+		)
+	})
+
+	t.Run("max", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+				encoded: []byte{
+					0xd8, CBORTagUFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 16
+					0x50,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+					0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				},
+			},
+		)
+	})
+
+	t.Run(">max", func(t *testing.T) {
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				encoded: []byte{
+					0xd8, CBORTagUFix128Value,
+					// positive bignum
+					0xc2,
+					// byte string, length 17
+					0x51,
+					0x01,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				},
+				invalid: true,
+			},
+		)
+	})
+}
+
 func TestEncodeDecodeAddressValue(t *testing.T) {
 
 	t.Parallel()
@@ -3296,6 +3492,88 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 		)
 	})
 
+	t.Run("composite, struct, custom location", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := LinkValue{
+			TargetPath: publicPathValue,
+			Type: NewCompositeStaticType(
+				testCustomLocation("foo"),
+				"Foo",
+			),
+		}
+
+		//nolint:gocritic
+		encoded := append(
+			expectedLinkEncodingPrefix[:],
+			// tag
+			0xd8, CBORTagCompositeStaticType,
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, CBORTagCustomLocation,
+			// array, 2 items follow
+			0x82,
+			// UTF-8 string, length 1
+			0x61,
+			// X
+			0x58,
+			// byte string, length 3
+			0x43,
+			// f, o, o
+			0x66, 0x6f, 0x6f,
+			// UTF-8 string, length 3
+			0x63,
+			// F, o, o
+			0x46, 0x6f, 0x6f,
+		)
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value:   value,
+				encoded: encoded,
+			},
+		)
+	})
+
+	t.Run("composite, struct, unknown custom location kind", func(t *testing.T) {
+
+		t.Parallel()
+
+		//nolint:gocritic
+		encoded := append(
+			expectedLinkEncodingPrefix[:],
+			// tag
+			0xd8, CBORTagCompositeStaticType,
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, CBORTagCustomLocation,
+			// array, 2 items follow
+			0x82,
+			// UTF-8 string, length 1
+			0x61,
+			// Y
+			0x59,
+			// byte string, length 3
+			0x43,
+			// f, o, o
+			0x66, 0x6f, 0x6f,
+			// UTF-8 string, length 3
+			0x63,
+			// F, o, o
+			0x46, 0x6f, 0x6f,
+		)
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				encoded: encoded,
+				invalid: true,
+			},
+		)
+	})
+
 	t.Run("interface, struct, qualified identifier", func(t *testing.T) {
 
 		t.Parallel()
@@ -3404,7 +3682,7 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 
 		value := LinkValue{
 			TargetPath: publicPathValue,
-			Type: ReferenceStaticType{
+			Type: &ReferenceStaticType{
 				Authorized: true,
 				Type:       PrimitiveStaticTypeBool,
 			},
@@ -3438,7 +3716,7 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 
 		value := LinkValue{
 			TargetPath: publicPathValue,
-			Type: ReferenceStaticType{
+			Type: &ReferenceStaticType{
 				Authorized: false,
 				Type:       PrimitiveStaticTypeBool,
 			},
@@ -3466,6 +3744,75 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 		)
 	})
 
+	t.Run("reference type, entitled, bool", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := LinkValue{
+			TargetPath: publicPathValue,
+			Type: &ReferenceStaticType{
+				Entitlements: []EntitlementStaticType{
+					{
+						Location:            utils.TestLocation,
+						QualifiedIdentifier: "E1",
+					},
+					{
+						Location:            utils.TestLocation,
+						QualifiedIdentifier: "E2",
+					},
+				},
+				Type: PrimitiveStaticTypeBool,
+			},
+		}
+
+		//nolint:gocritic
+		encoded := append(
+			expectedLinkEncodingPrefix[:],
+			// tag
+			0xd8, CBORTagReferenceStaticType,
+			// array, 3 items follow
+			0x83,
+			// false
+			0xf4,
+			// tag
+			0xd8, CBORTagPrimitiveStaticType,
+			0x6,
+			// array, length 2
+			0x82,
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, CBORTagStringLocation,
+			// UTF-8 string, length 4
+			0x64,
+			// t, e, s, t
+			0x74, 0x65, 0x73, 0x74,
+			// UTF-8 string, length 2
+			0x62,
+			// E1
+			0x45, 0x31,
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, CBORTagStringLocation,
+			// UTF-8 string, length 4
+			0x64,
+			// t, e, s, t
+			0x74, 0x65, 0x73, 0x74,
+			// UTF-8 string, length 2
+			0x62,
+			// E2
+			0x45, 0x32,
+		)
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value:   value,
+				encoded: encoded,
+			},
+		)
+	})
+
 	t.Run("dictionary, bool, string", func(t *testing.T) {
 
 		t.Parallel()
@@ -3674,6 +4021,23 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 	})
 }
 
+func TestEncodeDecodeAccountLinkValue(t *testing.T) {
+
+	t.Parallel()
+
+	testEncodeDecode(t,
+		encodeDecodeTest{
+			value: AccountLinkValue{},
+			encoded: []byte{
+				// tag
+				0xd8, CBORTagAccountLinkValue,
+				// null
+				0xf6,
+			},
+		},
+	)
+}
+
 func TestEncodeDecodeTypeValue(t *testing.T) {
 
 	t.Parallel()
@@ -3824,3 +4188,55 @@ func TestEncodeDecodeStaticType(t *testing.T) {
 		require.Equal(t, ty, actualType)
 	})
 }
+
+const testCustomLocationPrefix = "X"
+
+// testCustomLocation is a location of a custom kind,
+// registered using common.RegisterLocationKind
+//
+type testCustomLocation string
+
+var _ common.CustomLocation = testCustomLocation("")
+
+func (l testCustomLocation) ID() common.LocationID {
+	return common.NewLocationID(testCustomLocationPrefix, string(l))
+}
+
+func (l testCustomLocation) TypeID(qualifiedIdentifier string) common.TypeID {
+	return common.NewTypeID(testCustomLocationPrefix, string(l), qualifiedIdentifier)
+}
+
+func (l testCustomLocation) QualifiedIdentifier(typeID common.TypeID) string {
+	pieces := strings.SplitN(string(typeID), ".", 3)
+	if len(pieces) < 3 {
+		return ""
+	}
+	return pieces[2]
+}
+
+func (l testCustomLocation) String() string {
+	return string(l)
+}
+
+func (l testCustomLocation) LocationKindPrefix() string {
+	return testCustomLocationPrefix
+}
+
+func init() {
+	common.RegisterLocationKind(common.LocationKind{
+		Prefix: testCustomLocationPrefix,
+		DecodeTypeID: func(typeID string) (common.Location, string, error) {
+			pieces := strings.SplitN(typeID, ".", 3)
+			if len(pieces) < 3 {
+				return nil, "", fmt.Errorf("invalid type ID: %s", typeID)
+			}
+			return testCustomLocation(pieces[1]), pieces[2], nil
+		},
+		Encode: func(location common.Location) ([]byte, error) {
+			return []byte(location.(testCustomLocation)), nil
+		},
+		Decode: func(encoded []byte) (common.Location, error) {
+			return testCustomLocation(encoded), nil
+		},
+	})
+}
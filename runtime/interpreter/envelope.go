@@ -0,0 +1,245 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/onflow/atree"
+)
+
+// Value encoding envelope
+//
+// Values encoded with EncodeValue may optionally be wrapped in a self-describing envelope,
+// so that embedders do not have to track the encoding version of stored values separately.
+//
+// The envelope is a fixed-size header, followed by the CBOR encoding of the value's storable:
+//
+//	+--------------------------+---------------------+-------------------+
+//	| format identifier (4 B)  | version (2 B, BE)   | flags (2 B, BE)   |
+//	+--------------------------+---------------------+-------------------+
+//	| CBOR encoded storable ...                                          |
+//	+--------------------------------------------------------------------+
+//
+// The format identifier is the ASCII string "CDCV".
+// Its first byte is the head of a CBOR byte string,
+// and storables are never encoded as top-level byte strings,
+// so enveloped and plain encodings can always be distinguished.
+//
+
+// ValueEncodingFormatIdentifier is the format identifier at the start of a value encoding envelope
+//
+var ValueEncodingFormatIdentifier = [4]byte{'C', 'D', 'C', 'V'}
+
+// ValueEncodingEnvelopeHeaderLength is the length of the header of a value encoding envelope
+//
+const ValueEncodingEnvelopeHeaderLength = 8
+
+// ValueEncodingVersion is the version of the value encoding
+//
+type ValueEncodingVersion uint16
+
+// !!! *WARNING* !!!
+//
+// CurrentValueEncodingVersion MUST be incremented
+// when the encoding of storables changes incompatibly.
+//
+const CurrentValueEncodingVersion ValueEncodingVersion = 1
+
+// ValueEncodingFlags are the flags of a value encoding envelope.
+// No flags are defined yet, all bits are reserved and must be zero.
+//
+type ValueEncodingFlags uint16
+
+const supportedValueEncodingFlags ValueEncodingFlags = 0
+
+// ValueEncodingHeader is the header of a value encoding envelope
+//
+type ValueEncodingHeader struct {
+	Version ValueEncodingVersion
+	Flags   ValueEncodingFlags
+}
+
+// UnsupportedValueEncodingVersionError is reported when decoding a value
+// which was encoded with a newer version than CurrentValueEncodingVersion
+//
+type UnsupportedValueEncodingVersionError struct {
+	Version ValueEncodingVersion
+}
+
+func (UnsupportedValueEncodingVersionError) IsInternalError() {}
+
+func (e UnsupportedValueEncodingVersionError) Error() string {
+	return fmt.Sprintf(
+		"unsupported value encoding version: got %d, expected at most %d",
+		e.Version,
+		CurrentValueEncodingVersion,
+	)
+}
+
+// UnsupportedValueEncodingFlagsError is reported when decoding a value
+// whose envelope has unknown flags set
+//
+type UnsupportedValueEncodingFlagsError struct {
+	Flags ValueEncodingFlags
+}
+
+func (UnsupportedValueEncodingFlagsError) IsInternalError() {}
+
+func (e UnsupportedValueEncodingFlagsError) Error() string {
+	return fmt.Sprintf(
+		"unsupported value encoding flags: %#04x",
+		uint16(e.Flags),
+	)
+}
+
+// EncodeValue encodes the given value.
+//
+// Values which are not inlined, e.g. arrays, dictionaries, and composites,
+// are stored in the given storage, and the result only references them.
+//
+// If envelope is true, the result is wrapped in a value encoding envelope
+// of the current version, see ProbeValueEncoding.
+//
+func EncodeValue(
+	value Value,
+	storage atree.SlabStorage,
+	address atree.Address,
+	envelope bool,
+) ([]byte, error) {
+
+	storable, err := value.Storable(storage, address, math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := atree.Encode(storable, CBOREncMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !envelope {
+		return encoded, nil
+	}
+
+	header := ValueEncodingHeader{
+		Version: CurrentValueEncodingVersion,
+	}
+
+	return append(header.encode(), encoded...), nil
+}
+
+// DecodeValue decodes a value encoded by EncodeValue,
+// with or without a value encoding envelope.
+//
+// Encodings without an envelope are assumed to be of the current version.
+//
+// Nested values are decoded lazily, when they are accessed.
+// Use Interpreter.CheckStoredValueDecoding to decode the value fully.
+//
+func DecodeValue(data []byte, storage atree.SlabStorage) (Value, error) {
+	_, payload, err := decodeValueEncodingEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := CBORDecMode.NewByteStreamDecoder(payload)
+
+	storable, err := DecodeStorable(decoder, atree.StorageIDUndefined)
+	if err != nil {
+		return nil, ValueDecodingError{Err: err}
+	}
+
+	value, err := storable.StoredValue(storage)
+	if err != nil {
+		decodingErr := ValueDecodingError{Err: err}
+		if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+			decodingErr.StorageID = atree.StorageID(storageIDStorable)
+		}
+		return nil, decodingErr
+	}
+
+	return ConvertStoredValue(value)
+}
+
+// ProbeValueEncoding returns the header of the value encoding envelope of the given data,
+// without decoding the value.
+//
+// The result is false if the data has no envelope.
+// An error is returned if the envelope is invalid or unsupported.
+//
+func ProbeValueEncoding(data []byte) (header ValueEncodingHeader, ok bool, err error) {
+	header, payload, err := decodeValueEncodingEnvelope(data)
+	if err != nil {
+		return ValueEncodingHeader{}, false, err
+	}
+
+	ok = len(payload) < len(data)
+	return header, ok, nil
+}
+
+// decodeValueEncodingEnvelope returns the header and the payload of the given data.
+// If the data has no envelope, the header is for the current version,
+// and the payload is the data itself
+//
+func decodeValueEncodingEnvelope(data []byte) (ValueEncodingHeader, []byte, error) {
+	if !bytes.HasPrefix(data, ValueEncodingFormatIdentifier[:]) {
+		return ValueEncodingHeader{
+			Version: CurrentValueEncodingVersion,
+		}, data, nil
+	}
+
+	if len(data) < ValueEncodingEnvelopeHeaderLength {
+		return ValueEncodingHeader{}, nil, fmt.Errorf(
+			"invalid value encoding envelope: expected %d header bytes, got %d",
+			ValueEncodingEnvelopeHeaderLength,
+			len(data),
+		)
+	}
+
+	header := ValueEncodingHeader{
+		Version: ValueEncodingVersion(binary.BigEndian.Uint16(data[4:6])),
+		Flags:   ValueEncodingFlags(binary.BigEndian.Uint16(data[6:8])),
+	}
+
+	if header.Version == 0 || header.Version > CurrentValueEncodingVersion {
+		return ValueEncodingHeader{}, nil, UnsupportedValueEncodingVersionError{
+			Version: header.Version,
+		}
+	}
+
+	if header.Flags&^supportedValueEncodingFlags != 0 {
+		return ValueEncodingHeader{}, nil, UnsupportedValueEncodingFlagsError{
+			Flags: header.Flags,
+		}
+	}
+
+	return header, data[ValueEncodingEnvelopeHeaderLength:], nil
+}
+
+func (h ValueEncodingHeader) encode() []byte {
+	result := make([]byte, ValueEncodingEnvelopeHeaderLength)
+	copy(result, ValueEncodingFormatIdentifier[:])
+	binary.BigEndian.PutUint16(result[4:6], uint16(h.Version))
+	binary.BigEndian.PutUint16(result[6:8], uint16(h.Flags))
+	return result
+}
@@ -0,0 +1,204 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	. "github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestValueEncodingEnvelope(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("with envelope", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := NewInMemoryStorage()
+
+		value := NewStringValue("test")
+
+		encoded, err := EncodeValue(value, storage, atree.Address(testOwner), true)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]byte{
+				// format identifier
+				'C', 'D', 'C', 'V',
+				// version
+				0x0, 0x1,
+				// flags
+				0x0, 0x0,
+				// tag
+				0xd8, CBORTagStringValue,
+				// UTF-8 string, 4 bytes follow
+				0x64,
+				// t, e, s, t
+				0x74, 0x65, 0x73, 0x74,
+			},
+			encoded,
+		)
+
+		header, ok, err := ProbeValueEncoding(encoded)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t,
+			ValueEncodingHeader{
+				Version: CurrentValueEncodingVersion,
+			},
+			header,
+		)
+
+		decoded, err := DecodeValue(encoded, storage)
+		require.NoError(t, err)
+
+		inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+		require.NoError(t, err)
+
+		AssertValuesEqual(t, inter, value, decoded)
+	})
+
+	t.Run("without envelope", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := NewInMemoryStorage()
+
+		value := UInt8Value(42)
+
+		encoded, err := EncodeValue(value, storage, atree.Address(testOwner), false)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]byte{
+				// tag
+				0xd8, CBORTagUInt8Value,
+				// positive integer 42
+				0x18, 0x2a,
+			},
+			encoded,
+		)
+
+		_, ok, err := ProbeValueEncoding(encoded)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		decoded, err := DecodeValue(encoded, storage)
+		require.NoError(t, err)
+
+		assert.Equal(t, value, decoded)
+	})
+
+	t.Run("container", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := NewInMemoryStorage()
+
+		inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+		require.NoError(t, err)
+
+		value := NewArrayValue(
+			inter,
+			VariableSizedStaticType{
+				Type: PrimitiveStaticTypeAnyStruct,
+			},
+			common.Address(testOwner),
+			BoolValue(true),
+			NewStringValue("test"),
+		)
+
+		encoded, err := EncodeValue(value, storage, atree.Address(testOwner), true)
+		require.NoError(t, err)
+
+		decoded, err := DecodeValue(encoded, storage)
+		require.NoError(t, err)
+
+		AssertValuesEqual(t, inter, value, decoded)
+	})
+
+	t.Run("missing slab", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := NewInMemoryStorage()
+
+		inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+		require.NoError(t, err)
+
+		value := NewArrayValue(
+			inter,
+			VariableSizedStaticType{
+				Type: PrimitiveStaticTypeAnyStruct,
+			},
+			common.Address(testOwner),
+			BoolValue(true),
+		)
+
+		encoded, err := EncodeValue(value, storage, atree.Address(testOwner), true)
+		require.NoError(t, err)
+
+		_, err = DecodeValue(encoded, NewInMemoryStorage())
+		require.Error(t, err)
+
+		var decodingErr ValueDecodingError
+		require.ErrorAs(t, err, &decodingErr)
+		assert.Equal(t, value.StorageID(), decodingErr.StorageID)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded := []byte{'C', 'D', 'C', 'V', 0xff, 0xff, 0x0, 0x0, 0xf6}
+
+		_, _, err := ProbeValueEncoding(encoded)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &UnsupportedValueEncodingVersionError{})
+
+		_, err = DecodeValue(encoded, NewInMemoryStorage())
+		require.ErrorAs(t, err, &UnsupportedValueEncodingVersionError{})
+	})
+
+	t.Run("unsupported flags", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded := []byte{'C', 'D', 'C', 'V', 0x0, 0x1, 0x0, 0x1, 0xf6}
+
+		_, err := DecodeValue(encoded, NewInMemoryStorage())
+		require.ErrorAs(t, err, &UnsupportedValueEncodingFlagsError{})
+	})
+
+	t.Run("truncated header", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, _, err := ProbeValueEncoding([]byte{'C', 'D', 'C', 'V', 0x0})
+		require.Error(t, err)
+	})
+}
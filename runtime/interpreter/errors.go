@@ -35,6 +35,8 @@ type unsupportedOperation struct {
 	ast.Range
 }
 
+func (unsupportedOperation) IsInternalError() {}
+
 func (e *unsupportedOperation) Error() string {
 	return fmt.Sprintf(
 		"cannot evaluate unsupported %s operation: %s",
@@ -98,6 +100,8 @@ type NotDeclaredError struct {
 	Name         string
 }
 
+func (NotDeclaredError) IsUserError() {}
+
 func (e NotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find %s in this scope: `%s`",
@@ -116,6 +120,8 @@ type NotInvokableError struct {
 	Value Value
 }
 
+func (NotInvokableError) IsUserError() {}
+
 func (e NotInvokableError) Error() string {
 	return fmt.Sprintf("cannot call value: %#+v", e.Value)
 }
@@ -127,6 +133,8 @@ type ArgumentCountError struct {
 	ArgumentCount  int
 }
 
+func (ArgumentCountError) IsUserError() {}
+
 func (e ArgumentCountError) Error() string {
 	return fmt.Sprintf(
 		"incorrect number of arguments: expected %d, got %d",
@@ -141,6 +149,8 @@ type TransactionNotDeclaredError struct {
 	Index int
 }
 
+func (TransactionNotDeclaredError) IsUserError() {}
+
 func (e TransactionNotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find transaction with index %d in this scope",
@@ -156,6 +166,8 @@ type ConditionError struct {
 	LocationRange
 }
 
+func (ConditionError) IsUserError() {}
+
 func (e ConditionError) Error() string {
 	if e.Message == "" {
 		return fmt.Sprintf("%s failed", e.ConditionKind.Name())
@@ -169,6 +181,8 @@ type RedeclarationError struct {
 	Name string
 }
 
+func (RedeclarationError) IsInternalError() {}
+
 func (e RedeclarationError) Error() string {
 	return fmt.Sprintf("cannot redeclare: `%s` is already declared", e.Name)
 }
@@ -179,6 +193,8 @@ type DereferenceError struct {
 	LocationRange
 }
 
+func (DereferenceError) IsUserError() {}
+
 func (e DereferenceError) Error() string {
 	return "dereference failed"
 }
@@ -191,6 +207,8 @@ func (e OverflowError) Error() string {
 	return "overflow"
 }
 
+func (OverflowError) IsUserError() {}
+
 // UnderflowError
 
 type UnderflowError struct{}
@@ -199,6 +217,8 @@ func (e UnderflowError) Error() string {
 	return "underflow"
 }
 
+func (UnderflowError) IsUserError() {}
+
 // UnderflowError
 
 type DivisionByZeroError struct{}
@@ -207,22 +227,40 @@ func (e DivisionByZeroError) Error() string {
 	return "division by zero"
 }
 
+func (DivisionByZeroError) IsUserError() {}
+
 // InvalidatedResourceError
 
 type InvalidatedResourceError struct {
 	LocationRange
 }
 
+func (InvalidatedResourceError) IsUserError() {}
+
 func (e InvalidatedResourceError) Error() string {
 	return "resource is invalidated and cannot be used anymore"
 }
 
+// InvalidatedResourceReferenceError
+
+type InvalidatedResourceReferenceError struct {
+	LocationRange
+}
+
+func (InvalidatedResourceReferenceError) IsUserError() {}
+
+func (e InvalidatedResourceReferenceError) Error() string {
+	return "referenced resource has been moved and cannot be accessed through the reference anymore"
+}
+
 // ForceAssignmentToNonNilResourceError
 //
 type ForceAssignmentToNonNilResourceError struct {
 	LocationRange
 }
 
+func (ForceAssignmentToNonNilResourceError) IsUserError() {}
+
 func (e ForceAssignmentToNonNilResourceError) Error() string {
 	return "force assignment to non-nil resource-typed value"
 }
@@ -233,6 +271,8 @@ type ForceNilError struct {
 	LocationRange
 }
 
+func (ForceNilError) IsUserError() {}
+
 func (e ForceNilError) Error() string {
 	return "unexpectedly found nil while forcing an Optional value"
 }
@@ -244,6 +284,8 @@ type ForceCastTypeMismatchError struct {
 	LocationRange
 }
 
+func (ForceCastTypeMismatchError) IsUserError() {}
+
 func (e ForceCastTypeMismatchError) Error() string {
 	return fmt.Sprintf(
 		"unexpectedly found non-`%s` while force-casting value",
@@ -258,6 +300,8 @@ type TypeMismatchError struct {
 	LocationRange
 }
 
+func (TypeMismatchError) IsUserError() {}
+
 func (e TypeMismatchError) Error() string {
 	return fmt.Sprintf(
 		"type mismatch: expected %s",
@@ -273,6 +317,8 @@ type InvalidPathDomainError struct {
 	LocationRange
 }
 
+func (InvalidPathDomainError) IsUserError() {}
+
 func (e InvalidPathDomainError) Error() string {
 	return "invalid path domain"
 }
@@ -300,6 +346,8 @@ type OverwriteError struct {
 	LocationRange
 }
 
+func (OverwriteError) IsUserError() {}
+
 func (e OverwriteError) Error() string {
 	return fmt.Sprintf(
 		"failed to save object: path %s in account %s already stores an object",
@@ -316,6 +364,8 @@ type CyclicLinkError struct {
 	LocationRange
 }
 
+func (CyclicLinkError) IsUserError() {}
+
 func (e CyclicLinkError) Error() string {
 	var builder strings.Builder
 	for i, path := range e.Paths {
@@ -333,6 +383,69 @@ func (e CyclicLinkError) Error() string {
 	)
 }
 
+// AccountLinkingNotAllowedError
+//
+type AccountLinkingNotAllowedError struct {
+	Address common.Address
+	LocationRange
+}
+
+func (AccountLinkingNotAllowedError) IsUserError() {}
+
+func (e AccountLinkingNotAllowedError) Error() string {
+	return fmt.Sprintf(
+		"account linking is not allowed for account %s",
+		e.Address.ShortHexWithPrefix(),
+	)
+}
+
+// OwnerMismatchError is reported when owner validation is enabled,
+// and a value written to storage, or a value nested in it,
+// is not owned by the account it is written to.
+//
+// Path is the path to the offending value, relative to the written value.
+//
+type OwnerMismatchError struct {
+	Path          string
+	ExpectedOwner common.Address
+	ActualOwner   common.Address
+}
+
+func (OwnerMismatchError) IsUserError() {}
+
+func (e OwnerMismatchError) Error() string {
+	return fmt.Sprintf(
+		"invalid owner of value at %s: expected %s, got %s",
+		e.Path,
+		e.ExpectedOwner.ShortHexWithPrefix(),
+		e.ActualOwner.ShortHexWithPrefix(),
+	)
+}
+
+// InvalidLinkTargetError is reported when a link is created,
+// but the value already stored at the target path cannot be borrowed
+// with the borrow type of the link.
+//
+type InvalidLinkTargetError struct {
+	Address    common.Address
+	TargetPath PathValue
+	TargetType StaticType
+	BorrowType StaticType
+	LocationRange
+}
+
+func (InvalidLinkTargetError) IsUserError() {}
+
+func (e InvalidLinkTargetError) Error() string {
+	return fmt.Sprintf(
+		"failed to link: value of type `%s` at path %s in account %s cannot be borrowed as `%s`",
+		e.TargetType,
+		e.TargetPath,
+		e.Address.ShortHexWithPrefix(),
+		e.BorrowType,
+	)
+}
+
 // ArrayIndexOutOfBoundsError
 //
 type ArrayIndexOutOfBoundsError struct {
@@ -341,6 +454,8 @@ type ArrayIndexOutOfBoundsError struct {
 	LocationRange
 }
 
+func (ArrayIndexOutOfBoundsError) IsUserError() {}
+
 func (e ArrayIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"array index out of bounds: %d, but size is %d",
@@ -357,6 +472,8 @@ type StringIndexOutOfBoundsError struct {
 	LocationRange
 }
 
+func (StringIndexOutOfBoundsError) IsUserError() {}
+
 func (e StringIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"string index out of bounds: %d, but length is %d",
@@ -371,6 +488,8 @@ type EventEmissionUnavailableError struct {
 	LocationRange
 }
 
+func (EventEmissionUnavailableError) IsInternalError() {}
+
 func (e EventEmissionUnavailableError) Error() string {
 	return "cannot emit event: unavailable"
 }
@@ -381,6 +500,8 @@ type UUIDUnavailableError struct {
 	LocationRange
 }
 
+func (UUIDUnavailableError) IsInternalError() {}
+
 func (e UUIDUnavailableError) Error() string {
 	return "cannot get UUID: unavailable"
 }
@@ -391,6 +512,8 @@ type TypeLoadingError struct {
 	TypeID common.TypeID
 }
 
+func (TypeLoadingError) IsInternalError() {}
+
 func (e TypeLoadingError) Error() string {
 	return fmt.Sprintf("failed to load type: %s", e.TypeID)
 }
@@ -402,6 +525,8 @@ type MissingMemberValueError struct {
 	LocationRange
 }
 
+func (MissingMemberValueError) IsInternalError() {}
+
 func (e MissingMemberValueError) Error() string {
 	return fmt.Sprintf("missing value for member `%s`", e.Name)
 }
@@ -414,6 +539,8 @@ type InvocationArgumentTypeError struct {
 	LocationRange
 }
 
+func (InvocationArgumentTypeError) IsUserError() {}
+
 func (e InvocationArgumentTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid invocation with argument at index %d: expected %s",
@@ -430,6 +557,8 @@ type InvocationReceiverTypeError struct {
 	LocationRange
 }
 
+func (InvocationReceiverTypeError) IsInternalError() {}
+
 func (e InvocationReceiverTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid invocation on %s: expected %s",
@@ -445,6 +574,8 @@ type ValueTransferTypeError struct {
 	LocationRange
 }
 
+func (ValueTransferTypeError) IsInternalError() {}
+
 func (e ValueTransferTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid transfer of value: expected %s",
@@ -459,6 +590,8 @@ type ResourceConstructionError struct {
 	LocationRange
 }
 
+func (ResourceConstructionError) IsUserError() {}
+
 func (e ResourceConstructionError) Error() string {
 	return fmt.Sprintf(
 		"cannot create resource %s: outside of declaring location %s",
@@ -475,6 +608,8 @@ type ContainerMutationError struct {
 	LocationRange
 }
 
+func (ContainerMutationError) IsUserError() {}
+
 func (e ContainerMutationError) Error() string {
 	return fmt.Sprintf(
 		"invalid container update: expected a subtype of '%s', found '%s'",
@@ -483,12 +618,26 @@ func (e ContainerMutationError) Error() string {
 	)
 }
 
+// ReadOnlyValueMutationError
+//
+type ReadOnlyValueMutationError struct {
+	LocationRange
+}
+
+func (ReadOnlyValueMutationError) IsUserError() {}
+
+func (e ReadOnlyValueMutationError) Error() string {
+	return "cannot mutate or move read-only value"
+}
+
 // NonStorableValueError
 //
 type NonStorableValueError struct {
 	Value Value
 }
 
+func (NonStorableValueError) IsUserError() {}
+
 func (e NonStorableValueError) Error() string {
 	return fmt.Sprintf(
 		"cannot store non-storable value: %s",
@@ -502,6 +651,8 @@ type NonStorableStaticTypeError struct {
 	Type StaticType
 }
 
+func (NonStorableStaticTypeError) IsUserError() {}
+
 func (e NonStorableStaticTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot store non-storable static type: %s",
@@ -515,6 +666,8 @@ type InterfaceMissingLocationError struct {
 	QualifiedIdentifier string
 }
 
+func (InterfaceMissingLocationError) IsInternalError() {}
+
 func (e *InterfaceMissingLocationError) Error() string {
 	return fmt.Sprintf(
 		"tried to look up interface %s without a location",
@@ -522,6 +675,21 @@ func (e *InterfaceMissingLocationError) Error() string {
 	)
 }
 
+// EntitlementMissingLocationError is reported during entitlement lookup,
+// if an entitlement is looked up without a location
+type EntitlementMissingLocationError struct {
+	QualifiedIdentifier string
+}
+
+func (EntitlementMissingLocationError) IsInternalError() {}
+
+func (e *EntitlementMissingLocationError) Error() string {
+	return fmt.Sprintf(
+		"tried to look up entitlement %s without a location",
+		e.QualifiedIdentifier,
+	)
+}
+
 // InvalidOperandsError
 //
 type InvalidOperandsError struct {
@@ -532,6 +700,8 @@ type InvalidOperandsError struct {
 	LocationRange
 }
 
+func (InvalidOperandsError) IsInternalError() {}
+
 func (e InvalidOperandsError) Error() string {
 	var op string
 	if e.Operation == ast.OperationUnknown {
@@ -547,3 +717,19 @@ func (e InvalidOperandsError) Error() string {
 		e.RightType.String(),
 	)
 }
+
+// ReentrancyError
+//
+type ReentrancyError struct {
+	Location common.Location
+	LocationRange
+}
+
+func (ReentrancyError) IsUserError() {}
+
+func (e ReentrancyError) Error() string {
+	return fmt.Sprintf(
+		"re-entrant call into contract %s",
+		e.Location,
+	)
+}
@@ -0,0 +1,117 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// GlobalDeclaration is a global declaration of a program and its current value,
+// e.g. a variable, a function, or a contract.
+//
+// It allows embedders like debuggers and the REPL to inspect the state of an interpreter,
+// see Interpreter.GlobalDeclarations
+//
+type GlobalDeclaration struct {
+	Name            string
+	DeclarationKind common.DeclarationKind
+	// Type is the type of the declaration, as determined by the checker
+	Type     sema.Type
+	variable *Variable
+}
+
+// Value returns the current value of the declaration.
+//
+// Values of lazily initialized declarations, e.g. contracts, are initialized on first access
+//
+func (d GlobalDeclaration) Value() Value {
+	return d.variable.GetValue()
+}
+
+// GlobalDeclarations returns the global declarations of the program with the given location,
+// in the order they were declared. Predeclared values are not included.
+//
+// It returns false if no program with the given location was loaded by the interpreter,
+// or any of the interpreters it loaded programs with
+//
+func (interpreter *Interpreter) GlobalDeclarations(location common.Location) ([]GlobalDeclaration, bool) {
+	inter := interpreter.allInterpreters[location.ID()]
+	if inter == nil || inter.Program == nil {
+		return nil, false
+	}
+
+	elaboration := inter.Program.Elaboration
+
+	var declarations []GlobalDeclaration
+
+	elaboration.GlobalValues.Foreach(func(name string, semaVariable *sema.Variable) {
+		if _, ok := elaboration.EffectivePredeclaredValues[name]; ok {
+			return
+		}
+
+		variable, ok := inter.Globals.Get(name)
+		if !ok {
+			// Declarations are not necessarily global variables,
+			// e.g. declarations in the REPL are declared in the base activation
+			variable = inter.findVariable(name)
+			if variable == nil {
+				return
+			}
+		}
+
+		declarations = append(
+			declarations,
+			GlobalDeclaration{
+				Name:            name,
+				DeclarationKind: semaVariable.DeclarationKind,
+				Type:            semaVariable.Type,
+				variable:        variable,
+			},
+		)
+	})
+
+	return declarations, true
+}
+
+// ContractDeclarations returns the global declarations of the contracts
+// declared by the program with the given location, in the order they were declared.
+// The value of each declaration is the contract value.
+//
+// It returns false if no program with the given location was loaded,
+// see GlobalDeclarations
+//
+func (interpreter *Interpreter) ContractDeclarations(location common.Location) ([]GlobalDeclaration, bool) {
+	declarations, ok := interpreter.GlobalDeclarations(location)
+	if !ok {
+		return nil, false
+	}
+
+	var contractDeclarations []GlobalDeclaration
+
+	for _, declaration := range declarations {
+		if declaration.DeclarationKind != common.DeclarationKindContract {
+			continue
+		}
+
+		contractDeclarations = append(contractDeclarations, declaration)
+	}
+
+	return contractDeclarations, true
+}
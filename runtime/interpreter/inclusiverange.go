@@ -0,0 +1,150 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// InclusiveRange
+
+var inclusiveRangeFieldNames = []string{
+	sema.InclusiveRangeTypeStartFieldName,
+	sema.InclusiveRangeTypeEndFieldName,
+	sema.InclusiveRangeTypeIsEmptyFieldName,
+}
+
+// NewInclusiveRangeValue returns a new range value,
+// from the given start value up to and including the given end value.
+// The elements of the range are never materialized.
+//
+func NewInclusiveRangeValue(
+	start IntegerValue,
+	end IntegerValue,
+	isEmpty BoolValue,
+	rangeType *sema.InclusiveRangeType,
+) *SimpleCompositeValue {
+
+	containsFunction := NewHostFunctionValue(
+		func(invocation Invocation) Value {
+			element := invocation.Arguments[0].(IntegerValue)
+			if isEmpty {
+				return BoolValue(false)
+			}
+			return start.LessEqual(element) && element.LessEqual(end)
+		},
+		sema.InclusiveRangeContainsFunctionType(rangeType.MemberType),
+	)
+
+	return NewSimpleCompositeValue(
+		rangeType.ID(),
+		ConvertSemaToStaticType(rangeType),
+		InclusiveRangeDynamicType{
+			StaticType: rangeType,
+		},
+		inclusiveRangeFieldNames,
+		map[string]Value{
+			sema.InclusiveRangeTypeStartFieldName:       start,
+			sema.InclusiveRangeTypeEndFieldName:         end,
+			sema.InclusiveRangeTypeIsEmptyFieldName:     isEmpty,
+			sema.InclusiveRangeTypeContainsFunctionName: containsFunction,
+		},
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// newRangeValue returns the range value for the range operation,
+// i.e. `start...end` (inclusive) or `start..<end` (exclusive).
+//
+// An exclusive range is represented as an inclusive range
+// which ends at the element before the given end value.
+//
+func (interpreter *Interpreter) newRangeValue(
+	start IntegerValue,
+	end IntegerValue,
+	exclusive bool,
+	rangeType *sema.InclusiveRangeType,
+) *SimpleCompositeValue {
+
+	if !exclusive {
+		return NewInclusiveRangeValue(start, end, start.Greater(end), rangeType)
+	}
+
+	if start.GreaterEqual(end) {
+		return NewInclusiveRangeValue(start, start, true, rangeType)
+	}
+
+	// The end is greater than the start,
+	// so the subtraction cannot underflow
+
+	one := interpreter.integerOne(end)
+
+	return NewInclusiveRangeValue(
+		start,
+		end.Minus(one).(IntegerValue),
+		false,
+		rangeType,
+	)
+}
+
+// integerOne returns the value 1, with the same type as the given integer value
+//
+func (interpreter *Interpreter) integerOne(value IntegerValue) IntegerValue {
+	integerType := interpreter.MustConvertStaticToSemaType(value.StaticType())
+
+	return interpreter.convert(
+		NewIntValueFromInt64(1),
+		sema.IntType,
+		integerType,
+	).(IntegerValue)
+}
+
+// forEachRangeElement calls the given function for each element of the given range value,
+// in ascending order, until the function returns false.
+//
+func (interpreter *Interpreter) forEachRangeElement(
+	rangeValue *SimpleCompositeValue,
+	f func(element IntegerValue) bool,
+) {
+	if rangeValue.Fields[sema.InclusiveRangeTypeIsEmptyFieldName].(BoolValue) {
+		return
+	}
+
+	current := rangeValue.Fields[sema.InclusiveRangeTypeStartFieldName].(IntegerValue)
+	end := rangeValue.Fields[sema.InclusiveRangeTypeEndFieldName].(IntegerValue)
+
+	one := interpreter.integerOne(current)
+
+	for {
+		if !f(current) {
+			return
+		}
+
+		// Check before incrementing, so the increment cannot overflow
+		// if the range ends at the maximum value of the integer type
+
+		if current.GreaterEqual(end) {
+			return
+		}
+
+		current = current.Plus(one).(IntegerValue)
+	}
+}
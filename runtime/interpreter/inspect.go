@@ -31,3 +31,10 @@ func (f valueInspector) WalkValue(value Value) ValueWalker {
 func InspectValue(value Value, f func(Value) bool) {
 	WalkValue(valueInspector(f), value)
 }
+
+// InspectValueWithOptions calls the given function for each value of the given value graph,
+// like InspectValue, but allows configuring which content is traversed.
+//
+func InspectValueWithOptions(interpreter *Interpreter, value Value, options WalkOptions, f func(Value) bool) {
+	WalkValueWithOptions(interpreter, valueInspector(f), value, options)
+}
@@ -138,3 +138,130 @@ func TestInspectValue(t *testing.T) {
 		)
 	})
 }
+
+func TestInspectValueWithOptions(t *testing.T) {
+
+	t.Parallel()
+
+	inter := newTestInterpreter(t)
+
+	owner := common.Address{0x1}
+
+	innerArrayValue := NewArrayValue(
+		inter,
+		VariableSizedStaticType{
+			Type: PrimitiveStaticTypeInt,
+		},
+		owner,
+		NewIntValueFromInt64(1),
+	)
+
+	outerArrayValue := NewArrayValue(
+		inter,
+		VariableSizedStaticType{
+			Type: VariableSizedStaticType{
+				Type: PrimitiveStaticTypeInt,
+			},
+		},
+		owner,
+		innerArrayValue,
+	)
+
+	// Get actually stored values.
+	// The inner array was transferred when it was inserted into the outer array.
+
+	storedInnerArrayValue := outerArrayValue.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue)
+	storedElementValue := storedInnerArrayValue.Get(inter, ReturnEmptyLocationRange, 0)
+
+	inspect := func(value Value, options WalkOptions) []Value {
+		var inspectedValues []Value
+
+		InspectValueWithOptions(
+			inter,
+			value,
+			options,
+			func(value Value) bool {
+				inspectedValues = append(inspectedValues, value)
+				return true
+			},
+		)
+
+		return inspectedValues
+	}
+
+	t.Run("all contents", func(t *testing.T) {
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]Value{
+				outerArrayValue,
+				storedInnerArrayValue,
+				storedElementValue,
+				nil, // end element
+				nil, // end inner array
+				nil, // end outer array
+			},
+			inspect(outerArrayValue, WalkOptions{}),
+		)
+	})
+
+	t.Run("skip stored contents", func(t *testing.T) {
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]Value{
+				outerArrayValue,
+				storedInnerArrayValue,
+				nil, // end inner array
+				nil, // end outer array
+			},
+			inspect(
+				outerArrayValue,
+				WalkOptions{
+					SkipStoredContents: true,
+				},
+			),
+		)
+	})
+
+	referenceValue := &EphemeralReferenceValue{
+		Value: storedInnerArrayValue,
+	}
+
+	t.Run("references not followed", func(t *testing.T) {
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]Value{
+				referenceValue,
+				nil, // end reference
+			},
+			inspect(referenceValue, WalkOptions{}),
+		)
+	})
+
+	t.Run("references followed", func(t *testing.T) {
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]Value{
+				referenceValue,
+				storedInnerArrayValue,
+				storedElementValue,
+				nil, // end element
+				nil, // end inner array
+				nil, // end reference
+			},
+			inspect(
+				referenceValue,
+				WalkOptions{
+					FollowReferences: true,
+				},
+			),
+		)
+	})
+}
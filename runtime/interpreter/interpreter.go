@@ -95,25 +95,61 @@ type OnStatementFunc func(
 	statement ast.Statement,
 )
 
+// LoopIteration describes a loop iteration that is about to be executed.
+//
+type LoopIteration struct {
+	// Statement is the loop statement.
+	// It is nil if the loop is not executed by the interpreter, e.g. by the VM
+	Statement ast.Statement
+	Line      int
+}
+
 // OnLoopIterationFunc is a function that is triggered when a loop iteration is about to be executed.
 //
 type OnLoopIterationFunc func(
 	inter *Interpreter,
-	line int,
+	iteration LoopIteration,
 )
 
+// FunctionInvocation describes a function invocation.
+//
+// The function, arguments, and location range are only available
+// if the function is invoked by the interpreter, and not e.g. by the VM.
+//
+type FunctionInvocation struct {
+	Function      FunctionValue
+	Arguments     []Value
+	LocationRange LocationRange
+	Line          int
+}
+
 // OnFunctionInvocationFunc is a function that is triggered when a function is about to be invoked.
 //
+// The arguments must not be mutated.
+//
 type OnFunctionInvocationFunc func(
 	inter *Interpreter,
-	line int,
+	invocation FunctionInvocation,
 )
 
 // OnInvokedFunctionReturnFunc is a function that is triggered when an invoked function returned.
 //
+// The result is nil if the function is not invoked by the interpreter, e.g. by the VM.
+// The result must not be mutated.
+//
 type OnInvokedFunctionReturnFunc func(
 	inter *Interpreter,
-	line int,
+	invocation FunctionInvocation,
+	result Value,
+)
+
+// OnBigIntOperationFunc is a function that is triggered when an arithmetic, bitwise,
+// or comparison operation is about to be performed on integers of arbitrary or large size,
+// i.e. integers which are represented as big integers, e.g. Int, UInt256, and Fix128.
+//
+type OnBigIntOperationFunc func(
+	inter *Interpreter,
+	operation ast.Operation,
 )
 
 // OnRecordTraceFunc is a function thats records a trace.
@@ -132,6 +168,26 @@ type OnResourceOwnerChangeFunc func(
 	newOwner common.Address,
 )
 
+// OnAccountLinkedFunc is a function that is triggered when an account is linked,
+// i.e. when a capability targeting the account is created at the given path.
+//
+type OnAccountLinkedFunc func(
+	inter *Interpreter,
+	address AddressValue,
+	path PathValue,
+)
+
+// MemoizedFunctionHandlerFunc is a function that handles invocations of memoized contract functions,
+// see sema.MemoizePragma. The handler may return a cached result for the function,
+// or it may call compute to evaluate the function.
+//
+type MemoizedFunctionHandlerFunc func(
+	inter *Interpreter,
+	location common.Location,
+	qualifiedName string,
+	compute func() Value,
+) Value
+
 // InjectedCompositeFieldsHandlerFunc is a function that handles storage reads.
 //
 type InjectedCompositeFieldsHandlerFunc func(
@@ -165,9 +221,33 @@ type PublicAccountHandlerFunc func(
 	address AddressValue,
 ) Value
 
+// AuthAccountHandlerFunc is a function that handles retrieving an auth account at a given address.
+// The account returned must be of type `AuthAccount`.
+//
+type AuthAccountHandlerFunc func(
+	inter *Interpreter,
+	address AddressValue,
+) Value
+
+// AccountLinkingAllowedHandlerFunc is a function that determines
+// if the account at the given address may be linked.
+//
+type AccountLinkingAllowedHandlerFunc func(
+	inter *Interpreter,
+	address AddressValue,
+) bool
+
 // UUIDHandlerFunc is a function that handles the generation of UUIDs.
 type UUIDHandlerFunc func() (uint64, error)
 
+// SharedStateHandlerFunc is a function that returns the read-only view of the value
+// stored at the given path in the given account, or nil if no value is stored.
+//
+// If the interpreter has a shared state handler, all stored values are read through it,
+// and stored values cannot be written, see WithSharedStateHandler
+//
+type SharedStateHandlerFunc func(address common.Address, key common.StorageKey) *ReadOnlyValue
+
 // PublicKeyValidationHandlerFunc is a function that validates a given public key.
 type PublicKeyValidationHandlerFunc func(
 	interpreter *Interpreter,
@@ -229,7 +309,17 @@ type ExitHandlerFunc func() error
 //
 type CompositeTypeCode struct {
 	CompositeFunctions map[string]FunctionValue
+	// MemberSlots is the member dispatch table of the composite type,
+	// see sema.MemberSlots
+	MemberSlots *sema.MemberSlots
+	// FunctionSlots are the composite functions,
+	// indexed by their slot in MemberSlots
+	FunctionSlots      []FunctionValue
 	DestructorFunction FunctionValue
+	// ConstantFields are the values of the constant fields of a contract, e.g. `let x: Int = 1`,
+	// which are evaluated by the checker. They are stored in the contract value when it is constructed,
+	// and shared by all accesses
+	ConstantFields map[string]Value
 }
 
 type FunctionWrapper = func(inner FunctionValue) FunctionValue
@@ -275,7 +365,10 @@ func (c TypeCodes) Merge(codes TypeCodes) {
 
 type Storage interface {
 	atree.SlabStorage
-	GetStorageMap(address common.Address, domain string) *StorageMap
+	// GetStorageMap returns the storage map of the given domain of the given account.
+	// If the storage map does not exist yet, it is created if createIfNotExists is true,
+	// otherwise nil is returned
+	GetStorageMap(address common.Address, domain string, createIfNotExists bool) *StorageMap
 	CheckHealth() error
 }
 
@@ -288,6 +381,12 @@ type Interpreter struct {
 	Globals                        GlobalVariables
 	allInterpreters                map[common.LocationID]*Interpreter
 	typeCodes                      TypeCodes
+	copyOnWriteShares              *copyOnWriteShares
+	referencedResourceKindedValues referencedResourceKindedValues
+	transientStorage               transientStorage
+	reentrancyTracker              *reentrancyTracker
+	reentrancyHandling             ReentrancyHandling
+	safeCallTracker                *safeCallTracker
 	Transactions                   []*HostFunctionValue
 	Storage                        Storage
 	onEventEmitted                 OnEventEmittedFunc
@@ -295,12 +394,20 @@ type Interpreter struct {
 	onLoopIteration                OnLoopIterationFunc
 	onFunctionInvocation           OnFunctionInvocationFunc
 	onInvokedFunctionReturn        OnInvokedFunctionReturnFunc
+	onBigIntOperation              OnBigIntOperationFunc
 	onRecordTrace                  OnRecordTraceFunc
 	onResourceOwnerChange          OnResourceOwnerChangeFunc
+	onAccountLinked                OnAccountLinkedFunc
+	memoizedFunctionHandler        MemoizedFunctionHandlerFunc
+	typeLoader                     TypeLoader
+	onYield                        OnYieldFunc
+	callStack                      *CallStack
 	injectedCompositeFieldsHandler InjectedCompositeFieldsHandlerFunc
 	contractValueHandler           ContractValueHandlerFunc
 	importLocationHandler          ImportLocationHandlerFunc
 	publicAccountHandler           PublicAccountHandlerFunc
+	authAccountHandler             AuthAccountHandlerFunc
+	accountLinkingAllowedHandler   AccountLinkingAllowedHandlerFunc
 	uuidHandler                    UUIDHandlerFunc
 	PublicKeyValidationHandler     PublicKeyValidationHandlerFunc
 	SignatureVerificationHandler   SignatureVerificationHandlerFunc
@@ -314,7 +421,15 @@ type Interpreter struct {
 	debugger                       *Debugger
 	atreeValueValidationEnabled    bool
 	atreeStorageValidationEnabled  bool
+	ownerValidationEnabled         bool
+	linkValidationEnabled          bool
+	referenceInvalidationEnabled   bool
+	copyOnWriteEnabled             bool
 	tracingEnabled                 bool
+	mutationJournal                *MutationJournal
+	sharedStateHandler             SharedStateHandlerFunc
+	typeFingerprintsEnabled        bool
+	valueArena                     *valueArena
 }
 
 type Option func(*Interpreter) error
@@ -369,6 +484,26 @@ func WithOnInvokedFunctionReturnHandler(handler OnInvokedFunctionReturnFunc) Opt
 	}
 }
 
+// WithOnBigIntOperationHandler returns an interpreter option which sets
+// the given function as the big integer operation handler.
+//
+func WithOnBigIntOperationHandler(handler OnBigIntOperationFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOnBigIntOperationHandler(handler)
+		return nil
+	}
+}
+
+// WithOnYieldHandler returns an interpreter option which sets
+// the given function as the yield handler, see OnYieldFunc.
+//
+func WithOnYieldHandler(handler OnYieldFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOnYieldHandler(handler)
+		return nil
+	}
+}
+
 // WithOnRecordTraceHandler returns an interpreter option which sets
 // the given function as the record trace handler.
 //
@@ -389,6 +524,46 @@ func WithOnResourceOwnerChangeHandler(handler OnResourceOwnerChangeFunc) Option
 	}
 }
 
+// WithMemoizedFunctionHandler returns an interpreter option which sets
+// the given function as the memoized function handler, see MemoizedFunctionHandlerFunc.
+//
+func WithMemoizedFunctionHandler(handler MemoizedFunctionHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetMemoizedFunctionHandler(handler)
+		return nil
+	}
+}
+
+// WithTypeLoader returns an interpreter option which sets
+// the given type loader, which is used to resolve the types of decoded values, see TypeLoader.
+//
+func WithTypeLoader(loader TypeLoader) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetTypeLoader(loader)
+		return nil
+	}
+}
+
+// WithSharedStateHandler returns an interpreter option which sets
+// the given function as the shared state handler, see SharedStateHandlerFunc.
+//
+func WithSharedStateHandler(handler SharedStateHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.sharedStateHandler = handler
+		return nil
+	}
+}
+
+// WithOnAccountLinkedHandler returns an interpreter option which sets
+// the given function as the account linked handler.
+//
+func WithOnAccountLinkedHandler(handler OnAccountLinkedFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOnAccountLinkedHandler(handler)
+		return nil
+	}
+}
+
 // WithPredeclaredValues returns an interpreter option which declares
 // the given the predeclared values.
 //
@@ -460,6 +635,26 @@ func WithPublicAccountHandler(handler PublicAccountHandlerFunc) Option {
 	}
 }
 
+// WithAuthAccountHandler returns an interpreter option which sets the given function
+// as the function that is used to handle auth accounts.
+//
+func WithAuthAccountHandler(handler AuthAccountHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetAuthAccountHandler(handler)
+		return nil
+	}
+}
+
+// WithAccountLinkingAllowedHandler returns an interpreter option which sets the given function
+// as the function that is used to determine if an account may be linked.
+//
+func WithAccountLinkingAllowedHandler(handler AccountLinkingAllowedHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetAccountLinkingAllowedHandler(handler)
+		return nil
+	}
+}
+
 // WithUUIDHandler returns an interpreter option which sets the given function
 // as the function that is used to generate UUIDs.
 //
@@ -538,6 +733,16 @@ func WithAllInterpreters(allInterpreters map[common.LocationID]*Interpreter) Opt
 	}
 }
 
+// WithCallStack returns an interpreter option which sets
+// the call stack that records the function invocations of the execution.
+//
+func WithCallStack(callStack *CallStack) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetCallStack(callStack)
+		return nil
+	}
+}
+
 // WithAtreeValueValidationEnabled returns an interpreter option which sets
 // the atree validation option.
 //
@@ -558,6 +763,63 @@ func WithAtreeStorageValidationEnabled(enabled bool) Option {
 	}
 }
 
+// WithOwnerValidationEnabled returns an interpreter option which sets
+// the owner validation option.
+//
+// When enabled, every write to storage validates that the written value
+// and all values nested in it are owned by the account they are written to,
+// see OwnerMismatchError.
+//
+func WithOwnerValidationEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOwnerValidationEnabled(enabled)
+		return nil
+	}
+}
+
+// WithLinkValidationEnabled returns an interpreter option which sets
+// the link validation option.
+//
+// When enabled, creating a link validates that the value stored at the target path, if any,
+// can be borrowed with the borrow type of the link, see InvalidLinkTargetError.
+//
+func WithLinkValidationEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetLinkValidationEnabled(enabled)
+		return nil
+	}
+}
+
+// WithCopyOnWriteEnabled returns an interpreter option which sets
+// the copy-on-write option.
+//
+// When enabled, arrays and dictionaries which are transferred to a local destination
+// are copied lazily, see copyOnWriteValue. The option is enabled by default.
+//
+// Lazy copies allocate fewer temporary slabs than eager copies,
+// so the storage IDs of temporary containers, and the seeds of dictionaries derived from them, differ.
+// The stored dictionaries and their iteration order therefore depend on the option.
+//
+func WithCopyOnWriteEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetCopyOnWriteEnabled(enabled)
+		return nil
+	}
+}
+
+// WithReferenceInvalidationEnabled returns an interpreter option which sets
+// the reference invalidation option.
+//
+// When enabled, moving a resource invalidates all ephemeral references to it,
+// see InvalidatedResourceReferenceError. The option is enabled by default.
+//
+func WithReferenceInvalidationEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetReferenceInvalidationEnabled(enabled)
+		return nil
+	}
+}
+
 // WithTracingEnabled returns an interpreter option which sets
 // the tracing option.
 //
@@ -568,6 +830,26 @@ func WithTracingEnabled(enabled bool) Option {
 	}
 }
 
+// withCopyOnWriteShares returns an interpreter option which sets
+// the headers of containers which are shared by lazy copies.
+//
+func withCopyOnWriteShares(shares *copyOnWriteShares) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.copyOnWriteShares = shares
+		return nil
+	}
+}
+
+// withReferencedResourceKindedValues returns an interpreter option which sets
+// the references to resource-kinded values.
+//
+func withReferencedResourceKindedValues(values referencedResourceKindedValues) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.referencedResourceKindedValues = values
+		return nil
+	}
+}
+
 // withTypeCodes returns an interpreter option which sets the type codes.
 //
 func withTypeCodes(typeCodes TypeCodes) Option {
@@ -615,6 +897,13 @@ func NewInterpreter(program *Program, location common.Location, options ...Optio
 			InterfaceCodes:       map[sema.TypeID]WrapperCode{},
 			TypeRequirementCodes: map[sema.TypeID]WrapperCode{},
 		}),
+		withCopyOnWriteShares(&copyOnWriteShares{}),
+		withReferencedResourceKindedValues(referencedResourceKindedValues{}),
+		WithReferenceInvalidationEnabled(true),
+		WithCopyOnWriteEnabled(true),
+		withTransientStorage(transientStorage{}),
+		withReentrancyTracker(newReentrancyTracker()),
+		withSafeCallTracker(&safeCallTracker{}),
 	}
 
 	for _, option := range defaultOptions {
@@ -664,6 +953,19 @@ func (interpreter *Interpreter) SetOnInvokedFunctionReturnHandler(function OnInv
 	interpreter.onInvokedFunctionReturn = function
 }
 
+// SetOnBigIntOperationHandler sets the function that is triggered when an operation
+// on big integers is about to be performed.
+//
+func (interpreter *Interpreter) SetOnBigIntOperationHandler(function OnBigIntOperationFunc) {
+	interpreter.onBigIntOperation = function
+}
+
+// SetOnYieldHandler sets the function that is triggered at the yield points of the execution.
+//
+func (interpreter *Interpreter) SetOnYieldHandler(function OnYieldFunc) {
+	interpreter.onYield = function
+}
+
 // SetOnRecordTraceHandler sets the function that is triggered when a trace is recorded.
 //
 func (interpreter *Interpreter) SetOnRecordTraceHandler(function OnRecordTraceFunc) {
@@ -676,6 +978,24 @@ func (interpreter *Interpreter) SetOnResourceOwnerChangeHandler(function OnResou
 	interpreter.onResourceOwnerChange = function
 }
 
+// SetOnAccountLinkedHandler sets the function that is triggered when an account is linked.
+//
+func (interpreter *Interpreter) SetOnAccountLinkedHandler(function OnAccountLinkedFunc) {
+	interpreter.onAccountLinked = function
+}
+
+// SetMemoizedFunctionHandler sets the function that handles invocations of memoized contract functions.
+//
+func (interpreter *Interpreter) SetMemoizedFunctionHandler(function MemoizedFunctionHandlerFunc) {
+	interpreter.memoizedFunctionHandler = function
+}
+
+// SetTypeLoader sets the type loader that is used to resolve the types of decoded values.
+//
+func (interpreter *Interpreter) SetTypeLoader(loader TypeLoader) {
+	interpreter.typeLoader = loader
+}
+
 // SetStorage sets the value that is used for storage operations.
 func (interpreter *Interpreter) SetStorage(storage Storage) {
 	interpreter.Storage = storage
@@ -706,6 +1026,19 @@ func (interpreter *Interpreter) SetPublicAccountHandler(function PublicAccountHa
 	interpreter.publicAccountHandler = function
 }
 
+// SetAuthAccountHandler sets the function that is used to handle auth accounts.
+//
+func (interpreter *Interpreter) SetAuthAccountHandler(function AuthAccountHandlerFunc) {
+	interpreter.authAccountHandler = function
+}
+
+// SetAccountLinkingAllowedHandler sets the function that is used to determine
+// if an account may be linked.
+//
+func (interpreter *Interpreter) SetAccountLinkingAllowedHandler(function AccountLinkingAllowedHandlerFunc) {
+	interpreter.accountLinkingAllowedHandler = function
+}
+
 // SetUUIDHandler sets the function that is used to handle the generation of UUIDs.
 //
 func (interpreter *Interpreter) SetUUIDHandler(function UUIDHandlerFunc) {
@@ -748,6 +1081,12 @@ func (interpreter *Interpreter) SetExitHandler(function ExitHandlerFunc) {
 	interpreter.ExitHandler = function
 }
 
+// SetCallStack sets the call stack that records the function invocations of the execution.
+//
+func (interpreter *Interpreter) SetCallStack(callStack *CallStack) {
+	interpreter.callStack = callStack
+}
+
 // SetAllInterpreters sets the given map of interpreters as the map of all interpreters.
 //
 func (interpreter *Interpreter) SetAllInterpreters(allInterpreters map[common.LocationID]*Interpreter) {
@@ -772,6 +1111,30 @@ func (interpreter *Interpreter) SetAtreeStorageValidationEnabled(enabled bool) {
 	interpreter.atreeStorageValidationEnabled = enabled
 }
 
+// SetOwnerValidationEnabled sets the owner validation option.
+//
+func (interpreter *Interpreter) SetOwnerValidationEnabled(enabled bool) {
+	interpreter.ownerValidationEnabled = enabled
+}
+
+// SetLinkValidationEnabled sets the link validation option.
+//
+func (interpreter *Interpreter) SetLinkValidationEnabled(enabled bool) {
+	interpreter.linkValidationEnabled = enabled
+}
+
+// SetCopyOnWriteEnabled sets the copy-on-write option.
+//
+func (interpreter *Interpreter) SetCopyOnWriteEnabled(enabled bool) {
+	interpreter.copyOnWriteEnabled = enabled
+}
+
+// SetReferenceInvalidationEnabled sets the reference invalidation option.
+//
+func (interpreter *Interpreter) SetReferenceInvalidationEnabled(enabled bool) {
+	interpreter.referenceInvalidationEnabled = enabled
+}
+
 // SetTracingEnabled sets the tracing option.
 //
 func (interpreter *Interpreter) SetTracingEnabled(enabled bool) {
@@ -1010,7 +1373,8 @@ func (interpreter *Interpreter) RecoverErrors(onError func(error)) {
 		case error:
 			err = r
 		default:
-			err = fmt.Errorf("%s", r)
+			// Panics with values that are not errors are not expected
+			err = errors.NewUnexpectedError("%s", r)
 		}
 
 		// if the error is not yet an interpreter error, wrap it
@@ -1306,6 +1670,7 @@ func (interpreter *Interpreter) declareVariable(identifier string, value Value)
 	// NOTE: semantic analysis already checked possible invalid redeclaration
 	variable := NewVariableWithValue(value)
 	interpreter.setVariable(identifier, variable)
+	interpreter.reportVariableDeclaration(variable)
 	return variable
 }
 
@@ -1546,9 +1911,17 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 		wrapFunctions(interpreter.typeCodes.TypeRequirementCodes[typeRequirement.ID()])
 	}
 
+	memberSlots := interpreter.compositeMemberSlots(compositeType)
+	functionSlots := compositeFunctionSlots(memberSlots, functions)
+
+	constantFields := interpreter.compositeConstantFields(declaration, compositeType)
+
 	interpreter.typeCodes.CompositeCodes[compositeType.ID()] = CompositeTypeCode{
 		DestructorFunction: destructorFunction,
 		CompositeFunctions: functions,
+		MemberSlots:        memberSlots,
+		FunctionSlots:      functionSlots,
+		ConstantFields:     constantFields,
 	}
 
 	location := interpreter.Location
@@ -1606,6 +1979,24 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 					)
 				}
 
+				// Store the constant fields in declaration order
+
+				for _, field := range declaration.Members.Fields() {
+					name := field.Identifier.Identifier
+					constantField, ok := constantFields[name]
+					if !ok {
+						continue
+					}
+
+					fields = append(
+						fields,
+						CompositeField{
+							Name:  name,
+							Value: constantField,
+						},
+					)
+				}
+
 				value := NewCompositeValue(
 					interpreter,
 					location,
@@ -1617,6 +2008,9 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 
 				value.InjectedFields = injectedFields
 				value.Functions = functions
+				value.memberSlots = memberSlots
+				value.functionSlots = functionSlots
+				value.constantFields = constantFields
 				value.Destructor = destructorFunction
 
 				invocation.Self = value
@@ -1668,6 +2062,73 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 	return lexicalScope, variable
 }
 
+// compositeConstantFields returns the values of the constant fields of the given composite declaration,
+// e.g. `let x: Int = 1`, which were evaluated by the checker, see sema.Elaboration.FieldDeclarationConstantValues
+//
+func (interpreter *Interpreter) compositeConstantFields(
+	declaration *ast.CompositeDeclaration,
+	compositeType *sema.CompositeType,
+) map[string]Value {
+
+	var constantFields map[string]Value
+
+	for _, field := range declaration.Members.Fields() {
+		constant, ok := interpreter.Program.Elaboration.FieldDeclarationConstantValues[field]
+		if !ok {
+			continue
+		}
+
+		name := field.Identifier.Identifier
+		member, ok := compositeType.Members.Get(name)
+		if !ok {
+			continue
+		}
+
+		if constantFields == nil {
+			constantFields = map[string]Value{}
+		}
+
+		constantFields[name] = interpreter.ConvertAndBox(
+			NewConstantValue(constant),
+			constant.Type,
+			member.TypeAnnotation.Type,
+		)
+	}
+
+	return constantFields
+}
+
+// compositeMemberSlots returns the member dispatch table of the given composite type,
+// which was resolved by the checker, see sema.Elaboration.CompositeTypeMemberSlots
+//
+func (interpreter *Interpreter) compositeMemberSlots(compositeType *sema.CompositeType) *sema.MemberSlots {
+	return interpreter.Program.Elaboration.CompositeTypeMemberSlots[compositeType]
+}
+
+// compositeFunctionSlots returns the functions of a composite type,
+// indexed by their slot in the given member dispatch table of the composite type
+//
+func compositeFunctionSlots(memberSlots *sema.MemberSlots, functions map[string]FunctionValue) []FunctionValue {
+	if memberSlots == nil {
+		return nil
+	}
+
+	functionSlots := make([]FunctionValue, memberSlots.Len())
+
+	// Iterating over the map in a non-deterministic way is OK,
+	// each function is put into its own slot, the order does not matter.
+
+	for name, function := range functions { //nolint:maprangecheck
+		slot, ok := memberSlots.Slot(name)
+		if !ok {
+			continue
+		}
+		functionSlots[slot] = function
+	}
+
+	return functionSlots
+}
+
 func (interpreter *Interpreter) declareEnumConstructor(
 	declaration *ast.CompositeDeclaration,
 	lexicalScope *VariableActivation,
@@ -1771,6 +2232,26 @@ func EnumConstructorFunction(
 		sema.EnumConstructorType(enumType),
 	)
 
+	// Provide all enum cases, unless there is an enum case with the same name
+
+	if _, ok := nestedVariables[sema.EnumAllCasesFieldName]; !ok {
+		nestedVariables[sema.EnumAllCasesFieldName] = NewVariableWithGetter(func() Value {
+			values := make([]Value, len(caseValues))
+			for i, caseValue := range caseValues {
+				values[i] = caseValue.Transfer(inter, getLocationRange, atree.Address{}, false, nil)
+			}
+
+			return NewArrayValue(
+				inter,
+				VariableSizedStaticType{
+					Type: ConvertSemaToStaticType(enumType),
+				},
+				common.Address{},
+				values...,
+			)
+		})
+	}
+
 	constructor.NestedVariables = nestedVariables
 
 	return constructor
@@ -1876,16 +2357,56 @@ func (interpreter *Interpreter) compositeFunctions(
 
 	for _, functionDeclaration := range compositeDeclaration.Members.Functions() {
 		name := functionDeclaration.Identifier.Identifier
-		functions[name] =
-			interpreter.compositeFunction(
-				functionDeclaration,
-				lexicalScope,
+		function := interpreter.compositeFunction(
+			functionDeclaration,
+			lexicalScope,
+		)
+
+		if _, ok := interpreter.Program.Elaboration.MemoizedFunctions[functionDeclaration]; ok {
+			functions[name] = interpreter.memoizedFunction(
+				compositeDeclaration.Identifier.Identifier,
+				name,
+				function,
 			)
+		} else {
+			functions[name] = function
+		}
 	}
 
 	return functions
 }
 
+// memoizedFunction wraps the given memoized contract function,
+// so its invocations are handled by the memoized function handler, if any
+//
+func (interpreter *Interpreter) memoizedFunction(
+	contractName string,
+	functionName string,
+	function *InterpretedFunctionValue,
+) FunctionValue {
+
+	handler := interpreter.memoizedFunctionHandler
+	if handler == nil {
+		return function
+	}
+
+	qualifiedName := fmt.Sprintf("%s.%s", contractName, functionName)
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+			return handler(
+				invocation.Interpreter,
+				interpreter.Location,
+				qualifiedName,
+				func() Value {
+					return function.invoke(invocation)
+				},
+			)
+		},
+		function.Type,
+	)
+}
+
 func (interpreter *Interpreter) functionWrappers(
 	members *ast.Members,
 	lexicalScope *VariableActivation,
@@ -2004,13 +2525,7 @@ func (interpreter *Interpreter) transferAndConvert(
 	getLocationRange func() LocationRange,
 ) Value {
 
-	transferredValue := value.Transfer(
-		interpreter,
-		getLocationRange,
-		atree.Address{},
-		false,
-		nil,
-	)
+	transferredValue := interpreter.transferToLocal(value, getLocationRange)
 
 	result := interpreter.ConvertAndBox(
 		transferredValue,
@@ -2150,6 +2665,16 @@ func (interpreter *Interpreter) convert(value Value, valueType, targetType sema.
 		if !valueType.Equal(unwrappedTargetType) {
 			return ConvertUFix64(value)
 		}
+
+	case sema.Fix128Type:
+		if !valueType.Equal(unwrappedTargetType) {
+			return ConvertFix128(value)
+		}
+
+	case sema.UFix128Type:
+		if !valueType.Equal(unwrappedTargetType) {
+			return ConvertUFix128(value)
+		}
 	}
 
 	switch unwrappedTargetType.(type) {
@@ -2498,15 +3023,30 @@ func (interpreter *Interpreter) NewSubInterpreter(
 		WithOnLoopIterationHandler(interpreter.onLoopIteration),
 		WithOnFunctionInvocationHandler(interpreter.onFunctionInvocation),
 		WithOnInvokedFunctionReturnHandler(interpreter.onInvokedFunctionReturn),
+		WithOnBigIntOperationHandler(interpreter.onBigIntOperation),
+		WithOnYieldHandler(interpreter.onYield),
 		WithInjectedCompositeFieldsHandler(interpreter.injectedCompositeFieldsHandler),
 		WithContractValueHandler(interpreter.contractValueHandler),
 		WithImportLocationHandler(interpreter.importLocationHandler),
 		WithUUIDHandler(interpreter.uuidHandler),
 		WithAllInterpreters(interpreter.allInterpreters),
+		WithCallStack(interpreter.callStack),
 		WithAtreeValueValidationEnabled(interpreter.atreeValueValidationEnabled),
 		WithAtreeStorageValidationEnabled(interpreter.atreeStorageValidationEnabled),
+		WithOwnerValidationEnabled(interpreter.ownerValidationEnabled),
+		WithLinkValidationEnabled(interpreter.linkValidationEnabled),
 		withTypeCodes(interpreter.typeCodes),
+		withCopyOnWriteShares(interpreter.copyOnWriteShares),
+		withReferencedResourceKindedValues(interpreter.referencedResourceKindedValues),
+		WithReferenceInvalidationEnabled(interpreter.referenceInvalidationEnabled),
+		WithCopyOnWriteEnabled(interpreter.copyOnWriteEnabled),
+		withTransientStorage(interpreter.transientStorage),
+		withReentrancyTracker(interpreter.reentrancyTracker),
+		withSafeCallTracker(interpreter.safeCallTracker),
+		WithReentrancyHandling(interpreter.reentrancyHandling),
 		WithPublicAccountHandler(interpreter.publicAccountHandler),
+		WithAuthAccountHandler(interpreter.authAccountHandler),
+		WithAccountLinkingAllowedHandler(interpreter.accountLinkingAllowedHandler),
 		WithPublicKeyValidationHandler(interpreter.PublicKeyValidationHandler),
 		WithSignatureVerificationHandler(interpreter.SignatureVerificationHandler),
 		WithHashHandler(interpreter.HashHandler),
@@ -2520,6 +3060,13 @@ func (interpreter *Interpreter) NewSubInterpreter(
 		WithTracingEnabled(interpreter.tracingEnabled),
 		WithOnRecordTraceHandler(interpreter.onRecordTrace),
 		WithOnResourceOwnerChangeHandler(interpreter.onResourceOwnerChange),
+		WithOnAccountLinkedHandler(interpreter.onAccountLinked),
+		WithMemoizedFunctionHandler(interpreter.memoizedFunctionHandler),
+		WithTypeLoader(interpreter.typeLoader),
+		WithMutationJournal(interpreter.mutationJournal),
+		WithSharedStateHandler(interpreter.sharedStateHandler),
+		WithTypeFingerprintsEnabled(interpreter.typeFingerprintsEnabled),
+		withValueArena(interpreter.valueArena),
 	}
 
 	return NewInterpreter(
@@ -2534,30 +3081,73 @@ func (interpreter *Interpreter) NewSubInterpreter(
 
 func (interpreter *Interpreter) storedValueExists(
 	storageAddress common.Address,
-	domain string,
-	identifier string,
+	key common.StorageKey,
 ) bool {
-	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain)
-	return accountStorage.ValueExists(identifier)
+	interpreter.journalStoredRead(storageAddress, key, nil)
+
+	if interpreter.sharedStateHandler != nil {
+		return interpreter.sharedStateHandler(storageAddress, key) != nil
+	}
+
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, key.Domain, true)
+	return accountStorage.ValueExists(key.Identifier)
 }
 
 func (interpreter *Interpreter) ReadStored(
 	storageAddress common.Address,
-	domain string,
-	identifier string,
+	key common.StorageKey,
 ) Value {
-	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain)
-	return accountStorage.ReadValue(identifier)
+	var value Value
+
+	if interpreter.sharedStateHandler != nil {
+		readOnlyValue := interpreter.sharedStateHandler(storageAddress, key)
+		if readOnlyValue != nil {
+			value = readOnlyValue.Value()
+		}
+	} else {
+		accountStorage := interpreter.Storage.GetStorageMap(storageAddress, key.Domain, true)
+		value = accountStorage.ReadValue(key.Identifier)
+	}
+
+	interpreter.journalStoredRead(storageAddress, key, value)
+
+	return value
 }
 
 func (interpreter *Interpreter) writeStored(
 	storageAddress common.Address,
-	domain string,
-	identifier string,
+	key common.StorageKey,
 	value Value,
 ) {
-	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain)
-	accountStorage.WriteValue(interpreter, identifier, value)
+	if interpreter.sharedStateHandler != nil {
+		panic(ReadOnlyValueMutationError{})
+	}
+
+	interpreter.journalStoredWrite(storageAddress, key)
+
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, key.Domain, true)
+	accountStorage.WriteValue(interpreter, key.Identifier, value)
+
+	interpreter.writeTypeFingerprint(storageAddress, key, value)
+}
+
+// detachStored removes the value stored at the given path,
+// without removing the value's slabs, see StorageMap.DetachValue
+//
+func (interpreter *Interpreter) detachStored(
+	storageAddress common.Address,
+	key common.StorageKey,
+) {
+	if interpreter.sharedStateHandler != nil {
+		panic(ReadOnlyValueMutationError{})
+	}
+
+	interpreter.journalStoredWrite(storageAddress, key)
+
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, key.Domain, true)
+	accountStorage.DetachValue(interpreter, key.Identifier)
+
+	interpreter.writeTypeFingerprint(storageAddress, key, nil)
 }
 
 type valueConverterDeclaration struct {
@@ -2727,6 +3317,22 @@ var converterDeclarations = []valueConverterDeclaration{
 		min: UFix64Value(0),
 		max: UFix64Value(math.MaxUint64),
 	},
+	{
+		name: sema.Fix128TypeName,
+		convert: func(value Value) Value {
+			return ConvertFix128(value)
+		},
+		min: NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+		max: NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+	},
+	{
+		name: sema.UFix128TypeName,
+		convert: func(value Value) Value {
+			return ConvertUFix128(value)
+		},
+		min: NewUFix128ValueFromBigInt(sema.UFix128TypeMinBig),
+		max: NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+	},
 	{
 		name: "Address",
 		convert: func(value Value) Value {
@@ -2969,6 +3575,7 @@ func defineBaseFunctions(activation *VariableActivation) {
 	defineTypeFunction(activation)
 	defineRuntimeTypeConstructorFunctions(activation)
 	defineStringFunction(activation)
+	defineTimestampFunctions(activation)
 }
 
 type converterFunction struct {
@@ -3076,7 +3683,7 @@ var runtimeTypeConstructors = []runtimeTypeConstructor{
 		converter: NewHostFunctionValue(
 			func(invocation Invocation) Value {
 				return TypeValue{
-					Type: ReferenceStaticType{
+					Type: &ReferenceStaticType{
 						Authorized: bool(invocation.Arguments[0].(BoolValue)),
 						Type:       invocation.Arguments[1].(TypeValue).Type,
 					}}
@@ -3090,7 +3697,7 @@ var runtimeTypeConstructors = []runtimeTypeConstructor{
 			func(invocation Invocation) Value {
 				ty := invocation.Arguments[0].(TypeValue).Type
 				// Capabilities must hold references
-				_, ok := ty.(ReferenceStaticType)
+				_, ok := ty.(*ReferenceStaticType)
 				if !ok {
 					return NilValue{}
 				}
@@ -3186,6 +3793,31 @@ func defineStringFunction(activation *VariableActivation) {
 	defineBaseValue(activation, sema.StringType.String(), stringFunction)
 }
 
+// timestampFunction is the `Timestamp` function. It is stateless, hence it can be re-used across interpreters.
+//
+var timestampFunction = NewHostFunctionValue(
+	func(invocation Invocation) Value {
+		unixSeconds := invocation.Arguments[0].(UFix64Value)
+		return NewTimestampValueFromUFix64(unixSeconds)
+	},
+	sema.TimestampConstructorFunctionType,
+)
+
+// durationFunction is the `Duration` function. It is stateless, hence it can be re-used across interpreters.
+//
+var durationFunction = NewHostFunctionValue(
+	func(invocation Invocation) Value {
+		seconds := invocation.Arguments[0].(Fix64Value)
+		return NewDurationValueFromFix64(seconds)
+	},
+	sema.DurationConstructorFunctionType,
+)
+
+func defineTimestampFunctions(activation *VariableActivation) {
+	defineBaseValue(activation, sema.TimestampTypeName, timestampFunction)
+	defineBaseValue(activation, sema.DurationTypeName, durationFunction)
+}
+
 // TODO:
 // - FunctionType
 //
@@ -3242,6 +3874,9 @@ func (interpreter *Interpreter) IsSubType(subType DynamicType, superType sema.Ty
 	case CompositeDynamicType:
 		return sema.IsSubType(typedSubType.StaticType, superType)
 
+	case InclusiveRangeDynamicType:
+		return sema.IsSubType(typedSubType.StaticType, superType)
+
 	case *ArrayDynamicType:
 		var superTypeElementType sema.Type
 
@@ -3347,12 +3982,14 @@ func (interpreter *Interpreter) IsSubType(subType DynamicType, superType sema.Ty
 			}
 
 			// If the reference value is not authorized,
-			// it may not be downcasted
+			// it may not be downcasted,
+			// and it must grant all entitlements of the super type
 
 			return sema.IsSubType(
 				&sema.ReferenceType{
-					Authorized: authorized,
-					Type:       typedSubType.BorrowedType(),
+					Authorized:   authorized,
+					Entitlements: typedSubType.Entitlements(),
+					Type:         typedSubType.BorrowedType(),
 				},
 				typedSuperType,
 			)
@@ -3419,6 +4056,18 @@ func (interpreter *Interpreter) IsSubType(subType DynamicType, superType sema.Ty
 		case sema.AnyStructType, sema.BlockType:
 			return true
 		}
+
+	case TimestampDynamicType:
+		switch superType {
+		case sema.AnyStructType, sema.TimestampType:
+			return true
+		}
+
+	case DurationDynamicType:
+		switch superType {
+		case sema.AnyStructType, sema.DurationType:
+			return true
+		}
 	}
 
 	return false
@@ -3435,18 +4084,13 @@ func (interpreter *Interpreter) authAccountSaveFunction(addressValue AddressValu
 			value := invocation.Arguments[0]
 			path := invocation.Arguments[1].(PathValue)
 
-			domain := path.Domain.Identifier()
-			identifier := path.Identifier
+			key := common.NewPathStorageKey(path.Domain, path.Identifier)
 
 			// Prevent an overwrite
 
 			getLocationRange := invocation.GetLocationRange
 
-			if interpreter.storedValueExists(
-				address,
-				domain,
-				identifier,
-			) {
+			if interpreter.storedValueExists(address, key) {
 				panic(
 					OverwriteError{
 						Address:       addressValue,
@@ -3466,7 +4110,7 @@ func (interpreter *Interpreter) authAccountSaveFunction(addressValue AddressValu
 
 			// Write new value
 
-			interpreter.writeStored(address, domain, identifier, value)
+			interpreter.writeStored(address, key, value)
 
 			return VoidValue{}
 		},
@@ -3474,6 +4118,44 @@ func (interpreter *Interpreter) authAccountSaveFunction(addressValue AddressValu
 	)
 }
 
+func (interpreter *Interpreter) authAccountStorageUsedByPathFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			var keysAndValues []Value
+
+			for _, domain := range common.AllPathDomains {
+				storageMap := interpreter.Storage.GetStorageMap(address, domain.Identifier(), true)
+
+				for _, statistics := range storageMap.ValueStatistics() {
+					keysAndValues = append(
+						keysAndValues,
+						PathValue{
+							Domain:     domain,
+							Identifier: statistics.Key,
+						},
+						UInt64Value(statistics.Size),
+					)
+				}
+			}
+
+			return NewDictionaryValue(
+				interpreter,
+				DictionaryStaticType{
+					KeyType:   PrimitiveStaticTypePath,
+					ValueType: PrimitiveStaticTypeUInt64,
+				},
+				keysAndValues...,
+			)
+		},
+		sema.AuthAccountTypeStorageUsedByPathFunctionType,
+	)
+}
+
 func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValue) *HostFunctionValue {
 
 	// Converted addresses can be cached and don't have to be recomputed on each function invocation
@@ -3484,18 +4166,25 @@ func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValu
 
 			path := invocation.Arguments[0].(PathValue)
 
-			domain := path.Domain.Identifier()
-			identifier := path.Identifier
+			key := common.NewPathStorageKey(path.Domain, path.Identifier)
 
-			value := interpreter.ReadStored(address, domain, identifier)
+			// The type fingerprint of the value, if any,
+			// answers the query without decoding the value
 
-			if value == nil {
-				return NilValue{}
+			staticType := interpreter.storedTypeFingerprint(address, key)
+			if staticType == nil {
+				value := interpreter.ReadStored(address, key)
+
+				if value == nil {
+					return NilValue{}
+				}
+
+				staticType = value.StaticType()
 			}
 
 			return NewSomeValueNonCopying(
 				TypeValue{
-					Type: value.StaticType(),
+					Type: staticType,
 				},
 			)
 		},
@@ -3504,6 +4193,76 @@ func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValu
 	)
 }
 
+func (interpreter *Interpreter) authAccountStoragePathsFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			typeValue := invocation.Arguments[0].(TypeValue)
+
+			var paths []Value
+
+			if typeValue.Type != nil {
+				superType := interpreter.MustConvertStaticToSemaType(typeValue.Type)
+
+				domain := common.PathDomainStorage
+
+				// Iterating the storage map is an access of all values in the domain
+
+				interpreter.journalStoredRead(
+					address,
+					common.StorageKey{
+						Domain: domain.Identifier(),
+					},
+					nil,
+				)
+
+				storageMap := interpreter.Storage.GetStorageMap(address, domain.Identifier(), true)
+
+				// Collect the identifiers first, as reading values without type fingerprints
+				// must not happen while the storage map is iterated
+
+				var identifiers []string
+
+				iterator := storageMap.Iterator()
+				for identifier := iterator.NextKey(); identifier != ""; identifier = iterator.NextKey() {
+					identifiers = append(identifiers, identifier)
+				}
+
+				for _, identifier := range identifiers {
+					key := common.NewPathStorageKey(domain, identifier)
+
+					if !interpreter.storedValueHasType(address, key, superType) {
+						continue
+					}
+
+					paths = append(
+						paths,
+						PathValue{
+							Domain:     domain,
+							Identifier: identifier,
+						},
+					)
+				}
+			}
+
+			return NewArrayValue(
+				interpreter,
+				VariableSizedStaticType{
+					Type: PrimitiveStaticTypeStoragePath,
+				},
+				common.Address{},
+				paths...,
+			)
+		},
+
+		sema.AuthAccountTypeStoragePathsFunctionType,
+	)
+}
+
 func (interpreter *Interpreter) authAccountLoadFunction(addressValue AddressValue) *HostFunctionValue {
 	return interpreter.authAccountReadFunction(addressValue, true)
 }
@@ -3522,10 +4281,9 @@ func (interpreter *Interpreter) authAccountReadFunction(addressValue AddressValu
 
 			path := invocation.Arguments[0].(PathValue)
 
-			domain := path.Domain.Identifier()
-			identifier := path.Identifier
+			key := common.NewPathStorageKey(path.Domain, path.Identifier)
 
-			value := interpreter.ReadStored(address, domain, identifier)
+			value := interpreter.ReadStored(address, key)
 
 			if value == nil {
 				return NilValue{}
@@ -3552,6 +4310,22 @@ func (interpreter *Interpreter) authAccountReadFunction(addressValue AddressValu
 			inter := invocation.Interpreter
 			getLocationRange := invocation.GetLocationRange
 
+			// Resources which are loaded are moved out of storage
+			// without copying them, and only copied once necessary.
+			// This avoids copying resources which are moved
+			// from one storage path to another in the same account.
+
+			if clear {
+				if compositeValue, ok := value.(*CompositeValue); ok &&
+					compositeValue.Kind == common.CompositeKindResource {
+
+					interpreter.detachStored(address, key)
+					compositeValue.deferTransfer(inter)
+
+					return NewSomeValueNonCopying(compositeValue)
+				}
+			}
+
 			// We could also pass remove=true and the storable stored in storage,
 			// but passing remove=false here and writing nil below has the same effect
 			// TODO: potentially refactor and get storable in storage, pass it and remove=true
@@ -3566,7 +4340,7 @@ func (interpreter *Interpreter) authAccountReadFunction(addressValue AddressValu
 			// Remove the value from storage,
 			// but only if the type check succeeded.
 			if clear {
-				interpreter.writeStored(address, domain, identifier, nil)
+				interpreter.writeStored(address, key, nil)
 			}
 
 			return NewSomeValueNonCopying(transferredValue)
@@ -3598,6 +4372,7 @@ func (interpreter *Interpreter) authAccountBorrowFunction(addressValue AddressVa
 
 			reference := &StorageReferenceValue{
 				Authorized:           referenceType.Authorized,
+				Entitlements:         referenceType.Entitlements,
 				TargetStorageAddress: address,
 				TargetPath:           path,
 				BorrowedType:         referenceType.Type,
@@ -3639,17 +4414,24 @@ func (interpreter *Interpreter) authAccountLinkFunction(addressValue AddressValu
 			newCapabilityPath := invocation.Arguments[0].(PathValue)
 			targetPath := invocation.Arguments[1].(PathValue)
 
-			newCapabilityDomain := newCapabilityPath.Domain.Identifier()
-			newCapabilityIdentifier := newCapabilityPath.Identifier
+			newCapabilityKey := common.NewPathStorageKey(
+				newCapabilityPath.Domain,
+				newCapabilityPath.Identifier,
+			)
 
-			if interpreter.storedValueExists(
-				address,
-				newCapabilityDomain,
-				newCapabilityIdentifier,
-			) {
+			if interpreter.storedValueExists(address, newCapabilityKey) {
 				return NilValue{}
 			}
 
+			if interpreter.linkValidationEnabled {
+				interpreter.checkLinkTarget(
+					address,
+					targetPath,
+					borrowType,
+					invocation.GetLocationRange,
+				)
+			}
+
 			// Write new value
 
 			borrowStaticType := ConvertSemaToStaticType(borrowType)
@@ -3661,8 +4443,7 @@ func (interpreter *Interpreter) authAccountLinkFunction(addressValue AddressValu
 
 			interpreter.writeStored(
 				address,
-				newCapabilityDomain,
-				newCapabilityIdentifier,
+				newCapabilityKey,
 				linkValue,
 			)
 
@@ -3679,6 +4460,117 @@ func (interpreter *Interpreter) authAccountLinkFunction(addressValue AddressValu
 	)
 }
 
+// checkLinkTarget checks that the value stored at the given target path, if any,
+// can be borrowed with the given borrow type.
+//
+// If no value is stored at the target path, the link is valid,
+// as a value might be stored at the path later.
+// Links stored at the target path are not followed,
+// as they were already checked when they were created
+//
+func (interpreter *Interpreter) checkLinkTarget(
+	address common.Address,
+	targetPath PathValue,
+	borrowType *sema.ReferenceType,
+	getLocationRange func() LocationRange,
+) {
+	value := interpreter.ReadStored(
+		address,
+		common.NewPathStorageKey(targetPath.Domain, targetPath.Identifier),
+	)
+	if value == nil {
+		return
+	}
+
+	var targetType StaticType
+
+	switch value := value.(type) {
+	case LinkValue:
+		allowedType := interpreter.MustConvertStaticToSemaType(value.Type)
+		if sema.IsSubType(allowedType, borrowType) {
+			return
+		}
+		targetType = value.Type
+
+	case AccountLinkValue:
+		if sema.IsSubType(authAccountReferenceType, borrowType) {
+			return
+		}
+		targetType = ConvertSemaToStaticType(authAccountReferenceType)
+
+	default:
+		dynamicType := value.DynamicType(interpreter, SeenReferences{})
+		if interpreter.IsSubType(dynamicType, borrowType.Type) {
+			return
+		}
+		targetType = value.StaticType()
+	}
+
+	panic(InvalidLinkTargetError{
+		Address:       address,
+		TargetPath:    targetPath,
+		TargetType:    targetType,
+		BorrowType:    ConvertSemaToStaticType(borrowType),
+		LocationRange: getLocationRange(),
+	})
+}
+
+func (interpreter *Interpreter) authAccountLinkAccountFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			if interpreter.accountLinkingAllowedHandler == nil ||
+				!interpreter.accountLinkingAllowedHandler(interpreter, addressValue) {
+
+				panic(AccountLinkingNotAllowedError{
+					Address:       address,
+					LocationRange: invocation.GetLocationRange(),
+				})
+			}
+
+			newCapabilityPath := invocation.Arguments[0].(PathValue)
+
+			newCapabilityKey := common.NewPathStorageKey(
+				newCapabilityPath.Domain,
+				newCapabilityPath.Identifier,
+			)
+
+			if interpreter.storedValueExists(address, newCapabilityKey) {
+				return NilValue{}
+			}
+
+			// Write new value
+
+			interpreter.writeStored(
+				address,
+				newCapabilityKey,
+				AccountLinkValue{},
+			)
+
+			onAccountLinked := interpreter.onAccountLinked
+			if onAccountLinked != nil {
+				onAccountLinked(interpreter, addressValue, newCapabilityPath)
+			}
+
+			return NewSomeValueNonCopying(
+				&CapabilityValue{
+					Address: addressValue,
+					Path:    newCapabilityPath,
+					BorrowType: &ReferenceStaticType{
+						Type: PrimitiveStaticTypeAuthAccount,
+					},
+				},
+			)
+
+		},
+		sema.AuthAccountTypeLinkAccountFunctionType,
+	)
+}
+
 func (interpreter *Interpreter) accountGetLinkTargetFunction(addressValue AddressValue) *HostFunctionValue {
 
 	// Converted addresses can be cached and don't have to be recomputed on each function invocation
@@ -3689,10 +4581,9 @@ func (interpreter *Interpreter) accountGetLinkTargetFunction(addressValue Addres
 
 			capabilityPath := invocation.Arguments[0].(PathValue)
 
-			domain := capabilityPath.Domain.Identifier()
-			identifier := capabilityPath.Identifier
+			key := common.NewPathStorageKey(capabilityPath.Domain, capabilityPath.Identifier)
 
-			value := interpreter.ReadStored(address, domain, identifier)
+			value := interpreter.ReadStored(address, key)
 
 			if value == nil {
 				return NilValue{}
@@ -3719,12 +4610,11 @@ func (interpreter *Interpreter) authAccountUnlinkFunction(addressValue AddressVa
 
 			capabilityPath := invocation.Arguments[0].(PathValue)
 
-			domain := capabilityPath.Domain.Identifier()
-			identifier := capabilityPath.Identifier
+			key := common.NewPathStorageKey(capabilityPath.Domain, capabilityPath.Identifier)
 
 			// Write new value
 
-			interpreter.writeStored(address, domain, identifier, nil)
+			interpreter.writeStored(address, key, nil)
 
 			return VoidValue{}
 		},
@@ -3756,8 +4646,8 @@ func (interpreter *Interpreter) capabilityBorrowFunction(
 				panic(errors.NewUnreachableError())
 			}
 
-			targetPath, authorized, err :=
-				interpreter.GetCapabilityFinalTargetPath(
+			target, authorized, err :=
+				interpreter.GetCapabilityFinalTarget(
 					address,
 					pathValue,
 					borrowType,
@@ -3767,30 +4657,47 @@ func (interpreter *Interpreter) capabilityBorrowFunction(
 				panic(err)
 			}
 
-			if targetPath == EmptyPathValue {
+			if target == nil {
 				return NilValue{}
 			}
 
-			reference := &StorageReferenceValue{
-				Authorized:           authorized,
-				TargetStorageAddress: address,
-				TargetPath:           targetPath,
-				BorrowedType:         borrowType.Type,
-			}
+			switch target := target.(type) {
+			case AccountCapabilityTarget:
+				return NewSomeValueNonCopying(
+					&EphemeralReferenceValue{
+						Authorized:   authorized,
+						Entitlements: borrowType.Entitlements,
+						Value:        interpreter.authAccountHandler(interpreter, AddressValue(target)),
+						BorrowedType: borrowType.Type,
+					},
+				)
 
-			// Attempt to dereference,
-			// which reads the stored value
-			// and performs a dynamic type check
+			case PathCapabilityTarget:
+				reference := &StorageReferenceValue{
+					Authorized:           authorized,
+					Entitlements:         borrowType.Entitlements,
+					TargetStorageAddress: address,
+					TargetPath:           PathValue(target),
+					BorrowedType:         borrowType.Type,
+				}
 
-			value, err := reference.dereference(interpreter, invocation.GetLocationRange)
-			if err != nil {
-				panic(err)
-			}
-			if value == nil {
-				return NilValue{}
-			}
+				// Attempt to dereference,
+				// which reads the stored value
+				// and performs a dynamic type check
 
-			return NewSomeValueNonCopying(reference)
+				value, err := reference.dereference(interpreter, invocation.GetLocationRange)
+				if err != nil {
+					panic(err)
+				}
+				if value == nil {
+					return NilValue{}
+				}
+
+				return NewSomeValueNonCopying(reference)
+
+			default:
+				panic(errors.NewUnreachableError())
+			}
 		},
 		sema.CapabilityTypeBorrowFunctionType(borrowType),
 	)
@@ -3821,8 +4728,8 @@ func (interpreter *Interpreter) capabilityCheckFunction(
 				panic(errors.NewUnreachableError())
 			}
 
-			targetPath, authorized, err :=
-				interpreter.GetCapabilityFinalTargetPath(
+			target, authorized, err :=
+				interpreter.GetCapabilityFinalTarget(
 					address,
 					pathValue,
 					borrowType,
@@ -3832,31 +4739,85 @@ func (interpreter *Interpreter) capabilityCheckFunction(
 				panic(err)
 			}
 
-			if targetPath == EmptyPathValue {
+			if target == nil {
 				return BoolValue(false)
 			}
 
-			reference := &StorageReferenceValue{
-				Authorized:           authorized,
-				TargetStorageAddress: address,
-				TargetPath:           targetPath,
-				BorrowedType:         borrowType.Type,
-			}
+			switch target := target.(type) {
+			case AccountCapabilityTarget:
+				return BoolValue(true)
 
-			// Attempt to dereference,
-			// which reads the stored value
-			// and performs a dynamic type check
+			case PathCapabilityTarget:
+				targetPath := PathValue(target)
 
-			if reference.ReferencedValue(interpreter) == nil {
-				return BoolValue(false)
-			}
+				// Fast path: If the type fingerprint of the stored value
+				// is a subtype of the borrow type, the value exists and can be borrowed,
+				// without having to decode the value
+
+				if interpreter.storedValueHasSubType(
+					address,
+					common.NewPathStorageKey(targetPath.Domain, targetPath.Identifier),
+					borrowType.Type,
+				) {
+					return BoolValue(true)
+				}
+
+				reference := &StorageReferenceValue{
+					Authorized:           authorized,
+					Entitlements:         borrowType.Entitlements,
+					TargetStorageAddress: address,
+					TargetPath:           targetPath,
+					BorrowedType:         borrowType.Type,
+				}
+
+				// Attempt to dereference,
+				// which reads the stored value
+				// and performs a dynamic type check
 
-			return BoolValue(true)
+				if reference.ReferencedValue(interpreter) == nil {
+					return BoolValue(false)
+				}
+
+				return BoolValue(true)
+
+			default:
+				panic(errors.NewUnreachableError())
+			}
 		},
 		sema.CapabilityTypeCheckFunctionType(borrowType),
 	)
 }
 
+// authAccountReferenceType is the type of references to accounts
+// which are borrowed from capabilities created by AuthAccount.linkAccount
+//
+var authAccountReferenceType = &sema.ReferenceType{
+	Type: sema.AuthAccountType,
+}
+
+// CapabilityTarget is the final target of a capability,
+// i.e. either a storage path, or an account
+//
+type CapabilityTarget interface {
+	isCapabilityTarget()
+}
+
+// PathCapabilityTarget is the target of a capability which targets a stored value
+//
+type PathCapabilityTarget PathValue
+
+func (PathCapabilityTarget) isCapabilityTarget() {}
+
+// AccountCapabilityTarget is the target of a capability which targets an account,
+// i.e. which was created using AuthAccount.linkAccount
+//
+type AccountCapabilityTarget common.Address
+
+func (AccountCapabilityTarget) isCapabilityTarget() {}
+
+// GetCapabilityFinalTargetPath returns the final target path of a capability,
+// or the empty path if there is none, or if the capability targets an account.
+//
 func (interpreter *Interpreter) GetCapabilityFinalTargetPath(
 	address common.Address,
 	path PathValue,
@@ -3866,6 +4827,37 @@ func (interpreter *Interpreter) GetCapabilityFinalTargetPath(
 	finalPath PathValue,
 	authorized bool,
 	err error,
+) {
+	target, authorized, err := interpreter.GetCapabilityFinalTarget(
+		address,
+		path,
+		wantedBorrowType,
+		getLocationRange,
+	)
+	if err != nil {
+		return EmptyPathValue, false, err
+	}
+
+	pathTarget, ok := target.(PathCapabilityTarget)
+	if !ok {
+		return EmptyPathValue, false, nil
+	}
+
+	return PathValue(pathTarget), authorized, nil
+}
+
+// GetCapabilityFinalTarget follows the links of a capability
+// and returns its final target, or nil if there is none.
+//
+func (interpreter *Interpreter) GetCapabilityFinalTarget(
+	address common.Address,
+	path PathValue,
+	wantedBorrowType *sema.ReferenceType,
+	getLocationRange func() LocationRange,
+) (
+	target CapabilityTarget,
+	authorized bool,
+	err error,
 ) {
 	wantedReferenceType := wantedBorrowType
 
@@ -3876,7 +4868,7 @@ func (interpreter *Interpreter) GetCapabilityFinalTargetPath(
 		// Detect cyclic links
 
 		if _, ok := seenPaths[path]; ok {
-			return EmptyPathValue, false, CyclicLinkError{
+			return nil, false, CyclicLinkError{
 				Address:       address,
 				Paths:         paths,
 				LocationRange: getLocationRange(),
@@ -3885,30 +4877,44 @@ func (interpreter *Interpreter) GetCapabilityFinalTargetPath(
 			seenPaths[path] = struct{}{}
 		}
 
-		value := interpreter.ReadStored(
-			address,
-			path.Domain.Identifier(),
-			path.Identifier,
-		)
+		key := common.NewPathStorageKey(path.Domain, path.Identifier)
+
+		// Links have no type fingerprint, so if the stored value has a fingerprint,
+		// it is the final target, and the value does not have to be read
+
+		if interpreter.storedTypeFingerprint(address, key) != nil {
+			return PathCapabilityTarget(path), wantedReferenceType.Authorized, nil
+		}
+
+		value := interpreter.ReadStored(address, key)
 
 		if value == nil {
-			return EmptyPathValue, false, nil
+			return nil, false, nil
 		}
 
-		if link, ok := value.(LinkValue); ok {
+		switch value := value.(type) {
+		case LinkValue:
 
-			allowedType := interpreter.MustConvertStaticToSemaType(link.Type)
+			allowedType := interpreter.MustConvertStaticToSemaType(value.Type)
 
 			if !sema.IsSubType(allowedType, wantedBorrowType) {
-				return EmptyPathValue, false, nil
+				return nil, false, nil
 			}
 
-			targetPath := link.TargetPath
+			targetPath := value.TargetPath
 			paths = append(paths, targetPath)
 			path = targetPath
 
-		} else {
-			return path, wantedReferenceType.Authorized, nil
+		case AccountLinkValue:
+
+			if !sema.IsSubType(authAccountReferenceType, wantedBorrowType) {
+				return nil, false, nil
+			}
+
+			return AccountCapabilityTarget(address), wantedReferenceType.Authorized, nil
+
+		default:
+			return PathCapabilityTarget(path), wantedReferenceType.Authorized, nil
 		}
 	}
 }
@@ -3922,6 +4928,9 @@ func (interpreter *Interpreter) ConvertStaticToSemaType(staticType StaticType) (
 		func(location common.Location, qualifiedIdentifier string, typeID common.TypeID) (*sema.CompositeType, error) {
 			return interpreter.GetCompositeType(location, qualifiedIdentifier, typeID)
 		},
+		func(location common.Location, qualifiedIdentifier string) (*sema.EntitlementType, error) {
+			return interpreter.getEntitlementType(location, qualifiedIdentifier)
+		},
 	)
 }
 
@@ -3981,6 +4990,14 @@ func (interpreter *Interpreter) GetCompositeType(
 		return interpreter.getNativeCompositeType(qualifiedIdentifier)
 	}
 
+	loadedType, err := interpreter.loadType(location, qualifiedIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if compositeType, ok := loadedType.(*sema.CompositeType); ok {
+		return compositeType, nil
+	}
+
 	return interpreter.getUserCompositeType(location, typeID)
 }
 
@@ -4018,6 +5035,14 @@ func (interpreter *Interpreter) getInterfaceType(location common.Location, quali
 		return nil, &InterfaceMissingLocationError{QualifiedIdentifier: qualifiedIdentifier}
 	}
 
+	loadedType, err := interpreter.loadType(location, qualifiedIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if interfaceType, ok := loadedType.(*sema.InterfaceType); ok {
+		return interfaceType, nil
+	}
+
 	typeID := location.TypeID(qualifiedIdentifier)
 
 	elaboration := interpreter.getElaboration(location)
@@ -4036,29 +5061,118 @@ func (interpreter *Interpreter) getInterfaceType(location common.Location, quali
 	return ty, nil
 }
 
-func (interpreter *Interpreter) reportLoopIteration(pos ast.HasPosition) {
+func (interpreter *Interpreter) getEntitlementType(location common.Location, qualifiedIdentifier string) (*sema.EntitlementType, error) {
+	if location == nil {
+		return nil, &EntitlementMissingLocationError{QualifiedIdentifier: qualifiedIdentifier}
+	}
+
+	loadedType, err := interpreter.loadType(location, qualifiedIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if entitlementType, ok := loadedType.(*sema.EntitlementType); ok {
+		return entitlementType, nil
+	}
+
+	typeID := location.TypeID(qualifiedIdentifier)
+
+	elaboration := interpreter.getElaboration(location)
+	if elaboration == nil {
+		return nil, TypeLoadingError{
+			TypeID: typeID,
+		}
+	}
+
+	ty := elaboration.EntitlementTypes[typeID]
+	if ty == nil {
+		return nil, TypeLoadingError{
+			TypeID: typeID,
+		}
+	}
+	return ty, nil
+}
+
+func (interpreter *Interpreter) reportLoopIteration(statement ast.Statement) {
+	interpreter.yield(YieldPointLoopIteration)
+
 	if interpreter.onLoopIteration == nil {
 		return
 	}
 
-	line := pos.StartPosition().Line
-	interpreter.onLoopIteration(interpreter, line)
+	interpreter.onLoopIteration(
+		interpreter,
+		LoopIteration{
+			Statement: statement,
+			Line:      statement.StartPosition().Line,
+		},
+	)
 }
 
-func (interpreter *Interpreter) reportFunctionInvocation(line int) {
+// ReportLoopIteration reports a loop iteration to the yield handler
+// and the loop iteration handler, if any.
+// It is also used by other execution backends, e.g. the VM
+//
+func (interpreter *Interpreter) ReportLoopIteration(iteration LoopIteration) {
+	interpreter.yield(YieldPointLoopIteration)
+
+	if interpreter.onLoopIteration == nil {
+		return
+	}
+
+	interpreter.onLoopIteration(interpreter, iteration)
+}
+
+// ReportFunctionInvocation reports a function invocation to the yield handler
+// and the function invocation handler, if any.
+//
+func (interpreter *Interpreter) ReportFunctionInvocation(invocation FunctionInvocation) {
+	interpreter.yield(YieldPointFunctionInvocation)
+
+	if interpreter.callStack != nil {
+		interpreter.callStack.push(CallStackFrame{
+			Location: invocation.LocationRange.Location,
+			Line:     invocation.Line,
+		})
+	}
+
 	if interpreter.onFunctionInvocation == nil {
 		return
 	}
 
-	interpreter.onFunctionInvocation(interpreter, line)
+	interpreter.onFunctionInvocation(interpreter, invocation)
 }
 
-func (interpreter *Interpreter) reportInvokedFunctionReturn(line int) {
+// ReportInvokedFunctionReturn reports the return from a function invocation
+// to the function return handler, if any.
+//
+func (interpreter *Interpreter) ReportInvokedFunctionReturn(invocation FunctionInvocation, result Value) {
+	if interpreter.callStack != nil {
+		interpreter.callStack.pop()
+	}
+
 	if interpreter.onInvokedFunctionReturn == nil {
 		return
 	}
 
-	interpreter.onInvokedFunctionReturn(interpreter, line)
+	interpreter.onInvokedFunctionReturn(interpreter, invocation, result)
+}
+
+// reportBigIntOperation reports the given operation to the big integer operation handler, if any,
+// if the given operand is represented as a big integer
+//
+func (interpreter *Interpreter) reportBigIntOperation(operation ast.Operation, operand Value) {
+	if interpreter.onBigIntOperation == nil {
+		return
+	}
+
+	switch operand.(type) {
+	case IntValue, UIntValue,
+		Int128Value, Int256Value,
+		UInt128Value, UInt256Value,
+		Fix128Value, UFix128Value:
+
+		interpreter.onBigIntOperation(interpreter, operation)
+	}
 }
 
 // getMember gets the member value by the given identifier from the given Value depending on its type.
@@ -4088,6 +5202,28 @@ func (interpreter *Interpreter) getMember(self Value, getLocationRange func() Lo
 	return result
 }
 
+// getMemberWithInfo gets the member of the given value, like getMember.
+//
+// Members of composite values are accessed by the member slot
+// which was determined by the checker, instead of looking them up by name,
+// see CompositeValue.slotMember
+//
+func (interpreter *Interpreter) getMemberWithInfo(
+	self Value,
+	getLocationRange func() LocationRange,
+	identifier string,
+	memberInfo sema.MemberInfo,
+) Value {
+	if compositeValue, ok := self.(*CompositeValue); ok && memberInfo.Slot >= 0 {
+		member := compositeValue.slotMember(interpreter, getLocationRange, memberInfo)
+		if member != nil {
+			return member
+		}
+	}
+
+	return interpreter.getMember(self, getLocationRange, identifier)
+}
+
 func (interpreter *Interpreter) isInstanceFunction(self Value) *HostFunctionValue {
 	return NewHostFunctionValue(
 		func(invocation Invocation) Value {
@@ -65,6 +65,7 @@ func (interpreter *Interpreter) identifierExpressionGetterSetter(identifierExpre
 			return variable.GetValue()
 		},
 		set: func(value Value) {
+			interpreter.reportVariableAssignment(variable)
 			variable.SetValue(value)
 		},
 	}
@@ -105,6 +106,7 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(memberExpression *a
 	identifier := memberExpression.Identifier.Identifier
 	getLocationRange := locationRangeGetter(interpreter.Location, memberExpression)
 	_, isNestedResourceMove := interpreter.Program.Elaboration.IsNestedResourceMoveExpression[memberExpression]
+	memberInfo := interpreter.Program.Elaboration.MemberExpressionMemberInfos[memberExpression]
 	return getterSetter{
 		target: target,
 		get: func(allowMissing bool) Value {
@@ -127,7 +129,7 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(memberExpression *a
 			if isNestedResourceMove {
 				resultValue = target.(MemberAccessibleValue).RemoveMember(interpreter, getLocationRange, identifier)
 			} else {
-				resultValue = interpreter.getMember(target, getLocationRange, identifier)
+				resultValue = interpreter.getMemberWithInfo(target, getLocationRange, identifier, memberInfo)
 			}
 			if resultValue == nil && !allowMissing {
 				panic(MissingMemberValueError{
@@ -163,76 +165,158 @@ func (interpreter *Interpreter) evalExpression(expression ast.Expression) Value
 	return expression.Accept(interpreter).(Value)
 }
 
+// constantExpressionValue returns the value of the given expression,
+// if it is a constant expression which was already evaluated by the checker
+//
+func (interpreter *Interpreter) constantExpressionValue(expression ast.Expression) (Value, bool) {
+	constant, ok := interpreter.Program.Elaboration.ConstantExpressionValues[expression]
+	if !ok {
+		return nil, false
+	}
+
+	return NewConstantValue(constant), true
+}
+
+// NewConstantValue returns the value for the given constant,
+// e.g. the result of a constant expression evaluated by the checker
+//
+func NewConstantValue(constant sema.ConstantValue) Value {
+	switch value := constant.Value.(type) {
+	case *big.Int:
+		if sema.IsSameTypeKind(constant.Type, sema.FixedPointType) {
+			return newFixedPointConstantValue(value, constant.Type)
+		}
+		return NewIntValue(value, constant.Type)
+
+	case bool:
+		return BoolValue(value)
+
+	case string:
+		return NewStringValue(value)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// newFixedPointConstantValue returns the fixed-point value for the given scaled value
+//
+func newFixedPointConstantValue(value *big.Int, fixedPointType sema.Type) Value {
+	switch fixedPointType {
+	case sema.Fix64Type:
+		return Fix64Value(value.Int64())
+	case sema.UFix64Type:
+		return UFix64Value(value.Uint64())
+	case sema.Fix128Type:
+		return NewFix128ValueFromBigInt(value)
+	case sema.UFix128Type:
+		return NewUFix128ValueFromBigInt(value)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
 func (interpreter *Interpreter) VisitBinaryExpression(expression *ast.BinaryExpression) ast.Repr {
+	if value, ok := interpreter.constantExpressionValue(expression); ok {
+		return value
+	}
+
+	if interpreter.valueArena != nil && isArenaOperation(expression.Operation) {
+		value, _ := interpreter.evalArenaArithmetic(expression, false)
+		return value
+	}
+
 	switch expression.Operation {
 	case ast.OperationPlus:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Plus(right)
 
 	case ast.OperationMinus:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Minus(right)
 
 	case ast.OperationMod:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Mod(right)
 
 	case ast.OperationMul:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Mul(right)
 
 	case ast.OperationDiv:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Div(right)
 
 	case ast.OperationBitwiseOr:
 		left := interpreter.evalExpression(expression.Left).(IntegerValue)
 		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.BitwiseOr(right)
 
 	case ast.OperationBitwiseXor:
 		left := interpreter.evalExpression(expression.Left).(IntegerValue)
 		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.BitwiseXor(right)
 
 	case ast.OperationBitwiseAnd:
 		left := interpreter.evalExpression(expression.Left).(IntegerValue)
 		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.BitwiseAnd(right)
 
 	case ast.OperationBitwiseLeftShift:
 		left := interpreter.evalExpression(expression.Left).(IntegerValue)
 		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.BitwiseLeftShift(right)
 
 	case ast.OperationBitwiseRightShift:
 		left := interpreter.evalExpression(expression.Left).(IntegerValue)
 		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.BitwiseRightShift(right)
 
+	case ast.OperationRangeInclusive,
+		ast.OperationRangeExclusive:
+
+		left := interpreter.evalExpression(expression.Left).(IntegerValue)
+		right := interpreter.evalExpression(expression.Right).(IntegerValue)
+		rangeType := interpreter.Program.Elaboration.BinaryExpressionResultTypes[expression].(*sema.InclusiveRangeType)
+		exclusive := expression.Operation == ast.OperationRangeExclusive
+		return interpreter.newRangeValue(left, right, exclusive, rangeType)
+
 	case ast.OperationLess:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Less(right)
 
 	case ast.OperationLessEqual:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.LessEqual(right)
 
 	case ast.OperationGreater:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.Greater(right)
 
 	case ast.OperationGreaterEqual:
 		left := interpreter.evalExpression(expression.Left).(NumberValue)
 		right := interpreter.evalExpression(expression.Right).(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, left)
 		return left.GreaterEqual(right)
 
 	case ast.OperationEqual:
@@ -309,6 +393,10 @@ func (interpreter *Interpreter) testEqual(left, right Value, hasPosition ast.Has
 }
 
 func (interpreter *Interpreter) VisitUnaryExpression(expression *ast.UnaryExpression) ast.Repr {
+	if value, ok := interpreter.constantExpressionValue(expression); ok {
+		return value
+	}
+
 	value := interpreter.evalExpression(expression.Expression)
 
 	switch expression.Operation {
@@ -318,6 +406,7 @@ func (interpreter *Interpreter) VisitUnaryExpression(expression *ast.UnaryExpres
 
 	case ast.OperationMinus:
 		integerValue := value.(NumberValue)
+		interpreter.reportBigIntOperation(expression.Operation, integerValue)
 		return integerValue.Negate()
 
 	case ast.OperationMove:
@@ -408,10 +497,31 @@ func NewIntValue(value *big.Int, intSubType sema.Type) Value {
 }
 
 func (interpreter *Interpreter) VisitFixedPointExpression(expression *ast.FixedPointExpression) ast.Repr {
-	// TODO: adjust once/if we support more fixed point types
-
 	fixedPointSubType := interpreter.Program.Elaboration.FixedPointExpression[expression]
 
+	switch fixedPointSubType {
+	case sema.Fix128Type:
+		return NewFix128ValueFromBigInt(
+			fixedpoint.ConvertToFixedPointBigInt(
+				expression.Negative,
+				expression.UnsignedInteger,
+				expression.Fractional,
+				expression.Scale,
+				sema.Fix128Scale,
+			),
+		)
+	case sema.UFix128Type:
+		return NewUFix128ValueFromBigInt(
+			fixedpoint.ConvertToFixedPointBigInt(
+				expression.Negative,
+				expression.UnsignedInteger,
+				expression.Fractional,
+				expression.Scale,
+				sema.Fix128Scale,
+			),
+		)
+	}
+
 	value := fixedpoint.ConvertToFixedPointBigInt(
 		expression.Negative,
 		expression.UnsignedInteger,
@@ -580,9 +690,17 @@ func (interpreter *Interpreter) VisitInvocationExpression(invocationExpression *
 	parameterTypes :=
 		interpreter.Program.Elaboration.InvocationExpressionParameterTypes[invocationExpression]
 
-	line := invocationExpression.StartPosition().Line
+	invocation := FunctionInvocation{
+		Function:  function,
+		Arguments: arguments,
+		LocationRange: LocationRange{
+			Location: interpreter.Location,
+			Range:    ast.NewRangeFromPositioned(invocationExpression),
+		},
+		Line: invocationExpression.StartPosition().Line,
+	}
 
-	interpreter.reportFunctionInvocation(line)
+	interpreter.ReportFunctionInvocation(invocation)
 
 	resultValue := interpreter.invokeFunctionValue(
 		function,
@@ -594,7 +712,7 @@ func (interpreter *Interpreter) VisitInvocationExpression(invocationExpression *
 		invocationExpression,
 	)
 
-	interpreter.reportInvokedFunctionReturn(line)
+	interpreter.ReportInvokedFunctionReturn(invocation, resultValue)
 
 	// If this is invocation is optional chaining, wrap the result
 	// as an optional, as the result is expected to be an optional
@@ -734,11 +852,16 @@ func (interpreter *Interpreter) VisitReferenceExpression(referenceExpression *as
 
 	result := interpreter.evalExpression(referenceExpression.Expression)
 
-	return &EphemeralReferenceValue{
+	reference := &EphemeralReferenceValue{
 		Authorized:   borrowType.Authorized,
+		Entitlements: borrowType.Entitlements,
 		Value:        result,
 		BorrowedType: borrowType.Type,
 	}
+
+	interpreter.trackReferencedResourceKindedValue(reference)
+
+	return reference
 }
 
 func (interpreter *Interpreter) VisitForceExpression(expression *ast.ForceExpression) ast.Repr {
@@ -31,13 +31,16 @@ func (interpreter *Interpreter) VisitImportDeclaration(declaration *ast.ImportDe
 	resolvedLocations := interpreter.Program.Elaboration.ImportDeclarationsResolvedLocations[declaration]
 
 	for _, resolvedLocation := range resolvedLocations {
-		interpreter.importResolvedLocation(resolvedLocation)
+		interpreter.importResolvedLocation(resolvedLocation, declaration.Aliases)
 	}
 
 	return nil
 }
 
-func (interpreter *Interpreter) importResolvedLocation(resolvedLocation sema.ResolvedLocation) {
+func (interpreter *Interpreter) importResolvedLocation(
+	resolvedLocation sema.ResolvedLocation,
+	aliases map[string]string,
+) {
 
 	// tracing
 	if interpreter.tracingEnabled {
@@ -87,6 +90,11 @@ func (interpreter *Interpreter) importResolvedLocation(resolvedLocation sema.Res
 			}
 		}
 
+		// declare the variable under its alias, if any
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+
 		interpreter.setVariable(name, variable)
 		interpreter.Globals.Set(name, variable)
 	}
@@ -19,7 +19,6 @@
 package interpreter
 
 import (
-	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/sema"
@@ -86,13 +85,7 @@ func (interpreter *Interpreter) invokeFunctionValue(
 				getLocationRange,
 			)
 		} else {
-			transferredArguments[i] = argument.Transfer(
-				interpreter,
-				getLocationRange,
-				atree.Address{},
-				false,
-				nil,
-			)
+			transferredArguments[i] = interpreter.transferToLocal(argument, getLocationRange)
 		}
 	}
 
@@ -109,6 +102,36 @@ func (interpreter *Interpreter) invokeFunctionValue(
 	return function.invoke(invocation)
 }
 
+// invokeIterationFunction invokes the given function for an element of a container,
+// e.g. the function passed to the array function `map`.
+//
+// The arguments are copied, so the function cannot mutate the elements of the container.
+// Each invocation is reported as a loop iteration, so it is metered like a loop written by the user.
+//
+func (interpreter *Interpreter) invokeIterationFunction(
+	function FunctionValue,
+	arguments []Value,
+	argumentTypes []sema.Type,
+	getLocationRange func() LocationRange,
+) Value {
+
+	interpreter.ReportLoopIteration(LoopIteration{
+		Line: getLocationRange().StartPos.Line,
+	})
+
+	transferredArguments := make([]Value, len(arguments))
+	for i, argument := range arguments {
+		transferredArguments[i] = interpreter.transferToLocal(argument, getLocationRange)
+	}
+
+	return function.invoke(Invocation{
+		Arguments:        transferredArguments,
+		ArgumentTypes:    argumentTypes,
+		GetLocationRange: getLocationRange,
+		Interpreter:      interpreter,
+	})
+}
+
 func (interpreter *Interpreter) invokeInterpretedFunction(
 	function *InterpretedFunctionValue,
 	invocation Invocation,
@@ -125,6 +148,11 @@ func (interpreter *Interpreter) invokeInterpretedFunction(
 		interpreter.declareVariable(sema.SelfIdentifier, invocation.Self)
 	}
 
+	if interpreter.reentrancyHandling != ReentrancyHandlingNone {
+		interpreter.enterFunction(invocation)
+		defer interpreter.leaveFunction()
+	}
+
 	return interpreter.invokeInterpretedFunctionActivated(function, invocation.Arguments)
 }
 
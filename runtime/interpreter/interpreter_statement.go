@@ -23,6 +23,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 func (interpreter *Interpreter) evalStatement(statement ast.Statement) interface{} {
@@ -277,20 +278,6 @@ func (interpreter *Interpreter) VisitForStatement(statement *ast.ForStatement) a
 
 	getLocationRange := locationRangeGetter(interpreter.Location, statement)
 
-	value := interpreter.evalExpression(statement.Value)
-	transferredValue := value.Transfer(
-		interpreter,
-		getLocationRange,
-		atree.Address{},
-		false,
-		nil,
-	)
-
-	iterator, err := transferredValue.(*ArrayValue).array.Iterator()
-	if err != nil {
-		panic(ExternalError{err})
-	}
-
 	var indexVariable *Variable
 	var one = NewIntValueFromInt64(1)
 	if statement.Index != nil {
@@ -300,7 +287,116 @@ func (interpreter *Interpreter) VisitForStatement(statement *ast.ForStatement) a
 		)
 	}
 
+	// executeBody executes the loop body for the given element.
+	// It returns the result of the loop statement,
+	// and if the loop should be exited
+
+	executeBody := func(element Value) (result ast.Repr, done bool) {
+
+		interpreter.reportLoopIteration(statement)
+
+		variable.SetValue(element)
+
+		result = statement.Block.Accept(interpreter)
+
+		switch result.(type) {
+		case controlBreak:
+			return nil, true
+
+		case controlContinue:
+			// NO-OP
+
+		case functionReturn:
+			return result, true
+		}
+
+		if indexVariable != nil {
+			indexVariable.SetValue(indexVariable.GetValue().(IntValue).Plus(one))
+		}
+
+		return nil, false
+	}
+
+	// Iterating over the keys or values of a dictionary
+	// does not require materializing them in an array first
+
+	if memberExpression, ok := statement.Value.(*ast.MemberExpression); ok {
+		dictionaryValue, isValues, ok := interpreter.evalDictionaryView(memberExpression)
+		if ok {
+			return interpreter.forEachDictionaryViewElement(
+				dictionaryValue,
+				isValues,
+				getLocationRange,
+				executeBody,
+			)
+		}
+	}
+
+	value := interpreter.evalExpression(statement.Value)
+
+	switch value := value.(type) {
+	case *ArrayValue:
+		arrayValue := interpreter.transferToLocal(value, getLocationRange).(*ArrayValue)
+		return interpreter.forEachArrayElement(arrayValue, executeBody)
+
+	case *DictionaryValue:
+		dictionaryValue := interpreter.transferToLocal(value, getLocationRange).(*DictionaryValue)
+		return interpreter.forEachDictionaryKey(dictionaryValue, executeBody)
+
+	case *SimpleCompositeValue:
+		if _, ok := value.dynamicType.(InclusiveRangeDynamicType); ok {
+			var result ast.Repr
+			interpreter.forEachRangeElement(value, func(element IntegerValue) bool {
+				var done bool
+				result, done = executeBody(element)
+				return !done
+			})
+			return result
+		}
+
+	case *CompositeValue:
+		iterator := interpreter.transferToLocal(value, getLocationRange).(*CompositeValue)
+		return interpreter.forEachIteratorElement(iterator, statement, executeBody)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (interpreter *Interpreter) forEachArrayElement(
+	arrayValue *ArrayValue,
+	executeBody func(element Value) (result ast.Repr, done bool),
+) ast.Repr {
+
+	storageID := arrayValue.StorageID()
+
+	iterator, err := arrayValue.array.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	var index uint64
+
 	for {
+		// The iterated array might lazily share its elements with another array (copy-on-write).
+		// If the other array got mutated, the iterated array now refers to a copy of the elements,
+		// so continue the iteration on the copy
+
+		if arrayValue.StorageID() != storageID {
+			storageID = arrayValue.StorageID()
+
+			iterator, err = arrayValue.array.Iterator()
+			if err != nil {
+				panic(ExternalError{err})
+			}
+
+			for i := uint64(0); i < index; i++ {
+				_, err = iterator.Next()
+				if err != nil {
+					panic(ExternalError{err})
+				}
+			}
+		}
+
 		var atreeValue atree.Value
 		atreeValue, err = iterator.Next()
 		if err != nil {
@@ -311,29 +407,151 @@ func (interpreter *Interpreter) VisitForStatement(statement *ast.ForStatement) a
 			return nil
 		}
 
-		interpreter.reportLoopIteration(statement)
+		index++
 
 		// atree.Array iterator returns low-level atree.Value,
 		// convert to high-level interpreter.Value
 		value := MustConvertStoredValue(atreeValue)
 
-		variable.SetValue(value)
+		result, done := executeBody(value)
+		if done {
+			return result
+		}
+	}
+}
 
-		result := statement.Block.Accept(interpreter)
+func (interpreter *Interpreter) forEachDictionaryKey(
+	dictionaryValue *DictionaryValue,
+	executeBody func(element Value) (result ast.Repr, done bool),
+) ast.Repr {
 
-		switch result.(type) {
-		case controlBreak:
-			return nil
+	// Collect the keys before executing the loop body,
+	// as the body might mutate the dictionary
 
-		case controlContinue:
-			// NO-OP
+	keys := make([]Value, 0, dictionaryValue.Count())
+	dictionaryValue.Iterate(func(key, _ Value) (resume bool) {
+		keys = append(keys, key)
+		return true
+	})
 
-		case functionReturn:
+	for _, key := range keys {
+		result, done := executeBody(key)
+		if done {
 			return result
 		}
+	}
 
-		if indexVariable != nil {
-			indexVariable.SetValue(indexVariable.GetValue().(IntValue).Plus(one))
+	return nil
+}
+
+// evalDictionaryView evaluates the accessed dictionary of the given member expression,
+// if the expression accesses the keys or values of a dictionary, e.g. `dict.keys`.
+// It returns false if the expression is any other member expression,
+// in which case nothing is evaluated
+//
+func (interpreter *Interpreter) evalDictionaryView(
+	memberExpression *ast.MemberExpression,
+) (
+	dictionaryValue *DictionaryValue,
+	isValues bool,
+	ok bool,
+) {
+	if memberExpression.Optional {
+		return nil, false, false
+	}
+
+	switch memberExpression.Identifier.Identifier {
+	case sema.DictionaryTypeKeysFieldName:
+		isValues = false
+	case sema.DictionaryTypeValuesFieldName:
+		isValues = true
+	default:
+		return nil, false, false
+	}
+
+	memberInfo := interpreter.Program.Elaboration.MemberExpressionMemberInfos[memberExpression]
+	if _, ok := memberInfo.AccessedType.(*sema.DictionaryType); !ok {
+		return nil, false, false
+	}
+
+	dictionaryValue = interpreter.evalExpression(memberExpression.Expression).(*DictionaryValue)
+
+	return dictionaryValue, isValues, true
+}
+
+// forEachDictionaryViewElement iterates over the keys or the values of the given dictionary,
+// without materializing them in an array.
+//
+// Like for an iteration over the dictionary itself, the keys are collected
+// before executing the loop body, as the body might mutate the dictionary.
+// Each value is only copied when it is iterated over,
+// and entries removed by the loop body are skipped
+//
+func (interpreter *Interpreter) forEachDictionaryViewElement(
+	dictionaryValue *DictionaryValue,
+	isValues bool,
+	getLocationRange func() LocationRange,
+	executeBody func(element Value) (result ast.Repr, done bool),
+) ast.Repr {
+
+	keys := make([]Value, 0, dictionaryValue.Count())
+	dictionaryValue.Iterate(func(key, _ Value) (resume bool) {
+		keys = append(keys, interpreter.transferToLocal(key, getLocationRange))
+		return true
+	})
+
+	for _, key := range keys {
+		element := key
+
+		if isValues {
+			value, ok := dictionaryValue.Get(interpreter, getLocationRange, key)
+			if !ok {
+				continue
+			}
+			element = interpreter.transferToLocal(value, getLocationRange)
+		}
+
+		result, done := executeBody(element)
+		if done {
+			return result
+		}
+	}
+
+	return nil
+}
+
+// forEachIteratorElement iterates over a value which conforms to the iterator protocol,
+// i.e. it calls the value's function `next` until it returns nil
+//
+func (interpreter *Interpreter) forEachIteratorElement(
+	iterator *CompositeValue,
+	statement *ast.ForStatement,
+	executeBody func(element Value) (result ast.Repr, done bool),
+) ast.Repr {
+
+	getLocationRange := locationRangeGetter(interpreter.Location, statement.Value)
+
+	for {
+		next := interpreter.getMember(iterator, getLocationRange, sema.IteratorNextFunctionName).(FunctionValue)
+
+		nextResult := interpreter.invokeFunctionValue(
+			next,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			statement.Value,
+		)
+
+		someValue, ok := nextResult.(*SomeValue)
+		if !ok {
+			return nil
+		}
+
+		result, done := executeBody(someValue.Value)
+		if done {
+			return result
 		}
 	}
 }
@@ -351,10 +569,7 @@ func (interpreter *Interpreter) VisitEmitStatement(statement *ast.EmitStatement)
 		})
 	}
 
-	err := interpreter.onEventEmitted(interpreter, getLocationRange, event, eventType)
-	if err != nil {
-		panic(err)
-	}
+	interpreter.emitEvent(getLocationRange, event, eventType)
 
 	return nil
 }
@@ -363,6 +578,12 @@ func (interpreter *Interpreter) VisitPragmaDeclaration(_ *ast.PragmaDeclaration)
 	return nil
 }
 
+func (interpreter *Interpreter) VisitEntitlementDeclaration(_ *ast.EntitlementDeclaration) ast.Repr {
+	// NO-OP: entitlements are only used statically,
+	// and in the static types of references
+	return nil
+}
+
 // VisitVariableDeclaration first visits the declaration's value,
 // then declares the variable with the name bound to the value
 func (interpreter *Interpreter) VisitVariableDeclaration(declaration *ast.VariableDeclaration) ast.Repr {
@@ -0,0 +1,357 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// JournalAccessKind is the kind of access to a stored value,
+// see MutationJournal
+//
+type JournalAccessKind uint8
+
+const (
+	JournalAccessKindRead JournalAccessKind = iota
+	JournalAccessKindWrite
+)
+
+func (k JournalAccessKind) String() string {
+	switch k {
+	case JournalAccessKindRead:
+		return "read"
+	case JournalAccessKindWrite:
+		return "write"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// JournalPath is the path of a stored value, or of a value nested in a stored value,
+// e.g. the field `balance` of the value stored at `/storage/vault` in account 0x1.
+//
+// A path without a domain refers to all values of the account,
+// and a path without an identifier refers to all values of the domain.
+//
+type JournalPath struct {
+	Address    common.Address
+	Domain     string
+	Identifier string
+	// Members are the accessed members of nested values, starting at the stored value:
+	// field names of composites, and indices of arrays and keys of dictionaries in brackets,
+	// e.g. `balances`, `[0]`, and `["a"]`
+	Members []string
+}
+
+func (p JournalPath) String() string {
+	var sb strings.Builder
+	sb.WriteString(p.Address.ShortHexWithPrefix())
+	if p.Domain == "" {
+		return sb.String()
+	}
+	sb.WriteByte('/')
+	sb.WriteString(p.Domain)
+	if p.Identifier == "" {
+		return sb.String()
+	}
+	sb.WriteByte('/')
+	sb.WriteString(p.Identifier)
+	for _, member := range p.Members {
+		if !strings.HasPrefix(member, "[") {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(member)
+	}
+	return sb.String()
+}
+
+// Overlaps returns true if the values referred to by the paths may overlap,
+// i.e. if one of the paths is a prefix of the other
+//
+func (p JournalPath) Overlaps(other JournalPath) bool {
+	if p.Address != other.Address {
+		return false
+	}
+
+	if p.Domain == "" || other.Domain == "" {
+		return true
+	}
+	if p.Domain != other.Domain {
+		return false
+	}
+
+	if p.Identifier == "" || other.Identifier == "" {
+		return true
+	}
+	if p.Identifier != other.Identifier {
+		return false
+	}
+
+	for i := 0; i < len(p.Members) && i < len(other.Members); i++ {
+		if p.Members[i] != other.Members[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p JournalPath) withMember(member string) JournalPath {
+	members := make([]string, len(p.Members), len(p.Members)+1)
+	copy(members, p.Members)
+	p.Members = append(members, member)
+	return p
+}
+
+// JournalEntry is an access to a stored value, recorded in a MutationJournal
+//
+type JournalEntry struct {
+	Kind JournalAccessKind
+	Path JournalPath
+}
+
+// MutationJournal records the paths of the stored values which are read and written by an execution,
+// so that embedders can detect conflicts between executions, e.g. of transactions executed in parallel,
+// see WithMutationJournal.
+//
+// Accesses to values nested in stored values are recorded with the path of the nested value,
+// if the path of the value is known. Otherwise, e.g. for nested values obtained through iteration,
+// the access is conservatively recorded for the whole account.
+// The access of a value is also considered an access of all values nested in it,
+// see JournalPath.Overlaps.
+//
+type MutationJournal struct {
+	entries []JournalEntry
+	// recorded are the string representations of the recorded entries,
+	// used to record each entry only once
+	recorded map[string]struct{}
+	// containerPaths are the paths of the stored containers which were read
+	containerPaths map[atree.StorageID]JournalPath
+}
+
+func NewMutationJournal() *MutationJournal {
+	return &MutationJournal{
+		recorded:       map[string]struct{}{},
+		containerPaths: map[atree.StorageID]JournalPath{},
+	}
+}
+
+// Entries returns the recorded accesses, in the order they were first recorded
+//
+func (j *MutationJournal) Entries() []JournalEntry {
+	return j.entries
+}
+
+// Reads returns the paths of the recorded reads
+//
+func (j *MutationJournal) Reads() []JournalPath {
+	return j.paths(JournalAccessKindRead)
+}
+
+// Writes returns the paths of the recorded writes
+//
+func (j *MutationJournal) Writes() []JournalPath {
+	return j.paths(JournalAccessKindWrite)
+}
+
+func (j *MutationJournal) paths(kind JournalAccessKind) []JournalPath {
+	var paths []JournalPath
+	for _, entry := range j.entries {
+		if entry.Kind == kind {
+			paths = append(paths, entry.Path)
+		}
+	}
+	return paths
+}
+
+// ConflictsWith returns true if the accesses recorded in the journal conflict
+// with the accesses recorded in the other journal, i.e. if a write of one execution
+// overlaps with a read or write of the other execution
+//
+func (j *MutationJournal) ConflictsWith(other *MutationJournal) bool {
+	for _, entry := range j.entries {
+		for _, otherEntry := range other.entries {
+			if entry.Kind != JournalAccessKindWrite &&
+				otherEntry.Kind != JournalAccessKindWrite {
+
+				continue
+			}
+
+			if entry.Path.Overlaps(otherEntry.Path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Reset removes all recorded accesses, so the journal can be reused for another execution
+//
+func (j *MutationJournal) Reset() {
+	j.entries = nil
+	j.recorded = map[string]struct{}{}
+	j.containerPaths = map[atree.StorageID]JournalPath{}
+}
+
+// RecordRead records the read of the value at the given path,
+// e.g. for values which are read by the embedder
+//
+func (j *MutationJournal) RecordRead(path JournalPath) {
+	j.record(JournalAccessKindRead, path)
+}
+
+// RecordWrite records the write of the value at the given path,
+// e.g. for values which are written by the embedder
+//
+func (j *MutationJournal) RecordWrite(path JournalPath) {
+	j.record(JournalAccessKindWrite, path)
+}
+
+func (j *MutationJournal) record(kind JournalAccessKind, path JournalPath) {
+	key := fmt.Sprintf("%s %s", kind, path)
+	if _, ok := j.recorded[key]; ok {
+		return
+	}
+	j.recorded[key] = struct{}{}
+
+	j.entries = append(j.entries, JournalEntry{
+		Kind: kind,
+		Path: path,
+	})
+}
+
+// recordValue records the path of the given value, if it is a container,
+// so that accesses of its nested values can be attributed to the path
+//
+func (j *MutationJournal) recordValue(path JournalPath, value Value) {
+	container, ok := value.(containerValue)
+	if !ok {
+		return
+	}
+	j.containerPaths[container.StorageID()] = path
+}
+
+// containerPath returns the path of the container with the given storage ID.
+// If the path is unknown, the path of the whole account is returned
+//
+func (j *MutationJournal) containerPath(storageID atree.StorageID) JournalPath {
+	path, ok := j.containerPaths[storageID]
+	if !ok {
+		return JournalPath{
+			Address: common.Address(storageID.Address),
+		}
+	}
+	return path
+}
+
+// WithMutationJournal returns an interpreter option which sets
+// the journal in which the accesses to stored values are recorded.
+//
+func WithMutationJournal(journal *MutationJournal) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.mutationJournal = journal
+		return nil
+	}
+}
+
+func (interpreter *Interpreter) journalStoredRead(address common.Address, key common.StorageKey, value Value) {
+	journal := interpreter.mutationJournal
+	if journal == nil {
+		return
+	}
+
+	path := JournalPath{
+		Address:    address,
+		Domain:     key.Domain,
+		Identifier: key.Identifier,
+	}
+	journal.record(JournalAccessKindRead, path)
+	journal.recordValue(path, value)
+}
+
+func (interpreter *Interpreter) journalStoredWrite(address common.Address, key common.StorageKey) {
+	journal := interpreter.mutationJournal
+	if journal == nil {
+		return
+	}
+
+	journal.record(
+		JournalAccessKindWrite,
+		JournalPath{
+			Address:    address,
+			Domain:     key.Domain,
+			Identifier: key.Identifier,
+		},
+	)
+}
+
+func (interpreter *Interpreter) journalEnabled() bool {
+	return interpreter != nil && interpreter.mutationJournal != nil
+}
+
+// journalNestedRead records the read of the given member of the container with the given storage ID,
+// if the container is stored in an account.
+//
+// The journal must be enabled, see journalEnabled
+//
+func (interpreter *Interpreter) journalNestedRead(storageID atree.StorageID, member string, value Value) {
+	if storageID.Address == (atree.Address{}) {
+		return
+	}
+
+	journal := interpreter.mutationJournal
+
+	path, ok := journal.containerPaths[storageID]
+	if !ok {
+		journal.record(JournalAccessKindRead, journal.containerPath(storageID))
+		return
+	}
+
+	path = path.withMember(member)
+	journal.record(JournalAccessKindRead, path)
+	journal.recordValue(path, value)
+}
+
+// journalNestedWrite records the in-place mutation of the container with the given storage ID,
+// if the container is stored in an account
+//
+func (interpreter *Interpreter) journalNestedWrite(storageID atree.StorageID) {
+	journal := interpreter.mutationJournal
+	if journal == nil || storageID.Address == (atree.Address{}) {
+		return
+	}
+
+	journal.record(JournalAccessKindWrite, journal.containerPath(storageID))
+}
+
+func journalIndexMember(index int) string {
+	return "[" + strconv.Itoa(index) + "]"
+}
+
+func journalKeyMember(key Value) string {
+	return "[" + key.String() + "]"
+}
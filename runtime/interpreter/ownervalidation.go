@@ -0,0 +1,138 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// maybeValidateOwners validates that the given value, which was written to storage,
+// and all values nested in it, are owned by the given owner,
+// if owner validation is enabled.
+//
+// The path of the written value is only determined if owner validation is enabled,
+// and is used to report the offending value.
+//
+func (interpreter *Interpreter) maybeValidateOwners(value Value, owner atree.Address, getPath func() string) {
+	if !interpreter.ownerValidationEnabled {
+		return
+	}
+
+	err := ValidateOwners(value, common.Address(owner), getPath())
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ValidateOwners validates that the given value and all values nested in it
+// are owned by the given owner.
+//
+// The path is the path of the given value,
+// and is used to report the offending value in the returned OwnerMismatchError.
+// References are not followed.
+//
+func ValidateOwners(value Value, expectedOwner common.Address, path string) error {
+	switch value := value.(type) {
+	case *SomeValue:
+		if value.Value == nil {
+			return nil
+		}
+		return ValidateOwners(value.Value, expectedOwner, path)
+
+	case *ArrayValue:
+		err := validateOwner(value.GetOwner(), expectedOwner, path)
+		if err != nil {
+			return err
+		}
+
+		index := 0
+		value.Iterate(func(element Value) (resume bool) {
+			err = ValidateOwners(
+				element,
+				expectedOwner,
+				fmt.Sprintf("%s[%d]", path, index),
+			)
+			index++
+			return err == nil
+		})
+		return err
+
+	case *DictionaryValue:
+		err := validateOwner(value.GetOwner(), expectedOwner, path)
+		if err != nil {
+			return err
+		}
+
+		value.Iterate(func(key, element Value) (resume bool) {
+			err = ValidateOwners(
+				key,
+				expectedOwner,
+				fmt.Sprintf("%s.keys[%s]", path, key),
+			)
+			if err != nil {
+				return false
+			}
+
+			err = ValidateOwners(
+				element,
+				expectedOwner,
+				fmt.Sprintf("%s[%s]", path, key),
+			)
+			return err == nil
+		})
+		return err
+
+	case *CompositeValue:
+		err := validateOwner(value.GetOwner(), expectedOwner, path)
+		if err != nil {
+			return err
+		}
+
+		value.ForEachField(func(name string, field Value) {
+			if err != nil {
+				return
+			}
+
+			err = ValidateOwners(
+				field,
+				expectedOwner,
+				fmt.Sprintf("%s.%s", path, name),
+			)
+		})
+		return err
+	}
+
+	return nil
+}
+
+func validateOwner(actualOwner, expectedOwner common.Address, path string) error {
+	if actualOwner == expectedOwner {
+		return nil
+	}
+
+	return OwnerMismatchError{
+		Path:          path,
+		ExpectedOwner: expectedOwner,
+		ActualOwner:   actualOwner,
+	}
+}
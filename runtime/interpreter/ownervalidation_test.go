@@ -0,0 +1,168 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	. "github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestValidateOwners(t *testing.T) {
+
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	otherOwner := common.Address{0x2}
+
+	innerArrayType := VariableSizedStaticType{
+		Type: PrimitiveStaticTypeInt,
+	}
+
+	outerArrayType := VariableSizedStaticType{
+		Type: innerArrayType,
+	}
+
+	// newOuterArray returns an array owned by the owner,
+	// which contains an array owned by the given inner owner.
+	//
+	// NOTE: NewArrayValueWithIterator does not transfer the elements,
+	// so the owner of the inner array is not changed
+
+	newOuterArray := func(inter *Interpreter, innerOwner common.Address) *ArrayValue {
+		innerArrayValue := NewArrayValue(
+			inter,
+			innerArrayType,
+			innerOwner,
+			NewIntValueFromInt64(1),
+		)
+
+		returned := false
+
+		return NewArrayValueWithIterator(
+			inter,
+			outerArrayType,
+			owner,
+			func() Value {
+				if returned {
+					return nil
+				}
+				returned = true
+				return innerArrayValue
+			},
+		)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newTestInterpreter(t)
+
+		err := ValidateOwners(newOuterArray(inter, owner), owner, "values")
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newTestInterpreter(t)
+
+		err := ValidateOwners(newOuterArray(inter, owner), otherOwner, "values")
+		require.Equal(t,
+			OwnerMismatchError{
+				Path:          "values",
+				ExpectedOwner: otherOwner,
+				ActualOwner:   owner,
+			},
+			err,
+		)
+	})
+
+	t.Run("invalid nested owner", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newTestInterpreter(t)
+
+		err := ValidateOwners(newOuterArray(inter, otherOwner), owner, "values")
+		require.Equal(t,
+			OwnerMismatchError{
+				Path:          "values[0]",
+				ExpectedOwner: owner,
+				ActualOwner:   otherOwner,
+			},
+			err,
+		)
+	})
+
+	t.Run("storage write, validation enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, err := NewInterpreter(
+			nil,
+			utils.TestLocation,
+			WithStorage(NewInMemoryStorage()),
+			WithOwnerValidationEnabled(true),
+		)
+		require.NoError(t, err)
+
+		value := newOuterArray(inter, otherOwner)
+
+		storageMap := inter.Storage.GetStorageMap(owner, common.PathDomainStorage.Identifier(), true)
+
+		assert.PanicsWithValue(t,
+			OwnerMismatchError{
+				Path:          "values[0]",
+				ExpectedOwner: owner,
+				ActualOwner:   otherOwner,
+			},
+			func() {
+				storageMap.WriteValue(inter, "values", value)
+			},
+		)
+	})
+
+	t.Run("storage write, validation disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, err := NewInterpreter(
+			nil,
+			utils.TestLocation,
+			WithStorage(NewInMemoryStorage()),
+		)
+		require.NoError(t, err)
+
+		value := newOuterArray(inter, otherOwner)
+
+		storageMap := inter.Storage.GetStorageMap(owner, common.PathDomainStorage.Identifier(), true)
+
+		assert.NotPanics(t, func() {
+			storageMap.WriteValue(inter, "values", value)
+		})
+	})
+}
@@ -63,8 +63,8 @@ const (
 	PrimitiveStaticTypeCharacter
 	PrimitiveStaticTypeMetaType
 	PrimitiveStaticTypeBlock
-	_
-	_
+	PrimitiveStaticTypeTimestamp
+	PrimitiveStaticTypeDuration
 	_
 	_
 	_
@@ -130,7 +130,7 @@ const (
 	_ // future: Fix16
 	_ // future: Fix32
 	PrimitiveStaticTypeFix64
-	_ // future: Fix128
+	PrimitiveStaticTypeFix128
 	_ // future: Fix256
 	_
 
@@ -140,7 +140,7 @@ const (
 	_ // future: UFix16
 	_ // future: UFix32
 	PrimitiveStaticTypeUFix64
-	_ // future: UFix128
+	PrimitiveStaticTypeUFix128
 	_ // future: UFix256
 	_
 
@@ -207,6 +207,12 @@ func (i PrimitiveStaticType) SemaType() sema.Type {
 	case PrimitiveStaticTypeBlock:
 		return sema.BlockType
 
+	case PrimitiveStaticTypeTimestamp:
+		return sema.TimestampType
+
+	case PrimitiveStaticTypeDuration:
+		return sema.DurationType
+
 	// Number
 
 	case PrimitiveStaticTypeNumber:
@@ -272,10 +278,14 @@ func (i PrimitiveStaticType) SemaType() sema.Type {
 	// Fix*
 	case PrimitiveStaticTypeFix64:
 		return sema.Fix64Type
+	case PrimitiveStaticTypeFix128:
+		return sema.Fix128Type
 
 	// UFix*
 	case PrimitiveStaticTypeUFix64:
 		return sema.UFix64Type
+	case PrimitiveStaticTypeUFix128:
+		return sema.UFix128Type
 
 	// Storage
 
@@ -383,10 +393,14 @@ func ConvertSemaToPrimitiveStaticType(t sema.Type) PrimitiveStaticType {
 	// Fix*
 	case sema.Fix64Type:
 		return PrimitiveStaticTypeFix64
+	case sema.Fix128Type:
+		return PrimitiveStaticTypeFix128
 
 	// UFix*
 	case sema.UFix64Type:
 		return PrimitiveStaticTypeUFix64
+	case sema.UFix128Type:
+		return PrimitiveStaticTypeUFix128
 
 	case sema.PathType:
 		return PrimitiveStaticTypePath
@@ -420,6 +434,10 @@ func ConvertSemaToPrimitiveStaticType(t sema.Type) PrimitiveStaticType {
 		return PrimitiveStaticTypePublicAccount
 	case sema.BlockType:
 		return PrimitiveStaticTypeBlock
+	case sema.TimestampType:
+		return PrimitiveStaticTypeTimestamp
+	case sema.DurationType:
+		return PrimitiveStaticTypeDuration
 	case sema.DeployedContractType:
 		return PrimitiveStaticTypeDeployedContract
 	case sema.AuthAccountContractsType:
@@ -20,6 +20,8 @@ func _() {
 	_ = x[PrimitiveStaticTypeCharacter-9]
 	_ = x[PrimitiveStaticTypeMetaType-10]
 	_ = x[PrimitiveStaticTypeBlock-11]
+	_ = x[PrimitiveStaticTypeTimestamp-12]
+	_ = x[PrimitiveStaticTypeDuration-13]
 	_ = x[PrimitiveStaticTypeNumber-18]
 	_ = x[PrimitiveStaticTypeSignedNumber-19]
 	_ = x[PrimitiveStaticTypeInteger-24]
@@ -45,7 +47,9 @@ func _() {
 	_ = x[PrimitiveStaticTypeWord32-55]
 	_ = x[PrimitiveStaticTypeWord64-56]
 	_ = x[PrimitiveStaticTypeFix64-64]
+	_ = x[PrimitiveStaticTypeFix128-65]
 	_ = x[PrimitiveStaticTypeUFix64-72]
+	_ = x[PrimitiveStaticTypeUFix128-73]
 	_ = x[PrimitiveStaticTypePath-76]
 	_ = x[PrimitiveStaticTypeCapability-77]
 	_ = x[PrimitiveStaticTypeStoragePath-78]
@@ -62,7 +66,7 @@ func _() {
 	_ = x[PrimitiveStaticTypeAccountKey-97]
 }
 
-const _PrimitiveStaticType_name = "UnknownVoidAnyNeverAnyStructAnyResourceBoolAddressStringCharacterMetaTypeBlockNumberSignedNumberIntegerSignedIntegerFixedPointSignedFixedPointIntInt8Int16Int32Int64Int128Int256UIntUInt8UInt16UInt32UInt64UInt128UInt256Word8Word16Word32Word64Fix64UFix64PathCapabilityStoragePathCapabilityPathPublicPathPrivatePathAuthAccountPublicAccountDeployedContractAuthAccountContractsPublicAccountContractsAuthAccountKeysPublicAccountKeysAccountKey"
+const _PrimitiveStaticType_name = "UnknownVoidAnyNeverAnyStructAnyResourceBoolAddressStringCharacterMetaTypeBlockTimestampDurationNumberSignedNumberIntegerSignedIntegerFixedPointSignedFixedPointIntInt8Int16Int32Int64Int128Int256UIntUInt8UInt16UInt32UInt64UInt128UInt256Word8Word16Word32Word64Fix64Fix128UFix64UFix128PathCapabilityStoragePathCapabilityPathPublicPathPrivatePathAuthAccountPublicAccountDeployedContractAuthAccountContractsPublicAccountContractsAuthAccountKeysPublicAccountKeysAccountKey"
 
 var _PrimitiveStaticType_map = map[PrimitiveStaticType]string{
 	0:  _PrimitiveStaticType_name[0:7],
@@ -77,46 +81,50 @@ var _PrimitiveStaticType_map = map[PrimitiveStaticType]string{
 	9:  _PrimitiveStaticType_name[56:65],
 	10: _PrimitiveStaticType_name[65:73],
 	11: _PrimitiveStaticType_name[73:78],
-	18: _PrimitiveStaticType_name[78:84],
-	19: _PrimitiveStaticType_name[84:96],
-	24: _PrimitiveStaticType_name[96:103],
-	25: _PrimitiveStaticType_name[103:116],
-	30: _PrimitiveStaticType_name[116:126],
-	31: _PrimitiveStaticType_name[126:142],
-	36: _PrimitiveStaticType_name[142:145],
-	37: _PrimitiveStaticType_name[145:149],
-	38: _PrimitiveStaticType_name[149:154],
-	39: _PrimitiveStaticType_name[154:159],
-	40: _PrimitiveStaticType_name[159:164],
-	41: _PrimitiveStaticType_name[164:170],
-	42: _PrimitiveStaticType_name[170:176],
-	44: _PrimitiveStaticType_name[176:180],
-	45: _PrimitiveStaticType_name[180:185],
-	46: _PrimitiveStaticType_name[185:191],
-	47: _PrimitiveStaticType_name[191:197],
-	48: _PrimitiveStaticType_name[197:203],
-	49: _PrimitiveStaticType_name[203:210],
-	50: _PrimitiveStaticType_name[210:217],
-	53: _PrimitiveStaticType_name[217:222],
-	54: _PrimitiveStaticType_name[222:228],
-	55: _PrimitiveStaticType_name[228:234],
-	56: _PrimitiveStaticType_name[234:240],
-	64: _PrimitiveStaticType_name[240:245],
-	72: _PrimitiveStaticType_name[245:251],
-	76: _PrimitiveStaticType_name[251:255],
-	77: _PrimitiveStaticType_name[255:265],
-	78: _PrimitiveStaticType_name[265:276],
-	79: _PrimitiveStaticType_name[276:290],
-	80: _PrimitiveStaticType_name[290:300],
-	81: _PrimitiveStaticType_name[300:311],
-	90: _PrimitiveStaticType_name[311:322],
-	91: _PrimitiveStaticType_name[322:335],
-	92: _PrimitiveStaticType_name[335:351],
-	93: _PrimitiveStaticType_name[351:371],
-	94: _PrimitiveStaticType_name[371:393],
-	95: _PrimitiveStaticType_name[393:408],
-	96: _PrimitiveStaticType_name[408:425],
-	97: _PrimitiveStaticType_name[425:435],
+	12: _PrimitiveStaticType_name[78:87],
+	13: _PrimitiveStaticType_name[87:95],
+	18: _PrimitiveStaticType_name[95:101],
+	19: _PrimitiveStaticType_name[101:113],
+	24: _PrimitiveStaticType_name[113:120],
+	25: _PrimitiveStaticType_name[120:133],
+	30: _PrimitiveStaticType_name[133:143],
+	31: _PrimitiveStaticType_name[143:159],
+	36: _PrimitiveStaticType_name[159:162],
+	37: _PrimitiveStaticType_name[162:166],
+	38: _PrimitiveStaticType_name[166:171],
+	39: _PrimitiveStaticType_name[171:176],
+	40: _PrimitiveStaticType_name[176:181],
+	41: _PrimitiveStaticType_name[181:187],
+	42: _PrimitiveStaticType_name[187:193],
+	44: _PrimitiveStaticType_name[193:197],
+	45: _PrimitiveStaticType_name[197:202],
+	46: _PrimitiveStaticType_name[202:208],
+	47: _PrimitiveStaticType_name[208:214],
+	48: _PrimitiveStaticType_name[214:220],
+	49: _PrimitiveStaticType_name[220:227],
+	50: _PrimitiveStaticType_name[227:234],
+	53: _PrimitiveStaticType_name[234:239],
+	54: _PrimitiveStaticType_name[239:245],
+	55: _PrimitiveStaticType_name[245:251],
+	56: _PrimitiveStaticType_name[251:257],
+	64: _PrimitiveStaticType_name[257:262],
+	65: _PrimitiveStaticType_name[262:268],
+	72: _PrimitiveStaticType_name[268:274],
+	73: _PrimitiveStaticType_name[274:281],
+	76: _PrimitiveStaticType_name[281:285],
+	77: _PrimitiveStaticType_name[285:295],
+	78: _PrimitiveStaticType_name[295:306],
+	79: _PrimitiveStaticType_name[306:320],
+	80: _PrimitiveStaticType_name[320:330],
+	81: _PrimitiveStaticType_name[330:341],
+	90: _PrimitiveStaticType_name[341:352],
+	91: _PrimitiveStaticType_name[352:365],
+	92: _PrimitiveStaticType_name[365:381],
+	93: _PrimitiveStaticType_name[381:401],
+	94: _PrimitiveStaticType_name[401:423],
+	95: _PrimitiveStaticType_name[423:438],
+	96: _PrimitiveStaticType_name[438:455],
+	97: _PrimitiveStaticType_name[455:465],
 }
 
 func (i PrimitiveStaticType) String() string {
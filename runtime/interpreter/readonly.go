@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package interpreter
+
+import (
+	"math"
+	"sync"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// ReadOnlyValue is an immutable view of a value,
+// which can be cached and shared across concurrent executions,
+// e.g. for analytics or for serving read-only scripts.
+//
+// The value and all values nested in it are copied into a separate, sealed storage
+// when the view is created, so the view does not depend on the storage the value was read from.
+//
+// The view has no owner. All mutations of the view fail with a ReadOnlyValueMutationError,
+// and so do moves of resources out of it. Other values can be copied out of the view.
+//
+// Each execution must get its own instance of the value using Value.
+// All instances share the same immutable data, which is only decoded once.
+//
+type ReadOnlyValue struct {
+	// storable is the encoded storable of the value
+	storable []byte
+	storage  readOnlyStorage
+}
+
+// DecodeReadOnlyValue decodes a value encoded by EncodeValue into a read-only view,
+// see ReadOnlyValue.
+//
+// In contrast to DecodeValue, all nested values are decoded immediately.
+//
+func DecodeReadOnlyValue(data []byte, storage atree.SlabStorage) (*ReadOnlyValue, error) {
+	value, err := DecodeValue(data, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReadOnlyValue(value)
+}
+
+// NewReadOnlyValue returns a read-only view of the given value, see ReadOnlyValue.
+//
+func NewReadOnlyValue(value Value) (result *ReadOnlyValue, err error) {
+
+	// Copying and converting values panics on errors
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = ExternalError{r}
+			}
+		}
+	}()
+
+	storage := NewInMemoryStorage()
+
+	interpreter, err := NewInterpreter(nil, nil, WithStorage(storage))
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the value to no address, so the view has no owner
+
+	value = DeepCopy(interpreter, value, atree.Address{})
+
+	storable, err := value.Storable(storage, atree.Address{}, math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedStorable, err := atree.Encode(storable, CBOREncMode)
+	if err != nil {
+		return nil, err
+	}
+
+	slabs, err := storage.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadOnlyValue{
+		storable: encodedStorable,
+		storage: readOnlyStorage{
+			slabs:   slabs,
+			decoded: &sync.Map{},
+		},
+	}, nil
+}
+
+// Value returns a new instance of the read-only value.
+//
+// The instance must not be used concurrently,
+// but instances returned by different calls can.
+//
+func (v *ReadOnlyValue) Value() Value {
+	decoder := CBORDecMode.NewByteStreamDecoder(v.storable)
+
+	storable, err := DecodeStorable(decoder, atree.StorageIDUndefined)
+	if err != nil {
+		panic(err)
+	}
+
+	return StoredValue(storable, v.storage)
+}
+
+// readOnlyStorage is the storage of read-only values.
+//
+// It contains the encoded slabs, and decodes each slab on its first retrieval.
+// The decoded slabs are never mutated, so they are shared by all instances of a read-only value.
+//
+// Values which are their own storables and have mutable state, like StringValue,
+// are copied when they are retrieved from the storage, see StringValue.StoredValue,
+// so that no mutable data is shared between instances of a read-only value.
+//
+type readOnlyStorage struct {
+	slabs map[atree.StorageID][]byte
+	// decoded are the decoded slabs, by storage ID
+	decoded *sync.Map
+}
+
+var _ atree.SlabStorage = readOnlyStorage{}
+
+func isReadOnlyStorage(storage atree.SlabStorage) bool {
+	_, ok := storage.(readOnlyStorage)
+	return ok
+}
+
+func (s readOnlyStorage) Retrieve(id atree.StorageID) (atree.Slab, bool, error) {
+	if slab, ok := s.decoded.Load(id); ok {
+		return slab.(atree.Slab), true, nil
+	}
+
+	data, ok := s.slabs[id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	slab, err := atree.DecodeSlab(id, data, CBORDecMode, DecodeStorable, DecodeTypeInfo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// If the slab was decoded concurrently, use the stored slab,
+	// so all instances share the same slab
+
+	stored, _ := s.decoded.LoadOrStore(id, slab)
+
+	return stored.(atree.Slab), true, nil
+}
+
+func (readOnlyStorage) Store(_ atree.StorageID, _ atree.Slab) error {
+	return errors.NewUnexpectedError("cannot store slab in read-only storage")
+}
+
+func (readOnlyStorage) Remove(_ atree.StorageID) error {
+	return errors.NewUnexpectedError("cannot remove slab from read-only storage")
+}
+
+func (readOnlyStorage) GenerateStorageID(_ atree.Address) (atree.StorageID, error) {
+	return atree.StorageIDUndefined, errors.NewUnexpectedError("cannot generate storage ID in read-only storage")
+}
+
+func (s readOnlyStorage) Count() int {
+	return len(s.slabs)
+}
+
+func (s readOnlyStorage) SlabIterator() (atree.SlabIterator, error) {
+	slabs := make([]atree.Slab, 0, len(s.slabs))
+	for id := range s.slabs {
+		slab, _, err := s.Retrieve(id)
+		if err != nil {
+			return nil, err
+		}
+		slabs = append(slabs, slab)
+	}
+
+	return func() (atree.StorageID, atree.Slab) {
+		if len(slabs) == 0 {
+			return atree.StorageIDUndefined, nil
+		}
+
+		slab := slabs[0]
+		slabs = slabs[1:]
+
+		return slab.ID(), slab
+	}, nil
+}
@@ -0,0 +1,255 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package interpreter_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	. "github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestReadOnlyValue(t *testing.T) {
+
+	t.Parallel()
+
+	arrayType := VariableSizedStaticType{
+		Type: VariableSizedStaticType{
+			Type: PrimitiveStaticTypeString,
+		},
+	}
+
+	newStoredArray := func(t *testing.T) (*Interpreter, *ArrayValue, *ReadOnlyValue) {
+		storage := NewInMemoryStorage()
+
+		inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+		require.NoError(t, err)
+
+		value := NewArrayValue(
+			inter,
+			arrayType,
+			common.Address(testOwner),
+			NewArrayValue(
+				inter,
+				arrayType.Type.(VariableSizedStaticType),
+				common.Address(testOwner),
+				NewStringValue("a"),
+				NewStringValue("b"),
+			),
+		)
+
+		encoded, err := EncodeValue(value, storage, atree.Address(testOwner), true)
+		require.NoError(t, err)
+
+		readOnlyValue, err := DecodeReadOnlyValue(encoded, storage)
+		require.NoError(t, err)
+
+		return inter, value, readOnlyValue
+	}
+
+	t.Run("decode", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value, readOnlyValue := newStoredArray(t)
+
+		readOnlyArray := readOnlyValue.Value().(*ArrayValue)
+
+		AssertValuesEqual(t, inter, value, readOnlyArray)
+
+		// The view has no owner
+
+		assert.Equal(t, common.Address{}, readOnlyArray.GetOwner())
+
+		nested := readOnlyArray.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue)
+		assert.Equal(t, common.Address{}, nested.GetOwner())
+
+		// The view is independent of the original value
+
+		value.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue).
+			Append(inter, ReturnEmptyLocationRange, NewStringValue("c"))
+
+		assert.Equal(t, 2, readOnlyValue.Value().(*ArrayValue).
+			Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue).
+			Count(),
+		)
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, _, readOnlyValue := newStoredArray(t)
+
+		readOnlyArray := readOnlyValue.Value().(*ArrayValue)
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				readOnlyArray.Append(
+					inter,
+					ReturnEmptyLocationRange,
+					NewArrayValue(inter, arrayType.Type.(VariableSizedStaticType), common.Address{}),
+				)
+			},
+		)
+
+		nested := readOnlyArray.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue)
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				nested.Set(inter, ReturnEmptyLocationRange, 0, NewStringValue("c"))
+			},
+		)
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				nested.Remove(inter, ReturnEmptyLocationRange, 0)
+			},
+		)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value, readOnlyValue := newStoredArray(t)
+
+		readOnlyArray := readOnlyValue.Value().(*ArrayValue)
+
+		// Moving a non-resource value out of the view copies it
+
+		copied := readOnlyArray.Transfer(
+			inter,
+			ReturnEmptyLocationRange,
+			atree.Address{},
+			true,
+			nil,
+		).(*ArrayValue)
+
+		copied.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue).
+			Append(inter, ReturnEmptyLocationRange, NewStringValue("c"))
+
+		AssertValuesEqual(t, inter, value, readOnlyValue.Value())
+		assert.Equal(t, 1, readOnlyValue.Value().(*ArrayValue).Count())
+	})
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := NewInMemoryStorage()
+
+		inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+		require.NoError(t, err)
+
+		value := NewCompositeValue(
+			inter,
+			TestLocation,
+			"R",
+			common.CompositeKindResource,
+			[]CompositeField{
+				{
+					Name:  "x",
+					Value: NewIntValueFromInt64(1),
+				},
+			},
+			common.Address(testOwner),
+		)
+
+		readOnlyValue, err := NewReadOnlyValue(value)
+		require.NoError(t, err)
+
+		readOnlyComposite := readOnlyValue.Value().(*CompositeValue)
+
+		assert.Equal(t,
+			NewIntValueFromInt64(1),
+			readOnlyComposite.GetField("x"),
+		)
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				readOnlyComposite.SetMember(inter, ReturnEmptyLocationRange, "x", NewIntValueFromInt64(2))
+			},
+		)
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				readOnlyComposite.RemoveMember(inter, ReturnEmptyLocationRange, "x")
+			},
+		)
+
+		// Resources cannot be moved out of the view
+
+		assert.PanicsWithValue(t,
+			ReadOnlyValueMutationError{},
+			func() {
+				readOnlyComposite.Transfer(
+					inter,
+					ReturnEmptyLocationRange,
+					atree.Address(testOwner),
+					true,
+					nil,
+				)
+			},
+		)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, value, readOnlyValue := newStoredArray(t)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				inter, err := NewInterpreter(nil, TestLocation, WithStorage(NewInMemoryStorage()))
+				require.NoError(t, err)
+
+				readOnlyArray := readOnlyValue.Value().(*ArrayValue)
+				nested := readOnlyArray.Get(inter, ReturnEmptyLocationRange, 0).(*ArrayValue)
+
+				assert.Equal(t,
+					NewStringValue("b"),
+					nested.Get(inter, ReturnEmptyLocationRange, 1),
+				)
+				assert.Equal(t, 1, nested.Get(inter, ReturnEmptyLocationRange, 1).(*StringValue).Length())
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 1, value.Count())
+	})
+}
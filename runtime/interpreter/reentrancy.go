@@ -0,0 +1,167 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// ReentrancyHandling specifies how re-entrant calls into contracts are handled.
+//
+// A call into a contract is re-entrant if a function of the contract is called
+// while a function of the contract is already executing, and the call comes from outside of the contract,
+// e.g. from a function of another contract, which was called by the contract through a capability.
+//
+type ReentrancyHandling uint8
+
+const (
+	// ReentrancyHandlingNone does not track re-entrant calls
+	ReentrancyHandlingNone ReentrancyHandling = iota
+	// ReentrancyHandlingTrap aborts the execution when a function of a contract,
+	// or of a composite declared in a contract, is called re-entrantly
+	ReentrancyHandlingTrap
+	// ReentrancyHandlingGuard allows re-entrant calls,
+	// and exposes if a contract is currently re-entered through the field `isReentrant` of the contract
+	ReentrancyHandlingGuard
+)
+
+type reentrancyFrame struct {
+	locationID common.LocationID
+	entry      bool
+}
+
+// reentrancyTracker tracks the locations of the functions which are currently executing.
+//
+// The tracker is shared by all interpreters of an execution, see withReentrancyTracker.
+//
+type reentrancyTracker struct {
+	frames []reentrancyFrame
+	// entries are the number of times a location was entered from another location,
+	// and not yet left
+	entries map[common.LocationID]int
+}
+
+func newReentrancyTracker() *reentrancyTracker {
+	return &reentrancyTracker{
+		entries: map[common.LocationID]int{},
+	}
+}
+
+// enter records the call of a function declared in the given location,
+// and returns true if the call is re-entrant
+//
+func (t *reentrancyTracker) enter(location common.Location) (reentrant bool) {
+	var locationID common.LocationID
+	if location != nil {
+		locationID = location.ID()
+	}
+
+	entry := true
+	if count := len(t.frames); count > 0 {
+		entry = t.frames[count-1].locationID != locationID
+	}
+
+	if entry {
+		reentrant = t.entries[locationID] > 0
+		t.entries[locationID]++
+	}
+
+	t.frames = append(t.frames, reentrancyFrame{
+		locationID: locationID,
+		entry:      entry,
+	})
+
+	return
+}
+
+// leave records the return from the innermost function
+//
+func (t *reentrancyTracker) leave() {
+	count := len(t.frames)
+	if count == 0 {
+		return
+	}
+
+	frame := t.frames[count-1]
+	t.frames = t.frames[:count-1]
+
+	if frame.entry {
+		t.entries[frame.locationID]--
+	}
+}
+
+// isReentrant returns true if the given location is currently re-entered
+//
+func (t *reentrancyTracker) isReentrant(location common.Location) bool {
+	return t.entries[location.ID()] > 1
+}
+
+// WithReentrancyHandling returns an interpreter option which sets
+// how re-entrant calls into contracts are handled.
+//
+func WithReentrancyHandling(handling ReentrancyHandling) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetReentrancyHandling(handling)
+		return nil
+	}
+}
+
+// SetReentrancyHandling sets how re-entrant calls into contracts are handled.
+//
+func (interpreter *Interpreter) SetReentrancyHandling(handling ReentrancyHandling) {
+	interpreter.reentrancyHandling = handling
+}
+
+// withReentrancyTracker returns an interpreter option which sets
+// the tracker of re-entrant calls.
+//
+func withReentrancyTracker(tracker *reentrancyTracker) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.reentrancyTracker = tracker
+		return nil
+	}
+}
+
+// enterFunction records the invocation of an interpreted function declared in the interpreter's location.
+//
+// If the invocation is a re-entrant call of a function of a composite,
+// and re-entrant calls are trapped, the execution is aborted
+//
+func (interpreter *Interpreter) enterFunction(invocation Invocation) {
+	tracker := interpreter.reentrancyTracker
+
+	reentrant := tracker.enter(interpreter.Location)
+	if reentrant &&
+		invocation.Self != nil &&
+		interpreter.reentrancyHandling == ReentrancyHandlingTrap {
+
+		tracker.leave()
+
+		panic(ReentrancyError{
+			Location:      interpreter.Location,
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+}
+
+// leaveFunction records the return from an interpreted function
+//
+func (interpreter *Interpreter) leaveFunction() {
+	interpreter.reentrancyTracker.leave()
+}
@@ -0,0 +1,72 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestReentrancyTracker(t *testing.T) {
+
+	t.Parallel()
+
+	a := common.StringLocation("A")
+	b := common.StringLocation("B")
+
+	tracker := newReentrancyTracker()
+
+	// A calls A: not re-entrant
+
+	assert.False(t, tracker.enter(a))
+	assert.False(t, tracker.enter(a))
+	assert.False(t, tracker.isReentrant(a))
+
+	// A calls B, B calls A: re-entrant
+
+	assert.False(t, tracker.enter(b))
+	assert.True(t, tracker.enter(a))
+	assert.True(t, tracker.isReentrant(a))
+	assert.False(t, tracker.isReentrant(b))
+
+	// A returns to B
+
+	tracker.leave()
+	assert.False(t, tracker.isReentrant(a))
+
+	// B returns to A, A calls B again: not re-entrant
+
+	tracker.leave()
+	assert.False(t, tracker.enter(b))
+	tracker.leave()
+
+	tracker.leave()
+	tracker.leave()
+
+	assert.Empty(t, tracker.frames)
+	assert.Zero(t, tracker.entries[a.ID()])
+	assert.Zero(t, tracker.entries[b.ID()])
+
+	// Leaving without frames is a no-op
+
+	tracker.leave()
+}
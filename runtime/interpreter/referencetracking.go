@@ -0,0 +1,98 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/atree"
+)
+
+// referenceInvalidation records if a resource-kinded container was moved.
+//
+// It is shared by all ephemeral references to the container, and by their clones,
+// so moving the container invalidates all of them at once,
+// without having to record each reference individually.
+//
+type referenceInvalidation struct {
+	invalidated bool
+}
+
+// referencedResourceKindedValues records, for each referenced resource-kinded container,
+// the invalidation which is shared by all ephemeral references to it.
+//
+// Moving a resource invalidates all references to it,
+// so references cannot be used to access a resource after it was moved,
+// e.g. into another container or into an account.
+// Accesses of destroyed resources are already prevented by checkResourceNotDestroyed.
+//
+// There is at most one entry per container, and the entry is removed
+// when the container is moved or destroyed.
+//
+type referencedResourceKindedValues map[atree.StorageID]*referenceInvalidation
+
+// trackReferencedResourceKindedValue records the given reference,
+// if it refers to a resource-kinded container
+//
+func (interpreter *Interpreter) trackReferencedResourceKindedValue(reference *EphemeralReferenceValue) {
+	if !interpreter.referenceInvalidationEnabled {
+		return
+	}
+
+	value := reference.Value
+	if someValue, ok := value.(*SomeValue); ok {
+		value = someValue.Value
+	}
+
+	container, ok := value.(containerValue)
+	if !ok || !container.IsResourceKinded(interpreter) {
+		return
+	}
+
+	storageID := container.StorageID()
+
+	invalidation, ok := interpreter.referencedResourceKindedValues[storageID]
+	if !ok {
+		invalidation = &referenceInvalidation{}
+		interpreter.referencedResourceKindedValues[storageID] = invalidation
+	}
+
+	reference.invalidation = invalidation
+}
+
+// invalidateReferencedResourceKindedValue invalidates all references
+// to the resource-kinded container with the given storage ID.
+// It is called when the container is moved.
+//
+func (interpreter *Interpreter) invalidateReferencedResourceKindedValue(storageID atree.StorageID) {
+	invalidation, ok := interpreter.referencedResourceKindedValues[storageID]
+	if !ok {
+		return
+	}
+
+	invalidation.invalidated = true
+
+	delete(interpreter.referencedResourceKindedValues, storageID)
+}
+
+// untrackReferencedResourceKindedValue stops tracking the references
+// to the resource-kinded container with the given storage ID.
+// It is called when the container is destroyed.
+//
+func (interpreter *Interpreter) untrackReferencedResourceKindedValue(storageID atree.StorageID) {
+	delete(interpreter.referencedResourceKindedValues, storageID)
+}
@@ -0,0 +1,141 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestReferencedResourceKindedValues(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("test")
+
+	resourceType := &sema.CompositeType{
+		Location:   location,
+		Identifier: "R",
+		Kind:       common.CompositeKindResource,
+		Members:    &sema.StringMemberOrderedMap{},
+	}
+
+	newResource := func(t *testing.T, options ...Option) (*Interpreter, *CompositeValue) {
+
+		elaboration := sema.NewElaboration()
+		elaboration.CompositeTypes[resourceType.ID()] = resourceType
+
+		inter, err := NewInterpreter(
+			&Program{
+				Elaboration: elaboration,
+			},
+			location,
+			append(
+				[]Option{
+					WithStorage(NewInMemoryStorage()),
+				},
+				options...,
+			)...,
+		)
+		require.NoError(t, err)
+
+		resource := NewCompositeValue(
+			inter,
+			location,
+			"R",
+			common.CompositeKindResource,
+			nil,
+			common.Address{},
+		)
+
+		return inter, resource
+	}
+
+	newReference := func(inter *Interpreter, value Value) *EphemeralReferenceValue {
+		reference := &EphemeralReferenceValue{
+			Value:        value,
+			BorrowedType: resourceType,
+		}
+		inter.trackReferencedResourceKindedValue(reference)
+		return reference
+	}
+
+	t.Run("one entry per resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, resource := newResource(t)
+
+		first := newReference(inter, resource)
+		second := newReference(inter, NewSomeValueNonCopying(resource))
+
+		require.Len(t, inter.referencedResourceKindedValues, 1)
+		assert.Same(t, first.invalidation, second.invalidation)
+	})
+
+	t.Run("move invalidates references and clones", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, resource := newResource(t)
+
+		reference := newReference(inter, resource)
+		clone := reference.Clone(inter).(*EphemeralReferenceValue)
+
+		resource.Transfer(inter, ReturnEmptyLocationRange, atree.Address{}, true, nil)
+
+		assert.True(t, reference.isInvalidated())
+		assert.True(t, clone.isInvalidated())
+		assert.Empty(t, inter.referencedResourceKindedValues)
+	})
+
+	t.Run("destroy removes entry", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, resource := newResource(t)
+
+		reference := newReference(inter, resource)
+
+		resource.Destroy(inter, ReturnEmptyLocationRange)
+
+		assert.False(t, reference.isInvalidated())
+		assert.Empty(t, inter.referencedResourceKindedValues)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, resource := newResource(t, WithReferenceInvalidationEnabled(false))
+
+		reference := newReference(inter, resource)
+
+		resource.Transfer(inter, ReturnEmptyLocationRange, atree.Address{}, true, nil)
+
+		assert.False(t, reference.isInvalidated())
+		assert.Empty(t, inter.referencedResourceKindedValues)
+	})
+}
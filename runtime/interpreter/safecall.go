@@ -0,0 +1,276 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"bytes"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// safeCallTracker tracks the effects of the active safe calls, see CallSafe.
+// The tracker is shared by all interpreters of an execution.
+//
+type safeCallTracker struct {
+	frames []*safeCallFrame
+}
+
+type safeCallFrame struct {
+	// firstTemporaryIndex is the first storage index allocated for temporary values during the call.
+	// Temporary values with a lower storage index already existed before the call
+	firstTemporaryIndex atree.StorageIndex
+	// declaredVariables are the variables declared during the call
+	declaredVariables map[*Variable]struct{}
+	// events are the events emitted during the call.
+	// They are only emitted when the call succeeds
+	events []safeCallEvent
+	// hasEffects is true if the call had effects which cannot be undone,
+	// e.g. a write to account storage, or a mutation of a value which already existed before the call
+	hasEffects bool
+}
+
+type safeCallEvent struct {
+	interpreter      *Interpreter
+	getLocationRange func() LocationRange
+	event            *CompositeValue
+	eventType        *sema.CompositeType
+}
+
+// withSafeCallTracker returns an interpreter option which sets
+// the tracker of safe calls.
+//
+func withSafeCallTracker(tracker *safeCallTracker) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.safeCallTracker = tracker
+		return nil
+	}
+}
+
+// CallSafe invokes the given function, which must not have parameters,
+// and captures its failure.
+//
+// A failure is only captured if it is a user error, e.g. a failed condition or an explicit panic,
+// and if the call had no effects up to the point of the failure.
+// Effects are e.g. writes to account storage, mutations of values which already existed before the call,
+// assignments to variables declared outside of the call, or changes to accounts, like adding a key.
+// Events emitted during the call are discarded when the failure is captured.
+//
+// Any other failure is not captured, and aborts the execution, as if the function was invoked directly.
+//
+// If the call succeeds, CallSafe returns the result and a nil error.
+// If the failure was captured, CallSafe returns a nil result and the error.
+//
+func (interpreter *Interpreter) CallSafe(
+	function FunctionValue,
+	getLocationRange func() LocationRange,
+) (
+	result Value,
+	err error,
+) {
+	tracker := interpreter.safeCallTracker
+
+	storageID, storageErr := interpreter.Storage.GenerateStorageID(atree.Address{})
+	if storageErr != nil {
+		panic(ExternalError{storageErr})
+	}
+
+	frame := &safeCallFrame{
+		firstTemporaryIndex: storageID.Index,
+	}
+	tracker.frames = append(tracker.frames, frame)
+
+	var callStackDepth int
+	if interpreter.callStack != nil {
+		callStackDepth = len(interpreter.callStack.frames)
+	}
+
+	defer func() {
+		tracker.frames = tracker.frames[:len(tracker.frames)-1]
+
+		r := recover()
+		if r == nil {
+			interpreter.completeSafeCall(frame)
+			return
+		}
+
+		failure, ok := r.(error)
+		if !ok || frame.hasEffects || !errors.IsUserError(failure) {
+			panic(r)
+		}
+
+		// The call frames of the failed call were not removed
+		if interpreter.callStack != nil {
+			interpreter.callStack.frames = interpreter.callStack.frames[:callStackDepth]
+		}
+
+		result = nil
+		err = failure
+	}()
+
+	result = function.invoke(Invocation{
+		GetLocationRange: getLocationRange,
+		Interpreter:      interpreter,
+	})
+
+	return result, nil
+}
+
+// completeSafeCall passes on the variables declared and the events emitted
+// during the successful safe call to the enclosing safe call, if any,
+// or else emits the events.
+//
+func (interpreter *Interpreter) completeSafeCall(frame *safeCallFrame) {
+	tracker := interpreter.safeCallTracker
+
+	frameCount := len(tracker.frames)
+	if frameCount > 0 {
+		parent := tracker.frames[frameCount-1]
+
+		if len(frame.declaredVariables) > 0 && parent.declaredVariables == nil {
+			parent.declaredVariables = map[*Variable]struct{}{}
+		}
+		for variable := range frame.declaredVariables {
+			parent.declaredVariables[variable] = struct{}{}
+		}
+
+		parent.events = append(parent.events, frame.events...)
+		return
+	}
+
+	for _, event := range frame.events {
+		event.interpreter.emitEvent(
+			event.getLocationRange,
+			event.event,
+			event.eventType,
+		)
+	}
+}
+
+// inSafeCall returns true if a safe call is active.
+//
+func (interpreter *Interpreter) inSafeCall() bool {
+	return len(interpreter.safeCallTracker.frames) > 0
+}
+
+// ReportEffect reports an effect which cannot be undone,
+// e.g. a change to an account which is performed by the host environment.
+//
+// A failure of an active safe call which had an effect is not captured, see CallSafe.
+//
+func (interpreter *Interpreter) ReportEffect() {
+	for _, frame := range interpreter.safeCallTracker.frames {
+		frame.hasEffects = true
+	}
+}
+
+// reportMutation reports that the container value with the given storage ID
+// is about to be mutated in-place.
+//
+// The mutation is an effect for all active safe calls,
+// unless the value was created during the call and is not stored in an account.
+//
+func (interpreter *Interpreter) reportMutation(storageID atree.StorageID) {
+	if !interpreter.inSafeCall() {
+		return
+	}
+
+	if storageID.Address != (atree.Address{}) {
+		interpreter.ReportEffect()
+		return
+	}
+
+	for _, frame := range interpreter.safeCallTracker.frames {
+		if bytes.Compare(storageID.Index[:], frame.firstTemporaryIndex[:]) < 0 {
+			frame.hasEffects = true
+		}
+	}
+}
+
+// reportVariableDeclaration reports that the given variable was declared.
+//
+func (interpreter *Interpreter) reportVariableDeclaration(variable *Variable) {
+	if !interpreter.inSafeCall() {
+		return
+	}
+
+	frames := interpreter.safeCallTracker.frames
+	frame := frames[len(frames)-1]
+	if frame.declaredVariables == nil {
+		frame.declaredVariables = map[*Variable]struct{}{}
+	}
+	frame.declaredVariables[variable] = struct{}{}
+}
+
+// reportVariableAssignment reports that the given variable is about to be assigned.
+//
+// The assignment is an effect for all active safe calls
+// for which the variable was declared outside of the call.
+//
+func (interpreter *Interpreter) reportVariableAssignment(variable *Variable) {
+	if !interpreter.inSafeCall() {
+		return
+	}
+
+	frames := interpreter.safeCallTracker.frames
+
+	// A variable declared during a safe call was also declared during all enclosing safe calls
+
+	declared := false
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		if !declared {
+			_, declared = frame.declaredVariables[variable]
+		}
+		if !declared {
+			frame.hasEffects = true
+		}
+	}
+}
+
+// emitEvent emits the given event.
+// If a safe call is active, the event is only emitted when the call succeeds.
+//
+func (interpreter *Interpreter) emitEvent(
+	getLocationRange func() LocationRange,
+	event *CompositeValue,
+	eventType *sema.CompositeType,
+) {
+	if interpreter.inSafeCall() {
+		frames := interpreter.safeCallTracker.frames
+		frame := frames[len(frames)-1]
+		frame.events = append(
+			frame.events,
+			safeCallEvent{
+				interpreter:      interpreter,
+				getLocationRange: getLocationRange,
+				event:            event,
+				eventType:        eventType,
+			},
+		)
+		return
+	}
+
+	err := interpreter.onEventEmitted(interpreter, getLocationRange, event, eventType)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -276,34 +276,80 @@ outer:
 	return t.Type.Equal(otherRestrictedType.Type)
 }
 
+// EntitlementStaticType
+
+type EntitlementStaticType struct {
+	Location            common.Location
+	QualifiedIdentifier string
+}
+
+func (t EntitlementStaticType) String() string {
+	if t.Location == nil {
+		return t.QualifiedIdentifier
+	}
+	return string(t.Location.TypeID(t.QualifiedIdentifier))
+}
+
+func (t EntitlementStaticType) Equal(other EntitlementStaticType) bool {
+	return common.LocationsMatch(other.Location, t.Location) &&
+		other.QualifiedIdentifier == t.QualifiedIdentifier
+}
+
 // ReferenceStaticType
 
 type ReferenceStaticType struct {
-	Authorized bool
-	Type       StaticType
+	Authorized   bool
+	Entitlements []EntitlementStaticType
+	Type         StaticType
 }
 
-var _ StaticType = ReferenceStaticType{}
+var _ StaticType = &ReferenceStaticType{}
 
-func (ReferenceStaticType) isStaticType() {}
+// NOTE: must be pointer receiver, as static types get used in type values,
+// which are used as keys in maps when exporting.
+// Key types in Go maps must be (transitively) hashable types,
+// and slices are not, but `Entitlements` is one.
+//
+func (*ReferenceStaticType) isStaticType() {}
 
-func (t ReferenceStaticType) String() string {
+func (t *ReferenceStaticType) String() string {
 	auth := ""
 	if t.Authorized {
 		auth = "auth "
+	} else if len(t.Entitlements) > 0 {
+		entitlements := make([]string, len(t.Entitlements))
+
+		for i, entitlement := range t.Entitlements {
+			entitlements[i] = entitlement.String()
+		}
+
+		auth = fmt.Sprintf("auth(%s) ", strings.Join(entitlements, ", "))
 	}
 
 	return fmt.Sprintf("%s&%s", auth, t.Type)
 }
 
-func (t ReferenceStaticType) Equal(other StaticType) bool {
-	otherReferenceType, ok := other.(ReferenceStaticType)
+func (t *ReferenceStaticType) Equal(other StaticType) bool {
+	otherReferenceType, ok := other.(*ReferenceStaticType)
 	if !ok {
 		return false
 	}
 
-	return t.Authorized == otherReferenceType.Authorized &&
-		t.Type.Equal(otherReferenceType.Type)
+	if t.Authorized != otherReferenceType.Authorized ||
+		len(t.Entitlements) != len(otherReferenceType.Entitlements) {
+
+		return false
+	}
+
+	// NOTE: entitlements are sorted
+
+	for i, entitlement := range t.Entitlements {
+		if !entitlement.Equal(otherReferenceType.Entitlements[i]) {
+			return false
+		}
+	}
+
+	return t.Type.Equal(otherReferenceType.Type)
 }
 
 // CapabilityStaticType
@@ -339,6 +385,36 @@ func (t CapabilityStaticType) Equal(other StaticType) bool {
 	return t.BorrowType.Equal(otherCapabilityType.BorrowType)
 }
 
+// InclusiveRangeStaticType
+
+type InclusiveRangeStaticType struct {
+	ElementType StaticType
+}
+
+var _ StaticType = InclusiveRangeStaticType{}
+
+func (InclusiveRangeStaticType) isStaticType() {}
+
+func (t InclusiveRangeStaticType) String() string {
+	if t.ElementType != nil {
+		return fmt.Sprintf("%s<%s>", sema.InclusiveRangeTypeName, t.ElementType)
+	}
+	return sema.InclusiveRangeTypeName
+}
+
+func (t InclusiveRangeStaticType) Equal(other StaticType) bool {
+	otherRangeType, ok := other.(InclusiveRangeStaticType)
+	if !ok {
+		return false
+	}
+
+	if t.ElementType == nil {
+		return otherRangeType.ElementType == nil
+	}
+
+	return t.ElementType.Equal(otherRangeType.ElementType)
+}
+
 // Conversion
 
 func ConvertSemaToStaticType(t sema.Type) StaticType {
@@ -386,6 +462,13 @@ func ConvertSemaToStaticType(t sema.Type) StaticType {
 		}
 		return result
 
+	case *sema.InclusiveRangeType:
+		result := InclusiveRangeStaticType{}
+		if t.MemberType != nil {
+			result.ElementType = ConvertSemaToStaticType(t.MemberType)
+		}
+		return result
+
 	case *sema.FunctionType:
 		return FunctionStaticType{
 			Type: t,
@@ -424,10 +507,32 @@ func ConvertSemaDictionaryTypeToStaticDictionaryType(t *sema.DictionaryType) Dic
 	}
 }
 
-func ConvertSemaReferenceTyoeToStaticReferenceType(t *sema.ReferenceType) ReferenceStaticType {
-	return ReferenceStaticType{
-		Authorized: t.Authorized,
-		Type:       ConvertSemaToStaticType(t.Type),
+func ConvertSemaReferenceTyoeToStaticReferenceType(t *sema.ReferenceType) *ReferenceStaticType {
+	return &ReferenceStaticType{
+		Authorized:   t.Authorized,
+		Entitlements: ConvertSemaEntitlementTypesToStaticEntitlementTypes(t.Entitlements),
+		Type:         ConvertSemaToStaticType(t.Type),
+	}
+}
+
+func ConvertSemaEntitlementTypesToStaticEntitlementTypes(ts []*sema.EntitlementType) []EntitlementStaticType {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	entitlements := make([]EntitlementStaticType, len(ts))
+
+	for i, entitlement := range ts {
+		entitlements[i] = ConvertSemaEntitlementTypeToStaticEntitlementType(entitlement)
+	}
+
+	return entitlements
+}
+
+func ConvertSemaEntitlementTypeToStaticEntitlementType(t *sema.EntitlementType) EntitlementStaticType {
+	return EntitlementStaticType{
+		Location:            t.Location,
+		QualifiedIdentifier: t.QualifiedIdentifier(),
 	}
 }
 
@@ -442,6 +547,7 @@ func ConvertStaticToSemaType(
 	typ StaticType,
 	getInterface func(location common.Location, qualifiedIdentifier string) (*sema.InterfaceType, error),
 	getComposite func(location common.Location, qualifiedIdentifier string, typeID common.TypeID) (*sema.CompositeType, error),
+	getEntitlement func(location common.Location, qualifiedIdentifier string) (*sema.EntitlementType, error),
 ) (_ sema.Type, err error) {
 	switch t := typ.(type) {
 	case CompositeStaticType:
@@ -451,31 +557,31 @@ func ConvertStaticToSemaType(
 		return getInterface(t.Location, t.QualifiedIdentifier)
 
 	case VariableSizedStaticType:
-		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite)
+		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite, getEntitlement)
 		return &sema.VariableSizedType{
 			Type: ty,
 		}, err
 
 	case ConstantSizedStaticType:
-		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite)
+		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite, getEntitlement)
 		return &sema.ConstantSizedType{
 			Type: ty,
 			Size: t.Size,
 		}, err
 
 	case DictionaryStaticType:
-		keyType, err := ConvertStaticToSemaType(t.KeyType, getInterface, getComposite)
+		keyType, err := ConvertStaticToSemaType(t.KeyType, getInterface, getComposite, getEntitlement)
 		if err != nil {
 			return nil, err
 		}
-		valueType, err := ConvertStaticToSemaType(t.ValueType, getInterface, getComposite)
+		valueType, err := ConvertStaticToSemaType(t.ValueType, getInterface, getComposite, getEntitlement)
 		return &sema.DictionaryType{
 			KeyType:   keyType,
 			ValueType: valueType,
 		}, err
 
 	case OptionalStaticType:
-		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite)
+		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite, getEntitlement)
 		return &sema.OptionalType{
 			Type: ty,
 		}, err
@@ -490,23 +596,36 @@ func ConvertStaticToSemaType(
 			}
 		}
 
-		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite)
+		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite, getEntitlement)
 		return &sema.RestrictedType{
 			Type:         ty,
 			Restrictions: restrictions,
 		}, err
 
-	case ReferenceStaticType:
-		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite)
+	case *ReferenceStaticType:
+		var entitlements []*sema.EntitlementType
+		if len(t.Entitlements) > 0 {
+			entitlements = make([]*sema.EntitlementType, len(t.Entitlements))
+
+			for i, entitlement := range t.Entitlements {
+				entitlements[i], err = getEntitlement(entitlement.Location, entitlement.QualifiedIdentifier)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		ty, err := ConvertStaticToSemaType(t.Type, getInterface, getComposite, getEntitlement)
 		return &sema.ReferenceType{
-			Authorized: t.Authorized,
-			Type:       ty,
+			Authorized:   t.Authorized,
+			Entitlements: entitlements,
+			Type:         ty,
 		}, err
 
 	case CapabilityStaticType:
 		var borrowType sema.Type
 		if t.BorrowType != nil {
-			borrowType, err = ConvertStaticToSemaType(t.BorrowType, getInterface, getComposite)
+			borrowType, err = ConvertStaticToSemaType(t.BorrowType, getInterface, getComposite, getEntitlement)
 			if err != nil {
 				return nil, err
 			}
@@ -516,6 +635,19 @@ func ConvertStaticToSemaType(
 			BorrowType: borrowType,
 		}, nil
 
+	case InclusiveRangeStaticType:
+		var memberType sema.Type
+		if t.ElementType != nil {
+			memberType, err = ConvertStaticToSemaType(t.ElementType, getInterface, getComposite, getEntitlement)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &sema.InclusiveRangeType{
+			MemberType: memberType,
+		}, nil
+
 	case FunctionStaticType:
 		return t.Type, nil
 
@@ -88,7 +88,7 @@ func TestCapabilityStaticType_Equal(t *testing.T) {
 			CapabilityStaticType{
 				BorrowType: PrimitiveStaticTypeString,
 			}.Equal(
-				ReferenceStaticType{
+				&ReferenceStaticType{
 					Type: PrimitiveStaticTypeString,
 				},
 			),
@@ -105,11 +105,11 @@ func TestReferenceStaticType_Equal(t *testing.T) {
 		t.Parallel()
 
 		require.True(t,
-			ReferenceStaticType{
+			(&ReferenceStaticType{
 				Authorized: false,
 				Type:       PrimitiveStaticTypeString,
-			}.Equal(
-				ReferenceStaticType{
+			}).Equal(
+				&ReferenceStaticType{
 					Authorized: false,
 					Type:       PrimitiveStaticTypeString,
 				},
@@ -122,11 +122,11 @@ func TestReferenceStaticType_Equal(t *testing.T) {
 		t.Parallel()
 
 		require.False(t,
-			ReferenceStaticType{
+			(&ReferenceStaticType{
 				Authorized: false,
 				Type:       PrimitiveStaticTypeInt,
-			}.Equal(
-				ReferenceStaticType{
+			}).Equal(
+				&ReferenceStaticType{
 					Authorized: false,
 					Type:       PrimitiveStaticTypeString,
 				},
@@ -139,11 +139,11 @@ func TestReferenceStaticType_Equal(t *testing.T) {
 		t.Parallel()
 
 		require.False(t,
-			ReferenceStaticType{
+			(&ReferenceStaticType{
 				Authorized: false,
 				Type:       PrimitiveStaticTypeInt,
-			}.Equal(
-				ReferenceStaticType{
+			}).Equal(
+				&ReferenceStaticType{
 					Authorized: true,
 					Type:       PrimitiveStaticTypeInt,
 				},
@@ -156,9 +156,9 @@ func TestReferenceStaticType_Equal(t *testing.T) {
 		t.Parallel()
 
 		require.False(t,
-			ReferenceStaticType{
+			(&ReferenceStaticType{
 				Type: PrimitiveStaticTypeString,
-			}.Equal(
+			}).Equal(
 				CapabilityStaticType{
 					BorrowType: PrimitiveStaticTypeString,
 				},
@@ -935,7 +935,7 @@ func TestRestrictedStaticType_Equal(t *testing.T) {
 					},
 				},
 			}).Equal(
-				ReferenceStaticType{
+				&ReferenceStaticType{
 					Type: PrimitiveStaticTypeInt,
 				},
 			),
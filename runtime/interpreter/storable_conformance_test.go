@@ -0,0 +1,97 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/onflow/cadence/runtime/interpreter"
+)
+
+// storableConformanceCorpusPath is the path of the conformance corpus,
+// a list of encoded storables and the string representation of their decoded values.
+//
+// The encoded storables may already be present in state,
+// so they must be decodable by all future versions of the decoder.
+//
+// !!! *WARNING* !!!
+//
+// Only add new entries, e.g. when a new value or static type is added,
+// or when the encoding of an existing one changes.
+//
+// DO *NOT* CHANGE OR REMOVE EXISTING ENTRIES!
+//
+const storableConformanceCorpusPath = "testdata/storable_conformance.json"
+
+type storableConformanceEntry struct {
+	Name    string `json:"name"`
+	Encoded string `json:"encoded"`
+	Value   string `json:"value"`
+}
+
+func TestStorableConformance(t *testing.T) {
+
+	t.Parallel()
+
+	data, err := os.ReadFile(storableConformanceCorpusPath)
+	require.NoError(t, err)
+
+	var entries []storableConformanceEntry
+	err = json.Unmarshal(data, &entries)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, entries)
+
+	for _, entry := range entries {
+
+		entry := entry
+
+		t.Run(entry.Name, func(t *testing.T) {
+
+			t.Parallel()
+
+			encoded, err := hex.DecodeString(entry.Encoded)
+			require.NoError(t, err)
+
+			decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+			decoded, err := DecodeStorable(decoder, atree.StorageIDUndefined)
+			require.NoError(t, err)
+
+			storage := NewInMemoryStorage()
+
+			decodedValue := StoredValue(decoded, storage)
+			assert.Equal(t, entry.Value, decodedValue.String())
+
+			// Re-encoding the decoded storable must result in the same encoding,
+			// otherwise re-writing unchanged values would change state
+
+			reencoded, err := atree.Encode(decoded, CBOREncMode)
+			require.NoError(t, err)
+
+			assert.Equal(t, entry.Encoded, hex.EncodeToString(reencoded))
+		})
+	}
+}
@@ -19,7 +19,11 @@
 package interpreter
 
 import (
+	"fmt"
+
 	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
 )
 
 // StorageMap is an ordered map which stores values in an account.
@@ -93,7 +97,20 @@ func (s StorageMap) ReadValue(key string) Value {
 		panic(ExternalError{err})
 	}
 
-	return StoredValue(storable, s.orderedMap.Storage)
+	value, err := storable.StoredValue(s.orderedMap.Storage)
+	if err != nil {
+		decodingErr := ValueDecodingError{
+			Owner: s.owner(),
+			Key:   key,
+			Err:   err,
+		}
+		if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+			decodingErr.StorageID = atree.StorageID(storageIDStorable)
+		}
+		panic(decodingErr)
+	}
+
+	return MustConvertStoredValue(value)
 }
 
 // WriteValue sets or removes a value in the storage map.
@@ -112,6 +129,8 @@ func (s StorageMap) WriteValue(interpreter *Interpreter, key string, value Value
 // If the given key already stores a value, it is overwritten.
 //
 func (s StorageMap) setValue(interpreter *Interpreter, key string, value Value) {
+	interpreter.reportMutation(s.StorageID())
+
 	existingStorable, err := s.orderedMap.Set(
 		stringAtreeComparator,
 		stringAtreeHashInput,
@@ -122,6 +141,13 @@ func (s StorageMap) setValue(interpreter *Interpreter, key string, value Value)
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(s.orderedMap)
+	interpreter.maybeValidateOwners(
+		value,
+		s.orderedMap.Address(),
+		func() string {
+			return key
+		},
+	)
 
 	if existingStorable != nil {
 		existingValue := StoredValue(existingStorable, interpreter.Storage)
@@ -133,6 +159,8 @@ func (s StorageMap) setValue(interpreter *Interpreter, key string, value Value)
 // removeValue removes a value in the storage map, if it exists.
 //
 func (s StorageMap) removeValue(interpreter *Interpreter, key string) {
+	interpreter.reportMutation(s.StorageID())
+
 	existingKeyStorable, existingValueStorable, err := s.orderedMap.Remove(
 		stringAtreeComparator,
 		stringAtreeHashInput,
@@ -161,6 +189,31 @@ func (s StorageMap) removeValue(interpreter *Interpreter, key string) {
 	}
 }
 
+// DetachValue removes a value in the storage map, if it exists,
+// but does not remove the slabs of the value.
+// The caller becomes responsible for the value's slabs.
+//
+func (s StorageMap) DetachValue(interpreter *Interpreter, key string) {
+	interpreter.reportMutation(s.StorageID())
+
+	existingKeyStorable, _, err := s.orderedMap.Remove(
+		stringAtreeComparator,
+		stringAtreeHashInput,
+		stringAtreeValue(key),
+	)
+	if err != nil {
+		if _, ok := err.(*atree.KeyNotFoundError); ok {
+			return
+		}
+		panic(ExternalError{err})
+	}
+	interpreter.maybeValidateAtreeValue(s.orderedMap)
+
+	// NOTE: key / field name is stringAtreeValue,
+	// and not a Value, so no need to deep remove
+	interpreter.RemoveReferencedSlab(existingKeyStorable)
+}
+
 // Iterator returns an iterator (StorageMapIterator),
 // which allows iterating over the keys and values of the storage map
 //
@@ -180,6 +233,57 @@ func (s StorageMap) StorageID() atree.StorageID {
 	return s.orderedMap.StorageID()
 }
 
+func (s StorageMap) owner() common.Address {
+	return common.Address(s.orderedMap.Address())
+}
+
+// CheckDecoding fully decodes all values stored in the storage map,
+// see Interpreter.CheckStoredValueDecoding.
+//
+// If a value fails to decode, a ValueDecodingError is returned,
+// which contains the owner and the key of the stored value.
+//
+func (s StorageMap) CheckDecoding(interpreter *Interpreter) error {
+	var keys []string
+
+	err := s.orderedMap.IterateKeys(func(key atree.Value) (resume bool, err error) {
+		keys = append(keys, string(key.(stringAtreeValue)))
+		return true, nil
+	})
+	if err != nil {
+		return ValueDecodingError{
+			Owner:     s.owner(),
+			StorageID: s.StorageID(),
+			Err:       err,
+		}
+	}
+
+	for _, key := range keys {
+		storable, err := s.orderedMap.Get(
+			stringAtreeComparator,
+			stringAtreeHashInput,
+			stringAtreeValue(key),
+		)
+		if err == nil {
+			err = interpreter.CheckStoredValueDecoding(storable)
+		}
+		if err != nil {
+			decodingErr, ok := err.(ValueDecodingError)
+			if !ok {
+				decodingErr = ValueDecodingError{
+					StorageID: s.StorageID(),
+					Err:       err,
+				}
+			}
+			decodingErr.Owner = s.owner()
+			decodingErr.Key = key
+			return decodingErr
+		}
+	}
+
+	return nil
+}
+
 // StorageMapIterator is an iterator over StorageMap
 //
 type StorageMapIterator struct {
@@ -237,3 +341,103 @@ func (i StorageMapIterator) NextValue() Value {
 
 	return MustConvertStoredValue(v)
 }
+
+// StoredValueStatistics are statistics of a value stored in a storage map
+//
+type StoredValueStatistics struct {
+	Key  string
+	Type StaticType
+	// Size is the encoded size of the value in bytes,
+	// including all slabs in which the value is stored
+	Size uint64
+	// Deferred is true if the value is (partially) stored in separate slabs,
+	// which are only loaded when the value is accessed
+	Deferred bool
+}
+
+// ValueStatistics returns the statistics of all values in the storage map,
+// in iteration order.
+//
+// The values are not decoded, only the slabs of the values are loaded
+//
+func (s StorageMap) ValueStatistics() []StoredValueStatistics {
+	var result []StoredValueStatistics
+
+	err := s.orderedMap.IterateKeys(func(k atree.Value) (resume bool, err error) {
+		key := string(k.(stringAtreeValue))
+
+		storable, err := s.orderedMap.Get(
+			stringAtreeComparator,
+			stringAtreeHashInput,
+			k,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		statistics := StoredValueStatistics{
+			Key:  key,
+			Size: uint64(storable.ByteSize()),
+		}
+
+		err = forEachReferencedSlab(
+			s.orderedMap.Storage,
+			storable,
+			func(slab atree.Slab) {
+				statistics.Size += uint64(slab.ByteSize())
+				statistics.Deferred = true
+			},
+		)
+		if err != nil {
+			return false, err
+		}
+
+		// NOTE: converting the stored value only wraps the root slab, if any,
+		// the value is not decoded
+
+		statistics.Type = StoredValue(storable, s.orderedMap.Storage).StaticType()
+
+		result = append(result, statistics)
+
+		return true, nil
+	})
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return result
+}
+
+// forEachReferencedSlab calls the given function for all slabs
+// which are (transitively) referenced by the given storable
+//
+func forEachReferencedSlab(
+	storage atree.SlabStorage,
+	storable atree.Storable,
+	f func(slab atree.Slab),
+) error {
+	if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+		storageID := atree.StorageID(storageIDStorable)
+
+		slab, found, err := storage.Retrieve(storageID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("missing slab: %s", storageID)
+		}
+
+		f(slab)
+
+		storable = slab
+	}
+
+	for _, child := range storable.ChildStorables() {
+		err := forEachReferencedSlab(storage, child, f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,470 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/format"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// The Fix64 and UFix64 types have a scale of 8, i.e. a resolution of 10 nanoseconds
+//
+const nanosecondsPerFixedPointUnit = 1_000_000_000 / sema.Fix64Factor
+
+const nanosecondsPerSecond = 1_000_000_000
+
+func safeSubInt64(a, b int64) int64 {
+	// INT32-C
+	if (b > 0) && (a < (math.MinInt64 + b)) {
+		panic(UnderflowError{})
+	} else if (b < 0) && (a > (math.MaxInt64 + b)) {
+		panic(OverflowError{})
+	}
+	return a - b
+}
+
+// TimestampValue is a point in time,
+// the number of nanoseconds since the Unix epoch
+//
+type TimestampValue int64
+
+func NewTimestampValue(unixNanoseconds int64) TimestampValue {
+	return TimestampValue(unixNanoseconds)
+}
+
+// NewTimestampValueFromUFix64 returns the timestamp
+// for the given number of seconds since the Unix epoch
+//
+func NewTimestampValueFromUFix64(unixSeconds UFix64Value) TimestampValue {
+	if unixSeconds > math.MaxInt64/nanosecondsPerFixedPointUnit {
+		panic(OverflowError{})
+	}
+	return TimestampValue(int64(unixSeconds) * nanosecondsPerFixedPointUnit)
+}
+
+var _ Value = TimestampValue(0)
+var _ atree.Storable = TimestampValue(0)
+var _ EquatableValue = TimestampValue(0)
+var _ HashableValue = TimestampValue(0)
+var _ MemberAccessibleValue = TimestampValue(0)
+
+func (TimestampValue) IsValue() {}
+
+func (v TimestampValue) Accept(interpreter *Interpreter, visitor Visitor) {
+	visitor.VisitTimestampValue(interpreter, v)
+}
+
+func (TimestampValue) Walk(_ func(Value)) {
+	// NO-OP
+}
+
+var timestampDynamicType DynamicType = TimestampDynamicType{}
+
+func (TimestampValue) DynamicType(_ *Interpreter, _ SeenReferences) DynamicType {
+	return timestampDynamicType
+}
+
+func (TimestampValue) StaticType() StaticType {
+	return PrimitiveStaticTypeTimestamp
+}
+
+func (v TimestampValue) String() string {
+	return format.Timestamp(int64(v))
+}
+
+func (v TimestampValue) RecursiveString(_ SeenReferences) string {
+	return v.String()
+}
+
+// UnixSeconds returns the number of whole seconds since the Unix epoch,
+// rounded towards negative infinity
+//
+func (v TimestampValue) UnixSeconds() int64 {
+	seconds := int64(v) / nanosecondsPerSecond
+	if int64(v)%nanosecondsPerSecond < 0 {
+		seconds--
+	}
+	return seconds
+}
+
+func (v TimestampValue) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
+	otherTimestamp, ok := other.(TimestampValue)
+	if !ok {
+		return false
+	}
+	return v == otherTimestamp
+}
+
+// HashInput returns a byte slice containing:
+// - HashInputTypeTimestamp (1 byte)
+// - nanoseconds (8 bytes)
+func (v TimestampValue) HashInput(_ *Interpreter, _ func() LocationRange, scratch []byte) []byte {
+	scratch[0] = byte(HashInputTypeTimestamp)
+	binary.BigEndian.PutUint64(scratch[1:], uint64(v))
+	return scratch[:9]
+}
+
+func (v TimestampValue) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
+	switch name {
+
+	case sema.TimestampTypeUnixSecondsFieldName:
+		return Int64Value(v.UnixSeconds())
+
+	case sema.TimestampTypeUnixNanosecondsFieldName:
+		return Int64Value(v)
+
+	case sema.TimestampTypeAddFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				duration := invocation.Arguments[0].(DurationValue)
+				return TimestampValue(safeAddInt64(int64(v), int64(duration)))
+			},
+			sema.TimestampTypeAddFunctionType,
+		)
+
+	case sema.TimestampTypeSubtractFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				duration := invocation.Arguments[0].(DurationValue)
+				return TimestampValue(safeSubInt64(int64(v), int64(duration)))
+			},
+			sema.TimestampTypeSubtractFunctionType,
+		)
+
+	case sema.TimestampTypeDurationSinceFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(TimestampValue)
+				return DurationValue(safeSubInt64(int64(v), int64(other)))
+			},
+			sema.TimestampTypeDurationSinceFunctionType,
+		)
+
+	case sema.TimestampTypeIsBeforeFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(TimestampValue)
+				return BoolValue(v < other)
+			},
+			sema.TimestampTypeComparisonFunctionType,
+		)
+
+	case sema.TimestampTypeIsAfterFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(TimestampValue)
+				return BoolValue(v > other)
+			},
+			sema.TimestampTypeComparisonFunctionType,
+		)
+
+	case sema.ToStringFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return NewStringValue(v.String())
+			},
+			sema.ToStringFunctionType,
+		)
+	}
+
+	return nil
+}
+
+func (TimestampValue) RemoveMember(_ *Interpreter, _ func() LocationRange, _ string) Value {
+	// Timestamps have no removable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (TimestampValue) SetMember(_ *Interpreter, _ func() LocationRange, _ string, _ Value) {
+	// Timestamps have no settable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (v TimestampValue) ConformsToDynamicType(
+	_ *Interpreter,
+	_ func() LocationRange,
+	dynamicType DynamicType,
+	_ TypeConformanceResults,
+) bool {
+	_, ok := dynamicType.(TimestampDynamicType)
+	return ok
+}
+
+func (TimestampValue) IsStorable() bool {
+	return true
+}
+
+func (v TimestampValue) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return v, nil
+}
+
+func (TimestampValue) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (TimestampValue) IsResourceKinded(_ *Interpreter) bool {
+	return false
+}
+
+func (v TimestampValue) Transfer(
+	interpreter *Interpreter,
+	_ func() LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+) Value {
+	if remove {
+		interpreter.RemoveReferencedSlab(storable)
+	}
+	return v
+}
+
+func (v TimestampValue) Clone(_ *Interpreter) Value {
+	return v
+}
+
+func (TimestampValue) DeepRemove(_ *Interpreter) {
+	// NO-OP
+}
+
+func (v TimestampValue) ByteSize() uint32 {
+	return cborTagSize + getIntCBORSize(int64(v))
+}
+
+func (v TimestampValue) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+	return v, nil
+}
+
+func (TimestampValue) ChildStorables() []atree.Storable {
+	return nil
+}
+
+// DurationValue is an amount of time,
+// a signed number of nanoseconds
+//
+type DurationValue int64
+
+func NewDurationValue(nanoseconds int64) DurationValue {
+	return DurationValue(nanoseconds)
+}
+
+// NewDurationValueFromFix64 returns the duration
+// for the given number of seconds
+//
+func NewDurationValueFromFix64(seconds Fix64Value) DurationValue {
+	if seconds > math.MaxInt64/nanosecondsPerFixedPointUnit {
+		panic(OverflowError{})
+	} else if seconds < math.MinInt64/nanosecondsPerFixedPointUnit {
+		panic(UnderflowError{})
+	}
+	return DurationValue(int64(seconds) * nanosecondsPerFixedPointUnit)
+}
+
+var _ Value = DurationValue(0)
+var _ atree.Storable = DurationValue(0)
+var _ EquatableValue = DurationValue(0)
+var _ HashableValue = DurationValue(0)
+var _ MemberAccessibleValue = DurationValue(0)
+
+func (DurationValue) IsValue() {}
+
+func (v DurationValue) Accept(interpreter *Interpreter, visitor Visitor) {
+	visitor.VisitDurationValue(interpreter, v)
+}
+
+func (DurationValue) Walk(_ func(Value)) {
+	// NO-OP
+}
+
+var durationDynamicType DynamicType = DurationDynamicType{}
+
+func (DurationValue) DynamicType(_ *Interpreter, _ SeenReferences) DynamicType {
+	return durationDynamicType
+}
+
+func (DurationValue) StaticType() StaticType {
+	return PrimitiveStaticTypeDuration
+}
+
+func (v DurationValue) String() string {
+	return format.Duration(int64(v))
+}
+
+func (v DurationValue) RecursiveString(_ SeenReferences) string {
+	return v.String()
+}
+
+func (v DurationValue) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
+	otherDuration, ok := other.(DurationValue)
+	if !ok {
+		return false
+	}
+	return v == otherDuration
+}
+
+// HashInput returns a byte slice containing:
+// - HashInputTypeDuration (1 byte)
+// - nanoseconds (8 bytes)
+func (v DurationValue) HashInput(_ *Interpreter, _ func() LocationRange, scratch []byte) []byte {
+	scratch[0] = byte(HashInputTypeDuration)
+	binary.BigEndian.PutUint64(scratch[1:], uint64(v))
+	return scratch[:9]
+}
+
+func (v DurationValue) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
+	switch name {
+
+	case sema.DurationTypeSecondsFieldName:
+		return Int64Value(int64(v) / nanosecondsPerSecond)
+
+	case sema.DurationTypeNanosecondsFieldName:
+		return Int64Value(v)
+
+	case sema.DurationTypeAddFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(DurationValue)
+				return DurationValue(safeAddInt64(int64(v), int64(other)))
+			},
+			sema.DurationTypeArithmeticFunctionType,
+		)
+
+	case sema.DurationTypeSubtractFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(DurationValue)
+				return DurationValue(safeSubInt64(int64(v), int64(other)))
+			},
+			sema.DurationTypeArithmeticFunctionType,
+		)
+
+	case sema.DurationTypeMultiplyFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				factor := invocation.Arguments[0].(Int64Value)
+				result := Int64Value(v).Mul(factor).(Int64Value)
+				return DurationValue(result)
+			},
+			sema.DurationTypeMultiplyFunctionType,
+		)
+
+	case sema.DurationTypeIsShorterThanFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(DurationValue)
+				return BoolValue(v < other)
+			},
+			sema.DurationTypeComparisonFunctionType,
+		)
+
+	case sema.DurationTypeIsLongerThanFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(DurationValue)
+				return BoolValue(v > other)
+			},
+			sema.DurationTypeComparisonFunctionType,
+		)
+
+	case sema.ToStringFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return NewStringValue(v.String())
+			},
+			sema.ToStringFunctionType,
+		)
+	}
+
+	return nil
+}
+
+func (DurationValue) RemoveMember(_ *Interpreter, _ func() LocationRange, _ string) Value {
+	// Durations have no removable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (DurationValue) SetMember(_ *Interpreter, _ func() LocationRange, _ string, _ Value) {
+	// Durations have no settable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (v DurationValue) ConformsToDynamicType(
+	_ *Interpreter,
+	_ func() LocationRange,
+	dynamicType DynamicType,
+	_ TypeConformanceResults,
+) bool {
+	_, ok := dynamicType.(DurationDynamicType)
+	return ok
+}
+
+func (DurationValue) IsStorable() bool {
+	return true
+}
+
+func (v DurationValue) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return v, nil
+}
+
+func (DurationValue) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (DurationValue) IsResourceKinded(_ *Interpreter) bool {
+	return false
+}
+
+func (v DurationValue) Transfer(
+	interpreter *Interpreter,
+	_ func() LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+) Value {
+	if remove {
+		interpreter.RemoveReferencedSlab(storable)
+	}
+	return v
+}
+
+func (v DurationValue) Clone(_ *Interpreter) Value {
+	return v
+}
+
+func (DurationValue) DeepRemove(_ *Interpreter) {
+	// NO-OP
+}
+
+func (v DurationValue) ByteSize() uint32 {
+	return cborTagSize + getIntCBORSize(int64(v))
+}
+
+func (v DurationValue) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+	return v, nil
+}
+
+func (DurationValue) ChildStorables() []atree.Storable {
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// transientStorage is the transaction-scoped scratch space of accounts.
+//
+// Values in transient storage are kept in memory only, they are never written to account storage.
+// The transient storage is shared by all interpreters of a transaction,
+// and it is discarded when the transaction ends, see DiscardTransientStorage.
+//
+type transientStorage map[transientStorageKey]Value
+
+type transientStorageKey struct {
+	address    common.Address
+	identifier string
+}
+
+func newTransientStorageKey(address common.Address, path PathValue) transientStorageKey {
+	return transientStorageKey{
+		address:    address,
+		identifier: path.Identifier,
+	}
+}
+
+// withTransientStorage returns an interpreter option which sets
+// the transient storage.
+//
+func withTransientStorage(storage transientStorage) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.transientStorage = storage
+		return nil
+	}
+}
+
+// DiscardTransientStorage removes all values from the transient storage
+// of all interpreters of the transaction.
+//
+func (interpreter *Interpreter) DiscardTransientStorage() {
+	for key, value := range interpreter.transientStorage {
+		value.DeepRemove(interpreter)
+		delete(interpreter.transientStorage, key)
+	}
+}
+
+func (interpreter *Interpreter) authAccountSaveTransientFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			value := invocation.Arguments[0]
+			path := invocation.Arguments[1].(PathValue)
+
+			key := newTransientStorageKey(address, path)
+
+			// Prevent an overwrite
+
+			getLocationRange := invocation.GetLocationRange
+
+			if _, ok := interpreter.transientStorage[key]; ok {
+				panic(
+					OverwriteError{
+						Address:       addressValue,
+						Path:          path,
+						LocationRange: getLocationRange(),
+					},
+				)
+			}
+
+			// Only structures can be saved, so the value is copied.
+			// The copy is not owned by the account, as it is never written to account storage
+
+			invocation.Interpreter.ReportEffect()
+
+			interpreter.transientStorage[key] = value.Transfer(
+				interpreter,
+				getLocationRange,
+				atree.Address{},
+				true,
+				nil,
+			)
+
+			return VoidValue{}
+		},
+		sema.AuthAccountTypeSaveTransientFunctionType,
+	)
+}
+
+func (interpreter *Interpreter) authAccountLoadTransientFunction(addressValue AddressValue) *HostFunctionValue {
+	return interpreter.authAccountReadTransientFunction(addressValue, true)
+}
+
+func (interpreter *Interpreter) authAccountCopyTransientFunction(addressValue AddressValue) *HostFunctionValue {
+	return interpreter.authAccountReadTransientFunction(addressValue, false)
+}
+
+func (interpreter *Interpreter) authAccountReadTransientFunction(addressValue AddressValue, clear bool) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			path := invocation.Arguments[0].(PathValue)
+
+			key := newTransientStorageKey(address, path)
+
+			value, ok := interpreter.transientStorage[key]
+			if !ok {
+				return NilValue{}
+			}
+
+			// If there is value stored for the given path,
+			// check that it satisfies the type given as the type argument.
+
+			typeParameterPair := invocation.TypeParameterTypes.Oldest()
+			if typeParameterPair == nil {
+				panic(errors.NewUnreachableError())
+			}
+
+			ty := typeParameterPair.Value
+
+			dynamicType := value.DynamicType(interpreter, SeenReferences{})
+			if !interpreter.IsSubType(dynamicType, ty) {
+				panic(ForceCastTypeMismatchError{
+					ExpectedType:  ty,
+					LocationRange: invocation.GetLocationRange(),
+				})
+			}
+
+			// Values which are loaded are moved out of the transient storage,
+			// so they do not have to be copied
+
+			if clear {
+				invocation.Interpreter.ReportEffect()
+				delete(interpreter.transientStorage, key)
+				return NewSomeValueNonCopying(value)
+			}
+
+			transferredValue := value.Transfer(
+				invocation.Interpreter,
+				invocation.GetLocationRange,
+				atree.Address{},
+				false,
+				nil,
+			)
+
+			return NewSomeValueNonCopying(transferredValue)
+		},
+
+		// same as sema.AuthAccountTypeCopyTransientFunctionType
+		sema.AuthAccountTypeLoadTransientFunctionType,
+	)
+}
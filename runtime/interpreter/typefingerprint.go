@@ -0,0 +1,188 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// TypeFingerprintStorageDomain is the storage domain in which the type fingerprints
+// of the values stored in an account are stored, see WithTypeFingerprintsEnabled.
+//
+// The type fingerprint of a value is its static type, stored as a type value,
+// with the key `<domain>/<identifier>` of the path of the value.
+// Links have no static type, so they have no fingerprint.
+//
+const TypeFingerprintStorageDomain = "type"
+
+func typeFingerprintKey(key common.StorageKey) string {
+	return key.Domain + "/" + key.Identifier
+}
+
+// WithTypeFingerprintsEnabled returns an interpreter option which sets
+// the type fingerprints option.
+//
+// When enabled, the static type of each value written to a path is stored alongside the value,
+// see TypeFingerprintStorageDomain. The fingerprints allow answering questions about the types
+// of stored values without decoding the values, e.g. in `Capability.check`.
+//
+// When disabled, no fingerprints are stored, but the fingerprints of overwritten and removed values
+// are still removed, so the remaining fingerprints are up-to-date when the option is enabled again.
+//
+func WithTypeFingerprintsEnabled(enabled bool) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetTypeFingerprintsEnabled(enabled)
+		return nil
+	}
+}
+
+// SetTypeFingerprintsEnabled sets the type fingerprints option.
+//
+func (interpreter *Interpreter) SetTypeFingerprintsEnabled(enabled bool) {
+	interpreter.typeFingerprintsEnabled = enabled
+}
+
+// writeTypeFingerprint writes the type fingerprint of the given value,
+// which is written to the given path, or removes the fingerprint if the value is nil.
+//
+// The existing fingerprint is also removed if type fingerprints are disabled,
+// so no stale fingerprint remains when they are enabled again.
+// The storage domain of the fingerprints is only created if type fingerprints are enabled
+//
+func (interpreter *Interpreter) writeTypeFingerprint(
+	address common.Address,
+	key common.StorageKey,
+	value Value,
+) {
+	enabled := interpreter.typeFingerprintsEnabled
+
+	storageMap := interpreter.Storage.GetStorageMap(address, TypeFingerprintStorageDomain, enabled)
+	if storageMap == nil {
+		return
+	}
+
+	var fingerprint Value
+	if enabled && value != nil {
+		if staticType := value.StaticType(); staticType != nil {
+			fingerprint = TypeValue{
+				Type: staticType,
+			}
+		}
+	}
+
+	storageMap.WriteValue(interpreter, typeFingerprintKey(key), fingerprint)
+}
+
+// storedTypeFingerprint returns the type fingerprint of the value stored at the given path,
+// i.e. the static type of the value, if any.
+//
+// The result is nil if type fingerprints are disabled or no fingerprint is stored,
+// e.g. because the value was stored before type fingerprints were enabled,
+// so the absence of a fingerprint does not imply the absence of a value
+//
+func (interpreter *Interpreter) storedTypeFingerprint(address common.Address, key common.StorageKey) StaticType {
+	if !interpreter.typeFingerprintsEnabled {
+		return nil
+	}
+
+	// The fingerprint is an access of the value
+
+	interpreter.journalStoredRead(address, key, nil)
+
+	fingerprintKey := common.StorageKey{
+		Domain:     TypeFingerprintStorageDomain,
+		Identifier: typeFingerprintKey(key),
+	}
+
+	var fingerprint Value
+	if interpreter.sharedStateHandler != nil {
+		readOnlyValue := interpreter.sharedStateHandler(address, fingerprintKey)
+		if readOnlyValue != nil {
+			fingerprint = readOnlyValue.Value()
+		}
+	} else {
+		storageMap := interpreter.Storage.GetStorageMap(address, TypeFingerprintStorageDomain, false)
+		if storageMap != nil {
+			fingerprint = storageMap.ReadValue(fingerprintKey.Identifier)
+		}
+	}
+
+	typeValue, ok := fingerprint.(TypeValue)
+	if !ok {
+		return nil
+	}
+
+	return typeValue.Type
+}
+
+// storedValueHasSubType returns true if the type fingerprint of the value stored at the given path
+// is a subtype of the given type. As the static type of a value is a supertype of its dynamic type,
+// the value can then be borrowed with the given type without decoding it.
+//
+// The result is false if there is no fingerprint, or if the value might only have a dynamic subtype
+//
+func (interpreter *Interpreter) storedValueHasSubType(
+	address common.Address,
+	key common.StorageKey,
+	superType sema.Type,
+) bool {
+	staticType := interpreter.storedTypeFingerprint(address, key)
+	if staticType == nil {
+		return false
+	}
+
+	return interpreter.isStaticSubType(staticType, superType)
+}
+
+// storedValueHasType returns true if the static type of the value stored at the given path
+// is a subtype of the given type.
+//
+// The type fingerprint of the value is used if there is one,
+// otherwise the value is read to determine its static type
+//
+func (interpreter *Interpreter) storedValueHasType(
+	address common.Address,
+	key common.StorageKey,
+	superType sema.Type,
+) bool {
+	staticType := interpreter.storedTypeFingerprint(address, key)
+	if staticType == nil {
+		value := interpreter.ReadStored(address, key)
+		if value == nil {
+			return false
+		}
+
+		staticType = value.StaticType()
+		if staticType == nil {
+			return false
+		}
+	}
+
+	return interpreter.isStaticSubType(staticType, superType)
+}
+
+func (interpreter *Interpreter) isStaticSubType(staticType StaticType, superType sema.Type) bool {
+	semaType, err := interpreter.ConvertStaticToSemaType(staticType)
+	if err != nil {
+		return false
+	}
+
+	return sema.IsSubType(semaType, superType)
+}
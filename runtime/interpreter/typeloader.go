@@ -0,0 +1,123 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// TypeLoader resolves user-defined types, i.e. composite, interface, and entitlement types,
+// given their location and qualified identifier.
+//
+// Decoded values only refer to their types by location and qualified identifier,
+// and their types are only resolved when needed, e.g. when the value is type checked dynamically.
+// By default, the program declaring the type is loaded (and parsed and checked, if necessary) to resolve it.
+// A type loader allows the embedder to resolve types without loading the program,
+// e.g. from type information that was recorded when the program was deployed.
+//
+// LoadType returns nil if the loader cannot resolve the type,
+// in which case the program declaring the type is loaded.
+//
+type TypeLoader interface {
+	LoadType(location common.Location, qualifiedIdentifier string) (sema.Type, error)
+}
+
+// CachingTypeLoader is a TypeLoader which caches the types resolved by another type loader.
+//
+// The cache may be shared across executions, e.g. across the transactions of a block,
+// as long as the types of the locations do not change, e.g. through a contract update.
+//
+type CachingTypeLoader struct {
+	loader TypeLoader
+	lock   sync.RWMutex
+	types  map[common.TypeID]sema.Type
+}
+
+var _ TypeLoader = &CachingTypeLoader{}
+
+func NewCachingTypeLoader(loader TypeLoader) *CachingTypeLoader {
+	return &CachingTypeLoader{
+		loader: loader,
+		types:  map[common.TypeID]sema.Type{},
+	}
+}
+
+func (l *CachingTypeLoader) LoadType(location common.Location, qualifiedIdentifier string) (sema.Type, error) {
+	typeID := location.TypeID(qualifiedIdentifier)
+
+	l.lock.RLock()
+	ty, ok := l.types[typeID]
+	l.lock.RUnlock()
+
+	if ok {
+		return ty, nil
+	}
+
+	ty, err := l.loader.LoadType(location, qualifiedIdentifier)
+	if err != nil || ty == nil {
+		return nil, err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.types[typeID] = ty
+
+	return ty, nil
+}
+
+// Invalidate removes all cached types of the given location,
+// e.g. after the contract at the location was updated
+//
+func (l *CachingTypeLoader) Invalidate(location common.Location) {
+	prefix := string(location.TypeID(""))
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for typeID := range l.types {
+		if strings.HasPrefix(string(typeID), prefix) {
+			delete(l.types, typeID)
+		}
+	}
+}
+
+// Len returns the number of cached types
+//
+func (l *CachingTypeLoader) Len() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return len(l.types)
+}
+
+// loadType resolves the given type using the type loader, if any.
+// The result is nil if there is no type loader, or the type loader cannot resolve the type
+//
+func (interpreter *Interpreter) loadType(location common.Location, qualifiedIdentifier string) (sema.Type, error) {
+	if interpreter.typeLoader == nil {
+		return nil, nil
+	}
+
+	return interpreter.typeLoader.LoadType(location, qualifiedIdentifier)
+}
@@ -0,0 +1,126 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	. "github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type testTypeLoader struct {
+	types map[common.TypeID]sema.Type
+	loads int
+}
+
+func (l *testTypeLoader) LoadType(location common.Location, qualifiedIdentifier string) (sema.Type, error) {
+	l.loads++
+	return l.types[location.TypeID(qualifiedIdentifier)], nil
+}
+
+func TestCachingTypeLoader(t *testing.T) {
+
+	t.Parallel()
+
+	location := utils.TestLocation
+
+	compositeType := &sema.CompositeType{
+		Location:   location,
+		Identifier: "S",
+		Kind:       common.CompositeKindStructure,
+	}
+
+	loader := &testTypeLoader{
+		types: map[common.TypeID]sema.Type{
+			compositeType.ID(): compositeType,
+		},
+	}
+
+	cachingLoader := NewCachingTypeLoader(loader)
+
+	for i := 0; i < 2; i++ {
+		ty, err := cachingLoader.LoadType(location, "S")
+		require.NoError(t, err)
+		assert.Same(t, compositeType, ty)
+	}
+
+	assert.Equal(t, 1, loader.loads)
+	assert.Equal(t, 1, cachingLoader.Len())
+
+	// Unresolved types are not cached
+
+	ty, err := cachingLoader.LoadType(location, "T")
+	require.NoError(t, err)
+	assert.Nil(t, ty)
+
+	assert.Equal(t, 2, loader.loads)
+	assert.Equal(t, 1, cachingLoader.Len())
+
+	// Types of other locations are not invalidated
+
+	cachingLoader.Invalidate(common.StringLocation("other"))
+	assert.Equal(t, 1, cachingLoader.Len())
+
+	cachingLoader.Invalidate(location)
+	assert.Equal(t, 0, cachingLoader.Len())
+
+	_, err = cachingLoader.LoadType(location, "S")
+	require.NoError(t, err)
+	assert.Equal(t, 3, loader.loads)
+}
+
+func TestInterpreterTypeLoader(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("other")
+
+	compositeType := &sema.CompositeType{
+		Location:   location,
+		Identifier: "S",
+		Kind:       common.CompositeKindStructure,
+	}
+
+	loader := &testTypeLoader{
+		types: map[common.TypeID]sema.Type{
+			compositeType.ID(): compositeType,
+		},
+	}
+
+	inter, err := NewInterpreter(
+		nil,
+		utils.TestLocation,
+		WithTypeLoader(loader),
+		WithImportLocationHandler(func(_ *Interpreter, location common.Location) Import {
+			require.FailNow(t, "unexpected import", location)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	ty, err := inter.GetCompositeType(location, "S", compositeType.ID())
+	require.NoError(t, err)
+	assert.Same(t, compositeType, ty)
+}
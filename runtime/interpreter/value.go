@@ -19,11 +19,13 @@
 package interpreter
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
@@ -40,9 +42,15 @@ import (
 
 type TypeConformanceResults map[typeConformanceResultEntry]bool
 
+// typeConformanceResultEntry is the key of a type conformance result.
+//
+// NOTE: Only the inner type of the reference dynamic type is part of the key,
+// as the other parts (authorization, entitlements, borrowed type) are checked
+// before the cache is consulted, and entitlements are not Go hashable
+//
 type typeConformanceResultEntry struct {
-	EphemeralReferenceValue       *EphemeralReferenceValue
-	EphemeralReferenceDynamicType EphemeralReferenceDynamicType
+	EphemeralReferenceValue *EphemeralReferenceValue
+	InnerDynamicType        DynamicType
 }
 
 // SeenReferences is a set of seen references.
@@ -864,7 +872,13 @@ func (v *StringValue) ByteSize() uint32 {
 	return cborTagSize + getBytesCBORSize([]byte(v.Str))
 }
 
-func (v *StringValue) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+func (v *StringValue) StoredValue(storage atree.SlabStorage) (atree.Value, error) {
+	// The string might be shared by concurrent instances of a read-only value,
+	// so return a copy, which has its own cached length and grapheme iterator
+	if isReadOnlyStorage(storage) {
+		return NewStringValue(v.Str), nil
+	}
+
 	return v, nil
 }
 
@@ -920,6 +934,10 @@ type ArrayValue struct {
 	array            *atree.Array
 	isDestroyed      bool
 	isResourceKinded *bool
+	// isCopyOnWrite is true if the array lazily shares
+	// the underlying atree array with other array values,
+	// see copyOnWriteValue
+	isCopyOnWrite bool
 }
 
 func NewArrayValue(
@@ -989,6 +1007,7 @@ var _ atree.Value = &ArrayValue{}
 var _ EquatableValue = &ArrayValue{}
 var _ ValueIndexableValue = &ArrayValue{}
 var _ MemberAccessibleValue = &ArrayValue{}
+var _ copyOnWriteValue = &ArrayValue{}
 
 func (*ArrayValue) IsValue() {}
 
@@ -1049,6 +1068,8 @@ func (v *ArrayValue) Destroy(interpreter *Interpreter, getLocationRange func() L
 		maybeDestroy(interpreter, getLocationRange, element)
 	})
 
+	interpreter.untrackReferencedResourceKindedValue(v.StorageID())
+
 	v.isDestroyed = true
 }
 
@@ -1144,7 +1165,13 @@ func (v *ArrayValue) Get(interpreter *Interpreter, getLocationRange func() Locat
 		panic(ExternalError{err})
 	}
 
-	return StoredValue(storable, interpreter.Storage)
+	value := StoredValue(storable, v.array.Storage)
+
+	if interpreter.journalEnabled() {
+		interpreter.journalNestedRead(v.StorageID(), journalIndexMember(index), value)
+	}
+
+	return value
 }
 
 func (v *ArrayValue) SetKey(interpreter *Interpreter, getLocationRange func() LocationRange, key Value, value Value) {
@@ -1156,6 +1183,8 @@ func (v *ArrayValue) Set(interpreter *Interpreter, getLocationRange func() Locat
 
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
+	v.prepareMutation(interpreter, getLocationRange)
+
 	element = element.Transfer(
 		interpreter,
 		getLocationRange,
@@ -1171,6 +1200,13 @@ func (v *ArrayValue) Set(interpreter *Interpreter, getLocationRange func() Locat
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(v.array)
+	interpreter.maybeValidateOwners(
+		element,
+		v.array.Address(),
+		func() string {
+			return fmt.Sprintf("[%d]", index)
+		},
+	)
 
 	existingValue := StoredValue(existingStorable, interpreter.Storage)
 
@@ -1199,6 +1235,8 @@ func (v *ArrayValue) Append(interpreter *Interpreter, getLocationRange func() Lo
 
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
+	v.prepareMutation(interpreter, getLocationRange)
+
 	element = element.Transfer(
 		interpreter,
 		getLocationRange,
@@ -1212,6 +1250,13 @@ func (v *ArrayValue) Append(interpreter *Interpreter, getLocationRange func() Lo
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(v.array)
+	interpreter.maybeValidateOwners(
+		element,
+		v.array.Address(),
+		func() string {
+			return fmt.Sprintf("[%d]", v.Count()-1)
+		},
+	)
 }
 
 func (v *ArrayValue) AppendAll(interpreter *Interpreter, getLocationRange func() LocationRange, other *ArrayValue) {
@@ -1229,6 +1274,8 @@ func (v *ArrayValue) Insert(interpreter *Interpreter, getLocationRange func() Lo
 
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
+	v.prepareMutation(interpreter, getLocationRange)
+
 	element = element.Transfer(
 		interpreter,
 		getLocationRange,
@@ -1244,6 +1291,13 @@ func (v *ArrayValue) Insert(interpreter *Interpreter, getLocationRange func() Lo
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(v.array)
+	interpreter.maybeValidateOwners(
+		element,
+		v.array.Address(),
+		func() string {
+			return fmt.Sprintf("[%d]", index)
+		},
+	)
 }
 
 func (v *ArrayValue) RemoveKey(interpreter *Interpreter, getLocationRange func() LocationRange, key Value) Value {
@@ -1252,6 +1306,9 @@ func (v *ArrayValue) RemoveKey(interpreter *Interpreter, getLocationRange func()
 }
 
 func (v *ArrayValue) Remove(interpreter *Interpreter, getLocationRange func() LocationRange, index int) Value {
+
+	v.prepareMutation(interpreter, getLocationRange)
+
 	storable, err := v.array.Remove(uint64(index))
 	if err != nil {
 		v.handleIndexOutOfBoundsError(err, index, getLocationRange)
@@ -1297,6 +1354,107 @@ func (v *ArrayValue) Contains(interpreter *Interpreter, getLocationRange func()
 	return BoolValue(result)
 }
 
+// ForEach calls the given function for each element of the array.
+//
+// The array may be mutated by the function,
+// the number of elements is determined before each call
+//
+func (v *ArrayValue) ForEach(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	function FunctionValue,
+	elementType sema.Type,
+) {
+	argumentTypes := []sema.Type{elementType}
+
+	for index := 0; index < v.Count(); index++ {
+		element := v.Get(interpreter, getLocationRange, index)
+		interpreter.invokeIterationFunction(
+			function,
+			[]Value{element},
+			argumentTypes,
+			getLocationRange,
+		)
+	}
+}
+
+// Map returns a new array containing the results of calling the given function for each element
+//
+func (v *ArrayValue) Map(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	transformFunction FunctionValue,
+	transformFunctionType *sema.FunctionType,
+) *ArrayValue {
+
+	elementType := transformFunctionType.Parameters[0].TypeAnnotation.Type
+	resultType := transformFunctionType.ReturnTypeAnnotation.Type
+
+	var results []Value
+
+	v.ForEach(
+		interpreter,
+		getLocationRange,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				result := transformFunction.invoke(invocation)
+				results = append(results, result)
+				return VoidValue{}
+			},
+			nil,
+		),
+		elementType,
+	)
+
+	return NewArrayValue(
+		interpreter,
+		VariableSizedStaticType{
+			Type: ConvertSemaToStaticType(resultType),
+		},
+		common.Address{},
+		results...,
+	)
+}
+
+// Filter returns a new array containing the elements
+// for which the given predicate function returns true
+//
+func (v *ArrayValue) Filter(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	predicateFunction FunctionValue,
+	elementType sema.Type,
+) *ArrayValue {
+
+	var results []Value
+
+	v.ForEach(
+		interpreter,
+		getLocationRange,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				element := invocation.Arguments[0]
+				if predicateFunction.invoke(invocation).(BoolValue) {
+					// The argument is already a copy of the element
+					results = append(results, element)
+				}
+				return VoidValue{}
+			},
+			nil,
+		),
+		elementType,
+	)
+
+	return NewArrayValue(
+		interpreter,
+		VariableSizedStaticType{
+			Type: v.Type.ElementType(),
+		},
+		common.Address{},
+		results...,
+	)
+}
+
 func (v *ArrayValue) GetMember(inter *Interpreter, _ func() LocationRange, name string) Value {
 	switch name {
 	case "length":
@@ -1420,6 +1578,50 @@ func (v *ArrayValue) GetMember(inter *Interpreter, _ func() LocationRange, name
 				v.SemaType(inter).ElementType(false),
 			),
 		)
+
+	case sema.ArrayTypeForEachFunctionName:
+		elementType := v.SemaType(inter).ElementType(false)
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				v.ForEach(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+					elementType,
+				)
+				return VoidValue{}
+			},
+			sema.ArrayForEachFunctionType(elementType),
+		)
+
+	case sema.ArrayTypeMapFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return v.Map(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+					invocation.ArgumentTypes[0].(*sema.FunctionType),
+				)
+			},
+			sema.ArrayMapFunctionType(
+				v.SemaType(inter).ElementType(false),
+			),
+		)
+
+	case sema.ArrayTypeFilterFunctionName:
+		elementType := v.SemaType(inter).ElementType(false)
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return v.Filter(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+					elementType,
+				)
+			},
+			sema.ArrayFilterFunctionType(elementType),
+		)
 	}
 
 	return nil
@@ -1533,6 +1735,31 @@ func (v *ArrayValue) Transfer(
 	needsStoreTo := v.NeedsStoreTo(address)
 	isResourceKinded := v.IsResourceKinded(interpreter)
 
+	if v.isReadOnly() {
+		// Read-only values can be copied, but not moved
+		if isResourceKinded {
+			panic(ReadOnlyValueMutationError{
+				LocationRange: getLocationRange(),
+			})
+		}
+		remove = false
+	}
+
+	if isResourceKinded {
+		// Moving the resource invalidates all references to it
+		interpreter.invalidateReferencedResourceKindedValue(v.StorageID())
+	}
+
+	if remove {
+		if v.isCopyOnWrite {
+			// The elements are shared with other arrays,
+			// so they must be copied, but not removed
+			remove = false
+		} else {
+			interpreter.materializeCopyOnWriteValues(v.StorageID())
+		}
+	}
+
 	if needsStoreTo || !isResourceKinded {
 
 		iterator, err := v.array.Iterator()
@@ -1629,6 +1856,14 @@ func (v *ArrayValue) Clone(interpreter *Interpreter) Value {
 
 func (v *ArrayValue) DeepRemove(interpreter *Interpreter) {
 
+	if v.isCopyOnWrite {
+		// The elements are shared with other arrays,
+		// so they must not be removed
+		return
+	}
+
+	interpreter.materializeCopyOnWriteValues(v.StorageID())
+
 	// Remove nested values and storables
 
 	storage := v.array.Storage
@@ -1671,6 +1906,100 @@ func (v *ArrayValue) IsResourceKinded(interpreter *Interpreter) bool {
 	return *v.isResourceKinded
 }
 
+func (v *ArrayValue) canCopyOnWrite(interpreter *Interpreter) bool {
+	return !v.NeedsStoreTo(atree.Address{}) &&
+		!v.isReadOnly() &&
+		!v.IsResourceKinded(interpreter) &&
+		isCopyOnWriteElementStaticType(v.Type.ElementType())
+}
+
+func (v *ArrayValue) copyOnWrite(interpreter *Interpreter) Value {
+	array := v.array
+	if !v.isCopyOnWrite {
+		array = interpreter.copyOnWriteArrayShare(v.array).header
+	}
+
+	return &ArrayValue{
+		Type:             v.Type,
+		semaType:         v.semaType,
+		isResourceKinded: v.isResourceKinded,
+		array:            array,
+		isDestroyed:      v.isDestroyed,
+		isCopyOnWrite:    true,
+	}
+}
+
+// prepareMutation must be called before the array is mutated in-place
+//
+func (v *ArrayValue) prepareMutation(interpreter *Interpreter, getLocationRange func() LocationRange) {
+	if !v.isCopyOnWrite && v.isReadOnly() {
+		panic(ReadOnlyValueMutationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+
+	interpreter.reportMutation(v.StorageID())
+	interpreter.journalNestedWrite(v.StorageID())
+
+	if v.isCopyOnWrite {
+		v.materializeCopyOnWrite(interpreter)
+	} else {
+		interpreter.materializeCopyOnWriteValues(v.StorageID())
+	}
+}
+
+// isReadOnly returns true if the array is part of a read-only value, see ReadOnlyValue
+//
+func (v *ArrayValue) isReadOnly() bool {
+	return isReadOnlyStorage(v.array.Storage)
+}
+
+func (v *ArrayValue) materializeCopyOnWrite(interpreter *Interpreter) {
+	if !v.isCopyOnWrite {
+		return
+	}
+
+	v.array = copyAtreeArray(interpreter, v.array)
+	v.isCopyOnWrite = false
+}
+
+// copyAtreeArray returns a copy of the given atree array, in the same account
+//
+func copyAtreeArray(interpreter *Interpreter, array *atree.Array) *atree.Array {
+
+	iterator, err := array.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	address := array.Address()
+
+	result, err := atree.NewArrayFromBatchData(
+		interpreter.Storage,
+		address,
+		array.Type(),
+		func() (atree.Value, error) {
+			value, err := iterator.Next()
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				return nil, nil
+			}
+
+			element := MustConvertStoredValue(value).
+				Transfer(interpreter, ReturnEmptyLocationRange, address, false, nil)
+
+			return element, nil
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return result
+}
+
 // NumberValue
 //
 type NumberValue interface {
@@ -1770,6 +2099,34 @@ func getNumberValueMember(v NumberValue, name string, typ sema.Type) Value {
 				),
 			},
 		)
+
+	case sema.FixedPointTypeMultiplyRoundFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(NumberValue)
+				rule := roundingRuleFromValue(invocation.Arguments[1])
+				return v.(FixedPointValue).MulRound(other, rule)
+			},
+			&sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(
+					typ,
+				),
+			},
+		)
+
+	case sema.FixedPointTypeDivideRoundFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				other := invocation.Arguments[0].(NumberValue)
+				rule := roundingRuleFromValue(invocation.Arguments[1])
+				return v.(FixedPointValue).DivRound(other, rule)
+			},
+			&sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(
+					typ,
+				),
+			},
+		)
 	}
 
 	return nil
@@ -1792,6 +2149,63 @@ type BigNumberValue interface {
 	ToBigInt() *big.Int
 }
 
+// FixedPointValue is a fixed-point number value,
+// which supports arithmetic with an explicit rounding rule
+//
+type FixedPointValue interface {
+	NumberValue
+	MulRound(other NumberValue, rule sema.RoundingRule) NumberValue
+	DivRound(other NumberValue, rule sema.RoundingRule) NumberValue
+}
+
+// roundedQuotient returns n / d, rounded according to the given rule
+//
+func roundedQuotient(n, d *big.Int, rule sema.RoundingRule) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	var roundAwayFromZero bool
+
+	switch rule {
+	case sema.RoundingRuleTowardZero:
+		roundAwayFromZero = false
+
+	case sema.RoundingRuleAwayFromZero:
+		roundAwayFromZero = true
+
+	case sema.RoundingRuleNearestHalfAway, sema.RoundingRuleNearestHalfEven:
+		// Compare the discarded remainder with half of the divisor
+
+		twiceRemainder := new(big.Int).Abs(remainder)
+		twiceRemainder.Lsh(twiceRemainder, 1)
+
+		switch twiceRemainder.Cmp(new(big.Int).Abs(d)) {
+		case 1:
+			roundAwayFromZero = true
+		case 0:
+			roundAwayFromZero = rule == sema.RoundingRuleNearestHalfAway ||
+				quotient.Bit(0) == 1
+		}
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	if roundAwayFromZero {
+		sign := int64(n.Sign() * d.Sign())
+		quotient.Add(quotient, big.NewInt(sign))
+	}
+
+	return quotient
+}
+
+func roundingRuleFromValue(value Value) sema.RoundingRule {
+	rawValue := value.(*CompositeValue).GetField(sema.EnumRawValueFieldName)
+	return sema.RoundingRule(rawValue.(UInt8Value))
+}
+
 // Int
 
 type IntValue struct {
@@ -9561,6 +9975,7 @@ func NewFix64ValueWithInteger(integer int64) Fix64Value {
 var _ Value = Fix64Value(0)
 var _ atree.Storable = Fix64Value(0)
 var _ NumberValue = Fix64Value(0)
+var _ FixedPointValue = Fix64Value(0)
 var _ EquatableValue = Fix64Value(0)
 var _ HashableValue = Fix64Value(0)
 var _ MemberAccessibleValue = Fix64Value(0)
@@ -9778,36 +10193,89 @@ func (v Fix64Value) SaturatingDiv(other NumberValue) NumberValue {
 	return Fix64Value(result.Int64())
 }
 
-func (v Fix64Value) Mod(other NumberValue) NumberValue {
+func (v Fix64Value) MulRound(other NumberValue, rule sema.RoundingRule) NumberValue {
 	o, ok := other.(Fix64Value)
 	if !ok {
 		panic(InvalidOperandsError{
-			Operation: ast.OperationMod,
-			LeftType:  v.StaticType(),
-			RightType: other.StaticType(),
+			FunctionName: sema.FixedPointTypeMultiplyRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
 		})
 	}
 
-	// v - int(v/o) * o
-	quotient := v.Div(o).(Fix64Value)
-	truncatedQuotient := (int64(quotient) / sema.Fix64Factor) * sema.Fix64Factor
-	return v.Minus(Fix64Value(truncatedQuotient).Mul(o))
-}
+	a := new(big.Int).SetInt64(int64(v))
+	b := new(big.Int).SetInt64(int64(o))
 
-func (v Fix64Value) Less(other NumberValue) BoolValue {
-	return v < other.(Fix64Value)
-}
+	result := roundedQuotient(new(big.Int).Mul(a, b), sema.Fix64FactorBig, rule)
 
-func (v Fix64Value) LessEqual(other NumberValue) BoolValue {
-	return v <= other.(Fix64Value)
-}
+	if result.Cmp(minInt64Big) < 0 {
+		panic(UnderflowError{})
+	} else if result.Cmp(maxInt64Big) > 0 {
+		panic(OverflowError{})
+	}
 
-func (v Fix64Value) Greater(other NumberValue) BoolValue {
-	return v > other.(Fix64Value)
+	return Fix64Value(result.Int64())
 }
 
-func (v Fix64Value) GreaterEqual(other NumberValue) BoolValue {
-	return v >= other.(Fix64Value)
+func (v Fix64Value) DivRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(Fix64Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeDivideRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	a := new(big.Int).SetInt64(int64(v))
+	b := new(big.Int).SetInt64(int64(o))
+
+	result := roundedQuotient(new(big.Int).Mul(a, sema.Fix64FactorBig), b, rule)
+
+	if result.Cmp(minInt64Big) < 0 {
+		panic(UnderflowError{})
+	} else if result.Cmp(maxInt64Big) > 0 {
+		panic(OverflowError{})
+	}
+
+	return Fix64Value(result.Int64())
+}
+
+func (v Fix64Value) Mod(other NumberValue) NumberValue {
+	o, ok := other.(Fix64Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMod,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	// v - int(v/o) * o
+	quotient := v.Div(o).(Fix64Value)
+	truncatedQuotient := (int64(quotient) / sema.Fix64Factor) * sema.Fix64Factor
+	return v.Minus(Fix64Value(truncatedQuotient).Mul(o))
+}
+
+func (v Fix64Value) Less(other NumberValue) BoolValue {
+	return v < other.(Fix64Value)
+}
+
+func (v Fix64Value) LessEqual(other NumberValue) BoolValue {
+	return v <= other.(Fix64Value)
+}
+
+func (v Fix64Value) Greater(other NumberValue) BoolValue {
+	return v > other.(Fix64Value)
+}
+
+func (v Fix64Value) GreaterEqual(other NumberValue) BoolValue {
+	return v >= other.(Fix64Value)
 }
 
 func (v Fix64Value) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
@@ -9838,6 +10306,12 @@ func ConvertFix64(value Value) Fix64Value {
 		}
 		return Fix64Value(value)
 
+	case Fix128Value:
+		return convertFix128ToFix64(value.BigInt)
+
+	case UFix128Value:
+		return convertFix128ToFix64(value.BigInt)
+
 	case BigNumberValue:
 		v := value.ToBigInt()
 
@@ -9862,6 +10336,21 @@ func ConvertFix64(value Value) Fix64Value {
 	}
 }
 
+// convertFix128ToFix64 converts the given Fix128 or UFix128 value to Fix64.
+// The fractional digits which exceed the scale of Fix64 are truncated
+//
+func convertFix128ToFix64(value *big.Int) Fix64Value {
+	res := new(big.Int).Quo(value, fix64ToFix128FactorBig)
+
+	if res.Cmp(minInt64Big) < 0 {
+		panic(UnderflowError{})
+	} else if res.Cmp(maxInt64Big) > 0 {
+		panic(OverflowError{})
+	}
+
+	return Fix64Value(res.Int64())
+}
+
 func (v Fix64Value) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
 	return getNumberValueMember(v, name, sema.Fix64Type)
 }
@@ -9958,6 +10447,7 @@ func NewUFix64ValueWithInteger(integer uint64) UFix64Value {
 var _ Value = UFix64Value(0)
 var _ atree.Storable = UFix64Value(0)
 var _ NumberValue = UFix64Value(0)
+var _ FixedPointValue = UFix64Value(0)
 var _ EquatableValue = UFix64Value(0)
 var _ HashableValue = UFix64Value(0)
 var _ MemberAccessibleValue = UFix64Value(0)
@@ -10147,6 +10637,55 @@ func (v UFix64Value) SaturatingDiv(other NumberValue) NumberValue {
 	return v.Div(other)
 }
 
+func (v UFix64Value) MulRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(UFix64Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeMultiplyRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	a := new(big.Int).SetUint64(uint64(v))
+	b := new(big.Int).SetUint64(uint64(o))
+
+	result := roundedQuotient(new(big.Int).Mul(a, b), sema.Fix64FactorBig, rule)
+
+	if !result.IsUint64() {
+		panic(OverflowError{})
+	}
+
+	return UFix64Value(result.Uint64())
+}
+
+func (v UFix64Value) DivRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(UFix64Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeDivideRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	a := new(big.Int).SetUint64(uint64(v))
+	b := new(big.Int).SetUint64(uint64(o))
+
+	result := roundedQuotient(new(big.Int).Mul(a, sema.Fix64FactorBig), b, rule)
+
+	if !result.IsUint64() {
+		panic(OverflowError{})
+	}
+
+	return UFix64Value(result.Uint64())
+}
+
 func (v UFix64Value) Mod(other NumberValue) NumberValue {
 	o, ok := other.(UFix64Value)
 	if !ok {
@@ -10207,6 +10746,12 @@ func ConvertUFix64(value Value) UFix64Value {
 		}
 		return UFix64Value(value)
 
+	case Fix128Value:
+		return convertFix128ToUFix64(value.BigInt)
+
+	case UFix128Value:
+		return convertFix128ToUFix64(value.BigInt)
+
 	case BigNumberValue:
 		v := value.ToBigInt()
 
@@ -10238,6 +10783,21 @@ func ConvertUFix64(value Value) UFix64Value {
 	}
 }
 
+// convertFix128ToUFix64 converts the given Fix128 or UFix128 value to UFix64.
+// The fractional digits which exceed the scale of UFix64 are truncated
+//
+func convertFix128ToUFix64(value *big.Int) UFix64Value {
+	res := new(big.Int).Quo(value, fix64ToFix128FactorBig)
+
+	if res.Sign() < 0 {
+		panic(UnderflowError{})
+	} else if !res.IsUint64() {
+		panic(OverflowError{})
+	}
+
+	return UFix64Value(res.Uint64())
+}
+
 func (v UFix64Value) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
 	return getNumberValueMember(v, name, sema.UFix64Type)
 }
@@ -10317,150 +10877,1061 @@ func (UFix64Value) ChildStorables() []atree.Storable {
 	return nil
 }
 
-// CompositeValue
-
-type CompositeValue struct {
-	dictionary          *atree.OrderedMap
-	Location            common.Location
-	QualifiedIdentifier string
-	Kind                common.CompositeKind
-	InjectedFields      map[string]Value
-	ComputedFields      map[string]ComputedField
-	NestedVariables     map[string]*Variable
-	Functions           map[string]FunctionValue
-	Destructor          FunctionValue
-	Stringer            func(value *CompositeValue, seenReferences SeenReferences) string
-	isDestroyed         bool
-	typeID              common.TypeID
-	staticType          StaticType
-	dynamicType         DynamicType
+// Fix128Value
+//
+// Arithmetic operations round towards zero,
+// e.g. the result of a division is truncated.
+//
+type Fix128Value struct {
+	BigInt *big.Int
 }
 
-type ComputedField func(*Interpreter, func() LocationRange) Value
-
-type CompositeField struct {
-	Name  string
-	Value Value
+func NewFix128ValueFromBigInt(value *big.Int) Fix128Value {
+	return Fix128Value{BigInt: value}
 }
 
-func NewCompositeValue(
-	interpreter *Interpreter,
-	location common.Location,
-	qualifiedIdentifier string,
-	kind common.CompositeKind,
-	fields []CompositeField,
-	address common.Address,
-) *CompositeValue {
+// NewFix128ValueWithInteger returns the Fix128 value for the given integer.
+// It panics if the integer is outside of the range of Fix128.
+//
+func NewFix128ValueWithInteger(integer *big.Int) Fix128Value {
 
-	dictionary, err := atree.NewMap(
-		interpreter.Storage,
-		atree.Address(address),
-		atree.NewDefaultDigesterBuilder(),
-		compositeTypeInfo{
-			location:            location,
-			qualifiedIdentifier: qualifiedIdentifier,
-			kind:                kind,
-		},
-	)
-	if err != nil {
-		panic(ExternalError{err})
+	if integer.Cmp(sema.Fix128TypeMinIntBig) < 0 {
+		panic(UnderflowError{})
 	}
 
-	v := &CompositeValue{
-		dictionary:          dictionary,
-		Location:            location,
-		QualifiedIdentifier: qualifiedIdentifier,
-		Kind:                kind,
+	if integer.Cmp(sema.Fix128TypeMaxIntBig) > 0 {
+		panic(OverflowError{})
 	}
 
-	for _, field := range fields {
-		v.SetMember(
-			interpreter,
-			// TODO: provide proper location range
-			ReturnEmptyLocationRange,
-			field.Name,
-			field.Value,
-		)
-	}
+	return Fix128Value{new(big.Int).Mul(integer, sema.Fix128FactorBig)}
+}
 
-	return v
+var _ Value = Fix128Value{}
+var _ atree.Storable = Fix128Value{}
+var _ NumberValue = Fix128Value{}
+var _ FixedPointValue = Fix128Value{}
+var _ BigNumberValue = Fix128Value{}
+var _ EquatableValue = Fix128Value{}
+var _ HashableValue = Fix128Value{}
+var _ MemberAccessibleValue = Fix128Value{}
+
+func (Fix128Value) IsValue() {}
+
+func (v Fix128Value) Accept(interpreter *Interpreter, visitor Visitor) {
+	visitor.VisitFix128Value(interpreter, v)
 }
 
-var _ Value = &CompositeValue{}
-var _ EquatableValue = &CompositeValue{}
-var _ HashableValue = &CompositeValue{}
-var _ MemberAccessibleValue = &CompositeValue{}
+func (Fix128Value) Walk(_ func(Value)) {
+	// NO-OP
+}
 
-func (*CompositeValue) IsValue() {}
+var fix128DynamicType DynamicType = NumberDynamicType{sema.Fix128Type}
 
-func (v *CompositeValue) Accept(interpreter *Interpreter, visitor Visitor) {
-	descend := visitor.VisitCompositeValue(interpreter, v)
-	if !descend {
-		return
-	}
+func (Fix128Value) DynamicType(_ *Interpreter, _ SeenReferences) DynamicType {
+	return fix128DynamicType
+}
 
-	v.ForEachField(func(_ string, value Value) {
-		value.Accept(interpreter, visitor)
-	})
+func (Fix128Value) StaticType() StaticType {
+	return PrimitiveStaticTypeFix128
 }
 
-// Walk iterates over all field values of the composite value.
-// It does NOT walk the computed fields and functions!
-//
-func (v *CompositeValue) Walk(walkChild func(Value)) {
-	v.ForEachField(func(_ string, value Value) {
-		walkChild(value)
-	})
+func (v Fix128Value) String() string {
+	return format.Fix128(v.BigInt)
 }
 
-func (v *CompositeValue) DynamicType(interpreter *Interpreter, _ SeenReferences) DynamicType {
-	if v.dynamicType == nil {
-		var staticType sema.Type
-		var err error
-		if v.Location == nil {
-			staticType, err = interpreter.getNativeCompositeType(v.QualifiedIdentifier)
-		} else {
-			staticType, err = interpreter.getUserCompositeType(v.Location, v.TypeID())
-		}
-		if err != nil {
-			panic(err)
-		}
-		v.dynamicType = CompositeDynamicType{
-			StaticType: staticType,
-		}
-	}
-	return v.dynamicType
+func (v Fix128Value) RecursiveString(_ SeenReferences) string {
+	return v.String()
 }
 
-func (v *CompositeValue) StaticType() StaticType {
-	if v.staticType == nil {
-		// NOTE: Instead of using NewCompositeStaticType, which always generates the type ID,
-		// use the TypeID accessor, which may return an already computed type ID
-		v.staticType = CompositeStaticType{
-			Location:            v.Location,
-			QualifiedIdentifier: v.QualifiedIdentifier,
-			TypeID:              v.TypeID(),
-		}
+func (v Fix128Value) ToInt() int {
+	integer := v.ToBigInt()
+	if !integer.IsInt64() {
+		panic(OverflowError{})
 	}
-	return v.staticType
+	return int(integer.Int64())
 }
 
-func (v *CompositeValue) IsDestroyed() bool {
-	return v.isDestroyed
+// ToBigInt returns the integer part of the value
+//
+func (v Fix128Value) ToBigInt() *big.Int {
+	return new(big.Int).Quo(v.BigInt, sema.Fix128FactorBig)
 }
 
-func (v *CompositeValue) Destroy(interpreter *Interpreter, getLocationRange func() LocationRange) {
-	interpreter = v.getInterpreter(interpreter)
+func (v Fix128Value) Negate() NumberValue {
+	// INT32-C
+	if v.BigInt.Cmp(sema.Fix128TypeMinBig) == 0 {
+		panic(OverflowError{})
+	}
+	return Fix128Value{new(big.Int).Neg(v.BigInt)}
+}
 
-	// if composite was deserialized, dynamically link in the destructor
-	if v.Destructor == nil {
-		v.Destructor = interpreter.typeCodes.CompositeCodes[v.TypeID()].DestructorFunction
+// checkFix128Range panics if the given result is outside the range of Fix128
+//
+func checkFix128Range(res *big.Int) Fix128Value {
+	if res.Cmp(sema.Fix128TypeMinBig) < 0 {
+		panic(UnderflowError{})
+	} else if res.Cmp(sema.Fix128TypeMaxBig) > 0 {
+		panic(OverflowError{})
 	}
+	return Fix128Value{res}
+}
 
-	destructor := v.Destructor
+// saturateFix128Range returns the minimum or maximum Fix128 value
+// if the given result is outside the range of Fix128
+//
+func saturateFix128Range(res *big.Int) Fix128Value {
+	if res.Cmp(sema.Fix128TypeMinBig) < 0 {
+		return Fix128Value{sema.Fix128TypeMinBig}
+	} else if res.Cmp(sema.Fix128TypeMaxBig) > 0 {
+		return Fix128Value{sema.Fix128TypeMaxBig}
+	}
+	return Fix128Value{res}
+}
 
-	if destructor != nil {
-		invocation := Invocation{
+func (v Fix128Value) Plus(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationPlus,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Add(v.BigInt, o.BigInt)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) SaturatingPlus(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingAddFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Add(v.BigInt, o.BigInt)
+	return saturateFix128Range(res)
+}
+
+func (v Fix128Value) Minus(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMinus,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Sub(v.BigInt, o.BigInt)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) SaturatingMinus(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingSubtractFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Sub(v.BigInt, o.BigInt)
+	return saturateFix128Range(res)
+}
+
+func (v Fix128Value) Mul(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMul,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res.Quo(res, sema.Fix128FactorBig)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) SaturatingMul(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingMultiplyFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res.Quo(res, sema.Fix128FactorBig)
+	return saturateFix128Range(res)
+}
+
+// Div divides the value by the other value.
+// The quotient is truncated, i.e. rounded towards zero
+//
+func (v Fix128Value) Div(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationDiv,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res.Quo(res, o.BigInt)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) SaturatingDiv(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingDivideFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res.Quo(res, o.BigInt)
+	return saturateFix128Range(res)
+}
+
+func (v Fix128Value) MulRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeMultiplyRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res = roundedQuotient(res, sema.Fix128FactorBig, rule)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) DivRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeDivideRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res = roundedQuotient(res, o.BigInt, rule)
+	return checkFix128Range(res)
+}
+
+func (v Fix128Value) Mod(other NumberValue) NumberValue {
+	o, ok := other.(Fix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMod,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	// v - int(v/o) * o
+	res := new(big.Int).Rem(v.BigInt, o.BigInt)
+	return Fix128Value{res}
+}
+
+func (v Fix128Value) Less(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(Fix128Value).BigInt)
+	return cmp == -1
+}
+
+func (v Fix128Value) LessEqual(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(Fix128Value).BigInt)
+	return cmp <= 0
+}
+
+func (v Fix128Value) Greater(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(Fix128Value).BigInt)
+	return cmp == 1
+}
+
+func (v Fix128Value) GreaterEqual(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(Fix128Value).BigInt)
+	return cmp >= 0
+}
+
+func (v Fix128Value) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
+	otherFix128, ok := other.(Fix128Value)
+	if !ok {
+		return false
+	}
+	cmp := v.BigInt.Cmp(otherFix128.BigInt)
+	return cmp == 0
+}
+
+// HashInput returns a byte slice containing:
+// - HashInputTypeFix128 (1 byte)
+// - big int value encoded in big-endian (n bytes)
+func (v Fix128Value) HashInput(_ *Interpreter, _ func() LocationRange, scratch []byte) []byte {
+	b := SignedBigIntToBigEndianBytes(v.BigInt)
+
+	length := 1 + len(b)
+	var buffer []byte
+	if length <= len(scratch) {
+		buffer = scratch[:length]
+	} else {
+		buffer = make([]byte, length)
+	}
+
+	buffer[0] = byte(HashInputTypeFix128)
+	copy(buffer[1:], b)
+	return buffer
+}
+
+// fix64ToFix128FactorBig is the factor between the scale of Fix64 and UFix64,
+// and the scale of Fix128 and UFix128
+//
+var fix64ToFix128FactorBig = new(big.Int).Quo(sema.Fix128FactorBig, sema.Fix64FactorBig)
+
+func ConvertFix128(value Value) Fix128Value {
+	switch value := value.(type) {
+	case Fix128Value:
+		return value
+
+	case UFix128Value:
+		return checkFix128Range(new(big.Int).Set(value.BigInt))
+
+	case Fix64Value:
+		// Fix64 values are always in the range of Fix128
+		res := new(big.Int).SetInt64(int64(value))
+		return Fix128Value{res.Mul(res, fix64ToFix128FactorBig)}
+
+	case UFix64Value:
+		// UFix64 values are always in the range of Fix128
+		res := new(big.Int).SetUint64(uint64(value))
+		return Fix128Value{res.Mul(res, fix64ToFix128FactorBig)}
+
+	case BigNumberValue:
+		return NewFix128ValueWithInteger(value.ToBigInt())
+
+	case NumberValue:
+		return NewFix128ValueWithInteger(big.NewInt(int64(value.ToInt())))
+
+	default:
+		panic(fmt.Sprintf("can't convert to Fix128: %s", value))
+	}
+}
+
+func (v Fix128Value) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
+	return getNumberValueMember(v, name, sema.Fix128Type)
+}
+
+func (Fix128Value) RemoveMember(_ *Interpreter, _ func() LocationRange, _ string) Value {
+	// Numbers have no removable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (Fix128Value) SetMember(_ *Interpreter, _ func() LocationRange, _ string, _ Value) {
+	// Numbers have no settable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (v Fix128Value) ToBigEndianBytes() []byte {
+	return SignedBigIntToBigEndianBytes(v.BigInt)
+}
+
+func (v Fix128Value) ConformsToDynamicType(
+	_ *Interpreter,
+	_ func() LocationRange,
+	dynamicType DynamicType,
+	_ TypeConformanceResults,
+) bool {
+	numberType, ok := dynamicType.(NumberDynamicType)
+	return ok && sema.Fix128Type.Equal(numberType.StaticType)
+}
+
+func (Fix128Value) IsStorable() bool {
+	return true
+}
+
+func (v Fix128Value) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return v, nil
+}
+
+func (Fix128Value) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (Fix128Value) IsResourceKinded(_ *Interpreter) bool {
+	return false
+}
+
+func (v Fix128Value) Transfer(
+	interpreter *Interpreter,
+	_ func() LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+) Value {
+	if remove {
+		interpreter.RemoveReferencedSlab(storable)
+	}
+	return v
+}
+
+func (v Fix128Value) Clone(_ *Interpreter) Value {
+	return NewFix128ValueFromBigInt(v.BigInt)
+}
+
+func (Fix128Value) DeepRemove(_ *Interpreter) {
+	// NO-OP
+}
+
+func (v Fix128Value) ByteSize() uint32 {
+	return cborTagSize + getBigIntCBORSize(v.BigInt)
+}
+
+func (v Fix128Value) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+	return v, nil
+}
+
+func (Fix128Value) ChildStorables() []atree.Storable {
+	return nil
+}
+
+// UFix128Value
+//
+// Arithmetic operations round towards zero,
+// e.g. the result of a division is truncated.
+//
+type UFix128Value struct {
+	BigInt *big.Int
+}
+
+func NewUFix128ValueFromBigInt(value *big.Int) UFix128Value {
+	return UFix128Value{BigInt: value}
+}
+
+// NewUFix128ValueWithInteger returns the UFix128 value for the given integer.
+// It panics if the integer is outside of the range of UFix128.
+//
+func NewUFix128ValueWithInteger(integer *big.Int) UFix128Value {
+
+	if integer.Sign() < 0 {
+		panic(UnderflowError{})
+	}
+
+	if integer.Cmp(sema.UFix128TypeMaxIntBig) > 0 {
+		panic(OverflowError{})
+	}
+
+	return UFix128Value{new(big.Int).Mul(integer, sema.Fix128FactorBig)}
+}
+
+var _ Value = UFix128Value{}
+var _ atree.Storable = UFix128Value{}
+var _ NumberValue = UFix128Value{}
+var _ FixedPointValue = UFix128Value{}
+var _ BigNumberValue = UFix128Value{}
+var _ EquatableValue = UFix128Value{}
+var _ HashableValue = UFix128Value{}
+var _ MemberAccessibleValue = UFix128Value{}
+
+func (UFix128Value) IsValue() {}
+
+func (v UFix128Value) Accept(interpreter *Interpreter, visitor Visitor) {
+	visitor.VisitUFix128Value(interpreter, v)
+}
+
+func (UFix128Value) Walk(_ func(Value)) {
+	// NO-OP
+}
+
+var ufix128DynamicType DynamicType = NumberDynamicType{sema.UFix128Type}
+
+func (UFix128Value) DynamicType(_ *Interpreter, _ SeenReferences) DynamicType {
+	return ufix128DynamicType
+}
+
+func (UFix128Value) StaticType() StaticType {
+	return PrimitiveStaticTypeUFix128
+}
+
+func (v UFix128Value) String() string {
+	return format.UFix128(v.BigInt)
+}
+
+func (v UFix128Value) RecursiveString(_ SeenReferences) string {
+	return v.String()
+}
+
+func (v UFix128Value) ToInt() int {
+	integer := v.ToBigInt()
+	if !integer.IsInt64() {
+		panic(OverflowError{})
+	}
+	return int(integer.Int64())
+}
+
+// ToBigInt returns the integer part of the value
+//
+func (v UFix128Value) ToBigInt() *big.Int {
+	return new(big.Int).Quo(v.BigInt, sema.Fix128FactorBig)
+}
+
+func (v UFix128Value) Negate() NumberValue {
+	panic(errors.NewUnreachableError())
+}
+
+// checkUFix128Range panics if the given result is outside the range of UFix128
+//
+func checkUFix128Range(res *big.Int) UFix128Value {
+	if res.Sign() < 0 {
+		panic(UnderflowError{})
+	} else if res.Cmp(sema.UFix128TypeMaxBig) > 0 {
+		panic(OverflowError{})
+	}
+	return UFix128Value{res}
+}
+
+// saturateUFix128Range returns the minimum or maximum UFix128 value
+// if the given result is outside the range of UFix128
+//
+func saturateUFix128Range(res *big.Int) UFix128Value {
+	if res.Sign() < 0 {
+		return UFix128Value{sema.UFix128TypeMinBig}
+	} else if res.Cmp(sema.UFix128TypeMaxBig) > 0 {
+		return UFix128Value{sema.UFix128TypeMaxBig}
+	}
+	return UFix128Value{res}
+}
+
+func (v UFix128Value) Plus(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationPlus,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Add(v.BigInt, o.BigInt)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) SaturatingPlus(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingAddFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Add(v.BigInt, o.BigInt)
+	return saturateUFix128Range(res)
+}
+
+func (v UFix128Value) Minus(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMinus,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Sub(v.BigInt, o.BigInt)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) SaturatingMinus(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingSubtractFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Sub(v.BigInt, o.BigInt)
+	return saturateUFix128Range(res)
+}
+
+func (v UFix128Value) Mul(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMul,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res.Quo(res, sema.Fix128FactorBig)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) SaturatingMul(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingMultiplyFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res.Quo(res, sema.Fix128FactorBig)
+	return saturateUFix128Range(res)
+}
+
+// Div divides the value by the other value.
+// The quotient is truncated, i.e. rounded towards zero
+//
+func (v UFix128Value) Div(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationDiv,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res.Quo(res, o.BigInt)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) SaturatingDiv(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.NumericTypeSaturatingDivideFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res.Quo(res, o.BigInt)
+	return saturateUFix128Range(res)
+}
+
+func (v UFix128Value) MulRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeMultiplyRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, o.BigInt)
+	res = roundedQuotient(res, sema.Fix128FactorBig, rule)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) DivRound(other NumberValue, rule sema.RoundingRule) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			FunctionName: sema.FixedPointTypeDivideRoundFunctionName,
+			LeftType:     v.StaticType(),
+			RightType:    other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	res := new(big.Int).Mul(v.BigInt, sema.Fix128FactorBig)
+	res = roundedQuotient(res, o.BigInt, rule)
+	return checkUFix128Range(res)
+}
+
+func (v UFix128Value) Mod(other NumberValue) NumberValue {
+	o, ok := other.(UFix128Value)
+	if !ok {
+		panic(InvalidOperandsError{
+			Operation: ast.OperationMod,
+			LeftType:  v.StaticType(),
+			RightType: other.StaticType(),
+		})
+	}
+
+	// INT33-C
+	if o.BigInt.Sign() == 0 {
+		panic(DivisionByZeroError{})
+	}
+
+	// v - int(v/o) * o
+	res := new(big.Int).Rem(v.BigInt, o.BigInt)
+	return UFix128Value{res}
+}
+
+func (v UFix128Value) Less(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(UFix128Value).BigInt)
+	return cmp == -1
+}
+
+func (v UFix128Value) LessEqual(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(UFix128Value).BigInt)
+	return cmp <= 0
+}
+
+func (v UFix128Value) Greater(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(UFix128Value).BigInt)
+	return cmp == 1
+}
+
+func (v UFix128Value) GreaterEqual(other NumberValue) BoolValue {
+	cmp := v.BigInt.Cmp(other.(UFix128Value).BigInt)
+	return cmp >= 0
+}
+
+func (v UFix128Value) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
+	otherUFix128, ok := other.(UFix128Value)
+	if !ok {
+		return false
+	}
+	cmp := v.BigInt.Cmp(otherUFix128.BigInt)
+	return cmp == 0
+}
+
+// HashInput returns a byte slice containing:
+// - HashInputTypeUFix128 (1 byte)
+// - big int value encoded in big-endian (n bytes)
+func (v UFix128Value) HashInput(_ *Interpreter, _ func() LocationRange, scratch []byte) []byte {
+	b := UnsignedBigIntToBigEndianBytes(v.BigInt)
+
+	length := 1 + len(b)
+	var buffer []byte
+	if length <= len(scratch) {
+		buffer = scratch[:length]
+	} else {
+		buffer = make([]byte, length)
+	}
+
+	buffer[0] = byte(HashInputTypeUFix128)
+	copy(buffer[1:], b)
+	return buffer
+}
+
+func ConvertUFix128(value Value) UFix128Value {
+	switch value := value.(type) {
+	case UFix128Value:
+		return value
+
+	case Fix128Value:
+		return checkUFix128Range(new(big.Int).Set(value.BigInt))
+
+	case Fix64Value:
+		if value < 0 {
+			panic(UnderflowError{})
+		}
+		// Positive Fix64 values are always in the range of UFix128
+		res := new(big.Int).SetInt64(int64(value))
+		return UFix128Value{res.Mul(res, fix64ToFix128FactorBig)}
+
+	case UFix64Value:
+		// UFix64 values are always in the range of UFix128
+		res := new(big.Int).SetUint64(uint64(value))
+		return UFix128Value{res.Mul(res, fix64ToFix128FactorBig)}
+
+	case BigNumberValue:
+		return NewUFix128ValueWithInteger(value.ToBigInt())
+
+	case NumberValue:
+		return NewUFix128ValueWithInteger(big.NewInt(int64(value.ToInt())))
+
+	default:
+		panic(fmt.Sprintf("can't convert to UFix128: %s", value))
+	}
+}
+
+func (v UFix128Value) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
+	return getNumberValueMember(v, name, sema.UFix128Type)
+}
+
+func (UFix128Value) RemoveMember(_ *Interpreter, _ func() LocationRange, _ string) Value {
+	// Numbers have no removable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (UFix128Value) SetMember(_ *Interpreter, _ func() LocationRange, _ string, _ Value) {
+	// Numbers have no settable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (v UFix128Value) ToBigEndianBytes() []byte {
+	return UnsignedBigIntToBigEndianBytes(v.BigInt)
+}
+
+func (v UFix128Value) ConformsToDynamicType(
+	_ *Interpreter,
+	_ func() LocationRange,
+	dynamicType DynamicType,
+	_ TypeConformanceResults,
+) bool {
+	numberType, ok := dynamicType.(NumberDynamicType)
+	return ok && sema.UFix128Type.Equal(numberType.StaticType)
+}
+
+func (UFix128Value) IsStorable() bool {
+	return true
+}
+
+func (v UFix128Value) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return v, nil
+}
+
+func (UFix128Value) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (UFix128Value) IsResourceKinded(_ *Interpreter) bool {
+	return false
+}
+
+func (v UFix128Value) Transfer(
+	interpreter *Interpreter,
+	_ func() LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+) Value {
+	if remove {
+		interpreter.RemoveReferencedSlab(storable)
+	}
+	return v
+}
+
+func (v UFix128Value) Clone(_ *Interpreter) Value {
+	return NewUFix128ValueFromBigInt(v.BigInt)
+}
+
+func (UFix128Value) DeepRemove(_ *Interpreter) {
+	// NO-OP
+}
+
+func (v UFix128Value) ByteSize() uint32 {
+	return cborTagSize + getBigIntCBORSize(v.BigInt)
+}
+
+func (v UFix128Value) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+	return v, nil
+}
+
+func (UFix128Value) ChildStorables() []atree.Storable {
+	return nil
+}
+
+// CompositeValue
+
+type CompositeValue struct {
+	dictionary          *atree.OrderedMap
+	Location            common.Location
+	QualifiedIdentifier string
+	Kind                common.CompositeKind
+	InjectedFields      map[string]Value
+	ComputedFields      map[string]ComputedField
+	NestedVariables     map[string]*Variable
+	Functions           map[string]FunctionValue
+	memberSlots         *sema.MemberSlots
+	functionSlots       []FunctionValue
+	Destructor          FunctionValue
+	Stringer            func(value *CompositeValue, seenReferences SeenReferences) string
+	isDestroyed         bool
+	typeID              common.TypeID
+	staticType          StaticType
+	dynamicType         DynamicType
+	// transferDeferredBy is the interpreter which moved the resource out of account storage,
+	// if its atree map still resides in the account, see deferTransfer
+	transferDeferredBy *Interpreter
+	// constantFields are the values of the constant fields of a contract,
+	// which are shared by all accesses, see CompositeTypeCode.ConstantFields
+	constantFields map[string]Value
+	// fieldSlots are the cached values of constant fields,
+	// indexed by their slot in memberSlots, see fieldSlot
+	fieldSlots []Value
+}
+
+type ComputedField func(*Interpreter, func() LocationRange) Value
+
+type CompositeField struct {
+	Name  string
+	Value Value
+}
+
+func NewCompositeValue(
+	interpreter *Interpreter,
+	location common.Location,
+	qualifiedIdentifier string,
+	kind common.CompositeKind,
+	fields []CompositeField,
+	address common.Address,
+) *CompositeValue {
+
+	dictionary, err := atree.NewMap(
+		interpreter.Storage,
+		atree.Address(address),
+		atree.NewDefaultDigesterBuilder(),
+		compositeTypeInfo{
+			location:            location,
+			qualifiedIdentifier: qualifiedIdentifier,
+			kind:                kind,
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	v := &CompositeValue{
+		dictionary:          dictionary,
+		Location:            location,
+		QualifiedIdentifier: qualifiedIdentifier,
+		Kind:                kind,
+	}
+
+	for _, field := range fields {
+		v.SetMember(
+			interpreter,
+			// TODO: provide proper location range
+			ReturnEmptyLocationRange,
+			field.Name,
+			field.Value,
+		)
+	}
+
+	return v
+}
+
+var _ Value = &CompositeValue{}
+var _ EquatableValue = &CompositeValue{}
+var _ HashableValue = &CompositeValue{}
+var _ MemberAccessibleValue = &CompositeValue{}
+
+func (*CompositeValue) IsValue() {}
+
+func (v *CompositeValue) Accept(interpreter *Interpreter, visitor Visitor) {
+	descend := visitor.VisitCompositeValue(interpreter, v)
+	if !descend {
+		return
+	}
+
+	v.ForEachField(func(_ string, value Value) {
+		value.Accept(interpreter, visitor)
+	})
+}
+
+// Walk iterates over all field values of the composite value.
+// It does NOT walk the computed fields and functions!
+//
+func (v *CompositeValue) Walk(walkChild func(Value)) {
+	v.ForEachField(func(_ string, value Value) {
+		walkChild(value)
+	})
+}
+
+func (v *CompositeValue) DynamicType(interpreter *Interpreter, _ SeenReferences) DynamicType {
+	if v.dynamicType == nil {
+		staticType, err := interpreter.GetCompositeType(
+			v.Location,
+			v.QualifiedIdentifier,
+			v.TypeID(),
+		)
+		if err != nil {
+			panic(err)
+		}
+		v.dynamicType = CompositeDynamicType{
+			StaticType: staticType,
+		}
+	}
+	return v.dynamicType
+}
+
+func (v *CompositeValue) StaticType() StaticType {
+	if v.staticType == nil {
+		// NOTE: Instead of using NewCompositeStaticType, which always generates the type ID,
+		// use the TypeID accessor, which may return an already computed type ID
+		v.staticType = CompositeStaticType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			TypeID:              v.TypeID(),
+		}
+	}
+	return v.staticType
+}
+
+func (v *CompositeValue) IsDestroyed() bool {
+	return v.isDestroyed
+}
+
+func (v *CompositeValue) Destroy(interpreter *Interpreter, getLocationRange func() LocationRange) {
+	interpreter = v.getInterpreter(interpreter)
+
+	// if composite was deserialized, dynamically link in the destructor
+	if v.Destructor == nil {
+		v.Destructor = interpreter.typeCodes.CompositeCodes[v.TypeID()].DestructorFunction
+	}
+
+	destructor := v.Destructor
+
+	if destructor != nil {
+		invocation := Invocation{
 			Self:             v,
 			Arguments:        nil,
 			ArgumentTypes:    nil,
@@ -10471,6 +11942,16 @@ func (v *CompositeValue) Destroy(interpreter *Interpreter, getLocationRange func
 		destructor.invoke(invocation)
 	}
 
+	if v.isTransferDeferred() {
+		// The atree map still resides in the account
+		// the resource was moved out of, so it must be removed
+		v.transferDeferredBy = nil
+		v.DeepRemove(interpreter)
+		interpreter.RemoveReferencedSlab(atree.StorageIDStorable(v.StorageID()))
+	}
+
+	interpreter.untrackReferencedResourceKindedValue(v.StorageID())
+
 	v.isDestroyed = true
 }
 
@@ -10482,18 +11963,24 @@ func (v *CompositeValue) GetMember(interpreter *Interpreter, getLocationRange fu
 		return v.OwnerValue(interpreter, getLocationRange)
 	}
 
-	storable, err := v.dictionary.Get(
-		stringAtreeComparator,
-		stringAtreeHashInput,
-		stringAtreeValue(name),
-	)
-	if err != nil {
-		if _, ok := err.(*atree.KeyNotFoundError); !ok {
-			panic(ExternalError{err})
+	if v.Kind == common.CompositeKindContract &&
+		name == sema.ContractIsReentrantFieldName &&
+		interpreter.reentrancyHandling == ReentrancyHandlingGuard {
+
+		return BoolValue(interpreter.reentrancyTracker.isReentrant(v.Location))
+	}
+
+	// The values of constant fields are shared,
+	// so they are neither read from the stored value, nor decoded, on each access
+
+	if v.Kind == common.CompositeKindContract {
+		if value := v.constantField(interpreter, name); value != nil {
+			return value
 		}
 	}
-	if storable != nil {
-		return StoredValue(storable, interpreter.Storage)
+
+	if value := v.getStoredField(interpreter, getLocationRange, name); value != nil {
+		return value
 	}
 
 	if v.NestedVariables != nil {
@@ -10511,64 +11998,214 @@ func (v *CompositeValue) GetMember(interpreter *Interpreter, getLocationRange fu
 		}
 	}
 
-	// If the composite value was deserialized, dynamically link in the functions
-	// and get injected fields
+	// If the composite value was deserialized, dynamically link in the functions
+	// and get injected fields
+
+	v.InitializeFunctions(interpreter)
+
+	if v.InjectedFields == nil && interpreter.injectedCompositeFieldsHandler != nil {
+		v.InjectedFields = interpreter.injectedCompositeFieldsHandler(
+			interpreter,
+			v.Location,
+			v.QualifiedIdentifier,
+			v.Kind,
+		)
+	}
+
+	if v.InjectedFields != nil {
+		value, ok := v.InjectedFields[name]
+		if ok {
+			return value
+		}
+	}
+
+	function, ok := v.Functions[name]
+	if ok {
+		return BoundFunctionValue{
+			Self:     v,
+			Function: function,
+		}
+	}
+
+	if name == sema.ToStringFunctionName && v.hasDerivedToString() {
+		return derivedToStringFunction(v)
+	}
+
+	return nil
+}
+
+func (v *CompositeValue) getInterpreter(interpreter *Interpreter) *Interpreter {
+
+	// Get the correct interpreter. The program code might need to be loaded.
+	// NOTE: standard library values have no location
+
+	location := v.Location
+
+	if location == nil || common.LocationsMatch(interpreter.Location, location) {
+		return interpreter
+	}
+
+	return interpreter.EnsureLoaded(v.Location)
+}
+
+// getStoredField returns the value of the field with the given name
+// which is stored in the composite value, or nil if there is no such field
+//
+func (v *CompositeValue) getStoredField(interpreter *Interpreter, getLocationRange func() LocationRange, name string) Value {
+	storable, err := v.dictionary.Get(
+		stringAtreeComparator,
+		stringAtreeHashInput,
+		stringAtreeValue(name),
+	)
+	if err != nil {
+		if _, ok := err.(*atree.KeyNotFoundError); !ok {
+			panic(ExternalError{err})
+		}
+	}
+	if storable == nil {
+		return nil
+	}
+
+	if _, ok := storable.(atree.StorageIDStorable); ok && v.isTransferDeferred() {
+		// The field is a nested container, which must not be owned by the account anymore
+		v.completeDeferredTransfer(getLocationRange)
+		return v.getStoredField(interpreter, getLocationRange, name)
+	}
+
+	value := StoredValue(storable, v.dictionary.Storage)
+
+	if interpreter.journalEnabled() {
+		interpreter.journalNestedRead(v.StorageID(), name, value)
+	}
+
+	return value
+}
+
+func (v *CompositeValue) InitializeFunctions(interpreter *Interpreter) {
+	if v.Functions != nil {
+		return
+	}
+
+	typeCode := interpreter.typeCodes.CompositeCodes[v.TypeID()]
+	v.Functions = typeCode.CompositeFunctions
+	v.memberSlots = typeCode.MemberSlots
+	v.functionSlots = typeCode.FunctionSlots
+	v.constantFields = typeCode.ConstantFields
+}
+
+// constantField returns the value of the constant field with the given name,
+// or nil if the value has no such field
+//
+func (v *CompositeValue) constantField(interpreter *Interpreter, name string) Value {
+	if v.Functions == nil {
+		v.InitializeFunctions(v.getInterpreter(interpreter))
+	}
+
+	return v.constantFields[name]
+}
+
+// slotMember returns the member in the slot of the given member,
+// or nil if the member cannot be accessed by its slot:
+// if the value has no member dispatch table, if the member is not a member of the value's type,
+// e.g. of a type requirement, or if the member is neither a function nor a constant field.
+//
+// The member dispatch table of the value's type is identified by the type,
+// so no type IDs are compared
+//
+func (v *CompositeValue) slotMember(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	memberInfo sema.MemberInfo,
+) Value {
+	if v.Functions == nil {
+		v.InitializeFunctions(v.getInterpreter(interpreter))
+	}
+
+	memberSlots := v.memberSlots
+	member := memberInfo.Member
+	slot := memberInfo.Slot
 
-	v.InitializeFunctions(interpreter)
+	if memberSlots == nil ||
+		member.ContainerType != memberSlots.Type ||
+		slot >= memberSlots.Len() {
 
-	if v.InjectedFields == nil && interpreter.injectedCompositeFieldsHandler != nil {
-		v.InjectedFields = interpreter.injectedCompositeFieldsHandler(
-			interpreter,
-			v.Location,
-			v.QualifiedIdentifier,
-			v.Kind,
-		)
+		return nil
 	}
 
-	if v.InjectedFields != nil {
-		value, ok := v.InjectedFields[name]
-		if ok {
-			return value
+	switch member.DeclarationKind {
+	case common.DeclarationKindFunction:
+		function := v.functionSlots[slot]
+		if function == nil {
+			return nil
 		}
-	}
 
-	function, ok := v.Functions[name]
-	if ok {
 		return BoundFunctionValue{
 			Self:     v,
 			Function: function,
 		}
+
+	case common.DeclarationKindField:
+		// Predeclared fields, e.g. `owner`, are not stored
+
+		if member.VariableKind != ast.VariableKindConstant || member.Predeclared {
+			return nil
+		}
+
+		return v.fieldSlot(interpreter, getLocationRange, slot, member.Identifier.Identifier)
 	}
 
 	return nil
 }
 
-func (v *CompositeValue) getInterpreter(interpreter *Interpreter) *Interpreter {
-
-	// Get the correct interpreter. The program code might need to be loaded.
-	// NOTE: standard library values have no location
-
-	location := v.Location
+// fieldSlot returns the value of the constant field with the given name and slot.
+//
+// The values of constant fields which are simple values, e.g. numbers, strings, or addresses,
+// are immutable, so they are cached in the field slots of the composite value,
+// and are only read from the stored value once.
+//
+// Other values, e.g. containers, are read from the stored value on each access,
+// e.g. to complete a deferred transfer.
+// Variable fields are never cached, as they might be written through another instance
+// of the same stored composite value
+//
+func (v *CompositeValue) fieldSlot(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	slot int,
+	name string,
+) Value {
+	if v.fieldSlots != nil {
+		value := v.fieldSlots[slot]
+		if value != nil {
+			if interpreter.journalEnabled() {
+				interpreter.journalNestedRead(v.StorageID(), name, value)
+			}
 
-	if location == nil || common.LocationsMatch(interpreter.Location, location) {
-		return interpreter
+			return value
+		}
 	}
 
-	return interpreter.EnsureLoaded(v.Location)
-}
+	value := v.getStoredField(interpreter, getLocationRange, name)
 
-func (v *CompositeValue) InitializeFunctions(interpreter *Interpreter) {
-	if v.Functions != nil {
-		return
+	switch value.(type) {
+	case *CompositeValue:
+		// Enum values are hashable, but they are composite values
+		break
+
+	case HashableValue:
+		if v.fieldSlots == nil {
+			v.fieldSlots = make([]Value, v.memberSlots.Len())
+		}
+		v.fieldSlots[slot] = value
 	}
 
-	v.Functions = interpreter.typeCodes.CompositeCodes[v.TypeID()].CompositeFunctions
+	return value
 }
 
 func (v *CompositeValue) OwnerValue(interpreter *Interpreter, getLocationRange func() LocationRange) OptionalValue {
-	address := v.StorageID().Address
+	address := v.GetOwner()
 
-	if address == (atree.Address{}) {
+	if address == (common.Address{}) {
 		return NilValue{}
 	}
 
@@ -10586,6 +12223,19 @@ func (v *CompositeValue) RemoveMember(
 	name string,
 ) Value {
 
+	if v.isReadOnly() {
+		panic(ReadOnlyValueMutationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+
+	v.completeDeferredTransfer(getLocationRange)
+
+	interpreter.reportMutation(v.StorageID())
+	interpreter.journalNestedWrite(v.StorageID())
+
+	v.fieldSlots = nil
+
 	// No need to clean up storable for passed-in key value,
 	// as atree never calls Storable()
 	existingKeyStorable, existingValueStorable, err := v.dictionary.Remove(
@@ -10625,6 +12275,19 @@ func (v *CompositeValue) SetMember(
 	name string,
 	value Value,
 ) {
+	if v.isReadOnly() {
+		panic(ReadOnlyValueMutationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+
+	v.completeDeferredTransfer(getLocationRange)
+
+	interpreter.reportMutation(v.StorageID())
+	interpreter.journalNestedWrite(v.StorageID())
+
+	v.fieldSlots = nil
+
 	address := v.StorageID().Address
 
 	value = value.Transfer(
@@ -10645,6 +12308,13 @@ func (v *CompositeValue) SetMember(
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(v.dictionary)
+	interpreter.maybeValidateOwners(
+		value,
+		address,
+		func() string {
+			return "." + name
+		},
+	)
 
 	if existingStorable != nil {
 		existingValue := StoredValue(existingStorable, interpreter.Storage)
@@ -10719,6 +12389,12 @@ func (v *CompositeValue) GetField(name string) Value {
 		panic(ExternalError{err})
 	}
 
+	if _, ok := storable.(atree.StorageIDStorable); ok && v.isTransferDeferred() {
+		// The field is a nested container, which must not be owned by the account anymore
+		v.completeDeferredTransfer(ReturnEmptyLocationRange)
+		return v.GetField(name)
+	}
+
 	return StoredValue(storable, v.dictionary.Storage)
 }
 
@@ -10792,9 +12468,63 @@ func (v *CompositeValue) HashInput(interpreter *Interpreter, getLocationRange fu
 		return buffer
 	}
 
+	if v.Kind == common.CompositeKindStructure {
+		return v.structHashInput(interpreter, getLocationRange)
+	}
+
 	panic(errors.NewUnreachableError())
 }
 
+// structHashInput returns a byte slice containing:
+// - HashInputTypeStruct (1 byte)
+// - length of type id (4 bytes)
+// - type id (n bytes)
+// - for each field, ordered by name:
+//   - length of field name (4 bytes)
+//   - field name (n bytes)
+//   - length of hash input of field value (4 bytes)
+//   - hash input of field value (n bytes)
+//
+// The checker ensures that all field values of hashable structures are hashable.
+//
+func (v *CompositeValue) structHashInput(interpreter *Interpreter, getLocationRange func() LocationRange) []byte {
+	typeID := v.TypeID()
+
+	var fieldNames []string
+	v.ForEachField(func(name string, _ Value) {
+		fieldNames = append(fieldNames, name)
+	})
+
+	// NOTE: the iteration order of fields depends on the storage ID,
+	// so the fields must be sorted to get a deterministic hash input
+	sort.Strings(fieldNames)
+
+	buffer := make([]byte, 0, 1+4+len(typeID))
+
+	appendWithLength := func(data []byte) {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+		buffer = append(buffer, length[:]...)
+		buffer = append(buffer, data...)
+	}
+
+	buffer = append(buffer, byte(HashInputTypeStruct))
+	appendWithLength([]byte(typeID))
+
+	// NOTE: the hash input of fixed-size values is written into the scratch buffer,
+	// which is reused, as the hash input is copied into the buffer immediately
+	var fieldScratch [32]byte
+
+	for _, fieldName := range fieldNames {
+		fieldValue := v.GetField(fieldName).(HashableValue)
+
+		appendWithLength([]byte(fieldName))
+		appendWithLength(fieldValue.HashInput(interpreter, getLocationRange, fieldScratch[:]))
+	}
+
+	return buffer
+}
+
 func (v *CompositeValue) TypeID() common.TypeID {
 	if v.typeID == "" {
 		location := v.Location
@@ -10920,7 +12650,40 @@ func (v *CompositeValue) Transfer(
 
 	dictionary := v.dictionary
 
+	if v.isReadOnly() {
+		// Read-only values can be copied, but not moved
+		if v.IsResourceKinded(interpreter) {
+			panic(ReadOnlyValueMutationError{
+				LocationRange: getLocationRange(),
+			})
+		}
+		remove = false
+	}
+
+	if v.IsResourceKinded(interpreter) {
+		// Moving the resource invalidates all references to it
+		interpreter.invalidateReferencedResourceKindedValue(v.StorageID())
+	}
+
 	currentAddress := v.StorageID().Address
+	currentOwner := currentAddress
+
+	if v.isTransferDeferred() {
+		if address == (atree.Address{}) && !remove {
+			// The resource is moved to a local destination, e.g. a variable,
+			// so the transfer of the atree map can be deferred further
+			return v
+		}
+
+		// The atree map is exclusively owned by the resource,
+		// so it can be moved out of the account it still resides in.
+		// If the resource is moved back into the same account, no copy is necessary at all
+
+		v.transferDeferredBy = nil
+		currentOwner = atree.Address{}
+		remove = true
+		storable = atree.StorageIDStorable(v.StorageID())
+	}
 
 	needsStoreTo := address != currentAddress
 	isResourceKinded := v.IsResourceKinded(interpreter)
@@ -10990,6 +12753,9 @@ func (v *CompositeValue) Transfer(
 			ComputedFields:      v.ComputedFields,
 			NestedVariables:     v.NestedVariables,
 			Functions:           v.Functions,
+			memberSlots:         v.memberSlots,
+			functionSlots:       v.functionSlots,
+			constantFields:      v.constantFields,
 			Destructor:          v.Destructor,
 			Stringer:            v.Stringer,
 			isDestroyed:         v.isDestroyed,
@@ -10999,14 +12765,14 @@ func (v *CompositeValue) Transfer(
 		}
 	}
 
-	if needsStoreTo &&
+	if address != currentOwner &&
 		res.Kind == common.CompositeKindResource &&
 		interpreter.onResourceOwnerChange != nil {
 
 		interpreter.onResourceOwnerChange(
 			interpreter,
 			res,
-			common.Address(currentAddress),
+			common.Address(currentOwner),
 			common.Address(address),
 		)
 	}
@@ -11014,6 +12780,60 @@ func (v *CompositeValue) Transfer(
 	return res
 }
 
+// deferTransfer moves the resource out of account storage,
+// without moving its atree map out of the account yet.
+//
+// The atree map is only moved when the resource is stored in an account or a container,
+// when it is mutated, or when its nested containers are accessed.
+// This avoids copying the whole value graph of a resource
+// which is moved from one storage path to another in the same account.
+//
+// The caller must have already removed the resource from account storage,
+// see StorageMap.DetachValue.
+//
+func (v *CompositeValue) deferTransfer(interpreter *Interpreter) {
+	currentAddress := v.StorageID().Address
+
+	v.transferDeferredBy = interpreter
+
+	if interpreter.onResourceOwnerChange != nil {
+		interpreter.onResourceOwnerChange(
+			interpreter,
+			v,
+			common.Address(currentAddress),
+			common.Address{},
+		)
+	}
+}
+
+// isTransferDeferred returns true if the resource was moved out of account storage,
+// but its atree map still resides in the account, see deferTransfer
+//
+func (v *CompositeValue) isTransferDeferred() bool {
+	return v.transferDeferredBy != nil
+}
+
+// completeDeferredTransfer moves the atree map of a resource
+// which was moved out of account storage (see deferTransfer) out of the account.
+//
+// The transfer is completed using the interpreter which deferred it,
+// so it can also be completed when the value is only read,
+// e.g. when its fields are iterated over or when it is exported
+//
+func (v *CompositeValue) completeDeferredTransfer(getLocationRange func() LocationRange) {
+	if !v.isTransferDeferred() {
+		return
+	}
+
+	v.Transfer(
+		v.transferDeferredBy,
+		getLocationRange,
+		atree.Address{},
+		true,
+		nil,
+	)
+}
+
 func (v *CompositeValue) ResourceUUID() *UInt64Value {
 	fieldValue := v.GetField(sema.ResourceUUIDFieldName)
 	uuid, ok := fieldValue.(UInt64Value)
@@ -11067,6 +12887,9 @@ func (v *CompositeValue) Clone(interpreter *Interpreter) Value {
 		ComputedFields:      v.ComputedFields,
 		NestedVariables:     v.NestedVariables,
 		Functions:           v.Functions,
+		memberSlots:         v.memberSlots,
+		functionSlots:       v.functionSlots,
+		constantFields:      v.constantFields,
 		Destructor:          v.Destructor,
 		Stringer:            v.Stringer,
 		isDestroyed:         v.isDestroyed,
@@ -11098,6 +12921,9 @@ func (v *CompositeValue) DeepRemove(interpreter *Interpreter) {
 }
 
 func (v *CompositeValue) GetOwner() common.Address {
+	if v.isTransferDeferred() {
+		return common.Address{}
+	}
 	return common.Address(v.StorageID().Address)
 }
 
@@ -11105,6 +12931,11 @@ func (v *CompositeValue) GetOwner() common.Address {
 // It does NOT iterate over computed fields and functions!
 //
 func (v *CompositeValue) ForEachField(f func(fieldName string, fieldValue Value)) {
+
+	// Nested containers must not be owned by the account anymore
+
+	v.completeDeferredTransfer(ReturnEmptyLocationRange)
+
 	err := v.dictionary.Iterate(func(key atree.Value, value atree.Value) (resume bool, err error) {
 		f(
 			string(key.(stringAtreeValue)),
@@ -11117,16 +12948,24 @@ func (v *CompositeValue) ForEachField(f func(fieldName string, fieldValue Value)
 	}
 }
 
+// isReadOnly returns true if the composite is part of a read-only value, see ReadOnlyValue
+//
+func (v *CompositeValue) isReadOnly() bool {
+	return isReadOnlyStorage(v.dictionary.Storage)
+}
+
 func (v *CompositeValue) StorageID() atree.StorageID {
 	return v.dictionary.StorageID()
 }
 
 func (v *CompositeValue) RemoveField(
 	interpreter *Interpreter,
-	_ func() LocationRange,
+	getLocationRange func() LocationRange,
 	name string,
 ) {
 
+	v.completeDeferredTransfer(getLocationRange)
+
 	existingKeyStorable, existingValueStorable, err := v.dictionary.Remove(
 		stringAtreeComparator,
 		stringAtreeHashInput,
@@ -11191,6 +13030,10 @@ type DictionaryValue struct {
 	isResourceKinded *bool
 	dictionary       *atree.OrderedMap
 	isDestroyed      bool
+	// isCopyOnWrite is true if the dictionary lazily shares
+	// the underlying atree map with other dictionary values,
+	// see copyOnWriteValue
+	isCopyOnWrite bool
 }
 
 func NewDictionaryValue(
@@ -11249,6 +13092,7 @@ var _ atree.Value = &DictionaryValue{}
 var _ EquatableValue = &DictionaryValue{}
 var _ ValueIndexableValue = &DictionaryValue{}
 var _ MemberAccessibleValue = &DictionaryValue{}
+var _ copyOnWriteValue = &DictionaryValue{}
 
 func (*DictionaryValue) IsValue() {}
 
@@ -11308,23 +13152,198 @@ func (v *DictionaryValue) DynamicType(interpreter *Interpreter, seenReferences S
 	}
 }
 
-func (v *DictionaryValue) StaticType() StaticType {
-	return v.Type
-}
+func (v *DictionaryValue) StaticType() StaticType {
+	return v.Type
+}
+
+func (v *DictionaryValue) IsDestroyed() bool {
+	return v.isDestroyed
+}
+
+func (v *DictionaryValue) Destroy(interpreter *Interpreter, getLocationRange func() LocationRange) {
+	v.Iterate(func(key, value Value) (resume bool) {
+		// Resources cannot be keys at the moment, so should theoretically not be needed
+		maybeDestroy(interpreter, getLocationRange, key)
+		maybeDestroy(interpreter, getLocationRange, value)
+		return true
+	})
+
+	interpreter.untrackReferencedResourceKindedValue(v.StorageID())
+
+	v.isDestroyed = true
+}
+
+// KeysSorted returns a new array containing all keys of the dictionary,
+// sorted in ascending order.
+//
+// The key type must be sortable, see sema.IsSortableType
+//
+func (v *DictionaryValue) KeysSorted(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+) *ArrayValue {
+
+	keys := make([]Value, 0, v.Count())
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	for {
+		key, err := iterator.NextKey()
+		if err != nil {
+			panic(ExternalError{err})
+		}
+		if key == nil {
+			break
+		}
+
+		keys = append(
+			keys,
+			MustConvertStoredValue(key).
+				Transfer(interpreter, getLocationRange, atree.Address{}, false, nil),
+		)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return sortableValueLess(keys[i], keys[j])
+	})
+
+	return NewArrayValue(
+		interpreter,
+		VariableSizedStaticType{
+			Type: v.Type.KeyType,
+		},
+		common.Address{},
+		keys...,
+	)
+}
+
+// ForEach calls the given function for each entry of the dictionary.
+//
+// The keys are collected before the first call, as the function might mutate the dictionary.
+// Entries removed by the function are skipped
+//
+func (v *DictionaryValue) ForEach(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	function FunctionValue,
+) {
+	keys := make([]Value, 0, v.Count())
+	v.Iterate(func(key, _ Value) (resume bool) {
+		keys = append(keys, key)
+		return true
+	})
+
+	dictionaryType := v.SemaType(interpreter)
+	argumentTypes := []sema.Type{
+		dictionaryType.KeyType,
+		dictionaryType.ValueType,
+	}
+
+	for _, key := range keys {
+		value, ok := v.Get(interpreter, getLocationRange, key)
+		if !ok {
+			continue
+		}
+
+		interpreter.invokeIterationFunction(
+			function,
+			[]Value{key, value},
+			argumentTypes,
+			getLocationRange,
+		)
+	}
+}
+
+// Map returns a new dictionary with the same keys,
+// containing the results of calling the given function for each entry
+//
+func (v *DictionaryValue) Map(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	transformFunction FunctionValue,
+	transformFunctionType *sema.FunctionType,
+) *DictionaryValue {
+
+	resultType := transformFunctionType.ReturnTypeAnnotation.Type
+
+	keysAndValues := make([]Value, 0, v.Count()*2)
+
+	v.ForEach(
+		interpreter,
+		getLocationRange,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				key := invocation.Arguments[0]
+				result := transformFunction.invoke(invocation)
+				keysAndValues = append(keysAndValues, key, result)
+				return VoidValue{}
+			},
+			nil,
+		),
+	)
+
+	return NewDictionaryValue(
+		interpreter,
+		DictionaryStaticType{
+			KeyType:   v.Type.KeyType,
+			ValueType: ConvertSemaToStaticType(resultType),
+		},
+		keysAndValues...,
+	)
+}
+
+// Filter returns a new dictionary containing the entries
+// for which the given predicate function returns true
+//
+func (v *DictionaryValue) Filter(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	predicateFunction FunctionValue,
+) *DictionaryValue {
+
+	keysAndValues := make([]Value, 0, v.Count()*2)
+
+	v.ForEach(
+		interpreter,
+		getLocationRange,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				key := invocation.Arguments[0]
+				value := invocation.Arguments[1]
+				if predicateFunction.invoke(invocation).(BoolValue) {
+					// The arguments are already copies of the entry
+					keysAndValues = append(keysAndValues, key, value)
+				}
+				return VoidValue{}
+			},
+			nil,
+		),
+	)
+
+	return NewDictionaryValue(
+		interpreter,
+		v.Type,
+		keysAndValues...,
+	)
+}
+
+func sortableValueLess(value, other Value) bool {
+	switch value := value.(type) {
+	case NumberValue:
+		return bool(value.Less(other.(NumberValue)))
 
-func (v *DictionaryValue) IsDestroyed() bool {
-	return v.isDestroyed
-}
+	case *StringValue:
+		return value.Str < other.(*StringValue).Str
 
-func (v *DictionaryValue) Destroy(interpreter *Interpreter, getLocationRange func() LocationRange) {
-	v.Iterate(func(key, value Value) (resume bool) {
-		// Resources cannot be keys at the moment, so should theoretically not be needed
-		maybeDestroy(interpreter, getLocationRange, key)
-		maybeDestroy(interpreter, getLocationRange, value)
-		return true
-	})
+	case AddressValue:
+		otherAddress := other.(AddressValue)
+		return bytes.Compare(value[:], otherAddress[:]) < 0
+	}
 
-	v.isDestroyed = true
+	panic(errors.NewUnreachableError())
 }
 
 func (v *DictionaryValue) ContainsKey(
@@ -11373,6 +13392,11 @@ func (v *DictionaryValue) Get(
 
 	storage := v.dictionary.Storage
 	value := StoredValue(storable, storage)
+
+	if interpreter.journalEnabled() {
+		interpreter.journalNestedRead(v.StorageID(), journalKeyMember(keyValue), value)
+	}
+
 	return value, true
 }
 
@@ -11451,7 +13475,7 @@ func (v *DictionaryValue) GetMember(
 	case "length":
 		return NewIntValueFromInt64(int64(v.Count()))
 
-	case "keys":
+	case sema.DictionaryTypeKeysFieldName:
 
 		iterator, err := v.dictionary.Iterator()
 		if err != nil {
@@ -11479,7 +13503,7 @@ func (v *DictionaryValue) GetMember(
 			},
 		)
 
-	case "values":
+	case sema.DictionaryTypeValuesFieldName:
 
 		iterator, err := v.dictionary.Iterator()
 		if err != nil {
@@ -11506,6 +13530,19 @@ func (v *DictionaryValue) GetMember(
 					Transfer(interpreter, getLocationRange, atree.Address{}, false, nil)
 			})
 
+	case "keysSorted":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return v.KeysSorted(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+				)
+			},
+			sema.DictionaryKeysSortedFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
 	case "remove":
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
@@ -11554,6 +13591,50 @@ func (v *DictionaryValue) GetMember(
 			),
 		)
 
+	case sema.DictionaryTypeForEachFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				v.ForEach(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+				)
+				return VoidValue{}
+			},
+			sema.DictionaryForEachFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case sema.DictionaryTypeMapFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return v.Map(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+					invocation.ArgumentTypes[0].(*sema.FunctionType),
+				)
+			},
+			sema.DictionaryMapFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case sema.DictionaryTypeFilterFunctionName:
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return v.Filter(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0].(FunctionValue),
+				)
+			},
+			sema.DictionaryFilterFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
 	}
 
 	return nil
@@ -11587,6 +13668,8 @@ func (v *DictionaryValue) Remove(
 	keyValue Value,
 ) OptionalValue {
 
+	v.prepareMutation(interpreter, getLocationRange)
+
 	valueComparator := newValueComparator(interpreter, getLocationRange)
 	hashInputProvider := newHashInputProvider(interpreter, getLocationRange)
 
@@ -11644,6 +13727,8 @@ func (v *DictionaryValue) Insert(
 	interpreter.checkContainerMutation(v.Type.KeyType, keyValue, getLocationRange)
 	interpreter.checkContainerMutation(v.Type.ValueType, value, getLocationRange)
 
+	v.prepareMutation(interpreter, getLocationRange)
+
 	address := v.dictionary.Address()
 
 	keyValue = keyValue.Transfer(
@@ -11677,6 +13762,20 @@ func (v *DictionaryValue) Insert(
 		panic(ExternalError{err})
 	}
 	interpreter.maybeValidateAtreeValue(v.dictionary)
+	interpreter.maybeValidateOwners(
+		keyValue,
+		address,
+		func() string {
+			return fmt.Sprintf("keys[%s]", keyValue)
+		},
+	)
+	interpreter.maybeValidateOwners(
+		value,
+		address,
+		func() string {
+			return fmt.Sprintf("[%s]", keyValue)
+		},
+	)
 
 	if existingValueStorable == nil {
 		return NilValue{}
@@ -11833,6 +13932,31 @@ func (v *DictionaryValue) Transfer(
 	needsStoreTo := v.NeedsStoreTo(address)
 	isResourceKinded := v.IsResourceKinded(interpreter)
 
+	if v.isReadOnly() {
+		// Read-only values can be copied, but not moved
+		if isResourceKinded {
+			panic(ReadOnlyValueMutationError{
+				LocationRange: getLocationRange(),
+			})
+		}
+		remove = false
+	}
+
+	if isResourceKinded {
+		// Moving the resource invalidates all references to it
+		interpreter.invalidateReferencedResourceKindedValue(v.StorageID())
+	}
+
+	if remove {
+		if v.isCopyOnWrite {
+			// The entries are shared with other dictionaries,
+			// so they must be copied, but not removed
+			remove = false
+		} else {
+			interpreter.materializeCopyOnWriteValues(v.StorageID())
+		}
+	}
+
 	if needsStoreTo || !isResourceKinded {
 
 		valueComparator := newValueComparator(interpreter, getLocationRange)
@@ -11954,6 +14078,14 @@ func (v *DictionaryValue) Clone(interpreter *Interpreter) Value {
 
 func (v *DictionaryValue) DeepRemove(interpreter *Interpreter) {
 
+	if v.isCopyOnWrite {
+		// The entries are shared with other dictionaries,
+		// so they must not be removed
+		return
+	}
+
+	interpreter.materializeCopyOnWriteValues(v.StorageID())
+
 	// Remove nested values and storables
 
 	storage := v.dictionary.Storage
@@ -12001,6 +14133,112 @@ func (v *DictionaryValue) IsResourceKinded(interpreter *Interpreter) bool {
 	return *v.isResourceKinded
 }
 
+func (v *DictionaryValue) canCopyOnWrite(interpreter *Interpreter) bool {
+	return !v.NeedsStoreTo(atree.Address{}) &&
+		!v.isReadOnly() &&
+		!v.IsResourceKinded(interpreter) &&
+		isCopyOnWriteElementStaticType(v.Type.KeyType) &&
+		isCopyOnWriteElementStaticType(v.Type.ValueType)
+}
+
+func (v *DictionaryValue) copyOnWrite(interpreter *Interpreter) Value {
+	dictionary := v.dictionary
+	if !v.isCopyOnWrite {
+		dictionary = interpreter.copyOnWriteDictionaryShare(v.dictionary).header
+	}
+
+	return &DictionaryValue{
+		Type:             v.Type,
+		semaType:         v.semaType,
+		isResourceKinded: v.isResourceKinded,
+		dictionary:       dictionary,
+		isDestroyed:      v.isDestroyed,
+		isCopyOnWrite:    true,
+	}
+}
+
+// prepareMutation must be called before the dictionary is mutated in-place
+//
+func (v *DictionaryValue) prepareMutation(interpreter *Interpreter, getLocationRange func() LocationRange) {
+	if !v.isCopyOnWrite && v.isReadOnly() {
+		panic(ReadOnlyValueMutationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+
+	interpreter.reportMutation(v.StorageID())
+	interpreter.journalNestedWrite(v.StorageID())
+
+	if v.isCopyOnWrite {
+		v.materializeCopyOnWrite(interpreter)
+	} else {
+		interpreter.materializeCopyOnWriteValues(v.StorageID())
+	}
+}
+
+// isReadOnly returns true if the dictionary is part of a read-only value, see ReadOnlyValue
+//
+func (v *DictionaryValue) isReadOnly() bool {
+	return isReadOnlyStorage(v.dictionary.Storage)
+}
+
+func (v *DictionaryValue) materializeCopyOnWrite(interpreter *Interpreter) {
+	if !v.isCopyOnWrite {
+		return
+	}
+
+	v.dictionary = copyAtreeMap(interpreter, v.dictionary)
+	v.isCopyOnWrite = false
+}
+
+// copyAtreeMap returns a copy of the given atree map, in the same account
+//
+func copyAtreeMap(interpreter *Interpreter, dictionary *atree.OrderedMap) *atree.OrderedMap {
+
+	valueComparator := newValueComparator(interpreter, ReturnEmptyLocationRange)
+	hashInputProvider := newHashInputProvider(interpreter, ReturnEmptyLocationRange)
+
+	iterator, err := dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	address := dictionary.Address()
+
+	result, err := atree.NewMapFromBatchData(
+		interpreter.Storage,
+		address,
+		atree.NewDefaultDigesterBuilder(),
+		dictionary.Type(),
+		valueComparator,
+		hashInputProvider,
+		dictionary.Seed(),
+		func() (atree.Value, atree.Value, error) {
+
+			atreeKey, atreeValue, err := iterator.Next()
+			if err != nil {
+				return nil, nil, err
+			}
+			if atreeKey == nil || atreeValue == nil {
+				return nil, nil, nil
+			}
+
+			key := MustConvertStoredValue(atreeKey).
+				Transfer(interpreter, ReturnEmptyLocationRange, address, false, nil)
+
+			value := MustConvertStoredValue(atreeValue).
+				Transfer(interpreter, ReturnEmptyLocationRange, address, false, nil)
+
+			return key, value, nil
+		},
+	)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return result
+}
+
 // OptionalValue
 
 type OptionalValue interface {
@@ -12395,6 +14633,7 @@ func (s SomeStorable) ChildStorables() []atree.Storable {
 
 type StorageReferenceValue struct {
 	Authorized           bool
+	Entitlements         []*sema.EntitlementType
 	TargetStorageAddress common.Address
 	TargetPath           PathValue
 	BorrowedType         sema.Type
@@ -12434,6 +14673,7 @@ func (v *StorageReferenceValue) DynamicType(interpreter *Interpreter, seenRefere
 
 	return StorageReferenceDynamicType{
 		authorized:   v.Authorized,
+		entitlements: v.Entitlements,
 		innerType:    innerType,
 		borrowedType: v.BorrowedType,
 	}
@@ -12444,18 +14684,18 @@ func (v *StorageReferenceValue) StaticType() StaticType {
 	if v.BorrowedType != nil {
 		borrowedType = ConvertSemaToStaticType(v.BorrowedType)
 	}
-	return ReferenceStaticType{
-		Authorized: v.Authorized,
-		Type:       borrowedType,
+	return &ReferenceStaticType{
+		Authorized:   v.Authorized,
+		Entitlements: ConvertSemaEntitlementTypesToStaticEntitlementTypes(v.Entitlements),
+		Type:         borrowedType,
 	}
 }
 
 func (v *StorageReferenceValue) dereference(interpreter *Interpreter, getLocationRange func() LocationRange) (*Value, error) {
 	address := v.TargetStorageAddress
-	domain := v.TargetPath.Domain.Identifier()
-	identifier := v.TargetPath.Identifier
+	key := common.NewPathStorageKey(v.TargetPath.Domain, v.TargetPath.Identifier)
 
-	referenced := interpreter.ReadStored(address, domain, identifier)
+	referenced := interpreter.ReadStored(address, key)
 	if referenced == nil {
 		return nil, nil
 	}
@@ -12626,7 +14866,8 @@ func (v *StorageReferenceValue) Equal(_ *Interpreter, _ func() LocationRange, ot
 	if !ok ||
 		v.TargetStorageAddress != otherReference.TargetStorageAddress ||
 		v.TargetPath != otherReference.TargetPath ||
-		v.Authorized != otherReference.Authorized {
+		v.Authorized != otherReference.Authorized ||
+		!sema.EntitlementSetsEqual(v.Entitlements, otherReference.Entitlements) {
 
 		return false
 	}
@@ -12647,7 +14888,8 @@ func (v *StorageReferenceValue) ConformsToDynamicType(
 
 	refType, ok := dynamicType.(StorageReferenceDynamicType)
 	if !ok ||
-		refType.authorized != v.Authorized {
+		refType.authorized != v.Authorized ||
+		!sema.EntitlementSetsEqual(refType.entitlements, v.Entitlements) {
 
 		return false
 	}
@@ -12705,6 +14947,7 @@ func (v *StorageReferenceValue) Transfer(
 func (v *StorageReferenceValue) Clone(_ *Interpreter) Value {
 	return &StorageReferenceValue{
 		Authorized:           v.Authorized,
+		Entitlements:         v.Entitlements,
 		TargetStorageAddress: v.TargetStorageAddress,
 		TargetPath:           v.TargetPath,
 		BorrowedType:         v.BorrowedType,
@@ -12719,8 +14962,13 @@ func (*StorageReferenceValue) DeepRemove(_ *Interpreter) {
 
 type EphemeralReferenceValue struct {
 	Authorized   bool
+	Entitlements []*sema.EntitlementType
 	Value        Value
 	BorrowedType sema.Type
+	// invalidation records if the referenced resource was moved.
+	// It is shared with all other references to the resource,
+	// see referencedResourceKindedValues
+	invalidation *referenceInvalidation
 }
 
 var _ Value = &EphemeralReferenceValue{}
@@ -12754,6 +15002,8 @@ func (v *EphemeralReferenceValue) RecursiveString(seenReferences SeenReferences)
 }
 
 func (v *EphemeralReferenceValue) DynamicType(interpreter *Interpreter, seenReferences SeenReferences) DynamicType {
+	v.checkNotInvalidated(ReturnEmptyLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{})
@@ -12770,6 +15020,7 @@ func (v *EphemeralReferenceValue) DynamicType(interpreter *Interpreter, seenRefe
 
 	return EphemeralReferenceDynamicType{
 		authorized:   v.Authorized,
+		entitlements: v.Entitlements,
 		innerType:    innerType,
 		borrowedType: v.BorrowedType,
 	}
@@ -12780,13 +15031,19 @@ func (v *EphemeralReferenceValue) StaticType() StaticType {
 	if v.BorrowedType != nil {
 		borrowedType = ConvertSemaToStaticType(v.BorrowedType)
 	}
-	return ReferenceStaticType{
-		Authorized: v.Authorized,
-		Type:       borrowedType,
+	return &ReferenceStaticType{
+		Authorized:   v.Authorized,
+		Entitlements: ConvertSemaEntitlementTypesToStaticEntitlementTypes(v.Entitlements),
+		Type:         borrowedType,
 	}
 }
 
 func (v *EphemeralReferenceValue) ReferencedValue() *Value {
+	// References to moved resources cannot be dereferenced anymore
+	if v.isInvalidated() {
+		return nil
+	}
+
 	// Just like for storage references, references to optionals are unwrapped,
 	// i.e. a reference to `nil` aborts when dereferenced.
 
@@ -12800,11 +15057,29 @@ func (v *EphemeralReferenceValue) ReferencedValue() *Value {
 	}
 }
 
+// isInvalidated returns true if the referenced resource was moved
+//
+func (v *EphemeralReferenceValue) isInvalidated() bool {
+	return v.invalidation != nil && v.invalidation.invalidated
+}
+
+// checkNotInvalidated aborts if the referenced resource was moved
+//
+func (v *EphemeralReferenceValue) checkNotInvalidated(getLocationRange func() LocationRange) {
+	if v.isInvalidated() {
+		panic(InvalidatedResourceReferenceError{
+			LocationRange: getLocationRange(),
+		})
+	}
+}
+
 func (v *EphemeralReferenceValue) GetMember(
 	interpreter *Interpreter,
 	getLocationRange func() LocationRange,
 	name string,
 ) Value {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12824,6 +15099,8 @@ func (v *EphemeralReferenceValue) RemoveMember(
 	getLocationRange func() LocationRange,
 	identifier string,
 ) Value {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12848,6 +15125,8 @@ func (v *EphemeralReferenceValue) SetMember(
 	name string,
 	value Value,
 ) {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12867,6 +15146,8 @@ func (v *EphemeralReferenceValue) GetKey(
 	getLocationRange func() LocationRange,
 	key Value,
 ) Value {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12888,6 +15169,8 @@ func (v *EphemeralReferenceValue) SetKey(
 	key Value,
 	value Value,
 ) {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12909,6 +15192,8 @@ func (v *EphemeralReferenceValue) InsertKey(
 	key Value,
 	value Value,
 ) {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12929,6 +15214,8 @@ func (v *EphemeralReferenceValue) RemoveKey(
 	getLocationRange func() LocationRange,
 	key Value,
 ) Value {
+	v.checkNotInvalidated(getLocationRange)
+
 	referencedValue := v.ReferencedValue()
 	if referencedValue == nil {
 		panic(DereferenceError{
@@ -12948,7 +15235,8 @@ func (v *EphemeralReferenceValue) Equal(_ *Interpreter, _ func() LocationRange,
 	otherReference, ok := other.(*EphemeralReferenceValue)
 	if !ok ||
 		v.Value != otherReference.Value ||
-		v.Authorized != otherReference.Authorized {
+		v.Authorized != otherReference.Authorized ||
+		!sema.EntitlementSetsEqual(v.Entitlements, otherReference.Entitlements) {
 
 		return false
 	}
@@ -12969,7 +15257,8 @@ func (v *EphemeralReferenceValue) ConformsToDynamicType(
 
 	refType, ok := dynamicType.(EphemeralReferenceDynamicType)
 	if !ok ||
-		refType.authorized != v.Authorized {
+		refType.authorized != v.Authorized ||
+		!sema.EntitlementSetsEqual(refType.entitlements, v.Entitlements) {
 
 		return false
 	}
@@ -12988,8 +15277,8 @@ func (v *EphemeralReferenceValue) ConformsToDynamicType(
 	}
 
 	entry := typeConformanceResultEntry{
-		EphemeralReferenceValue:       v,
-		EphemeralReferenceDynamicType: refType,
+		EphemeralReferenceValue: v,
+		InnerDynamicType:        refType.InnerType(),
 	}
 
 	if result, contains := results[entry]; contains {
@@ -13044,8 +15333,12 @@ func (v *EphemeralReferenceValue) Transfer(
 func (v *EphemeralReferenceValue) Clone(_ *Interpreter) Value {
 	return &EphemeralReferenceValue{
 		Authorized:   v.Authorized,
+		Entitlements: v.Entitlements,
 		BorrowedType: v.BorrowedType,
 		Value:        v.Value,
+		// The clone refers to the same resource,
+		// so it is invalidated together with the original
+		invalidation: v.invalidation,
 	}
 }
 
@@ -13817,6 +16110,109 @@ func (v LinkValue) ChildStorables() []atree.Storable {
 	}
 }
 
+// AccountLinkValue is a link to an account,
+// created by AuthAccount.linkAccount.
+// Capabilities for the path it is stored at target the account itself.
+//
+type AccountLinkValue struct{}
+
+var _ Value = AccountLinkValue{}
+var _ atree.Value = AccountLinkValue{}
+var _ atree.Storable = AccountLinkValue{}
+var _ EquatableValue = AccountLinkValue{}
+
+func (AccountLinkValue) IsValue() {}
+
+func (v AccountLinkValue) Accept(interpreter *Interpreter, visitor Visitor) {
+	visitor.VisitAccountLinkValue(interpreter, v)
+}
+
+func (AccountLinkValue) Walk(_ func(Value)) {
+	// NO-OP
+}
+
+func (AccountLinkValue) DynamicType(_ *Interpreter, _ SeenReferences) DynamicType {
+	return nil
+}
+
+func (AccountLinkValue) StaticType() StaticType {
+	return nil
+}
+
+func (AccountLinkValue) String() string {
+	return format.AccountLink
+}
+
+func (v AccountLinkValue) RecursiveString(_ SeenReferences) string {
+	return v.String()
+}
+
+func (AccountLinkValue) ConformsToDynamicType(
+	_ *Interpreter,
+	_ func() LocationRange,
+	_ DynamicType,
+	_ TypeConformanceResults,
+) bool {
+	// There is no dynamic type for links,
+	// as they are not first-class values in programs,
+	// but only stored
+	return false
+}
+
+func (AccountLinkValue) Equal(_ *Interpreter, _ func() LocationRange, other Value) bool {
+	_, ok := other.(AccountLinkValue)
+	return ok
+}
+
+func (AccountLinkValue) IsStorable() bool {
+	return true
+}
+
+func (v AccountLinkValue) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return v, nil
+}
+
+func (AccountLinkValue) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (AccountLinkValue) IsResourceKinded(_ *Interpreter) bool {
+	return false
+}
+
+func (v AccountLinkValue) Transfer(
+	interpreter *Interpreter,
+	_ func() LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+) Value {
+	if remove {
+		interpreter.RemoveReferencedSlab(storable)
+	}
+	return v
+}
+
+func (v AccountLinkValue) Clone(_ *Interpreter) Value {
+	return v
+}
+
+func (AccountLinkValue) DeepRemove(_ *Interpreter) {
+	// NO-OP
+}
+
+func (AccountLinkValue) ByteSize() uint32 {
+	return uint32(len(cborAccountLinkValue))
+}
+
+func (v AccountLinkValue) StoredValue(_ atree.SlabStorage) (atree.Value, error) {
+	return v, nil
+}
+
+func (AccountLinkValue) ChildStorables() []atree.Storable {
+	return nil
+}
+
 // NewPublicKeyValue constructs a PublicKey value.
 func NewPublicKeyValue(
 	interpreter *Interpreter,
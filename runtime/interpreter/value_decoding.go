@@ -0,0 +1,209 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/onflow/atree"
+)
+
+// CheckStoredValueDecoding fully decodes the value stored in the given storable,
+// including all nested values, and all slabs which are (transitively) referenced.
+//
+// Values are otherwise decoded lazily, when they are accessed,
+// so a corrupted nested value is usually only detected much later,
+// and without any indication which value is affected.
+//
+// If a value fails to decode, a ValueDecodingError is returned,
+// which contains the path from the given root value to the value.
+//
+func (interpreter *Interpreter) CheckStoredValueDecoding(storable atree.Storable) (err error) {
+	checker := valueDecodingChecker{
+		interpreter: interpreter,
+		storage:     interpreter.Storage,
+	}
+
+	// Converting stored values panics on errors,
+	// so report the panic for the currently checked value
+
+	defer func() {
+		if r := recover(); r != nil {
+			recoveredErr, ok := r.(error)
+			if !ok {
+				recoveredErr = ExternalError{r}
+			}
+			err = checker.error(recoveredErr)
+		}
+	}()
+
+	return checker.check(storable, "")
+}
+
+type valueDecodingChecker struct {
+	interpreter *Interpreter
+	storage     atree.SlabStorage
+	path        string
+	storageID   atree.StorageID
+}
+
+func (c *valueDecodingChecker) error(err error) error {
+	if decodingErr, ok := err.(ValueDecodingError); ok {
+		return decodingErr
+	}
+
+	return ValueDecodingError{
+		Path:      c.path,
+		StorageID: c.storageID,
+		Err:       err,
+	}
+}
+
+func (c *valueDecodingChecker) check(storable atree.Storable, path string) error {
+	c.path = path
+
+	switch storable := storable.(type) {
+	case SomeStorable:
+		return c.check(storable.Storable, path)
+
+	case atree.StorageIDStorable:
+		c.storageID = atree.StorageID(storable)
+
+		_, found, err := c.storage.Retrieve(c.storageID)
+		if err != nil {
+			return c.error(err)
+		}
+		if !found {
+			return c.error(fmt.Errorf("slab not found"))
+		}
+	}
+
+	value := StoredValue(storable, c.storage)
+
+	switch value := value.(type) {
+	case *ArrayValue:
+		return c.checkArray(value, path)
+
+	case *DictionaryValue:
+		return c.checkDictionary(value, path)
+
+	case *CompositeValue:
+		return c.checkComposite(value, path)
+	}
+
+	return nil
+}
+
+func (c *valueDecodingChecker) checkArray(value *ArrayValue, path string) error {
+	storageID := value.StorageID()
+
+	count := value.array.Count()
+	for index := uint64(0); index < count; index++ {
+		c.path = path
+		c.storageID = storageID
+
+		element, err := value.array.Get(index)
+		if err != nil {
+			return c.error(err)
+		}
+
+		err = c.check(element, fmt.Sprintf("%s[%d]", path, index))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *valueDecodingChecker) checkDictionary(value *DictionaryValue, path string) error {
+	storageID := value.StorageID()
+
+	var keys []atree.Value
+
+	c.path = path
+	c.storageID = storageID
+
+	err := value.dictionary.IterateKeys(func(key atree.Value) (resume bool, err error) {
+		keys = append(keys, key)
+		return true, nil
+	})
+	if err != nil {
+		return c.error(err)
+	}
+
+	getLocationRange := ReturnEmptyLocationRange
+	valueComparator := newValueComparator(c.interpreter, getLocationRange)
+	hashInputProvider := newHashInputProvider(c.interpreter, getLocationRange)
+
+	for _, key := range keys {
+		keyPath := fmt.Sprintf("%s[%s]", path, MustConvertStoredValue(key))
+
+		c.path = keyPath
+		c.storageID = storageID
+
+		element, err := value.dictionary.Get(valueComparator, hashInputProvider, key)
+		if err != nil {
+			return c.error(err)
+		}
+
+		err = c.check(element, keyPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *valueDecodingChecker) checkComposite(value *CompositeValue, path string) error {
+	storageID := value.StorageID()
+
+	var names []atree.Value
+
+	c.path = path
+	c.storageID = storageID
+
+	err := value.dictionary.IterateKeys(func(key atree.Value) (resume bool, err error) {
+		names = append(names, key)
+		return true, nil
+	})
+	if err != nil {
+		return c.error(err)
+	}
+
+	for _, name := range names {
+		fieldPath := fmt.Sprintf("%s.%s", path, name.(stringAtreeValue))
+
+		c.path = fieldPath
+		c.storageID = storageID
+
+		field, err := value.dictionary.Get(stringAtreeComparator, stringAtreeHashInput, name)
+		if err != nil {
+			return c.error(err)
+		}
+
+		err = c.check(field, fieldPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
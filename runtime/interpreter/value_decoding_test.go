@@ -0,0 +1,101 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	. "github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckStoredValueDecoding(t *testing.T) {
+
+	t.Parallel()
+
+	storage := NewInMemoryStorage()
+
+	inter, err := NewInterpreter(nil, TestLocation, WithStorage(storage))
+	require.NoError(t, err)
+
+	owner := common.Address(testOwner)
+
+	inner := NewArrayValue(
+		inter,
+		VariableSizedStaticType{
+			Type: PrimitiveStaticTypeInt,
+		},
+		owner,
+		NewIntValueFromInt64(1),
+	)
+
+	dictionary := NewDictionaryValueWithAddress(
+		inter,
+		DictionaryStaticType{
+			KeyType: PrimitiveStaticTypeString,
+			ValueType: VariableSizedStaticType{
+				Type: PrimitiveStaticTypeInt,
+			},
+		},
+		owner,
+		NewStringValue("a"),
+		inner,
+	)
+
+	value := NewArrayValue(
+		inter,
+		VariableSizedStaticType{
+			Type: PrimitiveStaticTypeAnyStruct,
+		},
+		owner,
+		BoolValue(true),
+		NewSomeValueNonCopying(dictionary),
+	)
+
+	storable, err := value.Storable(storage, atree.Address(testOwner), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, inter.CheckStoredValueDecoding(storable))
+
+	// Remove the slab of the innermost array
+
+	storedDictionary := value.Get(inter, ReturnEmptyLocationRange, 1).(*SomeValue).Value.(*DictionaryValue)
+
+	element, ok := storedDictionary.Get(inter, ReturnEmptyLocationRange, NewStringValue("a"))
+	require.True(t, ok)
+
+	innerStorageID := element.(*ArrayValue).StorageID()
+
+	err = storage.Remove(innerStorageID)
+	require.NoError(t, err)
+
+	err = inter.CheckStoredValueDecoding(storable)
+	require.Error(t, err)
+
+	var decodingErr ValueDecodingError
+	require.ErrorAs(t, err, &decodingErr)
+
+	assert.Equal(t, `[1]["a"]`, decodingErr.Path)
+	assert.Equal(t, innerStorageID, decodingErr.StorageID)
+}
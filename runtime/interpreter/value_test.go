@@ -53,7 +53,7 @@ var testCompositeValueType = &sema.CompositeType{
 	Location:   utils.TestLocation,
 	Identifier: "Test",
 	Kind:       common.CompositeKindStructure,
-	Members:    sema.NewStringMemberOrderedMap(),
+	Members:    &sema.StringMemberOrderedMap{},
 }
 
 func TestOwnerNewArray(t *testing.T) {
@@ -3349,3 +3349,160 @@ func TestNonStorable(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestDeepCopy(t *testing.T) {
+
+	t.Parallel()
+
+	inter := newTestInterpreter(t)
+
+	owner := common.Address{0x1}
+	newOwner := common.Address{0x2}
+
+	arrayStaticType := VariableSizedStaticType{
+		Type: PrimitiveStaticTypeInt,
+	}
+
+	dictionaryStaticType := DictionaryStaticType{
+		KeyType:   PrimitiveStaticTypeString,
+		ValueType: arrayStaticType,
+	}
+
+	newValue := func() *CompositeValue {
+		compositeValue := NewCompositeValue(
+			inter,
+			utils.TestLocation,
+			"Test",
+			common.CompositeKindResource,
+			[]CompositeField{
+				{
+					Name:  "uuid",
+					Value: UInt64Value(42),
+				},
+				{
+					Name: "values",
+					Value: NewSomeValueNonCopying(
+						NewDictionaryValue(
+							inter,
+							dictionaryStaticType,
+							NewStringValue("a"),
+							NewArrayValue(
+								inter,
+								arrayStaticType,
+								common.Address{},
+								NewIntValueFromInt64(1),
+								NewIntValueFromInt64(2),
+							),
+						),
+					),
+				},
+			},
+			common.Address{},
+		)
+
+		return compositeValue.Transfer(
+			inter,
+			ReturnEmptyLocationRange,
+			atree.Address(owner),
+			true,
+			nil,
+		).(*CompositeValue)
+	}
+
+	getArray := func(compositeValue *CompositeValue) *ArrayValue {
+		dictionaryValue := compositeValue.GetField("values").(*SomeValue).Value.(*DictionaryValue)
+		arrayValue, ok := dictionaryValue.Get(inter, ReturnEmptyLocationRange, NewStringValue("a"))
+		require.True(t, ok)
+		return arrayValue.(*ArrayValue)
+	}
+
+	for _, address := range []common.Address{{}, owner, newOwner} {
+
+		address := address
+
+		t.Run(address.String(), func(t *testing.T) {
+
+			original := newValue()
+
+			copied := DeepCopy(inter, original, atree.Address(address)).(*CompositeValue)
+
+			require.True(t, DeepEqual(inter, original, copied))
+
+			// The copy is owned by the given address,
+			// the original is unchanged
+
+			assert.Equal(t, address, copied.GetOwner())
+			assert.Equal(t, address, getArray(copied).GetOwner())
+			assert.Equal(t, owner, original.GetOwner())
+			assert.Equal(t, owner, getArray(original).GetOwner())
+
+			// The UUID is copied
+
+			assert.Equal(t, original.ResourceUUID(), copied.ResourceUUID())
+
+			// Mutating the copy does not affect the original
+
+			getArray(copied).Append(inter, ReturnEmptyLocationRange, NewIntValueFromInt64(3))
+
+			assert.Equal(t, 3, getArray(copied).Count())
+			assert.Equal(t, 2, getArray(original).Count())
+			assert.False(t, DeepEqual(inter, original, copied))
+			assert.True(t, DeepEqual(inter, original, newValue()))
+		})
+	}
+}
+
+func TestDeepEqual(t *testing.T) {
+
+	t.Parallel()
+
+	inter := newTestInterpreter(t)
+
+	t.Run("nil", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.True(t, DeepEqual(inter, nil, nil))
+		assert.False(t, DeepEqual(inter, nil, NilValue{}))
+		assert.False(t, DeepEqual(inter, NilValue{}, nil))
+	})
+
+	t.Run("different types", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.False(t, DeepEqual(inter, NewIntValueFromInt64(1), UInt8Value(1)))
+	})
+
+	t.Run("different owners", func(t *testing.T) {
+
+		t.Parallel()
+
+		newArray := func(owner common.Address) Value {
+			return NewArrayValue(
+				inter,
+				VariableSizedStaticType{
+					Type: PrimitiveStaticTypeInt,
+				},
+				owner,
+				NewIntValueFromInt64(1),
+			)
+		}
+
+		assert.True(t, DeepEqual(inter, newArray(common.Address{}), newArray(common.Address{0x1})))
+	})
+
+	t.Run("functions", func(t *testing.T) {
+
+		t.Parallel()
+
+		function := NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				return VoidValue{}
+			},
+			nil,
+		)
+
+		assert.False(t, DeepEqual(inter, function, function))
+	})
+}
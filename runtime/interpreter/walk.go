@@ -18,6 +18,10 @@
 
 package interpreter
 
+import (
+	"github.com/onflow/atree"
+)
+
 type ValueWalker interface {
 	WalkValue(value Value) ValueWalker
 }
@@ -45,3 +49,127 @@ func WalkValue(walker ValueWalker, value Value) {
 
 	walker.WalkValue(nil)
 }
+
+// WalkOptions configures which parts of a value graph are traversed by WalkValueWithOptions.
+//
+type WalkOptions struct {
+	// SkipStoredContents skips the children of containers (arrays, dictionaries, and composites)
+	// which are stored in an account, except for the children of the initial value.
+	// The contents of stored containers are loaded lazily from storage,
+	// so skipping them avoids loading them.
+	// The stored containers themselves are still walked.
+	SkipStoredContents bool
+	// FollowReferences walks the value referenced by a reference value
+	// as the only child of the reference value.
+	// Each referenced container is walked at most once,
+	// and references which cannot be dereferenced have no children.
+	FollowReferences bool
+}
+
+// WalkValueWithOptions traverses a Value object graph in depth-first order,
+// like WalkValue, but allows configuring which content is traversed.
+//
+// The interpreter is used to dereference storage references.
+//
+func WalkValueWithOptions(interpreter *Interpreter, walker ValueWalker, value Value, options WalkOptions) {
+	w := &optionsWalker{
+		interpreter: interpreter,
+		options:     options,
+		seen:        map[atree.StorageID]struct{}{},
+	}
+	w.walk(walker, value, true)
+}
+
+// HydrateAll fully loads the given value from storage,
+// i.e. all elements of arrays and dictionaries and all fields of composites,
+// including the contents of nested containers.
+//
+// The contents of stored containers are loaded lazily, on access.
+// Embedders which need all contents up-front, e.g. to compute deep hashes
+// or to check the integrity of stored values, can use this function
+// to load them eagerly.
+//
+// References are not followed, i.e. referenced values are not loaded.
+//
+func HydrateAll(interpreter *Interpreter, value Value) {
+	InspectValueWithOptions(
+		interpreter,
+		value,
+		WalkOptions{},
+		func(_ Value) bool {
+			return true
+		},
+	)
+}
+
+type optionsWalker struct {
+	interpreter *Interpreter
+	options     WalkOptions
+	// seen are the containers which have already been walked,
+	// used to prevent walking referenced containers repeatedly
+	seen map[atree.StorageID]struct{}
+}
+
+// containerValue is a value which is backed by atree storage
+//
+type containerValue interface {
+	Value
+	StorageID() atree.StorageID
+}
+
+func (w *optionsWalker) walk(walker ValueWalker, value Value, isInitial bool) {
+	if container, ok := value.(containerValue); ok {
+		w.seen[container.StorageID()] = struct{}{}
+	}
+
+	if walker = walker.WalkValue(value); walker == nil {
+		return
+	}
+
+	w.walkChildren(walker, value, isInitial)
+
+	walker.WalkValue(nil)
+}
+
+func (w *optionsWalker) walkChildren(walker ValueWalker, value Value, isInitial bool) {
+
+	switch value := value.(type) {
+	case *StorageReferenceValue:
+		if w.options.FollowReferences {
+			w.walkReferenced(walker, value.ReferencedValue(w.interpreter))
+		}
+		return
+
+	case *EphemeralReferenceValue:
+		if w.options.FollowReferences {
+			w.walkReferenced(walker, value.ReferencedValue())
+		}
+		return
+
+	case containerValue:
+		if w.options.SkipStoredContents &&
+			!isInitial &&
+			value.StorageID().Address != (atree.Address{}) {
+
+			return
+		}
+	}
+
+	value.Walk(func(child Value) {
+		w.walk(walker, child, false)
+	})
+}
+
+func (w *optionsWalker) walkReferenced(walker ValueWalker, referenced *Value) {
+	if referenced == nil {
+		return
+	}
+
+	if container, ok := (*referenced).(containerValue); ok {
+		if _, ok := w.seen[container.StorageID()]; ok {
+			return
+		}
+	}
+
+	w.walk(walker, *referenced, false)
+}
@@ -0,0 +1,73 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+// YieldPoint is the kind of point at which an execution yields to the embedder,
+// see OnYieldFunc.
+//
+type YieldPoint uint8
+
+const (
+	YieldPointUnknown YieldPoint = iota
+	// YieldPointLoopIteration is the back-edge of a loop,
+	// i.e. a loop iteration is about to be executed
+	YieldPointLoopIteration
+	// YieldPointFunctionInvocation is a function invocation,
+	// i.e. a function is about to be invoked
+	YieldPointFunctionInvocation
+)
+
+func (p YieldPoint) String() string {
+	switch p {
+	case YieldPointLoopIteration:
+		return "YieldPointLoopIteration"
+	case YieldPointFunctionInvocation:
+		return "YieldPointFunctionInvocation"
+	default:
+		return "YieldPointUnknown"
+	}
+}
+
+// OnYieldFunc is a function that is triggered at the yield points of an execution,
+// i.e. at loop back-edges and function invocations.
+//
+// It allows embedders to interleave work with long-running executions,
+// e.g. to check for preemption or to report progress,
+// without running the execution in a separate goroutine.
+//
+// If the function returns an error, the execution is aborted with the error.
+//
+type OnYieldFunc func(
+	inter *Interpreter,
+	point YieldPoint,
+) error
+
+// yield calls the yield handler, if any,
+// and aborts the execution if the handler returns an error
+//
+func (interpreter *Interpreter) yield(point YieldPoint) {
+	if interpreter.onYield == nil {
+		return
+	}
+
+	err := interpreter.onYield(interpreter, point)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -211,7 +211,28 @@ func fixedPointLiteralValue(expression ast.Expression, ty sema.Type) (cadence.Va
 		return nil, InvalidLiteralError
 	}
 
-	// TODO: adjust once/if we support more fixed point types
+	switch ty {
+	case sema.Fix128Type:
+		return cadence.NewFix128FromBig(
+			fixedpoint.ConvertToFixedPointBigInt(
+				fixedPointExpression.Negative,
+				fixedPointExpression.UnsignedInteger,
+				fixedPointExpression.Fractional,
+				fixedPointExpression.Scale,
+				sema.Fix128Scale,
+			),
+		)
+	case sema.UFix128Type:
+		return cadence.NewUFix128FromBig(
+			fixedpoint.ConvertToFixedPointBigInt(
+				fixedPointExpression.Negative,
+				fixedPointExpression.UnsignedInteger,
+				fixedPointExpression.Fractional,
+				fixedPointExpression.Scale,
+				sema.Fix128Scale,
+			),
+		)
+	}
 
 	value := fixedpoint.ConvertToFixedPointBigInt(
 		fixedPointExpression.Negative,
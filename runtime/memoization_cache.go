@@ -0,0 +1,132 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// MemoizationCache caches the results of memoized contract functions
+// across executions, e.g. across the transactions of a block,
+// so the functions are not evaluated again, see sema.MemoizePragma.
+//
+// The cache is owned by the embedder: it should create a new cache
+// for each block, and pass it in the context of each execution, see Context.MemoizationCache.
+//
+// Results are keyed by location, code hash, and function name, so the result of a function
+// is only reused if the code of the contract did not change, e.g. through a contract update.
+// Memoized functions only return values of primitive types, which are immutable,
+// so the cached values can be shared by executions.
+//
+// The computation used to compute a result is recorded, and charged again when the result is reused,
+// so the computation used by an execution does not depend on the state of the cache.
+//
+type MemoizationCache struct {
+	lock    sync.RWMutex
+	results map[memoizationCacheKey]MemoizedResult
+}
+
+// MemoizedResult is the cached result of a memoized function
+//
+type MemoizedResult struct {
+	Value interpreter.Value
+	// ComputationUsed is the computation which was used to compute the value
+	ComputationUsed uint64
+	// Metered is true if the computation was metered when the value was computed.
+	// If it is false, the computation used is unknown
+	Metered bool
+}
+
+type memoizationCacheKey struct {
+	locationID    common.LocationID
+	codeHash      [32]byte
+	qualifiedName string
+}
+
+func NewMemoizationCache() *MemoizationCache {
+	return &MemoizationCache{
+		results: map[memoizationCacheKey]MemoizedResult{},
+	}
+}
+
+// memoizationCodeHash returns the hash of the given code,
+// by which the results of the functions declared in the code are keyed
+//
+func memoizationCodeHash(code []byte) [32]byte {
+	return sha3.Sum256(code)
+}
+
+// Get returns the result of the function with the given qualified name,
+// declared in the given location and code with the given hash, if any
+//
+func (c *MemoizationCache) Get(
+	location common.Location,
+	codeHash [32]byte,
+	qualifiedName string,
+) (
+	result MemoizedResult,
+	ok bool,
+) {
+	key := memoizationCacheKey{
+		locationID:    location.ID(),
+		codeHash:      codeHash,
+		qualifiedName: qualifiedName,
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	result, ok = c.results[key]
+	return
+}
+
+// Set sets the result of the function with the given qualified name,
+// declared in the given location and code with the given hash
+//
+func (c *MemoizationCache) Set(
+	location common.Location,
+	codeHash [32]byte,
+	qualifiedName string,
+	result MemoizedResult,
+) {
+	key := memoizationCacheKey{
+		locationID:    location.ID(),
+		codeHash:      codeHash,
+		qualifiedName: qualifiedName,
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.results[key] = result
+}
+
+// Len returns the number of cached results
+//
+func (c *MemoizationCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return len(c.results)
+}
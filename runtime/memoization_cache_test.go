@@ -0,0 +1,303 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeMemoizationCache(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0xCA, 0xDE})
+
+	contract := []byte(`
+      #memoize("Test.fee")
+
+      pub contract Test {
+          pub let base: Int
+
+          init() {
+              self.base = 3
+          }
+
+          pub fun fee(): Int {
+              var sum = 0
+              var i = 0
+              while i < 10 {
+                  sum = sum + self.base
+                  i = i + 1
+              }
+              return sum
+          }
+      }
+    `)
+
+	script := []byte(`
+      import Test from 0xCADE
+
+      pub fun main(): Int {
+          return Test.fee() + Test.fee()
+      }
+    `)
+
+	deploy := utils.DeploymentTransaction("Test", contract)
+
+	var accountCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(_ Location) (bytes []byte, err error) {
+			return accountCode, nil
+		},
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: deploy,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	memoizationCache := NewMemoizationCache()
+
+	executeScript := func(cache *MemoizationCache) cadence.Value {
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:        runtimeInterface,
+				Location:         nextTransactionLocation(),
+				MemoizationCache: cache,
+			},
+		)
+		require.NoError(t, err)
+		return value
+	}
+
+	assert.Equal(t, cadence.NewInt(60), executeScript(memoizationCache))
+	assert.Equal(t, 1, memoizationCache.Len())
+
+	contractLocation := common.AddressLocation{
+		Address: address,
+		Name:    "Test",
+	}
+
+	codeHash := memoizationCodeHash(accountCode)
+
+	result, ok := memoizationCache.Get(contractLocation, codeHash, "Test.fee")
+	require.True(t, ok)
+	require.Equal(t,
+		MemoizedResult{
+			Value: interpreter.NewIntValueFromInt64(30),
+		},
+		result,
+	)
+
+	// The cached result is used by following executions,
+	// i.e. the function is not evaluated again
+
+	memoizationCache.Set(
+		contractLocation,
+		codeHash,
+		"Test.fee",
+		MemoizedResult{
+			Value: interpreter.NewIntValueFromInt64(1),
+		},
+	)
+
+	assert.Equal(t, cadence.NewInt(2), executeScript(memoizationCache))
+
+	// Without a cache, the function is evaluated
+
+	assert.Equal(t, cadence.NewInt(60), executeScript(nil))
+}
+
+func TestRuntimeMemoizationCacheMetering(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0xCA, 0xDE})
+
+	contract := []byte(`
+      #memoize("Test.fee")
+
+      pub contract Test {
+
+          pub fun fee(): Int {
+              var sum = 0
+              var i = 0
+              while i < 10 {
+                  sum = sum + i
+                  i = i + 1
+              }
+              return sum
+          }
+      }
+    `)
+
+	script := []byte(`
+      import Test from 0xCADE
+
+      pub fun main(): Int {
+          return Test.fee()
+      }
+    `)
+
+	deploy := utils.DeploymentTransaction("Test", contract)
+
+	var accountCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(_ Location) (bytes []byte, err error) {
+			return accountCode, nil
+		},
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: deploy,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	contractLocation := common.AddressLocation{
+		Address: address,
+		Name:    "Test",
+	}
+
+	// executeScript executes the script and returns the computation used,
+	// or zero if computation is not metered
+
+	executeScript := func(cache *MemoizationCache, computationLimit uint64) uint64 {
+		runtimeInterface.computationLimit = computationLimit
+		runtimeInterface.computationUsed = 0
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:        runtimeInterface,
+				Location:         nextTransactionLocation(),
+				MemoizationCache: cache,
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, cadence.NewInt(45), value)
+
+		return runtimeInterface.computationUsed
+	}
+
+	const computationLimit = 1000
+
+	t.Run("metered", func(t *testing.T) {
+
+		memoizationCache := NewMemoizationCache()
+
+		computationUsed := executeScript(memoizationCache, computationLimit)
+		require.NotZero(t, computationUsed)
+
+		result, ok := memoizationCache.Get(contractLocation, memoizationCodeHash(accountCode), "Test.fee")
+		require.True(t, ok)
+		assert.True(t, result.Metered)
+		assert.NotZero(t, result.ComputationUsed)
+
+		// The recorded computation is charged when the cached result is used
+
+		assert.Equal(t, computationUsed, executeScript(memoizationCache, computationLimit))
+	})
+
+	t.Run("not metered", func(t *testing.T) {
+
+		memoizationCache := NewMemoizationCache()
+
+		computationUsed := executeScript(nil, computationLimit)
+
+		executeScript(memoizationCache, 0)
+
+		result, ok := memoizationCache.Get(contractLocation, memoizationCodeHash(accountCode), "Test.fee")
+		require.True(t, ok)
+		assert.False(t, result.Metered)
+
+		// The result computed without metering is not used by metered executions,
+		// as the computation used to compute it is unknown
+
+		assert.Equal(t, computationUsed, executeScript(memoizationCache, computationLimit))
+
+		result, ok = memoizationCache.Get(contractLocation, memoizationCodeHash(accountCode), "Test.fee")
+		require.True(t, ok)
+		assert.True(t, result.Metered)
+	})
+}
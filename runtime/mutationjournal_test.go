@@ -0,0 +1,203 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestRuntimeMutationJournal(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	execute := func(code string) *interpreter.MutationJournal {
+		journal := interpreter.NewMutationJournal()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        nextTransactionLocation(),
+				MutationJournal: journal,
+			},
+		)
+		require.NoError(t, err)
+
+		return journal
+	}
+
+	entries := func(journal *interpreter.MutationJournal) []string {
+		var result []string
+		for _, entry := range journal.Entries() {
+			result = append(result, entry.Kind.String()+" "+entry.Path.String())
+		}
+		return result
+	}
+
+	setup := execute(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save([[1], [2]], to: /storage/numbers)
+              signer.save({"a": 1}, to: /storage/balances)
+          }
+      }
+    `)
+
+	assert.Equal(t,
+		[]string{
+			"read 0x1/storage/numbers",
+			"write 0x1/storage/numbers",
+			"read 0x1/storage/balances",
+			"write 0x1/storage/balances",
+		},
+		entries(setup),
+	)
+
+	appendNumber := execute(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let numbers = signer.borrow<&[[Int]]>(from: /storage/numbers)!
+              numbers[1].append(3)
+          }
+      }
+    `)
+
+	assert.Equal(t,
+		[]string{
+			"read 0x1/storage/numbers",
+			"read 0x1/storage/numbers[1]",
+			"write 0x1/storage/numbers[1]",
+		},
+		entries(appendNumber),
+	)
+
+	readNumber := execute(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let numbers = signer.borrow<&[[Int]]>(from: /storage/numbers)!
+              let number = numbers[0][0]
+          }
+      }
+    `)
+
+	updateBalance := execute(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let balances = signer.borrow<&{String: Int}>(from: /storage/balances)!
+              balances["a"] = 2
+          }
+      }
+    `)
+
+	assert.Equal(t,
+		[]string{
+			"read 0x1/storage/balances",
+			"write 0x1/storage/balances",
+		},
+		entries(updateBalance),
+	)
+
+	// Writes conflict with overlapping reads and writes
+
+	assert.True(t, setup.ConflictsWith(appendNumber))
+	assert.True(t, appendNumber.ConflictsWith(setup))
+
+	// Reading a stored value conservatively conflicts with writes of its nested values
+
+	assert.True(t, appendNumber.ConflictsWith(readNumber))
+	assert.True(t, readNumber.ConflictsWith(appendNumber))
+
+	// Accesses of different stored values do not conflict
+
+	assert.False(t, appendNumber.ConflictsWith(updateBalance))
+
+	// Reads do not conflict with each other
+
+	assert.False(t, readNumber.ConflictsWith(readNumber))
+}
+
+func TestRuntimeMutationJournalPathOverlaps(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+	otherAddress := common.BytesToAddress([]byte{0x2})
+
+	account := interpreter.JournalPath{
+		Address: address,
+	}
+	domain := interpreter.JournalPath{
+		Address: address,
+		Domain:  "storage",
+	}
+	vault := interpreter.JournalPath{
+		Address:    address,
+		Domain:     "storage",
+		Identifier: "vault",
+	}
+	balance := interpreter.JournalPath{
+		Address:    address,
+		Domain:     "storage",
+		Identifier: "vault",
+		Members:    []string{"balance"},
+	}
+	uuid := interpreter.JournalPath{
+		Address:    address,
+		Domain:     "storage",
+		Identifier: "vault",
+		Members:    []string{"uuid"},
+	}
+	otherVault := interpreter.JournalPath{
+		Address:    otherAddress,
+		Domain:     "storage",
+		Identifier: "vault",
+	}
+
+	assert.True(t, account.Overlaps(balance))
+	assert.True(t, domain.Overlaps(balance))
+	assert.True(t, vault.Overlaps(balance))
+	assert.True(t, balance.Overlaps(vault))
+	assert.True(t, balance.Overlaps(balance))
+
+	assert.False(t, balance.Overlaps(uuid))
+	assert.False(t, vault.Overlaps(otherVault))
+
+	assert.Equal(t, "0x1/storage/vault.balance", balance.String())
+}
@@ -58,6 +58,8 @@ func parseDeclarations(p *parser, endTokenType lexer.TokenType) (declarations []
 }
 
 func parseDeclaration(p *parser, docString string) ast.Declaration {
+	p.enterNesting()
+	defer p.leaveNesting()
 
 	access := ast.AccessNotSpecified
 	var accessPos *ast.Position
@@ -85,6 +87,9 @@ func parseDeclaration(p *parser, docString string) ast.Declaration {
 			case keywordStruct, keywordResource, keywordContract, keywordEnum:
 				return parseCompositeOrInterfaceDeclaration(p, access, accessPos, docString)
 
+			case keywordEntitlement:
+				return parseEntitlementDeclaration(p, access, accessPos, docString)
+
 			case KeywordTransaction:
 				if access != ast.AccessNotSpecified {
 					panic(fmt.Errorf("invalid access modifier for transaction"))
@@ -97,7 +102,11 @@ func parseDeclaration(p *parser, docString string) ast.Declaration {
 				}
 				pos := p.current.StartPos
 				accessPos = &pos
-				access = parseAccess(p)
+				var entitlements []*ast.NominalType
+				access, entitlements = parseAccess(p)
+				if len(entitlements) > 0 {
+					panic(fmt.Errorf("entitlement access is only supported for members"))
+				}
 				continue
 			}
 		}
@@ -106,27 +115,31 @@ func parseDeclaration(p *parser, docString string) ast.Declaration {
 	}
 }
 
-// parseAccess parses an access modifier
+// parseAccess parses an access modifier.
+// Entitlement access is public access which additionally
+// requires the given entitlements when accessed through a reference.
+//
+//     entitlements : nominalType ( ',' nominalType )*
 //
 //     access
 //         : 'priv'
 //         | 'pub' ( '(' 'set' ')' )?
-//         | 'access' '(' ( 'self' | 'contract' | 'account' | 'all' ) ')'
+//         | 'access' '(' ( 'self' | 'contract' | 'account' | 'all' | entitlements ) ')'
 //
-func parseAccess(p *parser) ast.Access {
+func parseAccess(p *parser) (ast.Access, []*ast.NominalType) {
 
 	switch p.current.Value {
 	case keywordPriv:
 		// Skip the `priv` keyword
 		p.next()
-		return ast.AccessPrivate
+		return ast.AccessPrivate, nil
 
 	case keywordPub:
 		// Skip the `pub` keyword
 		p.next()
 		p.skipSpaceAndComments(true)
 		if !p.current.Is(lexer.TokenParenOpen) {
-			return ast.AccessPublic
+			return ast.AccessPublic, nil
 		}
 
 		// Skip the opening paren
@@ -154,7 +167,7 @@ func parseAccess(p *parser) ast.Access {
 
 		p.mustOne(lexer.TokenParenClose)
 
-		return ast.AccessPublicSettable
+		return ast.AccessPublicSettable, nil
 
 	case keywordAccess:
 		// Skip the `access` keyword
@@ -197,19 +210,13 @@ func parseAccess(p *parser) ast.Access {
 			access = ast.AccessPrivate
 
 		default:
-			panic(fmt.Errorf(
-				"expected keyword %s, got %q",
-				common.EnumerateWords(
-					[]string{
-						strconv.Quote(keywordAll),
-						strconv.Quote(keywordAccount),
-						strconv.Quote(keywordContract),
-						strconv.Quote(keywordSelf),
-					},
-					"or",
-				),
-				p.current.Value,
-			))
+			// Any other identifier starts a list of entitlements
+
+			entitlements, _ := parseNominalTypes(p, lexer.TokenParenClose)
+
+			p.mustOne(lexer.TokenParenClose)
+
+			return ast.AccessPublic, entitlements
 		}
 
 		// Skip the keyword
@@ -218,7 +225,7 @@ func parseAccess(p *parser) ast.Access {
 
 		p.mustOne(lexer.TokenParenClose)
 
-		return access
+		return access, nil
 
 	default:
 		panic(errors.NewUnreachableError())
@@ -361,14 +368,17 @@ func parsePragmaDeclaration(p *parser) *ast.PragmaDeclaration {
 //
 //     importDeclaration :
 //         'import'
-//         ( identifier (',' identifier)* 'from' )?
+//         ( importedIdentifier (',' importedIdentifier)* 'from' )?
 //         ( string | hexadecimalLiteral | identifier )
 //
+//     importedIdentifier : identifier ( 'as' identifier )?
+//
 func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 
 	startPosition := p.current.StartPos
 
 	var identifiers []ast.Identifier
+	var aliases map[string]string
 
 	var location common.Location
 	var locationPos ast.Position
@@ -419,8 +429,34 @@ func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 		}
 	}
 
-	parseMoreIdentifiers := func() {
-		expectCommaOrFrom := false
+	parseAlias := func(identifier ast.Identifier) {
+		if _, ok := aliases[identifier.Identifier]; ok {
+			panic(fmt.Errorf(
+				"duplicate alias for imported identifier %q",
+				identifier.Identifier,
+			))
+		}
+
+		// Skip the `as` keyword
+		p.next()
+		p.skipSpaceAndComments(true)
+
+		if p.current.Type != lexer.TokenIdentifier {
+			panic(fmt.Errorf(
+				"expected %s for alias of imported identifier %q, got %s",
+				lexer.TokenIdentifier,
+				identifier.Identifier,
+				p.current.Type,
+			))
+		}
+
+		if aliases == nil {
+			aliases = map[string]string{}
+		}
+		aliases[identifier.Identifier] = p.current.Value.(string)
+	}
+
+	parseMoreIdentifiers := func(expectCommaOrFrom bool) {
 
 		atEnd := false
 		for !atEnd {
@@ -441,6 +477,11 @@ func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 
 			case lexer.TokenIdentifier:
 
+				if p.current.Value == keywordAs && expectCommaOrFrom {
+					parseAlias(identifiers[len(identifiers)-1])
+					break
+				}
+
 				if p.current.Value == keywordFrom {
 					if expectCommaOrFrom {
 						atEnd = true
@@ -504,6 +545,13 @@ func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 
 			parseLocation()
 
+		} else if p.current.Value == keywordAs {
+			// The given (previous) identifier is an imported identifier
+			// which is aliased, and more identifiers may follow
+			identifiers = append(identifiers, identifier)
+			parseAlias(identifier)
+			parseMoreIdentifiers(true)
+
 		} else {
 			setIdentifierLocation(identifier)
 		}
@@ -528,7 +576,7 @@ func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 			// The previous identifier is an imported identifier,
 			// not the import location
 			identifiers = append(identifiers, identifier)
-			parseMoreIdentifiers()
+			parseMoreIdentifiers(false)
 
 		case lexer.TokenIdentifier:
 			maybeParseFromIdentifier(identifier)
@@ -558,6 +606,7 @@ func parseImportDeclaration(p *parser) *ast.ImportDeclaration {
 
 	return &ast.ImportDeclaration{
 		Identifiers: identifiers,
+		Aliases:     aliases,
 		Location:    location,
 		Range: ast.Range{
 			StartPos: startPosition,
@@ -610,7 +659,7 @@ func parseHexadecimalLocation(literal string) common.AddressLocation {
 
 // parseEventDeclaration parses an event declaration.
 //
-//     eventDeclaration : 'event' identifier parameterList
+//     eventDeclaration : 'event' identifier eventParameterList
 //
 func parseEventDeclaration(
 	p *parser,
@@ -639,7 +688,7 @@ func parseEventDeclaration(
 	// Skip the identifier
 	p.next()
 
-	parameterList := parseParameterList(p)
+	parameterList := parseEventParameterList(p)
 
 	initializer :=
 		&ast.SpecialFunctionDeclaration{
@@ -696,7 +745,9 @@ func parseCompositeKind(p *parser) common.CompositeKind {
 //
 //     variableKind : 'var' | 'let'
 //
-//     field : variableKind identifier ':' typeAnnotation
+//     field : variableKind identifier ':' typeAnnotation ( '=' expression )?
+//
+// The value is only valid for constant fields of contracts, which is checked by the checker.
 //
 func parseFieldWithVariableKind(
 	p *parser,
@@ -740,16 +791,75 @@ func parseFieldWithVariableKind(
 	p.skipSpaceAndComments(true)
 
 	typeAnnotation := parseTypeAnnotation(p)
+	endPos := typeAnnotation.EndPosition()
+
+	// Parse the optional value of the field, on the same line
+
+	var value ast.Expression
+
+	p.skipSpaceAndComments(false)
+	if p.current.Is(lexer.TokenEqual) {
+		// Skip the `=`
+		p.next()
+		p.skipSpaceAndComments(true)
+
+		value = parseExpression(p, lowestBindingPower)
+		endPos = value.EndPosition()
+	}
 
 	return &ast.FieldDeclaration{
 		Access:         access,
 		VariableKind:   variableKind,
 		Identifier:     identifier,
 		TypeAnnotation: typeAnnotation,
+		Value:          value,
 		DocString:      docString,
 		Range: ast.Range{
 			StartPos: startPos,
-			EndPos:   typeAnnotation.EndPosition(),
+			EndPos:   endPos,
+		},
+	}
+}
+
+// parseEntitlementDeclaration parses an entitlement declaration.
+//
+//     entitlementDeclaration : 'entitlement' identifier
+//
+func parseEntitlementDeclaration(
+	p *parser,
+	access ast.Access,
+	accessPos *ast.Position,
+	docString string,
+) *ast.EntitlementDeclaration {
+
+	startPos := p.current.StartPos
+	if accessPos != nil {
+		startPos = *accessPos
+	}
+
+	// Skip the `entitlement` keyword
+	p.next()
+
+	p.skipSpaceAndComments(true)
+	if !p.current.Is(lexer.TokenIdentifier) {
+		panic(fmt.Errorf(
+			"expected identifier after start of entitlement declaration, got %s",
+			p.current.Type,
+		))
+	}
+
+	identifier := tokenToIdentifier(p.current)
+
+	// Skip the identifier
+	p.next()
+
+	return &ast.EntitlementDeclaration{
+		Access:     access,
+		Identifier: identifier,
+		DocString:  docString,
+		Range: ast.Range{
+			StartPos: startPos,
+			EndPos:   identifier.EndPosition(),
 		},
 	}
 }
@@ -923,11 +1033,14 @@ func parseMembersAndNestedDeclarations(p *parser, endTokenType lexer.TokenType)
 //                               | enumCase
 //
 func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration {
+	p.enterNesting()
+	defer p.leaveNesting()
 
 	const functionBlockIsOptional = true
 
 	access := ast.AccessNotSpecified
 	var accessPos *ast.Position
+	var entitlements []*ast.NominalType
 
 	var previousIdentifierToken *lexer.Token
 
@@ -938,27 +1051,38 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 		case lexer.TokenIdentifier:
 			switch p.current.Value {
 			case keywordLet, keywordVar:
-				return parseFieldWithVariableKind(p, access, accessPos, docString)
+				field := parseFieldWithVariableKind(p, access, accessPos, docString)
+				field.Entitlements = entitlements
+				return field
 
 			case keywordCase:
+				rejectEntitlements(entitlements, common.DeclarationKindEnumCase)
 				return parseEnumCase(p, access, accessPos, docString)
 
 			case keywordFun:
-				return parseFunctionDeclaration(p, functionBlockIsOptional, access, accessPos, docString)
+				function := parseFunctionDeclaration(p, functionBlockIsOptional, access, accessPos, docString)
+				function.Entitlements = entitlements
+				return function
 
 			case keywordEvent:
+				rejectEntitlements(entitlements, common.DeclarationKindEvent)
 				return parseEventDeclaration(p, access, accessPos, docString)
 
 			case keywordStruct, keywordResource, keywordContract, keywordEnum:
+				rejectEntitlements(entitlements, common.DeclarationKindType)
 				return parseCompositeOrInterfaceDeclaration(p, access, accessPos, docString)
 
+			case keywordEntitlement:
+				rejectEntitlements(entitlements, common.DeclarationKindEntitlement)
+				return parseEntitlementDeclaration(p, access, accessPos, docString)
+
 			case keywordPriv, keywordPub, keywordAccess:
 				if access != ast.AccessNotSpecified {
 					panic(fmt.Errorf("unexpected access modifier"))
 				}
 				pos := p.current.StartPos
 				accessPos = &pos
-				access = parseAccess(p)
+				access, entitlements = parseAccess(p)
 				continue
 
 			default:
@@ -979,7 +1103,9 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 			}
 
 			identifier := tokenToIdentifier(*previousIdentifierToken)
-			return parseFieldDeclarationWithoutVariableKind(p, access, accessPos, identifier, docString)
+			field := parseFieldDeclarationWithoutVariableKind(p, access, accessPos, identifier, docString)
+			field.Entitlements = entitlements
+			return field
 
 		case lexer.TokenParenOpen:
 			if previousIdentifierToken == nil {
@@ -987,6 +1113,7 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 			}
 
 			identifier := tokenToIdentifier(*previousIdentifierToken)
+			rejectEntitlements(entitlements, common.DeclarationKindFunction)
 			return parseSpecialFunctionDeclaration(p, functionBlockIsOptional, access, accessPos, identifier)
 		}
 
@@ -994,6 +1121,16 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 	}
 }
 
+// rejectEntitlements reports an error if entitlements were given
+// for a member declaration which does not support entitlement access.
+// Only fields and functions support entitlement access.
+//
+func rejectEntitlements(entitlements []*ast.NominalType, declarationKind common.DeclarationKind) {
+	if len(entitlements) > 0 {
+		panic(fmt.Errorf("invalid entitlement access for %s", declarationKind.Name()))
+	}
+}
+
 func parseFieldDeclarationWithoutVariableKind(
 	p *parser,
 	access ast.Access,
@@ -966,7 +966,8 @@ func TestParseAccess(t *testing.T) {
 		return Parse(
 			input,
 			func(p *parser) interface{} {
-				return parseAccess(p)
+				access, _ := parseAccess(p)
+				return access
 			},
 		)
 	}
@@ -1167,16 +1168,84 @@ func TestParseAccess(t *testing.T) {
 		)
 	})
 
-	t.Run("access, invalid inner keyword", func(t *testing.T) {
+	parseWithEntitlements := func(input string) (interface{}, []error) {
+		return Parse(
+			input,
+			func(p *parser) interface{} {
+				access, entitlements := parseAccess(p)
+				return []interface{}{access, entitlements}
+			},
+		)
+	}
+
+	t.Run("access(E)", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := parseWithEntitlements("access ( foo )")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]interface{}{
+				ast.AccessPublic,
+				[]*ast.NominalType{
+					{
+						Identifier: ast.Identifier{
+							Identifier: "foo",
+							Pos:        ast.Position{Offset: 9, Line: 1, Column: 9},
+						},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("access(E1, C.E2)", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := parseWithEntitlements("access ( E1 , C.E2 )")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]interface{}{
+				ast.AccessPublic,
+				[]*ast.NominalType{
+					{
+						Identifier: ast.Identifier{
+							Identifier: "E1",
+							Pos:        ast.Position{Offset: 9, Line: 1, Column: 9},
+						},
+					},
+					{
+						Identifier: ast.Identifier{
+							Identifier: "C",
+							Pos:        ast.Position{Offset: 14, Line: 1, Column: 14},
+						},
+						NestedIdentifiers: []ast.Identifier{
+							{
+								Identifier: "E2",
+								Pos:        ast.Position{Offset: 16, Line: 1, Column: 16},
+							},
+						},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("access, entitlements, missing closing paren", func(t *testing.T) {
 
 		t.Parallel()
 
-		result, errs := parse("access ( foo )")
+		result, errs := parseWithEntitlements("access ( E1 ")
 		utils.AssertEqualWithDiff(t,
 			[]error{
 				&SyntaxError{
-					Message: "expected keyword \"all\", \"account\", \"contract\", or \"self\", got \"foo\"",
-					Pos:     ast.Position{Offset: 9, Line: 1, Column: 9},
+					Message: "invalid end of input, expected ')'",
+					Pos:     ast.Position{Offset: 12, Line: 1, Column: 12},
 				},
 			},
 			errs,
@@ -1425,6 +1494,127 @@ func TestParseImportDeclaration(t *testing.T) {
 		)
 	})
 
+	t.Run("aliased first identifier, address location", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(` import foo as f , bar from 0x42`)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.ImportDeclaration{
+					Identifiers: []ast.Identifier{
+						{
+							Identifier: "foo",
+							Pos:        ast.Position{Line: 1, Column: 8, Offset: 8},
+						},
+						{
+							Identifier: "bar",
+							Pos:        ast.Position{Line: 1, Column: 19, Offset: 19},
+						},
+					},
+					Aliases: map[string]string{
+						"foo": "f",
+					},
+					Location: common.AddressLocation{
+						Address: common.BytesToAddress([]byte{0x42}),
+					},
+					LocationPos: ast.Position{Line: 1, Column: 28, Offset: 28},
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 1, Offset: 1},
+						EndPos:   ast.Position{Line: 1, Column: 31, Offset: 31},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("aliased identifiers, address location", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(` import foo as f, bar as b from 0x42`)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.ImportDeclaration{
+					Identifiers: []ast.Identifier{
+						{
+							Identifier: "foo",
+							Pos:        ast.Position{Line: 1, Column: 8, Offset: 8},
+						},
+						{
+							Identifier: "bar",
+							Pos:        ast.Position{Line: 1, Column: 18, Offset: 18},
+						},
+					},
+					Aliases: map[string]string{
+						"foo": "f",
+						"bar": "b",
+					},
+					Location: common.AddressLocation{
+						Address: common.BytesToAddress([]byte{0x42}),
+					},
+					LocationPos: ast.Position{Line: 1, Column: 32, Offset: 32},
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 1, Offset: 1},
+						EndPos:   ast.Position{Line: 1, Column: 35, Offset: 35},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("aliased identifier, missing alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(` import foo as , bar from 0x42`)
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: `expected identifier for alias of imported identifier "foo", got ','`,
+					Pos:     ast.Position{Offset: 15, Line: 1, Column: 15},
+				},
+			},
+			errs,
+		)
+
+		var expected []ast.Declaration
+
+		utils.AssertEqualWithDiff(t,
+			expected,
+			result,
+		)
+	})
+
+	t.Run("aliased identifier, duplicate alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(` import foo , bar as b as c from 0x42`)
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: `duplicate alias for imported identifier "bar"`,
+					Pos:     ast.Position{Offset: 23, Line: 1, Column: 23},
+				},
+			},
+			errs,
+		)
+
+		var expected []ast.Declaration
+
+		utils.AssertEqualWithDiff(t,
+			expected,
+			result,
+		)
+	})
+
 	t.Run("from keyword as second identifier", func(t *testing.T) {
 
 		t.Parallel()
@@ -1618,6 +1808,136 @@ func TestParseEvent(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("indexed parameters", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("event E(indexed a: Int, indexed l b: String, indexed: Bool)")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.CompositeDeclaration{
+					CompositeKind: common.CompositeKindEvent,
+					Identifier: ast.Identifier{
+						Identifier: "E",
+						Pos:        ast.Position{Offset: 6, Line: 1, Column: 6},
+					},
+					Members: ast.NewMembers(
+						[]ast.Declaration{
+							&ast.SpecialFunctionDeclaration{
+								Kind: common.DeclarationKindInitializer,
+								FunctionDeclaration: &ast.FunctionDeclaration{
+									ParameterList: &ast.ParameterList{
+										Parameters: []*ast.Parameter{
+											{
+												Label: "",
+												Identifier: ast.Identifier{
+													Identifier: "a",
+													Pos:        ast.Position{Offset: 16, Line: 1, Column: 16},
+												},
+												TypeAnnotation: &ast.TypeAnnotation{
+													IsResource: false,
+													Type: &ast.NominalType{
+														Identifier: ast.Identifier{
+															Identifier: "Int",
+															Pos:        ast.Position{Offset: 19, Line: 1, Column: 19},
+														},
+													},
+													StartPos: ast.Position{Offset: 19, Line: 1, Column: 19},
+												},
+												Indexed: true,
+												Range: ast.Range{
+													StartPos: ast.Position{Offset: 8, Line: 1, Column: 8},
+													EndPos:   ast.Position{Offset: 21, Line: 1, Column: 21},
+												},
+											},
+											{
+												Label: "l",
+												Identifier: ast.Identifier{
+													Identifier: "b",
+													Pos:        ast.Position{Offset: 34, Line: 1, Column: 34},
+												},
+												TypeAnnotation: &ast.TypeAnnotation{
+													IsResource: false,
+													Type: &ast.NominalType{
+														Identifier: ast.Identifier{
+															Identifier: "String",
+															Pos:        ast.Position{Offset: 37, Line: 1, Column: 37},
+														},
+													},
+													StartPos: ast.Position{Offset: 37, Line: 1, Column: 37},
+												},
+												Indexed: true,
+												Range: ast.Range{
+													StartPos: ast.Position{Offset: 24, Line: 1, Column: 24},
+													EndPos:   ast.Position{Offset: 42, Line: 1, Column: 42},
+												},
+											},
+											{
+												Label: "",
+												Identifier: ast.Identifier{
+													Identifier: "indexed",
+													Pos:        ast.Position{Offset: 45, Line: 1, Column: 45},
+												},
+												TypeAnnotation: &ast.TypeAnnotation{
+													IsResource: false,
+													Type: &ast.NominalType{
+														Identifier: ast.Identifier{
+															Identifier: "Bool",
+															Pos:        ast.Position{Offset: 54, Line: 1, Column: 54},
+														},
+													},
+													StartPos: ast.Position{Offset: 54, Line: 1, Column: 54},
+												},
+												Range: ast.Range{
+													StartPos: ast.Position{Offset: 45, Line: 1, Column: 45},
+													EndPos:   ast.Position{Offset: 57, Line: 1, Column: 57},
+												},
+											},
+										},
+										Range: ast.Range{
+											StartPos: ast.Position{Offset: 7, Line: 1, Column: 7},
+											EndPos:   ast.Position{Offset: 58, Line: 1, Column: 58},
+										},
+									},
+									StartPos: ast.Position{Offset: 7, Line: 1, Column: 7},
+								},
+							},
+						},
+					),
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+						EndPos:   ast.Position{Offset: 58, Line: 1, Column: 58},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("indexed in function parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Parameters of functions cannot be indexed,
+		// so the identifier is the argument label
+
+		result, errs := ParseDeclarations("fun test(indexed a: Int) {}")
+		require.Empty(t, errs)
+
+		require.Len(t, result, 1)
+		require.IsType(t, &ast.FunctionDeclaration{}, result[0])
+
+		parameters := result[0].(*ast.FunctionDeclaration).ParameterList.Parameters
+		require.Len(t, parameters, 1)
+
+		parameter := parameters[0]
+		require.Equal(t, "indexed", parameter.Label)
+		require.Equal(t, "a", parameter.Identifier.Identifier)
+		require.False(t, parameter.Indexed)
+	})
 }
 
 func TestParseFieldWithVariableKind(t *testing.T) {
@@ -1700,6 +2020,49 @@ func TestParseFieldWithVariableKind(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("constant with value", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := parse("let x : Int = 1")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.FieldDeclaration{
+				Access:       ast.AccessNotSpecified,
+				VariableKind: ast.VariableKindConstant,
+				Identifier: ast.Identifier{
+					Identifier: "x",
+					Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+				},
+				TypeAnnotation: &ast.TypeAnnotation{
+					IsResource: false,
+					Type: &ast.NominalType{
+						Identifier: ast.Identifier{
+							Identifier: "Int",
+							Pos:        ast.Position{Line: 1, Column: 8, Offset: 8},
+						},
+					},
+					StartPos: ast.Position{Line: 1, Column: 8, Offset: 8},
+				},
+				Value: &ast.IntegerExpression{
+					PositiveLiteral: "1",
+					Value:           big.NewInt(1),
+					Base:            10,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 14, Offset: 14},
+						EndPos:   ast.Position{Line: 1, Column: 14, Offset: 14},
+					},
+				},
+				Range: ast.Range{
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+					EndPos:   ast.Position{Line: 1, Column: 14, Offset: 14},
+				},
+			},
+			result,
+		)
+	})
 }
 
 func TestParseCompositeDeclaration(t *testing.T) {
@@ -4898,3 +5261,181 @@ func TestParsePreconditionWithUnaryNegation(t *testing.T) {
 		result.Declarations(),
 	)
 }
+
+func TestParseEntitlementDeclaration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("top-level", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(" pub entitlement E ")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.EntitlementDeclaration{
+					Access: ast.AccessPublic,
+					Identifier: ast.Identifier{
+						Identifier: "E",
+						Pos:        ast.Position{Line: 1, Column: 17, Offset: 17},
+					},
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 1, Offset: 1},
+						EndPos:   ast.Position{Line: 1, Column: 17, Offset: 17},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(" contract C { entitlement E } ")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.CompositeDeclaration{
+					CompositeKind: common.CompositeKindContract,
+					Identifier: ast.Identifier{
+						Identifier: "C",
+						Pos:        ast.Position{Line: 1, Column: 10, Offset: 10},
+					},
+					Members: ast.NewMembers(
+						[]ast.Declaration{
+							&ast.EntitlementDeclaration{
+								Identifier: ast.Identifier{
+									Identifier: "E",
+									Pos:        ast.Position{Line: 1, Column: 26, Offset: 26},
+								},
+								Range: ast.Range{
+									StartPos: ast.Position{Line: 1, Column: 14, Offset: 14},
+									EndPos:   ast.Position{Line: 1, Column: 26, Offset: 26},
+								},
+							},
+						},
+					),
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 1, Offset: 1},
+						EndPos:   ast.Position{Line: 1, Column: 28, Offset: 28},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("missing identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations(" entitlement ")
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected identifier after start of entitlement declaration, got EOF",
+					Pos:     ast.Position{Offset: 13, Line: 1, Column: 13},
+				},
+			},
+			errs,
+		)
+	})
+}
+
+func TestParseEntitledMembers(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("field and function", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations(`
+          struct S {
+              access(E) let x: Int
+              access(E1, E2) fun f() {}
+          }
+        `)
+		require.Empty(t, errs)
+
+		require.Len(t, result, 1)
+		members := result[0].(*ast.CompositeDeclaration).Members
+
+		fields := members.Fields()
+		require.Len(t, fields, 1)
+		field := fields[0]
+
+		require.Equal(t, ast.AccessPublic, field.Access)
+		utils.AssertEqualWithDiff(t,
+			[]*ast.NominalType{
+				{
+					Identifier: ast.Identifier{
+						Identifier: "E",
+						Pos:        ast.Position{Offset: 43, Line: 3, Column: 21},
+					},
+				},
+			},
+			field.Entitlements,
+		)
+
+		functions := members.Functions()
+		require.Len(t, functions, 1)
+		function := functions[0]
+
+		require.Equal(t, ast.AccessPublic, function.Access)
+		utils.AssertEqualWithDiff(t,
+			[]*ast.NominalType{
+				{
+					Identifier: ast.Identifier{
+						Identifier: "E1",
+						Pos:        ast.Position{Offset: 78, Line: 4, Column: 21},
+					},
+				},
+				{
+					Identifier: ast.Identifier{
+						Identifier: "E2",
+						Pos:        ast.Position{Offset: 82, Line: 4, Column: 25},
+					},
+				},
+			},
+			function.Entitlements,
+		)
+	})
+
+	t.Run("top-level", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations(" access(E) fun f() {} ")
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "entitlement access is only supported for members",
+					Pos:     ast.Position{Offset: 10, Line: 1, Column: 10},
+				},
+			},
+			errs,
+		)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations(" contract C { access(E) struct S {} } ")
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "invalid entitlement access for type",
+					Pos:     ast.Position{Offset: 24, Line: 1, Column: 24},
+				},
+			},
+			errs,
+		)
+	})
+}
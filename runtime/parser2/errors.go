@@ -35,6 +35,8 @@ type Error struct {
 	Errors []error
 }
 
+func (Error) IsUserError() {}
+
 func (e Error) Error() string {
 	var sb strings.Builder
 	sb.WriteString("Parsing failed:\n")
@@ -79,6 +81,81 @@ func (e *SyntaxError) Error() string {
 	return e.Message
 }
 
+// NestingDepthLimitReachedError is reported when the nesting depth
+// of declarations, statements, expressions, and types exceeds the configured maximum
+
+type NestingDepthLimitReachedError struct {
+	Limit int
+	Pos   ast.Position
+}
+
+func (*NestingDepthLimitReachedError) isParseError() {}
+
+func (e *NestingDepthLimitReachedError) StartPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *NestingDepthLimitReachedError) EndPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *NestingDepthLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"nesting depth limit reached: maximum is %d",
+		e.Limit,
+	)
+}
+
+// TokenCountLimitReachedError is reported when the number of tokens
+// in the input exceeds the configured maximum
+
+type TokenCountLimitReachedError struct {
+	Limit int
+	Pos   ast.Position
+}
+
+func (*TokenCountLimitReachedError) isParseError() {}
+
+func (e *TokenCountLimitReachedError) StartPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *TokenCountLimitReachedError) EndPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *TokenCountLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"token count limit reached: maximum is %d",
+		e.Limit,
+	)
+}
+
+// ExperimentalFeatureNotEnabledError is reported when the syntax of an experimental feature
+// is used, but the feature is not enabled in the configuration
+
+type ExperimentalFeatureNotEnabledError struct {
+	Feature common.ExperimentalFeature
+	Pos     ast.Position
+}
+
+func (*ExperimentalFeatureNotEnabledError) isParseError() {}
+
+func (e *ExperimentalFeatureNotEnabledError) StartPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *ExperimentalFeatureNotEnabledError) EndPosition() ast.Position {
+	return e.Pos
+}
+
+func (e *ExperimentalFeatureNotEnabledError) Error() string {
+	return fmt.Sprintf(
+		"cannot use experimental feature `%s`: feature is not enabled",
+		e.Feature,
+	)
+}
+
 // JuxtaposedUnaryOperatorsError
 
 type JuxtaposedUnaryOperatorsError struct {
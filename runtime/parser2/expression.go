@@ -35,6 +35,7 @@ const (
 	exprLeftBindingPowerLogicalAnd
 	exprLeftBindingPowerComparison
 	exprLeftBindingPowerNilCoalescing
+	exprLeftBindingPowerRange
 	exprLeftBindingPowerBitwiseOr
 	exprLeftBindingPowerBitwiseXor
 	exprLeftBindingPowerBitwiseAnd
@@ -282,6 +283,18 @@ func init() {
 		rightAssociative: true,
 	})
 
+	defineExpr(binaryExpr{
+		tokenType:        lexer.TokenDotDotDot,
+		leftBindingPower: exprLeftBindingPowerRange,
+		operation:        ast.OperationRangeInclusive,
+	})
+
+	defineExpr(binaryExpr{
+		tokenType:        lexer.TokenDotDotLess,
+		leftBindingPower: exprLeftBindingPowerRange,
+		operation:        ast.OperationRangeExclusive,
+	})
+
 	defineExpr(binaryExpr{
 		tokenType:        lexer.TokenVerticalBar,
 		leftBindingPower: exprLeftBindingPowerBitwiseOr,
@@ -430,6 +443,18 @@ func init() {
 		},
 	})
 
+	defineExpr(literalExpr{
+		tokenType: lexer.TokenRawString,
+		nullDenotation: func(p *parser, token lexer.Token) ast.Expression {
+			parsedString, errs := parseRawStringLiteral(token.Value.(string))
+			p.report(errs...)
+			return &ast.StringExpression{
+				Value: parsedString,
+				Range: token.Range,
+			}
+		},
+	})
+
 	defineExpr(prefixExpr{
 		tokenType:    lexer.TokenMinus,
 		bindingPower: exprLeftBindingPowerUnaryPrefix,
@@ -554,7 +579,11 @@ func defineLessThanOrTypeArgumentsExpression() {
 
 			(func() {
 				defer func() {
-					_ = recover()
+					// Limits must not be circumvented
+					switch r := recover().(type) {
+					case *NestingDepthLimitReachedError, *TokenCountLimitReachedError:
+						panic(r)
+					}
 				}()
 
 				typeArguments = parseCommaSeparatedTypeAnnotations(p, lexer.TokenGreater)
@@ -1199,6 +1228,8 @@ func exprLeftDenotationAllowsWhitespaceAfterToken(tokenType lexer.TokenType) boo
 // parse expressions.
 //
 func parseExpression(p *parser, rightBindingPower int) ast.Expression {
+	p.enterNesting()
+	defer p.leaveNesting()
 
 	p.skipSpaceAndComments(true)
 	t := p.current
@@ -1349,6 +1380,34 @@ func parseStringLiteral(literal string) (result string, errs []error) {
 	return
 }
 
+// parseRawStringLiteral parses a raw string literal, including start and end backticks.
+//
+// The content of raw string literals is taken verbatim, there are no escape sequences.
+// Carriage returns are removed, so the value does not depend on the line endings of the source file
+//
+func parseRawStringLiteral(literal string) (result string, errs []error) {
+	report := func(err error) {
+		errs = append(errs, err)
+	}
+
+	length := len(literal)
+	if length == 0 || literal[0] != '`' {
+		report(fmt.Errorf("missing start of raw string literal: expected '`'"))
+		return
+	}
+
+	endOffset := length
+	if length >= 2 && literal[length-1] == '`' {
+		endOffset = length - 1
+	} else {
+		report(fmt.Errorf("invalid end of raw string literal: missing '`'"))
+	}
+
+	result = strings.ReplaceAll(literal[1:endOffset], "\r", "")
+
+	return
+}
+
 // parseStringLiteralContent parses the string literalExpr contents, excluding start and end quotes
 //
 func parseStringLiteralContent(s string) (result string, errs []error) {
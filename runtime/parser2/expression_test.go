@@ -1126,6 +1126,57 @@ func TestParseString(t *testing.T) {
 	})
 }
 
+func TestParseRawString(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid, multiple lines, no escapes", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("`{\"a\": \"\\n\"}\r\n<svg/>`")
+		assert.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.StringExpression{
+				Value: "{\"a\": \"\\n\"}\n<svg/>",
+				Range: ast.Range{
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+					EndPos:   ast.Position{Line: 2, Column: 6, Offset: 20},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("invalid, missing end", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("`abc")
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "invalid end of raw string literal: missing '`'",
+					Pos:     ast.Position{Offset: 4, Line: 1, Column: 4},
+				},
+			},
+			errs,
+		)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.StringExpression{
+				Value: "abc",
+				Range: ast.Range{
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+					EndPos:   ast.Position{Line: 1, Column: 3, Offset: 3},
+				},
+			},
+			result,
+		)
+	})
+}
+
 func TestInvocation(t *testing.T) {
 
 	t.Parallel()
@@ -5007,6 +5058,64 @@ func TestParseNilCoalescing(t *testing.T) {
 	)
 }
 
+func TestParseRangeExpression(t *testing.T) {
+
+	t.Parallel()
+
+	result, errs := ParseProgram(`
+       let x = 0..<n - 1
+	`)
+	require.Empty(t, errs)
+
+	utils.AssertEqualWithDiff(t,
+		[]ast.Declaration{
+			&ast.VariableDeclaration{
+				IsConstant: true,
+				Identifier: ast.Identifier{
+					Identifier: "x",
+					Pos:        ast.Position{Offset: 12, Line: 2, Column: 11},
+				},
+				Transfer: &ast.Transfer{
+					Operation: ast.TransferOperationCopy,
+					Pos:       ast.Position{Offset: 14, Line: 2, Column: 13},
+				},
+				Value: &ast.BinaryExpression{
+					Operation: ast.OperationRangeExclusive,
+					Left: &ast.IntegerExpression{
+						PositiveLiteral: "0",
+						Value:           big.NewInt(0),
+						Base:            10,
+						Range: ast.Range{
+							StartPos: ast.Position{Offset: 16, Line: 2, Column: 15},
+							EndPos:   ast.Position{Offset: 16, Line: 2, Column: 15},
+						},
+					},
+					Right: &ast.BinaryExpression{
+						Operation: ast.OperationMinus,
+						Left: &ast.IdentifierExpression{
+							Identifier: ast.Identifier{
+								Identifier: "n",
+								Pos:        ast.Position{Offset: 20, Line: 2, Column: 19},
+							},
+						},
+						Right: &ast.IntegerExpression{
+							PositiveLiteral: "1",
+							Value:           big.NewInt(1),
+							Base:            10,
+							Range: ast.Range{
+								StartPos: ast.Position{Offset: 24, Line: 2, Column: 23},
+								EndPos:   ast.Position{Offset: 24, Line: 2, Column: 23},
+							},
+						},
+					},
+				},
+				StartPos: ast.Position{Offset: 8, Line: 2, Column: 7},
+			},
+		},
+		result.Declarations(),
+	)
+}
+
 func TestParseNilCoalescingRightAssociativity(t *testing.T) {
 
 	t.Parallel()
@@ -26,6 +26,18 @@ import (
 )
 
 func parseParameterList(p *parser) (parameterList *ast.ParameterList) {
+	return parseParameters(p, false)
+}
+
+// parseEventParameterList parses the parameter list of an event declaration.
+// In addition to a parameter list of a function,
+// parameters may be marked as indexed, see parseParameter.
+//
+func parseEventParameterList(p *parser) (parameterList *ast.ParameterList) {
+	return parseParameters(p, true)
+}
+
+func parseParameters(p *parser, allowIndexed bool) (parameterList *ast.ParameterList) {
 	var parameters []*ast.Parameter
 
 	p.skipSpaceAndComments(true)
@@ -54,7 +66,7 @@ func parseParameterList(p *parser) (parameterList *ast.ParameterList) {
 			if !expectParameter {
 				panic("expected comma, got start of parameter")
 			}
-			parameter := parseParameter(p)
+			parameter := parseParameter(p, allowIndexed)
 			parameters = append(parameters, parameter)
 			expectParameter = false
 
@@ -105,7 +117,14 @@ func parseParameterList(p *parser) (parameterList *ast.ParameterList) {
 	}
 }
 
-func parseParameter(p *parser) *ast.Parameter {
+// parseParameter parses a parameter.
+//
+//     parameter : ( 'indexed' )? ( argumentLabel )? identifier ':' typeAnnotation
+//
+// The `indexed` modifier is only allowed if allowIndexed is true, i.e. for event parameters.
+// If it is followed by a colon, it is the parameter name.
+//
+func parseParameter(p *parser, allowIndexed bool) *ast.Parameter {
 	p.skipSpaceAndComments(true)
 
 	startPos := p.current.StartPos
@@ -122,6 +141,19 @@ func parseParameter(p *parser) *ast.Parameter {
 	// Skip the identifier
 	p.next()
 
+	indexed := false
+
+	if allowIndexed && parameterName == keywordIndexed {
+		p.skipSpaceAndComments(true)
+		if p.current.Is(lexer.TokenIdentifier) {
+			indexed = true
+			parameterName = p.current.Value.(string)
+			parameterPos = p.current.StartPos
+			// Skip the identifier
+			p.next()
+		}
+	}
+
 	// If another identifier is provided, then the previous identifier
 	// is the argument label, and this identifier is the parameter name
 
@@ -158,6 +190,7 @@ func parseParameter(p *parser) *ast.Parameter {
 			Pos:        parameterPos,
 		},
 		TypeAnnotation: typeAnnotation,
+		Indexed:        indexed,
 		Range: ast.Range{
 			StartPos: startPos,
 			EndPos:   endPos,
@@ -21,6 +21,7 @@ package lexer
 import (
 	"context"
 	"fmt"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/onflow/cadence/runtime/ast"
@@ -211,6 +212,28 @@ func (l *lexer) word() string {
 	return l.input[start:end]
 }
 
+// acceptString reads the given ASCII string ahead,
+// if the input continues with it, and returns true.
+// Otherwise, it does not read any input and returns false.
+//
+func (l *lexer) acceptString(s string) bool {
+	if !strings.HasPrefix(l.input[l.endOffset:], s) {
+		return false
+	}
+	for range s {
+		l.next()
+	}
+	return true
+}
+
+// isRangeOperatorAhead returns true if the current rune is a dot,
+// which starts a range operator, i.e. `...` or `..<`
+//
+func (l *lexer) isRangeOperatorAhead() bool {
+	return l.current == '.' &&
+		strings.HasPrefix(l.input[l.endOffset:], ".")
+}
+
 // acceptOne reads one rune ahead.
 // It returns true if the next rune matches with the input rune,
 // otherwise it steps back one rune and returns false.
@@ -381,6 +404,22 @@ func (l *lexer) scanString(quote rune) {
 	}
 }
 
+// scanRawString scans a raw string literal, after the opening backtick.
+// Raw string literals may span multiple lines and have no escape sequences
+//
+func (l *lexer) scanRawString() {
+	for {
+		switch l.next() {
+		case '`':
+			return
+		case EOF:
+			// NOTE: invalid end of string handled by parser
+			l.backupOne()
+			return
+		}
+	}
+}
+
 func (l *lexer) scanBinaryRemainder() {
 	l.acceptWhile(func(r rune) bool {
 		return r == '0' || r == '1' || r == '_'
@@ -405,7 +444,8 @@ func (l *lexer) scanHexadecimalRemainder() {
 func (l *lexer) scanDecimalOrFixedPointRemainder() TokenType {
 	l.acceptWhile(isDecimalDigitOrUnderscore)
 	r := l.next()
-	if r == '.' {
+	// A range operator follows an integer literal, e.g. `1...10`
+	if r == '.' && !l.isRangeOperatorAhead() {
 		l.scanFixedPointRemainder()
 		return TokenFixedPointNumberLiteral
 	} else {
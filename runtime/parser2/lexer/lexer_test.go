@@ -639,6 +639,75 @@ func TestLexBasic(t *testing.T) {
 			},
 		)
 	})
+
+	t.Run("range operators", func(t *testing.T) {
+		testLex(t,
+			"0..<10 1...2",
+			[]Token{
+				{
+					Type:  TokenDecimalIntegerLiteral,
+					Value: "0",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 1, Column: 0, Offset: 0},
+					},
+				},
+				{
+					Type: TokenDotDotLess,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 1, Offset: 1},
+						EndPos:   ast.Position{Line: 1, Column: 3, Offset: 3},
+					},
+				},
+				{
+					Type:  TokenDecimalIntegerLiteral,
+					Value: "10",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 4, Offset: 4},
+						EndPos:   ast.Position{Line: 1, Column: 5, Offset: 5},
+					},
+				},
+				{
+					Type:  TokenSpace,
+					Value: Space{" ", false},
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 6, Offset: 6},
+						EndPos:   ast.Position{Line: 1, Column: 6, Offset: 6},
+					},
+				},
+				{
+					Type:  TokenDecimalIntegerLiteral,
+					Value: "1",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+						EndPos:   ast.Position{Line: 1, Column: 7, Offset: 7},
+					},
+				},
+				{
+					Type: TokenDotDotDot,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 8, Offset: 8},
+						EndPos:   ast.Position{Line: 1, Column: 10, Offset: 10},
+					},
+				},
+				{
+					Type:  TokenDecimalIntegerLiteral,
+					Value: "2",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 11, Offset: 11},
+						EndPos:   ast.Position{Line: 1, Column: 11, Offset: 11},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 12, Offset: 12},
+						EndPos:   ast.Position{Line: 1, Column: 12, Offset: 12},
+					},
+				},
+			},
+		)
+	})
 }
 
 func TestLexString(t *testing.T) {
@@ -900,6 +969,57 @@ func TestLexString(t *testing.T) {
 	})
 }
 
+func TestLexRawString(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid, multiple lines, backslashes", func(t *testing.T) {
+		testLex(t,
+			"`a\\n\n\"b\"`",
+			[]Token{
+				{
+					Type:  TokenRawString,
+					Value: "`a\\n\n\"b\"`",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 2, Column: 3, Offset: 8},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 2, Column: 4, Offset: 9},
+						EndPos:   ast.Position{Line: 2, Column: 4, Offset: 9},
+					},
+				},
+			},
+		)
+	})
+
+	t.Run("invalid, missing end", func(t *testing.T) {
+		testLex(t,
+			"`a\nb",
+			[]Token{
+				{
+					Type:  TokenRawString,
+					Value: "`a\nb",
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 2, Column: 0, Offset: 3},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 2, Column: 1, Offset: 4},
+						EndPos:   ast.Position{Line: 2, Column: 1, Offset: 4},
+					},
+				},
+			},
+		)
+	})
+}
+
 func TestLexBlockComment(t *testing.T) {
 
 	t.Parallel()
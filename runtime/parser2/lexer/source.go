@@ -0,0 +1,246 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lexer
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// TriviaKind is the kind of trivia
+//
+type TriviaKind uint8
+
+const (
+	TriviaKindUnknown TriviaKind = iota
+	TriviaKindSpace
+	TriviaKindLineComment
+	TriviaKindBlockComment
+)
+
+func (k TriviaKind) String() string {
+	switch k {
+	case TriviaKindSpace:
+		return "space"
+	case TriviaKindLineComment:
+		return "line comment"
+	case TriviaKindBlockComment:
+		return "block comment"
+	}
+
+	return "unknown"
+}
+
+// Trivia is a part of the source code which does not affect
+// the meaning of the program, i.e. whitespace or a comment.
+//
+// Nested block comments are a single trivia.
+//
+type Trivia struct {
+	Kind TriviaKind
+	Text string
+	ast.Range
+}
+
+// SourceToken is a token together with its raw source text
+// and the trivia preceding it.
+//
+// The text of error tokens and the EOF token is empty.
+// For code without errors, concatenating the leading trivia and the text
+// of all source tokens, including the final EOF token, reproduces the code.
+//
+type SourceToken struct {
+	Token
+	Text          string
+	LeadingTrivia []Trivia
+}
+
+// SourceTokenStream is a stream of source tokens.
+//
+// Unlike the token stream used by the parser,
+// it preserves the raw text of tokens and comments,
+// so it can be used by tools like syntax highlighters and formatters,
+// which operate on tokens instead of a full parse.
+//
+type SourceTokenStream struct {
+	tokens  TokenStream
+	input   string
+	pending *Token
+	eof     *Token
+}
+
+// LexSource returns a stream of the source tokens of the given code.
+// The stream must be closed once it is no longer used.
+//
+func LexSource(input string) *SourceTokenStream {
+	return &SourceTokenStream{
+		tokens: Lex(input),
+		input:  input,
+	}
+}
+
+// Next returns the next source token.
+//
+// The last token is an EOF token, which has the trailing trivia of the code
+// as its leading trivia. After that, Next keeps returning EOF tokens without trivia.
+//
+func (s *SourceTokenStream) Next() SourceToken {
+	var trivia []Trivia
+
+	for {
+		if s.eof != nil {
+			return SourceToken{
+				Token: *s.eof,
+			}
+		}
+
+		token := s.nextToken()
+
+		switch token.Type {
+		case TokenSpace:
+			trivia = append(trivia, s.trivia(TriviaKindSpace, token.Range))
+
+		case TokenLineComment:
+			trivia = append(trivia, s.trivia(TriviaKindLineComment, token.Range))
+
+		case TokenBlockCommentStart:
+			trivia = append(trivia, s.blockComment(token))
+
+		case TokenEOF:
+			s.eof = &token
+			return SourceToken{
+				Token:         token,
+				LeadingTrivia: trivia,
+			}
+
+		case TokenError:
+			return SourceToken{
+				Token:         token,
+				LeadingTrivia: trivia,
+			}
+
+		default:
+			return SourceToken{
+				Token:         token,
+				Text:          s.text(token.Range),
+				LeadingTrivia: trivia,
+			}
+		}
+	}
+}
+
+// Close stops the underlying lexer
+//
+func (s *SourceTokenStream) Close() {
+	s.tokens.Close()
+}
+
+// Input returns the whole input as source code
+//
+func (s *SourceTokenStream) Input() string {
+	return s.input
+}
+
+func (s *SourceTokenStream) nextToken() Token {
+	if s.pending != nil {
+		token := *s.pending
+		s.pending = nil
+		return token
+	}
+
+	return s.tokens.Next()
+}
+
+// blockComment consumes the tokens of a potentially nested block comment,
+// starting after the given start token, and returns it as a single trivia.
+//
+// If the comment is unterminated, the comment extends to the last consumed comment token.
+//
+func (s *SourceTokenStream) blockComment(start Token) Trivia {
+	depth := 1
+	endPos := start.EndPos
+
+	for depth > 0 {
+		token := s.nextToken()
+
+		switch token.Type {
+		case TokenBlockCommentStart:
+			depth++
+		case TokenBlockCommentEnd:
+			depth--
+		case TokenBlockCommentContent:
+			// part of the comment
+		default:
+			s.pending = &token
+			depth = 0
+			continue
+		}
+
+		endPos = token.EndPos
+	}
+
+	return s.trivia(
+		TriviaKindBlockComment,
+		ast.Range{
+			StartPos: start.StartPos,
+			EndPos:   endPos,
+		},
+	)
+}
+
+func (s *SourceTokenStream) trivia(kind TriviaKind, r ast.Range) Trivia {
+	return Trivia{
+		Kind:  kind,
+		Text:  s.text(r),
+		Range: r,
+	}
+}
+
+// text returns the source code in the given range, which is inclusive
+//
+func (s *SourceTokenStream) text(r ast.Range) string {
+	start := r.StartPos.Offset
+	end := r.EndPos.Offset + 1
+
+	length := len(s.input)
+	if end > length {
+		end = length
+	}
+	if start < 0 || start > end {
+		return ""
+	}
+
+	return s.input[start:end]
+}
+
+// SourceTokens returns all source tokens of the given code,
+// including the final EOF token
+//
+func SourceTokens(input string) []SourceToken {
+	stream := LexSource(input)
+	defer stream.Close()
+
+	var tokens []SourceToken
+	for {
+		token := stream.Next()
+		tokens = append(tokens, token)
+		if token.Is(TokenEOF) {
+			return tokens
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func sourceText(tokens []SourceToken) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		for _, trivia := range token.LeadingTrivia {
+			builder.WriteString(trivia.Text)
+		}
+		builder.WriteString(token.Text)
+	}
+	return builder.String()
+}
+
+func TestSourceTokens(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("text and trivia", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = "let x = 0x1 // one\n"
+
+		tokens := SourceTokens(code)
+
+		require.Len(t, tokens, 5)
+
+		assert.Equal(t, TokenIdentifier, tokens[0].Type)
+		assert.Equal(t, "let", tokens[0].Text)
+		assert.Empty(t, tokens[0].LeadingTrivia)
+
+		assert.Equal(t, TokenIdentifier, tokens[1].Type)
+		assert.Equal(t, "x", tokens[1].Text)
+		assert.Equal(t,
+			[]Trivia{
+				{
+					Kind: TriviaKindSpace,
+					Text: " ",
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 3, Line: 1, Column: 3},
+						EndPos:   ast.Position{Offset: 3, Line: 1, Column: 3},
+					},
+				},
+			},
+			tokens[1].LeadingTrivia,
+		)
+
+		assert.Equal(t, TokenEqual, tokens[2].Type)
+		assert.Equal(t, "=", tokens[2].Text)
+
+		assert.Equal(t, TokenHexadecimalIntegerLiteral, tokens[3].Type)
+		assert.Equal(t, "0x1", tokens[3].Text)
+		assert.Equal(t,
+			ast.Range{
+				StartPos: ast.Position{Offset: 8, Line: 1, Column: 8},
+				EndPos:   ast.Position{Offset: 10, Line: 1, Column: 10},
+			},
+			tokens[3].Range,
+		)
+
+		eof := tokens[4]
+		assert.Equal(t, TokenEOF, eof.Type)
+		assert.Empty(t, eof.Text)
+		require.Len(t, eof.LeadingTrivia, 3)
+		assert.Equal(t, TriviaKindLineComment, eof.LeadingTrivia[1].Kind)
+		assert.Equal(t, "// one", eof.LeadingTrivia[1].Text)
+		assert.Equal(t, "\n", eof.LeadingTrivia[2].Text)
+
+		assert.Equal(t, code, sourceText(tokens))
+	})
+
+	t.Run("nested block comment", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = "/* a /* b */ c */ x"
+
+		tokens := SourceTokens(code)
+
+		require.Len(t, tokens, 2)
+		require.Len(t, tokens[0].LeadingTrivia, 2)
+
+		comment := tokens[0].LeadingTrivia[0]
+		assert.Equal(t, TriviaKindBlockComment, comment.Kind)
+		assert.Equal(t, "/* a /* b */ c */", comment.Text)
+		assert.Equal(t,
+			ast.Range{
+				StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+				EndPos:   ast.Position{Offset: 16, Line: 1, Column: 16},
+			},
+			comment.Range,
+		)
+
+		assert.Equal(t, "x", tokens[0].Text)
+		assert.Equal(t, code, sourceText(tokens))
+	})
+
+	t.Run("unterminated block comment", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = "x /* a /* b */"
+
+		tokens := SourceTokens(code)
+
+		require.Len(t, tokens, 2)
+
+		eof := tokens[1]
+		assert.Equal(t, TokenEOF, eof.Type)
+		require.Len(t, eof.LeadingTrivia, 2)
+		assert.Equal(t, TriviaKindBlockComment, eof.LeadingTrivia[1].Kind)
+		assert.Equal(t, "/* a /* b */", eof.LeadingTrivia[1].Text)
+
+		assert.Equal(t, code, sourceText(tokens))
+	})
+
+	t.Run("multi-byte characters", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `"héllo" + x`
+
+		tokens := SourceTokens(code)
+
+		require.Len(t, tokens, 4)
+		assert.Equal(t, `"héllo"`, tokens[0].Text)
+		assert.Equal(t, "x", tokens[2].Text)
+
+		assert.Equal(t, code, sourceText(tokens))
+	})
+
+	t.Run("error", func(t *testing.T) {
+
+		t.Parallel()
+
+		tokens := SourceTokens("a $ b")
+
+		require.Len(t, tokens, 3)
+		assert.Equal(t, "a", tokens[0].Text)
+
+		assert.Equal(t, TokenError, tokens[1].Type)
+		assert.Empty(t, tokens[1].Text)
+		assert.Implements(t, (*error)(nil), tokens[1].Value)
+		assert.Len(t, tokens[1].LeadingTrivia, 1)
+
+		assert.Equal(t, TokenEOF, tokens[2].Type)
+	})
+
+	t.Run("after EOF", func(t *testing.T) {
+
+		t.Parallel()
+
+		stream := LexSource(" x ")
+		defer stream.Close()
+
+		assert.Equal(t, TokenIdentifier, stream.Next().Type)
+
+		eof := stream.Next()
+		assert.Equal(t, TokenEOF, eof.Type)
+		assert.Len(t, eof.LeadingTrivia, 1)
+
+		eof = stream.Next()
+		assert.Equal(t, TokenEOF, eof.Type)
+		assert.Empty(t, eof.LeadingTrivia)
+	})
+}
@@ -66,7 +66,13 @@ func rootState(l *lexer) stateFn {
 		case ':':
 			l.emitType(TokenColon)
 		case '.':
-			l.emitType(TokenDot)
+			if l.acceptString("..") {
+				l.emitType(TokenDotDotDot)
+			} else if l.acceptString(".<") {
+				l.emitType(TokenDotDotLess)
+			} else {
+				l.emitType(TokenDot)
+			}
 		case '=':
 			if l.acceptOne('=') {
 				l.emitType(TokenEqualEqual)
@@ -110,6 +116,8 @@ func rootState(l *lexer) stateFn {
 			return numberState
 		case '"':
 			return stringState
+		case '`':
+			return rawStringState
 		case '/':
 			r = l.next()
 			switch r {
@@ -215,8 +223,14 @@ func numberState(l *lexer) stateFn {
 			l.emitValue(tokenType)
 
 		case '.':
-			l.scanFixedPointRemainder()
-			l.emitValue(TokenFixedPointNumberLiteral)
+			// A range operator follows an integer literal, e.g. `0..<10`
+			if l.isRangeOperatorAhead() {
+				l.backupOne()
+				l.emitValue(TokenDecimalIntegerLiteral)
+			} else {
+				l.scanFixedPointRemainder()
+				l.emitValue(TokenFixedPointNumberLiteral)
+			}
 
 		case EOF:
 			l.backupOne()
@@ -295,6 +309,12 @@ func stringState(l *lexer) stateFn {
 	return rootState
 }
 
+func rawStringState(l *lexer) stateFn {
+	l.scanRawString()
+	l.emitValue(TokenRawString)
+	return rootState
+}
+
 func lineCommentState(l *lexer) stateFn {
 	l.scanLineComment()
 	l.emitValue(TokenLineComment)
@@ -81,6 +81,9 @@ const (
 	TokenAsExclamationMark
 	TokenAsQuestionMark
 	TokenPragma
+	TokenRawString
+	TokenDotDotDot
+	TokenDotDotLess
 	// NOTE: not an actual token, must be last item
 	TokenMax
 )
@@ -116,6 +119,8 @@ func (t TokenType) String() string {
 		return "identifier"
 	case TokenString:
 		return "string"
+	case TokenRawString:
+		return "raw string"
 	case TokenPlus:
 		return `'+'`
 	case TokenMinus:
@@ -202,6 +207,10 @@ func (t TokenType) String() string {
 		return `'as?'`
 	case TokenPragma:
 		return `'#'`
+	case TokenDotDotDot:
+		return `'...'`
+	case TokenDotDotLess:
+		return `'..<'`
 	default:
 		panic(errors.NewUnreachableError())
 	}
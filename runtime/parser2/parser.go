@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/parser2/lexer"
 )
 
@@ -33,6 +34,32 @@ import (
 
 const lowestBindingPower = 0
 
+// Config is the configuration of the parser
+//
+type Config struct {
+	// MaximumNestingDepth is the maximum nesting depth
+	// of declarations, statements, expressions, and types.
+	// Zero means the depth is not limited
+	MaximumNestingDepth int
+	// MaximumTokenCount is the maximum number of tokens in the input,
+	// including whitespace and comments.
+	// Zero means the number of tokens is not limited
+	MaximumTokenCount int
+	// EnabledFeatures are the experimental features which are enabled,
+	// i.e. which syntax is accepted, see common.ExperimentalFeature.
+	// By default no experimental features are enabled
+	EnabledFeatures common.ExperimentalFeatures
+}
+
+// DefaultConfig is the configuration used by the parse functions
+// which do not accept a configuration.
+//
+// The nesting depth is limited to prevent the exhaustion of the stack.
+//
+var DefaultConfig = Config{
+	MaximumNestingDepth: 1000,
+}
+
 type parser struct {
 	// tokens is a stream of tokens from the lexer
 	tokens lexer.TokenStream
@@ -49,6 +76,12 @@ type parser struct {
 	bufferPos int
 	// bufferedErrors are the parsing errors encountered during buffering
 	bufferedErrors []error
+	// config is the configuration of the parser
+	config Config
+	// depth is the current nesting depth
+	depth int
+	// tokenCount is the number of tokens read from the lexer
+	tokenCount int
 }
 
 // Parse creates a lexer to scan the given input string,
@@ -58,13 +91,42 @@ type parser struct {
 // See "ParseExpression", "ParseStatements" as examples.
 //
 func Parse(input string, parse func(*parser) interface{}) (result interface{}, errors []error) {
+	return ParseWithConfig(input, parse, DefaultConfig)
+}
+
+// ParseWithConfig is like Parse, but uses the given configuration
+//
+func ParseWithConfig(
+	input string,
+	parse func(*parser) interface{},
+	config Config,
+) (
+	result interface{},
+	errors []error,
+) {
 	// create a lexer, which turns the input string into tokens
 	tokens := lexer.Lex(input)
-	return ParseTokenStream(tokens, parse)
+	return ParseTokenStreamWithConfig(tokens, parse, config)
 }
 
 func ParseTokenStream(tokens lexer.TokenStream, parse func(*parser) interface{}) (result interface{}, errors []error) {
-	p := &parser{tokens: tokens}
+	return ParseTokenStreamWithConfig(tokens, parse, DefaultConfig)
+}
+
+// ParseTokenStreamWithConfig is like ParseTokenStream, but uses the given configuration
+//
+func ParseTokenStreamWithConfig(
+	tokens lexer.TokenStream,
+	parse func(*parser) interface{},
+	config Config,
+) (
+	result interface{},
+	errors []error,
+) {
+	p := &parser{
+		tokens: tokens,
+		config: config,
+	}
 
 	defer tokens.Close()
 
@@ -130,6 +192,25 @@ func (p *parser) report(errs ...error) {
 	}
 }
 
+// checkExperimentalFeature returns true if the given experimental feature is enabled.
+// If it is not enabled, an error is reported at the given position.
+//
+// It should be called when the syntax of an experimental feature is encountered,
+// see common.ExperimentalFeature
+//
+func (p *parser) checkExperimentalFeature(feature common.ExperimentalFeature, pos ast.Position) bool {
+	if p.config.EnabledFeatures.Enabled(feature) {
+		return true
+	}
+
+	p.report(&ExperimentalFeatureNotEnabledError{
+		Feature: feature,
+		Pos:     pos,
+	})
+
+	return false
+}
+
 const bufferPosTrimThreshold = 128
 
 // maybeTrimBuffer checks whether the index of token we've read from buffered tokens
@@ -150,7 +231,18 @@ func (p *parser) maybeTrimBuffer() {
 func (p *parser) next() {
 	// nextFromLexer reads the next token from the lexer.
 	nextFromLexer := func() lexer.Token {
-		return p.tokens.Next()
+		token := p.tokens.Next()
+
+		p.tokenCount++
+		maximumTokenCount := p.config.MaximumTokenCount
+		if maximumTokenCount > 0 && p.tokenCount > maximumTokenCount {
+			panic(&TokenCountLimitReachedError{
+				Limit: maximumTokenCount,
+				Pos:   token.StartPos,
+			})
+		}
+
+		return token
 	}
 
 	// nextFromLexer reads the next token from the buffer tokens, assuming there are buffered tokens.
@@ -332,6 +424,27 @@ func (p *parser) startBuffering() {
 	}
 }
 
+// enterNesting is called when a nested declaration, statement, expression, or type
+// is about to be parsed. It panics if the maximum nesting depth is reached.
+//
+// leaveNesting must be called once the nested element was parsed.
+//
+func (p *parser) enterNesting() {
+	p.depth++
+
+	maximumNestingDepth := p.config.MaximumNestingDepth
+	if maximumNestingDepth > 0 && p.depth > maximumNestingDepth {
+		panic(&NestingDepthLimitReachedError{
+			Limit: maximumNestingDepth,
+			Pos:   p.current.StartPos,
+		})
+	}
+}
+
+func (p *parser) leaveNesting() {
+	p.depth--
+}
+
 func mustIdentifier(p *parser) ast.Identifier {
 	identifier := p.mustOne(lexer.TokenIdentifier)
 	return tokenToIdentifier(identifier)
@@ -413,15 +526,38 @@ func ParseArgumentList(input string) (arguments ast.Arguments, errors []error) {
 }
 
 func ParseProgram(input string) (program *ast.Program, err error) {
-	return ParseProgramFromTokenStream(lexer.Lex(input))
+	return ParseProgramWithConfig(input, DefaultConfig)
+}
+
+// ParseProgramWithConfig is like ParseProgram, but uses the given configuration
+//
+func ParseProgramWithConfig(input string, config Config) (program *ast.Program, err error) {
+	return ParseProgramFromTokenStreamWithConfig(lexer.Lex(input), config)
 }
 
 func ParseProgramFromTokenStream(input lexer.TokenStream) (program *ast.Program, err error) {
+	return ParseProgramFromTokenStreamWithConfig(input, DefaultConfig)
+}
+
+// ParseProgramFromTokenStreamWithConfig is like ParseProgramFromTokenStream,
+// but uses the given configuration
+//
+func ParseProgramFromTokenStreamWithConfig(
+	input lexer.TokenStream,
+	config Config,
+) (
+	program *ast.Program,
+	err error,
+) {
 	var res interface{}
 	var errs []error
-	res, errs = ParseTokenStream(input, func(p *parser) interface{} {
-		return parseDeclarations(p, lexer.TokenEOF)
-	})
+	res, errs = ParseTokenStreamWithConfig(
+		input,
+		func(p *parser) interface{} {
+			return parseDeclarations(p, lexer.TokenEOF)
+		},
+		config,
+	)
 	if len(errs) > 0 {
 		err = Error{
 			Code:   input.Input(),
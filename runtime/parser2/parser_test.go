@@ -21,6 +21,7 @@ package parser2
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ import (
 	"go.uber.org/goleak"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/parser2/lexer"
 	"github.com/onflow/cadence/runtime/tests/utils"
 )
@@ -430,3 +432,212 @@ func TestParseArgumentList(t *testing.T) {
 	})
 
 }
+
+func TestParseLimits(t *testing.T) {
+
+	t.Parallel()
+
+	const depth = 100
+
+	deeplyNested := map[string]string{
+		"expression":  "let x = " + strings.Repeat("(", depth) + "1" + strings.Repeat(")", depth),
+		"array":       "let x = " + strings.Repeat("[", depth) + strings.Repeat("]", depth),
+		"unary":       "let x = " + strings.Repeat("-", depth) + "1",
+		"type":        "let x: " + strings.Repeat("[", depth) + "Int" + strings.Repeat("]", depth) + " = []",
+		"dictionary":  "let x: " + strings.Repeat("{Int: ", depth) + "Int" + strings.Repeat("}", depth) + " = {}",
+		"block":       "fun test() " + strings.Repeat("{ if true ", depth) + "{" + strings.Repeat("}", depth+1),
+		"composite":   strings.Repeat("struct S { ", depth) + strings.Repeat("}", depth),
+		"invocation":  "let x = f<" + strings.Repeat("[", depth) + "Int" + strings.Repeat("]", depth) + ">()",
+		"conditional": "let x = " + strings.Repeat("true ? 1 : ", depth) + "1",
+	}
+
+	for name, code := range deeplyNested {
+
+		code := code
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			t.Run("within limit", func(t *testing.T) {
+
+				t.Parallel()
+
+				_, err := ParseProgramWithConfig(
+					code,
+					Config{
+						MaximumNestingDepth: 10 * depth,
+					},
+				)
+				require.NoError(t, err)
+			})
+
+			t.Run("limit reached", func(t *testing.T) {
+
+				t.Parallel()
+
+				_, err := ParseProgramWithConfig(
+					code,
+					Config{
+						MaximumNestingDepth: depth / 2,
+					},
+				)
+				require.Error(t, err)
+
+				require.IsType(t, Error{}, err)
+				errs := err.(Error).Errors
+				require.Len(t, errs, 1)
+
+				var limitErr *NestingDepthLimitReachedError
+				require.ErrorAs(t, errs[0], &limitErr)
+				assert.Equal(t, depth/2, limitErr.Limit)
+			})
+		})
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		const depth = 100_000
+
+		code := "let x = " + strings.Repeat("(", depth) + "1" + strings.Repeat(")", depth)
+
+		_, err := ParseProgram(code)
+		require.Error(t, err)
+
+		require.IsType(t, Error{}, err)
+		errs := err.(Error).Errors
+		require.Len(t, errs, 1)
+
+		var limitErr *NestingDepthLimitReachedError
+		require.ErrorAs(t, errs[0], &limitErr)
+		assert.Equal(t, DefaultConfig.MaximumNestingDepth, limitErr.Limit)
+	})
+
+	t.Run("token count", func(t *testing.T) {
+
+		t.Parallel()
+
+		code := strings.Repeat("let x = 1\n", 100)
+
+		_, err := ParseProgramWithConfig(
+			code,
+			Config{
+				MaximumTokenCount: 1000,
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = ParseProgramWithConfig(
+			code,
+			Config{
+				MaximumTokenCount: 100,
+			},
+		)
+		require.Error(t, err)
+
+		require.IsType(t, Error{}, err)
+		errs := err.(Error).Errors
+		require.Len(t, errs, 1)
+
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&TokenCountLimitReachedError{
+					Limit: 100,
+					Pos:   ast.Position{Offset: 126, Line: 13, Column: 6},
+				},
+			},
+			errs,
+		)
+	})
+}
+
+func TestParseExperimentalFeature(t *testing.T) {
+
+	t.Parallel()
+
+	const feature common.ExperimentalFeature = "test"
+
+	parse := func(config Config) []error {
+		_, errs := ParseWithConfig(
+			"x",
+			func(p *parser) interface{} {
+				if p.checkExperimentalFeature(feature, p.current.StartPos) {
+					p.next()
+				}
+				return nil
+			},
+			config,
+		)
+		return errs
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		errs := parse(Config{
+			EnabledFeatures: common.NewExperimentalFeatures(feature),
+		})
+		require.Empty(t, errs)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		errs := parse(Config{})
+
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&ExperimentalFeatureNotEnabledError{
+					Feature: feature,
+					Pos:     ast.Position{Offset: 0, Line: 1, Column: 0},
+				},
+				&SyntaxError{
+					Message: "unexpected token: identifier",
+					Pos:     ast.Position{Offset: 0, Line: 1, Column: 0},
+				},
+			},
+			errs,
+		)
+	})
+}
+
+func TestParseProgramNodeIDs(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub contract C {
+          pub fun test(a: Int): Int {
+              pre { a > 0 }
+              let b = [a, 2]
+              return b[0] + a
+          }
+      }
+    `
+
+	program1, err := ParseProgram(code)
+	require.NoError(t, err)
+
+	program2, err := ParseProgram(code)
+	require.NoError(t, err)
+
+	nodeIDs1 := program1.NodeIDs()
+	nodeIDs2 := program2.NodeIDs()
+
+	require.Equal(t, nodeIDs1.Count(), nodeIDs2.Count())
+
+	// The elements of separately parsed programs have the same IDs
+
+	for id := ast.NodeID(1); int(id) <= nodeIDs1.Count(); id++ {
+		element1 := nodeIDs1.Element(id)
+		element2 := nodeIDs2.Element(id)
+
+		assert.IsType(t, element1, element2)
+		assert.Equal(t, element1.StartPosition(), element2.StartPosition())
+		assert.Equal(t, element1.EndPosition(), element2.EndPosition())
+	}
+}
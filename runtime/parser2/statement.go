@@ -72,6 +72,9 @@ func parseStatements(p *parser, isEndToken func(token lexer.Token) bool) (statem
 }
 
 func parseStatement(p *parser) ast.Statement {
+	p.enterNesting()
+	defer p.leaveNesting()
+
 	p.skipSpaceAndComments(true)
 
 	// It might start with a keyword for a statement
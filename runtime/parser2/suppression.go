@@ -0,0 +1,88 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser2
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser2/lexer"
+)
+
+var suppressionCommentRegexp = regexp.MustCompile(`^//\s*cadence-disable-(next-)?line(?:\s+(.*))?$`)
+
+var suppressionCodeSeparatorRegexp = regexp.MustCompile(`[\s,]+`)
+
+// ParseDiagnosticSuppressions parses the suppression comments of the given code.
+//
+// A suppression comment is a line comment of the form
+// `// cadence-disable-line [<code>, ...]`, which suppresses diagnostics on the line of the comment,
+// or `// cadence-disable-next-line [<code>, ...]`, which suppresses diagnostics on the following line.
+//
+// If no codes are given, all diagnostics on the line are suppressed.
+//
+// The validity of the codes is NOT checked by this function.
+//
+func ParseDiagnosticSuppressions(input string) []ast.DiagnosticSuppression {
+	var suppressions []ast.DiagnosticSuppression
+
+	tokens := lexer.LexSource(input)
+	defer tokens.Close()
+
+	for {
+		token := tokens.Next()
+
+		for _, trivia := range token.LeadingTrivia {
+			if trivia.Kind != lexer.TriviaKindLineComment {
+				continue
+			}
+
+			match := suppressionCommentRegexp.FindStringSubmatch(strings.TrimSpace(trivia.Text))
+			if match == nil {
+				continue
+			}
+
+			line := trivia.StartPos.Line
+			if match[1] != "" {
+				line++
+			}
+
+			var codes []string
+			for _, code := range suppressionCodeSeparatorRegexp.Split(match[2], -1) {
+				if code == "" {
+					continue
+				}
+				codes = append(codes, code)
+			}
+
+			suppressions = append(
+				suppressions,
+				ast.DiagnosticSuppression{
+					Line:  line,
+					Codes: codes,
+				},
+			)
+		}
+
+		if token.Is(lexer.TokenEOF) {
+			return suppressions
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func TestParseDiagnosticSuppressions(t *testing.T) {
+
+	actual := ParseDiagnosticSuppressions(`
+      // cadence-disable-next-line
+      let x = 1
+      let y = 2 // cadence-disable-line UnnecessaryCastHint, ReplacementHint
+      // not a cadence-disable-line comment
+      /* cadence-disable-line */
+      //cadence-disable-next-line   RemovalHint
+    `)
+
+	require.Equal(t,
+		[]ast.DiagnosticSuppression{
+			{
+				Line: 3,
+			},
+			{
+				Line:  4,
+				Codes: []string{"UnnecessaryCastHint", "ReplacementHint"},
+			},
+			{
+				Line:  8,
+				Codes: []string{"RemovalHint"},
+			},
+		},
+		actual,
+	)
+}
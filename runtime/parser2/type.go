@@ -155,12 +155,28 @@ func init() {
 			switch token.Value {
 			case keywordAuth:
 				p.skipSpaceAndComments(true)
+
+				var entitlements []*ast.NominalType
+				if p.current.Is(lexer.TokenParenOpen) {
+					// Skip the opening paren
+					p.next()
+
+					entitlements, _ = parseNominalTypes(p, lexer.TokenParenClose)
+					if len(entitlements) == 0 {
+						p.report(fmt.Errorf("expected at least one entitlement"))
+					}
+
+					p.mustOne(lexer.TokenParenClose)
+					p.skipSpaceAndComments(true)
+				}
+
 				p.mustOne(lexer.TokenAmpersand)
 				right := parseType(p, typeLeftBindingPowerReference)
 				return &ast.ReferenceType{
-					Authorized: true,
-					Type:       right,
-					StartPos:   token.StartPos,
+					Authorized:   true,
+					Entitlements: entitlements,
+					Type:         right,
+					StartPos:     token.StartPos,
 				}
 
 			default:
@@ -649,6 +665,8 @@ func parseParameterTypeAnnotations(p *parser) (typeAnnotations []*ast.TypeAnnota
 }
 
 func parseType(p *parser, rightBindingPower int) ast.Type {
+	p.enterNesting()
+	defer p.leaveNesting()
 
 	p.skipSpaceAndComments(true)
 	t := p.current
@@ -263,6 +263,58 @@ func TestParseReferenceType(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("entitled, nominal", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseType("auth(E1, E2) &Int")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.ReferenceType{
+				Authorized: true,
+				Entitlements: []*ast.NominalType{
+					{
+						Identifier: ast.Identifier{
+							Identifier: "E1",
+							Pos:        ast.Position{Line: 1, Column: 5, Offset: 5},
+						},
+					},
+					{
+						Identifier: ast.Identifier{
+							Identifier: "E2",
+							Pos:        ast.Position{Line: 1, Column: 9, Offset: 9},
+						},
+					},
+				},
+				Type: &ast.NominalType{
+					Identifier: ast.Identifier{
+						Identifier: "Int",
+						Pos:        ast.Position{Line: 1, Column: 14, Offset: 14},
+					},
+				},
+				StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+			},
+			result,
+		)
+	})
+
+	t.Run("entitled, empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseType("auth() &Int")
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected at least one entitlement",
+					Pos:     ast.Position{Line: 1, Column: 5, Offset: 5},
+				},
+			},
+			errs,
+		)
+	})
 }
 
 func TestParseOptionalReferenceType(t *testing.T) {
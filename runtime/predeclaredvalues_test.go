@@ -146,3 +146,115 @@ func TestRuntimePredeclaredValues(t *testing.T) {
 
 	require.IsType(t, &sema.NotDeclaredError{}, errs[1])
 }
+
+func TestRuntimePredeclaredTypes(t *testing.T) {
+
+	t.Parallel()
+
+	// Declare a type 'Secret' and a function 'privileged',
+	// which are only available to the contracts of the service account 0x1.
+	// The script imports the contracts of 0x1 and 0x2.
+	// The script and both contracts attempt to use the type and function.
+
+	serviceAddress := common.BytesToAddress([]byte{0x1})
+	otherAddress := common.BytesToAddress([]byte{0x2})
+
+	availableToServiceAccount := AvailableToAccounts(serviceAddress)
+
+	typeDeclaration := TypeDeclaration{
+		Name: "Secret",
+		Type: &sema.CompositeType{
+			Location:   common.IdentifierLocation("Host"),
+			Identifier: "Secret",
+			Kind:       common.CompositeKindStructure,
+			Members:    &sema.StringMemberOrderedMap{},
+		},
+		Kind:      common.DeclarationKindStructure,
+		Available: availableToServiceAccount,
+	}
+
+	valueDeclaration := ValueDeclaration{
+		Name: "privileged",
+		Type: &sema.FunctionType{
+			ReturnTypeAnnotation: &sema.TypeAnnotation{
+				Type: sema.VoidType,
+			},
+		},
+		Kind:       common.DeclarationKindFunction,
+		IsConstant: true,
+		Available:  availableToServiceAccount,
+	}
+
+	serviceProgram := []byte(`
+      pub contract C1 {
+          pub fun main(_ secret: Secret?) {
+              privileged()
+          }
+      }
+    `)
+
+	otherProgram := []byte(`
+      pub contract C2 {
+          pub fun main(_ secret: Secret?) {
+              privileged()
+          }
+      }
+    `)
+
+	script := []byte(`
+      import 0x1
+      import 0x2
+
+      pub fun main() {
+          let secret: Secret? = nil
+      }
+    `)
+
+	runtime := newTestInterpreterRuntime()
+
+	runtimeInterface := &testRuntimeInterface{
+		getAccountContractCode: func(address Address, name string) (bytes []byte, err error) {
+			switch address {
+			case serviceAddress:
+				return serviceProgram, nil
+			case otherAddress:
+				return otherProgram, nil
+			default:
+				return nil, fmt.Errorf("unknown address: %s", address.ShortHexWithPrefix())
+			}
+		},
+	}
+
+	_, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface:         runtimeInterface,
+			Location:          common.ScriptLocation{},
+			PredeclaredValues: []ValueDeclaration{valueDeclaration},
+			PredeclaredTypes:  []TypeDeclaration{typeDeclaration},
+		},
+	)
+
+	errs := checker.ExpectCheckerErrors(t, err, 2)
+
+	// The illegal uses of 'Secret' and 'privileged' in 0x2 should be reported
+
+	var importedProgramError *sema.ImportedProgramError
+	require.ErrorAs(t, errs[0], &importedProgramError)
+	require.Equal(t,
+		common.AddressLocation{
+			Address: otherAddress,
+		},
+		importedProgramError.Location,
+	)
+	importedErrs := checker.ExpectCheckerErrors(t, importedProgramError.Err, 3)
+	for _, importedErr := range importedErrs {
+		require.IsType(t, &sema.NotDeclaredError{}, importedErr)
+	}
+
+	// The illegal use of 'Secret' in the script should be reported
+
+	require.IsType(t, &sema.NotDeclaredError{}, errs[1])
+}
@@ -357,6 +357,13 @@ func TestRuntimeScriptParameterTypeValidation(t *testing.T) {
 					},
 				).WithType(SignAlgoType)
 
+			case sema.RoundingRuleType:
+				value = cadence.NewEnum(
+					[]cadence.Value{
+						cadence.NewUInt8(0),
+					},
+				).WithType(RoundingRuleType)
+
 			case sema.PublicKeyType:
 				value = cadence.NewStruct(
 					[]cadence.Value{
@@ -836,6 +843,13 @@ func TestRuntimeTransactionParameterTypeValidation(t *testing.T) {
 					},
 				).WithType(SignAlgoType)
 
+			case sema.RoundingRuleType:
+				value = cadence.NewEnum(
+					[]cadence.Value{
+						cadence.NewUInt8(0),
+					},
+				).WithType(RoundingRuleType)
+
 			case sema.PublicKeyType:
 				value = cadence.NewStruct(
 					[]cadence.Value{
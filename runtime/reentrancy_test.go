@@ -0,0 +1,219 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeReentrancyHandling(t *testing.T) {
+
+	t.Parallel()
+
+	const bankContract = `
+      pub contract Bank {
+
+          pub var withdrawals: Int
+
+          init() {
+              self.withdrawals = 0
+          }
+
+          pub fun withdraw(_ receiver: ((): Void)) {
+              self.count()
+              receiver()
+          }
+
+          access(self) fun count() {
+              self.withdrawals = self.withdrawals + 1
+          }
+      }
+    `
+
+	const guardedBankContract = `
+      pub contract Bank {
+
+          pub fun withdraw(_ receiver: ((): Void)) {
+              log(self.isReentrant)
+              if self.isReentrant {
+                  panic("re-entrant withdrawal")
+              }
+              receiver()
+          }
+      }
+    `
+
+	const reentrantTransaction = `
+      import Bank from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              Bank.withdraw(fun () {
+                  Bank.withdraw(fun () {})
+              })
+          }
+      }
+    `
+
+	const sequentialTransaction = `
+      import Bank from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              Bank.withdraw(fun () {})
+              Bank.withdraw(fun () {})
+          }
+      }
+    `
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	test := func(
+		t *testing.T,
+		handling interpreter.ReentrancyHandling,
+		contract string,
+		transaction string,
+	) (
+		loggedMessages []string,
+		err error,
+	) {
+		runtime := NewInterpreterRuntime(
+			WithReentrancyHandling(handling),
+		)
+
+		accountCodes := map[common.LocationID][]byte{}
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			resolveLocation: singleIdentifierLocationResolver(t),
+			getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+				location := common.AddressLocation{
+					Address: address,
+					Name:    name,
+				}
+				return accountCodes[location.ID()], nil
+			},
+			updateAccountContractCode: func(address Address, name string, code []byte) error {
+				location := common.AddressLocation{
+					Address: address,
+					Name:    name,
+				}
+				accountCodes[location.ID()] = code
+				return nil
+			},
+			emitEvent: func(_ cadence.Event) error {
+				return nil
+			},
+			log: func(message string) {
+				loggedMessages = append(loggedMessages, message)
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err = runtime.ExecuteTransaction(
+			Script{
+				Source: utils.DeploymentTransaction("Bank", []byte(contract)),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		err = runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(transaction),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+
+		return loggedMessages, err
+	}
+
+	t.Run("none", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := test(t, interpreter.ReentrancyHandlingNone, bankContract, reentrantTransaction)
+		require.NoError(t, err)
+	})
+
+	t.Run("trap, re-entrant", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := test(t, interpreter.ReentrancyHandlingTrap, bankContract, reentrantTransaction)
+		require.Error(t, err)
+
+		var reentrancyErr interpreter.ReentrancyError
+		require.ErrorAs(t, err, &reentrancyErr)
+
+		require.Equal(t,
+			common.AddressLocation{
+				Address: address,
+				Name:    "Bank",
+			},
+			reentrancyErr.Location,
+		)
+	})
+
+	t.Run("trap, sequential", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := test(t, interpreter.ReentrancyHandlingTrap, bankContract, sequentialTransaction)
+		require.NoError(t, err)
+	})
+
+	t.Run("guard, re-entrant", func(t *testing.T) {
+
+		t.Parallel()
+
+		loggedMessages, err := test(t, interpreter.ReentrancyHandlingGuard, guardedBankContract, reentrantTransaction)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "re-entrant withdrawal")
+
+		require.Equal(t, []string{"false", "true"}, loggedMessages)
+	})
+
+	t.Run("guard, sequential", func(t *testing.T) {
+
+		t.Parallel()
+
+		loggedMessages, err := test(t, interpreter.ReentrancyHandlingGuard, guardedBankContract, sequentialTransaction)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"false", "false"}, loggedMessages)
+	})
+}
@@ -21,6 +21,9 @@ package runtime
 import (
 	"fmt"
 	"sort"
+	"strings"
+
+	"github.com/turbolent/prettier"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/cmd"
@@ -28,6 +31,7 @@ import (
 	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/parser2/lexer"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
 )
@@ -171,14 +175,22 @@ func (r *REPL) check(element ast.Element, code string) bool {
 	return r.handleCheckerError()
 }
 
+// Accept parses, checks, and executes the given code.
+//
+// If the code cannot be parsed because it is incomplete,
+// e.g. a block or a parenthesized expression is not closed yet,
+// the code is neither checked nor executed,
+// and the input is reported as not complete.
+// The caller can then append the next line of input and try again.
+//
 func (r *REPL) Accept(code string) (inputIsComplete bool) {
 
-	// TODO: detect if the input is complete
 	inputIsComplete = true
 
 	var err error
 	result, errs := parser2.ParseStatements(code)
 	if len(errs) > 0 {
+		inputIsComplete = isInputComplete(code)
 		err = parser2.Error{
 			Code:   code,
 			Errors: errs,
@@ -226,6 +238,87 @@ func (r *REPL) Accept(code string) (inputIsComplete bool) {
 	return
 }
 
+// isInputComplete returns true if the given code has no unclosed
+// parentheses, braces, brackets, or block comments
+//
+func isInputComplete(code string) bool {
+	tokens := lexer.Lex(code)
+	defer tokens.Close()
+
+	depth := 0
+	commentDepth := 0
+
+	for {
+		token := tokens.Next()
+
+		switch token.Type {
+		case lexer.TokenEOF:
+			return depth <= 0 && commentDepth <= 0
+
+		case lexer.TokenParenOpen,
+			lexer.TokenBraceOpen,
+			lexer.TokenBracketOpen:
+
+			depth++
+
+		case lexer.TokenParenClose,
+			lexer.TokenBraceClose,
+			lexer.TokenBracketClose:
+
+			depth--
+
+		case lexer.TokenBlockCommentStart:
+			commentDepth++
+
+		case lexer.TokenBlockCommentEnd:
+			commentDepth--
+		}
+	}
+}
+
+// ExpressionType checks the given expression and returns its type,
+// without evaluating it.
+//
+// If the expression is invalid, the errors are reported
+// and nil is returned.
+//
+func (r *REPL) ExpressionType(code string) sema.Type {
+
+	expression, errs := parser2.ParseExpression(code)
+	if len(errs) > 0 {
+		r.onError(
+			parser2.Error{
+				Code:   code,
+				Errors: errs,
+			},
+			r.checker.Location,
+			r.codes,
+		)
+		return nil
+	}
+
+	r.checker.ResetErrors()
+	r.checker.ResetHints()
+	r.checker.Program = nil
+
+	ty := r.checker.VisitExpression(expression, nil)
+
+	r.codes[r.checker.Location.ID()] = code
+	if !r.handleCheckerError() {
+		return nil
+	}
+
+	return ty
+}
+
+// GlobalDeclarations returns the declarations of the REPL and their current values,
+// in the order they were declared
+//
+func (r *REPL) GlobalDeclarations() []interpreter.GlobalDeclaration {
+	declarations, _ := r.inter.GlobalDeclarations(r.checker.Location)
+	return declarations
+}
+
 type REPLSuggestion struct {
 	Name, Description string
 }
@@ -250,11 +343,152 @@ func (r *REPL) Suggestions() (result []REPLSuggestion) {
 		})
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		a := result[i]
-		b := result[j]
+	sortREPLSuggestions(result)
+
+	return
+}
+
+// MemberSuggestions returns the members of the type of the given expression,
+// e.g. the fields and functions of a composite.
+//
+// The expression is only checked, not evaluated.
+// If the expression is invalid, no suggestions are returned.
+//
+func (r *REPL) MemberSuggestions(code string) (result []REPLSuggestion) {
+
+	expression, errs := parser2.ParseExpression(code)
+	if len(errs) > 0 {
+		return nil
+	}
+
+	r.checker.ResetErrors()
+	r.checker.ResetHints()
+	r.checker.Program = nil
+
+	ty := r.checker.VisitExpression(expression, nil)
+
+	hasErrors := r.checker.CheckerError() != nil
+	r.checker.ResetErrors()
+	if hasErrors || ty.IsInvalidType() {
+		return nil
+	}
+
+	// Iterating over the members is safe,
+	// as the suggested entries are sorted afterwards
+
+	for name, resolver := range ty.GetMembers() { //nolint:maprangecheck
+		description := resolver.Kind.Name()
+
+		member := resolver.Resolve(name, ast.Range{}, func(error) {})
+		if member != nil {
+			description = member.TypeAnnotation.Type.String()
+		}
+
+		result = append(result, REPLSuggestion{
+			Name:        name,
+			Description: description,
+		})
+	}
+
+	sortREPLSuggestions(result)
+
+	return
+}
+
+func sortREPLSuggestions(suggestions []REPLSuggestion) {
+	sort.Slice(suggestions, func(i, j int) bool {
+		a := suggestions[i]
+		b := suggestions[j]
 		return a.Name < b.Name
 	})
+}
 
-	return
+// FormatREPLValue formats the given value for the REPL.
+//
+// Composites, arrays, and dictionaries which do not fit
+// into the given line width are broken up into multiple lines,
+// with one field, element, or entry per line.
+// The fields of composites are sorted by name.
+//
+func FormatREPLValue(value interpreter.Value, maxLineWidth int) string {
+	var builder strings.Builder
+	prettier.Prettier(&builder, valueDoc(value), maxLineWidth, "    ")
+	return builder.String()
+}
+
+func valueDoc(value interpreter.Value) prettier.Doc {
+	switch value := value.(type) {
+	case *interpreter.SomeValue:
+		return valueDoc(value.Value)
+
+	case *interpreter.ArrayValue:
+		var elements []prettier.Doc
+		value.Walk(func(element interpreter.Value) {
+			elements = append(elements, valueDoc(element))
+		})
+		return wrapValueDocs("[", elements, "]")
+
+	case *interpreter.DictionaryValue:
+		var entries []prettier.Doc
+		value.Iterate(func(key, value interpreter.Value) (resume bool) {
+			entries = append(
+				entries,
+				prettier.Concat{
+					valueDoc(key),
+					prettier.Text(": "),
+					valueDoc(value),
+				},
+			)
+			return true
+		})
+		return wrapValueDocs("{", entries, "}")
+
+	case *interpreter.CompositeValue:
+		if value.Stringer != nil {
+			break
+		}
+
+		// Sort the fields by name,
+		// so the output is deterministic
+
+		var names []string
+		value.ForEachField(func(name string, _ interpreter.Value) {
+			names = append(names, name)
+		})
+		sort.Strings(names)
+
+		fields := make([]prettier.Doc, 0, len(names))
+		for _, name := range names {
+			fields = append(
+				fields,
+				prettier.Concat{
+					prettier.Text(name),
+					prettier.Text(": "),
+					valueDoc(value.GetField(name)),
+				},
+			)
+		}
+		return wrapValueDocs(string(value.TypeID())+"(", fields, ")")
+	}
+
+	return prettier.Text(value.String())
+}
+
+func wrapValueDocs(left string, docs []prettier.Doc, right string) prettier.Doc {
+	if len(docs) == 0 {
+		return prettier.Text(left + right)
+	}
+
+	return prettier.Wrap(
+		prettier.Text(left),
+		prettier.Join(
+			prettier.Concat{
+				prettier.Text(","),
+				prettier.Line{},
+			},
+			docs...,
+		),
+		prettier.Text(right),
+		prettier.SoftLine{},
+	)
 }
@@ -0,0 +1,267 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestREPLGlobalDeclarations(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			require.NoError(t, err)
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	repl.Accept("fun answer(): Int { return 42 }")
+	repl.Accept("let x = answer()")
+
+	declarations := repl.GlobalDeclarations()
+	require.Len(t, declarations, 2)
+
+	assert.Equal(t, "answer", declarations[0].Name)
+	assert.Equal(t, common.DeclarationKindFunction, declarations[0].DeclarationKind)
+
+	assert.Equal(t, "x", declarations[1].Name)
+	assert.Equal(t, common.DeclarationKindConstant, declarations[1].DeclarationKind)
+	assert.Equal(t, sema.IntType, declarations[1].Type)
+	assert.Equal(t,
+		interpreter.NewIntValueFromInt64(42),
+		declarations[1].Value(),
+	)
+}
+
+func TestREPLIncompleteInput(t *testing.T) {
+
+	t.Parallel()
+
+	var results []interpreter.Value
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			require.NoError(t, err)
+		},
+		func(value interpreter.Value) {
+			results = append(results, value)
+		},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	code := "fun answer(): Int {\n"
+	assert.False(t, repl.Accept(code))
+
+	code += "  return 42\n"
+	assert.False(t, repl.Accept(code))
+
+	code += "}\n"
+	assert.True(t, repl.Accept(code))
+
+	code = "answer(\n"
+	assert.False(t, repl.Accept(code))
+
+	code += ")\n"
+	assert.True(t, repl.Accept(code))
+
+	assert.Equal(t,
+		[]interpreter.Value{
+			interpreter.NewIntValueFromInt64(42),
+		},
+		results,
+	)
+}
+
+func TestREPLInvalidInput(t *testing.T) {
+
+	t.Parallel()
+
+	var reportedErr error
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			reportedErr = err
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	assert.True(t, repl.Accept("let x = )\n"))
+	require.Error(t, reportedErr)
+}
+
+func TestREPLExpressionType(t *testing.T) {
+
+	t.Parallel()
+
+	var reportedErr error
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			reportedErr = err
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	repl.Accept("let xs = [1, 2, 3]")
+	require.NoError(t, reportedErr)
+
+	assert.Equal(t,
+		&sema.VariableSizedType{
+			Type: sema.IntType,
+		},
+		repl.ExpressionType("xs"),
+	)
+
+	assert.Equal(t,
+		sema.IntType,
+		repl.ExpressionType("xs.length"),
+	)
+
+	assert.Nil(t, repl.ExpressionType("ys"))
+	require.Error(t, reportedErr)
+}
+
+func TestREPLMemberSuggestions(t *testing.T) {
+
+	t.Parallel()
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			require.NoError(t, err)
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	repl.Accept(`
+      struct Point {
+          let x: Int
+          let y: Int
+
+          init(x: Int, y: Int) {
+              self.x = x
+              self.y = y
+          }
+
+          fun sum(): Int {
+              return self.x + self.y
+          }
+      }
+    `)
+	repl.Accept("let p = Point(x: 1, y: 2)")
+
+	var names []string
+	for _, suggestion := range repl.MemberSuggestions("p") {
+		names = append(names, suggestion.Name)
+	}
+
+	assert.Subset(t, names, []string{"x", "y", "sum"})
+	assert.Contains(t,
+		repl.MemberSuggestions("p"),
+		REPLSuggestion{
+			Name:        "x",
+			Description: "Int",
+		},
+	)
+
+	assert.Empty(t, repl.MemberSuggestions("q"))
+}
+
+func TestFormatREPLValue(t *testing.T) {
+
+	t.Parallel()
+
+	var results []interpreter.Value
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			require.NoError(t, err)
+		},
+		func(value interpreter.Value) {
+			results = append(results, value)
+		},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	repl.Accept(`
+      struct Point {
+          let x: Int
+          let y: Int
+
+          init(x: Int, y: Int) {
+              self.x = x
+              self.y = y
+          }
+      }
+    `)
+	repl.Accept("Point(x: 1, y: 2)")
+	repl.Accept("[1, 2, 3]")
+	repl.Accept("[Point(x: 1000000, y: 2000000), Point(x: 3000000, y: 4000000)]")
+	repl.Accept("let empty: {String: Int} = {}")
+	repl.Accept("empty")
+
+	require.Len(t, results, 4)
+
+	assert.Equal(t,
+		"REPL.Point(x: 1, y: 2)",
+		FormatREPLValue(results[0], 80),
+	)
+
+	assert.Equal(t,
+		"[1, 2, 3]",
+		FormatREPLValue(results[1], 80),
+	)
+
+	assert.Equal(t,
+		`[
+    REPL.Point(x: 1000000, y: 2000000),
+    REPL.Point(x: 3000000, y: 4000000)
+]`,
+		FormatREPLValue(results[2], 40),
+	)
+
+	assert.Equal(t,
+		"{}",
+		FormatREPLValue(results[3], 80),
+	)
+}
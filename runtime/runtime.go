@@ -25,11 +25,16 @@ import (
 	goRuntime "runtime"
 	"time"
 
+	"github.com/fxamacker/cbor/v2"
+	"github.com/onflow/atree"
 	opentracing "github.com/opentracing/opentracing-go"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/bbq"
+	"github.com/onflow/cadence/runtime/bbq/compiler"
+	"github.com/onflow/cadence/runtime/bbq/vm"
 	"github.com/onflow/cadence/runtime/common"
 	runtimeErrors "github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
@@ -59,6 +64,22 @@ type Runtime interface {
 	// or if the execution fails.
 	ExecuteTransaction(Script, Context) error
 
+	// ExecuteScriptWithResult executes the given script,
+	// and returns the result of the script together with the effects of the execution,
+	// e.g. the emitted events and logs, see ExecutionResult.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the execution fails. The result is also returned if the execution fails,
+	// but it has no value.
+	ExecuteScriptWithResult(Script, Context) (ExecutionResult, error)
+
+	// ExecuteTransactionWithResult executes the given transaction,
+	// and returns the effects of the execution, e.g. the emitted events and logs, see ExecutionResult.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the execution fails. The result is also returned if the execution fails.
+	ExecuteTransactionWithResult(Script, Context) (ExecutionResult, error)
+
 	// InvokeContractFunction invokes a contract function with the given arguments.
 	//
 	// This function returns an error if the execution fails.
@@ -89,12 +110,46 @@ type Runtime interface {
 	// SetAtreeValidationEnabled configures if atree validation is enabled.
 	SetAtreeValidationEnabled(enabled bool)
 
+	// SetOwnerValidationEnabled configures if owner validation is enabled.
+	SetOwnerValidationEnabled(enabled bool)
+
 	// SetTracingEnabled configures if tracing is enabled.
 	SetTracingEnabled(enabled bool)
 
 	// SetResourceOwnerChangeCallbackEnabled configures if the resource owner change callback is enabled.
 	SetResourceOwnerChangeHandlerEnabled(enabled bool)
 
+	// SetStorageCapacityCheckEnabled configures if the storage capacity check is enabled.
+	SetStorageCapacityCheckEnabled(enabled bool)
+
+	// SetLinkValidationEnabled configures if link validation is enabled.
+	SetLinkValidationEnabled(enabled bool)
+
+	// SetExternalMutationCheckEnabled configures if the external mutation check is enabled.
+	SetExternalMutationCheckEnabled(enabled bool)
+
+	// SetReferenceInvalidationEnabled configures if references are invalidated when the referenced resource is moved.
+	SetReferenceInvalidationEnabled(enabled bool)
+
+	// SetCopyOnWriteEnabled configures if arrays and dictionaries are copied lazily.
+	SetCopyOnWriteEnabled(enabled bool)
+
+	// SetTypeFingerprintsEnabled configures if the type fingerprints of stored values are stored.
+	SetTypeFingerprintsEnabled(enabled bool)
+
+	// SetValueArenaEnabled configures if transient values are allocated in a value arena.
+	SetValueArenaEnabled(enabled bool)
+
+	// SetDecodingLimits configures the limits enforced when decoding stored values.
+	// It panics if the limits are outside the range supported by the CBOR decoder.
+	SetDecodingLimits(limits common.DecodingLimits)
+
+	// SetReentrancyHandling configures how re-entrant calls into contracts are handled.
+	SetReentrancyHandling(handling interpreter.ReentrancyHandling)
+
+	// SetEnabledFeatures configures which experimental language features are enabled.
+	SetEnabledFeatures(features common.ExperimentalFeatures)
+
 	// ReadStored reads the value stored at the given path
 	//
 	ReadStored(address common.Address, path cadence.Path, context Context) (cadence.Value, error)
@@ -102,6 +157,36 @@ type Runtime interface {
 	// ReadLinked dereferences the path and returns the value stored at the target
 	//
 	ReadLinked(address common.Address, path cadence.Path, context Context) (cadence.Value, error)
+
+	// AccountStorageStatistics returns the statistics of all values stored in the account,
+	// without decoding the values
+	//
+	AccountStorageStatistics(address common.Address, context Context) ([]StoredValueStatistics, error)
+
+	// CheckAccountStorageDecoding fully decodes all values stored in the account.
+	//
+	// If a value fails to decode, the returned error is an interpreter.ValueDecodingError,
+	// which locates the value, i.e. the storage path and the path within the stored value
+	//
+	CheckAccountStorageDecoding(address common.Address, context Context) error
+
+	// EstimateComputation executes the given transaction with metering enabled,
+	// without committing its effects, and returns the estimated cost of the transaction.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the execution fails. The estimate is also returned if the execution fails,
+	// but it might be incomplete.
+	//
+	EstimateComputation(Script, Context) (ComputationEstimate, error)
+
+	// SimulateContractDeployment parses and checks the given contract code,
+	// validates the given arguments against the parameters of the contract initializer,
+	// and instantiates the contract, without committing its effects.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// if the arguments are invalid, or if the initializer fails.
+	//
+	SimulateContractDeployment(ContractDeployment, Context) (ContractDeploymentSimulation, error)
 }
 
 var typeDeclarations = append(
@@ -112,6 +197,7 @@ var typeDeclarations = append(
 type ImportResolver = func(location common.Location) (program *ast.Program, e error)
 
 var validTopLevelDeclarationsInTransaction = []common.DeclarationKind{
+	common.DeclarationKindPragma,
 	common.DeclarationKindImport,
 	common.DeclarationKindFunction,
 	common.DeclarationKindTransaction,
@@ -158,8 +244,20 @@ type interpreterRuntime struct {
 	coverageReport                    *CoverageReport
 	contractUpdateValidationEnabled   bool
 	atreeValidationEnabled            bool
+	ownerValidationEnabled            bool
 	tracingEnabled                    bool
 	resourceOwnerChangeHandlerEnabled bool
+	storageCapacityCheckEnabled       bool
+	linkValidationEnabled             bool
+	externalMutationCheckEnabled      bool
+	referenceInvalidationEnabled      bool
+	copyOnWriteEnabled                bool
+	typeFingerprintsEnabled           bool
+	valueArenaEnabled                 bool
+	decodingLimits                    *common.DecodingLimits
+	decodingDecMode                   cbor.DecMode
+	reentrancyHandling                interpreter.ReentrancyHandling
+	enabledFeatures                   common.ExperimentalFeatures
 }
 
 type Option func(Runtime)
@@ -182,6 +280,19 @@ func WithAtreeValidationEnabled(enabled bool) Option {
 	}
 }
 
+// WithOwnerValidationEnabled returns a runtime option
+// that configures if owner validation is enabled.
+//
+// This is a debug mode: Every write to storage validates
+// that the written value and all values nested in it
+// are owned by the account they are written to.
+//
+func WithOwnerValidationEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetOwnerValidationEnabled(enabled)
+	}
+}
+
 // WithTracingEnabled returns a runtime option
 // that configures if tracing is enabled.
 //
@@ -200,6 +311,139 @@ func WithResourceOwnerChangeCallbackEnabled(enabled bool) Option {
 	}
 }
 
+// WithStorageCapacityCheckEnabled returns a runtime option
+// that configures if the storage capacity check is enabled.
+//
+// If enabled, committing storage fails with a StorageCapacityExceededError
+// if the storage used by an account which was written to exceeds its storage capacity.
+//
+func WithStorageCapacityCheckEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetStorageCapacityCheckEnabled(enabled)
+	}
+}
+
+// WithLinkValidationEnabled returns a runtime option
+// that configures if link validation is enabled.
+//
+// If enabled, creating a link fails with an interpreter.InvalidLinkTargetError
+// if the value stored at the target path cannot be borrowed with the borrow type of the link,
+// so broken links are detected when they are created, instead of when they are first borrowed.
+//
+func WithLinkValidationEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetLinkValidationEnabled(enabled)
+	}
+}
+
+// WithExternalMutationCheckEnabled returns a runtime option
+// that configures if the external mutation check is enabled.
+//
+// If enabled, programs which mutate array or dictionary fields outside of their composite,
+// e.g. `s.xs.append(1)` where the field `xs` is not publicly settable, are rejected.
+// If disabled, such mutations are only reported as hints, so existing programs can be migrated.
+//
+func WithExternalMutationCheckEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetExternalMutationCheckEnabled(enabled)
+	}
+}
+
+// WithReferenceInvalidationEnabled returns a runtime option
+// that configures if references are invalidated when the referenced resource is moved.
+//
+// If enabled, programs which use a reference after the referenced resource was moved or destroyed are rejected,
+// and using a reference to a moved resource aborts at run-time.
+// If disabled, such uses are only reported as hints, so existing programs can be migrated.
+//
+func WithReferenceInvalidationEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetReferenceInvalidationEnabled(enabled)
+	}
+}
+
+// WithCopyOnWriteEnabled returns a runtime option
+// that configures if arrays and dictionaries are copied lazily
+// when they are transferred to a local destination, e.g. a variable or an argument,
+// see interpreter.WithCopyOnWriteEnabled.
+//
+// Lazy copies change the storage IDs of temporary containers,
+// which determine the seeds of dictionaries, and so the encoding and iteration order of stored dictionaries.
+// Enabling the option therefore changes the results of existing transactions
+//
+func WithCopyOnWriteEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetCopyOnWriteEnabled(enabled)
+	}
+}
+
+// WithTypeFingerprintsEnabled returns a runtime option
+// that configures if the type fingerprints of stored values are stored.
+//
+// If enabled, the static type of each value saved to a path is stored alongside the value,
+// so that questions about the type of the value can be answered without decoding it,
+// e.g. `Capability.check`, see interpreter.WithTypeFingerprintsEnabled.
+// Links have no static type, so they have no fingerprint.
+// Values stored before type fingerprints were enabled have no fingerprint.
+//
+// If disabled, the fingerprints of overwritten and removed values are still removed,
+// so no fingerprint is stale when type fingerprints are enabled again.
+//
+func WithTypeFingerprintsEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetTypeFingerprintsEnabled(enabled)
+	}
+}
+
+// WithValueArenaEnabled returns a runtime option
+// that configures if transient values are allocated in a value arena.
+//
+// If enabled, the intermediate results of arithmetic expressions are allocated in an arena,
+// which is reused during the execution, see interpreter.WithValueArenaEnabled.
+//
+func WithValueArenaEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetValueArenaEnabled(enabled)
+	}
+}
+
+// WithDecodingLimits returns a runtime option
+// that configures the limits enforced when decoding stored values,
+// e.g. the maximum nesting depth and the maximum number of values.
+//
+// By default, the limits common.DefaultDecodingLimits are enforced.
+//
+func WithDecodingLimits(limits common.DecodingLimits) Option {
+	return func(runtime Runtime) {
+		runtime.SetDecodingLimits(limits)
+	}
+}
+
+// WithReentrancyHandling returns a runtime option
+// that configures how re-entrant calls into contracts are handled.
+//
+// By default, re-entrant calls are not tracked.
+// Re-entrant calls can either be trapped, or contracts can guard against them
+// using the contract field `isReentrant`, see interpreter.ReentrancyHandling.
+//
+func WithReentrancyHandling(handling interpreter.ReentrancyHandling) Option {
+	return func(runtime Runtime) {
+		runtime.SetReentrancyHandling(handling)
+	}
+}
+
+// WithEnabledFeatures returns a runtime option
+// that enables the given experimental language features in the parser and checker.
+//
+// Experimental features are still in progress, see common.ExperimentalFeature.
+// They should not be enabled for executions which must be deterministic, e.g. on mainnet.
+//
+func WithEnabledFeatures(features common.ExperimentalFeatures) Option {
+	return func(runtime Runtime) {
+		runtime.SetEnabledFeatures(features)
+	}
+}
+
 // NewInterpreterRuntime returns a interpreter-based version of the Flow runtime.
 func NewInterpreterRuntime(options ...Option) Runtime {
 	runtime := &interpreterRuntime{}
@@ -221,6 +465,10 @@ func (r *interpreterRuntime) SetAtreeValidationEnabled(enabled bool) {
 	r.atreeValidationEnabled = enabled
 }
 
+func (r *interpreterRuntime) SetOwnerValidationEnabled(enabled bool) {
+	r.ownerValidationEnabled = enabled
+}
+
 func (r *interpreterRuntime) SetTracingEnabled(enabled bool) {
 	r.tracingEnabled = enabled
 }
@@ -229,10 +477,93 @@ func (r *interpreterRuntime) SetResourceOwnerChangeHandlerEnabled(enabled bool)
 	r.resourceOwnerChangeHandlerEnabled = enabled
 }
 
+func (r *interpreterRuntime) SetStorageCapacityCheckEnabled(enabled bool) {
+	r.storageCapacityCheckEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetLinkValidationEnabled(enabled bool) {
+	r.linkValidationEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetExternalMutationCheckEnabled(enabled bool) {
+	r.externalMutationCheckEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetReferenceInvalidationEnabled(enabled bool) {
+	r.referenceInvalidationEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetCopyOnWriteEnabled(enabled bool) {
+	r.copyOnWriteEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetTypeFingerprintsEnabled(enabled bool) {
+	r.typeFingerprintsEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetValueArenaEnabled(enabled bool) {
+	r.valueArenaEnabled = enabled
+}
+
+func (r *interpreterRuntime) SetDecodingLimits(limits common.DecodingLimits) {
+	decMode, err := interpreter.NewCBORDecMode(limits)
+	if err != nil {
+		panic(err)
+	}
+	r.decodingLimits = &limits
+	r.decodingDecMode = decMode
+}
+
+func (r *interpreterRuntime) SetReentrancyHandling(handling interpreter.ReentrancyHandling) {
+	r.reentrancyHandling = handling
+}
+
+func (r *interpreterRuntime) SetEnabledFeatures(features common.ExperimentalFeatures) {
+	r.enabledFeatures = features
+}
+
+// parserConfig returns the configuration of the parser,
+// which enables the configured experimental features, if any
+//
+func (r *interpreterRuntime) parserConfig() parser2.Config {
+	config := parser2.DefaultConfig
+	config.EnabledFeatures = r.enabledFeatures
+	return config
+}
+
+// newStorage returns a new storage for the given context,
+// which enforces the configured decoding limits, if any
+//
+func (r *interpreterRuntime) newStorage(context Context) *Storage {
+	var ledger atree.Ledger = context.Interface
+	if context.ExecutionReport != nil {
+		ledger = reportingLedger{
+			Ledger: ledger,
+			report: context.ExecutionReport,
+		}
+	}
+
+	var storage *Storage
+	if r.decodingLimits == nil {
+		storage = NewStorage(ledger)
+	} else {
+		storage = newStorage(
+			ledger,
+			r.decodingDecMode,
+			interpreter.NewStorableDecoder(*r.decodingLimits),
+		)
+	}
+
+	storage.mutationJournal = context.MutationJournal
+
+	return storage
+}
+
 func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cadence.Value, error) {
 	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
 
-	storage := NewStorage(context.Interface)
+	storage := r.newStorage(context)
 
 	var checkerOptions []sema.Option
 	var interpreterOptions []interpreter.Option
@@ -282,10 +613,17 @@ func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cade
 		return nil, newError(err, context)
 	}
 
+	var compiledProgram *bbq.Program
+	if context.UseVM {
+		// Scripts which cannot be compiled yet are executed by the interpreter
+		compiledProgram, _ = compiler.Compile(program.Program, program.Elaboration)
+	}
+
 	interpret := scriptExecutionFunction(
 		functionEntryPointType.Parameters,
 		script.Arguments,
 		context.Interface,
+		compiledProgram,
 	)
 
 	value, inter, err := r.interpret(
@@ -304,7 +642,7 @@ func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cade
 
 	// Export before committing storage
 
-	result, err := exportValue(value)
+	result, err := exportValue(value, context.ExportOptions)
 	if err != nil {
 		return nil, newError(err, context)
 	}
@@ -314,15 +652,21 @@ func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cade
 	// Even though this function is `ExecuteScript`, that doesn't imply the changes
 	// to storage will be actually persisted
 
-	err = r.commitStorage(storage, inter)
+	err = r.commitStorage(storage, inter, context.Interface)
 	if err != nil {
 		return nil, newError(err, context)
 	}
 
+	context.registerEventTypes()
+
 	return result, nil
 }
 
-func (r *interpreterRuntime) commitStorage(storage *Storage, inter *interpreter.Interpreter) error {
+func (r *interpreterRuntime) commitStorage(
+	storage *Storage,
+	inter *interpreter.Interpreter,
+	runtimeInterface Interface,
+) error {
 	const commitContractUpdates = true
 	err := storage.Commit(inter, commitContractUpdates)
 	if err != nil {
@@ -336,6 +680,51 @@ func (r *interpreterRuntime) commitStorage(storage *Storage, inter *interpreter.
 		}
 	}
 
+	if r.storageCapacityCheckEnabled {
+		err = checkStorageCapacity(storage, runtimeInterface)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkStorageCapacity checks that the storage used by each account which was written to
+// does not exceed the account's storage capacity.
+//
+// The accounts are checked in lexicographic order of their addresses,
+// so the reported account is deterministic
+//
+func checkStorageCapacity(storage *Storage, runtimeInterface Interface) error {
+	for _, address := range storage.WrittenAddresses() {
+
+		var storageUsed, storageCapacity uint64
+		var err error
+
+		wrapPanic(func() {
+			storageUsed, err = runtimeInterface.GetStorageUsed(address)
+		})
+		if err != nil {
+			return err
+		}
+
+		wrapPanic(func() {
+			storageCapacity, err = runtimeInterface.GetStorageCapacity(address)
+		})
+		if err != nil {
+			return err
+		}
+
+		if storageUsed > storageCapacity {
+			return StorageCapacityExceededError{
+				Address:         address,
+				StorageUsed:     storageUsed,
+				StorageCapacity: storageCapacity,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -345,6 +734,7 @@ func scriptExecutionFunction(
 	parameters []*sema.Parameter,
 	arguments [][]byte,
 	runtimeInterface Interface,
+	compiledProgram *bbq.Program,
 ) interpretFunc {
 	return func(inter *interpreter.Interpreter) (value interpreter.Value, err error) {
 
@@ -364,6 +754,11 @@ func scriptExecutionFunction(
 		if err != nil {
 			return nil, err
 		}
+
+		if compiledProgram != nil {
+			return vm.NewVM(compiledProgram, inter).Invoke("main", values...)
+		}
+
 		return inter.Invoke("main", values...)
 	}
 }
@@ -468,8 +863,9 @@ func (r *interpreterRuntime) InvokeContractFunction(
 	context Context,
 ) (cadence.Value, error) {
 	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
 
-	storage := NewStorage(context.Interface)
+	storage := r.newStorage(context)
 
 	var interpreterOptions []interpreter.Option
 	var checkerOptions []sema.Option
@@ -546,13 +942,15 @@ func (r *interpreterRuntime) InvokeContractFunction(
 	}
 
 	// Write back all stored values, which were actually just cached, back into storage
-	err = r.commitStorage(storage, inter)
+	err = r.commitStorage(storage, inter, context.Interface)
 	if err != nil {
 		return nil, newError(err, context)
 	}
 
+	context.registerEventTypes()
+
 	var exportedValue cadence.Value
-	exportedValue, err = ExportValue(value, inter)
+	exportedValue, err = ExportValueWithOptions(value, inter, context.ExportOptions)
 	if err != nil {
 		return nil, newError(err, context)
 	}
@@ -595,8 +993,9 @@ func (r *interpreterRuntime) convertArgument(
 
 func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context) error {
 	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
 
-	storage := NewStorage(context.Interface)
+	storage := r.newStorage(context)
 
 	var interpreterOptions []interpreter.Option
 	var checkerOptions []sema.Option
@@ -653,7 +1052,7 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 		return newError(err, context)
 	}
 
-	transactionAuthorizerCount := len(transactionType.PrepareParameters)
+	transactionAuthorizerCount := transactionType.AuthorizerCount()
 	if authorizerCount != transactionAuthorizerCount {
 		err = InvalidTransactionAuthorizerCountError{
 			Expected: transactionAuthorizerCount,
@@ -662,14 +1061,21 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 		return newError(err, context)
 	}
 
-	// gather authorizers
+	// gather authorizers.
+	// Authorizers for prepare parameters of a constant-sized array type
+	// are grouped into an array
 
 	authorizerValues := func(inter *interpreter.Interpreter) []interpreter.Value {
 
-		authorizerValues := make([]interpreter.Value, authorizerCount)
+		authorizerValues := make([]interpreter.Value, 0, len(transactionType.PrepareParameters))
+
+		nextAuthorizer := 0
 
-		for i, address := range authorizers {
-			authorizerValues[i] = r.newAuthAccountValue(
+		newAuthAccountValue := func() interpreter.Value {
+			address := authorizers[nextAuthorizer]
+			nextAuthorizer++
+
+			return r.newAuthAccountValue(
 				interpreter.NewAddressValue(address),
 				context,
 				storage,
@@ -678,6 +1084,30 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 			)
 		}
 
+		for _, parameter := range transactionType.PrepareParameters {
+
+			arrayType, ok := parameter.TypeAnnotation.Type.(*sema.ConstantSizedType)
+			if !ok {
+				authorizerValues = append(authorizerValues, newAuthAccountValue())
+				continue
+			}
+
+			accountValues := make([]interpreter.Value, arrayType.Size)
+			for i := range accountValues {
+				accountValues[i] = newAuthAccountValue()
+			}
+
+			authorizerValues = append(
+				authorizerValues,
+				interpreter.NewArrayValue(
+					inter,
+					interpreter.ConvertSemaArrayTypeToStaticArrayType(arrayType),
+					common.Address{},
+					accountValues...,
+				),
+			)
+		}
+
 		return authorizerValues
 	}
 
@@ -700,12 +1130,17 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 		return newError(err, context)
 	}
 
+	// The transient storage is scoped to the transaction
+	inter.DiscardTransientStorage()
+
 	// Write back all stored values, which were actually just cached, back into storage
-	err = r.commitStorage(storage, inter)
+	err = r.commitStorage(storage, inter, context.Interface)
 	if err != nil {
 		return newError(err, context)
 	}
 
+	context.registerEventTypes()
+
 	return nil
 }
 
@@ -798,16 +1233,21 @@ func validateArgumentParams(
 
 		if err != nil {
 			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err:   err,
+				Index:         i,
+				ParameterType: parameterType,
+				Err:           err,
 			}
 		}
 
+		argumentType := decodedArgumentType(value)
+
 		arg, err := importValue(inter, value, parameterType)
 		if err != nil {
 			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err:   err,
+				Index:         i,
+				ParameterType: parameterType,
+				ArgumentType:  argumentType,
+				Err:           err,
 			}
 		}
 
@@ -816,14 +1256,17 @@ func validateArgumentParams(
 		// Ensure the argument is of an importable type
 		if !dynamicType.IsImportable() {
 			return nil, &ArgumentNotImportableError{
-				Type: dynamicType,
+				Index: i,
+				Type:  dynamicType,
 			}
 		}
 
 		// Check that decoded value is a subtype of static parameter type
 		if !inter.IsSubType(dynamicType, parameterType) {
 			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
+				Index:         i,
+				ParameterType: parameterType,
+				ArgumentType:  argumentType,
 				Err: &InvalidValueTypeError{
 					ExpectedType: parameterType,
 				},
@@ -839,7 +1282,9 @@ func validateArgumentParams(
 			conformanceResults,
 		) {
 			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
+				Index:         i,
+				ParameterType: parameterType,
+				ArgumentType:  argumentType,
 				Err: &MalformedValueError{
 					ExpectedType: parameterType,
 				},
@@ -865,6 +1310,30 @@ func validateArgumentParams(
 	return argumentValues, nil
 }
 
+// decodedArgumentType returns the type of the given decoded argument,
+// or nil if the type is unknown or incomplete,
+// e.g. if the argument is an array value without an array type
+//
+func decodedArgumentType(value cadence.Value) (ty cadence.Type) {
+	if value == nil {
+		return nil
+	}
+
+	defer func() {
+		if recover() != nil {
+			ty = nil
+		}
+	}()
+
+	ty = value.Type()
+	if ty != nil {
+		// Incomplete types fail to produce an ID
+		_ = ty.ID()
+	}
+
+	return ty
+}
+
 func hasValidStaticType(value interpreter.Value) bool {
 	switch value := value.(type) {
 	case *interpreter.ArrayValue:
@@ -884,8 +1353,9 @@ func hasValidStaticType(value interpreter.Value) bool {
 //
 func (r *interpreterRuntime) ParseAndCheckProgram(code []byte, context Context) (*interpreter.Program, error) {
 	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
 
-	storage := NewStorage(context.Interface)
+	storage := r.newStorage(context)
 
 	var interpreterOptions []interpreter.Option
 	var checkerOptions []sema.Option
@@ -941,7 +1411,7 @@ func (r *interpreterRuntime) parseAndCheckProgram(
 	var parse *ast.Program
 	reportMetric(
 		func() {
-			parse, err = parser2.ParseProgram(string(code))
+			parse, err = parser2.ParseProgramWithConfig(string(code), r.parserConfig())
 		},
 		context.Interface,
 		func(metrics Metrics, duration time.Duration) {
@@ -1001,14 +1471,64 @@ func (r *interpreterRuntime) check(
 		valueDeclarations = append(valueDeclarations, predeclaredValue)
 	}
 
+	for _, hostFunction := range startContext.HostFunctions {
+		valueDeclarations = append(valueDeclarations, hostFunction.valueDeclaration(nil, 0))
+	}
+
+	// If an import cache is used, record the dependencies of the program,
+	// i.e. the code hashes of all imported locations, direct and transitive,
+	// so the import cache can detect if an import changed, see ImportCache.
+	// The dependencies are unknown if the program of an import is provided by the host environment
+
+	trackDependencies := startContext.ImportCache != nil
+	dependencies := importDependencies{}
+
+	addDependencies := func(context Context, importedLocation common.Location) error {
+		importedDependencies, ok := startContext.importDependencies[importedLocation.ID()]
+		if !ok {
+			trackDependencies = false
+			return nil
+		}
+
+		codeHash, err := r.codeHash(context, importedLocation)
+		if err != nil {
+			return err
+		}
+
+		dependencies[importedLocation.ID()] = importDependency{
+			location: importedLocation,
+			codeHash: codeHash,
+		}
+
+		for locationID, dependency := range importedDependencies { //nolint:maprangecheck
+			dependencies[locationID] = dependency
+		}
+
+		return nil
+	}
+
+	predeclaredTypes := typeDeclarations
+	if len(startContext.PredeclaredTypes) > 0 {
+		predeclaredTypes = make([]sema.TypeDeclaration, 0, len(typeDeclarations)+len(startContext.PredeclaredTypes))
+		predeclaredTypes = append(predeclaredTypes, typeDeclarations...)
+		for _, predeclaredType := range startContext.PredeclaredTypes {
+			predeclaredTypes = append(predeclaredTypes, predeclaredType)
+		}
+	}
+
 	checker, err := sema.NewChecker(
 		program,
 		startContext.Location,
 		append(
 			[]sema.Option{
 				sema.WithPredeclaredValues(valueDeclarations),
-				sema.WithPredeclaredTypes(typeDeclarations),
+				sema.WithPredeclaredTypes(predeclaredTypes),
 				sema.WithValidTopLevelDeclarationsHandler(validTopLevelDeclarations),
+				sema.WithFeatureGates(accountLinkingFeatureGate),
+				sema.WithExternalMutationReportOnly(!r.externalMutationCheckEnabled),
+				sema.WithReferenceInvalidationReportOnly(!r.referenceInvalidationEnabled),
+				sema.WithReentrancyGuardEnabled(r.reentrancyHandling == interpreter.ReentrancyHandlingGuard),
+				sema.WithEnabledFeatures(r.enabledFeatures),
 				sema.WithLocationHandler(
 					func(identifiers []Identifier, location Location) (res []ResolvedLocation, err error) {
 						wrapPanic(func() {
@@ -1044,6 +1564,13 @@ func (r *interpreterRuntime) check(
 								return nil, err
 							}
 
+							if trackDependencies {
+								err = addDependencies(context, importedLocation)
+								if err != nil {
+									return nil, err
+								}
+							}
+
 							elaboration = program.Elaboration
 						}
 
@@ -1072,13 +1599,31 @@ func (r *interpreterRuntime) check(
 	elaboration = checker.Elaboration
 
 	err = checker.Check()
+
+	if startContext.resultRecorder != nil {
+		startContext.resultRecorder.recordHints(checker)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	if trackDependencies {
+		startContext.importDependencies[startContext.Location.ID()] = dependencies
+	}
+
 	return elaboration, nil
 }
 
+// accountLinkingFeatureGate gates the use of AuthAccount.linkAccount
+// behind the account linking pragma
+//
+var accountLinkingFeatureGate = sema.FeatureGate{
+	Pragma:        sema.AccountLinkingPragma,
+	ContainerType: sema.AuthAccountType,
+	Member:        sema.AuthAccountLinkAccountField,
+}
+
 func (r *interpreterRuntime) newInterpreter(
 	program *interpreter.Program,
 	context Context,
@@ -1096,6 +1641,24 @@ func (r *interpreterRuntime) newInterpreter(
 		preDeclaredValues = append(preDeclaredValues, predeclaredValue)
 	}
 
+	computationCostTable := context.computationCostTable()
+
+	meteringOptions, meterComputation, computationUsed := r.meteringInterpreterOptions(
+		context.Interface,
+		computationCostTable,
+		context.ExecutionReport,
+	)
+
+	for _, hostFunction := range context.HostFunctions {
+		preDeclaredValues = append(
+			preDeclaredValues,
+			hostFunction.valueDeclaration(
+				meterComputation,
+				computationCostTable.HostFunctionCost(hostFunction),
+			),
+		)
+	}
+
 	publicKeyValidator := func(
 		inter *interpreter.Interpreter,
 		getLocationRange func() interpreter.LocationRange,
@@ -1169,6 +1732,42 @@ func (r *interpreterRuntime) newInterpreter(
 				)
 			},
 		),
+		interpreter.WithAuthAccountHandler(
+			func(_ *interpreter.Interpreter, address interpreter.AddressValue) interpreter.Value {
+				return r.newAuthAccountValue(
+					address,
+					context,
+					storage,
+					interpreterOptions,
+					checkerOptions,
+				)
+			},
+		),
+		interpreter.WithAccountLinkingAllowedHandler(
+			func(_ *interpreter.Interpreter, address interpreter.AddressValue) bool {
+				var allowed bool
+				var err error
+				wrapPanic(func() {
+					allowed, err = context.Interface.IsAccountLinkingAllowed(address.ToAddress())
+				})
+				if err != nil {
+					panic(err)
+				}
+				return allowed
+			},
+		),
+		interpreter.WithOnAccountLinkedHandler(
+			func(inter *interpreter.Interpreter, address interpreter.AddressValue, path interpreter.PathValue) {
+				r.emitAccountEvent(
+					stdlib.AccountLinkedEventType,
+					context.Interface,
+					[]exportableValue{
+						newExportableValue(address, inter),
+						newExportableValue(path, inter),
+					},
+				)
+			},
+		),
 		interpreter.WithPublicKeyValidationHandler(publicKeyValidator),
 		interpreter.WithBLSCryptoFunctions(
 			func(
@@ -1255,12 +1854,61 @@ func (r *interpreterRuntime) newInterpreter(
 		// and disable storage validation after each value modification.
 		// Instead, storage is validated after commits (if validation is enabled).
 		interpreter.WithAtreeStorageValidationEnabled(false),
+		interpreter.WithOwnerValidationEnabled(r.ownerValidationEnabled),
+		interpreter.WithLinkValidationEnabled(r.linkValidationEnabled),
+		interpreter.WithReferenceInvalidationEnabled(r.referenceInvalidationEnabled),
+		interpreter.WithCopyOnWriteEnabled(r.copyOnWriteEnabled),
+		interpreter.WithTypeFingerprintsEnabled(r.typeFingerprintsEnabled),
+		interpreter.WithValueArenaEnabled(r.valueArenaEnabled),
+		interpreter.WithReentrancyHandling(r.reentrancyHandling),
 		interpreter.WithOnResourceOwnerChangeHandler(r.resourceOwnerChangedHandler(context.Interface)),
 	}
 
-	defaultOptions = append(defaultOptions,
-		r.meteringInterpreterOptions(context.Interface)...,
-	)
+	defaultOptions = append(defaultOptions, meteringOptions...)
+
+	if context.callStack != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithCallStack(context.callStack),
+		)
+	}
+
+	if context.YieldHandler != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithOnYieldHandler(r.yieldHandler(context.YieldHandler)),
+		)
+	}
+
+	if context.MemoizationCache != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithMemoizedFunctionHandler(
+				r.memoizedFunctionHandler(context, meterComputation, computationUsed),
+			),
+		)
+	}
+
+	if context.TypeLoader != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithTypeLoader(context.TypeLoader),
+		)
+	}
+
+	if context.MutationJournal != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithMutationJournal(context.MutationJournal),
+		)
+	}
+
+	if context.SharedState != nil {
+		defaultOptions = append(
+			defaultOptions,
+			interpreter.WithSharedStateHandler(sharedStateHandler(context.SharedState)),
+		)
+	}
 
 	return interpreter.NewInterpreter(
 		program,
@@ -1339,17 +1987,58 @@ func (r *interpreterRuntime) getProgram(
 			return nil, err
 		}
 
-		program, err = r.parseAndCheckProgram(
-			code,
-			context,
-			functions,
-			values,
-			checkerOptions,
-			true,
-			checkedImports,
-		)
-		if err != nil {
-			return nil, err
+		importCache := context.ImportCache
+		if importCache != nil {
+			var dependencies importDependencies
+			program, dependencies, err = importCache.get(
+				context.Location,
+				code,
+				func(location common.Location) ([32]byte, error) {
+					return r.codeHash(context, location)
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			if program != nil {
+				context.importDependencies[context.Location.ID()] = dependencies
+			}
+		}
+
+		if program != nil {
+			// The program was already parsed and checked in a previous execution,
+			// and neither its code nor the code of any of its imports changed since
+			context.SetCode(context.Location, string(code))
+
+			wrapPanic(func() {
+				err = context.Interface.SetProgram(context.Location, program)
+			})
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			program, err = r.parseAndCheckProgram(
+				code,
+				context,
+				functions,
+				values,
+				checkerOptions,
+				true,
+				checkedImports,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// The program can only be cached if all its imports are known
+
+			if importCache != nil {
+				dependencies, ok := context.importDependencies[context.Location.ID()]
+				if ok {
+					importCache.set(context.Location, code, program, dependencies)
+				}
+			}
 		}
 	}
 
@@ -1405,13 +2094,135 @@ func (r *interpreterRuntime) injectedCompositeFieldsHandler(
 	}
 }
 
-func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interface) []interpreter.Option {
+// yieldHandler returns an interpreter yield handler,
+// which calls the given yield handler of the embedder
+//
+func (r *interpreterRuntime) yieldHandler(
+	handler func(point interpreter.YieldPoint) error,
+) interpreter.OnYieldFunc {
+	return func(_ *interpreter.Interpreter, point interpreter.YieldPoint) (err error) {
+		wrapPanic(func() {
+			err = handler(point)
+		})
+		return
+	}
+}
+
+// memoizedFunctionHandler returns a memoized function handler
+// which caches the results of memoized contract functions in the memoization cache of the context.
+//
+// If computation is metered, the computation used to compute a result is recorded,
+// and it is charged again when the cached result is used
+//
+func (r *interpreterRuntime) memoizedFunctionHandler(
+	context Context,
+	meterComputation func(uint64),
+	computationUsed func() uint64,
+) interpreter.MemoizedFunctionHandlerFunc {
+	cache := context.MemoizationCache
+	metered := meterComputation != nil
+
+	return func(
+		_ *interpreter.Interpreter,
+		location common.Location,
+		qualifiedName string,
+		compute func() interpreter.Value,
+	) interpreter.Value {
+
+		codeHash, err := r.codeHash(context, location)
+		if err != nil {
+			panic(err)
+		}
+
+		// The computation used by a cached result is unknown if it was not metered,
+		// so it is only reused by executions which are not metered either
+
+		result, ok := cache.Get(location, codeHash, qualifiedName)
+		if ok && (result.Metered || !metered) {
+			if metered {
+				meterComputation(result.ComputationUsed)
+			}
+			return result.Value
+		}
+
+		var computationUsedBefore uint64
+		if metered {
+			computationUsedBefore = computationUsed()
+		}
+
+		value := compute()
+
+		result = MemoizedResult{
+			Value:   value,
+			Metered: metered,
+		}
+		if metered {
+			result.ComputationUsed = computationUsed() - computationUsedBefore
+		}
+
+		cache.Set(location, codeHash, qualifiedName, result)
+
+		return value
+	}
+}
+
+// codeHash returns the hash of the code of the given location.
+// The hash is only computed once per execution.
+//
+// The code of the location is unknown if the program was provided
+// by the interface, i.e. it was not parsed and checked in this execution
+//
+func (r *interpreterRuntime) codeHash(context Context, location common.Location) ([32]byte, error) {
+	locationID := location.ID()
+
+	codeHash, ok := context.codeHashes[locationID]
+	if ok {
+		return codeHash, nil
+	}
+
+	code, ok := context.codes[locationID]
+	if !ok {
+		codeBytes, err := r.getCode(context.WithLocation(location))
+		if err != nil {
+			return codeHash, err
+		}
+		code = string(codeBytes)
+		context.SetCode(location, code)
+	}
+
+	codeHash = memoizationCodeHash([]byte(code))
+	context.codeHashes[locationID] = codeHash
+
+	return codeHash, nil
+}
+
+// meteringInterpreterOptions returns the interpreter options which meter computation,
+// and a function which allows metering additional computation, e.g. of host functions.
+// The computation used by operations is determined by the given cost table.
+// If there is no computation limit, no function is returned.
+//
+// If an execution report is given, the options also count the operations in the report.
+// If there is neither a computation limit nor a report, no options are returned.
+//
+// If there is a computation limit, a function which returns the computation used so far is also returned
+//
+func (r *interpreterRuntime) meteringInterpreterOptions(
+	runtimeInterface Interface,
+	costTable *ComputationCostTable,
+	report *ExecutionReport,
+) (
+	options []interpreter.Option,
+	meterComputation func(uint64),
+	getComputationUsed func() uint64,
+) {
 	var computationLimit uint64
 	wrapPanic(func() {
 		computationLimit = runtimeInterface.GetComputationLimit()
 	})
-	if computationLimit == 0 {
-		return nil
+	meteringEnabled := computationLimit != 0
+
+	if !meteringEnabled && report == nil {
+		return nil, nil, nil
 	}
 
 	if computationLimit == math.MaxUint64 {
@@ -1421,7 +2232,16 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 	var computationUsed uint64
 
 	checkComputationLimit := func(increase uint64) {
-		computationUsed += increase
+		if !meteringEnabled {
+			return
+		}
+
+		// Saturate instead of overflowing, as the costs are data, see ComputationCostTable
+		if increase > math.MaxUint64-computationUsed {
+			computationUsed = math.MaxUint64
+		} else {
+			computationUsed += increase
+		}
 
 		if computationUsed <= computationLimit {
 			return
@@ -1440,6 +2260,17 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 		})
 	}
 
+	reportOperation := func(kind OperationKind) {
+		if report == nil {
+			return
+		}
+		report.AddOperation(kind)
+	}
+
+	statementCost := costTable.OperationCost(ComputationKindStatement)
+	loopIterationCost := costTable.OperationCost(ComputationKindLoopIteration)
+	functionInvocationCost := costTable.OperationCost(ComputationKindFunctionInvocation)
+
 	callStackDepth := 0
 	// TODO: make runtime interface function
 	const callStackDepthLimit = 2000
@@ -1455,27 +2286,45 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 		})
 	}
 
-	return []interpreter.Option{
-		interpreter.WithOnStatementHandler(
-			func(_ *interpreter.Interpreter, _ ast.Statement) {
-				checkComputationLimit(1)
-			},
-		),
+	options = []interpreter.Option{
 		interpreter.WithOnLoopIterationHandler(
-			func(_ *interpreter.Interpreter, _ int) {
-				checkComputationLimit(1)
+			func(_ *interpreter.Interpreter, _ interpreter.LoopIteration) {
+				reportOperation(OperationKindLoopIteration)
+				checkComputationLimit(loopIterationCost)
 			},
 		),
 		interpreter.WithOnFunctionInvocationHandler(
-			func(_ *interpreter.Interpreter, _ int) {
+			func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation) {
+				reportOperation(OperationKindFunctionInvocation)
+
+				if !meteringEnabled {
+					return
+				}
+
 				callStackDepth++
 				checkCallStackDepth()
 
-				checkComputationLimit(1)
+				checkComputationLimit(functionInvocationCost)
+			},
+		),
+		interpreter.WithOnBigIntOperationHandler(
+			bigIntOperationReportingHandler(report),
+		),
+	}
+
+	if !meteringEnabled {
+		return options, nil, nil
+	}
+
+	options = append(
+		options,
+		interpreter.WithOnStatementHandler(
+			func(_ *interpreter.Interpreter, _ ast.Statement) {
+				checkComputationLimit(statementCost)
 			},
 		),
 		interpreter.WithOnInvokedFunctionReturnHandler(
-			func(_ *interpreter.Interpreter, _ int) {
+			func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation, _ interpreter.Value) {
 				callStackDepth--
 			},
 		),
@@ -1484,7 +2333,13 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 				return runtimeInterface.SetComputationUsed(computationUsed)
 			},
 		),
+	)
+
+	getComputationUsed = func() uint64 {
+		return computationUsed
 	}
+
+	return options, checkComputationLimit, getComputationUsed
 }
 
 var getAuthAccountFunctionType = &sema.FunctionType{
@@ -1649,6 +2504,8 @@ func (r *interpreterRuntime) newCreateAccountFunction(
 
 		payerAddress := payerAddressValue.(interpreter.AddressValue).ToAddress()
 
+		inter.ReportEffect()
+
 		var address Address
 		var err error
 		wrapPanic(func() {
@@ -1785,6 +2642,8 @@ func (r *interpreterRuntime) newAddPublicKeyFunction(
 				panic("addPublicKey requires the first argument to be a byte array")
 			}
 
+			invocation.Interpreter.ReportEffect()
+
 			wrapPanic(func() {
 				err = runtimeInterface.AddEncodedAccountKey(address, publicKey)
 			})
@@ -1821,6 +2680,8 @@ func (r *interpreterRuntime) newRemovePublicKeyFunction(
 		func(invocation interpreter.Invocation) interpreter.Value {
 			index := invocation.Arguments[0].(interpreter.IntValue)
 
+			invocation.Interpreter.ReportEffect()
+
 			var publicKey []byte
 			var err error
 			wrapPanic(func() {
@@ -1895,11 +2756,13 @@ func (r *interpreterRuntime) loadContract(
 		switch location := compositeType.Location.(type) {
 
 		case common.AddressLocation:
-			storageMap := storage.GetStorageMap(
+			storedValue = inter.ReadStored(
 				location.Address,
-				StorageDomainContract,
+				common.StorageKey{
+					Domain:     StorageDomainContract,
+					Identifier: location.Name,
+				},
 			)
-			storedValue = storageMap.ReadValue(location.Name)
 		}
 
 		if storedValue == nil {
@@ -2152,6 +3015,22 @@ func (r *interpreterRuntime) getBlockAtHeight(
 	return NewBlockValue(inter, block), nil
 }
 
+// getCurrentTime returns the current time of the clock provided by the host.
+// The result is false if the host provides no clock.
+//
+func (r *interpreterRuntime) getCurrentTime(runtimeInterface Interface) (now time.Time, ok bool, err error) {
+	wrapPanic(func() {
+		clock := runtimeInterface.Clock()
+		if clock == nil {
+			return
+		}
+
+		now, err = clock.Now()
+		ok = true
+	})
+	return
+}
+
 func (r *interpreterRuntime) newGetCurrentBlockFunction(runtimeInterface Interface) interpreter.HostFunction {
 	return func(invocation interpreter.Invocation) interpreter.Value {
 		var height uint64
@@ -2162,15 +3041,30 @@ func (r *interpreterRuntime) newGetCurrentBlockFunction(runtimeInterface Interfa
 		if err != nil {
 			panic(err)
 		}
-		block, err := r.getBlockAtHeight(
-			height,
-			runtimeInterface,
-			invocation.Interpreter,
-		)
+
+		var block Block
+		var exists bool
+		wrapPanic(func() {
+			block, exists, err = runtimeInterface.GetBlockAtHeight(height)
+		})
+		if err != nil {
+			panic(err)
+		}
+		if !exists {
+			return nil
+		}
+
+		// The timestamp of the current block is the current time of the clock, if any
+
+		now, ok, err := r.getCurrentTime(runtimeInterface)
 		if err != nil {
 			panic(err)
 		}
-		return block
+		if ok {
+			block.Timestamp = now.UnixNano()
+		}
+
+		return NewBlockValue(invocation.Interpreter, block)
 	}
 }
 
@@ -2485,7 +3379,7 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 				if cachedProgram != nil {
 					oldProgram = cachedProgram.Program
 				} else {
-					oldProgram, err = parser2.ParseProgram(string(existingCode))
+					oldProgram, err = parser2.ParseProgramWithConfig(string(existingCode), r.parserConfig())
 					handleContractUpdateError(err)
 				}
 
@@ -2501,6 +3395,8 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 
 			inter := invocation.Interpreter
 
+			inter.ReportEffect()
+
 			err = r.updateAccountContractCode(
 				inter,
 				program,
@@ -2527,6 +3423,10 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 				panic(err)
 			}
 
+			if context.EventSchemaRegistry != nil {
+				context.recordEventTypes(declaredEventTypes(program, context.Location))
+			}
+
 			codeHashValue := CodeToHashValue(inter, code)
 
 			eventArguments := []exportableValue{
@@ -2730,7 +3630,7 @@ func (r *interpreterRuntime) newAuthAccountContractsRemoveFunction(
 				// the existing code contains enums.
 				if r.contractUpdateValidationEnabled {
 
-					existingProgram, err := parser2.ParseProgram(string(code))
+					existingProgram, err := parser2.ParseProgramWithConfig(string(code), r.parserConfig())
 
 					// If the existing code is not parsable (i.e: `err != nil`), that shouldn't be a reason to
 					// fail the contract removal. Therefore, validate only if the code is a valid one.
@@ -2742,6 +3642,8 @@ func (r *interpreterRuntime) newAuthAccountContractsRemoveFunction(
 					}
 				}
 
+				inter.ReportEffect()
+
 				wrapPanic(func() {
 					err = runtimeInterface.RemoveAccountContractCode(address, nameArgument)
 				})
@@ -2837,10 +3739,11 @@ func (r *interpreterRuntime) onStatementHandler() interpreter.OnStatementFunc {
 
 func (r *interpreterRuntime) executeNonProgram(interpret interpretFunc, context Context) (cadence.Value, error) {
 	context.InitializeCodesAndPrograms()
+	defer context.reportPanic()
 
 	var program *interpreter.Program
 
-	storage := NewStorage(context.Interface)
+	storage := r.newStorage(context)
 
 	var functions stdlib.StandardLibraryFunctions
 	var values stdlib.StandardLibraryValues
@@ -2865,18 +3768,20 @@ func (r *interpreterRuntime) executeNonProgram(interpret interpretFunc, context
 		return nil, nil
 	}
 
-	return exportValue(value)
+	return exportValue(value, context.ExportOptions)
 }
 
 func (r *interpreterRuntime) ReadStored(address common.Address, path cadence.Path, context Context) (cadence.Value, error) {
 	return r.executeNonProgram(
 		func(inter *interpreter.Interpreter) (interpreter.Value, error) {
-			pathValue := importPathValue(path)
+			pathValue, err := importPathValue(path)
+			if err != nil {
+				return nil, err
+			}
 
-			domain := pathValue.Domain.Identifier()
-			identifier := pathValue.Identifier
+			key := common.NewPathStorageKey(pathValue.Domain, pathValue.Identifier)
 
-			value := inter.ReadStored(address, domain, identifier)
+			value := inter.ReadStored(address, key)
 
 			return value, nil
 		},
@@ -2887,9 +3792,14 @@ func (r *interpreterRuntime) ReadStored(address common.Address, path cadence.Pat
 func (r *interpreterRuntime) ReadLinked(address common.Address, path cadence.Path, context Context) (cadence.Value, error) {
 	return r.executeNonProgram(
 		func(inter *interpreter.Interpreter) (interpreter.Value, error) {
+			pathValue, err := importPathValue(path)
+			if err != nil {
+				return nil, err
+			}
+
 			targetPath, _, err := inter.GetCapabilityFinalTargetPath(
 				address,
-				importPathValue(path),
+				pathValue,
 				&sema.ReferenceType{
 					Type: sema.AnyType,
 				},
@@ -2905,8 +3815,7 @@ func (r *interpreterRuntime) ReadLinked(address common.Address, path cadence.Pat
 
 			value := inter.ReadStored(
 				address,
-				targetPath.Domain.Identifier(),
-				targetPath.Identifier,
+				common.NewPathStorageKey(targetPath.Domain, targetPath.Identifier),
 			)
 			return value, nil
 		},
@@ -2975,6 +3884,8 @@ func (r *interpreterRuntime) newAccountKeysAddFunction(
 			hashAlgo := NewHashAlgorithmFromValue(inter, getLocationRange, invocation.Arguments[1])
 			weight := invocation.Arguments[2].(interpreter.UFix64Value).ToInt()
 
+			inter.ReportEffect()
+
 			var accountKey *AccountKey
 			wrapPanic(func() {
 				accountKey, err = runtimeInterface.AddAccountKey(address, publicKey, hashAlgo, weight)
@@ -3060,6 +3971,8 @@ func (r *interpreterRuntime) newAccountKeysRevokeFunction(
 			indexValue := invocation.Arguments[0].(interpreter.IntValue)
 			index := indexValue.ToInt()
 
+			invocation.Interpreter.ReportEffect()
+
 			var err error
 			var accountKey *AccountKey
 			wrapPanic(func() {
@@ -128,6 +128,7 @@ func newTestLedger(
 func newTestInterpreterRuntime(options ...Option) Runtime {
 	rt := NewInterpreterRuntime(options...)
 	rt.SetAtreeValidationEnabled(true)
+	rt.SetOwnerValidationEnabled(true)
 	return rt
 }
 
@@ -156,6 +157,7 @@ type testRuntimeInterface struct {
 	)
 	generateUUID       func() (uint64, error)
 	computationLimit   uint64
+	computationUsed    uint64
 	decodeArgument     func(b []byte, t cadence.Type) (cadence.Value, error)
 	programParsed      func(location common.Location, duration time.Duration)
 	programChecked     func(location common.Location, duration time.Duration)
@@ -183,6 +185,8 @@ type testRuntimeInterface struct {
 	aggregateBLSPublicKeys     func(keys []*PublicKey) (*PublicKey, error)
 	getAccountContractNames    func(address Address) ([]string, error)
 	recordTrace                func(operation string, location common.Location, duration time.Duration, logs []opentracing.LogRecord)
+	isAccountLinkingAllowed    func(address Address) (bool, error)
+	clock                      Clock
 }
 
 // testRuntimeInterface should implement Interface
@@ -319,7 +323,8 @@ func (i *testRuntimeInterface) GetComputationLimit() uint64 {
 	return i.computationLimit
 }
 
-func (i *testRuntimeInterface) SetComputationUsed(uint64) error {
+func (i *testRuntimeInterface) SetComputationUsed(used uint64) error {
+	i.computationUsed = used
 	return nil
 }
 
@@ -482,6 +487,17 @@ func (i *testRuntimeInterface) RecordTrace(operation string, location common.Loc
 	i.recordTrace(operation, location, duration, logs)
 }
 
+func (i *testRuntimeInterface) Clock() Clock {
+	return i.clock
+}
+
+func (i *testRuntimeInterface) IsAccountLinkingAllowed(address Address) (bool, error) {
+	if i.isAccountLinkingAllowed == nil {
+		return false, nil
+	}
+	return i.isAccountLinkingAllowed(address)
+}
+
 func TestRuntimeImport(t *testing.T) {
 
 	t.Parallel()
@@ -847,6 +863,99 @@ func TestRuntimeTransactionWithAccount(t *testing.T) {
 	assert.Equal(t, "0x000000000000002a", loggedMessage)
 }
 
+func TestRuntimeTransactionWithAccountArray(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	script := []byte(`
+      transaction {
+        prepare(payer: AuthAccount, signers: [AuthAccount; 2]) {
+          log(payer.address)
+          log(signers.length)
+          log(signers[0].address)
+          log(signers[1].address)
+        }
+      }
+    `)
+
+	var signingAccounts []Address
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return signingAccounts, nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func() error {
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+	}
+
+	t.Run("matching authorizer count", func(t *testing.T) {
+
+		signingAccounts = []Address{
+			common.BytesToAddress([]byte{1}),
+			common.BytesToAddress([]byte{2}),
+			common.BytesToAddress([]byte{3}),
+		}
+		loggedMessages = nil
+
+		err := executeTransaction()
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]string{
+				"0x0000000000000001",
+				"2",
+				"0x0000000000000002",
+				"0x0000000000000003",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("mismatching authorizer count", func(t *testing.T) {
+
+		signingAccounts = []Address{
+			common.BytesToAddress([]byte{1}),
+			common.BytesToAddress([]byte{2}),
+		}
+		loggedMessages = nil
+
+		err := executeTransaction()
+		require.Error(t, err)
+
+		var authorizerCountErr InvalidTransactionAuthorizerCountError
+		require.ErrorAs(t, err, &authorizerCountErr)
+
+		assert.Equal(t,
+			InvalidTransactionAuthorizerCountError{
+				Expected: 3,
+				Actual:   2,
+			},
+			authorizerCountErr,
+		)
+
+		assert.Empty(t, loggedMessages)
+	})
+}
+
 func TestRuntimeTransactionWithArguments(t *testing.T) {
 
 	t.Parallel()
@@ -1440,6 +1549,56 @@ func TestRuntimeScriptArguments(t *testing.T) {
 			},
 			expectedLogs: []string{`"bar"`},
 		},
+		{
+			name: "Type mismatch, error details",
+			script: `
+                pub fun main(x: Int, y: Int) {}
+            `,
+			args: [][]byte{
+				jsoncdc.MustEncode(cadence.NewInt(1)),
+				jsoncdc.MustEncode(cadence.String("foo")),
+			},
+			check: func(t *testing.T, err error) {
+				require.Error(t, err)
+
+				var argErr *InvalidEntryPointArgumentError
+				require.ErrorAs(t, err, &argErr)
+
+				assert.Equal(t, 1, argErr.Index)
+				assert.Equal(t, sema.IntType, argErr.ParameterType)
+				assert.Equal(t, cadence.StringType{}, argErr.ArgumentType)
+				assert.IsType(t, &InvalidValueTypeError{}, argErr.Err)
+
+				assert.Equal(t,
+					"invalid argument at index 1 for parameter of type `Int`, "+
+						"got argument of type `String`: expected value of type `Int`",
+					argErr.Error(),
+				)
+			},
+		},
+		{
+			name: "Invalid encoding, error details",
+			script: `
+                pub fun main(x: [Int]) {}
+            `,
+			args: [][]byte{
+				[]byte("{"),
+			},
+			check: func(t *testing.T, err error) {
+				require.Error(t, err)
+
+				var argErr *InvalidEntryPointArgumentError
+				require.ErrorAs(t, err, &argErr)
+
+				assert.Equal(t, 0, argErr.Index)
+				assert.Equal(t,
+					&sema.VariableSizedType{Type: sema.IntType},
+					argErr.ParameterType,
+				)
+				assert.Nil(t, argErr.ArgumentType)
+				require.Error(t, argErr.Err)
+			},
+		},
 	}
 
 	test := func(tt testCase) {
@@ -3451,6 +3610,240 @@ func TestRuntimeContractNestedResource(t *testing.T) {
 	assert.Equal(t, `"Hello World!"`, loggedMessage)
 }
 
+func TestRuntimeContractInterfaceNestedTypes(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	addressValue := Address{
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+	}
+
+	contractInterface := []byte(`
+        pub contract interface CI {
+
+            pub struct S {
+                pub fun hello(): String
+            }
+
+            pub struct interface SI {}
+
+            pub resource R {}
+        }
+    `)
+
+	contract := []byte(`
+        import CI from 0x01
+
+        pub contract C: CI {
+
+            pub struct S: CI.SI {
+                pub fun hello(): String {
+                    return "Hello World!"
+                }
+            }
+
+            pub resource R {}
+
+            init() {
+                let values: [CI.S] = [S()]
+                self.account.save(values, to: /storage/values)
+
+                let r: @CI.R <- create R()
+                self.account.save(<-r, to: /storage/r)
+                self.account.link<&CI.R>(/public/r, target: /storage/r)
+
+                let interfaceValues: [{CI.SI}] = [S()]
+                self.account.save(interfaceValues, to: /storage/interfaceValues)
+            }
+        }
+    `)
+
+	tx := []byte(`
+        import CI from 0x01
+
+        transaction {
+
+            prepare(signer: AuthAccount) {
+                log(Type<CI.S>().identifier)
+                log(Type<{CI.SI}>().identifier)
+
+                let values = signer.load<[CI.S]>(from: /storage/values)!
+                log(values.getType().identifier)
+                log(values[0].hello())
+
+                log(signer.getCapability<&CI.R>(/public/r).borrow() != nil)
+                log(signer.load<[{CI.SI}]>(from: /storage/interfaceValues)!.length)
+            }
+        }
+    `)
+
+	accountCodes := map[string][]byte{}
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{addressValue}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(_ Address, name string) (code []byte, err error) {
+			return accountCodes[name], nil
+		},
+		updateAccountContractCode: func(_ Address, name string, code []byte) error {
+			accountCodes[name] = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+		decodeArgument: func(b []byte, t cadence.Type) (value cadence.Value, err error) {
+			return jsoncdc.Decode(b)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	for _, transaction := range [][]byte{
+		utils.DeploymentTransaction("CI", contractInterface),
+		utils.DeploymentTransaction("C", contract),
+		tx,
+	} {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: transaction,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t,
+		[]string{
+			`"A.0000000000000001.CI.S"`,
+			`"AnyStruct{A.0000000000000001.CI.SI}"`,
+			`"[A.0000000000000001.CI.S]"`,
+			`"Hello World!"`,
+			"true",
+			"1",
+		},
+		loggedMessages,
+	)
+
+	t.Run("runtime type lookup", func(t *testing.T) {
+
+		result, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(`
+                  pub fun main(): [Type?] {
+                      return [
+                          CompositeType("A.0000000000000001.CI.S"),
+                          InterfaceType("A.0000000000000001.CI.SI")
+                      ]
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		ciLocation := common.AddressLocation{
+			Address: addressValue,
+			Name:    "CI",
+		}
+
+		require.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewOptional(cadence.TypeValue{
+					StaticType: &cadence.StructType{
+						Location:            ciLocation,
+						QualifiedIdentifier: "CI.S",
+						Fields:              []cadence.Field{},
+					},
+				}),
+				cadence.NewOptional(cadence.TypeValue{
+					StaticType: &cadence.StructInterfaceType{
+						Location:            ciLocation,
+						QualifiedIdentifier: "CI.SI",
+						Fields:              []cadence.Field{},
+					},
+				}),
+			}),
+			result,
+		)
+	})
+
+	argumentScript := []byte(`
+      import CI from 0x01
+
+      pub fun main(s: CI.S): String {
+          return s.hello()
+      }
+    `)
+
+	executeArgumentScript := func(argument cadence.Value) (cadence.Value, error) {
+		encodedArgument, err := jsoncdc.Encode(argument)
+		require.NoError(t, err)
+
+		return runtime.ExecuteScript(
+			Script{
+				Source:    argumentScript,
+				Arguments: [][]byte{encodedArgument},
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+	}
+
+	t.Run("argument of implementing type", func(t *testing.T) {
+
+		result, err := executeArgumentScript(
+			cadence.NewStruct([]cadence.Value{}).
+				WithType(&cadence.StructType{
+					Location: common.AddressLocation{
+						Address: addressValue,
+						Name:    "C",
+					},
+					QualifiedIdentifier: "C.S",
+					Fields:              []cadence.Field{},
+				}),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.String("Hello World!"), result)
+	})
+
+	t.Run("argument of type requirement", func(t *testing.T) {
+
+		_, err := executeArgumentScript(
+			cadence.NewStruct([]cadence.Value{}).
+				WithType(&cadence.StructType{
+					Location: common.AddressLocation{
+						Address: addressValue,
+						Name:    "CI",
+					},
+					QualifiedIdentifier: "CI.S",
+					Fields:              []cadence.Field{},
+				}),
+		)
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "cannot import value of type requirement CI.S")
+	})
+}
+
 func TestRuntimeStorageLoadedDestructionConcreteType(t *testing.T) {
 
 	t.Parallel()
@@ -4396,37 +4789,141 @@ func TestRuntimeBlock(t *testing.T) {
 	)
 }
 
-func TestRuntimeUnsafeRandom(t *testing.T) {
+type testClock struct {
+	now time.Time
+	err error
+}
+
+func (c testClock) Now() (time.Time, error) {
+	return c.now, c.err
+}
+
+func TestRuntimeBlockClock(t *testing.T) {
 
 	t.Parallel()
 
 	runtime := newTestInterpreterRuntime()
 
 	script := []byte(`
-      transaction {
-        prepare() {
-          let rand = unsafeRandom()
-          log(rand)
-        }
+      pub fun main(): [UFix64] {
+          return [
+              getCurrentBlock().timestamp,
+              getBlock(at: 2)!.timestamp
+          ]
       }
     `)
 
-	var loggedMessages []string
-
-	runtimeInterface := &testRuntimeInterface{
-		unsafeRandom: func() (uint64, error) {
-			return 7558174677681708339, nil
-		},
-		log: func(message string) {
-			loggedMessages = append(loggedMessages, message)
-		},
+	newRuntimeInterface := func(clock Clock) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			clock:   clock,
+		}
 	}
 
-	nextTransactionLocation := newTransactionLocationGenerator()
+	t.Run("clock", func(t *testing.T) {
 
-	err := runtime.ExecuteTransaction(
-		Script{
-			Source: script,
+		t.Parallel()
+
+		result, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: newRuntimeInterface(testClock{
+					now: time.Unix(1234, 0),
+				}),
+				Location: common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		// Only the timestamp of the current block is provided by the clock
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(1234_00000000),
+				cadence.UFix64(2_00000000),
+			}),
+			result,
+		)
+	})
+
+	t.Run("no clock", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: newRuntimeInterface(nil),
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(1_00000000),
+				cadence.UFix64(2_00000000),
+			}),
+			result,
+		)
+	})
+
+	t.Run("error", func(t *testing.T) {
+
+		t.Parallel()
+
+		clockErr := errors.New("clock failed")
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: newRuntimeInterface(testClock{
+					err: clockErr,
+				}),
+				Location: common.ScriptLocation{},
+			},
+		)
+		require.ErrorIs(t, err, clockErr)
+	})
+}
+
+func TestRuntimeUnsafeRandom(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	script := []byte(`
+      transaction {
+        prepare() {
+          let rand = unsafeRandom()
+          log(rand)
+        }
+      }
+    `)
+
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		unsafeRandom: func() (uint64, error) {
+			return 7558174677681708339, nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: script,
 		},
 		Context{
 			Interface: runtimeInterface,
@@ -5256,6 +5753,59 @@ func TestRuntimeComputationLimit(t *testing.T) {
 	}
 }
 
+func TestRuntimeYieldHandler(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+      transaction {
+          prepare() {
+              while true {}
+          }
+      }
+    `)
+
+	runtime := newTestInterpreterRuntime()
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return nil, nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	preemptedErr := errors.New("preempted")
+
+	const maxIterations = 100
+
+	var iterations int
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+			YieldHandler: func(point interpreter.YieldPoint) error {
+				if point != interpreter.YieldPointLoopIteration {
+					return nil
+				}
+				iterations++
+				if iterations == maxIterations {
+					return preemptedErr
+				}
+				return nil
+			},
+		},
+	)
+	require.ErrorIs(t, err, preemptedErr)
+
+	assert.Equal(t, maxIterations, iterations)
+}
+
 func TestRuntimeMetrics(t *testing.T) {
 
 	t.Parallel()
@@ -6909,3 +7459,349 @@ func TestRuntimeStackOverflow(t *testing.T) {
 	var callStackLimitExceededErr CallStackLimitExceededError
 	require.ErrorAs(t, err, &callStackLimitExceededErr)
 }
+
+func TestRuntimeScriptVM(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	executeScript := func(script string, arguments [][]byte, computationLimit uint64) (cadence.Value, error) {
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			decodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+				return jsoncdc.Decode(b)
+			},
+			computationLimit: computationLimit,
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source:    []byte(script),
+				Arguments: arguments,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+				UseVM:     true,
+			},
+		)
+	}
+
+	t.Run("compiled", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := executeScript(
+			`
+              pub fun main(n: Int): Int {
+                  return fib(n)
+              }
+
+              pub fun fib(_ n: Int): Int {
+                  if n < 2 {
+                      return n
+                  }
+                  return fib(n - 1) + fib(n - 2)
+              }
+            `,
+			[][]byte{
+				jsoncdc.MustEncode(cadence.NewInt(10)),
+			},
+			0,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(55), value)
+	})
+
+	t.Run("not compiled", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := executeScript(
+			`
+              pub fun main(): Int {
+                  var sum = 0
+                  for i in [1, 2, 3] {
+                      sum = sum + i
+                  }
+                  return sum
+              }
+            `,
+			nil,
+			0,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(6), value)
+	})
+
+	t.Run("computation limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(
+			`
+              pub fun main() {
+                  while true {}
+              }
+            `,
+			nil,
+			10,
+		)
+		require.Error(t, err)
+
+		var computationLimitErr ComputationLimitExceededError
+		require.ErrorAs(t, err, &computationLimitErr)
+	})
+}
+
+func TestRuntimeExternalMutationCheck(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(externalMutationCheckEnabled bool) error {
+
+		runtime := NewInterpreterRuntime(
+			WithExternalMutationCheckEnabled(externalMutationCheckEnabled),
+		)
+
+		script := []byte(`
+          pub struct S {
+              pub let xs: [Int]
+
+              init() {
+                  self.xs = []
+              }
+          }
+
+          pub fun main(): Int {
+              let s = S()
+              s.xs.append(1)
+              return s.xs.length
+          }
+        `)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		return err
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(false)
+		require.NoError(t, err)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(true)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checker.ExpectCheckerErrors(t, checkerErr, 1)
+
+		assert.IsType(t, &sema.ExternalMutationError{}, errs[0])
+	})
+}
+
+func TestRuntimeReferenceInvalidation(t *testing.T) {
+
+	t.Parallel()
+
+	// The resource is temporarily moved out of the field to borrow it,
+	// and then moved back, which invalidates the reference.
+	// This pattern is used by existing contracts, e.g. ItemNFT in TestRuntimeMissingMemberFabricant
+
+	test := func(referenceInvalidationEnabled bool) (cadence.Value, error) {
+
+		runtime := NewInterpreterRuntime(
+			WithReferenceInvalidationEnabled(referenceInvalidationEnabled),
+		)
+
+		script := []byte(`
+          pub resource R {
+              pub let value: Int
+
+              init() {
+                  self.value = 42
+              }
+          }
+
+          pub resource Holder {
+              pub var r: @R?
+
+              init() {
+                  self.r <- create R()
+              }
+
+              pub fun borrowR(): &R? {
+                  let rOptional <- self.r <- nil
+                  let r <- rOptional!
+                  let rRef = &r as &R
+                  self.r <-! r
+                  return rRef
+              }
+
+              destroy() {
+                  destroy self.r
+              }
+          }
+
+          pub fun main(): Int {
+              let holder <- create Holder()
+              let value = holder.borrowR()!.value
+              destroy holder
+              return value
+          }
+        `)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := test(false)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewInt(42), value)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := test(true)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checker.ExpectCheckerErrors(t, checkerErr, 1)
+
+		assert.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+}
+
+func TestRuntimeIndexedEventFields(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      pub contract Test {
+
+          pub event Transferred(indexed from: Address?, indexed to: Address?, amount: Int)
+
+          pub fun transfer(from: Address?, to: Address?, amount: Int) {
+              emit Transferred(from: from, to: to, amount: amount)
+          }
+      }
+    `)
+
+	transaction := []byte(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              Test.transfer(from: nil, to: signer.address, amount: 42)
+          }
+      }
+    `)
+
+	var accountCode []byte
+	var events []cadence.Event
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: utils.DeploymentTransaction("Test", contract),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	events = nil
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: transaction,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, events, 1)
+	event := events[0]
+
+	assert.Equal(t, []string{"from", "to"}, event.EventType.IndexedFields)
+	assert.True(t, event.EventType.IsIndexedField("to"))
+	assert.False(t, event.EventType.IsIndexedField("amount"))
+
+	assert.Equal(t,
+		map[string]cadence.Value{
+			"from": cadence.NewOptional(nil),
+			"to":   cadence.NewOptional(cadence.Address(address)),
+		},
+		event.IndexedFields(),
+	)
+}
@@ -20,6 +20,7 @@ package sema
 
 import (
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
 )
 
 const AuthAccountTypeName = "AuthAccount"
@@ -27,12 +28,14 @@ const AuthAccountAddressField = "address"
 const AuthAccountBalanceField = "balance"
 const AuthAccountAvailableBalanceField = "availableBalance"
 const AuthAccountStorageUsedField = "storageUsed"
+const AuthAccountStorageUsedByPathField = "storageUsedByPath"
 const AuthAccountStorageCapacityField = "storageCapacity"
 const AuthAccountAddPublicKeyField = "addPublicKey"
 const AuthAccountRemovePublicKeyField = "removePublicKey"
 const AuthAccountSaveField = "save"
 const AuthAccountLoadField = "load"
 const AuthAccountTypeField = "type"
+const AuthAccountStoragePathsField = "storagePaths"
 const AuthAccountCopyField = "copy"
 const AuthAccountBorrowField = "borrow"
 const AuthAccountLinkField = "link"
@@ -41,6 +44,15 @@ const AuthAccountGetCapabilityField = "getCapability"
 const AuthAccountGetLinkTargetField = "getLinkTarget"
 const AuthAccountContractsField = "contracts"
 const AuthAccountKeysField = "keys"
+const AuthAccountLinkAccountField = "linkAccount"
+const AuthAccountSaveTransientField = "saveTransient"
+const AuthAccountLoadTransientField = "loadTransient"
+const AuthAccountCopyTransientField = "copyTransient"
+
+// AccountLinkingPragma is the pragma which programs must declare
+// to use AuthAccount.linkAccount, i.e. `#allowAccountLinking`
+//
+const AccountLinkingPragma = "allowAccountLinking"
 
 // AuthAccountType represents the authorized access to an account.
 // Access to an AuthAccount means having full access to its storage, public keys, and code.
@@ -55,7 +67,7 @@ var AuthAccountType = func() *CompositeType {
 		importable:         false,
 
 		nestedTypes: func() *StringTypeOrderedMap {
-			nestedTypes := NewStringTypeOrderedMap()
+			nestedTypes := &StringTypeOrderedMap{}
 			nestedTypes.Set(AuthAccountContractsTypeName, AuthAccountContractsType)
 			nestedTypes.Set(AccountKeysTypeName, AuthAccountKeysType)
 			return nestedTypes
@@ -87,6 +99,12 @@ var AuthAccountType = func() *CompositeType {
 			UInt64Type,
 			accountTypeStorageUsedFieldDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountStorageUsedByPathField,
+			AuthAccountTypeStorageUsedByPathFunctionType,
+			authAccountTypeStorageUsedByPathFunctionDocString,
+		),
 		NewPublicConstantFieldMember(
 			authAccountType,
 			AuthAccountStorageCapacityField,
@@ -117,6 +135,12 @@ var AuthAccountType = func() *CompositeType {
 			AuthAccountTypeTypeFunctionType,
 			authAccountTypeTypeFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountStoragePathsField,
+			AuthAccountTypeStoragePathsFunctionType,
+			authAccountTypeStoragePathsFunctionDocString,
+		),
 		NewPublicFunctionMember(
 			authAccountType,
 			AuthAccountLoadField,
@@ -135,6 +159,24 @@ var AuthAccountType = func() *CompositeType {
 			AuthAccountTypeBorrowFunctionType,
 			authAccountTypeBorrowFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountSaveTransientField,
+			AuthAccountTypeSaveTransientFunctionType,
+			authAccountTypeSaveTransientFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountLoadTransientField,
+			AuthAccountTypeLoadTransientFunctionType,
+			authAccountTypeLoadTransientFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountCopyTransientField,
+			AuthAccountTypeCopyTransientFunctionType,
+			authAccountTypeCopyTransientFunctionDocString,
+		),
 		NewPublicFunctionMember(
 			authAccountType,
 			AuthAccountLinkField,
@@ -147,6 +189,12 @@ var AuthAccountType = func() *CompositeType {
 			AuthAccountTypeUnlinkFunctionType,
 			authAccountTypeUnlinkFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountLinkAccountField,
+			newAuthAccountTypeLinkAccountFunctionType(authAccountType),
+			authAccountTypeLinkAccountFunctionDocString,
+		),
 		NewPublicFunctionMember(
 			authAccountType,
 			AuthAccountGetCapabilityField,
@@ -292,6 +340,22 @@ If there is an object stored, the type of the object is returned without modifyi
 The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
 `
 
+const authAccountTypeStorageUsedByPathFunctionDocString = `
+Returns the amount of storage used by each object stored in the account's storage, in bytes, keyed by path.
+
+The amounts are the encoded sizes of the objects, without the storage overhead of the account itself,
+so their sum is less than the account's ` + "`storageUsed`" + `
+`
+
+var AuthAccountTypeStorageUsedByPathFunctionType = &FunctionType{
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&DictionaryType{
+			KeyType:   PathType,
+			ValueType: UInt64Type,
+		},
+	),
+}
+
 var AuthAccountTypeTypeFunctionType = &FunctionType{
 	Parameters: []*Parameter{
 		{
@@ -307,6 +371,29 @@ var AuthAccountTypeTypeFunctionType = &FunctionType{
 	),
 }
 
+const authAccountTypeStoragePathsFunctionDocString = `
+Returns the storage paths of all objects in the account's storage which have a subtype of the given type,
+i.e. the paths for which ` + "`type(at:)`" + ` returns a subtype of the given type.
+
+The types of the objects are determined without loading the objects if possible.
+The order of the returned paths is unspecified
+`
+
+var AuthAccountTypeStoragePathsFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          "ofType",
+			Identifier:     "type",
+			TypeAnnotation: NewTypeAnnotation(MetaType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&VariableSizedType{
+			Type: StoragePathType,
+		},
+	),
+}
+
 const authAccountTypeLoadFunctionDocString = `
 Loads an object from the account's storage which is stored under the given path, or nil if no object is stored under the given path.
 
@@ -362,6 +449,74 @@ The given type must not necessarily be exactly the same as the type of the copie
 The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
 `
 
+var AuthAccountTypeSaveTransientFunctionType = func() *FunctionType {
+
+	typeParameter := &TypeParameter{
+		Name:      "T",
+		TypeBound: AnyStructType,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "value",
+				TypeAnnotation: NewTypeAnnotation(
+					&GenericType{
+						TypeParameter: typeParameter,
+					},
+				),
+			},
+			{
+				Label:          "to",
+				Identifier:     "path",
+				TypeAnnotation: NewTypeAnnotation(StoragePathType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+	}
+}()
+
+const authAccountTypeSaveTransientFunctionDocString = `
+Saves the given structure into the account's transient storage at the given path.
+The structure is copied.
+
+The transient storage is a scratch space which only exists for the duration of the current transaction:
+Objects in transient storage are never written to the account's storage, so they do not use any storage,
+and they are discarded when the transaction ends.
+
+If there is already an object stored under the given path in transient storage, the program aborts.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
+var AuthAccountTypeLoadTransientFunctionType = AuthAccountTypeCopyFunctionType
+
+const authAccountTypeLoadTransientFunctionDocString = `
+Loads a structure from the account's transient storage which is stored under the given path, or nil if no object is stored under the given path.
+
+If there is a structure stored, it is moved out of transient storage and returned as an optional.
+
+When the function returns, the transient storage no longer contains an object under the given path.
+
+The given type must be a supertype of the type of the loaded structure.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
+var AuthAccountTypeCopyTransientFunctionType = AuthAccountTypeCopyFunctionType
+
+const authAccountTypeCopyTransientFunctionDocString = `
+Returns a copy of a structure stored in the account's transient storage under the given path, without removing it, or nil if no object is stored under the given path.
+
+The given type must be a supertype of the type of the copied structure.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
 var AuthAccountTypeBorrowFunctionType = func() *FunctionType {
 
 	typeParameter := &TypeParameter{
@@ -456,6 +611,50 @@ The link function does **not** check if the target path is valid/exists at the t
 The link is latent. The target value might be stored after the link is created, and the target value might be moved out after the link has been created.
 `
 
+// AuthAccountTypeLinkAccountFunctionType is the type of AuthAccount.linkAccount.
+//
+// NOTE: The function type refers to the AuthAccount type itself,
+// so it is constructed when the AuthAccount type is constructed.
+//
+var AuthAccountTypeLinkAccountFunctionType = func() *FunctionType {
+	member, ok := AuthAccountType.Members.Get(AuthAccountLinkAccountField)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return member.TypeAnnotation.Type.(*FunctionType)
+}()
+
+func newAuthAccountTypeLinkAccountFunctionType(authAccountType *CompositeType) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:          ArgumentLabelNotRequired,
+				Identifier:     "newCapabilityPath",
+				TypeAnnotation: NewTypeAnnotation(PrivatePathType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&OptionalType{
+				Type: &CapabilityType{
+					BorrowType: &ReferenceType{
+						Type: authAccountType,
+					},
+				},
+			},
+		),
+	}
+}
+
+const authAccountTypeLinkAccountFunctionDocString = `
+Creates a capability at the given private path which targets this account.
+
+The capability can be borrowed as a reference to this account, i.e. it grants full access to the account.
+Linking accounts is only allowed if the program declares the ` + "`#" + AccountLinkingPragma + "`" + ` pragma
+and the host environment permits it for this account.
+
+Returns nil if a link for the given capability path already exists, or the newly created capability if not.
+`
+
 var AuthAccountTypeUnlinkFunctionType = &FunctionType{
 	Parameters: []*Parameter{
 		{
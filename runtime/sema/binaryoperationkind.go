@@ -35,6 +35,7 @@ const (
 	BinaryOperationKindEquality
 	BinaryOperationKindNilCoalescing
 	BinaryOperationKindBitwise
+	BinaryOperationKindRange
 )
 
 func binaryOperationKind(operation ast.Operation) BinaryOperationKind {
@@ -74,6 +75,11 @@ func binaryOperationKind(operation ast.Operation) BinaryOperationKind {
 		ast.OperationBitwiseRightShift:
 
 		return BinaryOperationKindBitwise
+
+	case ast.OperationRangeInclusive,
+		ast.OperationRangeExclusive:
+
+		return BinaryOperationKindRange
 	}
 
 	panic(errors.NewUnreachableError())
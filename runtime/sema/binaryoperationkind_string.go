@@ -15,11 +15,12 @@ func _() {
 	_ = x[BinaryOperationKindEquality-4]
 	_ = x[BinaryOperationKindNilCoalescing-5]
 	_ = x[BinaryOperationKindBitwise-6]
+	_ = x[BinaryOperationKindRange-7]
 }
 
-const _BinaryOperationKind_name = "BinaryOperationKindUnknownBinaryOperationKindArithmeticBinaryOperationKindNonEqualityComparisonBinaryOperationKindBooleanLogicBinaryOperationKindEqualityBinaryOperationKindNilCoalescingBinaryOperationKindBitwise"
+const _BinaryOperationKind_name = "BinaryOperationKindUnknownBinaryOperationKindArithmeticBinaryOperationKindNonEqualityComparisonBinaryOperationKindBooleanLogicBinaryOperationKindEqualityBinaryOperationKindNilCoalescingBinaryOperationKindBitwiseBinaryOperationKindRange"
 
-var _BinaryOperationKind_index = [...]uint8{0, 26, 55, 95, 126, 153, 185, 211}
+var _BinaryOperationKind_index = [...]uint8{0, 26, 55, 95, 126, 153, 185, 211, 235}
 
 func (i BinaryOperationKind) String() string {
 	if i >= BinaryOperationKind(len(_BinaryOperationKind_index)-1) {
@@ -46,6 +46,8 @@ func (checker *Checker) checkAssignment(
 
 	targetType = checker.visitAssignmentValueType(target)
 
+	checker.setTransferOccurrenceRole(value, transfer)
+
 	valueType = checker.VisitExpression(value, targetType)
 
 	// NOTE: Visiting the `value` checks the compatibility between value and target types.
@@ -104,6 +106,11 @@ func (checker *Checker) checkAssignment(
 		ResourceInvalidationKindMoveDefinite,
 	)
 
+	if identifierExpression, ok := target.(*ast.IdentifierExpression); ok {
+		variable := checker.valueActivations.Find(identifierExpression.Identifier.Identifier)
+		checker.recordReference(variable, value)
+	}
+
 	return
 }
 
@@ -167,6 +174,15 @@ func (checker *Checker) visitAssignmentValueType(
 		return InvalidType
 	}
 
+	// The target is written. For an indexing expression,
+	// the written declaration is the one of the indexed container
+
+	if indexExpression, ok := targetExpression.(*ast.IndexExpression); ok {
+		checker.setOccurrenceRole(indexExpression.TargetExpression, OccurrenceRoleWrite)
+	} else {
+		checker.setOccurrenceRole(targetExpression, OccurrenceRoleWrite)
+	}
+
 	switch target := targetExpression.(type) {
 	case *ast.IdentifierExpression:
 		return checker.visitIdentifierExpressionAssignment(target)
@@ -50,12 +50,57 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 	case BinaryOperationKindArithmetic,
 		BinaryOperationKindBitwise:
 		expectedType = UnwrapOptionalType(checker.expectedType)
+
+	case BinaryOperationKindRange:
+		// For range expressions, the operands are expected
+		// to have the member type of the expected range type,
+		// e.g. `let range: InclusiveRange<UInt8> = 1...10`
+		rangeType, ok := UnwrapOptionalType(checker.expectedType).(*InclusiveRangeType)
+		if ok {
+			expectedType = rangeType.MemberType
+		}
+	}
+
+	// If the left-hand side is a literal which gets its type from the context,
+	// but the right-hand side is not, then check the right-hand side first,
+	// and infer the type of the left-hand side from it.
+	// For example, this allows a declaration like this to type-check:
+	//
+	// ```
+	// let x = 1 as UInt8
+	// let y = 1 + x
+	// ```
+
+	var checkRightFirst bool
+	switch operationKind {
+	case BinaryOperationKindArithmetic,
+		BinaryOperationKindNonEqualityComparison,
+		BinaryOperationKindEquality,
+		BinaryOperationKindBitwise,
+		BinaryOperationKindRange:
+
+		checkRightFirst = isTypeInferredFromOther(expression.Left, expression.Right)
 	}
 
-	// Visit the expression, with contextually expected type. Use the expected type
-	// only for inferring wherever possible, but do not check for compatibility.
-	// Compatibility is checked separately for each operand kind.
-	leftType := checker.VisitExpressionWithForceType(expression.Left, expectedType, false)
+	var leftType, rightType Type
+
+	if checkRightFirst {
+		rightType = checker.VisitExpressionWithForceType(expression.Right, expectedType, false)
+
+		leftExpectedType := expectedType
+		if leftExpectedType == nil ||
+			(rightType != leftExpectedType && IsProperSubType(rightType, leftExpectedType)) {
+
+			leftExpectedType = rightType
+		}
+
+		leftType = checker.VisitExpressionWithForceType(expression.Left, leftExpectedType, false)
+	} else {
+		// Visit the expression, with contextually expected type. Use the expected type
+		// only for inferring wherever possible, but do not check for compatibility.
+		// Compatibility is checked separately for each operand kind.
+		leftType = checker.VisitExpressionWithForceType(expression.Left, expectedType, false)
+	}
 
 	leftIsInvalid := leftType.IsInvalidType()
 
@@ -71,7 +116,8 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 	case BinaryOperationKindArithmetic,
 		BinaryOperationKindNonEqualityComparison,
 		BinaryOperationKindEquality,
-		BinaryOperationKindBitwise:
+		BinaryOperationKindBitwise,
+		BinaryOperationKindRange:
 
 		// Right hand side will always be evaluated
 
@@ -105,40 +151,63 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 		// let character = string[index + 1]
 		// ```
 
-		if expectedType == nil ||
-			(leftType != expectedType && IsProperSubType(leftType, expectedType)) {
+		if !checkRightFirst {
+			if expectedType == nil ||
+				(leftType != expectedType && IsProperSubType(leftType, expectedType)) {
 
-			expectedType = leftType
-		}
+				expectedType = leftType
+			}
 
-		rightType := checker.VisitExpressionWithForceType(expression.Right, expectedType, false)
+			rightType = checker.VisitExpressionWithForceType(expression.Right, expectedType, false)
+		}
 
 		rightIsInvalid := rightType.IsInvalidType()
 
 		anyInvalid := leftIsInvalid || rightIsInvalid
 
+		var resultType Type
+
 		switch operationKind {
 		case BinaryOperationKindArithmetic,
 			BinaryOperationKindNonEqualityComparison,
 			BinaryOperationKindBitwise:
 
-			return checker.checkBinaryExpressionArithmeticOrNonEqualityComparisonOrBitwise(
+			resultType = checker.checkBinaryExpressionArithmeticOrNonEqualityComparisonOrBitwise(
 				expression, operation, operationKind,
 				leftType, rightType,
 				leftIsInvalid, rightIsInvalid, anyInvalid,
 			)
 
 		case BinaryOperationKindEquality:
-			return checker.checkBinaryExpressionEquality(
+			resultType = checker.checkBinaryExpressionEquality(
+				expression, operation, operationKind,
+				leftType, rightType,
+				leftIsInvalid, rightIsInvalid, anyInvalid,
+			)
+
+		case BinaryOperationKindRange:
+			resultType = checker.checkBinaryExpressionRange(
 				expression, operation, operationKind,
 				leftType, rightType,
 				leftIsInvalid, rightIsInvalid, anyInvalid,
 			)
 
+			checker.Elaboration.BinaryExpressionResultTypes[expression] = resultType
+
+			return resultType
+
 		default:
 			return unsupportedOperation()
 		}
 
+		checker.Elaboration.BinaryExpressionResultTypes[expression] = resultType
+
+		if !anyInvalid {
+			checker.foldBinaryExpression(expression)
+		}
+
+		return resultType
+
 	case BinaryOperationKindBooleanLogic,
 		BinaryOperationKindNilCoalescing:
 
@@ -146,7 +215,7 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 		// That means that resource invalidation and returns
 		// are not definite, but only potential.
 
-		rightType := checker.checkPotentiallyUnevaluated(func() Type {
+		rightType = checker.checkPotentiallyUnevaluated(func() Type {
 			var expectedType Type
 			if !leftIsInvalid {
 				if optionalLeftType, ok := leftType.(*OptionalType); ok {
@@ -162,12 +231,18 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 
 		switch operationKind {
 		case BinaryOperationKindBooleanLogic:
-			return checker.checkBinaryExpressionBooleanLogic(
+			resultType := checker.checkBinaryExpressionBooleanLogic(
 				expression, operation, operationKind,
 				leftType, rightType,
 				leftIsInvalid, rightIsInvalid, anyInvalid,
 			)
 
+			if !anyInvalid {
+				checker.foldBinaryExpression(expression)
+			}
+
+			return resultType
+
 		case BinaryOperationKindNilCoalescing:
 			resultType := checker.checkBinaryExpressionNilCoalescing(
 				expression, operation, operationKind,
@@ -205,7 +280,9 @@ func (checker *Checker) checkBinaryExpressionArithmeticOrNonEqualityComparisonOr
 
 		expectedSuperType = NumberType
 
-	case BinaryOperationKindBitwise:
+	case BinaryOperationKindBitwise,
+		BinaryOperationKindRange:
+
 		expectedSuperType = IntegerType
 
 	default:
@@ -280,11 +357,39 @@ func (checker *Checker) checkBinaryExpressionArithmeticOrNonEqualityComparisonOr
 	case BinaryOperationKindNonEqualityComparison:
 		return BoolType
 
+	case BinaryOperationKindRange:
+		return &InclusiveRangeType{
+			MemberType: leftType,
+		}
+
 	default:
 		panic(errors.NewUnreachableError())
 	}
 }
 
+// checkBinaryExpressionRange checks a range expression, e.g. `0...10` or `0..<10`.
+// Both operands must have the same integer type, the result is an inclusive range of that type.
+//
+func (checker *Checker) checkBinaryExpressionRange(
+	expression *ast.BinaryExpression,
+	operation ast.Operation,
+	operationKind BinaryOperationKind,
+	leftType, rightType Type,
+	leftIsInvalid, rightIsInvalid, anyInvalid bool,
+) Type {
+	resultType := checker.checkBinaryExpressionArithmeticOrNonEqualityComparisonOrBitwise(
+		expression, operation, operationKind,
+		leftType, rightType,
+		leftIsInvalid, rightIsInvalid, anyInvalid,
+	)
+
+	if anyInvalid || !IsSameTypeKind(leftType, IntegerType) {
+		return InvalidType
+	}
+
+	return resultType
+}
+
 func (checker *Checker) checkBinaryExpressionEquality(
 	expression *ast.BinaryExpression,
 	operation ast.Operation,
@@ -76,15 +76,28 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 
 	checker.declareCompositeNestedTypes(declaration, kind, true)
 
+	// The values of fields are only valid in composites, not in type requirements
+
+	if kind == ContainerKindComposite {
+		checker.checkFieldValues(declaration.Members.Fields(), compositeType)
+	} else {
+		checker.checkFieldValues(declaration.Members.Fields(), nil)
+	}
+
 	var initializationInfo *InitializationInfo
 
 	if kind == ContainerKindComposite {
 		// The initializer must initialize all members that are fields,
-		// e.g. not composite functions (which are by definition constant and "initialized")
+		// e.g. not composite functions (which are by definition constant and "initialized"),
+		// and not fields which declare a value
 
-		fieldMembers := NewMemberAstFieldDeclarationOrderedMap()
+		fieldMembers := &MemberAstFieldDeclarationOrderedMap{}
 
 		for _, field := range declaration.Members.Fields() {
+			if field.Value != nil {
+				continue
+			}
+
 			fieldName := field.Identifier.Identifier
 			member, ok := compositeType.Members.Get(fieldName)
 			if !ok {
@@ -95,6 +108,11 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 		}
 
 		initializationInfo = NewInitializationInfo(compositeType, fieldMembers)
+		initializationInfo.InitializingFunctions = checker.initializingFunctions(
+			declaration.Members.Functions(),
+			compositeType,
+			fieldMembers,
+		)
 	}
 
 	checker.checkInitializers(
@@ -116,6 +134,7 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 			declaration.Members.Functions(),
 			compositeType,
 			declaration.DocString,
+			initializationInfo,
 		)
 
 	case ContainerKindInterface:
@@ -197,6 +216,10 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 	for _, nestedComposite := range declaration.Members.Composites() {
 		nestedComposite.Accept(checker)
 	}
+
+	for _, nestedEntitlement := range declaration.Members.Entitlements() {
+		nestedEntitlement.Accept(checker)
+	}
 }
 
 // declareCompositeNestedTypes declares the types nested in a composite,
@@ -290,10 +313,12 @@ func (checker *Checker) declareNestedDeclarations(
 	containerDeclarationKind common.DeclarationKind,
 	nestedCompositeDeclarations []*ast.CompositeDeclaration,
 	nestedInterfaceDeclarations []*ast.InterfaceDeclaration,
+	nestedEntitlementDeclarations []*ast.EntitlementDeclaration,
 ) (
 	nestedDeclarations map[string]ast.Declaration,
 	nestedInterfaceTypes []*InterfaceType,
 	nestedCompositeTypes []*CompositeType,
+	nestedEntitlementTypes []*EntitlementType,
 ) {
 	nestedDeclarations = map[string]ast.Declaration{}
 
@@ -327,6 +352,15 @@ func (checker *Checker) declareNestedDeclarations(
 				firstNestedInterfaceDeclaration.DeclarationKind(),
 				firstNestedInterfaceDeclaration.Identifier,
 			)
+
+		} else if len(nestedEntitlementDeclarations) > 0 {
+
+			firstNestedEntitlementDeclaration := nestedEntitlementDeclarations[0]
+
+			reportInvalidNesting(
+				firstNestedEntitlementDeclaration.DeclarationKind(),
+				firstNestedEntitlementDeclaration.Identifier,
+			)
 		}
 
 		// NOTE: don't return, so nested declarations / types are still declared
@@ -400,6 +434,17 @@ func (checker *Checker) declareNestedDeclarations(
 		nestedCompositeTypes = append(nestedCompositeTypes, nestedCompositeType)
 	}
 
+	// Declare nested entitlements
+
+	for _, nestedDeclaration := range nestedEntitlementDeclarations {
+		if _, exists := nestedDeclarations[nestedDeclaration.Identifier.Identifier]; !exists {
+			nestedDeclarations[nestedDeclaration.Identifier.Identifier] = nestedDeclaration
+		}
+
+		nestedEntitlementType := checker.declareEntitlementType(nestedDeclaration)
+		nestedEntitlementTypes = append(nestedEntitlementTypes, nestedEntitlementType)
+	}
+
 	return
 }
 
@@ -420,8 +465,8 @@ func (checker *Checker) declareCompositeType(declaration *ast.CompositeDeclarati
 		Location:    checker.Location,
 		Kind:        declaration.CompositeKind,
 		Identifier:  identifier.Identifier,
-		nestedTypes: NewStringTypeOrderedMap(),
-		Members:     NewStringMemberOrderedMap(),
+		nestedTypes: &StringTypeOrderedMap{},
+		Members:     &StringMemberOrderedMap{},
 	}
 
 	variable, err := checker.typeActivations.DeclareType(typeDeclaration{
@@ -465,12 +510,13 @@ func (checker *Checker) declareCompositeType(declaration *ast.CompositeDeclarati
 
 	// Check and declare nested types
 
-	nestedDeclarations, nestedInterfaceTypes, nestedCompositeTypes :=
+	nestedDeclarations, nestedInterfaceTypes, nestedCompositeTypes, nestedEntitlementTypes :=
 		checker.declareNestedDeclarations(
 			declaration.CompositeKind,
 			declaration.DeclarationKind(),
 			declaration.Members.Composites(),
 			declaration.Members.Interfaces(),
+			declaration.Members.Entitlements(),
 		)
 
 	checker.Elaboration.CompositeNestedDeclarations[declaration] = nestedDeclarations
@@ -485,6 +531,11 @@ func (checker *Checker) declareCompositeType(declaration *ast.CompositeDeclarati
 		nestedCompositeType.SetContainerType(compositeType)
 	}
 
+	for _, nestedEntitlementType := range nestedEntitlementTypes {
+		compositeType.nestedTypes.Set(nestedEntitlementType.Identifier, nestedEntitlementType)
+		nestedEntitlementType.SetContainerType(compositeType)
+	}
+
 	return compositeType
 }
 
@@ -504,7 +555,7 @@ func (checker *Checker) declareCompositeMembersAndValue(
 		panic(errors.NewUnreachableError())
 	}
 
-	declarationMembers := NewStringMemberOrderedMap()
+	declarationMembers := &StringMemberOrderedMap{}
 
 	(func() {
 		// Activate new scopes for nested types
@@ -637,6 +688,7 @@ func (checker *Checker) declareCompositeMembersAndValue(
 
 		compositeType.Members = members
 		compositeType.Fields = fields
+		checker.Elaboration.CompositeTypeMemberSlots[compositeType] = newMemberSlots(compositeType)
 		if checker.positionInfoEnabled {
 			checker.memberOrigins[compositeType] = origins
 		}
@@ -783,6 +835,16 @@ func (checker *Checker) declareEnumConstructor(
 		}
 	}
 
+	// Declare the field containing all enum cases,
+	// unless there is an enum case with the same name
+
+	if _, ok := constructorType.Members.Get(EnumAllCasesFieldName); !ok {
+		constructorType.Members.Set(
+			EnumAllCasesFieldName,
+			EnumAllCasesMember(constructorType, compositeType),
+		)
+	}
+
 	if checker.positionInfoEnabled {
 		checker.memberOrigins[constructorType] = constructorOrigins
 	}
@@ -815,7 +877,7 @@ func EnumConstructorType(compositeType *CompositeType) *FunctionType {
 				Type: compositeType,
 			},
 		),
-		Members: NewStringMemberOrderedMap(),
+		Members: &StringMemberOrderedMap{},
 	}
 }
 
@@ -1171,6 +1233,15 @@ func (checker *Checker) memberSatisfied(compositeMember, interfaceMember *Member
 		return false
 	}
 
+	// Check entitlements:
+	// The composite member may not require more entitlements than the interface member,
+	// otherwise the entitlements could be circumvented by accessing the member
+	// through a reference to the interface
+
+	if !entitlementSetIncludes(interfaceMember.Entitlements, compositeMember.Entitlements) {
+		return false
+	}
+
 	// Check access
 
 	effectiveInterfaceMemberAccess := checker.effectiveInterfaceMemberAccess(interfaceMember.Access)
@@ -1353,7 +1424,7 @@ func (checker *Checker) defaultMembersAndOrigins(
 	requireNonPrivateMemberAccess := containerKind == ContainerKindInterface
 
 	memberCount := len(fields) + len(functions)
-	members = NewStringMemberOrderedMap()
+	members = &StringMemberOrderedMap{}
 	if checker.positionInfoEnabled {
 		origins = make(map[string]*Origin, memberCount)
 	}
@@ -1395,7 +1466,12 @@ func (checker *Checker) defaultMembersAndOrigins(
 			continue
 		}
 
-		identifier := field.Identifier.Identifier
+		// Member names are retained by types and values, so intern them
+		identifier := checker.interner.Intern(field.Identifier.Identifier)
+		memberIdentifier := ast.Identifier{
+			Identifier: identifier,
+			Pos:        field.Identifier.Pos,
+		}
 
 		fieldNames = append(fieldNames, identifier)
 
@@ -1419,12 +1495,15 @@ func (checker *Checker) defaultMembersAndOrigins(
 			)
 		}
 
+		entitlements := checker.convertEntitlements(field.Entitlements)
+
 		members.Set(
 			identifier,
 			&Member{
 				ContainerType:   containerType,
 				Access:          field.Access,
-				Identifier:      field.Identifier,
+				Entitlements:    entitlements,
+				Identifier:      memberIdentifier,
 				DeclarationKind: declarationKind,
 				TypeAnnotation:  fieldTypeAnnotation,
 				VariableKind:    field.VariableKind,
@@ -1458,7 +1537,11 @@ func (checker *Checker) defaultMembersAndOrigins(
 			continue
 		}
 
-		identifier := function.Identifier.Identifier
+		identifier := checker.interner.Intern(function.Identifier.Identifier)
+		memberIdentifier := ast.Identifier{
+			Identifier: identifier,
+			Pos:        function.Identifier.Pos,
+		}
 
 		functionType := checker.functionType(function.ParameterList, function.ReturnTypeAnnotation)
 
@@ -1483,12 +1566,15 @@ func (checker *Checker) defaultMembersAndOrigins(
 			)
 		}
 
+		entitlements := checker.convertEntitlements(function.Entitlements)
+
 		members.Set(
 			identifier,
 			&Member{
 				ContainerType:   containerType,
 				Access:          function.Access,
-				Identifier:      function.Identifier,
+				Entitlements:    entitlements,
+				Identifier:      memberIdentifier,
 				DeclarationKind: declarationKind,
 				TypeAnnotation:  fieldTypeAnnotation,
 				VariableKind:    ast.VariableKindConstant,
@@ -1515,7 +1601,7 @@ func (checker *Checker) eventMembersAndOrigins(
 ) {
 	parameters := initializer.FunctionDeclaration.ParameterList.Parameters
 
-	members = NewStringMemberOrderedMap()
+	members = &StringMemberOrderedMap{}
 	if checker.positionInfoEnabled {
 		origins = make(map[string]*Origin, len(parameters))
 	}
@@ -1556,6 +1642,25 @@ const enumRawValueFieldDocString = `
 The raw value of the enum case
 `
 
+const EnumAllCasesFieldName = "allCases"
+const enumAllCasesFieldDocString = `
+An array containing all cases of the enum, in declaration order
+`
+
+// EnumAllCasesMember returns the member of an enum constructor
+// which contains all cases of the given enum type.
+//
+func EnumAllCasesMember(constructorType Type, enumType *CompositeType) *Member {
+	return NewPublicConstantFieldMember(
+		constructorType,
+		EnumAllCasesFieldName,
+		&VariableSizedType{
+			Type: enumType,
+		},
+		enumAllCasesFieldDocString,
+	)
+}
+
 func (checker *Checker) enumMembersAndOrigins(
 	allMembers *ast.Members,
 	containerType *CompositeType,
@@ -1598,7 +1703,7 @@ func (checker *Checker) enumMembersAndOrigins(
 	// Each individual enum case is an instance of the enum type,
 	// so only has a single member, the raw value field
 
-	members = NewStringMemberOrderedMap()
+	members = &StringMemberOrderedMap{}
 	members.Set(
 		EnumRawValueFieldName,
 		&Member{
@@ -1682,17 +1787,28 @@ func (checker *Checker) checkNoInitializerNoFields(
 	containerKind ContainerKind,
 ) {
 	// If there are no fields, or the container is an interface,
-	// no initializer needs to be declared
+	// no initializer needs to be declared.
+	// Fields which declare a value do not need to be initialized
+
+	if containerKind == ContainerKindInterface {
+		return
+	}
+
+	var firstField *ast.FieldDeclaration
+	for _, field := range fields {
+		if field.Value == nil {
+			firstField = field
+			break
+		}
+	}
 
-	if len(fields) == 0 || containerKind == ContainerKindInterface {
+	if firstField == nil {
 		return
 	}
 
 	// An initializer should be declared but does not exist.
 	// Report an error for the first field
 
-	firstField := fields[0]
-
 	checker.report(
 		&MissingInitializerError{
 			ContainerType:  containerType,
@@ -1772,8 +1888,27 @@ func (checker *Checker) checkCompositeFunctions(
 	functions []*ast.FunctionDeclaration,
 	selfType *CompositeType,
 	selfDocString string,
+	initializationInfo *InitializationInfo,
 ) {
 	for _, function := range functions {
+
+		// Initializing functions are checked like the initializer,
+		// but only need to initialize the fields they declare
+
+		var functionInitializationInfo *InitializationInfo
+		if initializationInfo != nil {
+			functionMember, ok := selfType.Members.Get(function.Identifier.Identifier)
+			if ok {
+				initializedMembers, ok := initializationInfo.InitializingFunctions[functionMember]
+				if ok {
+					functionInitializationInfo = initializingFunctionInitializationInfo(
+						initializationInfo,
+						initializedMembers,
+					)
+				}
+			}
+		}
+
 		// NOTE: new activation, as function declarations
 		// shouldn't be visible in other function declarations,
 		// and `self` is is only visible inside function
@@ -1787,9 +1922,10 @@ func (checker *Checker) checkCompositeFunctions(
 			checker.visitFunctionDeclaration(
 				function,
 				functionDeclarationOptions{
-					mustExit:          true,
-					declareFunction:   false,
-					checkResourceLoss: true,
+					mustExit:           true,
+					declareFunction:    false,
+					checkResourceLoss:  true,
+					initializationInfo: functionInitializationInfo,
 				},
 			)
 		}()
@@ -28,16 +28,25 @@ func (checker *Checker) VisitIfStatement(statement *ast.IfStatement) ast.Repr {
 	thenElement := statement.Then
 
 	var elseElement ast.Element = ast.NotAnElement{}
+
+	// If there is no else-branch, the path which skips the then-branch
+	// is represented by the whole statement
+
+	elseRange := ast.NewRangeFromPositioned(statement)
 	if statement.Else != nil {
 		elseElement = statement.Else
+		elseRange = ast.NewRangeFromPositioned(statement.Else)
 	}
 
+	thenRange := ast.NewRangeFromPositioned(thenElement)
+
 	switch test := statement.Test.(type) {
 	case ast.Expression:
-		checker.visitConditional(test, thenElement, elseElement)
+		checker.visitConditional(test, thenElement, thenRange, elseElement, elseRange)
 
 	case *ast.VariableDeclaration:
 		checker.checkConditionalBranches(
+			thenRange,
 			func() Type {
 				checker.enterValueScope()
 				defer checker.leaveValueScope(thenElement.EndPosition, true)
@@ -47,6 +56,7 @@ func (checker *Checker) VisitIfStatement(statement *ast.IfStatement) ast.Repr {
 
 				return nil
 			},
+			elseRange,
 			func() Type {
 				elseElement.Accept(checker)
 				return nil
@@ -66,14 +76,58 @@ func (checker *Checker) VisitConditionalExpression(expression *ast.ConditionalEx
 
 	checker.VisitExpression(expression.Test, BoolType)
 
-	thenType, elseType := checker.checkConditionalBranches(
-		func() Type {
-			return checker.VisitExpression(expression.Then, expectedType)
-		},
-		func() Type {
-			return checker.VisitExpression(expression.Else, expectedType)
-		},
-	)
+	var thenType, elseType Type
+
+	thenRange := ast.NewRangeFromPositioned(expression.Then)
+	elseRange := ast.NewRangeFromPositioned(expression.Else)
+
+	switch {
+	case expectedType == nil && isTypeInferredFromOther(expression.Then, expression.Else):
+		// If there is no contextually expected type,
+		// and the then-branch is a literal which gets its type from the context,
+		// e.g. in `cond ? 1 : x` or `cond ? [] : [1]`, then check the else-branch first,
+		// and infer the type of the then-branch from it.
+
+		elseType, thenType = checker.checkConditionalBranches(
+			elseRange,
+			func() Type {
+				elseType = checker.VisitExpression(expression.Else, nil)
+				return elseType
+			},
+			thenRange,
+			func() Type {
+				return checker.VisitExpressionWithForceType(expression.Then, elseType, false)
+			},
+		)
+
+	case expectedType == nil && isTypeInferredFromOther(expression.Else, expression.Then):
+		// Likewise, if the else-branch is such a literal, e.g. in `cond ? x : 1`,
+		// then infer the type of the else-branch from the then-branch.
+
+		thenType, elseType = checker.checkConditionalBranches(
+			thenRange,
+			func() Type {
+				thenType = checker.VisitExpression(expression.Then, nil)
+				return thenType
+			},
+			elseRange,
+			func() Type {
+				return checker.VisitExpressionWithForceType(expression.Else, thenType, false)
+			},
+		)
+
+	default:
+		thenType, elseType = checker.checkConditionalBranches(
+			thenRange,
+			func() Type {
+				return checker.VisitExpression(expression.Then, expectedType)
+			},
+			elseRange,
+			func() Type {
+				return checker.VisitExpression(expression.Else, expectedType)
+			},
+		)
+	}
 
 	if thenType == nil || elseType == nil {
 		panic(errors.NewUnreachableError())
@@ -93,11 +147,14 @@ func (checker *Checker) VisitConditionalExpression(expression *ast.ConditionalEx
 // visitConditional checks a conditional.
 // The test expression must be a boolean.
 // The "then" and "else" elements may be expressions, in which case their types are returned.
+// The ranges of the elements are the ranges of the branches.
 //
 func (checker *Checker) visitConditional(
 	test ast.Expression,
 	thenElement ast.Element,
+	thenRange ast.Range,
 	elseElement ast.Element,
+	elseRange ast.Range,
 ) (
 	thenType, elseType Type,
 ) {
@@ -105,6 +162,7 @@ func (checker *Checker) visitConditional(
 	checker.VisitExpression(test, BoolType)
 
 	return checker.checkConditionalBranches(
+		thenRange,
 		func() Type {
 			thenResult, ok := thenElement.Accept(checker).(Type)
 			if !ok || thenResult == nil {
@@ -112,6 +170,7 @@ func (checker *Checker) visitConditional(
 			}
 			return thenResult
 		},
+		elseRange,
 		func() Type {
 			elseResult, ok := elseElement.Accept(checker).(Type)
 			if !ok || elseResult == nil {
@@ -127,8 +186,13 @@ func (checker *Checker) visitConditional(
 // resource uses and invalidations, as well as field initializations,
 // are only potential in each branch, but definite if they occur in both branches.
 //
+// The ranges of the branches are used to report the branches
+// in which fields are not initialized.
+//
 func (checker *Checker) checkConditionalBranches(
+	thenRange ast.Range,
 	checkThen TypeCheckFunc,
+	elseRange ast.Range,
 	checkElse TypeCheckFunc,
 ) (
 	thenType, elseType Type,
@@ -139,12 +203,13 @@ func (checker *Checker) checkConditionalBranches(
 	thenReturnInfo := initialReturnInfo.Clone()
 	elseReturnInfo := initialReturnInfo.Clone()
 
-	var thenInitializedMembers *MemberSet
-	var elseInitializedMembers *MemberSet
-	if functionActivation.InitializationInfo != nil {
-		initialInitializedMembers := functionActivation.InitializationInfo.InitializedFieldMembers
-		thenInitializedMembers = initialInitializedMembers.Clone()
-		elseInitializedMembers = initialInitializedMembers.Clone()
+	initializationInfo := functionActivation.InitializationInfo
+
+	var thenInitializations *fieldInitializations
+	var elseInitializations *fieldInitializations
+	if initializationInfo != nil {
+		thenInitializations = initializationInfo.branch()
+		elseInitializations = initializationInfo.branch()
 	}
 
 	initialResources := checker.resources
@@ -154,30 +219,36 @@ func (checker *Checker) checkConditionalBranches(
 	thenType = checker.checkBranch(
 		checkThen,
 		thenReturnInfo,
-		thenInitializedMembers,
+		thenInitializations,
 		thenResources,
 	)
 
 	elseType = checker.checkBranch(
 		checkElse,
 		elseReturnInfo,
-		elseInitializedMembers,
+		elseInitializations,
 		elseResources,
 	)
 
 	functionActivation.ReturnInfo.MergeBranches(thenReturnInfo, elseReturnInfo)
 
-	if functionActivation.InitializationInfo != nil {
+	if initializationInfo != nil {
 
 		// If one side definitely halted, the initializations in the other side can be considered definite
 
 		if thenReturnInfo.DefinitelyHalted {
-			functionActivation.InitializationInfo.InitializedFieldMembers = elseInitializedMembers
+			initializationInfo.InitializedFieldMembers = elseInitializations.initializedMembers
+			initializationInfo.UninitializedFieldBranches = elseInitializations.uninitializedBranches
 		} else if elseReturnInfo.DefinitelyHalted {
-			functionActivation.InitializationInfo.InitializedFieldMembers = thenInitializedMembers
+			initializationInfo.InitializedFieldMembers = thenInitializations.initializedMembers
+			initializationInfo.UninitializedFieldBranches = thenInitializations.uninitializedBranches
 		} else {
-			functionActivation.InitializationInfo.InitializedFieldMembers =
-				thenInitializedMembers.Intersection(elseInitializedMembers)
+			initializationInfo.mergeBranches(
+				thenInitializations,
+				thenRange,
+				elseInitializations,
+				elseRange,
+			)
 		}
 	}
 
@@ -193,7 +264,7 @@ func (checker *Checker) checkConditionalBranches(
 func (checker *Checker) checkBranch(
 	check TypeCheckFunc,
 	temporaryReturnInfo *ReturnInfo,
-	temporaryInitializedMembers *MemberSet,
+	temporaryInitializations *fieldInitializations,
 	temporaryResources *Resources,
 ) Type {
 	return wrapTypeCheck(check,
@@ -201,7 +272,7 @@ func (checker *Checker) checkBranch(
 			return checker.checkWithResources(f, temporaryResources)
 		},
 		func(f TypeCheckFunc) Type {
-			return checker.checkWithInitializedMembers(f, temporaryInitializedMembers)
+			return checker.checkWithInitializedMembers(f, temporaryInitializations)
 		},
 		func(f TypeCheckFunc) Type {
 			return checker.checkWithReturnInfo(f, temporaryReturnInfo)
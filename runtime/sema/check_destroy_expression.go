@@ -25,6 +25,8 @@ import (
 func (checker *Checker) VisitDestroyExpression(expression *ast.DestroyExpression) (resultType ast.Repr) {
 	resultType = VoidType
 
+	checker.setOccurrenceRole(expression.Expression, OccurrenceRoleDestroy)
+
 	valueType := checker.VisitExpression(expression.Expression, nil)
 
 	checker.recordResourceInvalidation(
@@ -103,15 +103,28 @@ func (checker *Checker) VisitDictionaryExpression(expression *ast.DictionaryExpr
 }
 
 func IsValidDictionaryKeyType(keyType Type) bool {
+	return isValidDictionaryKeyType(keyType, nil)
+}
+
+func isValidDictionaryKeyType(keyType Type, visitedStructTypes map[*CompositeType]struct{}) bool {
 	// TODO: implement support for more built-in types here and in interpreter
 	switch keyType := keyType.(type) {
 	case *AddressType:
 		return true
 	case *CompositeType:
-		return keyType.Kind == common.CompositeKindEnum
+		switch keyType.Kind {
+		case common.CompositeKindEnum:
+			return true
+		case common.CompositeKindStructure:
+			return isHashableStructType(keyType, visitedStructTypes)
+		default:
+			return false
+		}
 	default:
 		switch keyType {
-		case NeverType, BoolType, CharacterType, StringType, MetaType:
+		case NeverType, BoolType, CharacterType, StringType, MetaType,
+			TimestampType, DurationType:
+
 			return true
 		default:
 			return IsSameTypeKind(keyType, NumberType) ||
@@ -119,3 +132,34 @@ func IsValidDictionaryKeyType(keyType Type) bool {
 		}
 	}
 }
+
+// isHashableStructType returns true if the given structure type is hashable,
+// i.e. if it is user-defined, and the types of all its fields are valid dictionary key types.
+//
+// Built-in structures are not hashable, as they may have state which is not stored in fields
+//
+func isHashableStructType(structType *CompositeType, visitedStructTypes map[*CompositeType]struct{}) bool {
+	if structType.Location == nil {
+		return false
+	}
+
+	// A structure cannot (transitively) contain itself
+	if _, ok := visitedStructTypes[structType]; ok {
+		return false
+	}
+
+	if visitedStructTypes == nil {
+		visitedStructTypes = map[*CompositeType]struct{}{}
+	}
+	visitedStructTypes[structType] = struct{}{}
+	defer delete(visitedStructTypes, structType)
+
+	for _, fieldName := range structType.Fields {
+		member, ok := structType.Members.Get(fieldName)
+		if !ok || !isValidDictionaryKeyType(member.TypeAnnotation.Type, visitedStructTypes) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,156 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// VisitEntitlementDeclaration checks the entitlement declaration.
+//
+// NOTE: This function assumes that the entitlement type was previously declared using
+// `declareEntitlementType` and exists in `checker.Elaboration.EntitlementDeclarationTypes`.
+//
+func (checker *Checker) VisitEntitlementDeclaration(declaration *ast.EntitlementDeclaration) ast.Repr {
+
+	entitlementType := checker.Elaboration.EntitlementDeclarationTypes[declaration]
+	if entitlementType == nil {
+		panic(errors.NewUnreachableError())
+	}
+
+	checker.checkDeclarationAccessModifier(
+		declaration.Access,
+		declaration.DeclarationKind(),
+		declaration.StartPos,
+		true,
+	)
+
+	return nil
+}
+
+// declareEntitlementType declares the type for the given entitlement declaration
+// and records it in the elaboration.
+//
+func (checker *Checker) declareEntitlementType(declaration *ast.EntitlementDeclaration) *EntitlementType {
+
+	identifier := declaration.Identifier
+
+	entitlementType := &EntitlementType{
+		Location:   checker.Location,
+		Identifier: identifier.Identifier,
+	}
+
+	variable, err := checker.typeActivations.DeclareType(typeDeclaration{
+		identifier:               identifier,
+		ty:                       entitlementType,
+		declarationKind:          declaration.DeclarationKind(),
+		access:                   declaration.Access,
+		docString:                declaration.DocString,
+		allowOuterScopeShadowing: false,
+	})
+	checker.report(err)
+	checker.recordVariableDeclarationOccurrence(
+		identifier.Identifier,
+		variable,
+	)
+
+	checker.Elaboration.EntitlementDeclarationTypes[declaration] = entitlementType
+
+	return entitlementType
+}
+
+// convertEntitlements converts the given nominal types to entitlement types.
+// Types which are not entitlements are reported and ignored.
+//
+// The result is a canonical entitlement set, see `NewEntitlementSet`.
+//
+func (checker *Checker) convertEntitlements(nominalTypes []*ast.NominalType) []*EntitlementType {
+	if len(nominalTypes) == 0 {
+		return nil
+	}
+
+	entitlements := make([]*EntitlementType, 0, len(nominalTypes))
+
+	for _, nominalType := range nominalTypes {
+		ty := checker.convertNominalType(nominalType)
+
+		entitlementType, ok := ty.(*EntitlementType)
+		if !ok {
+			if !ty.IsInvalidType() {
+				checker.report(
+					&InvalidEntitlementTypeError{
+						Type:  ty,
+						Range: ast.NewRangeFromPositioned(nominalType),
+					},
+				)
+			}
+
+			// NOTE: ignore this invalid type
+			// and do not add it to the entitlements result
+			continue
+		}
+
+		entitlements = append(entitlements, entitlementType)
+	}
+
+	return NewEntitlementSet(entitlements)
+}
+
+// checkMemberEntitlements checks that the given member may be accessed
+// through a value of the given type:
+// If the value is a reference, it must have been granted all entitlements
+// which the member requires.
+//
+// Accessing a member directly, i.e. not through a reference, is always allowed.
+//
+// The accessed type may be optional, in case the member is accessed using optional chaining.
+//
+func (checker *Checker) checkMemberEntitlements(
+	accessedType Type,
+	member *Member,
+	memberRange ast.Range,
+) {
+	if len(member.Entitlements) == 0 {
+		return
+	}
+
+	if optionalType, ok := accessedType.(*OptionalType); ok {
+		accessedType = optionalType.Type
+	}
+
+	referenceType, ok := accessedType.(*ReferenceType)
+	if !ok {
+		return
+	}
+
+	if referenceType.HasEntitlements(member.Entitlements) {
+		return
+	}
+
+	checker.report(
+		&InsufficientEntitlementsError{
+			Name:                 member.Identifier.Identifier,
+			DeclarationKind:      member.DeclarationKind,
+			RequiredEntitlements: member.Entitlements,
+			ReferenceType:        referenceType,
+			Range:                memberRange,
+		},
+	)
+}
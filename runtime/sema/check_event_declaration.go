@@ -49,9 +49,45 @@ func (checker *Checker) checkEventParameters(
 				},
 			)
 		}
+
+		// Indexed parameters must have a type which can be used as a key of an index
+
+		if parameter.Indexed &&
+			!parameterType.IsInvalidType() &&
+			!IsValidEventIndexType(parameterType) {
+
+			checker.report(
+				&InvalidIndexedEventParameterTypeError{
+					Type: parameterType,
+					Range: ast.Range{
+						StartPos: parameter.StartPos,
+						EndPos:   parameter.TypeAnnotation.EndPosition(),
+					},
+				},
+			)
+		}
 	}
 }
 
+// IsValidEventIndexType returns true if the given type is a valid type of an indexed event parameter.
+//
+// Indexed event parameters must have a simple type which can be used as a key of an index,
+// or an optional of such a type.
+//
+func IsValidEventIndexType(t Type) bool {
+	if optionalType, ok := t.(*OptionalType); ok {
+		t = optionalType.Type
+	}
+
+	if compositeType, ok := t.(*CompositeType); ok &&
+		compositeType.Kind == common.CompositeKindStructure {
+
+		return false
+	}
+
+	return IsValidDictionaryKeyType(t)
+}
+
 // IsValidEventParameterType returns true if the given type is a valid event parameter type.
 //
 // Events currently only support a few simple Cadence types.
@@ -92,7 +128,9 @@ func IsValidEventParameterType(t Type, results map[*Member]bool) bool {
 
 	default:
 		switch t {
-		case MetaType, BoolType, CharacterType, StringType:
+		case MetaType, BoolType, CharacterType, StringType,
+			TimestampType, DurationType:
+
 			return true
 		}
 
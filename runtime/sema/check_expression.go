@@ -38,8 +38,12 @@ func (checker *Checker) VisitIdentifierExpression(expression *ast.IdentifierExpr
 		checker.resources.AddUse(variable, identifier.Pos)
 	}
 
+	checker.checkReferenceValidity(variable, expression)
+
 	checker.checkSelfVariableUseInInitializer(variable, identifier.Pos)
 
+	checker.checkDeprecation(variable.Identifier, variable.DocString, expression)
+
 	if checker.inInvocation {
 		checker.Elaboration.IdentifierInInvocationTypes[expression] = valueType
 	}
@@ -95,10 +99,13 @@ func (checker *Checker) checkSelfVariableUseInInitializer(variable *Variable, po
 		if accessedSelfMember == nil || !accessedSelfMember.Predeclared {
 
 			// If the member access is to a non-field, e.g. a function,
-			// *all* fields must have been initialized
+			// *all* fields must have been initialized.
+			// Initializing functions may be called before,
+			// their use is checked in `checkInitializingFunctionUse`
 
 			field, _ := initializationInfo.FieldMembers.Get(accessedSelfMember)
-			if field == nil {
+			_, isInitializingFunction := initializationInfo.InitializingFunctions[accessedSelfMember]
+			if field == nil && !isInitializingFunction {
 				checkInitializationComplete()
 			}
 		}
@@ -226,6 +233,75 @@ func (checker *Checker) VisitStringExpression(expression *ast.StringExpression)
 	return StringType
 }
 
+// isContextuallyTypedLiteral returns true if the type of the given expression
+// is inferred from the contextually expected type, if any.
+// This is the case for number literals, and for array and dictionary literals
+// which only consist of such literals, including empty ones.
+//
+func isContextuallyTypedLiteral(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.IntegerExpression, *ast.FixedPointExpression:
+		return true
+
+	case *ast.ArrayExpression:
+		for _, value := range expression.Values {
+			if !isContextuallyTypedLiteral(value) {
+				return false
+			}
+		}
+		return true
+
+	case *ast.DictionaryExpression:
+		for _, entry := range expression.Entries {
+			if !isContextuallyTypedLiteral(entry.Key) ||
+				!isContextuallyTypedLiteral(entry.Value) {
+
+				return false
+			}
+		}
+		return true
+
+	default:
+		return false
+	}
+}
+
+// isEmptyCollectionLiteral returns true if the given expression
+// is an empty array literal or an empty dictionary literal.
+//
+func isEmptyCollectionLiteral(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.ArrayExpression:
+		return len(expression.Values) == 0
+
+	case *ast.DictionaryExpression:
+		return len(expression.Entries) == 0
+
+	default:
+		return false
+	}
+}
+
+// isTypeInferredFromOther returns true if the type of the given expression
+// should be inferred from the type of the other expression,
+// e.g. the other operand of a binary expression, or the other branch of a conditional expression.
+//
+// This is the case if the expression is a contextually typed literal, and the other is not,
+// or if the expression is an empty collection literal, and the other is not.
+//
+func isTypeInferredFromOther(expression ast.Expression, other ast.Expression) bool {
+	if !isContextuallyTypedLiteral(expression) {
+		return false
+	}
+
+	if !isContextuallyTypedLiteral(other) {
+		return true
+	}
+
+	return isEmptyCollectionLiteral(expression) &&
+		!isEmptyCollectionLiteral(other)
+}
+
 func (checker *Checker) VisitIndexExpression(expression *ast.IndexExpression) ast.Repr {
 	return checker.visitIndexExpression(expression, false)
 }
@@ -274,6 +350,10 @@ func (checker *Checker) visitIndexExpression(
 		isAssignment,
 	)
 
+	if arrayType, ok := indexedType.(*ConstantSizedType); ok {
+		checker.checkIndexBounds(arrayType, indexExpression.IndexingExpression)
+	}
+
 	if isAssignment && !indexedType.AllowsValueIndexingAssignment() {
 		checker.report(
 			&NotIndexingAssignableTypeError{
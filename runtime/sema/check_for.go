@@ -30,7 +30,7 @@ func (checker *Checker) VisitForStatement(statement *ast.ForStatement) ast.Repr
 
 	valueExpression := statement.Value
 
-	// iterations are only supported for non-resource arrays.
+	// iterations are only supported for non-resource values.
 	// Hence, if the array is empty and no context type is available,
 	// then default it to [AnyStruct].
 	var expectedType Type
@@ -58,12 +58,12 @@ func (checker *Checker) VisitForStatement(statement *ast.ForStatement) ast.Repr
 					Range: ast.NewRangeFromPositioned(valueExpression),
 				},
 			)
-		} else if arrayType, ok := valueType.(ArrayType); ok {
-			elementType = arrayType.ElementType(false)
+		} else if iteratedElementType := IteratedElementType(valueType); iteratedElementType != nil {
+			elementType = iteratedElementType
 		} else {
 			checker.report(
 				&TypeMismatchWithDescriptionError{
-					ExpectedTypeDescription: "array",
+					ExpectedTypeDescription: "array, dictionary, range, or iterator",
 					ActualType:              valueType,
 					Range:                   ast.NewRangeFromPositioned(valueExpression),
 				},
@@ -83,6 +83,7 @@ func (checker *Checker) VisitForStatement(statement *ast.ForStatement) ast.Repr
 		allowOuterScopeShadowing: false,
 	})
 	checker.report(err)
+	checker.recordLoopVariableInterval(variable, valueExpression)
 	if checker.positionInfoEnabled {
 		checker.recordVariableDeclarationOccurrence(identifier, variable)
 	}
@@ -104,20 +105,83 @@ func (checker *Checker) VisitForStatement(statement *ast.ForStatement) ast.Repr
 		}
 	}
 
-	// The body of the loop will maybe be evaluated.
-	// That means that resource invalidations and
-	// returns are not definite, but only potential.
+	checker.checkLoop(statement, func() {
+		statement.Block.Accept(checker)
+	})
 
-	_ = checker.checkPotentiallyUnevaluated(func() Type {
-		checker.functionActivations.WithLoop(func() {
-			statement.Block.Accept(checker)
-		})
+	return nil
+}
 
-		// ignored
-		return nil
-	})
+const IteratorNextFunctionName = "next"
+
+// IteratedElementType returns the type of the elements
+// produced when iterating over a value of the given type in a for-in loop,
+// or nil if the type is not iterable.
+//
+// The following types are iterable:
+// - Arrays, which produce their elements
+// - Dictionaries, which produce their keys
+// - Ranges, which produce their integers
+// - Structures which conform to the iterator protocol,
+//   i.e. which have a public function `fun next(): T?`.
+//   The function is called until it returns nil.
+//
+func IteratedElementType(ty Type) Type {
+	switch ty := ty.(type) {
+	case ArrayType:
+		return ty.ElementType(false)
+
+	case *DictionaryType:
+		return ty.KeyType
+
+	case *InclusiveRangeType:
+		if ty.MemberType == nil {
+			return IntegerType
+		}
+		return ty.MemberType
 
-	checker.reportResourceUsesInLoop(statement.StartPos, statement.EndPosition())
+	case *CompositeType:
+		if ty.Kind != common.CompositeKindStructure {
+			return nil
+		}
+
+		return iteratorElementType(ty)
+	}
 
 	return nil
 }
+
+// iteratorElementType returns the element type of the given iterator type,
+// i.e. `T` if the type has a function `fun next(): T?`,
+// or nil if the type does not conform to the iterator protocol.
+//
+func iteratorElementType(ty *CompositeType) Type {
+	member, ok := ty.Members.Get(IteratorNextFunctionName)
+	if !ok || member.DeclarationKind != common.DeclarationKindFunction {
+		return nil
+	}
+
+	// The loop calls the function on behalf of the iterating code,
+	// so the function must be publicly accessible
+
+	switch member.Access {
+	case ast.AccessPrivate, ast.AccessContract, ast.AccessAccount:
+		return nil
+	}
+
+	functionType, ok := member.TypeAnnotation.Type.(*FunctionType)
+	if !ok ||
+		len(functionType.TypeParameters) > 0 ||
+		len(functionType.Parameters) > 0 ||
+		functionType.ReturnTypeAnnotation == nil {
+
+		return nil
+	}
+
+	optionalType, ok := functionType.ReturnTypeAnnotation.Type.(*OptionalType)
+	if !ok {
+		return nil
+	}
+
+	return optionalType.Type
+}
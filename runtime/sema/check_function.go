@@ -47,6 +47,9 @@ type functionDeclarationOptions struct {
 	// checkResourceLoss if the function should be checked for resource loss.
 	// For example, function declarations in interfaces should not be checked.
 	checkResourceLoss bool
+	// initializationInfo is the initialization info of the function,
+	// if it is an initializing function of a composite
+	initializationInfo *InitializationInfo
 }
 
 func (checker *Checker) visitFunctionDeclaration(
@@ -74,19 +77,61 @@ func (checker *Checker) visitFunctionDeclaration(
 
 	checker.Elaboration.FunctionDeclarationFunctionTypes[declaration] = functionType
 
+	// If the function declaration is unaffected by changes since a previous check,
+	// reuse the result of the previous check instead of checking the function again.
+	// Position info is not reused, so the function must be checked if it is enabled
+
+	if checker.incrementalCheck != nil && !checker.positionInfoEnabled {
+		if result, ok := checker.incrementalCheck.previousResult(declaration); ok {
+			checker.errors = append(checker.errors, result.errors...)
+			checker.hints = append(checker.hints, result.hints...)
+			checker.functionCheckResults[declaration] = result
+			return nil
+		}
+	}
+
+	errorCount := len(checker.errors)
+	hintCount := len(checker.hints)
+
 	checker.checkFunction(
 		declaration.ParameterList,
 		declaration.ReturnTypeAnnotation,
 		functionType,
 		declaration.FunctionBlock,
 		options.mustExit,
-		nil,
+		options.initializationInfo,
 		options.checkResourceLoss,
 	)
 
+	checker.recordFunctionCheckResult(declaration, errorCount, hintCount)
+
 	return nil
 }
 
+// recordFunctionCheckResult records the errors and hints reported
+// while checking the given function declaration,
+// so they can be reused by a later incremental check
+//
+func (checker *Checker) recordFunctionCheckResult(
+	declaration *ast.FunctionDeclaration,
+	errorCount int,
+	hintCount int,
+) {
+	var result functionCheckResult
+
+	if len(checker.errors) > errorCount {
+		result.errors = make([]error, len(checker.errors)-errorCount)
+		copy(result.errors, checker.errors[errorCount:])
+	}
+
+	if len(checker.hints) > hintCount {
+		result.hints = make([]Hint, len(checker.hints)-hintCount)
+		copy(result.hints, checker.hints[hintCount:])
+	}
+
+	checker.functionCheckResults[declaration] = result
+}
+
 func (checker *Checker) declareFunctionDeclaration(
 	declaration *ast.FunctionDeclaration,
 	functionType *FunctionType,
@@ -129,12 +174,15 @@ func (checker *Checker) checkFunction(
 		checker.checkTypeAnnotation(functionType.ReturnTypeAnnotation, returnTypeAnnotation)
 	}
 
-	// Reset the returning state and restore it when leaving
+	// Reset the returning and jumping state and restore it when leaving
 
 	returned := checker.resources.Returns
+	jumped := checker.resources.Jumps
 	checker.resources.Returns = false
+	checker.resources.Jumps = false
 	defer func() {
 		checker.resources.Returns = returned
+		checker.resources.Jumps = jumped
 	}()
 
 	// NOTE: Always declare the function parameters, even if the function body is empty.
@@ -333,7 +381,7 @@ func (checker *Checker) visitWithPostConditions(postConditions *ast.Conditions,
 
 		checker.Elaboration.PostConditionsRewrite[postConditions] = rewriteResult
 
-		checker.visitStatements(rewriteResult.BeforeStatements)
+		checker.visitBeforeStatements(rewriteResult.BeforeStatements)
 	}
 
 	body()
@@ -369,6 +417,77 @@ func (checker *Checker) visitWithPostConditions(postConditions *ast.Conditions,
 	}
 }
 
+// visitBeforeStatements checks the variable declarations
+// which were extracted from the `before` expressions of post-conditions.
+//
+// The declared variables are snapshots of the values before the function body is executed.
+// Resources cannot be copied, and references would observe later mutations,
+// so values containing them cannot be captured
+//
+func (checker *Checker) visitBeforeStatements(statements []ast.Statement) {
+	for _, statement := range statements {
+		declaration, ok := statement.(*ast.VariableDeclaration)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		valueType := checker.VisitExpression(declaration.Value, nil)
+
+		if !valueType.IsInvalidType() &&
+			!IsValidBeforeValueType(valueType) {
+
+			checker.report(
+				&InvalidBeforeValueTypeError{
+					Type:  valueType,
+					Range: ast.NewRangeFromPositioned(declaration.Value),
+				},
+			)
+
+			valueType = InvalidType
+		}
+
+		checker.Elaboration.VariableDeclarationValueTypes[declaration] = valueType
+		checker.Elaboration.VariableDeclarationTargetTypes[declaration] = valueType
+
+		_, err := checker.valueActivations.Declare(variableDeclaration{
+			identifier:               declaration.Identifier.Identifier,
+			ty:                       valueType,
+			kind:                     declaration.DeclarationKind(),
+			pos:                      declaration.Identifier.Pos,
+			isConstant:               true,
+			allowOuterScopeShadowing: true,
+		})
+		checker.report(err)
+	}
+}
+
+// IsValidBeforeValueType returns true if values of the given type
+// can be captured using `before` in post-conditions,
+// i.e. the type is not a resource type and does not contain references
+//
+func IsValidBeforeValueType(ty Type) bool {
+	if ty.IsResourceType() {
+		return false
+	}
+
+	switch ty := ty.(type) {
+	case *ReferenceType:
+		return false
+
+	case *OptionalType:
+		return IsValidBeforeValueType(ty.Type)
+
+	case ArrayType:
+		return IsValidBeforeValueType(ty.ElementType(false))
+
+	case *DictionaryType:
+		return IsValidBeforeValueType(ty.KeyType) &&
+			IsValidBeforeValueType(ty.ValueType)
+	}
+
+	return true
+}
+
 func (checker *Checker) visitFunctionBlock(
 	functionBlock *ast.FunctionBlock,
 	returnTypeAnnotation *TypeAnnotation,
@@ -458,9 +577,10 @@ func (checker *Checker) checkFieldMembersInitialized(info *InitializationInfo) {
 
 		checker.report(
 			&FieldUninitializedError{
-				Name:          field.Identifier.Identifier,
-				Pos:           field.Identifier.Pos,
-				ContainerType: info.ContainerType,
+				Name:                  field.Identifier.Identifier,
+				Pos:                   field.Identifier.Pos,
+				ContainerType:         info.ContainerType,
+				UninitializedBranches: info.UninitializedFieldBranches[member],
 			},
 		)
 	}
@@ -59,12 +59,56 @@ func (checker *Checker) declareImportDeclaration(declaration *ast.ImportDeclarat
 	checker.Elaboration.ImportDeclarationsResolvedLocations[declaration] = resolvedLocations
 
 	for _, resolvedLocation := range resolvedLocations {
-		checker.importResolvedLocation(resolvedLocation, locationRange)
+		checker.importResolvedLocation(resolvedLocation, declaration.Aliases, locationRange)
 	}
 
+	checker.checkUnresolvedImportIdentifiers(declaration, resolvedLocations)
+
 	return nil
 }
 
+// checkUnresolvedImportIdentifiers reports the identifiers of the import declaration
+// which were not resolved to any location, e.g. because the location handler
+// determined that there is no declaration with the identifier at the location.
+//
+func (checker *Checker) checkUnresolvedImportIdentifiers(
+	declaration *ast.ImportDeclaration,
+	resolvedLocations []ResolvedLocation,
+) {
+	if len(declaration.Identifiers) == 0 {
+		return
+	}
+
+	resolved := map[string]struct{}{}
+	var available []string
+
+	for _, resolvedLocation := range resolvedLocations {
+		for _, identifier := range resolvedLocation.Identifiers {
+			name := identifier.Identifier
+			if _, ok := resolved[name]; ok {
+				continue
+			}
+			resolved[name] = struct{}{}
+			available = append(available, name)
+		}
+	}
+
+	var missing []ast.Identifier
+
+	for _, identifier := range declaration.Identifiers {
+		if _, ok := resolved[identifier.Identifier]; ok {
+			continue
+		}
+		missing = append(missing, identifier)
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	checker.handleMissingImports(missing, declaration.Aliases, available, declaration.Location)
+}
+
 func (checker *Checker) resolveLocation(identifiers []ast.Identifier, location common.Location) ([]ResolvedLocation, error) {
 
 	// If no location handler is available,
@@ -84,7 +128,11 @@ func (checker *Checker) resolveLocation(identifiers []ast.Identifier, location c
 	return checker.locationHandler(identifiers, location)
 }
 
-func (checker *Checker) importResolvedLocation(resolvedLocation ResolvedLocation, locationRange ast.Range) {
+func (checker *Checker) importResolvedLocation(
+	resolvedLocation ResolvedLocation,
+	aliases map[string]string,
+	locationRange ast.Range,
+) {
 
 	// First, get the Import for the resolved location
 
@@ -147,6 +195,7 @@ func (checker *Checker) importResolvedLocation(resolvedLocation ResolvedLocation
 	foundValues, invalidAccessedValues := checker.importElements(
 		checker.valueActivations,
 		resolvedLocation.Identifiers,
+		aliases,
 		allValueElements,
 		imp.IsImportableValue,
 	)
@@ -157,6 +206,7 @@ func (checker *Checker) importResolvedLocation(resolvedLocation ResolvedLocation
 	foundTypes, invalidAccessedTypes := checker.importElements(
 		checker.typeActivations,
 		resolvedLocation.Identifiers,
+		aliases,
 		allTypeElements,
 		imp.IsImportableType,
 	)
@@ -233,11 +283,16 @@ func (checker *Checker) importResolvedLocation(resolvedLocation ResolvedLocation
 			available = append(available, identifier)
 		})
 
-		checker.handleMissingImports(missing, available, location)
+		checker.handleMissingImports(missing, aliases, available, location)
 	}
 }
 
-func (checker *Checker) handleMissingImports(missing []ast.Identifier, available []string, importLocation common.Location) {
+func (checker *Checker) handleMissingImports(
+	missing []ast.Identifier,
+	aliases map[string]string,
+	available []string,
+	importLocation common.Location,
+) {
 	for _, identifier := range missing {
 		checker.report(
 			&NotExportedError{
@@ -251,8 +306,11 @@ func (checker *Checker) handleMissingImports(missing []ast.Identifier, available
 		// NOTE: declare constant variable with invalid type to silence rest of program
 		const access = ast.AccessPrivate
 
+		declaredIdentifier := identifier
+		declaredIdentifier.Identifier = importedName(identifier.Identifier, aliases)
+
 		_, err := checker.valueActivations.Declare(variableDeclaration{
-			identifier:               identifier.Identifier,
+			identifier:               declaredIdentifier.Identifier,
 			ty:                       InvalidType,
 			access:                   access,
 			kind:                     common.DeclarationKindValue,
@@ -264,7 +322,7 @@ func (checker *Checker) handleMissingImports(missing []ast.Identifier, available
 
 		// NOTE: declare type with invalid type to silence rest of program
 		_, err = checker.typeActivations.DeclareType(typeDeclaration{
-			identifier:               identifier,
+			identifier:               declaredIdentifier,
 			ty:                       InvalidType,
 			declarationKind:          common.DeclarationKindType,
 			access:                   access,
@@ -277,6 +335,7 @@ func (checker *Checker) handleMissingImports(missing []ast.Identifier, available
 func (checker *Checker) importElements(
 	valueActivations *VariableActivations,
 	requestedIdentifiers []ast.Identifier,
+	aliases map[string]string,
 	availableElements *StringImportElementOrderedMap,
 	filter func(name string) bool,
 ) (
@@ -295,7 +354,7 @@ func (checker *Checker) importElements(
 
 	identifiersCount := len(requestedIdentifiers)
 	if identifiersCount > 0 && availableElements != nil {
-		elements = NewStringImportElementOrderedMap()
+		elements = &StringImportElementOrderedMap{}
 		for _, identifier := range requestedIdentifiers {
 			name := identifier.Identifier
 			element, ok := availableElements.Get(name)
@@ -335,7 +394,7 @@ func (checker *Checker) importElements(
 			}
 
 			_, err := valueActivations.Declare(variableDeclaration{
-				identifier: name,
+				identifier: importedName(name, aliases),
 				ty:         element.Type,
 				// TODO: implies that type is "re-exported"
 				access: access,
@@ -345,6 +404,7 @@ func (checker *Checker) importElements(
 				isConstant:               true,
 				argumentLabels:           element.ArgumentLabels,
 				allowOuterScopeShadowing: false,
+				docString:                element.DocString,
 			})
 			checker.report(err)
 		})
@@ -352,3 +412,13 @@ func (checker *Checker) importElements(
 
 	return
 }
+
+// importedName returns the name under which the imported declaration
+// with the given name is declared in the importing program
+//
+func importedName(name string, aliases map[string]string) string {
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
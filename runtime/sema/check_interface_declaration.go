@@ -55,6 +55,8 @@ func (checker *Checker) VisitInterfaceDeclaration(declaration *ast.InterfaceDecl
 	// NOTE: functions are checked separately
 	checker.checkFieldsAccessModifier(declaration.Members.Fields())
 
+	checker.checkFieldValues(declaration.Members.Fields(), nil)
+
 	checker.checkNestedIdentifiers(declaration.Members)
 
 	// Activate new scope for nested types
@@ -120,6 +122,10 @@ func (checker *Checker) VisitInterfaceDeclaration(declaration *ast.InterfaceDecl
 		checker.visitCompositeDeclaration(nestedComposite, kind)
 	}
 
+	for _, nestedEntitlement := range declaration.Members.Entitlements() {
+		nestedEntitlement.Accept(checker)
+	}
+
 	return nil
 }
 
@@ -214,8 +220,8 @@ func (checker *Checker) declareInterfaceType(declaration *ast.InterfaceDeclarati
 		Location:      checker.Location,
 		Identifier:    identifier.Identifier,
 		CompositeKind: declaration.CompositeKind,
-		nestedTypes:   NewStringTypeOrderedMap(),
-		Members:       NewStringMemberOrderedMap(),
+		nestedTypes:   &StringTypeOrderedMap{},
+		Members:       &StringMemberOrderedMap{},
 	}
 
 	variable, err := checker.typeActivations.DeclareType(typeDeclaration{
@@ -254,12 +260,13 @@ func (checker *Checker) declareInterfaceType(declaration *ast.InterfaceDeclarati
 
 	// Check and declare nested types
 
-	nestedDeclarations, nestedInterfaceTypes, nestedCompositeTypes :=
+	nestedDeclarations, nestedInterfaceTypes, nestedCompositeTypes, nestedEntitlementTypes :=
 		checker.declareNestedDeclarations(
 			declaration.CompositeKind,
 			declaration.DeclarationKind(),
 			declaration.Members.Composites(),
 			declaration.Members.Interfaces(),
+			declaration.Members.Entitlements(),
 		)
 
 	checker.Elaboration.InterfaceNestedDeclarations[declaration] = nestedDeclarations
@@ -274,6 +281,11 @@ func (checker *Checker) declareInterfaceType(declaration *ast.InterfaceDeclarati
 		nestedCompositeType.SetContainerType(interfaceType)
 	}
 
+	for _, nestedEntitlementType := range nestedEntitlementTypes {
+		interfaceType.nestedTypes.Set(nestedEntitlementType.Identifier, nestedEntitlementType)
+		nestedEntitlementType.SetContainerType(interfaceType)
+	}
+
 	return interfaceType
 }
 
@@ -58,7 +58,11 @@ func (checker *Checker) checkInvocationExpression(invocationExpression *ast.Invo
 	// check the invoked expression can be invoked
 
 	invokedExpression := invocationExpression.InvokedExpression
+
+	previousInvokedExpression := checker.currentInvokedExpression
+	checker.currentInvokedExpression = invokedExpression
 	expressionType := checker.VisitExpression(invokedExpression, nil)
+	checker.currentInvokedExpression = previousInvokedExpression
 
 	// Get the member from the invoked value
 	// based on the use of optional chaining syntax
@@ -129,6 +133,10 @@ func (checker *Checker) checkInvocationExpression(invocationExpression *ast.Invo
 		})
 	} else {
 		checkInvocation()
+
+		// The invocation of an initializing function initializes fields
+
+		checker.recordInitializingFunctionInvocation(invocationExpression)
 	}
 
 	arguments := invocationExpression.Arguments
@@ -179,6 +187,8 @@ func (checker *Checker) checkInvocationExpression(invocationExpression *ast.Invo
 
 	checker.checkMemberInvocationResourceInvalidation(invokedExpression)
 
+	checker.checkAccountStorageInvocation(invocationExpression, argumentTypes)
+
 	// Update the return info for invocations that do not return (i.e. have a `Never` return type)
 
 	if returnType == NeverType {
@@ -376,7 +386,7 @@ func (checker *Checker) checkInvocation(
 
 	typeArgumentCount := len(invocationExpression.TypeArguments)
 
-	typeArguments := NewTypeParameterTypeOrderedMap()
+	typeArguments := &TypeParameterTypeOrderedMap{}
 
 	// If the function type is generic, the invocation might provide
 	// explicit type arguments for the type parameters.
@@ -536,8 +546,15 @@ func (checker *Checker) checkInvocationRequiredArgument(
 		// param types can be used to infer the types for arguments.
 		argumentType = checker.VisitExpression(argument.Expression, parameterType)
 	} else {
-		// TODO: pass the expected type to support for parameters
-		argumentType = checker.VisitExpression(argument.Expression, nil)
+		// If the parameter type can already be fully resolved,
+		// e.g. because it does not refer to any type parameters,
+		// or because the type arguments were provided explicitly,
+		// then use it to infer the type of the argument.
+		// Otherwise, infer the type from the argument expression.
+
+		expectedType := parameterType.Resolve(typeParameters)
+
+		argumentType = checker.VisitExpression(argument.Expression, expectedType)
 
 		// Try to unify the parameter type with the argument type.
 		// If unification fails, fall back to the parameter type for now.
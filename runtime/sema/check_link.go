@@ -0,0 +1,131 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// checkAccountStorageInvocation checks invocations of the storage functions of accounts,
+// i.e. `save`, `load`, and `link`, where the account is a variable and the paths are literals.
+//
+// It records the static types of values saved in the current function,
+// and reports a hint if a link targets a path that a value was saved to,
+// and the value can statically never be borrowed with the borrow type of the link.
+//
+func (checker *Checker) checkAccountStorageInvocation(
+	invocationExpression *ast.InvocationExpression,
+	argumentTypes []Type,
+) {
+	functionActivation := checker.functionActivations.Current()
+	if functionActivation == nil {
+		return
+	}
+
+	memberExpression, ok := invocationExpression.InvokedExpression.(*ast.MemberExpression)
+	if !ok {
+		return
+	}
+
+	accountExpression, ok := memberExpression.Expression.(*ast.IdentifierExpression)
+	if !ok {
+		return
+	}
+
+	_, member, _ := checker.visitMember(memberExpression)
+	if member == nil || member.ContainerType != AuthAccountType {
+		return
+	}
+
+	arguments := invocationExpression.Arguments
+
+	literalStoragePathKey := func(index int) (string, bool) {
+		if len(arguments) <= index {
+			return "", false
+		}
+		pathExpression, ok := arguments[index].Expression.(*ast.PathExpression)
+		if !ok ||
+			common.PathDomainFromIdentifier(pathExpression.Domain.Identifier) != common.PathDomainStorage {
+
+			return "", false
+		}
+		return accountExpression.Identifier.Identifier + pathExpression.String(), true
+	}
+
+	switch member.Identifier.Identifier {
+	case AuthAccountSaveField:
+		key, ok := literalStoragePathKey(1)
+		if !ok || len(argumentTypes) < 1 {
+			return
+		}
+		if functionActivation.SavedStorageTypes == nil {
+			functionActivation.SavedStorageTypes = map[string]Type{}
+		}
+		functionActivation.SavedStorageTypes[key] = argumentTypes[0]
+
+	case AuthAccountLoadField:
+		key, ok := literalStoragePathKey(0)
+		if !ok {
+			return
+		}
+		delete(functionActivation.SavedStorageTypes, key)
+
+	case AuthAccountLinkField:
+		key, ok := literalStoragePathKey(1)
+		if !ok {
+			return
+		}
+
+		savedType := functionActivation.SavedStorageTypes[key]
+
+		// Only the dynamic types of composite values are statically known,
+		// as they are always equal to the static type
+
+		compositeType, ok := savedType.(*CompositeType)
+		if !ok {
+			return
+		}
+
+		typeArguments := checker.Elaboration.InvocationExpressionTypeArguments[invocationExpression]
+		if typeArguments == nil {
+			return
+		}
+		typeArgumentPair := typeArguments.Oldest()
+		if typeArgumentPair == nil {
+			return
+		}
+		borrowType, ok := typeArgumentPair.Value.(*ReferenceType)
+		if !ok || borrowType.Type.IsInvalidType() {
+			return
+		}
+
+		if IsSubType(compositeType, borrowType.Type) {
+			return
+		}
+
+		checker.hint(
+			&InvalidLinkTargetHint{
+				TargetType: compositeType,
+				BorrowType: borrowType,
+				Range:      ast.NewRangeFromPositioned(invocationExpression),
+			},
+		)
+	}
+}
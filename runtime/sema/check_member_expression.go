@@ -108,6 +108,7 @@ func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedT
 				AccessedType: accessedType,
 				Member:       member,
 				IsOptional:   isOptional,
+				Slot:         checker.memberSlot(member),
 			}
 	}()
 
@@ -230,6 +231,7 @@ func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedT
 				identifierStartPosition,
 				identifierEndPosition,
 				origin,
+				checker.occurrenceRole(expression),
 			)
 		}
 
@@ -246,6 +248,42 @@ func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedT
 			)
 		}
 
+		// Check that the member is accessible through the reference, if any
+
+		checker.checkMemberEntitlements(
+			accessedType,
+			member,
+			ast.NewRangeFromPositioned(expression),
+		)
+
+		// Check if the member is deprecated.
+		// Uses of the member inside of its containing type are not reported
+
+		if !checker.containerTypes[member.ContainerType] {
+			checker.checkDeprecation(
+				member.Identifier.Identifier,
+				member.DocString,
+				ast.Range{
+					StartPos: identifierStartPosition,
+					EndPos:   identifierEndPosition,
+				},
+			)
+		}
+
+		// Check that initializing functions are only called during initialization
+
+		checker.checkInitializingFunctionUse(member, expression)
+
+		// Check that the member is not gated behind a pragma
+		// which is not declared by the program
+
+		checker.checkMemberFeatureGate(member, expression)
+
+		// Check that the member does not mutate a container field
+		// outside of the field's composite
+
+		checker.checkExternalMutation(accessedType, member, expression)
+
 		// Check that the member access is not to a function of resource type
 		// outside of an invocation of it.
 		//
@@ -267,6 +305,117 @@ func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedT
 	return accessedType, member, isOptional
 }
 
+// checkExternalMutation checks that the given member expression does not mutate
+// an array or dictionary which is stored in a field of a composite,
+// if the field is not writeable in the current location,
+// e.g. `s.xs.append(1)` outside of the composite of `s`, where the field `xs` is `pub`.
+//
+// Fields which may be mutated externally must be declared publicly settable (`pub(set)`)
+//
+func (checker *Checker) checkExternalMutation(
+	accessedType Type,
+	member *Member,
+	expression *ast.MemberExpression,
+) {
+	if !isContainerMutationMember(accessedType, member.Identifier.Identifier) {
+		return
+	}
+
+	fieldExpression, ok := expression.Expression.(*ast.MemberExpression)
+	if !ok {
+		return
+	}
+
+	fieldMemberInfo := checker.Elaboration.MemberExpressionMemberInfos[fieldExpression]
+	fieldMember := fieldMemberInfo.Member
+	if fieldMember == nil ||
+		fieldMember.DeclarationKind != common.DeclarationKindField {
+
+		return
+	}
+
+	if _, ok := fieldMember.ContainerType.(CompositeKindedType); !ok {
+		return
+	}
+
+	if checker.isWriteableMember(fieldMember) {
+		return
+	}
+
+	name := fieldMember.Identifier.Identifier
+	declarationKind := fieldMember.DeclarationKind
+	containerType := fieldMember.ContainerType
+	errorRange := ast.NewRangeFromPositioned(expression)
+
+	if checker.externalMutationReportOnly {
+		checker.hint(
+			&ExternalMutationHint{
+				Name:            name,
+				DeclarationKind: declarationKind,
+				ContainerType:   containerType,
+				Range:           errorRange,
+			},
+		)
+	} else {
+		checker.report(
+			&ExternalMutationError{
+				Name:            name,
+				DeclarationKind: declarationKind,
+				ContainerType:   containerType,
+				Range:           errorRange,
+			},
+		)
+	}
+}
+
+// isContainerMutationMember returns true if the member with the given name
+// of the given array or dictionary type mutates the container in-place
+//
+func isContainerMutationMember(containerType Type, name string) bool {
+	switch containerType.(type) {
+	case ArrayType:
+		switch name {
+		case "append", "appendAll", "insert", "remove", "removeFirst", "removeLast":
+			return true
+		}
+
+	case *DictionaryType:
+		switch name {
+		case "insert", "remove":
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkMemberFeatureGate checks that the program declares the pragma
+// which the use of the given member is gated behind, if any
+//
+func (checker *Checker) checkMemberFeatureGate(member *Member, expression *ast.MemberExpression) {
+	if checker.featureGates == nil || member.ContainerType == nil {
+		return
+	}
+
+	members, ok := checker.featureGates[member.ContainerType.ID()]
+	if !ok {
+		return
+	}
+
+	pragma, ok := members[member.Identifier.Identifier]
+	if !ok || checker.Elaboration.HasPragma(pragma) {
+		return
+	}
+
+	checker.report(
+		&FeatureNotEnabledError{
+			Name:   member.Identifier.Identifier,
+			Pragma: pragma,
+			Range:  ast.NewRangeFromPositioned(expression),
+		},
+	)
+}
+
 // isReadableMember returns true if the given member can be read from
 // in the current location of the checker
 //
@@ -305,11 +454,15 @@ func (checker *Checker) isReadableMember(member *Member) bool {
 }
 
 // isWriteableMember returns true if the given member can be written to
-// in the current location of the checker
+// in the current location of the checker.
+//
+// Fields with entitlement access are writeable by the owner of the value,
+// and through references which are granted the entitlements (see `checkMemberEntitlements`).
 //
 func (checker *Checker) isWriteableMember(member *Member) bool {
 	return checker.isWriteableAccess(member.Access) ||
-		checker.containerTypes[member.ContainerType]
+		checker.containerTypes[member.ContainerType] ||
+		len(member.Entitlements) > 0
 }
 
 // containingContractKindedType returns the containing contract-kinded type
@@ -0,0 +1,345 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// MemoizePragma is the pragma which declares a contract function as memoized,
+// e.g. `#memoize("Fees.baseFee")`.
+//
+// The result of a memoized function may be cached by the runtime across executions,
+// so the function must not have parameters, must return a value of a primitive type,
+// and must be pure, i.e. it must only depend on constants, see checkMemoizedFunctionPurity
+//
+const MemoizePragma = "memoize"
+
+// checkMemoizedFunctions checks the contract functions declared as memoized
+// and records the valid ones in the elaboration
+//
+func (checker *Checker) checkMemoizedFunctions(program *ast.Program) {
+
+	memoizedFunctionNames := map[string]struct{}{}
+
+	for _, pragma := range checker.Elaboration.Pragmas {
+		if pragma.Identifier != MemoizePragma || len(pragma.Arguments) != 1 {
+			continue
+		}
+		memoizedFunctionNames[pragma.Arguments[0]] = struct{}{}
+	}
+
+	for _, pragma := range checker.Elaboration.Pragmas {
+		if pragma.Identifier != MemoizePragma {
+			continue
+		}
+
+		functionDeclaration := checker.memoizedFunctionDeclaration(program, pragma)
+		if functionDeclaration == nil {
+			continue
+		}
+
+		if !checker.checkMemoizedFunctionPurity(functionDeclaration, memoizedFunctionNames) {
+			continue
+		}
+
+		checker.Elaboration.MemoizedFunctions[functionDeclaration] = struct{}{}
+	}
+}
+
+// memoizedFunctionDeclaration returns the declaration of the contract function
+// which is declared as memoized by the given pragma.
+// If the function is not found or cannot be memoized, an error is reported and nil is returned
+//
+func (checker *Checker) memoizedFunctionDeclaration(
+	program *ast.Program,
+	pragma Pragma,
+) *ast.FunctionDeclaration {
+
+	reportInvalid := func(name string, message string) {
+		checker.report(
+			&InvalidMemoizedFunctionError{
+				Name:    name,
+				Message: message,
+				Range:   ast.NewRangeFromPositioned(pragma.Declaration),
+			},
+		)
+	}
+
+	if len(pragma.Arguments) != 1 {
+		reportInvalid(
+			MemoizePragma,
+			"expected the name of a contract function, e.g. `Contract.function`",
+		)
+		return nil
+	}
+
+	name := pragma.Arguments[0]
+
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 {
+		reportInvalid(name, "expected the name of a contract function, e.g. `Contract.function`")
+		return nil
+	}
+
+	contractName, functionName := parts[0], parts[1]
+
+	var functionDeclaration *ast.FunctionDeclaration
+
+	for _, compositeDeclaration := range program.CompositeDeclarations() {
+		if compositeDeclaration.CompositeKind != common.CompositeKindContract ||
+			compositeDeclaration.Identifier.Identifier != contractName {
+
+			continue
+		}
+
+		for _, declaration := range compositeDeclaration.Members.Functions() {
+			if declaration.Identifier.Identifier == functionName {
+				functionDeclaration = declaration
+				break
+			}
+		}
+	}
+
+	if functionDeclaration == nil || functionDeclaration.FunctionBlock == nil {
+		reportInvalid(name, "the program declares no such contract function")
+		return nil
+	}
+
+	functionType := checker.Elaboration.FunctionDeclarationFunctionTypes[functionDeclaration]
+	if functionType == nil {
+		return nil
+	}
+
+	if len(functionType.Parameters) > 0 {
+		reportInvalid(name, "the function must not have parameters")
+		return nil
+	}
+
+	if !IsMemoizableType(functionType.ReturnTypeAnnotation.Type) {
+		reportInvalid(
+			name,
+			fmt.Sprintf(
+				"the function must return a value of a primitive type, not `%s`",
+				functionType.ReturnTypeAnnotation.Type.QualifiedString(),
+			),
+		)
+		return nil
+	}
+
+	return functionDeclaration
+}
+
+// checkMemoizedFunctionPurity checks that the given memoized function only depends on constants.
+// The body of the function must not:
+//   - assign to or swap anything other than local variables
+//   - emit events
+//   - read variable fields, or fields of non-primitive types
+//   - call composite functions, other than memoized functions
+//   - call global functions, other than conversion functions, e.g. `UFix64(x)`
+//   - call functions of built-in types, other than primitive types, arrays, dictionaries, and types
+//   - call function values, other than function expressions
+//
+func (checker *Checker) checkMemoizedFunctionPurity(
+	functionDeclaration *ast.FunctionDeclaration,
+	memoizedFunctionNames map[string]struct{},
+) (pure bool) {
+
+	pure = true
+
+	functionName := functionDeclaration.Identifier.Identifier
+
+	reportImpure := func(positioned ast.HasPosition, message string) {
+		pure = false
+		checker.report(
+			&ImpureMemoizedFunctionError{
+				FunctionName: functionName,
+				Message:      message,
+				Range:        ast.NewRangeFromPositioned(positioned),
+			},
+		)
+	}
+
+	isLocalTarget := func(expression ast.Expression) bool {
+		_, ok := expression.(*ast.IdentifierExpression)
+		return ok
+	}
+
+	var inspect func(element ast.Element) bool
+	inspect = func(element ast.Element) bool {
+		switch element := element.(type) {
+		case *ast.AssignmentStatement:
+			if !isLocalTarget(element.Target) {
+				reportImpure(element.Target, "cannot assign to non-local variables")
+
+				// Only check the value, the target is already reported
+				ast.Inspect(element.Value, inspect)
+				return false
+			}
+
+		case *ast.SwapStatement:
+			if !isLocalTarget(element.Left) || !isLocalTarget(element.Right) {
+				reportImpure(element, "cannot swap non-local variables")
+				return false
+			}
+
+		case *ast.EmitStatement:
+			reportImpure(element, "cannot emit events")
+			return false
+
+		case *ast.MemberExpression:
+			memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[element]
+			if !ok || memberInfo.Member == nil {
+				break
+			}
+			member := memberInfo.Member
+
+			var containerIdentifier string
+			switch containerType := member.ContainerType.(type) {
+			case *CompositeType:
+				containerIdentifier = containerType.Identifier
+			case *InterfaceType:
+				containerIdentifier = containerType.Identifier
+			default:
+				return true
+			}
+
+			memberName := member.Identifier.Identifier
+
+			switch member.DeclarationKind {
+			case common.DeclarationKindField:
+				if member.VariableKind != ast.VariableKindConstant {
+					reportImpure(
+						element.Identifier,
+						fmt.Sprintf("cannot read variable field `%s`", memberName),
+					)
+				} else if !IsMemoizableType(member.TypeAnnotation.Type) {
+					reportImpure(
+						element.Identifier,
+						fmt.Sprintf(
+							"cannot read field `%s` of non-primitive type `%s`",
+							memberName,
+							member.TypeAnnotation.Type.QualifiedString(),
+						),
+					)
+				}
+
+			case common.DeclarationKindFunction:
+				qualifiedName := fmt.Sprintf("%s.%s", containerIdentifier, memberName)
+				_, isMemoized := memoizedFunctionNames[qualifiedName]
+				if !isMemoized {
+					reportImpure(
+						element.Identifier,
+						fmt.Sprintf("cannot call non-memoized function `%s`", qualifiedName),
+					)
+				}
+			}
+
+		case *ast.InvocationExpression:
+			switch invokedExpression := element.InvokedExpression.(type) {
+			case *ast.IdentifierExpression:
+				name := invokedExpression.Identifier.Identifier
+				if BaseValueActivation.Find(name) == nil {
+					reportImpure(
+						invokedExpression,
+						fmt.Sprintf("cannot call function `%s`", name),
+					)
+				}
+
+			case *ast.MemberExpression:
+				memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[invokedExpression]
+				if !ok || memberInfo.Member == nil {
+					break
+				}
+				member := memberInfo.Member
+
+				switch member.ContainerType.(type) {
+				case *CompositeType, *InterfaceType:
+					// Functions of composites and interfaces are checked
+					// when the member expression itself is inspected
+
+				default:
+					if !isPureMemberContainerType(member.ContainerType) {
+						reportImpure(
+							invokedExpression.Identifier,
+							fmt.Sprintf(
+								"cannot call function `%s` of `%s`",
+								member.Identifier.Identifier,
+								member.ContainerType.QualifiedString(),
+							),
+						)
+					}
+				}
+
+			case *ast.FunctionExpression:
+				// The body of the function expression is inspected
+
+			default:
+				reportImpure(invokedExpression, "cannot call function values")
+			}
+		}
+
+		return true
+	}
+
+	ast.Inspect(functionDeclaration.FunctionBlock, inspect)
+
+	return
+}
+
+// isPureMemberContainerType returns true if the functions of the given built-in type
+// can be called by memoized functions, i.e. they only depend on the value they are called on
+//
+func isPureMemberContainerType(ty Type) bool {
+	switch ty.(type) {
+	case *VariableSizedType, *ConstantSizedType, *DictionaryType:
+		return true
+	}
+
+	return ty == MetaType ||
+		IsMemoizableType(ty)
+}
+
+// IsMemoizableType returns true if values of the given type can be memoized,
+// i.e. the type is a primitive type, like a number, a string, or an address,
+// or an optional of such a type
+//
+func IsMemoizableType(ty Type) bool {
+	switch ty := ty.(type) {
+	case *OptionalType:
+		return IsMemoizableType(ty.Type)
+
+	case *AddressType:
+		return true
+	}
+
+	switch ty {
+	case BoolType, StringType, CharacterType,
+		TimestampType, DurationType:
+
+		return true
+	}
+
+	return IsSubType(ty, NumberType) ||
+		IsSubType(ty, PathType)
+}
@@ -20,6 +20,56 @@ package sema
 
 import "github.com/onflow/cadence/runtime/ast"
 
+// Pragma is a pragma declared by a program,
+// e.g. `#allowAccountLinking` or `#version("1.0")`
+//
+type Pragma struct {
+	Identifier  string
+	Arguments   []string
+	Declaration *ast.PragmaDeclaration
+}
+
+// declarePragma records the given pragma declaration in the elaboration.
+// Invalid pragma declarations are ignored, they are reported when they are checked
+//
+func (checker *Checker) declarePragma(declaration *ast.PragmaDeclaration) {
+
+	var identifierExpression *ast.IdentifierExpression
+	var arguments []string
+
+	switch expression := declaration.Expression.(type) {
+	case *ast.IdentifierExpression:
+		identifierExpression = expression
+
+	case *ast.InvocationExpression:
+		var ok bool
+		identifierExpression, ok = expression.InvokedExpression.(*ast.IdentifierExpression)
+		if !ok {
+			return
+		}
+
+		for _, argument := range expression.Arguments {
+			stringExpression, ok := argument.Expression.(*ast.StringExpression)
+			if !ok {
+				return
+			}
+			arguments = append(arguments, stringExpression.Value)
+		}
+
+	default:
+		return
+	}
+
+	checker.Elaboration.Pragmas = append(
+		checker.Elaboration.Pragmas,
+		Pragma{
+			Identifier:  identifierExpression.Identifier.Identifier,
+			Arguments:   arguments,
+			Declaration: declaration,
+		},
+	)
+}
+
 func (checker *Checker) VisitPragmaDeclaration(p *ast.PragmaDeclaration) ast.Repr {
 
 	invocPragma, isInvocPragma := p.Expression.(*ast.InvocationExpression)
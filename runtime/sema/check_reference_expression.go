@@ -54,18 +54,20 @@ func (checker *Checker) VisitReferenceExpression(referenceExpression *ast.Refere
 
 	referencedExpression := referenceExpression.Expression
 
-	// If the referenced expression is an index expression, it might be into storage
-
-	indexExpression, isIndexExpression := referencedExpression.(*ast.IndexExpression)
-	if isIndexExpression {
-		// The referenced expression will evaluate to an optional type if it is indexing:
-		// the result of the access is an optional.
+	// If the referenced expression is an index expression, it might be into storage.
+	// If the referenced expression is a member expression, it might access an optional field,
+	// e.g. a resource field which is only conditionally set.
+
+	switch referencedExpression.(type) {
+	case *ast.IndexExpression, *ast.MemberExpression:
+		// The referenced expression may evaluate to an optional type if it is indexing
+		// (the result of the access is an optional), or if the accessed field is optional.
 		//
 		// Hence expect an optional.
 
 		expectedType := wrapWithOptionalIfNotNil(targetType)
 
-		_, referencedType = checker.visitExpression(indexExpression, expectedType)
+		_, referencedType = checker.visitExpression(referencedExpression, expectedType)
 
 		// Unwrap the optional one level, but not infinitely
 
@@ -73,8 +75,8 @@ func (checker *Checker) VisitReferenceExpression(referenceExpression *ast.Refere
 			referencedType = optionalReferencedType.Type
 		}
 
-	} else {
-		// If the referenced expression is not an index expression, check it normally
+	default:
+		// If the referenced expression is not an index or member expression, check it normally
 		_, referencedType = checker.visitExpression(referencedExpression, targetType)
 	}
 
@@ -95,3 +97,123 @@ func (checker *Checker) VisitReferenceExpression(referenceExpression *ast.Refere
 
 	return referenceType
 }
+
+// recordReference records the resource variables which the reference
+// that is stored in the given variable refers to, if any.
+//
+// This allows reporting uses of the reference after the referenced resource
+// was moved or destroyed, see checkReferenceValidity.
+//
+func (checker *Checker) recordReference(variable *Variable, expression ast.Expression) {
+	if variable == nil {
+		return
+	}
+
+	if _, ok := UnwrapOptionalType(variable.Type).(*ReferenceType); !ok {
+		return
+	}
+
+	variable.referencedResourceVariables = checker.referencedResourceVariables(expression)
+}
+
+// referencedResourceVariables returns the resource variables
+// which the reference that the given expression evaluates to refers to.
+//
+func (checker *Checker) referencedResourceVariables(expression ast.Expression) []*Variable {
+	switch expression := expression.(type) {
+	case *ast.ReferenceExpression:
+		rootVariable := checker.rootOfAccessChain(expression.Expression)
+		if rootVariable == nil || !rootVariable.Type.IsResourceType() {
+			return nil
+		}
+
+		return []*Variable{rootVariable}
+
+	case *ast.IdentifierExpression:
+		variable := checker.valueActivations.Find(expression.Identifier.Identifier)
+		if variable == nil {
+			return nil
+		}
+
+		return variable.referencedResourceVariables
+
+	case *ast.ConditionalExpression:
+		thenVariables := checker.referencedResourceVariables(expression.Then)
+		elseVariables := checker.referencedResourceVariables(expression.Else)
+
+		if len(thenVariables) == 0 {
+			return elseVariables
+		}
+
+		if len(elseVariables) == 0 {
+			return thenVariables
+		}
+
+		variables := make([]*Variable, 0, len(thenVariables)+len(elseVariables))
+		variables = append(variables, thenVariables...)
+		variables = append(variables, elseVariables...)
+		return variables
+
+	case *ast.CastingExpression:
+		return checker.referencedResourceVariables(expression.Expression)
+
+	case *ast.ForceExpression:
+		return checker.referencedResourceVariables(expression.Expression)
+
+	default:
+		return nil
+	}
+}
+
+// rootOfAccessChain returns the variable at the root of the given
+// chain of member and index accesses, e.g. `r` for `r.a[0].b`.
+//
+func (checker *Checker) rootOfAccessChain(expression ast.Expression) *Variable {
+	for {
+		switch accessExpression := expression.(type) {
+		case *ast.IdentifierExpression:
+			return checker.valueActivations.Find(accessExpression.Identifier.Identifier)
+
+		case *ast.MemberExpression:
+			expression = accessExpression.Expression
+
+		case *ast.IndexExpression:
+			expression = accessExpression.TargetExpression
+
+		default:
+			return nil
+		}
+	}
+}
+
+// checkReferenceValidity checks if the given variable stores a reference
+// to a resource which was previously moved or destroyed,
+// and reports the use of the invalidated reference.
+//
+func (checker *Checker) checkReferenceValidity(variable *Variable, usePosition ast.HasPosition) {
+	for _, referencedVariable := range variable.referencedResourceVariables {
+		resourceInfo := checker.resources.Get(referencedVariable)
+		if resourceInfo.Invalidations.Size() == 0 {
+			continue
+		}
+
+		errorRange := ast.NewRangeFromPositioned(usePosition)
+
+		if checker.referenceInvalidationReportOnly {
+			checker.hint(
+				&InvalidatedResourceReferenceHint{
+					Range: errorRange,
+				},
+			)
+		} else {
+			checker.report(
+				&InvalidatedResourceReferenceError{
+					Invalidations: resourceInfo.Invalidations.All(),
+					Range:         errorRange,
+				},
+			)
+		}
+
+		return
+	}
+}
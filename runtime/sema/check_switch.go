@@ -49,9 +49,22 @@ func (checker *Checker) VisitSwitchStatement(statement *ast.SwitchStatement) ast
 		checker.visitSwitchCase(switchCase, defaultAllowed, testType, testTypeIsValid)
 	}
 
-	checker.functionActivations.WithSwitch(func() {
-		checker.checkSwitchCasesStatements(statement.Cases)
-	})
+	jumpTarget := checker.functionActivations.WithSwitch(
+		checker.valueActivations.Depth(),
+		func() {
+			checker.checkSwitchCasesStatements(statement, statement.Cases)
+		},
+	)
+
+	// `break` statements flow to the end of the switch statement
+
+	if len(jumpTarget.BreakResources) > 0 {
+		for _, breakResources := range jumpTarget.BreakResources {
+			checker.resources.MergeJump(breakResources)
+		}
+		checker.resources.Returns = false
+		checker.resources.Jumps = false
+	}
 
 	return nil
 }
@@ -125,7 +138,7 @@ func (checker *Checker) checkSwitchCaseExpression(
 	}
 }
 
-func (checker *Checker) checkSwitchCasesStatements(cases []*ast.SwitchCase) {
+func (checker *Checker) checkSwitchCasesStatements(statement *ast.SwitchStatement, cases []*ast.SwitchCase) {
 	caseCount := len(cases)
 	if caseCount == 0 {
 		return
@@ -145,13 +158,29 @@ func (checker *Checker) checkSwitchCasesStatements(cases []*ast.SwitchCase) {
 		}
 	}
 
+	// The branch of the remaining cases spans them.
+	// If there are no remaining cases, the path on which no case is taken
+	// is represented by the whole statement
+
+	remainingCases := cases[1:]
+
+	remainingRange := ast.NewRangeFromPositioned(statement)
+	if len(remainingCases) > 0 {
+		remainingRange = ast.Range{
+			StartPos: remainingCases[0].StartPosition(),
+			EndPos:   remainingCases[len(remainingCases)-1].EndPosition(),
+		}
+	}
+
 	_, _ = checker.checkConditionalBranches(
+		ast.NewRangeFromPositioned(switchCase),
 		func() Type {
 			checker.checkSwitchCaseStatements(switchCase)
 			return nil
 		},
+		remainingRange,
 		func() Type {
-			checker.checkSwitchCasesStatements(cases[1:])
+			checker.checkSwitchCasesStatements(statement, remainingCases)
 			return nil
 		},
 	)
@@ -35,7 +35,7 @@ func (checker *Checker) VisitTransactionDeclaration(declaration *ast.Transaction
 		checker.containerTypes[transactionType] = false
 	}()
 
-	fieldMembers := NewMemberAstFieldDeclarationOrderedMap()
+	fieldMembers := &MemberAstFieldDeclarationOrderedMap{}
 
 	for _, field := range declaration.Fields {
 		fieldName := field.Identifier.Identifier
@@ -124,6 +124,8 @@ func (checker *Checker) checkTransactionFields(declaration *ast.TransactionDecla
 			)
 		}
 	}
+
+	checker.checkFieldValues(declaration.Fields, nil)
 }
 
 // checkTransactionBlocks checks that a transaction contains the required prepare and execute blocks.
@@ -198,7 +200,8 @@ func (checker *Checker) visitTransactionPrepareFunction(
 	)
 }
 
-// checkTransactionPrepareFunctionParameters checks that the parameters are each of type Account.
+// checkTransactionPrepareFunctionParameters checks that the parameters are each of type `AuthAccount`,
+// or of a constant-sized array type of `AuthAccount`, e.g. `[AuthAccount; 2]`.
 //
 func (checker *Checker) checkTransactionPrepareFunctionParameters(
 	parameterList *ast.ParameterList,
@@ -208,7 +211,7 @@ func (checker *Checker) checkTransactionPrepareFunctionParameters(
 		parameterType := parameters[i].TypeAnnotation.Type
 
 		if !parameterType.IsInvalidType() &&
-			!IsSameTypeKind(parameterType, AuthAccountType) {
+			!isValidTransactionPrepareParameterType(parameterType) {
 
 			checker.report(
 				&InvalidTransactionPrepareParameterTypeError{
@@ -221,6 +224,14 @@ func (checker *Checker) checkTransactionPrepareFunctionParameters(
 
 }
 
+func isValidTransactionPrepareParameterType(parameterType Type) bool {
+	if arrayType, ok := parameterType.(*ConstantSizedType); ok {
+		parameterType = arrayType.Type
+	}
+
+	return IsSameTypeKind(parameterType, AuthAccountType)
+}
+
 // visitTransactionExecuteFunction visits and checks the execute function of a transaction.
 func (checker *Checker) visitTransactionExecuteFunction(
 	executeFunction *ast.SpecialFunctionDeclaration,
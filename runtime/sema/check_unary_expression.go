@@ -28,6 +28,7 @@ func (checker *Checker) VisitUnaryExpression(expression *ast.UnaryExpression) as
 	var expectedType Type
 	if expression.Operation == ast.OperationMove {
 		expectedType = checker.expectedType
+		checker.setOccurrenceRole(expression.Expression, OccurrenceRoleMove)
 	}
 
 	valueType := checker.VisitExpressionWithForceType(expression.Expression, expectedType, false)
@@ -50,6 +51,9 @@ func (checker *Checker) VisitUnaryExpression(expression *ast.UnaryExpression) as
 			reportInvalidUnaryOperator(expectedType)
 			return InvalidType
 		}
+
+		checker.foldUnaryExpression(expression)
+
 		return valueType
 
 	case ast.OperationMinus:
@@ -59,6 +63,8 @@ func (checker *Checker) VisitUnaryExpression(expression *ast.UnaryExpression) as
 			return InvalidType
 		}
 
+		checker.foldUnaryExpression(expression)
+
 		return valueType
 
 	case ast.OperationMove:
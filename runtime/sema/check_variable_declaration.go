@@ -59,6 +59,8 @@ func (checker *Checker) visitVariableDeclaration(declaration *ast.VariableDeclar
 		}
 	}
 
+	checker.setTransferOccurrenceRole(declaration.Value, declaration.Transfer)
+
 	valueType := checker.VisitExpression(declaration.Value, expectedValueType)
 
 	checker.Elaboration.VariableDeclarationValueTypes[declaration] = valueType
@@ -188,6 +190,12 @@ func (checker *Checker) visitVariableDeclaration(declaration *ast.VariableDeclar
 	})
 	checker.report(err)
 
+	checker.recordReference(variable, declaration.Value)
+
+	if declaration.SecondValue == nil {
+		checker.recordConstantInterval(variable, declaration.Value)
+	}
+
 	if checker.positionInfoEnabled {
 		checker.recordVariableDeclarationOccurrence(identifier, variable)
 		checker.recordVariableDeclarationRange(declaration, identifier, declarationType)
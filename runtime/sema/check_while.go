@@ -27,22 +27,53 @@ func (checker *Checker) VisitWhileStatement(statement *ast.WhileStatement) ast.R
 
 	checker.VisitExpression(statement.Test, BoolType)
 
-	// The body of the loop will maybe be evaluated.
-	// That means that resource invalidations and
-	// returns are not definite, but only potential.
+	checker.checkLoop(statement, func() {
+		statement.Block.Accept(checker)
+	})
+
+	return nil
+}
+
+// checkLoop checks the body of the given loop statement.
+//
+// The body of the loop will maybe be evaluated.
+// That means that resource invalidations and
+// returns are not definite, but only potential.
+//
+func (checker *Checker) checkLoop(statement ast.Statement, checkBody func()) {
+
+	var jumpTarget *JumpTarget
 
 	_ = checker.checkPotentiallyUnevaluated(func() Type {
-		checker.functionActivations.WithLoop(func() {
-			statement.Block.Accept(checker)
-		})
+		jumpTarget = checker.functionActivations.WithLoop(
+			checker.valueActivations.Depth(),
+			checkBody,
+		)
+
+		// Like the end of the body, `continue` statements
+		// flow back to the start of the loop
+
+		if len(jumpTarget.ContinueResources) > 0 {
+			for _, continueResources := range jumpTarget.ContinueResources {
+				checker.resources.MergeJump(continueResources)
+			}
+			checker.resources.Returns = false
+			checker.resources.Jumps = false
+		}
 
 		// ignored
 		return nil
 	})
 
-	checker.reportResourceUsesInLoop(statement.StartPos, statement.EndPosition())
+	checker.reportResourceUsesInLoop(statement.StartPosition(), statement.EndPosition())
 
-	return nil
+	// `break` statements flow to the end of the loop,
+	// so they do not affect the next iteration,
+	// but the code after the loop
+
+	for _, breakResources := range jumpTarget.BreakResources {
+		checker.resources.MergeJump(breakResources)
+	}
 }
 
 func (checker *Checker) reportResourceUsesInLoop(startPos, endPos ast.Position) {
@@ -110,8 +141,16 @@ func (checker *Checker) VisitBreakStatement(statement *ast.BreakStatement) ast.R
 				Range:            ast.NewRangeFromPositioned(statement),
 			},
 		)
+
+		return nil
 	}
 
+	jumpTarget := checker.functionActivations.Current().BreakTarget()
+	jumpTarget.BreakResources = append(
+		jumpTarget.BreakResources,
+		checker.recordJump(jumpTarget),
+	)
+
 	return nil
 }
 
@@ -126,7 +165,31 @@ func (checker *Checker) VisitContinueStatement(statement *ast.ContinueStatement)
 				Range:            ast.NewRangeFromPositioned(statement),
 			},
 		)
+
+		return nil
 	}
 
+	jumpTarget := checker.functionActivations.Current().ContinueTarget()
+	jumpTarget.ContinueResources = append(
+		jumpTarget.ContinueResources,
+		checker.recordJump(jumpTarget),
+	)
+
 	return nil
 }
+
+// recordJump checks a jump to the given target, i.e. a `break` or `continue` statement,
+// and returns the resources at the jump.
+//
+// Resources declared inside the target statement go out of scope, so they must have been invalidated.
+// Control flow does not continue after the jump.
+//
+func (checker *Checker) recordJump(jumpTarget *JumpTarget) *Resources {
+	checker.checkResourceLoss(jumpTarget.ValueActivationDepth + 1)
+
+	jumpResources := checker.resources.Clone()
+
+	checker.resources.Jumps = true
+
+	return jumpResources
+}
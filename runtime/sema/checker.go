@@ -78,6 +78,16 @@ type ImportHandlerFunc func(checker *Checker, importedLocation common.Location,
 
 type MemberAccountAccessHandlerFunc func(checker *Checker, memberLocation common.Location) bool
 
+// FeatureGate gates the use of a member of a type behind a pragma,
+// i.e. the member may only be used by programs which declare the pragma,
+// e.g. `#allowAccountLinking`
+//
+type FeatureGate struct {
+	Pragma        string
+	ContainerType Type
+	Member        string
+}
+
 // Checker
 
 type Checker struct {
@@ -90,13 +100,17 @@ type Checker struct {
 	hints                              []Hint
 	valueActivations                   *VariableActivations
 	resources                          *Resources
+	reportedResourceLosses             map[*Variable]struct{}
+	integerIntervals                   map[*Variable]integerInterval
 	typeActivations                    *VariableActivations
 	containerTypes                     map[Type]bool
 	functionActivations                *FunctionActivations
 	inCondition                        bool
 	positionInfoEnabled                bool
 	Occurrences                        *Occurrences
+	NodeFacts                          *NodeFacts
 	variableOrigins                    map[*Variable]*Origin
+	occurrenceRoles                    map[ast.Expression]OccurrenceRole
 	memberOrigins                      map[Type]map[string]*Origin
 	MemberAccesses                     *MemberAccesses
 	Ranges                             *Ranges
@@ -104,6 +118,7 @@ type Checker struct {
 	isChecked                          bool
 	inCreate                           bool
 	inInvocation                       bool
+	currentInvokedExpression           ast.Expression
 	inAssignment                       bool
 	allowSelfResourceFieldInvalidation bool
 	Elaboration                        *Elaboration
@@ -116,6 +131,32 @@ type Checker struct {
 	expectedType                       Type
 	memberAccountAccessHandler         MemberAccountAccessHandlerFunc
 	lintEnabled                        bool
+	externalMutationReportOnly         bool
+	referenceInvalidationReportOnly    bool
+	reentrancyGuardEnabled             bool
+	featureGates                       map[TypeID]map[string]string
+	enabledFeatures                    common.ExperimentalFeatures
+	maximumExpressionDepth             int
+	expressionDepth                    int
+	expressionDepthLimitReached        bool
+	maximumDeclarationCount            int
+	maximumTypeComplexity              int
+	typeDepth                          int
+	typeComplexity                     int
+	declaringMembers                   bool
+	deferredDictionaryKeyTypeChecks    []dictionaryKeyTypeCheck
+	incrementalCheck                   *IncrementalCheck
+	functionCheckResults               map[*ast.FunctionDeclaration]functionCheckResult
+	typeCache                          *TypeCache
+	interner                           *common.Interner
+	diagnosticConfig                   *DiagnosticConfig
+}
+
+// dictionaryKeyTypeCheck is a deferred check of a dictionary key type
+//
+type dictionaryKeyTypeCheck struct {
+	keyType Type
+	ast.Range
 }
 
 type Option func(*Checker) error
@@ -142,7 +183,9 @@ func WithPredeclaredTypes(predeclaredTypes []TypeDeclaration) Option {
 		checker.PredeclaredTypes = predeclaredTypes
 
 		for _, declaration := range predeclaredTypes {
-			checker.declareTypeDeclaration(declaration)
+			if !checker.declareTypeDeclaration(declaration) {
+				continue
+			}
 
 			name := declaration.TypeDeclarationName()
 			checker.Elaboration.EffectivePredeclaredTypes[name] = declaration
@@ -215,6 +258,76 @@ func WithMemberAccountAccessHandler(handler MemberAccountAccessHandlerFunc) Opti
 	}
 }
 
+// WithFeatureGates returns a checker option which gates
+// the use of the given members behind the given pragmas.
+//
+func WithFeatureGates(gates ...FeatureGate) Option {
+	return func(checker *Checker) error {
+		if checker.featureGates == nil {
+			checker.featureGates = map[TypeID]map[string]string{}
+		}
+
+		for _, gate := range gates {
+			typeID := gate.ContainerType.ID()
+			members, ok := checker.featureGates[typeID]
+			if !ok {
+				members = map[string]string{}
+				checker.featureGates[typeID] = members
+			}
+			members[gate.Member] = gate.Pragma
+		}
+
+		return nil
+	}
+}
+
+// WithEnabledFeatures returns a checker option which enables
+// the given experimental features, see common.ExperimentalFeature.
+//
+func WithEnabledFeatures(features common.ExperimentalFeatures) Option {
+	return func(checker *Checker) error {
+		checker.enabledFeatures = features
+		return nil
+	}
+}
+
+// WithMaximumExpressionDepth returns a checker option which limits
+// the nesting depth of expressions.
+//
+// Zero means the depth is not limited.
+//
+func WithMaximumExpressionDepth(depth int) Option {
+	return func(checker *Checker) error {
+		checker.maximumExpressionDepth = depth
+		return nil
+	}
+}
+
+// WithMaximumDeclarationCount returns a checker option which limits
+// the number of declarations in a program, including nested declarations.
+//
+// Zero means the number is not limited.
+//
+func WithMaximumDeclarationCount(count int) Option {
+	return func(checker *Checker) error {
+		checker.maximumDeclarationCount = count
+		return nil
+	}
+}
+
+// WithMaximumTypeComplexity returns a checker option which limits
+// the complexity of types, i.e. the number of types a type consists of,
+// e.g. `[{String: Int}]` has a complexity of 4.
+//
+// Zero means the complexity is not limited.
+//
+func WithMaximumTypeComplexity(complexity int) Option {
+	return func(checker *Checker) error {
+		checker.maximumTypeComplexity = complexity
+		return nil
+	}
+}
+
 // WithPositionInfoEnabled returns a checker option which enables/disables
 // if position info recoding is enabled.
 //
@@ -226,10 +339,14 @@ func WithPositionInfoEnabled(enabled bool) Option {
 		if enabled {
 			checker.memberOrigins = map[Type]map[string]*Origin{}
 			checker.variableOrigins = map[*Variable]*Origin{}
+			checker.occurrenceRoles = map[ast.Expression]OccurrenceRole{}
 			checker.Occurrences = NewOccurrences()
 			checker.MemberAccesses = NewMemberAccesses()
 			checker.Ranges = NewRanges()
 			checker.FunctionInvocations = NewFunctionInvocations()
+			if checker.Program != nil {
+				checker.NodeFacts = NewNodeFacts(checker.Program.NodeIDs(), checker.Elaboration)
+			}
 		}
 
 		return nil
@@ -246,6 +363,83 @@ func WithLintingEnabled(enabled bool) Option {
 	}
 }
 
+// WithExternalMutationReportOnly returns a checker option which enables/disables
+// the report-only mode for external mutations of container fields.
+//
+// If enabled, mutations of array and dictionary fields outside of their composite,
+// e.g. `s.xs.append(1)`, are only reported as hints instead of errors.
+// This allows existing programs to be migrated.
+//
+func WithExternalMutationReportOnly(enabled bool) Option {
+	return func(checker *Checker) error {
+		checker.externalMutationReportOnly = enabled
+		return nil
+	}
+}
+
+// WithReferenceInvalidationReportOnly returns a checker option which enables/disables
+// the report-only mode for uses of invalidated references.
+//
+// If enabled, uses of references to resources after the resources were moved or destroyed
+// are only reported as hints instead of errors.
+// This allows existing programs to be migrated.
+//
+func WithReferenceInvalidationReportOnly(enabled bool) Option {
+	return func(checker *Checker) error {
+		checker.referenceInvalidationReportOnly = enabled
+		return nil
+	}
+}
+
+// WithReentrancyGuardEnabled returns a checker option which enables/disables
+// the predeclared field `isReentrant` of contracts.
+//
+// The field is only available if the interpreter exposes it,
+// see interpreter.ReentrancyHandlingGuard.
+//
+func WithReentrancyGuardEnabled(enabled bool) Option {
+	return func(checker *Checker) error {
+		checker.reentrancyGuardEnabled = enabled
+		return nil
+	}
+}
+
+// WithTypeCache returns a checker option which sets the given type cache,
+// e.g. to share it between the checkers of a session.
+//
+// See TypeCache for details.
+//
+func WithTypeCache(typeCache *TypeCache) Option {
+	return func(checker *Checker) error {
+		checker.typeCache = typeCache
+		return nil
+	}
+}
+
+// WithInterner returns a checker option which sets the given interner,
+// e.g. to share it between the checkers of a session.
+//
+// See common.Interner for details.
+//
+func WithInterner(interner *common.Interner) Option {
+	return func(checker *Checker) error {
+		checker.interner = interner
+		return nil
+	}
+}
+
+// WithIncrementalCheck returns a checker option which reuses
+// the results of a previous check for unaffected function declarations.
+//
+// See IncrementalCheck for details.
+//
+func WithIncrementalCheck(incrementalCheck *IncrementalCheck) Option {
+	return func(checker *Checker) error {
+		checker.incrementalCheck = incrementalCheck
+		return nil
+	}
+}
+
 func NewChecker(program *ast.Program, location common.Location, options ...Option) (*Checker, error) {
 
 	if location == nil {
@@ -261,14 +455,17 @@ func NewChecker(program *ast.Program, location common.Location, options ...Optio
 	)
 
 	checker := &Checker{
-		Program:             program,
-		Location:            location,
-		valueActivations:    valueActivations,
-		resources:           NewResources(),
-		typeActivations:     typeActivations,
-		functionActivations: functionActivations,
-		containerTypes:      map[Type]bool{},
-		Elaboration:         NewElaboration(),
+		Program:                program,
+		Location:               location,
+		valueActivations:       valueActivations,
+		resources:              NewResources(),
+		reportedResourceLosses: map[*Variable]struct{}{},
+		integerIntervals:       map[*Variable]integerInterval{},
+		typeActivations:        typeActivations,
+		functionActivations:    functionActivations,
+		containerTypes:         map[Type]bool{},
+		Elaboration:            NewElaboration(),
+		functionCheckResults:   map[*ast.FunctionDeclaration]functionCheckResult{},
 	}
 
 	checker.beforeExtractor = NewBeforeExtractor(checker.report)
@@ -280,6 +477,14 @@ func NewChecker(program *ast.Program, location common.Location, options ...Optio
 		}
 	}
 
+	if checker.typeCache == nil {
+		checker.typeCache = NewTypeCache()
+	}
+
+	if checker.interner == nil {
+		checker.interner = common.NewInterner()
+	}
+
 	err := checker.CheckerError()
 	if err != nil {
 		return nil, err
@@ -295,10 +500,14 @@ func (checker *Checker) SubChecker(program *ast.Program, location common.Locatio
 		WithPredeclaredValues(checker.PredeclaredValues),
 		WithPredeclaredTypes(checker.PredeclaredTypes),
 		WithAccessCheckMode(checker.accessCheckMode),
+		WithExternalMutationReportOnly(checker.externalMutationReportOnly),
+		WithReferenceInvalidationReportOnly(checker.referenceInvalidationReportOnly),
 		WithValidTopLevelDeclarationsHandler(checker.validTopLevelDeclarationsHandler),
 		WithCheckHandler(checker.checkHandler),
 		WithImportHandler(checker.importHandler),
 		WithLocationHandler(checker.locationHandler),
+		WithTypeCache(checker.typeCache),
+		WithInterner(checker.interner),
 	)
 }
 
@@ -328,7 +537,16 @@ func (checker *Checker) declareValue(declaration ValueDeclaration) *Variable {
 	return variable
 }
 
-func (checker *Checker) declareTypeDeclaration(declaration TypeDeclaration) {
+// declareTypeDeclaration declares the given type declaration,
+// if it is available in the checked location.
+// It returns true if the type was declared
+//
+func (checker *Checker) declareTypeDeclaration(declaration TypeDeclaration) bool {
+
+	if !declaration.TypeDeclarationAvailable(checker.Location) {
+		return false
+	}
+
 	identifier := ast.Identifier{
 		Identifier: declaration.TypeDeclarationName(),
 		Pos:        declaration.TypeDeclarationPosition(),
@@ -359,6 +577,8 @@ func (checker *Checker) declareTypeDeclaration(declaration TypeDeclaration) {
 	if checker.positionInfoEnabled {
 		checker.recordVariableDeclarationOccurrence(identifier.Identifier, variable)
 	}
+
+	return true
 }
 
 func (checker *Checker) IsChecked() bool {
@@ -407,7 +627,37 @@ func (checker *Checker) report(err error) {
 	checker.errors = append(checker.errors, err)
 }
 
+// FeatureEnabled returns true if the given experimental feature is enabled
+//
+func (checker *Checker) FeatureEnabled(feature common.ExperimentalFeature) bool {
+	return checker.enabledFeatures.Enabled(feature)
+}
+
+// checkExperimentalFeature returns true if the given experimental feature is enabled.
+// If it is not enabled, an error is reported for the given use of the feature.
+//
+// It should be called when the semantics of an experimental feature are checked,
+// see common.ExperimentalFeature
+//
+func (checker *Checker) checkExperimentalFeature(feature common.ExperimentalFeature, use ast.HasPosition) bool {
+	if checker.FeatureEnabled(feature) {
+		return true
+	}
+
+	checker.report(
+		&ExperimentalFeatureNotEnabledError{
+			Feature: feature,
+			Range:   ast.NewRangeFromPositioned(use),
+		},
+	)
+
+	return false
+}
+
 func (checker *Checker) hint(hint Hint) {
+	if !checker.diagnosticConfig.IsReported(diagnosticCode(hint), hint.StartPosition().Line) {
+		return
+	}
 	checker.hints = append(checker.hints, hint)
 }
 
@@ -441,6 +691,16 @@ func (checker *Checker) UserDefinedValues() map[string]*Variable {
 
 func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 
+	if !checker.checkDeclarationCount(program) {
+		return nil
+	}
+
+	// Declare pragmas first, they may affect the checking of all other declarations
+
+	for _, declaration := range program.PragmaDeclarations() {
+		checker.declarePragma(declaration)
+	}
+
 	for _, declaration := range program.ImportDeclarations() {
 		checker.declareImportDeclaration(declaration)
 	}
@@ -453,11 +713,18 @@ func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 			checker.Elaboration.InterfaceTypes[typedType.ID()] = typedType
 		case *CompositeType:
 			checker.Elaboration.CompositeTypes[typedType.ID()] = typedType
+		case *EntitlementType:
+			checker.Elaboration.EntitlementTypes[typedType.ID()] = typedType
 		default:
 			panic(errors.NewUnreachableError())
 		}
 	}
 
+	for _, declaration := range program.EntitlementDeclarations() {
+		entitlementType := checker.declareEntitlementType(declaration)
+		checker.Elaboration.EntitlementTypes[entitlementType.ID()] = entitlementType
+	}
+
 	for _, declaration := range program.InterfaceDeclarations() {
 		interfaceType := checker.declareInterfaceType(declaration)
 
@@ -467,6 +734,11 @@ func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 		VisitThisAndNested(interfaceType, registerInElaboration)
 	}
 
+	// Validity checks of dictionary key types depend on the members of composite types,
+	// so they are deferred until the members of all types are declared
+
+	checker.declaringMembers = true
+
 	for _, declaration := range program.CompositeDeclarations() {
 		compositeType := checker.declareCompositeType(declaration)
 
@@ -496,6 +768,13 @@ func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 		checker.declareTransactionDeclaration(declaration)
 	}
 
+	checker.declaringMembers = false
+
+	for _, check := range checker.deferredDictionaryKeyTypeChecks {
+		checker.checkDictionaryKeyType(check.keyType, check.Range)
+	}
+	checker.deferredDictionaryKeyTypeChecks = nil
+
 	// Check all declarations
 
 	declarations := program.Declarations()
@@ -513,6 +792,8 @@ func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 		checker.declareGlobalDeclaration(declaration)
 	}
 
+	checker.checkMemoizedFunctions(program)
+
 	return nil
 }
 
@@ -665,7 +946,20 @@ func (checker *Checker) checkTypeCompatibility(expression ast.Expression, valueT
 		}
 	}
 
-	return IsSubType(valueType, targetType)
+	return checker.IsSubType(valueType, targetType)
+}
+
+// IsSubType returns true if the given subtype is a subtype of the given supertype.
+//
+// The result is memoized in the checker's type cache,
+// unless the members of types are still being declared
+//
+func (checker *Checker) IsSubType(subType Type, superType Type) bool {
+	if checker.declaringMembers {
+		return IsSubType(subType, superType)
+	}
+
+	return checker.typeCache.IsSubType(subType, superType)
 }
 
 // CheckIntegerLiteral checks that the value of the integer literal
@@ -899,6 +1193,7 @@ func (checker *Checker) findAndCheckValueVariable(identifierExpression *ast.Iden
 			identifier.StartPosition(),
 			identifier.EndPosition(),
 			variable,
+			checker.occurrenceRole(identifierExpression),
 		)
 	}
 
@@ -907,9 +1202,29 @@ func (checker *Checker) findAndCheckValueVariable(identifierExpression *ast.Iden
 
 // ConvertType converts an AST type representation to a sema type
 func (checker *Checker) ConvertType(t ast.Type) Type {
+	if !checker.enterType(t) {
+		return InvalidType
+	}
+	defer checker.leaveType()
+
 	switch t := t.(type) {
 	case *ast.NominalType:
-		return checker.convertNominalType(t)
+		ty := checker.convertNominalType(t)
+
+		// Entitlements are not value types,
+		// they may only be used in access modifiers and reference types
+
+		if entitlementType, ok := ty.(*EntitlementType); ok {
+			checker.report(
+				&InvalidEntitlementUsageError{
+					Type:  entitlementType,
+					Range: ast.NewRangeFromPositioned(t),
+				},
+			)
+			return InvalidType
+		}
+
+		return ty
 
 	case *ast.VariableSizedType:
 		return checker.convertVariableSizedType(t)
@@ -1172,9 +1487,21 @@ func (checker *Checker) convertRestrictedType(t *ast.RestrictedType) Type {
 func (checker *Checker) convertReferenceType(t *ast.ReferenceType) Type {
 	ty := checker.ConvertType(t.Type)
 
+	// An entitled reference type `auth(E1, E2) &T` is not fully authorized,
+	// it is only granted the given entitlements
+
+	authorized := t.Authorized
+	var entitlements []*EntitlementType
+
+	if len(t.Entitlements) > 0 {
+		authorized = false
+		entitlements = checker.convertEntitlements(t.Entitlements)
+	}
+
 	return &ReferenceType{
-		Authorized: t.Authorized,
-		Type:       ty,
+		Authorized:   authorized,
+		Entitlements: entitlements,
+		Type:         ty,
 	}
 }
 
@@ -1182,13 +1509,18 @@ func (checker *Checker) convertDictionaryType(t *ast.DictionaryType) Type {
 	keyType := checker.ConvertType(t.KeyType)
 	valueType := checker.ConvertType(t.ValueType)
 
-	if !IsValidDictionaryKeyType(keyType) {
-		checker.report(
-			&InvalidDictionaryKeyTypeError{
-				Type:  keyType,
-				Range: ast.NewRangeFromPositioned(t.KeyType),
+	keyTypeRange := ast.NewRangeFromPositioned(t.KeyType)
+
+	if _, ok := keyType.(*CompositeType); ok && checker.declaringMembers {
+		checker.deferredDictionaryKeyTypeChecks = append(
+			checker.deferredDictionaryKeyTypeChecks,
+			dictionaryKeyTypeCheck{
+				keyType: keyType,
+				Range:   keyTypeRange,
 			},
 		)
+	} else {
+		checker.checkDictionaryKeyType(keyType, keyTypeRange)
 	}
 
 	return &DictionaryType{
@@ -1197,6 +1529,19 @@ func (checker *Checker) convertDictionaryType(t *ast.DictionaryType) Type {
 	}
 }
 
+func (checker *Checker) checkDictionaryKeyType(keyType Type, keyTypeRange ast.Range) {
+	if IsValidDictionaryKeyType(keyType) {
+		return
+	}
+
+	checker.report(
+		&InvalidDictionaryKeyTypeError{
+			Type:  keyType,
+			Range: keyTypeRange,
+		},
+	)
+}
+
 func (checker *Checker) convertOptionalType(t *ast.OptionalType) Type {
 	ty := checker.ConvertType(t.Type)
 	return &OptionalType{
@@ -1300,6 +1645,7 @@ func (checker *Checker) findAndCheckTypeVariable(identifier ast.Identifier, reco
 			identifier.StartPosition(),
 			identifier.EndPosition(),
 			variable,
+			OccurrenceRoleRead,
 		)
 	}
 
@@ -1312,6 +1658,8 @@ func (checker *Checker) convertNominalType(t *ast.NominalType) Type {
 		return InvalidType
 	}
 
+	checker.checkDeprecation(variable.Identifier, variable.DocString, t.Identifier)
+
 	ty := variable.Type
 
 	var resolvedIdentifiers []ast.Identifier
@@ -1400,13 +1748,18 @@ func (checker *Checker) parameters(parameterList *ast.ParameterList) []*Paramete
 				IsResource: parameter.TypeAnnotation.IsResource,
 				Type:       convertedParameterType,
 			},
+			Indexed: parameter.Indexed,
 		}
 	}
 
 	return parameters
 }
 
-func (checker *Checker) recordVariableReferenceOccurrence(startPos, endPos ast.Position, variable *Variable) {
+func (checker *Checker) recordVariableReferenceOccurrence(
+	startPos, endPos ast.Position,
+	variable *Variable,
+	role OccurrenceRole,
+) {
 	if !checker.positionInfoEnabled {
 		return
 	}
@@ -1428,7 +1781,7 @@ func (checker *Checker) recordVariableReferenceOccurrence(startPos, endPos ast.P
 		}
 		checker.variableOrigins[variable] = origin
 	}
-	checker.Occurrences.Put(startPos, endPos, origin)
+	checker.Occurrences.Put(startPos, endPos, origin, role)
 }
 
 func (checker *Checker) recordVariableDeclarationOccurrence(name string, variable *Variable) {
@@ -1437,7 +1790,40 @@ func (checker *Checker) recordVariableDeclarationOccurrence(name string, variabl
 	}
 	startPos := *variable.Pos
 	endPos := variable.Pos.Shifted(len(name) - 1)
-	checker.recordVariableReferenceOccurrence(startPos, endPos, variable)
+	checker.recordVariableReferenceOccurrence(startPos, endPos, variable, OccurrenceRoleDeclaration)
+}
+
+// setOccurrenceRole sets the role of the occurrence of the declaration
+// which the given expression refers to, e.g. an identifier or a member expression.
+// It must be called before the expression is checked.
+// Occurrences without a role are reads
+//
+func (checker *Checker) setOccurrenceRole(expression ast.Expression, role OccurrenceRole) {
+	if !checker.positionInfoEnabled {
+		return
+	}
+	checker.occurrenceRoles[expression] = role
+}
+
+// setTransferOccurrenceRole sets the role of the occurrence
+// of the given transferred value to a move, if the transfer is a move
+//
+func (checker *Checker) setTransferOccurrenceRole(value ast.Expression, transfer *ast.Transfer) {
+	if transfer == nil {
+		return
+	}
+
+	switch transfer.Operation {
+	case ast.TransferOperationMove, ast.TransferOperationMoveForced:
+		checker.setOccurrenceRole(value, OccurrenceRoleMove)
+	}
+}
+
+func (checker *Checker) occurrenceRole(expression ast.Expression) OccurrenceRole {
+	if role, ok := checker.occurrenceRoles[expression]; ok {
+		return role
+	}
+	return OccurrenceRoleRead
 }
 
 func (checker *Checker) recordFieldDeclarationOrigin(
@@ -1464,6 +1850,7 @@ func (checker *Checker) recordFieldDeclarationOrigin(
 		startPosition,
 		endPosition,
 		origin,
+		OccurrenceRoleDeclaration,
 	)
 
 	return origin
@@ -1492,6 +1879,7 @@ func (checker *Checker) recordFunctionDeclarationOrigin(
 		startPosition,
 		endPosition,
 		origin,
+		OccurrenceRoleDeclaration,
 	)
 	return origin
 }
@@ -1514,7 +1902,10 @@ func (checker *Checker) leaveValueScope(getEndPosition func() ast.Position, chec
 //    when detecting resource use after invalidation in loops
 
 // checkResourceLoss reports an error if there is a variable in the current scope
-// that has a resource type and which was not moved or destroyed
+// that has a resource type and which was not moved or destroyed.
+//
+// The loss of a resource is only reported once, for the first branch in which it is lost,
+// e.g. the first return statement
 //
 func (checker *Checker) checkResourceLoss(depth int) {
 
@@ -1524,6 +1915,11 @@ func (checker *Checker) checkResourceLoss(depth int) {
 			variable.DeclarationKind != common.DeclarationKindSelf &&
 			!checker.resources.Get(variable).DefinitivelyInvalidated {
 
+			if _, ok := checker.reportedResourceLosses[variable]; ok {
+				return
+			}
+			checker.reportedResourceLosses[variable] = struct{}{}
+
 			checker.report(
 				&ResourceLossError{
 					Range: ast.Range{
@@ -1649,17 +2045,30 @@ func (checker *Checker) checkWithReturnInfo(
 	return check()
 }
 
+// checkWithInitializedMembers runs the given type checking function
+// with the given temporary field initializations,
+// and records the resulting initializations in them
+//
 func (checker *Checker) checkWithInitializedMembers(
 	check TypeCheckFunc,
-	temporaryInitializedMembers *MemberSet,
+	temporaryInitializations *fieldInitializations,
 ) Type {
-	if temporaryInitializedMembers != nil {
+	if temporaryInitializations != nil {
 		functionActivation := checker.functionActivations.Current()
 		initializationInfo := functionActivation.InitializationInfo
 		initialInitializedMembers := initializationInfo.InitializedFieldMembers
-		initializationInfo.InitializedFieldMembers = temporaryInitializedMembers
+		initialUninitializedBranches := initializationInfo.UninitializedFieldBranches
+		initializationInfo.InitializedFieldMembers = temporaryInitializations.initializedMembers
+		initializationInfo.UninitializedFieldBranches = temporaryInitializations.uninitializedBranches
 		defer func() {
+			// NOTE: the initializations might have been replaced
+			// while checking, e.g. when merging nested branches
+
+			temporaryInitializations.initializedMembers = initializationInfo.InitializedFieldMembers
+			temporaryInitializations.uninitializedBranches = initializationInfo.UninitializedFieldBranches
+
 			initializationInfo.InitializedFieldMembers = initialInitializedMembers
+			initializationInfo.UninitializedFieldBranches = initialUninitializedBranches
 		}()
 	}
 
@@ -1767,10 +2176,9 @@ func (checker *Checker) checkPotentiallyUnevaluated(check TypeCheckFunc) Type {
 	initialReturnInfo := functionActivation.ReturnInfo
 	temporaryReturnInfo := initialReturnInfo.Clone()
 
-	var temporaryInitializedMembers *MemberSet
+	var temporaryInitializations *fieldInitializations
 	if functionActivation.InitializationInfo != nil {
-		initialInitializedMembers := functionActivation.InitializationInfo.InitializedFieldMembers
-		temporaryInitializedMembers = initialInitializedMembers.Clone()
+		temporaryInitializations = functionActivation.InitializationInfo.branch()
 	}
 
 	initialResources := checker.resources
@@ -1779,7 +2187,7 @@ func (checker *Checker) checkPotentiallyUnevaluated(check TypeCheckFunc) Type {
 	result := checker.checkBranch(
 		check,
 		temporaryReturnInfo,
-		temporaryInitializedMembers,
+		temporaryInitializations,
 		temporaryResources,
 	)
 
@@ -1995,12 +2403,18 @@ func (checker *Checker) withSelfResourceInvalidationAllowed(f func()) {
 }
 
 const ResourceOwnerFieldName = "owner"
+const ContractIsReentrantFieldName = "isReentrant"
 const ResourceUUIDFieldName = "uuid"
 
 const contractAccountFieldDocString = `
 The account where the contract is deployed in
 `
 
+const contractIsReentrantFieldDocString = `
+True if the contract is currently re-entered, i.e. a function of the contract was called from outside of the contract,
+while another function of the contract is still executing
+`
+
 const resourceOwnerFieldDocString = `
 The account owning the resource, i.e. the account that stores the account, or nil if the resource is not currently in storage
 `
@@ -2073,6 +2487,22 @@ func (checker *Checker) predeclaredMembers(containerType Type) []*Member {
 				contractAccountFieldDocString,
 			)
 
+			// If the re-entrancy guard is enabled,
+			// all contracts have a predeclared member
+			// `priv let isReentrant: Bool`,
+			// which is ignored in serialization
+
+			if checker.reentrancyGuardEnabled {
+				addPredeclaredMember(
+					ContractIsReentrantFieldName,
+					BoolType,
+					common.DeclarationKindField,
+					ast.AccessPrivate,
+					true,
+					contractIsReentrantFieldDocString,
+				)
+			}
+
 		case common.CompositeKindResource:
 
 			// All resources have two predeclared fields:
@@ -2397,6 +2827,11 @@ func (checker *Checker) visitExpressionWithForceType(
 	forceType bool,
 ) (visibleType Type, actualType Type) {
 
+	if !checker.enterExpression(expr) {
+		return InvalidType, InvalidType
+	}
+	defer checker.leaveExpression()
+
 	// Cache the current contextually expected type, and set the `expectedType`
 	// as the new contextually expected type.
 	prevExpectedType := checker.expectedType
@@ -2413,11 +2848,15 @@ func (checker *Checker) visitExpressionWithForceType(
 		panic(errors.NewUnreachableError())
 	}
 
+	if checker.NodeFacts != nil {
+		checker.NodeFacts.recordExpressionType(expr, actualType)
+	}
+
 	if forceType &&
 		expectedType != nil &&
 		!expectedType.IsInvalidType() &&
 		actualType != InvalidType &&
-		!IsSubType(actualType, expectedType) {
+		!checker.IsSubType(actualType, expectedType) {
 
 		checker.report(
 			&TypeMismatchError{
@@ -19,11 +19,16 @@
 package sema
 
 import (
+	"reflect"
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
 )
 
 func TestOptionalSubtyping(t *testing.T) {
@@ -273,3 +278,146 @@ func TestFunctionSubtyping(t *testing.T) {
 		)
 	})
 }
+
+func TestCheckExperimentalFeature(t *testing.T) {
+
+	t.Parallel()
+
+	const feature common.ExperimentalFeature = "test"
+
+	use := ast.Range{
+		StartPos: ast.Position{Offset: 1, Line: 2, Column: 3},
+		EndPos:   ast.Position{Offset: 4, Line: 2, Column: 6},
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := NewChecker(
+			&ast.Program{},
+			common.StringLocation("test"),
+			WithEnabledFeatures(common.NewExperimentalFeatures(feature)),
+		)
+		require.NoError(t, err)
+
+		assert.True(t, checker.FeatureEnabled(feature))
+		assert.True(t, checker.checkExperimentalFeature(feature, use))
+		assert.Empty(t, checker.errors)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := NewChecker(
+			&ast.Program{},
+			common.StringLocation("test"),
+		)
+		require.NoError(t, err)
+
+		assert.False(t, checker.FeatureEnabled(feature))
+		assert.False(t, checker.checkExperimentalFeature(feature, use))
+		assert.Equal(t,
+			[]error{
+				&ExperimentalFeatureNotEnabledError{
+					Feature: feature,
+					Range:   use,
+				},
+			},
+			checker.errors,
+		)
+	})
+}
+
+func TestCheckerInterner(t *testing.T) {
+
+	t.Parallel()
+
+	stringData := func(s string) uintptr {
+		return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	}
+
+	check := func(t *testing.T, code string, options ...Option) *Checker {
+		program, err := parser2.ParseProgram(code)
+		require.NoError(t, err)
+
+		checker, err := NewChecker(
+			program,
+			common.StringLocation("test"),
+			options...,
+		)
+		require.NoError(t, err)
+
+		err = checker.Check()
+		require.NoError(t, err)
+
+		return checker
+	}
+
+	memberIdentifier := func(checker *Checker, typeName string, memberName string) string {
+		variable := checker.typeActivations.Find(typeName)
+		member, ok := variable.Type.(*CompositeType).Members.Get(memberName)
+		require.True(t, ok)
+		return member.Identifier.Identifier
+	}
+
+	t.Run("member names", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker := check(t, `
+          pub struct S1 {
+              pub let foo: Int
+              init() { self.foo = 1 }
+          }
+
+          pub struct S2 {
+              pub let foo: Int
+              init() { self.foo = 2 }
+          }
+        `)
+
+		assert.Equal(t,
+			stringData(memberIdentifier(checker, "S1", "foo")),
+			stringData(memberIdentifier(checker, "S2", "foo")),
+		)
+	})
+
+	t.Run("session", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Checkers of the same session share the interner,
+		// checkers of different sessions do not
+
+		code := `
+          pub struct S {
+              pub let foo: Int
+              init() { self.foo = 1 }
+          }
+        `
+
+		interner := common.NewInterner()
+
+		first := check(t, code, WithInterner(interner))
+		second := check(t, code, WithInterner(interner))
+		other := check(t, code)
+
+		assert.Equal(t,
+			stringData(memberIdentifier(first, "S", "foo")),
+			stringData(memberIdentifier(second, "S", "foo")),
+		)
+		assert.NotEqual(t,
+			stringData(memberIdentifier(first, "S", "foo")),
+			stringData(memberIdentifier(other, "S", "foo")),
+		)
+
+		assert.Same(t, first.interner, interner)
+		assert.NotSame(t, other.interner, interner)
+
+		subChecker, err := first.SubChecker(nil, common.StringLocation("sub"))
+		require.NoError(t, err)
+		assert.Same(t, interner, subChecker.interner)
+	})
+}
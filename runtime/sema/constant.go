@@ -0,0 +1,528 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"math"
+	"math/big"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/onflow/cadence/fixedpoint"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// ConstantValue is the value of a constant expression,
+// i.e. an expression which only consists of literals and operations on them,
+// and which is evaluated at check time.
+//
+// The interpreter uses the value instead of re-evaluating the expression.
+//
+type ConstantValue struct {
+	// Type is the type of the value
+	Type Type
+	// Value is a *big.Int if the type is an integer type,
+	// a *big.Int scaled by the scale of the type if the type is a fixed-point type,
+	// a string if the type is String, and a bool if the type is Bool
+	Value interface{}
+}
+
+// constantValue returns the value of the given expression,
+// if it is a literal or a constant expression
+//
+func (checker *Checker) constantValue(expression ast.Expression) (ConstantValue, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerExpression:
+		integerType := checker.Elaboration.IntegerExpressionType[expression]
+		if !IsSameTypeKind(integerType, IntegerType) {
+			return ConstantValue{}, false
+		}
+		return ConstantValue{
+			Type:  integerType,
+			Value: expression.Value,
+		}, true
+
+	case *ast.FixedPointExpression:
+		fixedPointType := checker.Elaboration.FixedPointExpression[expression]
+		ranged, ok := fixedPointType.(FractionalRangedType)
+		if !ok || fixedPointTypeRangeMin(fixedPointType) == nil {
+			return ConstantValue{}, false
+		}
+		if !CheckFixedPointLiteral(expression, fixedPointType, nil) {
+			return ConstantValue{}, false
+		}
+		return ConstantValue{
+			Type: fixedPointType,
+			Value: fixedpoint.ConvertToFixedPointBigInt(
+				expression.Negative,
+				expression.UnsignedInteger,
+				expression.Fractional,
+				expression.Scale,
+				ranged.Scale(),
+			),
+		}, true
+
+	case *ast.StringExpression:
+		return ConstantValue{
+			Type:  StringType,
+			Value: expression.Value,
+		}, true
+
+	case *ast.BoolExpression:
+		return ConstantValue{
+			Type:  BoolType,
+			Value: expression.Value,
+		}, true
+
+	default:
+		constant, ok := checker.Elaboration.ConstantExpressionValues[expression]
+		return constant, ok
+	}
+}
+
+// foldBinaryExpression evaluates the given binary expression,
+// if both operands are constant and the operation has a constant result.
+//
+// Integer and fixed-point overflows and underflows are reported.
+// Expressions which fail at run-time, e.g. divisions by zero, are not folded.
+//
+func (checker *Checker) foldBinaryExpression(expression *ast.BinaryExpression) {
+	left, ok := checker.constantValue(expression.Left)
+	if !ok {
+		return
+	}
+
+	right, ok := checker.constantValue(expression.Right)
+	if !ok {
+		return
+	}
+
+	if !left.Type.Equal(right.Type) {
+		return
+	}
+
+	var result interface{}
+	resultType := left.Type
+
+	switch leftValue := left.Value.(type) {
+	case *big.Int:
+		rightValue := right.Value.(*big.Int)
+
+		switch expression.Operation {
+		case ast.OperationEqual,
+			ast.OperationNotEqual,
+			ast.OperationLess,
+			ast.OperationLessEqual,
+			ast.OperationGreater,
+			ast.OperationGreaterEqual:
+
+			result = compareConstantIntegers(expression.Operation, leftValue, rightValue)
+			resultType = BoolType
+
+		default:
+			var numberResult *big.Int
+			if IsSameTypeKind(resultType, FixedPointType) {
+				numberResult = foldFixedPointOperation(expression.Operation, resultType, leftValue, rightValue)
+			} else {
+				numberResult = foldIntegerOperation(expression.Operation, leftValue, rightValue)
+			}
+			if numberResult == nil {
+				return
+			}
+
+			numberResult, ok = checker.checkConstantNumberRange(expression, resultType, numberResult)
+			if !ok {
+				return
+			}
+
+			result = numberResult
+		}
+
+	case string:
+		rightValue := right.Value.(string)
+
+		switch expression.Operation {
+		case ast.OperationEqual:
+			result = norm.NFC.String(leftValue) == norm.NFC.String(rightValue)
+		case ast.OperationNotEqual:
+			result = norm.NFC.String(leftValue) != norm.NFC.String(rightValue)
+		default:
+			return
+		}
+
+		resultType = BoolType
+
+	case bool:
+		rightValue := right.Value.(bool)
+
+		switch expression.Operation {
+		case ast.OperationEqual:
+			result = leftValue == rightValue
+		case ast.OperationNotEqual:
+			result = leftValue != rightValue
+		case ast.OperationAnd:
+			result = leftValue && rightValue
+		case ast.OperationOr:
+			result = leftValue || rightValue
+		default:
+			return
+		}
+
+	default:
+		return
+	}
+
+	checker.Elaboration.ConstantExpressionValues[expression] = ConstantValue{
+		Type:  resultType,
+		Value: result,
+	}
+}
+
+// foldUnaryExpression evaluates the given unary expression,
+// if the operand is constant.
+//
+func (checker *Checker) foldUnaryExpression(expression *ast.UnaryExpression) {
+	operand, ok := checker.constantValue(expression.Expression)
+	if !ok {
+		return
+	}
+
+	var result interface{}
+
+	switch value := operand.Value.(type) {
+	case *big.Int:
+		if expression.Operation != ast.OperationMinus {
+			return
+		}
+
+		result, ok = checker.checkConstantNumberRange(
+			expression,
+			operand.Type,
+			new(big.Int).Neg(value),
+		)
+		if !ok {
+			return
+		}
+
+	case bool:
+		if expression.Operation != ast.OperationNegate {
+			return
+		}
+
+		result = !value
+
+	default:
+		return
+	}
+
+	checker.Elaboration.ConstantExpressionValues[expression] = ConstantValue{
+		Type:  operand.Type,
+		Value: result,
+	}
+}
+
+// foldIntegerOperation returns the result of the given arithmetic or bitwise operation,
+// or nil if the operation is not folded.
+//
+// The semantics of the operation must match the interpreter's semantics for all integer types,
+// which only differ for the division and remainder of negative integers,
+// and for shifts.
+//
+func foldIntegerOperation(operation ast.Operation, left, right *big.Int) *big.Int {
+	result := new(big.Int)
+
+	switch operation {
+	case ast.OperationPlus:
+		return result.Add(left, right)
+
+	case ast.OperationMinus:
+		return result.Sub(left, right)
+
+	case ast.OperationMul:
+		return result.Mul(left, right)
+
+	case ast.OperationDiv, ast.OperationMod:
+		if left.Sign() < 0 || right.Sign() <= 0 {
+			return nil
+		}
+
+		if operation == ast.OperationDiv {
+			return result.Quo(left, right)
+		}
+		return result.Rem(left, right)
+
+	case ast.OperationBitwiseOr:
+		return result.Or(left, right)
+
+	case ast.OperationBitwiseXor:
+		return result.Xor(left, right)
+
+	case ast.OperationBitwiseAnd:
+		return result.And(left, right)
+	}
+
+	return nil
+}
+
+// foldFixedPointOperation returns the result of the given arithmetic operation
+// on the given scaled fixed-point values, or nil if the operation is not folded.
+//
+// The semantics of the operation must match the interpreter's semantics:
+// The results of multiplications and divisions of the 64-bit fixed-point types are rounded down,
+// the results of the 128-bit fixed-point types are truncated.
+// Remainders are not folded.
+//
+func foldFixedPointOperation(operation ast.Operation, fixedPointType Type, left, right *big.Int) *big.Int {
+	ranged := fixedPointType.(FractionalRangedType)
+	factor := new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(uint64(ranged.Scale())), nil)
+
+	result := new(big.Int)
+
+	divide := result.Quo
+	if ranged.Scale() == Fix64Scale {
+		divide = result.Div
+	}
+
+	switch operation {
+	case ast.OperationPlus:
+		return result.Add(left, right)
+
+	case ast.OperationMinus:
+		return result.Sub(left, right)
+
+	case ast.OperationMul:
+		result.Mul(left, right)
+		return divide(result, factor)
+
+	case ast.OperationDiv:
+		if right.Sign() == 0 {
+			return nil
+		}
+
+		result.Mul(left, factor)
+		divide(result, right)
+
+		// The interpreter does not report overflows of UFix64 divisions
+		if fixedPointType == UFix64Type &&
+			result.Cmp(fixedPointTypeRangeMax(fixedPointType)) > 0 {
+
+			return nil
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+func compareConstantIntegers(operation ast.Operation, left, right *big.Int) bool {
+	comparison := left.Cmp(right)
+
+	switch operation {
+	case ast.OperationEqual:
+		return comparison == 0
+	case ast.OperationNotEqual:
+		return comparison != 0
+	case ast.OperationLess:
+		return comparison < 0
+	case ast.OperationLessEqual:
+		return comparison <= 0
+	case ast.OperationGreater:
+		return comparison > 0
+	case ast.OperationGreaterEqual:
+		return comparison >= 0
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// checkConstantNumberRange checks that the given result of a constant expression
+// fits into the range of the given integer or fixed-point type, and reports an error if it does not.
+//
+// Word types wrap around, so the result is truncated for them.
+//
+func (checker *Checker) checkConstantNumberRange(
+	expression ast.Expression,
+	integerType Type,
+	value *big.Int,
+) (*big.Int, bool) {
+
+	if fractionalRanged, ok := integerType.(FractionalRangedType); ok {
+		return checker.checkConstantFixedPointRange(expression, fractionalRanged, value)
+	}
+
+	ranged, ok := integerType.(IntegerRangedType)
+	if !ok {
+		return nil, false
+	}
+
+	minInt := ranged.MinInt()
+	maxInt := ranged.MaxInt()
+
+	switch integerType {
+	case Word8Type, Word16Type, Word32Type, Word64Type:
+		modulus := new(big.Int).Add(maxInt, big.NewInt(1))
+		return value.Mod(value, modulus), true
+	}
+
+	if !checkIntegerRange(value, minInt, maxInt) {
+		checker.report(
+			&InvalidConstantExpressionRangeError{
+				ExpectedType:   integerType,
+				ExpectedMinInt: minInt,
+				ExpectedMaxInt: maxInt,
+				Range:          ast.NewRangeFromPositioned(expression),
+			},
+		)
+
+		return nil, false
+	}
+
+	return value, true
+}
+
+// checkConstantFixedPointRange checks that the given scaled result of a constant expression
+// fits into the range of the given fixed-point type, and reports an error if it does not.
+//
+func (checker *Checker) checkConstantFixedPointRange(
+	expression ast.Expression,
+	fixedPointType FractionalRangedType,
+	value *big.Int,
+) (*big.Int, bool) {
+
+	minValue := fixedPointTypeRangeMin(fixedPointType)
+	maxValue := fixedPointTypeRangeMax(fixedPointType)
+	if minValue == nil || maxValue == nil {
+		return nil, false
+	}
+
+	if !checkIntegerRange(value, minValue, maxValue) {
+		checker.report(
+			&InvalidFixedPointConstantExpressionRangeError{
+				ExpectedType:          fixedPointType,
+				ExpectedMinInt:        fixedPointType.MinInt(),
+				ExpectedMinFractional: fixedPointType.MinFractional(),
+				ExpectedMaxInt:        fixedPointType.MaxInt(),
+				ExpectedMaxFractional: fixedPointType.MaxFractional(),
+				Range:                 ast.NewRangeFromPositioned(expression),
+			},
+		)
+
+		return nil, false
+	}
+
+	return value, true
+}
+
+// fixedPointTypeRangeMin returns the minimum scaled value of the given fixed-point type,
+// or nil if the type is not a concrete fixed-point type
+//
+func fixedPointTypeRangeMin(fixedPointType Type) *big.Int {
+	switch fixedPointType {
+	case Fix64Type:
+		return fixedPointFix64MinBig
+	case UFix64Type:
+		return fixedPointUFix64MinBig
+	case Fix128Type:
+		return Fix128TypeMinBig
+	case UFix128Type:
+		return UFix128TypeMinBig
+	}
+
+	return nil
+}
+
+// fixedPointTypeRangeMax returns the maximum scaled value of the given fixed-point type,
+// or nil if the type is not a concrete fixed-point type
+//
+func fixedPointTypeRangeMax(fixedPointType Type) *big.Int {
+	switch fixedPointType {
+	case Fix64Type:
+		return fixedPointFix64MaxBig
+	case UFix64Type:
+		return fixedPointUFix64MaxBig
+	case Fix128Type:
+		return Fix128TypeMaxBig
+	case UFix128Type:
+		return UFix128TypeMaxBig
+	}
+
+	return nil
+}
+
+var fixedPointFix64MinBig = big.NewInt(math.MinInt64)
+var fixedPointFix64MaxBig = big.NewInt(math.MaxInt64)
+var fixedPointUFix64MinBig = new(big.Int)
+var fixedPointUFix64MaxBig = new(big.Int).SetUint64(math.MaxUint64)
+
+// checkFieldValues checks the values of the given fields, e.g. `let x: Int = 1`.
+//
+// Only constant fields of contracts may declare a value, and the value must be a constant expression,
+// so it can be stored once when the contract is deployed.
+// The values are recorded in the elaboration, see Elaboration.FieldDeclarationConstantValues.
+//
+// The composite type is nil if the fields are not fields of a composite, e.g. the fields of a transaction
+//
+func (checker *Checker) checkFieldValues(fields []*ast.FieldDeclaration, compositeType *CompositeType) {
+	for _, field := range fields {
+		if field.Value == nil {
+			continue
+		}
+
+		name := field.Identifier.Identifier
+
+		if compositeType == nil ||
+			compositeType.Kind != common.CompositeKindContract ||
+			field.VariableKind != ast.VariableKindConstant {
+
+			checker.report(
+				&InvalidFieldValueError{
+					Name:  name,
+					Range: ast.NewRangeFromPositioned(field.Value),
+				},
+			)
+			continue
+		}
+
+		member, ok := compositeType.Members.Get(name)
+		if !ok {
+			continue
+		}
+
+		valueType := checker.VisitExpression(field.Value, member.TypeAnnotation.Type)
+		if valueType.IsInvalidType() {
+			continue
+		}
+
+		constant, ok := checker.constantValue(field.Value)
+		if !ok {
+			checker.report(
+				&NonConstantFieldValueError{
+					Name:  name,
+					Range: ast.NewRangeFromPositioned(field.Value),
+				},
+			)
+			continue
+		}
+
+		checker.Elaboration.FieldDeclarationConstantValues[field] = constant
+	}
+}
@@ -39,4 +39,5 @@ type TypeDeclaration interface {
 	TypeDeclarationType() Type
 	TypeDeclarationKind() common.DeclarationKind
 	TypeDeclarationPosition() ast.Position
+	TypeDeclarationAvailable(common.Location) bool
 }
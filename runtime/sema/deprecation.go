@@ -0,0 +1,85 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+const deprecatedDocPragma = "@deprecated"
+
+var deprecatedDocPragmaRegexp = regexp.MustCompile(`^\s*@deprecated(?:\(\s*("(?:[^"\\]|\\.)*")\s*\))?\s*$`)
+
+// parseDeprecation parses the docstring of a declaration
+// and returns the deprecation message, if the declaration is deprecated.
+//
+// A declaration is deprecated if its docstring contains a line
+// with the doc pragma `@deprecated`, optionally with a message,
+// e.g. `@deprecated("use `bar` instead")`.
+//
+func parseDeprecation(docString string) (message string, deprecated bool) {
+
+	// Fast path: most docstrings do not contain the pragma
+
+	if !strings.Contains(docString, deprecatedDocPragma) {
+		return "", false
+	}
+
+	for _, line := range strings.Split(docString, "\n") {
+		match := deprecatedDocPragmaRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		if match[1] == "" {
+			return "", true
+		}
+
+		message, err := strconv.Unquote(match[1])
+		if err != nil {
+			message = match[1][1 : len(match[1])-1]
+		}
+
+		return message, true
+	}
+
+	return "", false
+}
+
+// checkDeprecation reports a hint if the declaration with the given name
+// and docstring is deprecated, at the given range where it is referenced
+//
+func (checker *Checker) checkDeprecation(name string, docString string, hasPosition ast.HasPosition) {
+	message, deprecated := parseDeprecation(docString)
+	if !deprecated {
+		return
+	}
+
+	checker.hint(
+		&DeprecatedDeclarationHint{
+			Name:    name,
+			Message: message,
+			Range:   ast.NewRangeFromPositioned(hasPosition),
+		},
+	)
+}
@@ -0,0 +1,214 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"reflect"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=DiagnosticSeverity
+
+// DiagnosticSeverity is the severity of a diagnostic
+//
+type DiagnosticSeverity uint
+
+const (
+	DiagnosticSeverityUnknown DiagnosticSeverity = iota
+	DiagnosticSeverityError
+	DiagnosticSeverityWarning
+	DiagnosticSeverityInformation
+	DiagnosticSeverityHint
+)
+
+// Diagnostic is a structured representation of a checker error or hint,
+// which allows tools like the language server or the CLI to render
+// rich diagnostics without parsing messages.
+//
+type Diagnostic struct {
+	Severity DiagnosticSeverity
+	// Code identifies the kind of the diagnostic, e.g. `TypeMismatchError`.
+	// It is the name of the type of the error or hint
+	Code string
+	// Message is the primary message, e.g. `mismatched types`
+	Message string
+	// SecondaryMessage is an optional additional message,
+	// e.g. `expected `Int`, got `String``
+	SecondaryMessage string
+	Location         common.Location
+	// Range is the primary range of the diagnostic.
+	// It is empty if the error has no position
+	ast.Range
+	// Related contains related information, e.g. the location of a previous declaration
+	Related []DiagnosticRelatedInformation
+}
+
+// DiagnosticRelatedInformation is additional information for a diagnostic,
+// e.g. the location of a previous declaration for a redeclaration error
+//
+type DiagnosticRelatedInformation struct {
+	Message  string
+	Location common.Location
+	ast.Range
+}
+
+// NewErrorDiagnostic returns a diagnostic for the given error,
+// which occurred in the program with the given location.
+//
+// The notes of the error become related information.
+// The errors of imported programs become related information
+// in the location of the imported program.
+//
+func NewErrorDiagnostic(err error, location common.Location) Diagnostic {
+	diagnostic := Diagnostic{
+		Severity: DiagnosticSeverityError,
+		Code:     diagnosticCode(err),
+		Message:  err.Error(),
+		Location: location,
+	}
+
+	if secondaryError, ok := err.(errors.SecondaryError); ok {
+		diagnostic.SecondaryMessage = secondaryError.SecondaryError()
+	}
+
+	if positioned, ok := err.(ast.HasPosition); ok {
+		diagnostic.Range = ast.NewRangeFromPositioned(positioned)
+	}
+
+	if errorNotes, ok := err.(errors.ErrorNotes); ok {
+		for _, errorNote := range errorNotes.ErrorNotes() {
+			related := DiagnosticRelatedInformation{
+				Message:  errorNote.Message(),
+				Location: location,
+			}
+
+			if positioned, ok := errorNote.(ast.HasPosition); ok {
+				related.Range = ast.NewRangeFromPositioned(positioned)
+			}
+
+			diagnostic.Related = append(diagnostic.Related, related)
+		}
+	}
+
+	if importedProgramError, ok := err.(*ImportedProgramError); ok {
+		diagnostic.Related = append(
+			diagnostic.Related,
+			importedProgramRelatedInformation(importedProgramError)...,
+		)
+	}
+
+	return diagnostic
+}
+
+// importedProgramRelatedInformation returns related information
+// for the errors of the imported program of the given error
+//
+func importedProgramRelatedInformation(err *ImportedProgramError) (related []DiagnosticRelatedInformation) {
+
+	var childErrors []error
+	if checkerError, ok := err.Err.(*CheckerError); ok {
+		childErrors = checkerError.Errors
+	} else {
+		childErrors = []error{err.Err}
+	}
+
+	for _, childError := range childErrors {
+		childDiagnostic := NewErrorDiagnostic(childError, err.Location)
+
+		related = append(
+			related,
+			DiagnosticRelatedInformation{
+				Message:  childDiagnostic.Message,
+				Location: childDiagnostic.Location,
+				Range:    childDiagnostic.Range,
+			},
+		)
+	}
+
+	return
+}
+
+// NewHintDiagnostic returns a diagnostic for the given hint,
+// which was reported for the program with the given location
+//
+func NewHintDiagnostic(hint Hint, location common.Location) Diagnostic {
+	return NewConfiguredHintDiagnostic(hint, location, nil)
+}
+
+// NewConfiguredHintDiagnostic returns a diagnostic for the given hint,
+// which was reported for the program with the given location,
+// with the severity of the given diagnostic configuration
+//
+func NewConfiguredHintDiagnostic(hint Hint, location common.Location, config *DiagnosticConfig) Diagnostic {
+	code := diagnosticCode(hint)
+	return Diagnostic{
+		Severity: config.Severity(code),
+		Code:     code,
+		Message:  hint.Hint(),
+		Location: location,
+		Range:    ast.NewRangeFromPositioned(hint),
+	}
+}
+
+// diagnosticCode returns the code for the given error or hint,
+// i.e. the name of its type
+//
+func diagnosticCode(value interface{}) string {
+	ty := reflect.TypeOf(value)
+	for ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	return ty.Name()
+}
+
+// Diagnostics returns the diagnostics for all errors and hints
+// which were reported by the checker, in the order they were reported:
+// First all errors, then all hints.
+//
+func (checker *Checker) Diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(checker.errors)+len(checker.hints))
+
+	for _, err := range checker.errors {
+		diagnostics = append(diagnostics, NewErrorDiagnostic(err, checker.Location))
+	}
+
+	for _, hint := range checker.hints {
+		diagnostics = append(
+			diagnostics,
+			NewConfiguredHintDiagnostic(hint, checker.Location, checker.diagnosticConfig),
+		)
+	}
+
+	return diagnostics
+}
+
+// Diagnostics returns the diagnostics for all errors
+//
+func (e CheckerError) Diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(e.Errors))
+
+	for _, err := range e.Errors {
+		diagnostics = append(diagnostics, NewErrorDiagnostic(err, e.Location))
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,84 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// DiagnosticConfig configures which hints are reported by the checker,
+// and with which severity they are reported as diagnostics.
+//
+// Errors can neither be disabled nor suppressed, and their severity cannot be changed,
+// as they make the program invalid.
+//
+type DiagnosticConfig struct {
+	// Disabled contains the codes of the hints which are not reported,
+	// e.g. `UnnecessaryCastHint`
+	Disabled map[string]bool
+	// Severities overrides the severity of the diagnostics of hints with the given codes.
+	// Overriding the severity of a hint does not make it an error, i.e. the check still succeeds
+	Severities map[string]DiagnosticSeverity
+	// Suppressions are the per-line suppressions of the program,
+	// see parser2.ParseDiagnosticSuppressions
+	Suppressions []ast.DiagnosticSuppression
+}
+
+// IsReported returns true if the hint with the given code,
+// which starts on the given line, should be reported
+//
+func (c *DiagnosticConfig) IsReported(code string, line int) bool {
+	if c == nil {
+		return true
+	}
+
+	if c.Disabled[code] {
+		return false
+	}
+
+	for _, suppression := range c.Suppressions {
+		if suppression.Suppresses(code, line) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Severity returns the severity of the diagnostic of the hint with the given code
+//
+func (c *DiagnosticConfig) Severity(code string) DiagnosticSeverity {
+	if c != nil {
+		if severity, ok := c.Severities[code]; ok {
+			return severity
+		}
+	}
+
+	return DiagnosticSeverityHint
+}
+
+// WithDiagnosticConfig returns a checker option which sets the given diagnostic configuration,
+// which allows disabling and suppressing hints, and overriding their severity.
+//
+func WithDiagnosticConfig(config *DiagnosticConfig) Option {
+	return func(checker *Checker) error {
+		checker.diagnosticConfig = config
+		return nil
+	}
+}
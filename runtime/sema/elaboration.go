@@ -28,6 +28,10 @@ type MemberInfo struct {
 	Member       *Member
 	IsOptional   bool
 	AccessedType Type
+	// Slot is the slot of the member in the member dispatch table
+	// of its containing composite type, or -1 if the member has none.
+	// See MemberSlots
+	Slot int
 }
 
 type Elaboration struct {
@@ -42,6 +46,7 @@ type Elaboration struct {
 	CompositeTypeDeclarations           map[*CompositeType]*ast.CompositeDeclaration
 	InterfaceDeclarationTypes           map[*ast.InterfaceDeclaration]*InterfaceType
 	InterfaceTypeDeclarations           map[*InterfaceType]*ast.InterfaceDeclaration
+	EntitlementDeclarationTypes         map[*ast.EntitlementDeclaration]*EntitlementType
 	ConstructorFunctionTypes            map[*ast.SpecialFunctionDeclaration]*FunctionType
 	FunctionExpressionFunctionType      map[*ast.FunctionExpression]*FunctionType
 	InvocationExpressionArgumentTypes   map[*ast.InvocationExpression][]Type
@@ -68,21 +73,31 @@ type Elaboration struct {
 	// IsNestedResourceMoveExpression indicates if the access the index or member expression
 	// is implicitly moving a resource out of the container, e.g. in a shift or swap statement.
 	IsNestedResourceMoveExpression      map[ast.Expression]struct{}
+	ConstantExpressionValues            map[ast.Expression]ConstantValue
+	FieldDeclarationConstantValues      map[*ast.FieldDeclaration]ConstantValue
 	CompositeNestedDeclarations         map[*ast.CompositeDeclaration]map[string]ast.Declaration
 	InterfaceNestedDeclarations         map[*ast.InterfaceDeclaration]map[string]ast.Declaration
 	PostConditionsRewrite               map[*ast.Conditions]PostConditionsRewrite
 	EmitStatementEventTypes             map[*ast.EmitStatement]*CompositeType
 	CompositeTypes                      map[TypeID]*CompositeType
 	InterfaceTypes                      map[TypeID]*InterfaceType
+	EntitlementTypes                    map[TypeID]*EntitlementType
 	IdentifierInInvocationTypes         map[*ast.IdentifierExpression]Type
 	ImportDeclarationsResolvedLocations map[*ast.ImportDeclaration][]ResolvedLocation
 	GlobalValues                        *StringVariableOrderedMap
 	GlobalTypes                         *StringVariableOrderedMap
 	TransactionTypes                    []*TransactionType
+	Pragmas                             []Pragma
 	EffectivePredeclaredValues          map[string]ValueDeclaration
 	EffectivePredeclaredTypes           map[string]TypeDeclaration
 	isChecking                          bool
 	ReferenceExpressionBorrowTypes      map[*ast.ReferenceExpression]*ReferenceType
+	// MemoizedFunctions are the contract functions declared as memoized
+	// with the `#memoize` pragma, see MemoizePragma
+	MemoizedFunctions map[*ast.FunctionDeclaration]struct{}
+	// CompositeTypeMemberSlots are the member dispatch tables of the composite types
+	// declared in the program, and of the composite types whose members are accessed in the program
+	CompositeTypeMemberSlots map[*CompositeType]*MemberSlots
 }
 
 func NewElaboration() *Elaboration {
@@ -98,6 +113,7 @@ func NewElaboration() *Elaboration {
 		CompositeTypeDeclarations:           map[*CompositeType]*ast.CompositeDeclaration{},
 		InterfaceDeclarationTypes:           map[*ast.InterfaceDeclaration]*InterfaceType{},
 		InterfaceTypeDeclarations:           map[*InterfaceType]*ast.InterfaceDeclaration{},
+		EntitlementDeclarationTypes:         map[*ast.EntitlementDeclaration]*EntitlementType{},
 		ConstructorFunctionTypes:            map[*ast.SpecialFunctionDeclaration]*FunctionType{},
 		FunctionExpressionFunctionType:      map[*ast.FunctionExpression]*FunctionType{},
 		InvocationExpressionArgumentTypes:   map[*ast.InvocationExpression][]Type{},
@@ -118,6 +134,8 @@ func NewElaboration() *Elaboration {
 		DictionaryExpressionEntryTypes:      map[*ast.DictionaryExpression][]DictionaryEntryType{},
 		IntegerExpressionType:               map[*ast.IntegerExpression]Type{},
 		FixedPointExpression:                map[*ast.FixedPointExpression]Type{},
+		ConstantExpressionValues:            map[ast.Expression]ConstantValue{},
+		FieldDeclarationConstantValues:      map[*ast.FieldDeclaration]ConstantValue{},
 		TransactionDeclarationTypes:         map[*ast.TransactionDeclaration]*TransactionType{},
 		SwapStatementLeftTypes:              map[*ast.SwapStatement]Type{},
 		SwapStatementRightTypes:             map[*ast.SwapStatement]Type{},
@@ -128,13 +146,16 @@ func NewElaboration() *Elaboration {
 		EmitStatementEventTypes:             map[*ast.EmitStatement]*CompositeType{},
 		CompositeTypes:                      map[TypeID]*CompositeType{},
 		InterfaceTypes:                      map[TypeID]*InterfaceType{},
+		EntitlementTypes:                    map[TypeID]*EntitlementType{},
 		IdentifierInInvocationTypes:         map[*ast.IdentifierExpression]Type{},
 		ImportDeclarationsResolvedLocations: map[*ast.ImportDeclaration][]ResolvedLocation{},
-		GlobalValues:                        NewStringVariableOrderedMap(),
-		GlobalTypes:                         NewStringVariableOrderedMap(),
+		GlobalValues:                        &StringVariableOrderedMap{},
+		GlobalTypes:                         &StringVariableOrderedMap{},
 		EffectivePredeclaredValues:          map[string]ValueDeclaration{},
 		EffectivePredeclaredTypes:           map[string]TypeDeclaration{},
 		ReferenceExpressionBorrowTypes:      map[*ast.ReferenceExpression]*ReferenceType{},
+		MemoizedFunctions:                   map[*ast.FunctionDeclaration]struct{}{},
+		CompositeTypeMemberSlots:            map[*CompositeType]*MemberSlots{},
 	}
 }
 
@@ -150,6 +171,18 @@ func (e *Elaboration) setIsChecking(isChecking bool) {
 	e.isChecking = isChecking
 }
 
+// HasPragma returns true if the program declares a pragma with the given identifier,
+// e.g. `#allowAccountLinking` or `#allowAccountLinking("...")`.
+//
+func (e *Elaboration) HasPragma(identifier string) bool {
+	for _, pragma := range e.Pragmas {
+		if pragma.Identifier == identifier {
+			return true
+		}
+	}
+	return false
+}
+
 // FunctionEntryPointType returns the type of the entry point function declaration, if any.
 //
 // Returns an error if no valid entry point function declaration exists.
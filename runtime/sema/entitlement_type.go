@@ -0,0 +1,254 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// EntitlementType represents an entitlement.
+//
+// Entitlements are not value types: they may only be used in entitlement access modifiers
+// of members, e.g. `access(E) fun f()`, and in entitled reference types, e.g. `auth(E) &R`.
+// A member with entitlement access can only be accessed through a reference
+// which has been granted all entitlements of the member.
+//
+type EntitlementType struct {
+	Location          common.Location
+	Identifier        string
+	containerType     Type
+	cachedIdentifiers *struct {
+		TypeID              TypeID
+		QualifiedIdentifier string
+	}
+	cachedIdentifiersLock sync.RWMutex
+}
+
+func (*EntitlementType) IsType() {}
+
+func (*EntitlementType) Tag() TypeTag {
+	return EntitlementTypeTag
+}
+
+func (t *EntitlementType) String() string {
+	return t.Identifier
+}
+
+func (t *EntitlementType) QualifiedString() string {
+	return t.QualifiedIdentifier()
+}
+
+func (t *EntitlementType) GetContainerType() Type {
+	return t.containerType
+}
+
+func (t *EntitlementType) SetContainerType(containerType Type) {
+	t.checkIdentifiersCached()
+	t.containerType = containerType
+}
+
+func (t *EntitlementType) checkIdentifiersCached() {
+	t.cachedIdentifiersLock.Lock()
+	defer t.cachedIdentifiersLock.Unlock()
+
+	if t.cachedIdentifiers != nil {
+		panic(errors.NewUnreachableError())
+	}
+}
+
+func (t *EntitlementType) GetLocation() common.Location {
+	return t.Location
+}
+
+func (t *EntitlementType) QualifiedIdentifier() string {
+	t.initializeIdentifiers()
+	return t.cachedIdentifiers.QualifiedIdentifier
+}
+
+func (t *EntitlementType) ID() TypeID {
+	t.initializeIdentifiers()
+	return t.cachedIdentifiers.TypeID
+}
+
+func (t *EntitlementType) initializeIdentifiers() {
+	t.cachedIdentifiersLock.Lock()
+	defer t.cachedIdentifiersLock.Unlock()
+
+	if t.cachedIdentifiers != nil {
+		return
+	}
+
+	identifier := qualifiedIdentifier(t.Identifier, t.containerType)
+
+	var typeID TypeID
+	if t.Location == nil {
+		typeID = TypeID(identifier)
+	} else {
+		typeID = t.Location.TypeID(identifier)
+	}
+
+	t.cachedIdentifiers = &struct {
+		TypeID              TypeID
+		QualifiedIdentifier string
+	}{
+		TypeID:              typeID,
+		QualifiedIdentifier: identifier,
+	}
+}
+
+func (t *EntitlementType) Equal(other Type) bool {
+	otherEntitlement, ok := other.(*EntitlementType)
+	if !ok {
+		return false
+	}
+
+	return otherEntitlement.ID() == t.ID()
+}
+
+func (*EntitlementType) IsResourceType() bool {
+	return false
+}
+
+func (*EntitlementType) IsInvalidType() bool {
+	return false
+}
+
+func (*EntitlementType) IsStorable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*EntitlementType) IsExternallyReturnable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*EntitlementType) IsImportable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*EntitlementType) IsEquatable() bool {
+	return false
+}
+
+func (*EntitlementType) TypeAnnotationState() TypeAnnotationState {
+	return TypeAnnotationStateValid
+}
+
+func (t *EntitlementType) RewriteWithRestrictedTypes() (Type, bool) {
+	return t, false
+}
+
+func (*EntitlementType) Unify(_ Type, _ *TypeParameterTypeOrderedMap, _ func(err error), _ ast.Range) bool {
+	return false
+}
+
+func (t *EntitlementType) Resolve(_ *TypeParameterTypeOrderedMap) Type {
+	return t
+}
+
+func (*EntitlementType) GetMembers() map[string]MemberResolver {
+	return map[string]MemberResolver{}
+}
+
+// NewEntitlementSet returns the given entitlements sorted by their type ID,
+// with duplicates removed, so the result can be used as a canonical set,
+// e.g. in the string representation and type ID of reference types.
+//
+func NewEntitlementSet(entitlements []*EntitlementType) []*EntitlementType {
+	if len(entitlements) == 0 {
+		return nil
+	}
+
+	result := make([]*EntitlementType, 0, len(entitlements))
+	seen := make(map[TypeID]struct{}, len(entitlements))
+
+	for _, entitlement := range entitlements {
+		typeID := entitlement.ID()
+		if _, ok := seen[typeID]; ok {
+			continue
+		}
+		seen[typeID] = struct{}{}
+		result = append(result, entitlement)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+
+	return result
+}
+
+// entitlementSetIncludes returns true if all of the required entitlements
+// are included in the given entitlements.
+//
+func entitlementSetIncludes(entitlements, requiredEntitlements []*EntitlementType) bool {
+	for _, requiredEntitlement := range requiredEntitlements {
+		if !entitlementSetContains(entitlements, requiredEntitlement) {
+			return false
+		}
+	}
+	return true
+}
+
+func entitlementSetContains(entitlements []*EntitlementType, entitlement *EntitlementType) bool {
+	for _, otherEntitlement := range entitlements {
+		if otherEntitlement.Equal(entitlement) {
+			return true
+		}
+	}
+	return false
+}
+
+// entitlementSetIntersection returns the entitlements which are included in both sets.
+//
+func entitlementSetIntersection(entitlements, otherEntitlements []*EntitlementType) []*EntitlementType {
+	var result []*EntitlementType
+	for _, entitlement := range entitlements {
+		if entitlementSetContains(otherEntitlements, entitlement) {
+			result = append(result, entitlement)
+		}
+	}
+	return result
+}
+
+// EntitlementSetsEqual returns true if both sets contain the same entitlements.
+//
+func EntitlementSetsEqual(entitlements, otherEntitlements []*EntitlementType) bool {
+	return entitlementSetIncludes(entitlements, otherEntitlements) &&
+		entitlementSetIncludes(otherEntitlements, entitlements)
+}
+
+// formatEntitlementSet returns the given entitlements as a comma-separated list,
+// each entitlement formatted using the given formatter.
+//
+func formatEntitlementSet(entitlements []*EntitlementType, formatter func(*EntitlementType) string) string {
+	var builder strings.Builder
+	for i, entitlement := range entitlements {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(formatter(entitlement))
+	}
+	return builder.String()
+}
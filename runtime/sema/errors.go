@@ -68,6 +68,94 @@ func (e *InvalidPragmaError) Error() string {
 	return fmt.Sprintf("invalid pragma %s", e.Message)
 }
 
+// FeatureNotEnabledError
+
+type FeatureNotEnabledError struct {
+	Name   string
+	Pragma string
+	ast.Range
+}
+
+func (e *FeatureNotEnabledError) isSemanticError() {}
+
+func (e *FeatureNotEnabledError) Error() string {
+	return fmt.Sprintf(
+		"cannot use `%s`: feature is not enabled",
+		e.Name,
+	)
+}
+
+func (e *FeatureNotEnabledError) SecondaryError() string {
+	return fmt.Sprintf(
+		"enable the feature by declaring the pragma `#%s`",
+		e.Pragma,
+	)
+}
+
+// ExperimentalFeatureNotEnabledError
+
+type ExperimentalFeatureNotEnabledError struct {
+	Feature common.ExperimentalFeature
+	ast.Range
+}
+
+func (e *ExperimentalFeatureNotEnabledError) isSemanticError() {}
+
+func (e *ExperimentalFeatureNotEnabledError) Error() string {
+	return fmt.Sprintf(
+		"cannot use experimental feature `%s`: feature is not enabled",
+		e.Feature,
+	)
+}
+
+// ExpressionDepthLimitReachedError
+
+type ExpressionDepthLimitReachedError struct {
+	Limit int
+	ast.Range
+}
+
+func (e *ExpressionDepthLimitReachedError) isSemanticError() {}
+
+func (e *ExpressionDepthLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"expression nesting depth limit reached: maximum is %d",
+		e.Limit,
+	)
+}
+
+// DeclarationCountLimitReachedError
+
+type DeclarationCountLimitReachedError struct {
+	Limit int
+	ast.Range
+}
+
+func (e *DeclarationCountLimitReachedError) isSemanticError() {}
+
+func (e *DeclarationCountLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"declaration count limit reached: maximum is %d",
+		e.Limit,
+	)
+}
+
+// TypeComplexityLimitReachedError
+
+type TypeComplexityLimitReachedError struct {
+	Limit int
+	ast.Range
+}
+
+func (e *TypeComplexityLimitReachedError) isSemanticError() {}
+
+func (e *TypeComplexityLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"type complexity limit reached: maximum is %d",
+		e.Limit,
+	)
+}
+
 // MissingLocationError
 
 type MissingLocationError struct{}
@@ -84,6 +172,8 @@ type CheckerError struct {
 	Errors   []error
 }
 
+func (CheckerError) IsUserError() {}
+
 func (e CheckerError) Error() string {
 	var sb strings.Builder
 	sb.WriteString("Checking failed:\n")
@@ -310,6 +400,22 @@ func (e *NotEquatableTypeError) Error() string {
 
 func (*NotEquatableTypeError) isSemanticError() {}
 
+// NotSortableTypeError
+
+type NotSortableTypeError struct {
+	Type Type
+	ast.Range
+}
+
+func (e *NotSortableTypeError) Error() string {
+	return fmt.Sprintf(
+		"cannot sort values of type: `%s`",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (*NotSortableTypeError) isSemanticError() {}
+
 // NotCallableError
 
 type NotCallableError struct {
@@ -721,6 +827,9 @@ type FieldUninitializedError struct {
 	Name          string
 	ContainerType Type
 	Pos           ast.Position
+	// UninitializedBranches are the branches in which the field is not initialized,
+	// if it is initialized on some, but not all control-flow paths
+	UninitializedBranches []ast.Range
 }
 
 func (e *FieldUninitializedError) Error() string {
@@ -732,7 +841,28 @@ func (e *FieldUninitializedError) Error() string {
 }
 
 func (e *FieldUninitializedError) SecondaryError() string {
-	return "not initialized"
+	if len(e.UninitializedBranches) == 0 {
+		return "not initialized"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("not initialized on all paths, not in the branches at ")
+	for i, branch := range e.UninitializedBranches {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		_, _ = fmt.Fprintf(&builder, "%d:%d", branch.StartPos.Line, branch.StartPos.Column)
+	}
+	return builder.String()
+}
+
+func (e *FieldUninitializedError) ErrorNotes() (notes []errors.ErrorNote) {
+	for _, branch := range e.UninitializedBranches {
+		notes = append(notes, &UninitializedFieldBranchNote{
+			Range: branch,
+		})
+	}
+	return
 }
 
 func (*FieldUninitializedError) isSemanticError() {}
@@ -746,6 +876,16 @@ func (e *FieldUninitializedError) EndPosition() ast.Position {
 	return e.Pos.Shifted(length - 1)
 }
 
+// UninitializedFieldBranchNote
+
+type UninitializedFieldBranchNote struct {
+	ast.Range
+}
+
+func (n UninitializedFieldBranchNote) Message() string {
+	return "not initialized in this branch"
+}
+
 // FieldTypeNotStorableError is an error that is reported for
 // fields of composite types that are not storable.
 //
@@ -796,6 +936,30 @@ func (e *FunctionExpressionInConditionError) Error() string {
 
 func (*FunctionExpressionInConditionError) isSemanticError() {}
 
+// InvalidBeforeValueTypeError
+
+type InvalidBeforeValueTypeError struct {
+	Type Type
+	ast.Range
+}
+
+func (e *InvalidBeforeValueTypeError) Error() string {
+	return fmt.Sprintf(
+		"cannot capture value of type `%s` in `%s`",
+		e.Type.QualifiedString(),
+		BeforeIdentifier,
+	)
+}
+
+func (*InvalidBeforeValueTypeError) isSemanticError() {}
+
+func (e *InvalidBeforeValueTypeError) SecondaryError() string {
+	if e.Type.IsResourceType() {
+		return "resources cannot be copied"
+	}
+	return "references do not capture the state of the referenced value"
+}
+
 // MissingReturnValueError
 
 type MissingReturnValueError struct {
@@ -911,8 +1075,6 @@ func (*InvalidEnumConformancesError) isSemanticError() {}
 
 // ConformanceError
 
-// TODO: report each missing member and mismatch as note
-
 type MemberMismatch struct {
 	CompositeMember *Member
 	InterfaceMember *Member
@@ -923,9 +1085,6 @@ type InitializerMismatch struct {
 	InterfaceParameters []*Parameter
 }
 
-// TODO: improve error message:
-//  use `InitializerMismatch`, `MissingMembers`, `MemberMismatches`, etc
-
 type ConformanceError struct {
 	CompositeDeclaration           *ast.CompositeDeclaration
 	CompositeType                  *CompositeType
@@ -956,6 +1115,59 @@ func (e *ConformanceError) Error() string {
 	)
 }
 
+// SecondaryError lists all problems with the conformance,
+// i.e. the initializer mismatch, missing members, member mismatches,
+// and missing nested types, in one message
+//
+func (e *ConformanceError) SecondaryError() string {
+	var problems []string
+
+	if e.InitializerMismatch != nil {
+		problems = append(problems,
+			fmt.Sprintf(
+				"initializer mismatch: expected `%s`, got `%s`",
+				formatInitializerSignature(e.InitializerMismatch.InterfaceParameters),
+				formatInitializerSignature(e.InitializerMismatch.CompositeParameters),
+			),
+		)
+	}
+
+	for _, member := range e.MissingMembers {
+		problems = append(problems,
+			fmt.Sprintf(
+				"missing %s `%s`",
+				member.DeclarationKind.Name(),
+				FormatMemberSignature(member),
+			),
+		)
+	}
+
+	for _, memberMismatch := range e.MemberMismatches {
+		interfaceMember := memberMismatch.InterfaceMember
+		problems = append(problems,
+			fmt.Sprintf(
+				"mismatched %s `%s`: expected `%s`, got `%s`",
+				interfaceMember.DeclarationKind.Name(),
+				interfaceMember.Identifier.Identifier,
+				FormatMemberSignature(interfaceMember),
+				FormatMemberSignature(memberMismatch.CompositeMember),
+			),
+		)
+	}
+
+	for _, nestedCompositeType := range e.MissingNestedCompositeTypes {
+		problems = append(problems,
+			fmt.Sprintf(
+				"missing nested %s `%s`",
+				nestedCompositeType.Kind.Name(),
+				nestedCompositeType.Identifier,
+			),
+		)
+	}
+
+	return strings.Join(problems, "; ")
+}
+
 func (*ConformanceError) isSemanticError() {}
 
 func (e *ConformanceError) StartPosition() ast.Position {
@@ -973,21 +1185,81 @@ func (e *ConformanceError) ErrorNotes() (notes []errors.ErrorNote) {
 			ast.NewRangeFromPositioned(memberMismatch.CompositeMember.Identifier)
 
 		notes = append(notes, &MemberMismatchNote{
-			Range: compositeMemberIdentifierRange,
+			ExpectedSignature: FormatMemberSignature(memberMismatch.InterfaceMember),
+			Range:             compositeMemberIdentifierRange,
 		})
 	}
 
 	return
 }
 
+// FormatMemberSignature returns the declaration of the given member,
+// e.g. `pub let balance: Int` or `pub fun deposit(from: @Vault)`
+//
+func FormatMemberSignature(member *Member) string {
+	var builder strings.Builder
+
+	accessKeyword := member.Access.Keyword()
+	if accessKeyword != "" {
+		builder.WriteString(accessKeyword)
+		builder.WriteRune(' ')
+	}
+
+	identifier := member.Identifier.Identifier
+
+	if functionType, ok := member.TypeAnnotation.Type.(*FunctionType); ok &&
+		member.DeclarationKind == common.DeclarationKindFunction {
+
+		builder.WriteString("fun ")
+		builder.WriteString(identifier)
+		builder.WriteString(formatParameterList(functionType.Parameters))
+
+		returnTypeAnnotation := functionType.ReturnTypeAnnotation
+		if returnTypeAnnotation != nil &&
+			!returnTypeAnnotation.Type.Equal(VoidType) {
+
+			builder.WriteString(": ")
+			builder.WriteString(returnTypeAnnotation.QualifiedString())
+		}
+
+		return builder.String()
+	}
+
+	variableKindKeyword := member.VariableKind.Keyword()
+	if variableKindKeyword != "" {
+		builder.WriteString(variableKindKeyword)
+		builder.WriteRune(' ')
+	}
+
+	builder.WriteString(identifier)
+	builder.WriteString(": ")
+	builder.WriteString(member.TypeAnnotation.QualifiedString())
+
+	return builder.String()
+}
+
+func formatInitializerSignature(parameters []*Parameter) string {
+	return "init" + formatParameterList(parameters)
+}
+
+func formatParameterList(parameters []*Parameter) string {
+	formattedParameters := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		formattedParameters[i] = parameter.QualifiedString()
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(formattedParameters, ", "))
+}
+
 // MemberMismatchNote
 
 type MemberMismatchNote struct {
+	ExpectedSignature string
 	ast.Range
 }
 
 func (n MemberMismatchNote) Message() string {
-	return "mismatch here"
+	return fmt.Sprintf("mismatch here, expected `%s`", n.ExpectedSignature)
 }
 
 // DuplicateConformanceError
@@ -1207,6 +1479,58 @@ func (e *InvalidIntegerLiteralRangeError) SecondaryError() string {
 
 func (*InvalidIntegerLiteralRangeError) isSemanticError() {}
 
+// InvalidConstantExpressionRangeError
+
+type InvalidConstantExpressionRangeError struct {
+	ExpectedType   Type
+	ExpectedMinInt *big.Int
+	ExpectedMaxInt *big.Int
+	ast.Range
+}
+
+func (e *InvalidConstantExpressionRangeError) Error() string {
+	return "constant expression out of range"
+}
+
+func (e *InvalidConstantExpressionRangeError) SecondaryError() string {
+	return fmt.Sprintf(
+		"expected `%s`, in range [%s, %s]",
+		e.ExpectedType.QualifiedString(),
+		e.ExpectedMinInt,
+		e.ExpectedMaxInt,
+	)
+}
+
+func (*InvalidConstantExpressionRangeError) isSemanticError() {}
+
+// InvalidFixedPointConstantExpressionRangeError
+
+type InvalidFixedPointConstantExpressionRangeError struct {
+	ExpectedType          Type
+	ExpectedMinInt        *big.Int
+	ExpectedMinFractional *big.Int
+	ExpectedMaxInt        *big.Int
+	ExpectedMaxFractional *big.Int
+	ast.Range
+}
+
+func (e *InvalidFixedPointConstantExpressionRangeError) Error() string {
+	return "constant expression out of range"
+}
+
+func (e *InvalidFixedPointConstantExpressionRangeError) SecondaryError() string {
+	return fmt.Sprintf(
+		"expected `%s`, in range [%s.%s, %s.%s]",
+		e.ExpectedType.QualifiedString(),
+		e.ExpectedMinInt,
+		e.ExpectedMinFractional,
+		e.ExpectedMaxInt,
+		e.ExpectedMaxFractional,
+	)
+}
+
+func (*InvalidFixedPointConstantExpressionRangeError) isSemanticError() {}
+
 // InvalidAddressLiteralError
 
 type InvalidAddressLiteralError struct {
@@ -1582,6 +1906,36 @@ func (n ResourceInvalidationNote) Message() string {
 	return fmt.Sprintf("resource %s here", action)
 }
 
+// InvalidatedResourceReferenceError
+
+type InvalidatedResourceReferenceError struct {
+	Invalidations []ResourceInvalidation
+	ast.Range
+}
+
+func (e *InvalidatedResourceReferenceError) Error() string {
+	return "invalid reference: referenced resource may have been moved or destroyed"
+}
+
+func (e *InvalidatedResourceReferenceError) SecondaryError() string {
+	return "reference used here after the referenced resource was invalidated"
+}
+
+func (e *InvalidatedResourceReferenceError) ErrorNotes() (notes []errors.ErrorNote) {
+	for _, invalidation := range e.Invalidations {
+		notes = append(notes, &ResourceInvalidationNote{
+			ResourceInvalidation: invalidation,
+			Range: ast.Range{
+				StartPos: invalidation.StartPos,
+				EndPos:   invalidation.EndPos,
+			},
+		})
+	}
+	return
+}
+
+func (*InvalidatedResourceReferenceError) isSemanticError() {}
+
 // MissingCreateError
 
 type MissingCreateError struct {
@@ -1735,6 +2089,22 @@ func (e *InvalidEventParameterTypeError) Error() string {
 
 func (*InvalidEventParameterTypeError) isSemanticError() {}
 
+// InvalidIndexedEventParameterTypeError
+
+type InvalidIndexedEventParameterTypeError struct {
+	Type Type
+	ast.Range
+}
+
+func (e *InvalidIndexedEventParameterTypeError) Error() string {
+	return fmt.Sprintf(
+		"unsupported indexed event parameter type: `%s`",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (*InvalidIndexedEventParameterTypeError) isSemanticError() {}
+
 // InvalidEventUsageError
 
 type InvalidEventUsageError struct {
@@ -1901,6 +2271,63 @@ func (e *UninitializedFieldAccessError) EndPosition() ast.Position {
 	return e.Pos.Shifted(length - 1)
 }
 
+// UnknownInitializedFieldError is reported for a field in the `@initializes` doc pragma
+// of an initializing function which is not a field of the container
+//
+type UnknownInitializedFieldError struct {
+	FieldName    string
+	FunctionName string
+	ast.Range
+}
+
+func (e *UnknownInitializedFieldError) Error() string {
+	return fmt.Sprintf(
+		"initializing function `%s` cannot initialize unknown field `%s`",
+		e.FunctionName,
+		e.FieldName,
+	)
+}
+
+func (*UnknownInitializedFieldError) isSemanticError() {}
+
+// InvalidInitializingFunctionAccessError is reported for an initializing function
+// which is not private, i.e. which might be called outside of the initialization
+//
+type InvalidInitializingFunctionAccessError struct {
+	Name string
+	ast.Range
+}
+
+func (e *InvalidInitializingFunctionAccessError) Error() string {
+	return fmt.Sprintf(
+		"initializing function `%s` must be private",
+		e.Name,
+	)
+}
+
+func (*InvalidInitializingFunctionAccessError) isSemanticError() {}
+
+// InvalidInitializingFunctionUseError is reported for a use of an initializing function
+// which is not a call on `self` in the initializer or in an initializing function
+//
+type InvalidInitializingFunctionUseError struct {
+	Name string
+	ast.Range
+}
+
+func (e *InvalidInitializingFunctionUseError) Error() string {
+	return fmt.Sprintf(
+		"invalid use of initializing function `%s`",
+		e.Name,
+	)
+}
+
+func (e *InvalidInitializingFunctionUseError) SecondaryError() string {
+	return "initializing functions can only be called on `self` in an initializer or an initializing function"
+}
+
+func (*InvalidInitializingFunctionUseError) isSemanticError() {}
+
 // UnreachableStatementError
 
 type UnreachableStatementError struct {
@@ -2185,6 +2612,100 @@ func (e *InvalidAssignmentAccessError) SecondaryError() string {
 
 func (*InvalidAssignmentAccessError) isSemanticError() {}
 
+// ExternalMutationError
+
+type ExternalMutationError struct {
+	Name            string
+	DeclarationKind common.DeclarationKind
+	ContainerType   Type
+	ast.Range
+}
+
+func (e *ExternalMutationError) Error() string {
+	return fmt.Sprintf(
+		"cannot mutate `%s`: %s is only mutable inside `%s`",
+		e.Name,
+		e.DeclarationKind.Name(),
+		e.ContainerType.QualifiedString(),
+	)
+}
+
+func (e *ExternalMutationError) SecondaryError() string {
+	return fmt.Sprintf(
+		"consider adding a function to `%s` which performs the mutation, or making the %s publicly settable with `%s`",
+		e.ContainerType.QualifiedString(),
+		e.DeclarationKind.Name(),
+		ast.AccessPublicSettable.Keyword(),
+	)
+}
+
+func (*ExternalMutationError) isSemanticError() {}
+
+// InsufficientEntitlementsError
+
+type InsufficientEntitlementsError struct {
+	Name                 string
+	DeclarationKind      common.DeclarationKind
+	RequiredEntitlements []*EntitlementType
+	ReferenceType        *ReferenceType
+	ast.Range
+}
+
+func (e *InsufficientEntitlementsError) Error() string {
+	return fmt.Sprintf(
+		"cannot access `%s`: %s requires entitlements `%s`, which are not granted by reference type `%s`",
+		e.Name,
+		e.DeclarationKind.Name(),
+		formatEntitlementSet(e.RequiredEntitlements, func(entitlement *EntitlementType) string {
+			return entitlement.QualifiedString()
+		}),
+		e.ReferenceType.QualifiedString(),
+	)
+}
+
+func (e *InsufficientEntitlementsError) SecondaryError() string {
+	return "consider borrowing a reference which is granted the required entitlements"
+}
+
+func (*InsufficientEntitlementsError) isSemanticError() {}
+
+// InvalidEntitlementTypeError is reported when a type
+// which is not an entitlement is used where an entitlement is expected
+
+type InvalidEntitlementTypeError struct {
+	Type Type
+	ast.Range
+}
+
+func (e *InvalidEntitlementTypeError) Error() string {
+	return fmt.Sprintf(
+		"expected entitlement, got `%s`",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (*InvalidEntitlementTypeError) isSemanticError() {}
+
+// InvalidEntitlementUsageError is reported when an entitlement is used as a type
+
+type InvalidEntitlementUsageError struct {
+	Type *EntitlementType
+	ast.Range
+}
+
+func (e *InvalidEntitlementUsageError) Error() string {
+	return fmt.Sprintf(
+		"cannot use entitlement `%s` as a type",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (*InvalidEntitlementUsageError) SecondaryError() string {
+	return "entitlements can only be used in access modifiers and reference types"
+}
+
+func (*InvalidEntitlementUsageError) isSemanticError() {}
+
 // InvalidCharacterLiteralError
 
 type InvalidCharacterLiteralError struct {
@@ -2366,7 +2887,8 @@ type InvalidTransactionPrepareParameterTypeError struct {
 
 func (e *InvalidTransactionPrepareParameterTypeError) Error() string {
 	return fmt.Sprintf(
-		"prepare parameter must be of type `%s`, not `%s`",
+		"prepare parameter must be of type `%s` or `[%s; N]`, not `%s`",
+		AuthAccountType,
 		AuthAccountType,
 		e.Type.QualifiedString(),
 	)
@@ -2374,6 +2896,7 @@ func (e *InvalidTransactionPrepareParameterTypeError) Error() string {
 
 func (*InvalidTransactionPrepareParameterTypeError) isSemanticError() {}
 
+
 // InvalidNestedDeclarationError
 
 type InvalidNestedDeclarationError struct {
@@ -2966,3 +3489,93 @@ func (e *InvalidEntryPointTypeError) Error() string {
 		e.Type.QualifiedString(),
 	)
 }
+
+// DuplicateProjectLocationError
+
+type DuplicateProjectLocationError struct {
+	Location common.Location
+}
+
+func (e *DuplicateProjectLocationError) Error() string {
+	return fmt.Sprintf("duplicate program location in project: `%s`", e.Location)
+}
+
+// InvalidMemoizedFunctionError
+
+type InvalidMemoizedFunctionError struct {
+	Name    string
+	Message string
+	ast.Range
+}
+
+func (e *InvalidMemoizedFunctionError) Error() string {
+	return fmt.Sprintf(
+		"invalid memoized function `%s`: %s",
+		e.Name,
+		e.Message,
+	)
+}
+
+func (*InvalidMemoizedFunctionError) isSemanticError() {}
+
+// ImpureMemoizedFunctionError
+
+type ImpureMemoizedFunctionError struct {
+	FunctionName string
+	Message      string
+	ast.Range
+}
+
+func (e *ImpureMemoizedFunctionError) Error() string {
+	return fmt.Sprintf(
+		"memoized function `%s` must be pure: %s",
+		e.FunctionName,
+		e.Message,
+	)
+}
+
+func (e *ImpureMemoizedFunctionError) SecondaryError() string {
+	return "the result of a memoized function is cached, so it must only depend on constants"
+}
+
+func (*ImpureMemoizedFunctionError) isSemanticError() {}
+
+// InvalidFieldValueError
+
+type InvalidFieldValueError struct {
+	Name string
+	ast.Range
+}
+
+func (e *InvalidFieldValueError) Error() string {
+	return fmt.Sprintf(
+		"invalid value for field `%s`",
+		e.Name,
+	)
+}
+
+func (e *InvalidFieldValueError) SecondaryError() string {
+	return "only constant fields of contracts may declare a value"
+}
+
+func (*InvalidFieldValueError) isSemanticError() {}
+
+// NonConstantFieldValueError
+
+type NonConstantFieldValueError struct {
+	Name string
+	ast.Range
+}
+
+func (e *NonConstantFieldValueError) Error() string {
+	return fmt.Sprintf(
+		"value of field `%s` is not a constant expression",
+		e.Name,
+	)
+}
+
+func (e *NonConstantFieldValueError) SecondaryError() string {
+	return "the value of a field must only consist of literals and operations on them"
+}
+
+func (*NonConstantFieldValueError) isSemanticError() {}
@@ -20,20 +20,67 @@ package sema
 
 type FunctionActivation struct {
 	ReturnType           Type
-	Loops                int
-	Switches             int
+	JumpTargets          []*JumpTarget
 	ValueActivationDepth int
 	ReturnInfo           *ReturnInfo
 	ReportedDeadCode     bool
 	InitializationInfo   *InitializationInfo
+	// SavedStorageTypes are the static types of the values saved to literal storage paths
+	// of account variables in the function, see Checker.checkAccountStorageInvocation
+	SavedStorageTypes map[string]Type
 }
 
 func (a FunctionActivation) InLoop() bool {
-	return a.Loops > 0
+	return a.ContinueTarget() != nil
 }
 
 func (a FunctionActivation) InSwitch() bool {
-	return a.Switches > 0
+	for _, target := range a.JumpTargets {
+		if !target.IsLoop {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakTarget returns the innermost loop or switch statement,
+// i.e. the statement a `break` statement jumps out of.
+// It returns nil if there is no such statement.
+//
+func (a FunctionActivation) BreakTarget() *JumpTarget {
+	count := len(a.JumpTargets)
+	if count < 1 {
+		return nil
+	}
+	return a.JumpTargets[count-1]
+}
+
+// ContinueTarget returns the innermost loop statement,
+// i.e. the statement a `continue` statement jumps to.
+// It returns nil if there is no such statement.
+//
+func (a FunctionActivation) ContinueTarget() *JumpTarget {
+	for i := len(a.JumpTargets) - 1; i >= 0; i-- {
+		target := a.JumpTargets[i]
+		if target.IsLoop {
+			return target
+		}
+	}
+	return nil
+}
+
+// JumpTarget is a loop or switch statement,
+// which `break` and `continue` statements jump out of.
+//
+type JumpTarget struct {
+	IsLoop bool
+	// ValueActivationDepth is the depth of the value activations
+	// when the loop or switch statement is entered
+	ValueActivationDepth int
+	// BreakResources are the resources at each `break` statement
+	BreakResources []*Resources
+	// ContinueResources are the resources at each `continue` statement
+	ContinueResources []*Resources
 }
 
 type FunctionActivations struct {
@@ -84,18 +131,28 @@ func (a *FunctionActivations) Current() *FunctionActivation {
 	return a.activations[lastIndex]
 }
 
-func (a *FunctionActivations) WithLoop(f func()) {
-	a.Current().Loops++
-	defer func() {
-		a.Current().Loops--
-	}()
-	f()
+func (a *FunctionActivations) WithLoop(valueActivationDepth int, f func()) *JumpTarget {
+	return a.withJumpTarget(true, valueActivationDepth, f)
+}
+
+func (a *FunctionActivations) WithSwitch(valueActivationDepth int, f func()) *JumpTarget {
+	return a.withJumpTarget(false, valueActivationDepth, f)
 }
 
-func (a *FunctionActivations) WithSwitch(f func()) {
-	a.Current().Switches++
+func (a *FunctionActivations) withJumpTarget(isLoop bool, valueActivationDepth int, f func()) *JumpTarget {
+	target := &JumpTarget{
+		IsLoop:               isLoop,
+		ValueActivationDepth: valueActivationDepth,
+	}
+
+	current := a.Current()
+	current.JumpTargets = append(current.JumpTargets, target)
 	defer func() {
-		a.Current().Switches--
+		lastIndex := len(current.JumpTargets) - 1
+		current.JumpTargets = current.JumpTargets[:lastIndex]
 	}()
+
 	f()
+
+	return target
 }
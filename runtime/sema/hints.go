@@ -20,8 +20,10 @@ package sema
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 )
 
 type Hint interface {
@@ -116,3 +118,109 @@ func (h *UnnecessaryCastHint) Hint() string {
 }
 
 func (*UnnecessaryCastHint) isHint() {}
+
+// DeprecatedDeclarationHint
+
+type DeprecatedDeclarationHint struct {
+	Name    string
+	Message string
+	ast.Range
+}
+
+func (h *DeprecatedDeclarationHint) Hint() string {
+	if h.Message == "" {
+		return fmt.Sprintf("`%s` is deprecated", h.Name)
+	}
+	return fmt.Sprintf("`%s` is deprecated: %s", h.Name, h.Message)
+}
+
+func (*DeprecatedDeclarationHint) isHint() {}
+
+// InvalidLinkTargetHint
+
+type InvalidLinkTargetHint struct {
+	TargetType Type
+	BorrowType Type
+	ast.Range
+}
+
+func (h *InvalidLinkTargetHint) Hint() string {
+	return fmt.Sprintf(
+		"link target of type `%s` can never be borrowed as `%s`",
+		h.TargetType,
+		h.BorrowType,
+	)
+}
+
+func (*InvalidLinkTargetHint) isHint() {}
+
+// ExternalMutationHint
+
+type ExternalMutationHint struct {
+	Name            string
+	DeclarationKind common.DeclarationKind
+	ContainerType   Type
+	ast.Range
+}
+
+func (h *ExternalMutationHint) Hint() string {
+	return fmt.Sprintf(
+		"`%s` is mutated outside of `%s`, which will be an error in the future: %s is only mutable inside `%s`",
+		h.Name,
+		h.ContainerType.QualifiedString(),
+		h.DeclarationKind.Name(),
+		h.ContainerType.QualifiedString(),
+	)
+}
+
+func (*ExternalMutationHint) isHint() {}
+
+// InvalidatedResourceReferenceHint
+
+type InvalidatedResourceReferenceHint struct {
+	ast.Range
+}
+
+func (*InvalidatedResourceReferenceHint) Hint() string {
+	return "reference is used after the referenced resource may have been moved or destroyed, which will be an error in the future"
+}
+
+func (*InvalidatedResourceReferenceHint) isHint() {}
+
+// IndexOutOfBoundsHint
+
+type IndexOutOfBoundsHint struct {
+	Index *big.Int
+	Size  int64
+	ast.Range
+}
+
+func (h *IndexOutOfBoundsHint) Hint() string {
+	return fmt.Sprintf(
+		"index %s is out of bounds for array of size %d, which aborts at run-time",
+		h.Index,
+		h.Size,
+	)
+}
+
+func (*IndexOutOfBoundsHint) isHint() {}
+
+// LoopBoundOutOfBoundsHint
+
+type LoopBoundOutOfBoundsHint struct {
+	Min  *big.Int
+	Max  *big.Int
+	Size int64
+	ast.Range
+}
+
+func (h *LoopBoundOutOfBoundsHint) Hint() string {
+	return fmt.Sprintf(
+		"index ranges from %s to %s, which exceeds the bounds of the array of size %d",
+		h.Min,
+		h.Max,
+		h.Size,
+	)
+}
+
+func (*LoopBoundOutOfBoundsHint) isHint() {}
@@ -40,6 +40,7 @@ type ImportElement struct {
 	Access          ast.Access
 	Type            Type
 	ArgumentLabels  []string
+	DocString       string
 }
 
 // ElaborationImport
@@ -50,7 +51,7 @@ type ElaborationImport struct {
 
 func variablesToImportElements(variables *StringVariableOrderedMap) *StringImportElementOrderedMap {
 
-	elements := NewStringImportElementOrderedMap()
+	elements := &StringImportElementOrderedMap{}
 
 	variables.Foreach(func(name string, variable *Variable) {
 
@@ -59,6 +60,7 @@ func variablesToImportElements(variables *StringVariableOrderedMap) *StringImpor
 			Access:          variable.Access,
 			Type:            variable.Type,
 			ArgumentLabels:  variable.ArgumentLabels,
+			DocString:       variable.DocString,
 		})
 	})
 
@@ -0,0 +1,290 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+const InclusiveRangeTypeName = "InclusiveRange"
+
+const InclusiveRangeTypeStartFieldName = "start"
+const InclusiveRangeTypeEndFieldName = "end"
+const InclusiveRangeTypeIsEmptyFieldName = "isEmpty"
+const InclusiveRangeTypeContainsFunctionName = "contains"
+
+const inclusiveRangeTypeStartFieldDocString = `
+The first element of the range
+`
+
+const inclusiveRangeTypeEndFieldDocString = `
+The last element of the range.
+If the range is empty, the value is unspecified
+`
+
+const inclusiveRangeTypeIsEmptyFieldDocString = `
+Returns true if the range contains no elements
+`
+
+const inclusiveRangeTypeContainsFunctionDocString = `
+Returns true if the given element is contained in the range
+`
+
+// InclusiveRangeType represents a range of integers,
+// from a start value up to and including an end value.
+//
+// Values of this type are created by the range operators `...` and `..<`,
+// and are evaluated lazily, i.e. the elements are never materialized.
+//
+type InclusiveRangeType struct {
+	MemberType          Type
+	memberResolvers     map[string]MemberResolver
+	memberResolversOnce sync.Once
+}
+
+var _ ParameterizedType = &InclusiveRangeType{}
+
+func (*InclusiveRangeType) IsType() {}
+
+func (*InclusiveRangeType) Tag() TypeTag {
+	return InclusiveRangeTypeTag
+}
+
+func (t *InclusiveRangeType) string(typeFormatter func(Type) string) string {
+	var builder strings.Builder
+	builder.WriteString(InclusiveRangeTypeName)
+	if t.MemberType != nil {
+		builder.WriteRune('<')
+		builder.WriteString(typeFormatter(t.MemberType))
+		builder.WriteRune('>')
+	}
+	return builder.String()
+}
+
+func (t *InclusiveRangeType) String() string {
+	return t.string(func(t Type) string {
+		return t.String()
+	})
+}
+
+func (t *InclusiveRangeType) QualifiedString() string {
+	return t.string(func(t Type) string {
+		return t.QualifiedString()
+	})
+}
+
+func (t *InclusiveRangeType) ID() TypeID {
+	return TypeID(t.string(func(t Type) string {
+		return string(t.ID())
+	}))
+}
+
+func (t *InclusiveRangeType) Equal(other Type) bool {
+	otherRange, ok := other.(*InclusiveRangeType)
+	if !ok {
+		return false
+	}
+	if otherRange.MemberType == nil {
+		return t.MemberType == nil
+	}
+	return otherRange.MemberType.Equal(t.MemberType)
+}
+
+func (*InclusiveRangeType) IsResourceType() bool {
+	return false
+}
+
+func (t *InclusiveRangeType) IsInvalidType() bool {
+	if t.MemberType == nil {
+		return false
+	}
+	return t.MemberType.IsInvalidType()
+}
+
+func (t *InclusiveRangeType) TypeAnnotationState() TypeAnnotationState {
+	if t.MemberType == nil {
+		return TypeAnnotationStateValid
+	}
+	return t.MemberType.TypeAnnotationState()
+}
+
+func (*InclusiveRangeType) IsStorable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*InclusiveRangeType) IsExternallyReturnable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*InclusiveRangeType) IsImportable(_ map[*Member]bool) bool {
+	return false
+}
+
+func (*InclusiveRangeType) IsEquatable() bool {
+	return false
+}
+
+func (t *InclusiveRangeType) RewriteWithRestrictedTypes() (Type, bool) {
+	return t, false
+}
+
+func (t *InclusiveRangeType) Unify(
+	other Type,
+	typeParameters *TypeParameterTypeOrderedMap,
+	report func(err error),
+	outerRange ast.Range,
+) bool {
+	otherRange, ok := other.(*InclusiveRangeType)
+	if !ok {
+		return false
+	}
+
+	if t.MemberType == nil {
+		return false
+	}
+
+	return t.MemberType.Unify(otherRange.MemberType, typeParameters, report, outerRange)
+}
+
+func (t *InclusiveRangeType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
+	var resolvedMemberType Type
+	if t.MemberType != nil {
+		resolvedMemberType = t.MemberType.Resolve(typeArguments)
+		if resolvedMemberType == nil {
+			return nil
+		}
+	}
+
+	return &InclusiveRangeType{
+		MemberType: resolvedMemberType,
+	}
+}
+
+var inclusiveRangeTypeParameter = &TypeParameter{
+	Name:      "T",
+	TypeBound: IntegerType,
+}
+
+func (*InclusiveRangeType) TypeParameters() []*TypeParameter {
+	return []*TypeParameter{
+		inclusiveRangeTypeParameter,
+	}
+}
+
+func (*InclusiveRangeType) Instantiate(typeArguments []Type, _ func(err error)) Type {
+	memberType := typeArguments[0]
+	return &InclusiveRangeType{
+		MemberType: memberType,
+	}
+}
+
+func (t *InclusiveRangeType) BaseType() Type {
+	if t.MemberType == nil {
+		return nil
+	}
+	return &InclusiveRangeType{}
+}
+
+func (t *InclusiveRangeType) TypeArguments() []Type {
+	memberType := t.MemberType
+	if memberType == nil {
+		memberType = IntegerType
+	}
+	return []Type{
+		memberType,
+	}
+}
+
+func (t *InclusiveRangeType) GetMembers() map[string]MemberResolver {
+	t.initializeMemberResolvers()
+	return t.memberResolvers
+}
+
+func (t *InclusiveRangeType) initializeMemberResolvers() {
+	t.memberResolversOnce.Do(func() {
+		memberType := t.MemberType
+		if memberType == nil {
+			memberType = IntegerType
+		}
+
+		t.memberResolvers = withBuiltinMembers(t, map[string]MemberResolver{
+			InclusiveRangeTypeStartFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						memberType,
+						inclusiveRangeTypeStartFieldDocString,
+					)
+				},
+			},
+			InclusiveRangeTypeEndFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						memberType,
+						inclusiveRangeTypeEndFieldDocString,
+					)
+				},
+			},
+			InclusiveRangeTypeIsEmptyFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						BoolType,
+						inclusiveRangeTypeIsEmptyFieldDocString,
+					)
+				},
+			},
+			InclusiveRangeTypeContainsFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						InclusiveRangeContainsFunctionType(memberType),
+						inclusiveRangeTypeContainsFunctionDocString,
+					)
+				},
+			},
+		})
+	})
+}
+
+func InclusiveRangeContainsFunctionType(memberType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:          ArgumentLabelNotRequired,
+				Identifier:     "element",
+				TypeAnnotation: NewTypeAnnotation(memberType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+	}
+}
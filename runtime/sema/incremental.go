@@ -0,0 +1,343 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// IncrementalCheck allows a checker to reuse the results of a previous check of a program,
+// e.g. after the program was edited in the language server.
+//
+// The check of a function declaration, i.e. of its signature and its body, is reused,
+// if the function declaration is unchanged and is not affected by any changes.
+// A function declaration is unchanged if it has the same source code and position
+// as a function declaration in the previous program, and its enclosing composite
+// and interface declarations have the same headers (kind, name, and conformances).
+//
+// Affected declarations are determined using a dependency graph of all declarations:
+// A declaration depends on all declarations whose names occur in its source code,
+// and it is affected if it transitively depends on a changed, added, or removed declaration.
+// The dependencies are an over-approximation, so a change never results in stale errors.
+// If an import or pragma declaration changed, no checks are reused.
+//
+// NOTE: The elaboration of a checker which reuses previous results does not contain
+// the results of the reused function declarations, so the checked program must not be interpreted.
+// Reuse is also disabled if position info is enabled, as it is not reused.
+//
+type IncrementalCheck struct {
+	previous *Checker
+	// reusableFunctions maps the unaffected function declarations of the program
+	// to the equivalent function declarations of the previous program
+	reusableFunctions map[*ast.FunctionDeclaration]*ast.FunctionDeclaration
+}
+
+// functionCheckResult is the result of checking a function declaration
+//
+type functionCheckResult struct {
+	errors []error
+	hints  []Hint
+}
+
+// NewIncrementalCheck returns an incremental check of the given program and code,
+// which reuses the results of the given checker of the previous program and code.
+//
+// The previous checker must have already checked the previous program,
+// and it must have been configured with the same options as the new checker.
+//
+func NewIncrementalCheck(
+	previousChecker *Checker,
+	previousCode string,
+	program *ast.Program,
+	code string,
+) *IncrementalCheck {
+
+	incrementalCheck := &IncrementalCheck{
+		previous:          previousChecker,
+		reusableFunctions: map[*ast.FunctionDeclaration]*ast.FunctionDeclaration{},
+	}
+
+	if previousChecker == nil || !previousChecker.IsChecked() {
+		return incrementalCheck
+	}
+
+	previousDeclarations := collectIncrementalDeclarations(previousChecker.Program, previousCode)
+	declarations := collectIncrementalDeclarations(program, code)
+
+	if !stringsEqual(previousDeclarations.imports, declarations.imports) ||
+		!stringsEqual(previousDeclarations.pragmas, declarations.pragmas) {
+
+		return incrementalCheck
+	}
+
+	affectedNames := declarations.affectedNames(previousDeclarations)
+
+	previousFunctions := map[ast.Position]incrementalFunction{}
+	for _, function := range previousDeclarations.functions {
+		previousFunctions[function.declaration.StartPos] = function
+	}
+
+	for _, function := range declarations.functions {
+		previousFunction, ok := previousFunctions[function.declaration.StartPos]
+		if !ok ||
+			previousFunction.text != function.text ||
+			!stringsEqual(previousFunction.containerHeaders, function.containerHeaders) {
+
+			continue
+		}
+
+		if function.references.intersects(affectedNames) {
+			continue
+		}
+
+		incrementalCheck.reusableFunctions[function.declaration] = previousFunction.declaration
+	}
+
+	return incrementalCheck
+}
+
+// previousResult returns the result of the check of the function declaration
+// in the previous program which is equivalent to the given function declaration,
+// if the function declaration is unaffected and was checked previously
+//
+func (c *IncrementalCheck) previousResult(declaration *ast.FunctionDeclaration) (functionCheckResult, bool) {
+	previousDeclaration, ok := c.reusableFunctions[declaration]
+	if !ok {
+		return functionCheckResult{}, false
+	}
+
+	result, ok := c.previous.functionCheckResults[previousDeclaration]
+	return result, ok
+}
+
+type nameSet map[string]struct{}
+
+func (s nameSet) intersects(other nameSet) bool {
+	for name := range s {
+		if _, ok := other[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+type incrementalDeclaration struct {
+	name       string
+	text       string
+	references nameSet
+}
+
+type incrementalFunction struct {
+	declaration      *ast.FunctionDeclaration
+	text             string
+	containerHeaders []string
+	references       nameSet
+}
+
+type incrementalDeclarations struct {
+	imports      []string
+	pragmas      []string
+	declarations []incrementalDeclaration
+	functions    []incrementalFunction
+}
+
+func collectIncrementalDeclarations(program *ast.Program, code string) *incrementalDeclarations {
+	result := &incrementalDeclarations{}
+
+	for _, declaration := range program.ImportDeclarations() {
+		result.imports = append(result.imports, sourceText(code, declaration))
+	}
+
+	for _, declaration := range program.PragmaDeclarations() {
+		result.pragmas = append(result.pragmas, sourceText(code, declaration))
+	}
+
+	for _, declaration := range program.Declarations() {
+		switch declaration.(type) {
+		case *ast.ImportDeclaration, *ast.PragmaDeclaration:
+			continue
+		}
+
+		result.collect(declaration, code, nil)
+	}
+
+	return result
+}
+
+func (d *incrementalDeclarations) collect(declaration ast.Declaration, code string, containerHeaders []string) {
+	text := sourceText(code, declaration)
+
+	if identifier := declaration.DeclarationIdentifier(); identifier != nil {
+		d.declarations = append(
+			d.declarations,
+			incrementalDeclaration{
+				name:       identifier.Identifier,
+				text:       text,
+				references: referencedNames(text),
+			},
+		)
+	}
+
+	switch declaration := declaration.(type) {
+	case *ast.FunctionDeclaration:
+		d.functions = append(
+			d.functions,
+			incrementalFunction{
+				declaration:      declaration,
+				text:             text,
+				containerHeaders: containerHeaders,
+				references:       referencedNames(text),
+			},
+		)
+		return
+
+	case *ast.CompositeDeclaration:
+		headerEnd := declaration.Identifier.EndPosition()
+		for _, conformance := range declaration.Conformances {
+			headerEnd = conformance.EndPosition()
+		}
+		containerHeaders = appendHeader(containerHeaders, code, declaration.StartPos, headerEnd)
+
+	case *ast.InterfaceDeclaration:
+		containerHeaders = appendHeader(containerHeaders, code, declaration.StartPos, declaration.Identifier.EndPosition())
+	}
+
+	members := declaration.DeclarationMembers()
+	if members == nil {
+		return
+	}
+
+	for _, member := range members.Declarations() {
+		d.collect(member, code, containerHeaders)
+	}
+}
+
+// affectedNames returns the names of all declarations which are affected by the changes
+// from the given previous declarations, i.e. which are changed, added, or removed,
+// or which transitively depend on such declarations
+//
+func (d *incrementalDeclarations) affectedNames(previous *incrementalDeclarations) nameSet {
+
+	texts := func(declarations []incrementalDeclaration) map[string][]string {
+		result := map[string][]string{}
+		for _, declaration := range declarations {
+			result[declaration.name] = append(result[declaration.name], declaration.text)
+		}
+		return result
+	}
+
+	previousTexts := texts(previous.declarations)
+	currentTexts := texts(d.declarations)
+
+	affected := nameSet{}
+
+	for name, previousText := range previousTexts {
+		if !stringsEqual(previousText, currentTexts[name]) {
+			affected[name] = struct{}{}
+		}
+	}
+
+	for name := range currentTexts {
+		if _, ok := previousTexts[name]; !ok {
+			affected[name] = struct{}{}
+		}
+	}
+
+	// Propagate to dependents, until a fixed point is reached
+
+	for changed := true; changed; {
+		changed = false
+		for _, declaration := range d.declarations {
+			if _, ok := affected[declaration.name]; ok {
+				continue
+			}
+			if declaration.references.intersects(affected) {
+				affected[declaration.name] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	return affected
+}
+
+func appendHeader(headers []string, code string, startPos, endPos ast.Position) []string {
+	// NOTE: copy, the headers are shared between sibling declarations
+	result := make([]string, len(headers), len(headers)+1)
+	copy(result, headers)
+	return append(result, sourceText(code, ast.Range{StartPos: startPos, EndPos: endPos}))
+}
+
+// sourceText returns the source code of the given element
+//
+func sourceText(code string, element ast.HasPosition) string {
+	start := element.StartPosition().Offset
+	end := element.EndPosition().Offset + 1
+	if start < 0 || end > len(code) || start > end {
+		return ""
+	}
+	return code[start:end]
+}
+
+// referencedNames returns all identifiers which occur in the given source code.
+//
+// NOTE: The result includes identifiers in comments and strings,
+// as well as keywords, i.e. it is an over-approximation
+//
+func referencedNames(code string) nameSet {
+	names := nameSet{}
+
+	isIdentifierStart := func(c byte) bool {
+		return c == '_' ||
+			('a' <= c && c <= 'z') ||
+			('A' <= c && c <= 'Z')
+	}
+
+	isIdentifierPart := func(c byte) bool {
+		return isIdentifierStart(c) ||
+			('0' <= c && c <= '9')
+	}
+
+	for i := 0; i < len(code); {
+		if !isIdentifierStart(code[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(code) && isIdentifierPart(code[i]) {
+			i++
+		}
+		names[code[start:i]] = struct{}{}
+	}
+
+	return names
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, s := range a {
+		if b[i] != s {
+			return false
+		}
+	}
+	return true
+}
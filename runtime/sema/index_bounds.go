@@ -0,0 +1,228 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sema
+
+import (
+	"math/big"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// integerInterval is the set of integers an expression may evaluate to,
+// from min up to and including max.
+//
+// The intervals are used to detect indexing expressions
+// which are out of bounds of constant-sized arrays,
+// e.g. a constant index, or the variable of a loop over a range
+// which exceeds the size of the array.
+//
+type integerInterval struct {
+	min *big.Int
+	max *big.Int
+}
+
+func newIntegerPointInterval(value *big.Int) integerInterval {
+	return integerInterval{
+		min: value,
+		max: value,
+	}
+}
+
+func (i integerInterval) isPoint() bool {
+	return i.min.Cmp(i.max) == 0
+}
+
+// integerInterval returns the interval of the given, already checked integer expression,
+// if it is known.
+//
+// The interval is known for constant expressions, constants with a known interval,
+// the length of constant-sized arrays, and additions and subtractions of such expressions.
+//
+func (checker *Checker) integerInterval(expression ast.Expression) (integerInterval, bool) {
+
+	constant, ok := checker.constantValue(expression)
+	if ok {
+		value, ok := constant.Value.(*big.Int)
+		if !ok || !IsSameTypeKind(constant.Type, IntegerType) {
+			return integerInterval{}, false
+		}
+		return newIntegerPointInterval(value), true
+	}
+
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		variable := checker.valueActivations.Find(expression.Identifier.Identifier)
+		if variable == nil {
+			return integerInterval{}, false
+		}
+		interval, ok := checker.integerIntervals[variable]
+		return interval, ok
+
+	case *ast.MemberExpression:
+		if expression.Identifier.Identifier != "length" {
+			return integerInterval{}, false
+		}
+
+		memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[expression]
+		if !ok || memberInfo.IsOptional {
+			return integerInterval{}, false
+		}
+
+		arrayType, ok := memberInfo.AccessedType.(*ConstantSizedType)
+		if !ok {
+			return integerInterval{}, false
+		}
+
+		return newIntegerPointInterval(big.NewInt(arrayType.Size)), true
+
+	case *ast.BinaryExpression:
+		if expression.Operation != ast.OperationPlus &&
+			expression.Operation != ast.OperationMinus {
+
+			return integerInterval{}, false
+		}
+
+		left, ok := checker.integerInterval(expression.Left)
+		if !ok {
+			return integerInterval{}, false
+		}
+
+		right, ok := checker.integerInterval(expression.Right)
+		if !ok {
+			return integerInterval{}, false
+		}
+
+		if expression.Operation == ast.OperationPlus {
+			return integerInterval{
+				min: new(big.Int).Add(left.min, right.min),
+				max: new(big.Int).Add(left.max, right.max),
+			}, true
+		}
+
+		return integerInterval{
+			min: new(big.Int).Sub(left.min, right.max),
+			max: new(big.Int).Sub(left.max, right.min),
+		}, true
+	}
+
+	return integerInterval{}, false
+}
+
+// recordConstantInterval records the interval of the value of the given constant,
+// if it is known.
+//
+func (checker *Checker) recordConstantInterval(variable *Variable, value ast.Expression) {
+	if !checker.lintEnabled ||
+		variable == nil ||
+		!variable.IsConstant {
+
+		return
+	}
+
+	interval, ok := checker.integerInterval(value)
+	if !ok {
+		return
+	}
+
+	checker.integerIntervals[variable] = interval
+}
+
+// recordLoopVariableInterval records the interval of the variable of a for-in loop
+// which iterates over a range expression, i.e. `start...end` or `start..<end`,
+// if the bounds of the range are known.
+//
+func (checker *Checker) recordLoopVariableInterval(variable *Variable, value ast.Expression) {
+	if !checker.lintEnabled || variable == nil {
+		return
+	}
+
+	rangeExpression, ok := value.(*ast.BinaryExpression)
+	if !ok || binaryOperationKind(rangeExpression.Operation) != BinaryOperationKindRange {
+		return
+	}
+
+	start, ok := checker.integerInterval(rangeExpression.Left)
+	if !ok {
+		return
+	}
+
+	end, ok := checker.integerInterval(rangeExpression.Right)
+	if !ok {
+		return
+	}
+
+	max := end.max
+	if rangeExpression.Operation == ast.OperationRangeExclusive {
+		max = new(big.Int).Sub(max, big.NewInt(1))
+	}
+
+	// The loop variable can only have values
+	// if the range is not empty
+
+	if start.min.Cmp(max) > 0 {
+		return
+	}
+
+	checker.integerIntervals[variable] = integerInterval{
+		min: start.min,
+		max: max,
+	}
+}
+
+// checkIndexBounds reports a hint if the given indexing expression,
+// which indexes into a constant-sized array, is out of bounds for some or all of its values.
+//
+func (checker *Checker) checkIndexBounds(arrayType *ConstantSizedType, indexingExpression ast.Expression) {
+	if !checker.lintEnabled {
+		return
+	}
+
+	interval, ok := checker.integerInterval(indexingExpression)
+	if !ok {
+		return
+	}
+
+	size := big.NewInt(arrayType.Size)
+
+	exceedsLowerBound := interval.min.Sign() < 0
+	exceedsUpperBound := interval.max.Cmp(size) >= 0
+
+	if !exceedsLowerBound && !exceedsUpperBound {
+		return
+	}
+
+	if interval.isPoint() {
+		checker.hint(
+			&IndexOutOfBoundsHint{
+				Index: interval.min,
+				Size:  arrayType.Size,
+				Range: ast.NewRangeFromPositioned(indexingExpression),
+			},
+		)
+		return
+	}
+
+	checker.hint(
+		&LoopBoundOutOfBoundsHint{
+			Min:   interval.min,
+			Max:   interval.max,
+			Size:  arrayType.Size,
+			Range: ast.NewRangeFromPositioned(indexingExpression),
+		},
+	)
+}
@@ -18,10 +18,25 @@
 
 package sema
 
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
 type InitializationInfo struct {
 	ContainerType           Type
 	FieldMembers            *MemberAstFieldDeclarationOrderedMap
 	InitializedFieldMembers *MemberSet
+	// UninitializedFieldBranches are, for each field member which is initialized
+	// on some, but not all control-flow paths, the branches in which it is not initialized
+	UninitializedFieldBranches map[*Member][]ast.Range
+	// InitializingFunctions are the functions of the container which initialize fields,
+	// and which may be called before the initialization is complete,
+	// together with the fields they initialize
+	InitializingFunctions map[*Member][]*Member
+	// Partial is true if only the field members are initialized,
+	// e.g. in an initializing function, so other fields of the container
+	// might not be initialized yet, and the initialization is never complete
+	Partial bool
 }
 
 func NewInitializationInfo(
@@ -39,6 +54,10 @@ func NewInitializationInfo(
 // were initialized, false if some fields are uninitialized
 //
 func (info *InitializationInfo) InitializationComplete() bool {
+	if info.Partial {
+		return false
+	}
+
 	for pair := info.FieldMembers.Oldest(); pair != nil; pair = pair.Next() {
 		member := pair.Key
 
@@ -49,3 +68,118 @@ func (info *InitializationInfo) InitializationComplete() bool {
 
 	return true
 }
+
+// fieldInitializations are the field initializations on a control-flow path
+//
+type fieldInitializations struct {
+	initializedMembers    *MemberSet
+	uninitializedBranches map[*Member][]ast.Range
+}
+
+// branch returns the field initializations for a new branch,
+// which starts with the current initializations
+//
+func (info *InitializationInfo) branch() *fieldInitializations {
+	return &fieldInitializations{
+		initializedMembers: info.InitializedFieldMembers.Clone(),
+		// NOTE: the map is never mutated, only replaced
+		uninitializedBranches: info.UninitializedFieldBranches,
+	}
+}
+
+// mergeBranches sets the field initializations after two branches, one of which is taken.
+//
+// Only fields which are initialized in both branches are definitely initialized.
+// For the fields which are only initialized on some paths,
+// the branches in which they are not initialized are recorded
+//
+func (info *InitializationInfo) mergeBranches(
+	thenInitializations *fieldInitializations,
+	thenRange ast.Range,
+	elseInitializations *fieldInitializations,
+	elseRange ast.Range,
+) {
+	thenMembers := thenInitializations.initializedMembers
+	elseMembers := elseInitializations.initializedMembers
+
+	var uninitializedBranches map[*Member][]ast.Range
+
+	for pair := info.FieldMembers.Oldest(); pair != nil; pair = pair.Next() {
+		member := pair.Key
+
+		initializedInThen := thenMembers.Contains(member)
+		initializedInElse := elseMembers.Contains(member)
+
+		if initializedInThen && initializedInElse {
+			continue
+		}
+
+		// Prefer the more precise branches nested inside a branch,
+		// if the field is only initialized on some of its paths
+
+		thenBranches := thenInitializations.uninitializedBranches[member]
+		elseBranches := elseInitializations.uninitializedBranches[member]
+
+		var branches []ast.Range
+
+		switch {
+		case initializedInThen:
+			branches = elseBranches
+			if len(branches) == 0 {
+				branches = []ast.Range{elseRange}
+			}
+
+		case initializedInElse:
+			branches = thenBranches
+			if len(branches) == 0 {
+				branches = []ast.Range{thenRange}
+			}
+
+		default:
+			// The field is not initialized in either branch.
+			// If it is initialized on no path of either branch,
+			// it is just uninitialized
+
+			if len(thenBranches) == 0 && len(elseBranches) == 0 {
+				continue
+			}
+
+			if len(thenBranches) == 0 {
+				thenBranches = []ast.Range{thenRange}
+			}
+			if len(elseBranches) == 0 {
+				elseBranches = []ast.Range{elseRange}
+			}
+
+			branches = appendUniqueRanges(thenBranches, elseBranches)
+		}
+
+		if uninitializedBranches == nil {
+			uninitializedBranches = map[*Member][]ast.Range{}
+		}
+		uninitializedBranches[member] = branches
+	}
+
+	info.InitializedFieldMembers = thenMembers.Intersection(elseMembers)
+	info.UninitializedFieldBranches = uninitializedBranches
+}
+
+// appendUniqueRanges returns the ranges of both slices, without duplicates.
+// Both branches may share the ranges recorded before them
+//
+func appendUniqueRanges(ranges []ast.Range, others []ast.Range) []ast.Range {
+	result := make([]ast.Range, 0, len(ranges)+len(others))
+	result = append(result, ranges...)
+
+outer:
+	for _, other := range others {
+		for _, existing := range ranges {
+			if existing == other {
+				continue outer
+			}
+		}
+		result = append(result, other)
+	}
+
+	return result
+}
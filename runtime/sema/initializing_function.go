@@ -0,0 +1,251 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+const initializesDocPragma = "@initializes"
+
+var initializesDocPragmaRegexp = regexp.MustCompile(`^\s*@initializes\(([^)]*)\)\s*$`)
+
+// parseInitializedFields parses the docstring of a function
+// and returns the names of the fields it initializes, if it is an initializing function.
+//
+// A function of a composite is an initializing function if its docstring contains a line
+// with the doc pragma `@initializes`, with the names of the initialized fields,
+// e.g. `@initializes(balance, owner)`.
+//
+func parseInitializedFields(docString string) (names []string, ok bool) {
+
+	// Fast path: most docstrings do not contain the pragma
+
+	if !strings.Contains(docString, initializesDocPragma) {
+		return nil, false
+	}
+
+	for _, line := range strings.Split(docString, "\n") {
+		match := initializesDocPragmaRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		for _, name := range strings.Split(match[1], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			names = append(names, name)
+		}
+
+		return names, true
+	}
+
+	return nil, false
+}
+
+// initializingFunctions returns the initializing functions of the given composite declaration,
+// together with the field members they initialize.
+//
+// Initializing functions must be private, as they may only be called during initialization,
+// and they may only initialize fields of the composite
+//
+func (checker *Checker) initializingFunctions(
+	functions []*ast.FunctionDeclaration,
+	compositeType *CompositeType,
+	fieldMembers *MemberAstFieldDeclarationOrderedMap,
+) map[*Member][]*Member {
+
+	var result map[*Member][]*Member
+
+	for _, function := range functions {
+		names, ok := parseInitializedFields(function.DocString)
+		if !ok {
+			continue
+		}
+
+		functionName := function.Identifier.Identifier
+
+		functionMember, ok := compositeType.Members.Get(functionName)
+		if !ok {
+			continue
+		}
+
+		if function.Access != ast.AccessPrivate {
+			checker.report(
+				&InvalidInitializingFunctionAccessError{
+					Name:  functionName,
+					Range: ast.NewRangeFromPositioned(function.Identifier),
+				},
+			)
+		}
+
+		initializedMembers := []*Member{}
+		seen := map[*Member]bool{}
+
+		for _, name := range names {
+			member, ok := compositeType.Members.Get(name)
+			if ok {
+				_, ok = fieldMembers.Get(member)
+			}
+			if !ok {
+				checker.report(
+					&UnknownInitializedFieldError{
+						FieldName:    name,
+						FunctionName: functionName,
+						Range:        ast.NewRangeFromPositioned(function.Identifier),
+					},
+				)
+				continue
+			}
+
+			if seen[member] {
+				continue
+			}
+			seen[member] = true
+
+			initializedMembers = append(initializedMembers, member)
+		}
+
+		if result == nil {
+			result = map[*Member][]*Member{}
+		}
+		result[functionMember] = initializedMembers
+	}
+
+	return result
+}
+
+// initializingFunctionInitializationInfo returns the initialization info
+// for the given initializing function, which must initialize the given field members.
+//
+// Other fields of the container might not be initialized yet
+// when the function is called, so the initialization is partial
+//
+func initializingFunctionInitializationInfo(
+	initializerInfo *InitializationInfo,
+	initializedMembers []*Member,
+) *InitializationInfo {
+
+	fieldMembers := &MemberAstFieldDeclarationOrderedMap{}
+
+	for _, member := range initializedMembers {
+		field, _ := initializerInfo.FieldMembers.Get(member)
+		fieldMembers.Set(member, field)
+	}
+
+	info := NewInitializationInfo(initializerInfo.ContainerType, fieldMembers)
+	info.InitializingFunctions = initializerInfo.InitializingFunctions
+	info.Partial = fieldMembers.Len() < initializerInfo.FieldMembers.Len()
+
+	return info
+}
+
+// recordInitializingFunctionInvocation records the initialization of the fields
+// which are initialized by the invoked function, if it is an initializing function
+//
+func (checker *Checker) recordInitializingFunctionInvocation(invocationExpression *ast.InvocationExpression) {
+	functionActivation := checker.functionActivations.Current()
+	if functionActivation == nil {
+		return
+	}
+
+	info := functionActivation.InitializationInfo
+	if info == nil {
+		return
+	}
+
+	accessedSelfMember := checker.accessedSelfMember(invocationExpression.InvokedExpression)
+	if accessedSelfMember == nil {
+		return
+	}
+
+	initializedMembers, ok := info.InitializingFunctions[accessedSelfMember]
+	if !ok {
+		return
+	}
+
+	// If the function has already returned, the initialization
+	// is not definitive, and it must be ignored
+
+	if functionActivation.ReturnInfo.MaybeReturned {
+		return
+	}
+
+	for _, member := range initializedMembers {
+
+		// The field must be initializable in the current function,
+		// and a constant field must not be initialized twice
+
+		_, initializable := info.FieldMembers.Get(member)
+
+		if !initializable ||
+			(member.VariableKind == ast.VariableKindConstant &&
+				info.InitializedFieldMembers.Contains(member)) {
+
+			checker.report(
+				&AssignmentToConstantMemberError{
+					Name:  member.Identifier.Identifier,
+					Range: ast.NewRangeFromPositioned(invocationExpression),
+				},
+			)
+			continue
+		}
+
+		info.InitializedFieldMembers.Add(member)
+	}
+}
+
+// checkInitializingFunctionUse checks that a member expression which refers to an initializing function
+// is the invoked expression of an invocation on `self` in the initializer or in an initializing function
+// of the same composite
+//
+func (checker *Checker) checkInitializingFunctionUse(member *Member, expression *ast.MemberExpression) {
+	if member.DeclarationKind != common.DeclarationKindFunction {
+		return
+	}
+
+	if _, ok := parseInitializedFields(member.DocString); !ok {
+		return
+	}
+
+	if expression == checker.currentInvokedExpression {
+		functionActivation := checker.functionActivations.Current()
+		if functionActivation != nil &&
+			functionActivation.InitializationInfo != nil &&
+			checker.accessedSelfMember(expression) == member {
+
+			if _, ok := functionActivation.InitializationInfo.InitializingFunctions[member]; ok {
+				return
+			}
+		}
+	}
+
+	checker.report(
+		&InvalidInitializingFunctionUseError{
+			Name:  member.Identifier.Identifier,
+			Range: ast.NewRangeFromPositioned(expression),
+		},
+	)
+}
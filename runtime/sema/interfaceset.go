@@ -23,7 +23,7 @@ package sema
 type InterfaceSet InterfaceTypeStructOrderedMap
 
 func NewInterfaceSet() *InterfaceSet {
-	return (*InterfaceSet)(NewInterfaceTypeStructOrderedMap())
+	return (*InterfaceSet)(&InterfaceTypeStructOrderedMap{})
 }
 
 func (s *InterfaceSet) IsSubsetOf(other *InterfaceSet) bool {
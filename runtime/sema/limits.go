@@ -0,0 +1,146 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// enterExpression is called before an expression is checked.
+// It returns false if the expression must not be checked,
+// because the maximum expression nesting depth is reached.
+//
+// If it returns true, leaveExpression must be called
+// once the expression was checked.
+//
+func (checker *Checker) enterExpression(expression ast.Expression) bool {
+	if checker.maximumExpressionDepth > 0 &&
+		checker.expressionDepth >= checker.maximumExpressionDepth {
+
+		// Only report the error once for the outermost expression
+
+		if !checker.expressionDepthLimitReached {
+			checker.report(
+				&ExpressionDepthLimitReachedError{
+					Limit: checker.maximumExpressionDepth,
+					Range: ast.NewRangeFromPositioned(expression),
+				},
+			)
+			checker.expressionDepthLimitReached = true
+		}
+
+		return false
+	}
+
+	checker.expressionDepth++
+	return true
+}
+
+func (checker *Checker) leaveExpression() {
+	checker.expressionDepth--
+	if checker.expressionDepth == 0 {
+		checker.expressionDepthLimitReached = false
+	}
+}
+
+// enterType is called before a type is converted.
+// It returns false if the type must not be converted,
+// because the maximum type complexity is reached.
+//
+// The complexity of a type is the number of types it consists of,
+// so it is counted from the outermost type.
+//
+// If it returns true, leaveType must be called
+// once the type was converted.
+//
+func (checker *Checker) enterType(t ast.Type) bool {
+	if checker.typeDepth == 0 {
+		checker.typeComplexity = 0
+	}
+
+	if checker.maximumTypeComplexity > 0 &&
+		checker.typeComplexity >= checker.maximumTypeComplexity {
+
+		// Only report the error once, for the outermost type
+
+		if checker.typeComplexity == checker.maximumTypeComplexity {
+			checker.report(
+				&TypeComplexityLimitReachedError{
+					Limit: checker.maximumTypeComplexity,
+					Range: ast.NewRangeFromPositioned(t),
+				},
+			)
+			checker.typeComplexity++
+		}
+
+		return false
+	}
+
+	checker.typeComplexity++
+	checker.typeDepth++
+	return true
+}
+
+func (checker *Checker) leaveType() {
+	checker.typeDepth--
+}
+
+// checkDeclarationCount checks that the program does not have more declarations
+// than the maximum declaration count, if any, and reports an error if it does
+//
+func (checker *Checker) checkDeclarationCount(program *ast.Program) bool {
+	if checker.maximumDeclarationCount <= 0 {
+		return true
+	}
+
+	count := 0
+
+	for _, declaration := range program.Declarations() {
+		count += countDeclarations(declaration)
+
+		if count > checker.maximumDeclarationCount {
+			checker.report(
+				&DeclarationCountLimitReachedError{
+					Limit: checker.maximumDeclarationCount,
+					Range: ast.NewRangeFromPositioned(declaration),
+				},
+			)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// countDeclarations returns the number of declarations in the given declaration,
+// i.e. the declaration itself and all its nested declarations
+//
+func countDeclarations(declaration ast.Declaration) int {
+	count := 1
+
+	members := declaration.DeclarationMembers()
+	if members != nil {
+		for _, member := range members.Declarations() {
+			count += countDeclarations(member)
+		}
+	}
+
+	return count
+}
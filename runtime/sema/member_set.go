@@ -37,7 +37,7 @@ func NewMemberSet(parent *MemberSet) *MemberSet {
 //
 func (ms *MemberSet) Add(member *Member) {
 	if ms.members == nil {
-		ms.members = NewMemberStructOrderedMap()
+		ms.members = &MemberStructOrderedMap{}
 	}
 
 	ms.members.Set(member, struct{}{})
@@ -0,0 +1,99 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+// MemberSlots is the member dispatch table of a composite type.
+//
+// Each member of the composite type, field or function, is assigned a slot,
+// its index in declaration order. This allows the interpreter to access members by index,
+// instead of looking them up by name.
+//
+// The slots are determined by the members of the type only,
+// so the dispatch tables of the same type in different elaborations agree.
+//
+type MemberSlots struct {
+	// Type is the composite type the slots belong to
+	Type  *CompositeType
+	slots map[string]int
+}
+
+func newMemberSlots(compositeType *CompositeType) *MemberSlots {
+	var slots map[string]int
+
+	if compositeType.Members != nil {
+		slots = make(map[string]int, compositeType.Members.Len())
+		compositeType.Members.Foreach(func(name string, _ *Member) {
+			slots[name] = len(slots)
+		})
+	}
+
+	return &MemberSlots{
+		Type:  compositeType,
+		slots: slots,
+	}
+}
+
+// Slot returns the slot of the member with the given name
+//
+func (s *MemberSlots) Slot(name string) (int, bool) {
+	slot, ok := s.slots[name]
+	return slot, ok
+}
+
+// Len returns the number of slots
+//
+func (s *MemberSlots) Len() int {
+	return len(s.slots)
+}
+
+// memberSlots returns the member dispatch table of the given composite type.
+//
+// The table is resolved once per type and elaboration,
+// see Elaboration.CompositeTypeMemberSlots
+//
+func (checker *Checker) memberSlots(compositeType *CompositeType) *MemberSlots {
+	memberSlots, ok := checker.Elaboration.CompositeTypeMemberSlots[compositeType]
+	if !ok {
+		memberSlots = newMemberSlots(compositeType)
+		checker.Elaboration.CompositeTypeMemberSlots[compositeType] = memberSlots
+	}
+	return memberSlots
+}
+
+// memberSlot returns the slot of the given member
+// in the member dispatch table of its containing composite type,
+// or -1 if the member is not a member of a composite type
+//
+func (checker *Checker) memberSlot(member *Member) int {
+	if member == nil {
+		return -1
+	}
+
+	compositeType, ok := member.ContainerType.(*CompositeType)
+	if !ok {
+		return -1
+	}
+
+	slot, ok := checker.memberSlots(compositeType).Slot(member.Identifier.Identifier)
+	if !ok {
+		return -1
+	}
+
+	return slot
+}
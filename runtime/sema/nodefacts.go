@@ -0,0 +1,98 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// NodeFacts are the facts which the checker determined about the elements of a program,
+// by the IDs of the elements, see ast.NodeID.
+//
+// Unlike positions, IDs are stable references to elements,
+// which tools, e.g. coverage reports, analyses, and language servers,
+// can exchange across processes.
+//
+// Node facts are only recorded if position info is enabled, see WithPositionInfoEnabled.
+//
+type NodeFacts struct {
+	nodeIDs         *ast.NodeIDs
+	elaboration     *Elaboration
+	expressionTypes map[ast.NodeID]Type
+}
+
+func NewNodeFacts(nodeIDs *ast.NodeIDs, elaboration *Elaboration) *NodeFacts {
+	return &NodeFacts{
+		nodeIDs:         nodeIDs,
+		elaboration:     elaboration,
+		expressionTypes: map[ast.NodeID]Type{},
+	}
+}
+
+// NodeIDs returns the IDs of the elements of the program
+//
+func (f *NodeFacts) NodeIDs() *ast.NodeIDs {
+	return f.nodeIDs
+}
+
+func (f *NodeFacts) recordExpressionType(expression ast.Expression, ty Type) {
+	id, ok := f.nodeIDs.ID(expression)
+	if !ok {
+		return
+	}
+	f.expressionTypes[id] = ty
+}
+
+// ExpressionType returns the type of the expression with the given ID,
+// or false if there is no such expression, or it was not checked
+//
+func (f *NodeFacts) ExpressionType(id ast.NodeID) (Type, bool) {
+	ty, ok := f.expressionTypes[id]
+	return ty, ok
+}
+
+// DeclarationType returns the type of the declaration with the given ID,
+// i.e. the type of the declared function, composite, interface, or variable,
+// or false if there is no such declaration, or it was not checked
+//
+func (f *NodeFacts) DeclarationType(id ast.NodeID) (Type, bool) {
+	var ty Type
+
+	switch declaration := f.nodeIDs.Element(id).(type) {
+	case *ast.FunctionDeclaration:
+		if functionType, ok := f.elaboration.FunctionDeclarationFunctionTypes[declaration]; ok {
+			ty = functionType
+		}
+
+	case *ast.CompositeDeclaration:
+		if compositeType, ok := f.elaboration.CompositeDeclarationTypes[declaration]; ok {
+			ty = compositeType
+		}
+
+	case *ast.InterfaceDeclaration:
+		if interfaceType, ok := f.elaboration.InterfaceDeclarationTypes[declaration]; ok {
+			ty = interfaceType
+		}
+
+	case *ast.VariableDeclaration:
+		ty = f.elaboration.VariableDeclarationTargetTypes[declaration]
+	}
+
+	return ty, ty != nil
+}
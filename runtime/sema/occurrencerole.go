@@ -0,0 +1,41 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=OccurrenceRole
+
+// OccurrenceRole is the role of an occurrence of a declaration,
+// e.g. whether a variable or field is read or written
+//
+type OccurrenceRole uint
+
+const (
+	OccurrenceRoleUnknown OccurrenceRole = iota
+	// OccurrenceRoleDeclaration is the declaration itself
+	OccurrenceRoleDeclaration
+	// OccurrenceRoleRead is a use which reads the value
+	OccurrenceRoleRead
+	// OccurrenceRoleWrite is the target of an assignment or swap,
+	// or the container of an element which is assigned, e.g. `xs` in `xs[0] = 1`
+	OccurrenceRoleWrite
+	// OccurrenceRoleMove is a use which moves the resource, e.g. `<-r`
+	OccurrenceRoleMove
+	// OccurrenceRoleDestroy is a use which destroys the resource, e.g. `destroy r`
+	OccurrenceRoleDestroy
+)
@@ -20,6 +20,7 @@ package sema
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
@@ -87,11 +88,12 @@ func ASTToSemaPosition(position ast.Position) Position {
 	}
 }
 
-func (o *Occurrences) Put(startPos, endPos ast.Position, origin *Origin) {
+func (o *Occurrences) Put(startPos, endPos ast.Position, origin *Origin, role OccurrenceRole) {
 	occurrence := Occurrence{
 		StartPos: ASTToSemaPosition(startPos),
 		EndPos:   ASTToSemaPosition(endPos),
 		Origin:   origin,
+		Role:     role,
 	}
 	interval := intervalst.NewInterval(
 		occurrence.StartPos,
@@ -112,7 +114,10 @@ func (o *Occurrences) Put(startPos, endPos ast.Position, origin *Origin) {
 type Occurrence struct {
 	StartPos Position
 	EndPos   Position
-	Origin   *Origin
+	// Origin is the declaration which the occurrence refers to, if known
+	Origin *Origin
+	// Role is the role of the occurrence, e.g. whether it is a read or a write
+	Role OccurrenceRole
 }
 
 func (o *Occurrences) All() []Occurrence {
@@ -152,3 +157,29 @@ func (o *Occurrences) FindAll(pos Position) []Occurrence {
 	}
 	return occurrences
 }
+
+// ForOrigin returns all occurrences of the given declaration, in the order of their positions,
+// e.g. to find all writes of a field
+//
+func (o *Occurrences) ForOrigin(origin *Origin) []Occurrence {
+	var occurrences []Occurrence
+	for _, occurrence := range o.All() {
+		if occurrence.Origin == origin {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		a := occurrences[i]
+		b := occurrences[j]
+		if cmp := a.StartPos.Compare(b.StartPos); cmp != 0 {
+			return cmp < 0
+		}
+		if cmp := a.EndPos.Compare(b.EndPos); cmp != 0 {
+			return cmp < 0
+		}
+		return a.Role < b.Role
+	})
+
+	return occurrences
+}
@@ -0,0 +1,48 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common/orderedmap"
+)
+
+type StringTypeOrderedMap = orderedmap.OrderedMap[string, Type]
+
+type StringMemberOrderedMap = orderedmap.OrderedMap[string, *Member]
+
+type InterfaceTypeStructOrderedMap = orderedmap.OrderedMap[*InterfaceType, struct{}]
+
+type MemberStructOrderedMap = orderedmap.OrderedMap[*Member, struct{}]
+
+type StringVariableOrderedMap = orderedmap.OrderedMap[string, *Variable]
+
+type StringValueDeclarationOrderedMap = orderedmap.OrderedMap[string, ValueDeclaration]
+
+type ResourceInvalidationStructOrderedMap = orderedmap.OrderedMap[ResourceInvalidation, struct{}]
+
+type AstPositionResourceUseOrderedMap = orderedmap.OrderedMap[ast.Position, ResourceUse]
+
+type resourceKeyResourceInfoOrderedMap = orderedmap.OrderedMap[resourceKey, ResourceInfo]
+
+type StringImportElementOrderedMap = orderedmap.OrderedMap[string, ImportElement]
+
+type TypeParameterTypeOrderedMap = orderedmap.OrderedMap[*TypeParameter, Type]
+
+type MemberAstFieldDeclarationOrderedMap = orderedmap.OrderedMap[*Member, *ast.FieldDeclaration]
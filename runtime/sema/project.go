@@ -0,0 +1,225 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// ProjectProgram is a program of a project, e.g. a file
+//
+type ProjectProgram struct {
+	Location common.Location
+	Program  *ast.Program
+	// Suppressions are the per-line diagnostic suppressions of the program, if any.
+	// They are added to the diagnostic configuration given in the options
+	Suppressions []ast.DiagnosticSuppression
+}
+
+// ProjectImportResolverFunc resolves the location imported by the program
+// with the given importing location to the location of a program of the project.
+//
+// It returns false if the imported location is not a program of the project.
+//
+type ProjectImportResolverFunc func(
+	importingLocation common.Location,
+	importedLocation common.Location,
+) (
+	location common.Location,
+	ok bool,
+)
+
+// ProjectCheckResult is the result of checking a program of a project
+//
+type ProjectCheckResult struct {
+	Location    common.Location
+	Checker     *Checker
+	Elaboration *Elaboration
+	Diagnostics []Diagnostic
+	// Err is the error returned by the check of the program, if any
+	Err error
+}
+
+// CheckProject checks all given programs of a project.
+//
+// The imports of a program are resolved using the given resolver,
+// and the imported programs of the project are checked before the importing program,
+// i.e. the programs are checked in dependency order.
+// If the resolver is nil, imported locations are used as-is.
+// Imports of locations outside of the project are resolved using the import handler
+// given in the options, if any.
+//
+// The given options are used for the checkers of all programs.
+//
+// The results are returned in the order the programs were checked.
+// The returned error is only non-nil if a checker could not be created,
+// errors in the programs are reported in the results.
+//
+func CheckProject(
+	programs []ProjectProgram,
+	resolveImport ProjectImportResolverFunc,
+	options ...Option,
+) (
+	[]*ProjectCheckResult,
+	error,
+) {
+	projectChecker := &projectChecker{
+		programs:      map[common.LocationID]ProjectProgram{},
+		resolveImport: resolveImport,
+		options:       options,
+		checkers:      map[common.LocationID]*Checker{},
+	}
+
+	for _, program := range programs {
+		locationID := program.Location.ID()
+		if _, ok := projectChecker.programs[locationID]; ok {
+			return nil, &DuplicateProjectLocationError{
+				Location: program.Location,
+			}
+		}
+		projectChecker.programs[locationID] = program
+	}
+
+	for _, program := range programs {
+		_, err := projectChecker.check(program)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return projectChecker.results, nil
+}
+
+type projectChecker struct {
+	programs      map[common.LocationID]ProjectProgram
+	resolveImport ProjectImportResolverFunc
+	options       []Option
+	checkers      map[common.LocationID]*Checker
+	results       []*ProjectCheckResult
+}
+
+// check checks the given program, if it was not checked yet.
+//
+// The imported programs of the project are checked on demand,
+// when the import declarations of the program are checked,
+// so they are checked before the program
+//
+func (p *projectChecker) check(program ProjectProgram) (*Checker, error) {
+	locationID := program.Location.ID()
+
+	if checker, ok := p.checkers[locationID]; ok {
+		return checker, nil
+	}
+
+	checker, err := NewChecker(program.Program, program.Location, p.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.checkers[locationID] = checker
+
+	if len(program.Suppressions) > 0 {
+		config := DiagnosticConfig{}
+		if checker.diagnosticConfig != nil {
+			config = *checker.diagnosticConfig
+		}
+
+		// Copy the suppressions of the shared configuration,
+		// so the suppressions of the program are not added to it
+
+		suppressions := make([]ast.DiagnosticSuppression, 0, len(config.Suppressions)+len(program.Suppressions))
+		suppressions = append(suppressions, config.Suppressions...)
+		config.Suppressions = append(suppressions, program.Suppressions...)
+
+		checker.diagnosticConfig = &config
+	}
+
+	fallbackImportHandler := checker.importHandler
+
+	checker.importHandler = func(
+		checker *Checker,
+		importedLocation common.Location,
+		importRange ast.Range,
+	) (Import, error) {
+
+		importedProgram, ok := p.resolve(checker.Location, importedLocation)
+		if !ok {
+			if fallbackImportHandler == nil {
+				return nil, nil
+			}
+			return fallbackImportHandler(checker, importedLocation, importRange)
+		}
+
+		importedChecker, err := p.check(importedProgram)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the imported program is still being checked, the import is cyclic.
+		// The checker detects this using the elaboration import
+
+		if importedChecker.IsChecked() {
+			checkerErr := importedChecker.CheckerError()
+			if checkerErr != nil {
+				return nil, checkerErr
+			}
+		}
+
+		return ElaborationImport{
+			Elaboration: importedChecker.Elaboration,
+		}, nil
+	}
+
+	err = checker.Check()
+
+	p.results = append(
+		p.results,
+		&ProjectCheckResult{
+			Location:    program.Location,
+			Checker:     checker,
+			Elaboration: checker.Elaboration,
+			Diagnostics: checker.Diagnostics(),
+			Err:         err,
+		},
+	)
+
+	return checker, nil
+}
+
+// resolve returns the program of the project for the given imported location, if any
+//
+func (p *projectChecker) resolve(
+	importingLocation common.Location,
+	importedLocation common.Location,
+) (ProjectProgram, bool) {
+
+	location := importedLocation
+
+	if p.resolveImport != nil {
+		var ok bool
+		location, ok = p.resolveImport(importingLocation, importedLocation)
+		if !ok {
+			return ProjectProgram{}, false
+		}
+	}
+
+	program, ok := p.programs[location.ID()]
+	return program, ok
+}
@@ -87,7 +87,7 @@ func (ris *ResourceInvalidations) Add(invalidation ResourceInvalidation) {
 		return
 	}
 	if ris.invalidations == nil {
-		ris.invalidations = NewResourceInvalidationStructOrderedMap()
+		ris.invalidations = &ResourceInvalidationStructOrderedMap{}
 	}
 	ris.invalidations.Set(invalidation, struct{}{})
 }
@@ -97,7 +97,7 @@ func (rus *ResourceUses) Add(pos ast.Position) {
 		return
 	}
 	if rus.positions == nil {
-		rus.positions = NewAstPositionResourceUseOrderedMap()
+		rus.positions = &AstPositionResourceUseOrderedMap{}
 	}
 	rus.positions.Set(pos, ResourceUse{})
 }
@@ -109,7 +109,7 @@ func (rus *ResourceUses) MarkUseAfterInvalidationReported(pos ast.Position) {
 	use := rus.getOrEmpty(pos)
 	use.UseAfterInvalidationReported = true
 	if rus.positions == nil {
-		rus.positions = NewAstPositionResourceUseOrderedMap()
+		rus.positions = &AstPositionResourceUseOrderedMap{}
 	}
 	rus.positions.Set(pos, use)
 }
@@ -125,7 +125,7 @@ func (rus ResourceUses) IsUseAfterInvalidationReported(pos ast.Position) bool {
 //
 func (rus *ResourceUses) Merge(other ResourceUses) {
 	if rus.positions == nil {
-		rus.positions = NewAstPositionResourceUseOrderedMap()
+		rus.positions = &AstPositionResourceUseOrderedMap{}
 	}
 
 	_ = other.ForEach(func(pos ast.Position, use ResourceUse) error {
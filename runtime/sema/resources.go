@@ -84,16 +84,45 @@ func (ri ResourceInfo) Clone() ResourceInfo {
 	}
 }
 
+// resourceKey is the key of a resource in Resources:
+// a resource is either a variable or a composite member
+//
+type resourceKey struct {
+	variable *Variable
+	member   *Member
+}
+
+func newResourceKey(resource interface{}) resourceKey {
+	switch resource := resource.(type) {
+	case *Variable:
+		return resourceKey{variable: resource}
+	case *Member:
+		return resourceKey{member: resource}
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+func (k resourceKey) resource() interface{} {
+	if k.variable != nil {
+		return k.variable
+	}
+	return k.member
+}
+
 // Resources is a map which contains invalidation info for resources.
 //
 type Resources struct {
-	resources *InterfaceResourceInfoOrderedMap
+	resources *resourceKeyResourceInfoOrderedMap
 	Returns   bool
+	// Jumps is true if the resources are at a point after a `break` or `continue` statement,
+	// i.e. control flow jumped out of the current branch
+	Jumps bool
 }
 
 func NewResources() *Resources {
 	return &Resources{
-		resources: NewInterfaceResourceInfoOrderedMap(),
+		resources: &resourceKeyResourceInfoOrderedMap{},
 	}
 }
 
@@ -111,7 +140,8 @@ func (ris *Resources) String() string {
 }
 
 func (ris *Resources) Get(resource interface{}) ResourceInfo {
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	return info
 }
 
@@ -119,12 +149,13 @@ func (ris *Resources) Get(resource interface{}) ResourceInfo {
 // If the invalidation is not temporary, marks the resource to be definitely invalidated.
 //
 func (ris *Resources) AddInvalidation(resource interface{}, invalidation ResourceInvalidation) {
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	info.Invalidations.Add(invalidation)
 	if invalidation.Kind.IsDefinite() {
 		info.DefinitivelyInvalidated = true
 	}
-	ris.resources.Set(resource, info)
+	ris.resources.Set(key, info)
 }
 
 // RemoveTemporaryMoveInvalidation removes the given invalidation
@@ -135,38 +166,43 @@ func (ris *Resources) RemoveTemporaryMoveInvalidation(resource interface{}, inva
 		panic(errors.NewUnreachableError())
 	}
 
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	info.Invalidations.DeleteLocally(invalidation)
-	ris.resources.Set(resource, info)
+	ris.resources.Set(key, info)
 }
 
 // AddUse adds the given use position to the set of use positions for the given resource.
 //
 func (ris *Resources) AddUse(resource interface{}, use ast.Position) {
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	info.UsePositions.Add(use)
-	ris.resources.Set(resource, info)
+	ris.resources.Set(key, info)
 }
 
 func (ris *Resources) MarkUseAfterInvalidationReported(resource interface{}, pos ast.Position) {
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	info.UsePositions.MarkUseAfterInvalidationReported(pos)
-	ris.resources.Set(resource, info)
+	ris.resources.Set(key, info)
 }
 
 func (ris *Resources) IsUseAfterInvalidationReported(resource interface{}, pos ast.Position) bool {
-	info, _ := ris.resources.Get(resource)
+	key := newResourceKey(resource)
+	info, _ := ris.resources.Get(key)
 	return info.UsePositions.IsUseAfterInvalidationReported(pos)
 }
 
 func (ris *Resources) Clone() *Resources {
 	result := NewResources()
 	result.Returns = ris.Returns
+	result.Jumps = ris.Jumps
 	for pair := ris.resources.Oldest(); pair != nil; pair = pair.Next() {
-		resource := pair.Key
+		key := pair.Key
 		info := pair.Value
 
-		result.resources.Set(resource, info.Clone())
+		result.resources.Set(key, info.Clone())
 	}
 	return result
 }
@@ -176,7 +212,16 @@ func (ris *Resources) Size() int {
 }
 
 func (ris *Resources) ForEach(f func(resource interface{}, info ResourceInfo)) {
-	ris.resources.Foreach(f)
+	ris.resources.Foreach(func(key resourceKey, info ResourceInfo) {
+		f(key.resource(), info)
+	})
+}
+
+// Halts returns true if control flow does not continue after the current point,
+// i.e. the current branch returned or jumped.
+//
+func (ris *Resources) Halts() bool {
+	return ris.Returns || ris.Jumps
 }
 
 // MergeBranches merges the given resources from two branches into these resources.
@@ -187,46 +232,54 @@ func (ris *Resources) ForEach(f func(resource interface{}, info ResourceInfo)) {
 func (ris *Resources) MergeBranches(thenResources *Resources, elseResources *Resources) {
 
 	elseReturns := false
+	elseHalts := false
 	if elseResources != nil {
 		elseReturns = elseResources.Returns
+		elseHalts = elseResources.Halts()
 	}
 
-	merged := make(map[interface{}]struct{})
+	thenHalts := thenResources.Halts()
+
+	merged := make(map[resourceKey]struct{})
 
-	merge := func(resource interface{}) {
+	merge := func(key resourceKey) {
 
 		// Only merge each resource once.
 		// We iterate over the resources of the then-branch
 		// and the else-branch (if it exists)
 
-		if _, ok := merged[resource]; ok {
+		if _, ok := merged[key]; ok {
 			return
 		}
 		defer func() {
-			merged[resource] = struct{}{}
+			merged[key] = struct{}{}
 		}()
 
 		// Get the resource info in this outer scope,
 		// in the then-branch,
 		// and if there is an else-branch, from it.
 
-		info := ris.Get(resource)
-		thenInfo := thenResources.Get(resource)
+		info, _ := ris.resources.Get(key)
+		thenInfo, _ := thenResources.resources.Get(key)
 		var elseInfo ResourceInfo
 		if elseResources != nil {
-			elseInfo = elseResources.Get(resource)
+			elseInfo, _ = elseResources.resources.Get(key)
 		}
 
 		// The resource can be considered definitely invalidated in both branches
-		// if in both branches, there were invalidations or the branch returned.
+		// if in both branches, the resource was definitely invalidated,
+		// or the branch returned or jumped.
+		//
+		// Potential invalidations in a branch, e.g. in only one branch of a nested conditional,
+		// are not sufficient.
 		//
 		// The assumption that a returning branch results in a definitive invalidation
 		// can be made, because we check at the point of the return if the resource
-		// was invalidated.
+		// was invalidated. The same applies to jumps, i.e. `break` and `continue` statements.
 
 		definitelyInvalidatedInBranches :=
-			(!thenInfo.Invalidations.IsEmpty() || thenResources.Returns) &&
-				(!elseInfo.Invalidations.IsEmpty() || elseReturns)
+			(thenInfo.DefinitivelyInvalidated || thenHalts) &&
+				(elseInfo.DefinitivelyInvalidated || elseHalts)
 
 		// The resource can be considered definitively invalidated if it was already invalidated,
 		// or the resource was invalidated in both branches
@@ -235,37 +288,85 @@ func (ris *Resources) MergeBranches(thenResources *Resources, elseResources *Res
 			info.DefinitivelyInvalidated ||
 				definitelyInvalidatedInBranches
 
-		// If the a branch returns, the invalidations and uses won't have occurred in the outer scope,
-		// so only merge invalidations and uses if the branch did not return
+		// If the a branch returns or jumps, the invalidations and uses won't have occurred in the outer scope,
+		// so only merge invalidations and uses if the branch did not return or jump.
+		// The invalidations and uses of jumps are merged separately, see MergeJump
 
-		if !thenResources.Returns {
+		if !thenHalts {
 			info.Invalidations.Merge(thenInfo.Invalidations)
 			info.UsePositions.Merge(thenInfo.UsePositions)
 		}
 
-		if !elseReturns {
+		if !elseHalts {
 			info.Invalidations.Merge(elseInfo.Invalidations)
 			info.UsePositions.Merge(elseInfo.UsePositions)
 		}
 
-		ris.resources.Set(resource, info)
+		ris.resources.Set(key, info)
 	}
 
 	// Merge the resource info of all resources in the then-branch
 
-	thenResources.ForEach(func(resource interface{}, _ ResourceInfo) {
-		merge(resource)
+	thenResources.resources.Foreach(func(key resourceKey, _ ResourceInfo) {
+		merge(key)
 	})
 
 	// If there is an else-branch,
 	// then merge the resource info of all resources in it
 
 	if elseResources != nil {
-		elseResources.ForEach(func(resource interface{}, _ ResourceInfo) {
-			merge(resource)
+		elseResources.resources.Foreach(func(key resourceKey, _ ResourceInfo) {
+			merge(key)
 		})
 	}
 
 	ris.Returns = ris.Returns ||
 		(thenResources.Returns && elseReturns)
+
+	ris.Jumps = ris.Jumps ||
+		(thenHalts && elseHalts && !ris.Returns)
+}
+
+// MergeJump merges the given resources at a jump, i.e. a `break` or `continue` statement,
+// into these resources at the jump's target.
+// A resource is only considered definitively invalidated
+// if it was definitively invalidated both here and at the jump.
+//
+func (ris *Resources) MergeJump(jumpResources *Resources) {
+
+	merged := make(map[resourceKey]struct{})
+
+	merge := func(key resourceKey) {
+
+		// Only merge each resource once
+
+		if _, ok := merged[key]; ok {
+			return
+		}
+		merged[key] = struct{}{}
+
+		info, _ := ris.resources.Get(key)
+		jumpInfo, _ := jumpResources.resources.Get(key)
+
+		info.DefinitivelyInvalidated =
+			info.DefinitivelyInvalidated &&
+				jumpInfo.DefinitivelyInvalidated
+
+		info.Invalidations.Merge(jumpInfo.Invalidations)
+		info.UsePositions.Merge(jumpInfo.UsePositions)
+
+		ris.resources.Set(key, info)
+	}
+
+	// Merge the resource info of all resources known here,
+	// which may not have been known yet at the jump,
+	// and all resources known at the jump
+
+	ris.resources.Foreach(func(key resourceKey, _ ResourceInfo) {
+		merge(key)
+	})
+
+	jumpResources.resources.Foreach(func(key resourceKey, _ ResourceInfo) {
+		merge(key)
+	})
 }
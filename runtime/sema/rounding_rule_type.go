@@ -0,0 +1,116 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+const RoundingRuleTypeName = "RoundingRule"
+
+var RoundingRules = []NativeEnumCase{
+	RoundingRuleTowardZero,
+	RoundingRuleAwayFromZero,
+	RoundingRuleNearestHalfAway,
+	RoundingRuleNearestHalfEven,
+}
+
+var RoundingRuleType = newNativeEnumType(
+	RoundingRuleTypeName,
+	UInt8Type,
+	nil,
+)
+
+// RoundingRule determines how the digits of a fixed-point result
+// which are beyond the scale of the type are discarded
+//
+type RoundingRule uint8
+
+const (
+	RoundingRuleTowardZero RoundingRule = iota
+	RoundingRuleAwayFromZero
+	RoundingRuleNearestHalfAway
+	RoundingRuleNearestHalfEven
+)
+
+const RoundingRuleDocStringTowardZero = `
+Rounds towards zero, i.e. discards the digits beyond the scale of the type
+`
+
+const RoundingRuleDocStringAwayFromZero = `
+Rounds away from zero, i.e. to the next representable value with a greater magnitude
+`
+
+const RoundingRuleDocStringNearestHalfAway = `
+Rounds to the nearest representable value, and rounds ties away from zero
+`
+
+const RoundingRuleDocStringNearestHalfEven = `
+Rounds to the nearest representable value, and rounds ties to the value with an even last digit
+`
+
+func (rule RoundingRule) Name() string {
+	switch rule {
+	case RoundingRuleTowardZero:
+		return "towardZero"
+	case RoundingRuleAwayFromZero:
+		return "awayFromZero"
+	case RoundingRuleNearestHalfAway:
+		return "nearestHalfAway"
+	case RoundingRuleNearestHalfEven:
+		return "nearestHalfEven"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (rule RoundingRule) RawValue() uint8 {
+	// NOTE: only add new rules, do *NOT* change existing items,
+	// reuse raw values for other items, swap the order, etc.
+	//
+	// Existing stored values use these raw values and should not change
+
+	switch rule {
+	case RoundingRuleTowardZero:
+		return 0
+	case RoundingRuleAwayFromZero:
+		return 1
+	case RoundingRuleNearestHalfAway:
+		return 2
+	case RoundingRuleNearestHalfEven:
+		return 3
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (rule RoundingRule) DocString() string {
+	switch rule {
+	case RoundingRuleTowardZero:
+		return RoundingRuleDocStringTowardZero
+	case RoundingRuleAwayFromZero:
+		return RoundingRuleDocStringAwayFromZero
+	case RoundingRuleNearestHalfAway:
+		return RoundingRuleDocStringNearestHalfAway
+	case RoundingRuleNearestHalfEven:
+		return RoundingRuleDocStringNearestHalfEven
+	}
+
+	panic(errors.NewUnreachableError())
+}
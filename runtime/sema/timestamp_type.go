@@ -0,0 +1,506 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+const TimestampTypeName = "Timestamp"
+
+// TimestampType represents the type `Timestamp`,
+// a point in time with nanosecond precision,
+// stored as the number of nanoseconds since the Unix epoch (1970-01-01T00:00:00Z).
+//
+// Timestamps are always in UTC and have no notion of time zones, calendars, or leap seconds
+//
+var TimestampType = &SimpleType{
+	Name:                 TimestampTypeName,
+	QualifiedName:        TimestampTypeName,
+	TypeID:               TimestampTypeName,
+	tag:                  TimestampTypeTag,
+	IsInvalid:            false,
+	IsResource:           false,
+	Storable:             true,
+	Equatable:            true,
+	ExternallyReturnable: true,
+	Importable:           true,
+}
+
+const DurationTypeName = "Duration"
+
+// DurationType represents the type `Duration`,
+// the signed amount of time between two timestamps, stored as a number of nanoseconds
+//
+var DurationType = &SimpleType{
+	Name:                 DurationTypeName,
+	QualifiedName:        DurationTypeName,
+	TypeID:               DurationTypeName,
+	tag:                  DurationTypeTag,
+	IsInvalid:            false,
+	IsResource:           false,
+	Storable:             true,
+	Equatable:            true,
+	ExternallyReturnable: true,
+	Importable:           true,
+}
+
+func init() {
+	// The members are declared here instead of in the type declarations,
+	// as their function types refer to the types themselves
+
+	TimestampType.Members = func(t *SimpleType) map[string]MemberResolver {
+		return map[string]MemberResolver{
+			TimestampTypeUnixSecondsFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						Int64Type,
+						timestampTypeUnixSecondsFieldDocString,
+					)
+				},
+			},
+			TimestampTypeUnixNanosecondsFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						Int64Type,
+						timestampTypeUnixNanosecondsFieldDocString,
+					)
+				},
+			},
+			TimestampTypeAddFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						TimestampTypeAddFunctionType,
+						timestampTypeAddFunctionDocString,
+					)
+				},
+			},
+			TimestampTypeSubtractFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						TimestampTypeSubtractFunctionType,
+						timestampTypeSubtractFunctionDocString,
+					)
+				},
+			},
+			TimestampTypeDurationSinceFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						TimestampTypeDurationSinceFunctionType,
+						timestampTypeDurationSinceFunctionDocString,
+					)
+				},
+			},
+			TimestampTypeIsBeforeFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						TimestampTypeComparisonFunctionType,
+						timestampTypeIsBeforeFunctionDocString,
+					)
+				},
+			},
+			TimestampTypeIsAfterFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						TimestampTypeComparisonFunctionType,
+						timestampTypeIsAfterFunctionDocString,
+					)
+				},
+			},
+			ToStringFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						ToStringFunctionType,
+						timestampTypeToStringFunctionDocString,
+					)
+				},
+			},
+		}
+	}
+
+	DurationType.Members = func(t *SimpleType) map[string]MemberResolver {
+		return map[string]MemberResolver{
+			DurationTypeSecondsFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						Int64Type,
+						durationTypeSecondsFieldDocString,
+					)
+				},
+			},
+			DurationTypeNanosecondsFieldName: {
+				Kind: common.DeclarationKindField,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicConstantFieldMember(
+						t,
+						identifier,
+						Int64Type,
+						durationTypeNanosecondsFieldDocString,
+					)
+				},
+			},
+			DurationTypeAddFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DurationTypeArithmeticFunctionType,
+						durationTypeAddFunctionDocString,
+					)
+				},
+			},
+			DurationTypeSubtractFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DurationTypeArithmeticFunctionType,
+						durationTypeSubtractFunctionDocString,
+					)
+				},
+			},
+			DurationTypeMultiplyFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DurationTypeMultiplyFunctionType,
+						durationTypeMultiplyFunctionDocString,
+					)
+				},
+			},
+			DurationTypeIsShorterThanFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DurationTypeComparisonFunctionType,
+						durationTypeIsShorterThanFunctionDocString,
+					)
+				},
+			},
+			DurationTypeIsLongerThanFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DurationTypeComparisonFunctionType,
+						durationTypeIsLongerThanFunctionDocString,
+					)
+				},
+			},
+			ToStringFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						ToStringFunctionType,
+						durationTypeToStringFunctionDocString,
+					)
+				},
+			},
+		}
+	}
+}
+
+// Timestamp members
+
+const TimestampTypeUnixSecondsFieldName = "unixSeconds"
+
+const timestampTypeUnixSecondsFieldDocString = `
+The number of whole seconds since the Unix epoch, rounded towards negative infinity
+`
+
+const TimestampTypeUnixNanosecondsFieldName = "unixNanoseconds"
+
+const timestampTypeUnixNanosecondsFieldDocString = `
+The number of nanoseconds since the Unix epoch
+`
+
+const TimestampTypeAddFunctionName = "add"
+
+const timestampTypeAddFunctionDocString = `
+Returns the timestamp which is the given duration after this timestamp.
+
+Aborts if the result is outside of the range of timestamps
+`
+
+var TimestampTypeAddFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "duration",
+			TypeAnnotation: NewTypeAnnotation(DurationType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(TimestampType),
+}
+
+const TimestampTypeSubtractFunctionName = "subtract"
+
+const timestampTypeSubtractFunctionDocString = `
+Returns the timestamp which is the given duration before this timestamp.
+
+Aborts if the result is outside of the range of timestamps
+`
+
+var TimestampTypeSubtractFunctionType = TimestampTypeAddFunctionType
+
+const TimestampTypeDurationSinceFunctionName = "durationSince"
+
+const timestampTypeDurationSinceFunctionDocString = `
+Returns the duration from the given timestamp to this timestamp.
+
+The duration is negative if the given timestamp is after this timestamp.
+Aborts if the result is outside of the range of durations
+`
+
+var TimestampTypeDurationSinceFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(TimestampType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(DurationType),
+}
+
+const TimestampTypeIsBeforeFunctionName = "isBefore"
+
+const timestampTypeIsBeforeFunctionDocString = `
+Returns true if this timestamp is strictly before the given timestamp
+`
+
+const TimestampTypeIsAfterFunctionName = "isAfter"
+
+const timestampTypeIsAfterFunctionDocString = `
+Returns true if this timestamp is strictly after the given timestamp
+`
+
+var TimestampTypeComparisonFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(TimestampType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+}
+
+const timestampTypeToStringFunctionDocString = `
+Returns the timestamp as an RFC 3339 date and time in UTC, e.g. "2021-03-04T05:06:07.5Z".
+
+Trailing zeros of the fractional seconds are removed
+`
+
+// Duration members
+
+const DurationTypeSecondsFieldName = "seconds"
+
+const durationTypeSecondsFieldDocString = `
+The number of whole seconds of the duration, rounded towards zero
+`
+
+const DurationTypeNanosecondsFieldName = "nanoseconds"
+
+const durationTypeNanosecondsFieldDocString = `
+The number of nanoseconds of the duration
+`
+
+const DurationTypeAddFunctionName = "add"
+
+const durationTypeAddFunctionDocString = `
+Returns the sum of this duration and the given duration.
+
+Aborts if the result is outside of the range of durations
+`
+
+const DurationTypeSubtractFunctionName = "subtract"
+
+const durationTypeSubtractFunctionDocString = `
+Returns the difference of this duration and the given duration.
+
+Aborts if the result is outside of the range of durations
+`
+
+var DurationTypeArithmeticFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(DurationType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(DurationType),
+}
+
+const DurationTypeMultiplyFunctionName = "multiply"
+
+const durationTypeMultiplyFunctionDocString = `
+Returns this duration multiplied by the given factor.
+
+Aborts if the result is outside of the range of durations
+`
+
+var DurationTypeMultiplyFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "factor",
+			TypeAnnotation: NewTypeAnnotation(Int64Type),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(DurationType),
+}
+
+const DurationTypeIsShorterThanFunctionName = "isShorterThan"
+
+const durationTypeIsShorterThanFunctionDocString = `
+Returns true if this duration is strictly less than the given duration
+`
+
+const DurationTypeIsLongerThanFunctionName = "isLongerThan"
+
+const durationTypeIsLongerThanFunctionDocString = `
+Returns true if this duration is strictly greater than the given duration
+`
+
+var DurationTypeComparisonFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(DurationType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+}
+
+const durationTypeToStringFunctionDocString = `
+Returns the duration as a sequence of decimal numbers with unit suffixes, e.g. "1h2m3.5s".
+
+The units are "h", "m", "s", "ms", "µs", and "ns". A zero duration is "0s"
+`
+
+// Constructors
+
+var TimestampConstructorFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Identifier:     "unixSeconds",
+			TypeAnnotation: NewTypeAnnotation(UFix64Type),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(TimestampType),
+}
+
+const timestampConstructorFunctionDocString = `
+Creates a timestamp from the given number of seconds since the Unix epoch, e.g. the timestamp of a block.
+
+The fractional seconds are preserved. Aborts if the result is outside of the range of timestamps
+`
+
+var DurationConstructorFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Identifier:     "seconds",
+			TypeAnnotation: NewTypeAnnotation(Fix64Type),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(DurationType),
+}
+
+const durationConstructorFunctionDocString = `
+Creates a duration from the given, possibly negative and fractional, number of seconds.
+
+Aborts if the result is outside of the range of durations
+`
+
+func init() {
+
+	for _, constructor := range []struct {
+		name         string
+		functionType *FunctionType
+		docString    string
+	}{
+		{
+			name:         TimestampTypeName,
+			functionType: TimestampConstructorFunctionType,
+			docString:    timestampConstructorFunctionDocString,
+		},
+		{
+			name:         DurationTypeName,
+			functionType: DurationConstructorFunctionType,
+			docString:    durationConstructorFunctionDocString,
+		},
+	} {
+
+		// Check that the function is not accidentally redeclared
+
+		if BaseValueActivation.Find(constructor.name) != nil {
+			panic(errors.NewUnreachableError())
+		}
+
+		BaseValueActivation.Set(
+			constructor.name,
+			baseFunctionVariable(
+				constructor.name,
+				constructor.functionType,
+				constructor.docString,
+			),
+		)
+	}
+}
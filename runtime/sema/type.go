@@ -355,6 +355,11 @@ const toStringFunctionDocString = `
 A textual representation of this object
 `
 
+const derivedToStringFunctionDocString = `
+A textual representation of this object, which consists of the name of its type,
+and the names and values of its public fields, in declaration order
+`
+
 // toBigEndianBytes
 
 const ToBigEndianBytesFunctionName = "toBigEndianBytes"
@@ -469,11 +474,11 @@ func (t *OptionalType) QualifiedString() string {
 }
 
 func (t *OptionalType) ID() TypeID {
-	var id string
+	var id TypeID
 	if t.Type != nil {
-		id = string(t.Type.ID())
+		id = t.Type.ID()
 	}
-	return TypeID(fmt.Sprintf("%s?", id))
+	return common.NewOptionalTypeID(id)
 }
 
 func (t *OptionalType) Equal(other Type) bool {
@@ -842,6 +847,55 @@ func addSaturatingArithmeticFunctions(t SaturatingArithmeticType, members map[st
 	}
 }
 
+const FixedPointTypeMultiplyRoundFunctionName = "multiplyRound"
+const fixedPointTypeMultiplyRoundFunctionDocString = `
+self * other, with the digits beyond the scale of the type rounded according to the given rule.
+`
+
+const FixedPointTypeDivideRoundFunctionName = "divideRound"
+const fixedPointTypeDivideRoundFunctionDocString = `
+self / other, with the digits beyond the scale of the type rounded according to the given rule.
+`
+
+const FixedPointTypeRoundingRuleParameterName = "rule"
+
+func addRoundingArithmeticFunctions(t Type, members map[string]MemberResolver) {
+
+	arithmeticFunctionType := &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:          ArgumentLabelNotRequired,
+				Identifier:     "other",
+				TypeAnnotation: NewTypeAnnotation(t),
+			},
+			{
+				Identifier:     FixedPointTypeRoundingRuleParameterName,
+				TypeAnnotation: NewTypeAnnotation(RoundingRuleType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(t),
+	}
+
+	addArithmeticFunction := func(name string, docString string) {
+		members[name] = MemberResolver{
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+				return NewPublicFunctionMember(t, name, arithmeticFunctionType, docString)
+			},
+		}
+	}
+
+	addArithmeticFunction(
+		FixedPointTypeMultiplyRoundFunctionName,
+		fixedPointTypeMultiplyRoundFunctionDocString,
+	)
+
+	addArithmeticFunction(
+		FixedPointTypeDivideRoundFunctionName,
+		fixedPointTypeDivideRoundFunctionDocString,
+	)
+}
+
 // NumericType represent all the types in the integer range
 // and non-fractional ranged types.
 //
@@ -1190,6 +1244,7 @@ func (t *FixedPointNumericType) initializeMemberResolvers() {
 		members := map[string]MemberResolver{}
 
 		addSaturatingArithmeticFunctions(t, members)
+		addRoundingArithmeticFunctions(t, members)
 
 		t.memberResolvers = withBuiltinMembers(t, members)
 	})
@@ -1387,6 +1442,29 @@ var (
 			WithSaturatingAdd().
 			WithSaturatingSubtract().
 			WithSaturatingMultiply()
+
+	// Fix128Type represents the 128-bit signed decimal fixed-point type `Fix128`
+	// which has a scale of Fix128Scale, and checks for overflow and underflow
+	Fix128Type = NewFixedPointNumericType(Fix128TypeName).
+			WithTag(Fix128TypeTag).
+			WithIntRange(Fix128TypeMinIntBig, Fix128TypeMaxIntBig).
+			WithFractionalRange(Fix128TypeMinFractionalBig, Fix128TypeMaxFractionalBig).
+			WithScale(Fix128Scale).
+			WithSaturatingAdd().
+			WithSaturatingSubtract().
+			WithSaturatingMultiply().
+			WithSaturatingDivide()
+
+	// UFix128Type represents the 128-bit unsigned decimal fixed-point type `UFix128`
+	// which has a scale of Fix128Scale, and checks for overflow and underflow
+	UFix128Type = NewFixedPointNumericType(UFix128TypeName).
+			WithTag(UFix128TypeTag).
+			WithIntRange(UFix128TypeMinIntBig, UFix128TypeMaxIntBig).
+			WithFractionalRange(UFix128TypeMinFractionalBig, UFix128TypeMaxFractionalBig).
+			WithScale(Fix128Scale).
+			WithSaturatingAdd().
+			WithSaturatingSubtract().
+			WithSaturatingMultiply()
 )
 
 // Numeric type ranges
@@ -1487,9 +1565,30 @@ var (
 
 	UFix64TypeMinFractionalBig = fixedpoint.UFix64TypeMinFractionalBig
 	UFix64TypeMaxFractionalBig = fixedpoint.UFix64TypeMaxFractionalBig
+
+	Fix128FactorBig = fixedpoint.Fix128FactorBig
+
+	Fix128TypeMinBig = fixedpoint.Fix128TypeMinBig
+	Fix128TypeMaxBig = fixedpoint.Fix128TypeMaxBig
+
+	Fix128TypeMinIntBig = fixedpoint.Fix128TypeMinIntBig
+	Fix128TypeMaxIntBig = fixedpoint.Fix128TypeMaxIntBig
+
+	Fix128TypeMinFractionalBig = fixedpoint.Fix128TypeMinFractionalBig
+	Fix128TypeMaxFractionalBig = fixedpoint.Fix128TypeMaxFractionalBig
+
+	UFix128TypeMinBig = fixedpoint.UFix128TypeMinBig
+	UFix128TypeMaxBig = fixedpoint.UFix128TypeMaxBig
+
+	UFix128TypeMinIntBig = fixedpoint.UFix128TypeMinIntBig
+	UFix128TypeMaxIntBig = fixedpoint.UFix128TypeMaxIntBig
+
+	UFix128TypeMinFractionalBig = fixedpoint.UFix128TypeMinFractionalBig
+	UFix128TypeMaxFractionalBig = fixedpoint.UFix128TypeMaxFractionalBig
 )
 
 const Fix64Scale = fixedpoint.Fix64Scale
+const Fix128Scale = fixedpoint.Fix128Scale
 const Fix64Factor = fixedpoint.Fix64Factor
 
 const Fix64TypeMinInt = fixedpoint.Fix64TypeMinInt
@@ -1561,6 +1660,24 @@ Removes the last element from the array and returns it.
 The array must not be empty. If the array is empty, the program aborts
 `
 
+const arrayTypeForEachFunctionDocString = `
+Calls the given function for each element of the array, in order
+`
+
+const arrayTypeMapFunctionDocString = `
+Returns a new array containing the results of calling the given transform function
+for each element of the array, in order
+`
+
+const arrayTypeFilterFunctionDocString = `
+Returns a new array containing the elements of the array
+for which the given predicate function returns true, in order
+`
+
+const ArrayTypeForEachFunctionName = "forEach"
+const ArrayTypeMapFunctionName = "map"
+const ArrayTypeFilterFunctionName = "filter"
+
 func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 
 	members := map[string]MemberResolver{
@@ -1613,6 +1730,54 @@ func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 				)
 			},
 		},
+		ArrayTypeForEachFunctionName: {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				reportInvalidResourceArrayFunction(elementType, identifier, targetRange, report)
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					ArrayForEachFunctionType(elementType),
+					arrayTypeForEachFunctionDocString,
+				)
+			},
+		},
+		ArrayTypeMapFunctionName: {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				reportInvalidResourceArrayFunction(elementType, identifier, targetRange, report)
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					ArrayMapFunctionType(elementType),
+					arrayTypeMapFunctionDocString,
+				)
+			},
+		},
+		ArrayTypeFilterFunctionName: {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				reportInvalidResourceArrayFunction(elementType, identifier, targetRange, report)
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					ArrayFilterFunctionType(elementType),
+					arrayTypeFilterFunctionDocString,
+				)
+			},
+		},
 	}
 
 	// TODO: maybe still return members but report a helpful error?
@@ -1749,6 +1914,129 @@ func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 	return withBuiltinMembers(arrayType, members)
 }
 
+// reportInvalidResourceArrayFunction reports an error if the element type is a resource type.
+//
+// The higher-order functions pass the elements to the given function,
+// which is impossible for resources, as they would be moved out of the array
+//
+func reportInvalidResourceArrayFunction(
+	elementType Type,
+	identifier string,
+	targetRange ast.Range,
+	report func(error),
+) {
+	if !elementType.IsResourceType() {
+		return
+	}
+
+	report(
+		&InvalidResourceArrayMemberError{
+			Name:            identifier,
+			DeclarationKind: common.DeclarationKindFunction,
+			Range:           targetRange,
+		},
+	)
+}
+
+func ArrayForEachFunctionType(elementType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							VoidType,
+						),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			VoidType,
+		),
+	}
+}
+
+func ArrayMapFunctionType(elementType Type) *FunctionType {
+	typeParameter := &TypeParameter{
+		Name: "U",
+	}
+
+	resultType := &GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "transform",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							resultType,
+						),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&VariableSizedType{
+				Type: resultType,
+			},
+		),
+	}
+}
+
+func ArrayFilterFunctionType(elementType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "predicate",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							BoolType,
+						),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&VariableSizedType{
+				Type: elementType,
+			},
+		),
+	}
+}
+
 func ArrayRemoveLastFunctionType(elementType Type) *FunctionType {
 	return &FunctionType{
 		ReturnTypeAnnotation: NewTypeAnnotation(
@@ -1879,7 +2167,7 @@ func (t *VariableSizedType) QualifiedString() string {
 }
 
 func (t *VariableSizedType) ID() TypeID {
-	return TypeID(fmt.Sprintf("[%s]", t.Type.ID()))
+	return common.NewVariableSizedTypeID(t.Type.ID())
 }
 
 func (t *VariableSizedType) Equal(other Type) bool {
@@ -2009,7 +2297,7 @@ func (t *ConstantSizedType) QualifiedString() string {
 }
 
 func (t *ConstantSizedType) ID() TypeID {
-	return TypeID(fmt.Sprintf("[%s;%d]", t.Type.ID(), t.Size))
+	return common.NewConstantSizedTypeID(t.Type.ID(), t.Size)
 }
 
 func (t *ConstantSizedType) Equal(other Type) bool {
@@ -2150,6 +2438,9 @@ type Parameter struct {
 	Label          string
 	Identifier     string
 	TypeAnnotation *TypeAnnotation
+	// Indexed is true if the parameter of an event is marked as indexed.
+	// Indexed parameters are a hint for indexing emitted events, they do not affect the type
+	Indexed bool
 }
 
 func (p *Parameter) String() string {
@@ -2379,27 +2670,22 @@ func (t *FunctionType) QualifiedString() string {
 // NOTE: parameter names and argument labels are *not* part of the ID!
 func (t *FunctionType) ID() TypeID {
 
-	typeParameters := make([]string, len(t.TypeParameters))
+	typeParameters := make([]TypeID, len(t.TypeParameters))
 
 	for i, typeParameter := range t.TypeParameters {
-		typeParameters[i] = string(typeParameter.TypeBound.ID())
+		typeParameters[i] = typeParameter.TypeBound.ID()
 	}
 
-	parameters := make([]string, len(t.Parameters))
+	parameters := make([]TypeID, len(t.Parameters))
 
 	for i, parameter := range t.Parameters {
-		parameters[i] = string(parameter.TypeAnnotation.Type.ID())
+		parameters[i] = parameter.TypeAnnotation.Type.ID()
 	}
 
-	returnTypeAnnotation := string(t.ReturnTypeAnnotation.Type.ID())
-
-	return TypeID(
-		formatFunctionType(
-			false,
-			typeParameters,
-			parameters,
-			returnTypeAnnotation,
-		),
+	return common.NewFunctionTypeID(
+		typeParameters,
+		parameters,
+		t.ReturnTypeAnnotation.Type.ID(),
 	)
 }
 
@@ -2779,12 +3065,16 @@ func init() {
 		PrivatePathType,
 		PublicPathType,
 		&CapabilityType{},
+		&InclusiveRangeType{},
 		DeployedContractType,
 		BlockType,
+		TimestampType,
+		DurationType,
 		AccountKeyType,
 		PublicKeyType,
 		SignatureAlgorithmType,
 		HashAlgorithmType,
+		RoundingRuleType,
 	)
 
 	for _, ty := range types {
@@ -2828,10 +3118,12 @@ var BaseValueActivation = NewVariableActivation(nil)
 
 var AllSignedFixedPointTypes = []Type{
 	Fix64Type,
+	Fix128Type,
 }
 
 var AllUnsignedFixedPointTypes = []Type{
 	UFix64Type,
+	UFix128Type,
 }
 
 var AllFixedPointTypes = append(
@@ -2935,7 +3227,7 @@ func init() {
 
 			addMember := func(member *Member) {
 				if functionType.Members == nil {
-					functionType.Members = NewStringMemberOrderedMap()
+					functionType.Members = &StringMemberOrderedMap{}
 				}
 				name := member.Identifier.Identifier
 				_, exists := functionType.Members.Get(name)
@@ -3120,7 +3412,7 @@ func init() {
 
 	addMember := func(member *Member) {
 		if functionType.Members == nil {
-			functionType.Members = NewStringMemberOrderedMap()
+			functionType.Members = &StringMemberOrderedMap{}
 		}
 		name := member.Identifier.Identifier
 		_, exists := functionType.Members.Get(name)
@@ -3451,6 +3743,8 @@ func checkIdentifiersCached(_ string, typ Type) {
 		semaType.checkIdentifiersCached()
 	case *InterfaceType:
 		semaType.checkIdentifiersCached()
+	case *EntitlementType:
+		semaType.checkIdentifiersCached()
 	}
 }
 
@@ -3655,6 +3949,18 @@ func (t *CompositeType) TypeRequirements() []*CompositeType {
 	return typeRequirements
 }
 
+// IsTypeRequirement returns true if the composite type is declared
+// in an interface, i.e. it is a type requirement
+// which must be implemented by conforming composites.
+//
+// Type requirements can be used in type annotations,
+// but there are no values of the type requirement itself
+//
+func (t *CompositeType) IsTypeRequirement() bool {
+	_, ok := t.containerType.(*InterfaceType)
+	return ok
+}
+
 func (*CompositeType) Unify(_ Type, _ *TypeParameterTypeOrderedMap, _ func(err error), _ ast.Range) bool {
 	// TODO:
 	return false
@@ -3702,6 +4008,29 @@ func (t *CompositeType) initializeMemberResolvers() {
 				}
 			})
 
+		// User-defined structures and resources have a derived `toString` function,
+		// unless they declare a `toString` member themselves.
+		// Built-in composite types have no location
+
+		switch t.Kind {
+		case common.CompositeKindStructure,
+			common.CompositeKindResource:
+
+			if _, ok := members[ToStringFunctionName]; !ok && t.Location != nil {
+				members[ToStringFunctionName] = MemberResolver{
+					Kind: common.DeclarationKindFunction,
+					Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+						return NewPublicFunctionMember(
+							t,
+							identifier,
+							ToStringFunctionType,
+							derivedToStringFunctionDocString,
+						)
+					},
+				}
+			}
+		}
+
 		t.memberResolvers = withBuiltinMembers(t, members)
 	})
 }
@@ -3709,8 +4038,11 @@ func (t *CompositeType) initializeMemberResolvers() {
 // Member
 
 type Member struct {
-	ContainerType  Type
-	Access         ast.Access
+	ContainerType Type
+	Access        ast.Access
+	// Entitlements are the entitlements a reference must have
+	// to access the member through it
+	Entitlements   []*EntitlementType
 	Identifier     ast.Identifier
 	TypeAnnotation *TypeAnnotation
 	// TODO: replace with dedicated MemberKind enum
@@ -4104,11 +4436,10 @@ func (t *DictionaryType) QualifiedString() string {
 }
 
 func (t *DictionaryType) ID() TypeID {
-	return TypeID(fmt.Sprintf(
-		"{%s:%s}",
+	return common.NewDictionaryTypeID(
 		t.KeyType.ID(),
 		t.ValueType.ID(),
-	))
+	)
 }
 
 func (t *DictionaryType) Equal(other Type) bool {
@@ -4195,6 +4526,12 @@ const dictionaryTypeValuesFieldDocString = `
 An array containing all values of the dictionary
 `
 
+const dictionaryTypeKeysSortedFunctionDocString = `
+Returns an array containing all keys of the dictionary, sorted in ascending order.
+
+Numbers are sorted by value, strings and characters lexicographically, and addresses by their bytes
+`
+
 const dictionaryTypeInsertFunctionDocString = `
 Inserts the given value into the dictionary under the given key.
 
@@ -4207,6 +4544,27 @@ Removes the value for the given key from the dictionary.
 Returns the value as an optional if the dictionary contained the key, or nil if the dictionary did not contain the key
 `
 
+const dictionaryTypeForEachFunctionDocString = `
+Calls the given function for each key and value of the dictionary
+`
+
+const dictionaryTypeMapFunctionDocString = `
+Returns a new dictionary with the same keys as the dictionary,
+and values which are the results of calling the given transform function for each key and value
+`
+
+const dictionaryTypeFilterFunctionDocString = `
+Returns a new dictionary containing the entries of the dictionary
+for which the given predicate function returns true
+`
+
+const DictionaryTypeKeysFieldName = "keys"
+const DictionaryTypeValuesFieldName = "values"
+
+const DictionaryTypeForEachFunctionName = "forEach"
+const DictionaryTypeMapFunctionName = "map"
+const DictionaryTypeFilterFunctionName = "filter"
+
 func (t *DictionaryType) GetMembers() map[string]MemberResolver {
 	t.initializeMemberResolvers()
 	return t.memberResolvers
@@ -4239,7 +4597,7 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
-			"keys": {
+			DictionaryTypeKeysFieldName: {
 				Kind: common.DeclarationKindField,
 				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
 					// TODO: maybe allow for resource key type
@@ -4262,7 +4620,7 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
-			"values": {
+			DictionaryTypeValuesFieldName: {
 				Kind: common.DeclarationKindField,
 				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
 					// TODO: maybe allow for resource value type
@@ -4285,6 +4643,27 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
+			"keysSorted": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					if !IsSortableType(t.KeyType) {
+						report(
+							&NotSortableTypeError{
+								Type:  t.KeyType,
+								Range: targetRange,
+							},
+						)
+					}
+
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						DictionaryKeysSortedFunctionType(t),
+						dictionaryTypeKeysSortedFunctionDocString,
+					)
+				},
+			},
 			"insert": {
 				Kind: common.DeclarationKindFunction,
 				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
@@ -4305,10 +4684,160 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
+			DictionaryTypeForEachFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					t.reportInvalidResourceFunction(identifier, targetRange, report)
+
+					return NewPublicFunctionMember(t,
+						identifier,
+						DictionaryForEachFunctionType(t),
+						dictionaryTypeForEachFunctionDocString,
+					)
+				},
+			},
+			DictionaryTypeMapFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					t.reportInvalidResourceFunction(identifier, targetRange, report)
+
+					return NewPublicFunctionMember(t,
+						identifier,
+						DictionaryMapFunctionType(t),
+						dictionaryTypeMapFunctionDocString,
+					)
+				},
+			},
+			DictionaryTypeFilterFunctionName: {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					t.reportInvalidResourceFunction(identifier, targetRange, report)
+
+					return NewPublicFunctionMember(t,
+						identifier,
+						DictionaryFilterFunctionType(t),
+						dictionaryTypeFilterFunctionDocString,
+					)
+				},
+			},
 		})
 	})
 }
 
+// reportInvalidResourceFunction reports an error if the key or value type is a resource type.
+//
+// The higher-order functions pass the keys and values to the given function,
+// which is impossible for resources, as they would be moved out of the dictionary
+//
+func (t *DictionaryType) reportInvalidResourceFunction(
+	identifier string,
+	targetRange ast.Range,
+	report func(error),
+) {
+	if !t.IsResourceType() {
+		return
+	}
+
+	report(
+		&InvalidResourceDictionaryMemberError{
+			Name:            identifier,
+			DeclarationKind: common.DeclarationKindFunction,
+			Range:           targetRange,
+		},
+	)
+}
+
+// dictionaryEntryFunctionType returns the type of a function
+// which is called with a key and value of the given dictionary type
+//
+func dictionaryEntryFunctionType(t *DictionaryType, returnType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:          ArgumentLabelNotRequired,
+				Identifier:     "key",
+				TypeAnnotation: NewTypeAnnotation(t.KeyType),
+			},
+			{
+				Label:          ArgumentLabelNotRequired,
+				Identifier:     "value",
+				TypeAnnotation: NewTypeAnnotation(t.ValueType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			returnType,
+		),
+	}
+}
+
+func DictionaryForEachFunctionType(t *DictionaryType) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					dictionaryEntryFunctionType(t, VoidType),
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			VoidType,
+		),
+	}
+}
+
+func DictionaryMapFunctionType(t *DictionaryType) *FunctionType {
+	typeParameter := &TypeParameter{
+		Name: "U",
+	}
+
+	resultType := &GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "transform",
+				TypeAnnotation: NewTypeAnnotation(
+					dictionaryEntryFunctionType(t, resultType),
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&DictionaryType{
+				KeyType:   t.KeyType,
+				ValueType: resultType,
+			},
+		),
+	}
+}
+
+func DictionaryFilterFunctionType(t *DictionaryType) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "predicate",
+				TypeAnnotation: NewTypeAnnotation(
+					dictionaryEntryFunctionType(t, BoolType),
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			t,
+		),
+	}
+}
+
 func DictionaryContainsKeyFunctionType(t *DictionaryType) *FunctionType {
 	return &FunctionType{
 		Parameters: []*Parameter{
@@ -4324,6 +4853,41 @@ func DictionaryContainsKeyFunctionType(t *DictionaryType) *FunctionType {
 	}
 }
 
+func DictionaryKeysSortedFunctionType(t *DictionaryType) *FunctionType {
+	return &FunctionType{
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&VariableSizedType{
+				Type: t.KeyType,
+			},
+		),
+	}
+}
+
+// IsSortableType returns true if values of the given type have a total order,
+// i.e. they can be sorted, e.g. using the dictionary function `keysSorted`.
+//
+// Abstract number types are not sortable, as their values may have different concrete types.
+//
+func IsSortableType(ty Type) bool {
+	switch ty {
+	case StringType, CharacterType:
+		return true
+
+	case NumberType, SignedNumberType,
+		IntegerType, SignedIntegerType,
+		FixedPointType, SignedFixedPointType,
+		NeverType:
+
+		return false
+	}
+
+	if _, ok := ty.(*AddressType); ok {
+		return true
+	}
+
+	return IsSubType(ty, NumberType)
+}
+
 func DictionaryInsertFunctionType(t *DictionaryType) *FunctionType {
 	return &FunctionType{
 		Parameters: []*Parameter{
@@ -4416,10 +4980,16 @@ func (t *DictionaryType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Typ
 	}
 }
 
-// ReferenceType represents the reference to a value
+// ReferenceType represents the reference to a value.
+//
+// An authorized reference (`auth &T`) grants full access, including downcasting.
+// An entitled reference (`auth(E1, E2) &T`) is not authorized,
+// but grants access to members which require (a subset of) its entitlements.
+//
 type ReferenceType struct {
-	Authorized bool
-	Type       Type
+	Authorized   bool
+	Entitlements []*EntitlementType
+	Type         Type
 }
 
 func (*ReferenceType) IsType() {}
@@ -4435,6 +5005,14 @@ func (t *ReferenceType) string(typeFormatter func(Type) string) string {
 	var builder strings.Builder
 	if t.Authorized {
 		builder.WriteString("auth ")
+	} else if len(t.Entitlements) > 0 {
+		builder.WriteString("auth(")
+		builder.WriteString(
+			formatEntitlementSet(t.Entitlements, func(entitlement *EntitlementType) string {
+				return typeFormatter(entitlement)
+			}),
+		)
+		builder.WriteString(") ")
 	}
 	builder.WriteRune('&')
 	builder.WriteString(typeFormatter(t.Type))
@@ -4454,10 +5032,19 @@ func (t *ReferenceType) QualifiedString() string {
 }
 
 func (t *ReferenceType) ID() TypeID {
-	return TypeID(
-		t.string(func(ty Type) string {
-			return string(ty.ID())
-		}),
+	if t.Type == nil {
+		return "reference"
+	}
+
+	entitlements := make([]TypeID, len(t.Entitlements))
+	for i, entitlement := range t.Entitlements {
+		entitlements[i] = entitlement.ID()
+	}
+
+	return common.NewReferenceTypeID(
+		t.Authorized,
+		entitlements,
+		t.Type.ID(),
 	)
 }
 
@@ -4471,9 +5058,21 @@ func (t *ReferenceType) Equal(other Type) bool {
 		return false
 	}
 
+	if !EntitlementSetsEqual(t.Entitlements, otherReference.Entitlements) {
+		return false
+	}
+
 	return t.Type.Equal(otherReference.Type)
 }
 
+// HasEntitlements returns true if the reference grants all of the given entitlements,
+// i.e. the reference is authorized, or it is entitled to all given entitlements.
+//
+func (t *ReferenceType) HasEntitlements(entitlements []*EntitlementType) bool {
+	return t.Authorized ||
+		entitlementSetIncludes(t.Entitlements, entitlements)
+}
+
 func (t *ReferenceType) IsResourceType() bool {
 	return false
 }
@@ -4506,8 +5105,9 @@ func (t *ReferenceType) RewriteWithRestrictedTypes() (Type, bool) {
 	rewrittenType, rewritten := t.Type.RewriteWithRestrictedTypes()
 	if rewritten {
 		return &ReferenceType{
-			Authorized: t.Authorized,
-			Type:       rewrittenType,
+			Authorized:   t.Authorized,
+			Entitlements: t.Entitlements,
+			Type:         rewrittenType,
 		}, true
 	} else {
 		return t, false
@@ -4796,7 +5396,7 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 	case FixedPointType:
 		switch subType {
 		case FixedPointType, SignedFixedPointType,
-			UFix64Type:
+			UFix64Type, UFix128Type:
 
 			return true
 
@@ -4806,7 +5406,7 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 
 	case SignedFixedPointType:
 		switch subType {
-		case SignedFixedPointType, Fix64Type:
+		case SignedFixedPointType, Fix64Type, Fix128Type:
 			return true
 
 		default:
@@ -4884,6 +5484,28 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 			return false
 		}
 
+		// An entitled reference type `auth(Es) &T` is only a subtype
+		// of an entitled reference type `auth(Fs) &U` if `Es` is a superset of `Fs`.
+		//
+		// Again, the holder of the reference may not gain more entitlements.
+
+		if !typedSubType.HasEntitlements(typedSuperType.Entitlements) {
+			return false
+		}
+
+		// Any additional entitlements of the subtype may be dropped,
+		// the remaining rules are independent of entitlements
+
+		if !EntitlementSetsEqual(typedSubType.Entitlements, typedSuperType.Entitlements) {
+			return IsSubType(
+				&ReferenceType{
+					Entitlements: typedSuperType.Entitlements,
+					Type:         typedSubType.Type,
+				},
+				typedSuperType,
+			)
+		}
+
 		switch typedInnerSuperType := typedSuperType.Type.(type) {
 		case *RestrictedType:
 
@@ -5385,6 +6007,25 @@ func (t *TransactionType) EntryPointFunctionType() *FunctionType {
 	}
 }
 
+// AuthorizerCount returns the number of authorizers (signing accounts)
+// required by the prepare function of the transaction.
+//
+// Each parameter of type `AuthAccount` requires one authorizer,
+// and each parameter of a constant-sized array type `[AuthAccount; N]`
+// requires N authorizers
+//
+func (t *TransactionType) AuthorizerCount() int {
+	count := 0
+	for _, parameter := range t.PrepareParameters {
+		if arrayType, ok := parameter.TypeAnnotation.Type.(*ConstantSizedType); ok {
+			count += int(arrayType.Size)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
 func (t *TransactionType) PrepareFunctionType() *FunctionType {
 	return &FunctionType{
 		IsConstructor:        true,
@@ -5544,11 +6185,12 @@ func (t *RestrictedType) QualifiedString() string {
 }
 
 func (t *RestrictedType) ID() TypeID {
-	return TypeID(
-		t.string("", func(ty Type) string {
-			return string(ty.ID())
-		}),
-	)
+	restrictions := make([]TypeID, len(t.Restrictions))
+	for i, restriction := range t.Restrictions {
+		restrictions[i] = restriction.ID()
+	}
+
+	return common.NewRestrictedTypeID(t.Type.ID(), restrictions)
 }
 
 func (t *RestrictedType) Equal(other Type) bool {
@@ -5750,9 +6392,11 @@ func (t *CapabilityType) QualifiedString() string {
 }
 
 func (t *CapabilityType) ID() TypeID {
-	return TypeID(t.string(func(t Type) string {
-		return string(t.ID())
-	}))
+	var borrowType TypeID
+	if t.BorrowType != nil {
+		borrowType = t.BorrowType.ID()
+	}
+	return common.NewCapabilityTypeID(borrowType)
 }
 
 func (t *CapabilityType) Equal(other Type) bool {
@@ -5990,6 +6634,7 @@ func init() {
 		PublicKeyType,
 		HashAlgorithmType,
 		SignatureAlgorithmType,
+		RoundingRuleType,
 		AuthAccountType,
 		AuthAccountKeysType,
 		AuthAccountContractsType,
@@ -6183,14 +6828,19 @@ var PublicKeyVerifyPoPFunctionType = &FunctionType{
 	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
 }
 
-type CryptoAlgorithm interface {
+// NativeEnumCase is a case of a native enum type,
+// e.g. a hash algorithm or a rounding rule
+//
+type NativeEnumCase interface {
 	RawValue() uint8
 	Name() string
 	DocString() string
 }
 
+type CryptoAlgorithm = NativeEnumCase
+
 func GetMembersAsMap(members []*Member) *StringMemberOrderedMap {
-	membersMap := NewStringMemberOrderedMap()
+	membersMap := &StringMemberOrderedMap{}
 	for _, member := range members {
 		name := member.Identifier.Identifier
 		_, ok := membersMap.Get(name)
@@ -0,0 +1,165 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+// TypeCache canonicalizes types and memoizes the results of subtype checks.
+//
+// Canonicalization (hash-consing) maps equal types to one canonical instance,
+// so equality of canonical types is pointer comparison,
+// and the results of subtype checks can be memoized by canonical type pair.
+//
+// A cache may be shared by the checkers of a session, e.g. the checkers of imported programs.
+// It must only be used after the involved types are fully declared,
+// as e.g. the conformances of composite types are determined during their declaration.
+//
+type TypeCache struct {
+	// canonicalTypes maps all cacheable types seen so far to their canonical type
+	canonicalTypes map[Type]Type
+	// canonicalTypesByID groups the canonical types by type ID.
+	// Types with the same ID are not necessarily equal,
+	// e.g. a structure and a resource type which are declared with the same name
+	canonicalTypesByID map[TypeID][]Type
+	subTypeResults     map[typePair]bool
+}
+
+type typePair struct {
+	subType   Type
+	superType Type
+}
+
+func NewTypeCache() *TypeCache {
+	return &TypeCache{
+		canonicalTypes:     map[Type]Type{},
+		canonicalTypesByID: map[TypeID][]Type{},
+		subTypeResults:     map[typePair]bool{},
+	}
+}
+
+// Canonicalize returns the canonical type for the given type.
+//
+// Types which are not cacheable, e.g. types which contain type parameters
+// or function types, are returned as-is.
+//
+func (c *TypeCache) Canonicalize(ty Type) Type {
+	if !isCacheableType(ty) {
+		return ty
+	}
+
+	return c.canonicalize(ty)
+}
+
+func (c *TypeCache) canonicalize(ty Type) Type {
+	if canonicalType, ok := c.canonicalTypes[ty]; ok {
+		return canonicalType
+	}
+
+	id := ty.ID()
+
+	for _, canonicalType := range c.canonicalTypesByID[id] {
+		if canonicalType.Equal(ty) {
+			c.canonicalTypes[ty] = canonicalType
+			return canonicalType
+		}
+	}
+
+	c.canonicalTypesByID[id] = append(c.canonicalTypesByID[id], ty)
+	c.canonicalTypes[ty] = ty
+
+	return ty
+}
+
+// IsSubType returns true if the given subtype is a subtype of the given supertype,
+// just like the function IsSubType, but memoizes the result for cacheable types.
+//
+func (c *TypeCache) IsSubType(subType Type, superType Type) bool {
+
+	if subType == nil || superType == nil ||
+		!isCacheableType(subType) ||
+		!isCacheableType(superType) {
+
+		return IsSubType(subType, superType)
+	}
+
+	key := typePair{
+		subType:   c.canonicalize(subType),
+		superType: c.canonicalize(superType),
+	}
+
+	if key.subType == key.superType {
+		return true
+	}
+
+	if result, ok := c.subTypeResults[key]; ok {
+		return result
+	}
+
+	result := IsSubType(key.subType, key.superType)
+	c.subTypeResults[key] = result
+	return result
+}
+
+// isCacheableType returns true if the given type can be canonicalized,
+// i.e. if the type is valid, and if its equality and subtyping are fully
+// determined by its structure and the identity of the nominal types it refers to.
+//
+// Function types and type parameters are not cacheable,
+// e.g. the subtyping of function types depends on argument labels and type parameter bounds
+//
+func isCacheableType(ty Type) bool {
+	switch ty := ty.(type) {
+	case *SimpleType,
+		*NumericType,
+		*FixedPointNumericType,
+		*AddressType,
+		*CompositeType,
+		*InterfaceType:
+
+		return !ty.IsInvalidType()
+
+	case *OptionalType:
+		return isCacheableType(ty.Type)
+
+	case *VariableSizedType:
+		return isCacheableType(ty.Type)
+
+	case *ConstantSizedType:
+		return isCacheableType(ty.Type)
+
+	case *DictionaryType:
+		return isCacheableType(ty.KeyType) &&
+			isCacheableType(ty.ValueType)
+
+	case *ReferenceType:
+		return isCacheableType(ty.Type)
+
+	case *RestrictedType:
+		return isCacheableType(ty.Type)
+
+	case *CapabilityType:
+		return ty.BorrowType == nil ||
+			isCacheableType(ty.BorrowType)
+
+	case *InclusiveRangeType:
+		return ty.MemberType == nil ||
+			isCacheableType(ty.MemberType)
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestTypeCache_Canonicalize(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("equal types", func(t *testing.T) {
+
+		t.Parallel()
+
+		cache := NewTypeCache()
+
+		first := &OptionalType{
+			Type: &VariableSizedType{Type: IntType},
+		}
+		second := &OptionalType{
+			Type: &VariableSizedType{Type: IntType},
+		}
+
+		assert.Same(t, first, cache.Canonicalize(first))
+		assert.Same(t, first, cache.Canonicalize(second))
+	})
+
+	t.Run("composite types with same ID", func(t *testing.T) {
+
+		t.Parallel()
+
+		cache := NewTypeCache()
+
+		newCompositeType := func(kind common.CompositeKind) *CompositeType {
+			return &CompositeType{
+				Location:   common.StringLocation("test"),
+				Identifier: "S",
+				Kind:       kind,
+			}
+		}
+
+		first := &OptionalType{Type: newCompositeType(common.CompositeKindStructure)}
+		second := &OptionalType{Type: newCompositeType(common.CompositeKindResource)}
+
+		assert.Equal(t, first.ID(), second.ID())
+
+		assert.Same(t, first, cache.Canonicalize(first))
+		assert.Same(t, second, cache.Canonicalize(second))
+	})
+
+	t.Run("function type", func(t *testing.T) {
+
+		t.Parallel()
+
+		cache := NewTypeCache()
+
+		first := &FunctionType{
+			ReturnTypeAnnotation: NewTypeAnnotation(IntType),
+		}
+		second := &FunctionType{
+			ReturnTypeAnnotation: NewTypeAnnotation(IntType),
+		}
+
+		assert.Same(t, first, cache.Canonicalize(first))
+		assert.Same(t, second, cache.Canonicalize(second))
+	})
+}
+
+func TestTypeCache_IsSubType(t *testing.T) {
+
+	t.Parallel()
+
+	cache := NewTypeCache()
+
+	interfaceType := &InterfaceType{
+		Location:      common.StringLocation("test"),
+		Identifier:    "I",
+		CompositeKind: common.CompositeKindResource,
+	}
+
+	compositeType := &CompositeType{
+		Location:                      common.StringLocation("test"),
+		Identifier:                    "R",
+		Kind:                          common.CompositeKindResource,
+		ExplicitInterfaceConformances: []*InterfaceType{interfaceType},
+	}
+
+	newReferenceType := func(ty Type) *ReferenceType {
+		return &ReferenceType{
+			Type: ty,
+		}
+	}
+
+	newRestrictedType := func() *RestrictedType {
+		return &RestrictedType{
+			Type:         AnyResourceType,
+			Restrictions: []*InterfaceType{interfaceType},
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+
+		assert.True(t,
+			cache.IsSubType(
+				newReferenceType(compositeType),
+				newReferenceType(newRestrictedType()),
+			),
+		)
+
+		assert.False(t,
+			cache.IsSubType(
+				newReferenceType(newRestrictedType()),
+				newReferenceType(compositeType),
+			),
+		)
+	}
+
+	assert.Len(t, cache.subTypeResults, 2)
+}
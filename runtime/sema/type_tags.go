@@ -172,14 +172,14 @@ const (
 	_ // future: Fix16
 	_ // future: Fix32
 	fix64TypeMask
-	_ // future: Fix128
+	fix128TypeMask
 	_ // future: Fix256
 
 	_ // future: UFix8
 	_ // future: UFix16
 	_ // future: UFix32
 	ufix64TypeMask
-	_ // future: UFix128
+	ufix128TypeMask
 	_ // future: UFix256
 
 	stringTypeMask
@@ -219,6 +219,10 @@ const (
 	capabilityTypeMask uint64 = 1 << iota
 	restrictedTypeMask
 	transactionTypeMask
+	entitlementTypeMask
+	inclusiveRangeTypeMask
+	timestampTypeMask
+	durationTypeMask
 
 	invalidTypeMask
 )
@@ -254,10 +258,12 @@ var (
 			Or(UnsignedIntegerTypeTag)
 
 	SignedFixedPointTypeTag = newTypeTagFromLowerMask(signedFixedPointTypeMask).
-				Or(Fix64TypeTag)
+				Or(Fix64TypeTag).
+				Or(Fix128TypeTag)
 
 	UnsignedFixedPointTypeTag = newTypeTagFromLowerMask(unsignedFixedPointTypeMask).
-					Or(UFix64TypeTag)
+					Or(UFix64TypeTag).
+					Or(UFix128TypeTag)
 
 	FixedPointTypeTag = newTypeTagFromLowerMask(fixedPointTypeMask).
 				Or(SignedFixedPointTypeTag).
@@ -293,8 +299,10 @@ var (
 	Word32TypeTag = newTypeTagFromLowerMask(word32TypeMask)
 	Word64TypeTag = newTypeTagFromLowerMask(word64TypeMask)
 
-	Fix64TypeTag  = newTypeTagFromLowerMask(fix64TypeMask)
-	UFix64TypeTag = newTypeTagFromLowerMask(ufix64TypeMask)
+	Fix64TypeTag   = newTypeTagFromLowerMask(fix64TypeMask)
+	Fix128TypeTag  = newTypeTagFromLowerMask(fix128TypeMask)
+	UFix64TypeTag  = newTypeTagFromLowerMask(ufix64TypeMask)
+	UFix128TypeTag = newTypeTagFromLowerMask(ufix128TypeMask)
 
 	StringTypeTag           = newTypeTagFromLowerMask(stringTypeMask)
 	CharacterTypeTag        = newTypeTagFromLowerMask(characterTypeMask)
@@ -332,6 +340,12 @@ var (
 	CapabilityTypeTag  = newTypeTagFromUpperMask(capabilityTypeMask)
 	InvalidTypeTag     = newTypeTagFromUpperMask(invalidTypeMask)
 	TransactionTypeTag = newTypeTagFromUpperMask(transactionTypeMask)
+	EntitlementTypeTag = newTypeTagFromUpperMask(entitlementTypeMask)
+
+	InclusiveRangeTypeTag = newTypeTagFromUpperMask(inclusiveRangeTypeMask)
+
+	TimestampTypeTag = newTypeTagFromUpperMask(timestampTypeMask)
+	DurationTypeTag  = newTypeTagFromUpperMask(durationTypeMask)
 
 	// AnyStructTypeTag only includes the types that are pre-known
 	// to belong to AnyStruct type. This is more of an optimization.
@@ -353,6 +367,9 @@ var (
 				Or(BlockTypeTag).
 				Or(DeployedContractTypeTag).
 				Or(CapabilityTypeTag).
+				Or(InclusiveRangeTypeTag).
+				Or(TimestampTypeTag).
+				Or(DurationTypeTag).
 				Or(FunctionTypeTag)
 
 	AnyResourceTypeTag = newTypeTagFromLowerMask(anyResourceTypeMask)
@@ -366,6 +383,7 @@ var (
 			Or(GenericTypeTag).
 			Or(InterfaceTypeTag).
 			Or(TransactionTypeTag).
+			Or(EntitlementTypeTag).
 			Or(RestrictedTypeTag)
 )
 
@@ -421,7 +439,7 @@ func findCommonSuperType(joinedTypeTag TypeTag, types ...Type) Type {
 	if joinedTypeTag.ContainsAny(NilTypeTag) {
 		// Get the type without the optional flag
 		innerTypeTag := joinedTypeTag.And(notNilType)
-		superType := findCommonSuperType(innerTypeTag, types...)
+		superType := findCommonSuperType(innerTypeTag, unwrapOptionalTypes(types)...)
 
 		// If the common supertype of the rest of types contain nil,
 		// then do not wrap with optional again.
@@ -523,8 +541,12 @@ func findSuperTypeFromLowerMask(joinedTypeTag TypeTag, types []Type) Type {
 
 	case fix64TypeMask:
 		return Fix64Type
+	case fix128TypeMask:
+		return Fix128Type
 	case ufix64TypeMask:
 		return UFix64Type
+	case ufix128TypeMask:
+		return UFix128Type
 
 	case stringTypeMask:
 		return StringType
@@ -612,10 +634,17 @@ func findSuperTypeFromUpperMask(joinedTypeTag TypeTag, types []Type) Type {
 	case invalidTypeMask:
 		return InvalidType
 
+	case timestampTypeMask:
+		return TimestampType
+	case durationTypeMask:
+		return DurationType
+
 	// All derived types goes here.
 	case capabilityTypeMask,
 		restrictedTypeMask,
-		transactionTypeMask:
+		transactionTypeMask,
+		entitlementTypeMask,
+		inclusiveRangeTypeMask:
 		return getSuperTypeOfDerivedTypes(types)
 	default:
 		return nil
@@ -641,6 +670,10 @@ func getSuperTypeOfDerivedTypes(types []Type) Type {
 		}
 
 		if !typ.Equal(prevType) {
+			if _, ok := prevType.(*ReferenceType); ok {
+				return commonSuperTypeOfReferences(types)
+			}
+
 			return commonSuperTypeOfHeterogeneousTypes(types)
 		}
 	}
@@ -652,6 +685,79 @@ func getSuperTypeOfDerivedTypes(types []Type) Type {
 	return prevType
 }
 
+// unwrapOptionalTypes returns the inner types of the given types,
+// i.e. the types with one level of optionality removed.
+// For example, `Int?` becomes `Int`, and `Never?` (the type of `nil`) becomes `Never`.
+//
+func unwrapOptionalTypes(types []Type) []Type {
+	result := make([]Type, len(types))
+	for i, typ := range types {
+		if optionalType, ok := typ.(*OptionalType); ok {
+			typ = optionalType.Type
+		}
+		result[i] = typ
+	}
+	return result
+}
+
+// commonSuperTypeOfReferences returns the common supertype of the given reference types.
+//
+// References are covariant in the referenced type,
+// so the common supertype is a reference to the common supertype of the referenced types.
+// The resulting reference is only authorized if all references are authorized,
+// and it is only entitled to the entitlements which all references are entitled to.
+//
+// If the referenced types have no common supertype,
+// e.g. when they are a mix of structs and resources,
+// then the common supertype is the one of heterogeneous types.
+//
+func commonSuperTypeOfReferences(types []Type) Type {
+	authorized := true
+
+	// NOTE: nil means all entitlements, i.e. only authorized references were seen so far
+	var entitlements []*EntitlementType
+
+	referencedTypes := make([]Type, 0, len(types))
+
+	for _, typ := range types {
+		// Ignore 'Never' type as it doesn't affect the supertype.
+		if typ == NeverType {
+			continue
+		}
+
+		referenceType, ok := typ.(*ReferenceType)
+		if !ok {
+			return commonSuperTypeOfHeterogeneousTypes(types)
+		}
+
+		if !referenceType.Authorized {
+			if authorized {
+				entitlements = referenceType.Entitlements
+			} else {
+				entitlements = entitlementSetIntersection(entitlements, referenceType.Entitlements)
+			}
+		}
+
+		authorized = authorized && referenceType.Authorized
+		referencedTypes = append(referencedTypes, referenceType.Type)
+	}
+
+	if authorized {
+		entitlements = nil
+	}
+
+	referencedSuperType := LeastCommonSuperType(referencedTypes...)
+	if referencedSuperType.IsInvalidType() {
+		return commonSuperTypeOfHeterogeneousTypes(types)
+	}
+
+	return &ReferenceType{
+		Authorized:   authorized,
+		Entitlements: entitlements,
+		Type:         referencedSuperType,
+	}
+}
+
 func commonSuperTypeOfHeterogeneousTypes(types []Type) Type {
 	var hasStructs, hasResources bool
 	for _, typ := range types {
@@ -436,7 +436,7 @@ func TestRestrictedType_GetMember(t *testing.T) {
 			Identifier: "R",
 			Location:   common.StringLocation("a"),
 			Fields:     []string{},
-			Members:    NewStringMemberOrderedMap(),
+			Members:    &StringMemberOrderedMap{},
 		}
 		ty := &RestrictedType{
 			Type:         resourceType,
@@ -471,7 +471,7 @@ func TestRestrictedType_GetMember(t *testing.T) {
 		interfaceType := &InterfaceType{
 			CompositeKind: common.CompositeKindResource,
 			Identifier:    "I",
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		resourceType := &CompositeType{
@@ -479,7 +479,7 @@ func TestRestrictedType_GetMember(t *testing.T) {
 			Identifier: "R",
 			Location:   common.StringLocation("a"),
 			Fields:     []string{},
-			Members:    NewStringMemberOrderedMap(),
+			Members:    &StringMemberOrderedMap{},
 		}
 		restrictedType := &RestrictedType{
 			Type: resourceType,
@@ -541,7 +541,7 @@ func TestQualifiedIdentifierCreation(t *testing.T) {
 			Identifier: "A",
 			Location:   common.StringLocation("a"),
 			Fields:     []string{},
-			Members:    NewStringMemberOrderedMap(),
+			Members:    &StringMemberOrderedMap{},
 		}
 
 		b := &CompositeType{
@@ -549,7 +549,7 @@ func TestQualifiedIdentifierCreation(t *testing.T) {
 			Identifier:    "B",
 			Location:      common.StringLocation("a"),
 			Fields:        []string{},
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 			containerType: a,
 		}
 
@@ -558,7 +558,7 @@ func TestQualifiedIdentifierCreation(t *testing.T) {
 			Identifier:    "C",
 			Location:      common.StringLocation("a"),
 			Fields:        []string{},
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 			containerType: b,
 		}
 
@@ -584,7 +584,7 @@ func BenchmarkQualifiedIdentifierCreation(b *testing.B) {
 		Identifier: "foo",
 		Location:   common.StringLocation("a"),
 		Fields:     []string{},
-		Members:    NewStringMemberOrderedMap(),
+		Members:    &StringMemberOrderedMap{},
 	}
 
 	bar := &CompositeType{
@@ -592,7 +592,7 @@ func BenchmarkQualifiedIdentifierCreation(b *testing.B) {
 		Identifier:    "bar",
 		Location:      common.StringLocation("a"),
 		Fields:        []string{},
-		Members:       NewStringMemberOrderedMap(),
+		Members:       &StringMemberOrderedMap{},
 		containerType: foo,
 	}
 
@@ -921,21 +921,21 @@ func TestCommonSuperType(t *testing.T) {
 			Location:      testLocation,
 			Identifier:    "I1",
 			CompositeKind: common.CompositeKindStructure,
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		interfaceType2 := &InterfaceType{
 			Location:      testLocation,
 			Identifier:    "I2",
 			CompositeKind: common.CompositeKindStructure,
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		interfaceType3 := &InterfaceType{
 			Location:      testLocation,
 			Identifier:    "I3",
 			CompositeKind: common.CompositeKindStructure,
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		newCompositeWithInterfaces := func(name string, interfaces ...*InterfaceType) *CompositeType {
@@ -944,7 +944,7 @@ func TestCommonSuperType(t *testing.T) {
 				Identifier:                    name,
 				Kind:                          common.CompositeKindStructure,
 				ExplicitInterfaceConformances: interfaces,
-				Members:                       NewStringMemberOrderedMap(),
+				Members:                       &StringMemberOrderedMap{},
 			}
 		}
 
@@ -1301,7 +1301,87 @@ func TestCommonSuperType(t *testing.T) {
 						Type: StringType,
 					},
 				},
-				expectedSuperType: AnyStructType,
+				expectedSuperType: &ReferenceType{
+					Type: AnyStructType,
+				},
+			},
+			{
+				name: "references to subtypes",
+				types: []Type{
+					&ReferenceType{
+						Type: Int8Type,
+					},
+					&ReferenceType{
+						Type: Int16Type,
+					},
+				},
+				expectedSuperType: &ReferenceType{
+					Type: SignedIntegerType,
+				},
+			},
+			{
+				name: "authorized references",
+				types: []Type{
+					&ReferenceType{
+						Authorized: true,
+						Type:       Int8Type,
+					},
+					&ReferenceType{
+						Authorized: true,
+						Type:       Int16Type,
+					},
+				},
+				expectedSuperType: &ReferenceType{
+					Authorized: true,
+					Type:       SignedIntegerType,
+				},
+			},
+			{
+				name: "authorized & unauthorized references",
+				types: []Type{
+					&ReferenceType{
+						Authorized: true,
+						Type:       Int8Type,
+					},
+					&ReferenceType{
+						Type: Int8Type,
+					},
+				},
+				expectedSuperType: &ReferenceType{
+					Type: Int8Type,
+				},
+			},
+			{
+				name: "references & nil",
+				types: []Type{
+					&ReferenceType{
+						Type: Int8Type,
+					},
+					NilType,
+				},
+				expectedSuperType: &OptionalType{
+					Type: &ReferenceType{
+						Type: Int8Type,
+					},
+				},
+			},
+			{
+				name: "optional references & references",
+				types: []Type{
+					&OptionalType{
+						Type: &ReferenceType{
+							Type: Int8Type,
+						},
+					},
+					&ReferenceType{
+						Type: Int16Type,
+					},
+				},
+				expectedSuperType: &OptionalType{
+					Type: &ReferenceType{
+						Type: SignedIntegerType,
+					},
+				},
 			},
 			{
 				name: "references & non-references",
@@ -1383,7 +1463,7 @@ func TestCommonSuperType(t *testing.T) {
 			Location:      testLocation,
 			Identifier:    "I1",
 			CompositeKind: common.CompositeKindStructure,
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		restrictedType1 := &RestrictedType{
@@ -1427,7 +1507,7 @@ func TestCommonSuperType(t *testing.T) {
 			Location:      testLocation,
 			Identifier:    "I1",
 			CompositeKind: common.CompositeKindStructure,
-			Members:       NewStringMemberOrderedMap(),
+			Members:       &StringMemberOrderedMap{},
 		}
 
 		restrictedType1 := &RestrictedType{
@@ -1472,7 +1552,7 @@ func TestCommonSuperType(t *testing.T) {
 				},
 			},
 			ReturnTypeAnnotation: NewTypeAnnotation(Int8Type),
-			Members:              NewStringMemberOrderedMap(),
+			Members:              &StringMemberOrderedMap{},
 		}
 
 		funcType2 := &FunctionType{
@@ -1482,7 +1562,7 @@ func TestCommonSuperType(t *testing.T) {
 				},
 			},
 			ReturnTypeAnnotation: NewTypeAnnotation(Int8Type),
-			Members:              NewStringMemberOrderedMap(),
+			Members:              &StringMemberOrderedMap{},
 		}
 
 		tests := []testCase{
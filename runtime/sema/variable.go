@@ -43,4 +43,7 @@ type Variable struct {
 	Pos *ast.Position
 	// DocString is the optional docstring
 	DocString string
+	// referencedResourceVariables are the resource-typed variables
+	// which the reference stored in this variable refers to, if any
+	referencedResourceVariables []*Variable
 }
@@ -0,0 +1,159 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sync"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// SharedState is a decoded, immutable view of the account state,
+// which can be shared by concurrent read-only executions, e.g. the scripts served by an access node,
+// so that stored values are only loaded and decoded once, see Context.SharedState.
+//
+// Stored values are loaded lazily from the given ledger, which must be a snapshot of the state,
+// i.e. it must not change while the shared state is used.
+// Each loaded value is kept as a read-only value, see interpreter.ReadOnlyValue.
+//
+// Executions using the shared state cannot write stored values,
+// and the values read from the shared state have no owner.
+//
+type SharedState struct {
+	// loadLock serializes the loading of values, as the storage is not safe for concurrent use
+	loadLock sync.Mutex
+	storage  *Storage
+	lock     sync.RWMutex
+	// values are the loaded values, nil if no value is stored
+	values map[sharedStateKey]*interpreter.ReadOnlyValue
+}
+
+type sharedStateKey struct {
+	address common.Address
+	key     common.StorageKey
+}
+
+func NewSharedState(ledger atree.Ledger) *SharedState {
+	return &SharedState{
+		storage: NewStorage(ledger),
+		values:  map[sharedStateKey]*interpreter.ReadOnlyValue{},
+	}
+}
+
+// ReadStored returns the read-only view of the value stored at the given path,
+// or nil if no value is stored
+//
+func (s *SharedState) ReadStored(address common.Address, key common.StorageKey) (*interpreter.ReadOnlyValue, error) {
+	stateKey := sharedStateKey{
+		address: address,
+		key:     key,
+	}
+
+	s.lock.RLock()
+	value, ok := s.values[stateKey]
+	s.lock.RUnlock()
+
+	if ok {
+		return value, nil
+	}
+
+	s.loadLock.Lock()
+	defer s.loadLock.Unlock()
+
+	// The value might have been loaded concurrently
+
+	s.lock.RLock()
+	value, ok = s.values[stateKey]
+	s.lock.RUnlock()
+
+	if ok {
+		return value, nil
+	}
+
+	value, err := s.load(address, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	s.values[stateKey] = value
+	s.lock.Unlock()
+
+	return value, nil
+}
+
+// Len returns the number of loaded values
+//
+func (s *SharedState) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.values)
+}
+
+func (s *SharedState) load(address common.Address, key common.StorageKey) (
+	value *interpreter.ReadOnlyValue,
+	err error,
+) {
+	// Loading values panics on errors
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = interpreter.ExternalError{Recovered: r}
+			}
+		}
+	}()
+
+	// NOTE: only get the storage map if it exists,
+	// getting a non-existing storage map creates it, which writes to the ledger
+
+	var data []byte
+	wrapPanic(func() {
+		data, err = s.storage.Ledger.GetValue(address[:], []byte(key.Domain))
+	})
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	storedValue := s.storage.GetStorageMap(address, key.Domain, true).ReadValue(key.Identifier)
+	if storedValue == nil {
+		return nil, nil
+	}
+
+	return interpreter.NewReadOnlyValue(storedValue)
+}
+
+// sharedStateHandler returns an interpreter shared state handler,
+// which reads the stored values from the given shared state
+//
+func sharedStateHandler(state *SharedState) interpreter.SharedStateHandlerFunc {
+	return func(address common.Address, key common.StorageKey) *interpreter.ReadOnlyValue {
+		value, err := state.ReadStored(address, key)
+		if err != nil {
+			panic(err)
+		}
+		return value
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// newSharedStateTestLedger returns a ledger with values stored in account 0x1
+//
+func newSharedStateTestLedger(t testing.TB) testLedger {
+
+	ledger := newTestLedger(nil, nil)
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: ledger,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{common.BytesToAddress([]byte{0x1})}, nil
+		},
+	}
+
+	err := newTestInterpreterRuntime().ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let numbers: [Int] = []
+                      var i = 0
+                      while i < 1000 {
+                          numbers.append(i)
+                          i = i + 1
+                      }
+                      signer.save(numbers, to: /storage/numbers)
+                      signer.save({"greeting": "Hello, 世界"}, to: /storage/strings)
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	return ledger
+}
+
+const sharedStateTestScript = `
+  pub fun main(): Int {
+      let account = getAuthAccount(0x1)
+      let numbers = account.copy<[Int]>(from: /storage/numbers)!
+      var sum = 0
+      for number in numbers {
+          sum = sum + number
+      }
+      let strings = account.borrow<&{String: String}>(from: /storage/strings)!
+      return sum + strings["greeting"]!.length
+  }
+`
+
+func TestRuntimeSharedState(t *testing.T) {
+
+	t.Parallel()
+
+	ledger := newSharedStateTestLedger(t)
+
+	sharedState := NewSharedState(ledger)
+
+	executeScript := func(code string) (cadence.Value, error) {
+		return newTestInterpreterRuntime().ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				// Values are read from the shared state, not from the ledger of the execution
+				Interface: &testRuntimeInterface{
+					storage: newTestLedger(nil, nil),
+				},
+				Location:    common.ScriptLocation{},
+				SharedState: sharedState,
+			},
+		)
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				value, err := executeScript(sharedStateTestScript)
+				require.NoError(t, err)
+				assert.Equal(t, cadence.NewInt(499509), value)
+			}()
+		}
+
+		wg.Wait()
+
+		// Each stored value is only loaded once
+
+		assert.Equal(t, 2, sharedState.Len())
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+
+		value, err := executeScript(`
+          pub fun main(): Bool {
+              return getAuthAccount(0x1).borrow<&[Int]>(from: /storage/missing) == nil
+          }
+        `)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewBool(true), value)
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+
+		_, err := executeScript(`
+          pub fun main() {
+              getAuthAccount(0x1).borrow<&[Int]>(from: /storage/numbers)!.append(100)
+          }
+        `)
+		require.Error(t, err)
+
+		assert.ErrorAs(t, err, &interpreter.ReadOnlyValueMutationError{})
+	})
+
+	t.Run("write", func(t *testing.T) {
+
+		_, err := executeScript(`
+          pub fun main() {
+              getAuthAccount(0x1).save(1, to: /storage/one)
+          }
+        `)
+		require.Error(t, err)
+
+		assert.ErrorAs(t, err, &interpreter.ReadOnlyValueMutationError{})
+	})
+}
+
+func BenchmarkRuntimeSharedState(b *testing.B) {
+
+	ledger := newSharedStateTestLedger(b)
+
+	runtime := newTestInterpreterRuntime()
+
+	benchmark := func(b *testing.B, sharedState *SharedState) {
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			runtimeInterface := &testRuntimeInterface{
+				storage: ledger,
+			}
+
+			for pb.Next() {
+				_, err := runtime.ExecuteScript(
+					Script{
+						Source: []byte(sharedStateTestScript),
+					},
+					Context{
+						Interface:   runtimeInterface,
+						Location:    common.ScriptLocation{},
+						SharedState: sharedState,
+					},
+				)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	b.Run("ledger", func(b *testing.B) {
+		benchmark(b, nil)
+	})
+
+	b.Run("shared state", func(b *testing.B) {
+		benchmark(b, NewSharedState(ledger))
+	})
+}
@@ -24,38 +24,15 @@ import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/declarations"
 )
 
 // This file defines functions built-in to Cadence.
 
 // AssertFunction
 
-const assertFunctionDocString = `
-Terminates the program if the given condition is false, and reports a message which explains how the condition is false. Use this function for internal sanity checks.
-
-The message argument is optional.
-`
-
-var AssertFunction = NewStandardLibraryFunction(
-	"assert",
-	&sema.FunctionType{
-		Parameters: []*sema.Parameter{
-			{
-				Label:          sema.ArgumentLabelNotRequired,
-				Identifier:     "condition",
-				TypeAnnotation: sema.NewTypeAnnotation(sema.BoolType),
-			},
-			{
-				Identifier:     "message",
-				TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(
-			sema.VoidType,
-		),
-		RequiredArgumentCount: sema.RequiredArgumentCount(1),
-	},
-	assertFunctionDocString,
+var AssertFunction = newStandardLibraryFunction(
+	declarations.AssertFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		result := invocation.Arguments[0].(interpreter.BoolValue)
 		if !result {
@@ -79,31 +56,16 @@ type PanicError struct {
 	interpreter.LocationRange
 }
 
+func (PanicError) IsUserError() {}
+
 func (e PanicError) Error() string {
 	return fmt.Sprintf("panic: %s", e.Message)
 }
 
 // PanicFunction
 
-const panicFunctionDocString = `
-Terminates the program unconditionally and reports a message which explains why the unrecoverable error occurred.
-`
-
-var PanicFunction = NewStandardLibraryFunction(
-	"panic",
-	&sema.FunctionType{
-		Parameters: []*sema.Parameter{
-			{
-				Label:          sema.ArgumentLabelNotRequired,
-				Identifier:     "message",
-				TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(
-			sema.NeverType,
-		),
-	},
-	panicFunctionDocString,
+var PanicFunction = newStandardLibraryFunction(
+	declarations.PanicFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		message := invocation.Arguments[0].(*interpreter.StringValue)
 		panic(PanicError{
@@ -113,6 +75,22 @@ var PanicFunction = NewStandardLibraryFunction(
 	},
 )
 
+// CallSafeFunction
+
+var CallSafeFunction = newStandardLibraryFunction(
+	declarations.CallSafeFunction,
+	func(invocation interpreter.Invocation) interpreter.Value {
+		function := invocation.Arguments[0].(interpreter.FunctionValue)
+
+		result, err := invocation.Interpreter.CallSafe(function, invocation.GetLocationRange)
+		if err != nil {
+			return interpreter.NilValue{}
+		}
+
+		return interpreter.NewSomeValueNonCopying(result)
+	},
+)
+
 // BuiltinFunctions
 
 var BuiltinFunctions = StandardLibraryFunctions{
@@ -121,18 +99,13 @@ var BuiltinFunctions = StandardLibraryFunctions{
 	CreatePublicKeyFunction,
 	AggregateBLSSignaturesFunction,
 	AggregateBLSPublicKeysFunction,
+	CallSafeFunction,
 }
 
 // LogFunction
 
-const logFunctionDocString = `
-Logs a string representation of the given value
-`
-
-var LogFunction = NewStandardLibraryFunction(
-	"log",
-	LogFunctionType,
-	logFunctionDocString,
+var LogFunction = newStandardLibraryFunction(
+	declarations.LogFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		fmt.Println(invocation.Arguments[0].String())
 		return interpreter.VoidValue{}
@@ -145,26 +118,8 @@ var HelperFunctions = StandardLibraryFunctions{
 	LogFunction,
 }
 
-const createPublicKeyFunctionDocString = `
-Constructs a new public key
-`
-
-var CreatePublicKeyFunction = NewStandardLibraryFunction(
-	sema.PublicKeyTypeName,
-	&sema.FunctionType{
-		Parameters: []*sema.Parameter{
-			{
-				Identifier:     sema.PublicKeyPublicKeyField,
-				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.UInt8Type}),
-			},
-			{
-				Identifier:     sema.PublicKeySignAlgoField,
-				TypeAnnotation: sema.NewTypeAnnotation(sema.SignatureAlgorithmType),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.PublicKeyType),
-	},
-	createPublicKeyFunctionDocString,
+var CreatePublicKeyFunction = newStandardLibraryFunction(
+	declarations.CreatePublicKeyFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		publicKey := invocation.Arguments[0].(*interpreter.ArrayValue)
 		signAlgo := invocation.Arguments[1].(*interpreter.CompositeValue)
@@ -181,59 +136,16 @@ var CreatePublicKeyFunction = NewStandardLibraryFunction(
 	},
 )
 
-const aggregateBLSSignaturesFunctionDocString = `
-This is a specific function for the BLS signature scheme. 
-It aggregates multiple BLS signatures into one, 
-considering the proof of possession as a defense against rogue attacks.
-
-Signatures could be generated from the same or distinct messages, 
-they could also be the aggregation of other signatures.
-The order of the signatures in the slice does not matter since the aggregation is commutative. 
-No subgroup membership check is performed on the input signatures.
-The function errors if the array is empty or if decoding one of the signature fails. 
-`
-
-var AggregateBLSSignaturesFunction = NewStandardLibraryFunction(
-	"AggregateBLSSignatures",
-	&sema.FunctionType{
-		Parameters: []*sema.Parameter{
-			{
-				Label:          sema.ArgumentLabelNotRequired,
-				Identifier:     "signatures",
-				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.ByteArrayType}),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.ByteArrayType),
-	},
-	aggregateBLSSignaturesFunctionDocString,
+var AggregateBLSSignaturesFunction = newStandardLibraryFunction(
+	declarations.AggregateBLSSignaturesFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		signatures := invocation.Arguments[0].(*interpreter.ArrayValue)
 		return AggregateBLSSignatures(invocation.Interpreter, signatures)
 	},
 )
 
-const aggregateBLSPublicKeysFunctionDocString = `
-This is a specific function for the BLS signature scheme. 
-It aggregates multiple BLS public keys into one.
-
-The order of the public keys in the slice does not matter since the aggregation is commutative. 
-No subgroup membership check is performed on the input keys.
-The function errors if the array is empty or any of the input keys is not a BLS key.
-`
-
-var AggregateBLSPublicKeysFunction = NewStandardLibraryFunction(
-	"AggregateBLSPublicKeys",
-	&sema.FunctionType{
-		Parameters: []*sema.Parameter{
-			{
-				Label:          sema.ArgumentLabelNotRequired,
-				Identifier:     "keys",
-				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.PublicKeyType}),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.PublicKeyType),
-	},
-	aggregateBLSPublicKeysFunctionDocString,
+var AggregateBLSPublicKeysFunction = newStandardLibraryFunction(
+	declarations.AggregateBLSPublicKeysFunction,
 	func(invocation interpreter.Invocation) interpreter.Value {
 		publicKeys := invocation.Arguments[0].(*interpreter.ArrayValue)
 		return AggregateBLSPublicKeys(
@@ -310,12 +222,9 @@ func AggregateBLSSignatures(
 func BuiltinValues() StandardLibraryValues {
 	signatureAlgorithmValue := StandardLibraryValue{
 		Name: sema.SignatureAlgorithmTypeName,
-		Type: cryptoAlgorithmEnumConstructorType(
-			sema.SignatureAlgorithmType,
-			sema.SignatureAlgorithms,
-		),
+		Type: declarations.SignatureAlgorithmConstructor.Type,
 		ValueFactory: func(inter *interpreter.Interpreter) interpreter.Value {
-			return cryptoAlgorithmEnumValue(
+			return nativeEnumValue(
 				inter,
 				interpreter.ReturnEmptyLocationRange,
 				sema.SignatureAlgorithmType,
@@ -328,12 +237,9 @@ func BuiltinValues() StandardLibraryValues {
 
 	hashAlgorithmValue := StandardLibraryValue{
 		Name: sema.HashAlgorithmTypeName,
-		Type: cryptoAlgorithmEnumConstructorType(
-			sema.HashAlgorithmType,
-			sema.HashAlgorithms,
-		),
+		Type: declarations.HashAlgorithmConstructor.Type,
 		ValueFactory: func(inter *interpreter.Interpreter) interpreter.Value {
-			return cryptoAlgorithmEnumValue(
+			return nativeEnumValue(
 				inter,
 				interpreter.ReturnEmptyLocationRange,
 				sema.HashAlgorithmType,
@@ -344,12 +250,37 @@ func BuiltinValues() StandardLibraryValues {
 		Kind: common.DeclarationKindEnum,
 	}
 
+	roundingRuleValue := StandardLibraryValue{
+		Name: sema.RoundingRuleTypeName,
+		Type: declarations.RoundingRuleConstructor.Type,
+		ValueFactory: func(inter *interpreter.Interpreter) interpreter.Value {
+			return lazyNativeEnumValue(
+				inter,
+				interpreter.ReturnEmptyLocationRange,
+				sema.RoundingRuleType,
+				sema.RoundingRules,
+				NewRoundingRuleCase,
+			)
+		},
+		Kind: common.DeclarationKindEnum,
+	}
+
 	return StandardLibraryValues{
 		signatureAlgorithmValue,
 		hashAlgorithmValue,
+		roundingRuleValue,
 	}
 }
 
+func NewRoundingRuleCase(inter *interpreter.Interpreter, rawValue uint8) *interpreter.CompositeValue {
+	return interpreter.NewEnumCaseValue(
+		inter,
+		sema.RoundingRuleType,
+		interpreter.UInt8Value(rawValue),
+		nil,
+	)
+}
+
 func NewSignatureAlgorithmCase(inter *interpreter.Interpreter, rawValue uint8) *interpreter.CompositeValue {
 	return interpreter.NewEnumCaseValue(
 		inter,
@@ -426,45 +357,11 @@ var hashAlgorithmHashWithTagFunction = interpreter.NewHostFunctionValue(
 	sema.HashAlgorithmTypeHashWithTagFunctionType,
 )
 
-func cryptoAlgorithmEnumConstructorType(
-	enumType *sema.CompositeType,
-	enumCases []sema.CryptoAlgorithm,
-) *sema.FunctionType {
-
-	members := make([]*sema.Member, len(enumCases))
-	for i, algo := range enumCases {
-		members[i] = sema.NewPublicConstantFieldMember(
-			enumType,
-			algo.Name(),
-			enumType,
-			algo.DocString(),
-		)
-	}
-
-	constructorType := &sema.FunctionType{
-		IsConstructor: true,
-		Parameters: []*sema.Parameter{
-			{
-				Identifier:     sema.EnumRawValueFieldName,
-				TypeAnnotation: sema.NewTypeAnnotation(enumType.EnumRawType),
-			},
-		},
-		ReturnTypeAnnotation: sema.NewTypeAnnotation(
-			&sema.OptionalType{
-				Type: enumType,
-			},
-		),
-		Members: sema.GetMembersAsMap(members),
-	}
-
-	return constructorType
-}
-
-func cryptoAlgorithmEnumValue(
+func nativeEnumValue(
 	inter *interpreter.Interpreter,
 	getLocationRange func() interpreter.LocationRange,
 	enumType *sema.CompositeType,
-	enumCases []sema.CryptoAlgorithm,
+	enumCases []sema.NativeEnumCase,
 	caseConstructor func(inter *interpreter.Interpreter, rawValue uint8) *interpreter.CompositeValue,
 ) interpreter.Value {
 
@@ -488,3 +385,63 @@ func cryptoAlgorithmEnumValue(
 		constructorNestedVariables,
 	)
 }
+
+// lazyNativeEnumValue returns the constructor of a native enum, like nativeEnumValue,
+// but the case values are only created when the enum is first used,
+// i.e. when the constructor is called, or when a case is accessed.
+//
+// Creating the case values allocates storage, which affects the storage indices
+// and the seeds of all values created afterwards, e.g. the iteration order of dictionaries.
+// Enums which are added to the standard library must be lazy,
+// so the behaviour of existing programs which do not use them does not change.
+//
+func lazyNativeEnumValue(
+	inter *interpreter.Interpreter,
+	getLocationRange func() interpreter.LocationRange,
+	enumType *sema.CompositeType,
+	enumCases []sema.NativeEnumCase,
+	caseConstructor func(inter *interpreter.Interpreter, rawValue uint8) *interpreter.CompositeValue,
+) interpreter.Value {
+
+	var constructor *interpreter.HostFunctionValue
+
+	getConstructor := func() *interpreter.HostFunctionValue {
+		if constructor == nil {
+			constructor = nativeEnumValue(
+				inter,
+				getLocationRange,
+				enumType,
+				enumCases,
+				caseConstructor,
+			).(*interpreter.HostFunctionValue)
+		}
+		return constructor
+	}
+
+	lazyConstructor := interpreter.NewHostFunctionValue(
+		func(invocation interpreter.Invocation) interpreter.Value {
+			return getConstructor().Function(invocation)
+		},
+		sema.EnumConstructorType(enumType),
+	)
+
+	nestedVariableNames := make([]string, 0, len(enumCases)+1)
+	for _, enumCase := range enumCases {
+		nestedVariableNames = append(nestedVariableNames, enumCase.Name())
+	}
+	nestedVariableNames = append(nestedVariableNames, sema.EnumAllCasesFieldName)
+
+	nestedVariables := make(map[string]*interpreter.Variable, len(nestedVariableNames))
+	for _, name := range nestedVariableNames {
+		// NOTE: declare a new variable in each iteration,
+		// as the name is captured by the getter
+		name := name
+		nestedVariables[name] = interpreter.NewVariableWithGetter(func() interpreter.Value {
+			return getConstructor().NestedVariables[name].GetValue()
+		})
+	}
+
+	lazyConstructor.NestedVariables = nestedVariables
+
+	return lazyConstructor
+}
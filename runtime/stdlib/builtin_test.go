@@ -123,3 +123,51 @@ func TestPanic(t *testing.T) {
 		err,
 	)
 }
+
+func TestRoundingRuleValue(t *testing.T) {
+
+	t.Parallel()
+
+	var roundingRuleValue StandardLibraryValue
+	for _, value := range BuiltinValues() {
+		if value.Name == sema.RoundingRuleTypeName {
+			roundingRuleValue = value
+		}
+	}
+	require.NotNil(t, roundingRuleValue.ValueFactory)
+
+	storage := interpreter.NewInMemoryStorage()
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		interpreter.WithStorage(storage),
+	)
+	require.NoError(t, err)
+
+	// Declaring the enum does not create the cases,
+	// so no storage is allocated
+
+	constructor := roundingRuleValue.ValueFactory(inter).(*interpreter.HostFunctionValue)
+
+	assert.Equal(t, 0, storage.Count())
+
+	// Accessing a case creates all cases,
+	// which are shared by the constructor
+
+	rule := sema.RoundingRules[1]
+
+	caseValue := constructor.NestedVariables[rule.Name()].GetValue()
+
+	assert.Equal(t, len(sema.RoundingRules), storage.Count())
+
+	result := constructor.Function(interpreter.Invocation{
+		Arguments:   []interpreter.Value{interpreter.UInt8Value(rule.RawValue())},
+		Interpreter: inter,
+	})
+
+	require.IsType(t, &interpreter.SomeValue{}, result)
+	assert.Same(t, caseValue, result.(*interpreter.SomeValue).Value)
+
+	assert.Equal(t, len(sema.RoundingRules), storage.Count())
+}
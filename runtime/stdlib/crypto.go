@@ -20,43 +20,13 @@ package stdlib
 
 import (
 	"github.com/onflow/cadence/runtime/ast"
-	"github.com/onflow/cadence/runtime/common"
 	errors2 "github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
-	"github.com/onflow/cadence/runtime/parser2"
 	"github.com/onflow/cadence/runtime/sema"
-	"github.com/onflow/cadence/runtime/stdlib/internal"
+	"github.com/onflow/cadence/runtime/stdlib/declarations"
 )
 
-var CryptoChecker = func() *sema.Checker {
-
-	code := internal.MustAssetString("contracts/crypto.cdc")
-
-	program, err := parser2.ParseProgram(code)
-	if err != nil {
-		panic(err)
-	}
-
-	location := common.IdentifierLocation("Crypto")
-
-	var checker *sema.Checker
-	checker, err = sema.NewChecker(
-		program,
-		location,
-		sema.WithPredeclaredValues(BuiltinFunctions.ToSemaValueDeclarations()),
-		sema.WithPredeclaredTypes(BuiltinTypes.ToTypeDeclarations()),
-	)
-	if err != nil {
-		panic(err)
-	}
-
-	err = checker.Check()
-	if err != nil {
-		panic(err)
-	}
-
-	return checker
-}()
+var CryptoChecker = declarations.CryptoChecker
 
 var cryptoContractType = func() *sema.CompositeType {
 	variable, ok := CryptoChecker.Elaboration.GlobalTypes.Get("Crypto")
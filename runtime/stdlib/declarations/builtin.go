@@ -0,0 +1,284 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package declarations
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+const assertFunctionDocString = `
+Terminates the program if the given condition is false, and reports a message which explains how the condition is false. Use this function for internal sanity checks.
+
+The message argument is optional.
+`
+
+var AssertFunction = FunctionDeclaration{
+	Name: "assert",
+	Type: &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Label:          sema.ArgumentLabelNotRequired,
+				Identifier:     "condition",
+				TypeAnnotation: sema.NewTypeAnnotation(sema.BoolType),
+			},
+			{
+				Identifier:     "message",
+				TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			sema.VoidType,
+		),
+		RequiredArgumentCount: sema.RequiredArgumentCount(1),
+	},
+	DocString: assertFunctionDocString,
+}
+
+const panicFunctionDocString = `
+Terminates the program unconditionally and reports a message which explains why the unrecoverable error occurred.
+`
+
+var PanicFunction = FunctionDeclaration{
+	Name: "panic",
+	Type: &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Label:          sema.ArgumentLabelNotRequired,
+				Identifier:     "message",
+				TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			sema.NeverType,
+		),
+	},
+	DocString: panicFunctionDocString,
+}
+
+const createPublicKeyFunctionDocString = `
+Constructs a new public key
+`
+
+var CreatePublicKeyFunction = FunctionDeclaration{
+	Name: sema.PublicKeyTypeName,
+	Type: &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Identifier:     sema.PublicKeyPublicKeyField,
+				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.UInt8Type}),
+			},
+			{
+				Identifier:     sema.PublicKeySignAlgoField,
+				TypeAnnotation: sema.NewTypeAnnotation(sema.SignatureAlgorithmType),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.PublicKeyType),
+	},
+	DocString: createPublicKeyFunctionDocString,
+}
+
+const aggregateBLSSignaturesFunctionDocString = `
+This is a specific function for the BLS signature scheme. 
+It aggregates multiple BLS signatures into one, 
+considering the proof of possession as a defense against rogue attacks.
+
+Signatures could be generated from the same or distinct messages, 
+they could also be the aggregation of other signatures.
+The order of the signatures in the slice does not matter since the aggregation is commutative. 
+No subgroup membership check is performed on the input signatures.
+The function errors if the array is empty or if decoding one of the signature fails. 
+`
+
+var AggregateBLSSignaturesFunction = FunctionDeclaration{
+	Name: "AggregateBLSSignatures",
+	Type: &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Label:          sema.ArgumentLabelNotRequired,
+				Identifier:     "signatures",
+				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.ByteArrayType}),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.ByteArrayType),
+	},
+	DocString: aggregateBLSSignaturesFunctionDocString,
+}
+
+const aggregateBLSPublicKeysFunctionDocString = `
+This is a specific function for the BLS signature scheme. 
+It aggregates multiple BLS public keys into one.
+
+The order of the public keys in the slice does not matter since the aggregation is commutative. 
+No subgroup membership check is performed on the input keys.
+The function errors if the array is empty or any of the input keys is not a BLS key.
+`
+
+var AggregateBLSPublicKeysFunction = FunctionDeclaration{
+	Name: "AggregateBLSPublicKeys",
+	Type: &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Label:          sema.ArgumentLabelNotRequired,
+				Identifier:     "keys",
+				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.PublicKeyType}),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.PublicKeyType),
+	},
+	DocString: aggregateBLSPublicKeysFunctionDocString,
+}
+
+const callSafeFunctionDocString = `
+Calls the given function and returns its result, or nil if the call failed.
+
+A failure is only handled if the call had no effects before it failed,
+e.g. it did not write to account storage, mutate an existing object, assign an existing variable,
+or change an account, like adding a key or deploying a contract.
+Events emitted by the failed call are discarded.
+If the call had effects, or the failure is not a program error, the program aborts as usual.
+
+The function must not return a resource.
+`
+
+var CallSafeFunction = func() FunctionDeclaration {
+
+	typeParameter := &sema.TypeParameter{
+		Name:      "T",
+		TypeBound: sema.AnyStructType,
+	}
+
+	resultType := &sema.GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	return FunctionDeclaration{
+		Name: "callSafe",
+		Type: &sema.FunctionType{
+			TypeParameters: []*sema.TypeParameter{
+				typeParameter,
+			},
+			Parameters: []*sema.Parameter{
+				{
+					Label:      sema.ArgumentLabelNotRequired,
+					Identifier: "function",
+					TypeAnnotation: sema.NewTypeAnnotation(
+						&sema.FunctionType{
+							ReturnTypeAnnotation: sema.NewTypeAnnotation(resultType),
+						},
+					),
+				},
+			},
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(
+				&sema.OptionalType{
+					Type: resultType,
+				},
+			),
+		},
+		DocString: callSafeFunctionDocString,
+	}
+}()
+
+// BuiltinFunctions
+
+var BuiltinFunctions = FunctionDeclarations{
+	AssertFunction,
+	PanicFunction,
+	CreatePublicKeyFunction,
+	AggregateBLSSignaturesFunction,
+	AggregateBLSPublicKeysFunction,
+	CallSafeFunction,
+}
+
+// SignatureAlgorithmConstructor
+
+var SignatureAlgorithmConstructor = ValueDeclaration{
+	Name: sema.SignatureAlgorithmTypeName,
+	Type: nativeEnumConstructorType(
+		sema.SignatureAlgorithmType,
+		sema.SignatureAlgorithms,
+	),
+	Kind: common.DeclarationKindEnum,
+}
+
+// HashAlgorithmConstructor
+
+var HashAlgorithmConstructor = ValueDeclaration{
+	Name: sema.HashAlgorithmTypeName,
+	Type: nativeEnumConstructorType(
+		sema.HashAlgorithmType,
+		sema.HashAlgorithms,
+	),
+	Kind: common.DeclarationKindEnum,
+}
+
+// RoundingRuleConstructor
+
+var RoundingRuleConstructor = ValueDeclaration{
+	Name: sema.RoundingRuleTypeName,
+	Type: nativeEnumConstructorType(
+		sema.RoundingRuleType,
+		sema.RoundingRules,
+	),
+	Kind: common.DeclarationKindEnum,
+}
+
+// BuiltinValues
+
+var BuiltinValues = ValueDeclarations{
+	SignatureAlgorithmConstructor,
+	HashAlgorithmConstructor,
+	RoundingRuleConstructor,
+}
+
+func nativeEnumConstructorType(
+	enumType *sema.CompositeType,
+	enumCases []sema.NativeEnumCase,
+) *sema.FunctionType {
+
+	members := make([]*sema.Member, len(enumCases), len(enumCases)+1)
+	for i, algo := range enumCases {
+		members[i] = sema.NewPublicConstantFieldMember(
+			enumType,
+			algo.Name(),
+			enumType,
+			algo.DocString(),
+		)
+	}
+
+	members = append(members, sema.EnumAllCasesMember(enumType, enumType))
+
+	constructorType := &sema.FunctionType{
+		IsConstructor: true,
+		Parameters: []*sema.Parameter{
+			{
+				Identifier:     sema.EnumRawValueFieldName,
+				TypeAnnotation: sema.NewTypeAnnotation(enumType.EnumRawType),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			&sema.OptionalType{
+				Type: enumType,
+			},
+		),
+		Members: sema.GetMembersAsMap(members),
+	}
+
+	return constructorType
+}
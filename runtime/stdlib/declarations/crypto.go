@@ -0,0 +1,58 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package declarations
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/internal"
+)
+
+// CryptoChecker is the checker of the Crypto contract,
+// which can be imported by programs using `import Crypto`
+//
+var CryptoChecker = func() *sema.Checker {
+
+	code := internal.MustAssetString("contracts/crypto.cdc")
+
+	program, err := parser2.ParseProgram(code)
+	if err != nil {
+		panic(err)
+	}
+
+	location := common.IdentifierLocation("Crypto")
+
+	var checker *sema.Checker
+	checker, err = sema.NewChecker(
+		program,
+		location,
+		sema.WithPredeclaredValues(BuiltinFunctions.ToSemaValueDeclarations()),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = checker.Check()
+	if err != nil {
+		panic(err)
+	}
+
+	return checker
+}()
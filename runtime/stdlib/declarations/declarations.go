@@ -0,0 +1,161 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package declarations contains the declarations of the standard library
+// which are needed to check programs, i.e. their names, types, and documentation.
+//
+// Unlike package stdlib, which provides the implementations,
+// this package does not depend on the interpreter.
+// Programs which only parse and check code, like the WebAssembly build of the checker,
+// use this package to avoid including the interpreter, storage encoding, and cryptography.
+//
+package declarations
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// FunctionDeclaration is the declaration of a standard library function
+//
+type FunctionDeclaration struct {
+	Name      string
+	Type      *sema.FunctionType
+	DocString string
+}
+
+var _ sema.ValueDeclaration = FunctionDeclaration{}
+
+func (f FunctionDeclaration) ValueDeclarationName() string {
+	return f.Name
+}
+
+func (f FunctionDeclaration) ValueDeclarationType() sema.Type {
+	return f.Type
+}
+
+func (f FunctionDeclaration) ValueDeclarationDocString() string {
+	return f.DocString
+}
+
+func (FunctionDeclaration) ValueDeclarationKind() common.DeclarationKind {
+	return common.DeclarationKindFunction
+}
+
+func (FunctionDeclaration) ValueDeclarationPosition() ast.Position {
+	return ast.Position{}
+}
+
+func (FunctionDeclaration) ValueDeclarationIsConstant() bool {
+	return true
+}
+
+func (FunctionDeclaration) ValueDeclarationAvailable(_ common.Location) bool {
+	return true
+}
+
+func (f FunctionDeclaration) ValueDeclarationArgumentLabels() []string {
+	parameters := f.Type.Parameters
+
+	argumentLabels := make([]string, len(parameters))
+
+	for i, parameter := range parameters {
+		argumentLabels[i] = parameter.EffectiveArgumentLabel()
+	}
+
+	return argumentLabels
+}
+
+// FunctionDeclarations
+
+type FunctionDeclarations []FunctionDeclaration
+
+func (functions FunctionDeclarations) ToSemaValueDeclarations() []sema.ValueDeclaration {
+	valueDeclarations := make([]sema.ValueDeclaration, len(functions))
+	for i, function := range functions {
+		valueDeclarations[i] = function
+	}
+	return valueDeclarations
+}
+
+// ValueDeclaration is the declaration of a standard library value
+//
+type ValueDeclaration struct {
+	Name      string
+	Type      sema.Type
+	DocString string
+	Kind      common.DeclarationKind
+}
+
+var _ sema.ValueDeclaration = ValueDeclaration{}
+
+func (v ValueDeclaration) ValueDeclarationName() string {
+	return v.Name
+}
+
+func (v ValueDeclaration) ValueDeclarationType() sema.Type {
+	return v.Type
+}
+
+func (v ValueDeclaration) ValueDeclarationDocString() string {
+	return v.DocString
+}
+
+func (v ValueDeclaration) ValueDeclarationKind() common.DeclarationKind {
+	return v.Kind
+}
+
+func (ValueDeclaration) ValueDeclarationPosition() ast.Position {
+	return ast.Position{}
+}
+
+func (v ValueDeclaration) ValueDeclarationIsConstant() bool {
+	return v.Kind != common.DeclarationKindVariable
+}
+
+func (ValueDeclaration) ValueDeclarationAvailable(_ common.Location) bool {
+	return true
+}
+
+func (ValueDeclaration) ValueDeclarationArgumentLabels() []string {
+	return nil
+}
+
+// ValueDeclarations
+
+type ValueDeclarations []ValueDeclaration
+
+func (values ValueDeclarations) ToSemaValueDeclarations() []sema.ValueDeclaration {
+	valueDeclarations := make([]sema.ValueDeclaration, len(values))
+	for i, value := range values {
+		valueDeclarations[i] = value
+	}
+	return valueDeclarations
+}
+
+// All returns the declarations of all standard library functions and values
+// which are available in Flow programs
+//
+func All() []sema.ValueDeclaration {
+	var result []sema.ValueDeclaration
+	result = append(result, FlowBuiltinFunctions.ToSemaValueDeclarations()...)
+	result = append(result, BuiltinFunctions.ToSemaValueDeclarations()...)
+	result = append(result, BuiltinValues.ToSemaValueDeclarations()...)
+	return result
+}
@@ -0,0 +1,173 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package declarations
+
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// This file declares the functions built in to the Flow runtime.
+
+const authAccountFunctionDocString = `
+Creates a new account, paid by the given existing account
+`
+
+var authAccountFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Identifier: "payer",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				sema.AuthAccountType,
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.AuthAccountType,
+	),
+}
+
+const getAccountFunctionDocString = `
+Returns the public account for the given address
+`
+
+var getAccountFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "address",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.AddressType{},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.PublicAccountType,
+	),
+}
+
+var logFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "value",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				sema.AnyStructType,
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const getCurrentBlockFunctionDocString = `
+Returns the current block, i.e. the block which contains the currently executed transaction
+`
+
+var getCurrentBlockFunctionType = &sema.FunctionType{
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.BlockType,
+	),
+}
+
+const getBlockFunctionDocString = `
+Returns the block at the given height. If the given block does not exist the function returns nil
+`
+
+var getBlockFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      "at",
+			Identifier: "height",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				sema.UInt64Type,
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.OptionalType{
+			Type: sema.BlockType,
+		},
+	),
+}
+
+const unsafeRandomFunctionDocString = `
+Returns a pseudo-random number.
+
+NOTE: The use of this function is unsafe if not used correctly.
+
+Follow best practices to prevent security issues when using this function
+`
+
+var unsafeRandomFunctionType = &sema.FunctionType{
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.UInt64Type,
+	),
+}
+
+const logFunctionDocString = `
+Logs a string representation of the given value
+`
+
+var AuthAccountFunction = FunctionDeclaration{
+	Name:      "AuthAccount",
+	Type:      authAccountFunctionType,
+	DocString: authAccountFunctionDocString,
+}
+
+var GetAccountFunction = FunctionDeclaration{
+	Name:      "getAccount",
+	Type:      getAccountFunctionType,
+	DocString: getAccountFunctionDocString,
+}
+
+var LogFunction = FunctionDeclaration{
+	Name:      "log",
+	Type:      logFunctionType,
+	DocString: logFunctionDocString,
+}
+
+var GetCurrentBlockFunction = FunctionDeclaration{
+	Name:      "getCurrentBlock",
+	Type:      getCurrentBlockFunctionType,
+	DocString: getCurrentBlockFunctionDocString,
+}
+
+var GetBlockFunction = FunctionDeclaration{
+	Name:      "getBlock",
+	Type:      getBlockFunctionType,
+	DocString: getBlockFunctionDocString,
+}
+
+var UnsafeRandomFunction = FunctionDeclaration{
+	Name:      "unsafeRandom",
+	Type:      unsafeRandomFunctionType,
+	DocString: unsafeRandomFunctionDocString,
+}
+
+// FlowBuiltinFunctions
+
+var FlowBuiltinFunctions = FunctionDeclarations{
+	AuthAccountFunction,
+	GetAccountFunction,
+	LogFunction,
+	GetCurrentBlockFunction,
+	GetBlockFunction,
+	UnsafeRandomFunction,
+}
@@ -27,106 +27,14 @@ import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/declarations"
 )
 
 // This file defines functions built in to the Flow runtime.
 
-const authAccountFunctionDocString = `
-Creates a new account, paid by the given existing account
-`
-
-var authAccountFunctionType = &sema.FunctionType{
-	Parameters: []*sema.Parameter{
-		{
-			Identifier: "payer",
-			TypeAnnotation: sema.NewTypeAnnotation(
-				sema.AuthAccountType,
-			),
-		},
-	},
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		sema.AuthAccountType,
-	),
-}
-
-const getAccountFunctionDocString = `
-Returns the public account for the given address
-`
-
-var getAccountFunctionType = &sema.FunctionType{
-	Parameters: []*sema.Parameter{
-		{
-			Label:      sema.ArgumentLabelNotRequired,
-			Identifier: "address",
-			TypeAnnotation: sema.NewTypeAnnotation(
-				&sema.AddressType{},
-			),
-		},
-	},
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		sema.PublicAccountType,
-	),
-}
-
-var LogFunctionType = &sema.FunctionType{
-	Parameters: []*sema.Parameter{
-		{
-			Label:      sema.ArgumentLabelNotRequired,
-			Identifier: "value",
-			TypeAnnotation: sema.NewTypeAnnotation(
-				sema.AnyStructType,
-			),
-		},
-	},
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		sema.VoidType,
-	),
-}
-
-const getCurrentBlockFunctionDocString = `
-Returns the current block, i.e. the block which contains the currently executed transaction
-`
-
-var getCurrentBlockFunctionType = &sema.FunctionType{
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		sema.BlockType,
-	),
-}
-
-const getBlockFunctionDocString = `
-Returns the block at the given height. If the given block does not exist the function returns nil
-`
-
-var getBlockFunctionType = &sema.FunctionType{
-	Parameters: []*sema.Parameter{
-		{
-			Label:      "at",
-			Identifier: "height",
-			TypeAnnotation: sema.NewTypeAnnotation(
-				sema.UInt64Type,
-			),
-		},
-	},
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		&sema.OptionalType{
-			Type: sema.BlockType,
-		},
-	),
-}
-
-const unsafeRandomFunctionDocString = `
-Returns a pseudo-random number.
-
-NOTE: The use of this function is unsafe if not used correctly.
-
-Follow best practices to prevent security issues when using this function
-`
-
-var unsafeRandomFunctionType = &sema.FunctionType{
-	ReturnTypeAnnotation: sema.NewTypeAnnotation(
-		sema.UInt64Type,
-	),
-}
+// LogFunctionType is the type of the `log` function
+//
+var LogFunctionType = declarations.LogFunction.Type
 
 // FlowBuiltinImpls defines the set of functions needed to implement the Flow
 // built-in functions.
@@ -143,42 +51,12 @@ type FlowBuiltinImpls struct {
 // the provided implementation.
 func FlowBuiltInFunctions(impls FlowBuiltinImpls) StandardLibraryFunctions {
 	return StandardLibraryFunctions{
-		NewStandardLibraryFunction(
-			"AuthAccount",
-			authAccountFunctionType,
-			authAccountFunctionDocString,
-			impls.CreateAccount,
-		),
-		NewStandardLibraryFunction(
-			"getAccount",
-			getAccountFunctionType,
-			getAccountFunctionDocString,
-			impls.GetAccount,
-		),
-		NewStandardLibraryFunction(
-			"log",
-			LogFunctionType,
-			logFunctionDocString,
-			impls.Log,
-		),
-		NewStandardLibraryFunction(
-			"getCurrentBlock",
-			getCurrentBlockFunctionType,
-			getCurrentBlockFunctionDocString,
-			impls.GetCurrentBlock,
-		),
-		NewStandardLibraryFunction(
-			"getBlock",
-			getBlockFunctionType,
-			getBlockFunctionDocString,
-			impls.GetBlock,
-		),
-		NewStandardLibraryFunction(
-			"unsafeRandom",
-			unsafeRandomFunctionType,
-			unsafeRandomFunctionDocString,
-			impls.UnsafeRandom,
-		),
+		newStandardLibraryFunction(declarations.AuthAccountFunction, impls.CreateAccount),
+		newStandardLibraryFunction(declarations.GetAccountFunction, impls.GetAccount),
+		newStandardLibraryFunction(declarations.LogFunction, impls.Log),
+		newStandardLibraryFunction(declarations.GetCurrentBlockFunction, impls.GetCurrentBlock),
+		newStandardLibraryFunction(declarations.GetBlockFunction, impls.GetBlock),
+		newStandardLibraryFunction(declarations.UnsafeRandomFunction, impls.UnsafeRandom),
 	}
 }
 
@@ -295,7 +173,7 @@ func newFlowEventType(identifier string, parameters ...*sema.Parameter) *sema.Co
 		Location:   FlowLocation{},
 		Identifier: identifier,
 		Fields:     []string{},
-		Members:    sema.NewStringMemberOrderedMap(),
+		Members:    &sema.StringMemberOrderedMap{},
 	}
 
 	for _, parameter := range parameters {
@@ -361,6 +239,11 @@ var AccountEventContractParameter = &sema.Parameter{
 	TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
 }
 
+var AccountEventPathParameter = &sema.Parameter{
+	Identifier:     "path",
+	TypeAnnotation: sema.NewTypeAnnotation(sema.PrivatePathType),
+}
+
 var AccountCreatedEventType = newFlowEventType(
 	"AccountCreated",
 	AccountEventAddressParameter,
@@ -399,4 +282,10 @@ var AccountContractRemovedEventType = newFlowEventType(
 	AccountEventContractParameter,
 )
 
+var AccountLinkedEventType = newFlowEventType(
+	"AccountLinked",
+	AccountEventAddressParameter,
+	AccountEventPathParameter,
+)
+
 var FlowBuiltInTypes StandardLibraryTypes
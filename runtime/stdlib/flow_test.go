@@ -37,6 +37,7 @@ func TestFlowEventTypeIDs(t *testing.T) {
 		AccountContractAddedEventType,
 		AccountContractUpdatedEventType,
 		AccountContractRemovedEventType,
+		AccountLinkedEventType,
 	} {
 		assert.True(t, strings.HasPrefix(string(ty.ID()), "flow"))
 	}
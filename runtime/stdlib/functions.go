@@ -25,6 +25,7 @@ import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/declarations"
 )
 
 // StandardLibraryFunction
@@ -103,6 +104,21 @@ func NewStandardLibraryFunction(
 	}
 }
 
+// newStandardLibraryFunction returns a standard library function
+// with the name, type, and documentation of the given declaration
+//
+func newStandardLibraryFunction(
+	declaration declarations.FunctionDeclaration,
+	function interpreter.HostFunction,
+) StandardLibraryFunction {
+	return NewStandardLibraryFunction(
+		declaration.Name,
+		declaration.Type,
+		declaration.DocString,
+		function,
+	)
+}
+
 // StandardLibraryFunctions
 
 type StandardLibraryFunctions []StandardLibraryFunction
@@ -130,6 +146,8 @@ type AssertionError struct {
 	interpreter.LocationRange
 }
 
+func (AssertionError) IsUserError() {}
+
 func (e AssertionError) Error() string {
 	const message = "assertion failed"
 	if e.Message == "" {
@@ -0,0 +1,496 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// TestFramework is the environment in which the functions
+// of the `Test` standard library value operate.
+// It is provided by the embedder, e.g. an in-memory blockchain.
+//
+type TestFramework interface {
+	CreateAccount() (common.Address, error)
+
+	DeployContract(name string, code string, account common.Address) error
+
+	ExecuteScript(inter *interpreter.Interpreter, code string) (interpreter.Value, error)
+
+	ExecuteTransaction(code string, signers []common.Address) error
+
+	CreateSnapshot(name string) error
+
+	LoadSnapshot(name string) error
+}
+
+const TestTypeName = "Test"
+
+const testAssertFunctionName = "assert"
+const testExpectFunctionName = "expect"
+const testAssertErrorFunctionName = "assertError"
+const testCreateAccountFunctionName = "createAccount"
+const testDeployContractFunctionName = "deployContract"
+const testExecuteScriptFunctionName = "executeScript"
+const testExecuteTransactionFunctionName = "executeTransaction"
+const testCreateSnapshotFunctionName = "createSnapshot"
+const testLoadSnapshotFunctionName = "loadSnapshot"
+
+const testAssertFunctionDocString = `
+Fails the test if the given condition is false, and reports a message which explains how the condition is false.
+`
+
+var testAssertFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "condition",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.BoolType),
+		},
+		{
+			Identifier:     "message",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+	RequiredArgumentCount: sema.RequiredArgumentCount(1),
+}
+
+const testExpectFunctionDocString = `
+Fails the test if the given value is not equal to the expected value
+`
+
+var testExpectFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "value",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.AnyStructType),
+		},
+		{
+			Identifier:     "toEqual",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.AnyStructType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const testAssertErrorFunctionDocString = `
+Calls the given function and fails the test if the function does not fail with an error containing the given message
+`
+
+var testAssertErrorFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "function",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.FunctionType{
+					ReturnTypeAnnotation: sema.NewTypeAnnotation(
+						sema.VoidType,
+					),
+				},
+			),
+		},
+		{
+			Identifier:     "errorMessage",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const testCreateAccountFunctionDocString = `
+Creates a new account and returns its address
+`
+
+var testCreateAccountFunctionType = &sema.FunctionType{
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.AddressType{},
+	),
+}
+
+const testDeployContractFunctionDocString = `
+Deploys the contract with the given name and code to the given account
+`
+
+var testDeployContractFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Identifier:     "name",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+		{
+			Identifier:     "code",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+		{
+			Identifier:     "account",
+			TypeAnnotation: sema.NewTypeAnnotation(&sema.AddressType{}),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const testExecuteScriptFunctionDocString = `
+Executes the given script and returns its result
+`
+
+var testExecuteScriptFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "code",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.AnyStructType,
+	),
+}
+
+const testExecuteTransactionFunctionDocString = `
+Executes the given transaction, signed by the accounts with the given addresses
+`
+
+var testExecuteTransactionFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "code",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+		{
+			Identifier: "signers",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{
+					Type: &sema.AddressType{},
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const testCreateSnapshotFunctionDocString = `
+Creates a snapshot of the blockchain state with the given name
+`
+
+var testCreateSnapshotFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Identifier:     "name",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+const testLoadSnapshotFunctionDocString = `
+Resets the blockchain state to the snapshot with the given name
+`
+
+var testLoadSnapshotFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Identifier:     "name",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+// TestType represents the type of the `Test` standard library value
+//
+var TestType = func() *sema.CompositeType {
+
+	testType := &sema.CompositeType{
+		Identifier: TestTypeName,
+		Kind:       common.CompositeKindStructure,
+	}
+
+	var members = []*sema.Member{
+		sema.NewPublicFunctionMember(
+			testType,
+			testAssertFunctionName,
+			testAssertFunctionType,
+			testAssertFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testExpectFunctionName,
+			testExpectFunctionType,
+			testExpectFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testAssertErrorFunctionName,
+			testAssertErrorFunctionType,
+			testAssertErrorFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testCreateAccountFunctionName,
+			testCreateAccountFunctionType,
+			testCreateAccountFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testDeployContractFunctionName,
+			testDeployContractFunctionType,
+			testDeployContractFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testExecuteScriptFunctionName,
+			testExecuteScriptFunctionType,
+			testExecuteScriptFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testExecuteTransactionFunctionName,
+			testExecuteTransactionFunctionType,
+			testExecuteTransactionFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testCreateSnapshotFunctionName,
+			testCreateSnapshotFunctionType,
+			testCreateSnapshotFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			testType,
+			testLoadSnapshotFunctionName,
+			testLoadSnapshotFunctionType,
+			testLoadSnapshotFunctionDocString,
+		),
+	}
+
+	testType.Members = sema.GetMembersAsMap(members)
+	return testType
+}()
+
+var testTypeID = TestType.ID()
+var testStaticType interpreter.StaticType = interpreter.ConvertSemaToStaticType(TestType)
+var testDynamicType interpreter.DynamicType = interpreter.CompositeDynamicType{
+	StaticType: TestType,
+}
+
+const testValueDocString = `
+The standard library for writing tests
+`
+
+// NewTestValue returns the `Test` standard library value,
+// whose functions operate on the given test framework.
+//
+func NewTestValue(framework TestFramework) StandardLibraryValue {
+	return StandardLibraryValue{
+		Name:      TestTypeName,
+		Type:      TestType,
+		DocString: testValueDocString,
+		ValueFactory: func(_ *interpreter.Interpreter) interpreter.Value {
+			return newTestCompositeValue(framework)
+		},
+		Kind: common.DeclarationKindConstant,
+	}
+}
+
+func newTestCompositeValue(framework TestFramework) interpreter.Value {
+
+	fields := map[string]interpreter.Value{
+		testAssertFunctionName: interpreter.NewHostFunctionValue(
+			testAssertFunction,
+			testAssertFunctionType,
+		),
+		testExpectFunctionName: interpreter.NewHostFunctionValue(
+			testExpectFunction,
+			testExpectFunctionType,
+		),
+		testAssertErrorFunctionName: interpreter.NewHostFunctionValue(
+			testAssertErrorFunction,
+			testAssertErrorFunctionType,
+		),
+		testCreateAccountFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				address, err := framework.CreateAccount()
+				if err != nil {
+					panic(err)
+				}
+				return interpreter.NewAddressValue(address)
+			},
+			testCreateAccountFunctionType,
+		),
+		testDeployContractFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				name := invocation.Arguments[0].(*interpreter.StringValue).Str
+				code := invocation.Arguments[1].(*interpreter.StringValue).Str
+				account := invocation.Arguments[2].(interpreter.AddressValue)
+
+				err := framework.DeployContract(name, code, common.Address(account))
+				if err != nil {
+					panic(err)
+				}
+				return interpreter.VoidValue{}
+			},
+			testDeployContractFunctionType,
+		),
+		testExecuteScriptFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				code := invocation.Arguments[0].(*interpreter.StringValue).Str
+
+				result, err := framework.ExecuteScript(invocation.Interpreter, code)
+				if err != nil {
+					panic(err)
+				}
+				return result
+			},
+			testExecuteScriptFunctionType,
+		),
+		testExecuteTransactionFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				code := invocation.Arguments[0].(*interpreter.StringValue).Str
+				signersValue := invocation.Arguments[1].(*interpreter.ArrayValue)
+
+				signers := make([]common.Address, 0, signersValue.Count())
+				signersValue.Iterate(func(element interpreter.Value) (resume bool) {
+					signers = append(signers, common.Address(element.(interpreter.AddressValue)))
+					return true
+				})
+
+				err := framework.ExecuteTransaction(code, signers)
+				if err != nil {
+					panic(err)
+				}
+				return interpreter.VoidValue{}
+			},
+			testExecuteTransactionFunctionType,
+		),
+		testCreateSnapshotFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				name := invocation.Arguments[0].(*interpreter.StringValue).Str
+
+				err := framework.CreateSnapshot(name)
+				if err != nil {
+					panic(err)
+				}
+				return interpreter.VoidValue{}
+			},
+			testCreateSnapshotFunctionType,
+		),
+		testLoadSnapshotFunctionName: interpreter.NewHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				name := invocation.Arguments[0].(*interpreter.StringValue).Str
+
+				err := framework.LoadSnapshot(name)
+				if err != nil {
+					panic(err)
+				}
+				return interpreter.VoidValue{}
+			},
+			testLoadSnapshotFunctionType,
+		),
+	}
+
+	return interpreter.NewSimpleCompositeValue(
+		testTypeID,
+		testStaticType,
+		testDynamicType,
+		nil,
+		fields,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func testAssertFunction(invocation interpreter.Invocation) interpreter.Value {
+	result := invocation.Arguments[0].(interpreter.BoolValue)
+	if !result {
+		var message string
+		if len(invocation.Arguments) > 1 {
+			message = invocation.Arguments[1].(*interpreter.StringValue).Str
+		}
+		panic(AssertionError{
+			Message:       message,
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+	return interpreter.VoidValue{}
+}
+
+func testExpectFunction(invocation interpreter.Invocation) interpreter.Value {
+	value := invocation.Arguments[0]
+	expected := invocation.Arguments[1]
+
+	equatableValue, ok := value.(interpreter.EquatableValue)
+	if !ok || !equatableValue.Equal(invocation.Interpreter, invocation.GetLocationRange, expected) {
+		panic(AssertionError{
+			Message:       fmt.Sprintf("expected %s, got %s", expected, value),
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+	return interpreter.VoidValue{}
+}
+
+func testAssertErrorFunction(invocation interpreter.Invocation) interpreter.Value {
+	function := invocation.Arguments[0].(interpreter.FunctionValue)
+	errorMessage := invocation.Arguments[1].(*interpreter.StringValue).Str
+
+	_, err := invocation.Interpreter.InvokeFunction(
+		function,
+		interpreter.Invocation{
+			GetLocationRange: invocation.GetLocationRange,
+			Interpreter:      invocation.Interpreter,
+		},
+	)
+	if err == nil {
+		panic(AssertionError{
+			Message:       fmt.Sprintf("expected error containing %q, got none", errorMessage),
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+
+	if !strings.Contains(err.Error(), errorMessage) {
+		panic(AssertionError{
+			Message:       fmt.Sprintf("expected error containing %q, got %q", errorMessage, err.Error()),
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+
+	return interpreter.VoidValue{}
+}
@@ -25,9 +25,10 @@ import (
 )
 
 type StandardLibraryType struct {
-	Name string
-	Type sema.Type
-	Kind common.DeclarationKind
+	Name      string
+	Type      sema.Type
+	Kind      common.DeclarationKind
+	Available func(common.Location) bool
 }
 
 func (t StandardLibraryType) TypeDeclarationName() string {
@@ -46,6 +47,13 @@ func (StandardLibraryType) TypeDeclarationPosition() ast.Position {
 	return ast.Position{}
 }
 
+func (t StandardLibraryType) TypeDeclarationAvailable(location common.Location) bool {
+	if t.Available == nil {
+		return true
+	}
+	return t.Available(location)
+}
+
 // StandardLibraryTypes
 
 type StandardLibraryTypes []StandardLibraryType
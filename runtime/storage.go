@@ -19,10 +19,12 @@
 package runtime
 
 import (
+	"bytes"
 	"fmt"
 	"runtime"
 	"sort"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence/runtime/common"
@@ -31,24 +33,99 @@ import (
 
 const StorageDomainContract = "contract"
 
+// StorageDomains are the domains of account storage.
+//
+// The storage map of a domain is stored in the account register
+// which has the domain as its key.
+//
+var StorageDomains = func() []string {
+	domains := make([]string, 0, len(common.AllPathDomains)+1)
+	for _, pathDomain := range common.AllPathDomains {
+		domains = append(domains, pathDomain.Identifier())
+	}
+	return append(domains, StorageDomainContract)
+}()
+
+// StorageRegisterDomain returns the storage domain whose storage map
+// is stored in the account register with the given key.
+//
+// The result is false for all other registers, e.g. the registers of atree slabs,
+// so embedders can group registers by domain without parsing the key.
+//
+func StorageRegisterDomain(key []byte) (domain string, ok bool) {
+	for _, domain := range StorageDomains {
+		if string(key) == domain {
+			return domain, true
+		}
+	}
+	return "", false
+}
+
 type Storage struct {
 	*atree.PersistentSlabStorage
-	writes          map[interpreter.StorageKey]atree.StorageIndex
-	storageMaps     map[interpreter.StorageKey]*interpreter.StorageMap
-	contractUpdates map[interpreter.StorageKey]*interpreter.CompositeValue
-	Ledger          atree.Ledger
+	writes      map[interpreter.StorageKey]atree.StorageIndex
+	storageMaps map[interpreter.StorageKey]*interpreter.StorageMap
+	// missingStorageMaps are the keys of the storage maps
+	// which were looked up, but do not exist in the ledger,
+	// so repeated lookups, e.g. of the type fingerprint domain, do not read the ledger again
+	missingStorageMaps map[interpreter.StorageKey]struct{}
+	contractUpdates    map[interpreter.StorageKey]*interpreter.CompositeValue
+	Ledger             atree.Ledger
+	// writtenAddresses are the addresses of all accounts
+	// which registers were written to in the ledger
+	writtenAddresses map[common.Address]struct{}
+	// mutationJournal is an optional journal,
+	// in which the updates of contracts are recorded, see Context.MutationJournal
+	mutationJournal *interpreter.MutationJournal
 }
 
 var _ atree.SlabStorage = &Storage{}
 var _ interpreter.Storage = &Storage{}
 
 func NewStorage(ledger atree.Ledger) *Storage {
+	return newStorage(
+		ledger,
+		interpreter.CBORDecMode,
+		interpreter.DecodeStorable,
+	)
+}
+
+// NewStorageWithDecodingLimits returns a new storage
+// which enforces the given limits when decoding stored values.
+//
+// An error is returned if the limits are outside the range supported by the CBOR decoder.
+//
+func NewStorageWithDecodingLimits(ledger atree.Ledger, limits common.DecodingLimits) (*Storage, error) {
+	decMode, err := interpreter.NewCBORDecMode(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStorage(
+		ledger,
+		decMode,
+		interpreter.NewStorableDecoder(limits),
+	), nil
+}
+
+func newStorage(
+	ledger atree.Ledger,
+	decMode cbor.DecMode,
+	decodeStorable atree.StorableDecoder,
+) *Storage {
+	writtenAddresses := map[common.Address]struct{}{}
+
+	ledger = writeRecordingLedger{
+		Ledger:           ledger,
+		writtenAddresses: writtenAddresses,
+	}
+
 	ledgerStorage := atree.NewLedgerBaseStorage(ledger)
 	persistentSlabStorage := atree.NewPersistentSlabStorage(
 		ledgerStorage,
 		interpreter.CBOREncMode,
-		interpreter.CBORDecMode,
-		interpreter.DecodeStorable,
+		decMode,
+		decodeStorable,
 		interpreter.DecodeTypeInfo,
 	)
 	return &Storage{
@@ -56,13 +133,53 @@ func NewStorage(ledger atree.Ledger) *Storage {
 		PersistentSlabStorage: persistentSlabStorage,
 		writes:                map[interpreter.StorageKey]atree.StorageIndex{},
 		storageMaps:           map[interpreter.StorageKey]*interpreter.StorageMap{},
+		missingStorageMaps:    map[interpreter.StorageKey]struct{}{},
 		contractUpdates:       map[interpreter.StorageKey]*interpreter.CompositeValue{},
+		writtenAddresses:      writtenAddresses,
+	}
+}
+
+// writeRecordingLedger is a ledger which records the owners of all written registers
+//
+type writeRecordingLedger struct {
+	atree.Ledger
+	writtenAddresses map[common.Address]struct{}
+}
+
+func (l writeRecordingLedger) SetValue(owner, key, value []byte) error {
+	l.writtenAddresses[common.BytesToAddress(owner)] = struct{}{}
+	return l.Ledger.SetValue(owner, key, value)
+}
+
+// WrittenAddresses returns the addresses of all accounts
+// which registers were written to in the ledger, sorted in lexicographic order
+//
+func (s *Storage) WrittenAddresses() []common.Address {
+	addresses := make([]common.Address, 0, len(s.writtenAddresses))
+
+	// NOTE: ranging over maps is safe (deterministic),
+	// if it is side effect free and the keys are sorted afterwards
+
+	for address := range s.writtenAddresses { //nolint:maprangecheck
+		addresses = append(addresses, address)
 	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	return addresses
 }
 
 const storageIndexLength = 8
 
-func (s *Storage) GetStorageMap(address common.Address, domain string) (storageMap *interpreter.StorageMap) {
+func (s *Storage) GetStorageMap(
+	address common.Address,
+	domain string,
+	createIfNotExists bool,
+) (
+	storageMap *interpreter.StorageMap,
+) {
 	key := interpreter.StorageKey{
 		Address: address,
 		Key:     domain,
@@ -71,6 +188,21 @@ func (s *Storage) GetStorageMap(address common.Address, domain string) (storageM
 	storageMap = s.storageMaps[key]
 	if storageMap == nil {
 
+		// The storage map is known to not exist,
+		// so there is no need to read the ledger again
+
+		if _, ok := s.missingStorageMaps[key]; ok {
+			if !createIfNotExists {
+				return nil
+			}
+
+			storageMap = s.storeNewStorageMap(atree.Address(address), domain)
+			s.storageMaps[key] = storageMap
+			delete(s.missingStorageMaps, key)
+
+			return storageMap
+		}
+
 		// Load data through the runtime interface
 
 		var data []byte
@@ -100,8 +232,11 @@ func (s *Storage) GetStorageMap(address common.Address, domain string) (storageM
 			var storageIndex atree.StorageIndex
 			copy(storageIndex[:], data[:])
 			storageMap = s.loadExistingStorageMap(atreeAddress, storageIndex)
-		} else {
+		} else if createIfNotExists {
 			storageMap = s.storeNewStorageMap(atreeAddress, domain)
+		} else {
+			s.missingStorageMaps[key] = struct{}{}
+			return nil
 		}
 
 		s.storageMaps[key] = storageMap
@@ -149,6 +284,14 @@ func (s *Storage) recordContractUpdate(
 	// otherwise the removal write is lost
 
 	s.contractUpdates[key] = contractValue
+
+	if s.mutationJournal != nil {
+		s.mutationJournal.RecordWrite(interpreter.JournalPath{
+			Address:    address,
+			Domain:     StorageDomainContract,
+			Identifier: name,
+		})
+	}
 }
 
 type ContractUpdate struct {
@@ -213,7 +356,7 @@ func (s *Storage) writeContractUpdate(
 	key interpreter.StorageKey,
 	contractValue *interpreter.CompositeValue,
 ) {
-	storageMap := s.GetStorageMap(key.Address, StorageDomainContract)
+	storageMap := s.GetStorageMap(key.Address, StorageDomainContract, true)
 	// NOTE: pass nil instead of allocating a Value-typed  interface that points to nil
 	if contractValue == nil {
 		storageMap.WriteValue(inter, key.Key, nil)
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1573,6 +1574,221 @@ func TestRuntimeStorageReferenceCast(t *testing.T) {
 	require.Contains(t, err.Error(), "unexpectedly found non-`&Test.R` while force-casting value")
 }
 
+func TestRuntimeStorageStructDictionaryKeys(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	signerAddress := common.BytesToAddress([]byte{0x42})
+
+	deployTx := utils.DeploymentTransaction("Test", []byte(`
+      pub contract Test {
+
+          pub struct Key {
+              pub let name: String
+              pub let number: Int
+
+              init(name: String, number: Int) {
+                  self.name = name
+                  self.number = number
+              }
+          }
+      }
+    `))
+
+	accountCodes := map[common.LocationID][]byte{}
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signerAddress}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			code = accountCodes[location.ID()]
+			return code, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	// Deploy contract
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: deployTx,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Store a dictionary with structure keys
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              import Test from 0x42
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let xs: {Test.Key: Int} = {
+                          Test.Key(name: "a", number: 1): 1,
+                          Test.Key(name: "b", number: 2): 2
+                      }
+                      signer.save(xs, to: /storage/xs)
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Load the dictionary and look up the keys
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              import Test from 0x42
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let xs = signer.load<{Test.Key: Int}>(from: /storage/xs)!
+                      assert(xs.length == 2)
+                      assert(xs[Test.Key(name: "a", number: 1)] == 1)
+                      assert(xs[Test.Key(name: "b", number: 2)] == 2)
+                      assert(xs[Test.Key(name: "a", number: 2)] == nil)
+
+                      for key in xs.keys {
+                          assert(xs[key] == key.number)
+                      }
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestRuntimeStorageDictionaryIterationOrder(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	signerAddress := common.BytesToAddress([]byte{0x42})
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signerAddress}, nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code string) {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	// Store a dictionary large enough to span multiple slabs,
+	// and the order of its keys at the time it was created
+
+	executeTransaction(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let dict: {String: Int} = {}
+              var i = 0
+              while i < 200 {
+                  dict["key_".concat(i.toString())] = i
+                  i = i + 1
+              }
+              signer.save(dict.keys, to: /storage/keys)
+              signer.save(dict, to: /storage/dict)
+          }
+      }
+    `)
+
+	// The iteration order survives decoding and copying,
+	// and copies can be stored again
+
+	executeTransaction(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let keys = signer.copy<[String]>(from: /storage/keys)!
+              let dict = signer.load<{String: Int}>(from: /storage/dict)!
+              let copy = dict
+
+              assert(dict.keys.length == keys.length)
+              assert(copy.keys.length == keys.length)
+
+              var i = 0
+              for key in dict.keys {
+                  assert(key == keys[i])
+                  assert(copy.keys[i] == keys[i])
+                  i = i + 1
+              }
+
+              signer.save(copy, to: /storage/copy)
+          }
+      }
+    `)
+
+	// The iteration order survives re-encoding
+
+	executeTransaction(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let keys = signer.copy<[String]>(from: /storage/keys)!
+              let copy = signer.borrow<&{String: Int}>(from: /storage/copy)!
+
+              assert(copy.keys.length == keys.length)
+
+              var i = 0
+              for key in copy.keys {
+                  assert(key == keys[i])
+                  i = i + 1
+              }
+          }
+      }
+    `)
+}
+
 func TestRuntimeStorageNonStorable(t *testing.T) {
 
 	t.Parallel()
@@ -2268,3 +2484,861 @@ transaction {
 	)
 	require.NoError(t, err)
 }
+
+const testStorageMoveCollectionContract = `
+  pub contract Test {
+
+      pub resource NFT {
+          pub let id: UInt64
+
+          init(id: UInt64) {
+              self.id = id
+          }
+      }
+
+      pub resource Collection {
+          pub var ownedNFTs: @{UInt64: NFT}
+
+          pub fun deposit(token: @NFT) {
+              self.ownedNFTs[token.id] <-! token
+          }
+
+          pub fun getIDs(): [UInt64] {
+              return self.ownedNFTs.keys
+          }
+
+          init() {
+              self.ownedNFTs <- {}
+          }
+
+          destroy() {
+              destroy self.ownedNFTs
+          }
+      }
+
+      pub fun createNFT(id: UInt64): @NFT {
+          return <-create NFT(id: id)
+      }
+
+      pub fun createCollection(size: UInt64): @Collection {
+          let collection <- create Collection()
+          var id: UInt64 = 0
+          while id < size {
+              collection.deposit(token: <-create NFT(id: id))
+              id = id + 1
+          }
+          return <-collection
+      }
+  }
+`
+
+func TestRuntimeStorageMoveResourceWithinAccount(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	ledger := newTestLedger(nil, nil)
+
+	accountCodes := map[common.LocationID][]byte{}
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: ledger,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			code = accountCodes[location.ID()]
+			return code, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code string) {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	nonEmptyKeyCount := func() int {
+		var count int
+		for _, data := range ledger.storedValues {
+			if len(data) > 0 {
+				count++
+			}
+		}
+		return count
+	}
+
+	deployTx := utils.DeploymentTransaction("Test", []byte(testStorageMoveCollectionContract))
+	executeTransaction(string(deployTx))
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(<-Test.createCollection(size: 100), to: /storage/a)
+          }
+      }
+    `)
+
+	keyCountBeforeMove := nonEmptyKeyCount()
+
+	// Moving the collection to another path in the same account
+	// neither copies nor leaks any slabs
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection <- signer.load<@Test.Collection>(from: /storage/a)!
+              let moved <- collection
+              signer.save(<-moved, to: /storage/b)
+          }
+      }
+    `)
+
+	assert.Equal(t, keyCountBeforeMove, nonEmptyKeyCount())
+
+	// Mutating the loaded collection before storing it again
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection <- signer.load<@Test.Collection>(from: /storage/b)!
+              log(collection.owner?.address)
+              collection.deposit(token: <-Test.createNFT(id: 100))
+              signer.save(<-collection, to: /storage/c)
+          }
+      }
+    `)
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection = signer.borrow<&Test.Collection>(from: /storage/c)!
+              log(collection.owner?.address)
+              log(collection.getIDs().length)
+          }
+      }
+    `)
+
+	// Destroying the loaded collection removes all its slabs
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection <- signer.load<@Test.Collection>(from: /storage/c)!
+              destroy collection
+          }
+      }
+    `)
+
+	assert.Equal(t,
+		[]string{
+			"nil",
+			"0x0000000000000001",
+			"101",
+		},
+		loggedMessages,
+	)
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(<-Test.createCollection(size: 0), to: /storage/a)
+          }
+      }
+    `)
+
+	emptyCollectionKeyCount := nonEmptyKeyCount()
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              destroy signer.load<@Test.Collection>(from: /storage/a)!
+          }
+      }
+    `)
+
+	assert.Less(t, nonEmptyKeyCount(), emptyCollectionKeyCount)
+}
+
+func TestRuntimeStorageReadResourceMovedWithinAccount(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	accountCodes := map[common.LocationID][]byte{}
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			code = accountCodes[location.ID()]
+			return code, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code string) {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	deployTx := utils.DeploymentTransaction("Test", []byte(testStorageMoveCollectionContract))
+	executeTransaction(string(deployTx))
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(<-Test.createCollection(size: 2), to: /storage/a)
+          }
+      }
+    `)
+
+	// Iterating over the fields of the loaded collection, e.g. when logging it,
+	// before storing it again
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection <- signer.load<@Test.Collection>(from: /storage/a)!
+              log(&collection as &Test.Collection)
+              signer.save(<-collection, to: /storage/b)
+          }
+      }
+    `)
+
+	executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection = signer.borrow<&Test.Collection>(from: /storage/b)!
+              log(collection.owner?.address)
+              log(collection.getIDs().length)
+          }
+      }
+    `)
+
+	require.Len(t, loggedMessages, 3)
+	assert.Equal(t, []string{"0x0000000000000001", "2"}, loggedMessages[1:])
+
+	// Exporting the loaded collection
+
+	value, err := runtime.ExecuteScript(
+		Script{
+			Source: []byte(`
+              import Test from 0x1
+
+              pub fun main(): @Test.Collection {
+                  return <-getAuthAccount(0x1).load<@Test.Collection>(from: /storage/b)!
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	collection := value.(cadence.Resource)
+	require.Equal(t, "ownedNFTs", collection.ResourceType.Fields[1].Identifier)
+	require.Len(t, collection.Fields[1].(cadence.Dictionary).Pairs, 2)
+}
+
+func BenchmarkRuntimeStorageMoveResourceWithinAccount(b *testing.B) {
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	var accountCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(b),
+		getAccountContractCode: func(_ Address, _ string) (bytes []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code []byte) {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: code,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(b, err)
+	}
+
+	executeTransaction(utils.DeploymentTransaction("Test", []byte(testStorageMoveCollectionContract)))
+
+	executeTransaction([]byte(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(<-Test.createCollection(size: 1000), to: /storage/a)
+          }
+      }
+    `))
+
+	moveTx := []byte(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let collection <- signer.load<@Test.Collection>(from: /storage/a)!
+              signer.save(<-collection, to: /storage/b)
+              signer.save(<-signer.load<@Test.Collection>(from: /storage/b)!, to: /storage/a)
+          }
+      }
+    `)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		executeTransaction(moveTx)
+	}
+}
+
+func TestRuntimeStorageHydrateAll(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	var readCount int
+
+	ledger := newTestLedger(
+		func(_, _, _ []byte) {
+			readCount++
+		},
+		nil,
+	)
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: ledger,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+	}
+
+	// Store a nested array which is large enough
+	// that it is split into many slabs
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let values: [[String]] = []
+                      var i = 0
+                      while i < 20 {
+                          let inner: [String] = []
+                          var j = 0
+                          while j < 20 {
+                              inner.append("value number ".concat(j.toString()))
+                              j = j + 1
+                          }
+                          values.append(inner)
+                          i = i + 1
+                      }
+                      signer.save(values, to: /storage/values)
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  newTransactionLocationGenerator()(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Read the stored value using new storage,
+	// so the contents of the value are loaded lazily
+
+	storage := NewStorage(ledger)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		interpreter.WithStorage(storage),
+	)
+	require.NoError(t, err)
+
+	value := storage.
+		GetStorageMap(address, common.PathDomainStorage.Identifier(), true).
+		ReadValue("values")
+	require.NotNil(t, value)
+
+	readCountBeforeHydration := readCount
+
+	interpreter.HydrateAll(inter, value)
+
+	readCountAfterHydration := readCount
+	assert.Greater(t, readCountAfterHydration, readCountBeforeHydration)
+
+	// Hydrating again does not read from the ledger,
+	// as all contents are already loaded
+
+	interpreter.HydrateAll(inter, value)
+
+	assert.Equal(t, readCountAfterHydration, readCount)
+}
+
+func TestRuntimeStorageRegisterDomain(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	tx := []byte(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(1, to: /storage/one)
+              signer.link<&Int>(/public/one, target: /storage/one)
+          }
+       }
+    `)
+
+	domains := map[string]int{}
+	var otherWrites int
+
+	onWrite := func(_, key, _ []byte) {
+		domain, ok := StorageRegisterDomain(key)
+		if ok {
+			domains[domain]++
+		} else {
+			otherWrites++
+		}
+	}
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, onWrite),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: tx,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		map[string]int{
+			"storage": 1,
+			"public":  1,
+		},
+		domains,
+	)
+
+	// The storage maps of the domains are stored in slabs
+	assert.Equal(t, 2, otherWrites)
+
+	_, ok := StorageRegisterDomain([]byte{'$', 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1})
+	assert.False(t, ok)
+
+	domain, ok := StorageRegisterDomain([]byte(StorageDomainContract))
+	assert.True(t, ok)
+	assert.Equal(t, StorageDomainContract, domain)
+}
+
+func TestRuntimeStorageCapacityCheck(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	tx := []byte(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save("a string which uses some storage", to: /storage/string)
+          }
+       }
+    `)
+
+	test := func(capacityCheckEnabled bool, storageCapacity uint64) error {
+
+		runtime := NewInterpreterRuntime(
+			WithStorageCapacityCheckEnabled(capacityCheckEnabled),
+		)
+
+		registerSizes := map[string]uint64{}
+
+		onWrite := func(owner, key, value []byte) {
+			registerSizes[string(owner)+string(key)] = uint64(len(value))
+		}
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, onWrite),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			getStorageUsed: func(_ Address) (uint64, error) {
+				var storageUsed uint64
+				for _, size := range registerSizes {
+					storageUsed += size
+				}
+				return storageUsed, nil
+			},
+			getStorageCapacity: func(_ Address) (uint64, error) {
+				return storageCapacity, nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: tx,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+	}
+
+	t.Run("enabled, sufficient capacity", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(true, 1000)
+		require.NoError(t, err)
+	})
+
+	t.Run("enabled, insufficient capacity", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(true, 10)
+		require.Error(t, err)
+
+		var storageCapacityExceededError StorageCapacityExceededError
+		require.ErrorAs(t, err, &storageCapacityExceededError)
+
+		assert.Equal(t, address, storageCapacityExceededError.Address)
+		assert.Equal(t, uint64(10), storageCapacityExceededError.StorageCapacity)
+		assert.Greater(t, storageCapacityExceededError.StorageUsed, uint64(10))
+	})
+
+	t.Run("disabled, insufficient capacity", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(false, 10)
+		require.NoError(t, err)
+	})
+}
+
+func TestRuntimeLinkValidation(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	test := func(linkValidationEnabled bool, borrowType string) error {
+
+		runtime := NewInterpreterRuntime(
+			WithLinkValidationEnabled(linkValidationEnabled),
+		)
+
+		tx := []byte(fmt.Sprintf(
+			`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.link<&Int>(/public/before, target: /storage/value)
+                      signer.save(1, to: /storage/value)
+                      signer.link<%s>(/public/after, target: /storage/value)
+                      signer.link<&Int>(/private/value, target: /storage/value)
+                      signer.link<%[1]s>(/public/chained, target: /private/value)
+                  }
+               }
+            `,
+			borrowType,
+		))
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: tx,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+	}
+
+	t.Run("enabled, valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(true, "&Int")
+		require.NoError(t, err)
+	})
+
+	t.Run("enabled, invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(true, "&String")
+		require.Error(t, err)
+
+		var linkErr interpreter.InvalidLinkTargetError
+		require.ErrorAs(t, err, &linkErr)
+
+		assert.Equal(t, address, linkErr.Address)
+		assert.Equal(t,
+			interpreter.PathValue{
+				Domain:     common.PathDomainStorage,
+				Identifier: "value",
+			},
+			linkErr.TargetPath,
+		)
+		assert.Equal(t, interpreter.PrimitiveStaticTypeInt, linkErr.TargetType)
+	})
+
+	t.Run("disabled, invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(false, "&String")
+		require.NoError(t, err)
+	})
+}
+
+func TestRuntimeStorageDecodingLimits(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	ledger := newTestLedger(nil, nil)
+
+	newRuntimeInterface := func() *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: ledger,
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := newTestInterpreterRuntime().ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let numbers: [Int] = []
+                      var i = 0
+                      while i < 20 {
+                          numbers.append(i)
+                          i = i + 1
+                      }
+                      signer.save(numbers, to: /storage/numbers)
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: newRuntimeInterface(),
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	readTx := []byte(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              log(signer.borrow<&[Int]>(from: /storage/numbers)!.length)
+          }
+      }
+    `)
+
+	t.Run("default limits", func(t *testing.T) {
+
+		runtimeInterface := newRuntimeInterface()
+
+		var loggedMessages []string
+		runtimeInterface.log = func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		}
+
+		err := newTestInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: readTx,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"20"}, loggedMessages)
+	})
+
+	t.Run("exceeded array length", func(t *testing.T) {
+
+		runtime := newTestInterpreterRuntime(
+			WithDecodingLimits(common.DecodingLimits{
+				MaxNestedLevels:  16,
+				MaxArrayElements: 16,
+				MaxValueCount:    1024,
+			}),
+		)
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: readTx,
+			},
+			Context{
+				Interface: newRuntimeInterface(),
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.Error(t, err)
+
+		var arrayElementsErr *cbor.MaxArrayElementsError
+		require.ErrorAs(t, err, &arrayElementsErr)
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+
+		assert.Panics(t, func() {
+			newTestInterpreterRuntime(
+				WithDecodingLimits(common.DecodingLimits{
+					MaxNestedLevels:  1,
+					MaxArrayElements: 16,
+					MaxValueCount:    1024,
+				}),
+			)
+		})
+	})
+}
@@ -0,0 +1,156 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// StoredValueStatistics are statistics of a value stored in an account
+//
+type StoredValueStatistics struct {
+	Path cadence.Path
+	Type cadence.Type
+	// Size is the encoded size of the value in bytes,
+	// including all slabs in which the value is stored
+	Size uint64
+	// Deferred is true if the value is (partially) stored in separate slabs,
+	// which are only loaded when the value is accessed
+	Deferred bool
+}
+
+func (r *interpreterRuntime) AccountStorageStatistics(
+	address common.Address,
+	context Context,
+) (
+	result []StoredValueStatistics,
+	err error,
+) {
+	_, err = r.executeNonProgram(
+		func(inter *interpreter.Interpreter) (interpreter.Value, error) {
+			result = accountStorageStatistics(inter, address)
+			return nil, nil
+		},
+		context,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func accountStorageStatistics(inter *interpreter.Interpreter, address common.Address) []StoredValueStatistics {
+	var result []StoredValueStatistics
+
+	exportedTypes := map[sema.TypeID]cadence.Type{}
+
+	for _, domain := range common.AllPathDomains {
+		storageMap := inter.Storage.GetStorageMap(address, domain.Identifier(), true)
+
+		for _, statistics := range storageMap.ValueStatistics() {
+
+			var exportedType cadence.Type
+			if statistics.Type != nil {
+				semaType := inter.MustConvertStaticToSemaType(statistics.Type)
+				exportedType = ExportType(semaType, exportedTypes)
+			}
+
+			result = append(
+				result,
+				StoredValueStatistics{
+					Path: cadence.Path{
+						Domain:     domain.Identifier(),
+						Identifier: statistics.Key,
+					},
+					Type:     exportedType,
+					Size:     statistics.Size,
+					Deferred: statistics.Deferred,
+				},
+			)
+		}
+	}
+
+	return result
+}
+
+func (r *interpreterRuntime) CheckAccountStorageDecoding(address common.Address, context Context) error {
+	_, err := r.executeNonProgram(
+		func(inter *interpreter.Interpreter) (interpreter.Value, error) {
+			return nil, checkAccountStorageDecoding(inter, address)
+		},
+		context,
+	)
+	return err
+}
+
+func checkAccountStorageDecoding(inter *interpreter.Interpreter, address common.Address) error {
+	for _, domain := range common.AllPathDomains {
+		err := checkStorageMapDecoding(inter, address, domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkStorageMapDecoding(
+	inter *interpreter.Interpreter,
+	address common.Address,
+	domain common.PathDomain,
+) (err error) {
+
+	domainKey := "/" + domain.Identifier()
+
+	// Loading the storage map panics if its root slab fails to decode
+
+	defer func() {
+		if r := recover(); r != nil {
+			recoveredErr, ok := r.(error)
+			if !ok {
+				recoveredErr = interpreter.ExternalError{Recovered: r}
+			}
+			err = interpreter.ValueDecodingError{
+				Owner: address,
+				Key:   domainKey,
+				Err:   recoveredErr,
+			}
+		}
+	}()
+
+	storageMap := inter.Storage.GetStorageMap(address, domain.Identifier(), true)
+
+	err = storageMap.CheckDecoding(inter)
+	if decodingErr, ok := err.(interpreter.ValueDecodingError); ok {
+		if decodingErr.Key != "" {
+			decodingErr.Key = fmt.Sprintf("%s/%s", domainKey, decodingErr.Key)
+		} else {
+			decodingErr.Key = domainKey
+		}
+		return decodingErr
+	}
+
+	return err
+}
@@ -0,0 +1,234 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeAccountStorageStatistics(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	signer := common.BytesToAddress([]byte{0x42})
+
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signer}, nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	// Store values and link a capability
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                 prepare(signer: AuthAccount) {
+                     signer.save(42, to: /storage/int)
+                     signer.save(["a", "b", "c"], to: /storage/array)
+                     signer.link<&Int>(/public/int, target: /storage/int)
+                 }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	t.Run("Go API", func(t *testing.T) {
+
+		statistics, err := runtime.AccountStorageStatistics(
+			signer,
+			Context{
+				// NOTE: no location
+				Interface: runtimeInterface,
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, statistics, 3)
+
+		statisticsByPath := map[cadence.Path]StoredValueStatistics{}
+		for _, valueStatistics := range statistics {
+			statisticsByPath[valueStatistics.Path] = valueStatistics
+		}
+
+		intStatistics := statisticsByPath[cadence.Path{Domain: "storage", Identifier: "int"}]
+		assert.Equal(t, cadence.IntType{}, intStatistics.Type)
+		assert.False(t, intStatistics.Deferred)
+		assert.NotZero(t, intStatistics.Size)
+
+		arrayStatistics := statisticsByPath[cadence.Path{Domain: "storage", Identifier: "array"}]
+		assert.Equal(t,
+			cadence.VariableSizedArrayType{
+				ElementType: cadence.StringType{},
+			},
+			arrayStatistics.Type,
+		)
+		assert.True(t, arrayStatistics.Deferred)
+		assert.Greater(t, arrayStatistics.Size, intStatistics.Size)
+
+		linkStatistics := statisticsByPath[cadence.Path{Domain: "public", Identifier: "int"}]
+		assert.False(t, linkStatistics.Deferred)
+		assert.NotZero(t, linkStatistics.Size)
+	})
+
+	t.Run("storageUsedByPath", func(t *testing.T) {
+
+		loggedMessages = nil
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                     prepare(signer: AuthAccount) {
+                         let storageUsed = signer.storageUsedByPath()
+                         log(storageUsed.length)
+                         log(storageUsed[/storage/array]! > storageUsed[/storage/int]!)
+                         log(storageUsed[/storage/other])
+                     }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]string{
+				"3",
+				"true",
+				"nil",
+			},
+			loggedMessages,
+		)
+	})
+}
+
+func TestRuntimeCheckAccountStorageDecoding(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	signer := common.BytesToAddress([]byte{0x42})
+
+	ledger := newTestLedger(nil, nil)
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: ledger,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signer}, nil
+		},
+	}
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                 prepare(signer: AuthAccount) {
+                     signer.save([{"a": [1, 2]}], to: /storage/array)
+                 }
+              }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  utils.TestLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	context := Context{
+		// NOTE: no location
+		Interface: runtimeInterface,
+	}
+
+	require.NoError(t, runtime.CheckAccountStorageDecoding(signer, context))
+
+	// Corrupt each slab in turn, and check that the failing value is located
+
+	var slabKeys []string
+	for key := range ledger.storedValues {
+		if strings.Contains(key, "|$") {
+			slabKeys = append(slabKeys, key)
+		}
+	}
+
+	locations := map[string]bool{}
+
+	for _, key := range slabKeys {
+		data := ledger.storedValues[key]
+		ledger.storedValues[key] = []byte{0xff}
+
+		err := runtime.CheckAccountStorageDecoding(signer, context)
+		require.Error(t, err)
+
+		var decodingErr interpreter.ValueDecodingError
+		require.ErrorAs(t, err, &decodingErr)
+
+		assert.Equal(t, signer, decodingErr.Owner)
+		if decodingErr.Key != "/storage" {
+			assert.NotEqual(t, atree.StorageIDUndefined, decodingErr.StorageID)
+		}
+
+		locations[decodingErr.Key+decodingErr.Path] = true
+
+		ledger.storedValues[key] = data
+	}
+
+	assert.Equal(t,
+		map[string]bool{
+			"/storage":               true,
+			"/storage/array":         true,
+			"/storage/array[0]":      true,
+			`/storage/array[0]["a"]`: true,
+		},
+		locations,
+	)
+
+	require.NoError(t, runtime.CheckAccountStorageDecoding(signer, context))
+}
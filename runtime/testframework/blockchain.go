@@ -0,0 +1,139 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testframework
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// Blockchain is an in-memory blockchain,
+// which executes scripts and transactions using the Cadence runtime.
+//
+// It implements stdlib.TestFramework, so it can be used as the backend
+// of the `Test` standard library value.
+//
+type Blockchain struct {
+	runtime        runtime.Runtime
+	environment    *inmemory.Interface
+	executionCount uint64
+	snapshots      map[string]*inmemory.Snapshot
+}
+
+var _ stdlib.TestFramework = &Blockchain{}
+
+func NewBlockchain() *Blockchain {
+	return &Blockchain{
+		runtime:     runtime.NewInterpreterRuntime(),
+		environment: inmemory.NewInterface(),
+		snapshots:   map[string]*inmemory.Snapshot{},
+	}
+}
+
+// Logs returns the messages logged by scripts and transactions
+//
+func (b *Blockchain) Logs() []string {
+	return b.environment.Logs()
+}
+
+func (b *Blockchain) CreateAccount() (common.Address, error) {
+	return b.environment.CreateAccount(common.Address{})
+}
+
+func (b *Blockchain) DeployContract(name string, code string, account common.Address) error {
+	transaction := fmt.Sprintf(
+		`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.contracts.add(name: "%s", code: "%s".decodeHex())
+              }
+          }
+        `,
+		name,
+		hex.EncodeToString([]byte(code)),
+	)
+
+	return b.ExecuteTransaction(transaction, []common.Address{account})
+}
+
+func (b *Blockchain) ExecuteScript(inter *interpreter.Interpreter, code string) (interpreter.Value, error) {
+	result, err := b.runtime.ExecuteScript(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: b.environment,
+			Location:  common.ScriptLocation(b.nextExecutionID()),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.ImportValue(inter, result, sema.AnyStructType)
+}
+
+func (b *Blockchain) ExecuteTransaction(code string, signers []common.Address) error {
+	b.environment.SetSigningAccounts(signers)
+	defer b.environment.SetSigningAccounts(nil)
+
+	return b.runtime.ExecuteTransaction(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: b.environment,
+			Location:  common.TransactionLocation(b.nextExecutionID()),
+		},
+	)
+}
+
+// CreateSnapshot records the current state of the blockchain under the given name.
+// An existing snapshot with the same name is replaced.
+//
+func (b *Blockchain) CreateSnapshot(name string) error {
+	b.snapshots[name] = b.environment.Snapshot()
+	return nil
+}
+
+// LoadSnapshot resets the state of the blockchain to the snapshot with the given name
+//
+func (b *Blockchain) LoadSnapshot(name string) error {
+	snapshot, ok := b.snapshots[name]
+	if !ok {
+		return fmt.Errorf("cannot find snapshot `%s`", name)
+	}
+	b.environment.Restore(snapshot)
+	return nil
+}
+
+func (b *Blockchain) nextExecutionID() []byte {
+	b.executionCount++
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], b.executionCount)
+	return id[:]
+}
@@ -0,0 +1,188 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testframework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// testFunctionPrefix is the prefix of the names of the functions
+// in a test script which are run as tests
+//
+const testFunctionPrefix = "test"
+
+// TestScriptLocation is the location of test scripts
+//
+const TestScriptLocation = common.StringLocation("test")
+
+// Result is the result of running a single test function
+//
+type Result struct {
+	TestName string
+	Error    error
+}
+
+// Succeeded returns true if the test ran without an error
+//
+func (r Result) Succeeded() bool {
+	return r.Error == nil
+}
+
+type Results []Result
+
+// TestRunner runs the tests declared in test scripts.
+//
+// Each test script is checked and interpreted once,
+// and its test functions are run in declaration order
+// against a fresh in-memory blockchain, which is shared by all tests of the script.
+//
+type TestRunner struct{}
+
+func NewTestRunner() *TestRunner {
+	return &TestRunner{}
+}
+
+// RunTests runs all test functions of the given script,
+// i.e. all global functions whose name starts with `test`.
+//
+// The returned error is only non-nil if the script itself is invalid,
+// failing tests are reported through the results.
+//
+func (r *TestRunner) RunTests(script string) (Results, error) {
+	inter, testFunctions, err := r.prepare(script)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(Results, 0, len(testFunctions))
+	for _, testFunction := range testFunctions {
+		results = append(results, runTestFunction(inter, testFunction))
+	}
+
+	return results, nil
+}
+
+// RunTest runs the test function with the given name in the given script
+//
+func (r *TestRunner) RunTest(script string, funcName string) (*Result, error) {
+	inter, testFunctions, err := r.prepare(script)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, testFunction := range testFunctions {
+		if testFunction != funcName {
+			continue
+		}
+		result := runTestFunction(inter, testFunction)
+		return &result, nil
+	}
+
+	return nil, fmt.Errorf("cannot find test function `%s`", funcName)
+}
+
+func runTestFunction(inter *interpreter.Interpreter, name string) Result {
+	_, err := inter.Invoke(name)
+	return Result{
+		TestName: name,
+		Error:    err,
+	}
+}
+
+// prepare checks and interprets the given test script,
+// and returns the names of its test functions in declaration order
+//
+func (r *TestRunner) prepare(script string) (*interpreter.Interpreter, []string, error) {
+
+	program, err := parser2.ParseProgram(script)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	testValue := stdlib.NewTestValue(NewBlockchain())
+
+	var semaValueDeclarations []sema.ValueDeclaration
+	semaValueDeclarations = append(semaValueDeclarations, stdlib.BuiltinFunctions.ToSemaValueDeclarations()...)
+	semaValueDeclarations = append(semaValueDeclarations, stdlib.HelperFunctions.ToSemaValueDeclarations()...)
+	semaValueDeclarations = append(semaValueDeclarations, testValue)
+
+	var interpreterValueDeclarations []interpreter.ValueDeclaration
+	interpreterValueDeclarations = append(interpreterValueDeclarations, stdlib.BuiltinFunctions.ToInterpreterValueDeclarations()...)
+	interpreterValueDeclarations = append(interpreterValueDeclarations, stdlib.HelperFunctions.ToInterpreterValueDeclarations()...)
+	interpreterValueDeclarations = append(interpreterValueDeclarations, testValue)
+
+	checker, err := sema.NewChecker(
+		program,
+		TestScriptLocation,
+		sema.WithPredeclaredValues(semaValueDeclarations),
+		sema.WithPredeclaredTypes(stdlib.BuiltinTypes.ToTypeDeclarations()),
+		sema.WithImportHandler(
+			func(_ *sema.Checker, importedLocation common.Location, _ ast.Range) (sema.Import, error) {
+				return nil, fmt.Errorf("cannot import `%s`: imports are not supported in test scripts", importedLocation)
+			},
+		),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var uuid uint64
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+		interpreter.WithPredeclaredValues(interpreterValueDeclarations),
+		interpreter.WithUUIDHandler(func() (uint64, error) {
+			defer func() { uuid++ }()
+			return uuid, nil
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = inter.Interpret()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var testFunctions []string
+	for _, functionDeclaration := range program.FunctionDeclarations() {
+		name := functionDeclaration.Identifier.Identifier
+		if strings.HasPrefix(name, testFunctionPrefix) {
+			testFunctions = append(testFunctions, name)
+		}
+	}
+
+	return inter, testFunctions, nil
+}
@@ -0,0 +1,262 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testframework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func TestRunningMultipleTests(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testFunc1() {
+          Test.assert(false)
+      }
+
+      pub fun testFunc2() {
+          Test.assert(true)
+      }
+
+      pub fun helper() {
+          Test.assert(false)
+      }
+    `
+
+	runner := NewTestRunner()
+	results, err := runner.RunTests(code)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+
+	result1 := results[0]
+	assert.Equal(t, "testFunc1", result1.TestName)
+	assert.False(t, result1.Succeeded())
+	require.ErrorAs(t, result1.Error, &stdlib.AssertionError{})
+
+	result2 := results[1]
+	assert.Equal(t, "testFunc2", result2.TestName)
+	assert.True(t, result2.Succeeded())
+}
+
+func TestRunningSingleTest(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testFunc1() {
+          Test.assert(false)
+      }
+
+      pub fun testFunc2() {
+          Test.assert(true)
+      }
+    `
+
+	runner := NewTestRunner()
+
+	result, err := runner.RunTest(code, "testFunc1")
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+
+	result, err = runner.RunTest(code, "testFunc2")
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+
+	_, err = runner.RunTest(code, "testFunc3")
+	require.Error(t, err)
+}
+
+func TestRunningInvalidScript(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testFunc() {
+          Test.assert("true")
+      }
+    `
+
+	runner := NewTestRunner()
+	_, err := runner.RunTests(code)
+	require.Error(t, err)
+}
+
+func TestExpect(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testEqual() {
+          Test.expect([1, 2], toEqual: [1, 2])
+      }
+
+      pub fun testNotEqual() {
+          Test.expect("foo", toEqual: "bar")
+      }
+    `
+
+	runner := NewTestRunner()
+	results, err := runner.RunTests(code)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+
+	assert.NoError(t, results[0].Error)
+
+	require.Error(t, results[1].Error)
+	assert.Contains(t, results[1].Error.Error(), `expected "bar", got "foo"`)
+}
+
+func TestAssertError(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testExpectedError() {
+          Test.assertError(fun () { panic("broken") }, errorMessage: "broken")
+      }
+
+      pub fun testUnexpectedError() {
+          Test.assertError(fun () { panic("broken") }, errorMessage: "other")
+      }
+
+      pub fun testMissingError() {
+          Test.assertError(fun () {}, errorMessage: "broken")
+      }
+    `
+
+	runner := NewTestRunner()
+	results, err := runner.RunTests(code)
+	require.NoError(t, err)
+
+	require.Len(t, results, 3)
+
+	assert.NoError(t, results[0].Error)
+
+	require.Error(t, results[1].Error)
+	assert.Contains(t, results[1].Error.Error(), `expected error containing "other"`)
+
+	require.Error(t, results[2].Error)
+	assert.Contains(t, results[2].Error.Error(), "got none")
+}
+
+func TestDeployAndExecute(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testCounter() {
+          let account = Test.createAccount()
+
+          Test.deployContract(
+              name: "Counter",
+              code: "pub contract Counter { pub var count: Int; init() { self.count = 0 }; pub fun increment() { self.count = self.count + 1 } }",
+              account: account
+          )
+
+          let script = "import Counter from ".concat(account.toString()).concat(" pub fun main(): Int { return Counter.count }")
+
+          Test.expect(Test.executeScript(script), toEqual: 0)
+
+          Test.executeTransaction(
+              "import Counter from ".concat(account.toString()).concat(" transaction { prepare(signer: AuthAccount) { Counter.increment() } }"),
+              signers: [account]
+          )
+
+          Test.expect(Test.executeScript(script), toEqual: 1)
+      }
+
+      pub fun testFailingTransaction() {
+          let account = Test.createAccount()
+
+          Test.assertError(
+              fun () {
+                  Test.executeTransaction(
+                      "transaction { prepare(signer: AuthAccount) { panic(\"failed\") } }",
+                      signers: [account]
+                  )
+              },
+              errorMessage: "failed"
+          )
+      }
+    `
+
+	runner := NewTestRunner()
+	results, err := runner.RunTests(code)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		assert.NoError(t, result.Error, result.TestName)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun testRollback() {
+          let account = Test.createAccount()
+
+          Test.executeTransaction(
+              "transaction { prepare(signer: AuthAccount) { signer.save(1, to: /storage/value) } }",
+              signers: [account]
+          )
+
+          Test.createSnapshot(name: "initial")
+
+          Test.executeTransaction(
+              "transaction { prepare(signer: AuthAccount) { signer.load<Int>(from: /storage/value); signer.save(2, to: /storage/value) } }",
+              signers: [account]
+          )
+
+          let script = "pub fun main(): Int { return getAuthAccount(".concat(account.toString()).concat(").copy<Int>(from: /storage/value)! }")
+
+          Test.expect(Test.executeScript(script), toEqual: 2)
+
+          Test.loadSnapshot(name: "initial")
+
+          Test.expect(Test.executeScript(script), toEqual: 1)
+      }
+
+      pub fun testMissingSnapshot() {
+          Test.loadSnapshot(name: "missing")
+      }
+    `
+
+	runner := NewTestRunner()
+	results, err := runner.RunTests(code)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+
+	assert.NoError(t, results[0].Error)
+
+	require.Error(t, results[1].Error)
+	assert.Contains(t, results[1].Error.Error(), "cannot find snapshot `missing`")
+}
@@ -228,16 +228,14 @@ func TestCheckAccount_save(t *testing.T) {
 
 			if domain == common.PathDomainStorage {
 
-				errs := ExpectCheckerErrors(t, err, 2)
+				errs := ExpectCheckerErrors(t, err, 1)
 
-				require.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
-				require.IsType(t, &sema.TypeMismatchError{}, errs[1])
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 			} else {
-				errs := ExpectCheckerErrors(t, err, 3)
+				errs := ExpectCheckerErrors(t, err, 2)
 
-				require.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 				require.IsType(t, &sema.TypeMismatchError{}, errs[1])
-				require.IsType(t, &sema.TypeMismatchError{}, errs[2])
 			}
 		})
 
@@ -263,16 +261,14 @@ func TestCheckAccount_save(t *testing.T) {
 
 			if domain == common.PathDomainStorage {
 
-				errs := ExpectCheckerErrors(t, err, 2)
+				errs := ExpectCheckerErrors(t, err, 1)
 
-				require.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
-				require.IsType(t, &sema.TypeMismatchError{}, errs[1])
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 			} else {
-				errs := ExpectCheckerErrors(t, err, 3)
+				errs := ExpectCheckerErrors(t, err, 2)
 
-				require.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 				require.IsType(t, &sema.TypeMismatchError{}, errs[1])
-				require.IsType(t, &sema.TypeMismatchError{}, errs[2])
 			}
 		})
 	}
@@ -405,6 +401,28 @@ func TestCheckAccount_typeAt(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_storagePaths(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheckAccount(t,
+		`
+          let paths = authAccount.storagePaths(ofType: Type<@AnyResource>())
+        `,
+	)
+
+	require.NoError(t, err)
+
+	typ := RequireGlobalValue(t, checker.Elaboration, "paths")
+
+	require.Equal(t,
+		&sema.VariableSizedType{
+			Type: sema.StoragePathType,
+		},
+		typ,
+	)
+}
+
 func TestCheckAccount_load(t *testing.T) {
 
 	t.Parallel()
@@ -655,6 +673,108 @@ func TestCheckAccount_copy(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_transient(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckAccount(t,
+			`
+              struct S {}
+
+              fun test(): S? {
+                  authAccount.saveTransient(S(), to: /storage/s)
+                  let s = authAccount.copyTransient<S>(from: /storage/s)
+                  return authAccount.loadTransient<S>(from: /storage/s)
+              }
+            `,
+		)
+		require.NoError(t, err)
+
+		sType := RequireGlobalType(t, checker.Elaboration, "S")
+
+		require.Equal(t,
+			&sema.OptionalType{
+				Type: sType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "test").(*sema.FunctionType).ReturnTypeAnnotation.Type,
+		)
+	})
+
+	t.Run("save resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t,
+			`
+              resource R {}
+
+              fun test() {
+                  authAccount.saveTransient(<-create R(), to: /storage/r)
+              }
+            `,
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("load resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t,
+			`
+              resource R {}
+
+              let r <- authAccount.loadTransient<@R>(from: /storage/r)
+            `,
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public path", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t,
+			`
+              fun test() {
+                  authAccount.saveTransient(true, to: /public/lock)
+              }
+            `,
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t,
+			`
+              fun test() {
+                  publicAccount.saveTransient(true, to: /storage/lock)
+              }
+            `,
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
 func TestCheckAccount_borrow(t *testing.T) {
 
 	t.Parallel()
@@ -1151,6 +1271,44 @@ func TestCheckAccount_unlink(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_linkAccount(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(domain common.PathDomain) {
+
+		t.Run(domain.Identifier(), func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+                      fun test(): Capability<&AuthAccount>? {
+                          return authAccount.linkAccount(/%s/r)
+                      }
+                    `,
+					domain.Identifier(),
+				),
+			)
+
+			switch domain {
+			case common.PathDomainPrivate:
+				require.NoError(t, err)
+
+			default:
+				errs := ExpectCheckerErrors(t, err, 1)
+
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+			}
+		})
+	}
+
+	for _, domain := range common.AllPathDomainsByIdentifier {
+		test(domain)
+	}
+}
+
 func TestCheckAccount_getLinkTarget(t *testing.T) {
 
 	t.Parallel()
@@ -1508,3 +1666,86 @@ func TestPublicAccountContracts(t *testing.T) {
 	})
 
 }
+
+func TestCheckAccount_linkTargetHint(t *testing.T) {
+
+	t.Parallel()
+
+	check := func(t *testing.T, body string) []sema.Hint {
+		checker, err := ParseAndCheckAccount(t,
+			fmt.Sprintf(
+				`
+                  resource interface I {}
+
+                  resource R: I {}
+
+                  resource S {}
+
+                  fun test() {
+                      %s
+                  }
+                `,
+				body,
+			),
+		)
+		require.NoError(t, err)
+
+		return checker.Hints()
+	}
+
+	t.Run("incompatible", func(t *testing.T) {
+
+		t.Parallel()
+
+		hints := check(t, `
+          authAccount.save(<-create R(), to: /storage/r)
+          authAccount.link<&S>(/public/r, target: /storage/r)
+        `)
+
+		require.Len(t, hints, 1)
+		require.IsType(t, &sema.InvalidLinkTargetHint{}, hints[0])
+
+		assert.Equal(t,
+			"link target of type `R` can never be borrowed as `&S`",
+			hints[0].Hint(),
+		)
+	})
+
+	t.Run("compatible", func(t *testing.T) {
+
+		t.Parallel()
+
+		hints := check(t, `
+          authAccount.save(<-create R(), to: /storage/r)
+          authAccount.link<&R>(/public/r, target: /storage/r)
+          authAccount.link<&R{I}>(/public/i, target: /storage/r)
+        `)
+
+		require.Empty(t, hints)
+	})
+
+	t.Run("loaded", func(t *testing.T) {
+
+		t.Parallel()
+
+		hints := check(t, `
+          authAccount.save(<-create R(), to: /storage/r)
+          destroy authAccount.load<@R>(from: /storage/r)
+          authAccount.link<&S>(/public/r, target: /storage/r)
+        `)
+
+		require.Empty(t, hints)
+	})
+
+	t.Run("other path", func(t *testing.T) {
+
+		t.Parallel()
+
+		hints := check(t, `
+          authAccount.save(<-create R(), to: /storage/r)
+          authAccount.link<&S>(/public/s, target: /storage/s)
+        `)
+
+		require.Empty(t, hints)
+	})
+}
@@ -298,6 +298,68 @@ func TestCheckDictionaryKeys(t *testing.T) {
 	)
 }
 
+func TestCheckDictionaryKeysSorted(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("sortable key types", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, keyType := range []string{"String", "Character", "Address", "Int", "UInt8", "Word64", "Fix64", "UFix64"} {
+
+			t.Run(keyType, func(t *testing.T) {
+
+				checker, err := ParseAndCheck(t,
+					fmt.Sprintf(
+						`
+                          fun test(dict: {%[1]s: Int}): [%[1]s] {
+                              return dict.keysSorted()
+                          }
+                        `,
+						keyType,
+					),
+				)
+
+				require.NoError(t, err)
+
+				testType := RequireGlobalValue(t, checker.Elaboration, "test").(*sema.FunctionType)
+
+				assert.IsType(t,
+					&sema.VariableSizedType{},
+					testType.ReturnTypeAnnotation.Type,
+				)
+			})
+		}
+	})
+
+	t.Run("non-sortable key types", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, keyType := range []string{"Number", "Integer", "SignedFixedPoint", "Bool", "Path", "Type"} {
+
+			t.Run(keyType, func(t *testing.T) {
+
+				_, err := ParseAndCheck(t,
+					fmt.Sprintf(
+						`
+                          fun test(dict: {%[1]s: Int}): [%[1]s] {
+                              return dict.keysSorted()
+                          }
+                        `,
+						keyType,
+					),
+				)
+
+				errs := ExpectCheckerErrors(t, err, 1)
+
+				assert.IsType(t, &sema.NotSortableTypeError{}, errs[0])
+			})
+		}
+	})
+}
+
 func TestCheckDictionaryValues(t *testing.T) {
 
 	t.Parallel()
@@ -1065,8 +1127,11 @@ func TestCheckDictionaryKeyTypesExpressions(t *testing.T) {
 	}
 
 	for name, code := range map[string]string{
-		"struct": `
-           struct X {}
+		"struct with non-hashable field": `
+           struct X {
+               let xs: [Int]
+               init() { self.xs = [] }
+           }
            let k = X()
         `,
 		"array":      `let k = [1]`,
@@ -1091,6 +1156,116 @@ func TestCheckDictionaryKeyTypesExpressions(t *testing.T) {
 	}
 }
 
+func TestCheckStructDictionaryKeyTypes(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("hashable fields", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          enum E: UInt8 {
+              case a
+          }
+
+          struct Inner {
+              let x: Int
+              init(x: Int) { self.x = x }
+          }
+
+          struct Key {
+              let a: String
+              var b: UInt64
+              let c: Inner
+              let d: E
+              let e: Address
+
+              init() {
+                  self.a = "a"
+                  self.b = 1
+                  self.c = Inner(x: 2)
+                  self.d = E.a
+                  self.e = 0x1
+              }
+          }
+
+          let xs: {Key: String} = {Key(): "x"}
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("key type declared later", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Container {
+              let xs: {Key: Int}
+              init() { self.xs = {} }
+          }
+
+          struct Key {
+              let x: Int
+              init() { self.x = 1 }
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("non-hashable field, key type declared later", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Container {
+              let xs: {Key: Int}
+              init() { self.xs = {} }
+          }
+
+          struct Key {
+              let x: Int?
+              init() { self.x = 1 }
+          }
+        `)
+
+		// NOTE: the field's type annotation is converted twice
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.InvalidDictionaryKeyTypeError{}, errs[0])
+		assert.IsType(t, &sema.InvalidDictionaryKeyTypeError{}, errs[1])
+	})
+
+	t.Run("non-hashable nested field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Inner {
+              let xs: [Int]
+              init() { self.xs = [] }
+          }
+
+          struct Key {
+              let inner: Inner
+              init() { self.inner = Inner() }
+          }
+
+          let xs: {Key: Int} = {}
+        `)
+
+		// NOTE: both the type annotation and the dictionary expression are checked
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.InvalidDictionaryKeyTypeError{}, errs[0])
+		assert.IsType(t, &sema.InvalidDictionaryKeyTypeError{}, errs[1])
+	})
+}
+
 func TestNilAssignmentToDictionary(t *testing.T) {
 
 	t.Parallel()
@@ -1121,3 +1296,272 @@ func TestNilAssignmentToDictionary(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestCheckArrayFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              var sum = 0
+              [1, 2, 3].forEach(fun (x: Int) {
+                  sum = sum + x
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].map(fun (x: Int): String {
+              return x.toString()
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.StringType},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].filter(fun (x: Int): Bool {
+              return x > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.IntType},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("invalid filter, predicate result type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].filter(fun (x: Int): Int {
+              return x
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("invalid map, parameter type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].map(fun (x: String): String {
+              return x
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
+func TestCheckDictionaryFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              var sum = 0
+              {"a": 1, "b": 2}.forEach(fun (key: String, value: Int) {
+                  sum = sum + value
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let dict = {"a": 1, "b": 2}.map(fun (key: String, value: Int): Bool {
+              return value > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: sema.BoolType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "dict"),
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let dict = {"a": 1, "b": 2}.filter(fun (key: String, value: Int): Bool {
+              return value > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "dict"),
+		)
+	})
+}
+
+func TestCheckConstantSizedArrayIndexBoundsHints(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("constant index out of bounds", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          let size = 2
+          let xs: [Int; 3] = [1, 2, 3]
+          let a = xs[3]
+          let b = xs[size + 1]
+          let c = xs[-1]
+          let d = xs[xs.length]
+        `)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 4)
+
+		for i, index := range []int64{3, 3, -1, 3} {
+			require.IsType(t, &sema.IndexOutOfBoundsHint{}, hints[i])
+			hint := hints[i].(*sema.IndexOutOfBoundsHint)
+			assert.Equal(t, big.NewInt(index), hint.Index)
+			assert.Equal(t, int64(3), hint.Size)
+		}
+	})
+
+	t.Run("constant index in bounds", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          let xs: [Int; 3] = [1, 2, 3]
+          let a = xs[0]
+          let b = xs[xs.length - 1]
+        `)
+		require.NoError(t, err)
+
+		assert.Empty(t, checker.Hints())
+	})
+
+	t.Run("loop bound exceeds size", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          fun test() {
+              let xs: [Int; 3] = [1, 2, 3]
+              for i in 0...xs.length {
+                  xs[i] = 0
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		require.IsType(t, &sema.LoopBoundOutOfBoundsHint{}, hints[0])
+		hint := hints[0].(*sema.LoopBoundOutOfBoundsHint)
+		assert.Equal(t, big.NewInt(0), hint.Min)
+		assert.Equal(t, big.NewInt(3), hint.Max)
+		assert.Equal(t, int64(3), hint.Size)
+	})
+
+	t.Run("loop bound within size", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          fun test() {
+              let xs: [Int; 3] = [1, 2, 3]
+              for i in 0..<xs.length {
+                  xs[i] = 0
+              }
+              for j in 1...2 {
+                  xs[j - 1] = xs[j]
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Empty(t, checker.Hints())
+	})
+
+	t.Run("variables and variable-sized arrays", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          fun test() {
+              var index = 3
+              let xs: [Int; 3] = [1, 2, 3]
+              let a = xs[index]
+              let ys = [1, 2, 3]
+              let b = ys[3]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Empty(t, checker.Hints())
+	})
+
+	t.Run("linting disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs: [Int; 3] = [1, 2, 3]
+          let a = xs[3]
+        `)
+		require.NoError(t, err)
+
+		assert.Empty(t, checker.Hints())
+	})
+}
@@ -61,6 +61,114 @@ func TestCheckToString(t *testing.T) {
 	}
 }
 
+func TestCheckCompositeToString(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          struct S {
+              let x: Int
+
+              init() {
+                  self.x = 1
+              }
+          }
+
+          let res = S().toString()
+        `)
+
+		require.NoError(t, err)
+
+		resType := RequireGlobalValue(t, checker.Elaboration, "res")
+
+		assert.Equal(t,
+			sema.StringType,
+			resType,
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          fun test(): String {
+              let r <- create R()
+              let ref = &r as &R
+              let res = r.toString().concat(ref.toString())
+              destroy r
+              return res
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		// A declared toString function replaces the derived function
+
+		checker, err := ParseAndCheck(t, `
+          struct S {
+              fun toString(): Int {
+                  return 1
+              }
+          }
+
+          let res = S().toString()
+        `)
+
+		require.NoError(t, err)
+
+		resType := RequireGlobalValue(t, checker.Elaboration, "res")
+
+		assert.Equal(t,
+			sema.IntType,
+			resType,
+		)
+	})
+
+	t.Run("contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {}
+
+          let res = C.toString()
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+
+	t.Run("enum", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          enum E: UInt8 {
+              case a
+          }
+
+          let res = E.a.toString()
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
 func TestCheckToBytes(t *testing.T) {
 
 	t.Parallel()
@@ -0,0 +1,145 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func parseAndCheckWithCallSafe(t *testing.T, code string) (*sema.Checker, error) {
+	return ParseAndCheckWithOptions(t,
+		code,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithPredeclaredValues(stdlib.BuiltinFunctions.ToSemaValueDeclarations()),
+			},
+		},
+	)
+}
+
+func TestCheckCallSafe(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("struct result", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := parseAndCheckWithCallSafe(t, `
+          let x = callSafe(fun (): Int {
+              return 1
+          })
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{
+				Type: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("optional result", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := parseAndCheckWithCallSafe(t, `
+          let x = callSafe(fun (): Int? {
+              return nil
+          })
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{
+				Type: &sema.OptionalType{
+					Type: sema.IntType,
+				},
+			},
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("resource result", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheckWithCallSafe(t, `
+          resource R {}
+
+          let x <- callSafe(fun (): @R {
+              return <-create R()
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("function with parameters", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheckWithCallSafe(t, `
+          let x = callSafe(fun (y: Int): Int {
+              return y
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+		require.IsType(t, &sema.TypeParameterTypeInferenceError{}, errs[1])
+	})
+
+	t.Run("resource capture", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheckWithCallSafe(t, `
+          resource R {
+              fun test(): Int {
+                  return 1
+              }
+          }
+
+          fun test(): Int? {
+              let r <- create R()
+              let x = callSafe(fun (): Int {
+                  return r.test()
+              })
+              destroy r
+              return x
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		require.IsType(t, &sema.ResourceCapturingError{}, errs[0])
+		require.IsType(t, &sema.ResourceLossError{}, errs[1])
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -201,6 +202,137 @@ func TestCheckFunctionWithBeforeVariable(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckFunctionPostConditionWithBeforeContainers(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      struct S {
+          var values: [Int]
+          var names: {String: [Int]}
+
+          init() {
+              self.values = []
+              self.names = {}
+          }
+
+          fun test() {
+              post {
+                  before(self.values).length < self.values.length
+                  before(self.names)["a"] == nil
+                  before(self).values.length == 0
+              }
+              self.values.append(1)
+          }
+      }
+    `)
+
+	require.NoError(t, err)
+}
+
+func TestCheckInvalidFunctionPostConditionWithBeforeResource(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let x: Int
+
+              init() {
+                  self.x = 1
+              }
+          }
+
+          resource C {
+              var r: @R
+
+              init() {
+                  self.r <- create R()
+              }
+
+              fun test() {
+                  post {
+                      before(self.r).x == 1
+                  }
+              }
+
+              destroy() {
+                  destroy self.r
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var beforeErr *sema.InvalidBeforeValueTypeError
+		require.ErrorAs(t, errs[0], &beforeErr)
+
+		assert.Equal(t,
+			ast.Position{Offset: 339, Line: 19, Column: 29},
+			beforeErr.StartPosition(),
+		)
+	})
+
+	t.Run("resource array", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          fun test(rs: @[R]): @[R] {
+              post {
+                  before(rs.length) == 0
+                  before(rs).length == 0
+              }
+              return <-rs
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBeforeValueTypeError{}, errs[0])
+	})
+
+	t.Run("reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(values: &[Int]) {
+              post {
+                  before(values).length == 0
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBeforeValueTypeError{}, errs[0])
+	})
+
+	t.Run("optional references", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(values: {String: &Int}) {
+              post {
+                  before(values["a"]) == nil
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBeforeValueTypeError{}, errs[0])
+	})
+}
+
 func TestCheckFunctionPostCondition(t *testing.T) {
 
 	t.Parallel()
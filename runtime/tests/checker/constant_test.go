@@ -0,0 +1,452 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckConstantExpressionValues(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let a = 1 + 2 * 3
+      let b: UInt8 = 10 - 3 % 2
+      let c = -(4 / 2)
+      let d = 1 < 2 && !("a" == "b")
+      let e: Word8 = 255 + 2
+      let f: Int8 = 1 | 6 & 3
+      let x = 1
+      let g = x + 1
+      let h = -1 / 2
+    `)
+	require.NoError(t, err)
+
+	constantValue := func(name string) (sema.ConstantValue, bool) {
+		variableDeclaration := checker.Program.Declarations()[indexOfGlobal(t, checker, name)].(*ast.VariableDeclaration)
+		value, ok := checker.Elaboration.ConstantExpressionValues[variableDeclaration.Value]
+		return value, ok
+	}
+
+	for name, expected := range map[string]sema.ConstantValue{
+		"a": {Type: sema.IntType, Value: big.NewInt(7)},
+		"b": {Type: sema.UInt8Type, Value: big.NewInt(9)},
+		"c": {Type: sema.IntType, Value: big.NewInt(-2)},
+		"d": {Type: sema.BoolType, Value: true},
+		"e": {Type: sema.Word8Type, Value: big.NewInt(1)},
+		"f": {Type: sema.Int8Type, Value: big.NewInt(3)},
+	} {
+		value, ok := constantValue(name)
+		require.True(t, ok, name)
+		assert.Equal(t, expected, value, name)
+	}
+
+	// Expressions with variables are not constant
+
+	_, ok := constantValue("g")
+	assert.False(t, ok)
+
+	// Divisions of negative integers are not folded,
+	// as the semantics differ between integer types
+
+	_, ok = constantValue("h")
+	assert.False(t, ok)
+}
+
+func TestCheckConstantExpressionOverflow(t *testing.T) {
+
+	t.Parallel()
+
+	for _, ty := range sema.AllIntegerTypes {
+
+		ty := ty
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			t.Parallel()
+
+			ranged := ty.(sema.IntegerRangedType)
+			maxInt := ranged.MaxInt()
+			minInt := ranged.MinInt()
+
+			var code string
+			switch {
+			case maxInt != nil:
+				code = fmt.Sprintf(`let x: %s = %s + 1`, ty, maxInt)
+			case minInt != nil:
+				code = fmt.Sprintf(`let x: %s = %s - 1`, ty, minInt)
+			default:
+				// Int is unbounded
+				return
+			}
+
+			_, err := ParseAndCheck(t, code)
+
+			switch ty {
+			case sema.Word8Type, sema.Word16Type, sema.Word32Type, sema.Word64Type:
+				// Word types wrap around
+				require.NoError(t, err)
+
+			default:
+				errs := ExpectCheckerErrors(t, err, 1)
+
+				assert.IsType(t, &sema.InvalidConstantExpressionRangeError{}, errs[0])
+			}
+		})
+	}
+}
+
+func TestCheckConstantExpressionUnderflow(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      let x: UInt = 1 - 2
+      let y: Int8 = -128 - 1
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 2)
+
+	assert.IsType(t, &sema.InvalidConstantExpressionRangeError{}, errs[0])
+	assert.IsType(t, &sema.InvalidConstantExpressionRangeError{}, errs[1])
+}
+
+func TestCheckFixedPointConstantExpressionValues(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let a: UFix64 = 1.5 + 2.25
+      let b: Fix64 = -1.5 * 2.0
+      let c: UFix128 = 1.0 / 4.0
+      let d = 1.0 < 2.5
+      let e: Fix64 = 1.0 % 0.3
+      let f: UFix64 = 1.0 / 0.0
+    `)
+	require.NoError(t, err)
+
+	constantValue := func(name string) (sema.ConstantValue, bool) {
+		variableDeclaration := checker.Program.Declarations()[indexOfGlobal(t, checker, name)].(*ast.VariableDeclaration)
+		value, ok := checker.Elaboration.ConstantExpressionValues[variableDeclaration.Value]
+		return value, ok
+	}
+
+	for name, expected := range map[string]sema.ConstantValue{
+		"a": {Type: sema.UFix64Type, Value: big.NewInt(375000000)},
+		"b": {Type: sema.Fix64Type, Value: big.NewInt(-300000000)},
+		"c": {Type: sema.UFix128Type, Value: new(big.Int).Quo(sema.Fix128FactorBig, big.NewInt(4))},
+		"d": {Type: sema.BoolType, Value: true},
+	} {
+		value, ok := constantValue(name)
+		require.True(t, ok, name)
+		assert.Equal(t, expected, value, name)
+	}
+
+	// Remainders and divisions by zero are not folded
+
+	_, ok := constantValue("e")
+	assert.False(t, ok)
+
+	_, ok = constantValue("f")
+	assert.False(t, ok)
+}
+
+func TestCheckFixedPointConstantExpressionOverflow(t *testing.T) {
+
+	t.Parallel()
+
+	for _, ty := range sema.AllFixedPointTypes {
+
+		ty := ty
+
+		ranged, ok := ty.(sema.FractionalRangedType)
+		if !ok || ty == sema.FixedPointType || ty == sema.SignedFixedPointType {
+			continue
+		}
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			t.Parallel()
+
+			smallest := "0." + strings.Repeat("0", int(ranged.Scale())-1) + "1"
+
+			code := fmt.Sprintf(
+				`let x: %s = %s.%s + %s`,
+				ty,
+				ranged.MaxInt(),
+				ranged.MaxFractional(),
+				smallest,
+			)
+
+			_, err := ParseAndCheck(t, code)
+
+			errs := ExpectCheckerErrors(t, err, 1)
+
+			assert.IsType(t, &sema.InvalidFixedPointConstantExpressionRangeError{}, errs[0])
+		})
+	}
+
+	t.Run("multiplication", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let b: Fix64 = 92233720368.0 * 100.0
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidFixedPointConstantExpressionRangeError{}, errs[0])
+	})
+}
+
+func TestCheckFixedPointConstantExpressionUnderflow(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      let x: UFix64 = 1.0 - 2.0
+      let y: Fix64 = -92233720368.0 * 100.0
+      let z: Fix128 = -170141183460469.0 - 1.0
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 3)
+
+	assert.IsType(t, &sema.InvalidFixedPointConstantExpressionRangeError{}, errs[0])
+	assert.IsType(t, &sema.InvalidFixedPointConstantExpressionRangeError{}, errs[1])
+	assert.IsType(t, &sema.InvalidFixedPointConstantExpressionRangeError{}, errs[2])
+}
+
+func indexOfGlobal(t *testing.T, checker *sema.Checker, name string) int {
+	for i, declaration := range checker.Program.Declarations() {
+		identifier := declaration.DeclarationIdentifier()
+		if identifier != nil && identifier.Identifier == name {
+			return i
+		}
+	}
+	require.FailNow(t, "missing global", name)
+	return -1
+}
+
+func TestCheckContractConstantFields(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: Int = 1 + 2
+              pub let b: UInt8 = 255
+              pub let c: String = "hello"
+              pub let d: Bool = 1 < 2
+              pub let e: String? = "optional"
+
+              pub fun sum(): Int {
+                  return self.a + Int(self.b)
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		compositeDeclaration := checker.Program.CompositeDeclarations()[0]
+
+		constantValues := map[string]sema.ConstantValue{}
+		for _, field := range compositeDeclaration.Members.Fields() {
+			value, ok := checker.Elaboration.FieldDeclarationConstantValues[field]
+			require.True(t, ok, field.Identifier.Identifier)
+			constantValues[field.Identifier.Identifier] = value
+		}
+
+		assert.Equal(t,
+			map[string]sema.ConstantValue{
+				"a": {Type: sema.IntType, Value: big.NewInt(3)},
+				"b": {Type: sema.UInt8Type, Value: big.NewInt(255)},
+				"c": {Type: sema.StringType, Value: "hello"},
+				"d": {Type: sema.BoolType, Value: true},
+				"e": {Type: sema.StringType, Value: "optional"},
+			},
+			constantValues,
+		)
+	})
+
+	t.Run("initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Constant fields are initialized by their declaration,
+		// so the initializer may read them
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: Int = 1
+              pub let b: Int
+
+              init() {
+                  self.b = self.a + 1
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("assignment in initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: Int = 1
+
+              init() {
+                  self.a = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.AssignmentToConstantMemberError{}, errs[0])
+	})
+
+	t.Run("missing initialization", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: Int = 1
+              pub let b: Int
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingInitializerError{}, errs[0])
+	})
+
+	t.Run("variable field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub var a: Int = 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidFieldValueError{}, errs[0])
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub struct S {
+              pub let a: Int = 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidFieldValueError{}, errs[0])
+	})
+
+	t.Run("contract interface field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract interface CI {
+              pub let a: Int = 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidFieldValueError{}, errs[0])
+	})
+
+	t.Run("transaction field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          transaction {
+              let a: Int = 1
+
+              prepare() {}
+
+              execute {}
+          }
+        `)
+
+		// The field must still be initialized in the prepare block
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.InvalidFieldValueError{}, errs[0])
+		assert.IsType(t, &sema.FieldUninitializedError{}, errs[1])
+	})
+
+	t.Run("non-constant value", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: Int = [1, 2].length
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NonConstantFieldValueError{}, errs[0])
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          pub contract C {
+              pub let a: String = 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
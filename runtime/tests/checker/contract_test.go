@@ -708,3 +708,66 @@ func TestCheckInvalidContractNestedTypeShadowing(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckContractIsReentrant(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      contract Test {
+          fun test(): Bool {
+              return self.isReentrant
+          }
+      }
+    `
+
+	t.Run("guard enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithReentrancyGuardEnabled(true),
+				},
+			},
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("guard disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, code)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+
+	t.Run("private", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              contract Test {}
+
+              fun test(): Bool {
+                  return Test.isReentrant
+              }
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithReentrancyGuardEnabled(true),
+				},
+			},
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidAccessError{}, errs[0])
+	})
+}
@@ -58,6 +58,26 @@ func TestCheckHashAlgorithmCases(t *testing.T) {
 	}
 }
 
+func TestCheckHashAlgorithmAllCases(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheckWithOptions(t,
+		`
+           let algos: [HashAlgorithm] = HashAlgorithm.allCases
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithPredeclaredValues(
+					stdlib.BuiltinValues().ToSemaValueDeclarations(),
+				),
+			},
+		},
+	)
+
+	require.NoError(t, err)
+}
+
 func TestCheckHashAlgorithmConstructor(t *testing.T) {
 
 	t.Parallel()
@@ -0,0 +1,197 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckDeprecation(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("function, with message", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          /// Returns the answer.
+          ///
+          /// @deprecated("use \"answer\" instead")
+          fun oldAnswer(): Int {
+              return 42
+          }
+
+          fun answer(): Int {
+              return 42
+          }
+
+          let x = oldAnswer()
+          let y = answer()
+        `)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		require.IsType(t, &sema.DeprecatedDeclarationHint{}, hints[0])
+		hint := hints[0].(*sema.DeprecatedDeclarationHint)
+
+		assert.Equal(t, "oldAnswer", hint.Name)
+		assert.Equal(t, `use "answer" instead`, hint.Message)
+		assert.Equal(t,
+			ast.Range{
+				StartPos: ast.Position{Offset: 256, Line: 13, Column: 18},
+				EndPos:   ast.Position{Offset: 264, Line: 13, Column: 26},
+			},
+			hint.Range,
+		)
+		assert.Equal(t,
+			"`oldAnswer` is deprecated: use \"answer\" instead",
+			hint.Hint(),
+		)
+	})
+
+	t.Run("field, without message", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          struct S {
+              /// @deprecated
+              let x: Int
+
+              init() {
+                  self.x = 1
+              }
+
+              fun test(): Int {
+                  return self.x
+              }
+          }
+
+          let y = S().x
+        `)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		require.IsType(t, &sema.DeprecatedDeclarationHint{}, hints[0])
+		hint := hints[0].(*sema.DeprecatedDeclarationHint)
+
+		assert.Equal(t, "x", hint.Name)
+		assert.Equal(t, "", hint.Message)
+		assert.Equal(t, "`x` is deprecated", hint.Hint())
+	})
+
+	t.Run("type", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          /// @deprecated("use T instead")
+          struct S {}
+
+          struct T {}
+
+          fun test(s: S) {}
+        `)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		require.IsType(t, &sema.DeprecatedDeclarationHint{}, hints[0])
+		hint := hints[0].(*sema.DeprecatedDeclarationHint)
+
+		assert.Equal(t, "S", hint.Name)
+		assert.Equal(t, "use T instead", hint.Message)
+	})
+
+	t.Run("pragma not on own line", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          /// This function is not @deprecated
+          fun test() {}
+
+          let x = test()
+        `)
+		require.NoError(t, err)
+
+		require.Empty(t, checker.Hints())
+	})
+
+	t.Run("imported", func(t *testing.T) {
+
+		t.Parallel()
+
+		importedChecker, err := ParseAndCheckWithOptions(t,
+			`
+              /// @deprecated("use bar instead")
+              pub fun foo() {}
+            `,
+			ParseAndCheckOptions{
+				Location: utils.ImportedLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		checker, err := ParseAndCheckWithOptions(t,
+			`
+              import foo from "imported"
+
+              pub fun test() {
+                  foo()
+              }
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithImportHandler(
+						func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+							return sema.ElaborationImport{
+								Elaboration: importedChecker.Elaboration,
+							}, nil
+						},
+					),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		require.IsType(t, &sema.DeprecatedDeclarationHint{}, hints[0])
+		hint := hints[0].(*sema.DeprecatedDeclarationHint)
+
+		assert.Equal(t, "foo", hint.Name)
+		assert.Equal(t, "use bar instead", hint.Message)
+	})
+}
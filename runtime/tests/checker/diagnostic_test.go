@@ -0,0 +1,211 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckDiagnostics(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x: Int = "1"
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		diagnostic := sema.NewErrorDiagnostic(errs[0], utils.TestLocation)
+
+		assert.Equal(t, sema.DiagnosticSeverityError, diagnostic.Severity)
+		assert.Equal(t, "TypeMismatchError", diagnostic.Code)
+		assert.Equal(t, "mismatched types", diagnostic.Message)
+		assert.Equal(t, "expected `Int`, got `String`", diagnostic.SecondaryMessage)
+		assert.Equal(t, utils.TestLocation, diagnostic.Location)
+		assert.Equal(t,
+			ast.Range{
+				StartPos: ast.Position{Offset: 24, Line: 2, Column: 23},
+				EndPos:   ast.Position{Offset: 26, Line: 2, Column: 25},
+			},
+			diagnostic.Range,
+		)
+		assert.Empty(t, diagnostic.Related)
+	})
+
+	t.Run("redeclaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = 1
+          let x = 2
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		diagnostics := checkerErr.Diagnostics()
+		require.Len(t, diagnostics, 1)
+
+		assert.Equal(t,
+			sema.NewErrorDiagnostic(errs[0], utils.TestLocation),
+			diagnostics[0],
+		)
+
+		diagnostic := diagnostics[0]
+
+		assert.Equal(t, "RedeclarationError", diagnostic.Code)
+		assert.Equal(t,
+			[]sema.DiagnosticRelatedInformation{
+				{
+					Message:  "previously declared here",
+					Location: utils.TestLocation,
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 15, Line: 2, Column: 14},
+						EndPos:   ast.Position{Offset: 15, Line: 2, Column: 14},
+					},
+				},
+			},
+			diagnostic.Related,
+		)
+	})
+
+	t.Run("hint", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithLinting(t, `
+          let x = UFix64(1)
+        `)
+		require.NoError(t, err)
+
+		diagnostics := checker.Diagnostics()
+		require.Len(t, diagnostics, 1)
+
+		diagnostic := diagnostics[0]
+
+		assert.Equal(t, sema.DiagnosticSeverityHint, diagnostic.Severity)
+		assert.Equal(t, "ReplacementHint", diagnostic.Code)
+		assert.Equal(t, "consider replacing with: `1.0`", diagnostic.Message)
+		assert.Equal(t,
+			ast.Range{
+				StartPos: ast.Position{Offset: 19, Line: 2, Column: 18},
+				EndPos:   ast.Position{Offset: 27, Line: 2, Column: 26},
+			},
+			diagnostic.Range,
+		)
+	})
+}
+
+func TestCheckDiagnosticConfig(t *testing.T) {
+
+	t.Parallel()
+
+	check := func(t *testing.T, code string, config *sema.DiagnosticConfig) []sema.Diagnostic {
+		checker, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithLintingEnabled(true),
+					sema.WithDiagnosticConfig(config),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		return checker.Diagnostics()
+	}
+
+	const code = `
+      let x = UFix64(1)
+      let y = UFix64(2)
+    `
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := check(t,
+			code,
+			&sema.DiagnosticConfig{
+				Disabled: map[string]bool{
+					"ReplacementHint": true,
+				},
+			},
+		)
+
+		require.Empty(t, diagnostics)
+	})
+
+	t.Run("severity", func(t *testing.T) {
+
+		t.Parallel()
+
+		diagnostics := check(t,
+			code,
+			&sema.DiagnosticConfig{
+				Severities: map[string]sema.DiagnosticSeverity{
+					"ReplacementHint": sema.DiagnosticSeverityWarning,
+				},
+			},
+		)
+
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, sema.DiagnosticSeverityWarning, diagnostics[0].Severity)
+		assert.Equal(t, sema.DiagnosticSeverityWarning, diagnostics[1].Severity)
+	})
+
+	t.Run("suppression comments", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `
+          // cadence-disable-next-line ReplacementHint
+          let x = UFix64(1)
+          let y = UFix64(2) // cadence-disable-line UnnecessaryCastHint
+          let z = UFix64(3) // cadence-disable-line
+        `
+
+		diagnostics := check(t,
+			code,
+			&sema.DiagnosticConfig{
+				Suppressions: parser2.ParseDiagnosticSuppressions(code),
+			},
+		)
+
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, "ReplacementHint", diagnostics[0].Code)
+		assert.Equal(t, 4, diagnostics[0].StartPos.Line)
+	})
+}
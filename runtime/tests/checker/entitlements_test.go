@@ -0,0 +1,479 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckEntitlementDeclaration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("top-level", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          entitlement E
+        `)
+
+		require.NoError(t, err)
+
+		entitlementType := RequireGlobalType(t, checker.Elaboration, "E")
+		require.IsType(t, &sema.EntitlementType{}, entitlementType)
+	})
+
+	t.Run("nested in contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {
+              entitlement E
+
+              struct S {
+                  access(E) let x: Int
+
+                  init() {
+                      self.x = 1
+                  }
+              }
+          }
+
+          fun test(s: auth(C.E) &C.S): Int {
+              return s.x
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("nested in contract interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract interface CI {
+              entitlement E
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("nested in struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              entitlement E
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidNestedDeclarationError{}, errs[0])
+	})
+
+	t.Run("redeclaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          entitlement E
+          entitlement E
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.RedeclarationError{}, errs[0])
+	})
+
+	t.Run("usage as type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          entitlement E
+
+          fun test(e: E) {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidEntitlementUsageError{}, errs[0])
+	})
+}
+
+func TestCheckEntitlementAccess(t *testing.T) {
+
+	t.Parallel()
+
+	const declarations = `
+      entitlement E1
+      entitlement E2
+
+      struct S {
+          access(E1) var x: Int
+
+          access(E2) fun f() {}
+
+          pub fun g() {}
+
+          init() {
+              self.x = 1
+          }
+      }
+    `
+
+	t.Run("direct access", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: S) {
+              s.x
+              s.f()
+              s.g()
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("reference, no entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: &S) {
+              s.x
+              s.f()
+              s.g()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		require.IsType(t, &sema.InsufficientEntitlementsError{}, errs[0])
+		assert.Equal(t,
+			"x",
+			errs[0].(*sema.InsufficientEntitlementsError).Name,
+		)
+
+		require.IsType(t, &sema.InsufficientEntitlementsError{}, errs[1])
+		assert.Equal(t,
+			"f",
+			errs[1].(*sema.InsufficientEntitlementsError).Name,
+		)
+	})
+
+	t.Run("reference, some entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1) &S) {
+              s.x
+              s.f()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InsufficientEntitlementsError{}, errs[0])
+		assert.Equal(t,
+			"f",
+			errs[0].(*sema.InsufficientEntitlementsError).Name,
+		)
+	})
+
+	t.Run("reference, all entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1, E2) &S) {
+              s.x
+              s.f()
+              s.g()
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("authorized reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth &S) {
+              s.x
+              s.f()
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("optional reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: &S?) {
+              s?.f()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InsufficientEntitlementsError{}, errs[0])
+	})
+
+	t.Run("assignment through entitled reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1) &S) {
+              s.x = 2
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("assignment through unentitled reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: &S) {
+              s.x = 2
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InsufficientEntitlementsError{}, errs[0])
+	})
+
+	t.Run("non-entitlement", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct T {}
+
+          struct S {
+              access(T) let x: Int
+
+              init() {
+                  self.x = 1
+              }
+          }
+
+          fun test(s: auth(T) &S) {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.InvalidEntitlementTypeError{}, errs[0])
+		assert.IsType(t, &sema.InvalidEntitlementTypeError{}, errs[1])
+	})
+}
+
+func TestCheckEntitledReferenceSubtyping(t *testing.T) {
+
+	t.Parallel()
+
+	const declarations = `
+      entitlement E1
+      entitlement E2
+
+      struct S {}
+    `
+
+	t.Run("fewer entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1, E2) &S): auth(E1) &S {
+              return s
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("more entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1) &S): auth(E1, E2) &S {
+              return s
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("to unentitled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1) &S): &S {
+              return s
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("authorized to entitled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth &S): auth(E1, E2) &S {
+              return s
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("entitled to authorized", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, declarations+`
+          fun test(s: auth(E1, E2) &S): auth &S {
+              return s
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("reference expression", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, declarations+`
+          let s = S()
+          let ref = &s as auth(E2, E1, E2) &S
+        `)
+
+		require.NoError(t, err)
+
+		refType := RequireGlobalValue(t, checker.Elaboration, "ref")
+		require.IsType(t, &sema.ReferenceType{}, refType)
+
+		assert.Equal(t,
+			"auth(E1, E2) &S",
+			refType.QualifiedString(),
+		)
+		assert.Equal(t,
+			common.TypeID("auth(S.test.E1, S.test.E2) &S.test.S"),
+			refType.ID(),
+		)
+	})
+}
+
+func TestCheckEntitlementConformance(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("same entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          entitlement E
+
+          struct interface I {
+              access(E) fun f()
+          }
+
+          struct S: I {
+              access(E) fun f() {}
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("fewer entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          entitlement E
+
+          struct interface I {
+              access(E) fun f()
+          }
+
+          struct S: I {
+              pub fun f() {}
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("more entitlements", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          entitlement E
+
+          struct interface I {
+              pub fun f()
+          }
+
+          struct S: I {
+              access(E) fun f() {}
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ConformanceError{}, errs[0])
+	})
+}
@@ -219,6 +219,56 @@ func TestCheckEnumConstructor(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckEnumAllCases(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          enum E: UInt8 {
+              case a
+              case b
+          }
+
+          let cases = E.allCases
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: RequireGlobalType(t, checker.Elaboration, "E"),
+			},
+			RequireGlobalValue(t, checker.Elaboration, "cases"),
+		)
+	})
+
+	t.Run("enum case with same name", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          enum E: UInt8 {
+              case a
+              case allCases
+          }
+
+          let allCases = E.allCases
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			RequireGlobalType(t, checker.Elaboration, "E"),
+			RequireGlobalValue(t, checker.Elaboration, "allCases"),
+		)
+	})
+
+}
+
 func TestCheckEnumInContract(t *testing.T) {
 
 	t.Parallel()
@@ -187,6 +187,50 @@ func TestCheckEventDeclaration(t *testing.T) {
 		assert.IsType(t, &sema.RedeclarationError{}, errs[1])
 	})
 
+	t.Run("indexed", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+            event Transfer(indexed from: Address?, indexed to: Address?, amount: UFix64)
+		`)
+		require.NoError(t, err)
+
+		eventType := RequireGlobalType(t, checker.Elaboration, "Transfer").(*sema.CompositeType)
+
+		var indexed []bool
+		for _, parameter := range eventType.ConstructorParameters {
+			indexed = append(indexed, parameter.Indexed)
+		}
+
+		assert.Equal(t, []bool{true, true, false}, indexed)
+	})
+
+	t.Run("indexed, invalid type", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, ty := range []string{"[Int]", "{String: Int}", "S"} {
+
+			t.Run(ty, func(t *testing.T) {
+
+				_, err := ParseAndCheck(t,
+					fmt.Sprintf(
+						`
+                          struct S {}
+
+                          event E(indexed value: %s)
+                        `,
+						ty,
+					),
+				)
+
+				errs := ExpectCheckerErrors(t, err, 1)
+
+				assert.IsType(t, &sema.InvalidIndexedEventParameterTypeError{}, errs[0])
+			})
+		}
+	})
 }
 
 func TestCheckEmitEvent(t *testing.T) {
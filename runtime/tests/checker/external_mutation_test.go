@@ -0,0 +1,176 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckExternalMutation(t *testing.T) {
+
+	t.Parallel()
+
+	const declarations = `
+      struct S {
+          pub let xs: [Int]
+          pub var dict: {String: Int}
+          pub(set) var ys: [Int]
+
+          init() {
+              self.xs = []
+              self.dict = {}
+              self.ys = []
+          }
+
+          pub fun add(_ x: Int) {
+              self.xs.append(x)
+              self.dict.insert(key: x.toString(), x)
+          }
+      }
+    `
+
+	for _, mutation := range []string{
+		"s.xs.append(1)",
+		"s.xs.appendAll([1])",
+		"s.xs.insert(at: 0, 1)",
+		"s.xs.remove(at: 0)",
+		"s.xs.removeFirst()",
+		"s.xs.removeLast()",
+		`s.dict.insert(key: "a", 1)`,
+		`s.dict.remove(key: "a")`,
+	} {
+
+		mutation := mutation
+
+		t.Run(mutation, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      %s
+
+                      fun test(s: S) {
+                          %s
+                      }
+                    `,
+					declarations,
+					mutation,
+				),
+			)
+
+			errs := ExpectCheckerErrors(t, err, 1)
+
+			assert.IsType(t, &sema.ExternalMutationError{}, errs[0])
+		})
+	}
+
+	t.Run("non-mutating member", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t,
+			declarations+`
+              fun test(s: S): Bool {
+                  return s.xs.contains(1) && s.dict.containsKey("a")
+              }
+            `,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("publicly settable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t,
+			declarations+`
+              fun test(s: S) {
+                  s.ys.append(1)
+              }
+            `,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("inside composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t,
+			declarations+`
+              fun test(s: S) {
+                  s.add(1)
+              }
+            `,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("local copy", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t,
+			declarations+`
+              fun test(s: S) {
+                  let xs = s.xs
+                  xs.append(1)
+              }
+            `,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("report only", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t,
+			declarations+`
+              fun test(s: S) {
+                  s.xs.append(1)
+              }
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithExternalMutationReportOnly(true),
+				},
+			},
+		)
+
+		require.NoError(t, err)
+
+		hints := checker.Hints()
+		require.Len(t, hints, 1)
+
+		assert.IsType(t, &sema.ExternalMutationHint{}, hints[0])
+	})
+}
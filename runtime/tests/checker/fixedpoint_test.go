@@ -29,6 +29,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/format"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
 )
 
 func TestCheckFixedPointLiteralTypeConversionInVariableDeclaration(t *testing.T) {
@@ -658,13 +659,24 @@ func TestCheckFixedPointLiteralScales(t *testing.T) {
 					),
 				)
 
-				if i <= scale {
+				// Literals without a type annotation are inferred to have type `UFix64`
+
+				expectedErrorCount := 0
+				if i > scale {
+					expectedErrorCount++
+				}
+				if i > sema.Fix64Scale {
+					expectedErrorCount++
+				}
+
+				if expectedErrorCount == 0 {
 					assert.NoError(t, err)
 				} else {
-					errs := ExpectCheckerErrors(t, err, 2)
+					errs := ExpectCheckerErrors(t, err, expectedErrorCount)
 
-					assert.IsType(t, &sema.InvalidFixedPointLiteralScaleError{}, errs[0])
-					assert.IsType(t, &sema.InvalidFixedPointLiteralScaleError{}, errs[1])
+					for _, err := range errs {
+						assert.IsType(t, &sema.InvalidFixedPointLiteralScaleError{}, err)
+					}
 				}
 			}
 		})
@@ -710,3 +722,91 @@ func TestCheckFixedPointMinMax(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckFixedPointRoundingFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	parseAndCheck := func(t *testing.T, code string) error {
+		_, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithPredeclaredValues(
+						stdlib.BuiltinValues().ToSemaValueDeclarations(),
+					),
+				},
+			},
+		)
+		return err
+	}
+
+	for _, ty := range sema.AllFixedPointTypes {
+		// Only test leaf types
+		switch ty {
+		case sema.FixedPointType, sema.SignedFixedPointType:
+			continue
+		}
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			err := parseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      let x: %[1]s = 1.0
+                      let a: %[1]s = x.multiplyRound(3.0, rule: RoundingRule.towardZero)
+                      let b: %[1]s = x.divideRound(3.0, rule: RoundingRule.nearestHalfEven)
+                    `,
+					ty,
+				),
+			)
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("all cases", func(t *testing.T) {
+
+		err := parseAndCheck(t, `
+          let rules: [RoundingRule] = RoundingRule.allCases
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("missing rule", func(t *testing.T) {
+
+		err := parseAndCheck(t, `
+          let x: UFix64 = 1.0
+          let y = x.divideRound(3.0)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ArgumentCountError{}, errs[0])
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+
+		err := parseAndCheck(t, `
+          let x: UFix64 = 1.0
+          let y = x.divideRound(3.0, rule: 1)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("integer", func(t *testing.T) {
+
+		err := parseAndCheck(t, `
+          let x: Int = 1
+          let y = x.divideRound(3, rule: RoundingRule.towardZero)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
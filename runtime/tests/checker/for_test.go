@@ -255,3 +255,163 @@ func TestCheckInvalidForShadowing(t *testing.T) {
 
 	assert.IsType(t, &sema.RedeclarationError{}, errs[0])
 }
+
+func TestCheckForDictionary(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      fun test(): [String] {
+          let keys: [String] = []
+          let xs = {"a": 1, "b": 2}
+          for key in xs {
+              keys.append(key)
+          }
+          return keys
+      }
+    `)
+
+	assert.NoError(t, err)
+}
+
+func TestCheckForRange(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("exclusive", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(n: Int): Int {
+              var sum = 0
+              for i in 0..<n {
+                  sum = sum + i
+              }
+              return sum
+          }
+        `)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("inclusive, inferred element type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(n: UInt8): UInt8 {
+              var sum: UInt8 = 0
+              for i in 1...n {
+                  sum = sum + i
+              }
+              return sum
+          }
+        `)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestCheckForIterator(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Countdown {
+              var current: Int
+
+              init(from: Int) {
+                  self.current = from
+              }
+
+              fun next(): Int? {
+                  if self.current <= 0 {
+                      return nil
+                  }
+                  self.current = self.current - 1
+                  return self.current + 1
+              }
+          }
+
+          fun test(): Int {
+              var sum = 0
+              for i in Countdown(from: 3) {
+                  sum = sum + i
+              }
+              return sum
+          }
+        `)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("non-optional result", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              fun next(): Int {
+                  return 1
+              }
+          }
+
+          fun test() {
+              for i in S() {}
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchWithDescriptionError{}, errs[0])
+	})
+
+	t.Run("private next function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              priv fun next(): Int? {
+                  return nil
+              }
+          }
+
+          fun test() {
+              for i in S() {}
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchWithDescriptionError{}, errs[0])
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              fun next(): Int? {
+                  return nil
+              }
+          }
+
+          fun test() {
+              let r <- create R()
+              for i in r {}
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.UnsupportedResourceForLoopError{}, errs[0])
+	})
+}
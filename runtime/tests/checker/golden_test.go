@@ -0,0 +1,191 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+var updateGoldenFiles = flag.Bool("update", false, "update the golden files")
+
+const diagnosticsCorpusPath = "testdata/diagnostics"
+
+// TestCheckDiagnosticsGolden parses and checks each program of the corpus,
+// and compares the rendered diagnostics against the golden file of the program.
+//
+// To add a regression case, add a `.cdc` file to the corpus,
+// run the test with the `-update` flag, e.g.
+// `go test ./runtime/tests/checker -run TestCheckDiagnosticsGolden -update`,
+// and review and commit the generated `.golden` file.
+// Changes to error messages are updated the same way.
+//
+// Programs are checked with linting enabled, and suppression comments are honored.
+//
+func TestCheckDiagnosticsGolden(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join(diagnosticsCorpusPath, "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), ".cdc")
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			actual := renderDiagnostics(checkDiagnostics(t, string(code)))
+
+			goldenPath := strings.TrimSuffix(path, ".cdc") + ".golden"
+
+			if *updateGoldenFiles {
+				err = os.WriteFile(goldenPath, []byte(actual), 0644)
+				require.NoError(t, err)
+			}
+
+			expected, err := os.ReadFile(goldenPath)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), actual)
+		})
+	}
+}
+
+// checkDiagnostics returns the diagnostics of parsing and checking the given code.
+//
+// The program is checked even if there are parsing errors,
+// as long as the parser produced a program
+//
+func checkDiagnostics(t *testing.T, code string) []sema.Diagnostic {
+	var diagnostics []sema.Diagnostic
+
+	program, err := parser2.ParseProgram(code)
+	if err != nil {
+		parentErr, ok := err.(errors.ParentError)
+		require.True(t, ok)
+
+		for _, childErr := range parentErr.ChildErrors() {
+			diagnostics = append(
+				diagnostics,
+				sema.NewErrorDiagnostic(childErr, utils.TestLocation),
+			)
+		}
+	}
+
+	if program == nil {
+		return diagnostics
+	}
+
+	checker, err := sema.NewChecker(
+		program,
+		utils.TestLocation,
+		sema.WithAccessCheckMode(sema.AccessCheckModeStrict),
+		sema.WithLintingEnabled(true),
+		sema.WithDiagnosticConfig(&sema.DiagnosticConfig{
+			Suppressions: parser2.ParseDiagnosticSuppressions(code),
+		}),
+	)
+	require.NoError(t, err)
+
+	// Errors are reported as diagnostics
+	_ = checker.Check()
+
+	return append(diagnostics, checker.Diagnostics()...)
+}
+
+// renderDiagnostics renders the given diagnostics in a stable, line-based format,
+// sorted by their position, e.g.
+//
+//   2:17-2:19 error TypeMismatchError: mismatched types: expected `Int`, got `String`
+//     1:8-1:8 note: previously declared here
+//
+func renderDiagnostics(diagnostics []sema.Diagnostic) string {
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].StartPos.Offset < diagnostics[j].StartPos.Offset
+	})
+
+	var sb strings.Builder
+
+	for _, diagnostic := range diagnostics {
+		sb.WriteString(
+			fmt.Sprintf(
+				"%s %s %s: %s",
+				renderRange(diagnostic.Range),
+				renderSeverity(diagnostic.Severity),
+				diagnostic.Code,
+				diagnostic.Message,
+			),
+		)
+		if diagnostic.SecondaryMessage != "" {
+			sb.WriteString(": ")
+			sb.WriteString(diagnostic.SecondaryMessage)
+		}
+		sb.WriteByte('\n')
+
+		for _, related := range diagnostic.Related {
+			sb.WriteString(
+				fmt.Sprintf(
+					"  %s note: %s\n",
+					renderRange(related.Range),
+					related.Message,
+				),
+			)
+		}
+	}
+
+	return sb.String()
+}
+
+func renderRange(r ast.Range) string {
+	return fmt.Sprintf(
+		"%d:%d-%d:%d",
+		r.StartPos.Line,
+		r.StartPos.Column,
+		r.EndPos.Line,
+		r.EndPos.Column,
+	)
+}
+
+func renderSeverity(severity sema.DiagnosticSeverity) string {
+	return strings.ToLower(
+		strings.TrimPrefix(severity.String(), "DiagnosticSeverity"),
+	)
+}
@@ -288,6 +288,78 @@ func TestCheckImportResolutionSplit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckInvalidImportResolutionMissing(t *testing.T) {
+
+	t.Parallel()
+
+	importedAddress := common.BytesToAddress([]byte{0x1})
+
+	importedCheckerX, err := ParseAndCheckWithOptions(t,
+		`
+          pub let x = 1
+        `,
+		ParseAndCheckOptions{
+			Location: common.AddressLocation{
+				Address: importedAddress,
+				Name:    "x",
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = ParseAndCheckWithOptions(t,
+		`
+           import x, y as z from 0x1
+
+           pub let a = x
+           pub let b = z
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithLocationHandler(
+					func(identifiers []ast.Identifier, location common.Location) (result []sema.ResolvedLocation, err error) {
+						// Only resolve identifiers which are declared at the location
+						for _, identifier := range identifiers {
+							if identifier.Identifier != "x" {
+								continue
+							}
+							result = append(result, sema.ResolvedLocation{
+								Location: common.AddressLocation{
+									Address: importedAddress,
+									Name:    identifier.Identifier,
+								},
+								Identifiers: []ast.Identifier{
+									identifier,
+								},
+							})
+						}
+						return
+					},
+				),
+				sema.WithImportHandler(
+					func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+						return sema.ElaborationImport{
+							Elaboration: importedCheckerX.Elaboration,
+						}, nil
+					},
+				),
+			},
+		},
+	)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	require.IsType(t, &sema.NotExportedError{}, errs[0])
+
+	notExportedErr := errs[0].(*sema.NotExportedError)
+	assert.Equal(t, "y", notExportedErr.Name)
+	assert.Equal(t,
+		common.AddressLocation{Address: importedAddress},
+		notExportedErr.ImportLocation,
+	)
+	assert.Equal(t, []string{"x"}, notExportedErr.Available)
+}
+
 func TestCheckImportAll(t *testing.T) {
 
 	t.Parallel()
@@ -699,7 +771,7 @@ func TestCheckImportVirtual(t *testing.T) {
 
 	fooType.Fields = []string{"bar"}
 
-	fooType.Members = sema.NewStringMemberOrderedMap()
+	fooType.Members = &sema.StringMemberOrderedMap{}
 	fooType.Members.Set(
 		"bar",
 		sema.NewPublicFunctionMember(
@@ -711,7 +783,7 @@ func TestCheckImportVirtual(t *testing.T) {
 			"",
 		))
 
-	valueElements := sema.NewStringImportElementOrderedMap()
+	valueElements := &sema.StringImportElementOrderedMap{}
 
 	valueElements.Set("Foo", sema.ImportElement{
 		DeclarationKind: common.DeclarationKindStructure,
@@ -736,3 +808,214 @@ func TestCheckImportVirtual(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestCheckImportAlias(t *testing.T) {
+
+	t.Parallel()
+
+	locationA := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x1}),
+		Name:    "Token",
+	}
+
+	locationB := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x2}),
+		Name:    "Token",
+	}
+
+	importedCheckerA, err := ParseAndCheckWithOptions(t,
+		`
+          pub contract Token {
+              pub struct Vault {
+                  pub let balance: Int
+                  init() { self.balance = 1 }
+              }
+          }
+        `,
+		ParseAndCheckOptions{
+			Location: locationA,
+		},
+	)
+	require.NoError(t, err)
+
+	importedCheckerB, err := ParseAndCheckWithOptions(t,
+		`
+          pub contract Token {
+              pub struct Vault {
+                  pub let amount: UFix64
+                  init() { self.amount = 1.0 }
+              }
+          }
+        `,
+		ParseAndCheckOptions{
+			Location: locationB,
+		},
+	)
+	require.NoError(t, err)
+
+	importHandler := sema.WithImportHandler(
+		func(_ *sema.Checker, location common.Location, _ ast.Range) (sema.Import, error) {
+			switch location {
+			case locationA:
+				return sema.ElaborationImport{
+					Elaboration: importedCheckerA.Elaboration,
+				}, nil
+
+			case locationB:
+				return sema.ElaborationImport{
+					Elaboration: importedCheckerB.Elaboration,
+				}, nil
+
+			default:
+				return nil, fmt.Errorf("unknown location: %s", location)
+			}
+		},
+	)
+
+	locationHandler := sema.WithLocationHandler(
+		func(identifiers []ast.Identifier, location common.Location) ([]sema.ResolvedLocation, error) {
+			addressLocation := location.(common.AddressLocation)
+			return []sema.ResolvedLocation{
+				{
+					Location: common.AddressLocation{
+						Address: addressLocation.Address,
+						Name:    identifiers[0].Identifier,
+					},
+					Identifiers: identifiers,
+				},
+			}, nil
+		},
+	)
+
+	t.Run("same name, different locations", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t,
+			`
+              import Token as TokenA from 0x1
+              import Token as TokenB from 0x2
+
+              pub let a: TokenA.Vault = TokenA.Vault()
+              pub let b: TokenB.Vault = TokenB.Vault()
+              pub let balance: Int = a.balance
+              pub let amount: UFix64 = b.amount
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					importHandler,
+					locationHandler,
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		aType := RequireGlobalValue(t, checker.Elaboration, "a")
+		bType := RequireGlobalValue(t, checker.Elaboration, "b")
+
+		assert.Equal(t, common.TypeID("A.0000000000000001.Token.Vault"), aType.ID())
+		assert.Equal(t, common.TypeID("A.0000000000000002.Token.Vault"), bType.ID())
+	})
+
+	t.Run("mismatched aliased types", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              import Token as TokenA from 0x1
+              import Token as TokenB from 0x2
+
+              pub let a: TokenA.Vault = TokenB.Vault()
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					importHandler,
+					locationHandler,
+				},
+			},
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("original name is not declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              import Token as TokenA from 0x1
+
+              pub let a = Token.Vault()
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					importHandler,
+					locationHandler,
+				},
+			},
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("same alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              import Token as T from 0x1
+              import Token as T from 0x2
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					importHandler,
+					locationHandler,
+				},
+			},
+		)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.RedeclarationError{}, errs[0])
+		assert.IsType(t, &sema.RedeclarationError{}, errs[1])
+	})
+
+	t.Run("missing aliased declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              import Vault as V from 0x1
+
+              pub let v: V = V()
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					importHandler,
+					sema.WithLocationHandler(
+						func(identifiers []ast.Identifier, location common.Location) ([]sema.ResolvedLocation, error) {
+							return []sema.ResolvedLocation{
+								{
+									Location:    locationA,
+									Identifiers: identifiers,
+								},
+							}, nil
+						},
+					),
+				},
+			},
+		)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotExportedError{}, errs[0])
+	})
+}
@@ -0,0 +1,145 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckInclusiveRange(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("inclusive", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let range = 1...10
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.InclusiveRangeType{
+				MemberType: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "range"),
+		)
+	})
+
+	t.Run("exclusive", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let n: UInt64 = 10
+          let range = 0..<n
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.InclusiveRangeType{
+				MemberType: sema.UInt64Type,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "range"),
+		)
+	})
+
+	t.Run("annotation", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let range: InclusiveRange<UInt8> = 1...10
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.InclusiveRangeType{
+				MemberType: sema.UInt8Type,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "range"),
+		)
+	})
+
+	t.Run("members", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let range = 1...10
+          let start = range.start
+          let end = range.end
+          let isEmpty = range.isEmpty
+          let contains = range.contains(5)
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t, sema.IntType, RequireGlobalValue(t, checker.Elaboration, "start"))
+		assert.Equal(t, sema.IntType, RequireGlobalValue(t, checker.Elaboration, "end"))
+		assert.Equal(t, sema.BoolType, RequireGlobalValue(t, checker.Elaboration, "isEmpty"))
+		assert.Equal(t, sema.BoolType, RequireGlobalValue(t, checker.Elaboration, "contains"))
+	})
+
+	t.Run("invalid, mismatched operand types", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let a: Int8 = 1
+          let b: Int16 = 2
+          let range = a...b
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBinaryOperandsError{}, errs[0])
+	})
+
+	t.Run("invalid, non-integer operands", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let range = 1.0...2.0
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBinaryOperandsError{}, errs[0])
+	})
+
+	t.Run("invalid, non-integer type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let range: InclusiveRange<String>? = nil
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
@@ -0,0 +1,246 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckIncremental(t *testing.T) {
+
+	t.Parallel()
+
+	check := func(t *testing.T, code string, options ...sema.Option) (*sema.Checker, []error) {
+		program, err := parser2.ParseProgram(code)
+		require.NoError(t, err)
+
+		checker, err := sema.NewChecker(
+			program,
+			utils.TestLocation,
+			append(
+				[]sema.Option{
+					sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+				},
+				options...,
+			)...,
+		)
+		require.NoError(t, err)
+
+		err = checker.Check()
+		if err == nil {
+			return checker, nil
+		}
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		return checker, checkerErr.Errors
+	}
+
+	checkIncremental := func(
+		t *testing.T,
+		previousChecker *sema.Checker,
+		previousCode string,
+		code string,
+		options ...sema.Option,
+	) []error {
+		program, err := parser2.ParseProgram(code)
+		require.NoError(t, err)
+
+		incrementalCheck := sema.NewIncrementalCheck(previousChecker, previousCode, program, code)
+
+		checker, err := sema.NewChecker(
+			program,
+			utils.TestLocation,
+			append(
+				[]sema.Option{
+					sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+					sema.WithIncrementalCheck(incrementalCheck),
+				},
+				options...,
+			)...,
+		)
+		require.NoError(t, err)
+
+		err = checker.Check()
+		if err == nil {
+			return nil
+		}
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		// The result must be the same as the result of a full check
+
+		_, fullErrs := check(t, code, options...)
+		assert.Equal(t, fullErrs, checkerErr.Errors)
+
+		return checkerErr.Errors
+	}
+
+	const previousCode = `
+      fun f(): Int {
+          return "f"
+      }
+
+      fun g(): Int {
+          return 1
+      }
+    `
+
+	t.Run("unaffected function", func(t *testing.T) {
+
+		t.Parallel()
+
+		previousChecker, previousErrs := check(t, previousCode)
+		require.Len(t, previousErrs, 1)
+
+		code := strings.Replace(previousCode, "return 1", `return "g"`, 1)
+
+		errs := checkIncremental(t, previousChecker, previousCode, code)
+		require.Len(t, errs, 2)
+
+		// The error of f is reused, g is checked again
+
+		assert.Same(t, previousErrs[0], errs[0])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	})
+
+	t.Run("affected function", func(t *testing.T) {
+
+		t.Parallel()
+
+		const previousCode = `
+          fun f(): Int {
+              let x: Bool = 1
+              return g()
+          }
+
+          fun g(): Int {
+              return 1
+          }
+        `
+
+		previousChecker, previousErrs := check(t, previousCode)
+		require.Len(t, previousErrs, 1)
+
+		const code = `
+          fun f(): Int {
+              let x: Bool = 1
+              return g()
+          }
+
+          fun g(): String {
+              return "1"
+          }
+        `
+
+		errs := checkIncremental(t, previousChecker, previousCode, code)
+		require.Len(t, errs, 2)
+
+		// f calls g, so it is checked again
+
+		assert.NotSame(t, previousErrs[0], errs[0])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	})
+
+	t.Run("moved function", func(t *testing.T) {
+
+		t.Parallel()
+
+		previousChecker, previousErrs := check(t, previousCode)
+		require.Len(t, previousErrs, 1)
+
+		code := "\n" + previousCode
+
+		errs := checkIncremental(t, previousChecker, previousCode, code)
+		require.Len(t, errs, 1)
+
+		assert.NotSame(t, previousErrs[0], errs[0])
+	})
+
+	t.Run("position info enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		previousChecker, previousErrs := check(
+			t,
+			previousCode,
+			sema.WithPositionInfoEnabled(true),
+		)
+		require.Len(t, previousErrs, 1)
+
+		errs := checkIncremental(
+			t,
+			previousChecker,
+			previousCode,
+			previousCode,
+			sema.WithPositionInfoEnabled(true),
+		)
+		require.Len(t, errs, 1)
+
+		assert.NotSame(t, previousErrs[0], errs[0])
+	})
+
+	t.Run("composite member function", func(t *testing.T) {
+
+		t.Parallel()
+
+		const previousCode = `
+          struct S {
+              fun f(): Int {
+                  return "f"
+              }
+
+              fun g(): Int {
+                  return 1
+              }
+          }
+        `
+
+		previousChecker, previousErrs := check(t, previousCode)
+		require.Len(t, previousErrs, 1)
+
+		const code = `
+          struct S {
+              fun f(): Int {
+                  return "f"
+              }
+
+              fun g(): Int {
+                  return 2
+              }
+          }
+        `
+
+		errs := checkIncremental(t, previousChecker, previousCode, code)
+		require.Len(t, errs, 1)
+
+		assert.Same(t, previousErrs[0], errs[0])
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -531,3 +532,456 @@ func TestCheckInvalidFieldInitializationWithUseOfUninitializedInPrecondition(t *
 
 	assert.IsType(t, &sema.UninitializedFieldAccessError{}, errs[0])
 }
+
+func TestCheckFieldInitializationInNestedBranches(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(a: Bool, b: Bool) {
+                  if a {
+                      if b {
+                          self.foo = 1
+                      } else {
+                          self.foo = 2
+                      }
+                  } else {
+                      self.foo = 3
+                  }
+              }
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid, missing in nested branch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(a: Bool, b: Bool) {
+                  if a {
+                      if b {
+                          self.foo = 1
+                      }
+                  } else {
+                      self.foo = 2
+                  }
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		uninitializedErr := errs[0].(*sema.FieldUninitializedError)
+
+		require.Len(t, uninitializedErr.UninitializedBranches, 1)
+		assert.Equal(t,
+			ast.Position{Offset: 139, Line: 7, Column: 22},
+			uninitializedErr.UninitializedBranches[0].StartPos,
+		)
+		assert.Equal(t,
+			"not initialized on all paths, not in the branches at 7:22",
+			uninitializedErr.SecondaryError(),
+		)
+		assert.Len(t, uninitializedErr.ErrorNotes(), 1)
+	})
+
+	t.Run("invalid, missing in multiple branches", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(a: Bool, b: Bool) {
+                  if a {
+                      if b {
+                          self.foo = 1
+                      } else {}
+                  } else {
+                      if b {} else {
+                          self.foo = 2
+                      }
+                  }
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		uninitializedErr := errs[0].(*sema.FieldUninitializedError)
+
+		require.Len(t, uninitializedErr.UninitializedBranches, 2)
+		assert.Equal(t, 9, uninitializedErr.UninitializedBranches[0].StartPos.Line)
+		assert.Equal(t, 11, uninitializedErr.UninitializedBranches[1].StartPos.Line)
+	})
+
+	t.Run("invalid, not initialized at all", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(a: Bool) {
+                  if a {} else {}
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		uninitializedErr := errs[0].(*sema.FieldUninitializedError)
+
+		assert.Empty(t, uninitializedErr.UninitializedBranches)
+		assert.Equal(t, "not initialized", uninitializedErr.SecondaryError())
+	})
+
+	t.Run("invalid, switch without default", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(x: Int) {
+                  switch x {
+                  case 1:
+                      self.foo = 1
+                  case 2:
+                      self.foo = 2
+                  }
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		uninitializedErr := errs[0].(*sema.FieldUninitializedError)
+
+		// The path on which no case is taken is the whole switch statement
+
+		require.Len(t, uninitializedErr.UninitializedBranches, 1)
+		assert.Equal(t, 6, uninitializedErr.UninitializedBranches[0].StartPos.Line)
+	})
+}
+
+func TestCheckInitializingFunction(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+              let bar: Int
+              var baz: Int
+
+              init() {
+                  self.foo = 1
+                  self.setup(2)
+                  self.baz = self.bar
+              }
+
+              /// Initializes the bar and baz fields.
+              ///
+              /// @initializes(bar, baz)
+              priv fun setup(_ value: Int) {
+                  self.bar = value
+                  self.baz = self.bar
+              }
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("valid, nested", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+              let bar: Int
+
+              init() {
+                  self.setupBoth()
+              }
+
+              /// @initializes(foo, bar)
+              priv fun setupBoth() {
+                  self.foo = 1
+                  self.setupBar()
+              }
+
+              /// @initializes(bar)
+              priv fun setupBar() {
+                  self.bar = 2
+              }
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid, field not initialized in function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init(a: Bool) {
+                  self.setup(a)
+              }
+
+              /// @initializes(foo)
+              priv fun setup(_ a: Bool) {
+                  if a {
+                      self.foo = 1
+                  }
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		assert.Len(t, errs[0].(*sema.FieldUninitializedError).UninitializedBranches, 1)
+	})
+
+	t.Run("invalid, field not initialized in initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+              let bar: Int
+
+              init() {
+                  self.setup()
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.FieldUninitializedError{}, errs[0])
+		assert.Equal(t, "bar", errs[0].(*sema.FieldUninitializedError).Name)
+	})
+
+	t.Run("invalid, repeated initialization", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init() {
+                  self.foo = 1
+                  self.setup()
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.AssignmentToConstantMemberError{}, errs[0])
+	})
+
+	t.Run("invalid, use of other field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+              let bar: Int
+
+              init() {
+                  self.setup()
+                  self.bar = 2
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = self.bar
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.UninitializedUseError{}, errs[0])
+	})
+
+	t.Run("invalid, initialization of other field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+              let bar: Int
+
+              init() {
+                  self.setup()
+                  self.bar = 2
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = 1
+                  self.setupBar()
+              }
+
+              /// @initializes(bar)
+              priv fun setupBar() {
+                  self.bar = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.AssignmentToConstantMemberError{}, errs[0])
+	})
+
+	t.Run("invalid, call outside of initialization", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              var foo: Int
+
+              init() {
+                  self.foo = 1
+              }
+
+              fun reset() {
+                  self.setup()
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidInitializingFunctionUseError{}, errs[0])
+	})
+
+	t.Run("invalid, use as value", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              var foo: Int
+
+              init() {
+                  let setup = self.setup
+                  setup()
+              }
+
+              /// @initializes(foo)
+              priv fun setup() {
+                  self.foo = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.InvalidInitializingFunctionUseError{}, errs[0])
+		assert.IsType(t, &sema.FieldUninitializedError{}, errs[1])
+	})
+
+	t.Run("invalid, not private", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init() {
+                  self.setup()
+              }
+
+              /// @initializes(foo)
+              pub fun setup() {
+                  self.foo = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidInitializingFunctionAccessError{}, errs[0])
+	})
+
+	t.Run("invalid, unknown field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct Test {
+              let foo: Int
+
+              init() {
+                  self.setup()
+              }
+
+              /// @initializes(foo, bar)
+              priv fun setup() {
+                  self.foo = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.UnknownInitializedFieldError{}, errs[0])
+	})
+}
@@ -1559,6 +1559,96 @@ func TestCheckInvalidContractInterfaceConformanceMissingTypeRequirement(t *testi
 	assert.IsType(t, &sema.ConformanceError{}, errs[0])
 }
 
+func TestCheckInvalidInterfaceConformanceAllProblemsReported(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t,
+		`
+          contract interface Test {
+
+              resource interface Provider {
+                  pub let balance: Int
+                  pub var count: Int
+                  pub fun withdraw(amount: Int): Int
+                  pub fun deposit(amount: Int)
+                  pub fun name(): String
+
+                  init(balance: Int)
+              }
+          }
+
+          contract TestImpl: Test {
+
+              resource Impl: Test.Provider {
+                  pub let balance: Int
+                  pub let count: Int
+
+                  pub fun withdraw(amount: UInt): Int {
+                      return 0
+                  }
+
+                  init(initialBalance: UInt) {
+                      self.balance = Int(initialBalance)
+                      self.count = 0
+                  }
+              }
+          }
+        `,
+	)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	var conformanceErr *sema.ConformanceError
+	require.ErrorAs(t, errs[0], &conformanceErr)
+
+	assert.Len(t, conformanceErr.MissingMembers, 2)
+	assert.Len(t, conformanceErr.MemberMismatches, 2)
+	assert.NotNil(t, conformanceErr.InitializerMismatch)
+
+	assert.Equal(t,
+		"initializer mismatch: expected `init(balance: Int)`, got `init(initialBalance: UInt)`; "+
+			"missing function `pub fun deposit(amount: Int)`; "+
+			"missing function `pub fun name(): String`; "+
+			"mismatched field `count`: expected `pub var count: Int`, got `pub let count: Int`; "+
+			"mismatched function `withdraw`: expected `pub fun withdraw(amount: Int): Int`, got `pub fun withdraw(amount: UInt): Int`",
+		conformanceErr.SecondaryError(),
+	)
+
+	notes := conformanceErr.ErrorNotes()
+	require.Len(t, notes, 2)
+	assert.Equal(t,
+		"mismatch here, expected `pub var count: Int`",
+		notes[0].Message(),
+	)
+}
+
+func TestCheckInvalidContractInterfaceConformanceMissingNestedTypeReported(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t,
+		`
+          contract interface Test {
+              struct Nested {}
+              resource R {}
+          }
+
+          contract TestImpl: Test {}
+        `,
+	)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	var conformanceErr *sema.ConformanceError
+	require.ErrorAs(t, errs[0], &conformanceErr)
+
+	assert.Equal(t,
+		"missing nested structure `Nested`; missing nested resource `R`",
+		conformanceErr.SecondaryError(),
+	)
+}
+
 func TestCheckInvalidContractInterfaceConformanceTypeRequirementKindMismatch(t *testing.T) {
 
 	t.Parallel()
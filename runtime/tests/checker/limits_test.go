@@ -0,0 +1,225 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckExpressionDepthLimit(t *testing.T) {
+
+	t.Parallel()
+
+	nestedExpression := func(depth int) string {
+		return strings.Repeat("(", depth) + "1" + strings.Repeat(" + 1)", depth)
+	}
+
+	check := func(code string) error {
+		_, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithMaximumExpressionDepth(10),
+				},
+			},
+		)
+		return err
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check("let x = " + nestedExpression(4))
+		require.NoError(t, err)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check("let x = " + nestedExpression(10))
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var limitErr *sema.ExpressionDepthLimitReachedError
+		require.ErrorAs(t, errs[0], &limitErr)
+		assert.Equal(t, 10, limitErr.Limit)
+	})
+
+	t.Run("limit exceeded in function", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          fun test(): Int {
+              return ` + nestedExpression(20) + `
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ExpressionDepthLimitReachedError{}, errs[0])
+	})
+
+	t.Run("not limited", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, "let x = "+nestedExpression(100))
+		require.NoError(t, err)
+	})
+}
+
+func TestCheckDeclarationCountLimit(t *testing.T) {
+
+	t.Parallel()
+
+	check := func(code string) error {
+		_, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithMaximumDeclarationCount(4),
+				},
+			},
+		)
+		return err
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          struct S {
+              let x: Int
+
+              init() {
+                  self.x = 1
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("limit exceeded, nested declarations", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          fun test() {}
+
+          struct S {
+              let x: Int
+              let y: Int
+
+              init() {
+                  self.x = 1
+                  self.y = 2
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var limitErr *sema.DeclarationCountLimitReachedError
+		require.ErrorAs(t, errs[0], &limitErr)
+		assert.Equal(t, 4, limitErr.Limit)
+		assert.Equal(t, 4, limitErr.StartPos.Line)
+	})
+}
+
+func TestCheckTypeComplexityLimit(t *testing.T) {
+
+	t.Parallel()
+
+	nestedType := func(depth int) string {
+		return strings.Repeat("[", depth) + "Int" + strings.Repeat("]", depth)
+	}
+
+	check := func(code string) error {
+		_, err := ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithMaximumTypeComplexity(4),
+				},
+			},
+		)
+		return err
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          let x: [{String: Int}] = []
+          let y: ` + nestedType(3) + ` = []
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          let x: ` + nestedType(20) + ` = []
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var limitErr *sema.TypeComplexityLimitReachedError
+		require.ErrorAs(t, errs[0], &limitErr)
+		assert.Equal(t, 4, limitErr.Limit)
+	})
+
+	t.Run("limit exceeded, function type", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          fun test(f: ((Int, Int, Int): Int)) {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeComplexityLimitReachedError{}, errs[0])
+	})
+
+	t.Run("limit applies to each type", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := check(`
+          let x: [[Int]] = []
+          let y: [[Int]] = []
+          let z: [[Int]] = []
+        `)
+		require.NoError(t, err)
+	})
+}
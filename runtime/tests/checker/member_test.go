@@ -24,7 +24,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
 )
 
 func TestCheckOptionalChainingNonOptionalFieldRead(t *testing.T) {
@@ -297,3 +300,146 @@ func TestCheckFunctionTypeReceiverType(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestCheckMemberSlots(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      struct interface I {
+          fun g()
+      }
+
+      struct S: I {
+          let x: Int
+
+          init() {
+              self.x = 1
+          }
+
+          fun f(): Int {
+              return self.x
+          }
+
+          fun g() {}
+      }
+
+      fun test() {
+          let s = S()
+          s.f()
+          let i: {I} = s
+          i.g()
+      }
+    `)
+	require.NoError(t, err)
+
+	compositeType := checker.Elaboration.CompositeTypes[checker.Location.TypeID("S")]
+
+	memberSlots := checker.Elaboration.CompositeTypeMemberSlots[compositeType]
+	require.NotNil(t, memberSlots)
+	assert.Same(t, compositeType, memberSlots.Type)
+
+	// The slots are in declaration order
+
+	memberSlot := func(name string) int {
+		slot, ok := memberSlots.Slot(name)
+		require.True(t, ok)
+		return slot
+	}
+
+	xSlot := memberSlot("x")
+	fSlot := memberSlot("f")
+	gSlot := memberSlot("g")
+
+	assert.Less(t, xSlot, fSlot)
+	assert.Less(t, fSlot, gSlot)
+
+	_, ok := memberSlots.Slot("unknown")
+	assert.False(t, ok)
+
+	// Members of interfaces have no slot
+
+	slots := map[string][]int{}
+	for expression, memberInfo := range checker.Elaboration.MemberExpressionMemberInfos {
+		identifier := expression.Identifier.Identifier
+		slots[identifier] = append(slots[identifier], memberInfo.Slot)
+	}
+
+	assert.Equal(t,
+		map[string][]int{
+			"x": {xSlot, xSlot},
+			"f": {fSlot},
+			"g": {-1},
+		},
+		slots,
+	)
+}
+
+func TestCheckMemberSlotsImported(t *testing.T) {
+
+	t.Parallel()
+
+	importedChecker, err := ParseAndCheckWithOptions(t,
+		`
+          pub struct S {
+              pub let x: Int
+
+              init() {
+                  self.x = 1
+              }
+
+              pub fun f(): Int {
+                  return self.x
+              }
+          }
+        `,
+		ParseAndCheckOptions{
+			Location: utils.ImportedLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	checker, err := ParseAndCheckWithOptions(t,
+		`
+          import S from "imported"
+
+          fun test(): Int {
+              let s = S()
+              return s.x + s.f()
+          }
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithImportHandler(
+					func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+						return sema.ElaborationImport{
+							Elaboration: importedChecker.Elaboration,
+						}, nil
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// The member dispatch table of the imported type is resolved in the importing elaboration,
+	// and agrees with the one of the imported elaboration
+
+	compositeType := importedChecker.Elaboration.CompositeTypes[utils.ImportedLocation.TypeID("S")]
+
+	importedMemberSlots := importedChecker.Elaboration.CompositeTypeMemberSlots[compositeType]
+	require.NotNil(t, importedMemberSlots)
+
+	memberSlots := checker.Elaboration.CompositeTypeMemberSlots[compositeType]
+	require.NotNil(t, memberSlots)
+
+	for _, name := range []string{"x", "f"} {
+		importedSlot, ok := importedMemberSlots.Slot(name)
+		require.True(t, ok)
+
+		slot, ok := memberSlots.Slot(name)
+		require.True(t, ok)
+
+		assert.Equal(t, importedSlot, slot)
+	}
+}
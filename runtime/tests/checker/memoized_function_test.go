@@ -0,0 +1,273 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckMemoizedFunction(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          #memoize("Test.base")
+          #memoize("Test.fee")
+
+          contract Test {
+              let rate: UFix64
+              let name: String
+
+              init() {
+                  self.rate = 0.5
+                  self.name = "test"
+              }
+
+              fun base(): UFix64 {
+                  return UFix64(self.name.concat("!").length)
+              }
+
+              fun fee(): UFix64? {
+                  var total = 0.0
+                  var i = 0
+                  while i < 10 {
+                      total = total + self.rate * self.base()
+                      i = i + 1
+                  }
+                  let values = [total]
+                  values.append(1.0)
+                  return values[0]
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Len(t, checker.Elaboration.MemoizedFunctions, 2)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          #memoize("Test.fee")
+
+          contract Test {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidMemoizedFunctionError{}, errs[0])
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          #memoize("fee")
+
+          contract Test {
+              fun fee(): Int {
+                  return 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidMemoizedFunctionError{}, errs[0])
+	})
+
+	t.Run("not a contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          #memoize("S.fee")
+
+          struct S {
+              fun fee(): Int {
+                  return 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidMemoizedFunctionError{}, errs[0])
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          #memoize("Test.fee")
+
+          contract Test {
+              fun fee(amount: Int): Int {
+                  return amount
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidMemoizedFunctionError{}, errs[0])
+	})
+
+	t.Run("non-primitive return type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          #memoize("Test.fees")
+
+          contract Test {
+              fun fees(): [Int] {
+                  return [1, 2]
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidMemoizedFunctionError{}, errs[0])
+	})
+}
+
+func TestCheckImpureMemoizedFunction(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, body string) {
+
+		_, err := ParseAndCheck(t, `
+          #memoize("Test.fee")
+
+          contract Test {
+              var count: Int
+              let counts: [Int]
+              let rate: Int
+
+              event Computed()
+
+              init() {
+                  self.count = 0
+                  self.counts = []
+                  self.rate = 1
+              }
+
+              fun other(): Int {
+                  return 1
+              }
+
+              fun fee(): Int {
+                  `+body+`
+              }
+          }
+
+          fun compute(): Int {
+              return 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.ImpureMemoizedFunctionError{}, errs[0])
+	}
+
+	t.Run("variable field", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `return self.count`)
+	})
+
+	t.Run("non-primitive field", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `return self.counts.length`)
+	})
+
+	t.Run("assignment", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `
+          self.count = 1
+          return self.rate
+        `)
+	})
+
+	t.Run("emit", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `
+          emit Computed()
+          return self.rate
+        `)
+	})
+
+	t.Run("non-memoized function", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `return self.other()`)
+	})
+
+	t.Run("global function", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `return compute()`)
+	})
+
+	t.Run("built-in function", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `
+          let capability: Capability? = nil
+          if capability?.check<&Int>() ?? false {
+              return 1
+          }
+          return self.rate
+        `)
+	})
+
+	t.Run("function value", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t, `return [fun(): Int { return 1 }][0]()`)
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckNodeFacts(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("position info enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t,
+			`
+              fun test(a: Int): Int {
+                  let b = a > 0
+                  return a + 1
+              }
+            `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithPositionInfoEnabled(true),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		nodeFacts := checker.NodeFacts
+		require.NotNil(t, nodeFacts)
+
+		nodeIDs := nodeFacts.NodeIDs()
+		assert.Same(t, checker.Program.NodeIDs(), nodeIDs)
+
+		typeOf := func(element ast.Element) sema.Type {
+			id, ok := nodeIDs.ID(element)
+			require.True(t, ok)
+
+			if _, ok := element.(ast.Expression); ok {
+				ty, ok := nodeFacts.ExpressionType(id)
+				require.True(t, ok)
+				return ty
+			}
+
+			ty, ok := nodeFacts.DeclarationType(id)
+			require.True(t, ok)
+			return ty
+		}
+
+		functionDeclaration := checker.Program.FunctionDeclarations()[0]
+		statements := functionDeclaration.FunctionBlock.Block.Statements
+
+		variableDeclaration := statements[0].(*ast.VariableDeclaration)
+		comparison := variableDeclaration.Value.(*ast.BinaryExpression)
+		sum := statements[1].(*ast.ReturnStatement).Expression.(*ast.BinaryExpression)
+
+		assert.Equal(t,
+			&sema.FunctionType{
+				Parameters: []*sema.Parameter{
+					{
+						Identifier:     "a",
+						TypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+					},
+				},
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+			},
+			typeOf(functionDeclaration),
+		)
+		assert.Equal(t, sema.BoolType, typeOf(variableDeclaration))
+		assert.Equal(t, sema.BoolType, typeOf(comparison))
+		assert.Equal(t, sema.IntType, typeOf(comparison.Left))
+		assert.Equal(t, sema.IntType, typeOf(sum))
+		assert.Equal(t, sema.IntType, typeOf(sum.Right))
+
+		// Statements are neither expressions nor declarations
+
+		returnStatementID, ok := nodeIDs.ID(statements[1])
+		require.True(t, ok)
+
+		_, ok = nodeFacts.ExpressionType(returnStatementID)
+		assert.False(t, ok)
+
+		_, ok = nodeFacts.DeclarationType(returnStatementID)
+		assert.False(t, ok)
+	})
+
+	t.Run("position info disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `let x = 1`)
+		require.NoError(t, err)
+
+		assert.Nil(t, checker.NodeFacts)
+	})
+}
@@ -359,3 +359,174 @@ nextMatcher:
 		assert.NotNil(t, checker.Occurrences.Find(matcher.EndPos))
 	}
 }
+
+type testOccurrenceRole struct {
+	Pos  sema.Position
+	Role sema.OccurrenceRole
+}
+
+func testOccurrenceRoles(t *testing.T, checker *sema.Checker, declarationPos sema.Position) []testOccurrenceRole {
+	declaration := checker.Occurrences.Find(declarationPos)
+	require.NotNil(t, declaration)
+	require.NotNil(t, declaration.Origin)
+
+	var roles []testOccurrenceRole
+	for _, occurrence := range checker.Occurrences.ForOrigin(declaration.Origin) {
+		roles = append(roles, testOccurrenceRole{
+			Pos:  occurrence.StartPos,
+			Role: occurrence.Role,
+		})
+	}
+	return roles
+}
+
+func TestCheckOccurrenceRoles(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("read and write", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t, `
+        struct S {
+            var x: Int
+            init() {
+                self.x = 1
+            }
+        }
+
+        fun test() {
+            var a = 1
+            a = 2
+            let xs = [a]
+            xs[0] = a
+            let s = S()
+            s.x = xs[0]
+        }
+        `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithPositionInfoEnabled(true),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 10, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 11, Column: 12}, Role: sema.OccurrenceRoleWrite},
+				{Pos: sema.Position{Line: 12, Column: 22}, Role: sema.OccurrenceRoleRead},
+				{Pos: sema.Position{Line: 13, Column: 20}, Role: sema.OccurrenceRoleRead},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 10, Column: 16}),
+		)
+
+		// The container of an assigned element is written
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 12, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 13, Column: 12}, Role: sema.OccurrenceRoleWrite},
+				{Pos: sema.Position{Line: 15, Column: 18}, Role: sema.OccurrenceRoleRead},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 12, Column: 16}),
+		)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 3, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 5, Column: 21}, Role: sema.OccurrenceRoleWrite},
+				{Pos: sema.Position{Line: 15, Column: 14}, Role: sema.OccurrenceRoleWrite},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 3, Column: 16}),
+		)
+
+		// The object of an assigned member is only read
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 14, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 15, Column: 12}, Role: sema.OccurrenceRoleRead},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 14, Column: 16}),
+		)
+	})
+
+	t.Run("swap", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t, `
+        fun test() {
+            var a = 1
+            var b = 2
+            a <-> b
+        }
+        `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithPositionInfoEnabled(true),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 3, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 5, Column: 12}, Role: sema.OccurrenceRoleRead},
+				{Pos: sema.Position{Line: 5, Column: 12}, Role: sema.OccurrenceRoleWrite},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 3, Column: 16}),
+		)
+	})
+
+	t.Run("move and destroy", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckWithOptions(t, `
+        resource R {}
+
+        fun test() {
+            let r <- create R()
+            let r2 <- r
+            let rs <- [<-r2]
+            destroy rs
+        }
+        `,
+			ParseAndCheckOptions{
+				Options: []sema.Option{
+					sema.WithPositionInfoEnabled(true),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 5, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 6, Column: 22}, Role: sema.OccurrenceRoleMove},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 5, Column: 16}),
+		)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 6, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 7, Column: 25}, Role: sema.OccurrenceRoleMove},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 6, Column: 16}),
+		)
+
+		assert.Equal(t,
+			[]testOccurrenceRole{
+				{Pos: sema.Position{Line: 7, Column: 16}, Role: sema.OccurrenceRoleDeclaration},
+				{Pos: sema.Position{Line: 8, Column: 20}, Role: sema.OccurrenceRoleDestroy},
+			},
+			testOccurrenceRoles(t, checker, sema.Position{Line: 7, Column: 16}),
+		)
+	})
+}
@@ -105,7 +105,7 @@ func TestCheckIntegerBinaryOperations(t *testing.T) {
 			},
 			tests: []operationTest{
 				{sema.IntType, "1", "2", nil},
-				{sema.UFix64Type, "1.2", "3.4", nil},
+				{sema.UFix64Type, "3.4", "1.2", nil},
 				{sema.Fix64Type, "-1.2", "-3.4", nil},
 				{sema.UFix64Type, "1.2", "3", []error{
 					&sema.InvalidBinaryOperandsError{},
@@ -97,3 +97,102 @@ func TestCheckPragmaInvalidInvocationExprTypeArgs(t *testing.T) {
 	errs := ExpectCheckerErrors(t, err, 1)
 	assert.IsType(t, &sema.InvalidPragmaError{Message: "type arguments not supported"}, errs[0])
 }
+
+func TestCheckPragmaElaboration(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		#allowAccountLinking
+		#version("1.0")
+		#version(y)
+
+		pub fun test() {}
+	`)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+	assert.IsType(t, &sema.InvalidPragmaError{}, errs[0])
+
+	pragmas := checker.Elaboration.Pragmas
+	require.Len(t, pragmas, 2)
+
+	assert.Equal(t, "allowAccountLinking", pragmas[0].Identifier)
+	assert.Empty(t, pragmas[0].Arguments)
+
+	assert.Equal(t, "version", pragmas[1].Identifier)
+	assert.Equal(t, []string{"1.0"}, pragmas[1].Arguments)
+
+	assert.True(t, checker.Elaboration.HasPragma("allowAccountLinking"))
+	assert.True(t, checker.Elaboration.HasPragma("version"))
+	assert.False(t, checker.Elaboration.HasPragma("y"))
+}
+
+func TestCheckPragmaFeatureGate(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun test(account: AuthAccount) {
+          account.link<&Int>(/private/foo, target: /storage/foo)
+      }
+    `
+
+	options := ParseAndCheckOptions{
+		Options: []sema.Option{
+			sema.WithFeatureGates(
+				sema.FeatureGate{
+					Pragma:        "allowAccountLinking",
+					ContainerType: sema.AuthAccountType,
+					Member:        sema.AuthAccountLinkField,
+				},
+			),
+		},
+	}
+
+	t.Run("pragma not declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t, code, options)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		var featureNotEnabledErr *sema.FeatureNotEnabledError
+		require.ErrorAs(t, errs[0], &featureNotEnabledErr)
+		assert.Equal(t, sema.AuthAccountLinkField, featureNotEnabledErr.Name)
+		assert.Equal(t, "allowAccountLinking", featureNotEnabledErr.Pragma)
+	})
+
+	t.Run("pragma declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			"#allowAccountLinking\n"+code,
+			options,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("pragma declared after use", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t,
+			code+"\n#allowAccountLinking",
+			options,
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("not gated", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, code)
+
+		require.NoError(t, err)
+	})
+}
@@ -217,3 +217,65 @@ func TestCheckPredeclaredValues(t *testing.T) {
 		require.IsType(t, &sema.NotDeclaredError{}, errs[1])
 	})
 }
+
+func TestCheckPredeclaredTypes(t *testing.T) {
+
+	t.Parallel()
+
+	location1 := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x1}),
+	}
+
+	location2 := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x2}),
+	}
+
+	typeDeclaration := stdlib.StandardLibraryType{
+		Name: "Secret",
+		Type: &sema.CompositeType{
+			Location:   common.IdentifierLocation("Host"),
+			Identifier: "Secret",
+			Kind:       common.CompositeKindStructure,
+			Members:    &sema.StringMemberOrderedMap{},
+		},
+		Kind: common.DeclarationKindStructure,
+		Available: func(location common.Location) bool {
+			return location == location1
+		},
+	}
+
+	check := func(location common.Location) error {
+		_, err := ParseAndCheckWithOptions(t,
+			`
+              fun test(_ secret: Secret?) {}
+            `,
+			ParseAndCheckOptions{
+				Location: location,
+				Options: []sema.Option{
+					sema.WithPredeclaredTypes(
+						[]sema.TypeDeclaration{
+							typeDeclaration,
+						},
+					),
+				},
+			},
+		)
+		return err
+	}
+
+	t.Run("available", func(t *testing.T) {
+
+		t.Parallel()
+
+		require.NoError(t, check(location1))
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+
+		t.Parallel()
+
+		errs := ExpectCheckerErrors(t, check(location2), 1)
+
+		require.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+}
@@ -0,0 +1,217 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckProject(t *testing.T) {
+
+	t.Parallel()
+
+	parse := func(t *testing.T, location string, code string) sema.ProjectProgram {
+		program, err := parser2.ParseProgram(code)
+		require.NoError(t, err)
+
+		return sema.ProjectProgram{
+			Location: common.StringLocation(location),
+			Program:  program,
+		}
+	}
+
+	accessCheckMode := sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted)
+
+	resultLocations := func(results []*sema.ProjectCheckResult) (locations []common.Location) {
+		for _, result := range results {
+			locations = append(locations, result.Location)
+		}
+		return
+	}
+
+	t.Run("dependency order", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "a", `
+              import b from "b"
+
+              let a = b + 1
+            `),
+			parse(t, "b", `
+              import c from "c"
+
+              let b = c + 1
+            `),
+			parse(t, "c", `
+              let c = 1
+            `),
+		}
+
+		results, err := sema.CheckProject(programs, nil, accessCheckMode)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]common.Location{
+				common.StringLocation("c"),
+				common.StringLocation("b"),
+				common.StringLocation("a"),
+			},
+			resultLocations(results),
+		)
+
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+			assert.Empty(t, result.Diagnostics)
+			assert.Same(t, result.Checker.Elaboration, result.Elaboration)
+		}
+
+		aVariable, ok := results[2].Elaboration.GlobalValues.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, sema.IntType, aVariable.Type)
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "contracts/a.cdc", `
+              import b from "./b.cdc"
+
+              let a = b
+            `),
+			parse(t, "contracts/b.cdc", `
+              let b = 1
+            `),
+		}
+
+		results, err := sema.CheckProject(
+			programs,
+			func(importingLocation, importedLocation common.Location) (common.Location, bool) {
+				assert.Equal(t, common.StringLocation("contracts/a.cdc"), importingLocation)
+				assert.Equal(t, common.StringLocation("./b.cdc"), importedLocation)
+				return common.StringLocation("contracts/b.cdc"), true
+			},
+			accessCheckMode,
+		)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+	})
+
+	t.Run("error in import", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "a", `
+              import b from "b"
+            `),
+			parse(t, "b", `
+              let b: Int = "1"
+            `),
+		}
+
+		results, err := sema.CheckProject(programs, nil, accessCheckMode)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		bResult := results[0]
+		assert.Equal(t, common.StringLocation("b"), bResult.Location)
+		bErrs := ExpectCheckerErrors(t, bResult.Err, 1)
+		assert.IsType(t, &sema.TypeMismatchError{}, bErrs[0])
+
+		aResult := results[1]
+		aErrs := ExpectCheckerErrors(t, aResult.Err, 1)
+		assert.IsType(t, &sema.ImportedProgramError{}, aErrs[0])
+
+		require.Len(t, aResult.Diagnostics, 1)
+		related := aResult.Diagnostics[0].Related
+		require.Len(t, related, 1)
+		assert.Equal(t, common.StringLocation("b"), related[0].Location)
+	})
+
+	t.Run("cyclic import", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "a", `
+              import b from "b"
+
+              let a = 1
+            `),
+			parse(t, "b", `
+              import a from "a"
+
+              let b = 1
+            `),
+		}
+
+		results, err := sema.CheckProject(programs, nil, accessCheckMode)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		bErrs := ExpectCheckerErrors(t, results[0].Err, 1)
+		assert.IsType(t, &sema.CyclicImportsError{}, bErrs[0])
+	})
+
+	t.Run("unresolved import", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "a", `
+              import b from "b"
+            `),
+		}
+
+		results, err := sema.CheckProject(programs, nil, accessCheckMode)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		errs := ExpectCheckerErrors(t, results[0].Err, 1)
+		assert.IsType(t, &sema.UnresolvedImportError{}, errs[0])
+	})
+
+	t.Run("duplicate location", func(t *testing.T) {
+
+		t.Parallel()
+
+		programs := []sema.ProjectProgram{
+			parse(t, "a", `let a = 1`),
+			parse(t, "a", `let a = 2`),
+		}
+
+		_, err := sema.CheckProject(programs, nil, accessCheckMode)
+		require.IsType(t, &sema.DuplicateProjectLocationError{}, err)
+	})
+}
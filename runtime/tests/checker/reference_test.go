@@ -1129,3 +1129,280 @@ func TestCheckReferenceTypeImplicitConformance(t *testing.T) {
 		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 	})
 }
+
+func TestCheckInvalidatedReferenceUse(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("use after move", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let r <- create R()
+              let ref = &r as &R
+              let r2 <- r
+              let id = ref.id
+              destroy r2
+              return id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("use after destroy", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let r <- create R()
+              let ref = &r as &R
+              destroy r
+              return ref.id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("use before move", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let r <- create R()
+              let ref = &r as &R
+              let id = ref.id
+              destroy r
+              return id
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("copied reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let r <- create R()
+              let ref1 = &r as &R
+              let ref2 = ref1
+              destroy r
+              return ref2.id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("conditional move", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(_ rs: @[R], move: Bool): Int {
+              let r <- create R()
+              let ref = &r as &R
+              if move {
+                  rs.append(<-r)
+              } else {
+                  destroy r
+              }
+              let id = ref.id
+              destroy rs
+              return id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("conditional reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(cond: Bool): Int {
+              let r1 <- create R()
+              let r2 <- create R()
+              let ref = cond ? &r1 as &R : &r2 as &R
+              destroy r1
+              let id = ref.id
+              destroy r2
+              return id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("reassigned reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let r1 <- create R()
+              let r2 <- create R()
+              var ref = &r1 as &R
+              destroy r1
+              ref = &r2 as &R
+              let id = ref.id
+              destroy r2
+              return id
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("nested resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let rs <- [<-create R()]
+              let ref = &rs[0] as &R
+              destroy rs
+              return ref.id
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+	})
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let s = S()
+              let ref = &s as &S
+              let s2 = s
+              return ref.id
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestCheckReferenceExpressionOfOptionalField(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource R {}
+
+      resource C {
+          var r: @R?
+
+          init() {
+              self.r <- create R()
+          }
+
+          destroy() {
+              destroy self.r
+          }
+
+          fun borrowR(): &R? {
+              return &self.r as &R
+          }
+      }
+    `)
+
+	require.NoError(t, err)
+}
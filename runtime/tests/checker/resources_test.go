@@ -2252,6 +2252,8 @@ func TestCheckResourceUseInNestedIfStatement(t *testing.T) {
           if 1 > 2 {
               if 2 > 1 {
                   absorb(<-x)
+              } else {
+                  absorb(<-x)
               }
           } else {
               absorb(<-x)
@@ -2266,6 +2268,34 @@ func TestCheckResourceUseInNestedIfStatement(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckInvalidResourceLossInNestedIfStatement(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun test() {
+          let x <- create X()
+          if 1 > 2 {
+              if 2 > 1 {
+                  absorb(<-x)
+              }
+          } else {
+              absorb(<-x)
+          }
+      }
+
+      fun absorb(_ x: @X) {
+          destroy x
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+}
+
 ////
 
 func TestCheckInvalidResourceUseAfterIfStatement(t *testing.T) {
@@ -3499,6 +3529,52 @@ func TestCheckInvalidResourceArrayContains(t *testing.T) {
 	assert.IsType(t, &sema.NotEquatableTypeError{}, errs[1])
 }
 
+func TestCheckInvalidResourceArrayFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, code string) {
+
+		t.Run(name, func(t *testing.T) {
+
+			_, err := ParseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      resource X {}
+
+                      fun consume(_ x: @X) {
+                          destroy x
+                      }
+
+                      fun keep(_ x: @X): Bool {
+                          destroy x
+                          return true
+                      }
+
+                      fun test() {
+                          let xs: @[X] <- [<-create X()]
+                          %s
+                          destroy xs
+                      }
+                    `,
+					code,
+				),
+			)
+
+			errs := ExpectCheckerErrors(t, err, 1)
+
+			assert.IsType(t, &sema.InvalidResourceArrayMemberError{}, errs[0])
+		})
+	}
+
+	test("forEach", `xs.forEach(consume)`)
+	test("map", `let ys = xs.map(keep)`)
+	test("filter", `
+      let ys <- xs.filter(keep)
+      destroy ys
+    `)
+}
+
 func TestCheckResourceArrayLength(t *testing.T) {
 
 	t.Parallel()
@@ -4676,11 +4752,10 @@ func TestCheckInvalidOptionalResourceCoalescingRightSide(t *testing.T) {
       }
     `)
 
-	errs := ExpectCheckerErrors(t, err, 3)
+	errs := ExpectCheckerErrors(t, err, 2)
 
 	assert.IsType(t, &sema.InvalidNilCoalescingRightResourceOperandError{}, errs[0])
 	assert.IsType(t, &sema.ResourceLossError{}, errs[1])
-	assert.IsType(t, &sema.ResourceLossError{}, errs[2])
 }
 
 // https://github.com/dapperlabs/flow-go/issues/3407
@@ -5049,3 +5124,331 @@ func TestCheckEmptyResourceCollectionMove(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestCheckResourceLossInSwitch(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, code string) error {
+		_, err := ParseAndCheck(t, fmt.Sprintf(
+			`
+              resource R {}
+
+              fun test(x: Int) {
+                  let r <- create R()
+                  %s
+              }
+            `,
+			code,
+		))
+		return err
+	}
+
+	t.Run("all cases and default", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              destroy r
+          default:
+              destroy r
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("no default", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              destroy r
+          case 2:
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("lost in one case", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              destroy r
+          case 2:
+              x
+          default:
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("break after invalidation", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              destroy r
+              break
+          default:
+              destroy r
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("break before invalidation", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              if x > 0 {
+                  break
+              }
+              destroy r
+          default:
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("break before invalidation after switch", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          switch x {
+          case 1:
+              if x > 0 {
+                  break
+              }
+              destroy r
+              return
+          default:
+              x
+          }
+          destroy r
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("break loses resource declared in case", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          destroy r
+          switch x {
+          case 1:
+              let r2 <- create R()
+              if x > 0 {
+                  break
+              }
+              destroy r2
+          default:
+              x
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+}
+
+func TestCheckResourceLossInLoopWithJumps(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, code string) error {
+		_, err := ParseAndCheck(t, fmt.Sprintf(
+			`
+              resource R {}
+
+              fun test() {
+                  var i = 0
+                  %s
+              }
+            `,
+			code,
+		))
+		return err
+	}
+
+	t.Run("continue before invalidation", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          while i < 10 {
+              i = i + 1
+              let r <- create R()
+              if i > 5 {
+                  continue
+              }
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("continue after invalidation", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          while i < 10 {
+              i = i + 1
+              let r <- create R()
+              if i > 5 {
+                  destroy r
+                  continue
+              }
+              destroy r
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("break in nested loop", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          for x in [1, 2, 3] {
+              let r <- create R()
+              while true {
+                  if x > 1 {
+                      break
+                  }
+                  destroy r
+                  return
+              }
+              destroy r
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("break in nested loop loses resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          while i < 10 {
+              i = i + 1
+              while true {
+                  let r <- create R()
+                  if i > 5 {
+                      break
+                  }
+                  destroy r
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("continue in nested loop loses resource of outer loop", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          while i < 10 {
+              i = i + 1
+              let r <- create R()
+              while true {
+                  if i > 5 {
+                      continue
+                  }
+                  break
+              }
+              destroy r
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalidation followed by break", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := test(t, `
+          let r <- create R()
+          while i < 10 {
+              destroy r
+              break
+          }
+        `)
+
+		// The loop body is executed at most once,
+		// so there is no use after invalidation in a later iteration,
+		// but the loop might not be executed at all
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+}
+
+func TestCheckResourceLossReportedOnce(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource R {}
+
+      fun test(x: Int) {
+          let r <- create R()
+          if x == 1 {
+              return
+          }
+          if x == 2 {
+              return
+          }
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	require.IsType(t, &sema.ResourceLossError{}, errs[0])
+
+	assert.Equal(t,
+		ast.Position{Offset: 61, Line: 5, Column: 14},
+		errs[0].(*sema.ResourceLossError).StartPos,
+	)
+}
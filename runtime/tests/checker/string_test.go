@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -75,6 +76,40 @@ func TestCheckString(t *testing.T) {
 	)
 }
 
+func TestCheckRawString(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t,
+		"let x = `{\"a\": \"\\n\"}`\n"+
+			"let y: Character = `\\`\n"+
+			"let z = `\\n` == \"\\\\n\"",
+	)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+
+	assert.Equal(t,
+		sema.CharacterType,
+		RequireGlobalValue(t, checker.Elaboration, "y"),
+	)
+
+	// Raw string literals are constants, just like string literals
+
+	variableDeclaration := checker.Program.Declarations()[indexOfGlobal(t, checker, "z")].(*ast.VariableDeclaration)
+	assert.Equal(t,
+		sema.ConstantValue{
+			Type:  sema.BoolType,
+			Value: true,
+		},
+		checker.Elaboration.ConstantExpressionValues[variableDeclaration.Value],
+	)
+}
+
 func TestCheckStringConcat(t *testing.T) {
 
 	t.Parallel()
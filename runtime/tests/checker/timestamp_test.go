@@ -0,0 +1,176 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckTimestamp(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+        let start = Timestamp(unixSeconds: 1614834367.5)
+        let week = Duration(seconds: 604800.0)
+        let end = start.add(week)
+        let earlier = start.subtract(week)
+        let elapsed = end.durationSince(start)
+        let isBefore = start.isBefore(end)
+        let isAfter = start.isAfter(end)
+        let unixSeconds = start.unixSeconds
+        let unixNanoseconds = start.unixNanoseconds
+        let formatted = start.toString()
+        let equal = start == end
+    `)
+
+	require.NoError(t, err)
+
+	for name, expectedType := range map[string]sema.Type{
+		"start":           sema.TimestampType,
+		"week":            sema.DurationType,
+		"end":             sema.TimestampType,
+		"earlier":         sema.TimestampType,
+		"elapsed":         sema.DurationType,
+		"isBefore":        sema.BoolType,
+		"isAfter":         sema.BoolType,
+		"unixSeconds":     sema.Int64Type,
+		"unixNanoseconds": sema.Int64Type,
+		"formatted":       sema.StringType,
+		"equal":           sema.BoolType,
+	} {
+		assert.Equal(t,
+			expectedType,
+			RequireGlobalValue(t, checker.Elaboration, name),
+			name,
+		)
+	}
+}
+
+func TestCheckDuration(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+        let second = Duration(seconds: 1.0)
+        let sum = second.add(Duration(seconds: -0.5))
+        let difference = second.subtract(sum)
+        let product = second.multiply(60)
+        let isShorter = second.isShorterThan(product)
+        let isLonger = second.isLongerThan(product)
+        let seconds = second.seconds
+        let nanoseconds = second.nanoseconds
+        let formatted = second.toString()
+    `)
+
+	require.NoError(t, err)
+
+	for name, expectedType := range map[string]sema.Type{
+		"second":      sema.DurationType,
+		"sum":         sema.DurationType,
+		"difference":  sema.DurationType,
+		"product":     sema.DurationType,
+		"isShorter":   sema.BoolType,
+		"isLonger":    sema.BoolType,
+		"seconds":     sema.Int64Type,
+		"nanoseconds": sema.Int64Type,
+		"formatted":   sema.StringType,
+	} {
+		assert.Equal(t,
+			expectedType,
+			RequireGlobalValue(t, checker.Elaboration, name),
+			name,
+		)
+	}
+}
+
+func TestCheckInvalidTimestampArithmetic(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("timestamp plus timestamp", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+            let start = Timestamp(unixSeconds: 1.0)
+            let end = start.add(start)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("arithmetic operator", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+            let duration = Duration(seconds: 1.0)
+            let sum = duration + duration
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidBinaryOperandsError{}, errs[0])
+	})
+
+	t.Run("negative unix seconds", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+            let start = Timestamp(unixSeconds: -1.0)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidFixedPointLiteralRangeError{}, errs[0])
+	})
+}
+
+func TestCheckTimestampStorableAndDictionaryKey(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+        struct Auction {
+            let deadline: Timestamp
+            let extensions: {Timestamp: Duration}
+
+            init() {
+                self.deadline = Timestamp(unixSeconds: 1.0)
+                self.extensions = {self.deadline: Duration(seconds: 60.0)}
+            }
+        }
+
+        event Extended(deadline: Timestamp, by: Duration)
+    `)
+
+	require.NoError(t, err)
+
+	assert.True(t, sema.TimestampType.IsStorable(nil))
+	assert.True(t, sema.DurationType.IsStorable(nil))
+}
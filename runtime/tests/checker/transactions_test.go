@@ -19,6 +19,7 @@
 package checker
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -109,6 +110,38 @@ func TestCheckTransactions(t *testing.T) {
 		)
 	})
 
+	t.Run("ValidPrepareParameterArray", func(t *testing.T) {
+		test(
+			t,
+			`
+              transaction {
+
+                  prepare(payer: AuthAccount, signers: [AuthAccount; 2]) {
+                      let first: AuthAccount = signers[0]
+                  }
+              }
+            `,
+			nil,
+		)
+	})
+
+	t.Run("InvalidPrepareParameterArray", func(t *testing.T) {
+		test(
+			t,
+			`
+              transaction {
+
+                prepare(x: [AuthAccount], y: [Int; 2], z: [PublicAccount; 1]) {}
+              }
+            `,
+			[]error{
+				&sema.InvalidTransactionPrepareParameterTypeError{},
+				&sema.InvalidTransactionPrepareParameterTypeError{},
+				&sema.InvalidTransactionPrepareParameterTypeError{},
+			},
+		)
+	})
+
 	t.Run("InvalidFieldUninitialized", func(t *testing.T) {
 		test(
 			t,
@@ -350,6 +383,43 @@ func TestCheckTransactions(t *testing.T) {
 	})
 }
 
+func TestCheckTransactionAuthorizerCount(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(prepareParameters string, expectedCount int) {
+
+		t.Run(prepareParameters, func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      transaction {
+                          prepare(%s) {}
+                      }
+                    `,
+					prepareParameters,
+				),
+			)
+			require.NoError(t, err)
+
+			transactionTypes := checker.Elaboration.TransactionTypes
+			require.Len(t, transactionTypes, 1)
+
+			assert.Equal(t, expectedCount, transactionTypes[0].AuthorizerCount())
+		})
+	}
+
+	test("", 0)
+	test("signer: AuthAccount", 1)
+	test("first: AuthAccount, second: AuthAccount", 2)
+	test("signers: [AuthAccount; 3]", 3)
+	test("payer: AuthAccount, signers: [AuthAccount; 2]", 3)
+	test("signers: [AuthAccount; 0]", 0)
+}
+
 func TestCheckTransactionExecuteScope(t *testing.T) {
 
 	t.Parallel()
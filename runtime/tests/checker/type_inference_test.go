@@ -368,45 +368,127 @@ func TestCheckFunctionArgumentTypeInference(t *testing.T) {
 			},
 		)
 
-		errs := ExpectCheckerErrors(t, err, 2)
+		require.NoError(t, err)
+	})
 
-		require.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
-		typeParamMismatchErr := errs[0].(*sema.TypeParameterTypeMismatchError)
-		assert.Equal(
-			t,
-			&sema.VariableSizedType{
-				Type: sema.Int8Type,
-			},
-			typeParamMismatchErr.ExpectedType,
-		)
+	t.Run("with generics, empty literals", func(t *testing.T) {
 
-		assert.Equal(
-			t,
-			&sema.VariableSizedType{
-				Type: sema.IntType,
+		t.Parallel()
+
+		typeParameter := &sema.TypeParameter{
+			Name:      "T",
+			TypeBound: nil,
+		}
+
+		_, err := parseAndCheckWithTestValue(t,
+			`
+              let res1 = test<[Int8]>([])
+              let res2 = test<{String: Int8}>({})
+            `,
+			&sema.FunctionType{
+				TypeParameters: []*sema.TypeParameter{
+					typeParameter,
+				},
+				Parameters: []*sema.Parameter{
+					{
+						Label:      sema.ArgumentLabelNotRequired,
+						Identifier: "value",
+						TypeAnnotation: sema.NewTypeAnnotation(
+							&sema.GenericType{
+								TypeParameter: typeParameter,
+							},
+						),
+					},
+				},
+				ReturnTypeAnnotation:  sema.NewTypeAnnotation(sema.VoidType),
+				RequiredArgumentCount: nil,
 			},
-			typeParamMismatchErr.ActualType,
 		)
 
-		require.IsType(t, &sema.TypeMismatchError{}, errs[1])
-		typeMismatchErr := errs[1].(*sema.TypeMismatchError)
+		require.NoError(t, err)
+	})
 
-		assert.Equal(
-			t,
-			&sema.VariableSizedType{
-				Type: sema.Int8Type,
+	t.Run("with generics, non-generic parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		typeParameter := &sema.TypeParameter{
+			Name:      "T",
+			TypeBound: nil,
+		}
+
+		_, err := parseAndCheckWithTestValue(t,
+			`
+              let res = test([], 1)
+            `,
+			&sema.FunctionType{
+				TypeParameters: []*sema.TypeParameter{
+					typeParameter,
+				},
+				Parameters: []*sema.Parameter{
+					{
+						Label:      sema.ArgumentLabelNotRequired,
+						Identifier: "values",
+						TypeAnnotation: sema.NewTypeAnnotation(
+							&sema.VariableSizedType{
+								Type: sema.Int8Type,
+							},
+						),
+					},
+					{
+						Label:      sema.ArgumentLabelNotRequired,
+						Identifier: "value",
+						TypeAnnotation: sema.NewTypeAnnotation(
+							&sema.GenericType{
+								TypeParameter: typeParameter,
+							},
+						),
+					},
+				},
+				ReturnTypeAnnotation:  sema.NewTypeAnnotation(sema.VoidType),
+				RequiredArgumentCount: nil,
 			},
-			typeMismatchErr.ExpectedType,
 		)
 
-		assert.Equal(
-			t,
-			&sema.VariableSizedType{
-				Type: sema.IntType,
+		require.NoError(t, err)
+	})
+
+	t.Run("with generics, unresolved parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		typeParameter := &sema.TypeParameter{
+			Name:      "T",
+			TypeBound: nil,
+		}
+
+		_, err := parseAndCheckWithTestValue(t,
+			`
+              let res = test([])
+            `,
+			&sema.FunctionType{
+				TypeParameters: []*sema.TypeParameter{
+					typeParameter,
+				},
+				Parameters: []*sema.Parameter{
+					{
+						Label:      sema.ArgumentLabelNotRequired,
+						Identifier: "value",
+						TypeAnnotation: sema.NewTypeAnnotation(
+							&sema.GenericType{
+								TypeParameter: typeParameter,
+							},
+						),
+					},
+				},
+				ReturnTypeAnnotation:  sema.NewTypeAnnotation(sema.VoidType),
+				RequiredArgumentCount: nil,
 			},
-			typeMismatchErr.ActualType,
 		)
 
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeAnnotationRequiredError{}, errs[0])
 	})
 }
 
@@ -537,6 +619,184 @@ func TestCheckBinaryExpressionTypeInference(t *testing.T) {
 			yType,
 		)
 	})
+
+	t.Run("no contextually expected type, literal on the left", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = 1 as UInt8
+          let y = 1 + x
+          let z = 2 < x
+          let w = 3 & x
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.UInt8Type,
+			RequireGlobalValue(t, checker.Elaboration, "y"),
+		)
+
+		assert.Equal(t,
+			sema.BoolType,
+			RequireGlobalValue(t, checker.Elaboration, "z"),
+		)
+
+		assert.Equal(t,
+			sema.UInt8Type,
+			RequireGlobalValue(t, checker.Elaboration, "w"),
+		)
+	})
+
+	t.Run("no contextually expected type, fixed-point literal on the left", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = 1.0 as Fix64
+          let y = 2.5 * x
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.Fix64Type,
+			RequireGlobalValue(t, checker.Elaboration, "y"),
+		)
+	})
+
+	t.Run("literal on the left, out of range", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = 1 as UInt8
+          let y = 256 + x
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidIntegerLiteralRangeError{}, errs[0])
+	})
+}
+
+func TestCheckConditionalExpressionTypeInference(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("nil and literal", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = true ? nil : 1
+          let y = true ? 1 : nil
+        `)
+		require.NoError(t, err)
+
+		expected := &sema.OptionalType{
+			Type: sema.IntType,
+		}
+
+		assert.Equal(t, expected, RequireGlobalValue(t, checker.Elaboration, "x"))
+		assert.Equal(t, expected, RequireGlobalValue(t, checker.Elaboration, "y"))
+	})
+
+	t.Run("literal and typed value", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let a: Int8 = 1
+          let x = true ? a : 2
+          let y = true ? 2 : a
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t, sema.Int8Type, RequireGlobalValue(t, checker.Elaboration, "x"))
+		assert.Equal(t, sema.Int8Type, RequireGlobalValue(t, checker.Elaboration, "y"))
+	})
+
+	t.Run("literal and optional typed value", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let a: UFix64? = 1.0
+          let x = true ? 2.0 : a
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{
+				Type: sema.UFix64Type,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("empty collection literals", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let a: [UInt8] = [1]
+          let x = true ? [] : a
+          let y = true ? [1, 2] : []
+          let z = true ? {} : {"one": 1}
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.UInt8Type,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "y"),
+		)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "z"),
+		)
+	})
+
+	t.Run("references", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let a = 1
+          let b = 2 as Int8
+          let x = true ? &a as &Int : nil
+          let y = true ? &a as &Int : &b as &Int8
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{
+				Type: &sema.ReferenceType{
+					Type: sema.IntType,
+				},
+			},
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+
+		assert.Equal(t,
+			&sema.ReferenceType{
+				Type: sema.SignedIntegerType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "y"),
+		)
+	})
 }
 
 func TestCheckUnaryExpressionTypeInference(t *testing.T) {
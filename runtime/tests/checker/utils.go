@@ -47,6 +47,9 @@ type ParseAndCheckOptions struct {
 	Location         common.Location
 	IgnoreParseError bool
 	Options          []sema.Option
+	// EnabledFeatures are the experimental features
+	// which are enabled in the parser and the checker
+	EnabledFeatures common.ExperimentalFeatures
 }
 
 var checkConcurrently = flag.Int(
@@ -65,7 +68,10 @@ func ParseAndCheckWithOptions(
 		options.Location = utils.TestLocation
 	}
 
-	program, err := parser2.ParseProgram(code)
+	parserConfig := parser2.DefaultConfig
+	parserConfig.EnabledFeatures = options.EnabledFeatures
+
+	program, err := parser2.ParseProgramWithConfig(code, parserConfig)
 	if !options.IgnoreParseError && !assert.NoError(t, err) {
 		var sb strings.Builder
 		locationID := options.Location.ID()
@@ -83,6 +89,7 @@ func ParseAndCheckWithOptions(
 		checkerOptions := append(
 			[]sema.Option{
 				sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+				sema.WithEnabledFeatures(options.EnabledFeatures),
 			},
 			options.Options...,
 		)
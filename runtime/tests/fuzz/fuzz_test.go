@@ -0,0 +1,201 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+// The fuzz targets in this file can be run using `go test -fuzz`, e.g.
+//
+//   go test -fuzz=FuzzInterpret ./runtime/tests/fuzz
+//
+// The seed corpus is located in testdata/fuzz, and is run as part of the regular tests.
+//
+
+// computationLimit is the maximum number of statements, loop iterations,
+// and function invocations executed when interpreting a fuzzed program.
+// It bounds both the execution time and the depth of the call stack
+//
+const computationLimit = 1000
+
+// errComputationLimitExceeded is the error raised when
+// a fuzzed program exceeds the computation limit
+//
+var errComputationLimitExceeded = errors.New("computation limit exceeded")
+
+func parse(data []byte) *ast.Program {
+	if !utf8.Valid(data) {
+		return nil
+	}
+
+	program, err := parser2.ParseProgram(string(data))
+	if err != nil {
+		return nil
+	}
+
+	return program
+}
+
+func check(program *ast.Program) *sema.Checker {
+	checker, err := sema.NewChecker(
+		program,
+		utils.TestLocation,
+		sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+	)
+	if err != nil {
+		return nil
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil
+	}
+
+	return checker
+}
+
+func FuzzParse(f *testing.F) {
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = parse(data)
+	})
+}
+
+func FuzzCheck(f *testing.F) {
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		program := parse(data)
+		if program == nil {
+			return
+		}
+
+		_ = check(program)
+	})
+}
+
+func FuzzInterpret(f *testing.F) {
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		program := parse(data)
+		if program == nil {
+			return
+		}
+
+		checker := check(program)
+		if checker == nil {
+			return
+		}
+
+		computation := 0
+
+		meter := func() {
+			computation++
+			if computation > computationLimit {
+				panic(errComputationLimitExceeded)
+			}
+		}
+
+		var uuid uint64
+
+		inter, err := interpreter.NewInterpreter(
+			interpreter.ProgramFromChecker(checker),
+			checker.Location,
+			interpreter.WithUUIDHandler(func() (uint64, error) {
+				uuid++
+				return uuid, nil
+			}),
+			interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+			interpreter.WithAtreeValueValidationEnabled(true),
+			interpreter.WithAtreeStorageValidationEnabled(true),
+			interpreter.WithOnStatementHandler(func(_ *interpreter.Interpreter, _ ast.Statement) {
+				meter()
+			}),
+			interpreter.WithOnLoopIterationHandler(func(_ *interpreter.Interpreter, _ interpreter.LoopIteration) {
+				meter()
+			}),
+			interpreter.WithOnFunctionInvocationHandler(func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation) {
+				meter()
+			}),
+		)
+		require.NoError(t, err)
+
+		err = inter.Interpret()
+		if err != nil {
+			return
+		}
+
+		var values []interpreter.Value
+
+		if inter.Globals.Contains("main") {
+			var result interpreter.Value
+			result, err = inter.Invoke("main")
+			if err != nil {
+				return
+			}
+			values = append(values, result)
+		}
+
+		for _, declaration := range program.VariableDeclarations() {
+			name := declaration.Identifier.Identifier
+			values = append(values, inter.Globals[name].GetValue())
+		}
+
+		for _, value := range values {
+			assertEncodingRoundTrips(t, inter, value)
+		}
+	})
+}
+
+// assertEncodingRoundTrips asserts that the given value,
+// if it is exportable, can be encoded, decoded,
+// and re-encoded to the same encoding
+//
+func assertEncodingRoundTrips(t *testing.T, inter *interpreter.Interpreter, value interpreter.Value) {
+
+	exported, err := runtime.ExportValue(value, inter)
+	if err != nil {
+		return
+	}
+
+	encoded, err := json.Encode(exported)
+	if err != nil {
+		return
+	}
+
+	decoded, err := json.Decode(encoded)
+	require.NoError(t, err, string(encoded))
+
+	reencoded, err := json.Encode(decoded)
+	require.NoError(t, err, string(encoded))
+
+	require.Equal(t, string(encoded), string(reencoded))
+}
@@ -576,6 +576,127 @@ func TestInterpretAuthAccount_copy(t *testing.T) {
 	})
 }
 
+func TestInterpretAuthAccount_transient(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      struct S {
+          let values: [Int]
+
+          init(values: [Int]) {
+              self.values = values
+          }
+      }
+
+      struct S2 {}
+
+      fun save() {
+          account.saveTransient(S(values: [1, 2, 3]), to: /storage/s)
+      }
+
+      fun copyS(): S? {
+          return account.copyTransient<S>(from: /storage/s)
+      }
+
+      fun loadS(): S? {
+          return account.loadTransient<S>(from: /storage/s)
+      }
+
+      fun copyS2(): S2? {
+          return account.copyTransient<S2>(from: /storage/s)
+      }
+
+      fun mutateCopy(): Int {
+          let s = account.copyTransient<S>(from: /storage/s)!
+          s.values.append(4)
+          return account.copyTransient<S>(from: /storage/s)!.values.length
+      }
+    `
+
+	address := interpreter.NewAddressValueFromBytes([]byte{42})
+
+	t.Run("save, copy, and load", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, getAccountValues := testAccount(t, address, true, code)
+
+		_, err := inter.Invoke("save")
+		require.NoError(t, err)
+
+		// NOTE: check the value was not saved to account storage
+		require.Empty(t, getAccountValues())
+
+		for i := 0; i < 2; i++ {
+			value, err := inter.Invoke("copyS")
+			require.NoError(t, err)
+			require.IsType(t, &interpreter.SomeValue{}, value)
+		}
+
+		value, err := inter.Invoke("mutateCopy")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.NewIntValueFromInt64(3), value)
+
+		value, err = inter.Invoke("loadS")
+		require.NoError(t, err)
+		require.IsType(t, &interpreter.SomeValue{}, value)
+
+		value, err = inter.Invoke("loadS")
+		require.NoError(t, err)
+		require.Equal(t, interpreter.NilValue{}, value)
+
+		require.Empty(t, getAccountValues())
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, _ := testAccount(t, address, true, code)
+
+		_, err := inter.Invoke("save")
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("save")
+		require.ErrorAs(t, err, &interpreter.OverwriteError{})
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, _ := testAccount(t, address, true, code)
+
+		_, err := inter.Invoke("save")
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("copyS2")
+		require.ErrorAs(t, err, &interpreter.ForceCastTypeMismatchError{})
+
+		// NOTE: check the value was not removed
+		value, err := inter.Invoke("copyS")
+		require.NoError(t, err)
+		require.IsType(t, &interpreter.SomeValue{}, value)
+	})
+
+	t.Run("discard", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, _ := testAccount(t, address, true, code)
+
+		_, err := inter.Invoke("save")
+		require.NoError(t, err)
+
+		inter.DiscardTransientStorage()
+
+		value, err := inter.Invoke("copyS")
+		require.NoError(t, err)
+		require.Equal(t, interpreter.NilValue{}, value)
+	})
+}
+
 func TestInterpretAuthAccount_borrow(t *testing.T) {
 
 	t.Parallel()
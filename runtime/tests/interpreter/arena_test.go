@@ -0,0 +1,262 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func parseCheckAndInterpretWithValueArena(t testing.TB, code string, enabled bool) *interpreter.Interpreter {
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		code,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				interpreter.WithValueArenaEnabled(enabled),
+			},
+		},
+	)
+	require.NoError(t, err)
+	return inter
+}
+
+// testValueArena interprets the given code with and without the value arena,
+// and checks that the result of invoking the function `test` is the same
+//
+func testValueArena(t *testing.T, code string) (*interpreter.Interpreter, interpreter.Value) {
+
+	withoutArena := parseCheckAndInterpretWithValueArena(t, code, false)
+	withArena := parseCheckAndInterpretWithValueArena(t, code, true)
+
+	expected, err := withoutArena.Invoke("test")
+	require.NoError(t, err)
+
+	actual, err := withArena.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(t, withArena, expected, actual)
+
+	return withArena, actual
+}
+
+func TestInterpretValueArena(t *testing.T) {
+
+	t.Parallel()
+
+	for _, ty := range []sema.Type{
+		sema.IntType,
+		sema.UIntType,
+		sema.Int8Type,
+		sema.UInt64Type,
+		sema.Int256Type,
+		sema.Word8Type,
+	} {
+
+		ty := ty
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			t.Parallel()
+
+			_, _ = testValueArena(t,
+				fmt.Sprintf(
+					`
+                      fun two(): %[1]s {
+                          return 2
+                      }
+
+                      fun test(): [%[1]s] {
+                          let a: %[1]s = 1
+                          let b: %[1]s = 2
+                          let c: %[1]s = 6
+                          return [
+                              a + b,
+                              a * b + c,
+                              c - b * two(),
+                              (a + b) * (c - a) + a * b * c,
+                              ((a + b) * two() + c) * (c - b),
+                              a + b + c + a + b + c
+                          ]
+                      }
+                    `,
+					ty,
+				),
+			)
+		})
+	}
+
+	t.Run("constants", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := testValueArena(t, `
+          fun test(): Int {
+              let a = 3
+              return (1 + 2) * a + 4 * 5
+          }
+        `)
+
+		AssertValuesEqual(t, inter, interpreter.NewIntValueFromInt64(29), value)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Temporary values are reused across iterations,
+		// the accumulated values must not be affected
+
+		inter, value := testValueArena(t, `
+          fun test(): [Int] {
+              var sum = 0
+              var squares: [Int] = []
+              var i = 0
+              while i < 100 {
+                  let square = i * i
+                  squares.append(square)
+                  sum = sum + i * i + (i + 1) * 2
+                  i = i + 1
+              }
+              return [sum, squares[10], squares[99]]
+          }
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				common.Address{},
+				interpreter.NewIntValueFromInt64(338450),
+				interpreter.NewIntValueFromInt64(100),
+				interpreter.NewIntValueFromInt64(9801),
+			),
+			value,
+		)
+	})
+
+	t.Run("escape", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Results of arithmetic expressions escape to variables, containers, and composites,
+		// and must not be affected by later evaluations
+
+		_, _ = testValueArena(t, `
+          struct S {
+              var value: Int
+
+              init(value: Int) {
+                  self.value = value
+              }
+          }
+
+          fun compute(_ a: Int, _ b: Int): Int {
+              return (a + b) * (a - b) + a * b
+          }
+
+          fun test(): [AnyStruct] {
+              let a = 10000000000000000000000
+              let b = 3
+              let x = a * b + b
+              let array = [a * a + b, compute(a, b)]
+              let dict = {"y": (a - b) * (a + b)}
+              let s = S(value: a * b * b + a)
+              let f = fun (): Int {
+                  return x * x + a
+              }
+
+              // Evaluate other expressions, which reuse temporary values
+
+              var i = 0
+              while i < 10 {
+                  let y = (a + i) * (a - i) + i * i
+                  s.value = s.value + y * 0 + i
+                  i = i + 1
+              }
+
+              return [x, array, dict, s.value, f(), compute(b, a)]
+          }
+        `)
+	})
+
+	t.Run("underflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Subtraction of UInt values is not evaluated in the arena,
+		// and still fails on underflow
+
+		code := `
+          fun test(): UInt {
+              let a: UInt = 1
+              let b: UInt = 2
+              return (a + a) * b - (b * b + a)
+          }
+        `
+
+		for _, enabled := range []bool{false, true} {
+			inter := parseCheckAndInterpretWithValueArena(t, code, enabled)
+
+			_, err := inter.Invoke("test")
+			require.Error(t, err)
+
+			require.ErrorAs(t, err, &interpreter.UnderflowError{})
+		}
+	})
+}
+
+func TestInterpretValueArenaAllocations(t *testing.T) {
+
+	// NOTE: not parallel, as allocations are counted
+
+	code := `
+      fun test(): Int {
+          var sum = 0
+          var i = 0
+          while i < 100 {
+              sum = sum + (i + 1) * (i + 2) * (i + 3) + i * i * i
+              i = i + 1
+          }
+          return sum
+      }
+    `
+
+	allocations := func(enabled bool) float64 {
+		inter := parseCheckAndInterpretWithValueArena(t, code, enabled)
+
+		return testing.AllocsPerRun(10, func() {
+			_, err := inter.Invoke("test")
+			require.NoError(t, err)
+		})
+	}
+
+	assert.Less(t, allocations(true), allocations(false))
+}
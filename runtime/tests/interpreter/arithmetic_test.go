@@ -21,6 +21,7 @@ package interpreter_test
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -549,6 +550,51 @@ func TestInterpretSaturatedArithmeticFunctions(t *testing.T) {
 				},
 			},
 		},
+		sema.Fix128Type: {
+			add: testCalls{
+				overflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+				},
+				underflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(-2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+				},
+			},
+			subtract: testCalls{
+				overflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(-2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+				},
+				underflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+				},
+			},
+			multiply: testCalls{
+				overflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+				},
+				underflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+				},
+			},
+			divide: testCalls{
+				overflow: testCall{
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+					interpreter.NewFix128ValueWithInteger(big.NewInt(-1)),
+					interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+				},
+			},
+		},
 		sema.UIntType: {
 			subtract: testCalls{
 				underflow: testCall{
@@ -719,6 +765,29 @@ func TestInterpretSaturatedArithmeticFunctions(t *testing.T) {
 				},
 			},
 		},
+		sema.UFix128Type: {
+			add: testCalls{
+				overflow: testCall{
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+					interpreter.NewUFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+				},
+			},
+			subtract: testCalls{
+				underflow: testCall{
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMinBig),
+					interpreter.NewUFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMinBig),
+				},
+			},
+			multiply: testCalls{
+				overflow: testCall{
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+					interpreter.NewUFix128ValueWithInteger(big.NewInt(2)),
+					interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+				},
+			},
+		},
 	}
 
 	// Verify all test cases exist
@@ -20,8 +20,11 @@ package interpreter_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
@@ -81,12 +84,18 @@ func TestInterpretToString(t *testing.T) {
 
 			isSigned := sema.IsSubType(ty, sema.SignedFixedPointType)
 
+			scale := sema.Fix64Scale
+			if ranged, ok := ty.(sema.FractionalRangedType); ok {
+				scale = ranged.Scale()
+			}
+			fractional := "34" + strings.Repeat("0", int(scale)-2)
+
 			if isSigned {
 				literal = "-12.34"
-				expected = interpreter.NewStringValue("-12.34000000")
+				expected = interpreter.NewStringValue("-12." + fractional)
 			} else {
 				literal = "12.34"
-				expected = interpreter.NewStringValue("12.34000000")
+				expected = interpreter.NewStringValue("12." + fractional)
 			}
 
 			inter := parseCheckAndInterpret(t,
@@ -110,6 +119,196 @@ func TestInterpretToString(t *testing.T) {
 	}
 }
 
+func TestInterpretCompositeToString(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct Inner {
+              let x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          struct S {
+              pub let b: Int
+              pub let a: String
+              priv let secret: Int
+              pub let inner: Inner
+              pub let inners: [Inner]
+              pub let dict: {String: Inner}
+              pub let opt: Int?
+              pub let none: Int?
+
+              init() {
+                  self.b = 2
+                  self.a = "x"
+                  self.secret = 3
+                  self.inner = Inner(x: 1)
+                  self.inners = [Inner(x: 2)]
+                  self.dict = {"y": Inner(x: 3)}
+                  self.opt = 4
+                  self.none = nil
+              }
+          }
+
+          let res = S().toString()
+        `)
+
+		// Fields are in declaration order, private fields are omitted
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewStringValue(
+				`S(b: 2, a: "x", inner: Inner(x: 1), inners: [Inner(x: 2)], dict: {"y": Inner(x: 3)}, opt: 4, none: nil)`,
+			),
+			inter.Globals["res"].GetValue(),
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource Inner {}
+
+          resource R {
+              pub let id: Int
+              pub let inner: @Inner
+
+              init() {
+                  self.id = 1
+                  self.inner <- create Inner()
+              }
+
+              destroy() {
+                  destroy self.inner
+              }
+          }
+
+          fun test(): [String] {
+              let r <- create R()
+              let ref = &r as &R
+              let res = [r.toString(), ref.toString()]
+              destroy r
+              return res
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		// Resources are marked, and include their UUID
+
+		expected := interpreter.NewStringValue(`@R(uuid: 1, id: 1, inner: @Inner(uuid: 2))`)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+				common.Address{},
+				expected,
+				expected,
+			),
+			value,
+		)
+	})
+
+	t.Run("declared", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct S {
+              let x: Int
+
+              init() {
+                  self.x = 1
+              }
+
+              fun toString(): String {
+                  return "custom"
+              }
+          }
+
+          struct T {
+              let s: S
+
+              init() {
+                  self.s = S()
+              }
+          }
+
+          let res = S().toString()
+          let res2 = T().toString()
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewStringValue("custom"),
+			inter.Globals["res"].GetValue(),
+		)
+
+		// The derived function of other composites is not affected
+		// by the declared function of the values of their fields
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewStringValue("T(s: S(x: 1))"),
+			inter.Globals["res2"].GetValue(),
+		)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct S {
+              var ref: &S?
+
+              init() {
+                  self.ref = nil
+              }
+
+              fun setRef(_ ref: &S) {
+                  self.ref = ref
+              }
+          }
+
+          fun test(): String {
+              let s = S()
+              s.setRef(&s as &S)
+              return s.toString()
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewStringValue("S(ref: S(ref: ...))"),
+			value,
+		)
+	})
+}
+
 func TestInterpretToBytes(t *testing.T) {
 
 	t.Parallel()
@@ -300,12 +499,23 @@ func TestInterpretToBigEndianBytes(t *testing.T) {
 			"42.24": {0, 0, 0, 0, 251, 197, 32, 0},
 			"-1.0":  {255, 255, 255, 255, 250, 10, 31, 0},
 		},
+		"Fix128": {
+			"0.0":   {0},
+			"42.0":  {34, 189, 216, 143, 237, 158, 252, 106, 0, 0, 0},
+			"42.24": {34, 240, 170, 253, 0, 136, 125, 32, 0, 0, 0},
+			"-1.0":  {255, 44, 61, 228, 49, 51, 18, 95, 0, 0, 0},
+		},
 		// UFix*
 		"UFix64": {
 			"0.0":   {0, 0, 0, 0, 0, 0, 0, 0},
 			"42.0":  {0, 0, 0, 0, 250, 86, 234, 0},
 			"42.24": {0, 0, 0, 0, 251, 197, 32, 0},
 		},
+		"UFix128": {
+			"0.0":   {0},
+			"42.0":  {34, 189, 216, 143, 237, 158, 252, 106, 0, 0, 0},
+			"42.24": {34, 240, 170, 253, 0, 136, 125, 32, 0, 0, 0},
+		},
 	}
 
 	// Ensure the test cases are complete
@@ -0,0 +1,288 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func parseCheckAndInterpretWithCallSafe(t *testing.T, code string) *interpreter.Interpreter {
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		code,
+		ParseCheckAndInterpretOptions{
+			CheckerOptions: []sema.Option{
+				sema.WithPredeclaredValues(stdlib.BuiltinFunctions.ToSemaValueDeclarations()),
+			},
+			Options: []interpreter.Option{
+				interpreter.WithPredeclaredValues(stdlib.BuiltinFunctions.ToInterpreterValueDeclarations()),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	return inter
+}
+
+func TestInterpretCallSafe(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): Int? {
+              return callSafe(fun (): Int {
+                  return 42
+              })
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(42)),
+			result,
+		)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): [Int?] {
+              return [
+                  callSafe(fun (): Int {
+                      panic("oops")
+                  }),
+                  callSafe(fun (): Int {
+                      let xs: [Int] = []
+                      return xs[1]
+                  }),
+                  callSafe(fun (): Int {
+                      return 1
+                  })
+              ]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.OptionalStaticType{
+						Type: interpreter.PrimitiveStaticTypeInt,
+					},
+				},
+				common.Address{},
+				interpreter.NilValue{},
+				interpreter.NilValue{},
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(1)),
+			),
+			result,
+		)
+	})
+
+	t.Run("failure after mutation of temporary value", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): Int? {
+              return callSafe(fun (): Int {
+                  let xs = [1]
+                  xs.append(2)
+                  var count = 0
+                  count = xs.length
+                  panic("oops")
+              })
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(t, inter, interpreter.NilValue{}, result)
+	})
+
+	t.Run("failure after mutation of existing value", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): Int? {
+              let xs = [1]
+              return callSafe(fun (): Int {
+                  xs.append(2)
+                  panic("oops")
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+
+	t.Run("failure after mutation of existing composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          struct Counter {
+              var count: Int
+
+              init() {
+                  self.count = 0
+              }
+
+              fun increment() {
+                  self.count = self.count + 1
+              }
+          }
+
+          fun test(): Int? {
+              let counter = Counter()
+              return callSafe(fun (): Int {
+                  counter.increment()
+                  panic("oops")
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+
+	t.Run("failure after assignment of existing variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): Int? {
+              var count = 0
+              return callSafe(fun (): Int {
+                  count = 1
+                  panic("oops")
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+
+	t.Run("nested", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          fun test(): Int?? {
+              return callSafe(fun (): Int? {
+                  let xs = [1]
+                  let inner = callSafe(fun (): Int {
+                      xs.append(2)
+                      panic("oops")
+                  })
+                  return inner
+              })
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		// The inner call mutated a value of the outer call,
+		// so only the outer call's failure is captured
+
+		AssertValuesEqual(t, inter, interpreter.NilValue{}, result)
+	})
+
+	t.Run("events", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithCallSafe(t, `
+          event Test(value: Int)
+
+          fun test() {
+              emit Test(value: 1)
+              callSafe(fun (): Int {
+                  emit Test(value: 2)
+                  panic("oops")
+              })
+              callSafe(fun (): Int {
+                  emit Test(value: 3)
+                  return 3
+              })
+          }
+        `)
+
+		var eventValues []interpreter.Value
+
+		inter.SetOnEventEmittedHandler(
+			func(
+				_ *interpreter.Interpreter,
+				_ func() interpreter.LocationRange,
+				event *interpreter.CompositeValue,
+				_ *sema.CompositeType,
+			) error {
+				eventValues = append(
+					eventValues,
+					event.GetField("value"),
+				)
+				return nil
+			},
+		)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interpreter.Value{
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(3),
+			},
+			eventValues,
+		)
+	})
+}
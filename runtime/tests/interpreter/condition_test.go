@@ -170,6 +170,60 @@ func TestInterpretFunctionPostConditionWithBefore(t *testing.T) {
 	)
 }
 
+func TestInterpretFunctionPostConditionWithBeforeSnapshot(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      struct Counter {
+          var count: Int
+
+          init() {
+              self.count = 0
+          }
+
+          fun increment() {
+              self.count = self.count + 1
+          }
+      }
+
+      struct S {
+          var values: [Int]
+          var nested: [[Int]]
+          var names: {String: [Int]}
+          var counter: Counter
+
+          init() {
+              self.values = [1]
+              self.nested = [[1]]
+              self.names = {"a": [1]}
+              self.counter = Counter()
+          }
+
+          fun test() {
+              post {
+                  before(self.values).length + 1 == self.values.length: "values"
+                  before(self.nested)[0].length + 1 == self.nested[0].length: "nested"
+                  before(self.names)["a"]!.length + 1 == self.names["a"]!.length: "names"
+                  before(self.counter).count + 1 == self.counter.count: "counter"
+                  before(self).values.length + 1 == self.values.length: "self"
+              }
+              self.values.append(2)
+              self.nested[0].append(2)
+              self.names["a"]!.append(2)
+              self.counter.increment()
+          }
+      }
+
+      fun test() {
+          S().test()
+      }
+    `)
+
+	_, err := inter.Invoke("test")
+	require.NoError(t, err)
+}
+
 func TestInterpretFunctionPostConditionWithBeforeFailingPreCondition(t *testing.T) {
 
 	t.Parallel()
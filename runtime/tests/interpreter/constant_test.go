@@ -0,0 +1,281 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+// TestInterpretConstantExpressions checks that the values of constant expressions,
+// which are evaluated by the checker, are equal to the values of the same expressions
+// evaluated by the interpreter
+//
+func TestInterpretConstantExpressions(t *testing.T) {
+
+	t.Parallel()
+
+	operations := []string{"+", "-", "*", "/", "%", "&", "|", "^", "<", "<=", ">", ">=", "==", "!="}
+
+	for _, ty := range sema.AllIntegerTypes {
+
+		ty := ty
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			t.Parallel()
+
+			for _, operation := range operations {
+
+				// Word types wrap around, other types would overflow or underflow
+				var left, right string
+				switch ty {
+				case sema.Word8Type, sema.Word16Type, sema.Word32Type, sema.Word64Type:
+					left, right = "3", "5"
+				default:
+					left, right = "7", "5"
+				}
+
+				code := fmt.Sprintf(
+					`
+                      fun constant(): AnyStruct {
+                          let value: %[1]s = %[2]s %[4]s %[3]s
+                          return value
+                      }
+
+                      fun variable(): AnyStruct {
+                          let left: %[1]s = %[2]s
+                          let right: %[1]s = %[3]s
+                          let value: %[1]s = left %[4]s right
+                          return value
+                      }
+                    `,
+					ty,
+					left,
+					right,
+					operation,
+				)
+
+				switch operation {
+				case "<", "<=", ">", ">=", "==", "!=":
+					code = fmt.Sprintf(
+						`
+                          fun constant(): Bool {
+                              return %[2]s %[4]s %[3]s
+                          }
+
+                          fun variable(): Bool {
+                              let left: %[1]s = %[2]s
+                              let right: %[1]s = %[3]s
+                              return left %[4]s right
+                          }
+                        `,
+						ty,
+						left,
+						right,
+						operation,
+					)
+				}
+
+				inter := parseCheckAndInterpret(t, code)
+
+				constantValue, err := inter.Invoke("constant")
+				require.NoError(t, err)
+
+				variableValue, err := inter.Invoke("variable")
+				require.NoError(t, err)
+
+				AssertValuesEqual(t, inter, variableValue, constantValue)
+			}
+		})
+	}
+}
+
+// TestInterpretFixedPointConstantExpressions checks that the values of constant fixed-point expressions,
+// which are evaluated by the checker, are equal to the values of the same expressions
+// evaluated by the interpreter, including the rounding of inexact results
+//
+func TestInterpretFixedPointConstantExpressions(t *testing.T) {
+
+	t.Parallel()
+
+	operations := []string{"+", "-", "*", "/", "%"}
+
+	for _, ty := range []sema.Type{
+		sema.Fix64Type,
+		sema.UFix64Type,
+		sema.Fix128Type,
+		sema.UFix128Type,
+	} {
+
+		ty := ty
+
+		t.Run(ty.String(), func(t *testing.T) {
+
+			t.Parallel()
+
+			operands := [][2]string{
+				{"1.23456789", "0.33333333"},
+			}
+			if sema.IsSubType(ty, sema.SignedFixedPointType) {
+				operands = append(operands,
+					[2]string{"-1.23456789", "0.33333333"},
+					[2]string{"1.23456789", "-0.33333333"},
+				)
+			}
+
+			for _, operation := range operations {
+				for _, operand := range operands {
+
+					code := fmt.Sprintf(
+						`
+                          fun constant(): AnyStruct {
+                              let value: %[1]s = %[2]s %[4]s %[3]s
+                              return value
+                          }
+
+                          fun variable(): AnyStruct {
+                              let left: %[1]s = %[2]s
+                              let right: %[1]s = %[3]s
+                              let value: %[1]s = left %[4]s right
+                              return value
+                          }
+                        `,
+						ty,
+						operand[0],
+						operand[1],
+						operation,
+					)
+
+					inter := parseCheckAndInterpret(t, code)
+
+					constantValue, err := inter.Invoke("constant")
+					require.NoError(t, err)
+
+					variableValue, err := inter.Invoke("variable")
+					require.NoError(t, err)
+
+					AssertValuesEqual(t, inter, variableValue, constantValue)
+				}
+			}
+		})
+	}
+}
+
+func TestInterpretConstantExpressionWordWrapAround(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      let x: Word8 = 250 + 10
+      let y: Word8 = 3 - 5
+    `)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.Word8Value(4),
+		inter.Globals["x"].GetValue(),
+	)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.Word8Value(254),
+		inter.Globals["y"].GetValue(),
+	)
+}
+
+func TestInterpretContractConstantFields(t *testing.T) {
+
+	t.Parallel()
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          contract C {
+
+              pub let a: Int = 1 + 2
+              pub let s: String = "hello"
+              pub let b: Int
+
+              init() {
+                  self.b = self.a * 2
+              }
+
+              pub fun getA(): Int {
+                  return self.a
+              }
+          }
+
+          fun test(): [AnyStruct] {
+              return [C.a, C.s, C.b, C.getA()]
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				makeContractValueHandler(nil, nil, nil),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeAnyStruct,
+			},
+			common.Address{},
+			interpreter.NewIntValueFromInt64(3),
+			interpreter.NewStringValue("hello"),
+			interpreter.NewIntValueFromInt64(6),
+			interpreter.NewIntValueFromInt64(3),
+		),
+		value,
+	)
+
+	// The constant fields are stored in the contract value,
+	// and all accesses share the same value
+
+	contract := inter.Globals["C"].GetValue().(*interpreter.CompositeValue)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewStringValue("hello"),
+		contract.GetField("s"),
+	)
+
+	require.Same(t,
+		contract.GetMember(inter, interpreter.ReturnEmptyLocationRange, "s"),
+		contract.GetMember(inter, interpreter.ReturnEmptyLocationRange, "s"),
+	)
+}
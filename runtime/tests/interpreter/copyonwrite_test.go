@@ -0,0 +1,463 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretCopyOnWriteArray(t *testing.T) {
+
+	t.Parallel()
+
+	intArrayType := interpreter.VariableSizedStaticType{
+		Type: interpreter.PrimitiveStaticTypeInt,
+	}
+
+	t.Run("mutate copy", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [[Int]] {
+              let a = [1, 2, 3]
+              var b = a
+              b.append(4)
+              b[0] = 5
+              return [a, b]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: intArrayType,
+				},
+				common.Address{},
+				interpreter.NewArrayValue(
+					inter,
+					intArrayType,
+					common.Address{},
+					interpreter.NewIntValueFromInt64(1),
+					interpreter.NewIntValueFromInt64(2),
+					interpreter.NewIntValueFromInt64(3),
+				),
+				interpreter.NewArrayValue(
+					inter,
+					intArrayType,
+					common.Address{},
+					interpreter.NewIntValueFromInt64(5),
+					interpreter.NewIntValueFromInt64(2),
+					interpreter.NewIntValueFromInt64(3),
+					interpreter.NewIntValueFromInt64(4),
+				),
+			),
+			value,
+		)
+	})
+
+	t.Run("mutate original", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [[Int]] {
+              var a = [1, 2, 3]
+              let b = a
+              let c = b
+              a.remove(at: 0)
+              return [a, b, c]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: intArrayType,
+				},
+				common.Address{},
+				interpreter.NewArrayValue(
+					inter,
+					intArrayType,
+					common.Address{},
+					interpreter.NewIntValueFromInt64(2),
+					interpreter.NewIntValueFromInt64(3),
+				),
+				interpreter.NewArrayValue(
+					inter,
+					intArrayType,
+					common.Address{},
+					interpreter.NewIntValueFromInt64(1),
+					interpreter.NewIntValueFromInt64(2),
+					interpreter.NewIntValueFromInt64(3),
+				),
+				interpreter.NewArrayValue(
+					inter,
+					intArrayType,
+					common.Address{},
+					interpreter.NewIntValueFromInt64(1),
+					interpreter.NewIntValueFromInt64(2),
+					interpreter.NewIntValueFromInt64(3),
+				),
+			),
+			value,
+		)
+	})
+
+	t.Run("original in container", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              var outer = [[1, 2]]
+              let inner = outer[0]
+              outer[0] = [3]
+              outer.removeFirst()
+              return inner
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				intArrayType,
+				common.Address{},
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+			),
+			value,
+		)
+	})
+
+	t.Run("mutate original while iterating copy", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              var a = [1, 2, 3]
+              var values: [Int] = []
+              for x in a {
+                  a[2] = 4
+                  a.append(x)
+                  values.append(x)
+              }
+              return values
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				intArrayType,
+				common.Address{},
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(3),
+			),
+			value,
+		)
+	})
+
+	t.Run("copies in loop", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun last(_ values: [Int]): Int {
+              return values[values.length - 1]
+          }
+
+          fun test(): [Int] {
+              var a = [1]
+              var lasts: [Int] = []
+              var i = 0
+              while i < 100 {
+                  last([i])
+                  lasts.append(last(a))
+                  a.append(i + 2)
+                  i = i + 1
+              }
+              return [lasts[0], lasts[99], a.length]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				intArrayType,
+				common.Address{},
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(100),
+				interpreter.NewIntValueFromInt64(101),
+			),
+			value,
+		)
+	})
+
+	t.Run("nested arrays", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              let a = [[1]]
+              let b = a
+              b[0].append(2)
+              return a[0]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				intArrayType,
+				common.Address{},
+				interpreter.NewIntValueFromInt64(1),
+			),
+			value,
+		)
+	})
+
+	t.Run("resources are moved", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {}
+
+          fun test(): Int {
+              let a <- [<-create R()]
+              let b <- a
+              let count = b.length
+              destroy b
+              return count
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(1),
+			value,
+		)
+	})
+}
+
+func TestInterpretCopyOnWriteDictionary(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [Int?] {
+          var a = {"one": 1, "two": 2}
+          var b = a
+          b["one"] = 3
+          let c = a
+          a.remove(key: "two")
+          return [a["one"], a["two"], b["one"], c["two"]]
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.OptionalStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+			common.Address{},
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(1)),
+			interpreter.NilValue{},
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(3)),
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+		),
+		value,
+	)
+}
+
+func TestInterpretCopyOnWriteStorage(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewAddressValueFromBytes([]byte{42})
+
+	inter, getAccountValues := testAccount(
+		t,
+		address,
+		true,
+		`
+          fun test() {
+              var a = [1, 2]
+              let b = a
+              account.save(b, to: /storage/b)
+              a.append(3)
+              account.save(a, to: /storage/a)
+              account.save([1, 2], to: /storage/c)
+          }
+        `,
+	)
+
+	_, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	accountValues := getAccountValues()
+	require.Len(t, accountValues, 3)
+
+	intArrayType := interpreter.VariableSizedStaticType{
+		Type: interpreter.PrimitiveStaticTypeInt,
+	}
+
+	storedValue := func(identifier string) *interpreter.ArrayValue {
+		key := storageKey{
+			address: address.ToAddress(),
+			domain:  common.PathDomainStorage.Identifier(),
+			key:     identifier,
+		}
+		return accountValues[key].(*interpreter.ArrayValue)
+	}
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			intArrayType,
+			common.Address{},
+			interpreter.NewIntValueFromInt64(1),
+			interpreter.NewIntValueFromInt64(2),
+			interpreter.NewIntValueFromInt64(3),
+		),
+		storedValue("a"),
+	)
+
+	// The lazily copied array is encoded like an eagerly constructed array
+
+	encode := func(value *interpreter.ArrayValue) []byte {
+		slab, ok, err := inter.Storage.Retrieve(value.StorageID())
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		encoded, err := atree.Encode(slab, interpreter.CBOREncMode)
+		require.NoError(t, err)
+
+		return encoded
+	}
+
+	require.Equal(t,
+		encode(storedValue("c")),
+		encode(storedValue("b")),
+	)
+}
+
+func TestInterpretCopyOnWriteDisabled(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      let a = [1, 2, 3]
+      let b = a
+    `
+
+	storageIDs := func(t *testing.T, enabled bool) (atree.StorageID, atree.StorageID) {
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			code,
+			ParseCheckAndInterpretOptions{
+				Options: []interpreter.Option{
+					interpreter.WithCopyOnWriteEnabled(enabled),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		a := inter.Globals["a"].GetValue().(*interpreter.ArrayValue)
+		b := inter.Globals["b"].GetValue().(*interpreter.ArrayValue)
+
+		return a.StorageID(), b.StorageID()
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		// The lazy copy shares the container of the original
+
+		a, b := storageIDs(t, true)
+		require.Equal(t, a, b)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		// The container is copied eagerly
+
+		a, b := storageIDs(t, false)
+		require.NotEqual(t, a, b)
+	})
+}
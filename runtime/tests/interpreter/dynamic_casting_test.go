@@ -2203,12 +2203,13 @@ func returnReferenceCasted(fromType, targetType string, operation ast.Operation,
 		if isResource {
 			return fmt.Sprintf(
 				`
-                  fun test(): %[2]s? {
+                  fun test(): Bool {
                       let x <- create R()
                       let r = &x as %[1]s
                       let r2 = r as? %[2]s
+                      let isSuccess = r2 != nil
                       destroy x
-                      return r2
+                      return isSuccess
                   }
                 `,
 				fromType,
@@ -2232,12 +2233,12 @@ func returnReferenceCasted(fromType, targetType string, operation ast.Operation,
 		if isResource {
 			return fmt.Sprintf(
 				`
-                  fun test(): %[2]s {
+                  fun test(): Bool {
                       let x <- create R()
                       let r = &x as %[1]s
                       let r2 = r as! %[2]s
                       destroy x
-                      return r2
+                      return true
                   }
                 `,
 				fromType,
@@ -2271,6 +2272,16 @@ func testReferenceCastValid(t *testing.T, types, fromType, targetType string, op
 	value, err := inter.Invoke("test")
 	require.NoError(t, err)
 
+	// The reference to the resource cannot be returned,
+	// as the resource is destroyed at the end of the function
+	if isResource {
+		require.Equal(t,
+			interpreter.BoolValue(true),
+			value,
+		)
+		return
+	}
+
 	switch operation {
 	case ast.OperationFailableCast:
 
@@ -2310,10 +2321,17 @@ func testReferenceCastInvalid(t *testing.T, types, fromType, targetType string,
 	case ast.OperationFailableCast:
 		require.NoError(t, err)
 
-		require.IsType(t,
-			interpreter.NilValue{},
-			value,
-		)
+		if isResource {
+			require.Equal(t,
+				interpreter.BoolValue(false),
+				value,
+			)
+		} else {
+			require.IsType(t,
+				interpreter.NilValue{},
+				value,
+			)
+		}
 
 	case ast.OperationForceCast:
 		require.ErrorAs(t, err, &interpreter.ForceCastTypeMismatchError{})
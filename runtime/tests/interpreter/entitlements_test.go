@@ -0,0 +1,148 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretEntitledReferenceMemberAccess(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+        entitlement E
+
+        struct S {
+            access(E) var x: Int
+
+            init() {
+                self.x = 1
+            }
+        }
+
+        fun test(): Int {
+            let s = S()
+            let ref = &s as auth(E) &S
+            ref.x = 2
+            return ref.x
+        }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewIntValueFromInt64(2),
+		value,
+	)
+}
+
+func TestInterpretEntitledReferenceCasting(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+        entitlement E1
+        entitlement E2
+
+        struct S {}
+
+        let s = S()
+
+        fun fewer(): Bool {
+            let ref: AnyStruct = &s as auth(E1, E2) &S
+            return (ref as? auth(E1) &S) != nil
+        }
+
+        fun more(): Bool {
+            let ref: AnyStruct = &s as auth(E1) &S
+            return (ref as? auth(E1, E2) &S) != nil
+        }
+
+        fun authorized(): Bool {
+            let ref: AnyStruct = &s as auth &S
+            return (ref as? auth(E1, E2) &S) != nil
+        }
+
+        fun unauthorized(): Bool {
+            let ref: AnyStruct = &s as auth(E1, E2) &S
+            return (ref as? auth &S) != nil
+        }
+    `)
+
+	for name, expected := range map[string]bool{
+		"fewer":        true,
+		"more":         false,
+		"authorized":   true,
+		"unauthorized": false,
+	} {
+		value, err := inter.Invoke(name)
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.BoolValue(expected),
+			value,
+		)
+	}
+}
+
+func TestInterpretEntitledReferenceRuntimeType(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+        entitlement E1
+        entitlement E2
+
+        struct S {}
+
+        let type = Type<auth(E2, E1) &S>()
+        let isExpected = type == Type<auth(E1, E2) &S>()
+        let isUnentitled = type == Type<&S>()
+    `)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.BoolValue(true),
+		inter.Globals["isExpected"].GetValue(),
+	)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.BoolValue(false),
+		inter.Globals["isUnentitled"].GetValue(),
+	)
+
+	require.Equal(t,
+		"auth(S.test.E1, S.test.E2) &S.test.S",
+		inter.Globals["type"].GetValue().(interpreter.TypeValue).Type.String(),
+	)
+}
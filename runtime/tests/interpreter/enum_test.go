@@ -183,6 +183,70 @@ func TestInterpretEnumConstructor(t *testing.T) {
 	)
 }
 
+func TestInterpretEnumAllCases(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      enum E: Int64 {
+          case a
+          case b
+          case c
+      }
+
+      fun rawValues(): [Int64] {
+          let values: [Int64] = []
+          for e in E.allCases {
+              values.append(e.rawValue)
+          }
+          return values
+      }
+
+      let res = [
+          E.allCases.length == 3,
+          E.allCases[0] == E.a,
+          E.allCases[1] == E.b,
+          E.allCases[2] == E.c
+      ]
+    `)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeBool,
+			},
+			common.Address{},
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(true),
+		),
+		inter.Globals["res"].GetValue(),
+	)
+
+	value, err := inter.Invoke("rawValues")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt64,
+			},
+			common.Address{},
+			interpreter.Int64Value(0),
+			interpreter.Int64Value(1),
+			interpreter.Int64Value(2),
+		),
+		value,
+	)
+}
+
 func TestInterpretEnumInstance(t *testing.T) {
 
 	t.Parallel()
@@ -22,14 +22,17 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	. "github.com/onflow/cadence/runtime/tests/utils"
 
+	"github.com/onflow/cadence/fixedpoint"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
 )
 
 func TestInterpretNegativeZeroFixedPoint(t *testing.T) {
@@ -54,9 +57,11 @@ func TestInterpretFixedPointConversionAndAddition(t *testing.T) {
 
 	tests := map[string]interpreter.Value{
 		// Fix*
-		"Fix64": interpreter.Fix64Value(123000000),
+		"Fix64":  interpreter.Fix64Value(123000000),
+		"Fix128": interpreter.NewFix128ValueFromBigInt(parseFix128(t, "1.23")),
 		// UFix*
-		"UFix64": interpreter.UFix64Value(123000000),
+		"UFix64":  interpreter.UFix64Value(123000000),
+		"UFix128": interpreter.NewUFix128ValueFromBigInt(parseFix128(t, "1.23")),
 	}
 
 	for _, fixedPointType := range sema.AllFixedPointTypes {
@@ -112,8 +117,16 @@ func TestInterpretFixedPointConversionAndAddition(t *testing.T) {
 }
 
 var testFixedPointValues = map[string]interpreter.Value{
-	"Fix64":  interpreter.Fix64Value(50 * sema.Fix64Factor),
-	"UFix64": interpreter.UFix64Value(50 * sema.Fix64Factor),
+	"Fix64":   interpreter.Fix64Value(50 * sema.Fix64Factor),
+	"UFix64":  interpreter.UFix64Value(50 * sema.Fix64Factor),
+	"Fix128":  interpreter.NewFix128ValueWithInteger(big.NewInt(50)),
+	"UFix128": interpreter.NewUFix128ValueWithInteger(big.NewInt(50)),
+}
+
+func parseFix128(t *testing.T, literal string) *big.Int {
+	value, err := fixedpoint.ParseFix128(literal)
+	require.NoError(t, err)
+	return value
 }
 
 func init() {
@@ -571,6 +584,14 @@ func TestInterpretFixedPointMinMax(t *testing.T) {
 			min: interpreter.UFix64Value(0),
 			max: interpreter.UFix64Value(math.MaxUint64),
 		},
+		sema.Fix128Type: {
+			min: interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMinBig),
+			max: interpreter.NewFix128ValueFromBigInt(sema.Fix128TypeMaxBig),
+		},
+		sema.UFix128Type: {
+			min: interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMinBig),
+			max: interpreter.NewUFix128ValueFromBigInt(sema.UFix128TypeMaxBig),
+		},
 	}
 
 	for _, ty := range sema.AllFixedPointTypes {
@@ -592,3 +613,360 @@ func TestInterpretFixedPointMinMax(t *testing.T) {
 		})
 	}
 }
+
+func TestInterpretFix128(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("arithmetic", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          let a: Fix128 = 1.000000000000000000000001
+          let b: Fix128 = 2.5
+          let sum = a + b
+          let difference = a - b
+          let product = b * b
+          let quotient = b / 2.0
+          let remainder = b % 2.0
+        `)
+
+		for name, expected := range map[string]string{
+			"sum":        "3.500000000000000000000001",
+			"difference": "-1.499999999999999999999999",
+			"product":    "6.25",
+			"quotient":   "1.25",
+			"remainder":  "0.5",
+		} {
+			AssertValuesEqual(
+				t,
+				inter,
+				interpreter.NewFix128ValueFromBigInt(parseFix128(t, expected)),
+				inter.Globals[name].GetValue(),
+			)
+		}
+	})
+
+	t.Run("division rounds towards zero", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          let x: Fix128 = 1.0 / 3.0
+          let y: Fix128 = -1.0 / 3.0
+          let z: UFix128 = 2.0 / 3.0
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewFix128ValueFromBigInt(parseFix128(t, "0.333333333333333333333333")),
+			inter.Globals["x"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewFix128ValueFromBigInt(parseFix128(t, "-0.333333333333333333333333")),
+			inter.Globals["y"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUFix128ValueFromBigInt(parseFix128(t, "0.666666666666666666666666")),
+			inter.Globals["z"].GetValue(),
+		)
+	})
+
+	t.Run("conversion from and to Fix64", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          let a: Fix64 = -12.34567891
+          let b = Fix128(a)
+          let c: Fix128 = 1.234567891234567891234567
+          let d = Fix64(c)
+          let e = UFix64(UFix128(c))
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewFix128ValueFromBigInt(parseFix128(t, "-12.34567891")),
+			inter.Globals["b"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.Fix64Value(1_23456789),
+			inter.Globals["d"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.UFix64Value(1_23456789),
+			inter.Globals["e"].GetValue(),
+		)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Fix128 {
+              return Fix128.max + 0.000000000000000000000001
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+
+	t.Run("underflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): UFix128 {
+              return UFix128.min - 0.000000000000000000000001
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.UnderflowError{})
+	})
+
+	t.Run("division by zero", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Fix128 {
+              return 1.0 / Fix128(0)
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.DivisionByZeroError{})
+	})
+}
+
+func TestInterpretFixedPointRoundingFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	valueDeclarations := stdlib.BuiltinValues()
+
+	type testCase struct {
+		left, function, right string
+		signedOnly            bool
+		// expected results for the rules
+		// towardZero, awayFromZero, nearestHalfAway, nearestHalfEven
+		expected [4]string
+	}
+
+	for _, ty := range sema.AllFixedPointTypes {
+		// Only test leaf types
+		switch ty {
+		case sema.FixedPointType, sema.SignedFixedPointType:
+			continue
+		}
+
+		scale := int(ty.(sema.FractionalRangedType).Scale())
+
+		// fraction returns a number with the given integer part,
+		// and the given digit repeated up to the scale, followed by the given last digit
+		fraction := func(integer string, digit string, last string) string {
+			return integer + "." + strings.Repeat(digit, scale-1) + last
+		}
+
+		zero := fraction("0", "0", "0")
+		oneUnit := fraction("0", "0", "1")
+		twoUnits := fraction("0", "0", "2")
+		threeUnits := fraction("0", "0", "3")
+
+		testCases := []testCase{
+			{
+				left:     "1.0",
+				function: sema.FixedPointTypeDivideRoundFunctionName,
+				right:    "3.0",
+				expected: [4]string{
+					fraction("0", "3", "3"),
+					fraction("0", "3", "4"),
+					fraction("0", "3", "3"),
+					fraction("0", "3", "3"),
+				},
+			},
+			{
+				left:     "2.0",
+				function: sema.FixedPointTypeDivideRoundFunctionName,
+				right:    "3.0",
+				expected: [4]string{
+					fraction("0", "6", "6"),
+					fraction("0", "6", "7"),
+					fraction("0", "6", "7"),
+					fraction("0", "6", "7"),
+				},
+			},
+			{
+				left:     oneUnit,
+				function: sema.FixedPointTypeDivideRoundFunctionName,
+				right:    "2.0",
+				expected: [4]string{zero, oneUnit, oneUnit, zero},
+			},
+			{
+				left:     threeUnits,
+				function: sema.FixedPointTypeDivideRoundFunctionName,
+				right:    "2.0",
+				expected: [4]string{oneUnit, twoUnits, twoUnits, twoUnits},
+			},
+			{
+				left:     oneUnit,
+				function: sema.FixedPointTypeMultiplyRoundFunctionName,
+				right:    "0.5",
+				expected: [4]string{zero, oneUnit, oneUnit, zero},
+			},
+			{
+				left:     "1.0",
+				function: sema.FixedPointTypeMultiplyRoundFunctionName,
+				right:    "2.0",
+				expected: [4]string{
+					fraction("2", "0", "0"),
+					fraction("2", "0", "0"),
+					fraction("2", "0", "0"),
+					fraction("2", "0", "0"),
+				},
+			},
+			{
+				left:       "-2.0",
+				function:   sema.FixedPointTypeDivideRoundFunctionName,
+				right:      "3.0",
+				signedOnly: true,
+				expected: [4]string{
+					fraction("-0", "6", "6"),
+					fraction("-0", "6", "7"),
+					fraction("-0", "6", "7"),
+					fraction("-0", "6", "7"),
+				},
+			},
+			{
+				left:       "-" + oneUnit,
+				function:   sema.FixedPointTypeMultiplyRoundFunctionName,
+				right:      "0.5",
+				signedOnly: true,
+				expected:   [4]string{zero, "-" + oneUnit, "-" + oneUnit, zero},
+			},
+		}
+
+		isSigned := sema.IsSubType(ty, sema.SignedFixedPointType)
+
+		for _, testCase := range testCases {
+
+			if testCase.signedOnly && !isSigned {
+				continue
+			}
+
+			for i, rule := range sema.RoundingRules {
+
+				expected := testCase.expected[i]
+
+				testName := fmt.Sprintf(
+					"%s: %s.%s(%s, %s)",
+					ty,
+					testCase.left,
+					testCase.function,
+					testCase.right,
+					rule.Name(),
+				)
+
+				t.Run(testName, func(t *testing.T) {
+
+					inter, err := parseCheckAndInterpretWithOptions(t,
+						fmt.Sprintf(
+							`
+                              let x: %[1]s = %[2]s
+                              let y = x.%[3]s(%[4]s, rule: RoundingRule.%[5]s)
+                            `,
+							ty,
+							testCase.left,
+							testCase.function,
+							testCase.right,
+							rule.Name(),
+						),
+						ParseCheckAndInterpretOptions{
+							CheckerOptions: []sema.Option{
+								sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
+							},
+							Options: []interpreter.Option{
+								interpreter.WithPredeclaredValues(valueDeclarations.ToInterpreterValueDeclarations()),
+							},
+						},
+					)
+					require.NoError(t, err)
+
+					require.Equal(t,
+						expected,
+						inter.Globals["y"].GetValue().String(),
+					)
+				})
+			}
+		}
+	}
+
+	t.Run("division by zero", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			`
+              fun test(): Fix64 {
+                  let x: Fix64 = 1.0
+                  return x.divideRound(0.0, rule: RoundingRule.towardZero)
+              }
+            `,
+			ParseCheckAndInterpretOptions{
+				CheckerOptions: []sema.Option{
+					sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
+				},
+				Options: []interpreter.Option{
+					interpreter.WithPredeclaredValues(valueDeclarations.ToInterpreterValueDeclarations()),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.DivisionByZeroError{})
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			`
+              fun test(): UFix64 {
+                  return UFix64.max.multiplyRound(2.0, rule: RoundingRule.towardZero)
+              }
+            `,
+			ParseCheckAndInterpretOptions{
+				CheckerOptions: []sema.Option{
+					sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
+				},
+				Options: []interpreter.Option{
+					interpreter.WithPredeclaredValues(valueDeclarations.ToInterpreterValueDeclarations()),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+}
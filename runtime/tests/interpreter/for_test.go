@@ -25,6 +25,7 @@ import (
 
 	. "github.com/onflow/cadence/runtime/tests/utils"
 
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
@@ -219,3 +220,382 @@ func TestInterpretForStatementEmpty(t *testing.T) {
 		value,
 	)
 }
+
+func TestInterpretForStatementRange(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("exclusive", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               var sum = 0
+               for i in 0..<5 {
+                   sum = sum + i
+               }
+               return sum
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(10),
+			value,
+		)
+	})
+
+	t.Run("inclusive", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               var sum = 0
+               for i in 1...5 {
+                   sum = sum + i
+               }
+               return sum
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(15),
+			value,
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               var count = 0
+               let n: UInt = 0
+               for i in 0..<n {
+                   count = count + 1
+               }
+               for i in 5...1 {
+                   count = count + 1
+               }
+               return count
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(0),
+			value,
+		)
+	})
+
+	t.Run("maximum value", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               var count = 0
+               let start: UInt8 = 250
+               for i in start...UInt8.max {
+                   count = count + 1
+               }
+               return count
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(6),
+			value,
+		)
+	})
+
+	t.Run("break and index", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               var found = 0
+               for index, i in 10..<20 {
+                   if i == 13 {
+                       found = index
+                       break
+                   }
+               }
+               return found
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(3),
+			value,
+		)
+	})
+
+	t.Run("members", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           let range = 0..<10
+           let start = range.start
+           let end = range.end
+           let isEmpty = range.isEmpty
+           let containsEnd = range.contains(10)
+           let containsLast = range.contains(9)
+        `)
+
+		AssertValuesEqual(t, inter, interpreter.NewIntValueFromInt64(0), inter.Globals["start"].GetValue())
+		AssertValuesEqual(t, inter, interpreter.NewIntValueFromInt64(9), inter.Globals["end"].GetValue())
+		AssertValuesEqual(t, inter, interpreter.BoolValue(false), inter.Globals["isEmpty"].GetValue())
+		AssertValuesEqual(t, inter, interpreter.BoolValue(false), inter.Globals["containsEnd"].GetValue())
+		AssertValuesEqual(t, inter, interpreter.BoolValue(true), inter.Globals["containsLast"].GetValue())
+	})
+}
+
+func TestInterpretForStatementDictionary(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+       fun test(): Int {
+           let xs = {1: "a", 2: "b", 3: "c"}
+           var sum = 0
+           for key in xs {
+               xs.remove(key: key)
+               sum = sum + key
+           }
+           return sum
+       }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewIntValueFromInt64(6),
+		value,
+	)
+}
+
+func TestInterpretForStatementDictionaryView(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("keys", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               let xs = {1: "a", 2: "b", 3: "c"}
+               var sum = 0
+               for key in xs.keys {
+                   sum = sum + key
+               }
+               return sum
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(6),
+			value,
+		)
+	})
+
+	t.Run("values", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               let xs = {"a": 1, "b": 2, "c": 3}
+               var sum = 0
+               for value in xs.values {
+                   sum = sum + value
+               }
+               return sum
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(6),
+			value,
+		)
+	})
+
+	t.Run("values, removed during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               let xs = {"a": 1, "b": 2}
+               var count = 0
+               for value in xs.values {
+                   xs.remove(key: "a")
+                   xs.remove(key: "b")
+                   count = count + 1
+               }
+               return count
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(1),
+			value,
+		)
+	})
+
+	t.Run("values are copied", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           struct S {
+               var x: Int
+
+               init() {
+                   self.x = 0
+               }
+           }
+
+           fun test(): Int {
+               let xs = {"a": S(), "b": S()}
+               for s in xs.values {
+                   s.x = 1
+               }
+               return xs["a"]!.x + xs["b"]!.x
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(0),
+			value,
+		)
+	})
+
+	t.Run("optional chaining", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+           fun test(): Int {
+               let xs: {Int: String}? = {1: "a", 2: "b"}
+               var sum = 0
+               for key in xs?.keys ?? [] {
+                   sum = sum + key
+               }
+               return sum
+           }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(3),
+			value,
+		)
+	})
+}
+
+func TestInterpretForStatementIterator(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+       struct Countdown {
+           var current: Int
+
+           init(from: Int) {
+               self.current = from
+           }
+
+           fun next(): Int? {
+               if self.current <= 0 {
+                   return nil
+               }
+               self.current = self.current - 1
+               return self.current + 1
+           }
+       }
+
+       fun test(): [Int] {
+           let values: [Int] = []
+           for value in Countdown(from: 3) {
+               values.append(value)
+           }
+           return values
+       }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			common.Address{},
+			interpreter.NewIntValueFromInt64(3),
+			interpreter.NewIntValueFromInt64(2),
+			interpreter.NewIntValueFromInt64(1),
+		),
+		value,
+	)
+}
@@ -0,0 +1,145 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretGlobalDeclarations(t *testing.T) {
+
+	t.Parallel()
+
+	valueDeclarations := stdlib.StandardLibraryValues{
+		{
+			Name: "predeclared",
+			Type: sema.IntType,
+			ValueFactory: func(_ *interpreter.Interpreter) interpreter.Value {
+				return interpreter.NewIntValueFromInt64(42)
+			},
+			Kind: common.DeclarationKindConstant,
+		},
+	}
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          pub contract C {
+              pub let x: Int
+
+              init() {
+                  self.x = 1
+              }
+          }
+
+          pub var y = 2
+
+          pub fun test() {
+              y = 3
+          }
+
+          pub struct S {}
+        `,
+		ParseCheckAndInterpretOptions{
+			CheckerOptions: []sema.Option{
+				sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
+			},
+			Options: []interpreter.Option{
+				interpreter.WithPredeclaredValues(valueDeclarations.ToInterpreterValueDeclarations()),
+				makeContractValueHandler(nil, nil, nil),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = inter.Invoke("test")
+	require.NoError(t, err)
+
+	t.Run("global declarations", func(t *testing.T) {
+
+		t.Parallel()
+
+		declarations, ok := inter.GlobalDeclarations(TestLocation)
+		require.True(t, ok)
+
+		require.Len(t, declarations, 4)
+
+		names := make([]string, len(declarations))
+		for i, declaration := range declarations {
+			names[i] = declaration.Name
+		}
+		assert.Equal(t, []string{"C", "y", "test", "S"}, names)
+
+		assert.Equal(t, common.DeclarationKindContract, declarations[0].DeclarationKind)
+		assert.Equal(t, common.DeclarationKindVariable, declarations[1].DeclarationKind)
+		assert.Equal(t, sema.IntType, declarations[1].Type)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(3),
+			declarations[1].Value(),
+		)
+
+		assert.Equal(t, common.DeclarationKindFunction, declarations[2].DeclarationKind)
+		assert.IsType(t, &sema.FunctionType{}, declarations[2].Type)
+
+		assert.Equal(t, common.DeclarationKindStructure, declarations[3].DeclarationKind)
+	})
+
+	t.Run("contract declarations", func(t *testing.T) {
+
+		t.Parallel()
+
+		declarations, ok := inter.ContractDeclarations(TestLocation)
+		require.True(t, ok)
+
+		require.Len(t, declarations, 1)
+
+		declaration := declarations[0]
+		assert.Equal(t, "C", declaration.Name)
+		assert.IsType(t, &sema.CompositeType{}, declaration.Type)
+
+		contractValue, ok := declaration.Value().(*interpreter.CompositeValue)
+		require.True(t, ok)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(1),
+			contractValue.GetField("x"),
+		)
+	})
+
+	t.Run("unknown location", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, ok := inter.GlobalDeclarations(common.StringLocation("unknown"))
+		require.False(t, ok)
+	})
+}
@@ -46,6 +46,7 @@ type ParseCheckAndInterpretOptions struct {
 	Options            []interpreter.Option
 	CheckerOptions     []sema.Option
 	HandleCheckerError func(error)
+	EnabledFeatures    common.ExperimentalFeatures
 }
 
 func parseCheckAndInterpret(t testing.TB, code string) *interpreter.Interpreter {
@@ -66,7 +67,8 @@ func parseCheckAndInterpretWithOptions(
 	checker, err := checker.ParseAndCheckWithOptions(t,
 		code,
 		checker.ParseAndCheckOptions{
-			Options: options.CheckerOptions,
+			Options:         options.CheckerOptions,
+			EnabledFeatures: options.EnabledFeatures,
 		},
 	)
 
@@ -95,6 +97,7 @@ func parseCheckAndInterpretWithOptions(
 			interpreter.WithStorage(interpreter.NewInMemoryStorage()),
 			interpreter.WithAtreeValueValidationEnabled(true),
 			interpreter.WithAtreeStorageValidationEnabled(true),
+			interpreter.WithOwnerValidationEnabled(true),
 		},
 		options.Options...,
 	)
@@ -1552,6 +1555,65 @@ func TestInterpretConditionalOperator(t *testing.T) {
 	)
 }
 
+func TestInterpretLiteralTypeInferenceFromOtherOperand(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+       let a: UInt8 = 1
+
+       fun testConditional(): AnyStruct {
+           let x = false ? a : 2
+           return x
+       }
+
+       fun testBinary(): AnyStruct {
+           let x = 3 + a
+           return x
+       }
+
+       fun testEmptyArray(): Type {
+           let xs: [UInt8] = [1]
+           let ys = true ? [] : xs
+           return ys.getType()
+       }
+    `)
+
+	value, err := inter.Invoke("testConditional")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.UInt8Value(2),
+		value,
+	)
+
+	value, err = inter.Invoke("testBinary")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.UInt8Value(4),
+		value,
+	)
+
+	value, err = inter.Invoke("testEmptyArray")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.TypeValue{
+			Type: interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeUInt8,
+			},
+		},
+		value,
+	)
+}
+
 func TestInterpretFunctionBindingInFunction(t *testing.T) {
 
 	t.Parallel()
@@ -2224,6 +2286,39 @@ func TestInterpretStructureInitializesConstant(t *testing.T) {
 	)
 }
 
+func TestInterpretStructureInitializingFunction(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      struct Test {
+          let foo: Int
+          let bar: Int
+
+          init() {
+              self.foo = 1
+              self.setup(41)
+          }
+
+          /// @initializes(bar)
+          priv fun setup(_ value: Int) {
+              self.bar = value + 1
+          }
+      }
+
+      let test = Test()
+    `)
+
+	actual := inter.Globals["test"].GetValue().(*interpreter.CompositeValue).
+		GetMember(inter, interpreter.ReturnEmptyLocationRange, "bar")
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewIntValueFromInt64(42),
+		actual,
+	)
+}
+
 func TestInterpretStructureFunctionMutatesSelf(t *testing.T) {
 
 	t.Parallel()
@@ -4613,7 +4708,7 @@ func TestInterpretReferenceFailableDowncasting(t *testing.T) {
 			nil,
 		)
 
-		storageMap := storage.GetStorageMap(storageAddress, storagePath.Domain.Identifier())
+		storageMap := storage.GetStorageMap(storageAddress, storagePath.Domain.Identifier(), true)
 		storageMap.WriteValue(inter, storagePath.Identifier, r)
 
 		result, err := inter.Invoke("testInvalidUnauthorized")
@@ -5512,6 +5607,348 @@ func TestInterpretDictionaryKeys(t *testing.T) {
 	)
 }
 
+func TestInterpretDictionaryKeysSorted(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("String", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [String] {
+              let dict = {"def": 2, "abc": 1}
+              dict.insert(key: "a", 3)
+              return dict.keysSorted()
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewStringValue("a"),
+				interpreter.NewStringValue("abc"),
+				interpreter.NewStringValue("def"),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("Int", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              return {3: "c", -10: "a", 1: "b", 200: "d"}.keysSorted()
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewIntValueFromInt64(-10),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(3),
+				interpreter.NewIntValueFromInt64(200),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("Address", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Address] {
+              let dict: {Address: String} = {0x2: "b", 0x10: "c", 0x1: "a"}
+              return dict.keysSorted()
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewAddressValueFromBytes([]byte{0x1}),
+				interpreter.NewAddressValueFromBytes([]byte{0x2}),
+				interpreter.NewAddressValueFromBytes([]byte{0x10}),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [UFix64] {
+              let dict: {UFix64: Bool} = {}
+              return dict.keysSorted()
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.Equal(t, 0, value.(*interpreter.ArrayValue).Count())
+	})
+}
+
+func TestInterpretArrayFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              var sum = 0
+              [1, 2, 3].forEach(fun (x: Int) {
+                  sum = sum + x
+              })
+              return sum
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(6),
+			value,
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [String] {
+              return [1, 2, 3].map(fun (x: Int): String {
+                  return x.toString()
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+				common.Address{},
+				interpreter.NewStringValue("1"),
+				interpreter.NewStringValue("2"),
+				interpreter.NewStringValue("3"),
+			),
+			value,
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              return [1, 2, 3, 4].filter(fun (x: Int): Bool {
+                  return x % 2 == 0
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(4),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("elements are copied", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct S {
+              var x: Int
+
+              init() {
+                  self.x = 0
+              }
+          }
+
+          fun test(): Int {
+              let structs = [S(), S()]
+              structs.forEach(fun (s: S) {
+                  s.x = 1
+              })
+              return structs[0].x + structs[1].x
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(0),
+			value,
+		)
+	})
+}
+
+func TestInterpretDictionaryFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              var sum = 0
+              {1: 10, 2: 20}.forEach(fun (key: Int, value: Int) {
+                  sum = sum + key + value
+              })
+              return sum
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(33),
+			value,
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): {String: Bool} {
+              return {"a": 1, "b": 2}.map(fun (key: String, value: Int): Bool {
+                  return value > 1
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewDictionaryValue(
+				inter,
+				interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeBool,
+				},
+				interpreter.NewStringValue("a"), interpreter.BoolValue(false),
+				interpreter.NewStringValue("b"), interpreter.BoolValue(true),
+			),
+			value,
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): {String: Int} {
+              return {"a": 1, "b": 2, "c": 3}.filter(fun (key: String, value: Int): Bool {
+                  return key != "b"
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewDictionaryValue(
+				inter,
+				interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeInt,
+				},
+				interpreter.NewStringValue("a"), interpreter.NewIntValueFromInt64(1),
+				interpreter.NewStringValue("c"), interpreter.NewIntValueFromInt64(3),
+			),
+			value,
+		)
+	})
+
+	t.Run("removal during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              let dict = {1: 10, 2: 20}
+              var count = 0
+              dict.forEach(fun (key: Int, value: Int) {
+                  count = count + 1
+                  dict.remove(key: 1)
+                  dict.remove(key: 2)
+              })
+              return count
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(1),
+			value,
+		)
+	})
+}
+
 func TestInterpretDictionaryValues(t *testing.T) {
 
 	t.Parallel()
@@ -5601,6 +6038,71 @@ func TestInterpretDictionaryKeyTypes(t *testing.T) {
 	}
 }
 
+func TestInterpretStructDictionaryKeys(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      enum E: UInt8 {
+          case a
+          case b
+      }
+
+      struct Key {
+          let name: String
+          let number: Int
+          let e: E
+
+          init(name: String, number: Int, e: E) {
+              self.name = name
+              self.number = number
+              self.e = e
+          }
+      }
+
+      fun test(): [String?] {
+          let xs: {Key: String} = {
+              Key(name: "a", number: 1, e: E.a): "a1"
+          }
+          xs[Key(name: "a", number: 2, e: E.a)] = "a2"
+          xs[Key(name: "a", number: 1, e: E.a)] = "a1 again"
+
+          if xs.length != 2 {
+              return []
+          }
+
+          return [
+              xs[Key(name: "a", number: 1, e: E.a)],
+              xs[Key(name: "a", number: 2, e: E.a)],
+              xs[Key(name: "a", number: 1, e: E.b)],
+              xs[Key(name: "b", number: 1, e: E.a)]
+          ]
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.OptionalStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+			},
+			common.Address{},
+			interpreter.NewSomeValueNonCopying(interpreter.NewStringValue("a1 again")),
+			interpreter.NewSomeValueNonCopying(interpreter.NewStringValue("a2")),
+			interpreter.NilValue{},
+			interpreter.NilValue{},
+		),
+		value,
+	)
+}
+
 func TestInterpretPathToString(t *testing.T) {
 
 	t.Parallel()
@@ -6471,7 +6973,7 @@ func TestInterpretEmitEventParameterTypes(t *testing.T) {
 		Location:   TestLocation,
 		Identifier: "S",
 		Kind:       common.CompositeKindStructure,
-		Members:    sema.NewStringMemberOrderedMap(),
+		Members:    &sema.StringMemberOrderedMap{},
 	}
 
 	storage := interpreter.NewInMemoryStorage()
@@ -6591,11 +7093,19 @@ func TestInterpretEmitEventParameterTypes(t *testing.T) {
 			value: interpreter.Fix64Value(123000000),
 			ty:    sema.Fix64Type,
 		},
+		"Fix128": {
+			value: interpreter.NewFix128ValueFromBigInt(parseFix128(t, "1.23")),
+			ty:    sema.Fix128Type,
+		},
 		// UFix*
 		"UFix64": {
 			value: interpreter.UFix64Value(123000000),
 			ty:    sema.UFix64Type,
 		},
+		"UFix128": {
+			value: interpreter.NewUFix128ValueFromBigInt(parseFix128(t, "1.23")),
+			ty:    sema.UFix128Type,
+		},
 		// TODO:
 		//// Struct
 		//"S": {
@@ -6882,7 +7392,7 @@ func TestInterpretReferenceExpression(t *testing.T) {
 
 	t.Parallel()
 
-	inter := parseCheckAndInterpret(t, `
+	inter, err := parseCheckAndInterpretWithOptions(t, `
       resource R {}
 
       fun test(): &R {
@@ -6891,7 +7401,16 @@ func TestInterpretReferenceExpression(t *testing.T) {
           destroy r
           return ref
       }
-    `)
+    `,
+		ParseCheckAndInterpretOptions{
+			HandleCheckerError: func(err error) {
+				errs := checker.ExpectCheckerErrors(t, err, 1)
+
+				require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+			},
+		},
+	)
+	require.NoError(t, err)
 
 	value, err := inter.Invoke("test")
 	require.NoError(t, err)
@@ -7012,7 +7531,7 @@ func TestInterpretReferenceDereferenceFailure(t *testing.T) {
 
 	t.Parallel()
 
-	inter := parseCheckAndInterpret(t, `
+	inter, err := parseCheckAndInterpretWithOptions(t, `
       pub resource R {
           pub fun foo() {}
       }
@@ -7023,9 +7542,18 @@ func TestInterpretReferenceDereferenceFailure(t *testing.T) {
           destroy r
           ref.foo()
       }
-    `)
+    `,
+		ParseCheckAndInterpretOptions{
+			HandleCheckerError: func(err error) {
+				errs := checker.ExpectCheckerErrors(t, err, 1)
 
-	_, err := inter.Invoke("test")
+				require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = inter.Invoke("test")
 	require.Error(t, err)
 
 	require.ErrorAs(t, err, &interpreter.InvalidatedResourceError{})
@@ -7139,13 +7667,16 @@ func TestInterpretResourceMovingAndBorrowing(t *testing.T) {
                     destroy self.r2
                 }
 
-                fun moveToStack_Borrow_AndMoveBack(): &R2 {
+                fun moveToStack_Borrow_AndMoveBack(): String {
                     // The second assignment should not lead to the resource being cleared
                     let optR2 <- self.r2 <- nil
                     let r2 <- optR2!
                     let ref = &r2 as &R2
+                    // NOTE: moving the resource back invalidates the reference,
+                    // so it must be used before
+                    let value = ref.value
                     self.r2 <-! r2
-                    return ref
+                    return value
                 }
             }
 
@@ -7153,9 +7684,8 @@ func TestInterpretResourceMovingAndBorrowing(t *testing.T) {
                 let r2 <- create R2()
                 let r1 <- create R1()
                 r1.r2 <-! r2
-                let ref = r1.moveToStack_Borrow_AndMoveBack()
+                let refValue = r1.moveToStack_Borrow_AndMoveBack()
                 let value = r1.r2?.value
-                let refValue = ref.value
                 destroy r1
                 return [value, refValue]
             }
@@ -7211,13 +7741,16 @@ func TestInterpretResourceMovingAndBorrowing(t *testing.T) {
                     destroy self.r2
                 }
 
-                fun moveToStack_Borrow_AndMoveBack(): &R2 {
+                fun moveToStack_Borrow_AndMoveBack(): String {
                     // The second assignment should not lead to the resource being cleared
                     let optR2 <- self.r2 <- nil
                     let r2 <- optR2!
                     let ref = &r2 as &R2
+                    // NOTE: moving the resource back invalidates the reference,
+                    // so it must be used before
+                    let value = ref.value
                     self.r2 <-! r2
-                    return ref
+                    return value
                 }
             }
 
@@ -7228,9 +7761,8 @@ func TestInterpretResourceMovingAndBorrowing(t *testing.T) {
             fun test(r1: &R1): [String?] {
                 let r2 <- create R2()
                 r1.r2 <-! r2
-                let ref = r1.moveToStack_Borrow_AndMoveBack()
+                let refValue = r1.moveToStack_Borrow_AndMoveBack()
                 let value = r1.r2?.value
-                let refValue = ref.value
                 return [value, refValue]
             }
         `)
@@ -7981,8 +8513,7 @@ func TestInterpretNonStorageReferenceAfterDestruction(t *testing.T) {
 
 	t.Parallel()
 
-	inter := parseCheckAndInterpret(t,
-		`
+	inter, err := parseCheckAndInterpretWithOptions(t, `
           resource NFT {
               var id: Int
 
@@ -7998,9 +8529,17 @@ func TestInterpretNonStorageReferenceAfterDestruction(t *testing.T) {
               return nftRef.id
           }
         `,
+		ParseCheckAndInterpretOptions{
+			HandleCheckerError: func(err error) {
+				errs := checker.ExpectCheckerErrors(t, err, 1)
+
+				require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+			},
+		},
 	)
+	require.NoError(t, err)
 
-	_, err := inter.Invoke("test")
+	_, err = inter.Invoke("test")
 	require.Error(t, err)
 
 	require.ErrorAs(t, err, &interpreter.InvalidatedResourceError{})
@@ -8277,7 +8816,7 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {
               var name: String
               init(name: String) {
@@ -8292,18 +8831,27 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               ref.name = "2"
               destroy container
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
 
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
+
 	})
 
 	t.Run("resource, field read", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {
               var name: String
               init(name: String) {
@@ -8319,17 +8867,26 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               destroy container
               return name
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource array, insert", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8339,17 +8896,26 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               ref.insert(at: 1, <-create R())
               destroy container
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource array, append", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8359,17 +8925,26 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               ref.append(<-create R())
               destroy container
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource array, get/set", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8381,17 +8956,29 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               destroy container
               destroy r
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 2)
 
-		_, err := inter.Invoke("test")
+					// The swap statement checks the left-hand side twice,
+					// as a value and as an assignment target
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[1])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource array, remove", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8402,17 +8989,26 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               destroy container
               destroy r
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource dictionary, insert", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8422,17 +9018,26 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               ref[1] <-! create R()
               destroy container
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("resource dictionary, remove", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R {}
 
           fun test() {
@@ -8443,10 +9048,19 @@ func TestInterpretReferenceUseAfterCopy(t *testing.T) {
               destroy container
               destroy r
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		_, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("struct, field write and read", func(t *testing.T) {
@@ -0,0 +1,264 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretMemberFunctionDispatch(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      struct interface HasValue {
+          fun getValue(): Int
+      }
+
+      struct S: HasValue {
+          var value: Int
+
+          init() {
+              self.value = 1
+          }
+
+          fun increment() {
+              self.value = self.value + 1
+          }
+
+          fun getValue(): Int {
+              return self.value
+          }
+      }
+
+      fun test(): [Int] {
+          let s = S()
+          s.increment()
+          let ref = &s as &S
+          ref.increment()
+          let hasValue: {HasValue} = s
+          s.increment()
+          return [s.getValue(), ref.getValue(), hasValue.getValue(), s.value]
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]interpreter.Value{
+			interpreter.NewIntValueFromInt64(4),
+			interpreter.NewIntValueFromInt64(4),
+			interpreter.NewIntValueFromInt64(3),
+			interpreter.NewIntValueFromInt64(4),
+		},
+		arrayElements(inter, value.(*interpreter.ArrayValue)),
+	)
+}
+
+func TestInterpretMemberFunctionDispatchTypeRequirement(t *testing.T) {
+
+	t.Parallel()
+
+	// The functions of the type requirement and the implementation
+	// are declared in a different order, so they have different member slots.
+	// The condition of the type requirement calls a function on the implementation
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          pub contract interface Test {
+
+              pub struct Nested {
+                  pub fun a(): Int
+
+                  pub fun b(): Int {
+                      pre {
+                          self.a() == 1: "a() == 1"
+                      }
+                  }
+              }
+          }
+
+          pub contract TestImpl: Test {
+
+              pub struct Nested {
+                  pub let x: Int
+
+                  init() {
+                      self.x = 0
+                  }
+
+                  pub fun b(): Int {
+                      return 2
+                  }
+
+                  pub fun a(): Int {
+                      return 1
+                  }
+              }
+          }
+
+          pub fun test(): Int {
+              return TestImpl.Nested().b()
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				makeContractValueHandler(nil, nil, nil),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t, interpreter.NewIntValueFromInt64(2), value)
+}
+
+func TestInterpretMemberFieldSlots(t *testing.T) {
+
+	t.Parallel()
+
+	// Constant fields are accessed through their member slot,
+	// and simple values are cached in the field slots of the value.
+	// The results are the same as when accessing the fields by name
+
+	inter := parseCheckAndInterpret(t, `
+      enum E: UInt8 {
+          case a
+          case b
+      }
+
+      struct S {
+          let number: Int
+          let string: String
+          let optional: Int?
+          let e: E
+          let numbers: [Int]
+          var counter: Int
+
+          init() {
+              self.number = 1
+              self.string = "2"
+              self.optional = 3
+              self.e = E.b
+              self.numbers = []
+              self.counter = 0
+          }
+
+          fun update() {
+              self.numbers.append(self.number)
+              self.counter = self.counter + self.number
+          }
+      }
+
+      fun test(): [AnyStruct] {
+          let s = S()
+          let results: [AnyStruct] = []
+          var i = 0
+          while i < 2 {
+              s.update()
+              results.append(s.number)
+              results.append(s.string)
+              results.append(s.optional)
+              results.append(s.e.rawValue)
+              results.append(s.numbers.length)
+              results.append(s.counter)
+              i = i + 1
+          }
+          return results
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	one := interpreter.NewIntValueFromInt64(1)
+	two := interpreter.NewIntValueFromInt64(2)
+
+	utils.AssertValueSlicesEqual(
+		t,
+		inter,
+		[]interpreter.Value{
+			one,
+			interpreter.NewStringValue("2"),
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(3)),
+			interpreter.UInt8Value(1),
+			one,
+			one,
+			one,
+			interpreter.NewStringValue("2"),
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(3)),
+			interpreter.UInt8Value(1),
+			two,
+			two,
+		},
+		arrayElements(inter, value.(*interpreter.ArrayValue)),
+	)
+}
+
+func BenchmarkInterpretMemberFunctionInvocation(b *testing.B) {
+
+	inter, err := parseCheckAndInterpretWithOptions(b,
+		`
+      struct Counter {
+          var count: Int
+
+          init() {
+              self.count = 0
+          }
+
+          fun increment() {
+              self.count = self.count + 1
+          }
+      }
+
+      fun test() {
+          let counter = Counter()
+          var i = 0
+          while i < 100 {
+              counter.increment()
+              i = i + 1
+          }
+      }
+    `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				interpreter.WithAtreeValueValidationEnabled(false),
+				interpreter.WithAtreeStorageValidationEnabled(false),
+			},
+		},
+	)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := inter.Invoke("test")
+		require.NoError(b, err)
+	}
+}
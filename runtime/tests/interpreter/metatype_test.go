@@ -630,7 +630,7 @@ func TestInterpretGetType(t *testing.T) {
             `,
 			result: interpreter.TypeValue{
 				Type: interpreter.OptionalStaticType{
-					Type: interpreter.ReferenceStaticType{
+					Type: &interpreter.ReferenceStaticType{
 						Authorized: true,
 						Type:       interpreter.PrimitiveStaticTypeInt,
 					},
@@ -651,7 +651,7 @@ func TestInterpretGetType(t *testing.T) {
             `,
 			result: interpreter.TypeValue{
 				Type: interpreter.OptionalStaticType{
-					Type: interpreter.ReferenceStaticType{
+					Type: &interpreter.ReferenceStaticType{
 						Authorized: true,
 						Type:       interpreter.PrimitiveStaticTypeInt,
 					},
@@ -725,7 +725,7 @@ func TestInterpretGetType(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			storageMap := storage.GetStorageMap(storageAddress, storagePath.Domain.Identifier())
+			storageMap := storage.GetStorageMap(storageAddress, storagePath.Domain.Identifier(), true)
 			storageMap.WriteValue(
 				inter,
 				storagePath.Identifier,
@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -232,7 +233,7 @@ func TestInterpretLoopIterationHandler(t *testing.T) {
 		importingChecker.Location,
 		interpreter.WithStorage(storage),
 		interpreter.WithOnLoopIterationHandler(
-			func(inter *interpreter.Interpreter, line int) {
+			func(inter *interpreter.Interpreter, iteration interpreter.LoopIteration) {
 
 				id, ok := interpreterIDs[inter]
 				if !ok {
@@ -243,7 +244,7 @@ func TestInterpretLoopIterationHandler(t *testing.T) {
 
 				occurrences = append(occurrences, occurrence{
 					interpreterID: id,
-					line:          line,
+					line:          iteration.Line,
 				})
 			},
 		),
@@ -371,7 +372,7 @@ func TestInterpretFunctionInvocationHandler(t *testing.T) {
 		importingChecker.Location,
 		interpreter.WithStorage(storage),
 		interpreter.WithOnFunctionInvocationHandler(
-			func(inter *interpreter.Interpreter, line int) {
+			func(inter *interpreter.Interpreter, invocation interpreter.FunctionInvocation) {
 
 				id, ok := interpreterIDs[inter]
 				if !ok {
@@ -382,7 +383,7 @@ func TestInterpretFunctionInvocationHandler(t *testing.T) {
 
 				occurrences = append(occurrences, occurrence{
 					interpreterID: id,
-					line:          line,
+					line:          invocation.Line,
 				})
 			},
 		),
@@ -422,3 +423,252 @@ func TestInterpretFunctionInvocationHandler(t *testing.T) {
 		occurrences,
 	)
 }
+
+func TestInterpretFunctionInvocationHandlerContext(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun add(_ a: Int, _ b: Int): Int {
+          return a + b
+      }
+
+      fun test(): Int {
+          var sum = 0
+          for i in [1, 2] {
+              sum = add(sum, i)
+          }
+          return sum
+      }
+    `)
+	require.NoError(t, err)
+
+	type invocation struct {
+		arguments []interpreter.Value
+		line      int
+	}
+
+	var invocations []invocation
+	var results []interpreter.Value
+	var loopStatements []ast.Statement
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+		interpreter.WithOnLoopIterationHandler(
+			func(_ *interpreter.Interpreter, iteration interpreter.LoopIteration) {
+				loopStatements = append(loopStatements, iteration.Statement)
+			},
+		),
+		interpreter.WithOnFunctionInvocationHandler(
+			func(_ *interpreter.Interpreter, functionInvocation interpreter.FunctionInvocation) {
+				require.NotNil(t, functionInvocation.Function)
+				assert.Equal(t,
+					functionInvocation.Line,
+					functionInvocation.LocationRange.StartPos.Line,
+				)
+
+				invocations = append(invocations, invocation{
+					arguments: functionInvocation.Arguments,
+					line:      functionInvocation.Line,
+				})
+			},
+		),
+		interpreter.WithOnInvokedFunctionReturnHandler(
+			func(_ *interpreter.Interpreter, _ interpreter.FunctionInvocation, result interpreter.Value) {
+				results = append(results, result)
+			},
+		),
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	_, err = inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]invocation{
+			{
+				arguments: []interpreter.Value{
+					interpreter.NewIntValueFromInt64(0),
+					interpreter.NewIntValueFromInt64(1),
+				},
+				line: 9,
+			},
+			{
+				arguments: []interpreter.Value{
+					interpreter.NewIntValueFromInt64(1),
+					interpreter.NewIntValueFromInt64(2),
+				},
+				line: 9,
+			},
+		},
+		invocations,
+	)
+
+	assert.Equal(t,
+		[]interpreter.Value{
+			interpreter.NewIntValueFromInt64(1),
+			interpreter.NewIntValueFromInt64(3),
+		},
+		results,
+	)
+
+	require.Len(t, loopStatements, 2)
+	for _, statement := range loopStatements {
+		assert.IsType(t, &ast.ForStatement{}, statement)
+	}
+}
+
+func TestInterpretYieldHandler(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      fun add(_ a: Int, _ b: Int): Int {
+          return a + b
+      }
+
+      fun test(): Int {
+          var sum = 0
+          var i = 0
+          while i < 2 {
+              i = i + 1
+              sum = add(sum, i)
+          }
+          return sum
+      }
+    `
+
+	t.Run("yield points", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := checker.ParseAndCheck(t, code)
+		require.NoError(t, err)
+
+		var yieldPoints []interpreter.YieldPoint
+
+		inter, err := interpreter.NewInterpreter(
+			interpreter.ProgramFromChecker(checker),
+			checker.Location,
+			interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+			interpreter.WithOnYieldHandler(
+				func(_ *interpreter.Interpreter, point interpreter.YieldPoint) error {
+					yieldPoints = append(yieldPoints, point)
+					return nil
+				},
+			),
+		)
+		require.NoError(t, err)
+
+		err = inter.Interpret()
+		require.NoError(t, err)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t, interpreter.NewIntValueFromInt64(3), result)
+
+		assert.Equal(t,
+			[]interpreter.YieldPoint{
+				interpreter.YieldPointLoopIteration,
+				interpreter.YieldPointFunctionInvocation,
+				interpreter.YieldPointLoopIteration,
+				interpreter.YieldPointFunctionInvocation,
+			},
+			yieldPoints,
+		)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := checker.ParseAndCheck(t, code)
+		require.NoError(t, err)
+
+		abortErr := errors.New("preempted")
+
+		var yieldCount int
+
+		inter, err := interpreter.NewInterpreter(
+			interpreter.ProgramFromChecker(checker),
+			checker.Location,
+			interpreter.WithStorage(interpreter.NewInMemoryStorage()),
+			interpreter.WithOnYieldHandler(
+				func(_ *interpreter.Interpreter, _ interpreter.YieldPoint) error {
+					yieldCount++
+					if yieldCount == 2 {
+						return abortErr
+					}
+					return nil
+				},
+			),
+		)
+		require.NoError(t, err)
+
+		err = inter.Interpret()
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.ErrorIs(t, err, abortErr)
+
+		assert.Equal(t, 2, yieldCount)
+	})
+}
+
+func TestInterpretBigIntOperationHandler(t *testing.T) {
+
+	t.Parallel()
+
+	var operations []ast.Operation
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          fun test(x: Int, y: UInt8, z: UInt256, w: Int64): Bool {
+              let a = x + x
+              let b = y + y
+              let c = z * z
+              let d = -w
+              let e = -a
+              return a < x && b < y
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				interpreter.WithOnBigIntOperationHandler(
+					func(_ *interpreter.Interpreter, operation ast.Operation) {
+						operations = append(operations, operation)
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = inter.Invoke(
+		"test",
+		interpreter.NewIntValueFromInt64(1),
+		interpreter.UInt8Value(2),
+		interpreter.NewUInt256ValueFromUint64(3),
+		interpreter.Int64Value(4),
+	)
+	require.NoError(t, err)
+
+	// Constant expressions are not evaluated at run-time,
+	// and operations on fixed-size integers are not operations on big integers
+
+	assert.Equal(t,
+		[]ast.Operation{
+			ast.OperationPlus,
+			ast.OperationMul,
+			ast.OperationMinus,
+			ast.OperationLess,
+		},
+		operations,
+	)
+}
@@ -442,7 +442,7 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
             resource R {
                 let value: String
 
@@ -457,7 +457,16 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
                 target.append(<-r)
                 return ref.value
             }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
+
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
+		require.NoError(t, err)
 
 		address := common.Address{0x1}
 
@@ -477,22 +486,15 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
 			BorrowedType: rType,
 		}
 
-		value, err := inter.Invoke("test", arrayRef)
-		require.NoError(t, err)
-
-		AssertValuesEqual(
-			t,
-			inter,
-			interpreter.NewStringValue("testValue"),
-			value,
-		)
+		_, err = inter.Invoke("test", arrayRef)
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("array", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
             resource R {
                 let value: String
 
@@ -507,7 +509,16 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
                 target.append(<-rs)
                 return ref[0].value
             }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
+
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
+		require.NoError(t, err)
 
 		address := common.Address{0x1}
 
@@ -529,22 +540,15 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
 			BorrowedType: rType,
 		}
 
-		value, err := inter.Invoke("test", arrayRef)
-		require.NoError(t, err)
-
-		AssertValuesEqual(
-			t,
-			inter,
-			interpreter.NewStringValue("testValue"),
-			value,
-		)
+		_, err = inter.Invoke("test", arrayRef)
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 
 	t.Run("dictionary", func(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
             resource R {
                 let value: String
 
@@ -559,7 +563,16 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
                 target.append(<-rs)
                 return ref[1]?.value
             }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
+
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
+		require.NoError(t, err)
 
 		address := common.Address{0x1}
 
@@ -582,17 +595,8 @@ func TestInterpretResourceReferenceAfterMove(t *testing.T) {
 			BorrowedType: rType,
 		}
 
-		value, err := inter.Invoke("test", arrayRef)
-		require.NoError(t, err)
-
-		AssertValuesEqual(
-			t,
-			inter,
-			interpreter.NewSomeValueNonCopying(
-				interpreter.NewStringValue("testValue"),
-			),
-			value,
-		)
+		_, err = inter.Invoke("test", arrayRef)
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 	})
 }
 
@@ -604,7 +608,7 @@ func TestInterpretReferenceUseAfterShiftStatementMove(t *testing.T) {
 
 		t.Parallel()
 
-		inter := parseCheckAndInterpret(t, `
+		inter, err := parseCheckAndInterpretWithOptions(t, `
           resource R2 {
               let value: String
 
@@ -642,17 +646,19 @@ func TestInterpretReferenceUseAfterShiftStatementMove(t *testing.T) {
               destroy r1
               return value
           }
-        `)
+        `,
+			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
 
-		value, err := inter.Invoke("test")
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
+			},
+		)
 		require.NoError(t, err)
 
-		AssertValuesEqual(
-			t,
-			inter,
-			interpreter.NewStringValue("test"),
-			value,
-		)
+		_, err = inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 
 	})
 
@@ -711,6 +717,11 @@ func TestInterpretReferenceUseAfterShiftStatementMove(t *testing.T) {
               }
             `,
 			ParseCheckAndInterpretOptions{
+				HandleCheckerError: func(err error) {
+					errs := checker.ExpectCheckerErrors(t, err, 1)
+
+					require.IsType(t, &sema.InvalidatedResourceReferenceError{}, errs[0])
+				},
 				Options: []interpreter.Option{
 					interpreter.WithPublicAccountHandler(
 						func(_ *interpreter.Interpreter, address interpreter.AddressValue) interpreter.Value {
@@ -736,15 +747,8 @@ func TestInterpretReferenceUseAfterShiftStatementMove(t *testing.T) {
 
 		// Test
 
-		value, err := inter.Invoke("test", ref)
-		require.NoError(t, err)
-
-		AssertValuesEqual(
-			t,
-			inter,
-			interpreter.NewStringValue("test"),
-			value,
-		)
+		_, err = inter.Invoke("test", ref)
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
 
 		// Check R1 owner
 
@@ -775,3 +779,159 @@ func TestInterpretReferenceUseAfterShiftStatementMove(t *testing.T) {
 		)
 	})
 }
+
+func TestInterpretInvalidatedResourceReference(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("array element removed", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let rs <- [<-create R()]
+              let ref = &rs[0] as &R
+              let r <- rs.remove(at: 0)
+              let id = ref.id
+              destroy r
+              destroy rs
+              return id
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
+	})
+
+	t.Run("dictionary value swapped", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {
+              let id: Int
+
+              init(id: Int) {
+                  self.id = id
+              }
+          }
+
+          fun test(): Int {
+              let rs <- {"a": <-create R(id: 1)}
+              let ref = &rs["a"] as &R
+              var r: @R? <- create R(id: 2)
+              rs["a"] <-> r
+              let id = ref!.id
+              destroy r
+              destroy rs
+              return id
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.InvalidatedResourceReferenceError{})
+	})
+
+	t.Run("use before move", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {
+              let id: Int
+
+              init() {
+                  self.id = 1
+              }
+          }
+
+          fun test(): Int {
+              let rs <- [<-create R()]
+              let ref = &rs[0] as &R
+              let id = ref.id
+              let r <- rs.remove(at: 0)
+              destroy r
+              destroy rs
+              return id
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewIntValueFromInt64(1),
+			value,
+		)
+	})
+}
+
+func TestInterpretReferenceExpressionOfOptionalField(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      resource R {
+          let id: Int
+
+          init() {
+              self.id = 1
+          }
+      }
+
+      resource C {
+          var r: @R?
+
+          init() {
+              self.r <- nil
+          }
+
+          destroy() {
+              destroy self.r
+          }
+
+          fun borrowR(): &R {
+              return &self.r as &R
+          }
+      }
+
+      fun test(): Int {
+          let c <- create C()
+          c.r <-! create R()
+          let id = c.borrowR().id
+          destroy c
+          return id
+      }
+
+      fun testNil(): Int {
+          let c <- create C()
+          let id = c.borrowR().id
+          destroy c
+          return id
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewIntValueFromInt64(1),
+		value,
+	)
+
+	_, err = inter.Invoke("testNil")
+	require.ErrorAs(t, err, &interpreter.DereferenceError{})
+}
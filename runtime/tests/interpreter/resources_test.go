@@ -569,3 +569,158 @@ func TestInterpretImplicitResourceRemovalFromContainer(t *testing.T) {
 		)
 	})
 }
+
+func TestInterpretResourceDictionarySecondValue(t *testing.T) {
+
+	t.Parallel()
+
+	const resourceDeclaration = `
+      resource R {
+          let id: Int
+
+          init(id: Int) {
+              self.id = id
+          }
+      }
+    `
+
+	test := func(t *testing.T, code string) (*interpreter.Interpreter, interpreter.Value) {
+		inter := parseCheckAndInterpret(t, resourceDeclaration+code)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		return inter, value
+	}
+
+	t.Run("replace existing", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := test(t, `
+          fun test(): [Int?] {
+              let rs <- {1: <-create R(id: 1)}
+              let old <- rs[1] <- create R(id: 2)
+              let ids = [old?.id, rs[1]?.id]
+              destroy old
+              destroy rs
+              return ids
+          }
+        `)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(1)),
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("insert missing", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := test(t, `
+          fun test(): [Int?] {
+              let rs: @{Int: R} <- {}
+              let old <- rs[1] <- create R(id: 2)
+              let ids = [old?.id, rs[1]?.id]
+              destroy old
+              destroy rs
+              return ids
+          }
+        `)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NilValue{},
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("remove with nil", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := test(t, `
+          fun test(): [AnyStruct] {
+              let rs <- {1: <-create R(id: 1)}
+              let old <- rs[1] <- nil
+              let values: [AnyStruct] = [old?.id, rs.length]
+              destroy old
+              destroy rs
+              return values
+          }
+        `)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(1)),
+				interpreter.NewIntValueFromInt64(0),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("through reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := test(t, `
+          fun test(): Int? {
+              let rs <- {1: <-create R(id: 1)}
+              let ref = &rs as &{Int: R}
+              let old <- ref[1] <- create R(id: 2)
+              destroy old
+              let id = rs[1]?.id
+              destroy rs
+              return id
+          }
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+			value,
+		)
+	})
+
+	t.Run("same as insert", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, value := test(t, `
+          fun test(): [Int?] {
+              let rs <- {1: <-create R(id: 1)}
+              let old1 <- rs[1] <- create R(id: 2)
+              let old2 <- rs.insert(key: 1, <-create R(id: 3))
+              let ids = [old1?.id, old2?.id, rs[1]?.id]
+              destroy old1
+              destroy old2
+              destroy rs
+              return ids
+          }
+        `)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(1)),
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+				interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(3)),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+}
@@ -508,7 +508,7 @@ func TestInterpretReferenceType(t *testing.T) {
 
 	assert.Equal(t,
 		interpreter.TypeValue{
-			Type: interpreter.ReferenceStaticType{
+			Type: &interpreter.ReferenceStaticType{
 				Type: interpreter.CompositeStaticType{
 					QualifiedIdentifier: "R",
 					Location:            utils.TestLocation,
@@ -522,7 +522,7 @@ func TestInterpretReferenceType(t *testing.T) {
 
 	assert.Equal(t,
 		interpreter.TypeValue{
-			Type: interpreter.ReferenceStaticType{
+			Type: &interpreter.ReferenceStaticType{
 				Type:       interpreter.PrimitiveStaticTypeString,
 				Authorized: false,
 			},
@@ -532,7 +532,7 @@ func TestInterpretReferenceType(t *testing.T) {
 
 	assert.Equal(t,
 		interpreter.TypeValue{
-			Type: interpreter.ReferenceStaticType{
+			Type: &interpreter.ReferenceStaticType{
 				Type: interpreter.CompositeStaticType{
 					QualifiedIdentifier: "S",
 					Location:            utils.TestLocation,
@@ -703,7 +703,7 @@ func TestInterpretCapabilityType(t *testing.T) {
 	assert.Equal(t,
 		interpreter.TypeValue{
 			Type: interpreter.CapabilityStaticType{
-				BorrowType: interpreter.ReferenceStaticType{
+				BorrowType: &interpreter.ReferenceStaticType{
 					Type:       interpreter.PrimitiveStaticTypeString,
 					Authorized: false,
 				},
@@ -715,7 +715,7 @@ func TestInterpretCapabilityType(t *testing.T) {
 	assert.Equal(t,
 		interpreter.TypeValue{
 			Type: interpreter.CapabilityStaticType{
-				BorrowType: interpreter.ReferenceStaticType{
+				BorrowType: &interpreter.ReferenceStaticType{
 					Type:       interpreter.PrimitiveStaticTypeInt,
 					Authorized: false,
 				},
@@ -727,7 +727,7 @@ func TestInterpretCapabilityType(t *testing.T) {
 	assert.Equal(t,
 		interpreter.TypeValue{
 			Type: interpreter.CapabilityStaticType{
-				BorrowType: interpreter.ReferenceStaticType{
+				BorrowType: &interpreter.ReferenceStaticType{
 					Type: interpreter.CompositeStaticType{
 						QualifiedIdentifier: "R",
 						Location:            utils.TestLocation,
@@ -58,6 +58,20 @@ func TestInterpretRecursiveValueString(t *testing.T) {
 	)
 }
 
+func TestInterpretRawString(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, "let x = `<svg>\n  <text>\\n</text>\n</svg>`")
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewStringValue("<svg>\n  <text>\\n</text>\n</svg>"),
+		inter.Globals["x"].GetValue(),
+	)
+}
+
 func TestInterpretStringFunction(t *testing.T) {
 
 	t.Parallel()
@@ -0,0 +1,115 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestInterpretTimestamp(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+        let start = Timestamp(unixSeconds: 1614834367.5)
+        let week = Duration(seconds: 604800.0)
+        let end = start.add(week)
+        let earlier = start.subtract(week)
+        let elapsed = start.durationSince(end)
+        let isBefore = start.isBefore(end)
+        let isAfter = start.isAfter(end)
+        let unixSeconds = start.unixSeconds
+        let formatted = start.toString()
+        let equal = end.subtract(week) == start
+    `)
+
+	for name, expected := range map[string]interpreter.Value{
+		"start":       interpreter.NewTimestampValue(1614834367_500000000),
+		"week":        interpreter.NewDurationValue(604800_000000000),
+		"end":         interpreter.NewTimestampValue(1615439167_500000000),
+		"earlier":     interpreter.NewTimestampValue(1614229567_500000000),
+		"elapsed":     interpreter.NewDurationValue(-604800_000000000),
+		"isBefore":    interpreter.BoolValue(true),
+		"isAfter":     interpreter.BoolValue(false),
+		"unixSeconds": interpreter.Int64Value(1614834367),
+		"formatted":   interpreter.NewStringValue("2021-03-04T05:06:07.5Z"),
+		"equal":       interpreter.BoolValue(true),
+	} {
+		assert.Equal(t,
+			expected,
+			inter.Globals[name].GetValue(),
+			name,
+		)
+	}
+}
+
+func TestInterpretDuration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("arithmetic", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+            let second = Duration(seconds: 1.0)
+            let negative = Duration(seconds: -1.5)
+            let sum = second.add(negative)
+            let product = second.multiply(3661)
+            let isShorter = negative.isShorterThan(second)
+            let seconds = negative.seconds
+            let formatted = product.toString()
+        `)
+
+		for name, expected := range map[string]interpreter.Value{
+			"sum":       interpreter.NewDurationValue(-500_000000),
+			"product":   interpreter.NewDurationValue(3661_000000000),
+			"isShorter": interpreter.BoolValue(true),
+			"seconds":   interpreter.Int64Value(-1),
+			"formatted": interpreter.NewStringValue("1h1m1s"),
+		} {
+			assert.Equal(t,
+				expected,
+				inter.Globals[name].GetValue(),
+				name,
+			)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+            fun test(): Duration {
+                let max = Duration(seconds: 5000000000.0)
+                return max.multiply(2)
+            }
+        `)
+
+		_, err := inter.Invoke("test")
+
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+}
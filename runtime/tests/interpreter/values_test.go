@@ -993,6 +993,132 @@ func TestRandomCompositeValueOperations(t *testing.T) {
 	})
 }
 
+func TestRandomValueEncodingRoundTrip(t *testing.T) {
+
+	// By default, a fixed seed and a small number of values are used,
+	// so the test is deterministic and fast enough to be run regularly.
+	// The smoke tests use a random seed and more values
+
+	seed := int64(1)
+	valueCount := 20
+	if *runSmokeTests {
+		seed = time.Now().UnixNano()
+		valueCount = 1000
+	}
+
+	fmt.Printf("Seed used for value encoding round-trip test: %d \n", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < valueCount; i++ {
+
+		storage := interpreter.NewInMemoryStorage()
+		inter, err := interpreter.NewInterpreter(
+			&interpreter.Program{
+				Program:     ast.NewProgram([]ast.Declaration{}),
+				Elaboration: sema.NewElaboration(),
+			},
+			utils.TestLocation,
+			interpreter.WithStorage(storage),
+			interpreter.WithImportLocationHandler(
+				func(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+					return interpreter.VirtualImport{
+						Elaboration: inter.Program.Elaboration,
+					}
+				},
+			),
+		)
+		require.NoError(t, err)
+
+		var value interpreter.Value
+
+		// Links can only be stored directly, not in containers
+
+		if randomInt(10) == 0 {
+			value = interpreter.LinkValue{
+				TargetPath: randomPathValue(),
+				Type:       randomStaticType(),
+			}
+		} else {
+			value = randomStorableValue(inter, 0)
+		}
+
+		// Move the value to a random owner
+
+		owner := atree.Address(randomAddressValue())
+		value = value.Transfer(
+			inter,
+			interpreter.ReturnEmptyLocationRange,
+			owner,
+			true,
+			nil,
+		)
+
+		testValueEncodingRoundTrip(t, inter, storage, value, owner)
+	}
+}
+
+// testValueEncodingRoundTrip encodes the given value and all slabs of the storage,
+// decodes them into a new storage, and asserts that the decoded value
+// is equal to the original value, and that re-encoding it results in the same encoding
+//
+func testValueEncodingRoundTrip(
+	t *testing.T,
+	inter *interpreter.Interpreter,
+	storage interpreter.InMemoryStorage,
+	value interpreter.Value,
+	owner atree.Address,
+) {
+	storable, err := value.Storable(storage, owner, math.MaxUint64)
+	require.NoError(t, err)
+
+	encoded, err := atree.Encode(storable, interpreter.CBOREncMode)
+	require.NoError(t, err)
+
+	encodedSlabs, err := storage.Encode()
+	require.NoError(t, err)
+
+	// Decode the slabs and the value into a new storage
+
+	decodedStorage := interpreter.NewInMemoryStorage()
+
+	for id, data := range encodedSlabs {
+		slab, err := atree.DecodeSlab(
+			id,
+			data,
+			interpreter.CBORDecMode,
+			interpreter.DecodeStorable,
+			interpreter.DecodeTypeInfo,
+		)
+		require.NoError(t, err)
+
+		err = decodedStorage.Store(id, slab)
+		require.NoError(t, err)
+	}
+
+	decoder := interpreter.CBORDecMode.NewByteStreamDecoder(encoded)
+	decodedStorable, err := interpreter.DecodeStorable(decoder, atree.StorageIDUndefined)
+	require.NoError(t, err)
+
+	decodedValue := interpreter.StoredValue(decodedStorable, decodedStorage)
+
+	utils.AssertValuesEqual(t, inter, value, decodedValue)
+
+	// Re-encode the decoded value and slabs
+
+	reencodedStorable, err := decodedValue.Storable(decodedStorage, owner, math.MaxUint64)
+	require.NoError(t, err)
+
+	reencoded, err := atree.Encode(reencodedStorable, interpreter.CBOREncMode)
+	require.NoError(t, err)
+
+	assert.Equal(t, encoded, reencoded)
+
+	reencodedSlabs, err := decodedStorage.Encode()
+	require.NoError(t, err)
+
+	assert.Equal(t, encodedSlabs, reencodedSlabs)
+}
+
 func newCompositeValue(
 	orgOwner common.Address,
 	fieldsCount int,
@@ -1040,7 +1166,7 @@ func newCompositeValue(
 		Kind:       kind,
 	}
 
-	compositeType.Members = sema.NewStringMemberOrderedMap()
+	compositeType.Members = &sema.StringMemberOrderedMap{}
 	for _, field := range fields {
 		compositeType.Members.Set(
 			field.Name,
@@ -1215,6 +1341,10 @@ func deepCopyValue(inter *interpreter.Interpreter, value interpreter.Value) inte
 		}
 	case *interpreter.SomeValue:
 		return interpreter.NewSomeValueNonCopying(deepCopyValue(inter, v.Value))
+	case interpreter.TypeValue:
+		return interpreter.TypeValue{
+			Type: v.Type,
+		}
 	case interpreter.NilValue:
 		return interpreter.NilValue{}
 	default:
@@ -1237,6 +1367,10 @@ func randomStorableValue(inter *interpreter.Interpreter, currentDepth int) inter
 		return interpreter.VoidValue{}
 	case Nil:
 		return interpreter.NilValue{}
+	case Type:
+		return interpreter.TypeValue{
+			Type: randomStaticType(),
+		}
 	case Dictionary_1, Dictionary_2:
 		return randomDictionaryValue(inter, currentDepth)
 	case Array_1, Array_2:
@@ -1247,7 +1381,7 @@ func randomStorableValue(inter *interpreter.Interpreter, currentDepth int) inter
 		return &interpreter.CapabilityValue{
 			Address: randomAddressValue(),
 			Path:    randomPathValue(),
-			BorrowType: interpreter.ReferenceStaticType{
+			BorrowType: &interpreter.ReferenceStaticType{
 				Authorized: false,
 				Type:       interpreter.PrimitiveStaticTypeAnyStruct,
 			},
@@ -1415,6 +1549,28 @@ func randomPathValue() interpreter.PathValue {
 	}
 }
 
+func randomStaticType() interpreter.StaticType {
+	staticTypes := []interpreter.StaticType{
+		interpreter.PrimitiveStaticTypeAnyStruct,
+		interpreter.PrimitiveStaticTypeInt,
+		interpreter.PrimitiveStaticTypeString,
+		interpreter.VariableSizedStaticType{
+			Type: interpreter.PrimitiveStaticTypeUInt8,
+		},
+		interpreter.OptionalStaticType{
+			Type: interpreter.PrimitiveStaticTypeAddress,
+		},
+		interpreter.CapabilityStaticType{
+			BorrowType: &interpreter.ReferenceStaticType{
+				Authorized: true,
+				Type:       interpreter.PrimitiveStaticTypeAnyResource,
+			},
+		},
+	}
+
+	return staticTypes[rand.Intn(len(staticTypes))]
+}
+
 func randomDictionaryValue(
 	inter *interpreter.Interpreter,
 	currentDepth int,
@@ -1498,7 +1654,7 @@ func randomCompositeValue(
 		Kind:       kind,
 	}
 
-	compositeType.Members = sema.NewStringMemberOrderedMap()
+	compositeType.Members = &sema.StringMemberOrderedMap{}
 	for _, field := range fields {
 		compositeType.Members.Set(
 			field.Name,
@@ -1618,6 +1774,7 @@ const (
 
 	Void
 	Nil // `Never?`
+	Type
 	Capability
 
 	// Containers
@@ -130,8 +130,8 @@ func ValuesAreEqual(inter *interpreter.Interpreter, expected, actual interpreter
 		return actual == nil
 	}
 
-	if expected, ok := expected.(interpreter.EquatableValue); ok {
-		return expected.Equal(inter, interpreter.ReturnEmptyLocationRange, actual)
+	if _, ok := expected.(interpreter.EquatableValue); ok {
+		return interpreter.DeepEqual(inter, expected, actual)
 	}
 
 	return assert.ObjectsAreEqual(expected, actual)
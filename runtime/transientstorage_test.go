@@ -0,0 +1,178 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeTransientStorage(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	accountCodes := map[common.LocationID][]byte{}
+
+	var loggedMessages []string
+	var writes int
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, func(_, _, _ []byte) {
+			writes++
+		}),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			return accountCodes[location.ID()], nil
+		},
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		emitEvent: func(_ cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code string) error {
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+	}
+
+	err := executeTransaction(
+		string(utils.DeploymentTransaction(
+			"Guard",
+			[]byte(`
+              pub contract Guard {
+
+                  pub fun isLocked(): Bool {
+                      return self.account.copyTransient<Bool>(from: /storage/lock) ?? false
+                  }
+
+                  pub fun lock() {
+                      if self.isLocked() {
+                          panic("reentrant call")
+                      }
+                      self.account.saveTransient(true, to: /storage/lock)
+                  }
+
+                  pub fun unlock() {
+                      self.account.loadTransient<Bool>(from: /storage/lock)
+                  }
+
+                  pub fun guarded(_ f: ((): Void)) {
+                      self.lock()
+                      f()
+                      self.unlock()
+                  }
+              }
+            `),
+		)),
+	)
+	require.NoError(t, err)
+
+	t.Run("reentrancy", func(t *testing.T) {
+
+		err := executeTransaction(`
+          import Guard from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  Guard.guarded(fun () {
+                      log(Guard.isLocked())
+                  })
+                  log(Guard.isLocked())
+
+                  Guard.guarded(fun () {
+                      Guard.guarded(fun () {})
+                  })
+              }
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "reentrant call")
+
+		require.Equal(t, []string{"true", "false"}, loggedMessages)
+	})
+
+	t.Run("discarded at end of transaction", func(t *testing.T) {
+
+		loggedMessages = nil
+		writes = 0
+
+		err := executeTransaction(`
+          import Guard from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  Guard.lock()
+                  log(Guard.isLocked())
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		// NOTE: check the transient storage was not written to account storage
+		require.Zero(t, writes)
+
+		err = executeTransaction(`
+          import Guard from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(Guard.isLocked())
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"true", "false"}, loggedMessages)
+	})
+}
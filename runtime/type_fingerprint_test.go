@@ -0,0 +1,439 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestRuntimeTypeFingerprintCapabilityCheck(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	setup := []byte(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let numbers: [Int] = []
+              var i = 0
+              while i < 1000 {
+                  numbers.append(i)
+                  i = i + 1
+              }
+              signer.save(numbers, to: /storage/numbers)
+              signer.link<&[Int]>(/public/numbers, target: /storage/numbers)
+              signer.link<&[String]>(/public/strings, target: /storage/numbers)
+          }
+      }
+    `)
+
+	// newRuntime returns a runtime and an interface
+	// with values stored using the given type fingerprints option
+	//
+	newRuntime := func(fingerprintsEnabled bool) (Runtime, *testRuntimeInterface) {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.SetTypeFingerprintsEnabled(fingerprintsEnabled)
+
+		// The storage health check decodes all slabs of the account
+
+		runtime.SetAtreeValidationEnabled(false)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: setup,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		return runtime, runtimeInterface
+	}
+
+	check := func(
+		runtime Runtime,
+		runtimeInterface *testRuntimeInterface,
+		report *ExecutionReport,
+		code string,
+	) cadence.Value {
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        common.ScriptLocation{},
+				ExecutionReport: report,
+			},
+		)
+		require.NoError(t, err)
+		return value
+	}
+
+	const checkNumbers = `
+      pub fun main(): Bool {
+          return getAccount(0x1).getCapability<&[Int]>(/public/numbers).check()
+      }
+    `
+
+	const checkStrings = `
+      pub fun main(): Bool {
+          return getAccount(0x1).getCapability<&[String]>(/public/strings).check()
+      }
+    `
+
+	t.Run("fingerprint", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := newRuntime(true)
+
+		storage := NewStorage(runtimeInterface.storage)
+		fingerprint := storage.
+			GetStorageMap(address, interpreter.TypeFingerprintStorageDomain, true).
+			ReadValue("storage/numbers")
+
+		assert.Equal(t,
+			interpreter.TypeValue{
+				Type: interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+			fingerprint,
+		)
+
+		assert.Equal(t,
+			cadence.NewBool(true),
+			check(runtime, runtimeInterface, nil, checkNumbers),
+		)
+
+		// The type fingerprint is not a subtype of the borrow type,
+		// so the value is decoded and its dynamic type is checked
+
+		assert.Equal(t,
+			cadence.NewBool(false),
+			check(runtime, runtimeInterface, nil, checkStrings),
+		)
+	})
+
+	t.Run("no decoding", func(t *testing.T) {
+
+		t.Parallel()
+
+		decodes := func(fingerprintsEnabled bool) uint64 {
+			runtime, runtimeInterface := newRuntime(fingerprintsEnabled)
+
+			report := NewExecutionReport()
+
+			assert.Equal(t,
+				cadence.NewBool(true),
+				check(runtime, runtimeInterface, report, checkNumbers),
+			)
+
+			return report.OperationCount(OperationKindDecode)
+		}
+
+		// With type fingerprints, the slabs of the array are not read
+
+		assert.Less(t, decodes(true), decodes(false))
+	})
+
+	t.Run("moved value", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := newRuntime(true)
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.load<[Int]>(from: /storage/numbers)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		// Moving the value out of storage removes its fingerprint
+
+		assert.Equal(t,
+			cadence.NewBool(false),
+			check(runtime, runtimeInterface, nil, checkNumbers),
+		)
+	})
+
+	t.Run("overwritten while disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := newRuntime(true)
+
+		runtime.SetTypeFingerprintsEnabled(false)
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.load<[Int]>(from: /storage/numbers)
+                          signer.save(["1"], to: /storage/numbers)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		// Overwriting the value removes its fingerprint, even if type fingerprints are disabled,
+		// so the stale fingerprint is not used when type fingerprints are enabled again
+
+		storage := NewStorage(runtimeInterface.storage)
+		fingerprint := storage.
+			GetStorageMap(address, interpreter.TypeFingerprintStorageDomain, false).
+			ReadValue("storage/numbers")
+
+		assert.Nil(t, fingerprint)
+
+		runtime.SetTypeFingerprintsEnabled(true)
+
+		assert.Equal(t,
+			cadence.NewBool(false),
+			check(runtime, runtimeInterface, nil, checkNumbers),
+		)
+
+		assert.Equal(t,
+			cadence.NewBool(true),
+			check(runtime, runtimeInterface, nil, checkStrings),
+		)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, runtimeInterface := newRuntime(false)
+
+		// No fingerprints are stored, and the storage domain is not created
+
+		storage := NewStorage(runtimeInterface.storage)
+		assert.Nil(t,
+			storage.GetStorageMap(address, interpreter.TypeFingerprintStorageDomain, false),
+		)
+	})
+}
+
+func TestRuntimeTypeFingerprintTypeQueries(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	setup := []byte(`
+      transaction {
+          prepare(signer: AuthAccount) {
+              let numbers: [Int] = []
+              var i = 0
+              while i < 1000 {
+                  numbers.append(i)
+                  i = i + 1
+              }
+              signer.save(numbers, to: /storage/numbers)
+              signer.save(["a", "b"], to: /storage/strings)
+              signer.save(1, to: /storage/one)
+              signer.link<&[Int]>(/public/numbers, target: /storage/numbers)
+          }
+      }
+    `)
+
+	const script = `
+      pub fun main(): [String] {
+          let account = getAuthAccount(0x1)
+          let paths = account.storagePaths(ofType: Type<[Int]>())
+          assert(paths.length == 1 && paths[0].toString() == "/storage/numbers")
+          assert(account.storagePaths(ofType: Type<[AnyStruct]>()).length == 2)
+          assert(account.storagePaths(ofType: Type<@AnyResource>()).length == 0)
+          return [
+              account.type(at: /storage/numbers)!.identifier,
+              account.type(at: /storage/one)!.identifier
+          ]
+      }
+    `
+
+	// execute sets up an account with the given type fingerprints option,
+	// executes the script, and returns the number of decoded slabs
+	//
+	execute := func(t *testing.T, fingerprintsEnabled bool) uint64 {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.SetTypeFingerprintsEnabled(fingerprintsEnabled)
+
+		// The storage health check decodes all slabs of the account
+
+		runtime.SetAtreeValidationEnabled(false)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: setup,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		report := NewExecutionReport()
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(script),
+			},
+			Context{
+				Interface:       runtimeInterface,
+				Location:        common.ScriptLocation{},
+				ExecutionReport: report,
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.String("[Int]"),
+				cadence.String("Int"),
+			}),
+			value,
+		)
+
+		return report.OperationCount(OperationKindDecode)
+	}
+
+	// The results are the same with and without type fingerprints,
+	// but with type fingerprints, the stored values are not decoded
+
+	decodesWithoutFingerprints := execute(t, false)
+	decodesWithFingerprints := execute(t, true)
+
+	assert.Less(t, decodesWithFingerprints, decodesWithoutFingerprints)
+}
+
+func TestRuntimeTypeFingerprintsDisabledLedgerReads(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	// execute executes a transaction which writes the given number of values
+	// with type fingerprints disabled, and returns the number of ledger reads
+	// of all registers, and of the register of the type fingerprint domain
+	//
+	execute := func(t *testing.T, writeCount int) (reads int, fingerprintReads int) {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.SetTypeFingerprintsEnabled(false)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(
+				func(owner, key, value []byte) {
+					reads++
+					if string(key) == interpreter.TypeFingerprintStorageDomain {
+						fingerprintReads++
+					}
+				},
+				nil,
+			),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(fmt.Sprintf(`
+                      transaction {
+                          prepare(signer: AuthAccount) {
+                              var i = 0
+                              while i < %d {
+                                  signer.save(i, to: /storage/number)
+                                  signer.load<Int>(from: /storage/number)
+                                  i = i + 1
+                              }
+                              signer.save(i, to: /storage/number)
+                          }
+                      }
+                    `,
+					writeCount-1,
+				)),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		return
+	}
+
+	// The absence of the type fingerprint domain is only determined once,
+	// so the number of ledger reads does not depend on the number of writes
+
+	reads, fingerprintReads := execute(t, 1)
+	assert.Equal(t, 1, fingerprintReads)
+
+	manyReads, manyFingerprintReads := execute(t, 10)
+	assert.Equal(t, 1, manyFingerprintReads)
+	assert.Equal(t, reads, manyReads)
+}
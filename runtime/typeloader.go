@@ -0,0 +1,75 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ProgramTypeLoader is a type loader which resolves types
+// from the programs cached by the embedder, see Interface.GetProgram.
+//
+// Types of locations for which no program is cached are not resolved,
+// so their programs are loaded, parsed, and checked as usual.
+//
+type ProgramTypeLoader struct {
+	runtimeInterface Interface
+}
+
+var _ interpreter.TypeLoader = ProgramTypeLoader{}
+
+func NewProgramTypeLoader(runtimeInterface Interface) ProgramTypeLoader {
+	return ProgramTypeLoader{
+		runtimeInterface: runtimeInterface,
+	}
+}
+
+func (l ProgramTypeLoader) LoadType(location common.Location, qualifiedIdentifier string) (sema.Type, error) {
+	var program *interpreter.Program
+	var err error
+	wrapPanic(func() {
+		program, err = l.runtimeInterface.GetProgram(location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if program == nil || program.Elaboration == nil {
+		return nil, nil
+	}
+
+	elaboration := program.Elaboration
+	typeID := location.TypeID(qualifiedIdentifier)
+
+	if compositeType, ok := elaboration.CompositeTypes[typeID]; ok {
+		return compositeType, nil
+	}
+
+	if interfaceType, ok := elaboration.InterfaceTypes[typeID]; ok {
+		return interfaceType, nil
+	}
+
+	if entitlementType, ok := elaboration.EntitlementTypes[typeID]; ok {
+		return entitlementType, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,158 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeTypeLoader(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contractLocation := common.AddressLocation{
+		Address: address,
+		Name:    "Test",
+	}
+
+	accountCodes := map[common.LocationID][]byte{}
+
+	var contractCodeReads int
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			contractCodeReads++
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			return accountCodes[location.ID()], nil
+		},
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		emitEvent: func(_ cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	for _, tx := range [][]byte{
+		utils.DeploymentTransaction(
+			"Test",
+			[]byte(`
+              pub contract Test {
+                  pub struct S {}
+              }
+            `),
+		),
+		[]byte(`
+          import Test from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.save(Test.S(), to: /storage/s)
+              }
+          }
+        `),
+	} {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: tx,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	// Resolve the contract's types from the program cached by the embedder,
+	// but do not provide the program to the execution
+
+	contractProgram := runtimeInterface.programs[contractLocation.ID()]
+	require.NotNil(t, contractProgram)
+
+	typeLoader := interpreter.NewCachingTypeLoader(
+		NewProgramTypeLoader(&testRuntimeInterface{
+			getProgram: func(location Location) (*interpreter.Program, error) {
+				if location == contractLocation {
+					return contractProgram, nil
+				}
+				return nil, nil
+			},
+		}),
+	)
+
+	runtimeInterface.programs = nil
+	contractCodeReads = 0
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      // Borrowing checks the dynamic type of the stored value,
+                      // but does not access any members which require the program
+                      let s = signer.borrow<&AnyStruct>(from: /storage/s)
+                      log(s != nil)
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface:  runtimeInterface,
+			Location:   nextTransactionLocation(),
+			TypeLoader: typeLoader,
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"true"}, loggedMessages)
+	assert.Equal(t, 0, contractCodeReads)
+	assert.Equal(t, 1, typeLoader.Len())
+}
@@ -19,6 +19,8 @@
 package runtime
 
 import (
+	"time"
+
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/sema"
@@ -35,6 +37,14 @@ type Block struct {
 	Timestamp int64
 }
 
+// Clock provides the current time to programs,
+// e.g. the timestamp of the current block.
+//
+type Clock interface {
+	// Now returns the current time
+	Now() (time.Time, error)
+}
+
 type ResolvedLocation = sema.ResolvedLocation
 type Identifier = ast.Identifier
 type Location = common.Location
@@ -0,0 +1,162 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestRuntimeValueArenaStorage(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	// The results of arithmetic expressions escape to storage,
+	// and further expressions are evaluated after the values were saved
+
+	const store = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              let a = 10000000000000000000000
+              let b = 7
+
+              signer.save([a * b + b, (a + b) * (a - b)], to: /storage/numbers)
+              signer.save({"x": a * a * b + 1}, to: /storage/dict)
+
+              var sum = 0
+              var i = 0
+              while i < 10 {
+                  sum = sum + (a + i) * (a - i)
+                  i = i + 1
+              }
+              signer.save(sum, to: /storage/sum)
+
+              let numbers = signer.borrow<&[Int]>(from: /storage/numbers)!
+              numbers.append(numbers[0] * 2 + numbers[1] * b)
+          }
+      }
+    `
+
+	const load = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              let numbers = signer.load<[Int]>(from: /storage/numbers)!
+              let dict = signer.load<{String: Int}>(from: /storage/dict)!
+              let sum = signer.load<Int>(from: /storage/sum)!
+
+              signer.save(numbers[0] * numbers[1] + dict["x"]! * sum, to: /storage/result)
+          }
+      }
+    `
+
+	const read = `
+      pub fun main(): [AnyStruct] {
+          let account = getAuthAccount(0x1)
+          return [
+              account.copy<[Int]>(from: /storage/numbers)!,
+              account.copy<{String: Int}>(from: /storage/dict)!,
+              account.copy<Int>(from: /storage/sum)!
+          ]
+      }
+    `
+
+	// execute executes the given transactions with the given value arena option,
+	// and returns the result of the given script, and the storage
+	//
+	execute := func(t *testing.T, valueArenaEnabled bool, transactions []string, script string) (cadence.Value, map[string][]byte) {
+
+		runtime := newTestInterpreterRuntime()
+		runtime.SetValueArenaEnabled(valueArenaEnabled)
+
+		storage := newTestLedger(nil, nil)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: storage,
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		for _, transaction := range transactions {
+			err := runtime.ExecuteTransaction(
+				Script{
+					Source: []byte(transaction),
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  nextTransactionLocation(),
+				},
+			)
+			require.NoError(t, err)
+		}
+
+		if script == "" {
+			return nil, storage.storedValues
+		}
+
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(script),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+
+		return value, storage.storedValues
+	}
+
+	t.Run("store", func(t *testing.T) {
+
+		t.Parallel()
+
+		expectedValue, expectedStorage := execute(t, false, []string{store}, read)
+		actualValue, actualStorage := execute(t, true, []string{store}, read)
+
+		assert.Equal(t, expectedValue, actualValue)
+
+		// The encoded state is the same, byte-for-byte
+
+		assert.Equal(t, expectedStorage, actualStorage)
+	})
+
+	t.Run("load", func(t *testing.T) {
+
+		t.Parallel()
+
+		// Values loaded from storage are used as operands,
+		// and the result is stored again
+
+		_, expectedStorage := execute(t, false, []string{store, load}, "")
+		_, actualStorage := execute(t, true, []string{store, load}, "")
+
+		assert.Equal(t, expectedStorage, actualStorage)
+	})
+}
@@ -74,3 +74,25 @@ func (v ValueDeclaration) ValueDeclarationAvailable(location common.Location) bo
 	}
 	return v.Available(location)
 }
+
+// AvailableToAccounts returns a function which can be used as the availability
+// of a predeclared value or type, which restricts the declaration
+// to the programs of the given accounts, e.g. to the contracts of the service account.
+//
+// Transactions and scripts are not stored in an account,
+// so the declaration is not available to them
+//
+func AvailableToAccounts(addresses ...common.Address) func(common.Location) bool {
+	return func(location common.Location) bool {
+		addressLocation, ok := location.(common.AddressLocation)
+		if !ok {
+			return false
+		}
+		for _, address := range addresses {
+			if addressLocation.Address == address {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,447 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+// IsSubType determines if the given subtype is a subtype of the given supertype,
+// following the subtyping rules of the type checker (see sema.IsSubType).
+// Types are subtypes of themselves.
+//
+// This allows clients to check if a value is a valid argument for a parameter,
+// e.g. before submitting a transaction.
+//
+// NOTE: Exported composite types do not include the interfaces they conform to,
+// so composite types are never subtypes of interface types,
+// or of restricted types which require conformance, e.g. `AnyResource{I}`.
+// Restricted types of the form `T{Us}` are still subtypes of `AnyResource{Vs}`
+// if `Vs` is a subset of `Us`, as the conformance is implied.
+//
+func IsSubType(subType Type, superType Type) bool {
+	if subType == nil || superType == nil {
+		return false
+	}
+
+	if equalTypes(subType, superType) {
+		return true
+	}
+
+	return checkSubTypeWithoutEquality(subType, superType)
+}
+
+// equalTypes determines if the given types are equal.
+// Restricted types are equal if their restricted types are equal,
+// and their restriction sets are equal, independent of the order
+//
+func equalTypes(a, b Type) bool {
+	restrictedA, ok := a.(RestrictedType)
+	if !ok {
+		return a.ID() == b.ID()
+	}
+
+	restrictedB, ok := b.(RestrictedType)
+	if !ok {
+		return false
+	}
+
+	return equalTypes(restrictedA.Type, restrictedB.Type) &&
+		isRestrictionSubset(restrictedA.Restrictions, restrictedB.Restrictions) &&
+		isRestrictionSubset(restrictedB.Restrictions, restrictedA.Restrictions)
+}
+
+// isRestrictionSubset returns true if all restrictions in the subset
+// are also in the superset
+//
+func isRestrictionSubset(subset, superset []Type) bool {
+	supersetIDs := make(map[string]struct{}, len(superset))
+	for _, restriction := range superset {
+		supersetIDs[restriction.ID()] = struct{}{}
+	}
+
+	for _, restriction := range subset {
+		if _, ok := supersetIDs[restriction.ID()]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isResourceType returns true if values of the given type are resources
+//
+func isResourceType(ty Type) bool {
+	switch ty := ty.(type) {
+	case AnyResourceType, *ResourceType, *ResourceInterfaceType:
+		return true
+	case OptionalType:
+		return isResourceType(ty.Type)
+	case VariableSizedArrayType:
+		return isResourceType(ty.ElementType)
+	case ConstantSizedArrayType:
+		return isResourceType(ty.ElementType)
+	case DictionaryType:
+		return isResourceType(ty.ElementType)
+	case RestrictedType:
+		return isResourceType(ty.Type)
+	default:
+		return false
+	}
+}
+
+func isCompositeType(ty Type) bool {
+	_, ok := ty.(CompositeType)
+	return ok
+}
+
+func isTopType(ty Type) bool {
+	switch ty.(type) {
+	case AnyType, AnyStructType, AnyResourceType:
+		return true
+	default:
+		return false
+	}
+}
+
+func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
+
+	if _, ok := subType.(NeverType); ok {
+		return true
+	}
+
+	switch superType.(type) {
+	case AnyType:
+		return true
+
+	case AnyStructType:
+		if isResourceType(subType) {
+			return false
+		}
+		_, ok := subType.(AnyType)
+		return !ok
+
+	case AnyResourceType:
+		return isResourceType(subType)
+
+	case NumberType:
+		switch subType.(type) {
+		case SignedNumberType:
+			return true
+		}
+
+		return IsSubType(subType, IntegerType{}) ||
+			IsSubType(subType, FixedPointType{})
+
+	case SignedNumberType:
+		return IsSubType(subType, SignedIntegerType{}) ||
+			IsSubType(subType, SignedFixedPointType{})
+
+	case IntegerType:
+		switch subType.(type) {
+		case SignedIntegerType,
+			UIntType,
+			UInt8Type, UInt16Type, UInt32Type, UInt64Type, UInt128Type, UInt256Type,
+			Word8Type, Word16Type, Word32Type, Word64Type:
+
+			return true
+
+		default:
+			return IsSubType(subType, SignedIntegerType{})
+		}
+
+	case SignedIntegerType:
+		switch subType.(type) {
+		case IntType,
+			Int8Type, Int16Type, Int32Type, Int64Type, Int128Type, Int256Type:
+
+			return true
+
+		default:
+			return false
+		}
+
+	case FixedPointType:
+		switch subType.(type) {
+		case SignedFixedPointType, UFix64Type, UFix128Type:
+			return true
+
+		default:
+			return IsSubType(subType, SignedFixedPointType{})
+		}
+
+	case SignedFixedPointType:
+		switch subType.(type) {
+		case Fix64Type, Fix128Type:
+			return true
+
+		default:
+			return false
+		}
+
+	case PathType:
+		return IsSubType(subType, StoragePathType{}) ||
+			IsSubType(subType, CapabilityPathType{})
+
+	case CapabilityPathType:
+		return IsSubType(subType, PrivatePathType{}) ||
+			IsSubType(subType, PublicPathType{})
+	}
+
+	switch typedSuperType := superType.(type) {
+	case OptionalType:
+		optionalSubType, ok := subType.(OptionalType)
+		if !ok {
+			// T <: U? if T <: U
+			return IsSubType(subType, typedSuperType.Type)
+		}
+		// Optionals are covariant: T? <: U? if T <: U
+		return IsSubType(optionalSubType.Type, typedSuperType.Type)
+
+	case DictionaryType:
+		typedSubType, ok := subType.(DictionaryType)
+		if !ok {
+			return false
+		}
+
+		return IsSubType(typedSubType.KeyType, typedSuperType.KeyType) &&
+			IsSubType(typedSubType.ElementType, typedSuperType.ElementType)
+
+	case VariableSizedArrayType:
+		typedSubType, ok := subType.(VariableSizedArrayType)
+		if !ok {
+			return false
+		}
+
+		return IsSubType(typedSubType.ElementType, typedSuperType.ElementType)
+
+	case ConstantSizedArrayType:
+		typedSubType, ok := subType.(ConstantSizedArrayType)
+		if !ok {
+			return false
+		}
+
+		if typedSubType.Size != typedSuperType.Size {
+			return false
+		}
+
+		return IsSubType(typedSubType.ElementType, typedSuperType.ElementType)
+
+	case ReferenceType:
+		typedSubType, ok := subType.(ReferenceType)
+		if !ok {
+			return false
+		}
+
+		return isReferenceSubType(typedSubType, typedSuperType)
+
+	case FunctionType:
+		typedSubType, ok := subType.(FunctionType)
+		if !ok {
+			return false
+		}
+
+		if len(typedSubType.Parameters) != len(typedSuperType.Parameters) {
+			return false
+		}
+
+		// Functions are contravariant in their parameter types
+
+		for i, subParameter := range typedSubType.Parameters {
+			superParameter := typedSuperType.Parameters[i]
+			if !IsSubType(superParameter.Type, subParameter.Type) {
+				return false
+			}
+		}
+
+		// Functions are covariant in their return type
+
+		return IsSubType(typedSubType.ReturnType, typedSuperType.ReturnType)
+
+	case RestrictedType:
+		return isRestrictedSubType(subType, typedSuperType)
+
+	case CompositeType:
+		// A restricted type `T{Us}` is a subtype of an unrestricted type `V`:
+		// if `T == V`. The owner may freely unrestrict
+
+		if typedSubType, ok := subType.(RestrictedType); ok {
+			return isCompositeType(typedSubType.Type) &&
+				equalTypes(typedSubType.Type, typedSuperType)
+		}
+
+	case CapabilityType:
+		typedSubType, ok := subType.(CapabilityType)
+		if !ok {
+			return false
+		}
+
+		// Capability<T> <: Capability
+		if typedSuperType.BorrowType == nil {
+			return true
+		}
+
+		// Capability<T> <: Capability<U> if T <: U
+		if typedSubType.BorrowType == nil {
+			return false
+		}
+
+		return IsSubType(typedSubType.BorrowType, typedSuperType.BorrowType)
+	}
+
+	return false
+}
+
+func isRestrictedSubType(subType Type, superType RestrictedType) bool {
+
+	restrictedSuperType := superType.Type
+
+	if isTopType(restrictedSuperType) {
+
+		// `AnyResource`, `AnyStruct`, and `Any` are not statically subtypes
+		// of a restricted type `AnyResource{Us}` / `AnyStruct{Us}` / `Any{Us}`
+
+		if isTopType(subType) {
+			return false
+		}
+
+		if typedSubType, ok := subType.(RestrictedType); ok {
+			// A restricted type `T{Us}`
+			// is a subtype of a restricted type `AnyResource{Vs}` / `AnyStruct{Vs}` / `Any{Vs}`:
+			// if `T` is a subtype of the restricted supertype,
+			// and `Vs` is a subset of `Us`
+
+			return IsSubType(typedSubType.Type, restrictedSuperType) &&
+				isRestrictionSubset(superType.Restrictions, typedSubType.Restrictions)
+		}
+
+		// An unrestricted composite type `T` is a subtype of `AnyResource{Us}`
+		// if it conforms to `Us`, which is unknown for exported types,
+		// unless there are no restrictions
+
+		return isCompositeType(subType) &&
+			len(superType.Restrictions) == 0 &&
+			IsSubType(subType, restrictedSuperType)
+	}
+
+	switch typedSubType := subType.(type) {
+	case RestrictedType:
+		// A restricted type `T{Us}` is a subtype of a restricted type `V{Ws}`:
+		// if `T == V`. The owner may freely restrict and unrestrict
+
+		return isCompositeType(typedSubType.Type) &&
+			equalTypes(typedSubType.Type, restrictedSuperType)
+
+	case CompositeType:
+		// An unrestricted type `T` is a subtype of a restricted type `U{Vs}`:
+		// if `T == U`. The owner may freely restrict
+
+		return equalTypes(typedSubType, restrictedSuperType)
+	}
+
+	return false
+}
+
+func isReferenceSubType(subType ReferenceType, superType ReferenceType) bool {
+
+	// An authorized reference type `auth &T`
+	// is a subtype of a reference type `&U` (authorized or non-authorized),
+	// if `T` is a subtype of `U`
+
+	if subType.Authorized {
+		return IsSubType(subType.Type, superType.Type)
+	}
+
+	// An unauthorized reference type is not a subtype of an authorized reference type.
+	// The holder of the reference may not gain more permissions
+
+	if superType.Authorized {
+		return false
+	}
+
+	switch innerSuperType := superType.Type.(type) {
+	case RestrictedType:
+
+		restrictedSuperType := innerSuperType.Type
+
+		switch innerSubType := subType.Type.(type) {
+		case RestrictedType:
+
+			if isTopType(restrictedSuperType) {
+				// `&T{Us}` <: `&AnyResource{Vs}`:
+				// if `T` is a subtype of the restricted supertype,
+				// and `Vs` is a subset of `Us`.
+				// The holder of the reference may only further restrict the reference
+
+				return IsSubType(innerSubType.Type, restrictedSuperType) &&
+					isRestrictionSubset(innerSuperType.Restrictions, innerSubType.Restrictions)
+			}
+
+			// `&T{Us}` <: `&V{Ws}`: if `T == V` and `Ws` is a subset of `Us`
+
+			return isCompositeType(innerSubType.Type) &&
+				equalTypes(innerSubType.Type, restrictedSuperType) &&
+				isRestrictionSubset(innerSuperType.Restrictions, innerSubType.Restrictions)
+
+		case CompositeType:
+
+			if isTopType(restrictedSuperType) {
+				// `&T` <: `&AnyResource{Us}`: if `T` conforms to `Us`,
+				// which is unknown for exported types, unless there are no restrictions
+
+				return len(innerSuperType.Restrictions) == 0 &&
+					IsSubType(innerSubType, restrictedSuperType)
+			}
+
+			// `&T` <: `&U{Vs}`: if `T == U`
+
+			return equalTypes(innerSubType, restrictedSuperType)
+		}
+
+		return false
+
+	case CompositeType:
+		// An unauthorized reference is not a subtype of a reference to a composite type `&V`.
+		// The holder of the reference may not gain more permissions or knowledge
+
+		return false
+	}
+
+	switch superType.Type.(type) {
+	case AnyType:
+		// `&T` <: `&Any`: always
+
+		return true
+
+	case AnyResourceType:
+		// `&T{Us}` <: `&AnyResource`: if `T` is `AnyResource` or a resource-kinded composite.
+		// `&T` <: `&AnyResource`: if `T` is a resource-kinded composite
+
+		switch innerSubType := subType.Type.(type) {
+		case RestrictedType:
+			return isResourceType(innerSubType.Type)
+
+		case CompositeType:
+			return isResourceType(innerSubType)
+		}
+
+	case AnyStructType:
+		// `&T` <: `&AnyStruct`: if `T <: AnyStruct`
+
+		return IsSubType(subType.Type, superType.Type)
+	}
+
+	return false
+}
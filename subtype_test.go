@@ -0,0 +1,221 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestIsSubType(t *testing.T) {
+
+	t.Parallel()
+
+	structType := &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "S",
+	}
+
+	resourceType := &ResourceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "R",
+	}
+
+	otherResourceType := &ResourceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "R2",
+	}
+
+	interfaceType1 := &ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "I1",
+	}
+
+	interfaceType2 := &ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "I2",
+	}
+
+	type testCase struct {
+		subType   Type
+		superType Type
+		expected  bool
+	}
+
+	testCases := []testCase{
+		{IntType{}, IntType{}, true},
+		{IntType{}, AnyStructType{}, true},
+		{IntType{}, AnyType{}, true},
+		{IntType{}, AnyResourceType{}, false},
+		{NeverType{}, IntType{}, true},
+		{AnyType{}, AnyStructType{}, false},
+		{UInt8Type{}, IntegerType{}, true},
+		{UInt8Type{}, SignedIntegerType{}, false},
+		{Int8Type{}, SignedNumberType{}, true},
+		{UFix64Type{}, NumberType{}, true},
+		{UFix64Type{}, SignedFixedPointType{}, false},
+		{Fix64Type{}, SignedNumberType{}, true},
+		{IntType{}, UInt8Type{}, false},
+		{PublicPathType{}, CapabilityPathType{}, true},
+		{PublicPathType{}, PathType{}, true},
+		{StoragePathType{}, CapabilityPathType{}, false},
+		{PathType{}, StoragePathType{}, false},
+
+		// optionals
+		{IntType{}, OptionalType{Type: IntType{}}, true},
+		{OptionalType{Type: IntType{}}, IntType{}, false},
+		{OptionalType{Type: Int8Type{}}, OptionalType{Type: IntegerType{}}, true},
+
+		// arrays and dictionaries
+		{VariableSizedArrayType{ElementType: IntType{}}, VariableSizedArrayType{ElementType: NumberType{}}, true},
+		{VariableSizedArrayType{ElementType: IntType{}}, VariableSizedArrayType{ElementType: StringType{}}, false},
+		{ConstantSizedArrayType{ElementType: IntType{}, Size: 2}, ConstantSizedArrayType{ElementType: IntType{}, Size: 3}, false},
+		{ConstantSizedArrayType{ElementType: IntType{}, Size: 2}, VariableSizedArrayType{ElementType: IntType{}}, false},
+		{
+			DictionaryType{KeyType: StringType{}, ElementType: IntType{}},
+			DictionaryType{KeyType: StringType{}, ElementType: OptionalType{Type: IntType{}}},
+			true,
+		},
+		{VariableSizedArrayType{ElementType: resourceType}, AnyResourceType{}, true},
+		{VariableSizedArrayType{ElementType: resourceType}, AnyStructType{}, false},
+
+		// composites
+		{structType, AnyStructType{}, true},
+		{resourceType, AnyResourceType{}, true},
+		{resourceType, AnyStructType{}, false},
+		{resourceType, otherResourceType, false},
+		{resourceType, interfaceType1, false},
+
+		// restricted types
+		{
+			RestrictedType{Type: resourceType, Restrictions: []Type{interfaceType1}},
+			resourceType,
+			true,
+		},
+		{
+			resourceType,
+			RestrictedType{Type: resourceType, Restrictions: []Type{interfaceType1}},
+			true,
+		},
+		{
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1, interfaceType2}},
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}},
+			true,
+		},
+		{
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}},
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1, interfaceType2}},
+			false,
+		},
+		{
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1, interfaceType2}},
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType2, interfaceType1}},
+			true,
+		},
+		{
+			RestrictedType{Type: resourceType, Restrictions: []Type{interfaceType1}},
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}},
+			true,
+		},
+		{
+			AnyResourceType{},
+			RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}},
+			false,
+		},
+
+		// references
+		{ReferenceType{Type: resourceType}, ReferenceType{Type: AnyResourceType{}}, true},
+		{ReferenceType{Type: resourceType}, ReferenceType{Type: AnyType{}}, true},
+		{ReferenceType{Type: resourceType}, ReferenceType{Authorized: true, Type: resourceType}, false},
+		{ReferenceType{Authorized: true, Type: resourceType}, ReferenceType{Type: resourceType}, true},
+		{ReferenceType{Type: IntType{}}, ReferenceType{Type: AnyStructType{}}, true},
+		{ReferenceType{Type: IntType{}}, IntType{}, false},
+		{
+			ReferenceType{Type: RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1, interfaceType2}}},
+			ReferenceType{Type: RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType2}}},
+			true,
+		},
+		{
+			ReferenceType{Type: RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}}},
+			ReferenceType{Type: RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType2}}},
+			false,
+		},
+		{
+			ReferenceType{Type: RestrictedType{Type: AnyResourceType{}, Restrictions: []Type{interfaceType1}}},
+			ReferenceType{Type: resourceType},
+			false,
+		},
+		{
+			ReferenceType{Type: resourceType},
+			ReferenceType{Type: RestrictedType{Type: resourceType, Restrictions: []Type{interfaceType1}}},
+			true,
+		},
+
+		// capabilities
+		{CapabilityType{BorrowType: ReferenceType{Type: resourceType}}, CapabilityType{}, true},
+		{CapabilityType{}, CapabilityType{BorrowType: ReferenceType{Type: resourceType}}, false},
+		{
+			CapabilityType{BorrowType: ReferenceType{Type: resourceType}},
+			CapabilityType{BorrowType: ReferenceType{Type: AnyResourceType{}}},
+			true,
+		},
+		{
+			CapabilityType{BorrowType: ReferenceType{Type: AnyResourceType{}}},
+			CapabilityType{BorrowType: ReferenceType{Type: resourceType}},
+			false,
+		},
+
+		// functions
+		{
+			FunctionType{Parameters: []Parameter{{Type: IntegerType{}}}, ReturnType: IntType{}},
+			FunctionType{Parameters: []Parameter{{Type: IntType{}}}, ReturnType: IntegerType{}},
+			true,
+		},
+		{
+			FunctionType{Parameters: []Parameter{{Type: IntType{}}}, ReturnType: IntType{}},
+			FunctionType{Parameters: []Parameter{{Type: IntegerType{}}}, ReturnType: IntType{}},
+			false,
+		},
+		{
+			FunctionType{ReturnType: VoidType{}},
+			FunctionType{Parameters: []Parameter{{Type: IntType{}}}, ReturnType: VoidType{}},
+			false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		name := fmt.Sprintf("%s <: %s", testCase.subType.ID(), testCase.superType.ID())
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			assert.Equal(t,
+				testCase.expected,
+				IsSubType(testCase.subType, testCase.superType),
+			)
+		})
+	}
+}
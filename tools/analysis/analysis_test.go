@@ -0,0 +1,195 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func TestLoadAndRun(t *testing.T) {
+
+	t.Parallel()
+
+	contractLocation := common.StringLocation("contract")
+	scriptLocation := common.StringLocation("script")
+
+	codes := map[common.Location]string{
+		contractLocation: `
+          pub contract C {
+              pub fun answer(): Int { return 42 }
+          }
+        `,
+		scriptLocation: `
+          import C from "contract"
+
+          pub fun main(): Int {
+              return C.answer()
+          }
+        `,
+	}
+
+	config := &analysis.Config{
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) (string, error) {
+			code, ok := codes[location]
+			if !ok {
+				return "", fmt.Errorf("cannot find code for %s", location)
+			}
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, scriptLocation)
+	require.NoError(t, err)
+
+	require.Len(t, programs, 2)
+
+	program := programs[scriptLocation.ID()]
+	require.NotNil(t, program)
+
+	// count the function declarations in a required analyzer,
+	// and report the count in the dependent analyzer
+
+	functionCountAnalyzer := &analysis.Analyzer{
+		Run: func(pass *analysis.Pass) interface{} {
+			return len(pass.Program.Program.FunctionDeclarations())
+		},
+	}
+
+	reportingAnalyzer := &analysis.Analyzer{
+		Requires: []*analysis.Analyzer{
+			functionCountAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			count := pass.ResultOf[functionCountAnalyzer].(int)
+			pass.Report(analysis.Diagnostic{
+				Location: pass.Program.Location,
+				Message:  fmt.Sprintf("%d functions", count),
+			})
+			return nil
+		},
+	}
+
+	var diagnostics []analysis.Diagnostic
+
+	program.Run(
+		[]*analysis.Analyzer{reportingAnalyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	assert.Equal(t,
+		[]analysis.Diagnostic{
+			{
+				Location: scriptLocation,
+				Message:  "1 functions",
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestLoadInvalidProgram(t *testing.T) {
+
+	t.Parallel()
+
+	config := &analysis.Config{
+		ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+			return `pub fun main(): Int { return "not an integer" }`, nil
+		},
+	}
+
+	_, err := analysis.Load(config, common.StringLocation("script"))
+	require.Error(t, err)
+}
+
+func TestLoadOccurrences(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub fun main(): Int {
+          var x = 1
+          x = 2
+          return x
+      }
+    `
+
+	load := func(positionInfoEnabled bool) *analysis.Program {
+		config := &analysis.Config{
+			ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+				return code, nil
+			},
+			PositionInfoEnabled: positionInfoEnabled,
+		}
+
+		location := common.StringLocation("script")
+
+		programs, err := analysis.Load(config, location)
+		require.NoError(t, err)
+
+		return programs[location.ID()]
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		program := load(false)
+		assert.Nil(t, program.Occurrences)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		program := load(true)
+		require.NotNil(t, program.Occurrences)
+
+		declaration := program.Occurrences.Find(sema.Position{Line: 3, Column: 14})
+		require.NotNil(t, declaration)
+
+		var roles []sema.OccurrenceRole
+		for _, occurrence := range program.Occurrences.ForOrigin(declaration.Origin) {
+			roles = append(roles, occurrence.Role)
+		}
+
+		assert.Equal(t,
+			[]sema.OccurrenceRole{
+				sema.OccurrenceRoleDeclaration,
+				sema.OccurrenceRoleWrite,
+				sema.OccurrenceRoleRead,
+			},
+			roles,
+		)
+	})
+}
@@ -0,0 +1,36 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package analysis provides a framework for analyzing Cadence programs.
+//
+// An analyzer is run on a program which has been parsed and checked,
+// and reports diagnostics. Analyzers can depend on the results of other analyzers.
+//
+package analysis
+
+// Analyzer describes an analysis function and its dependencies
+//
+type Analyzer struct {
+	Description string
+	// Requires are the analyzers which must be run before this analyzer.
+	// Their results are available through Pass.ResultOf
+	Requires []*Analyzer
+	// Run applies the analyzer to the program of the given pass
+	// and returns its result, which may be nil
+	Run func(*Pass) interface{}
+}
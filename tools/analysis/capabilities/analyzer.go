@@ -0,0 +1,269 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package capabilities provides an analyzer which audits the public capabilities
+// a program links, for security reviews of contracts.
+//
+// For each public capability link, the report contains the path,
+// the borrow type, and the functions which are reachable through the capability.
+//
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const Category = "capabilities"
+
+const publicPathDomain = "public"
+
+// Link is a public capability link created by a program
+//
+type Link struct {
+	// Path is the public path of the capability, e.g. `/public/receiver`.
+	// It is empty if the path is not a literal
+	Path string `json:"path"`
+	// Target is the target path of the link, e.g. `/storage/vault`.
+	// It is empty if the path is not a literal
+	Target string `json:"target"`
+	// BorrowType is the type the capability can be borrowed as, e.g. `&Vault{Receiver}`
+	BorrowType string `json:"borrowType"`
+	// Authorized is true if the borrow type is an authorized reference,
+	// which allows downcasting to the referenced type
+	Authorized bool `json:"authorized"`
+	// Functions are the qualified names of the functions which are reachable
+	// through the capability, e.g. `Vault.deposit`
+	Functions []string `json:"functions"`
+	ast.Range
+}
+
+// Report is the capability audit report of a program
+//
+type Report struct {
+	Location common.LocationID `json:"location"`
+	Links    []Link            `json:"links"`
+}
+
+// Analyzer reports all public capability links of a program,
+// and returns a *Report
+//
+var Analyzer = &analysis.Analyzer{
+	Description: "Lists the public capabilities linked by a program and the functions reachable through them",
+	Run: func(pass *analysis.Pass) interface{} {
+		report := &Report{
+			Location: pass.Program.Location.ID(),
+			Links:    []Link{},
+		}
+
+		for _, declaration := range pass.Program.Program.Declarations() {
+			ast.Inspect(declaration, func(element ast.Element) bool {
+				invocationExpression, ok := element.(*ast.InvocationExpression)
+				if !ok {
+					return true
+				}
+
+				link, ok := publicLink(pass.Program.Elaboration, invocationExpression)
+				if !ok {
+					return true
+				}
+
+				report.Links = append(report.Links, link)
+				reportLink(pass, link)
+
+				return true
+			})
+		}
+
+		return report
+	},
+}
+
+// publicLink returns the public capability link created by the given invocation,
+// if it is an invocation of `AuthAccount.link` with a public path
+//
+func publicLink(elaboration *sema.Elaboration, invocationExpression *ast.InvocationExpression) (Link, bool) {
+
+	memberExpression, ok := invocationExpression.InvokedExpression.(*ast.MemberExpression)
+	if !ok || memberExpression.Identifier.Identifier != sema.AuthAccountLinkField {
+		return Link{}, false
+	}
+
+	memberInfo, ok := elaboration.MemberExpressionMemberInfos[memberExpression]
+	if !ok || memberInfo.AccessedType != sema.AuthAccountType {
+		return Link{}, false
+	}
+
+	arguments := invocationExpression.Arguments
+	if len(arguments) < 2 {
+		return Link{}, false
+	}
+
+	// The capability path is public if it is a public path literal,
+	// or if it has the public path type
+
+	path, isPathLiteral := pathLiteral(arguments[0].Expression)
+	if isPathLiteral {
+		if !strings.HasPrefix(path, "/"+publicPathDomain+"/") {
+			return Link{}, false
+		}
+	} else {
+		argumentTypes := elaboration.InvocationExpressionArgumentTypes[invocationExpression]
+		if len(argumentTypes) < 1 || argumentTypes[0] != sema.PublicPathType {
+			return Link{}, false
+		}
+	}
+
+	target, _ := pathLiteral(arguments[1].Expression)
+
+	link := Link{
+		Path:   path,
+		Target: target,
+		Range:  ast.NewRangeFromPositioned(invocationExpression),
+	}
+
+	typeArguments := elaboration.InvocationExpressionTypeArguments[invocationExpression]
+	if typeArguments != nil && typeArguments.Len() > 0 {
+		borrowType := typeArguments.Oldest().Value
+		link.BorrowType = borrowType.QualifiedString()
+		link.Authorized, link.Functions = reachableFunctions(borrowType)
+	}
+
+	if link.Functions == nil {
+		link.Functions = []string{}
+	}
+
+	return link, true
+}
+
+func pathLiteral(expression ast.Expression) (string, bool) {
+	pathExpression, ok := expression.(*ast.PathExpression)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf(
+		"/%s/%s",
+		pathExpression.Domain.Identifier,
+		pathExpression.Identifier.Identifier,
+	), true
+}
+
+// reachableFunctions returns the sorted qualified names of the functions
+// which can be called through a reference of the given borrow type.
+//
+// The functions of a restricted type are the functions of its restrictions.
+// Authorized references can be downcast to the referenced type,
+// so all functions of the restricted type are reachable as well.
+//
+func reachableFunctions(borrowType sema.Type) (authorized bool, functions []string) {
+
+	referenceType, ok := borrowType.(*sema.ReferenceType)
+	if !ok {
+		return false, nil
+	}
+
+	authorized = referenceType.Authorized
+
+	names := map[string]struct{}{}
+
+	addFunctions := func(ty sema.Type) {
+		var qualifiedIdentifier string
+		var members *sema.StringMemberOrderedMap
+
+		switch ty := ty.(type) {
+		case *sema.CompositeType:
+			qualifiedIdentifier = ty.QualifiedIdentifier()
+			members = ty.Members
+		case *sema.InterfaceType:
+			qualifiedIdentifier = ty.QualifiedIdentifier()
+			members = ty.Members
+		default:
+			return
+		}
+
+		if members == nil {
+			return
+		}
+
+		// NOTE: predeclared functions, like `getType`, are not reported,
+		// as they are available for all values
+
+		members.Foreach(func(name string, member *sema.Member) {
+			if member.Predeclared ||
+				member.DeclarationKind != common.DeclarationKindFunction ||
+				member.Access.IsLessPermissiveThan(ast.AccessPublic) {
+
+				return
+			}
+
+			names[qualifiedIdentifier+"."+name] = struct{}{}
+		})
+	}
+
+	switch referencedType := referenceType.Type.(type) {
+	case *sema.RestrictedType:
+		for _, restriction := range referencedType.Restrictions {
+			addFunctions(restriction)
+		}
+		if authorized || len(referencedType.Restrictions) == 0 {
+			addFunctions(referencedType.Type)
+		}
+
+	default:
+		addFunctions(referencedType)
+	}
+
+	functions = make([]string, 0, len(names))
+	for name := range names { //nolint:maprangecheck
+		functions = append(functions, name)
+	}
+	sort.Strings(functions)
+
+	return authorized, functions
+}
+
+func reportLink(pass *analysis.Pass, link Link) {
+
+	path := link.Path
+	if path == "" {
+		path = "<dynamic path>"
+	}
+
+	secondaryMessage := "no functions are reachable"
+	if len(link.Functions) > 0 {
+		secondaryMessage = fmt.Sprintf(
+			"reachable functions: %s",
+			strings.Join(link.Functions, ", "),
+		)
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Location:         pass.Program.Location,
+		Category:         Category,
+		Message:          fmt.Sprintf("public capability `%s` exposes `%s`", path, link.BorrowType),
+		SecondaryMessage: secondaryMessage,
+		Range:            link.Range,
+	})
+}
@@ -0,0 +1,179 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capabilities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func analyze(t *testing.T, code string) (*Report, []analysis.Diagnostic) {
+	location := common.StringLocation("test")
+
+	config := &analysis.Config{
+		ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	var diagnostics []analysis.Diagnostic
+
+	var report *Report
+
+	reportingAnalyzer := &analysis.Analyzer{
+		Requires: []*analysis.Analyzer{Analyzer},
+		Run: func(pass *analysis.Pass) interface{} {
+			report = pass.ResultOf[Analyzer].(*Report)
+			return nil
+		},
+	}
+
+	programs[location.ID()].Run(
+		[]*analysis.Analyzer{reportingAnalyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	return report, diagnostics
+}
+
+const code = `
+  pub contract Bank {
+
+      pub resource interface Receiver {
+          pub fun deposit(amount: Int)
+      }
+
+      pub resource interface Provider {
+          pub fun withdraw(amount: Int)
+      }
+
+      pub resource Vault: Receiver, Provider {
+          pub fun deposit(amount: Int) {}
+          pub fun withdraw(amount: Int) {}
+          access(contract) fun reset() {}
+      }
+
+      init() {
+          self.account.save(<-create Vault(), to: /storage/vault)
+          self.account.link<&Vault{Receiver}>(/public/receiver, target: /storage/vault)
+          self.account.link<auth &Vault{Receiver}>(/public/authReceiver, target: /storage/vault)
+          self.account.link<&Vault{Provider}>(/private/provider, target: /storage/vault)
+      }
+  }
+`
+
+func TestAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	report, diagnostics := analyze(t, code)
+
+	require.NotNil(t, report)
+	assert.Equal(t, common.LocationID("S.test"), report.Location)
+
+	require.Len(t, report.Links, 2)
+
+	receiverLink := report.Links[0]
+	assert.Equal(t, "/public/receiver", receiverLink.Path)
+	assert.Equal(t, "/storage/vault", receiverLink.Target)
+	assert.Equal(t, "&Bank.Vault{Bank.Receiver}", receiverLink.BorrowType)
+	assert.False(t, receiverLink.Authorized)
+	assert.Equal(t,
+		[]string{"Bank.Receiver.deposit"},
+		receiverLink.Functions,
+	)
+
+	// An authorized reference can be downcast,
+	// so the functions of the restricted type are reachable
+
+	authReceiverLink := report.Links[1]
+	assert.Equal(t, "/public/authReceiver", authReceiverLink.Path)
+	assert.True(t, authReceiverLink.Authorized)
+	assert.Equal(t,
+		[]string{
+			"Bank.Receiver.deposit",
+			"Bank.Vault.deposit",
+			"Bank.Vault.withdraw",
+		},
+		authReceiverLink.Functions,
+	)
+
+	require.Len(t, diagnostics, 2)
+
+	assert.Equal(t,
+		analysis.Diagnostic{
+			Location:         common.StringLocation("test"),
+			Category:         Category,
+			Message:          "public capability `/public/receiver` exposes `&Bank.Vault{Bank.Receiver}`",
+			SecondaryMessage: "reachable functions: Bank.Receiver.deposit",
+			Range:            receiverLink.Range,
+		},
+		diagnostics[0],
+	)
+}
+
+func TestReportJSON(t *testing.T) {
+
+	t.Parallel()
+
+	report, _ := analyze(t, `
+      pub fun main(account: AuthAccount) {
+          account.link<&Int>(/public/number, target: /storage/number)
+      }
+    `)
+
+	require.NotNil(t, report)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Location string `json:"location"`
+		Links    []struct {
+			Path       string   `json:"path"`
+			Target     string   `json:"target"`
+			BorrowType string   `json:"borrowType"`
+			Authorized bool     `json:"authorized"`
+			Functions  []string `json:"functions"`
+		} `json:"links"`
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, "S.test", decoded.Location)
+	require.Len(t, decoded.Links, 1)
+	assert.Equal(t, "/public/number", decoded.Links[0].Path)
+	assert.Equal(t, "/storage/number", decoded.Links[0].Target)
+	assert.Equal(t, "&Int", decoded.Links[0].BorrowType)
+	assert.False(t, decoded.Links[0].Authorized)
+	assert.Equal(t, []string{}, decoded.Links[0].Functions)
+}
@@ -0,0 +1,147 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package determinism provides an analyzer which reports the use of
+// non-deterministic or context-dependent constructs in code
+// which is expected to be replayable.
+//
+package determinism
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const Category = "determinism"
+
+const unsafeRandomFunctionName = "unsafeRandom"
+const capabilityBorrowFunctionName = "borrow"
+
+// Config configures which constructs are reported, and where
+//
+type Config struct {
+	// IgnoreUnsafeRandom disables reporting calls of `unsafeRandom`
+	IgnoreUnsafeRandom bool
+	// IgnoreBlockInformation disables reporting accesses of block information,
+	// e.g. `getCurrentBlock().timestamp`
+	IgnoreBlockInformation bool
+	// IgnoreCapabilityBorrows disables reporting borrows of capabilities,
+	// which result in calls of external code that may change
+	IgnoreCapabilityBorrows bool
+	// IsReplayable reports if the given function is expected to be replayable.
+	// Only replayable functions, including the functions nested in them, are analyzed.
+	// If nil, all functions are analyzed
+	IsReplayable func(declaration *ast.FunctionDeclaration) bool
+}
+
+// Analyzer reports all non-deterministic and context-dependent constructs
+//
+var Analyzer = NewAnalyzer(Config{})
+
+// NewAnalyzer returns a new determinism analyzer with the given configuration
+//
+func NewAnalyzer(config Config) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Description: "Detects non-deterministic and context-dependent constructs in replayable code",
+		Run: func(pass *analysis.Pass) interface{} {
+			for _, declaration := range pass.Program.Program.Declarations() {
+				ast.Inspect(declaration, func(element ast.Element) bool {
+					return inspect(config, pass, element)
+				})
+			}
+			return nil
+		},
+	}
+}
+
+func inspect(config Config, pass *analysis.Pass, element ast.Element) bool {
+	switch element := element.(type) {
+	case *ast.FunctionDeclaration:
+		return config.IsReplayable == nil || config.IsReplayable(element)
+
+	case *ast.SpecialFunctionDeclaration:
+		return config.IsReplayable == nil || config.IsReplayable(element.FunctionDeclaration)
+
+	case *ast.InvocationExpression:
+		if !config.IgnoreUnsafeRandom {
+			checkUnsafeRandom(pass, element)
+		}
+
+	case *ast.MemberExpression:
+		memberInfo, ok := pass.Program.Elaboration.MemberExpressionMemberInfos[element]
+		if !ok {
+			break
+		}
+
+		switch accessedType := memberInfo.AccessedType.(type) {
+		case *sema.SimpleType:
+			if !config.IgnoreBlockInformation && accessedType == sema.BlockType {
+				report(
+					pass,
+					element,
+					fmt.Sprintf(
+						"access of block information `%s` depends on the execution context",
+						element.Identifier.Identifier,
+					),
+					"the value differs between blocks",
+				)
+			}
+
+		case *sema.CapabilityType:
+			if !config.IgnoreCapabilityBorrows &&
+				element.Identifier.Identifier == capabilityBorrowFunctionName {
+
+				report(
+					pass,
+					element,
+					"borrowing a capability depends on the state of another account",
+					"the capability may be unlinked, and the borrowed object's code may be updated",
+				)
+			}
+		}
+	}
+
+	return true
+}
+
+func checkUnsafeRandom(pass *analysis.Pass, invocationExpression *ast.InvocationExpression) {
+	identifierExpression, ok := invocationExpression.InvokedExpression.(*ast.IdentifierExpression)
+	if !ok || identifierExpression.Identifier.Identifier != unsafeRandomFunctionName {
+		return
+	}
+
+	report(
+		pass,
+		invocationExpression,
+		fmt.Sprintf("call of `%s` is not deterministic", unsafeRandomFunctionName),
+		"the result differs between executions",
+	)
+}
+
+func report(pass *analysis.Pass, element ast.HasPosition, message string, secondaryMessage string) {
+	pass.Report(analysis.Diagnostic{
+		Location:         pass.Program.Location,
+		Category:         Category,
+		Message:          message,
+		SecondaryMessage: secondaryMessage,
+		Range:            ast.NewRangeFromPositioned(element),
+	})
+}
@@ -0,0 +1,187 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package determinism
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func analyze(t *testing.T, analyzer *analysis.Analyzer, code string) []analysis.Diagnostic {
+	location := common.StringLocation("test")
+
+	config := &analysis.Config{
+		ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	var diagnostics []analysis.Diagnostic
+
+	programs[location.ID()].Run(
+		[]*analysis.Analyzer{analyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	return diagnostics
+}
+
+func diagnosticMessages(diagnostics []analysis.Diagnostic) []string {
+	messages := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		messages[i] = diagnostic.Message
+	}
+	return messages
+}
+
+const code = `
+  pub resource interface Receiver {
+      pub fun receive()
+  }
+
+  pub fun random(): UInt64 {
+      return unsafeRandom()
+  }
+
+  pub fun timestamp(): UFix64 {
+      return getCurrentBlock().timestamp
+  }
+
+  pub fun receive(capability: Capability<&{Receiver}>) {
+      capability.borrow()!.receive()
+  }
+
+  pub fun deterministic(): Int {
+      return 1 + 2
+  }
+`
+
+func TestAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t, Analyzer, code)
+
+	assert.Equal(t,
+		[]string{
+			"call of `unsafeRandom` is not deterministic",
+			"access of block information `timestamp` depends on the execution context",
+			"borrowing a capability depends on the state of another account",
+		},
+		diagnosticMessages(diagnostics),
+	)
+
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, Category, diagnostic.Category)
+		assert.Equal(t, common.StringLocation("test"), diagnostic.Location)
+	}
+
+	assert.Equal(t,
+		ast.Range{
+			StartPos: ast.Position{Offset: 108, Line: 7, Column: 13},
+			EndPos:   ast.Position{Offset: 121, Line: 7, Column: 26},
+		},
+		diagnostics[0].Range,
+	)
+}
+
+func TestAnalyzerConfig(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("ignore constructs", func(t *testing.T) {
+
+		t.Parallel()
+
+		analyzer := NewAnalyzer(Config{
+			IgnoreUnsafeRandom:      true,
+			IgnoreCapabilityBorrows: true,
+		})
+
+		diagnostics := analyze(t, analyzer, code)
+
+		assert.Equal(t,
+			[]string{
+				"access of block information `timestamp` depends on the execution context",
+			},
+			diagnosticMessages(diagnostics),
+		)
+	})
+
+	t.Run("replayable functions", func(t *testing.T) {
+
+		t.Parallel()
+
+		analyzer := NewAnalyzer(Config{
+			IsReplayable: func(declaration *ast.FunctionDeclaration) bool {
+				return declaration.Identifier.Identifier == "random"
+			},
+		})
+
+		diagnostics := analyze(t, analyzer, code)
+
+		assert.Equal(t,
+			[]string{
+				"call of `unsafeRandom` is not deterministic",
+			},
+			diagnosticMessages(diagnostics),
+		)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+
+		t.Parallel()
+
+		analyzer := NewAnalyzer(Config{
+			IsReplayable: func(declaration *ast.FunctionDeclaration) bool {
+				return declaration.Identifier.Identifier == "execute"
+			},
+		})
+
+		diagnostics := analyze(t, analyzer, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let height = getCurrentBlock().height
+              }
+
+              execute {
+                  let random = unsafeRandom()
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{
+				"call of `unsafeRandom` is not deterministic",
+			},
+			diagnosticMessages(diagnostics),
+		)
+	})
+}
@@ -0,0 +1,131 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// Config specifies how programs are loaded
+//
+type Config struct {
+	// ResolveCode returns the code of the given location,
+	// which is imported by the given importing location,
+	// or which is to be loaded, in which case the importing location is nil
+	ResolveCode func(
+		location common.Location,
+		importingLocation common.Location,
+		importRange ast.Range,
+	) (string, error)
+	// PositionInfoEnabled specifies if position information,
+	// like the occurrences of declarations, is recorded while checking
+	PositionInfoEnabled bool
+}
+
+var valueDeclarations = append(
+	stdlib.FlowBuiltInFunctions(stdlib.DefaultFlowBuiltinImpls()),
+	stdlib.BuiltinFunctions...,
+).ToSemaValueDeclarations()
+
+var typeDeclarations = append(
+	stdlib.FlowBuiltInTypes,
+	stdlib.BuiltinTypes...,
+).ToTypeDeclarations()
+
+// Load parses and checks the programs with the given locations,
+// and all programs they import
+//
+func Load(config *Config, locations ...common.Location) (Programs, error) {
+	programs := Programs{}
+
+	for _, location := range locations {
+		_, err := programs.load(config, location, nil, ast.Range{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return programs, nil
+}
+
+func (programs Programs) load(
+	config *Config,
+	location common.Location,
+	importingLocation common.Location,
+	importRange ast.Range,
+) (*Program, error) {
+
+	if program, ok := programs[location.ID()]; ok {
+		return program, nil
+	}
+
+	code, err := config.ResolveCode(location, importingLocation, importRange)
+	if err != nil {
+		return nil, err
+	}
+
+	astProgram, err := parser2.ParseProgram(code)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := sema.NewChecker(
+		astProgram,
+		location,
+		sema.WithPredeclaredValues(valueDeclarations),
+		sema.WithPredeclaredTypes(typeDeclarations),
+		sema.WithPositionInfoEnabled(config.PositionInfoEnabled),
+		sema.WithImportHandler(
+			func(checker *sema.Checker, importedLocation common.Location, importRange ast.Range) (sema.Import, error) {
+				importedProgram, err := programs.load(config, importedLocation, location, importRange)
+				if err != nil {
+					return nil, err
+				}
+
+				return sema.ElaborationImport{
+					Elaboration: importedProgram.Elaboration,
+				}, nil
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	program := &Program{
+		Location:    location,
+		Code:        code,
+		Program:     astProgram,
+		Elaboration: checker.Elaboration,
+		Occurrences: checker.Occurrences,
+	}
+
+	programs[location.ID()] = program
+
+	return program, nil
+}
@@ -0,0 +1,312 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package overflow provides an analyzer which reports arithmetic operations
+// on user-controlled values which might overflow, for contract audits.
+//
+// An overflow of a fixed-size integer or fixed-point operation aborts the transaction.
+// A value is considered user-controlled if it is a parameter of the enclosing function
+// or transaction, or a member of such a parameter, e.g. `vault.balance`.
+// A parameter is considered bounds-checked if it is compared using `<`, `<=`, `>`, or `>=`
+// before the operation, e.g. in a pre-condition.
+//
+package overflow
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const Category = "overflow"
+
+// Config configures the analyzer
+//
+type Config struct {
+	// SuggestSaturatingArithmetic enables suggesting the saturating variant
+	// of a reported operation, e.g. `a.saturatingAdd(b)` for `a + b`
+	SuggestSaturatingArithmetic bool
+}
+
+// Analyzer reports overflow-prone arithmetic operations on user-controlled values
+//
+var Analyzer = NewAnalyzer(Config{})
+
+// NewAnalyzer returns a new overflow analyzer with the given configuration
+//
+func NewAnalyzer(config Config) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Description: "Detects overflow-prone arithmetic on user-controlled values which are not bounds-checked",
+		Run: func(pass *analysis.Pass) interface{} {
+			for _, declaration := range pass.Program.Program.Declarations() {
+				ast.Inspect(declaration, func(element ast.Element) bool {
+					inspect(config, pass, element)
+					return true
+				})
+			}
+			return nil
+		},
+	}
+}
+
+func inspect(config Config, pass *analysis.Pass, element ast.Element) {
+	switch element := element.(type) {
+	case *ast.FunctionDeclaration:
+		checkFunction(config, pass, element.ParameterList, element.FunctionBlock)
+
+	case *ast.SpecialFunctionDeclaration:
+		checkFunction(config, pass, element.FunctionDeclaration.ParameterList, element.FunctionDeclaration.FunctionBlock)
+
+	case *ast.FunctionExpression:
+		checkFunction(config, pass, element.ParameterList, element.FunctionBlock)
+
+	case *ast.TransactionDeclaration:
+		// The parameters of a transaction are user-controlled
+		// in all phases of the transaction
+
+		var functionBlocks []*ast.FunctionBlock
+		for _, function := range []*ast.SpecialFunctionDeclaration{element.Prepare, element.Execute} {
+			if function != nil && function.FunctionDeclaration.FunctionBlock != nil {
+				functionBlocks = append(functionBlocks, function.FunctionDeclaration.FunctionBlock)
+			}
+		}
+
+		checkParameters(
+			config,
+			pass,
+			element.ParameterList,
+			[]*ast.Conditions{element.PreConditions, element.PostConditions},
+			functionBlocks,
+		)
+	}
+}
+
+func checkFunction(
+	config Config,
+	pass *analysis.Pass,
+	parameterList *ast.ParameterList,
+	functionBlock *ast.FunctionBlock,
+) {
+	if functionBlock == nil {
+		return
+	}
+
+	checkParameters(
+		config,
+		pass,
+		parameterList,
+		nil,
+		[]*ast.FunctionBlock{functionBlock},
+	)
+}
+
+// checkParameters reports the overflow-prone operations on the given parameters
+// in the given conditions and function blocks.
+// Nested functions are not analyzed, they are analyzed separately
+//
+func checkParameters(
+	config Config,
+	pass *analysis.Pass,
+	parameterList *ast.ParameterList,
+	conditions []*ast.Conditions,
+	functionBlocks []*ast.FunctionBlock,
+) {
+	if parameterList == nil || len(parameterList.Parameters) == 0 {
+		return
+	}
+
+	parameters := parameterList.ParametersByIdentifier()
+
+	var elements []ast.Element
+	addConditions := func(conditions *ast.Conditions) {
+		if conditions == nil {
+			return
+		}
+		for _, condition := range *conditions {
+			elements = append(elements, condition.Test)
+		}
+	}
+
+	for _, functionBlock := range functionBlocks {
+		addConditions(functionBlock.PreConditions)
+		elements = append(elements, functionBlock.Block)
+		addConditions(functionBlock.PostConditions)
+	}
+	for _, conditions := range conditions {
+		addConditions(conditions)
+	}
+
+	inspectFunction := func(f func(*ast.BinaryExpression)) {
+		for _, element := range elements {
+			ast.Inspect(element, func(element ast.Element) bool {
+				switch element := element.(type) {
+				case *ast.FunctionDeclaration, *ast.FunctionExpression:
+					return false
+
+				case *ast.BinaryExpression:
+					f(element)
+				}
+				return true
+			})
+		}
+	}
+
+	// Find the earliest bounds check for each parameter
+
+	boundsChecks := map[string]int{}
+
+	inspectFunction(func(expression *ast.BinaryExpression) {
+		switch expression.Operation {
+		case ast.OperationLess,
+			ast.OperationLessEqual,
+			ast.OperationGreater,
+			ast.OperationGreaterEqual:
+
+			offset := expression.StartPosition().Offset
+
+			for _, operand := range []ast.Expression{expression.Left, expression.Right} {
+				name := rootIdentifier(operand)
+				if _, ok := parameters[name]; !ok {
+					continue
+				}
+				if previousOffset, ok := boundsChecks[name]; !ok || offset < previousOffset {
+					boundsChecks[name] = offset
+				}
+			}
+		}
+	})
+
+	// Report the operations on parameters which are not bounds-checked before
+
+	inspectFunction(func(expression *ast.BinaryExpression) {
+		operation, ok := arithmeticOperations[expression.Operation]
+		if !ok {
+			return
+		}
+
+		resultType, ok := pass.Program.Elaboration.BinaryExpressionResultTypes[expression].(sema.SaturatingArithmeticType)
+		if !ok || !operation.mayOverflow(resultType) {
+			return
+		}
+
+		offset := expression.StartPosition().Offset
+
+		for _, operand := range []ast.Expression{expression.Left, expression.Right} {
+			name := rootIdentifier(operand)
+			if _, ok := parameters[name]; !ok {
+				continue
+			}
+			if checkOffset, ok := boundsChecks[name]; ok && checkOffset < offset {
+				continue
+			}
+
+			report(config, pass, expression, operation, name)
+			return
+		}
+	})
+}
+
+type arithmeticOperation struct {
+	description            string
+	saturatingFunctionName string
+	// mayOverflow returns true if the operation aborts on overflow for the given type.
+	// Exactly the operations which abort on overflow have a saturating variant,
+	// e.g. operations on arbitrary-precision and word types never abort
+	mayOverflow func(sema.SaturatingArithmeticType) bool
+}
+
+var arithmeticOperations = map[ast.Operation]arithmeticOperation{
+	ast.OperationPlus: {
+		description:            "addition",
+		saturatingFunctionName: sema.NumericTypeSaturatingAddFunctionName,
+		mayOverflow:            sema.SaturatingArithmeticType.SupportsSaturatingAdd,
+	},
+	ast.OperationMinus: {
+		description:            "subtraction",
+		saturatingFunctionName: sema.NumericTypeSaturatingSubtractFunctionName,
+		mayOverflow:            sema.SaturatingArithmeticType.SupportsSaturatingSubtract,
+	},
+	ast.OperationMul: {
+		description:            "multiplication",
+		saturatingFunctionName: sema.NumericTypeSaturatingMultiplyFunctionName,
+		mayOverflow:            sema.SaturatingArithmeticType.SupportsSaturatingMultiply,
+	},
+}
+
+// rootIdentifier returns the name of the variable the given expression accesses,
+// e.g. `vault` for `vault.balance`, or the empty string if there is none
+//
+func rootIdentifier(expression ast.Expression) string {
+	for {
+		switch typedExpression := expression.(type) {
+		case *ast.IdentifierExpression:
+			return typedExpression.Identifier.Identifier
+
+		case *ast.MemberExpression:
+			expression = typedExpression.Expression
+
+		case *ast.IndexExpression:
+			expression = typedExpression.TargetExpression
+
+		case *ast.ForceExpression:
+			expression = typedExpression.Expression
+
+		case *ast.CastingExpression:
+			expression = typedExpression.Expression
+
+		default:
+			return ""
+		}
+	}
+}
+
+func report(
+	config Config,
+	pass *analysis.Pass,
+	expression *ast.BinaryExpression,
+	operation arithmeticOperation,
+	name string,
+) {
+	secondaryMessage := fmt.Sprintf(
+		"an overflow aborts the transaction, consider checking the bounds of `%s` first",
+		name,
+	)
+
+	if config.SuggestSaturatingArithmetic {
+		secondaryMessage += fmt.Sprintf(
+			", or using `%s.%s(%s)`",
+			expression.Left,
+			operation.saturatingFunctionName,
+			expression.Right,
+		)
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Location: pass.Program.Location,
+		Category: Category,
+		Message: fmt.Sprintf(
+			"%s with user-controlled `%s` may overflow",
+			operation.description,
+			name,
+		),
+		SecondaryMessage: secondaryMessage,
+		Range:            ast.NewRangeFromPositioned(expression),
+	})
+}
@@ -0,0 +1,189 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package overflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func analyze(t *testing.T, analyzer *analysis.Analyzer, code string) []analysis.Diagnostic {
+	location := common.StringLocation("test")
+
+	config := &analysis.Config{
+		ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	var diagnostics []analysis.Diagnostic
+
+	programs[location.ID()].Run(
+		[]*analysis.Analyzer{analyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	return diagnostics
+}
+
+func diagnosticMessages(diagnostics []analysis.Diagnostic) []string {
+	messages := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		messages[i] = diagnostic.Message
+	}
+	return messages
+}
+
+const code = `
+  pub resource Vault {
+      pub var balance: UFix64
+
+      init() {
+          self.balance = 0.0
+      }
+
+      pub fun deposit(from: @Vault) {
+          self.balance = self.balance + from.balance
+          destroy from
+      }
+
+      pub fun withdraw(amount: UFix64) {
+          pre {
+              amount <= self.balance
+          }
+          self.balance = self.balance - amount
+      }
+  }
+
+  pub fun fee(amount: UInt64, rate: UInt64): UInt64 {
+      return amount * rate
+  }
+
+  pub fun checkedFee(amount: UInt64): UInt64 {
+      if amount > 1000 {
+          panic("amount too large")
+      }
+      return amount * 2
+  }
+
+  pub fun lateCheck(amount: UInt8): UInt8 {
+      let sum = amount + 1
+      assert(amount < 100)
+      return sum
+  }
+
+  pub fun unbounded(amount: Int, word: Word8): Int {
+      let x = word + 1
+      return amount * 2
+  }
+`
+
+func TestAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t, Analyzer, code)
+
+	assert.Equal(t,
+		[]string{
+			"addition with user-controlled `from` may overflow",
+			"multiplication with user-controlled `amount` may overflow",
+			"addition with user-controlled `amount` may overflow",
+		},
+		diagnosticMessages(diagnostics),
+	)
+
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, Category, diagnostic.Category)
+		assert.Equal(t, common.StringLocation("test"), diagnostic.Location)
+	}
+
+	assert.Equal(t,
+		"an overflow aborts the transaction, consider checking the bounds of `from` first",
+		diagnostics[0].SecondaryMessage,
+	)
+}
+
+func TestAnalyzerConfig(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("suggest saturating arithmetic", func(t *testing.T) {
+
+		t.Parallel()
+
+		analyzer := NewAnalyzer(Config{
+			SuggestSaturatingArithmetic: true,
+		})
+
+		diagnostics := analyze(t, analyzer, `
+          pub fun fee(amount: UInt64, rate: UInt64): UInt64 {
+              return amount * rate
+          }
+        `)
+
+		require.Len(t, diagnostics, 1)
+
+		assert.Equal(t,
+			"an overflow aborts the transaction, consider checking the bounds of `amount` first, "+
+				"or using `amount.saturatingMultiply(rate)`",
+			diagnostics[0].SecondaryMessage,
+		)
+	})
+}
+
+func TestAnalyzerTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t, Analyzer, `
+      transaction(amount: UInt64, count: UInt64) {
+
+          prepare(signer: AuthAccount) {
+              let total = amount * 2
+          }
+
+          pre {
+              count < 10
+          }
+
+          execute {
+              let fee = count + 1
+          }
+      }
+    `)
+
+	assert.Equal(t,
+		[]string{
+			"multiplication with user-controlled `amount` may overflow",
+		},
+		diagnosticMessages(diagnostics),
+	)
+}
@@ -0,0 +1,71 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Program is a parsed and checked program
+//
+type Program struct {
+	Location    common.Location
+	Code        string
+	Program     *ast.Program
+	Elaboration *sema.Elaboration
+	// Occurrences are the occurrences of declarations in the program,
+	// including their role (e.g. read, write, move, destroy).
+	// They are only recorded if Config.PositionInfoEnabled is set
+	Occurrences *sema.Occurrences
+}
+
+// Programs are the loaded programs, by location
+//
+type Programs map[common.LocationID]*Program
+
+// Run runs the given analyzers on the program,
+// after running the analyzers they require,
+// and reports the diagnostics of all of them
+//
+func (program *Program) Run(analyzers []*Analyzer, report func(Diagnostic)) {
+	results := map[*Analyzer]interface{}{}
+
+	var run func(analyzer *Analyzer)
+	run = func(analyzer *Analyzer) {
+		if _, ok := results[analyzer]; ok {
+			return
+		}
+
+		for _, requiredAnalyzer := range analyzer.Requires {
+			run(requiredAnalyzer)
+		}
+
+		results[analyzer] = analyzer.Run(&Pass{
+			Program:  program,
+			Report:   report,
+			ResultOf: results,
+		})
+	}
+
+	for _, analyzer := range analyzers {
+		run(analyzer)
+	}
+}
@@ -0,0 +1,671 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package viewfunctions provides an interprocedural analysis which determines
+// the read-only functions of a set of programs, e.g. deployed contracts,
+// and exports the static call graph of the functions.
+//
+// A function is read-only if no path through it writes to storage or emits an event,
+// including the paths through all functions it calls.
+// The results of read-only public functions may be served as cached queries, e.g. by access nodes.
+//
+// The analysis is conservative: a function is not read-only if it calls a function
+// which cannot be resolved statically, e.g. a function value, an interface function,
+// or a function of a program which is not analyzed.
+//
+package viewfunctions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// Function is a function of the analyzed programs
+//
+type Function struct {
+	// ID identifies the function, e.g. `A.0000000000000001.Token.Vault.getBalance`
+	ID string `json:"id"`
+	// Location is the location of the program which declares the function
+	Location common.LocationID `json:"location"`
+	// Name is the qualified name of the function, e.g. `Token.Vault.getBalance`
+	Name string `json:"name"`
+	// Public is true if the function has public access
+	Public bool `json:"public"`
+	// ReadOnly is true if no path through the function writes to storage or emits an event
+	ReadOnly bool `json:"readOnly"`
+	// Reason describes why the function is not read-only
+	Reason string `json:"reason,omitempty"`
+	// Calls are the IDs of the analyzed functions which the function calls
+	Calls []string `json:"calls"`
+	ast.Range
+}
+
+// Report is the result of the analysis of a set of programs
+//
+type Report struct {
+	// Functions are all functions of the programs, sorted by ID
+	Functions []Function `json:"functions"`
+}
+
+// ReadOnlyPublicFunctions returns the IDs of the public functions which are read-only
+//
+func (r *Report) ReadOnlyPublicFunctions() []string {
+	ids := []string{}
+	for _, function := range r.Functions {
+		if function.Public && function.ReadOnly {
+			ids = append(ids, function.ID)
+		}
+	}
+	return ids
+}
+
+// readOnlyAuthAccountFunctions are the functions of `AuthAccount`
+// and its nested types which do not write to storage
+//
+var readOnlyAuthAccountFunctions = map[string]struct{}{
+	sema.AuthAccountTypeField:              {},
+	sema.AuthAccountCopyField:              {},
+	sema.AuthAccountBorrowField:            {},
+	sema.AuthAccountGetCapabilityField:     {},
+	sema.AuthAccountGetLinkTargetField:     {},
+	sema.AuthAccountStorageUsedByPathField: {},
+	sema.AuthAccountCopyTransientField:     {},
+	"get":                                  {},
+}
+
+// mutatingContainerFunctions are the functions of arrays and dictionaries
+// which mutate the container
+//
+var mutatingContainerFunctions = map[string]struct{}{
+	"append":      {},
+	"appendAll":   {},
+	"insert":      {},
+	"remove":      {},
+	"removeFirst": {},
+	"removeLast":  {},
+}
+
+type function struct {
+	Function
+	program       *analysis.Program
+	parameterList *ast.ParameterList
+	functionType  *sema.FunctionType
+	functionBlock *ast.FunctionBlock
+	// isInitializer is true if the function is an initializer,
+	// in which assignments to fields of `self` initialize a new value
+	isInitializer bool
+	// effect describes the first effect of the function itself, if any
+	effect string
+	calls  map[string]struct{}
+}
+
+type analyzer struct {
+	functions map[string]*function
+	// compositeTypeIDs are the IDs of the composite types declared by the analyzed programs
+	compositeTypeIDs map[sema.TypeID]struct{}
+}
+
+// Analyze determines the read-only functions of the given programs,
+// which are analyzed together, i.e. calls between them are resolved
+//
+func Analyze(programs analysis.Programs) *Report {
+	a := &analyzer{
+		functions:        map[string]*function{},
+		compositeTypeIDs: map[sema.TypeID]struct{}{},
+	}
+
+	locationIDs := make([]common.LocationID, 0, len(programs))
+	for locationID := range programs { //nolint:maprangecheck
+		locationIDs = append(locationIDs, locationID)
+	}
+	sort.Slice(locationIDs, func(i, j int) bool {
+		return locationIDs[i] < locationIDs[j]
+	})
+
+	for _, locationID := range locationIDs {
+		a.declareProgramFunctions(programs[locationID])
+	}
+
+	ids := make([]string, 0, len(a.functions))
+	for id := range a.functions { //nolint:maprangecheck
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		a.analyzeFunction(a.functions[id])
+	}
+
+	a.propagate(ids)
+
+	report := &Report{
+		Functions: make([]Function, 0, len(ids)),
+	}
+
+	for _, id := range ids {
+		function := a.functions[id]
+
+		calls := make([]string, 0, len(function.calls))
+		for callee := range function.calls { //nolint:maprangecheck
+			calls = append(calls, callee)
+		}
+		sort.Strings(calls)
+
+		result := function.Function
+		result.Calls = calls
+		report.Functions = append(report.Functions, result)
+	}
+
+	return report
+}
+
+func (a *analyzer) declareProgramFunctions(program *analysis.Program) {
+	elaboration := program.Elaboration
+	location := program.Location
+
+	for _, declaration := range program.Program.FunctionDeclarations() {
+		name := declaration.Identifier.Identifier
+		a.declareFunction(
+			program,
+			string(location.TypeID(name)),
+			name,
+			isPublic(declaration.Access),
+			declaration,
+			declaration.ParameterList,
+			elaboration.FunctionDeclarationFunctionTypes[declaration],
+			declaration.FunctionBlock,
+		)
+	}
+
+	var declareComposite func(declaration *ast.CompositeDeclaration)
+	declareComposite = func(declaration *ast.CompositeDeclaration) {
+		compositeType := elaboration.CompositeDeclarationTypes[declaration]
+
+		// Events only have a synthesized initializer
+
+		if compositeType == nil ||
+			compositeType.Kind == common.CompositeKindEvent {
+
+			return
+		}
+
+		a.compositeTypeIDs[compositeType.ID()] = struct{}{}
+
+		for _, functionDeclaration := range declaration.Members.Functions() {
+			name := functionDeclaration.Identifier.Identifier
+			a.declareFunction(
+				program,
+				memberFunctionID(compositeType, name),
+				compositeType.QualifiedIdentifier()+"."+name,
+				isPublic(functionDeclaration.Access),
+				functionDeclaration,
+				functionDeclaration.ParameterList,
+				elaboration.FunctionDeclarationFunctionTypes[functionDeclaration],
+				functionDeclaration.FunctionBlock,
+			)
+		}
+
+		for _, specialFunction := range declaration.Members.SpecialFunctions() {
+			name := specialFunction.Kind.Keywords()
+			functionDeclaration := specialFunction.FunctionDeclaration
+			a.declareFunction(
+				program,
+				memberFunctionID(compositeType, name),
+				compositeType.QualifiedIdentifier()+"."+name,
+				false,
+				specialFunction,
+				functionDeclaration.ParameterList,
+				elaboration.ConstructorFunctionTypes[specialFunction],
+				functionDeclaration.FunctionBlock,
+			)
+
+			if specialFunction.Kind == common.DeclarationKindInitializer {
+				a.functions[memberFunctionID(compositeType, name)].isInitializer = true
+			}
+		}
+
+		for _, nestedDeclaration := range declaration.Members.Composites() {
+			declareComposite(nestedDeclaration)
+		}
+	}
+
+	for _, declaration := range program.Program.CompositeDeclarations() {
+		declareComposite(declaration)
+	}
+}
+
+func (a *analyzer) declareFunction(
+	program *analysis.Program,
+	id string,
+	name string,
+	public bool,
+	declaration ast.HasPosition,
+	parameterList *ast.ParameterList,
+	functionType *sema.FunctionType,
+	functionBlock *ast.FunctionBlock,
+) {
+	a.functions[id] = &function{
+		Function: Function{
+			ID:       id,
+			Location: program.Location.ID(),
+			Name:     name,
+			Public:   public,
+			ReadOnly: true,
+			Range:    ast.NewRangeFromPositioned(declaration),
+		},
+		program:       program,
+		parameterList: parameterList,
+		functionType:  functionType,
+		functionBlock: functionBlock,
+		calls:         map[string]struct{}{},
+	}
+}
+
+func memberFunctionID(containerType sema.Type, name string) string {
+	return string(containerType.ID()) + "." + name
+}
+
+func isPublic(access ast.Access) bool {
+	return access == ast.AccessPublic ||
+		access == ast.AccessPublicSettable
+}
+
+// propagate marks all functions as not read-only which have an effect,
+// or which call a function that is not read-only
+//
+func (a *analyzer) propagate(ids []string) {
+
+	callers := map[string][]string{}
+
+	var worklist []*function
+
+	for _, id := range ids {
+		function := a.functions[id]
+
+		for callee := range function.calls { //nolint:maprangecheck
+			callers[callee] = append(callers[callee], id)
+		}
+
+		if function.effect != "" {
+			function.ReadOnly = false
+			function.Reason = function.effect
+			worklist = append(worklist, function)
+		}
+	}
+
+	for len(worklist) > 0 {
+		callee := worklist[0]
+		worklist = worklist[1:]
+
+		// NOTE: the callers are sorted, as the IDs are
+
+		for _, callerID := range callers[callee.ID] {
+			caller := a.functions[callerID]
+			if !caller.ReadOnly {
+				continue
+			}
+
+			caller.ReadOnly = false
+			caller.Reason = fmt.Sprintf("calls `%s`, which is not read-only", callee.Name)
+			worklist = append(worklist, caller)
+		}
+	}
+}
+
+// functionAnalysis is the analysis of the body of a single function
+//
+type functionAnalysis struct {
+	*analyzer
+	function    *function
+	elaboration *sema.Elaboration
+	// locals are the types of the parameters and variables declared in the function.
+	// The type is nil for nested function declarations, which are analyzed as part of the function
+	locals map[string]sema.Type
+	// parameters are the names of the parameters of the function and of nested functions,
+	// which might have function values which are not known statically
+	parameters map[string]struct{}
+}
+
+func (a *analyzer) analyzeFunction(function *function) {
+	if function.functionBlock == nil {
+		return
+	}
+
+	analysis := &functionAnalysis{
+		analyzer:    a,
+		function:    function,
+		elaboration: function.program.Elaboration,
+		locals:      map[string]sema.Type{},
+		parameters:  map[string]struct{}{},
+	}
+
+	analysis.declareParameters(function.parameterList, function.functionType)
+
+	// NOTE: conditions are not walked as part of the function block
+
+	var elements []ast.Element
+	for _, conditions := range []*ast.Conditions{
+		function.functionBlock.PreConditions,
+		function.functionBlock.PostConditions,
+	} {
+		if conditions == nil {
+			continue
+		}
+		for _, condition := range *conditions {
+			elements = append(elements, condition.Test)
+			if condition.Message != nil {
+				elements = append(elements, condition.Message)
+			}
+		}
+	}
+	elements = append(elements, function.functionBlock.Block)
+
+	for _, element := range elements {
+		ast.Inspect(element, analysis.declareLocals)
+	}
+
+	for _, element := range elements {
+		ast.Inspect(element, analysis.inspect)
+	}
+}
+
+func (f *functionAnalysis) declareParameters(parameterList *ast.ParameterList, functionType *sema.FunctionType) {
+	if parameterList == nil {
+		return
+	}
+
+	for i, parameter := range parameterList.Parameters {
+		name := parameter.Identifier.Identifier
+
+		var parameterType sema.Type
+		if functionType != nil && i < len(functionType.Parameters) {
+			parameterType = functionType.Parameters[i].TypeAnnotation.Type
+		}
+
+		f.locals[name] = parameterType
+		f.parameters[name] = struct{}{}
+	}
+}
+
+func (f *functionAnalysis) declareLocals(element ast.Element) bool {
+	switch element := element.(type) {
+	case *ast.VariableDeclaration:
+		f.locals[element.Identifier.Identifier] = f.elaboration.VariableDeclarationTargetTypes[element]
+
+	case *ast.FunctionDeclaration:
+		f.locals[element.Identifier.Identifier] = nil
+		f.declareParameters(
+			element.ParameterList,
+			f.elaboration.FunctionDeclarationFunctionTypes[element],
+		)
+
+	case *ast.FunctionExpression:
+		f.declareParameters(
+			element.ParameterList,
+			f.elaboration.FunctionExpressionFunctionType[element],
+		)
+	}
+
+	return true
+}
+
+func (f *functionAnalysis) inspect(element ast.Element) bool {
+	switch element := element.(type) {
+	case *ast.EmitStatement:
+		eventName := "an event"
+		if eventType := f.elaboration.EmitStatementEventTypes[element]; eventType != nil {
+			eventName = fmt.Sprintf("`%s`", eventType.QualifiedIdentifier())
+		}
+		f.reportEffect(fmt.Sprintf("emits %s", eventName))
+
+	case *ast.AssignmentStatement:
+		if f.isStateExpression(element.Target) {
+			f.reportEffect("writes to a field or stored value")
+		}
+
+	case *ast.SwapStatement:
+		if f.isStateExpression(element.Left) || f.isStateExpression(element.Right) {
+			f.reportEffect("writes to a field or stored value")
+		}
+
+	case *ast.DestroyExpression:
+		// NOTE: the destroyed resource's type is unknown,
+		// so its destructor might emit events or write to storage
+		f.reportEffect("destroys a resource")
+
+	case *ast.InvocationExpression:
+		f.inspectInvocation(element)
+	}
+
+	return true
+}
+
+func (f *functionAnalysis) reportEffect(effect string) {
+	if f.function.effect != "" {
+		return
+	}
+	f.function.effect = effect
+}
+
+func (f *functionAnalysis) addCall(id string) {
+	f.function.calls[id] = struct{}{}
+}
+
+func (f *functionAnalysis) inspectInvocation(invocationExpression *ast.InvocationExpression) {
+	switch invokedExpression := invocationExpression.InvokedExpression.(type) {
+	case *ast.IdentifierExpression:
+		f.inspectIdentifierInvocation(invokedExpression)
+
+	case *ast.MemberExpression:
+		f.inspectMemberInvocation(invokedExpression)
+
+	default:
+		f.reportEffect("calls a function value which is not known statically")
+	}
+}
+
+func (f *functionAnalysis) inspectIdentifierInvocation(identifierExpression *ast.IdentifierExpression) {
+	name := identifierExpression.Identifier.Identifier
+
+	// Nested functions are analyzed as part of the function,
+	// but the values of parameters and other variables are unknown
+
+	if localType, ok := f.locals[name]; ok {
+		_, isParameter := f.parameters[name]
+		if localType != nil || isParameter {
+			f.reportEffect(fmt.Sprintf("calls the function value `%s`, which is not known statically", name))
+		}
+		return
+	}
+
+	// Constructors may also be of nested composites, which are not global values
+
+	invokedType := f.elaboration.IdentifierInInvocationTypes[identifierExpression]
+	if functionType, ok := invokedType.(*sema.FunctionType); ok && functionType.IsConstructor {
+		compositeType, ok := functionType.ReturnTypeAnnotation.Type.(*sema.CompositeType)
+		if ok {
+			f.addConstructorCall(compositeType)
+			return
+		}
+	}
+
+	variable, ok := f.elaboration.GlobalValues.Get(name)
+	if !ok {
+		// The function is predeclared, e.g. a built-in function.
+		// Only the `AuthAccount` constructor, which creates an account, writes to storage
+
+		if name == sema.AuthAccountType.Identifier {
+			f.reportEffect("creates an account")
+		}
+		return
+	}
+
+	if variable.DeclarationKind == common.DeclarationKindFunction {
+		id := string(f.function.program.Location.TypeID(name))
+		if _, ok := f.functions[id]; ok {
+			f.addCall(id)
+			return
+		}
+	}
+
+	f.reportEffect(fmt.Sprintf("calls `%s`, which is not known statically", name))
+}
+
+func (f *functionAnalysis) addConstructorCall(compositeType *sema.CompositeType) {
+	if compositeType.Location == nil {
+		// Built-in composite
+		return
+	}
+
+	if _, ok := f.compositeTypeIDs[compositeType.ID()]; !ok {
+		f.reportEffect(fmt.Sprintf(
+			"creates `%s`, which is not in the analyzed programs",
+			compositeType.QualifiedIdentifier(),
+		))
+		return
+	}
+
+	// The composite might not have an initializer
+
+	id := memberFunctionID(compositeType, common.DeclarationKindInitializer.Keywords())
+	if _, ok := f.functions[id]; ok {
+		f.addCall(id)
+	}
+}
+
+func (f *functionAnalysis) inspectMemberInvocation(memberExpression *ast.MemberExpression) {
+	memberInfo, ok := f.elaboration.MemberExpressionMemberInfos[memberExpression]
+	if !ok || memberInfo.Member == nil {
+		f.reportEffect("calls a function which is not known statically")
+		return
+	}
+
+	member := memberInfo.Member
+	name := member.Identifier.Identifier
+
+	if member.DeclarationKind != common.DeclarationKindFunction {
+		f.reportEffect(fmt.Sprintf("calls the function value `%s`, which is not known statically", name))
+		return
+	}
+
+	switch containerType := member.ContainerType.(type) {
+	case *sema.CompositeType:
+		if isAuthAccountType(containerType) {
+			if _, ok := readOnlyAuthAccountFunctions[name]; !ok {
+				f.reportEffect(fmt.Sprintf("writes to storage using `%s.%s`", containerType.QualifiedIdentifier(), name))
+			}
+			return
+		}
+
+		if containerType.Location == nil {
+			// Other built-in composites, e.g. `PublicAccount`, do not write to storage
+			return
+		}
+
+		id := memberFunctionID(containerType, name)
+		if _, ok := f.functions[id]; ok {
+			f.addCall(id)
+			return
+		}
+
+		f.reportEffect(fmt.Sprintf(
+			"calls `%s.%s`, which is not in the analyzed programs",
+			containerType.QualifiedIdentifier(),
+			name,
+		))
+
+	case *sema.InterfaceType:
+		f.reportEffect(fmt.Sprintf(
+			"calls the interface function `%s.%s`, which might be implemented by any program",
+			containerType.QualifiedIdentifier(),
+			name,
+		))
+
+	case sema.ArrayType, *sema.DictionaryType:
+		if _, ok := mutatingContainerFunctions[name]; ok &&
+			f.isStateExpression(memberExpression.Expression) {
+
+			f.reportEffect(fmt.Sprintf("mutates a field or stored value using `%s`", name))
+		}
+
+	default:
+		// Functions of other built-in types, e.g. `String.concat`,
+		// do not write to storage
+	}
+}
+
+func isAuthAccountType(compositeType *sema.CompositeType) bool {
+	for ty := sema.Type(compositeType); ty != nil; {
+		if ty == sema.AuthAccountType {
+			return true
+		}
+		containerType, ok := ty.(sema.ContainedType)
+		if !ok {
+			return false
+		}
+		ty = containerType.GetContainerType()
+	}
+	return false
+}
+
+// isStateExpression returns true if the given expression might refer to state
+// which outlives the function, e.g. a field of `self`, or a value referenced by a reference.
+// Assigning to or mutating such state is a write
+//
+func (f *functionAnalysis) isStateExpression(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		name := expression.Identifier.Identifier
+
+		if localType, ok := f.locals[name]; ok {
+			if optionalType, ok := localType.(*sema.OptionalType); ok {
+				localType = optionalType.Type
+			}
+			_, isReference := localType.(*sema.ReferenceType)
+			return isReference
+		}
+
+		if name == sema.SelfIdentifier {
+			return !f.function.isInitializer
+		}
+
+		// Contracts are stored
+
+		variable, ok := f.elaboration.GlobalValues.Get(name)
+		return ok && variable.DeclarationKind == common.DeclarationKindContract
+
+	case *ast.MemberExpression:
+		return f.isStateExpression(expression.Expression)
+
+	case *ast.IndexExpression:
+		return f.isStateExpression(expression.TargetExpression)
+
+	case *ast.ForceExpression:
+		return f.isStateExpression(expression.Expression)
+
+	default:
+		// Other expressions, e.g. the results of invocations,
+		// might be references to stored values
+		return true
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package viewfunctions
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const tokenContract = `
+  pub contract Token {
+
+      pub event Transfer(amount: UFix64)
+
+      pub resource Vault {
+          pub var balance: UFix64
+
+          init(balance: UFix64) {
+              self.balance = balance
+          }
+
+          pub fun getBalance(): UFix64 {
+              return self.balance
+          }
+
+          pub fun withdraw(amount: UFix64): @Vault {
+              self.balance = self.balance - amount
+              return <-create Vault(balance: amount)
+          }
+
+          pub fun transfer(amount: UFix64) {
+              emit Transfer(amount: amount)
+          }
+      }
+
+      pub var total: UFix64
+
+      init() {
+          self.total = 0.0
+      }
+
+      pub fun getTotal(): UFix64 {
+          return self.total
+      }
+
+      pub fun double(_ x: UFix64): UFix64 {
+          return x * 2.0
+      }
+
+      pub fun doubleTotal(): UFix64 {
+          return self.double(self.total)
+      }
+
+      priv fun increase() {
+          self.total = self.total + 1.0
+      }
+
+      pub fun increaseTwice() {
+          self.increase()
+          self.increase()
+      }
+
+      pub fun localArray(): [Int] {
+          let xs: [Int] = []
+          xs.append(1)
+          return xs
+      }
+
+      pub fun apply(_ f: ((): Int)): Int {
+          return f()
+      }
+
+      pub fun countDown(_ n: Int): Int {
+          if n == 0 {
+              return 0
+          }
+          return self.countDown(n - 1)
+      }
+
+      pub fun createVault(): @Vault {
+          return <-create Vault(balance: 0.0)
+      }
+
+      pub fun save(account: AuthAccount) {
+          account.save(<-create Vault(balance: 0.0), to: /storage/vault)
+      }
+
+      pub fun storedBalance(account: AuthAccount): UFix64 {
+          return account.borrow<&Vault>(from: /storage/vault)!.balance
+      }
+
+      pub fun withdrawStored(account: AuthAccount): @Vault {
+          let vault = account.borrow<&Vault>(from: /storage/vault)!
+          return <-vault.withdraw(amount: 1.0)
+      }
+  }
+`
+
+const readerContract = `
+  import Token from "token"
+
+  pub contract Reader {
+
+      pub fun total(): UFix64 {
+          return Token.getTotal()
+      }
+
+      pub fun increase() {
+          Token.increaseTwice()
+      }
+  }
+`
+
+func analyze(t *testing.T) *Report {
+	codes := map[common.Location]string{
+		common.StringLocation("token"):  tokenContract,
+		common.StringLocation("reader"): readerContract,
+	}
+
+	config := &analysis.Config{
+		ResolveCode: func(location common.Location, _ common.Location, _ ast.Range) (string, error) {
+			code, ok := codes[location]
+			if !ok {
+				return "", fmt.Errorf("cannot find code for %s", location)
+			}
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, common.StringLocation("reader"))
+	require.NoError(t, err)
+	require.Len(t, programs, 2)
+
+	return Analyze(programs)
+}
+
+func TestAnalyze(t *testing.T) {
+
+	t.Parallel()
+
+	report := analyze(t)
+
+	functions := map[string]Function{}
+	for _, function := range report.Functions {
+		functions[function.Name] = function
+	}
+
+	type expectation struct {
+		readOnly bool
+		reason   string
+	}
+
+	expectations := map[string]expectation{
+		"Token.init":             {true, ""},
+		"Token.Vault.init":       {true, ""},
+		"Token.Vault.getBalance": {true, ""},
+		"Token.Vault.withdraw":   {false, "writes to a field or stored value"},
+		"Token.Vault.transfer":   {false, "emits `Token.Transfer`"},
+		"Token.getTotal":         {true, ""},
+		"Token.double":           {true, ""},
+		"Token.doubleTotal":      {true, ""},
+		"Token.increase":         {false, "writes to a field or stored value"},
+		"Token.increaseTwice":    {false, "calls `Token.increase`, which is not read-only"},
+		"Token.localArray":       {true, ""},
+		"Token.apply":            {false, "calls the function value `f`, which is not known statically"},
+		"Token.countDown":        {true, ""},
+		"Token.createVault":      {true, ""},
+		"Token.save":             {false, "writes to storage using `AuthAccount.save`"},
+		"Token.storedBalance":    {true, ""},
+		"Token.withdrawStored":   {false, "calls `Token.Vault.withdraw`, which is not read-only"},
+		"Reader.total":           {true, ""},
+		"Reader.increase":        {false, "calls `Token.increaseTwice`, which is not read-only"},
+	}
+
+	require.Len(t, functions, len(expectations))
+
+	for name, expected := range expectations {
+		function, ok := functions[name]
+		require.True(t, ok, name)
+
+		assert.Equal(t, expected.readOnly, function.ReadOnly, name)
+		assert.Equal(t, expected.reason, function.Reason, name)
+	}
+
+	// Calls across programs are resolved
+
+	assert.Equal(t,
+		[]string{"S.token.Token.getTotal"},
+		functions["Reader.total"].Calls,
+	)
+
+	assert.Equal(t,
+		[]string{"S.token.Token.Vault.init"},
+		functions["Token.Vault.withdraw"].Calls,
+	)
+
+	// Private functions and initializers are not public
+
+	assert.Equal(t,
+		[]string{
+			"S.reader.Reader.total",
+			"S.token.Token.Vault.getBalance",
+			"S.token.Token.countDown",
+			"S.token.Token.createVault",
+			"S.token.Token.double",
+			"S.token.Token.doubleTotal",
+			"S.token.Token.getTotal",
+			"S.token.Token.localArray",
+			"S.token.Token.storedBalance",
+		},
+		report.ReadOnlyPublicFunctions(),
+	)
+}
+
+func TestAnalyzeUnknownCallee(t *testing.T) {
+
+	t.Parallel()
+
+	// Only the reader program is analyzed,
+	// so the calls of the token contract's functions cannot be resolved
+
+	config := &analysis.Config{
+		ResolveCode: func(location common.Location, _ common.Location, _ ast.Range) (string, error) {
+			if location == common.StringLocation("token") {
+				return tokenContract, nil
+			}
+			return readerContract, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, common.StringLocation("reader"))
+	require.NoError(t, err)
+
+	delete(programs, common.StringLocation("token").ID())
+
+	report := Analyze(programs)
+
+	require.Len(t, report.Functions, 2)
+
+	total := report.Functions[1]
+	assert.Equal(t, "Reader.total", total.Name)
+	assert.False(t, total.ReadOnly)
+	assert.Equal(t,
+		"calls `Token.getTotal`, which is not in the analyzed programs",
+		total.Reason,
+	)
+}
+
+func TestReportJSON(t *testing.T) {
+
+	t.Parallel()
+
+	report := analyze(t)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Functions []struct {
+			ID       string   `json:"id"`
+			ReadOnly bool     `json:"readOnly"`
+			Calls    []string `json:"calls"`
+		} `json:"functions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	require.Len(t, decoded.Functions, len(report.Functions))
+	assert.Equal(t, "S.reader.Reader.increase", decoded.Functions[0].ID)
+	assert.False(t, decoded.Functions[0].ReadOnly)
+	assert.Equal(t, []string{"S.token.Token.increaseTwice"}, decoded.Functions[0].Calls)
+}
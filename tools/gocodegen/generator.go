@@ -0,0 +1,973 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gocodegen generates Go code for a contract API, see runtime.ExportContractAPI.
+//
+// The generated code contains:
+//
+// - Go types for the structures, resources, and events declared in the contract,
+//   with functions to decode them from Cadence values
+// - For each public field, a function which returns a script which returns the field's value
+// - For each public function with a return value,
+//   a function which returns a script that calls the function
+// - For each public function without a return value,
+//   a function which returns a transaction that calls the function
+//
+// The script and transaction functions encode their arguments,
+// and the results of scripts can be decoded with the generated decoding functions.
+//
+package gocodegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Config configures the generated code
+//
+type Config struct {
+	// PackageName is the name of the Go package of the generated code
+	PackageName string
+	// ContractAddress is the address of the account the contract is deployed to
+	ContractAddress common.Address
+}
+
+type compositeKind struct {
+	name      string
+	valueType string
+}
+
+var (
+	structKind   = compositeKind{name: "structure", valueType: "cadence.Struct"}
+	resourceKind = compositeKind{name: "resource", valueType: "cadence.Resource"}
+	eventKind    = compositeKind{name: "event", valueType: "cadence.Event"}
+)
+
+type composite struct {
+	kind                compositeKind
+	cadenceType         cadence.Type
+	goName              string
+	qualifiedIdentifier string
+	fields              []cadence.Field
+}
+
+type generator struct {
+	config       Config
+	contractName string
+	location     common.AddressLocation
+	composites   map[string]*composite
+	// compositeOrder is the order in which the composites were declared
+	compositeOrder []*composite
+	events         []*composite
+	helpers        map[string]bool
+	declarations   bytes.Buffer
+	helperCode     bytes.Buffer
+	usesBig        bool
+	usesFmt        bool
+	usesJSON       bool
+	usesCommon     bool
+}
+
+// Generate generates the Go code for the given contract API
+//
+func Generate(api *runtime.TypeAPI, config Config) ([]byte, error) {
+	contractName, err := contractName(api.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		config:       config,
+		contractName: contractName,
+		location: common.AddressLocation{
+			Address: config.ContractAddress,
+			Name:    contractName,
+		},
+		composites: map[string]*composite{},
+		helpers:    map[string]bool{},
+	}
+
+	g.collectComposites(api)
+
+	g.generateEvents()
+	g.generateComposites()
+	g.generateFieldScripts(api.Fields)
+	g.generateFunctionWrappers(api.Functions)
+
+	var code bytes.Buffer
+	g.writeHeader(&code)
+	code.Write(g.declarations.Bytes())
+	code.Write(g.helperCode.Bytes())
+
+	return format.Source(code.Bytes())
+}
+
+func contractName(t cadence.Type) (string, error) {
+	switch t := t.(type) {
+	case *cadence.ContractType:
+		return t.QualifiedIdentifier, nil
+	case *cadence.ContractInterfaceType:
+		return t.QualifiedIdentifier, nil
+	default:
+		return "", fmt.Errorf("cannot generate code for type %s: not a contract", t.ID())
+	}
+}
+
+func (g *generator) writeHeader(code *bytes.Buffer) {
+	code.WriteString("// Code generated by gocodegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(code, "package %s\n\n", g.config.PackageName)
+
+	code.WriteString("import (\n")
+	if g.usesFmt {
+		code.WriteString("\"fmt\"\n")
+	}
+	if g.usesBig {
+		code.WriteString("\"math/big\"\n")
+	}
+	code.WriteString("\n\"github.com/onflow/cadence\"\n")
+	if g.usesJSON {
+		code.WriteString("jsoncdc \"github.com/onflow/cadence/encoding/json\"\n")
+	}
+	if g.usesCommon {
+		code.WriteString("\"github.com/onflow/cadence/runtime/common\"\n")
+	}
+	code.WriteString(")\n\n")
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.declarations, format, args...)
+}
+
+func (g *generator) helperf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.helperCode, format, args...)
+}
+
+// helperName returns the name of an unexported helper function,
+// prefixed with the contract name to avoid clashes with other generated code
+//
+func (g *generator) helperName(name string) string {
+	return unexportedName(g.contractName) + name
+}
+
+// goName returns the name of the Go declaration for the given qualified identifier,
+// e.g. `FungibleTokenVault` for `FungibleToken.Vault`
+//
+func goName(qualifiedIdentifier string) string {
+	parts := strings.Split(qualifiedIdentifier, ".")
+	for i, part := range parts {
+		parts[i] = exportedName(part)
+	}
+	return strings.Join(parts, "")
+}
+
+func (g *generator) collectComposites(api *runtime.TypeAPI) {
+	for _, event := range api.Events {
+		eventType, ok := event.(*cadence.EventType)
+		if !ok {
+			continue
+		}
+
+		composite := &composite{
+			kind:                eventKind,
+			cadenceType:         eventType,
+			goName:              goName(eventType.QualifiedIdentifier) + "Event",
+			qualifiedIdentifier: eventType.QualifiedIdentifier,
+			fields:              eventType.Fields,
+		}
+		g.composites[event.ID()] = composite
+		g.events = append(g.events, composite)
+	}
+
+	for _, nestedType := range api.NestedTypes {
+		var composite *composite
+
+		switch t := nestedType.Type.(type) {
+		case *cadence.StructType:
+			composite = newComposite(structKind, t, t.QualifiedIdentifier, t.Fields)
+		case *cadence.ResourceType:
+			composite = newComposite(resourceKind, t, t.QualifiedIdentifier, t.Fields)
+		}
+
+		if composite != nil {
+			g.composites[nestedType.Type.ID()] = composite
+			g.compositeOrder = append(g.compositeOrder, composite)
+		}
+
+		g.collectComposites(nestedType)
+	}
+}
+
+func newComposite(
+	kind compositeKind,
+	cadenceType cadence.Type,
+	qualifiedIdentifier string,
+	fields []cadence.Field,
+) *composite {
+	return &composite{
+		kind:                kind,
+		cadenceType:         cadenceType,
+		goName:              goName(qualifiedIdentifier),
+		qualifiedIdentifier: qualifiedIdentifier,
+		fields:              fields,
+	}
+}
+
+// goType returns the Go type which represents values of the given Cadence type.
+// Values of types which have no dedicated representation are represented as cadence.Value
+//
+func (g *generator) goType(t cadence.Type) string {
+	if simple, ok := simpleTypes[t.ID()]; ok {
+		if simple.usesBig {
+			g.usesBig = true
+		}
+		return simple.goType
+	}
+
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		inner := g.goType(t.Type)
+		if inner == "cadence.Value" {
+			return inner
+		}
+		return "*" + inner
+
+	case cadence.VariableSizedArrayType:
+		return "[]" + g.goType(t.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		return "[]" + g.goType(t.ElementType)
+
+	case cadence.DictionaryType:
+		key, ok := simpleTypes[t.KeyType.ID()]
+		if !ok || !key.comparable {
+			return "cadence.Value"
+		}
+		return fmt.Sprintf("map[%s]%s", key.goType, g.goType(t.ElementType))
+	}
+
+	if composite, ok := g.composites[t.ID()]; ok {
+		return composite.goName
+	}
+
+	return "cadence.Value"
+}
+
+// typeName returns the name of the given type,
+// which is used in the names of the helper functions for the type
+//
+func (g *generator) typeName(t cadence.Type) string {
+	if g.goType(t) == "cadence.Value" {
+		return "Value"
+	}
+
+	if simple, ok := simpleTypes[t.ID()]; ok {
+		return simple.name
+	}
+
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		return "Optional" + g.typeName(t.Type)
+
+	case cadence.VariableSizedArrayType:
+		return "ArrayOf" + g.typeName(t.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		return fmt.Sprintf("Array%dOf%s", t.Size, g.typeName(t.ElementType))
+
+	case cadence.DictionaryType:
+		return fmt.Sprintf("DictionaryOf%sTo%s", g.typeName(t.KeyType), g.typeName(t.ElementType))
+	}
+
+	return g.composites[t.ID()].goName
+}
+
+// decodeFunction returns the name of the helper function
+// which decodes a Cadence value of the given type to its Go representation,
+// and generates the function if needed
+//
+func (g *generator) decodeFunction(t cadence.Type) string {
+	name := g.helperName("Decode" + g.typeName(t))
+	if g.helpers[name] {
+		return name
+	}
+	g.helpers[name] = true
+
+	goType := g.goType(t)
+
+	if goType == "cadence.Value" {
+		g.helperf("func %s(value cadence.Value) (cadence.Value, error) {\nreturn value, nil\n}\n\n", name)
+		return name
+	}
+
+	g.usesFmt = true
+
+	if simple, ok := simpleTypes[t.ID()]; ok {
+		g.helperf(
+			`func %[1]s(value cadence.Value) (%[2]s, error) {
+				v, ok := value.(%[3]s)
+				if !ok {
+					return %[4]s, fmt.Errorf("expected %[5]s, got %%T", value)
+				}
+				return %[6]s, nil
+			}
+
+			`,
+			name,
+			goType,
+			simple.valueType,
+			simple.zero,
+			t.ID(),
+			simple.decode,
+		)
+		return name
+	}
+
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		inner := g.decodeFunction(t.Type)
+		g.helperf(
+			`func %[1]s(value cadence.Value) (%[2]s, error) {
+				optional, ok := value.(cadence.Optional)
+				if !ok {
+					return nil, fmt.Errorf("expected optional, got %%T", value)
+				}
+				if optional.Value == nil {
+					return nil, nil
+				}
+				v, err := %[3]s(optional.Value)
+				if err != nil {
+					return nil, err
+				}
+				return &v, nil
+			}
+
+			`,
+			name,
+			goType,
+			inner,
+		)
+		return name
+
+	case cadence.VariableSizedArrayType, cadence.ConstantSizedArrayType:
+		var elementType cadence.Type
+		switch t := t.(type) {
+		case cadence.VariableSizedArrayType:
+			elementType = t.ElementType
+		case cadence.ConstantSizedArrayType:
+			elementType = t.ElementType
+		}
+
+		inner := g.decodeFunction(elementType)
+		g.helperf(
+			`func %[1]s(value cadence.Value) (%[2]s, error) {
+				array, ok := value.(cadence.Array)
+				if !ok {
+					return nil, fmt.Errorf("expected array, got %%T", value)
+				}
+				result := make(%[2]s, len(array.Values))
+				for i, element := range array.Values {
+					v, err := %[3]s(element)
+					if err != nil {
+						return nil, err
+					}
+					result[i] = v
+				}
+				return result, nil
+			}
+
+			`,
+			name,
+			goType,
+			inner,
+		)
+		return name
+
+	case cadence.DictionaryType:
+		keyFunction := g.decodeFunction(t.KeyType)
+		valueFunction := g.decodeFunction(t.ElementType)
+		g.helperf(
+			`func %[1]s(value cadence.Value) (%[2]s, error) {
+				dictionary, ok := value.(cadence.Dictionary)
+				if !ok {
+					return nil, fmt.Errorf("expected dictionary, got %%T", value)
+				}
+				result := make(%[2]s, len(dictionary.Pairs))
+				for _, pair := range dictionary.Pairs {
+					key, err := %[3]s(pair.Key)
+					if err != nil {
+						return nil, err
+					}
+					v, err := %[4]s(pair.Value)
+					if err != nil {
+						return nil, err
+					}
+					result[key] = v
+				}
+				return result, nil
+			}
+
+			`,
+			name,
+			goType,
+			keyFunction,
+			valueFunction,
+		)
+		return name
+	}
+
+	composite := g.composites[t.ID()]
+
+	fieldFunctions := make([]string, len(composite.fields))
+	for i, field := range composite.fields {
+		fieldFunctions[i] = g.decodeFunction(field.Type)
+	}
+
+	g.helperf(
+		`func %[1]s(value cadence.Value) (%[2]s, error) {
+			composite, ok := value.(%[3]s)
+			if !ok {
+				return %[2]s{}, fmt.Errorf("expected %[4]s, got %%T", value)
+			}
+			if len(composite.Fields) != %[5]d {
+				return %[2]s{}, fmt.Errorf("expected %[5]d fields, got %%d", len(composite.Fields))
+			}
+			var result %[2]s
+		`,
+		name,
+		goType,
+		composite.kind.valueType,
+		composite.qualifiedIdentifier,
+		len(composite.fields),
+	)
+
+	if len(composite.fields) > 0 {
+		g.helperf("var err error\n")
+	}
+
+	for i, field := range composite.fields {
+		g.helperf(
+			`result.%[1]s, err = %[2]s(composite.Fields[%[3]d])
+			if err != nil {
+				return %[4]s{}, err
+			}
+			`,
+			fieldName(field.Identifier),
+			fieldFunctions[i],
+			i,
+			goType,
+		)
+	}
+
+	g.helperf("return result, nil\n}\n\n")
+
+	return name
+}
+
+// encodeFunction returns the name of the helper function
+// which encodes the Go representation of the given type to a Cadence value,
+// and generates the function if needed
+//
+func (g *generator) encodeFunction(t cadence.Type) string {
+	name := g.helperName("Encode" + g.typeName(t))
+	if g.helpers[name] {
+		return name
+	}
+	g.helpers[name] = true
+
+	goType := g.goType(t)
+
+	if goType == "cadence.Value" {
+		g.helperf("func %s(value cadence.Value) (cadence.Value, error) {\nreturn value, nil\n}\n\n", name)
+		return name
+	}
+
+	if simple, ok := simpleTypes[t.ID()]; ok {
+		if simple.encodeFails {
+			g.helperf(
+				`func %[1]s(value %[2]s) (cadence.Value, error) {
+					v, err := %[3]s
+					if err != nil {
+						return nil, err
+					}
+					return v, nil
+				}
+
+				`,
+				name,
+				goType,
+				simple.encode,
+			)
+		} else {
+			g.helperf(
+				`func %[1]s(value %[2]s) (cadence.Value, error) {
+					return %[3]s, nil
+				}
+
+				`,
+				name,
+				goType,
+				simple.encode,
+			)
+		}
+		return name
+	}
+
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		inner := g.encodeFunction(t.Type)
+		g.helperf(
+			`func %[1]s(value %[2]s) (cadence.Value, error) {
+				if value == nil {
+					return cadence.NewOptional(nil), nil
+				}
+				v, err := %[3]s(*value)
+				if err != nil {
+					return nil, err
+				}
+				return cadence.NewOptional(v), nil
+			}
+
+			`,
+			name,
+			goType,
+			inner,
+		)
+		return name
+
+	case cadence.VariableSizedArrayType, cadence.ConstantSizedArrayType:
+		var elementType cadence.Type
+		switch t := t.(type) {
+		case cadence.VariableSizedArrayType:
+			elementType = t.ElementType
+		case cadence.ConstantSizedArrayType:
+			elementType = t.ElementType
+		}
+
+		inner := g.encodeFunction(elementType)
+		g.helperf(
+			`func %[1]s(value %[2]s) (cadence.Value, error) {
+				values := make([]cadence.Value, len(value))
+				for i, element := range value {
+					v, err := %[3]s(element)
+					if err != nil {
+						return nil, err
+					}
+					values[i] = v
+				}
+				return cadence.NewArray(values), nil
+			}
+
+			`,
+			name,
+			goType,
+			inner,
+		)
+		return name
+
+	case cadence.DictionaryType:
+		keyFunction := g.encodeFunction(t.KeyType)
+		valueFunction := g.encodeFunction(t.ElementType)
+		g.helperf(
+			`func %[1]s(value %[2]s) (cadence.Value, error) {
+				pairs := make([]cadence.KeyValuePair, 0, len(value))
+				for key, element := range value {
+					k, err := %[3]s(key)
+					if err != nil {
+						return nil, err
+					}
+					v, err := %[4]s(element)
+					if err != nil {
+						return nil, err
+					}
+					pairs = append(pairs, cadence.KeyValuePair{Key: k, Value: v})
+				}
+				return cadence.NewDictionary(pairs), nil
+			}
+
+			`,
+			name,
+			goType,
+			keyFunction,
+			valueFunction,
+		)
+		return name
+	}
+
+	// only structures can be encoded, see isEncodable
+
+	composite := g.composites[t.ID()]
+
+	fieldFunctions := make([]string, len(composite.fields))
+	for i, field := range composite.fields {
+		fieldFunctions[i] = g.encodeFunction(field.Type)
+	}
+
+	typeVariable := unexportedName(composite.goName) + "Type"
+
+	g.usesCommon = true
+
+	g.helperf(
+		`var %[1]s = &cadence.StructType{
+			Location: common.AddressLocation{
+				Address: %#[2]v,
+				Name:    %[3]q,
+			},
+			QualifiedIdentifier: %[4]q,
+			Fields: []cadence.Field{
+		`,
+		typeVariable,
+		g.location.Address,
+		g.location.Name,
+		composite.qualifiedIdentifier,
+	)
+
+	for _, field := range composite.fields {
+		g.helperf("{Identifier: %q},\n", field.Identifier)
+	}
+
+	g.helperf("},\n}\n\n")
+
+	g.helperf(
+		`func %[1]s(value %[2]s) (cadence.Value, error) {
+			fields := make([]cadence.Value, %[3]d)
+		`,
+		name,
+		goType,
+		len(composite.fields),
+	)
+
+	if len(composite.fields) > 0 {
+		g.helperf("var err error\n")
+	}
+
+	for i, field := range composite.fields {
+		g.helperf(
+			`fields[%[1]d], err = %[2]s(value.%[3]s)
+			if err != nil {
+				return nil, err
+			}
+			`,
+			i,
+			fieldFunctions[i],
+			fieldName(field.Identifier),
+		)
+	}
+
+	g.helperf("return cadence.NewStruct(fields).WithType(%s), nil\n}\n\n", typeVariable)
+
+	return name
+}
+
+// isEncodable returns true if Go values of the given type can be encoded to Cadence values,
+// and the type can be written in a script or transaction
+//
+func (g *generator) isEncodable(t cadence.Type) bool {
+	if isResourceType(t) {
+		return false
+	}
+	_, ok := g.cadenceSyntax(t)
+	return ok
+}
+
+func (g *generator) generateStruct(composite *composite) {
+	g.printf("type %s struct {\n", composite.goName)
+	for _, field := range composite.fields {
+		g.printf("%s %s\n", fieldName(field.Identifier), g.goType(field.Type))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) generateEvents() {
+	for _, event := range g.events {
+		typeID := g.location.TypeID(event.qualifiedIdentifier)
+
+		g.printf(
+			"// %[1]sType is the type ID of the event `%[2]s`\nconst %[1]sType = %[3]q\n\n",
+			event.goName,
+			event.qualifiedIdentifier,
+			typeID,
+		)
+
+		g.printf("// %s represents the event `%s`\n", event.goName, event.qualifiedIdentifier)
+		g.generateStruct(event)
+
+		decodeFunction := g.decodeFunction(event.cadenceType)
+
+		g.usesFmt = true
+
+		g.printf(
+			`// Decode%[1]s decodes the event `+"`%[2]s`"+`
+			func Decode%[1]s(event cadence.Event) (%[1]s, error) {
+				if event.EventType != nil && event.EventType.ID() != %[1]sType {
+					return %[1]s{}, fmt.Errorf("expected event %%s, got %%s", %[1]sType, event.EventType.ID())
+				}
+				return %[3]s(event)
+			}
+
+			`,
+			event.goName,
+			event.qualifiedIdentifier,
+			decodeFunction,
+		)
+	}
+}
+
+func (g *generator) generateComposites() {
+	for _, composite := range g.compositeOrder {
+		g.printf(
+			"// %s represents values of the %s `%s`\n",
+			composite.goName,
+			composite.kind.name,
+			composite.qualifiedIdentifier,
+		)
+		g.generateStruct(composite)
+	}
+}
+
+func isAPIAccess(access ast.Access) bool {
+	switch access {
+	case ast.AccessPublic, ast.AccessPublicSettable:
+		return true
+	}
+	return false
+}
+
+func (g *generator) importDeclaration() string {
+	return fmt.Sprintf("import %s from %s", g.contractName, g.location.Address.ShortHexWithPrefix())
+}
+
+func (g *generator) generateFieldScripts(fields []runtime.FieldAPI) {
+	for _, field := range fields {
+		if !isAPIAccess(field.Access) || isResourceType(field.Type) {
+			continue
+		}
+
+		resultSyntax, ok := g.cadenceSyntax(field.Type)
+		if !ok {
+			continue
+		}
+
+		code := fmt.Sprintf(
+			"%s\n\npub fun main(): %s {\n    return %s.%s\n}\n",
+			g.importDeclaration(),
+			resultSyntax,
+			g.contractName,
+			field.Identifier,
+		)
+
+		name := g.contractName + exportedName(field.Identifier)
+
+		g.generateWrapper(
+			name+"Script",
+			fmt.Sprintf("a script which returns the value of the field `%s.%s`", g.contractName, field.Identifier),
+			code,
+			nil,
+		)
+
+		g.generateResultDecoder(name, field.Type)
+	}
+}
+
+func (g *generator) generateFunctionWrappers(functions []runtime.FunctionAPI) {
+	for _, function := range functions {
+		if !isAPIAccess(function.Access) {
+			continue
+		}
+
+		functionType := function.Type
+
+		encodable := true
+		for _, parameter := range functionType.Parameters {
+			if !g.isEncodable(parameter.Type) {
+				encodable = false
+				break
+			}
+		}
+		if !encodable {
+			continue
+		}
+
+		var parameterDeclarations []string
+		var arguments []string
+
+		for _, parameter := range functionType.Parameters {
+			syntax, _ := g.cadenceSyntax(parameter.Type)
+			parameterDeclarations = append(
+				parameterDeclarations,
+				fmt.Sprintf("%s: %s", parameter.Identifier, syntax),
+			)
+
+			switch parameter.Label {
+			case sema.ArgumentLabelNotRequired:
+				arguments = append(arguments, parameter.Identifier)
+			case "":
+				arguments = append(arguments, fmt.Sprintf("%[1]s: %[1]s", parameter.Identifier))
+			default:
+				arguments = append(arguments, fmt.Sprintf("%s: %s", parameter.Label, parameter.Identifier))
+			}
+		}
+
+		invocation := fmt.Sprintf(
+			"%s.%s(%s)",
+			g.contractName,
+			function.Identifier,
+			strings.Join(arguments, ", "),
+		)
+
+		name := g.contractName + exportedName(function.Identifier)
+
+		if _, ok := functionType.ReturnType.(cadence.VoidType); ok {
+			code := fmt.Sprintf(
+				"%s\n\ntransaction(%s) {\n    execute {\n        %s\n    }\n}\n",
+				g.importDeclaration(),
+				strings.Join(parameterDeclarations, ", "),
+				invocation,
+			)
+
+			g.generateWrapper(
+				name+"Transaction",
+				fmt.Sprintf("a transaction which calls the function `%s.%s`", g.contractName, function.Identifier),
+				code,
+				functionType.Parameters,
+			)
+
+			continue
+		}
+
+		if isResourceType(functionType.ReturnType) {
+			continue
+		}
+
+		resultSyntax, ok := g.cadenceSyntax(functionType.ReturnType)
+		if !ok {
+			continue
+		}
+
+		code := fmt.Sprintf(
+			"%s\n\npub fun main(%s): %s {\n    return %s\n}\n",
+			g.importDeclaration(),
+			strings.Join(parameterDeclarations, ", "),
+			resultSyntax,
+			invocation,
+		)
+
+		g.generateWrapper(
+			name+"Script",
+			fmt.Sprintf("a script which returns the result of calling the function `%s.%s`", g.contractName, function.Identifier),
+			code,
+			functionType.Parameters,
+		)
+
+		g.generateResultDecoder(name, functionType.ReturnType)
+	}
+}
+
+// reservedParameterNames are the names of the local variables of the wrapper functions
+//
+var reservedParameterNames = map[string]bool{
+	"code":      true,
+	"arguments": true,
+	"argument":  true,
+	"encoded":   true,
+	"err":       true,
+	"cadence":   true,
+	"jsoncdc":   true,
+	"common":    true,
+	"big":       true,
+	"fmt":       true,
+}
+
+func goParameterName(identifier string) string {
+	name := unexportedName(identifier)
+	if token.IsKeyword(name) || reservedParameterNames[name] {
+		return name + "Argument"
+	}
+	return name
+}
+
+func (g *generator) generateWrapper(
+	name string,
+	description string,
+	code string,
+	parameters []cadence.Parameter,
+) {
+	codeConstant := unexportedName(name) + "Code"
+
+	g.printf("const %s = `%s`\n\n", codeConstant, code)
+
+	goParameters := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		goParameters[i] = fmt.Sprintf(
+			"%s %s",
+			goParameterName(parameter.Identifier),
+			g.goType(parameter.Type),
+		)
+	}
+
+	g.printf(
+		"// %s returns the code and the JSON-encoded arguments of\n// %s\nfunc %s(%s) (code []byte, arguments [][]byte, err error) {\n",
+		name,
+		description,
+		name,
+		strings.Join(goParameters, ", "),
+	)
+
+	if len(parameters) > 0 {
+		g.usesJSON = true
+
+		g.printf("var argument cadence.Value\nvar encoded []byte\n\n")
+
+		for _, parameter := range parameters {
+			g.printf(
+				`argument, err = %[1]s(%[2]s)
+				if err != nil {
+					return nil, nil, err
+				}
+				encoded, err = jsoncdc.Encode(argument)
+				if err != nil {
+					return nil, nil, err
+				}
+				arguments = append(arguments, encoded)
+
+				`,
+				g.encodeFunction(parameter.Type),
+				goParameterName(parameter.Identifier),
+			)
+		}
+	}
+
+	g.printf("return []byte(%s), arguments, nil\n}\n\n", codeConstant)
+}
+
+func (g *generator) generateResultDecoder(name string, resultType cadence.Type) {
+	g.printf(
+		"// Decode%[1]sResult decodes the result of the script returned by %[1]sScript\nfunc Decode%[1]sResult(value cadence.Value) (%[2]s, error) {\nreturn %[3]s(value)\n}\n\n",
+		name,
+		g.goType(resultType),
+		g.decodeFunction(resultType),
+	)
+}
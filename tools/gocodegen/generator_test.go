@@ -0,0 +1,176 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gocodegen
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+var updateGoldenFiles = flag.Bool("update", false, "update the golden files")
+
+func exportContractAPI(t *testing.T, name string, code string) *runtime.TypeAPI {
+	location := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x1}),
+		Name:    name,
+	}
+
+	programs, err := analysis.Load(
+		&analysis.Config{
+			ResolveCode: func(
+				_ common.Location,
+				_ common.Location,
+				_ ast.Range,
+			) (string, error) {
+				return code, nil
+			},
+		},
+		location,
+	)
+	require.NoError(t, err)
+
+	program := programs[location.ID()]
+
+	var contractType sema.CompositeKindedType
+	for _, compositeType := range program.Elaboration.CompositeDeclarationTypes {
+		if compositeType.Identifier == name {
+			contractType = compositeType
+		}
+	}
+	require.NotNil(t, contractType)
+
+	api, err := runtime.ExportContractAPI(contractType)
+	require.NoError(t, err)
+
+	return api
+}
+
+func TestGenerateExample(t *testing.T) {
+
+	t.Parallel()
+
+	code, err := os.ReadFile("internal/example/Counter.cdc")
+	require.NoError(t, err)
+
+	api := exportContractAPI(t, "Counter", string(code))
+
+	actual, err := Generate(
+		api,
+		Config{
+			PackageName:     "example",
+			ContractAddress: common.BytesToAddress([]byte{0x1}),
+		},
+	)
+	require.NoError(t, err)
+
+	const path = "internal/example/counter_gen.go"
+
+	if *updateGoldenFiles {
+		err = os.WriteFile(path, actual, 0644)
+		require.NoError(t, err)
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestGenerate(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("not a contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := Generate(
+			&runtime.TypeAPI{
+				Type: &cadence.StructType{
+					QualifiedIdentifier: "S",
+				},
+			},
+			Config{PackageName: "test"},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported types", func(t *testing.T) {
+
+		t.Parallel()
+
+		api := exportContractAPI(t, "C", `
+          pub contract C {
+
+              pub resource R {}
+
+              pub var cap: Capability<&R>?
+
+              init() {
+                  self.cap = nil
+              }
+
+              pub fun deposit(r: @R) {
+                  destroy r
+              }
+
+              pub fun borrow(path: PublicPath): &AnyStruct? {
+                  return nil
+              }
+
+              pub fun identity(_ value: AnyStruct): AnyStruct {
+                  return value
+              }
+          }
+        `)
+
+		code, err := Generate(api, Config{PackageName: "test"})
+		require.NoError(t, err)
+
+		source := string(code)
+
+		// Functions with resource parameters and types without syntax are skipped
+
+		assert.NotContains(t, source, "func CDepositTransaction")
+		assert.NotContains(t, source, "func CBorrowScript")
+		assert.NotContains(t, source, "func CCapScript")
+
+		// Values of types without a Go representation are passed through
+
+		assert.Contains(t, source, "func CIdentityScript(value cadence.Value)")
+		assert.Contains(t, source, "func DecodeCIdentityResult(value cadence.Value) (cadence.Value, error)")
+
+		// Resources have a Go representation, but cannot be passed
+
+		assert.Contains(t, source, "type CR struct")
+		assert.False(t, strings.Contains(source, "cRType"))
+	})
+}
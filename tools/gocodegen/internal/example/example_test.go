@@ -0,0 +1,232 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package example
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+)
+
+type environment struct {
+	t                *testing.T
+	runtime          runtime.Runtime
+	runtimeInterface *inmemory.Interface
+}
+
+func newEnvironment(t *testing.T) *environment {
+	runtimeInterface := inmemory.NewInterface()
+
+	env := &environment{
+		t:                t,
+		runtime:          runtime.NewInterpreterRuntime(),
+		runtimeInterface: runtimeInterface,
+	}
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToAddress([]byte{0x1}), address)
+
+	contract, err := os.ReadFile("Counter.cdc")
+	require.NoError(t, err)
+
+	deploy := fmt.Sprintf(
+		`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.contracts.add(name: "Counter", code: "%s".decodeHex())
+              }
+          }
+        `,
+		hex.EncodeToString(contract),
+	)
+
+	env.executeTransaction([]byte(deploy), nil, address)
+
+	return env
+}
+
+func (env *environment) executeTransaction(code []byte, arguments [][]byte, signers ...common.Address) {
+	env.runtimeInterface.SetSigningAccounts(signers)
+
+	err := env.runtime.ExecuteTransaction(
+		runtime.Script{
+			Source:    code,
+			Arguments: arguments,
+		},
+		runtime.Context{
+			Interface: env.runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(env.t, err)
+}
+
+func (env *environment) executeScript(code []byte, arguments [][]byte) cadence.Value {
+	result, err := env.runtime.ExecuteScript(
+		runtime.Script{
+			Source:    code,
+			Arguments: arguments,
+		},
+		runtime.Context{
+			Interface: env.runtimeInterface,
+			Location:  common.ScriptLocation{},
+		},
+	)
+	require.NoError(env.t, err)
+
+	return result
+}
+
+func TestGeneratedCode(t *testing.T) {
+
+	t.Parallel()
+
+	env := newEnvironment(t)
+
+	// Transaction with an optional argument, which emits an event
+
+	tag := "first"
+	code, arguments, err := CounterIncrementTransaction(big.NewInt(3), &tag)
+	require.NoError(t, err)
+
+	env.executeTransaction(code, arguments)
+
+	code, arguments, err = CounterIncrementTransaction(big.NewInt(4), nil)
+	require.NoError(t, err)
+
+	env.executeTransaction(code, arguments)
+
+	var events []CounterIncrementedEvent
+	for _, event := range env.runtimeInterface.Events() {
+		if event.EventType.ID() != CounterIncrementedEventType {
+			continue
+		}
+
+		decoded, err := DecodeCounterIncrementedEvent(event)
+		require.NoError(t, err)
+
+		events = append(events, decoded)
+	}
+
+	assert.Equal(t,
+		[]CounterIncrementedEvent{
+			{By: big.NewInt(3), Total: big.NewInt(3), Tag: &tag},
+			{By: big.NewInt(4), Total: big.NewInt(7), Tag: nil},
+		},
+		events,
+	)
+
+	// Scripts reading fields
+
+	code, arguments, err = CounterTotalScript()
+	require.NoError(t, err)
+
+	total, err := DecodeCounterTotalResult(env.executeScript(code, arguments))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), total)
+
+	code, arguments, err = CounterTagsScript()
+	require.NoError(t, err)
+
+	tags, err := DecodeCounterTagsResult(env.executeScript(code, arguments))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, tags)
+
+	// Transaction with an unlabeled argument, and a dictionary result
+
+	owner := cadence.BytesToAddress([]byte{0x2})
+	code, arguments, err = CounterSetOwnerTransaction("alice", owner)
+	require.NoError(t, err)
+
+	env.executeTransaction(code, arguments)
+
+	code, arguments, err = CounterOwnersScript()
+	require.NoError(t, err)
+
+	owners, err := DecodeCounterOwnersResult(env.executeScript(code, arguments))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cadence.Address{"alice": owner}, owners)
+
+	// Structures as results and arguments
+
+	code, arguments, err = CounterSnapshotScript()
+	require.NoError(t, err)
+
+	snapshot, err := DecodeCounterSnapshotResult(env.executeScript(code, arguments))
+	require.NoError(t, err)
+	assert.Equal(t,
+		CounterSnapshot{
+			Total: big.NewInt(7),
+			Tags:  []string{"first"},
+		},
+		snapshot,
+	)
+
+	snapshot.Total = big.NewInt(2)
+
+	code, arguments, err = CounterRestoreTransaction(snapshot)
+	require.NoError(t, err)
+
+	env.executeTransaction(code, arguments)
+
+	// Script with arguments
+
+	factor, err := cadence.NewUFix64("1.5")
+	require.NoError(t, err)
+
+	code, arguments, err = CounterScaledScript(factor, map[string]int8{"a": 1, "b": 2})
+	require.NoError(t, err)
+
+	scaled, err := DecodeCounterScaledResult(env.executeScript(code, arguments))
+	require.NoError(t, err)
+
+	expected, err := cadence.NewUFix64("6.0")
+	require.NoError(t, err)
+	assert.Equal(t, expected, scaled)
+}
+
+func TestDecodeInvalidValues(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := DecodeCounterTotalResult(cadence.String("7"))
+	require.EqualError(t, err, "expected Int, got cadence.String")
+
+	_, err = DecodeCounterSnapshotResult(cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}))
+	require.EqualError(t, err, "expected 2 fields, got 1")
+
+	_, err = DecodeCounterIncrementedEvent(
+		cadence.NewEvent(nil).WithType(&cadence.EventType{
+			Location:            common.AddressLocation{Name: "Other"},
+			QualifiedIdentifier: "Other.Incremented",
+		}),
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,296 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gocodegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+)
+
+// simpleType describes how a Cadence type without type parameters
+// is represented in Go, and how values are converted
+//
+type simpleType struct {
+	// name is used to derive the names of the helper functions
+	name string
+	// goType is the Go type of the representation
+	goType string
+	// valueType is the cadence.Value implementation
+	valueType string
+	// zero is the zero value of the Go type
+	zero string
+	// decode converts the cadence.Value `v` to the Go type
+	decode string
+	// encode converts the Go value `value` to a cadence.Value,
+	// possibly with an error
+	encode string
+	// encodeFails is true if encode returns an error
+	encodeFails bool
+	// comparable is true if the Go type can be used as a map key
+	comparable bool
+	usesBig    bool
+}
+
+var simpleTypes = map[string]simpleType{}
+
+func init() {
+	add := func(t cadence.Type, simple simpleType) {
+		simpleTypes[t.ID()] = simple
+	}
+
+	add(cadence.BoolType{}, simpleType{
+		name:       "Bool",
+		goType:     "bool",
+		valueType:  "cadence.Bool",
+		zero:       "false",
+		decode:     "bool(v)",
+		encode:     "cadence.NewBool(value)",
+		comparable: true,
+	})
+
+	add(cadence.StringType{}, simpleType{
+		name:        "String",
+		goType:      "string",
+		valueType:   "cadence.String",
+		zero:        `""`,
+		decode:      "string(v)",
+		encode:      "cadence.NewString(value)",
+		encodeFails: true,
+		comparable:  true,
+	})
+
+	add(cadence.AddressType{}, simpleType{
+		name:       "Address",
+		goType:     "cadence.Address",
+		valueType:  "cadence.Address",
+		zero:       "cadence.Address{}",
+		decode:     "v",
+		encode:     "value",
+		comparable: true,
+	})
+
+	for _, t := range []cadence.Type{
+		cadence.Fix64Type{},
+		cadence.UFix64Type{},
+		cadence.TimestampType{},
+		cadence.DurationType{},
+	} {
+		name := t.ID()
+		add(t, simpleType{
+			name:       name,
+			goType:     "cadence." + name,
+			valueType:  "cadence." + name,
+			zero:       "0",
+			decode:     "v",
+			encode:     "value",
+			comparable: true,
+		})
+	}
+
+	for _, t := range []struct {
+		ty     cadence.Type
+		goType string
+	}{
+		{cadence.Int8Type{}, "int8"},
+		{cadence.Int16Type{}, "int16"},
+		{cadence.Int32Type{}, "int32"},
+		{cadence.Int64Type{}, "int64"},
+		{cadence.UInt8Type{}, "uint8"},
+		{cadence.UInt16Type{}, "uint16"},
+		{cadence.UInt32Type{}, "uint32"},
+		{cadence.UInt64Type{}, "uint64"},
+		{cadence.Word8Type{}, "uint8"},
+		{cadence.Word16Type{}, "uint16"},
+		{cadence.Word32Type{}, "uint32"},
+		{cadence.Word64Type{}, "uint64"},
+	} {
+		name := t.ty.ID()
+		add(t.ty, simpleType{
+			name:       name,
+			goType:     t.goType,
+			valueType:  "cadence." + name,
+			zero:       "0",
+			decode:     fmt.Sprintf("%s(v)", t.goType),
+			encode:     fmt.Sprintf("cadence.%s(value)", name),
+			comparable: true,
+		})
+	}
+
+	add(cadence.IntType{}, simpleType{
+		name:      "Int",
+		goType:    "*big.Int",
+		valueType: "cadence.Int",
+		zero:      "nil",
+		decode:    "v.Big()",
+		encode:    "cadence.NewIntFromBig(value)",
+		usesBig:   true,
+	})
+
+	for _, t := range []cadence.Type{
+		cadence.Int128Type{},
+		cadence.Int256Type{},
+		cadence.UIntType{},
+		cadence.UInt128Type{},
+		cadence.UInt256Type{},
+	} {
+		name := t.ID()
+		add(t, simpleType{
+			name:        name,
+			goType:      "*big.Int",
+			valueType:   "cadence." + name,
+			zero:        "nil",
+			decode:      "v.Big()",
+			encode:      fmt.Sprintf("cadence.New%sFromBig(value)", name),
+			encodeFails: true,
+			usesBig:     true,
+		})
+	}
+
+	for _, t := range []cadence.Type{
+		cadence.Fix128Type{},
+		cadence.UFix128Type{},
+	} {
+		name := t.ID()
+		add(t, simpleType{
+			name:        name,
+			goType:      "*big.Int",
+			valueType:   "cadence." + name,
+			zero:        "nil",
+			decode:      "v.Value",
+			encode:      fmt.Sprintf("cadence.New%sFromBig(value)", name),
+			encodeFails: true,
+			usesBig:     true,
+		})
+	}
+}
+
+// isResourceType returns true if values of the given type are resources,
+// or contain resources
+//
+func isResourceType(t cadence.Type) bool {
+	switch t := t.(type) {
+	case *cadence.ResourceType, *cadence.ResourceInterfaceType, cadence.AnyResourceType:
+		return true
+	case cadence.OptionalType:
+		return isResourceType(t.Type)
+	case cadence.VariableSizedArrayType:
+		return isResourceType(t.ElementType)
+	case cadence.ConstantSizedArrayType:
+		return isResourceType(t.ElementType)
+	case cadence.DictionaryType:
+		return isResourceType(t.ElementType)
+	case cadence.RestrictedType:
+		return isResourceType(t.Type)
+	}
+	return false
+}
+
+// cadenceSyntax returns the Cadence source code for the given type,
+// as it can be written in a program which imports the generated contract.
+// It returns false if the type cannot be written,
+// e.g. because it is declared in another contract
+//
+func (g *generator) cadenceSyntax(t cadence.Type) (string, bool) {
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		inner, ok := g.cadenceSyntax(t.Type)
+		return inner + "?", ok
+
+	case cadence.VariableSizedArrayType:
+		element, ok := g.cadenceSyntax(t.ElementType)
+		return fmt.Sprintf("[%s]", element), ok
+
+	case cadence.ConstantSizedArrayType:
+		element, ok := g.cadenceSyntax(t.ElementType)
+		return fmt.Sprintf("[%s; %d]", element, t.Size), ok
+
+	case cadence.DictionaryType:
+		key, keyOK := g.cadenceSyntax(t.KeyType)
+		element, elementOK := g.cadenceSyntax(t.ElementType)
+		return fmt.Sprintf("{%s: %s}", key, element), keyOK && elementOK
+
+	case *cadence.StructType:
+		_, ok := g.composites[t.ID()]
+		return t.QualifiedIdentifier, ok
+
+	case *cadence.StructInterfaceType,
+		*cadence.ResourceType,
+		*cadence.ResourceInterfaceType,
+		*cadence.EventType,
+		*cadence.ContractType,
+		*cadence.ContractInterfaceType,
+		*cadence.EnumType,
+		cadence.FunctionType,
+		cadence.ReferenceType,
+		cadence.RestrictedType,
+		cadence.CapabilityType:
+
+		return "", false
+	}
+
+	id := t.ID()
+
+	// types declared in programs have qualified type IDs,
+	// which cannot be written in a program
+
+	if strings.Contains(id, ".") {
+		return "", false
+	}
+
+	return id, true
+}
+
+// exportedName returns the Go identifier for the given Cadence identifier,
+// i.e. the identifier with the first letter in upper case
+//
+func exportedName(identifier string) string {
+	if identifier == "" {
+		return identifier
+	}
+	return strings.ToUpper(identifier[:1]) + identifier[1:]
+}
+
+// initialisms are identifiers which are written in upper case in Go,
+// see https://github.com/golang/go/wiki/CodeReviewComments#initialisms
+//
+var initialisms = map[string]string{
+	"id":   "ID",
+	"uuid": "UUID",
+	"url":  "URL",
+}
+
+// fieldName returns the name of the Go struct field for the given Cadence field
+//
+func fieldName(identifier string) string {
+	if initialism, ok := initialisms[identifier]; ok {
+		return initialism
+	}
+	return exportedName(identifier)
+}
+
+// unexportedName returns the identifier with the first letter in lower case
+//
+func unexportedName(identifier string) string {
+	if identifier == "" {
+		return identifier
+	}
+	return strings.ToLower(identifier[:1]) + identifier[1:]
+}
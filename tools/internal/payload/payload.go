@@ -0,0 +1,137 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package payload provides the raw ledger payloads read by the storage tools,
+// and a read-only atree.Ledger backed by them.
+//
+package payload
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/atree"
+)
+
+// Payload is a raw ledger register: the owner, the key, and the encoded value
+//
+type Payload struct {
+	Owner []byte
+	Key   string
+	Value []byte
+}
+
+// Ledger is a read-only atree.Ledger backed by payloads
+//
+type Ledger struct {
+	registers map[string]map[string][]byte
+}
+
+var _ atree.Ledger = &Ledger{}
+
+// NewLedger returns a ledger with the registers of the given payloads.
+// Empty (deleted) registers are ignored
+//
+func NewLedger(payloads []Payload) *Ledger {
+	ledger := &Ledger{
+		registers: map[string]map[string][]byte{},
+	}
+
+	for _, payload := range payloads {
+		// Ignore empty (deleted) registers
+		if len(payload.Value) == 0 {
+			continue
+		}
+
+		owner := string(payload.Owner)
+		registers, ok := ledger.registers[owner]
+		if !ok {
+			registers = map[string][]byte{}
+			ledger.registers[owner] = registers
+		}
+		registers[payload.Key] = payload.Value
+	}
+
+	return ledger
+}
+
+// Owners returns the owners of all registers, sorted
+//
+func (l *Ledger) Owners() []string {
+	owners := make([]string, 0, len(l.registers))
+
+	// NOTE: iteration over map is safe,
+	// as result is sorted below
+
+	for owner := range l.registers { //nolint:maprangecheck
+		owners = append(owners, owner)
+	}
+
+	sort.Strings(owners)
+
+	return owners
+}
+
+// Keys returns the keys of all registers of the given owner, sorted
+//
+func (l *Ledger) Keys(owner string) []string {
+	registers := l.registers[owner]
+	keys := make([]string, 0, len(registers))
+
+	// NOTE: iteration over map is safe,
+	// as result is sorted below
+
+	for key := range registers { //nolint:maprangecheck
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Value returns the value of the register with the given owner and key,
+// or nil if there is no such register
+//
+func (l *Ledger) Value(owner, key string) []byte {
+	return l.registers[owner][key]
+}
+
+func (l *Ledger) GetValue(owner, key []byte) ([]byte, error) {
+	return l.Value(string(owner), string(key)), nil
+}
+
+func (l *Ledger) SetValue(_, _, _ []byte) error {
+	return fmt.Errorf("unexpected write to read-only ledger")
+}
+
+func (l *Ledger) ValueExists(owner, key []byte) (bool, error) {
+	_, ok := l.registers[string(owner)][string(key)]
+	return ok, nil
+}
+
+func (l *Ledger) AllocateStorageIndex(_ []byte) (atree.StorageIndex, error) {
+	return atree.StorageIndex{}, fmt.Errorf("unexpected storage index allocation in read-only ledger")
+}
+
+// FormatPath returns the storage path for the given domain and identifier,
+// e.g. `/storage/vault`
+//
+func FormatPath(domain, identifier string) string {
+	return fmt.Sprintf("/%s/%s", domain, identifier)
+}
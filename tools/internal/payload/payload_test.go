@@ -0,0 +1,66 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger(t *testing.T) {
+
+	t.Parallel()
+
+	ledger := NewLedger([]Payload{
+		{Owner: []byte{0x2}, Key: "b", Value: []byte{0x1}},
+		{Owner: []byte{0x2}, Key: "a", Value: []byte{0x2}},
+		{Owner: []byte{0x1}, Key: "c", Value: []byte{0x3}},
+		// Empty (deleted) register
+		{Owner: []byte{0x1}, Key: "d", Value: nil},
+	})
+
+	assert.Equal(t, []string{"\x01", "\x02"}, ledger.Owners())
+	assert.Equal(t, []string{"a", "b"}, ledger.Keys("\x02"))
+	assert.Equal(t, []string{"c"}, ledger.Keys("\x01"))
+
+	value, err := ledger.GetValue([]byte{0x2}, []byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x2}, value)
+
+	exists, err := ledger.ValueExists([]byte{0x1}, []byte("d"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// The ledger is read-only
+
+	err = ledger.SetValue([]byte{0x1}, []byte("c"), []byte{0x4})
+	require.Error(t, err)
+
+	_, err = ledger.AllocateStorageIndex([]byte{0x1})
+	require.Error(t, err)
+}
+
+func TestFormatPath(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, "/storage/vault", FormatPath("storage", "vault"))
+}
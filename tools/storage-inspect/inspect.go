@@ -0,0 +1,455 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/tools/internal/payload"
+)
+
+// Report is the result of inspecting a set of payloads
+//
+type Report struct {
+	Accounts []*AccountReport `json:"accounts"`
+}
+
+// AccountReport is the inspection result for a single account
+//
+type AccountReport struct {
+	Address               string            `json:"address"`
+	Values                []*ValueReport    `json:"values"`
+	Registers             []*RegisterReport `json:"registers"`
+	SlabCount             int               `json:"slabCount"`
+	SlabSize              int               `json:"slabSize"`
+	UnreferencedSlabCount int               `json:"unreferencedSlabCount"`
+}
+
+// ValueReport describes a stored value.
+// If the value could not be decoded, Error is set and Type is empty
+//
+type ValueReport struct {
+	Path  string `json:"path"`
+	Type  string `json:"type,omitempty"`
+	Size  int    `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// RegisterReport describes a register which is neither a slab nor a stored value,
+// for example account metadata maintained by the host environment
+//
+type RegisterReport struct {
+	Key  string `json:"key"`
+	Size int    `json:"size"`
+}
+
+const storagePathSeparator = "\x1f"
+
+// '$' + 8 byte index
+const slabKeyLength = 9
+
+const storageIndexLength = 8
+
+func isSlabKey(key string) bool {
+	return len(key) == slabKeyLength && key[0] == '$'
+}
+
+func isStorageDomain(domain string) bool {
+	return domain == runtime.StorageDomainContract ||
+		common.PathDomainFromIdentifier(domain) != common.PathDomainUnknown
+}
+
+// Inspect decodes all values in the given payloads and reports,
+// for each account, the stored values, their types and sizes, and slab usage.
+//
+// Both storage layouts are supported: values stored in per-domain storage maps,
+// and values stored directly in registers keyed by domain and identifier.
+// Values which fail to decode are reported with an error, they do not abort the inspection
+//
+func Inspect(payloads []payload.Payload) *Report {
+	ledger := payload.NewLedger(payloads)
+
+	inspector := &inspector{
+		ledger: ledger,
+		storage: atree.NewPersistentSlabStorage(
+			atree.NewLedgerBaseStorage(ledger),
+			interpreter.CBOREncMode,
+			interpreter.CBORDecMode,
+			interpreter.DecodeStorable,
+			interpreter.DecodeTypeInfo,
+		),
+	}
+
+	report := &Report{}
+
+	for _, owner := range ledger.Owners() {
+		report.Accounts = append(
+			report.Accounts,
+			inspector.inspectAccount(owner),
+		)
+	}
+
+	return report
+}
+
+// inspector
+
+type inspector struct {
+	ledger  *payload.Ledger
+	storage *atree.PersistentSlabStorage
+	// referencedSlabs are the slabs of the current account
+	// which are reachable from a stored value or a storage map
+	referencedSlabs map[atree.StorageID]struct{}
+}
+
+func (i *inspector) inspectAccount(owner string) *AccountReport {
+	address := common.BytesToAddress([]byte(owner))
+
+	accountReport := &AccountReport{
+		Address: address.HexWithPrefix(),
+	}
+
+	i.referencedSlabs = map[atree.StorageID]struct{}{}
+
+	keys := i.ledger.Keys(owner)
+
+	for _, key := range keys {
+		data := i.ledger.Value(owner, key)
+
+		switch {
+		case isSlabKey(key):
+			accountReport.SlabCount++
+			accountReport.SlabSize += len(data)
+
+		case isStorageDomain(key) && len(data) == storageIndexLength:
+			storageID := atree.StorageID{
+				Address: atree.Address(address),
+			}
+			copy(storageID.Index[:], data)
+
+			accountReport.Values = append(
+				accountReport.Values,
+				i.inspectStorageMap(key, storageID)...,
+			)
+
+			// The register only holds the index of the storage map's root slab
+			accountReport.Registers = append(
+				accountReport.Registers,
+				&RegisterReport{
+					Key:  key,
+					Size: len(data),
+				},
+			)
+
+		default:
+			keyParts := strings.SplitN(key, storagePathSeparator, 2)
+			if len(keyParts) == 2 && isStorageDomain(keyParts[0]) {
+				accountReport.Values = append(
+					accountReport.Values,
+					i.inspectStoredRegister(keyParts[0], keyParts[1], data),
+				)
+				continue
+			}
+
+			accountReport.Registers = append(
+				accountReport.Registers,
+				&RegisterReport{
+					Key:  formatKey(key),
+					Size: len(data),
+				},
+			)
+		}
+	}
+
+	for _, key := range keys {
+		if !isSlabKey(key) {
+			continue
+		}
+
+		storageID := atree.StorageID{
+			Address: atree.Address(address),
+		}
+		copy(storageID.Index[:], key[1:])
+
+		if _, ok := i.referencedSlabs[storageID]; !ok {
+			accountReport.UnreferencedSlabCount++
+		}
+	}
+
+	return accountReport
+}
+
+// inspectStorageMap reports all values of the storage map for the given domain
+//
+func (i *inspector) inspectStorageMap(domain string, storageID atree.StorageID) (valueReports []*ValueReport) {
+
+	err := catch(func() {
+		// The storage map itself is stored in slabs,
+		// which must be reachable, even if the map is empty
+
+		_, err := i.slabTreeSize(storageID, map[atree.StorageID]struct{}{})
+		if err != nil {
+			panic(err)
+		}
+
+		storageMap := interpreter.NewStorageMapWithRootID(i.storage, storageID)
+
+		var keys []string
+
+		iterator := storageMap.Iterator()
+		for {
+			key := iterator.NextKey()
+			if key == "" {
+				break
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			valueReport := &ValueReport{
+				Path: payload.FormatPath(domain, key),
+			}
+
+			err := catch(func() {
+				value := storageMap.ReadValue(key)
+
+				valueReport.Type = valueTypeString(value)
+
+				// NOTE: use the maximum inline size,
+				// so the storable is never moved into a new slab
+
+				storable, err := value.Storable(i.storage, storageID.Address, math.MaxUint64)
+				if err != nil {
+					panic(fmt.Errorf("failed to get storable: %w", err))
+				}
+
+				valueReport.Size, err = i.storableSize(storable)
+				if err != nil {
+					panic(err)
+				}
+			})
+			if err != nil {
+				valueReport.Error = err.Error()
+			}
+
+			valueReports = append(valueReports, valueReport)
+		}
+	})
+	if err != nil {
+		valueReports = append(
+			valueReports,
+			&ValueReport{
+				Path:  payload.FormatPath(domain, ""),
+				Error: fmt.Sprintf("failed to read storage map: %s", err),
+			},
+		)
+	}
+
+	return valueReports
+}
+
+// inspectStoredRegister reports a value which is stored directly in a register,
+// keyed by domain and identifier
+//
+func (i *inspector) inspectStoredRegister(domain string, identifier string, data []byte) *ValueReport {
+
+	valueReport := &ValueReport{
+		Path: payload.FormatPath(domain, identifier),
+		Size: len(data),
+	}
+
+	err := catch(func() {
+		decoder := interpreter.CBORDecMode.NewStreamDecoder(bytes.NewReader(data))
+		storable, err := interpreter.DecodeStorable(decoder, atree.StorageIDUndefined)
+		if err != nil {
+			panic(fmt.Errorf("failed to decode storable: %w", err))
+		}
+
+		atreeValue, err := storable.StoredValue(i.storage)
+		if err != nil {
+			panic(fmt.Errorf("failed to load stored value: %w", err))
+		}
+
+		value, err := interpreter.ConvertStoredValue(atreeValue)
+		if err != nil {
+			panic(fmt.Errorf("failed to convert stored value: %w", err))
+		}
+
+		valueReport.Type = valueTypeString(value)
+
+		// The register holds the encoded storable itself,
+		// only the slabs it references need to be added
+
+		slabsSize, err := i.referencedSlabsSize(
+			[]atree.Storable{storable},
+			map[atree.StorageID]struct{}{},
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		valueReport.Size += slabsSize
+	})
+	if err != nil {
+		valueReport.Error = err.Error()
+	}
+
+	return valueReport
+}
+
+func valueTypeString(value interpreter.Value) string {
+	if link, ok := value.(interpreter.LinkValue); ok {
+		if link.Type == nil {
+			return "Link"
+		}
+		return fmt.Sprintf("Link<%s>", link.Type)
+	}
+
+	staticType := value.StaticType()
+	if staticType == nil {
+		return fmt.Sprintf("%T", value)
+	}
+
+	return staticType.String()
+}
+
+// storableSize returns the encoded size of the given storable,
+// including the size of all slabs reachable from it
+//
+func (i *inspector) storableSize(storable atree.Storable) (int, error) {
+	visited := map[atree.StorageID]struct{}{}
+
+	if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+		return i.slabTreeSize(atree.StorageID(storageIDStorable), visited)
+	}
+
+	slabsSize, err := i.referencedSlabsSize(storable.ChildStorables(), visited)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(storable.ByteSize()) + slabsSize, nil
+}
+
+// referencedSlabsSize returns the total size of the slab trees
+// which are referenced by the given storables, directly or through inlined children.
+//
+// NOTE: inlined storables are part of their parent's encoding,
+// so their own size is not included
+//
+func (i *inspector) referencedSlabsSize(
+	storables []atree.Storable,
+	visited map[atree.StorageID]struct{},
+) (int, error) {
+	var size int
+
+	for _, storable := range storables {
+		var childSize int
+		var err error
+
+		if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+			childSize, err = i.slabTreeSize(atree.StorageID(storageIDStorable), visited)
+		} else {
+			childSize, err = i.referencedSlabsSize(storable.ChildStorables(), visited)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		size += childSize
+	}
+
+	return size, nil
+}
+
+// slabTreeSize returns the total encoded size of the slab with the given ID,
+// and all slabs reachable from it. Each slab is only counted once
+//
+func (i *inspector) slabTreeSize(storageID atree.StorageID, visited map[atree.StorageID]struct{}) (int, error) {
+
+	if _, ok := visited[storageID]; ok {
+		return 0, nil
+	}
+	visited[storageID] = struct{}{}
+	i.referencedSlabs[storageID] = struct{}{}
+
+	slab, found, err := i.storage.Retrieve(storageID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve slab %s: %w", storageID, err)
+	}
+	if !found {
+		return 0, fmt.Errorf("missing slab %s", storageID)
+	}
+
+	owner := string(storageID.Address[:])
+	key := "$" + string(storageID.Index[:])
+	size := len(i.ledger.Value(owner, key))
+
+	childSize, err := i.referencedSlabsSize(slab.ChildStorables(), visited)
+	if err != nil {
+		return 0, err
+	}
+
+	return size + childSize, nil
+}
+
+// catch calls the given function and returns the error it panicked with, if any
+//
+func catch(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			default:
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	f()
+
+	return nil
+}
+
+// formatKey returns the key as-is if it is printable, or quoted otherwise
+//
+func formatKey(key string) string {
+	if !utf8.ValidString(key) {
+		return strconv.Quote(key)
+	}
+
+	for _, r := range key {
+		if !strconv.IsPrint(r) {
+			return strconv.Quote(key)
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,266 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+	"github.com/onflow/cadence/tools/internal/payload"
+)
+
+type testLedger struct {
+	payloads       map[string]map[string][]byte
+	storageIndices map[string]uint64
+}
+
+var _ atree.Ledger = &testLedger{}
+
+func newTestLedger() *testLedger {
+	return &testLedger{
+		payloads:       map[string]map[string][]byte{},
+		storageIndices: map[string]uint64{},
+	}
+}
+
+func (l *testLedger) GetValue(owner, key []byte) ([]byte, error) {
+	return l.payloads[string(owner)][string(key)], nil
+}
+
+func (l *testLedger) SetValue(owner, key, value []byte) error {
+	registers, ok := l.payloads[string(owner)]
+	if !ok {
+		registers = map[string][]byte{}
+		l.payloads[string(owner)] = registers
+	}
+	registers[string(key)] = value
+	return nil
+}
+
+func (l *testLedger) ValueExists(owner, key []byte) (bool, error) {
+	return len(l.payloads[string(owner)][string(key)]) > 0, nil
+}
+
+func (l *testLedger) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := l.storageIndices[string(owner)] + 1
+	l.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+func (l *testLedger) Payloads() []payload.Payload {
+	var payloads []payload.Payload
+	for owner, registers := range l.payloads { //nolint:maprangecheck
+		for key, value := range registers { //nolint:maprangecheck
+			payloads = append(payloads, payload.Payload{
+				Owner: []byte(owner),
+				Key:   key,
+				Value: value,
+			})
+		}
+	}
+	return payloads
+}
+
+func TestInspect(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	ledger := newTestLedger()
+	storage := runtime.NewStorage(ledger)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		interpreter.WithStorage(storage),
+	)
+	require.NoError(t, err)
+
+	// Large enough to be split into multiple slabs
+
+	const count = 1000
+	numbers := make([]interpreter.Value, count)
+	for i := range numbers {
+		numbers[i] = interpreter.NewIntValueFromInt64(int64(i))
+	}
+
+	storage.GetStorageMap(address, common.PathDomainStorage.Identifier(), true).
+		WriteValue(
+			inter,
+			"numbers",
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				address,
+				numbers...,
+			),
+		)
+
+	storage.GetStorageMap(address, common.PathDomainStorage.Identifier(), true).
+		WriteValue(inter, "greeting", interpreter.NewStringValue("hello"))
+
+	storage.GetStorageMap(address, common.PathDomainPublic.Identifier(), true).
+		WriteValue(
+			inter,
+			"numbers",
+			interpreter.LinkValue{
+				TargetPath: interpreter.PathValue{
+					Domain:     common.PathDomainStorage,
+					Identifier: "numbers",
+				},
+				Type: interpreter.PrimitiveStaticTypeAnyStruct,
+			},
+		)
+
+	err = storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	// A value stored directly in a register, keyed by domain and identifier
+
+	var legacyValue bytes.Buffer
+	encoder := atree.NewEncoder(&legacyValue, interpreter.CBOREncMode)
+	err = interpreter.NewIntValueFromInt64(42).Encode(encoder)
+	require.NoError(t, err)
+	err = encoder.CBOR.Flush()
+	require.NoError(t, err)
+
+	err = ledger.SetValue(address[:], []byte("storage\x1flegacy"), legacyValue.Bytes())
+	require.NoError(t, err)
+
+	// A broken value
+
+	err = ledger.SetValue(address[:], []byte("storage\x1fbroken"), []byte{0xff})
+	require.NoError(t, err)
+
+	// An unrelated register
+
+	err = ledger.SetValue(address[:], []byte("exists"), []byte{0x1})
+	require.NoError(t, err)
+
+	report := Inspect(ledger.Payloads())
+
+	require.Len(t, report.Accounts, 1)
+	account := report.Accounts[0]
+
+	assert.Equal(t, "0x0000000000000001", account.Address)
+	assert.Greater(t, account.SlabCount, 3)
+	assert.Equal(t, 0, account.UnreferencedSlabCount)
+
+	values := map[string]*ValueReport{}
+	for _, value := range account.Values {
+		values[value.Path] = value
+	}
+
+	require.Len(t, values, 5)
+
+	numbersReport := values["/storage/numbers"]
+	require.NotNil(t, numbersReport)
+	assert.Empty(t, numbersReport.Error)
+	assert.Equal(t, "[Int]", numbersReport.Type)
+	assert.Greater(t, numbersReport.Size, count)
+	assert.Less(t, numbersReport.Size, account.SlabSize)
+
+	greetingReport := values["/storage/greeting"]
+	require.NotNil(t, greetingReport)
+	assert.Empty(t, greetingReport.Error)
+	assert.Equal(t, "String", greetingReport.Type)
+	assert.Greater(t, greetingReport.Size, len("hello"))
+
+	linkReport := values["/public/numbers"]
+	require.NotNil(t, linkReport)
+	assert.Empty(t, linkReport.Error)
+	assert.Equal(t, "Link<AnyStruct>", linkReport.Type)
+
+	legacyReport := values["/storage/legacy"]
+	require.NotNil(t, legacyReport)
+	assert.Empty(t, legacyReport.Error)
+	assert.Equal(t, "Int", legacyReport.Type)
+	assert.Equal(t, legacyValue.Len(), legacyReport.Size)
+
+	brokenReport := values["/storage/broken"]
+	require.NotNil(t, brokenReport)
+	assert.NotEmpty(t, brokenReport.Error)
+
+	registerKeys := map[string]int{}
+	for _, register := range account.Registers {
+		registerKeys[register.Key] = register.Size
+	}
+
+	assert.Equal(t,
+		map[string]int{
+			"exists":  1,
+			"public":  storageIndexLength,
+			"storage": storageIndexLength,
+		},
+		registerKeys,
+	)
+}
+
+func TestInspectUnreferencedSlab(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x2})
+
+	ledger := newTestLedger()
+	storage := runtime.NewStorage(ledger)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		interpreter.WithStorage(storage),
+	)
+	require.NoError(t, err)
+
+	// An array which is stored in the account, but not referenced from any path
+
+	_ = interpreter.NewArrayValue(
+		inter,
+		interpreter.VariableSizedStaticType{
+			Type: interpreter.PrimitiveStaticTypeInt,
+		},
+		address,
+		interpreter.NewIntValueFromInt64(1),
+	)
+
+	err = storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	report := Inspect(ledger.Payloads())
+
+	require.Len(t, report.Accounts, 1)
+	account := report.Accounts[0]
+
+	assert.Empty(t, account.Values)
+	assert.Equal(t, 1, account.SlabCount)
+	assert.Equal(t, 1, account.UnreferencedSlabCount)
+}
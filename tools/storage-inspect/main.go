@@ -0,0 +1,236 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// A utility program that inspects a state dump in JSON Lines format,
+// and reports the stored values of each account, their types and sizes
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/internal/payload"
+)
+
+type stringSlice []string
+
+func (s stringSlice) String() string {
+	return strings.Join(s, ", ")
+}
+
+func (s *stringSlice) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+var addressesFlag stringSlice
+
+func init() {
+	flag.Var(&addressesFlag, "addresses", "only inspect the given addresses")
+}
+
+var gzipFlag = flag.Bool("gzip", false, "set true if input file is gzipped")
+var jsonFlag = flag.Bool("json", false, "print the report as JSON")
+
+// owner, controller, key
+const keyPartCount = 3
+
+type encodedKeyPart struct {
+	Value string
+}
+
+type encodedKey struct {
+	KeyParts []encodedKeyPart
+}
+
+type encodedEntry struct {
+	Value string
+	Key   encodedKey
+}
+
+func main() {
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		log.Fatal("missing path argument")
+	}
+
+	var addresses []common.Address
+
+	for _, hexAddress := range addressesFlag {
+		address, err := common.HexToAddress(hexAddress)
+		if err != nil {
+			log.Fatalf("Invalid address: %s", hexAddress)
+		}
+		addresses = append(addresses, address)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if *gzipFlag {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	payloads, err := readPayloads(reader, addresses)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := Inspect(payloads)
+
+	if *jsonFlag {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = printReport(os.Stdout, report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readPayloads(reader io.Reader, addresses []common.Address) ([]payload.Payload, error) {
+
+	filter := len(addresses) > 0
+
+	decoder := json.NewDecoder(bufio.NewReader(reader))
+
+	var payloads []payload.Payload
+
+	for line := 0; ; line++ {
+		var e encodedEntry
+
+		err := decoder.Decode(&e)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		// Skip empty lines
+		currentKeyPartCount := len(e.Key.KeyParts)
+		if currentKeyPartCount < keyPartCount {
+			if currentKeyPartCount > 0 {
+				return nil, fmt.Errorf("invalid storage key parts on line %d: %#+v", line, e.Key)
+			}
+			continue
+		}
+
+		var keyParts [keyPartCount][]byte
+		for i := 0; i < keyPartCount; i++ {
+			keyPart := e.Key.KeyParts[i].Value
+			keyParts[i], err = hex.DecodeString(keyPart)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to hex-decode key part %d on line %d (%s): %w",
+					i, line, keyPart, err,
+				)
+			}
+		}
+
+		owner := keyParts[0]
+
+		if filter && !containsAddress(addresses, common.BytesToAddress(owner)) {
+			continue
+		}
+
+		value, err := hex.DecodeString(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value on line %d: %w", line, err)
+		}
+
+		payloads = append(payloads, payload.Payload{
+			Owner: owner,
+			Key:   string(keyParts[2]),
+			Value: value,
+		})
+	}
+
+	return payloads, nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func printReport(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, account := range report.Accounts {
+		_, err := fmt.Fprintf(
+			tw,
+			"Account %s: %d slabs (%d bytes, %d unreferenced)\n",
+			account.Address,
+			account.SlabCount,
+			account.SlabSize,
+			account.UnreferencedSlabCount,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range account.Values {
+			if value.Error != "" {
+				_, err = fmt.Fprintf(tw, "  %s\terror: %s\n", value.Path, value.Error)
+			} else {
+				_, err = fmt.Fprintf(tw, "  %s\t%s\t%d bytes\n", value.Path, value.Type, value.Size)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, register := range account.Registers {
+			_, err = fmt.Fprintf(tw, "  register %s\t\t%d bytes\n", register.Key, register.Size)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Flush()
+}
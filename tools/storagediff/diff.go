@@ -0,0 +1,429 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storagediff compares the values stored in two sets of ledger payloads,
+// for example before and after a storage migration.
+//
+// Values are compared semantically, i.e. by their static types and contents,
+// so differences in the encoding of values are ignored, e.g. a different encoding version,
+// a value moved from a path register into a storage map, or a container split into different slabs.
+//
+package storagediff
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/tools/internal/payload"
+)
+
+// Payload is a raw ledger register: the owner, the key, and the encoded value
+//
+type Payload = payload.Payload
+
+// Difference is a difference between a value before and after.
+//
+// Path is the storage path of the stored value, followed by the accessed
+// fields (e.g. `.balance`), array indices (e.g. `[0]`), and dictionary keys (e.g. `["a"]`).
+// Before and After are the string representations of the values, if any
+//
+type Difference struct {
+	Kind    DifferenceKind
+	Address common.Address
+	Path    string
+	Before  string
+	After   string
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DifferenceKindAdded:
+		return fmt.Sprintf("%s %s: added %s", d.Address, d.Path, d.After)
+	case DifferenceKindRemoved:
+		return fmt.Sprintf("%s %s: removed %s", d.Address, d.Path, d.Before)
+	default:
+		return fmt.Sprintf("%s %s: changed from %s to %s", d.Address, d.Path, d.Before, d.After)
+	}
+}
+
+// Diff decodes the values stored in the given payloads,
+// and returns the differences between the values before and after,
+// ordered by address and path.
+//
+// An error is returned if any stored value cannot be decoded
+//
+func Diff(before, after []Payload) (differences []Difference, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			default:
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	beforeValues, err := loadValues(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load values before: %w", err)
+	}
+
+	afterValues, err := loadValues(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load values after: %w", err)
+	}
+
+	d := &differ{}
+
+	for _, address := range sortedAddresses(beforeValues, afterValues) {
+		beforeAccountValues := beforeValues[address]
+		afterAccountValues := afterValues[address]
+
+		d.address = address
+
+		for _, path := range sortedKeys(beforeAccountValues, afterAccountValues) {
+			d.diff(path, beforeAccountValues[path], afterAccountValues[path])
+		}
+	}
+
+	return d.differences, nil
+}
+
+type differ struct {
+	address     common.Address
+	differences []Difference
+}
+
+func (d *differ) report(kind DifferenceKind, path string, before, after interpreter.Value) {
+	difference := Difference{
+		Kind:    kind,
+		Address: d.address,
+		Path:    path,
+	}
+	if before != nil {
+		difference.Before = before.String()
+	}
+	if after != nil {
+		difference.After = after.String()
+	}
+
+	d.differences = append(d.differences, difference)
+}
+
+func (d *differ) diff(path string, before, after interpreter.Value) {
+
+	switch {
+	case before == nil && after == nil:
+		return
+
+	case before == nil:
+		d.report(DifferenceKindAdded, path, nil, after)
+		return
+
+	case after == nil:
+		d.report(DifferenceKindRemoved, path, before, nil)
+		return
+	}
+
+	// Optionals are transparent, only their contents are compared
+
+	beforeSome, beforeIsSome := before.(*interpreter.SomeValue)
+	afterSome, afterIsSome := after.(*interpreter.SomeValue)
+	if beforeIsSome && afterIsSome {
+		d.diff(path, beforeSome.Value, afterSome.Value)
+		return
+	}
+
+	if staticTypeString(before) != staticTypeString(after) {
+		d.report(DifferenceKindChanged, path, before, after)
+		return
+	}
+
+	switch before := before.(type) {
+	case *interpreter.CompositeValue:
+		d.diffFields(
+			path,
+			compositeFields(before),
+			compositeFields(after.(*interpreter.CompositeValue)),
+		)
+
+	case *interpreter.ArrayValue:
+		d.diffElements(
+			path,
+			arrayElements(before),
+			arrayElements(after.(*interpreter.ArrayValue)),
+		)
+
+	case *interpreter.DictionaryValue:
+		d.diffEntries(
+			path,
+			dictionaryEntries(before),
+			dictionaryEntries(after.(*interpreter.DictionaryValue)),
+		)
+
+	default:
+		if before.String() != after.String() {
+			d.report(DifferenceKindChanged, path, before, after)
+		}
+	}
+}
+
+func (d *differ) diffFields(path string, before, after map[string]interpreter.Value) {
+	for _, name := range sortedKeys(before, after) {
+		d.diff(
+			fmt.Sprintf("%s.%s", path, name),
+			before[name],
+			after[name],
+		)
+	}
+}
+
+func (d *differ) diffElements(path string, before, after []interpreter.Value) {
+	count := len(before)
+	if len(after) > count {
+		count = len(after)
+	}
+
+	for i := 0; i < count; i++ {
+		var beforeElement, afterElement interpreter.Value
+		if i < len(before) {
+			beforeElement = before[i]
+		}
+		if i < len(after) {
+			afterElement = after[i]
+		}
+
+		d.diff(
+			fmt.Sprintf("%s[%d]", path, i),
+			beforeElement,
+			afterElement,
+		)
+	}
+}
+
+func (d *differ) diffEntries(path string, before, after map[string]interpreter.Value) {
+	for _, key := range sortedKeys(before, after) {
+		d.diff(
+			fmt.Sprintf("%s[%s]", path, key),
+			before[key],
+			after[key],
+		)
+	}
+}
+
+func staticTypeString(value interpreter.Value) string {
+	staticType := value.StaticType()
+	if staticType == nil {
+		return fmt.Sprintf("%T", value)
+	}
+	return staticType.String()
+}
+
+func compositeFields(value *interpreter.CompositeValue) map[string]interpreter.Value {
+	fields := map[string]interpreter.Value{}
+	value.ForEachField(func(name string, value interpreter.Value) {
+		fields[name] = value
+	})
+	return fields
+}
+
+func arrayElements(value *interpreter.ArrayValue) []interpreter.Value {
+	var elements []interpreter.Value
+	value.Iterate(func(element interpreter.Value) (resume bool) {
+		elements = append(elements, element)
+		return true
+	})
+	return elements
+}
+
+// dictionaryEntries returns the entries of the given dictionary,
+// keyed by the string representation of the keys
+//
+func dictionaryEntries(value *interpreter.DictionaryValue) map[string]interpreter.Value {
+	entries := map[string]interpreter.Value{}
+	value.Iterate(func(key, value interpreter.Value) (resume bool) {
+		entries[key.String()] = value
+		return true
+	})
+	return entries
+}
+
+func sortedKeys(a, b map[string]interpreter.Value) []string {
+	keys := make([]string, 0, len(a))
+
+	// NOTE: iteration over map is safe,
+	// as result is sorted below
+
+	for key := range a { //nolint:maprangecheck
+		keys = append(keys, key)
+	}
+
+	for key := range b { //nolint:maprangecheck
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedAddresses(a, b map[common.Address]map[string]interpreter.Value) []common.Address {
+	addresses := make([]common.Address, 0, len(a))
+
+	// NOTE: iteration over map is safe,
+	// as result is sorted below
+
+	for address := range a { //nolint:maprangecheck
+		addresses = append(addresses, address)
+	}
+
+	for address := range b { //nolint:maprangecheck
+		if _, ok := a[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	return addresses
+}
+
+// loading
+
+const storagePathSeparator = "\x1f"
+
+// '$' + 8 byte index
+const slabKeyLength = 9
+
+const storageIndexLength = 8
+
+// storageDomains are the domains of storage maps
+//
+var storageDomains = map[string]struct{}{
+	common.PathDomainStorage.Identifier(): {},
+	common.PathDomainPrivate.Identifier(): {},
+	common.PathDomainPublic.Identifier():  {},
+	"contract":                            {},
+}
+
+// loadValues decodes the values stored in the given payloads,
+// keyed by address and path (e.g. `/storage/vault`)
+//
+func loadValues(payloads []Payload) (map[common.Address]map[string]interpreter.Value, error) {
+	ledger := payload.NewLedger(payloads)
+
+	storage := atree.NewPersistentSlabStorage(
+		atree.NewLedgerBaseStorage(ledger),
+		interpreter.CBOREncMode,
+		interpreter.CBORDecMode,
+		interpreter.DecodeStorable,
+		interpreter.DecodeTypeInfo,
+	)
+
+	values := map[common.Address]map[string]interpreter.Value{}
+
+	addValue := func(address common.Address, path string, value interpreter.Value) {
+		accountValues, ok := values[address]
+		if !ok {
+			accountValues = map[string]interpreter.Value{}
+			values[address] = accountValues
+		}
+		accountValues[path] = value
+	}
+
+	for _, owner := range ledger.Owners() {
+		address := common.BytesToAddress([]byte(owner))
+
+		for _, key := range ledger.Keys(owner) {
+			data := ledger.Value(owner, key)
+
+			if len(key) == slabKeyLength && key[0] == '$' {
+				continue
+			}
+
+			// Storage map
+
+			if _, ok := storageDomains[key]; ok && len(data) == storageIndexLength {
+				domain := key
+
+				storageID := atree.StorageID{
+					Address: atree.Address(address),
+				}
+				copy(storageID.Index[:], data)
+
+				storageMap := interpreter.NewStorageMapWithRootID(storage, storageID)
+
+				iterator := storageMap.Iterator()
+				for {
+					identifier, value := iterator.Next()
+					if identifier == "" {
+						break
+					}
+					addValue(address, payload.FormatPath(domain, identifier), value)
+				}
+
+				continue
+			}
+
+			// Value stored directly in a path register
+
+			keyParts := strings.SplitN(key, storagePathSeparator, 2)
+			if len(keyParts) != 2 {
+				continue
+			}
+
+			domain, identifier := keyParts[0], keyParts[1]
+			if _, ok := storageDomains[domain]; !ok {
+				continue
+			}
+
+			path := payload.FormatPath(domain, identifier)
+
+			decoder := interpreter.CBORDecMode.NewStreamDecoder(bytes.NewReader(data))
+			storable, err := interpreter.DecodeStorable(decoder, atree.StorageIDUndefined)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode storable @ %s %s: %w", address, path, err)
+			}
+
+			atreeValue, err := storable.StoredValue(storage)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load stored value @ %s %s: %w", address, path, err)
+			}
+
+			value, err := interpreter.ConvertStoredValue(atreeValue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert stored value @ %s %s: %w", address, path, err)
+			}
+
+			addValue(address, path, value)
+		}
+	}
+
+	return values, nil
+}
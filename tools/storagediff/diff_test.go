@@ -0,0 +1,351 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storagediff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type payloadKey struct {
+	owner string
+	key   string
+}
+
+type testLedger struct {
+	payloads       map[payloadKey][]byte
+	storageIndices map[string]uint64
+}
+
+var _ atree.Ledger = &testLedger{}
+
+func (l *testLedger) GetValue(owner, key []byte) ([]byte, error) {
+	return l.payloads[payloadKey{owner: string(owner), key: string(key)}], nil
+}
+
+func (l *testLedger) SetValue(owner, key, value []byte) error {
+	l.payloads[payloadKey{owner: string(owner), key: string(key)}] = value
+	return nil
+}
+
+func (l *testLedger) ValueExists(owner, key []byte) (bool, error) {
+	return len(l.payloads[payloadKey{owner: string(owner), key: string(key)}]) > 0, nil
+}
+
+func (l *testLedger) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := l.storageIndices[string(owner)] + 1
+	l.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+var testAddress = common.BytesToAddress([]byte{0x1})
+
+// newTestPayloads returns the payloads of a storage,
+// after the given function wrote values to it
+//
+func newTestPayloads(
+	t *testing.T,
+	write func(inter *interpreter.Interpreter, storage *runtime.Storage),
+) []Payload {
+
+	ledger := &testLedger{
+		payloads:       map[payloadKey][]byte{},
+		storageIndices: map[string]uint64{},
+	}
+
+	storage := runtime.NewStorage(ledger)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		utils.TestLocation,
+		interpreter.WithStorage(storage),
+	)
+	require.NoError(t, err)
+
+	write(inter, storage)
+
+	err = storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	var payloads []Payload
+	for key, value := range ledger.payloads { //nolint:maprangecheck
+		payloads = append(payloads, Payload{
+			Owner: []byte(key.owner),
+			Key:   key.key,
+			Value: value,
+		})
+	}
+
+	return payloads
+}
+
+func writeStorageValue(inter *interpreter.Interpreter, storage *runtime.Storage, identifier string, value interpreter.Value) {
+	storage.GetStorageMap(testAddress, common.PathDomainStorage.Identifier(), true).
+		WriteValue(inter, identifier, value)
+}
+
+func newTestComposite(inter *interpreter.Interpreter, fields ...interpreter.CompositeField) *interpreter.CompositeValue {
+	return interpreter.NewCompositeValue(
+		inter,
+		utils.TestLocation,
+		"Test",
+		common.CompositeKindStructure,
+		fields,
+		testAddress,
+	)
+}
+
+func TestDiff(t *testing.T) {
+
+	t.Parallel()
+
+	before := newTestPayloads(t, func(inter *interpreter.Interpreter, storage *runtime.Storage) {
+		writeStorageValue(inter, storage, "removed", interpreter.NewStringValue("bye"))
+		writeStorageValue(inter, storage, "unchanged", interpreter.NewStringValue("same"))
+		writeStorageValue(inter, storage, "retyped", interpreter.NewIntValueFromInt64(1))
+		writeStorageValue(
+			inter,
+			storage,
+			"array",
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				testAddress,
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+			),
+		)
+		writeStorageValue(
+			inter,
+			storage,
+			"dictionary",
+			interpreter.NewDictionaryValueWithAddress(
+				inter,
+				interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeInt,
+				},
+				testAddress,
+				interpreter.NewStringValue("a"), interpreter.NewIntValueFromInt64(1),
+			),
+		)
+		writeStorageValue(
+			inter,
+			storage,
+			"composite",
+			newTestComposite(
+				inter,
+				interpreter.CompositeField{
+					Name:  "a",
+					Value: interpreter.NewIntValueFromInt64(1),
+				},
+				interpreter.CompositeField{
+					Name:  "b",
+					Value: interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(2)),
+				},
+			),
+		)
+	})
+
+	after := newTestPayloads(t, func(inter *interpreter.Interpreter, storage *runtime.Storage) {
+		writeStorageValue(inter, storage, "added", interpreter.NewStringValue("hi"))
+		writeStorageValue(inter, storage, "unchanged", interpreter.NewStringValue("same"))
+		writeStorageValue(inter, storage, "retyped", interpreter.UInt8Value(1))
+		writeStorageValue(
+			inter,
+			storage,
+			"array",
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				testAddress,
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(3),
+				interpreter.NewIntValueFromInt64(4),
+			),
+		)
+		writeStorageValue(
+			inter,
+			storage,
+			"dictionary",
+			interpreter.NewDictionaryValueWithAddress(
+				inter,
+				interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeInt,
+				},
+				testAddress,
+				interpreter.NewStringValue("a"), interpreter.NewIntValueFromInt64(1),
+				interpreter.NewStringValue("b"), interpreter.NewIntValueFromInt64(2),
+			),
+		)
+		writeStorageValue(
+			inter,
+			storage,
+			"composite",
+			newTestComposite(
+				inter,
+				interpreter.CompositeField{
+					Name:  "b",
+					Value: interpreter.NewSomeValueNonCopying(interpreter.NewIntValueFromInt64(3)),
+				},
+			),
+		)
+	})
+
+	differences, err := Diff(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]Difference{
+			{
+				Kind:    DifferenceKindAdded,
+				Address: testAddress,
+				Path:    "/storage/added",
+				After:   `"hi"`,
+			},
+			{
+				Kind:    DifferenceKindChanged,
+				Address: testAddress,
+				Path:    "/storage/array[1]",
+				Before:  "2",
+				After:   "3",
+			},
+			{
+				Kind:    DifferenceKindAdded,
+				Address: testAddress,
+				Path:    "/storage/array[2]",
+				After:   "4",
+			},
+			{
+				Kind:    DifferenceKindRemoved,
+				Address: testAddress,
+				Path:    "/storage/composite.a",
+				Before:  "1",
+			},
+			{
+				Kind:    DifferenceKindChanged,
+				Address: testAddress,
+				Path:    "/storage/composite.b",
+				Before:  "2",
+				After:   "3",
+			},
+			{
+				Kind:    DifferenceKindAdded,
+				Address: testAddress,
+				Path:    `/storage/dictionary["b"]`,
+				After:   "2",
+			},
+			{
+				Kind:    DifferenceKindRemoved,
+				Address: testAddress,
+				Path:    "/storage/removed",
+				Before:  `"bye"`,
+			},
+			{
+				Kind:    DifferenceKindChanged,
+				Address: testAddress,
+				Path:    "/storage/retyped",
+				Before:  "1",
+				After:   "1",
+			},
+		},
+		differences,
+	)
+}
+
+func TestDiffIgnoresEncoding(t *testing.T) {
+
+	t.Parallel()
+
+	// Before, the value is stored directly in a path register
+
+	var encoded bytes.Buffer
+	encoder := atree.NewEncoder(&encoded, interpreter.CBOREncMode)
+	err := interpreter.NewIntValueFromInt64(42).Encode(encoder)
+	require.NoError(t, err)
+	err = encoder.CBOR.Flush()
+	require.NoError(t, err)
+
+	before := []Payload{
+		{
+			Owner: testAddress[:],
+			Key:   "storage\x1fanswer",
+			Value: encoded.Bytes(),
+		},
+	}
+
+	// After, the value is stored in the storage map
+
+	after := newTestPayloads(t, func(inter *interpreter.Interpreter, storage *runtime.Storage) {
+		writeStorageValue(inter, storage, "answer", interpreter.NewIntValueFromInt64(42))
+	})
+
+	differences, err := Diff(before, after)
+	require.NoError(t, err)
+	assert.Empty(t, differences)
+}
+
+func TestDiffInvalidPayload(t *testing.T) {
+
+	t.Parallel()
+
+	before := []Payload{
+		{
+			Owner: testAddress[:],
+			Key:   "storage\x1fbroken",
+			Value: []byte{0xff},
+		},
+	}
+
+	_, err := Diff(before, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load values before")
+}
+
+func TestDifferenceString(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t,
+		"0000000000000001 /storage/a.b: changed from 1 to 2",
+		Difference{
+			Kind:    DifferenceKindChanged,
+			Address: testAddress,
+			Path:    "/storage/a.b",
+			Before:  "1",
+			After:   "2",
+		}.String(),
+	)
+}
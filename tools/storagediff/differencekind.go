@@ -0,0 +1,43 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storagediff
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=DifferenceKind
+
+type DifferenceKind uint
+
+const (
+	DifferenceKindUnknown DifferenceKind = iota
+	DifferenceKindAdded
+	DifferenceKindRemoved
+	DifferenceKindChanged
+)
+
+func (k DifferenceKind) Name() string {
+	switch k {
+	case DifferenceKindAdded:
+		return "added"
+	case DifferenceKindRemoved:
+		return "removed"
+	case DifferenceKindChanged:
+		return "changed"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,316 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// ErrInvalidTypeID is returned by ParseTypeID
+// if the given string is not a valid type ID
+//
+var ErrInvalidTypeID = common.ErrInvalidTypeID
+
+// NominalTypeResolver returns the type for a nominal type,
+// i.e. a composite or interface type, which is declared at the given location.
+// The location is nil for built-in composite types, e.g. `PublicKey`.
+//
+// The restricted type is nil, unless the nominal type occurs as a restriction
+// of a restricted type, e.g. `NFT.Receiver` in `NFT.Collection{NFT.Receiver}`.
+//
+type NominalTypeResolver func(
+	location common.Location,
+	qualifiedIdentifier string,
+	restrictedType Type,
+) (
+	Type,
+	error,
+)
+
+var simpleTypesByID = func() map[string]Type {
+	types := []Type{
+		AnyType{},
+		AnyStructType{},
+		AnyResourceType{},
+		MetaType{},
+		VoidType{},
+		NeverType{},
+		BoolType{},
+		StringType{},
+		CharacterType{},
+		BytesType{},
+		AddressType{},
+		NumberType{},
+		SignedNumberType{},
+		IntegerType{},
+		SignedIntegerType{},
+		FixedPointType{},
+		SignedFixedPointType{},
+		IntType{},
+		Int8Type{},
+		Int16Type{},
+		Int32Type{},
+		Int64Type{},
+		Int128Type{},
+		Int256Type{},
+		UIntType{},
+		UInt8Type{},
+		UInt16Type{},
+		UInt32Type{},
+		UInt64Type{},
+		UInt128Type{},
+		UInt256Type{},
+		Word8Type{},
+		Word16Type{},
+		Word32Type{},
+		Word64Type{},
+		Fix64Type{},
+		UFix64Type{},
+		Fix128Type{},
+		UFix128Type{},
+		BlockType{},
+		TimestampType{},
+		DurationType{},
+		PathType{},
+		CapabilityPathType{},
+		StoragePathType{},
+		PublicPathType{},
+		PrivatePathType{},
+		AuthAccountType{},
+		PublicAccountType{},
+		AuthAccountKeysType{},
+		PublicAccountKeysType{},
+		AuthAccountContractsType{},
+		PublicAccountContractsType{},
+		DeployedContractType{},
+		AccountKeyType{},
+	}
+
+	typesByID := make(map[string]Type, len(types))
+	for _, ty := range types {
+		typesByID[ty.ID()] = ty
+	}
+	return typesByID
+}()
+
+// ParseTypeID parses the given type ID, e.g. `A.0x1.FungibleToken.Vault`,
+// `[String]`, or `&AnyResource{A.0x1.NFT.Receiver}`, into a type.
+// The ID of the resulting type is the canonical form of the given type ID (see common.TypeIDVersion).
+//
+// Nominal types, i.e. composite and interface types, are resolved using the given resolver.
+// If the resolver is nil, nominal types are parsed as struct types without any fields,
+// and restrictions are parsed as interface types of the same kind as the restricted type,
+// e.g. resource interface types if the restricted type is `AnyResource`.
+//
+func ParseTypeID(typeID string, resolve NominalTypeResolver) (Type, error) {
+	if resolve == nil {
+		resolve = defaultNominalTypeResolver
+	}
+
+	parsedType, err := common.ParseTypeID(typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertParsedType(parsedType, resolve)
+}
+
+func defaultNominalTypeResolver(
+	location common.Location,
+	qualifiedIdentifier string,
+	restrictedType Type,
+) (
+	Type,
+	error,
+) {
+	if restrictedType == nil {
+		return &StructType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}, nil
+	}
+
+	switch restrictedType.(type) {
+	case AnyResourceType, *ResourceType:
+		return &ResourceInterfaceType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}, nil
+	default:
+		return &StructInterfaceType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+		}, nil
+	}
+}
+
+func convertParsedType(parsedType common.ParsedType, resolve NominalTypeResolver) (Type, error) {
+	switch parsedType := parsedType.(type) {
+	case *common.ParsedNominalType:
+		return convertParsedNominalType(parsedType, nil, resolve)
+
+	case *common.ParsedOptionalType:
+		ty, err := convertParsedType(parsedType.Type, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return OptionalType{Type: ty}, nil
+
+	case *common.ParsedVariableSizedType:
+		elementType, err := convertParsedType(parsedType.ElementType, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return VariableSizedArrayType{
+			ElementType: elementType,
+		}, nil
+
+	case *common.ParsedConstantSizedType:
+		elementType, err := convertParsedType(parsedType.ElementType, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return ConstantSizedArrayType{
+			Size:        uint(parsedType.Size),
+			ElementType: elementType,
+		}, nil
+
+	case *common.ParsedDictionaryType:
+		keyType, err := convertParsedType(parsedType.KeyType, resolve)
+		if err != nil {
+			return nil, err
+		}
+		elementType, err := convertParsedType(parsedType.ValueType, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return DictionaryType{
+			KeyType:     keyType,
+			ElementType: elementType,
+		}, nil
+
+	case *common.ParsedReferenceType:
+		ty, err := convertParsedType(parsedType.Type, resolve)
+		if err != nil {
+			return nil, err
+		}
+
+		var entitlements []string
+		for _, entitlement := range parsedType.Entitlements {
+			entitlements = append(entitlements, string(entitlement.ID()))
+		}
+
+		return ReferenceType{
+			Authorized:   parsedType.Authorized,
+			Entitlements: entitlements,
+			Type:         ty,
+		}, nil
+
+	case *common.ParsedRestrictedType:
+		ty, err := convertParsedType(parsedType.Type, resolve)
+		if err != nil {
+			return nil, err
+		}
+
+		var restrictions []Type
+		for _, parsedRestriction := range parsedType.Restrictions {
+			restriction, err := convertParsedNominalType(parsedRestriction, ty, resolve)
+			if err != nil {
+				return nil, err
+			}
+			restrictions = append(restrictions, restriction)
+		}
+
+		return RestrictedType{
+			Type:         ty,
+			Restrictions: restrictions,
+		}.WithID(string(parsedType.ID())), nil
+
+	case *common.ParsedCapabilityType:
+		if parsedType.BorrowType == nil {
+			return CapabilityType{}, nil
+		}
+
+		borrowType, err := convertParsedType(parsedType.BorrowType, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return CapabilityType{
+			BorrowType: borrowType,
+		}, nil
+
+	case *common.ParsedFunctionType:
+
+		// Type parameters are only preserved in the ID of the resulting type
+
+		var parameters []Parameter
+		for _, parsedParameterType := range parsedType.Parameters {
+			parameterType, err := convertParsedType(parsedParameterType, resolve)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(
+				parameters,
+				Parameter{
+					Type: parameterType,
+				},
+			)
+		}
+
+		returnType, err := convertParsedType(parsedType.ReturnType, resolve)
+		if err != nil {
+			return nil, err
+		}
+
+		return FunctionType{
+			Parameters: parameters,
+			ReturnType: returnType,
+		}.WithID(string(parsedType.ID())), nil
+
+	default:
+		panic(fmt.Errorf("unsupported parsed type: %T", parsedType))
+	}
+}
+
+// convertParsedNominalType converts a parsed nominal type.
+// Built-in simple types, e.g. `Int`, are not resolved
+//
+func convertParsedNominalType(
+	parsedType *common.ParsedNominalType,
+	restrictedType Type,
+	resolve NominalTypeResolver,
+) (
+	Type,
+	error,
+) {
+	if restrictedType == nil && parsedType.Location == nil {
+		if ty, ok := simpleTypesByID[parsedType.QualifiedIdentifier]; ok {
+			return ty, nil
+		}
+	}
+
+	ty, err := resolve(parsedType.Location, parsedType.QualifiedIdentifier, restrictedType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve type %s: %w", parsedType.ID(), err)
+	}
+
+	return ty, nil
+}
@@ -0,0 +1,234 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestParseTypeID(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	vaultType := &StructType{
+		Location: common.AddressLocation{
+			Address: address,
+			Name:    "FungibleToken",
+		},
+		QualifiedIdentifier: "FungibleToken.Vault",
+	}
+
+	receiverType := &ResourceInterfaceType{
+		Location: common.AddressLocation{
+			Address: address,
+			Name:    "NFT",
+		},
+		QualifiedIdentifier: "NFT.Receiver",
+	}
+
+	type testCase struct {
+		typeID   string
+		expected Type
+	}
+
+	testCases := []testCase{
+		{"Int", IntType{}},
+		{"Type", MetaType{}},
+		{"AuthAccount.Keys", AuthAccountKeysType{}},
+		{"String?", OptionalType{Type: StringType{}}},
+		{"String??", OptionalType{Type: OptionalType{Type: StringType{}}}},
+		{"[String]", VariableSizedArrayType{ElementType: StringType{}}},
+		{"[String;2]", ConstantSizedArrayType{ElementType: StringType{}, Size: 2}},
+		{"{String:[Int?]}", DictionaryType{
+			KeyType:     StringType{},
+			ElementType: VariableSizedArrayType{ElementType: OptionalType{Type: IntType{}}},
+		}},
+		{"&Int", ReferenceType{Type: IntType{}}},
+		{"auth &Int", ReferenceType{Authorized: true, Type: IntType{}}},
+		{"&Int?", OptionalType{Type: ReferenceType{Type: IntType{}}}},
+		{
+			"auth(S.test.E1, S.test.E2) &Int",
+			ReferenceType{
+				Entitlements: []string{"S.test.E1", "S.test.E2"},
+				Type:         IntType{},
+			},
+		},
+		{"Capability", CapabilityType{}},
+		{"Capability<&Int>", CapabilityType{BorrowType: ReferenceType{Type: IntType{}}}},
+		{"A.0000000000000001.FungibleToken.Vault", vaultType},
+		{"S.test.Foo", &StructType{Location: utils.TestLocation, QualifiedIdentifier: "Foo"}},
+		{"PublicKey", &StructType{QualifiedIdentifier: "PublicKey"}},
+		{
+			"&AnyResource{A.0000000000000001.NFT.Receiver}",
+			ReferenceType{
+				Type: RestrictedType{
+					Type:         AnyResourceType{},
+					Restrictions: []Type{receiverType},
+				}.WithID("AnyResource{A.0000000000000001.NFT.Receiver}"),
+			},
+		},
+		{
+			"AnyStruct{S.test.I1,S.test.I2}",
+			RestrictedType{
+				Type: AnyStructType{},
+				Restrictions: []Type{
+					&StructInterfaceType{Location: utils.TestLocation, QualifiedIdentifier: "I1"},
+					&StructInterfaceType{Location: utils.TestLocation, QualifiedIdentifier: "I2"},
+				},
+			}.WithID("AnyStruct{S.test.I1,S.test.I2}"),
+		},
+		{
+			"((Int,[String]):Bool)",
+			FunctionType{
+				Parameters: []Parameter{
+					{Type: IntType{}},
+					{Type: VariableSizedArrayType{ElementType: StringType{}}},
+				},
+				ReturnType: BoolType{},
+			}.WithID("((Int,[String]):Bool)"),
+		},
+		{
+			"(<AnyStruct>():Void)",
+			FunctionType{
+				ReturnType: VoidType{},
+			}.WithID("(<AnyStruct>():Void)"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.typeID, func(t *testing.T) {
+
+			t.Parallel()
+
+			ty, err := ParseTypeID(testCase.typeID, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, ty)
+			assert.Equal(t, testCase.typeID, ty.ID())
+		})
+	}
+}
+
+func TestParseTypeID_AuthorizedWithoutSpace(t *testing.T) {
+
+	t.Parallel()
+
+	ty, err := ParseTypeID("auth&Int", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		ReferenceType{
+			Authorized: true,
+			Type:       IntType{},
+		},
+		ty,
+	)
+}
+
+func TestParseTypeID_Resolver(t *testing.T) {
+
+	t.Parallel()
+
+	collectionType := &ResourceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "NFT.Collection",
+	}
+
+	receiverType := &ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "NFT.Receiver",
+	}
+
+	types := map[string]Type{
+		collectionType.ID(): collectionType,
+		receiverType.ID():   receiverType,
+	}
+
+	resolve := func(location common.Location, qualifiedIdentifier string, _ Type) (Type, error) {
+		ty, ok := types[string(location.TypeID(qualifiedIdentifier))]
+		if !ok {
+			return nil, errors.New("unknown type")
+		}
+		return ty, nil
+	}
+
+	const typeID = "[S.test.NFT.Collection{S.test.NFT.Receiver}]"
+
+	ty, err := ParseTypeID(typeID, resolve)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		VariableSizedArrayType{
+			ElementType: RestrictedType{
+				Type:         collectionType,
+				Restrictions: []Type{receiverType},
+			}.WithID("S.test.NFT.Collection{S.test.NFT.Receiver}"),
+		},
+		ty,
+	)
+	assert.Equal(t, typeID, ty.ID())
+
+	_, err = ParseTypeID("S.test.Unknown", resolve)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown type")
+}
+
+func TestParseTypeID_Invalid(t *testing.T) {
+
+	t.Parallel()
+
+	for _, typeID := range []string{
+		"",
+		"[Int",
+		"[Int;]",
+		"[Int;-1]",
+		"{String}",
+		"{String:Int",
+		"Int?!",
+		"&",
+		"Capability<Int",
+		"((Int):Bool",
+		"(Int:Bool)",
+		"AnyResource{",
+		"AnyResource{S.test.I,}",
+		"A.invalid.Foo",
+	} {
+		typeID := typeID
+
+		t.Run(typeID, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseTypeID(typeID, nil)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidTypeID))
+		})
+	}
+}
@@ -19,8 +19,6 @@
 package cadence
 
 import (
-	"fmt"
-
 	"github.com/onflow/cadence/runtime/common"
 )
 
@@ -68,7 +66,7 @@ type OptionalType struct {
 func (OptionalType) isType() {}
 
 func (t OptionalType) ID() string {
-	return fmt.Sprintf("%s?", t.Type.ID())
+	return string(common.NewOptionalTypeID(common.TypeID(t.Type.ID())))
 }
 
 // MetaType
@@ -411,6 +409,26 @@ func (UFix64Type) ID() string {
 	return "UFix64"
 }
 
+// Fix128Type
+
+type Fix128Type struct{}
+
+func (Fix128Type) isType() {}
+
+func (Fix128Type) ID() string {
+	return "Fix128"
+}
+
+// UFix128Type
+
+type UFix128Type struct{}
+
+func (UFix128Type) isType() {}
+
+func (UFix128Type) ID() string {
+	return "UFix128"
+}
+
 type ArrayType interface {
 	Type
 	Element() Type
@@ -425,7 +443,7 @@ type VariableSizedArrayType struct {
 func (VariableSizedArrayType) isType() {}
 
 func (t VariableSizedArrayType) ID() string {
-	return fmt.Sprintf("[%s]", t.ElementType.ID())
+	return string(common.NewVariableSizedTypeID(common.TypeID(t.ElementType.ID())))
 }
 
 func (t VariableSizedArrayType) Element() Type {
@@ -442,7 +460,10 @@ type ConstantSizedArrayType struct {
 func (ConstantSizedArrayType) isType() {}
 
 func (t ConstantSizedArrayType) ID() string {
-	return fmt.Sprintf("[%s;%d]", t.ElementType.ID(), t.Size)
+	return string(common.NewConstantSizedTypeID(
+		common.TypeID(t.ElementType.ID()),
+		int64(t.Size),
+	))
 }
 
 func (t ConstantSizedArrayType) Element() Type {
@@ -459,11 +480,10 @@ type DictionaryType struct {
 func (DictionaryType) isType() {}
 
 func (t DictionaryType) ID() string {
-	return fmt.Sprintf(
-		"{%s:%s}",
-		t.KeyType.ID(),
-		t.ElementType.ID(),
-	)
+	return string(common.NewDictionaryTypeID(
+		common.TypeID(t.KeyType.ID()),
+		common.TypeID(t.ElementType.ID()),
+	))
 }
 
 // Field
@@ -573,6 +593,10 @@ type EventType struct {
 	QualifiedIdentifier string
 	Fields              []Field
 	Initializer         []Parameter
+	// IndexedFields are the identifiers of the fields which are marked as indexed
+	// in the event declaration, in declaration order.
+	// They are a hint for systems which index emitted events
+	IndexedFields []string
 }
 
 func (*EventType) isType() {}
@@ -603,6 +627,18 @@ func (t *EventType) CompositeInitializers() [][]Parameter {
 	return [][]Parameter{t.Initializer}
 }
 
+// IsIndexedField returns true if the field with the given identifier
+// is marked as indexed in the event declaration
+//
+func (t *EventType) IsIndexedField(identifier string) bool {
+	for _, indexedField := range t.IndexedFields {
+		if indexedField == identifier {
+			return true
+		}
+	}
+	return false
+}
+
 // ContractType
 
 type ContractType struct {
@@ -773,7 +809,24 @@ type FunctionType struct {
 func (FunctionType) isType() {}
 
 func (t FunctionType) ID() string {
-	return t.typeID
+	if t.typeID != "" {
+		return t.typeID
+	}
+
+	// Function types which were not exported from a program,
+	// e.g. types constructed by clients, have no type ID set.
+	// Use the same format as the type checker
+
+	parameters := make([]common.TypeID, len(t.Parameters))
+	for i, parameter := range t.Parameters {
+		parameters[i] = common.TypeID(parameter.Type.ID())
+	}
+
+	return string(common.NewFunctionTypeID(
+		nil,
+		parameters,
+		common.TypeID(t.ReturnType.ID()),
+	))
 }
 
 func (t FunctionType) WithID(id string) FunctionType {
@@ -785,17 +838,25 @@ func (t FunctionType) WithID(id string) FunctionType {
 
 type ReferenceType struct {
 	Authorized bool
-	Type       Type
+	// Entitlements are the type IDs of the entitlements
+	// granted by the reference, if it is not fully authorized
+	Entitlements []string
+	Type         Type
 }
 
 func (ReferenceType) isType() {}
 
 func (t ReferenceType) ID() string {
-	id := fmt.Sprintf("&%s", t.Type.ID())
-	if t.Authorized {
-		id = "auth" + id
+	entitlements := make([]common.TypeID, len(t.Entitlements))
+	for i, entitlement := range t.Entitlements {
+		entitlements[i] = common.TypeID(entitlement)
 	}
-	return id
+
+	return string(common.NewReferenceTypeID(
+		t.Authorized,
+		entitlements,
+		common.TypeID(t.Type.ID()),
+	))
 }
 
 // RestrictedType
@@ -809,7 +870,23 @@ type RestrictedType struct {
 func (RestrictedType) isType() {}
 
 func (t RestrictedType) ID() string {
-	return t.typeID
+	if t.typeID != "" {
+		return t.typeID
+	}
+
+	// Restricted types which were not exported from a program,
+	// e.g. types constructed by clients, have no type ID set.
+	// Use the same format as the type checker
+
+	restrictions := make([]common.TypeID, len(t.Restrictions))
+	for i, restriction := range t.Restrictions {
+		restrictions[i] = common.TypeID(restriction.ID())
+	}
+
+	return string(common.NewRestrictedTypeID(
+		common.TypeID(t.Type.ID()),
+		restrictions,
+	))
 }
 
 func (t RestrictedType) WithID(id string) RestrictedType {
@@ -827,6 +904,26 @@ func (BlockType) ID() string {
 	return "Block"
 }
 
+// TimestampType
+
+type TimestampType struct{}
+
+func (TimestampType) isType() {}
+
+func (TimestampType) ID() string {
+	return "Timestamp"
+}
+
+// DurationType
+
+type DurationType struct{}
+
+func (DurationType) isType() {}
+
+func (DurationType) ID() string {
+	return "Duration"
+}
+
 // PathType
 
 type PathType struct{}
@@ -886,10 +983,11 @@ type CapabilityType struct {
 func (CapabilityType) isType() {}
 
 func (t CapabilityType) ID() string {
+	var borrowType common.TypeID
 	if t.BorrowType != nil {
-		return fmt.Sprintf("Capability<%s>", t.BorrowType.ID())
+		borrowType = common.TypeID(t.BorrowType.ID())
 	}
-	return "Capability"
+	return string(common.NewCapabilityTypeID(borrowType))
 }
 
 // EnumType
@@ -143,10 +143,52 @@ func TestType_ID(t *testing.T) {
 			},
 			"S.test.FooI",
 		},
+		{
+			ReferenceType{
+				Type: IntType{},
+			},
+			"&Int",
+		},
+		{
+			ReferenceType{
+				Authorized: true,
+				Type:       IntType{},
+			},
+			"auth &Int",
+		},
+		{
+			ReferenceType{
+				Entitlements: []string{"S.test.E1", "S.test.E2"},
+				Type:         IntType{},
+			},
+			"auth(S.test.E1, S.test.E2) &Int",
+		},
 		{
 			RestrictedType{}.WithID("S.test.Foo{S.test.FooI}"),
 			"S.test.Foo{S.test.FooI}",
 		},
+		{
+			RestrictedType{
+				Type: AnyResourceType{},
+				Restrictions: []Type{
+					&ResourceInterfaceType{
+						Location:            utils.TestLocation,
+						QualifiedIdentifier: "FooI",
+					},
+				},
+			},
+			"AnyResource{S.test.FooI}",
+		},
+		{
+			FunctionType{
+				Parameters: []Parameter{
+					{Type: IntType{}},
+					{Type: StringType{}},
+				},
+				ReturnType: BoolType{},
+			},
+			"((Int,String):Bool)",
+		},
 	}
 
 	test := func(ty Type, expected string) {
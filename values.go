@@ -22,6 +22,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"time"
 	"unicode/utf8"
 
 	"github.com/onflow/cadence/fixedpoint"
@@ -905,6 +906,154 @@ func (v UFix64) String() string {
 	return format.UFix64(uint64(v))
 }
 
+// Fix128
+
+type Fix128 struct {
+	Value *big.Int
+}
+
+// NewFix128 parses the given decimal, e.g. `-1.5`
+//
+func NewFix128(s string) (Fix128, error) {
+	v, err := fixedpoint.ParseFix128(s)
+	if err != nil {
+		return Fix128{}, err
+	}
+	return Fix128{v}, nil
+}
+
+// NewFix128FromBig returns the Fix128 for the given integer,
+// which is the value multiplied by 10^24
+//
+func NewFix128FromBig(i *big.Int) (Fix128, error) {
+	if i.Cmp(sema.Fix128TypeMinBig) < 0 {
+		return Fix128{}, fmt.Errorf("value exceeds min of Fix128: %s", i.String())
+	}
+	if i.Cmp(sema.Fix128TypeMaxBig) > 0 {
+		return Fix128{}, fmt.Errorf("value exceeds max of Fix128: %s", i.String())
+	}
+	return Fix128{i}, nil
+}
+
+func (Fix128) isValue() {}
+
+func (Fix128) Type() Type {
+	return Fix128Type{}
+}
+
+func (v Fix128) ToGoValue() interface{} {
+	return v.Value
+}
+
+func (v Fix128) ToBigEndianBytes() []byte {
+	return interpreter.SignedBigIntToBigEndianBytes(v.Value)
+}
+
+func (v Fix128) String() string {
+	return format.Fix128(v.Value)
+}
+
+// UFix128
+
+type UFix128 struct {
+	Value *big.Int
+}
+
+// NewUFix128 parses the given decimal, e.g. `1.5`
+//
+func NewUFix128(s string) (UFix128, error) {
+	v, err := fixedpoint.ParseUFix128(s)
+	if err != nil {
+		return UFix128{}, err
+	}
+	return UFix128{v}, nil
+}
+
+// NewUFix128FromBig returns the UFix128 for the given integer,
+// which is the value multiplied by 10^24
+//
+func NewUFix128FromBig(i *big.Int) (UFix128, error) {
+	if i.Sign() < 0 {
+		return UFix128{}, fmt.Errorf("invalid negative value for UFix128: %s", i.String())
+	}
+	if i.Cmp(sema.UFix128TypeMaxBig) > 0 {
+		return UFix128{}, fmt.Errorf("value exceeds max of UFix128: %s", i.String())
+	}
+	return UFix128{i}, nil
+}
+
+func (UFix128) isValue() {}
+
+func (UFix128) Type() Type {
+	return UFix128Type{}
+}
+
+func (v UFix128) ToGoValue() interface{} {
+	return v.Value
+}
+
+func (v UFix128) ToBigEndianBytes() []byte {
+	return interpreter.UnsignedBigIntToBigEndianBytes(v.Value)
+}
+
+func (v UFix128) String() string {
+	return format.UFix128(v.Value)
+}
+
+// Timestamp
+
+// Timestamp is a point in time, the number of nanoseconds since the Unix epoch
+//
+type Timestamp int64
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp(t.UnixNano())
+}
+
+func (Timestamp) isValue() {}
+
+func (Timestamp) Type() Type {
+	return TimestampType{}
+}
+
+func (v Timestamp) ToGoValue() interface{} {
+	return v.Time()
+}
+
+// Time returns the timestamp as a time in UTC
+//
+func (v Timestamp) Time() time.Time {
+	return time.Unix(0, int64(v)).UTC()
+}
+
+func (v Timestamp) String() string {
+	return format.Timestamp(int64(v))
+}
+
+// Duration
+
+// Duration is an amount of time, a signed number of nanoseconds
+//
+type Duration int64
+
+func NewDuration(d time.Duration) Duration {
+	return Duration(d)
+}
+
+func (Duration) isValue() {}
+
+func (Duration) Type() Type {
+	return DurationType{}
+}
+
+func (v Duration) ToGoValue() interface{} {
+	return time.Duration(v)
+}
+
+func (v Duration) String() string {
+	return format.Duration(int64(v))
+}
+
 // Array
 
 type Array struct {
@@ -938,11 +1087,7 @@ func (v Array) ToGoValue() interface{} {
 }
 
 func (v Array) String() string {
-	values := make([]string, len(v.Values))
-	for i, value := range v.Values {
-		values[i] = value.String()
-	}
-	return format.Array(values)
+	return Format(v, FormatOptions{})
 }
 
 // Dictionary
@@ -978,22 +1123,7 @@ func (v Dictionary) ToGoValue() interface{} {
 }
 
 func (v Dictionary) String() string {
-	pairs := make([]struct {
-		Key   string
-		Value string
-	}, len(v.Pairs))
-
-	for i, pair := range v.Pairs {
-		pairs[i] = struct {
-			Key   string
-			Value string
-		}{
-			Key:   pair.Key.String(),
-			Value: pair.Value.String(),
-		}
-	}
-
-	return format.Dictionary(pairs)
+	return Format(v, FormatOptions{})
 }
 
 // KeyValuePair
@@ -1036,28 +1166,7 @@ func (v Struct) ToGoValue() interface{} {
 }
 
 func (v Struct) String() string {
-	return formatComposite(v.StructType.ID(), v.StructType.Fields, v.Fields)
-}
-
-func formatComposite(typeID string, fields []Field, values []Value) string {
-	preparedFields := make([]struct {
-		Name  string
-		Value string
-	}, 0, len(fields))
-	for i, field := range fields {
-		value := values[i]
-		preparedFields = append(preparedFields,
-			struct {
-				Name  string
-				Value string
-			}{
-				Name:  field.Identifier,
-				Value: value.String(),
-			},
-		)
-	}
-
-	return format.Composite(typeID, preparedFields)
+	return Format(v, FormatOptions{})
 }
 
 // Resource
@@ -1093,7 +1202,7 @@ func (v Resource) ToGoValue() interface{} {
 }
 
 func (v Resource) String() string {
-	return formatComposite(v.ResourceType.ID(), v.ResourceType.Fields, v.Fields)
+	return Format(v, FormatOptions{})
 }
 
 // Event
@@ -1118,6 +1227,29 @@ func (v Event) WithType(typ *EventType) Event {
 	return v
 }
 
+// IndexedFields returns the values of the fields of the event
+// which are marked as indexed in the event declaration, by field identifier.
+// The event must have a type
+//
+func (v Event) IndexedFields() map[string]Value {
+	if len(v.EventType.IndexedFields) == 0 {
+		return nil
+	}
+
+	result := make(map[string]Value, len(v.EventType.IndexedFields))
+
+	for i, field := range v.EventType.Fields {
+		if i >= len(v.Fields) {
+			break
+		}
+		if v.EventType.IsIndexedField(field.Identifier) {
+			result[field.Identifier] = v.Fields[i]
+		}
+	}
+
+	return result
+}
+
 func (v Event) ToGoValue() interface{} {
 	ret := make([]interface{}, len(v.Fields))
 
@@ -1128,7 +1260,7 @@ func (v Event) ToGoValue() interface{} {
 	return ret
 }
 func (v Event) String() string {
-	return formatComposite(v.EventType.ID(), v.EventType.Fields, v.Fields)
+	return Format(v, FormatOptions{})
 }
 
 // Contract
@@ -1164,7 +1296,7 @@ func (v Contract) ToGoValue() interface{} {
 }
 
 func (v Contract) String() string {
-	return formatComposite(v.ContractType.ID(), v.ContractType.Fields, v.Fields)
+	return Format(v, FormatOptions{})
 }
 
 // Link
@@ -1246,7 +1378,12 @@ func (TypeValue) ToGoValue() interface{} {
 }
 
 func (v TypeValue) String() string {
-	return format.TypeValue(v.StaticType.ID())
+	var staticType string
+	if v.StaticType != nil {
+		staticType = v.StaticType.ID()
+	}
+
+	return format.TypeValue(staticType)
 }
 
 // Capability
@@ -1259,8 +1396,10 @@ type Capability struct {
 
 func (Capability) isValue() {}
 
-func (Capability) Type() Type {
-	return CapabilityType{}
+func (v Capability) Type() Type {
+	return CapabilityType{
+		BorrowType: v.BorrowType,
+	}
 }
 
 func (Capability) ToGoValue() interface{} {
@@ -1268,13 +1407,48 @@ func (Capability) ToGoValue() interface{} {
 }
 
 func (v Capability) String() string {
+	var borrowType string
+	if v.BorrowType != nil {
+		borrowType = v.BorrowType.ID()
+	}
+
 	return format.Capability(
-		v.BorrowType.ID(),
+		borrowType,
 		v.Address.String(),
 		v.Path.String(),
 	)
 }
 
+// Function
+//
+// Function is an opaque descriptor of an exported function value.
+// Only the type of the function is exported:
+// the function cannot be invoked, and it cannot be imported again.
+//
+type Function struct {
+	FunctionType FunctionType
+}
+
+func NewFunction(functionType FunctionType) Function {
+	return Function{
+		FunctionType: functionType,
+	}
+}
+
+func (Function) isValue() {}
+
+func (v Function) Type() Type {
+	return v.FunctionType
+}
+
+func (Function) ToGoValue() interface{} {
+	return nil
+}
+
+func (v Function) String() string {
+	return format.Function(v.FunctionType.ID())
+}
+
 // Enum
 type Enum struct {
 	EnumType *EnumType
@@ -1307,5 +1481,5 @@ func (v Enum) ToGoValue() interface{} {
 }
 
 func (v Enum) String() string {
-	return formatComposite(v.EnumType.ID(), v.EnumType.Fields, v.Fields)
+	return Format(v, FormatOptions{})
 }